@@ -0,0 +1,141 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/internal/backup/app/export_data"
+	"github.com/YuukanOO/seelf/internal/backup/app/import_data"
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	backupinfra "github.com/YuukanOO/seelf/internal/backup/infra"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+type Options interface {
+	ConnectionString() string
+	Keyring() crypto.Keyring
+}
+
+// Returns the export command which writes a portable bundle of the instance data.
+func Export(opts Options, logger log.Logger) *cobra.Command {
+	var output string
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export apps, targets, registries and users to a portable JSON bundle (artifacts excluded)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withBus(opts, logger, func(_ *sqlite.Database, b bus.Dispatcher) error {
+				bundle, err := bus.Send(b, context.Background(), export_data.Command{})
+
+				if err != nil {
+					return err
+				}
+
+				var w io.Writer = cmd.OutOrStdout()
+
+				if output != "" {
+					file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) // May contain secrets
+
+					if err != nil {
+						return err
+					}
+
+					defer file.Close()
+
+					w = file
+				}
+
+				encoder := json.NewEncoder(w)
+				encoder.SetIndent("", "  ")
+
+				return encoder.Encode(bundle)
+			})
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "file to write the bundle to, defaults to stdout")
+
+	return exportCmd
+}
+
+// Returns the import command which restores a bundle into an empty instance.
+func Import(opts Options, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <bundle.json>",
+		Short: "Import a bundle previously exported into an empty instance",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0])
+
+			if err != nil {
+				return err
+			}
+
+			defer file.Close()
+
+			var bundle domain.Bundle
+
+			decoder := json.NewDecoder(file)
+			decoder.UseNumber()
+
+			if err = decoder.Decode(&bundle); err != nil {
+				return err
+			}
+
+			return withBus(opts, logger, func(db *sqlite.Database, b bus.Dispatcher) error {
+				ctx := context.Background()
+
+				if _, err := bus.Send(b, ctx, import_data.Command{
+					Bundle: bundle,
+				}); err != nil {
+					return err
+				}
+
+				// Bundles contain secrets in plain text so encrypt them with the current key
+				if _, err := db.RotateSecrets(ctx, startup.EncryptedColumns...); err != nil {
+					return err
+				}
+
+				logger.Infow("bundle imported, targets will be configured on the next server start",
+					"users", len(bundle.Users),
+					"teams", len(bundle.Teams),
+					"targets", len(bundle.Targets),
+					"registries", len(bundle.Registries),
+					"apps", len(bundle.Apps),
+					"webhooks", len(bundle.Webhooks),
+					"channels", len(bundle.Channels),
+					"peers", len(bundle.Peers),
+					"dns_zones", len(bundle.DnsZones))
+
+				return nil
+			})
+		},
+	}
+}
+
+func withBus(opts Options, logger log.Logger, fn func(*sqlite.Database, bus.Dispatcher) error) error {
+	b := memory.NewBus()
+	db, err := sqlite.Open(opts.ConnectionString(), logger, b, sqlite.WithKeyring(opts.Keyring()))
+
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	if err = db.Migrate(startup.MigrationsModules...); err != nil {
+		return err
+	}
+
+	backupinfra.Setup(db, b, startup.EncryptedColumns...)
+
+	return fn(db, b)
+}
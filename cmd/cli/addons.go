@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+type (
+	addon struct {
+		ID          string        `json:"id"`
+		Environment string        `json:"environment"`
+		Kind        string        `json:"kind"`
+		Variable    string        `json:"variable"`
+		Target      targetSummary `json:"target"`
+	}
+
+	addonBackup struct {
+		ID        string                    `json:"id"`
+		Status    uint8                     `json:"status"`
+		Size      int64                     `json:"size"`
+		ErrCode   monad.Maybe[string]       `json:"error_code"`
+		Restore   monad.Maybe[addonRestore] `json:"restore"`
+		CreatedAt string                    `json:"created_at"`
+	}
+
+	addonRestore struct {
+		FinishedAt monad.Maybe[string] `json:"finished_at"`
+		ErrCode    monad.Maybe[string] `json:"error_code"`
+	}
+)
+
+// Returns the addon command used to manage add-ons of an application and their backups.
+func addonCommand(opts *options) *cobra.Command {
+	var appID, addonID, backupID string
+
+	addonCmd := opts.bind(&cobra.Command{
+		Use:   "addon",
+		Short: "Manage add-ons of an application and their backups",
+		Example: `  seelf addon list --app <id>
+  seelf addon backup --app <id> --addon <id>
+  seelf addon restore --app <id> --addon <id> --backup <id>`,
+	})
+
+	addonCmd.PersistentFlags().StringVar(&appID, "app", "", "id of the application")
+	addonCmd.MarkPersistentFlagRequired("app")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List add-ons of an application",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var addons []addon
+
+			if err := opts.client().get(cmd.Context(), fmt.Sprintf("/apps/%s/addons", appID), &addons); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+			fmt.Fprintln(w, "ID\tENVIRONMENT\tKIND\tVARIABLE\tTARGET")
+
+			for _, a := range addons {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.ID, a.Environment, a.Kind, a.Variable, a.Target.Name)
+			}
+
+			return w.Flush()
+		},
+	}
+
+	backupsCmd := &cobra.Command{
+		Use:   "backups",
+		Short: "List backups of an add-on, most recent first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var backups []addonBackup
+
+			if err := opts.client().get(cmd.Context(), addonBackupsPath(appID, addonID), &backups); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+			fmt.Fprintln(w, "ID\tCREATED AT\tSTATUS\tSIZE\tRESTORE")
+
+			for _, b := range backups {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", b.ID, b.CreatedAt, b.status(), b.Size, b.restoreStatus())
+			}
+
+			return w.Flush()
+		},
+	}
+
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up an add-on right now",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var backup addonBackup
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), requestTimeout)
+			defer cancel()
+
+			if err := opts.client().send(ctx, http.MethodPost, addonBackupsPath(appID, addonID), nil, "", &backup); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "backup %s started\n", backup.ID)
+
+			return nil
+		},
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Replace the data of an add-on by one of its backups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), requestTimeout)
+			defer cancel()
+
+			if err := opts.client().send(ctx, http.MethodPost, fmt.Sprintf("%s/%s/restore", addonBackupsPath(appID, addonID), backupID), nil, "", nil); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "restoration of backup %s requested\n", backupID)
+
+			return nil
+		},
+	}
+
+	for _, c := range []*cobra.Command{backupsCmd, backupCmd, restoreCmd} {
+		c.Flags().StringVar(&addonID, "addon", "", "id of the add-on")
+		c.MarkFlagRequired("addon")
+	}
+
+	restoreCmd.Flags().StringVar(&backupID, "backup", "", "id of the backup to restore")
+	restoreCmd.MarkFlagRequired("backup")
+
+	addonCmd.AddCommand(listCmd, backupsCmd, backupCmd, restoreCmd)
+
+	return addonCmd
+}
+
+func addonBackupsPath(appID, addonID string) string {
+	return fmt.Sprintf("/apps/%s/addons/%s/backups", appID, addonID)
+}
+
+func (b addonBackup) status() string {
+	switch domain.AddonBackupStatus(b.Status) {
+	case domain.AddonBackupStatusRunning:
+		return "running"
+	case domain.AddonBackupStatusFailed:
+		return "failed: " + b.ErrCode.Get("unknown error")
+	case domain.AddonBackupStatusSucceeded:
+		return "succeeded"
+	default:
+		return "unknown"
+	}
+}
+
+func (b addonBackup) restoreStatus() string {
+	restore, isSet := b.Restore.TryGet()
+
+	switch {
+	case !isSet:
+		return "-"
+	case !restore.FinishedAt.HasValue():
+		return "in progress"
+	case restore.ErrCode.HasValue():
+		return "failed: " + restore.ErrCode.MustGet()
+	default:
+		return "restored"
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/YuukanOO/seelf/internal/deployment/infra/gitops"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+var errUnknownTarget = errors.New("unknown target")
+
+type (
+	remoteTarget struct {
+		ID                 string              `json:"id"`
+		Name               string              `json:"name"`
+		Url                string              `json:"url"`
+		CleanupRequestedAt monad.Maybe[string] `json:"cleanup_requested_at"`
+		Provider           struct {
+			Data struct {
+				Host monad.Maybe[string] `json:"host"`
+				Port monad.Maybe[int]    `json:"port"`
+				User monad.Maybe[string] `json:"user"`
+			} `json:"data"`
+		} `json:"provider"`
+	}
+
+	remoteApp struct {
+		ID                 string              `json:"id"`
+		Name               string              `json:"name"`
+		CleanupRequestedAt monad.Maybe[string] `json:"cleanup_requested_at"`
+	}
+
+	createAppBody struct {
+		Name           string                 `json:"name"`
+		VersionControl *gitops.VersionControl `json:"version_control,omitempty"`
+		Production     environmentBody        `json:"production"`
+		Staging        environmentBody        `json:"staging"`
+	}
+
+	updateAppBody struct {
+		VersionControl *gitops.VersionControl `json:"version_control"` // A nil value removes it
+		Production     environmentBody        `json:"production"`
+		Staging        environmentBody        `json:"staging"`
+	}
+
+	environmentBody struct {
+		Target   string                            `json:"target"`
+		Vars     map[string]map[string]string      `json:"vars,omitempty"`
+		Exposure map[string]gitops.ServiceExposure `json:"exposure,omitempty"`
+	}
+
+	// Reconcile a spec with the state of a seelf instance.
+	applier struct {
+		client  *client
+		out     io.Writer
+		dryRun  bool
+		targets map[string]string // Target ids by name
+	}
+)
+
+// Returns the apply command used to reconcile the targets and apps of an instance
+// with a declarative spec, making it possible to manage seelf itself from a git repository.
+func applyCommand(opts *options) *cobra.Command {
+	var (
+		file   string
+		prune  bool
+		dryRun bool
+	)
+
+	applyCmd := opts.bind(&cobra.Command{
+		Use:   "apply",
+		Short: "Create or update targets and apps to match a declarative spec",
+		Example: `  seelf apply -f seelf.yml
+  seelf apply -f seelf.yml --prune --dry-run
+  cat seelf.yml | seelf apply -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := readSpec(file, cmd.InOrStdin())
+
+			if err != nil {
+				return err
+			}
+
+			a := &applier{
+				client:  opts.client(),
+				out:     cmd.ErrOrStderr(),
+				dryRun:  dryRun,
+				targets: make(map[string]string),
+			}
+
+			return a.apply(cmd.Context(), s, prune)
+		},
+	})
+
+	applyCmd.Flags().StringVarP(&file, "file", "f", "", "path to the spec file, use - to read it from stdin")
+	applyCmd.Flags().BoolVar(&prune, "prune", false, "delete targets and apps which are not part of the spec")
+	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print what would be done without changing anything")
+	applyCmd.MarkFlagRequired("file")
+
+	return applyCmd
+}
+
+func readSpec(path string, stdin io.Reader) (gitops.Spec, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	if path == stdinPath {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return gitops.Spec{}, err
+	}
+
+	return gitops.Parse(content)
+}
+
+func (a *applier) apply(ctx context.Context, s gitops.Spec, prune bool) error {
+	var (
+		targets []remoteTarget
+		apps    []remoteApp
+	)
+
+	if err := a.client.get(ctx, "/targets", &targets); err != nil {
+		return err
+	}
+
+	if err := a.client.get(ctx, "/apps", &apps); err != nil {
+		return err
+	}
+
+	// Resources being deleted are ignored so they will be created again if needed
+	existingTargets := make(map[string]remoteTarget, len(targets))
+
+	for _, t := range targets {
+		if !t.CleanupRequestedAt.HasValue() {
+			existingTargets[t.Name] = t
+			a.targets[t.Name] = t.ID
+		}
+	}
+
+	existingApps := make(map[string]remoteApp, len(apps))
+
+	for _, app := range apps {
+		if !app.CleanupRequestedAt.HasValue() {
+			existingApps[app.Name] = app
+		}
+	}
+
+	for _, t := range s.Targets {
+		existing, found := existingTargets[t.Name]
+
+		if err := a.applyTarget(ctx, t, existing, found); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+
+		delete(existingTargets, t.Name)
+	}
+
+	for _, app := range s.Apps {
+		existing, found := existingApps[app.Name]
+
+		if err := a.applyApp(ctx, app, existing, found); err != nil {
+			return fmt.Errorf("app %s: %w", app.Name, err)
+		}
+
+		delete(existingApps, app.Name)
+	}
+
+	if !prune {
+		return nil
+	}
+
+	// Apps are removed first since a target can not be deleted while still in use
+	for _, app := range existingApps {
+		if err := a.do(ctx, "deleting app "+app.Name, http.MethodDelete, "/apps/"+app.ID, nil, nil); err != nil {
+			return fmt.Errorf("app %s: %w", app.Name, err)
+		}
+	}
+
+	for _, t := range existingTargets {
+		if err := a.do(ctx, "deleting target "+t.Name, http.MethodDelete, "/targets/"+t.ID, nil, nil); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *applier) applyTarget(ctx context.Context, t gitops.Target, existing remoteTarget, found bool) error {
+	if !found {
+		var created remoteTarget
+
+		if err := a.do(ctx, "creating target "+t.Name, http.MethodPost, "/targets", t, &created); err != nil {
+			return err
+		}
+
+		a.targets[t.Name] = created.ID
+
+		return nil
+	}
+
+	// Updating a target triggers its reconfiguration so only do it when needed,
+	// a private key can not be read back so giving one always updates the target
+	data := existing.Provider.Data
+
+	if existing.Url == t.Url &&
+		t.Docker.Matches(data.Host.Get(""), data.Port.Get(0), data.User.Get("")) &&
+		t.Docker.PrivateKey == "" {
+		fmt.Fprintf(a.out, "target %s is up to date\n", t.Name)
+		return nil
+	}
+
+	return a.do(ctx, "updating target "+t.Name, http.MethodPatch, "/targets/"+existing.ID, t, nil)
+}
+
+func (a *applier) applyApp(ctx context.Context, app gitops.App, existing remoteApp, found bool) error {
+	production, err := a.environment(app.Production)
+
+	if err != nil {
+		return err
+	}
+
+	staging, err := a.environment(app.Staging)
+
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return a.do(ctx, "creating app "+app.Name, http.MethodPost, "/apps", createAppBody{
+			Name:           app.Name,
+			VersionControl: app.VersionControl,
+			Production:     production,
+			Staging:        staging,
+		}, nil)
+	}
+
+	return a.do(ctx, "updating app "+app.Name, http.MethodPatch, "/apps/"+existing.ID, updateAppBody{
+		VersionControl: app.VersionControl,
+		Production:     production,
+		Staging:        staging,
+	}, nil)
+}
+
+func (a *applier) environment(env gitops.Environment) (environmentBody, error) {
+	// When running dry, targets to create are known but does not have an id yet
+	id, found := a.targets[env.Target]
+
+	if !found {
+		return environmentBody{}, fmt.Errorf("%w %s", errUnknownTarget, env.Target)
+	}
+
+	return environmentBody{
+		Target:   id,
+		Vars:     env.Vars,
+		Exposure: env.Exposure,
+	}, nil
+}
+
+// Print the given action and send the related request, unless running dry.
+func (a *applier) do(ctx context.Context, action, method, path string, data, target any) error {
+	if a.dryRun {
+		fmt.Fprintf(a.out, "%s (dry run)\n", action)
+		return nil
+	}
+
+	fmt.Fprintln(a.out, action)
+
+	var (
+		body        io.Reader
+		contentType string
+		err         error
+	)
+
+	if data != nil {
+		if body, contentType, err = jsonBody(data); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	return a.client.send(ctx, method, path, body, contentType, target)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+type (
+	app struct {
+		ID                string            `json:"id"`
+		Name              string            `json:"name"`
+		ProductionTarget  targetSummary     `json:"production_target"`
+		StagingTarget     targetSummary     `json:"staging_target"`
+		LatestDeployments latestDeployments `json:"latest_deployments"`
+	}
+
+	targetSummary struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	latestDeployments struct {
+		Production monad.Maybe[deployment] `json:"production"`
+		Staging    monad.Maybe[deployment] `json:"staging"`
+	}
+)
+
+// Returns the app command used to manage applications.
+func appCommand(opts *options) *cobra.Command {
+	appCmd := opts.bind(&cobra.Command{
+		Use:   "app",
+		Short: "Manage applications of a seelf instance",
+	})
+
+	appCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List applications you have access to",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var apps []app
+
+			if err := opts.client().get(cmd.Context(), "/apps", &apps); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+			fmt.Fprintln(w, "ID\tNAME\tPRODUCTION\tSTAGING")
+
+			for _, a := range apps {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					a.ID,
+					a.Name,
+					describeEnvironment(a.ProductionTarget, a.LatestDeployments.Production),
+					describeEnvironment(a.StagingTarget, a.LatestDeployments.Staging),
+				)
+			}
+
+			return w.Flush()
+		},
+	})
+
+	return appCmd
+}
+
+func describeEnvironment(target targetSummary, latest monad.Maybe[deployment]) string {
+	depl, isSet := latest.TryGet()
+
+	if !isSet {
+		return target.Name
+	}
+
+	return fmt.Sprintf("%s (#%d %s)", target.Name, depl.DeploymentNumber, depl.State.status())
+}
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/cmd/version"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+const (
+	apiPrefix        = "/api/v1"
+	urlEnvVariable   = "SEELF_URL"
+	tokenEnvVariable = "SEELF_TOKEN"
+	requestTimeout   = 30 * time.Second
+
+	logLineEvent = "line"
+	logEndEvent  = "end"
+)
+
+var (
+	errMissingUrl   = fmt.Errorf("missing seelf url, use the --url flag or the %s environment variable", urlEnvVariable)
+	errMissingToken = fmt.Errorf("missing API token, use the --token flag or the %s environment variable", tokenEnvVariable)
+)
+
+type (
+	// Options shared by every client commands.
+	options struct {
+		url   string
+		token string
+	}
+
+	// Tiny client used to talk to a seelf instance HTTP API.
+	client struct {
+		url   string
+		token string
+		http  *http.Client
+	}
+
+	// Error returned by the API.
+	apiError struct {
+		Status int
+		Code   string       `json:"code"`
+		Errors []fieldError `json:"errors"`
+	}
+
+	fieldError struct {
+		Field string `json:"field"`
+		Code  string `json:"code"`
+	}
+)
+
+// Returns commands used to interact with a remote seelf instance through its API,
+// mostly useful in scripts and CI pipelines.
+func Commands() []*cobra.Command {
+	var opts options
+
+	return []*cobra.Command{
+		appCommand(&opts),
+		applyCommand(&opts),
+		addonCommand(&opts),
+		deployCommand(&opts),
+		logsCommand(&opts),
+	}
+}
+
+// Register flags needed to reach the API on the given command and make sure they
+// are set before running it.
+func (o *options) bind(cmd *cobra.Command) *cobra.Command {
+	cmd.PersistentFlags().StringVar(&o.url, "url", os.Getenv(urlEnvVariable), "url of the seelf instance, defaults to the "+urlEnvVariable+" environment variable")
+	cmd.PersistentFlags().StringVar(&o.token, "token", os.Getenv(tokenEnvVariable), "API key or token to authenticate with, defaults to the "+tokenEnvVariable+" environment variable")
+
+	// Client commands do not need the server configuration so override the root one
+	cmd.PersistentPreRunE = func(*cobra.Command, []string) error {
+		if o.url == "" {
+			return errMissingUrl
+		}
+
+		if o.token == "" {
+			return errMissingToken
+		}
+
+		return nil
+	}
+
+	return cmd
+}
+
+func (o *options) client() *client {
+	return &client{
+		url:   strings.TrimSuffix(o.url, "/") + apiPrefix,
+		token: o.token,
+		http:  &http.Client{},
+	}
+}
+
+// Send a request and decode the JSON response in the given target if any.
+func (c *client) send(ctx context.Context, method, path string, body io.Reader, contentType string, target any) error {
+	resp, err := c.do(ctx, method, path, body, contentType)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if target == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Retrieve the given resource as JSON.
+func (c *client) get(ctx context.Context, path string, target any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	return c.send(ctx, http.MethodGet, path, nil, "", target)
+}
+
+// Write the plain text response of the given path to w.
+func (c *client) copy(ctx context.Context, path string, w io.Writer) error {
+	resp, err := c.do(ctx, http.MethodGet, path, nil, "")
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// Follow the server-sent log events of the given path and write each line to w
+// until the end event is received.
+func (c *client) follow(ctx context.Context, path string, w io.Writer) error {
+	resp, err := c.do(ctx, http.MethodGet, path, nil, "")
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	var (
+		scanner = bufio.NewScanner(resp.Body)
+		event   string
+		data    monad.Maybe[string]
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "": // Dispatch the event
+			if event == logEndEvent {
+				return nil
+			}
+
+			if value, isSet := data.TryGet(); isSet && event == logLineEvent {
+				if _, err := fmt.Fprintln(w, value); err != nil {
+					return err
+				}
+			}
+
+			event = ""
+			data.Unset()
+		case strings.HasPrefix(line, ":"): // Keep-alive comment
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			value := strings.TrimPrefix(line, "data:")
+
+			if existing, isSet := data.TryGet(); isSet {
+				value = existing + "\n" + value
+			}
+
+			data.Set(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return io.ErrUnexpectedEOF
+}
+
+func (c *client) do(
+	ctx context.Context,
+	method, path string,
+	body io.Reader,
+	contentType string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("User-Agent", "seelf-cli/"+version.Current())
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	apiErr := apiError{Status: resp.StatusCode}
+
+	// Some errors, such as the unauthorized one, does not have a body
+	if err = json.NewDecoder(resp.Body).Decode(&apiErr); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return nil, apiErr
+}
+
+func (e apiError) Error() string {
+	msg := http.StatusText(e.Status)
+
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+
+	for _, field := range e.Errors {
+		msg += fmt.Sprintf(", %s: %s", field.Field, field.Code)
+	}
+
+	return msg
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+const stdinPath = "-"
+
+var errInvalidSource = errors.New("exactly one of --archive, --raw or --git-branch must be given")
+
+type (
+	gitSource struct {
+		Branch string              `json:"branch"`
+		Hash   monad.Maybe[string] `json:"hash"`
+	}
+
+	queueDeploymentBody struct {
+		Environment string                 `json:"environment"`
+		Raw         monad.Maybe[string]    `json:"raw"`
+		Git         monad.Maybe[gitSource] `json:"git"`
+	}
+)
+
+// Returns the deploy command used to queue a new deployment of an application.
+func deployCommand(opts *options) *cobra.Command {
+	var (
+		appID       string
+		environment string
+		archive     string
+		raw         string
+		git         gitSource
+		follow      bool
+	)
+
+	deployCmd := opts.bind(&cobra.Command{
+		Use:   "deploy",
+		Short: "Queue a new deployment of an application",
+		Example: `  seelf deploy --app <id> --env production --archive ./dist.tar.gz
+  seelf deploy --app <id> --env staging --git-branch main --git-hash <commit> -f
+  cat compose.yml | seelf deploy --app <id> --raw -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				c           = opts.client()
+				body        io.Reader
+				contentType string
+				err         error
+			)
+
+			switch {
+			case archive != "" && raw == "" && git.Branch == "":
+				body, contentType, err = archiveBody(environment, archive)
+			case raw != "" && archive == "" && git.Branch == "":
+				body, contentType, err = rawBody(environment, raw, cmd.InOrStdin())
+			case git.Branch != "" && archive == "" && raw == "":
+				body, contentType, err = jsonBody(queueDeploymentBody{
+					Environment: environment,
+					Git:         monad.Value(git),
+				})
+			default:
+				return errInvalidSource
+			}
+
+			if err != nil {
+				return err
+			}
+
+			var depl deployment
+
+			if err = c.send(cmd.Context(), http.MethodPost, fmt.Sprintf("/apps/%s/deployments", appID), body, contentType, &depl); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "deployment #%d queued on %s\n", depl.DeploymentNumber, depl.Environment)
+
+			if !follow {
+				return nil
+			}
+
+			return followDeployment(cmd.Context(), c, appID, depl.DeploymentNumber, cmd.OutOrStdout())
+		},
+	})
+
+	deployCmd.Flags().StringVar(&appID, "app", "", "id of the application to deploy")
+	deployCmd.Flags().StringVar(&environment, "env", "production", "environment to deploy, production or staging")
+	deployCmd.Flags().StringVar(&archive, "archive", "", "path to a tar.gz archive of the project to deploy")
+	deployCmd.Flags().StringVar(&raw, "raw", "", "path to a compose file to deploy, use - to read it from stdin")
+	deployCmd.Flags().StringVar(&git.Branch, "git-branch", "", "git branch to deploy")
+	deployCmd.Flags().Var(&maybeFlag{&git.Hash}, "git-hash", "specific commit to deploy, defaults to the branch head")
+	deployCmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow the deployment logs until it is done and fail if it has failed")
+	deployCmd.MarkFlagRequired("app")
+
+	return deployCmd
+}
+
+func archiveBody(environment, path string) (io.Reader, string, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer file.Close()
+
+	var (
+		buf    bytes.Buffer
+		writer = multipart.NewWriter(&buf)
+	)
+
+	if err = writer.WriteField("environment", environment); err != nil {
+		return nil, "", err
+	}
+
+	part, err := writer.CreateFormFile("archive", filepath.Base(path))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err = io.Copy(part, file); err != nil {
+		return nil, "", err
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, writer.FormDataContentType(), nil
+}
+
+func rawBody(environment, path string, stdin io.Reader) (io.Reader, string, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	if path == stdinPath {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return jsonBody(queueDeploymentBody{
+		Environment: environment,
+		Raw:         monad.Value(string(content)),
+	})
+}
+
+func jsonBody(data any) (io.Reader, string, error) {
+	b, err := json.Marshal(data)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return bytes.NewReader(b), "application/json", nil
+}
+
+// Flag setting an optional value only when given.
+type maybeFlag struct {
+	value *monad.Maybe[string]
+}
+
+func (f *maybeFlag) String() string     { return f.value.Get("") }
+func (f *maybeFlag) Set(s string) error { f.value.Set(s); return nil }
+func (f *maybeFlag) Type() string       { return "string" }
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+)
+
+const pendingPollInterval = 2 * time.Second
+
+var errDeploymentFailed = errors.New("deployment failed")
+
+type (
+	deployment struct {
+		AppID            string          `json:"app_id"`
+		DeploymentNumber int             `json:"deployment_number"`
+		Environment      string          `json:"environment"`
+		State            deploymentState `json:"state"`
+	}
+
+	deploymentState struct {
+		Status  uint8               `json:"status"`
+		ErrCode monad.Maybe[string] `json:"error_code"`
+	}
+
+	deploymentsPage struct {
+		Data []deployment `json:"data"`
+	}
+)
+
+// Returns the logs command used to print, and optionally follow, deployment logs.
+func logsCommand(opts *options) *cobra.Command {
+	var (
+		appID  string
+		number int
+		follow bool
+	)
+
+	logsCmd := opts.bind(&cobra.Command{
+		Use:   "logs",
+		Short: "Print the logs of a deployment, the latest one by default",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := opts.client()
+
+			if number == 0 {
+				var page deploymentsPage
+
+				if err := c.get(cmd.Context(), fmt.Sprintf("/apps/%s/deployments", appID), &page); err != nil {
+					return err
+				}
+
+				if len(page.Data) == 0 {
+					return fmt.Errorf("no deployment found for app %s", appID)
+				}
+
+				number = page.Data[0].DeploymentNumber
+			}
+
+			if !follow {
+				return c.copy(cmd.Context(), deploymentPath(appID, number)+"/logs", cmd.OutOrStdout())
+			}
+
+			return followDeployment(cmd.Context(), c, appID, number, cmd.OutOrStdout())
+		},
+	})
+
+	logsCmd.Flags().StringVar(&appID, "app", "", "id of the application")
+	logsCmd.Flags().IntVarP(&number, "number", "n", 0, "deployment number, defaults to the latest one")
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow the logs until the deployment is done and fail if it has failed")
+	logsCmd.MarkFlagRequired("app")
+
+	return logsCmd
+}
+
+// Wait for the deployment to start, stream its logs until it is done and returns
+// an error if it has failed.
+func followDeployment(ctx context.Context, c *client, appID string, number int, w io.Writer) error {
+	var (
+		path = deploymentPath(appID, number)
+		depl deployment
+	)
+
+	// The logs stream ends right away for pending deployments so wait for it to start
+	for {
+		if err := c.get(ctx, path, &depl); err != nil {
+			return err
+		}
+
+		if domain.DeploymentStatus(depl.State.Status) != domain.DeploymentStatusPending {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pendingPollInterval):
+		}
+	}
+
+	if err := c.follow(ctx, path+"/logs/stream", w); err != nil {
+		return err
+	}
+
+	if err := c.get(ctx, path, &depl); err != nil {
+		return err
+	}
+
+	if domain.DeploymentStatus(depl.State.Status) == domain.DeploymentStatusFailed {
+		return fmt.Errorf("%w: %s", errDeploymentFailed, depl.State.ErrCode.Get("unknown error"))
+	}
+
+	return nil
+}
+
+func deploymentPath(appID string, number int) string {
+	return fmt.Sprintf("/apps/%s/deployments/%d", appID, number)
+}
+
+func (s deploymentState) status() string {
+	switch domain.DeploymentStatus(s.Status) {
+	case domain.DeploymentStatusPending:
+		return "pending"
+	case domain.DeploymentStatusRunning:
+		return "running"
+	case domain.DeploymentStatusFailed:
+		return "failed"
+	case domain.DeploymentStatusSucceeded:
+		return "succeeded"
+	default:
+		return "unknown"
+	}
+}
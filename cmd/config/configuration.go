@@ -1,26 +1,42 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/YuukanOO/seelf/cmd/serve"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/gitops"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/hook"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/secret"
 	"github.com/YuukanOO/seelf/pkg/config"
 	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/id"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/mail"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/oidc"
+	"github.com/YuukanOO/seelf/pkg/s3"
+	"github.com/YuukanOO/seelf/pkg/ssh"
 	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/YuukanOO/seelf/pkg/validate/numbers"
+	vstrings "github.com/YuukanOO/seelf/pkg/validate/strings"
 )
 
 var (
+	databaseJournalModes     = regexp.MustCompile(`^(?i)(delete|truncate|persist|memory|wal|off)$`)
+	databaseSynchronousModes = regexp.MustCompile(`^(?i)(off|normal|full|extra)$`)
+	scanners                 = regexp.MustCompile(`^(` + docker.ScannerGrype + `|` + docker.ScannerTrivy + `)$`)
 	userConfigDir            = must.Panic(os.UserConfigDir())
 	generatedSecretKey       = must.Panic(crypto.RandomKey[string](64))
 	defaultDataDirectory     = filepath.Join(userConfigDir, "seelf")
@@ -29,8 +45,17 @@ var (
 )
 
 const (
-	databaseConnectionString      = "seelf.db?_journal=WAL&_timeout=5000&_foreign_keys=yes&_txlock=immediate"
+	databaseFilename              = "seelf.db"
+	defaultDatabaseJournalMode    = "wal"
+	defaultDatabaseBusyTimeout    = "5s"
+	defaultDatabaseSynchronous    = "normal"
+	defaultDatabaseCacheSize      = -2000 // Negative values are expressed in KiB, see https://sqlite.org/pragma.html#pragma_cache_size
+	defaultDatabaseCheckpoint     = "5m"
+	defaultDatabaseReadPoolSize   = 4
 	defaultConfigFilename         = "conf.yml"
+	defaultLogFileMaxSize         = 100 // In megabytes
+	defaultLogFileMaxBackups      = 3
+	defaultLogSyslogTag           = "seelf"
 	defaultPort                   = 8080
 	defaultHost                   = ""
 	defaultRunnersPollInterval    = "4s"
@@ -38,6 +63,45 @@ const (
 	defaultCleanupDeploymentCount = 2
 	defaultBalancerDomain         = "http://docker.localhost"
 	defaultDeploymentDirTemplate  = "{{ .Environment }}"
+	defaultQuotaInterval          = "1h"
+	defaultResourceUsageInterval  = "1m"
+	defaultResourceUsageRetention = "168h"
+	defaultIncidentsInterval      = "30s"
+	defaultTrafficInterval        = "1m"
+	defaultTrafficRetention       = "168h"
+	defaultMonitorsInterval       = "10s"
+	defaultAddonsBackupInterval   = "24h"
+	defaultAddonsBackupRetention  = 7
+	defaultProxyUpgradeInterval   = "24h"
+	defaultTargetSelection        = string(domain.TargetSelectionLeastLoaded)
+	defaultTargetsGCInterval      = "24h"
+	defaultTargetsGCMinAge        = "24h"
+	defaultDnsSyncInterval        = "1h"
+	defaultArchiveGracePeriod     = "168h"
+	defaultTrashRetention         = "168h"
+	defaultMaxLogSize             = 50   // In megabytes
+	defaultMaxBuildCacheSize      = 2048 // In megabytes
+	defaultSBOMImage              = "anchore/syft:latest"
+	defaultOIDCName               = "SSO"
+	defaultSessionLifetime        = "720h"
+	defaultSessionIdleTimeout     = "0"
+	defaultLockoutMaxAttempts     = 5
+	defaultLockoutWindow          = "15m"
+	defaultLockoutDuration        = "15m"
+	defaultSmtpPort               = 587
+	defaultRateLimit              = 600 // Requests per minute
+	defaultRateLimitBurst         = 100
+	defaultMaxBodySize            = 1   // In megabytes
+	defaultMaxArchiveSize         = 256 // In megabytes
+	defaultIdempotencyTTL         = "24h"
+	defaultGitOpsBranch           = "main"
+	defaultGitOpsPath             = "seelf.yml"
+	defaultGitOpsInterval         = "1m"
+	defaultSSHKeepAliveInterval   = "30s"
+	defaultSSHKeepAliveCountMax   = 3
+	defaultSSHPersist             = "10m"
+	megabyte                      = 1 << 20
+	envPrefix                     = "SEELF_"
 )
 
 type (
@@ -52,35 +116,186 @@ type (
 	ConfigurationBuilder func(*configuration)
 
 	configuration struct {
-		Log     logConfiguration
-		Data    dataConfiguration
-		Http    httpConfiguration
-		Runners runnersConfiguration
-		Private internalConfiguration `yaml:"-"`
+		Log       logConfiguration
+		Data      dataConfiguration
+		Http      httpConfiguration
+		Grpc      grpcConfiguration
+		Auth      authConfiguration
+		Runners   runnersConfiguration
+		Usage     resourceUsageConfiguration `yaml:"resource_usage"`
+		Incidents incidentsConfiguration
+		Traffic   trafficConfiguration
+		Monitors  monitorsConfiguration
+		Addons    addonsConfiguration
+		Proxy     proxyConfiguration
+		Targets   targetsConfiguration
+		Scan      scanConfiguration
+		Pipeline  pipelineConfiguration
+		Dns       dnsConfiguration    `yaml:"dns"`
+		Gitops    gitOpsConfiguration `yaml:"gitops"`
+		Ssh       sshConfiguration    `yaml:"ssh"`
+		Database  databaseConfiguration
+		Telemetry telemetryConfiguration
+		Smtp      smtpConfiguration
+		Secrets   secretsConfiguration
+		Private   internalConfiguration `yaml:"-"`
 
+		mu                    sync.RWMutex // Protects settings which could be reloaded while running
+		path                  string
+		logger                log.ConfigurableLogger
 		appExposedUrl         monad.Maybe[domain.Url]
 		pollInterval          time.Duration
+		busyTimeout           time.Duration
+		sessionLifetime       time.Duration
+		sessionIdleTimeout    time.Duration
+		lockoutWindow         time.Duration
+		lockoutDuration       time.Duration
+		checkpointInterval    time.Duration
+		quotaInterval         time.Duration
+		archiveGracePeriod    time.Duration
+		trashRetention        time.Duration
+		idempotencyTTL        time.Duration
+		usageInterval         time.Duration
+		usageRetention        time.Duration
+		incidentsInterval     time.Duration
+		trafficInterval       time.Duration
+		trafficRetention      time.Duration
+		monitorsInterval      time.Duration
+		backupInterval        time.Duration
+		proxyUpgradeInterval  time.Duration
+		targetSelection       domain.TargetSelection
+		targetsGCInterval     time.Duration
+		targetsGCMinAge       time.Duration
+		dnsSyncInterval       time.Duration
+		gitOpsInterval        time.Duration
+		sshKeepAliveInterval  time.Duration
+		sshPersist            time.Duration
+		scanFailOn            monad.Maybe[domain.VulnerabilitySeverity]
+		hooks                 []hook.Hook
+		freezeWindows         domain.FreezeWindows
+		timeouts              domain.DeploymentTimeouts
 		deploymentDirTemplate *template.Template
 		logLevel              log.Level
 		logFormat             log.OutputFormat
+		logModules            map[string]log.Level
+		keyring               crypto.Keyring
 	}
 
 	logConfiguration struct {
-		Level  string `env:"LOG_LEVEL"`
-		Format string `env:"LOG_FORMAT"`
+		Level   string `env:"LOG_LEVEL"`
+		Format  string `env:"LOG_FORMAT"`
+		Modules string `env:"LOG_MODULES" yaml:",omitempty"` // Per module levels, ie. scheduler=debug,http=warn
+		File    logFileConfiguration
+		Syslog  logSyslogConfiguration
+	}
+
+	// Optional file sink, enabled when a path is set.
+	logFileConfiguration struct {
+		Path       string `env:"LOG_FILE_PATH" yaml:",omitempty"`
+		MaxSize    int    `env:"LOG_FILE_MAX_SIZE" yaml:"max_size"`
+		MaxBackups int    `env:"LOG_FILE_MAX_BACKUPS" yaml:"max_backups"`
+	}
+
+	// Optional syslog sink.
+	logSyslogConfiguration struct {
+		Enabled bool   `env:"LOG_SYSLOG_ENABLED"`
+		Address string `env:"LOG_SYSLOG_ADDRESS" yaml:",omitempty"`
+		Tag     string `env:"LOG_SYSLOG_TAG"`
 	}
 
 	httpConfiguration struct {
-		Host   string            `env:"HTTP_HOST" yaml:",omitempty"`
-		Port   int               `env:"HTTP_PORT,PORT"`
-		Secure monad.Maybe[bool] `env:"HTTP_SECURE" yaml:",omitempty"`
-		Secret string            `env:"HTTP_SECRET"`
+		Host           string            `env:"HTTP_HOST" yaml:",omitempty"`
+		Port           int               `env:"HTTP_PORT,PORT"`
+		Secure         monad.Maybe[bool] `env:"HTTP_SECURE" yaml:",omitempty"`
+		Secret         string            `env:"HTTP_SECRET"`
+		IdempotencyTTL string            `env:"HTTP_IDEMPOTENCY_TTL" yaml:"idempotency_ttl"` // How long responses to requests with an idempotency key are kept, 0 to disable
+		Limits         httpLimitsConfiguration
+	}
+
+	// Configuration related to how much clients could send to the API.
+	httpLimitsConfiguration struct {
+		Rate        int `env:"HTTP_RATE_LIMIT"`                           // Requests per minute and per client, 0 to disable
+		Burst       int `env:"HTTP_RATE_LIMIT_BURST"`                     // Requests a client could send at once
+		BodySize    int `env:"HTTP_MAX_BODY_SIZE" yaml:"body_size"`       // In megabytes
+		ArchiveSize int `env:"HTTP_MAX_ARCHIVE_SIZE" yaml:"archive_size"` // In megabytes
+	}
+
+	// Optional gRPC API, enabled when a port is set. It listens on the HTTP host.
+	grpcConfiguration struct {
+		Port int `env:"GRPC_PORT" yaml:",omitempty"`
+	}
+
+	// Configuration related to how users could log in.
+	authConfiguration struct {
+		DisablePassword bool `env:"AUTH_DISABLE_PASSWORD" yaml:"disable_password,omitempty"`
+		Session         sessionConfiguration
+		Lockout         lockoutConfiguration
+		OIDC            oidcConfiguration
+	}
+
+	// Configuration related to when users sessions expire.
+	sessionConfiguration struct {
+		Lifetime    string `env:"AUTH_SESSION_LIFETIME"`
+		IdleTimeout string `env:"AUTH_SESSION_IDLE_TIMEOUT" yaml:"idle_timeout"`
+	}
+
+	// Configuration related to how failed login attempts lock an email out.
+	lockoutConfiguration struct {
+		MaxAttempts int    `env:"AUTH_LOCKOUT_MAX_ATTEMPTS" yaml:"max_attempts"`
+		Window      string `env:"AUTH_LOCKOUT_WINDOW"`
+		Duration    string `env:"AUTH_LOCKOUT_DURATION"`
+	}
+
+	// Optional OpenID Connect provider, enabled when an issuer is set.
+	oidcConfiguration struct {
+		Name         string `env:"OIDC_NAME"`
+		Issuer       string `env:"OIDC_ISSUER" yaml:",omitempty"`
+		ClientID     string `env:"OIDC_CLIENT_ID" yaml:"client_id,omitempty"`
+		ClientSecret string `env:"OIDC_CLIENT_SECRET" yaml:"client_secret,omitempty"`
 	}
 
 	// Contains configuration related to where files produced by seelf will be stored.
 	dataConfiguration struct {
 		Path                  string `env:"DATA_PATH"`
 		DeploymentDirTemplate string `env:"DEPLOYMENT_DIR_TEMPLATE" yaml:"deployment_dir_template"`
+		MaxLogSize            int    `env:"DATA_MAX_LOG_SIZE" yaml:"max_log_size"`                 // In megabytes, 0 for no limit
+		ArchiveGracePeriod    string `env:"DATA_ARCHIVE_GRACE_PERIOD" yaml:"archive_grace_period"` // How long archives of deleted apps are kept
+		TrashRetention        string `env:"DATA_TRASH_RETENTION" yaml:"trash_retention"`           // How long deleted apps could be restored, 0 to disable
+		S3                    s3Configuration
+		Quota                 quotaConfiguration
+		BuildCache            buildCacheConfiguration `yaml:"build_cache"`
+		SBOM                  sbomConfiguration       `yaml:"sbom"`
+	}
+
+	// Software bills of materials generated for deployed images by running a syft
+	// compatible image on the target.
+	sbomConfiguration struct {
+		Enabled bool   `env:"DATA_SBOM"`
+		Image   string `env:"DATA_SBOM_IMAGE"`
+	}
+
+	// Per app cache exported by image builds and imported by the next ones. The builder
+	// used by the target must support cache exports.
+	buildCacheConfiguration struct {
+		Enabled bool `env:"DATA_BUILD_CACHE"`
+		MaxSize int  `env:"DATA_BUILD_CACHE_MAX_SIZE" yaml:"max_size"` // In megabytes per app, 0 for no limit
+	}
+
+	// Limits on the disk space used by deployment artifacts, enforced periodically.
+	quotaConfiguration struct {
+		Total    int    `env:"DATA_QUOTA" yaml:",omitempty"`     // In megabytes, 0 for no limit
+		App      int    `env:"DATA_APP_QUOTA" yaml:",omitempty"` // In megabytes, 0 for no limit
+		Interval string `env:"DATA_QUOTA_INTERVAL"`              // How often the usage is computed, 0 to disable
+	}
+
+	// Optional S3 compatible bucket where artifacts are copied, enabled when a bucket is set.
+	s3Configuration struct {
+		Endpoint  string `env:"S3_ENDPOINT" yaml:",omitempty"`
+		Region    string `env:"S3_REGION" yaml:",omitempty"`
+		Bucket    string `env:"S3_BUCKET" yaml:",omitempty"`
+		AccessKey string `env:"S3_ACCESS_KEY" yaml:"access_key,omitempty"`
+		SecretKey string `env:"S3_SECRET_KEY" yaml:"secret_key,omitempty"`
+		PathStyle bool   `env:"S3_PATH_STYLE" yaml:"path_style,omitempty"`
 	}
 
 	// Configuration related to the async jobs runners.
@@ -90,6 +305,184 @@ type (
 		Cleanup      int    `env:"RUNNERS_CLEANUP_COUNT" yaml:"cleanup"`
 	}
 
+	// Configuration related to the collection of resources consumed by apps on targets.
+	resourceUsageConfiguration struct {
+		Interval  string `env:"RESOURCE_USAGE_INTERVAL"`  // How often stats are collected, 0 to disable
+		Retention string `env:"RESOURCE_USAGE_RETENTION"` // How long stats are kept, 0 to keep them forever
+	}
+
+	// Configuration related to the detection of incidents happening to apps containers.
+	incidentsConfiguration struct {
+		Interval string `env:"INCIDENTS_INTERVAL"` // How often targets are checked, 0 to disable
+	}
+
+	// Configuration related to the collection of requests served by the proxy of targets.
+	trafficConfiguration struct {
+		Interval  string `env:"TRAFFIC_INTERVAL"`  // How often access logs are collected, 0 to disable
+		Retention string `env:"TRAFFIC_RETENTION"` // How long samples are kept, 0 to keep them forever
+	}
+
+	// Configuration related to the uptime checks of apps public urls.
+	monitorsConfiguration struct {
+		Interval string `env:"MONITORS_INTERVAL"` // How often due monitors are looked for, 0 to disable
+	}
+
+	// Configuration related to the backups of managed database add-ons.
+	addonsConfiguration struct {
+		BackupInterval  string `env:"ADDONS_BACKUP_INTERVAL" yaml:"backup_interval"`   // How often add-ons are backed up, 0 to disable
+		BackupRetention int    `env:"ADDONS_BACKUP_RETENTION" yaml:"backup_retention"` // Number of successful backups kept per add-on
+	}
+
+	// Configuration related to the upgrade of the proxy deployed on targets.
+	proxyConfiguration struct {
+		UpgradeInterval string `env:"PROXY_UPGRADE_INTERVAL" yaml:"upgrade_interval"` // How often outdated proxies are looked for, 0 to disable
+	}
+
+	// Configuration related to how targets are chosen for apps created without one.
+	targetsConfiguration struct {
+		Selection targetSelectionConfiguration
+		GC        targetsGCConfiguration `yaml:"gc"`
+	}
+
+	// Rules applied to choose the target of an app environment when none is given.
+	targetSelectionConfiguration struct {
+		Labels   string `env:"TARGETS_SELECTION_LABELS" yaml:",omitempty"` // Comma separated labels candidates must have, ie. region=eu,ssd
+		Strategy string `env:"TARGETS_SELECTION_STRATEGY"`                 // least_loaded or round_robin
+	}
+
+	// Periodic removal of dangling images, stopped app containers and unused networks on targets.
+	targetsGCConfiguration struct {
+		Interval      string `env:"TARGETS_GC_INTERVAL"`                             // How often garbage is collected, 0 to disable
+		MinAge        string `env:"TARGETS_GC_MIN_AGE" yaml:"min_age"`               // Only resources created at least this long ago are removed
+		DiskThreshold int    `env:"TARGETS_GC_DISK_THRESHOLD" yaml:"disk_threshold"` // Percentage of used disk space from which garbage is collected, 0 to always collect
+	}
+
+	// Optional scan of images built by deployments, enabled when a scanner is set.
+	scanConfiguration struct {
+		Scanner string `env:"SCAN_SCANNER" yaml:",omitempty"`        // grype or trivy
+		Image   string `env:"SCAN_IMAGE" yaml:",omitempty"`          // Image of the scanner, default to the official one
+		FailOn  string `env:"SCAN_FAIL_ON" yaml:"fail_on,omitempty"` // Minimum severity failing the deployment, empty to never fail
+	}
+
+	// Configuration related to the deployment pipeline.
+	pipelineConfiguration struct {
+		Hooks    []hookConfiguration           `yaml:",omitempty"`
+		Freeze   []freezeWindowConfiguration   `yaml:",omitempty"` // Instance wide freeze windows of production deployments
+		Timeouts pipelineTimeoutsConfiguration `yaml:",omitempty"` // Instance wide timeouts of the pipeline stages
+	}
+
+	// Maximum durations of the deployment pipeline stages, empty for no limit.
+	pipelineTimeoutsConfiguration struct {
+		Fetch       string `env:"PIPELINE_FETCH_TIMEOUT" yaml:",omitempty"`
+		Build       string `env:"PIPELINE_BUILD_TIMEOUT" yaml:",omitempty"`
+		Deploy      string `env:"PIPELINE_DEPLOY_TIMEOUT" yaml:",omitempty"`
+		HealthCheck string `env:"PIPELINE_HEALTH_CHECK_TIMEOUT" yaml:"health_check,omitempty"`
+	}
+
+	// External program plugged into stages of the deployment pipeline, receiving a JSON
+	// description of the deployment on its standard input.
+	hookConfiguration struct {
+		Name    string
+		Command []string // Program and its arguments
+		Stages  []string // post-fetch, pre-deploy or post-deploy
+		Timeout string   `yaml:",omitempty"` // Maximum duration of a run, empty for no limit
+	}
+
+	// Period during which production deployments of every app are not processed, either
+	// recurring on some days and times or a one-off period between two dates.
+	freezeWindowConfiguration struct {
+		Name     string
+		Policy   string   `yaml:",omitempty"` // queue (default) or reject
+		Days     []string `yaml:",omitempty"` // Days of the week, every day if empty
+		From     string   `yaml:",omitempty"` // Time of the day (HH:MM) at which it starts
+		To       string   `yaml:",omitempty"` // Time of the day (HH:MM) at which it ends
+		Timezone string   `yaml:",omitempty"` // Location of days and times, UTC if empty
+		Start    string   `yaml:",omitempty"` // RFC3339 start date of a one-off window
+		End      string   `yaml:",omitempty"` // RFC3339 end date of a one-off window
+	}
+
+	// Records of apps exposed under DNS zones registered on the instance.
+	dnsConfiguration struct {
+		SyncInterval string `env:"DNS_SYNC_INTERVAL" yaml:"sync_interval"` // How often records are checked for drift, 0 to disable
+	}
+
+	// Optional repository describing targets and apps of the instance, enabled when an url is set.
+	gitOpsConfiguration struct {
+		Url      string `env:"GITOPS_URL" yaml:",omitempty"`
+		Branch   string `env:"GITOPS_BRANCH"`
+		Path     string `env:"GITOPS_PATH"`                    // Path of the spec file in the repository
+		Token    string `env:"GITOPS_TOKEN" yaml:",omitempty"` // Access token for private repositories
+		Interval string `env:"GITOPS_INTERVAL"`                // How often the branch is checked for new commits
+		Prune    bool   `env:"GITOPS_PRUNE"`                   // Delete targets and apps not part of the spec
+	}
+
+	// Configuration related to the connections opened to SSH targets.
+	sshConfiguration struct {
+		KeepAliveInterval string `env:"SSH_KEEPALIVE_INTERVAL" yaml:"keepalive_interval"`   // Interval between keepalive messages, 0 to disable
+		KeepAliveCountMax int    `env:"SSH_KEEPALIVE_COUNT_MAX" yaml:"keepalive_count_max"` // Unanswered keepalive messages before reconnecting
+		Persist           string `env:"SSH_PERSIST"`                                        // How long an idle connection is kept open, 0 to disable pooling
+	}
+
+	// Configuration related to the sqlite database tuning.
+	databaseConfiguration struct {
+		JournalMode        string `env:"DATABASE_JOURNAL_MODE" yaml:"journal_mode"`
+		BusyTimeout        string `env:"DATABASE_BUSY_TIMEOUT" yaml:"busy_timeout"`
+		Synchronous        string `env:"DATABASE_SYNCHRONOUS"`
+		CacheSize          int    `env:"DATABASE_CACHE_SIZE" yaml:"cache_size"`
+		CheckpointInterval string `env:"DATABASE_CHECKPOINT_INTERVAL" yaml:"checkpoint_interval"`
+		ReadPoolSize       int    `env:"DATABASE_READ_POOL_SIZE" yaml:"read_pool_size"`
+	}
+
+	// Configuration related to OpenTelemetry traces and metrics export.
+	telemetryConfiguration struct {
+		Endpoint string `env:"TELEMETRY_ENDPOINT" yaml:",omitempty"`
+		Insecure bool   `env:"TELEMETRY_INSECURE" yaml:",omitempty"`
+	}
+
+	// Optional SMTP server used to send email notifications, enabled when a host is set.
+	smtpConfiguration struct {
+		Host     string `env:"SMTP_HOST" yaml:",omitempty"`
+		Port     int    `env:"SMTP_PORT"`
+		Username string `env:"SMTP_USERNAME" yaml:",omitempty"`
+		Password string `env:"SMTP_PASSWORD" yaml:",omitempty"`
+		From     string `env:"SMTP_FROM" yaml:",omitempty"`
+	}
+
+	// Keys used to encrypt secrets stored in the database, such as credentials and
+	// environment variables. The key could be read from a file provided by a KMS.
+	secretsConfiguration struct {
+		Key          string `env:"SECRETS_KEY" yaml:",omitempty"`                        // Default to the HTTP secret
+		KeyFile      string `env:"SECRETS_KEY_FILE" yaml:"key_file,omitempty"`           // File containing the key, takes precedence over the key
+		PreviousKeys string `env:"SECRETS_PREVIOUS_KEYS" yaml:"previous_keys,omitempty"` // Comma separated keys still used to decrypt values not rotated yet
+		Vault        vaultConfiguration
+		Ssm          ssmConfiguration `yaml:"ssm"`
+		Doppler      dopplerConfiguration
+	}
+
+	// Optional HashiCorp Vault server environment variables could reference secrets from,
+	// enabled when an address is set.
+	vaultConfiguration struct {
+		Address   string `env:"VAULT_ADDR" yaml:",omitempty"`
+		Token     string `env:"VAULT_TOKEN" yaml:",omitempty"`
+		Namespace string `env:"VAULT_NAMESPACE" yaml:",omitempty"`
+	}
+
+	// Optional AWS Systems Manager Parameter Store environment variables could reference
+	// secrets from, enabled when a region is set.
+	ssmConfiguration struct {
+		Region    string `env:"SSM_REGION" yaml:",omitempty"`
+		Endpoint  string `env:"SSM_ENDPOINT" yaml:",omitempty"`
+		AccessKey string `env:"SSM_ACCESS_KEY" yaml:"access_key,omitempty"`
+		SecretKey string `env:"SSM_SECRET_KEY" yaml:"secret_key,omitempty"`
+	}
+
+	// Optional Doppler account environment variables could reference secrets from, enabled
+	// when a token is set.
+	dopplerConfiguration struct {
+		Token    string `env:"DOPPLER_TOKEN" yaml:",omitempty"`
+		Endpoint string `env:"DOPPLER_ENDPOINT" yaml:",omitempty"`
+	}
+
 	// internalConfiguration fields not read from the configuration file and use only during specific steps
 	internalConfiguration struct {
 		Email     string `env:"SEELF_ADMIN_EMAIL,ADMIN_EMAIL"`
@@ -104,21 +497,115 @@ func Default(builders ...ConfigurationBuilder) Configuration {
 		Log: logConfiguration{
 			Level:  "info",
 			Format: "console",
+			File: logFileConfiguration{
+				MaxSize:    defaultLogFileMaxSize,
+				MaxBackups: defaultLogFileMaxBackups,
+			},
+			Syslog: logSyslogConfiguration{
+				Tag: defaultLogSyslogTag,
+			},
 		},
 		Data: dataConfiguration{
 			Path:                  defaultDataDirectory,
 			DeploymentDirTemplate: defaultDeploymentDirTemplate,
+			MaxLogSize:            defaultMaxLogSize,
+			ArchiveGracePeriod:    defaultArchiveGracePeriod,
+			TrashRetention:        defaultTrashRetention,
+			Quota: quotaConfiguration{
+				Interval: defaultQuotaInterval,
+			},
+			BuildCache: buildCacheConfiguration{
+				MaxSize: defaultMaxBuildCacheSize,
+			},
+			SBOM: sbomConfiguration{
+				Image: defaultSBOMImage,
+			},
 		},
 		Http: httpConfiguration{
-			Host:   defaultHost,
-			Port:   defaultPort,
-			Secret: generatedSecretKey,
+			Host:           defaultHost,
+			Port:           defaultPort,
+			Secret:         generatedSecretKey,
+			IdempotencyTTL: defaultIdempotencyTTL,
+			Limits: httpLimitsConfiguration{
+				Rate:        defaultRateLimit,
+				Burst:       defaultRateLimitBurst,
+				BodySize:    defaultMaxBodySize,
+				ArchiveSize: defaultMaxArchiveSize,
+			},
+		},
+		Auth: authConfiguration{
+			Session: sessionConfiguration{
+				Lifetime:    defaultSessionLifetime,
+				IdleTimeout: defaultSessionIdleTimeout,
+			},
+			Lockout: lockoutConfiguration{
+				MaxAttempts: defaultLockoutMaxAttempts,
+				Window:      defaultLockoutWindow,
+				Duration:    defaultLockoutDuration,
+			},
+			OIDC: oidcConfiguration{
+				Name: defaultOIDCName,
+			},
 		},
 		Runners: runnersConfiguration{
 			PollInterval: defaultRunnersPollInterval,
 			Deployment:   defaultRunnersDeploymentCount,
 			Cleanup:      defaultCleanupDeploymentCount,
 		},
+		Usage: resourceUsageConfiguration{
+			Interval:  defaultResourceUsageInterval,
+			Retention: defaultResourceUsageRetention,
+		},
+		Incidents: incidentsConfiguration{
+			Interval: defaultIncidentsInterval,
+		},
+		Traffic: trafficConfiguration{
+			Interval:  defaultTrafficInterval,
+			Retention: defaultTrafficRetention,
+		},
+		Monitors: monitorsConfiguration{
+			Interval: defaultMonitorsInterval,
+		},
+		Addons: addonsConfiguration{
+			BackupInterval:  defaultAddonsBackupInterval,
+			BackupRetention: defaultAddonsBackupRetention,
+		},
+		Proxy: proxyConfiguration{
+			UpgradeInterval: defaultProxyUpgradeInterval,
+		},
+		Targets: targetsConfiguration{
+			Selection: targetSelectionConfiguration{
+				Strategy: defaultTargetSelection,
+			},
+			GC: targetsGCConfiguration{
+				Interval: defaultTargetsGCInterval,
+				MinAge:   defaultTargetsGCMinAge,
+			},
+		},
+		Dns: dnsConfiguration{
+			SyncInterval: defaultDnsSyncInterval,
+		},
+		Gitops: gitOpsConfiguration{
+			Branch:   defaultGitOpsBranch,
+			Path:     defaultGitOpsPath,
+			Interval: defaultGitOpsInterval,
+		},
+		Ssh: sshConfiguration{
+			KeepAliveInterval: defaultSSHKeepAliveInterval,
+			KeepAliveCountMax: defaultSSHKeepAliveCountMax,
+			Persist:           defaultSSHPersist,
+		},
+		Database: databaseConfiguration{
+			JournalMode:        defaultDatabaseJournalMode,
+			BusyTimeout:        defaultDatabaseBusyTimeout,
+			Synchronous:        defaultDatabaseSynchronous,
+			CacheSize:          defaultDatabaseCacheSize,
+			CheckpointInterval: defaultDatabaseCheckpoint,
+			ReadPoolSize:       defaultDatabaseReadPoolSize,
+		},
+		Smtp: smtpConfiguration{
+			Port: defaultSmtpPort,
+		},
 	}
 
 	for _, builder := range builders {
@@ -133,6 +620,9 @@ func Default(builders ...ConfigurationBuilder) Configuration {
 }
 
 func (c *configuration) Initialize(logger log.ConfigurableLogger, path string) error {
+	c.path = path
+	c.logger = logger
+
 	exists, err := config.Load(path, c)
 
 	if err != nil {
@@ -140,7 +630,7 @@ func (c *configuration) Initialize(logger log.ConfigurableLogger, path string) e
 	}
 
 	// Update logger based on loaded configuration
-	if err = logger.Configure(c.logFormat, c.logLevel); err != nil {
+	if err = c.configureLogger(nil); err != nil {
 		return err
 	}
 
@@ -164,32 +654,285 @@ func (c *configuration) Initialize(logger log.ConfigurableLogger, path string) e
 	return nil
 }
 
-func (c *configuration) DataDir() string                           { return c.Data.Path }
-func (c *configuration) DeploymentDirTemplate() *template.Template { return c.deploymentDirTemplate }
-func (c *configuration) AppExposedUrl() monad.Maybe[domain.Url]    { return c.appExposedUrl }
-func (c *configuration) DefaultEmail() string                      { return c.Private.Email }
-func (c *configuration) DefaultPassword() string                   { return c.Private.Password }
-func (c *configuration) Secret() []byte                            { return []byte(c.Http.Secret) }
-func (c *configuration) RunnersPollInterval() time.Duration        { return c.pollInterval }
-func (c *configuration) RunnersDeploymentCount() int               { return c.Runners.Deployment }
-func (c *configuration) RunnersCleanupCount() int                  { return c.Runners.Cleanup }
+// Reload settings which could be changed without restarting seelf: log settings,
+// runners poll interval and SMTP server settings. Enabling or disabling the SMTP
+// server still requires a restart since it determines which emails are sent.
+func (c *configuration) Reload() error {
+	fresh := Default().(*configuration)
 
-func (c *configuration) IsSecure() bool {
-	// If secure has been explicitly isSet, returns it
-	if secure, isSet := c.Http.Secure.TryGet(); isSet {
-		return secure
+	if _, err := config.Load(c.path, fresh); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previousModules := c.logModules
+
+	c.Log = fresh.Log
+	c.logLevel = fresh.logLevel
+	c.logFormat = fresh.logFormat
+	c.logModules = fresh.logModules
+	c.Runners.PollInterval = fresh.Runners.PollInterval
+	c.pollInterval = fresh.pollInterval
+
+	if (c.Smtp.Host == "") == (fresh.Smtp.Host == "") {
+		c.Smtp = fresh.Smtp
+	} else {
+		c.logger.Warn("enabling or disabling the SMTP server requires a restart, its settings have not been reloaded")
+	}
+
+	if err := c.configureLogger(previousModules); err != nil {
+		return err
+	}
+
+	c.logger.Infow("configuration reloaded",
+		"path", c.path)
+
+	return nil
+}
+
+func (c *configuration) EnvPrefix() string                             { return envPrefix }
+func (c *configuration) DataDir() string                               { return c.Data.Path }
+func (c *configuration) DeploymentDirTemplate() *template.Template     { return c.deploymentDirTemplate }
+func (c *configuration) AppExposedUrl() monad.Maybe[domain.Url]        { return c.appExposedUrl }
+func (c *configuration) DefaultEmail() string                          { return c.Private.Email }
+func (c *configuration) DefaultPassword() string                       { return c.Private.Password }
+func (c *configuration) Secret() []byte                                { return []byte(c.Http.Secret) }
+func (c *configuration) RunnersDeploymentCount() int                   { return c.Runners.Deployment }
+func (c *configuration) RunnersCleanupCount() int                      { return c.Runners.Cleanup }
+func (c *configuration) DatabaseReadPoolSize() int                     { return c.Database.ReadPoolSize }
+func (c *configuration) DatabaseCheckpointInterval() time.Duration     { return c.checkpointInterval }
+func (c *configuration) TelemetryEndpoint() string                     { return c.Telemetry.Endpoint }
+func (c *configuration) TelemetryInsecure() bool                       { return c.Telemetry.Insecure }
+func (c *configuration) PasswordAuthEnabled() bool                     { return !c.Auth.DisablePassword }
+func (c *configuration) SessionLifetime() time.Duration                { return c.sessionLifetime }
+func (c *configuration) SessionIdleTimeout() time.Duration             { return c.sessionIdleTimeout }
+func (c *configuration) LoginMaxAttempts() int                         { return c.Auth.Lockout.MaxAttempts }
+func (c *configuration) LoginAttemptsWindow() time.Duration            { return c.lockoutWindow }
+func (c *configuration) LoginLockoutDuration() time.Duration           { return c.lockoutDuration }
+func (c *configuration) RateLimit() int                                { return c.Http.Limits.Rate }
+func (c *configuration) RateLimitBurst() int                           { return c.Http.Limits.Burst }
+func (c *configuration) MaxBodySize() int64                            { return int64(c.Http.Limits.BodySize) * megabyte }
+func (c *configuration) MaxArchiveSize() int64                         { return int64(c.Http.Limits.ArchiveSize) * megabyte }
+func (c *configuration) IdempotencyTTL() time.Duration                 { return c.idempotencyTTL }
+func (c *configuration) MaxLogSize() int64                             { return int64(c.Data.MaxLogSize) * megabyte }
+func (c *configuration) BuildCacheEnabled() bool                       { return c.Data.BuildCache.Enabled }
+func (c *configuration) MaxBuildCacheSize() int64                      { return int64(c.Data.BuildCache.MaxSize) * megabyte }
+func (c *configuration) ArtifactsQuota() int64                         { return int64(c.Data.Quota.Total) * megabyte }
+func (c *configuration) AppArtifactsQuota() int64                      { return int64(c.Data.Quota.App) * megabyte }
+func (c *configuration) ArtifactsCollectInterval() time.Duration       { return c.quotaInterval }
+func (c *configuration) AppArchiveGracePeriod() time.Duration          { return c.archiveGracePeriod }
+func (c *configuration) TrashRetention() time.Duration                 { return c.trashRetention }
+func (c *configuration) ResourceUsageInterval() time.Duration          { return c.usageInterval }
+func (c *configuration) ResourceUsageRetention() time.Duration         { return c.usageRetention }
+func (c *configuration) IncidentsInterval() time.Duration              { return c.incidentsInterval }
+func (c *configuration) TrafficInterval() time.Duration                { return c.trafficInterval }
+func (c *configuration) TrafficRetention() time.Duration               { return c.trafficRetention }
+func (c *configuration) MonitorsInterval() time.Duration               { return c.monitorsInterval }
+func (c *configuration) AddonsBackupInterval() time.Duration           { return c.backupInterval }
+func (c *configuration) AddonsBackupRetention() int                    { return c.Addons.BackupRetention }
+func (c *configuration) ProxyUpgradeInterval() time.Duration           { return c.proxyUpgradeInterval }
+func (c *configuration) Hooks() []hook.Hook                            { return c.hooks }
+func (c *configuration) FreezeWindows() domain.FreezeWindows           { return c.freezeWindows }
+func (c *configuration) DeploymentTimeouts() domain.DeploymentTimeouts { return c.timeouts }
+func (c *configuration) TargetSelection() domain.TargetSelection       { return c.targetSelection }
+func (c *configuration) TargetsGCInterval() time.Duration              { return c.targetsGCInterval }
+func (c *configuration) DnsSyncInterval() time.Duration                { return c.dnsSyncInterval }
+func (c *configuration) Keyring() crypto.Keyring                       { return c.keyring }
+
+// Returns thresholds applied when collecting garbage on targets.
+func (c *configuration) TargetsGC() domain.GarbageCollectionOptions {
+	return domain.GarbageCollectionOptions{
+		MinAge:        c.targetsGCMinAge,
+		DiskThreshold: c.Targets.GC.DiskThreshold,
+	}
+}
+
+// Returns the image used to generate software bills of materials if enabled.
+func (c *configuration) SBOMImage() monad.Maybe[string] {
+	if !c.Data.SBOM.Enabled {
+		return monad.None[string]()
+	}
+
+	return monad.Value(c.Data.SBOM.Image)
+}
+
+func (c *configuration) RunnersPollInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.pollInterval
+}
+
+func (c *configuration) OIDC() (m monad.Maybe[oidc.Options]) {
+	if c.Auth.OIDC.Issuer == "" {
+		return m
+	}
+
+	m.Set(oidc.Options{
+		Name:         c.Auth.OIDC.Name,
+		Issuer:       c.Auth.OIDC.Issuer,
+		ClientID:     c.Auth.OIDC.ClientID,
+		ClientSecret: c.Auth.OIDC.ClientSecret,
+	})
+
+	return m
+}
+
+func (c *configuration) SMTP() (m monad.Maybe[mail.Options]) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.Smtp.Host == "" {
+		return m
+	}
+
+	m.Set(mail.Options{
+		Host:     c.Smtp.Host,
+		Port:     c.Smtp.Port,
+		Username: c.Smtp.Username,
+		Password: c.Smtp.Password,
+		From:     c.Smtp.From,
+	})
+
+	return m
+}
+
+func (c *configuration) S3() (m monad.Maybe[s3.Options]) {
+	if c.Data.S3.Bucket == "" {
+		return m
+	}
+
+	m.Set(s3.Options{
+		Endpoint:  c.Data.S3.Endpoint,
+		Region:    c.Data.S3.Region,
+		Bucket:    c.Data.S3.Bucket,
+		AccessKey: c.Data.S3.AccessKey,
+		SecretKey: c.Data.S3.SecretKey,
+		PathStyle: c.Data.S3.PathStyle,
+	})
+
+	return m
+}
+
+// Returns how images built by deployments should be scanned if enabled.
+func (c *configuration) VulnerabilityScan() (m monad.Maybe[docker.ScanOptions]) {
+	if c.Scan.Scanner == "" {
+		return m
+	}
+
+	options := docker.ScanOptions{
+		Scanner: c.Scan.Scanner,
+		FailOn:  c.scanFailOn,
+	}
+
+	if c.Scan.Image != "" {
+		options.Image.Set(c.Scan.Image)
+	}
+
+	m.Set(options)
+
+	return m
+}
+
+// Returns the GitOps repository to reconcile the instance with if enabled.
+func (c *configuration) GitOps() (m monad.Maybe[gitops.Options]) {
+	if c.Gitops.Url == "" {
+		return m
+	}
+
+	options := gitops.Options{
+		Url:      c.Gitops.Url,
+		Branch:   c.Gitops.Branch,
+		Path:     c.Gitops.Path,
+		Prune:    c.Gitops.Prune,
+		Interval: c.gitOpsInterval,
+	}
+
+	if c.Gitops.Token != "" {
+		options.Token.Set(c.Gitops.Token)
 	}
 
-	if defaultTargetUrl, isSet := c.appExposedUrl.TryGet(); isSet {
-		return defaultTargetUrl.UseSSL()
+	m.Set(options)
+
+	return m
+}
+
+// Returns the Vault server secrets could be referenced from if enabled.
+func (c *configuration) Vault() (m monad.Maybe[secret.VaultOptions]) {
+	if c.Secrets.Vault.Address == "" {
+		return m
 	}
 
-	return false
+	m.Set(secret.VaultOptions{
+		Address:   c.Secrets.Vault.Address,
+		Token:     c.Secrets.Vault.Token,
+		Namespace: c.Secrets.Vault.Namespace,
+	})
+
+	return m
 }
 
-// Gets the connection string to be used.
+// Returns the Parameter Store secrets could be referenced from if enabled.
+func (c *configuration) SSM() (m monad.Maybe[secret.SSMOptions]) {
+	if c.Secrets.Ssm.Region == "" {
+		return m
+	}
+
+	m.Set(secret.SSMOptions{
+		Region:    c.Secrets.Ssm.Region,
+		Endpoint:  c.Secrets.Ssm.Endpoint,
+		AccessKey: c.Secrets.Ssm.AccessKey,
+		SecretKey: c.Secrets.Ssm.SecretKey,
+	})
+
+	return m
+}
+
+// Returns the Doppler account secrets could be referenced from if enabled.
+func (c *configuration) Doppler() (m monad.Maybe[secret.DopplerOptions]) {
+	if c.Secrets.Doppler.Token == "" {
+		return m
+	}
+
+	m.Set(secret.DopplerOptions{
+		Token:    c.Secrets.Doppler.Token,
+		Endpoint: c.Secrets.Doppler.Endpoint,
+	})
+
+	return m
+}
+
+func (c *configuration) SSHKeepAlive() ssh.KeepAlive {
+	return ssh.KeepAlive{
+		Interval: c.sshKeepAliveInterval,
+		CountMax: c.Ssh.KeepAliveCountMax,
+		Persist:  c.sshPersist,
+	}
+}
+
+func (c *configuration) IsSecure() bool {
+	// If secure has been explicitly set, returns it, else determine it from the exposed url
+	return c.Http.Secure.OrElse(func() bool {
+		return monad.Map(c.appExposedUrl, domain.Url.UseSSL).Get(false)
+	})
+}
+
+// Gets the path of the database file.
+func (c *configuration) DatabasePath() string {
+	return path.Join(c.Data.Path, databaseFilename)
+}
+
+// Gets the connection string to be used with configured pragmas applied.
 func (c *configuration) ConnectionString() string {
-	return "file:" + path.Join(c.Data.Path, databaseConnectionString)
+	return fmt.Sprintf("file:%s?_journal=%s&_timeout=%d&_sync=%s&_cache_size=%d&_foreign_keys=yes&_txlock=immediate",
+		c.DatabasePath(),
+		strings.ToUpper(c.Database.JournalMode),
+		c.busyTimeout.Milliseconds(),
+		strings.ToUpper(c.Database.Synchronous),
+		c.Database.CacheSize,
+	)
 }
 
 // Returns the address to bind the HTTP server to.
@@ -197,14 +940,119 @@ func (c *configuration) ListenAddress() string {
 	return c.Http.Host + ":" + strconv.Itoa(c.Http.Port)
 }
 
+// Returns the address to bind the gRPC server to if enabled.
+func (c *configuration) GRPCListenAddress() (m monad.Maybe[string]) {
+	if c.Grpc.Port == 0 {
+		return m
+	}
+
+	m.Set(c.Http.Host + ":" + strconv.Itoa(c.Grpc.Port))
+
+	return m
+}
+
 func (c *configuration) PostLoad() error {
 	return validate.Struct(validate.Of{
 		"log.level":                    validate.Value(c.Log.Level, &c.logLevel, log.ParseLevel),
 		"log.format":                   validate.Value(c.Log.Format, &c.logFormat, log.ParseFormat),
+		"log.modules":                  validate.Value(c.Log.Modules, &c.logModules, log.ParseModuleLevels),
+		"grpc.port":                    validate.Field(c.Grpc.Port, numbers.Min(0)),
+		"http.limits.rate":             validate.Field(c.Http.Limits.Rate, numbers.Min(0)),
+		"http.limits.burst":            validate.Field(c.Http.Limits.Burst, numbers.Min(1)),
+		"http.limits.body_size":        validate.Field(c.Http.Limits.BodySize, numbers.Min(1)),
+		"http.limits.archive_size":     validate.Field(c.Http.Limits.ArchiveSize, numbers.Min(1)),
+		"http.idempotency_ttl":         validate.Value(c.Http.IdempotencyTTL, &c.idempotencyTTL, time.ParseDuration),
+		"log.file.max_size":            validate.Field(c.Log.File.MaxSize, numbers.Min(0)),
+		"log.file.max_backups":         validate.Field(c.Log.File.MaxBackups, numbers.Min(0)),
 		"data.deployment_dir_template": validate.Value(c.Data.DeploymentDirTemplate, &c.deploymentDirTemplate, template.New("").Parse),
+		"data.max_log_size":            validate.Field(c.Data.MaxLogSize, numbers.Min(0)),
+		"data.build_cache.max_size":    validate.Field(c.Data.BuildCache.MaxSize, numbers.Min(0)),
+		"data.quota.total":             validate.Field(c.Data.Quota.Total, numbers.Min(0)),
+		"data.quota.app":               validate.Field(c.Data.Quota.App, numbers.Min(0)),
+		"data.quota.interval":          validate.Value(c.Data.Quota.Interval, &c.quotaInterval, time.ParseDuration),
+		"data.archive_grace_period":    validate.Value(c.Data.ArchiveGracePeriod, &c.archiveGracePeriod, time.ParseDuration),
+		"data.trash_retention":         validate.Value(c.Data.TrashRetention, &c.trashRetention, time.ParseDuration),
 		"runners.poll_interval":        validate.Value(c.Runners.PollInterval, &c.pollInterval, time.ParseDuration),
+		"resource_usage.interval":      validate.Value(c.Usage.Interval, &c.usageInterval, time.ParseDuration),
+		"resource_usage.retention":     validate.Value(c.Usage.Retention, &c.usageRetention, time.ParseDuration),
+		"incidents.interval":           validate.Value(c.Incidents.Interval, &c.incidentsInterval, time.ParseDuration),
+		"traffic.interval":             validate.Value(c.Traffic.Interval, &c.trafficInterval, time.ParseDuration),
+		"traffic.retention":            validate.Value(c.Traffic.Retention, &c.trafficRetention, time.ParseDuration),
+		"monitors.interval":            validate.Value(c.Monitors.Interval, &c.monitorsInterval, time.ParseDuration),
+		"addons.backup_interval":       validate.Value(c.Addons.BackupInterval, &c.backupInterval, time.ParseDuration),
+		"addons.backup_retention":      validate.Field(c.Addons.BackupRetention, numbers.Min(1)),
+		"proxy.upgrade_interval":       validate.Value(c.Proxy.UpgradeInterval, &c.proxyUpgradeInterval, time.ParseDuration),
+		"targets.selection":            c.parseTargetSelection(),
+		"targets.gc.interval":          validate.Value(c.Targets.GC.Interval, &c.targetsGCInterval, time.ParseDuration),
+		"targets.gc.min_age":           validate.Value(c.Targets.GC.MinAge, &c.targetsGCMinAge, time.ParseDuration),
+		"targets.gc.disk_threshold":    validate.Field(c.Targets.GC.DiskThreshold, numbers.Min(0), numbers.Max(100)),
+		"dns.sync_interval":            validate.Value(c.Dns.SyncInterval, &c.dnsSyncInterval, time.ParseDuration),
 		"runners.deployment":           validate.Field(c.Runners.Deployment, numbers.Min(1)),
 		"runners.cleanup":              validate.Field(c.Runners.Cleanup, numbers.Min(1)),
+		"database.journal_mode":        validate.Field(c.Database.JournalMode, vstrings.Match(databaseJournalModes)),
+		"database.busy_timeout":        validate.Value(c.Database.BusyTimeout, &c.busyTimeout, time.ParseDuration),
+		"database.synchronous":         validate.Field(c.Database.Synchronous, vstrings.Match(databaseSynchronousModes)),
+		"database.checkpoint_interval": validate.Value(c.Database.CheckpointInterval, &c.checkpointInterval, time.ParseDuration),
+		"database.read_pool_size":      validate.Field(c.Database.ReadPoolSize, numbers.Min(0)),
+		"auth.session.lifetime":        validate.Value(c.Auth.Session.Lifetime, &c.sessionLifetime, time.ParseDuration),
+		"auth.session.idle_timeout":    validate.Value(c.Auth.Session.IdleTimeout, &c.sessionIdleTimeout, time.ParseDuration),
+		"auth.lockout.max_attempts":    validate.Field(c.Auth.Lockout.MaxAttempts, numbers.Min(0)),
+		"auth.lockout.window":          validate.Value(c.Auth.Lockout.Window, &c.lockoutWindow, time.ParseDuration),
+		"auth.lockout.duration":        validate.Value(c.Auth.Lockout.Duration, &c.lockoutDuration, time.ParseDuration),
+		// Disabling password authentication without a provider would prevent everyone from logging in
+		"auth.oidc.issuer": validate.If(c.Auth.DisablePassword, func() error {
+			return vstrings.Required(c.Auth.OIDC.Issuer)
+		}),
+		"auth.oidc.client_id": validate.If(c.Auth.OIDC.Issuer != "", func() error {
+			return vstrings.Required(c.Auth.OIDC.ClientID)
+		}),
+		"data.s3.endpoint": validate.If(c.Data.S3.Bucket != "", func() error {
+			return vstrings.Required(c.Data.S3.Endpoint)
+		}),
+		"data.sbom.image": validate.If(c.Data.SBOM.Enabled, func() error {
+			return vstrings.Required(c.Data.SBOM.Image)
+		}),
+		"scan.scanner": validate.If(c.Scan.Scanner != "", func() error {
+			return vstrings.Match(scanners)(c.Scan.Scanner)
+		}),
+		"scan.fail_on": validate.If(c.Scan.FailOn != "", func() error {
+			var severity domain.VulnerabilitySeverity
+
+			if err := validate.Value(c.Scan.FailOn, &severity, domain.VulnerabilitySeverityFrom); err != nil {
+				return err
+			}
+
+			c.scanFailOn.Set(severity)
+
+			return nil
+		}),
+		"pipeline.hooks":    c.parseHooks(),
+		"pipeline.freeze":   c.parseFreezeWindows(),
+		"pipeline.timeouts": c.parseTimeouts(),
+		"secrets":           c.parseSecrets(),
+		"gitops.branch": validate.If(c.Gitops.Url != "", func() error {
+			return vstrings.Required(c.Gitops.Branch)
+		}),
+		"gitops.path": validate.If(c.Gitops.Url != "", func() error {
+			return vstrings.Required(c.Gitops.Path)
+		}),
+		"gitops.interval":         validate.Value(c.Gitops.Interval, &c.gitOpsInterval, time.ParseDuration),
+		"ssh.keepalive_interval":  validate.Value(c.Ssh.KeepAliveInterval, &c.sshKeepAliveInterval, time.ParseDuration),
+		"ssh.keepalive_count_max": validate.Field(c.Ssh.KeepAliveCountMax, numbers.Min(1)),
+		"ssh.persist":             validate.Value(c.Ssh.Persist, &c.sshPersist, time.ParseDuration),
+		"secrets.vault.token": validate.If(c.Secrets.Vault.Address != "", func() error {
+			return vstrings.Required(c.Secrets.Vault.Token)
+		}),
+		"secrets.ssm.access_key": validate.If(c.Secrets.Ssm.Region != "", func() error {
+			return vstrings.Required(c.Secrets.Ssm.AccessKey)
+		}),
+		"secrets.ssm.secret_key": validate.If(c.Secrets.Ssm.Region != "", func() error {
+			return vstrings.Required(c.Secrets.Ssm.SecretKey)
+		}),
+		"smtp.port": validate.Field(c.Smtp.Port, numbers.Min(1)),
+		"smtp.from": validate.If(c.Smtp.Host != "", func() error {
+			return vstrings.Required(c.Smtp.From)
+		}),
 		"exposed_as": validate.If(c.Private.ExposedOn != "", func() error {
 			url, err := domain.UrlFrom(c.Private.ExposedOn)
 
@@ -219,6 +1067,233 @@ func (c *configuration) PostLoad() error {
 	})
 }
 
+// Parses rules used to choose the target of new apps.
+func (c *configuration) parseTargetSelection() (err error) {
+	var labels domain.Labels
+
+	if raw := c.Targets.Selection.Labels; raw != "" {
+		if labels, err = domain.LabelsFrom(strings.Split(raw, ",")); err != nil {
+			return err
+		}
+	}
+
+	c.targetSelection, err = domain.TargetSelectionFrom(labels, c.Targets.Selection.Strategy)
+
+	return err
+}
+
+// Builds the keyring used to encrypt secrets stored in the database.
+func (c *configuration) parseSecrets() (err error) {
+	key := c.Secrets.Key
+
+	if c.Secrets.KeyFile != "" {
+		content, err := os.ReadFile(c.Secrets.KeyFile)
+
+		if err != nil {
+			return err
+		}
+
+		key = strings.TrimSpace(string(content))
+	}
+
+	if key == "" {
+		key = c.Http.Secret
+	}
+
+	var previous [][]byte
+
+	for _, k := range strings.Split(c.Secrets.PreviousKeys, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			previous = append(previous, []byte(k))
+		}
+	}
+
+	c.keyring, err = crypto.NewKeyring([]byte(key), previous...)
+
+	return err
+}
+
+// Parses instance wide timeouts of the deployment pipeline stages.
+func (c *configuration) parseTimeouts() error {
+	c.timeouts = domain.DeploymentTimeouts{}
+	definition := c.Pipeline.Timeouts
+
+	return validate.Struct(validate.Of{
+		"fetch": validate.If(definition.Fetch != "", func() error {
+			return validate.Value(definition.Fetch, &c.timeouts.Fetch, time.ParseDuration)
+		}),
+		"build": validate.If(definition.Build != "", func() error {
+			return validate.Value(definition.Build, &c.timeouts.Build, time.ParseDuration)
+		}),
+		"deploy": validate.If(definition.Deploy != "", func() error {
+			return validate.Value(definition.Deploy, &c.timeouts.Deploy, time.ParseDuration)
+		}),
+		"health_check": validate.If(definition.HealthCheck != "", func() error {
+			return validate.Value(definition.HealthCheck, &c.timeouts.HealthCheck, time.ParseDuration)
+		}),
+	})
+}
+
+// Parses hooks plugged into the deployment pipeline.
+func (c *configuration) parseHooks() error {
+	c.hooks = make([]hook.Hook, len(c.Pipeline.Hooks))
+	definitions := make(validate.Of, len(c.Pipeline.Hooks))
+
+	for i, definition := range c.Pipeline.Hooks {
+		parsed := &c.hooks[i]
+		parsed.Name = definition.Name
+		parsed.Command = definition.Command
+		parsed.Stages = make([]domain.HookStage, len(definition.Stages))
+		stages := make(validate.Of, len(definition.Stages))
+
+		for j, stage := range definition.Stages {
+			stages[strconv.Itoa(j)] = validate.Value(stage, &parsed.Stages[j], domain.HookStageFrom)
+		}
+
+		stagesErr := validate.Struct(stages)
+
+		if len(definition.Stages) == 0 {
+			stagesErr = vstrings.ErrRequired
+		}
+
+		definitions[strconv.Itoa(i)] = validate.Struct(validate.Of{
+			"name": vstrings.Required(definition.Name),
+			"command": validate.If(len(definition.Command) == 0, func() error {
+				return vstrings.ErrRequired
+			}),
+			"stages": stagesErr,
+			"timeout": validate.If(definition.Timeout != "", func() error {
+				return validate.Value(definition.Timeout, &parsed.Timeout, time.ParseDuration)
+			}),
+		})
+	}
+
+	return validate.Struct(definitions)
+}
+
+// Parses instance wide freeze windows of production deployments.
+func (c *configuration) parseFreezeWindows() error {
+	c.freezeWindows = make(domain.FreezeWindows, len(c.Pipeline.Freeze))
+	definitions := make(validate.Of, len(c.Pipeline.Freeze))
+
+	for i, definition := range c.Pipeline.Freeze {
+		var (
+			policy     domain.FreezePolicy
+			days       = make([]string, len(definition.Days))
+			dayErrs    = make(validate.Of, len(definition.Days))
+			from, to   string
+			timezone   string
+			start, end monad.Maybe[time.Time]
+		)
+
+		for j, day := range definition.Days {
+			dayErrs[strconv.Itoa(j)] = validate.Value(day, &days[j], domain.FreezeDayFrom)
+		}
+
+		if err := validate.Struct(validate.Of{
+			"name":   vstrings.Required(definition.Name),
+			"policy": validate.Value(definition.Policy, &policy, domain.FreezePolicyFrom),
+			"days":   validate.Struct(dayErrs),
+			"from": validate.If(definition.From != "", func() error {
+				return validate.Value(definition.From, &from, domain.FreezeTimeFrom)
+			}),
+			"to": validate.If(definition.To != "", func() error {
+				return validate.Value(definition.To, &to, domain.FreezeTimeFrom)
+			}),
+			"timezone": validate.If(definition.Timezone != "", func() error {
+				return validate.Value(definition.Timezone, &timezone, domain.TimezoneFrom)
+			}),
+			"start": parseFreezeMoment(definition.Start, &start),
+			"end":   parseFreezeMoment(definition.End, &end),
+		}); err != nil {
+			definitions[strconv.Itoa(i)] = err
+			continue
+		}
+
+		window, err := domain.NewFreezeWindow(definition.Name, policy, days, from, to, timezone, start, end)
+
+		if err != nil {
+			definitions[strconv.Itoa(i)] = err
+			continue
+		}
+
+		c.freezeWindows[i] = window
+	}
+
+	return validate.Struct(definitions)
+}
+
+// Parses an optional RFC3339 date bounding a one-off freeze window.
+func parseFreezeMoment(value string, target *monad.Maybe[time.Time]) error {
+	if value == "" {
+		return nil
+	}
+
+	moment, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return err
+	}
+
+	target.Set(moment)
+
+	return nil
+}
+
+// Apply log settings to the logger. Module levels given as previous which are not
+// configured anymore are reset.
+func (c *configuration) configureLogger(previousModules map[string]log.Level) error {
+	sinks, err := c.logSinks()
+
+	if err != nil {
+		return err
+	}
+
+	if err = c.logger.Configure(c.logFormat, c.logLevel, sinks...); err != nil {
+		return err
+	}
+
+	for module := range previousModules {
+		if _, configured := c.logModules[module]; !configured {
+			c.logger.ResetModuleLevel(module)
+		}
+	}
+
+	for module, lvl := range c.logModules {
+		c.logger.SetModuleLevel(module, lvl)
+	}
+
+	return nil
+}
+
+// Builds the log sinks based on the configuration. The standard error is always
+// written to, file and syslog are added when configured.
+func (c *configuration) logSinks() ([]log.Sink, error) {
+	sinks := []log.Sink{log.Stderr()}
+
+	if c.Log.File.Path != "" {
+		file, err := log.NewFileSink(c.Log.File.Path, c.Log.File.MaxSize, c.Log.File.MaxBackups)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, file)
+	}
+
+	if c.Log.Syslog.Enabled {
+		syslog, err := log.NewSyslogSink(c.Log.Syslog.Address, c.Log.Syslog.Tag)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, syslog)
+	}
+
+	return sinks, nil
+}
+
 // Configuration builder used to set some tests sensible defaults.
 // Generates a random data directory path to avoid conflicts with other tests.
 func WithTestDefaults() ConfigurationBuilder {
@@ -0,0 +1,167 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var ErrUnknownModule = errors.New("unknown migrations module")
+
+type Options interface {
+	ConnectionString() string
+}
+
+// Returns the root migrate command used to inspect and manage database migrations.
+func Root(opts Options, logger log.Logger) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database migrations",
+	}
+
+	migrateCmd.AddCommand(
+		statusCmd(opts, logger),
+		upCmd(opts, logger),
+		downCmd(opts, logger),
+	)
+
+	return migrateCmd
+}
+
+func statusCmd(opts Options, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List applied and pending migrations for every module",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(opts, logger, func(db *sqlite.Database) error {
+				statuses, err := db.MigrationsStatus(startup.MigrationsModules...)
+
+				if err != nil {
+					return err
+				}
+
+				printStatuses(cmd.OutOrStdout(), statuses)
+
+				return nil
+			})
+		},
+	}
+}
+
+func upCmd(opts Options, logger log.Logger) *cobra.Command {
+	var dryRun bool
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(opts, logger, func(db *sqlite.Database) error {
+				if dryRun {
+					statuses, err := db.MigrationsStatus(startup.MigrationsModules...)
+
+					if err != nil {
+						return err
+					}
+
+					printStatuses(cmd.OutOrStdout(), statuses)
+
+					return nil
+				}
+
+				return db.Migrate(startup.MigrationsModules...)
+			})
+		},
+	}
+
+	upCmd.Flags().BoolVar(&dryRun, "dry-run", false, "only list pending migrations without applying them")
+
+	return upCmd
+}
+
+func downCmd(opts Options, logger log.Logger) *cobra.Command {
+	var steps int
+
+	downCmd := &cobra.Command{
+		Use:   "down <module>",
+		Short: "Revert the last applied migrations of a module",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			module, err := findModule(args[0])
+
+			if err != nil {
+				return err
+			}
+
+			return withDatabase(opts, logger, func(db *sqlite.Database) error {
+				return db.Rollback(module, steps)
+			})
+		},
+	}
+
+	downCmd.Flags().IntVarP(&steps, "steps", "n", 1, "number of migrations to revert")
+
+	return downCmd
+}
+
+func withDatabase(opts Options, logger log.Logger, fn func(*sqlite.Database) error) error {
+	db, err := sqlite.Open(opts.ConnectionString(), logger, memory.NewBus())
+
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	return fn(db)
+}
+
+func findModule(name string) (sqlite.MigrationsModule, error) {
+	names := make([]string, len(startup.MigrationsModules))
+
+	for i, module := range startup.MigrationsModules {
+		if module.Name() == name {
+			return module, nil
+		}
+
+		names[i] = module.Name()
+	}
+
+	return sqlite.MigrationsModule{}, fmt.Errorf("%w %s, expected one of %s", ErrUnknownModule, name, strings.Join(names, ", "))
+}
+
+func printStatuses(out io.Writer, statuses []sqlite.MigrationsStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "MODULE\tCURRENT\tLATEST\tSTATE")
+
+	for _, status := range statuses {
+		var state string
+
+		switch {
+		case status.Dirty:
+			state = "dirty"
+		case status.IsNewerThanBinary():
+			state = "newer than binary"
+		case len(status.Pending) > 0:
+			state = fmt.Sprintf("%d pending", len(status.Pending))
+		default:
+			state = "up to date"
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", status.Module, status.Current, status.Latest, state)
+
+		for _, migration := range status.Pending {
+			fmt.Fprintf(w, "\t\t%d\t%s\n", migration.Version, migration.Name)
+		}
+	}
+
+	w.Flush()
+}
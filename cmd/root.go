@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"github.com/YuukanOO/seelf/cmd/backup"
+	"github.com/YuukanOO/seelf/cmd/cli"
 	"github.com/YuukanOO/seelf/cmd/config"
+	"github.com/YuukanOO/seelf/cmd/migrate"
+	"github.com/YuukanOO/seelf/cmd/secrets"
 	"github.com/YuukanOO/seelf/cmd/serve"
+	"github.com/YuukanOO/seelf/cmd/update"
 	"github.com/YuukanOO/seelf/cmd/version"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/spf13/cobra"
@@ -36,6 +41,12 @@ func Root() *cobra.Command {
 
 	// Add sub-commands
 	rootCmd.AddCommand(serve.Root(conf, logger))
+	rootCmd.AddCommand(migrate.Root(conf, logger))
+	rootCmd.AddCommand(backup.Export(conf, logger))
+	rootCmd.AddCommand(backup.Import(conf, logger))
+	rootCmd.AddCommand(secrets.Root(conf, logger))
+	rootCmd.AddCommand(update.Command(logger))
+	rootCmd.AddCommand(cli.Commands()...)
 
 	return rootCmd
 }
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+type Options interface {
+	ConnectionString() string
+	Keyring() crypto.Keyring
+}
+
+// Returns the root secrets command used to manage secrets encrypted at rest.
+func Root(opts Options, logger log.Logger) *cobra.Command {
+	secretsCmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage secrets encrypted in the database",
+	}
+
+	secretsCmd.AddCommand(rotateCmd(opts, logger))
+
+	return secretsCmd
+}
+
+func rotateCmd(opts Options, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "Encrypt again every stored secret with the current key",
+		Long: `Encrypt again every stored secret with the current key, including the ones
+written before secrets were encrypted. Keys used to encrypt existing secrets must
+still be listed in the previous keys for them to be decrypted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := sqlite.Open(opts.ConnectionString(), logger, memory.NewBus(), sqlite.WithKeyring(opts.Keyring()))
+
+			if err != nil {
+				return err
+			}
+
+			defer db.Close()
+
+			if err = db.Migrate(startup.MigrationsModules...); err != nil {
+				return err
+			}
+
+			count, err := db.RotateSecrets(context.Background(), startup.EncryptedColumns...)
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d secrets encrypted with the current key\n", count)
+
+			return nil
+		},
+	}
+}
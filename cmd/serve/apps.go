@@ -1,13 +1,52 @@
 package serve
 
 import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/clear_build_cache"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_proxy_rules"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_timeouts"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_addon"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_env_vars"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_addon_backups"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_addons"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_archive"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_archives"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_env_revisions"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_logs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_traffic"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_trashed_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/import_env_vars"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_proxy_rules"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_restore"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restart_services"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_env_revision"
+	"github.com/YuukanOO/seelf/internal/deployment/app/scale_service"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/gin-gonic/gin"
 )
 
@@ -53,9 +92,22 @@ func (s *server) updateAppHandler() gin.HandlerFunc {
 	})
 }
 
+type listAppsFilters struct {
+	TeamID string   `form:"team_id"`
+	Labels []string `form:"labels"`
+}
+
 func (s *server) listAppsHandler() gin.HandlerFunc {
-	return http.Send(s, func(ctx *gin.Context) error {
-		apps, err := bus.Send(s.bus, ctx.Request.Context(), get_apps.Query{})
+	return http.Bind(s, func(ctx *gin.Context, request listAppsFilters) error {
+		query := get_apps.Query{
+			Labels: request.Labels,
+		}
+
+		if request.TeamID != "" {
+			query.TeamID.Set(request.TeamID)
+		}
+
+		apps, err := bus.Send(s.bus, ctx.Request.Context(), query)
 
 		if err != nil {
 			return err
@@ -79,10 +131,663 @@ func (s *server) getAppByIDHandler() gin.HandlerFunc {
 	})
 }
 
+func (s *server) clearBuildCacheHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), clear_build_cache.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// FIXME: till gin support custom types in query binding...
+type getAppLogsFilters struct {
+	Environment string   `form:"environment"`
+	Services    []string `form:"service"`
+	Tail        *int     `form:"tail"`
+	Since       string   `form:"since"` // Either a duration relative to now or a RFC3339 date
+	Follow      bool     `form:"follow"`
+}
+
+func (s *server) streamAppLogsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getAppLogsFilters) error {
+		query := get_app_logs.Query{
+			AppID:       ctx.Param("id"),
+			Environment: request.Environment,
+			Services:    request.Services,
+			Follow:      request.Follow,
+		}
+
+		if request.Tail != nil {
+			query.Tail.Set(*request.Tail)
+		}
+
+		if request.Since != "" {
+			since, err := parseSince(request.Since)
+
+			if err != nil {
+				return err
+			}
+
+			query.Since.Set(since)
+		}
+
+		stream, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("X-Accel-Buffering", "no") // Disable nginx buffering
+		ctx.Writer.Flush()
+
+		keepAlive := time.NewTicker(logStreamKeepAlive)
+		defer keepAlive.Stop()
+
+		for stream.Lines != nil {
+			select {
+			case line, ok := <-stream.Lines:
+				if !ok {
+					stream.Lines = nil
+					continue
+				}
+
+				ctx.SSEvent(logLineEvent, line.Container+" | "+line.Message)
+				ctx.Writer.Flush()
+			case <-keepAlive.C:
+				ctx.Writer.WriteString(": keep-alive\n\n")
+				ctx.Writer.Flush()
+			case <-ctx.Request.Context().Done():
+				return nil
+			}
+		}
+
+		// Headers have already been sent so the error is reported as an event
+		if err = <-stream.Err; err != nil {
+			s.logger.Errorw("could not retrieve app logs",
+				"app", query.AppID,
+				"error", err)
+			ctx.SSEvent(logErrorEvent, err.Error())
+		}
+
+		ctx.SSEvent(logEndEvent, "")
+		ctx.Writer.Flush()
+
+		return nil
+	})
+}
+
+// FIXME: till gin support custom types in query binding...
+type getAppResourceUsageFilters struct {
+	Environment string `form:"environment"`
+	Service     string `form:"service"`
+	Since       string `form:"since"` // Either a duration relative to now or a RFC3339 date
+}
+
+func (s *server) getAppResourceUsageHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getAppResourceUsageFilters) error {
+		query := get_app_resource_usage.Query{
+			AppID: ctx.Param("id"),
+		}
+
+		if request.Environment != "" {
+			query.Environment.Set(request.Environment)
+		}
+
+		if request.Service != "" {
+			query.Service.Set(request.Service)
+		}
+
+		if request.Since != "" {
+			since, err := parseSince(request.Since)
+
+			if err != nil {
+				return err
+			}
+
+			query.Since.Set(since)
+		}
+
+		samples, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, samples)
+	})
+}
+
+// FIXME: till gin support custom types in query binding...
+type getAppTrafficFilters struct {
+	Environment string `form:"environment"`
+	Service     string `form:"service"`
+	Since       string `form:"since"` // Either a duration relative to now or a RFC3339 date
+}
+
+func (s *server) getAppTrafficHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getAppTrafficFilters) error {
+		query := get_app_traffic.Query{
+			AppID: ctx.Param("id"),
+		}
+
+		if request.Environment != "" {
+			query.Environment.Set(request.Environment)
+		}
+
+		if request.Service != "" {
+			query.Service.Set(request.Service)
+		}
+
+		if request.Since != "" {
+			since, err := parseSince(request.Since)
+
+			if err != nil {
+				return err
+			}
+
+			query.Since.Set(since)
+		}
+
+		traffic, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, traffic)
+	})
+}
+
+// FIXME: till gin support custom types in query binding...
+type getAppIncidentsFilters struct {
+	Page        int    `form:"page"`
+	Environment string `form:"environment"`
+}
+
+func (s *server) listAppIncidentsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getAppIncidentsFilters) error {
+		query := get_app_incidents.Query{
+			AppID: ctx.Param("id"),
+		}
+
+		if request.Environment != "" {
+			query.Environment.Set(request.Environment)
+		}
+
+		if request.Page != 0 {
+			query.Page.Set(request.Page)
+		}
+
+		incidents, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, incidents)
+	})
+}
+
+func (s *server) listAppMonitorsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		monitors, err := bus.Send(s.bus, ctx.Request.Context(), get_app_monitors.Query{
+			AppID: ctx.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, monitors)
+	})
+}
+
+func (s *server) configureMonitorHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_monitor.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.Environment = ctx.Param("environment")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) deleteMonitorHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_monitor.Command{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("environment"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) configureAccessProtectionHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_access_protection.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.Environment = ctx.Param("environment")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) removeAccessProtectionHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), remove_access_protection.Command{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("environment"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) configureProxyRulesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_proxy_rules.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.Environment = ctx.Param("environment")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) removeProxyRulesHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), remove_proxy_rules.Command{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("environment"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) configureComposeOverrideHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_compose_override.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.Environment = ctx.Param("environment")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) removeComposeOverrideHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), remove_compose_override.Command{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("environment"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) configureUrlAliasesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_url_aliases.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.Environment = ctx.Param("environment")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) removeUrlAliasesHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), remove_url_aliases.Command{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("environment"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) scaleServiceHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd scale_service.Command) error {
+		cmd.AppID = ctx.Param("id")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) restartServicesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd restart_services.Command) error {
+		cmd.AppID = ctx.Param("id")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) configureTimeoutsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_timeouts.Command) error {
+		cmd.ID = ctx.Param("id")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listAppAddonsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		addons, err := bus.Send(s.bus, ctx.Request.Context(), get_app_addons.Query{
+			AppID: ctx.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, addons)
+	})
+}
+
+func (s *server) createAddonHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd create_addon.Command) error {
+		context := ctx.Request.Context()
+		cmd.AppID = ctx.Param("id")
+
+		id, err := bus.Send(s.bus, context, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		addons, err := bus.Send(s.bus, context, get_app_addons.Query{
+			AppID: cmd.AppID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(addons, func(a get_app_addons.Addon) bool { return a.ID == id })
+
+		if idx < 0 {
+			return apperr.ErrNotFound
+		}
+
+		return http.Created(s, ctx, addons[idx], "/api/v1/apps/%s/addons", cmd.AppID)
+	})
+}
+
+func (s *server) requestAddonCleanupHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), request_addon_cleanup.Command{
+			AppID: ctx.Param("id"),
+			ID:    ctx.Param("addon_id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listAddonBackupsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		backups, err := bus.Send(s.bus, ctx.Request.Context(), get_addon_backups.Query{
+			AppID:   ctx.Param("id"),
+			AddonID: ctx.Param("addon_id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, backups)
+	})
+}
+
+func (s *server) requestAddonBackupHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var (
+			context = ctx.Request.Context()
+			appID   = ctx.Param("id")
+			addonID = ctx.Param("addon_id")
+		)
+
+		id, err := bus.Send(s.bus, context, request_addon_backup.Command{
+			AppID:   appID,
+			AddonID: addonID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		backups, err := bus.Send(s.bus, context, get_addon_backups.Query{
+			AppID:   appID,
+			AddonID: addonID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(backups, func(b get_addon_backups.Backup) bool { return b.ID == id })
+
+		if idx < 0 {
+			return apperr.ErrNotFound
+		}
+
+		return http.Created(s, ctx, backups[idx], "/api/v1/apps/%s/addons/%s/backups", appID, addonID)
+	})
+}
+
+func (s *server) requestAddonRestoreHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), request_addon_restore.Command{
+			AppID:   ctx.Param("id"),
+			AddonID: ctx.Param("addon_id"),
+			ID:      ctx.Param("backup_id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// FIXME: till gin support custom types in query binding...
+type getAppEnvRevisionsFilters struct {
+	Page        int    `form:"page"`
+	Environment string `form:"environment"`
+}
+
+func (s *server) listAppEnvRevisionsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getAppEnvRevisionsFilters) error {
+		query := get_app_env_revisions.Query{
+			AppID: ctx.Param("id"),
+		}
+
+		if request.Environment != "" {
+			query.Environment.Set(request.Environment)
+		}
+
+		if request.Page != 0 {
+			query.Page.Set(request.Page)
+		}
+
+		revisions, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, revisions)
+	})
+}
+
+func (s *server) restoreEnvRevisionHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), restore_env_revision.Command{
+			AppID: ctx.Param("id"),
+			ID:    ctx.Param("revision_id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) exportEnvVarsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, query export_env_vars.Query) error {
+		query.AppID = ctx.Param("id")
+
+		content, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Reader(ctx, "text/plain; charset=utf-8", strings.NewReader(content))
+	})
+}
+
+func (s *server) importEnvVarsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd import_env_vars.Command) error {
+		cmd.AppID = ctx.Param("id")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// Parses a date given either as a duration relative to now or as a RFC3339 date.
+func parseSince(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+
+	since, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return since, validate.NewError(validate.FieldErrors{
+			"since": apperr.New("invalid_since"),
+		})
+	}
+
+	return since, nil
+}
+
+func (s *server) listAppArchivesHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		archives, err := bus.Send(s.bus, ctx.Request.Context(), get_app_archives.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, archives)
+	})
+}
+
+func (s *server) downloadAppArchiveHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		archive, err := bus.Send(s.bus, ctx.Request.Context(), get_app_archive.Query{
+			ID: ctx.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		defer archive.Close()
+
+		return http.AttachmentReader(ctx, ctx.Param("id")+"-archive.tar.gz", "application/gzip", archive)
+	})
+}
+
+func (s *server) listTrashedAppsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		apps, err := bus.Send(s.bus, ctx.Request.Context(), get_trashed_apps.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, apps)
+	})
+}
+
+func (s *server) restoreAppHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd restore_app.Command) error {
+		cmd.ID = ctx.Param("id")
+		context := ctx.Request.Context()
+		appid, err := bus.Send(s.bus, context, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, context, get_app_detail.Query{
+			ID: appid,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, ctx, data, "/api/v1/apps/%s", appid)
+	})
+}
+
 func (s *server) requestAppCleanupHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
+		// Read from the query only since clients may send a JSON content type without a body
 		if _, err := bus.Send(s.bus, ctx.Request.Context(), request_app_cleanup.Command{
-			ID: ctx.Param("id"),
+			ID:     ctx.Param("id"),
+			Export: ctx.Query("export") == "true",
 		}); err != nil {
 			return err
 		}
@@ -0,0 +1,20 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_artifacts_usage"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) getArtifactsUsageHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_artifacts_usage.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
@@ -0,0 +1,50 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/auth/app/get_audit_entries"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type listAuditEntriesFilters struct {
+	Page       int    `form:"page"`
+	UserID     string `form:"user_id"`
+	Action     string `form:"action"`
+	ResourceID string `form:"resource_id"`
+	Outcome    string `form:"outcome"`
+}
+
+func (s *server) listAuditEntriesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request listAuditEntriesFilters) error {
+		var query get_audit_entries.Query
+
+		if request.Page != 0 {
+			query.Page.Set(request.Page)
+		}
+
+		if request.UserID != "" {
+			query.UserID.Set(request.UserID)
+		}
+
+		if request.Action != "" {
+			query.Action.Set(request.Action)
+		}
+
+		if request.ResourceID != "" {
+			query.ResourceID.Set(request.ResourceID)
+		}
+
+		if request.Outcome != "" {
+			query.Outcome.Set(request.Outcome)
+		}
+
+		entries, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, entries)
+	})
+}
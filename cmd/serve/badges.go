@@ -0,0 +1,136 @@
+package serve
+
+import (
+	"bytes"
+	"html/template"
+	"unicode/utf8"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/generate_badge_token"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_badge"
+	"github.com/YuukanOO/seelf/internal/deployment/app/revoke_badge_token"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	badgeCharWidth = 7  // Approximated width of a character in the badge font
+	badgePadding   = 10 // Horizontal padding around each badge part
+)
+
+var badgeTemplate = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+<title>{{.Label}}: {{.Message}}</title>
+<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{.LabelX}}" y="14" textLength="{{.LabelTextWidth}}">{{.Label}}</text>
+<text x="{{.MessageX}}" y="14" textLength="{{.MessageTextWidth}}">{{.Message}}</text>
+</g>
+</svg>`))
+
+type (
+	badgeTokenResult struct {
+		Token string `json:"token"`
+	}
+
+	badgeData struct {
+		Label            string
+		Message          string
+		Color            string
+		Width            int
+		LabelWidth       int
+		LabelTextWidth   int
+		LabelX           int
+		MessageWidth     int
+		MessageTextWidth int
+		MessageX         int
+	}
+)
+
+func (s *server) generateBadgeTokenHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		token, err := bus.Send(s.bus, ctx.Request.Context(), generate_badge_token.Command{
+			ID: ctx.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, badgeTokenResult{Token: token})
+	})
+}
+
+func (s *server) revokeBadgeTokenHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), revoke_badge_token.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// Render the deployment badge of an app environment as an SVG image. This route is
+// public since badges are embedded in places where no session exists, access is
+// granted by the badge token instead.
+func (s *server) getAppBadgeHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, query get_app_badge.Query) error {
+		query.AppID = ctx.Param("id")
+		query.Environment = ctx.Param("environment")
+
+		badge, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+
+		if err = badgeTemplate.Execute(&buf, newBadgeData(badge)); err != nil {
+			return err
+		}
+
+		// Prevent proxies such as the GitHub image one from serving an outdated status
+		ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+
+		return http.Reader(ctx, "image/svg+xml; charset=utf-8", &buf)
+	})
+}
+
+func newBadgeData(badge get_app_badge.Badge) badgeData {
+	data := badgeData{
+		Label:   string(badge.Environment),
+		Message: "not deployed",
+		Color:   "#9f9f9f",
+	}
+
+	if status, isSet := badge.Status.TryGet(); isSet {
+		switch status {
+		case domain.DeploymentStatusPending:
+			data.Message, data.Color = "pending", "#9f9f9f"
+		case domain.DeploymentStatusRunning:
+			data.Message, data.Color = "running", "#007ec6"
+		case domain.DeploymentStatusFailed:
+			data.Message, data.Color = "failed", "#e05d44"
+		case domain.DeploymentStatusSucceeded:
+			data.Message, data.Color = "deployed", "#4c1"
+		}
+
+		data.Message = badge.Version + " " + data.Message
+	}
+
+	data.LabelTextWidth = utf8.RuneCountInString(data.Label) * badgeCharWidth
+	data.LabelWidth = data.LabelTextWidth + badgePadding
+	data.LabelX = data.LabelWidth / 2
+	data.MessageTextWidth = utf8.RuneCountInString(data.Message) * badgeCharWidth
+	data.MessageWidth = data.MessageTextWidth + badgePadding
+	data.MessageX = data.LabelWidth + data.MessageWidth/2
+	data.Width = data.LabelWidth + data.MessageWidth
+
+	return data
+}
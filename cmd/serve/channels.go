@@ -0,0 +1,105 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/notification/app/create_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channels"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_channel"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type listChannelsFilters struct {
+	AppID string `form:"app_id"`
+}
+
+func (s *server) createChannelHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_channel.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_channel.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/channels/%s", id)
+	})
+}
+
+func (s *server) updateChannelHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_channel.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_channel.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deleteChannelHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_channel.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listChannelsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request listChannelsFilters) error {
+		var query get_channels.Query
+
+		if request.AppID != "" {
+			query.AppID.Set(request.AppID)
+		}
+
+		data, err := bus.Send(s.bus, c.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getChannelByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_channel.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
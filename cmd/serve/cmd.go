@@ -2,6 +2,7 @@ package serve
 
 import (
 	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/cmd/update"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/spf13/cobra"
 )
@@ -9,6 +10,7 @@ import (
 type Options interface {
 	ServerOptions
 	startup.ServerOptions
+	update.Options
 }
 
 // Returns the root serve command
@@ -17,15 +19,18 @@ func Root(opts Options, logger log.Logger) *cobra.Command {
 		Use:   "serve",
 		Short: "Launch the web application!",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			root, err := startup.Server(opts, logger)
+			// Rolls back a freshly installed version if it could not start and listen
+			return update.WithRollback(opts, logger, func(started func()) error {
+				root, err := startup.Server(opts, logger)
 
-			if err != nil {
-				return err
-			}
+				if err != nil {
+					return err
+				}
 
-			defer root.Cleanup()
+				defer root.Cleanup()
 
-			return newHttpServer(opts, root).Listen()
+				return newHttpServer(opts, root).Listen(started)
+			})
 		},
 	}
 
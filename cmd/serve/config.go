@@ -0,0 +1,16 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) reloadConfigurationHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if err := s.reload(); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
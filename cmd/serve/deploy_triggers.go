@@ -0,0 +1,123 @@
+package serve
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/app/verify_deploy_trigger"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	httputils "github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	deployTriggerTimestampHeader = "X-Seelf-Timestamp"
+	deployTriggerSignatureHeader = "X-Seelf-Signature"
+)
+
+type deployTriggerResult struct {
+	Secret string `json:"secret,omitempty"` // Only returned when a secret has been generated
+}
+
+func (s *server) configureDeployTriggerHandler() gin.HandlerFunc {
+	return httputils.Bind(s, func(ctx *gin.Context, cmd configure_deploy_trigger.Command) error {
+		cmd.ID = ctx.Param("id")
+
+		secret, err := bus.Send(s.bus, ctx.Request.Context(), cmd)
+
+		if err != nil {
+			return err
+		}
+
+		return httputils.Ok(ctx, deployTriggerResult{Secret: secret})
+	})
+}
+
+func (s *server) removeDeployTriggerHandler() gin.HandlerFunc {
+	return httputils.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), remove_deploy_trigger.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return httputils.NoContent(ctx)
+	})
+}
+
+// Authenticate a deployment trigger signed by an external system with the app deploy
+// trigger key. The request acts on behalf of the user who configured it but is only
+// allowed to deploy this app. The body is read to check its signature and given back
+// to the next handlers untouched.
+func (s *server) authenticateDeployTrigger(ctx *gin.Context) {
+	appID := ctx.Param("id")
+	payload, err := io.ReadAll(ctx.Request.Body)
+
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, httputils.ErrRequestTooLarge)
+			return
+		}
+
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(payload))
+
+	signature := ctx.GetHeader(deployTriggerSignatureHeader)
+
+	uid, err := bus.Send(s.bus, ctx.Request.Context(), verify_deploy_trigger.Query{
+		AppID:     appID,
+		Timestamp: ctx.GetHeader(deployTriggerTimestampHeader),
+		Signature: signature,
+		Payload:   payload,
+	})
+
+	if err != nil {
+		ctx.AbortWithError(http.StatusUnauthorized, err)
+		return
+	}
+
+	// A signature is only accepted once so a captured request could not be replayed while
+	// its timestamp is valid. Since the timestamp could be ahead of the server time, it is
+	// remembered for twice the tolerance.
+	used, err := s.idempotencyStore.Reserve(ctx.Request.Context(), httputils.IdempotentRequest{
+		Key:         "deploy-trigger:" + appID + ":" + signature,
+		Fingerprint: ctx.Request.Method + " " + ctx.Request.URL.Path,
+		ExpiresAt:   time.Now().UTC().Add(2 * deployment.DeployTriggerTolerance),
+	})
+
+	if err != nil {
+		httputils.HandleError(s, ctx, err)
+		return
+	}
+
+	if used.HasValue() {
+		ctx.AbortWithError(http.StatusUnauthorized, verify_deploy_trigger.ErrInvalidDeployTrigger)
+		return
+	}
+
+	authCtx, err := s.withUser(
+		domain.WithScopes(ctx.Request.Context(), domain.Scopes{domain.Scope(string(domain.ScopeDeploy) + ":" + appID)}),
+		uid,
+	)
+
+	if err != nil {
+		ctx.AbortWithError(http.StatusUnauthorized, err)
+		return
+	}
+
+	ctx.Request = ctx.Request.WithContext(authCtx)
+
+	ctx.Next()
+}
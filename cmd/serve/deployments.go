@@ -1,19 +1,39 @@
 package serve
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"mime/multipart"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 
+	"github.com/YuukanOO/seelf/internal/deployment/app/annotate_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/broadcast_changes"
+	"github.com/YuukanOO/seelf/internal/deployment/app/follow_deployment_log"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_build_context"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log_steps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_reports"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_stats"
 	"github.com/YuukanOO/seelf/internal/deployment/app/promote"
 	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/retry_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/git"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
 	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/ostools"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/gin-gonic/gin"
 )
 
@@ -72,6 +92,56 @@ func (s *server) redeployHandler() gin.HandlerFunc {
 	})
 }
 
+func (s *server) retryDeploymentHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var (
+			appid     = ctx.Param("id")
+			number, _ = strconv.Atoi(ctx.Param("number"))
+		)
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), retry_deployment.Command{
+			AppID:            appid,
+			DeploymentNumber: number,
+		}); err != nil {
+			return err
+		}
+
+		deployment, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment.Query{
+			AppID:            appid,
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, deployment)
+	})
+}
+
+func (s *server) annotateDeploymentHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd annotate_deployment.Command) error {
+		cmd.AppID = ctx.Param("id")
+		cmd.DeploymentNumber, _ = strconv.Atoi(ctx.Param("number"))
+		context := ctx.Request.Context()
+
+		if _, err := bus.Send(s.bus, context, cmd); err != nil {
+			return err
+		}
+
+		deployment, err := bus.Send(s.bus, context, get_deployment.Query{
+			AppID:            cmd.AppID,
+			DeploymentNumber: cmd.DeploymentNumber,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, deployment)
+	})
+}
+
 func (s *server) promoteHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
 		var (
@@ -109,13 +179,256 @@ func (s *server) getDeploymentByIDHandler() gin.HandlerFunc {
 	})
 }
 
+const (
+	defaultDeploymentWaitTimeout = 30 * time.Second
+	maxDeploymentWaitTimeout     = 10 * time.Minute
+	deploymentWaitPollInterval   = 5 * time.Second // Safety net if realtime events have been dropped
+)
+
+type waitDeploymentFilters struct {
+	Timeout string `form:"timeout"` // Go duration, such as 5m, capped to 10 minutes
+}
+
+// Block until the deployment is done, either succeeded or failed, so CI pipelines could
+// gate their next steps on it without polling. If it is still pending or running when
+// the timeout expires, it is returned with a 202 status so the client could try again.
+func (s *server) waitDeploymentHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request waitDeploymentFilters) error {
+		var (
+			appID     = ctx.Param("id")
+			number, _ = strconv.Atoi(ctx.Param("number"))
+			timeout   = defaultDeploymentWaitTimeout
+		)
+
+		if request.Timeout != "" {
+			duration, err := time.ParseDuration(request.Timeout)
+
+			if err != nil || duration <= 0 {
+				return validate.NewError(validate.FieldErrors{
+					"timeout": apperr.New("invalid_timeout"),
+				})
+			}
+
+			timeout = min(duration, maxDeploymentWaitTimeout)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		// Subscribe before retrieving the deployment so no change could be missed in between
+		events := s.events.Subscribe(waitCtx)
+		ticker := time.NewTicker(deploymentWaitPollInterval)
+		defer ticker.Stop()
+
+		for {
+			deployment, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment.Query{
+				AppID:            appID,
+				DeploymentNumber: number,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			if isDeploymentDone(deployment) {
+				return http.Ok(ctx, deployment)
+			}
+
+			for changed := false; !changed; {
+				select {
+				case <-waitCtx.Done():
+					ctx.JSON(nethttp.StatusAccepted, deployment)
+					return nil
+				case <-ticker.C:
+					changed = true
+				case evt, open := <-events:
+					if !open {
+						// Dropped by the hub, rely on the ticker from now on
+						events = nil
+						continue
+					}
+
+					data, isDeployment := evt.Data.(broadcast_changes.Deployment)
+					changed = evt.Type == broadcast_changes.DeploymentStateChangedEvent && isDeployment &&
+						data.AppID == appID && data.DeploymentNumber == number
+				}
+			}
+		}
+	})
+}
+
+func isDeploymentDone(deployment get_deployment.Deployment) bool {
+	switch domain.DeploymentStatus(deployment.State.Status) {
+	case domain.DeploymentStatusSucceeded, domain.DeploymentStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *server) getDeploymentLogsHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
-		appid := ctx.Param("id")
+		log, err := s.deploymentLog(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		file, err := openDeploymentLog(log)
+
+		if os.IsNotExist(err) { // The log file does not exist until the deployment has started
+			return apperr.ErrNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		return http.Reader(ctx, "text/plain; charset=utf-8", artifact.NewTextReader(file))
+	})
+}
+
+func (s *server) getDeploymentLogStepsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
 		number, _ := strconv.Atoi(ctx.Param("number"))
 
-		logpath, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment_log.Query{
-			AppID:            appid,
+		steps, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment_log_steps.Query{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, steps)
+	})
+}
+
+// Download the deployment log file as stored on the disk, gzipped once the deployment
+// is done. Range requests are supported so big logs could be resumed.
+func (s *server) downloadDeploymentLogsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		log, err := s.deploymentLog(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Attachment(ctx, log.Path, filepath.Base(log.Path))
+	})
+}
+
+func (s *server) downloadDeploymentManifestHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		reports, err := s.deploymentReports(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return sendReport(ctx, reports.ManifestPath(), "manifest")
+	})
+}
+
+func (s *server) downloadDeploymentSBOMHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		reports, err := s.deploymentReports(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return sendReport(ctx, reports.SBOMPath(ctx.Param("service")), "sbom-"+ctx.Param("service"))
+	})
+}
+
+func (s *server) downloadDeploymentScanHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		reports, err := s.deploymentReports(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return sendReport(ctx, reports.ScanPath(ctx.Param("service")), "scan-"+ctx.Param("service"))
+	})
+}
+
+func (s *server) downloadDeploymentBuildContextHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		archive, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment_build_context.Query{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		defer archive.Close()
+
+		return http.AttachmentReader(ctx, ctx.Param("id")+"-"+ctx.Param("number")+"-build-context.tar.gz", "application/gzip", archive)
+	})
+}
+
+// Send a deployment report as an attachment named after the deployment.
+func sendReport(ctx *gin.Context, path, name string) error {
+	// Reports may not have been recorded depending on the deployment outcome
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return apperr.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return http.Attachment(ctx, path, ctx.Param("id")+"-"+ctx.Param("number")+"-"+name+".json")
+}
+
+func (s *server) deploymentReports(ctx *gin.Context) (domain.DeploymentReports, error) {
+	number, _ := strconv.Atoi(ctx.Param("number"))
+
+	return bus.Send(s.bus, ctx.Request.Context(), get_deployment_reports.Query{
+		AppID:            ctx.Param("id"),
+		DeploymentNumber: number,
+	})
+}
+
+func openDeploymentLog(log domain.DeploymentLog) (io.ReadCloser, error) {
+	if log.Compressed {
+		return ostools.OpenGzip(log.Path)
+	}
+
+	return os.Open(log.Path)
+}
+
+func (s *server) deploymentLog(ctx *gin.Context) (domain.DeploymentLog, error) {
+	number, _ := strconv.Atoi(ctx.Param("number"))
+
+	return bus.Send(s.bus, ctx.Request.Context(), get_deployment_log.Query{
+		AppID:            ctx.Param("id"),
+		DeploymentNumber: number,
+	})
+}
+
+const (
+	logLineEvent       = "line"
+	logEndEvent        = "end"
+	logErrorEvent      = "error"
+	logStreamKeepAlive = 15 * time.Second // Prevents proxies from closing the connection during long silent steps
+)
+
+// Stream deployment logs as server-sent events. Lines already written are sent first,
+// then new lines as they are written until the deployment is done.
+func (s *server) streamDeploymentLogsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		stream, err := bus.Send(s.bus, ctx.Request.Context(), follow_deployment_log.Query{
+			AppID:            ctx.Param("id"),
 			DeploymentNumber: number,
 		})
 
@@ -123,26 +436,116 @@ func (s *server) getDeploymentLogsHandler() gin.HandlerFunc {
 			return err
 		}
 
-		return http.File(ctx, logpath)
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("X-Accel-Buffering", "no") // Disable nginx buffering
+
+		w := &logLinesWriter{ctx: ctx}
+
+		file, err := openDeploymentLog(stream.DeploymentLog)
+
+		switch {
+		case err == nil:
+			var existing io.Reader = file
+
+			if stream.Chunks != nil {
+				existing = io.LimitReader(file, stream.Offset)
+			}
+
+			_, err = io.Copy(w, existing)
+			file.Close()
+
+			if err != nil {
+				return err
+			}
+		case !os.IsNotExist(err): // The log file does not exist until the deployment has started
+			return err
+		}
+
+		keepAlive := time.NewTicker(logStreamKeepAlive)
+		defer keepAlive.Stop()
+
+		for stream.Chunks != nil {
+			select {
+			case chunk, ok := <-stream.Chunks:
+				if !ok {
+					stream.Chunks = nil
+					continue
+				}
+
+				w.Write(chunk)
+			case <-keepAlive.C:
+				ctx.Writer.WriteString(": keep-alive\n\n")
+				ctx.Writer.Flush()
+			case <-ctx.Request.Context().Done():
+				return nil
+			}
+		}
+
+		w.End()
+
+		return nil
 	})
 }
 
+// Writer sending each complete line, rendered as plain text, as a server-sent event.
+type logLinesWriter struct {
+	ctx     *gin.Context
+	pending []byte
+}
+
+func (w *logLinesWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+
+		if idx < 0 {
+			break
+		}
+
+		w.ctx.SSEvent(logLineEvent, artifact.DecodeRecord(w.pending[:idx]).String())
+		w.pending = w.pending[idx+1:]
+	}
+
+	w.ctx.Writer.Flush()
+
+	return len(p), nil
+}
+
+// Sends the remaining partial line if any and the end event.
+func (w *logLinesWriter) End() {
+	if len(w.pending) > 0 {
+		w.ctx.SSEvent(logLineEvent, artifact.DecodeRecord(w.pending).String())
+		w.pending = nil
+	}
+
+	w.ctx.SSEvent(logEndEvent, "")
+	w.ctx.Writer.Flush()
+}
+
 // FIXME: till gin support custom types in query binding...
 type getDeploymentsFilters struct {
-	Page        int    `form:"page"`
-	Environment string `form:"environment"`
+	Page        int      `form:"page"`
+	Environment string   `form:"environment"`
+	Labels      []string `form:"labels"`
+	Search      string   `form:"search"`
 }
 
 func (s *server) listDeploymentsByAppHandler() gin.HandlerFunc {
 	return http.Bind(s, func(ctx *gin.Context, request getDeploymentsFilters) error {
 		query := get_app_deployments.Query{
-			AppID: ctx.Param("id"),
+			AppID:  ctx.Param("id"),
+			Labels: request.Labels,
 		}
 
 		if request.Environment != "" {
 			query.Environment.Set(request.Environment)
 		}
 
+		if request.Search != "" {
+			query.Search.Set(request.Search)
+		}
+
 		if request.Page != 0 {
 			query.Page.Set(request.Page)
 		}
@@ -169,3 +572,65 @@ func (s *server) sendDeploymentCreatedResponse(ctx *gin.Context, appid string, n
 
 	return http.Created(s, ctx, deployment, "/api/v1/apps/%s/deployments/%d", appid, number)
 }
+
+// Period defaults to the last 30 days.
+type getDeploymentStatsFilters struct {
+	Environment string `form:"environment"`
+	Since       string `form:"since"` // Either a duration relative to now or a RFC3339 date
+	Until       string `form:"until"` // RFC3339 date
+}
+
+func (s *server) getAppDeploymentStatsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getDeploymentStatsFilters) error {
+		return s.sendDeploymentStats(ctx, request, get_deployment_stats.Query{
+			AppID: monad.Value(ctx.Param("id")),
+		})
+	})
+}
+
+func (s *server) getTargetDeploymentStatsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getDeploymentStatsFilters) error {
+		return s.sendDeploymentStats(ctx, request, get_deployment_stats.Query{
+			TargetID: monad.Value(ctx.Param("id")),
+		})
+	})
+}
+
+func (s *server) sendDeploymentStats(ctx *gin.Context, request getDeploymentStatsFilters, query get_deployment_stats.Query) error {
+	query.Until = time.Now()
+	query.Since = query.Until.AddDate(0, 0, -30)
+
+	if request.Environment != "" {
+		query.Environment.Set(request.Environment)
+	}
+
+	if request.Since != "" {
+		since, err := parseSince(request.Since)
+
+		if err != nil {
+			return err
+		}
+
+		query.Since = since
+	}
+
+	if request.Until != "" {
+		until, err := time.Parse(time.RFC3339, request.Until)
+
+		if err != nil {
+			return validate.NewError(validate.FieldErrors{
+				"until": apperr.New("invalid_until"),
+			})
+		}
+
+		query.Until = until
+	}
+
+	stats, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+	if err != nil {
+		return err
+	}
+
+	return http.Ok(ctx, stats)
+}
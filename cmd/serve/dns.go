@@ -0,0 +1,95 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zones"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_dns_zone"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) createDnsZoneHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_dns_zone.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_dns_zone.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/dns/zones/%s", id)
+	})
+}
+
+func (s *server) updateDnsZoneHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_dns_zone.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_dns_zone.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deleteDnsZoneHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_dns_zone.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listDnsZonesHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_dns_zones.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getDnsZoneByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_dns_zone.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
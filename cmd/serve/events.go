@@ -0,0 +1,94 @@
+package serve
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/realtime"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	eventsWriteTimeout = 10 * time.Second
+	eventsPongTimeout  = 60 * time.Second
+	eventsPingInterval = eventsPongTimeout * 9 / 10
+	eventsMaxReadSize  = 512
+)
+
+// The default origin check rejects cross-origin requests, which matters since
+// browsers send the session cookie with the upgrade request.
+var eventsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// Upgrade the connection to a WebSocket and send realtime events the current user is
+// allowed to see until the client goes away. Permissions are the ones of the user when
+// the connection was opened.
+func (s *server) eventsHandler(ctx *gin.Context) {
+	conn, err := eventsUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+
+	if err != nil {
+		// The upgrader has already written the error response
+		s.logger.Debugw("could not upgrade events connection",
+			"error", err)
+		return
+	}
+
+	defer conn.Close()
+
+	subCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	events := s.events.Subscribe(subCtx)
+
+	// Clients are not expected to send anything, reading is only needed to process
+	// control messages and to know when the connection is closed.
+	go func() {
+		defer cancel()
+
+		conn.SetReadLimit(eventsMaxReadSize)
+		conn.SetReadDeadline(time.Now().Add(eventsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(eventsPongTimeout))
+		})
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				// Either the client went away or could not keep up, in which case it
+				// should reconnect and fetch the current state again
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, ""),
+					time.Now().Add(eventsWriteTimeout))
+				return
+			}
+
+			if err := s.writeEvent(conn, evt); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *server) writeEvent(conn *websocket.Conn, evt realtime.Event) error {
+	conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+
+	return conn.WriteJSON(evt)
+}
@@ -0,0 +1,176 @@
+package serve
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/exec_service"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const execMaxReadSize = 32 * 1024
+
+type (
+	// FIXME: till gin support custom types in query binding...
+	execServiceFilters struct {
+		Environment string   `form:"environment"`
+		Service     string   `form:"service"`
+		Cmd         []string `form:"cmd"`
+		Tty         bool     `form:"tty"`
+	}
+
+	// Last message sent on an exec connection before closing it.
+	execResult struct {
+		ExitCode *int  `json:"exit_code,omitempty"`
+		Error    error `json:"error,omitempty"`
+	}
+
+	// Sends everything written to it as binary messages.
+	execConn struct {
+		mu   sync.Mutex
+		conn *websocket.Conn
+	}
+)
+
+// Upgrade the connection to a WebSocket and run a one-off command inside a service
+// container. Messages received are sent to the command input, an empty one closing it,
+// and its output is sent back as binary messages. Once the command has exited, a JSON
+// message with its exit code, or the error which prevented it to run, is sent.
+func (s *server) execServiceHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request execServiceFilters) error {
+		conn, err := eventsUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+
+		if err != nil {
+			// The upgrader has already written the error response
+			s.logger.Debugw("could not upgrade exec connection",
+				"error", err)
+			return nil
+		}
+
+		defer conn.Close()
+
+		execCtx, cancel := context.WithCancel(ctx.Request.Context())
+		defer cancel()
+
+		var (
+			stdinReader, stdinWriter = io.Pipe()
+			output                   = &execConn{conn: conn}
+		)
+
+		go func() {
+			defer cancel()
+
+			conn.SetReadLimit(execMaxReadSize)
+
+			for {
+				_, data, err := conn.ReadMessage()
+
+				if err != nil {
+					stdinWriter.CloseWithError(err)
+					return
+				}
+
+				if len(data) == 0 {
+					stdinWriter.Close()
+					continue
+				}
+
+				if _, err = stdinWriter.Write(data); err != nil {
+					continue // Input has been closed, keep reading to process control messages
+				}
+			}
+		}()
+
+		go func() {
+			ping := time.NewTicker(eventsPingInterval)
+			defer ping.Stop()
+
+			for {
+				select {
+				case <-ping.C:
+					if err := output.ping(); err != nil {
+						return
+					}
+				case <-execCtx.Done():
+					return
+				}
+			}
+		}()
+
+		cmd := exec_service.Command{
+			AppID:       ctx.Param("id"),
+			Environment: request.Environment,
+			Service:     request.Service,
+			Cmd:         request.Cmd,
+			Tty:         request.Tty,
+			Stdin:       stdinReader,
+			Stdout:      output,
+		}
+
+		s.logger.Infow("executing command in service container",
+			"app", cmd.AppID,
+			"environment", cmd.Environment,
+			"service", cmd.Service,
+			"cmd", cmd.Cmd,
+			"correlation_id", bus.CorrelationID(execCtx).Get(""))
+
+		exitCode, err := bus.Send(s.bus, execCtx, cmd)
+
+		stdinReader.Close() // Unblock the reading loop if the command has not consumed its input
+
+		var result execResult
+
+		if err == nil {
+			result.ExitCode = &exitCode
+		} else if _, isAppErr := apperr.As[apperr.Error](err); isAppErr {
+			result.Error = err
+		} else {
+			s.logger.Errorw("could not execute command in service container",
+				"app", cmd.AppID,
+				"correlation_id", bus.CorrelationID(execCtx).Get(""),
+				"error", err)
+			result.Error = http.ErrUnexpected
+		}
+
+		output.close(result)
+
+		return nil
+	})
+}
+
+func (c *execConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (c *execConn) ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteTimeout))
+}
+
+func (c *execConn) close(result execResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+	c.conn.WriteJSON(result)
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(eventsWriteTimeout))
+}
@@ -0,0 +1,312 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployments_feed"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	feedFormatRSS  = "rss"
+	feedFormatAtom = "atom"
+	feedFormatICal = "ical"
+
+	icalDateLayout    = "20060102T150405Z"
+	icalMaxLineLength = 75
+)
+
+var (
+	errInvalidFeedFormat = apperr.New("invalid_feed_format")
+
+	feedStatuses = map[domain.DeploymentStatus]string{
+		domain.DeploymentStatusPending:   "pending",
+		domain.DeploymentStatusRunning:   "running",
+		domain.DeploymentStatusFailed:    "failed",
+		domain.DeploymentStatusSucceeded: "succeeded",
+	}
+
+	icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+)
+
+type (
+	getDeploymentsFeedFilters struct {
+		Format string `form:"format"` // One of rss (default), atom or ical
+		Token  string `form:"token"`  // API key or token, for clients which could not set the Authorization header
+	}
+
+	// Informations shared by every feed format.
+	feedEntry struct {
+		ID          string
+		Title       string
+		Description string
+		Link        string
+		Author      string
+		Start       time.Time
+		End         time.Time
+		Updated     time.Time
+	}
+
+	rssFeed struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Items         []rssItem `xml:"item"`
+	}
+
+	rssItem struct {
+		Title       string  `xml:"title"`
+		Link        string  `xml:"link"`
+		Description string  `xml:"description"`
+		GUID        rssGUID `xml:"guid"`
+		PubDate     string  `xml:"pubDate"`
+	}
+
+	rssGUID struct {
+		Value       string `xml:",chardata"`
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+	}
+
+	atomFeed struct {
+		XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string      `xml:"id"`
+		Title   string      `xml:"title"`
+		Updated string      `xml:"updated"`
+		Link    atomLink    `xml:"link"`
+		Entries []atomEntry `xml:"entry"`
+	}
+
+	atomEntry struct {
+		ID      string     `xml:"id"`
+		Title   string     `xml:"title"`
+		Updated string     `xml:"updated"`
+		Link    atomLink   `xml:"link"`
+		Author  atomAuthor `xml:"author"`
+		Summary string     `xml:"summary"`
+	}
+
+	atomLink struct {
+		Href string `xml:"href,attr"`
+	}
+
+	atomAuthor struct {
+		Name string `xml:"name"`
+	}
+)
+
+// Publish the most recent deployments of an app, or of every readable app if no id is
+// given, as a RSS or Atom feed or an iCal calendar so teams could subscribe to them.
+func (s *server) getDeploymentsFeedHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getDeploymentsFeedFilters) error {
+		var query get_deployments_feed.Query
+
+		if id := ctx.Param("id"); id != "" {
+			query.AppID.Set(id)
+		}
+
+		deployments, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		var (
+			baseUrl = s.baseUrl(ctx)
+			title   = "seelf deployments"
+			link    = baseUrl
+			entries = make([]feedEntry, len(deployments))
+		)
+
+		if id, isSet := query.AppID.TryGet(); isSet {
+			link = fmt.Sprintf("%s/apps/%s", baseUrl, id)
+
+			if len(deployments) > 0 {
+				title = deployments[0].AppName + " deployments"
+			}
+		}
+
+		for i, d := range deployments {
+			entries[i] = newFeedEntry(baseUrl, d)
+		}
+
+		switch request.Format {
+		case "", feedFormatRSS:
+			return sendFeed(ctx, "application/rss+xml; charset=utf-8", newRSSFeed(title, link, entries))
+		case feedFormatAtom:
+			return sendFeed(ctx, "application/atom+xml; charset=utf-8", newAtomFeed(title, link, entries))
+		case feedFormatICal:
+			return http.Reader(ctx, "text/calendar; charset=utf-8", strings.NewReader(newICalendar(title, entries)))
+		default:
+			return errInvalidFeedFormat
+		}
+	})
+}
+
+func (s *server) baseUrl(ctx *gin.Context) string {
+	if s.IsSecure() {
+		return "https://" + ctx.Request.Host
+	}
+
+	return "http://" + ctx.Request.Host
+}
+
+func newFeedEntry(baseUrl string, d get_deployments_feed.Deployment) feedEntry {
+	var (
+		status      = feedStatuses[domain.DeploymentStatus(d.Status)]
+		description strings.Builder
+		entry       = feedEntry{
+			ID:      fmt.Sprintf("%s/%d", d.AppID, d.DeploymentNumber),
+			Title:   fmt.Sprintf("%s #%d on %s %s", d.AppName, d.DeploymentNumber, d.Environment, status),
+			Link:    fmt.Sprintf("%s/apps/%s/deployments/%d", baseUrl, d.AppID, d.DeploymentNumber),
+			Author:  d.RequestedBy.Email,
+			Start:   d.StartedAt.Get(d.RequestedAt),
+			Updated: d.FinishedAt.Get(d.StartedAt.Get(d.RequestedAt)),
+		}
+	)
+
+	entry.End = d.FinishedAt.Get(entry.Start)
+
+	description.WriteString("Requested by " + d.RequestedBy.Email)
+
+	if code, isSet := d.ErrCode.TryGet(); isSet {
+		description.WriteString("\nError: " + code)
+	}
+
+	if d.Note != "" {
+		description.WriteString("\n\n" + d.Note)
+	}
+
+	entry.Description = description.String()
+
+	return entry
+}
+
+func newRSSFeed(title, link string, entries []feedEntry) rssFeed {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         title,
+			Link:          link,
+			Description:   title,
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(entries)),
+		},
+	}
+
+	for i, e := range entries {
+		feed.Channel.Items[i] = rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Description: e.Description,
+			GUID:        rssGUID{Value: e.Link, IsPermaLink: true},
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+		}
+	}
+
+	return feed
+}
+
+func newAtomFeed(title, link string, entries []feedEntry) atomFeed {
+	feed := atomFeed{
+		ID:      link,
+		Title:   title,
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: link},
+		Entries: make([]atomEntry, len(entries)),
+	}
+
+	if len(entries) > 0 {
+		feed.Updated = entries[0].Updated.UTC().Format(time.RFC3339)
+	}
+
+	for i, e := range entries {
+		feed.Entries[i] = atomEntry{
+			ID:      e.Link,
+			Title:   e.Title,
+			Updated: e.Updated.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: e.Link},
+			Author:  atomAuthor{Name: e.Author},
+			Summary: e.Description,
+		}
+	}
+
+	return feed
+}
+
+// Builds an iCalendar (RFC 5545) with one event per deployment, lasting from its start
+// to its end.
+func newICalendar(title string, entries []feedEntry) string {
+	var (
+		b     strings.Builder
+		stamp = time.Now().UTC().Format(icalDateLayout)
+	)
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//seelf//deployments//EN")
+	writeICalLine(&b, "X-WR-CALNAME:"+icalEscaper.Replace(title))
+
+	for _, e := range entries {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+e.ID+"@seelf")
+		writeICalLine(&b, "DTSTAMP:"+stamp)
+		writeICalLine(&b, "DTSTART:"+e.Start.UTC().Format(icalDateLayout))
+		writeICalLine(&b, "DTEND:"+e.End.UTC().Format(icalDateLayout))
+		writeICalLine(&b, "SUMMARY:"+icalEscaper.Replace(e.Title))
+		writeICalLine(&b, "DESCRIPTION:"+icalEscaper.Replace(e.Description))
+		writeICalLine(&b, "URL:"+e.Link)
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+
+	return b.String()
+}
+
+// Writes a content line, folded to 75 octets as required by the specification without
+// splitting multi-bytes characters.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalMaxLineLength
+
+	for len(line) > limit {
+		cut := limit
+
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = icalMaxLineLength - 1 // Folded lines start with a space
+	}
+
+	b.WriteString(line + "\r\n")
+}
+
+func sendFeed(ctx *gin.Context, contentType string, feed any) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+
+	if err := xml.NewEncoder(&buf).Encode(feed); err != nil {
+		return err
+	}
+
+	return http.Reader(ctx, contentType, &buf)
+}
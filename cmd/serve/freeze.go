@@ -0,0 +1,51 @@
+package serve
+
+import (
+	"strconv"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_freeze"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/unfreeze_deployment"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) configureFreezeHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd configure_freeze.Command) error {
+		cmd.ID = ctx.Param("id")
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) unfreezeDeploymentHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var (
+			appid     = ctx.Param("id")
+			number, _ = strconv.Atoi(ctx.Param("number"))
+		)
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), unfreeze_deployment.Command{
+			AppID:            appid,
+			DeploymentNumber: number,
+		}); err != nil {
+			return err
+		}
+
+		deployment, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment.Query{
+			AppID:            appid,
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, deployment)
+	})
+}
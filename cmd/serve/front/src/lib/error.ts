@@ -12,10 +12,10 @@ export class BadRequestError extends Error {
 	public readonly fields: Record<string, Maybe<string>>;
 	public readonly isValidationError: boolean;
 
-	public constructor(data: AppError<ValidationDetail>) {
-		super(data.code);
-		this.isValidationError = data.code === 'validation_failed';
-		this.fields = Object.entries(data.detail ?? {}).reduce(
+	public constructor(data?: AppError<ValidationDetail>) {
+		super(data?.code ?? 'bad_request');
+		this.isValidationError = data?.code === 'validation_failed';
+		this.fields = Object.entries(data?.detail ?? {}).reduce(
 			(result, [name, err]) => ({
 				...result,
 				[name]: err.code
@@ -43,6 +43,8 @@ export class HttpError extends Error {
 	private static readonly status: Record<number, Maybe<{ new (data?: any): Error }>> = {
 		400: BadRequestError,
 		401: UnauthorizedError,
+		403: BadRequestError,
+		422: BadRequestError,
 		500: UnexpectedError
 	};
 
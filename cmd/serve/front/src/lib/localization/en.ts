@@ -5,6 +5,7 @@ const translations = {
 	// Authentication
 	'auth.signin.title': 'Sign in',
 	'auth.signin.description': 'Please fill the form below to access your dashboard.',
+	'auth.signin.oidc': (name: string) => `Sign in with ${name}`,
 	// App
 	'app.no_targets': 'No targets found',
 	'app.no_targets.description':
@@ -117,12 +118,17 @@ You may reconsider and try to make the target reachable before deleting it.`,
 	'jobs.group': 'group',
 	'jobs.cancel': 'Cancel job',
 	'jobs.cancel.confirm': 'Are you sure you want to cancel this job?',
+	'jobs.worker.stalled': 'The worker looks stuck',
+	'jobs.worker.warning': 'Pending jobs are waiting',
 	// Jobs names
 	'deployment.command.cleanup_app': 'Application cleanup',
 	'deployment.command.cleanup_target': 'Target cleanup',
 	'deployment.command.delete_app': 'Application removal',
 	'deployment.command.delete_target': 'Target removal',
 	'deployment.command.configure_target': 'Target configuration',
+	'deployment.command.upgrade_proxy': 'Target proxy upgrade',
+	'deployment.command.collect_garbage': 'Target garbage collection',
+	'deployment.command.sync_dns_records': 'DNS records synchronization',
 	'deployment.command.deploy': 'Application deployment',
 	// Registries
 	'registry.new': 'New registry',
@@ -200,6 +206,8 @@ You may reconsider and try to make the target reachable before deleting it.`,
 	'deployment.services': 'deployed services',
 	'deployment.branch': 'branch',
 	'deployment.commit': 'commit',
+	'deployment.note': 'note',
+	'deployment.metadata': 'metadata',
 	'deployment.error_code': 'error code',
 	'deployment.details_tooltip': (number: number) => `View deployment #${number} details and logs`,
 	'deployment.not_found':
@@ -266,7 +274,32 @@ You may reconsider and try to make the target reachable before deleting it.`,
 	config_already_taken: 'A target for this host already exists',
 	invalid_host: 'Invalid host',
 	invalid_ssh_key: 'Invalid SSH key',
-	target_in_use: 'Target is used by at least one application and cannot be deleted.'
+	target_in_use: 'Target is used by at least one application and cannot be deleted.',
+	team_in_use: 'Team still has applications and cannot be deleted.',
+	user_disabled: 'Your account has been disabled, please contact the administrator.',
+	forbidden: 'You are not allowed to perform this action.',
+	email_already_taken: 'Email is already taken',
+	password_auth_disabled: 'Signing in with a password has been disabled.',
+	oidc_failed: 'Could not sign in with the identity provider, please try again.',
+	oidc_invalid_state: 'Your sign in attempt has expired, please try again.',
+	too_many_login_attempts: 'Too many failed sign in attempts, please try again later.',
+	invalid_url: 'Invalid url',
+	invalid_dns_zone: 'Invalid DNS zone, expected a domain such as example.com',
+	invalid_dns_provider: 'Invalid DNS provider, expected cloudflare, route53 or desec',
+	invalid_dns_credentials: 'Missing credentials expected by the DNS provider',
+	invalid_dns_address: 'Invalid IP address',
+	dns_zone_name_taken: 'This DNS zone is already registered',
+	invalid_url_alias: 'Invalid alias, expected a domain such as example.com',
+	url_alias_taken: 'One of these aliases is already used by another application on this target',
+	invalid_replicas: 'Number of containers must be between 0 and 32',
+	invalid_deployment_timeout: 'Timeout must not exceed 24 hours',
+	deployment_timed_out: 'Deployment stage exceeded its timeout',
+	invalid_event_type: 'Invalid event type',
+	invalid_channel_kind: 'Invalid channel kind, expected slack, discord or matrix',
+	too_many_requests: 'Too many requests, please slow down and try again later.',
+	request_too_large: 'The request is too large.',
+	maintenance: 'seelf is in maintenance mode, changes are not allowed for now.',
+	setup_completed: 'The setup has already been completed, please sign in.'
 } satisfies Translations;
 
 export default {
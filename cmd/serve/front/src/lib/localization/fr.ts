@@ -9,6 +9,7 @@ export default {
 		'auth.signin.title': 'Connexion',
 		'auth.signin.description':
 			'Remplissez le formulaire ci-dessous pour accéder au tableau de bord.',
+		'auth.signin.oidc': (name: string) => `Se connecter avec ${name}`,
 		// App
 		'app.no_targets': 'Aucune cible trouvée',
 		'app.no_targets.description': `Vous avez besoin d'au moins une cible pour pouvoir déployer votre application. Dirigez-vous vers la <a href="/targets/new">page de création</a> pour en créer une.`,
@@ -120,12 +121,17 @@ Vous devriez probablement essayer de rendre la cible accessible avant de la supp
 		'jobs.group': 'groupe',
 		'jobs.cancel': 'Annuler la tâche',
 		'jobs.cancel.confirm': 'Voulez-vous vraiment annuler la tâche ?',
+		'jobs.worker.stalled': 'Le worker semble bloqué',
+		'jobs.worker.warning': 'Des tâches sont en attente',
 		// Jobs names
 		'deployment.command.cleanup_app': "Nettoyage de l'application",
 		'deployment.command.cleanup_target': 'Nettoyage de la cible',
 		'deployment.command.delete_app': "Suppression de l'application",
 		'deployment.command.delete_target': 'Suppression de la cible',
 		'deployment.command.configure_target': 'Configuration de la cible',
+		'deployment.command.upgrade_proxy': 'Mise à jour du proxy de la cible',
+		'deployment.command.collect_garbage': 'Nettoyage de la cible',
+		'deployment.command.sync_dns_records': 'Synchronisation des enregistrements DNS',
 		'deployment.command.deploy': "Déploiement de l'application",
 		// Registries
 		'registry.new': 'Nouveau registre',
@@ -204,6 +210,8 @@ Vous devriez probablement essayer de rendre la cible accessible avant de la supp
 		'deployment.services': 'services déployés',
 		'deployment.branch': 'branche',
 		'deployment.commit': 'commit',
+		'deployment.note': 'note',
+		'deployment.metadata': 'métadonnées',
 		'deployment.error_code': 'code erreur',
 		'deployment.details_tooltip': (number: number) =>
 			`Voir les détails et logs du déploiement #${number}`,
@@ -273,6 +281,31 @@ Vous devriez probablement essayer de rendre la cible accessible avant de la supp
 		invalid_host: 'Hôte invalide',
 		invalid_ssh_key: 'Clé SSH invalide',
 		target_in_use:
-			"La cible est en cours d'utilisation par au moins une application et ne peut pas être supprimée."
+			"La cible est en cours d'utilisation par au moins une application et ne peut pas être supprimée.",
+		team_in_use: "L'équipe contient encore des applications et ne peut pas être supprimée.",
+		user_disabled: "Votre compte a été désactivé, veuillez contacter l'administrateur.",
+		forbidden: "Vous n'êtes pas autorisé à effectuer cette action.",
+		email_already_taken: 'Email déjà utilisé',
+		password_auth_disabled: 'La connexion par mot de passe a été désactivée.',
+		oidc_failed: "Impossible de se connecter avec le fournisseur d'identité, veuillez réessayer.",
+		oidc_invalid_state: 'Votre tentative de connexion a expiré, veuillez réessayer.',
+		too_many_login_attempts: 'Trop de tentatives de connexion échouées, veuillez réessayer plus tard.',
+		invalid_url: 'Url invalide',
+		invalid_dns_zone: 'Zone DNS invalide, un domaine tel que example.com est attendu',
+		invalid_dns_provider: 'Fournisseur DNS invalide, cloudflare, route53 ou desec attendu',
+		invalid_dns_credentials: 'Identifiants attendus par le fournisseur DNS manquants',
+		invalid_dns_address: 'Adresse IP invalide',
+		dns_zone_name_taken: 'Cette zone DNS est déjà enregistrée',
+		invalid_url_alias: 'Alias invalide, un domaine tel que example.com est attendu',
+		url_alias_taken: 'Un de ces alias est déjà utilisé par une autre application sur cette cible',
+		invalid_replicas: 'Le nombre de conteneurs doit être compris entre 0 et 32',
+		invalid_deployment_timeout: 'Le délai ne doit pas dépasser 24 heures',
+		deployment_timed_out: "L'étape du déploiement a dépassé son délai",
+		invalid_event_type: "Type d'événement invalide",
+		invalid_channel_kind: 'Type de canal invalide, slack, discord ou matrix attendu',
+		too_many_requests: 'Trop de requêtes, veuillez ralentir et réessayer plus tard.',
+		request_too_large: 'La requête est trop volumineuse.',
+		maintenance: 'seelf est en maintenance, les modifications ne sont pas autorisées pour le moment.',
+		setup_completed: 'La configuration initiale a déjà été effectuée, veuillez vous connecter.'
 	}
 } as const satisfies Locale<AppTranslations>;
@@ -1,7 +1,8 @@
 import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
 import { POLLING_INTERVAL_MS } from '$lib/config';
+import type { Paginated } from '$lib/pagination';
 import type { ByUserData } from '$lib/resources/users';
-import type { Deployment, DeploymentDetail } from '$lib/resources/deployments';
+import type { Deployment, DeploymentDetail, Environment } from '$lib/resources/deployments';
 
 export type App = {
 	id: string;
@@ -12,6 +13,7 @@ export type App = {
 	latest_deployments: LatestDeployments<Deployment>;
 	production_target: TargetSummary;
 	staging_target: TargetSummary;
+	team?: TeamSummary;
 };
 
 export type LatestDeployments<T> = {
@@ -20,8 +22,26 @@ export type LatestDeployments<T> = {
 };
 
 export type EnvironmentVariablesPerService = Record<string, Record<string, string>>;
+export type ServiceExposure = {
+	disabled: boolean;
+	port?: number;
+	subdomain?: string;
+	path_prefix?: string;
+	ports?: ExposedPort[];
+};
+export type ExposedPort = {
+	router: 'tcp' | 'udp';
+	port: number;
+	published?: number;
+};
+export type ServicesExposure = Record<string, ServiceExposure>;
 export type VersionControl = { url: string; token?: string };
 
+export type TeamSummary = {
+	id: string;
+	name: string;
+};
+
 export type TargetSummary = {
 	id: string;
 	name: string;
@@ -38,16 +58,101 @@ export type AppDetail = {
 	version_control?: VersionControl;
 	production: EnvironmentConfig;
 	staging: EnvironmentConfig;
+	team?: TeamSummary;
+	platforms: string[];
+	build_registry?: { id: string; name: string; url: string };
+	static_site?: StaticSite;
+	badge_enabled: boolean;
+	deploy_trigger?: DeployTriggerKind;
+	freeze_windows: FreezeWindow[];
+	timeouts: DeploymentTimeouts;
+};
+
+export type BadgeToken = {
+	token: string;
+};
+
+export type DeployTriggerKind = 'hmac' | 'ed25519';
+
+export type ConfigureDeployTrigger = {
+	/** When not given, a secret is generated to sign triggers with an HMAC */
+	public_key?: string;
+};
+
+export type DeployTrigger = {
+	/** Only returned once, when a secret has been generated */
+	secret?: string;
+};
+
+export type FreezePolicy = 'queue' | 'reject';
+
+export type FreezeWindow = {
+	name: string;
+	policy: FreezePolicy;
+	/** Days of the week (mon, tue...), every day if empty */
+	days: string[];
+	/** Time of the day (HH:MM), midnight if empty */
+	from: string;
+	/** Time of the day (HH:MM), ends on the next day if not after from */
+	to: string;
+	timezone: string;
+	/** Start of a one-off window */
+	start?: string;
+	/** End of a one-off window */
+	end?: string;
+};
+
+export type ConfigureFreeze = {
+	windows: Partial<FreezeWindow>[];
+};
+
+/** Timeouts of the deployment pipeline stages in seconds, 0 or missing to use the instance ones */
+export type DeploymentTimeouts = {
+	fetch?: number;
+	build?: number;
+	deploy?: number;
+	health_check?: number;
+};
+
+export type StaticSite = {
+	service: string;
+	directory: string;
 };
 
 export type EnvironmentConfig = {
 	target: TargetSummary;
 	vars?: EnvironmentVariablesPerService;
+	exposure?: ServicesExposure;
+	protection?: AccessProtection;
+	proxy_rules?: ProxyRules;
+	compose_override?: string;
+	url_aliases?: string[];
+	/** Number of containers per service when different than the compose file */
+	scale?: Record<string, number>;
+};
+
+export type ScaleService = {
+	environment: Environment;
+	service: string;
+	replicas: number;
+};
+
+export type RestartServices = {
+	environment: Environment;
+	/** Restart every service when empty */
+	services?: string[];
+};
+
+export type AccessProtection = {
+	basic_auth?: { username: string };
+	ip_allowlist?: string[];
+	forward_auth?: string;
 };
 
 export type CreateAppDataEnvironmentConfig = {
 	target: string;
 	vars?: EnvironmentVariablesPerService;
+	exposure?: ServicesExposure;
 };
 
 export type CreateApp = {
@@ -55,6 +160,10 @@ export type CreateApp = {
 	version_control?: VersionControl;
 	production: CreateAppDataEnvironmentConfig;
 	staging: CreateAppDataEnvironmentConfig;
+	team_id?: string;
+	platforms?: string[];
+	build_registry_id?: string;
+	static_site?: StaticSite;
 };
 
 export type UpdateApp = {
@@ -64,16 +173,293 @@ export type UpdateApp = {
 	}>;
 	production: Maybe<CreateAppDataEnvironmentConfig>;
 	staging: Maybe<CreateAppDataEnvironmentConfig>;
+	team_id?: Patch<string>;
+	platforms?: string[];
+	build_registry_id?: Patch<string>;
+	static_site?: Patch<StaticSite>;
+};
+
+export type AppLogsFilters = {
+	environment: Environment;
+	services?: string[];
+	tail?: number;
+	/** RFC3339 date or duration such as 10m */
+	since?: string;
+	follow?: boolean;
+};
+
+export type ExecService = {
+	environment: Environment;
+	service: string;
+	cmd: string[];
+	tty?: boolean;
+};
+
+export type ResourceUsageFilters = {
+	environment?: Environment;
+	service?: string;
+	/** RFC3339 date or duration such as 24h */
+	since?: string;
+};
+
+export type ResourceUsageSample = {
+	environment: Environment;
+	service: string;
+	collected_at: string;
+	/** In percent of a single CPU */
+	cpu: number;
+	/** In bytes */
+	memory: number;
+	memory_limit: number;
+	network_rx: number;
+	network_tx: number;
+};
+
+export type TrafficFilters = ResourceUsageFilters;
+
+export type StatusCounts = {
+	'2xx': number;
+	'3xx': number;
+	'4xx': number;
+	'5xx': number;
+};
+
+export type ServiceTraffic = {
+	environment: Environment;
+	service: string;
+	requests: number;
+	statuses: StatusCounts;
+	/** In milliseconds */
+	p95_latency: number;
+};
+
+export type TrafficSample = {
+	collected_at: string;
+	requests: number;
+	statuses: StatusCounts;
+};
+
+export type AppTraffic = {
+	requests: number;
+	statuses: StatusCounts;
+	/** In milliseconds */
+	p95_latency: number;
+	services: ServiceTraffic[];
+	samples: TrafficSample[];
+};
+
+export type IncidentKind = 'crashed' | 'restarted' | 'oom_killed';
+
+export type Incident = {
+	id: string;
+	deployment_number: number;
+	environment: Environment;
+	service: string;
+	container: string;
+	kind: IncidentKind;
+	exit_code?: number;
+	occurred_at: string;
+};
+
+export type QueryIncidentsFilters = {
+	page?: number;
+	environment?: Environment;
 };
 
+export type EnvImportMode = 'merge' | 'replace';
+
+export type ImportEnvVars = {
+	environment: Environment;
+	service: string;
+	/** Variables in the dotenv format */
+	content: string;
+	mode?: EnvImportMode;
+};
+
+export type EnvVarChangeKind = 'added' | 'updated' | 'removed';
+
+export type EnvRevision = {
+	id: string;
+	environment: Environment;
+	vars?: EnvironmentVariablesPerService;
+	changes: {
+		service: string;
+		name: string;
+		kind: EnvVarChangeKind;
+	}[];
+	created_at: string;
+	/** Not set for revisions recorded when upgrading seelf */
+	created_by?: ByUserData;
+};
+
+export type QueryEnvRevisionsFilters = {
+	page?: number;
+	environment?: Environment;
+};
+
+export enum MonitorStatus {
+	Unknown = 0,
+	Up = 1,
+	Down = 2
+}
+
+export type MonitorCheck = {
+	checked_at: string;
+	status: MonitorStatus;
+	status_code?: number;
+	/** In milliseconds */
+	response_time: number;
+	reason?: string;
+};
+
+export type Monitor = {
+	id: string;
+	environment: Environment;
+	/** In seconds */
+	interval: number;
+	status_code: number;
+	keyword?: string;
+	status: MonitorStatus;
+	next_check_at: string;
+	checks: MonitorCheck[];
+};
+
+export type ConfigureMonitor = {
+	/** In seconds */
+	interval: number;
+	status_code?: number;
+	keyword?: string;
+};
+
+export type Redirect = {
+	regex: string;
+	replacement: string;
+	permanent: boolean;
+};
+
+export type ProxyRules = {
+	www_redirect: boolean;
+	insecure_paths?: string[];
+	redirects?: Redirect[];
+	headers?: Record<string, string>;
+};
+
+export type ConfigureAccessProtection = {
+	/** Password could be omitted to keep the current one */
+	basic_auth?: { username: string; password?: string };
+	ip_allowlist?: string[];
+	forward_auth?: string;
+};
+
+export type AddonKind = 'postgres' | 'mysql' | 'redis';
+
+export enum AddonStatus {
+	Provisioning = 0,
+	Failed = 1,
+	Ready = 2
+}
+
+export type Addon = {
+	id: string;
+	environment: Environment;
+	kind: AddonKind;
+	variable: string;
+	host: string;
+	connection_string: string;
+	target: TargetSummary;
+	state: {
+		status: AddonStatus;
+		version: string;
+		error_code?: string;
+	};
+	cleanup_requested_at?: string;
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export type CreateAddon = {
+	environment: Environment;
+	kind: AddonKind;
+	variable?: string;
+};
+
+export enum AddonBackupStatus {
+	Running = 0,
+	Failed = 1,
+	Succeeded = 2
+}
+
+export type AddonBackup = {
+	id: string;
+	status: AddonBackupStatus;
+	size: number;
+	error_code?: string;
+	restore?: {
+		requested_at: string;
+		requested_by: ByUserData;
+		finished_at?: string;
+		error_code?: string;
+	};
+	created_at: string;
+};
+
+/** Last message sent on an exec connection */
+export type ExecResult = { exit_code: number } | { error: { code: string } };
+
 export interface AppsService {
 	create(payload: CreateApp): Promise<AppDetail>;
 	update(id: string, payload: UpdateApp): Promise<AppDetail>;
 	delete(id: string): Promise<void>;
+	clearBuildCache(id: string): Promise<void>;
+	generateBadgeToken(id: string): Promise<BadgeToken>;
+	revokeBadgeToken(id: string): Promise<void>;
+	badgeUrl(id: string, environment: Environment, token: string): string;
+	configureDeployTrigger(id: string, payload: ConfigureDeployTrigger): Promise<DeployTrigger>;
+	removeDeployTrigger(id: string): Promise<void>;
+	configureFreeze(id: string, payload: ConfigureFreeze): Promise<void>;
+	configureTimeouts(id: string, payload: DeploymentTimeouts): Promise<void>;
+	logsStreamUrl(id: string, filters: AppLogsFilters): string;
+	execUrl(id: string, exec: ExecService): string;
+	exportEnvVarsUrl(id: string, environment: Environment, service: string): string;
 	fetchAll(options?: FetchOptions): Promise<App[]>;
 	fetchById(id: string, options?: FetchOptions): Promise<AppDetail>;
 	queryAll(): QueryResult<App[]>;
 	queryById(id: string): QueryResult<AppDetail>;
+	queryResourceUsage(
+		id: string,
+		filters?: ResourceUsageFilters
+	): QueryResult<ResourceUsageSample[]>;
+	queryTraffic(id: string, filters?: TrafficFilters): QueryResult<AppTraffic>;
+	queryIncidents(id: string, filters?: QueryIncidentsFilters): QueryResult<Paginated<Incident>>;
+	importEnvVars(id: string, payload: ImportEnvVars): Promise<void>;
+	queryEnvRevisions(
+		id: string,
+		filters?: QueryEnvRevisionsFilters
+	): QueryResult<Paginated<EnvRevision>>;
+	restoreEnvRevision(id: string, revisionId: string): Promise<void>;
+	queryMonitors(id: string): QueryResult<Monitor[]>;
+	configureMonitor(id: string, environment: Environment, payload: ConfigureMonitor): Promise<void>;
+	deleteMonitor(id: string, environment: Environment): Promise<void>;
+	configureAccessProtection(
+		id: string,
+		environment: Environment,
+		payload: ConfigureAccessProtection
+	): Promise<void>;
+	removeAccessProtection(id: string, environment: Environment): Promise<void>;
+	configureProxyRules(id: string, environment: Environment, payload: ProxyRules): Promise<void>;
+	removeProxyRules(id: string, environment: Environment): Promise<void>;
+	configureComposeOverride(id: string, environment: Environment, content: string): Promise<void>;
+	removeComposeOverride(id: string, environment: Environment): Promise<void>;
+	configureUrlAliases(id: string, environment: Environment, aliases: string[]): Promise<void>;
+	removeUrlAliases(id: string, environment: Environment): Promise<void>;
+	scaleService(id: string, data: ScaleService): Promise<void>;
+	restartServices(id: string, data: RestartServices): Promise<void>;
+	queryAddons(id: string): QueryResult<Addon[]>;
+	createAddon(id: string, payload: CreateAddon): Promise<Addon>;
+	deleteAddon(id: string, addonId: string): Promise<void>;
+	queryAddonBackups(id: string, addonId: string): QueryResult<AddonBackup[]>;
+	backupAddon(id: string, addonId: string): Promise<AddonBackup>;
+	restoreAddonBackup(id: string, addonId: string, backupId: string): Promise<void>;
 }
 
 type Options = {
@@ -97,6 +483,78 @@ export class RemoteAppsService implements AppsService {
 		});
 	}
 
+	clearBuildCache(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/build-cache`);
+	}
+
+	generateBadgeToken(id: string): Promise<BadgeToken> {
+		return this._fetcher.post(`/api/v1/apps/${id}/badge-token`, undefined, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	revokeBadgeToken(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/badge-token`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	badgeUrl(id: string, environment: Environment, token: string): string {
+		return `/api/v1/apps/${id}/badges/${environment}?${new URLSearchParams({ token })}`;
+	}
+
+	configureDeployTrigger(id: string, payload: ConfigureDeployTrigger): Promise<DeployTrigger> {
+		return this._fetcher.put(`/api/v1/apps/${id}/deploy-trigger`, payload, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	removeDeployTrigger(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/deploy-trigger`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	configureFreeze(id: string, payload: ConfigureFreeze): Promise<void> {
+		return this._fetcher.put(`/api/v1/apps/${id}/freeze`, payload, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	configureTimeouts(id: string, payload: DeploymentTimeouts): Promise<void> {
+		return this._fetcher.put(`/api/v1/apps/${id}/timeouts`, payload, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	logsStreamUrl(id: string, filters: AppLogsFilters): string {
+		const params = new URLSearchParams({ environment: filters.environment });
+
+		filters.services?.forEach((service) => params.append('service', service));
+
+		if (filters.tail !== undefined) params.set('tail', filters.tail.toString());
+		if (filters.since) params.set('since', filters.since);
+		if (filters.follow) params.set('follow', 'true');
+
+		return `/api/v1/apps/${id}/logs?${params}`;
+	}
+
+	execUrl(id: string, exec: ExecService): string {
+		const params = new URLSearchParams({ environment: exec.environment, service: exec.service });
+
+		exec.cmd.forEach((arg) => params.append('cmd', arg));
+
+		if (exec.tty) params.set('tty', 'true');
+
+		return `/api/v1/apps/${id}/exec?${params}`;
+	}
+
+	exportEnvVarsUrl(id: string, environment: Environment, service: string): string {
+		const params = new URLSearchParams({ environment, service });
+
+		return `/api/v1/apps/${id}/env-vars?${params}`;
+	}
+
 	queryAll(): QueryResult<App[]> {
 		return this._fetcher.query('/api/v1/apps', { refreshInterval: this._options.pollingInterval });
 	}
@@ -107,6 +565,178 @@ export class RemoteAppsService implements AppsService {
 		});
 	}
 
+	queryResourceUsage(
+		id: string,
+		filters?: ResourceUsageFilters
+	): QueryResult<ResourceUsageSample[]> {
+		return this._fetcher.query(`/api/v1/apps/${id}/resource-usage`, {
+			params: filters,
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	queryTraffic(id: string, filters?: TrafficFilters): QueryResult<AppTraffic> {
+		return this._fetcher.query(`/api/v1/apps/${id}/traffic`, {
+			params: filters,
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	queryIncidents(id: string, filters?: QueryIncidentsFilters): QueryResult<Paginated<Incident>> {
+		return this._fetcher.query(`/api/v1/apps/${id}/incidents`, {
+			params: filters
+		});
+	}
+
+	importEnvVars(id: string, payload: ImportEnvVars): Promise<void> {
+		return this._fetcher.post(`/api/v1/apps/${id}/env-vars`, payload, {
+			invalidate: [`/api/v1/apps/${id}`, `/api/v1/apps/${id}/env-revisions`]
+		});
+	}
+
+	queryEnvRevisions(
+		id: string,
+		filters?: QueryEnvRevisionsFilters
+	): QueryResult<Paginated<EnvRevision>> {
+		return this._fetcher.query(`/api/v1/apps/${id}/env-revisions`, {
+			params: filters
+		});
+	}
+
+	restoreEnvRevision(id: string, revisionId: string): Promise<void> {
+		return this._fetcher.post(`/api/v1/apps/${id}/env-revisions/${revisionId}/restore`, undefined, {
+			invalidate: [`/api/v1/apps/${id}`, `/api/v1/apps/${id}/env-revisions`]
+		});
+	}
+
+	queryMonitors(id: string): QueryResult<Monitor[]> {
+		return this._fetcher.query(`/api/v1/apps/${id}/monitors`, {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	configureMonitor(id: string, environment: Environment, payload: ConfigureMonitor): Promise<void> {
+		return this._fetcher.put(`/api/v1/apps/${id}/monitors/${environment}`, payload, {
+			invalidate: [`/api/v1/apps/${id}/monitors`]
+		});
+	}
+
+	deleteMonitor(id: string, environment: Environment): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/monitors/${environment}`, {
+			invalidate: [`/api/v1/apps/${id}/monitors`]
+		});
+	}
+
+	configureAccessProtection(
+		id: string,
+		environment: Environment,
+		payload: ConfigureAccessProtection
+	): Promise<void> {
+		return this._fetcher.put(`/api/v1/apps/${id}/protection/${environment}`, payload, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	removeAccessProtection(id: string, environment: Environment): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/protection/${environment}`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	configureProxyRules(id: string, environment: Environment, payload: ProxyRules): Promise<void> {
+		return this._fetcher.put(`/api/v1/apps/${id}/proxy-rules/${environment}`, payload, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	removeProxyRules(id: string, environment: Environment): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/proxy-rules/${environment}`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	configureComposeOverride(id: string, environment: Environment, content: string): Promise<void> {
+		return this._fetcher.put(
+			`/api/v1/apps/${id}/compose-override/${environment}`,
+			{ content },
+			{
+				invalidate: [`/api/v1/apps/${id}`]
+			}
+		);
+	}
+
+	removeComposeOverride(id: string, environment: Environment): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/compose-override/${environment}`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	configureUrlAliases(id: string, environment: Environment, aliases: string[]): Promise<void> {
+		return this._fetcher.put(
+			`/api/v1/apps/${id}/url-aliases/${environment}`,
+			{ aliases },
+			{
+				invalidate: [`/api/v1/apps/${id}`]
+			}
+		);
+	}
+
+	removeUrlAliases(id: string, environment: Environment): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/url-aliases/${environment}`, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	scaleService(id: string, data: ScaleService): Promise<void> {
+		return this._fetcher.post(`/api/v1/apps/${id}/scale`, data, {
+			invalidate: [`/api/v1/apps/${id}`]
+		});
+	}
+
+	restartServices(id: string, data: RestartServices): Promise<void> {
+		return this._fetcher.post(`/api/v1/apps/${id}/restart`, data);
+	}
+
+	queryAddons(id: string): QueryResult<Addon[]> {
+		return this._fetcher.query(`/api/v1/apps/${id}/addons`, {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	createAddon(id: string, payload: CreateAddon): Promise<Addon> {
+		return this._fetcher.post(`/api/v1/apps/${id}/addons`, payload, {
+			invalidate: [`/api/v1/apps/${id}/addons`]
+		});
+	}
+
+	deleteAddon(id: string, addonId: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/apps/${id}/addons/${addonId}`, {
+			invalidate: [`/api/v1/apps/${id}/addons`]
+		});
+	}
+
+	queryAddonBackups(id: string, addonId: string): QueryResult<AddonBackup[]> {
+		return this._fetcher.query(`/api/v1/apps/${id}/addons/${addonId}/backups`, {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	backupAddon(id: string, addonId: string): Promise<AddonBackup> {
+		return this._fetcher.post(`/api/v1/apps/${id}/addons/${addonId}/backups`, undefined, {
+			invalidate: [`/api/v1/apps/${id}/addons/${addonId}/backups`]
+		});
+	}
+
+	restoreAddonBackup(id: string, addonId: string, backupId: string): Promise<void> {
+		return this._fetcher.post(
+			`/api/v1/apps/${id}/addons/${addonId}/backups/${backupId}/restore`,
+			undefined,
+			{
+				invalidate: [`/api/v1/apps/${id}/addons/${addonId}/backups`]
+			}
+		);
+	}
+
 	fetchAll(options?: FetchOptions): Promise<App[]> {
 		return this._fetcher.get('/api/v1/apps', options);
 	}
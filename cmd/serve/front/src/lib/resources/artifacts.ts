@@ -0,0 +1,29 @@
+import fetcher, { type FetchOptions, type FetchService } from '$lib/fetcher';
+
+export type ArtifactsUsage = {
+	total: number;
+	computed_at?: string;
+	apps: AppArtifactsUsage[];
+};
+
+export type AppArtifactsUsage = {
+	id: string;
+	name?: string;
+	size: number;
+};
+
+export interface ArtifactsService {
+	fetchUsage(options?: FetchOptions): Promise<ArtifactsUsage>;
+}
+
+export class RemoteArtifactsService implements ArtifactsService {
+	constructor(private readonly _fetcher: FetchService) {}
+
+	fetchUsage(options?: FetchOptions): Promise<ArtifactsUsage> {
+		return this._fetcher.get('/api/v1/artifacts/usage', options);
+	}
+}
+
+const service: ArtifactsService = new RemoteArtifactsService(fetcher);
+
+export default service;
@@ -0,0 +1,91 @@
+import { POLLING_INTERVAL_MS } from '$lib/config';
+import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
+import type { ByUserData } from '$lib/resources/users';
+
+export type ChannelKind = 'slack' | 'discord' | 'matrix';
+
+export type Channel = {
+	id: string;
+	name: string;
+	kind: ChannelKind;
+	url: string;
+	room_id?: string;
+	access_token?: string;
+	app?: ChannelApp;
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export type ChannelApp = {
+	id: string;
+	name: string;
+};
+
+export type CreateChannel = {
+	name: string;
+	kind: ChannelKind;
+	url: string;
+	room_id?: string;
+	access_token?: string;
+	app_id?: string;
+};
+
+export type UpdateChannel = {
+	name?: string;
+	url?: string;
+	room_id?: string;
+	access_token?: string;
+};
+
+export interface ChannelsService {
+	create(payload: CreateChannel): Promise<Channel>;
+	update(id: string, payload: UpdateChannel): Promise<Channel>;
+	delete(id: string): Promise<void>;
+	fetchAll(options?: FetchOptions): Promise<Channel[]>;
+	fetchById(id: string, options?: FetchOptions): Promise<Channel>;
+	queryAll(appId?: string): QueryResult<Channel[]>;
+}
+
+type Options = {
+	pollingInterval: number;
+};
+
+export class RemoteChannelsService implements ChannelsService {
+	constructor(private readonly _fetcher: FetchService, private readonly _options: Options) {}
+
+	create(payload: CreateChannel): Promise<Channel> {
+		return this._fetcher.post('/api/v1/channels', payload);
+	}
+
+	update(id: string, payload: UpdateChannel): Promise<Channel> {
+		return this._fetcher.patch(`/api/v1/channels/${id}`, payload);
+	}
+
+	delete(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/channels/${id}`, {
+			invalidate: ['/api/v1/channels'],
+			skipUrlInvalidate: true
+		});
+	}
+
+	queryAll(appId?: string): QueryResult<Channel[]> {
+		return this._fetcher.query('/api/v1/channels', {
+			refreshInterval: this._options.pollingInterval,
+			params: appId ? { app_id: appId } : undefined
+		});
+	}
+
+	fetchAll(options?: FetchOptions): Promise<Channel[]> {
+		return this._fetcher.get('/api/v1/channels', options);
+	}
+
+	fetchById(id: string, options?: FetchOptions): Promise<Channel> {
+		return this._fetcher.get(`/api/v1/channels/${id}`, options);
+	}
+}
+
+const service: ChannelsService = new RemoteChannelsService(fetcher, {
+	pollingInterval: POLLING_INTERVAL_MS
+});
+
+export default service;
@@ -29,6 +29,7 @@ export type Entrypoint = {
 	port: number;
 	is_custom: boolean;
 	subdomain?: string;
+	path_prefix?: string;
 	url?: string;
 	published_port?: number;
 };
@@ -68,10 +69,23 @@ export type Deployment = {
 	state: State;
 	requested_at: string;
 	requested_by: ByUserData;
+	/** Empty when not set */
+	note: string;
+	/** Structured data such as a ticket identifier or a release version */
+	metadata: Record<string, string>;
 };
 
 export type DeploymentDetail = Omit<Deployment, 'state'> & {
 	state: StateWithServices;
+	/** Only set for pending deployments */
+	queue?: DeploymentQueue;
+};
+
+export type DeploymentQueue = {
+	position: number;
+	estimated_start_at?: string;
+	/** In milliseconds */
+	estimated_duration?: number;
 };
 
 export type QueueDeployment =
@@ -83,20 +97,76 @@ export type QueueDeployment =
 export type QueryDeploymentsFilters = {
 	page?: number;
 	environment?: Environment;
+	/** Matches the note and metadata of deployments */
+	search?: string;
+};
+
+export type AnnotateDeployment = {
+	note?: string;
+	metadata?: Record<string, string>;
+};
+
+export type DeploymentStep = 'fetch' | 'build' | 'scan' | 'deploy' | 'cleanup';
+
+export type LogRecord = {
+	time?: string;
+	level: 'step' | 'info' | 'warn' | 'error';
+	stream: 'seelf' | 'output';
+	message: string;
+};
+
+export type LogStep = {
+	/** Empty for logs written before records were structured */
+	step: DeploymentStep | '';
+	started_at?: string;
+	/** In milliseconds */
+	duration: number;
+	records: LogRecord[];
+};
+
+export type ImageManifest = {
+	service: string;
+	image: string;
+	id?: string;
+	repo_digests?: string[];
+	built: boolean;
+	base_images?: string[];
+	build_args?: Record<string, string>;
+	sbom: boolean;
+	vulnerabilities?: Partial<Record<VulnerabilitySeverity, number>>;
+};
+
+export type VulnerabilitySeverity =
+	| 'unknown'
+	| 'negligible'
+	| 'low'
+	| 'medium'
+	| 'high'
+	| 'critical';
+
+export type DeploymentManifest = {
+	generated_at: string;
+	images: ImageManifest[];
 };
 
 export interface DeploymentsService {
 	queue(appid: string, data: QueueDeployment): Promise<Deployment>;
 	redeploy(appid: string, number: number): Promise<Deployment>;
 	promote(appid: string, number: number): Promise<Deployment>;
+	unfreeze(appid: string, number: number): Promise<DeploymentDetail>;
 	queryAllByApp(id: string, filters?: QueryDeploymentsFilters): QueryResult<Paginated<Deployment>>;
 	queryLogs(appid: string, number: number, poll?: boolean): QueryResult<string>;
+	queryLogSteps(appid: string, number: number, poll?: boolean): QueryResult<LogStep[]>;
 	queryByAppAndNumber(appid: string, number: number, poll?: boolean): QueryResult<DeploymentDetail>;
 	fetchByAppAndNumber(
 		appid: string,
 		number: number,
 		options?: FetchOptions
 	): Promise<DeploymentDetail>;
+	logsDownloadUrl(appid: string, number: number): string;
+	manifestDownloadUrl(appid: string, number: number): string;
+	sbomDownloadUrl(appid: string, number: number, service: string): string;
+	scanDownloadUrl(appid: string, number: number, service: string): string;
 }
 
 type Options = {
@@ -115,6 +185,22 @@ class RemoteDeploymentsService implements DeploymentsService {
 		return this._fetcher.get(`/api/v1/apps/${appid}/deployments/${number}`, options);
 	}
 
+	logsDownloadUrl(appid: string, number: number): string {
+		return `/api/v1/apps/${appid}/deployments/${number}/logs/download`;
+	}
+
+	manifestDownloadUrl(appid: string, number: number): string {
+		return `/api/v1/apps/${appid}/deployments/${number}/manifest`;
+	}
+
+	sbomDownloadUrl(appid: string, number: number, service: string): string {
+		return `/api/v1/apps/${appid}/deployments/${number}/sbom/${service}`;
+	}
+
+	scanDownloadUrl(appid: string, number: number, service: string): string {
+		return `/api/v1/apps/${appid}/deployments/${number}/scan/${service}`;
+	}
+
 	queryLogs(appid: string, number: number, poll?: boolean): QueryResult<string> {
 		return this._fetcher.query(`/api/v1/apps/${appid}/deployments/${number}/logs`, {
 			refreshInterval: poll ? this._options.runningDeploymentsPollingInterval : undefined,
@@ -122,6 +208,13 @@ class RemoteDeploymentsService implements DeploymentsService {
 		});
 	}
 
+	queryLogSteps(appid: string, number: number, poll?: boolean): QueryResult<LogStep[]> {
+		return this._fetcher.query(`/api/v1/apps/${appid}/deployments/${number}/logs/steps`, {
+			refreshInterval: poll ? this._options.runningDeploymentsPollingInterval : undefined,
+			cache: 'no-store'
+		});
+	}
+
 	queryByAppAndNumber(
 		appid: string,
 		number: number,
@@ -150,6 +243,12 @@ class RemoteDeploymentsService implements DeploymentsService {
 		});
 	}
 
+	unfreeze(appid: string, number: number): Promise<DeploymentDetail> {
+		return this._fetcher.post(`/api/v1/apps/${appid}/deployments/${number}/unfreeze`, undefined, {
+			invalidate: [`/api/v1/apps/${appid}`, `/api/v1/apps/${appid}/deployments/${number}`]
+		});
+	}
+
 	queryAllByApp(id: string, filters?: QueryDeploymentsFilters): QueryResult<Paginated<Deployment>> {
 		return this._fetcher.query(`/api/v1/apps/${id}/deployments`, {
 			params: filters
@@ -0,0 +1,102 @@
+import { POLLING_INTERVAL_MS } from '$lib/config';
+import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
+import type { ByUserData } from '$lib/resources/users';
+
+export type DnsProvider = 'cloudflare' | 'route53' | 'desec';
+
+export type DnsRecordSet = {
+	type: 'A' | 'AAAA' | 'CNAME';
+	values: string[];
+};
+
+export type DnsRecord = {
+	host: string;
+	app_id: string;
+	app_name: string;
+	environment: string;
+	records: DnsRecordSet[];
+	status: 'synced' | 'failed';
+	error?: string;
+	drifted_at?: string;
+	checked_at: string;
+};
+
+export type DnsZone = {
+	id: string;
+	name: string;
+	provider: DnsProvider;
+	addresses: string[];
+	created_at: string;
+	created_by: ByUserData;
+	records: DnsRecord[];
+};
+
+export type DnsCredentials = {
+	token?: string;
+	access_key?: string;
+	secret_key?: string;
+};
+
+export type CreateDnsZone = {
+	name: string;
+	provider: DnsProvider;
+	credentials: DnsCredentials;
+	addresses: string[];
+};
+
+export type UpdateDnsZone = {
+	credentials?: DnsCredentials;
+	addresses?: string[];
+};
+
+export interface DnsService {
+	create(payload: CreateDnsZone): Promise<DnsZone>;
+	update(id: string, payload: UpdateDnsZone): Promise<DnsZone>;
+	delete(id: string): Promise<void>;
+	fetchAll(options?: FetchOptions): Promise<DnsZone[]>;
+	fetchById(id: string, options?: FetchOptions): Promise<DnsZone>;
+	queryAll(): QueryResult<DnsZone[]>;
+}
+
+type Options = {
+	pollingInterval: number;
+};
+
+export class RemoteDnsService implements DnsService {
+	constructor(private readonly _fetcher: FetchService, private readonly _options: Options) {}
+
+	create(payload: CreateDnsZone): Promise<DnsZone> {
+		return this._fetcher.post('/api/v1/dns/zones', payload);
+	}
+
+	update(id: string, payload: UpdateDnsZone): Promise<DnsZone> {
+		return this._fetcher.patch(`/api/v1/dns/zones/${id}`, payload);
+	}
+
+	delete(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/dns/zones/${id}`, {
+			invalidate: ['/api/v1/dns/zones'],
+			skipUrlInvalidate: true
+		});
+	}
+
+	queryAll(): QueryResult<DnsZone[]> {
+		return this._fetcher.query('/api/v1/dns/zones', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	fetchAll(options?: FetchOptions): Promise<DnsZone[]> {
+		return this._fetcher.get('/api/v1/dns/zones', options);
+	}
+
+	fetchById(id: string, options?: FetchOptions): Promise<DnsZone> {
+		return this._fetcher.get(`/api/v1/dns/zones/${id}`, options);
+	}
+}
+
+const service: DnsService = new RemoteDnsService(fetcher, {
+	pollingInterval: POLLING_INTERVAL_MS
+});
+
+export default service;
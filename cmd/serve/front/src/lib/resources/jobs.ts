@@ -8,13 +8,33 @@ export type Job = {
 	group: string;
 	message_name: string;
 	message_data: string;
+	message_version: number;
 	queued_at: string;
 	not_before: string;
 	error_code?: string;
+	failed_at?: string;
 	policy: number;
 	retrieved: boolean;
 };
 
+export type WorkerHeartbeat = {
+	started_at: string;
+	beat_at: string;
+	last_poll?: string;
+	jobs_processed: number;
+	jobs_failed: number;
+	running_jobs: number;
+	paused: boolean;
+};
+
+export type WorkerStatus = {
+	heartbeat?: WorkerHeartbeat;
+	/** In nanoseconds */
+	threshold: number;
+	stalled: boolean;
+	warning?: string;
+};
+
 export enum JobPolicy {
 	PreserveOrder = 1,
 	WaitForOthersResourceID = 2,
@@ -26,6 +46,7 @@ export interface JobsService {
 	delete(id: string): Promise<void>;
 	fetchAll(page: number, options?: FetchOptions): Promise<Paginated<Job>>;
 	queryAll(page: number): QueryResult<Paginated<Job>>;
+	queryWorker(): QueryResult<WorkerStatus>;
 }
 
 type Options = {
@@ -50,6 +71,12 @@ export class RemoteJobsService implements JobsService {
 		});
 	}
 
+	queryWorker(): QueryResult<WorkerStatus> {
+		return this._fetcher.query('/api/v1/worker', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
 	fetchAll(page: number, options?: FetchOptions): Promise<Paginated<Job>> {
 		return this._fetcher.get('/api/v1/jobs', {
 			...options,
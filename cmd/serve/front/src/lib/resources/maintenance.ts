@@ -0,0 +1,34 @@
+import fetcher, { type FetchOptions, type FetchService } from '$lib/fetcher';
+
+export type MaintenanceStatus = {
+	enabled: boolean;
+	since?: string;
+	running_jobs: number;
+};
+
+export type UpdateMaintenance = {
+	enabled: boolean;
+};
+
+export interface MaintenanceService {
+	fetch(options?: FetchOptions): Promise<MaintenanceStatus>;
+	update(payload: UpdateMaintenance): Promise<MaintenanceStatus>;
+}
+
+export class RemoteMaintenanceService implements MaintenanceService {
+	constructor(private readonly _fetcher: FetchService) {}
+
+	fetch(options?: FetchOptions): Promise<MaintenanceStatus> {
+		return this._fetcher.get('/api/v1/maintenance', options);
+	}
+
+	update(payload: UpdateMaintenance): Promise<MaintenanceStatus> {
+		return this._fetcher.put('/api/v1/maintenance', payload, {
+			invalidate: ['/api/v1/maintenance']
+		});
+	}
+}
+
+const service: MaintenanceService = new RemoteMaintenanceService(fetcher);
+
+export default service;
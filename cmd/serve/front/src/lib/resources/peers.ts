@@ -0,0 +1,100 @@
+import { POLLING_INTERVAL_MS } from '$lib/config';
+import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
+import type { App } from '$lib/resources/apps';
+import type { ByUserData } from '$lib/resources/users';
+
+export type Peer = {
+	id: string;
+	name: string;
+	url: string;
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export type CreatePeer = {
+	name: string;
+	url: string;
+	token: string;
+};
+
+export type UpdatePeer = {
+	name?: string;
+	url?: string;
+	token?: string;
+};
+
+export type FederatedInstance = {
+	id?: string;
+	name: string;
+	url?: string;
+	error?: string;
+};
+
+export type FederatedApp = App & {
+	instance?: string;
+};
+
+export type FederatedApps = {
+	instances: FederatedInstance[];
+	apps: FederatedApp[];
+};
+
+export interface PeersService {
+	create(payload: CreatePeer): Promise<Peer>;
+	update(id: string, payload: UpdatePeer): Promise<Peer>;
+	delete(id: string): Promise<void>;
+	fetchAll(options?: FetchOptions): Promise<Peer[]>;
+	fetchById(id: string, options?: FetchOptions): Promise<Peer>;
+	queryAll(): QueryResult<Peer[]>;
+	queryFederatedApps(labels?: string[]): QueryResult<FederatedApps>;
+}
+
+type Options = {
+	pollingInterval: number;
+};
+
+export class RemotePeersService implements PeersService {
+	constructor(private readonly _fetcher: FetchService, private readonly _options: Options) {}
+
+	create(payload: CreatePeer): Promise<Peer> {
+		return this._fetcher.post('/api/v1/peers', payload);
+	}
+
+	update(id: string, payload: UpdatePeer): Promise<Peer> {
+		return this._fetcher.patch(`/api/v1/peers/${id}`, payload);
+	}
+
+	delete(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/peers/${id}`, {
+			invalidate: ['/api/v1/peers', '/api/v1/federation/apps'],
+			skipUrlInvalidate: true
+		});
+	}
+
+	queryAll(): QueryResult<Peer[]> {
+		return this._fetcher.query('/api/v1/peers', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	queryFederatedApps(labels: string[] = []): QueryResult<FederatedApps> {
+		return this._fetcher.query('/api/v1/federation/apps', {
+			refreshInterval: this._options.pollingInterval,
+			params: labels.length ? labels.map((label) => ['labels', label]) : undefined
+		});
+	}
+
+	fetchAll(options?: FetchOptions): Promise<Peer[]> {
+		return this._fetcher.get('/api/v1/peers', options);
+	}
+
+	fetchById(id: string, options?: FetchOptions): Promise<Peer> {
+		return this._fetcher.get(`/api/v1/peers/${id}`, options);
+	}
+}
+
+const service: PeersService = new RemotePeersService(fetcher, {
+	pollingInterval: POLLING_INTERVAL_MS
+});
+
+export default service;
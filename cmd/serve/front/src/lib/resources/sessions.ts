@@ -1,8 +1,14 @@
 import fetcher, { type FetchOptions, type FetchService } from '$lib/fetcher';
 import type { Profile } from '$lib/resources/users';
 
+export type AuthMethods = {
+	password: boolean;
+	oidc?: string | null;
+};
+
 export interface SessionsService {
 	create(email: string, password: string): Promise<Profile>;
+	getMethods(options?: FetchOptions): Promise<AuthMethods>;
 	getCurrent(options?: FetchOptions): Promise<Profile>;
 	delete(): Promise<void>;
 }
@@ -17,6 +23,10 @@ export class RemoteSessionsService implements SessionsService {
 		});
 	}
 
+	getMethods(options?: FetchOptions): Promise<AuthMethods> {
+		return this._fetcher.get('/api/v1/auth/methods', options);
+	}
+
 	getCurrent(options?: FetchOptions): Promise<Profile> {
 		return this._fetcher.get('/api/v1/profile', options);
 	}
@@ -0,0 +1,63 @@
+import fetcher, { type FetchOptions, type FetchService } from '$lib/fetcher';
+import type { Target } from '$lib/resources/targets';
+import type { Profile } from '$lib/resources/users';
+
+export type SetupStatus = {
+	required: boolean;
+};
+
+export type SetupAccount = {
+	email: string;
+	password: string;
+};
+
+export type SetupUrl = {
+	url: string;
+};
+
+export type SetupCheck = {
+	name: 'dns' | 'wildcard' | 'proxy';
+	healthy: boolean;
+	error?: string;
+	duration: number;
+};
+
+export type SetupChecksReport = {
+	healthy: boolean;
+	checks: SetupCheck[];
+};
+
+export interface SetupService {
+	fetch(options?: FetchOptions): Promise<SetupStatus>;
+	createAccount(payload: SetupAccount): Promise<Profile>;
+	check(payload: SetupUrl): Promise<SetupChecksReport>;
+	createTarget(payload: SetupUrl): Promise<Target>;
+}
+
+export class RemoteSetupService implements SetupService {
+	constructor(private readonly _fetcher: FetchService) {}
+
+	fetch(options?: FetchOptions): Promise<SetupStatus> {
+		return this._fetcher.get('/api/v1/setup', options);
+	}
+
+	createAccount(payload: SetupAccount): Promise<Profile> {
+		return this._fetcher.post('/api/v1/setup/account', payload, {
+			invalidate: ['/api/v1/setup']
+		});
+	}
+
+	check(payload: SetupUrl): Promise<SetupChecksReport> {
+		return this._fetcher.post('/api/v1/setup/checks', payload);
+	}
+
+	createTarget(payload: SetupUrl): Promise<Target> {
+		return this._fetcher.post('/api/v1/setup/target', payload, {
+			invalidate: ['/api/v1/targets']
+		});
+	}
+}
+
+const service: SetupService = new RemoteSetupService(fetcher);
+
+export default service;
@@ -21,6 +21,19 @@ export type ProviderConfigData = {
 		user?: string;
 		port?: number;
 		private_key?: string;
+		host_key?: string;
+		bastion?: {
+			host: string;
+			port: number;
+			user: string;
+			private_key?: string;
+			host_key?: string;
+		};
+		tls?: {
+			ca: string;
+			cert: string;
+			key: string;
+		};
 	};
 };
 
@@ -32,11 +45,22 @@ export type Target = {
 	url: string;
 	provider: ProviderConfigData;
 	state: TargetState;
+	platform?: string;
+	proxy_version?: string;
+	last_garbage_collection?: GarbageCollection;
 	cleanup_requested_at?: string;
 	created_at: string;
 	created_by: ByUserData;
 };
 
+export type GarbageCollection = {
+	images: number;
+	containers: number;
+	networks: number;
+	space_reclaimed: number;
+	collected_at: string;
+};
+
 export type CreateTarget = {
 	name: string;
 	url: string;
@@ -45,9 +69,24 @@ export type CreateTarget = {
 		user?: string;
 		port?: number;
 		private_key?: string;
+		host_key?: string;
+		bastion?: {
+			host: string;
+			port?: number;
+			user?: string;
+			private_key?: string;
+			host_key?: string;
+		};
+		tls?: TlsConfig;
 	};
 };
 
+export type TlsConfig = {
+	ca: string;
+	cert: string;
+	key?: string;
+};
+
 export type UpdateTarget = {
 	name?: string;
 	url?: string;
@@ -56,6 +95,15 @@ export type UpdateTarget = {
 		user?: string;
 		port?: number;
 		private_key: Patch<string>;
+		host_key?: string;
+		bastion?: {
+			host: string;
+			port?: number;
+			user?: string;
+			private_key: Patch<string>;
+			host_key?: string;
+		};
+		tls?: TlsConfig;
 	};
 };
 
@@ -63,13 +111,32 @@ export type GetTargetsFilters = {
 	active_only?: boolean;
 };
 
+export type TargetCapacity = {
+	id: string;
+	name: string;
+	url: string;
+	apps: number;
+	reserved_ports: string[];
+	resources?: TargetResources;
+};
+
+export type TargetResources = {
+	running_containers: number;
+	images_size: number;
+	disk_free: number;
+	disk_total: number;
+	collected_at: string;
+};
+
 export interface TargetsService {
 	create(payload: CreateTarget): Promise<Target>;
 	update(id: string, payload: UpdateTarget): Promise<Target>;
 	reconfigure(id: string): Promise<void>;
+	upgradeProxy(id: string): Promise<void>;
 	fetchAll(filters?: GetTargetsFilters, options?: FetchOptions): Promise<Target[]>;
 	fetchById(id: string, options?: FetchOptions): Promise<Target>;
 	queryAll(): QueryResult<Target[]>;
+	queryCapacity(): QueryResult<TargetCapacity[]>;
 	delete(id: string): Promise<void>;
 }
 
@@ -102,6 +169,12 @@ export class RemoteTargetsService implements TargetsService {
 		});
 	}
 
+	upgradeProxy(id: string): Promise<void> {
+		return this._fetcher.post(`/api/v1/targets/${id}/proxy/upgrade`, undefined, {
+			invalidate: [`/api/v1/targets/${id}`, '/api/v1/targets']
+		});
+	}
+
 	fetchAll(filters?: GetTargetsFilters, options?: FetchOptions): Promise<Target[]> {
 		return this._fetcher.get('/api/v1/targets', {
 			...options,
@@ -118,6 +191,12 @@ export class RemoteTargetsService implements TargetsService {
 			refreshInterval: this._options.pollingInterval
 		});
 	}
+
+	queryCapacity(): QueryResult<TargetCapacity[]> {
+		return this._fetcher.query('/api/v1/targets/capacity', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
 }
 
 const service: TargetsService = new RemoteTargetsService(fetcher, {
@@ -0,0 +1,85 @@
+import { POLLING_INTERVAL_MS } from '$lib/config';
+import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
+import type { ByUserData } from '$lib/resources/users';
+
+export type Team = {
+	id: string;
+	name: string;
+	apps_count: number;
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export type TeamDetail = {
+	id: string;
+	name: string;
+	members: TeamMember[];
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export type TeamMember = {
+	id: string;
+	email: string;
+	role: string;
+};
+
+export type CreateTeam = {
+	name: string;
+};
+
+export type UpdateTeam = {
+	name?: string;
+};
+
+export interface TeamsService {
+	create(payload: CreateTeam): Promise<TeamDetail>;
+	update(id: string, payload: UpdateTeam): Promise<TeamDetail>;
+	delete(id: string): Promise<void>;
+	fetchAll(options?: FetchOptions): Promise<Team[]>;
+	fetchById(id: string, options?: FetchOptions): Promise<TeamDetail>;
+	queryAll(): QueryResult<Team[]>;
+}
+
+type Options = {
+	pollingInterval: number;
+};
+
+export class RemoteTeamsService implements TeamsService {
+	constructor(private readonly _fetcher: FetchService, private readonly _options: Options) {}
+
+	create(payload: CreateTeam): Promise<TeamDetail> {
+		return this._fetcher.post('/api/v1/teams', payload);
+	}
+
+	update(id: string, payload: UpdateTeam): Promise<TeamDetail> {
+		return this._fetcher.patch(`/api/v1/teams/${id}`, payload);
+	}
+
+	delete(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/teams/${id}`, {
+			invalidate: ['/api/v1/teams'],
+			skipUrlInvalidate: true
+		});
+	}
+
+	queryAll(): QueryResult<Team[]> {
+		return this._fetcher.query('/api/v1/teams', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	fetchAll(options?: FetchOptions): Promise<Team[]> {
+		return this._fetcher.get('/api/v1/teams', options);
+	}
+
+	fetchById(id: string, options?: FetchOptions): Promise<TeamDetail> {
+		return this._fetcher.get(`/api/v1/teams/${id}`, options);
+	}
+}
+
+const service: TeamsService = new RemoteTeamsService(fetcher, {
+	pollingInterval: POLLING_INTERVAL_MS
+});
+
+export default service;
@@ -17,9 +17,18 @@ export type UpdateProfileData = {
 	password?: string;
 };
 
+export type EmailPreferences = {
+	deployment_failures: boolean;
+	certificate_expiry: boolean;
+};
+
+export type UpdateEmailPreferencesData = Partial<EmailPreferences>;
+
 export interface UsersService {
 	update(payload: UpdateProfileData): Promise<Profile>;
 	refreshAPIKey(): Promise<Pick<Profile, 'api_key'>>;
+	fetchNotifications(): Promise<EmailPreferences>;
+	updateNotifications(payload: UpdateEmailPreferencesData): Promise<EmailPreferences>;
 }
 
 export class RemoteUsersService implements UsersService {
@@ -32,6 +41,14 @@ export class RemoteUsersService implements UsersService {
 	refreshAPIKey(): Promise<Pick<Profile, 'api_key'>> {
 		return this._fetcher.put('/api/v1/profile/key');
 	}
+
+	fetchNotifications(): Promise<EmailPreferences> {
+		return this._fetcher.get('/api/v1/profile/notifications');
+	}
+
+	updateNotifications(payload: UpdateEmailPreferencesData): Promise<EmailPreferences> {
+		return this._fetcher.patch('/api/v1/profile/notifications', payload);
+	}
 }
 
 const service: UsersService = new RemoteUsersService(fetcher);
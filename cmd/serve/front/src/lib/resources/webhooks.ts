@@ -0,0 +1,117 @@
+import { POLLING_INTERVAL_MS } from '$lib/config';
+import fetcher, { type FetchOptions, type FetchService, type QueryResult } from '$lib/fetcher';
+import type { Paginated } from '$lib/pagination';
+import type { ByUserData } from '$lib/resources/users';
+
+export type WebhookEvent =
+	| 'deployment_started'
+	| 'deployment_succeeded'
+	| 'deployment_failed'
+	| 'target_unreachable'
+	| 'cleanup_completed'
+	| 'container_incident'
+	| 'app_down'
+	| 'app_up';
+
+export type Webhook = {
+	id: string;
+	name: string;
+	url: string;
+	events: WebhookEvent[];
+	created_at: string;
+	created_by: ByUserData;
+};
+
+export enum DeliveryStatus {
+	Pending = 0,
+	Succeeded = 1,
+	Failed = 2
+}
+
+export type Delivery = {
+	id: string;
+	event: WebhookEvent;
+	payload: string;
+	status: DeliveryStatus;
+	attempts: number;
+	status_code?: number;
+	error_code?: string;
+	created_at: string;
+	last_attempt_at?: string;
+};
+
+export type CreateWebhook = {
+	name: string;
+	url: string;
+	secret: string;
+	events: WebhookEvent[];
+};
+
+export type UpdateWebhook = {
+	name?: string;
+	url?: string;
+	secret?: string;
+	events?: WebhookEvent[];
+};
+
+export interface WebhooksService {
+	create(payload: CreateWebhook): Promise<Webhook>;
+	update(id: string, payload: UpdateWebhook): Promise<Webhook>;
+	delete(id: string): Promise<void>;
+	fetchAll(options?: FetchOptions): Promise<Webhook[]>;
+	fetchById(id: string, options?: FetchOptions): Promise<Webhook>;
+	queryAll(): QueryResult<Webhook[]>;
+	queryDeliveries(id: string, page: number): QueryResult<Paginated<Delivery>>;
+}
+
+type Options = {
+	pollingInterval: number;
+};
+
+export class RemoteWebhooksService implements WebhooksService {
+	constructor(private readonly _fetcher: FetchService, private readonly _options: Options) {}
+
+	create(payload: CreateWebhook): Promise<Webhook> {
+		return this._fetcher.post('/api/v1/webhooks', payload);
+	}
+
+	update(id: string, payload: UpdateWebhook): Promise<Webhook> {
+		return this._fetcher.patch(`/api/v1/webhooks/${id}`, payload);
+	}
+
+	delete(id: string): Promise<void> {
+		return this._fetcher.delete(`/api/v1/webhooks/${id}`, {
+			invalidate: ['/api/v1/webhooks'],
+			skipUrlInvalidate: true
+		});
+	}
+
+	queryAll(): QueryResult<Webhook[]> {
+		return this._fetcher.query('/api/v1/webhooks', {
+			refreshInterval: this._options.pollingInterval
+		});
+	}
+
+	queryDeliveries(id: string, page: number): QueryResult<Paginated<Delivery>> {
+		return this._fetcher.query(`/api/v1/webhooks/${id}/deliveries`, {
+			refreshInterval: this._options.pollingInterval,
+			params: {
+				page
+			}
+		});
+	}
+
+	fetchAll(options?: FetchOptions): Promise<Webhook[]> {
+		return this._fetcher.get('/api/v1/webhooks', options);
+	}
+
+	fetchById(id: string, options?: FetchOptions): Promise<Webhook> {
+		return this._fetcher.get(`/api/v1/webhooks/${id}`, options);
+	}
+}
+
+const service: WebhooksService = new RemoteWebhooksService(fetcher, {
+	pollingInterval: POLLING_INTERVAL_MS
+});
+
+export default service;
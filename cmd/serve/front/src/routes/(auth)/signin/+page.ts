@@ -0,0 +1,10 @@
+import service from '$lib/resources/sessions';
+
+export const load = async ({ fetch, url }) => {
+	const methods = await service.getMethods({ fetch });
+
+	return {
+		methods,
+		error: url.searchParams.get('error')
+	};
+};
@@ -0,0 +1,26 @@
+// Generates the OpenAPI document embedded in the server from the documented routes.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/YuukanOO/seelf/cmd/serve"
+)
+
+const output = "openapi.json"
+
+func main() {
+	data, err := json.MarshalIndent(serve.OpenAPI(), "", "  ")
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err = os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_gitops_runs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_gitops"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type listGitOpsRunsFilters struct {
+	Page int `form:"page"`
+}
+
+func (s *server) listGitOpsRunsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request listGitOpsRunsFilters) error {
+		var query get_gitops_runs.Query
+
+		if request.Page != 0 {
+			query.Page.Set(request.Page)
+		}
+
+		runs, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, runs)
+	})
+}
+
+func (s *server) syncGitOpsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), sync_gitops.Command{
+			Force: true,
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
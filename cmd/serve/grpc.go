@@ -0,0 +1,504 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/cmd/serve/seelfpb"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/git"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	httputils "github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Methods rejected while the instance is in maintenance mode.
+var grpcMutations = map[string]bool{
+	seelfpb.Seelf_QueueDeployment_FullMethodName: true,
+	seelfpb.Seelf_Redeploy_FullMethodName:        true,
+}
+
+// gRPC implementation of the API. It dispatches the same commands and queries as the
+// REST handlers and only accepts API keys and tokens.
+type grpcServer struct {
+	seelfpb.UnimplementedSeelfServer
+
+	s *server
+}
+
+func newGrpcServer(s *server) *grpc.Server {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(s.grpcInterceptor))
+
+	seelfpb.RegisterSeelfServer(srv, &grpcServer{s: s})
+
+	return srv
+}
+
+// Correlate and authenticate incoming calls using their metadata, then translate
+// errors returned by the handler to gRPC ones.
+func (s *server) grpcInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	correlationID := firstMetadata(md, correlationIDHeader)
+
+	if correlationID == "" || len(correlationID) > maxCorrelationIDLen {
+		correlationID = id.New[string]()
+	}
+
+	ctx = bus.WithCorrelationID(ctx, correlationID)
+	grpc.SetHeader(ctx, metadata.Pairs(correlationIDHeader, correlationID))
+
+	var (
+		key           string
+		authCtx       context.Context
+		authErr       error
+		authenticated bool
+	)
+
+	if authHeader := firstMetadata(md, apiAuthHeader); strings.HasPrefix(authHeader, apiAuthPrefix) {
+		key = authHeader[apiAuthPrefixLength:]
+	}
+
+	// Authenticate at most once, even if the rate limiter already needed to
+	authenticate := func() error {
+		if !authenticated {
+			authCtx, authErr = s.authenticateKey(ctx, key, info.FullMethod)
+			authenticated = true
+		}
+
+		return authErr
+	}
+
+	if s.limiter != nil {
+		if allowed, retryAfter := s.allowRequest(grpcClientIP(ctx), key, authenticate); !allowed {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", httputils.RetryAfterSeconds(retryAfter)))
+			return nil, status.Error(codes.ResourceExhausted, httputils.ErrTooManyRequests.Error())
+		}
+	}
+
+	if key == "" {
+		return nil, status.Error(codes.Unauthenticated, errUnauthorized.Error())
+	}
+
+	if err := authenticate(); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = authCtx
+
+	if grpcMutations[info.FullMethod] && s.maintenance.Maintenance().Enabled {
+		return nil, status.Error(codes.Unavailable, errMaintenance.Error())
+	}
+
+	resp, err := handler(ctx, req)
+
+	if err != nil {
+		return nil, s.grpcError(ctx, info.FullMethod, err)
+	}
+
+	return resp, nil
+}
+
+// Translates the error to a gRPC status the same way HTTP handlers set the response status.
+func (s *server) grpcError(ctx context.Context, method string, err error) error {
+	var result error
+
+	monad.Fail[any](err).MatchErr(
+		func(err error) {
+			result = status.Error(codes.InvalidArgument, err.Error())
+		},
+		func(err error) {
+			switch {
+			case errors.Is(err, apperr.ErrNotFound):
+				result = status.Error(codes.NotFound, err.Error())
+			case errors.Is(err, apperr.ErrForbidden):
+				result = status.Error(codes.PermissionDenied, err.Error())
+			case errors.Is(err, storage.ErrConcurrentModification):
+				result = status.Error(codes.Aborted, err.Error())
+			default:
+				result = status.Error(codes.FailedPrecondition, err.Error())
+			}
+		},
+		func(err error) {
+			s.logger.Errorw(err.Error(),
+				"method", method,
+				"correlation_id", bus.CorrelationID(ctx).Get(""),
+				"error", err)
+
+			result = status.Error(codes.Internal, "unexpected_error")
+		},
+	)
+
+	return result
+}
+
+func (g *grpcServer) ListApps(ctx context.Context, req *seelfpb.ListAppsRequest) (*seelfpb.ListAppsResponse, error) {
+	query := get_apps.Query{}
+
+	if req.TeamId != "" {
+		query.TeamID.Set(req.TeamId)
+	}
+
+	apps, err := bus.Send(g.s.bus, ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &seelfpb.ListAppsResponse{
+		Apps: make([]*seelfpb.App, len(apps)),
+	}
+
+	for i, a := range apps {
+		resp.Apps[i] = &seelfpb.App{
+			Id:                         a.ID,
+			Name:                       a.Name,
+			ProductionTarget:           targetSummaryToProto(a.ProductionTarget),
+			StagingTarget:              targetSummaryToProto(a.StagingTarget),
+			LatestProductionDeployment: monad.Map(a.LatestDeployments.Production, deploymentSummaryToProto).Get(nil),
+			LatestStagingDeployment:    monad.Map(a.LatestDeployments.Staging, deploymentSummaryToProto).Get(nil),
+			CreatedAt:                  timestamppb.New(a.CreatedAt),
+		}
+	}
+
+	return resp, nil
+}
+
+func (g *grpcServer) GetApp(ctx context.Context, req *seelfpb.GetAppRequest) (*seelfpb.App, error) {
+	a, err := bus.Send(g.s.bus, ctx, get_app_detail.Query{
+		ID: req.Id,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &seelfpb.App{
+		Id:                         a.ID,
+		Name:                       a.Name,
+		ProductionTarget:           targetSummaryToProto(a.Production.Target),
+		StagingTarget:              targetSummaryToProto(a.Staging.Target),
+		LatestProductionDeployment: monad.Map(a.LatestDeployments.Production, deploymentToProto).Get(nil),
+		LatestStagingDeployment:    monad.Map(a.LatestDeployments.Staging, deploymentToProto).Get(nil),
+		CreatedAt:                  timestamppb.New(a.CreatedAt),
+	}, nil
+}
+
+func (g *grpcServer) ListDeployments(ctx context.Context, req *seelfpb.ListDeploymentsRequest) (*seelfpb.ListDeploymentsResponse, error) {
+	query := get_app_deployments.Query{
+		AppID: req.AppId,
+	}
+
+	if req.Page != 0 {
+		query.Page.Set(int(req.Page))
+	}
+
+	if req.Environment != "" {
+		query.Environment.Set(req.Environment)
+	}
+
+	page, err := bus.Send(g.s.bus, ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &seelfpb.ListDeploymentsResponse{
+		Deployments: make([]*seelfpb.Deployment, len(page.Data)),
+		Page:        int32(page.Page),
+		Total:       int32(page.Total),
+		LastPage:    page.IsLastPage,
+	}
+
+	for i, d := range page.Data {
+		resp.Deployments[i] = deploymentSummaryToProto(d)
+	}
+
+	return resp, nil
+}
+
+func (g *grpcServer) GetDeployment(ctx context.Context, req *seelfpb.GetDeploymentRequest) (*seelfpb.Deployment, error) {
+	return g.getDeployment(ctx, req.AppId, int(req.DeploymentNumber))
+}
+
+func (g *grpcServer) QueueDeployment(ctx context.Context, req *seelfpb.QueueDeploymentRequest) (*seelfpb.Deployment, error) {
+	cmd := queue_deployment.Command{
+		AppID:       req.AppId,
+		Environment: req.Environment,
+	}
+
+	switch source := req.Source.(type) {
+	case *seelfpb.QueueDeploymentRequest_Raw:
+		cmd.Source = source.Raw
+	case *seelfpb.QueueDeploymentRequest_Git:
+		body := git.Body{
+			Branch: source.Git.GetBranch(),
+		}
+
+		if hash := source.Git.GetHash(); hash != "" {
+			body.Hash.Set(hash)
+		}
+
+		cmd.Source = body
+	}
+
+	number, err := bus.Send(g.s.bus, ctx, cmd)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.getDeployment(ctx, req.AppId, number)
+}
+
+func (g *grpcServer) Redeploy(ctx context.Context, req *seelfpb.RedeployRequest) (*seelfpb.Deployment, error) {
+	number, err := bus.Send(g.s.bus, ctx, redeploy.Command{
+		AppID:            req.AppId,
+		DeploymentNumber: int(req.DeploymentNumber),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.getDeployment(ctx, req.AppId, number)
+}
+
+func (g *grpcServer) ListTargets(ctx context.Context, req *seelfpb.ListTargetsRequest) (*seelfpb.ListTargetsResponse, error) {
+	targets, err := bus.Send(g.s.bus, ctx, get_targets.Query{
+		ActiveOnly: req.ActiveOnly,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &seelfpb.ListTargetsResponse{
+		Targets: make([]*seelfpb.Target, len(targets)),
+	}
+
+	for i, t := range targets {
+		resp.Targets[i] = targetToProto(t)
+	}
+
+	return resp, nil
+}
+
+func (g *grpcServer) GetTarget(ctx context.Context, req *seelfpb.GetTargetRequest) (*seelfpb.Target, error) {
+	target, err := bus.Send(g.s.bus, ctx, get_target.Query{
+		ID: req.Id,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return targetToProto(target), nil
+}
+
+func (g *grpcServer) ListJobs(ctx context.Context, req *seelfpb.ListJobsRequest) (*seelfpb.ListJobsResponse, error) {
+	if !domain.HasAdminRights(ctx) {
+		return nil, apperr.ErrForbidden
+	}
+
+	var filters bus.GetJobsFilters
+
+	if req.Page != 0 {
+		filters.Page.Set(int(req.Page))
+	}
+
+	page, err := g.s.scheduledJobsStore.GetAllJobs(ctx, filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &seelfpb.ListJobsResponse{
+		Jobs:     make([]*seelfpb.Job, len(page.Data)),
+		Page:     int32(page.Page),
+		Total:    int32(page.Total),
+		LastPage: page.IsLastPage,
+	}
+
+	for i, j := range page.Data {
+		if resp.Jobs[i], err = jobToProto(j); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (g *grpcServer) getDeployment(ctx context.Context, appID string, number int) (*seelfpb.Deployment, error) {
+	deployment, err := bus.Send(g.s.bus, ctx, get_deployment.Query{
+		AppID:            appID,
+		DeploymentNumber: number,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deploymentToProto(deployment), nil
+}
+
+func deploymentToProto(d get_deployment.Deployment) *seelfpb.Deployment {
+	return &seelfpb.Deployment{
+		AppId:            d.AppID,
+		DeploymentNumber: int32(d.DeploymentNumber),
+		Environment:      d.Environment,
+		Target: &seelfpb.TargetSummary{
+			Id:   d.Target.ID,
+			Name: d.Target.Name.Get(""),
+			Url:  d.Target.Url.Get(""),
+		},
+		State: &seelfpb.DeploymentState{
+			Status:     seelfpb.DeploymentStatus(d.State.Status),
+			ErrorCode:  d.State.ErrCode.Get(""),
+			StartedAt:  timestampToProto(d.State.StartedAt),
+			FinishedAt: timestampToProto(d.State.FinishedAt),
+		},
+		RequestedAt: timestamppb.New(d.RequestedAt),
+		RequestedBy: d.RequestedBy.Email,
+	}
+}
+
+func deploymentSummaryToProto(d get_app_deployments.Deployment) *seelfpb.Deployment {
+	return &seelfpb.Deployment{
+		AppId:            d.AppID,
+		DeploymentNumber: int32(d.DeploymentNumber),
+		Environment:      d.Environment,
+		Target: &seelfpb.TargetSummary{
+			Id:   d.Target.ID,
+			Name: d.Target.Name.Get(""),
+			Url:  d.Target.Url.Get(""),
+		},
+		State: &seelfpb.DeploymentState{
+			Status:     seelfpb.DeploymentStatus(d.State.Status),
+			ErrorCode:  d.State.ErrCode.Get(""),
+			StartedAt:  timestampToProto(d.State.StartedAt),
+			FinishedAt: timestampToProto(d.State.FinishedAt),
+		},
+		RequestedAt: timestamppb.New(d.RequestedAt),
+		RequestedBy: d.RequestedBy.Email,
+	}
+}
+
+func targetSummaryToProto(t app.TargetSummary) *seelfpb.TargetSummary {
+	return &seelfpb.TargetSummary{
+		Id:   t.ID,
+		Name: t.Name,
+		Url:  t.Url,
+	}
+}
+
+func targetToProto(t get_target.Target) *seelfpb.Target {
+	return &seelfpb.Target{
+		Id:       t.ID,
+		Name:     t.Name,
+		Url:      t.Url,
+		Provider: t.Provider.Kind,
+		State: &seelfpb.TargetState{
+			Status:           seelfpb.TargetStatus(t.State.Status),
+			ErrorCode:        t.State.ErrCode.Get(""),
+			LastReadyVersion: timestampToProto(t.State.LastReadyVersion),
+		},
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+}
+
+// Scheduled jobs only expose their details when serialized (that's what the REST
+// API returns) so go through their JSON representation.
+func jobToProto(j bus.ScheduledJob) (*seelfpb.Job, error) {
+	var data struct {
+		ID          string              `json:"id"`
+		ResourceID  string              `json:"resource_id"`
+		Group       string              `json:"group"`
+		MessageName string              `json:"message_name"`
+		QueuedAt    time.Time           `json:"queued_at"`
+		NotBefore   time.Time           `json:"not_before"`
+		ErrorCode   monad.Maybe[string] `json:"error_code"`
+		Retrieved   bool                `json:"retrieved"`
+		Correlation monad.Maybe[string] `json:"correlation_id"`
+	}
+
+	b, err := json.Marshal(j)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return &seelfpb.Job{
+		Id:            data.ID,
+		ResourceId:    data.ResourceID,
+		Group:         data.Group,
+		MessageName:   data.MessageName,
+		QueuedAt:      timestamppb.New(data.QueuedAt),
+		NotBefore:     timestamppb.New(data.NotBefore),
+		ErrorCode:     data.ErrorCode.Get(""),
+		Retrieved:     data.Retrieved,
+		CorrelationId: data.Correlation.Get(""),
+	}, nil
+}
+
+func timestampToProto(t monad.Maybe[time.Time]) *timestamppb.Timestamp {
+	return monad.Map(t, timestamppb.New).Get(nil)
+}
+
+// Retrieve the IP address of the client which made the call.
+func grpcClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	values := md.Get(key)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
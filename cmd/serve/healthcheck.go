@@ -1,6 +1,8 @@
 package serve
 
 import (
+	nethttp "net/http"
+
 	"github.com/YuukanOO/seelf/cmd/version"
 	"github.com/YuukanOO/seelf/pkg/http"
 	"github.com/gin-gonic/gin"
@@ -15,3 +17,24 @@ func (s *server) healthcheckHandler(ctx *gin.Context) {
 		Version: version.Current(),
 	})
 }
+
+// Liveness probe, the server is alive as long as it could answer.
+func (s *server) livenessHandler(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(nethttp.StatusOK, healthCheckResponse{
+		Version: version.Current(),
+	})
+}
+
+// Readiness probe, the server is ready only if every dependency checks has succeeded.
+func (s *server) readinessHandler(ctx *gin.Context) {
+	report := s.health.Run(ctx.Request.Context())
+	status := nethttp.StatusOK
+
+	if !report.Healthy {
+		status = nethttp.StatusServiceUnavailable
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(status, report)
+}
@@ -1,14 +1,24 @@
 package serve
 
 import (
+	"context"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
 	"github.com/gin-gonic/gin"
 )
 
-type listJobsFilters struct {
-	Page int `form:"page"`
-}
+type (
+	listJobsFilters struct {
+		Page int `form:"page"`
+	}
+
+	// Diagnose the background jobs worker.
+	workerDiagnostics interface {
+		WorkerStatus(context.Context) (startup.WorkerStatus, error)
+	}
+)
 
 func (s *server) listJobsHandler() gin.HandlerFunc {
 	return http.Bind(s, func(ctx *gin.Context, request listJobsFilters) error {
@@ -39,3 +49,15 @@ func (s *server) deleteJobsHandler() gin.HandlerFunc {
 		return http.NoContent(ctx)
 	})
 }
+
+func (s *server) getWorkerStatusHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		status, err := s.worker.WorkerStatus(ctx.Request.Context())
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, status)
+	})
+}
@@ -0,0 +1,31 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// Put the instance in maintenance mode or take it out of it.
+	maintenanceMode interface {
+		Maintenance() startup.MaintenanceStatus
+		SetMaintenance(bool) startup.MaintenanceStatus
+	}
+
+	updateMaintenanceRequest struct {
+		Enabled bool `json:"enabled"`
+	}
+)
+
+func (s *server) getMaintenanceHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		return http.Ok(ctx, s.maintenance.Maintenance())
+	})
+}
+
+func (s *server) updateMaintenanceHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request updateMaintenanceRequest) error {
+		return http.Ok(ctx, s.maintenance.SetMaintenance(request.Enabled))
+	})
+}
@@ -1,44 +1,84 @@
 package serve
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/YuukanOO/seelf/internal/auth/app/use_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/use_token"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
 	httputils "github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/telemetry"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	userSessionKey      = "seelf-session"
+	sessionIDKey        = "seelf-session"
 	apiAuthHeader       = "Authorization"
 	apiAuthPrefix       = "Bearer "
 	apiAuthPrefixLength = len(apiAuthPrefix)
+	apiAuthQueryParam   = "token"
+	apiAuthResultKey    = "seelf-api-auth"
+	correlationIDHeader = "X-Request-ID"
+	maxCorrelationIDLen = 128
 )
 
-var errUnauthorized = errors.New("unauthorized")
+var (
+	errUnauthorized = errors.New("unauthorized")
+	errMaintenance  = apperr.New("maintenance")
+
+	// Mutations still allowed in maintenance mode so admins could sign in and leave it.
+	maintenanceAllowedRoutes = map[string]bool{
+		http.MethodPost + " /api/v1/sessions":      true,
+		http.MethodDelete + " /api/v1/session":     true,
+		http.MethodPut + " /api/v1/maintenance":    true,
+		http.MethodPost + " /api/v1/config/reload": true,
+	}
+	// Safe methods routes which may still change something and are rejected in maintenance mode.
+	maintenanceMutatingRoutes = map[string]bool{
+		http.MethodGet + " /api/v1/apps/:id/exec": true, // Commands run in a service container through a websocket
+	}
+	requestDuration = telemetry.DurationHistogram("http.server.duration", "Duration of HTTP requests")
+)
 
 func (s *server) authenticate(withApiAccess bool) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// First, try to find a user id in the encrypted session cookie
+		// First, try to find a session id in the encrypted session cookie and check it is still active
 		sess := sessions.Default(ctx)
-		uid, ok := sess.Get(userSessionKey).(string)
-		failed := !ok || uid == ""
+		sid, ok := sess.Get(sessionIDKey).(string)
 
-		// If it failed and api access is not allowed, return early
-		if failed && !withApiAccess {
-			ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
-			return
+		if ok && sid != "" {
+			uid, err := bus.Send(s.bus, ctx.Request.Context(), use_session.Command{
+				ID: sid,
+				IP: ctx.ClientIP(),
+			})
+
+			// The session has been found, go on
+			if err == nil {
+				s.authenticateAs(ctx, uid)
+				return
+			}
 		}
 
-		// Else, if we do not fail, the user id has been found, go on
-		if !failed {
-			ctx.Request = ctx.Request.WithContext(domain.WithUserID(ctx.Request.Context(), domain.UserID(uid)))
-			ctx.Next()
+		// If it failed and api access is not allowed, return early
+		if !withApiAccess {
+			ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
 			return
 		}
 
@@ -50,25 +90,222 @@ func (s *server) authenticate(withApiAccess bool) gin.HandlerFunc {
 			return
 		}
 
-		id, err := s.usersReader.GetIDFromAPIKey(ctx.Request.Context(), domain.APIKey(authHeader[apiAuthPrefixLength:]))
+		authCtx, err := s.authenticateRequestKey(ctx, authHeader[apiAuthPrefixLength:])
 
 		if err != nil {
-			ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
+			ctx.AbortWithError(http.StatusUnauthorized, err)
 			return
 		}
 
-		// Attach the user id to the context passed down in every usecases.
-		ctx.Request = ctx.Request.WithContext(domain.WithUserID(ctx.Request.Context(), id))
+		ctx.Request = ctx.Request.WithContext(authCtx)
 
 		ctx.Next()
 	}
 }
 
+// Feed readers and calendar clients could not set the authorization header, so let them
+// give the api key or token in the query string instead. Must be used before authenticate.
+func (s *server) allowApiKeyInQuery(ctx *gin.Context) {
+	if key := ctx.Query(apiAuthQueryParam); key != "" && ctx.GetHeader(apiAuthHeader) == "" {
+		ctx.Request.Header.Set(apiAuthHeader, apiAuthPrefix+key)
+	}
+
+	ctx.Next()
+}
+
+// Idempotency keys belong to the authenticated user so they could not collide between users.
+func idempotencyScope(ctx *gin.Context) string {
+	return string(domain.CurrentUser(ctx.Request.Context()).Get(""))
+}
+
+// Load the given user and attach it to the context passed down in every usecases. Since
+// users could be disabled or deleted at any time, it is checked on every request.
+func (s *server) authenticateAs(ctx *gin.Context, uid domain.UserID) {
+	authCtx, err := s.withUser(ctx.Request.Context(), uid)
+
+	if err != nil {
+		ctx.AbortWithError(http.StatusUnauthorized, err)
+		return
+	}
+
+	ctx.Request = ctx.Request.WithContext(authCtx)
+
+	ctx.Next()
+}
+
+// Authenticate the given user API key or API token, returning a context with the
+// user, and the token scopes if any, attached to it. The endpoint is recorded in the
+// token usage statistics.
+func (s *server) authenticateKey(ctx context.Context, key, endpoint string) (context.Context, error) {
+	id, err := s.usersReader.GetIDFromAPIKey(ctx, domain.APIKey(key))
+
+	if err == nil {
+		return s.withUser(ctx, id)
+	}
+
+	// Not a user API key, it may be a scoped API token
+	token, err := bus.Send(s.bus, ctx, use_token.Command{
+		Token:    key,
+		Endpoint: endpoint,
+	})
+
+	if err != nil {
+		return ctx, errUnauthorized
+	}
+
+	return s.withUser(domain.WithScopes(ctx, token.Scopes), token.UserID)
+}
+
+type apiAuthResult struct {
+	ctx context.Context
+	err error
+}
+
+// Same as authenticateKey but the result is kept on the request so the key is only
+// checked, and its usage recorded, once even if the rate limiter already looked at it.
+func (s *server) authenticateRequestKey(ctx *gin.Context, key string) (context.Context, error) {
+	if result, found := ctx.Get(apiAuthResultKey); found {
+		r := result.(apiAuthResult)
+		return r.ctx, r.err
+	}
+
+	authCtx, err := s.authenticateKey(ctx.Request.Context(), key, ctx.Request.Method+" "+ctx.FullPath())
+	ctx.Set(apiAuthResultKey, apiAuthResult{authCtx, err})
+
+	return authCtx, err
+}
+
+func (s *server) withUser(ctx context.Context, uid domain.UserID) (context.Context, error) {
+	user, err := s.usersReader.GetByID(ctx, uid)
+
+	if err != nil || user.IsDisabled() {
+		return ctx, errUnauthorized
+	}
+
+	return domain.WithUser(ctx, user), nil
+}
+
+// Restrict the access to admin users.
+func (s *server) requireAdmin(ctx *gin.Context) {
+	if !domain.HasAdminRights(ctx.Request.Context()) {
+		httputils.HandleError(s, ctx, apperr.ErrForbidden)
+		return
+	}
+
+	ctx.Next()
+}
+
+// Attach a correlation ID to the request context so it can be traced through the bus and
+// scheduled jobs. An ID given by a reverse proxy is reused, else a new one is generated.
+func (s *server) correlate(ctx *gin.Context) {
+	correlationID := ctx.GetHeader(correlationIDHeader)
+
+	if correlationID == "" || len(correlationID) > maxCorrelationIDLen {
+		correlationID = id.New[string]()
+	}
+
+	ctx.Request = ctx.Request.WithContext(bus.WithCorrelationID(ctx.Request.Context(), correlationID))
+	ctx.Header(correlationIDHeader, correlationID)
+
+	ctx.Next()
+}
+
+// Start a span for every request and record its duration. The route template is used
+// instead of the raw path to keep a low cardinality.
+func (s *server) instrument(ctx *gin.Context) {
+	start := time.Now()
+	route := ctx.FullPath()
+
+	if route == "" {
+		route = "unmatched"
+	}
+
+	reqCtx := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+	reqCtx, span := telemetry.Tracer().Start(reqCtx, ctx.Request.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.method", ctx.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("correlation_id", bus.CorrelationID(ctx.Request.Context()).Get("")),
+		))
+
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	span.SetAttributes(attribute.Int("http.status_code", status))
+
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+
+	span.End()
+
+	requestDuration.Record(reqCtx, telemetry.Since(start), metric.WithAttributes(
+		attribute.String("http.method", ctx.Request.Method),
+		attribute.String("http.route", route),
+		attribute.Int("http.status_code", status),
+	))
+}
+
+// Rate limit API requests, see allowRequest for how clients are identified.
+func (s *server) rateLimit(ctx *gin.Context) {
+	var key string
+
+	if authHeader := ctx.GetHeader(apiAuthHeader); strings.HasPrefix(authHeader, apiAuthPrefix) {
+		key = authHeader[apiAuthPrefixLength:]
+	}
+
+	allowed, retryAfter := s.allowRequest(ctx.ClientIP(), key, func() error {
+		_, err := s.authenticateRequestKey(ctx, key)
+		return err
+	})
+
+	if !allowed {
+		httputils.TooManyRequests(ctx, retryAfter)
+		return
+	}
+
+	ctx.Next()
+}
+
+// Clients giving an API key or token are rate limited on their own, other ones by IP.
+// The key bucket is checked before authenticating so rate limited clients do not hit
+// the database nor record token usages. Keys failing to authenticate also consume the
+// IP bucket, else random keys would bypass the IP limit, and an IP already rate limited
+// is rejected before hitting the database. Keys are hashed so they are not kept in memory.
+func (s *server) allowRequest(ip, key string, authenticate func() error) (bool, time.Duration) {
+	ipKey := "ip:" + ip
+
+	if key == "" {
+		return s.limiter.Allow(ipKey)
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	if allowed, retryAfter := s.limiter.Allow("key:" + hex.EncodeToString(sum[:])); !allowed {
+		return false, retryAfter
+	}
+
+	if s.limiter.Exhausted(ipKey) || authenticate() != nil {
+		return s.limiter.Allow(ipKey)
+	}
+
+	return true, 0
+}
+
 func (s *server) requestLogger(ctx *gin.Context) {
 	defer func(start time.Time, c *gin.Context) {
 		path := ctx.Request.URL.Path
 		raw := ctx.Request.URL.RawQuery
 
+		// Api keys and badge tokens could be given in the query string, do not leak them
+		if query := ctx.Request.URL.Query(); query.Has(apiAuthQueryParam) {
+			query.Set(apiAuthQueryParam, "redacted")
+			raw = query.Encode()
+		}
+
 		if raw != "" {
 			path = path + "?" + raw
 		}
@@ -76,12 +313,31 @@ func (s *server) requestLogger(ctx *gin.Context) {
 		s.logger.Debugw(path,
 			"status", c.Writer.Status(),
 			"method", c.Request.Method,
+			"correlation_id", bus.CorrelationID(c.Request.Context()).Get(""),
 			"elapsed", time.Since(start))
 	}(time.Now(), ctx)
 
 	ctx.Next()
 }
 
+// Reject requests which may change something while the instance is in maintenance mode.
+func (s *server) rejectMutationsInMaintenance(ctx *gin.Context) {
+	route := ctx.Request.Method + " " + ctx.FullPath()
+
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if !maintenanceMutatingRoutes[route] {
+			return
+		}
+	}
+
+	if !s.maintenance.Maintenance().Enabled || maintenanceAllowedRoutes[route] {
+		return
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, errMaintenance)
+}
+
 func (s *server) recoverer(ctx *gin.Context) {
 	defer func() {
 		if err := recover(); err != nil {
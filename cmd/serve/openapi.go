@@ -0,0 +1,319 @@
+package serve
+
+import (
+	_ "embed"
+	"mime/multipart"
+	nethttp "net/http"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_audit_entries"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_sessions"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/grant_role"
+	"github.com/YuukanOO/seelf/internal/auth/app/invite_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/login"
+	"github.com/YuukanOO/seelf/internal/auth/app/setup_account"
+	"github.com/YuukanOO/seelf/internal/auth/app/update_user"
+	"github.com/YuukanOO/seelf/internal/deployment/app/annotate_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_freeze"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_proxy_rules"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_timeouts"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_saved_filter"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_team"
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_env_vars"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_addon_backups"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_addons"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_archives"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_badge"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_env_revisions"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_traffic"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_artifacts_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log_steps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_stats"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_federated_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_gitops_runs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_saved_filters"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets_capacity"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_team"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_teams"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_trashed_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/import_env_vars"
+	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restart_services"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/scale_service"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_team"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook_deliveries"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_webhook"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/health"
+	"github.com/YuukanOO/seelf/pkg/openapi"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate go run ./gen
+
+// OpenAPI document generated from the routes below, run `go generate ./cmd/serve`
+// after updating them.
+//
+//go:embed openapi.json
+var openapiDocument []byte
+
+const (
+	apiVersion          = "1"
+	sessionSecurity     = "session"
+	apiKeySecurity      = "api_key"
+	openapiDocumentPath = "/openapi.json"
+)
+
+var (
+	public    = []openapi.SecurityRequirement{}
+	apiAccess = []openapi.SecurityRequirement{{sessionSecurity: {}}, {apiKeySecurity: {}}}
+)
+
+// Builds the OpenAPI document describing the v1 API.
+func OpenAPI() *openapi.Document {
+	doc := openapi.New("seelf", apiVersion, sessionPath).
+		SecurityScheme(sessionSecurity, openapi.SecurityScheme{
+			Type: "apiKey",
+			In:   "cookie",
+			Name: sessionName,
+		}).
+		SecurityScheme(apiKeySecurity, openapi.SecurityScheme{
+			Type:   "http",
+			Scheme: "bearer",
+		}).
+		Override(&multipart.FileHeader{}, openapi.Schema{Type: "string", Format: "binary"}).
+		Override(storage.SecretString(""), openapi.Schema{Type: "string", Format: "password"})
+
+	doc.Security = []openapi.SecurityRequirement{{sessionSecurity: {}}}
+
+	return doc.Add(
+		// Sessions
+		openapi.Route{Method: nethttp.MethodGet, Path: "/auth/methods", ID: "getAuthMethods", Summary: "Retrieve available sign in methods", Tag: "sessions", Security: public, Response: authMethods{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/auth/oidc", ID: "signInWithOIDC", Summary: "Redirect to the OpenID Connect provider, if configured", Tag: "sessions", Security: public, Status: nethttp.StatusFound},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/auth/oidc/callback", ID: "completeOIDCSignIn", Summary: "Complete the OpenID Connect sign in, if configured", Tag: "sessions", Security: public, Status: nethttp.StatusFound},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/sessions", ID: "createSession", Summary: "Sign in with an email and password", Tag: "sessions", Security: public, Body: login.Command{}, Response: get_profile.Profile{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/session", ID: "deleteSession", Summary: "Sign out", Tag: "sessions"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/sessions", ID: "listSessions", Summary: "List active sessions of the current user", Tag: "sessions", Response: []get_sessions.Session{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/sessions/:id", ID: "revokeSession", Summary: "Revoke a session", Tag: "sessions"},
+
+		// First-run setup
+		openapi.Route{Method: nethttp.MethodGet, Path: "/setup", ID: "getSetup", Summary: "Check if the first-run setup should be completed", Tag: "setup", Security: public, Response: setupStatus{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/setup/account", ID: "setupAccount", Summary: "Create the administrator account and sign in, only while no account exists", Tag: "setup", Security: public, Body: setup_account.Command{}, Response: get_profile.Profile{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/setup/checks", ID: "runSetupChecks", Summary: "Check the DNS records and the proxy needed to expose apps on the given url", Tag: "setup", Body: setupUrlRequest{}, Response: health.Report{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/setup/target", ID: "setupTarget", Summary: "Create the default target on the local docker daemon", Tag: "setup", Body: setupUrlRequest{}, Response: get_target.Target{}, Status: nethttp.StatusCreated},
+
+		// Profile & users
+		openapi.Route{Method: nethttp.MethodGet, Path: "/profile", ID: "getProfile", Summary: "Retrieve the current user profile", Tag: "users", Response: get_profile.Profile{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/profile", ID: "updateProfile", Summary: "Update the current user profile", Tag: "users", Body: update_user.Command{}, Response: get_profile.Profile{}},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/profile/key", ID: "refreshProfileKey", Summary: "Generate a new API key for the current user", Tag: "users", Response: refreshProfileKeyResult{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/profile/notifications", ID: "getProfileNotifications", Summary: "Retrieve which emails the current user receives", Tag: "users", Response: get_email_preferences.EmailPreferences{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/profile/notifications", ID: "updateProfileNotifications", Summary: "Update which emails the current user receives", Tag: "users", Body: update_email_preferences.Command{}, Response: get_email_preferences.EmailPreferences{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/users", ID: "listUsers", Summary: "List users", Tag: "users", Response: []get_user.User{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/users", ID: "inviteUser", Summary: "Invite a new user", Tag: "users", Body: invite_user.Command{}, Response: get_user.User{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/users/:id", ID: "getUser", Summary: "Retrieve a user", Tag: "users", Response: get_user.User{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/users/:id", ID: "deleteUser", Summary: "Delete a user", Tag: "users"},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/users/:id/disable", ID: "disableUser", Summary: "Disable a user", Tag: "users"},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/users/:id/enable", ID: "enableUser", Summary: "Enable a user", Tag: "users"},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/users/:id/grants", ID: "grantRole", Summary: "Grant a role on a resource to a user", Tag: "users", Body: grant_role.Command{}, Response: get_user.User{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/users/:id/grants/:type/:resource", ID: "revokeRole", Summary: "Revoke the role granted to a user on a resource", Tag: "users"},
+
+		// Tokens
+		openapi.Route{Method: nethttp.MethodGet, Path: "/tokens", ID: "listTokens", Summary: "List API tokens of the current user", Tag: "tokens", Response: []get_token.Token{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/tokens", ID: "createToken", Summary: "Create an API token", Tag: "tokens", Body: create_token.Command{}, Response: createTokenResult{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/tokens/:id", ID: "getToken", Summary: "Retrieve an API token", Tag: "tokens", Response: get_token.Token{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/tokens/:id", ID: "revokeToken", Summary: "Revoke an API token", Tag: "tokens"},
+
+		// Targets
+		openapi.Route{Method: nethttp.MethodGet, Path: "/targets", ID: "listTargets", Summary: "List targets", Tag: "targets", Security: apiAccess, Query: get_targets.Query{}, Response: []get_target.Target{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/targets/capacity", ID: "getTargetsCapacity", Summary: "Retrieve the capacity of active targets, the ones with the most room to place a new app first", Tag: "targets", Security: apiAccess, Response: []get_targets_capacity.Target{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/targets", ID: "createTarget", Summary: "Create a target", Tag: "targets", Security: apiAccess, Body: createTargetBody{}, Response: get_target.Target{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/targets/:id", ID: "getTarget", Summary: "Retrieve a target", Tag: "targets", Security: apiAccess, Response: get_target.Target{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/targets/:id", ID: "updateTarget", Summary: "Update a target", Tag: "targets", Security: apiAccess, Body: updateTargetBody{}, Response: get_target.Target{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/targets/:id", ID: "deleteTarget", Summary: "Request a target cleanup and deletion", Tag: "targets", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/targets/:id/reconfigure", ID: "reconfigureTarget", Summary: "Reconfigure a target", Tag: "targets"},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/targets/:id/proxy/upgrade", ID: "upgradeTargetProxy", Summary: "Upgrade the proxy of a target", Tag: "targets"},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/targets/:id/redeploy", ID: "redeployTarget", Summary: "Redeploy every app on a target, optionally limited to apps having every given label", Tag: "targets", Security: apiAccess, Query: redeployTargetFilters{}, Response: []redeploy_target.Deployment{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/targets/:id/stats", ID: "getTargetDeploymentStats", Summary: "Compute metrics of deployments made on a target over a period", Tag: "targets", Security: apiAccess, Query: getDeploymentStatsFilters{}, Response: get_deployment_stats.Stats{}},
+
+		// Registries
+		openapi.Route{Method: nethttp.MethodGet, Path: "/registries", ID: "listRegistries", Summary: "List registries", Tag: "registries", Response: []get_registry.Registry{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/registries", ID: "createRegistry", Summary: "Create a registry", Tag: "registries", Body: create_registry.Command{}, Response: get_registry.Registry{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/registries/:id", ID: "getRegistry", Summary: "Retrieve a registry", Tag: "registries", Response: get_registry.Registry{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/registries/:id", ID: "updateRegistry", Summary: "Update a registry", Tag: "registries", Body: update_registry.Command{}, Response: get_registry.Registry{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/registries/:id", ID: "deleteRegistry", Summary: "Delete a registry", Tag: "registries"},
+
+		// Federation
+		openapi.Route{Method: nethttp.MethodGet, Path: "/peers", ID: "listPeers", Summary: "List other seelf instances registered as peers", Tag: "federation", Response: []get_peer.Peer{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/peers", ID: "createPeer", Summary: "Register a peer instance reached with an API token of one of its users", Tag: "federation", Body: create_peer.Command{}, Response: get_peer.Peer{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/peers/:id", ID: "getPeer", Summary: "Retrieve a peer", Tag: "federation", Response: get_peer.Peer{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/peers/:id", ID: "updatePeer", Summary: "Update a peer", Tag: "federation", Body: update_peer.Command{}, Response: get_peer.Peer{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/peers/:id", ID: "deletePeer", Summary: "Delete a peer", Tag: "federation"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/federation/apps", ID: "listFederatedApps", Summary: "List apps of this instance and every peer, optionally limited to apps having every given label", Tag: "federation", Query: listFederatedAppsFilters{}, Response: get_federated_apps.Result{}},
+
+		// DNS
+		openapi.Route{Method: nethttp.MethodGet, Path: "/dns/zones", ID: "listDnsZones", Summary: "List DNS zones with records managed for apps exposed under them", Tag: "dns", Response: []get_dns_zone.Zone{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/dns/zones", ID: "createDnsZone", Summary: "Register a DNS zone hosted by Cloudflare, deSEC or Route53 to manage records of apps", Tag: "dns", Body: create_dns_zone.Command{}, Response: get_dns_zone.Zone{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/dns/zones/:id", ID: "getDnsZone", Summary: "Retrieve a DNS zone", Tag: "dns", Response: get_dns_zone.Zone{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/dns/zones/:id", ID: "updateDnsZone", Summary: "Update credentials or addresses of a DNS zone", Tag: "dns", Body: update_dns_zone.Command{}, Response: get_dns_zone.Zone{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/dns/zones/:id", ID: "deleteDnsZone", Summary: "Stop managing records of a DNS zone", Tag: "dns"},
+
+		// Teams
+		openapi.Route{Method: nethttp.MethodGet, Path: "/teams", ID: "listTeams", Summary: "List teams", Tag: "teams", Response: []get_teams.Team{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/teams", ID: "createTeam", Summary: "Create a team", Tag: "teams", Body: create_team.Command{}, Response: get_team.Team{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/teams/:id", ID: "getTeam", Summary: "Retrieve a team and its members", Tag: "teams", Response: get_team.Team{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/teams/:id", ID: "updateTeam", Summary: "Update a team", Tag: "teams", Body: update_team.Command{}, Response: get_team.Team{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/teams/:id", ID: "deleteTeam", Summary: "Delete a team", Tag: "teams"},
+
+		// Saved filters
+		openapi.Route{Method: nethttp.MethodGet, Path: "/saved-filters", ID: "listSavedFilters", Summary: "List filters saved by the current user", Tag: "saved-filters", Query: listSavedFiltersFilters{}, Response: []get_saved_filters.SavedFilter{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/saved-filters", ID: "createSavedFilter", Summary: "Save apps or deployments list criteria for the current user", Tag: "saved-filters", Body: create_saved_filter.Command{}, Response: get_saved_filters.SavedFilter{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/saved-filters/:id", ID: "deleteSavedFilter", Summary: "Delete a saved filter", Tag: "saved-filters"},
+
+		// Apps
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps", ID: "listApps", Summary: "List apps", Tag: "apps", Security: apiAccess, Query: listAppsFilters{}, Response: []get_apps.App{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps", ID: "createApp", Summary: "Create an app", Tag: "apps", Security: apiAccess, Body: create_app.Command{}, Response: get_app_detail.App{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id", ID: "getApp", Summary: "Retrieve an app", Tag: "apps", Security: apiAccess, Response: get_app_detail.App{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/apps/:id", ID: "updateApp", Summary: "Update an app", Tag: "apps", Security: apiAccess, Body: update_app.Command{}, Response: get_app_detail.App{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id", ID: "deleteApp", Summary: "Request an app cleanup and deletion, optionally exported to an archive beforehand", Tag: "apps", Security: apiAccess, Query: request_app_cleanup.Command{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/app-archives", ID: "listAppArchives", Summary: "List archives of deleted apps, most recent first", Tag: "apps", Security: apiAccess, Response: []get_app_archives.Archive{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/trashed-apps", ID: "listTrashedApps", Summary: "List deleted apps which could still be restored, most recently deleted first", Tag: "apps", Security: apiAccess, Response: []get_trashed_apps.TrashedApp{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/trashed-apps/:id/restore", ID: "restoreApp", Summary: "Recreate a deleted app, optionally redeploying its last successful deployments", Tag: "apps", Security: apiAccess, Body: restore_app.Command{}, Response: get_app_detail.App{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/app-archives/:id/download", ID: "downloadAppArchive", Summary: "Download the gzipped tarball of an app archive", Tag: "apps", Security: apiAccess, Response: "", ContentType: "application/gzip"},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/build-cache", ID: "clearAppBuildCache", Summary: "Clear the app build cache so the next deployment starts from scratch", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/badge-token", ID: "generateAppBadgeToken", Summary: "Generate the token needed to embed the app deployment badges, revoking the previous one", Tag: "apps", Security: apiAccess, Response: badgeTokenResult{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/badge-token", ID: "revokeAppBadgeToken", Summary: "Revoke the app badge token, its badges could not be retrieved anymore", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/badges/:environment", ID: "getAppBadge", Summary: "Render the latest deployment status and version of an app environment as an SVG badge", Tag: "apps", Security: public, Query: get_app_badge.Query{}, Response: "", ContentType: "image/svg+xml"},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/deploy-trigger", ID: "configureAppDeployTrigger", Summary: "Configure the key used to verify signed deployment triggers of an app, a secret is generated and returned once if no public key is given", Tag: "apps", Security: apiAccess, Body: configure_deploy_trigger.Command{}, Response: deployTriggerResult{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/deploy-trigger", ID: "removeAppDeployTrigger", Summary: "Remove the deploy trigger of an app, signed deployment triggers are rejected", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/freeze", ID: "configureAppFreeze", Summary: "Replace the freeze windows during which production deployments of an app are not processed", Tag: "apps", Security: apiAccess, Body: configure_freeze.Command{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/logs", ID: "streamAppLogs", Summary: "Stream the runtime logs of the app services running on the target of an environment as server-sent events", Tag: "apps", Security: apiAccess, Query: getAppLogsFilters{}, Response: "", ContentType: "text/event-stream"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/exec", ID: "execService", Summary: "Upgrade to a WebSocket running a one-off command in a service container", Tag: "apps", Security: apiAccess, Query: execServiceFilters{}, Status: nethttp.StatusSwitchingProtocols},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/resource-usage", ID: "getAppResourceUsage", Summary: "Retrieve resources consumed by the app services over time, oldest first", Tag: "apps", Security: apiAccess, Query: getAppResourceUsageFilters{}, Response: []get_app_resource_usage.Sample{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/traffic", ID: "getAppTraffic", Summary: "Retrieve requests served to the app services by the proxy of their targets", Tag: "apps", Security: apiAccess, Query: getAppTrafficFilters{}, Response: get_app_traffic.Traffic{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/stats", ID: "getAppDeploymentStats", Summary: "Compute metrics of the app deployments over a period: frequency, success rate, durations and time to restore", Tag: "apps", Security: apiAccess, Query: getDeploymentStatsFilters{}, Response: get_deployment_stats.Stats{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/incidents", ID: "listAppIncidents", Summary: "List incidents which happened to the app containers, most recent first", Tag: "apps", Security: apiAccess, Query: getAppIncidentsFilters{}, Response: storage.Paginated[get_app_incidents.Incident]{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/env-vars", ID: "exportAppEnvVars", Summary: "Export environment variables of an app service in the dotenv format, secret values are masked", Tag: "apps", Security: apiAccess, Query: export_env_vars.Query{}, Response: "", ContentType: "text/plain"},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/env-vars", ID: "importAppEnvVars", Summary: "Import environment variables of an app service from a dotenv payload, merged with existing ones by default", Tag: "apps", Security: apiAccess, Body: import_env_vars.Command{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/env-revisions", ID: "listAppEnvRevisions", Summary: "List the history of the app environment variables, most recent first", Tag: "apps", Security: apiAccess, Query: getAppEnvRevisionsFilters{}, Response: storage.Paginated[get_app_env_revisions.Revision]{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/env-revisions/:revision_id/restore", ID: "restoreAppEnvRevision", Summary: "Restore the environment variables of an app environment recorded by a revision", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/monitors", ID: "listAppMonitors", Summary: "List uptime monitors of the app with their most recent checks", Tag: "apps", Security: apiAccess, Response: []get_app_monitors.Monitor{}},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/monitors/:environment", ID: "configureAppMonitor", Summary: "Monitor the public url of an app environment or update how it is checked", Tag: "apps", Body: configure_monitor.Command{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/monitors/:environment", ID: "deleteAppMonitor", Summary: "Stop monitoring the public url of an app environment", Tag: "apps"},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/protection/:environment", ID: "configureAppAccessProtection", Summary: "Protect exposed services of an app environment with basic auth, an IP allowlist or a forward authentication", Tag: "apps", Security: apiAccess, Body: configure_access_protection.Command{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/protection/:environment", ID: "removeAppAccessProtection", Summary: "Remove the access protection of an app environment", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/proxy-rules/:environment", ID: "configureAppProxyRules", Summary: "Apply redirects and custom headers to exposed services of an app environment", Tag: "apps", Security: apiAccess, Body: configure_proxy_rules.Command{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/proxy-rules/:environment", ID: "removeAppProxyRules", Summary: "Remove custom proxy rules of an app environment", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/compose-override/:environment", ID: "configureAppComposeOverride", Summary: "Merge a compose file with the one of the next deployments of an app environment", Tag: "apps", Security: apiAccess, Body: configure_compose_override.Command{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/compose-override/:environment", ID: "removeAppComposeOverride", Summary: "Remove the compose override of an app environment", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/url-aliases/:environment", ID: "configureAppUrlAliases", Summary: "Route additional hosts, such as apex domains, to an app environment", Tag: "apps", Security: apiAccess, Body: configure_url_aliases.Command{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/url-aliases/:environment", ID: "removeAppUrlAliases", Summary: "Remove URL aliases of an app environment", Tag: "apps", Security: apiAccess},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/scale", ID: "scaleAppService", Summary: "Change the number of running containers of an app service without redeploying it", Tag: "apps", Security: apiAccess, Body: scale_service.Command{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/restart", ID: "restartAppServices", Summary: "Restart all or some services of an app environment without redeploying it", Tag: "apps", Security: apiAccess, Body: restart_services.Command{}},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/apps/:id/timeouts", ID: "configureAppTimeouts", Summary: "Replace the timeouts of the deployment pipeline stages of an app, in seconds, taking precedence over the instance ones", Tag: "apps", Security: apiAccess, Body: configure_timeouts.Command{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/addons", ID: "listAppAddons", Summary: "List add-ons of the app with their connection string", Tag: "apps", Security: apiAccess, Response: []get_app_addons.Addon{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/addons", ID: "createAppAddon", Summary: "Provision a managed add-on, such as a database, for an app environment", Tag: "apps", Body: create_addon.Command{}, Response: get_app_addons.Addon{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/apps/:id/addons/:addon_id", ID: "deleteAppAddon", Summary: "Remove an add-on and its data from the target", Tag: "apps"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/addons/:addon_id/backups", ID: "listAddonBackups", Summary: "List backups of a database add-on, most recent first", Tag: "apps", Security: apiAccess, Response: []get_addon_backups.Backup{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/addons/:addon_id/backups", ID: "backupAddon", Summary: "Back up a database add-on right now", Tag: "apps", Security: apiAccess, Response: get_addon_backups.Backup{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/addons/:addon_id/backups/:backup_id/restore", ID: "restoreAddonBackup", Summary: "Replace the data of a database add-on by one of its backups", Tag: "apps", Security: apiAccess},
+
+		// Deployments
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments", ID: "listDeployments", Summary: "List deployments of an app", Tag: "deployments", Security: apiAccess, Query: getDeploymentsFilters{}, Response: storage.Paginated[get_app_deployments.Deployment]{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/deployments", ID: "queueDeployment", Summary: "Queue a new deployment from a raw file, an archive or a git reference", Tag: "deployments", Security: apiAccess, Body: queueDeploymentBody{}, Multipart: true, Response: get_deployment.Deployment{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/signed-deployments", ID: "queueSignedDeployment", Summary: "Queue a new deployment from a payload signed with the app deploy trigger key, given in the X-Seelf-Timestamp and X-Seelf-Signature headers", Tag: "deployments", Security: public, Body: queueDeploymentBody{}, Multipart: true, Response: get_deployment.Deployment{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number", ID: "getDeployment", Summary: "Retrieve a deployment", Tag: "deployments", Security: apiAccess, Response: get_deployment.Deployment{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/wait", ID: "waitDeployment", Summary: "Wait for a deployment to succeed or fail, up to the given timeout. Returns a 202 status if it is still pending or running when the timeout expires", Tag: "deployments", Security: apiAccess, Query: waitDeploymentFilters{}, Response: get_deployment.Deployment{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/apps/:id/deployments/:number", ID: "annotateDeployment", Summary: "Update the note and metadata of a deployment", Tag: "deployments", Security: apiAccess, Body: annotate_deployment.Command{}, Response: get_deployment.Deployment{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/deployments/:number/redeploy", ID: "redeploy", Summary: "Redeploy a deployment", Tag: "deployments", Security: apiAccess, Response: get_deployment.Deployment{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/deployments/:number/retry", ID: "retryDeployment", Summary: "Retry a failed deployment, resuming it from the step at which it has failed when possible", Tag: "deployments", Security: apiAccess, Response: get_deployment.Deployment{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/deployments/:number/unfreeze", ID: "unfreezeDeployment", Summary: "Allow a pending production deployment to be processed during freeze windows, administrators only", Tag: "deployments", Security: apiAccess, Response: get_deployment.Deployment{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/apps/:id/deployments/:number/promote", ID: "promote", Summary: "Promote a deployment to the production environment", Tag: "deployments", Security: apiAccess, Response: get_deployment.Deployment{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/feeds/deployments", ID: "getDeploymentsFeed", Summary: "Publish the most recent deployments of readable apps as a RSS or Atom feed or an iCal calendar", Tag: "deployments", Security: apiAccess, Query: getDeploymentsFeedFilters{}, Response: "", ContentType: "application/rss+xml"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/feeds/deployments", ID: "getAppDeploymentsFeed", Summary: "Publish the most recent deployments of an app as a RSS or Atom feed or an iCal calendar", Tag: "deployments", Security: apiAccess, Query: getDeploymentsFeedFilters{}, Response: "", ContentType: "application/rss+xml"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/logs", ID: "getDeploymentLogs", Summary: "Retrieve deployment logs", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "text/plain"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/logs/steps", ID: "getDeploymentLogSteps", Summary: "Retrieve deployment logs grouped by pipeline step with their duration", Tag: "deployments", Security: apiAccess, Response: []get_deployment_log_steps.Step{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/logs/stream", ID: "streamDeploymentLogs", Summary: "Stream deployment logs as server-sent events while the deployment runs", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "text/event-stream"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/logs/download", ID: "downloadDeploymentLogs", Summary: "Download the deployment log file, gzipped once the deployment is done. Range requests are supported", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "application/octet-stream"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/build-context", ID: "downloadDeploymentBuildContext", Summary: "Download a gzipped tarball of the files fetched and generated by a deployment in its build directory", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "application/gzip"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/manifest", ID: "downloadDeploymentManifest", Summary: "Download the manifest of images deployed by a successful deployment", Tag: "deployments", Security: apiAccess, Response: domain.DeploymentManifest{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/sbom/:service", ID: "downloadDeploymentSBOM", Summary: "Download the syft JSON software bill of materials of a deployed service image", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "application/json"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/apps/:id/deployments/:number/scan/:service", ID: "downloadDeploymentScan", Summary: "Download the vulnerability scanner report of a built service image", Tag: "deployments", Security: apiAccess, Response: "", ContentType: "application/json"},
+
+		// Webhooks
+		openapi.Route{Method: nethttp.MethodGet, Path: "/webhooks", ID: "listWebhooks", Summary: "List webhooks", Tag: "webhooks", Response: []get_webhook.Webhook{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/webhooks", ID: "createWebhook", Summary: "Create a webhook", Tag: "webhooks", Body: create_webhook.Command{}, Response: get_webhook.Webhook{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/webhooks/:id", ID: "getWebhook", Summary: "Retrieve a webhook", Tag: "webhooks", Response: get_webhook.Webhook{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/webhooks/:id", ID: "updateWebhook", Summary: "Update a webhook", Tag: "webhooks", Body: update_webhook.Command{}, Response: get_webhook.Webhook{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/webhooks/:id", ID: "deleteWebhook", Summary: "Delete a webhook and its delivery history", Tag: "webhooks"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/webhooks/:id/deliveries", ID: "listWebhookDeliveries", Summary: "Browse the delivery history of a webhook", Tag: "webhooks", Query: listWebhookDeliveriesFilters{}, Response: storage.Paginated[get_webhook_deliveries.Delivery]{}},
+
+		// Channels
+		openapi.Route{Method: nethttp.MethodGet, Path: "/channels", ID: "listChannels", Summary: "List channels notified of deployment results", Tag: "channels", Query: listChannelsFilters{}, Response: []get_channel.Channel{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/channels", ID: "createChannel", Summary: "Create a slack, discord or matrix channel", Tag: "channels", Body: create_channel.Command{}, Response: get_channel.Channel{}, Status: nethttp.StatusCreated},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/channels/:id", ID: "getChannel", Summary: "Retrieve a channel", Tag: "channels", Response: get_channel.Channel{}},
+		openapi.Route{Method: nethttp.MethodPatch, Path: "/channels/:id", ID: "updateChannel", Summary: "Update a channel", Tag: "channels", Body: update_channel.Command{}, Response: get_channel.Channel{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/channels/:id", ID: "deleteChannel", Summary: "Delete a channel", Tag: "channels"},
+
+		// Realtime
+		openapi.Route{Method: nethttp.MethodGet, Path: "/events", ID: "subscribeEvents", Summary: "Upgrade to a WebSocket receiving realtime events", Tag: "events", Security: apiAccess, Status: nethttp.StatusSwitchingProtocols},
+
+		// Administration
+		openapi.Route{Method: nethttp.MethodGet, Path: "/jobs", ID: "listJobs", Summary: "List scheduled jobs", Tag: "administration", Query: listJobsFilters{}, Response: storage.Paginated[bus.ScheduledJob]{}},
+		openapi.Route{Method: nethttp.MethodDelete, Path: "/jobs/:id", ID: "deleteJob", Summary: "Delete a scheduled job", Tag: "administration"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/worker", ID: "getWorkerStatus", Summary: "Diagnose the background jobs worker from its latest heartbeat", Tag: "administration", Response: startup.WorkerStatus{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/maintenance", ID: "getMaintenance", Summary: "Retrieve the maintenance mode status", Tag: "administration", Response: startup.MaintenanceStatus{}},
+		openapi.Route{Method: nethttp.MethodPut, Path: "/maintenance", ID: "updateMaintenance", Summary: "Enable or disable the maintenance mode", Tag: "administration", Body: updateMaintenanceRequest{}, Response: startup.MaintenanceStatus{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/config/reload", ID: "reloadConfiguration", Summary: "Reload settings which could be changed while running", Tag: "administration"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/audit", ID: "listAuditEntries", Summary: "Browse the audit log", Tag: "administration", Query: listAuditEntriesFilters{}, Response: storage.Paginated[get_audit_entries.AuditEntry]{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/gitops/runs", ID: "listGitOpsRuns", Summary: "List reconciliations with the GitOps repository, most recent first", Tag: "administration", Query: listGitOpsRunsFilters{}, Response: storage.Paginated[get_gitops_runs.Run]{}},
+		openapi.Route{Method: nethttp.MethodPost, Path: "/gitops/sync", ID: "syncGitOps", Summary: "Reconcile targets and apps with the GitOps repository right away", Tag: "administration"},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/artifacts/usage", ID: "getArtifactsUsage", Summary: "Retrieve the disk space used by artifacts as computed by the last collection", Tag: "administration", Response: get_artifacts_usage.Usage{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: "/healthcheck", ID: "healthcheck", Summary: "Check the server is up and running", Tag: "administration", Security: public, Response: healthCheckResponse{}},
+		openapi.Route{Method: nethttp.MethodGet, Path: openapiDocumentPath, ID: "getOpenAPIDocument", Summary: "Retrieve this document", Tag: "administration", Security: public, Response: map[string]any{}},
+	)
+}
+
+func (s *server) openapiHandler(ctx *gin.Context) {
+	ctx.Data(nethttp.StatusOK, "application/json; charset=utf-8", openapiDocument)
+}
@@ -0,0 +1,114 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_federated_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peers"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_peer"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) createPeerHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_peer.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_peer.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/peers/%s", id)
+	})
+}
+
+func (s *server) updatePeerHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_peer.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_peer.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deletePeerHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_peer.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listPeersHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_peers.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getPeerByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_peer.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+type listFederatedAppsFilters struct {
+	Labels []string `form:"labels"`
+}
+
+func (s *server) listFederatedAppsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request listFederatedAppsFilters) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_federated_apps.Query{
+			Labels: request.Labels,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
@@ -0,0 +1,73 @@
+package serve
+
+import (
+	"slices"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_saved_filter"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_saved_filter"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_saved_filters"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type listSavedFiltersFilters struct {
+	Kind string `form:"kind"`
+}
+
+func (s *server) listSavedFiltersHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request listSavedFiltersFilters) error {
+		var query get_saved_filters.Query
+
+		if request.Kind != "" {
+			query.Kind.Set(request.Kind)
+		}
+
+		data, err := bus.Send(s.bus, c.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) createSavedFilterHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_saved_filter.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		filters, err := bus.Send(s.bus, ctx, get_saved_filters.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(filters, func(f get_saved_filters.SavedFilter) bool { return f.ID == id })
+
+		if idx < 0 {
+			return apperr.ErrNotFound
+		}
+
+		return http.Created(s, c, filters[idx], "/api/v1/saved-filters")
+	})
+}
+
+func (s *server) deleteSavedFilterHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		if _, err := bus.Send(s.bus, c.Request.Context(), delete_saved_filter.Command{
+			ID: c.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(c)
+	})
+}
@@ -0,0 +1,6 @@
+// Package seelfpb contains the protobuf definitions of the seelf gRPC API along with
+// the code generated from them. Integrators can use the generated client to talk to a
+// seelf instance.
+package seelfpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative seelf.proto
@@ -0,0 +1,423 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: seelf.proto
+
+package seelfpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Seelf_ListApps_FullMethodName        = "/seelf.v1.Seelf/ListApps"
+	Seelf_GetApp_FullMethodName          = "/seelf.v1.Seelf/GetApp"
+	Seelf_ListDeployments_FullMethodName = "/seelf.v1.Seelf/ListDeployments"
+	Seelf_GetDeployment_FullMethodName   = "/seelf.v1.Seelf/GetDeployment"
+	Seelf_QueueDeployment_FullMethodName = "/seelf.v1.Seelf/QueueDeployment"
+	Seelf_Redeploy_FullMethodName        = "/seelf.v1.Seelf/Redeploy"
+	Seelf_ListTargets_FullMethodName     = "/seelf.v1.Seelf/ListTargets"
+	Seelf_GetTarget_FullMethodName       = "/seelf.v1.Seelf/GetTarget"
+	Seelf_ListJobs_FullMethodName        = "/seelf.v1.Seelf/ListJobs"
+)
+
+// SeelfClient is the client API for Seelf service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SeelfClient interface {
+	// List apps the caller has access to.
+	ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error)
+	// Retrieve an app.
+	GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*App, error)
+	// List deployments of an app, latest first.
+	ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error)
+	// Retrieve a deployment.
+	GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// Queue a new deployment of an app.
+	QueueDeployment(ctx context.Context, in *QueueDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// Queue a new deployment using the source of an existing one.
+	Redeploy(ctx context.Context, in *RedeployRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// List targets the caller has access to.
+	ListTargets(ctx context.Context, in *ListTargetsRequest, opts ...grpc.CallOption) (*ListTargetsResponse, error)
+	// Retrieve a target.
+	GetTarget(ctx context.Context, in *GetTargetRequest, opts ...grpc.CallOption) (*Target, error)
+	// List scheduled jobs, admins only.
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+}
+
+type seelfClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSeelfClient(cc grpc.ClientConnInterface) SeelfClient {
+	return &seelfClient{cc}
+}
+
+func (c *seelfClient) ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error) {
+	out := new(ListAppsResponse)
+	err := c.cc.Invoke(ctx, Seelf_ListApps_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*App, error) {
+	out := new(App)
+	err := c.cc.Invoke(ctx, Seelf_GetApp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error) {
+	out := new(ListDeploymentsResponse)
+	err := c.cc.Invoke(ctx, Seelf_ListDeployments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Seelf_GetDeployment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) QueueDeployment(ctx context.Context, in *QueueDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Seelf_QueueDeployment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) Redeploy(ctx context.Context, in *RedeployRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, Seelf_Redeploy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) ListTargets(ctx context.Context, in *ListTargetsRequest, opts ...grpc.CallOption) (*ListTargetsResponse, error) {
+	out := new(ListTargetsResponse)
+	err := c.cc.Invoke(ctx, Seelf_ListTargets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) GetTarget(ctx context.Context, in *GetTargetRequest, opts ...grpc.CallOption) (*Target, error) {
+	out := new(Target)
+	err := c.cc.Invoke(ctx, Seelf_GetTarget_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seelfClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, Seelf_ListJobs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SeelfServer is the server API for Seelf service.
+// All implementations must embed UnimplementedSeelfServer
+// for forward compatibility
+type SeelfServer interface {
+	// List apps the caller has access to.
+	ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error)
+	// Retrieve an app.
+	GetApp(context.Context, *GetAppRequest) (*App, error)
+	// List deployments of an app, latest first.
+	ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error)
+	// Retrieve a deployment.
+	GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error)
+	// Queue a new deployment of an app.
+	QueueDeployment(context.Context, *QueueDeploymentRequest) (*Deployment, error)
+	// Queue a new deployment using the source of an existing one.
+	Redeploy(context.Context, *RedeployRequest) (*Deployment, error)
+	// List targets the caller has access to.
+	ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error)
+	// Retrieve a target.
+	GetTarget(context.Context, *GetTargetRequest) (*Target, error)
+	// List scheduled jobs, admins only.
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	mustEmbedUnimplementedSeelfServer()
+}
+
+// UnimplementedSeelfServer must be embedded to have forward compatible implementations.
+type UnimplementedSeelfServer struct {
+}
+
+func (UnimplementedSeelfServer) ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApps not implemented")
+}
+func (UnimplementedSeelfServer) GetApp(context.Context, *GetAppRequest) (*App, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApp not implemented")
+}
+func (UnimplementedSeelfServer) ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeployments not implemented")
+}
+func (UnimplementedSeelfServer) GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeployment not implemented")
+}
+func (UnimplementedSeelfServer) QueueDeployment(context.Context, *QueueDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueDeployment not implemented")
+}
+func (UnimplementedSeelfServer) Redeploy(context.Context, *RedeployRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Redeploy not implemented")
+}
+func (UnimplementedSeelfServer) ListTargets(context.Context, *ListTargetsRequest) (*ListTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTargets not implemented")
+}
+func (UnimplementedSeelfServer) GetTarget(context.Context, *GetTargetRequest) (*Target, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTarget not implemented")
+}
+func (UnimplementedSeelfServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedSeelfServer) mustEmbedUnimplementedSeelfServer() {}
+
+// UnsafeSeelfServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SeelfServer will
+// result in compilation errors.
+type UnsafeSeelfServer interface {
+	mustEmbedUnimplementedSeelfServer()
+}
+
+func RegisterSeelfServer(s grpc.ServiceRegistrar, srv SeelfServer) {
+	s.RegisterService(&Seelf_ServiceDesc, srv)
+}
+
+func _Seelf_ListApps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).ListApps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_ListApps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).ListApps(ctx, req.(*ListAppsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_GetApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).GetApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_GetApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).GetApp(ctx, req.(*GetAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_ListDeployments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).ListDeployments(ctx, req.(*ListDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_GetDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).GetDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_GetDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).GetDeployment(ctx, req.(*GetDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_QueueDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).QueueDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_QueueDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).QueueDeployment(ctx, req.(*QueueDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_Redeploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeployRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).Redeploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_Redeploy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).Redeploy(ctx, req.(*RedeployRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_ListTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).ListTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_ListTargets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).ListTargets(ctx, req.(*ListTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_GetTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).GetTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_GetTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).GetTarget(ctx, req.(*GetTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seelf_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeelfServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seelf_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeelfServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Seelf_ServiceDesc is the grpc.ServiceDesc for Seelf service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Seelf_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "seelf.v1.Seelf",
+	HandlerType: (*SeelfServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListApps",
+			Handler:    _Seelf_ListApps_Handler,
+		},
+		{
+			MethodName: "GetApp",
+			Handler:    _Seelf_GetApp_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _Seelf_ListDeployments_Handler,
+		},
+		{
+			MethodName: "GetDeployment",
+			Handler:    _Seelf_GetDeployment_Handler,
+		},
+		{
+			MethodName: "QueueDeployment",
+			Handler:    _Seelf_QueueDeployment_Handler,
+		},
+		{
+			MethodName: "Redeploy",
+			Handler:    _Seelf_Redeploy_Handler,
+		},
+		{
+			MethodName: "ListTargets",
+			Handler:    _Seelf_ListTargets_Handler,
+		},
+		{
+			MethodName: "GetTarget",
+			Handler:    _Seelf_GetTarget_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Seelf_ListJobs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "seelf.proto",
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,7 +16,12 @@ import (
 	"github.com/YuukanOO/seelf/cmd/startup"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/health"
+	httputils "github.com/YuukanOO/seelf/pkg/http"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/oidc"
+	"github.com/YuukanOO/seelf/pkg/realtime"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
@@ -27,6 +33,7 @@ var front embed.FS
 const (
 	embeddedRootDir = "front/build"
 	sessionName     = "seelf"
+	sessionPath     = "/api/v1"
 )
 
 type (
@@ -35,6 +42,14 @@ type (
 		Secret() []byte
 		IsSecure() bool
 		ListenAddress() string
+		PasswordAuthEnabled() bool              // Wether or not users could log in with their email and password
+		OIDC() monad.Maybe[oidc.Options]        // OpenID Connect provider users could log in with if any
+		GRPCListenAddress() monad.Maybe[string] // Address of the gRPC API if enabled
+		RateLimit() int                         // Requests per minute allowed for each client, 0 to disable
+		RateLimitBurst() int                    // Requests a client could send at once
+		MaxBodySize() int64                     // Maximum size of request bodies in bytes
+		MaxArchiveSize() int64                  // Maximum size of uploaded archives in bytes
+		IdempotencyTTL() time.Duration          // How long responses to requests with an idempotency key are kept, 0 to disable
 	}
 
 	server struct {
@@ -44,6 +59,14 @@ type (
 		logger             log.Logger
 		usersReader        domain.UsersReader
 		scheduledJobsStore bus.ScheduledJobsStore
+		idempotencyStore   httputils.IdempotencyStore
+		events             *realtime.Hub
+		health             *health.Checker
+		reload             func() error
+		maintenance        maintenanceMode
+		worker             workerDiagnostics
+		oidc               *oidc.Provider
+		limiter            *httputils.RateLimiter
 	}
 )
 
@@ -55,8 +78,18 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 		router:             gin.New(),
 		usersReader:        root.UsersReader(),
 		scheduledJobsStore: root.ScheduledJobsStore(),
+		idempotencyStore:   root.IdempotencyStore(),
+		events:             root.Events(),
+		health:             root.Health(),
+		reload:             root.Reload,
+		maintenance:        root,
+		worker:             root,
 		bus:                root.Bus(),
-		logger:             root.Logger(),
+		logger:             root.Logger().Named("http"),
+	}
+
+	if opts, isSet := options.OIDC().TryGet(); isSet {
+		s.oidc = oidc.New(opts)
 	}
 
 	s.router.SetTrustedProxies(nil)
@@ -64,66 +97,221 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 	// Configure the session store
 	store := cookie.NewStore(s.options.Secret())
 	store.Options(sessions.Options{
+		Path:     sessionPath, // Explicitly set since sessions could be created from nested routes
 		Secure:   s.options.IsSecure(),
 		HttpOnly: true,
 		SameSite: http.SameSiteStrictMode,
 	})
 
-	s.router.Use(s.requestLogger, s.recoverer, sessions.Sessions(sessionName, store))
+	s.router.Use(s.correlate, s.instrument, s.requestLogger, s.recoverer, sessions.Sessions(sessionName, store))
+
+	// Probes are kept out of the API so they are never rate limited
+	s.router.GET("/healthz", s.livenessHandler)
+	s.router.GET("/readyz", s.readinessHandler)
 
 	// Let's register every routes now!
-	v1 := s.router.Group("/api/v1")
+	api := s.router.Group("/api/v1")
+
+	if s.options.RateLimit() > 0 {
+		s.limiter = httputils.NewRateLimiter(float64(s.options.RateLimit())/60, s.options.RateLimitBurst())
+		api.Use(s.rateLimit)
+	}
+
+	api.Use(s.rejectMutationsInMaintenance)
+
+	// Archives uploads are the only ones allowed to send big bodies
+	uploads := api.Group("", httputils.LimitBody(s.options.MaxArchiveSize()))
+	v1 := api.Group("", httputils.LimitBody(s.options.MaxBodySize()))
+
+	// Mutations could be retried safely by authenticated clients with an idempotency key
+	idempotent := httputils.Idempotent(s, s.idempotencyStore, s.options.IdempotencyTTL(), idempotencyScope)
 
 	// Public routes
 	v1.POST("/sessions", s.createSessionHandler())
 	v1.GET("/healthcheck", s.healthcheckHandler)
+	v1.GET("/auth/methods", s.getAuthMethodsHandler())
+	v1.GET("/setup", s.getSetupHandler())
+	v1.POST("/setup/account", s.setupAccountHandler())
+	v1.GET(openapiDocumentPath, s.openapiHandler)
+	v1.GET("/apps/:id/badges/:environment", s.getAppBadgeHandler())
+
+	if s.oidc != nil {
+		v1.GET("/auth/oidc", s.oidcLoginHandler)
+		v1.GET("/auth/oidc/callback", s.oidcCallbackHandler)
+	}
 
 	// Authenticated routes
-	v1secured := v1.Group("", s.authenticate(false))
+	v1secured := v1.Group("", s.authenticate(false), idempotent)
 	v1secured.DELETE("/session", s.deleteSessionHandler())
-	v1secured.GET("/jobs", s.listJobsHandler())
-	v1secured.DELETE("/jobs/:id", s.deleteJobsHandler())
+	v1secured.GET("/sessions", s.listSessionsHandler())
+	v1secured.DELETE("/sessions/:id", s.revokeSessionHandler())
+	v1secured.GET("/jobs", s.requireAdmin, s.listJobsHandler())
+	v1secured.DELETE("/jobs/:id", s.requireAdmin, s.deleteJobsHandler())
+	v1secured.GET("/worker", s.requireAdmin, s.getWorkerStatusHandler())
+	v1secured.GET("/audit", s.requireAdmin, s.listAuditEntriesHandler())
+	v1secured.GET("/artifacts/usage", s.requireAdmin, s.getArtifactsUsageHandler())
+	v1secured.POST("/config/reload", s.requireAdmin, s.reloadConfigurationHandler())
+	v1secured.GET("/gitops/runs", s.requireAdmin, s.listGitOpsRunsHandler())
+	v1secured.POST("/gitops/sync", s.requireAdmin, s.syncGitOpsHandler())
+	v1secured.POST("/setup/checks", s.requireAdmin, s.setupChecksHandler())
+	v1secured.POST("/setup/target", s.requireAdmin, s.setupTargetHandler())
+	v1secured.GET("/maintenance", s.getMaintenanceHandler())
+	v1secured.PUT("/maintenance", s.requireAdmin, s.updateMaintenanceHandler())
 	v1secured.GET("/profile", s.getProfileHandler())
 	v1secured.PATCH("/profile", s.updateProfileHandler())
 	v1secured.PUT("/profile/key", s.refreshProfileKeyHandler())
-	v1secured.POST("/targets", s.createTargetHandler())
-	v1secured.PATCH("/targets/:id", s.updateTargetHandler())
+	v1secured.GET("/profile/notifications", s.getProfileNotificationsHandler())
+	v1secured.PATCH("/profile/notifications", s.updateProfileNotificationsHandler())
+	v1secured.GET("/users", s.listUsersHandler())
+	v1secured.POST("/users", s.inviteUserHandler())
+	v1secured.GET("/users/:id", s.getUserByIDHandler())
+	v1secured.DELETE("/users/:id", s.deleteUserHandler())
+	v1secured.POST("/users/:id/disable", s.disableUserHandler())
+	v1secured.POST("/users/:id/enable", s.enableUserHandler())
+	v1secured.PUT("/users/:id/grants", s.grantRoleHandler())
+	v1secured.DELETE("/users/:id/grants/:type/:resource", s.revokeRoleHandler())
+	v1secured.GET("/tokens", s.listTokensHandler())
+	v1secured.POST("/tokens", s.createTokenHandler())
+	v1secured.GET("/tokens/:id", s.getTokenByIDHandler())
+	v1secured.DELETE("/tokens/:id", s.revokeTokenHandler())
 	v1secured.POST("/targets/:id/reconfigure", s.reconfigureTargetHandler())
-	v1secured.GET("/targets", s.listTargetsHandler())
-	v1secured.GET("/targets/:id", s.getTargetByIDHandler())
-	v1secured.DELETE("/targets/:id", s.deleteTargetHandler())
+	v1secured.POST("/targets/:id/proxy/upgrade", s.upgradeTargetProxyHandler())
 	v1secured.POST("/registries", s.createRegistryHandler())
 	v1secured.PATCH("/registries/:id", s.updateRegistryHandler())
 	v1secured.DELETE("/registries/:id", s.deleteRegistryHandler())
 	v1secured.GET("/registries", s.listRegistriesHandler())
 	v1secured.GET("/registries/:id", s.getRegistryByIDHandler())
-	v1secured.GET("/apps", s.listAppsHandler())
-	v1secured.POST("/apps", s.createAppHandler())
-	v1secured.PATCH("/apps/:id", s.updateAppHandler())
-	v1secured.DELETE("/apps/:id", s.requestAppCleanupHandler())
+	v1secured.POST("/peers", s.requireAdmin, s.createPeerHandler())
+	v1secured.PATCH("/peers/:id", s.requireAdmin, s.updatePeerHandler())
+	v1secured.DELETE("/peers/:id", s.requireAdmin, s.deletePeerHandler())
+	v1secured.GET("/peers", s.requireAdmin, s.listPeersHandler())
+	v1secured.GET("/peers/:id", s.requireAdmin, s.getPeerByIDHandler())
+	v1secured.GET("/federation/apps", s.requireAdmin, s.listFederatedAppsHandler())
+	v1secured.POST("/dns/zones", s.requireAdmin, s.createDnsZoneHandler())
+	v1secured.PATCH("/dns/zones/:id", s.requireAdmin, s.updateDnsZoneHandler())
+	v1secured.DELETE("/dns/zones/:id", s.requireAdmin, s.deleteDnsZoneHandler())
+	v1secured.GET("/dns/zones", s.requireAdmin, s.listDnsZonesHandler())
+	v1secured.GET("/dns/zones/:id", s.requireAdmin, s.getDnsZoneByIDHandler())
+	v1secured.POST("/teams", s.createTeamHandler())
+	v1secured.PATCH("/teams/:id", s.updateTeamHandler())
+	v1secured.DELETE("/teams/:id", s.deleteTeamHandler())
+	v1secured.GET("/teams", s.listTeamsHandler())
+	v1secured.GET("/teams/:id", s.getTeamByIDHandler())
+	v1secured.GET("/saved-filters", s.listSavedFiltersHandler())
+	v1secured.POST("/saved-filters", s.createSavedFilterHandler())
+	v1secured.DELETE("/saved-filters/:id", s.deleteSavedFilterHandler())
+	v1secured.POST("/webhooks", s.createWebhookHandler())
+	v1secured.PATCH("/webhooks/:id", s.updateWebhookHandler())
+	v1secured.DELETE("/webhooks/:id", s.deleteWebhookHandler())
+	v1secured.GET("/webhooks", s.listWebhooksHandler())
+	v1secured.GET("/webhooks/:id", s.getWebhookByIDHandler())
+	v1secured.GET("/webhooks/:id/deliveries", s.listWebhookDeliveriesHandler())
+	v1secured.POST("/channels", s.createChannelHandler())
+	v1secured.PATCH("/channels/:id", s.updateChannelHandler())
+	v1secured.DELETE("/channels/:id", s.deleteChannelHandler())
+	v1secured.GET("/channels", s.listChannelsHandler())
+	v1secured.GET("/channels/:id", s.getChannelByIDHandler())
+	v1secured.PUT("/apps/:id/monitors/:environment", s.configureMonitorHandler())
+	v1secured.DELETE("/apps/:id/monitors/:environment", s.deleteMonitorHandler())
+	v1secured.POST("/apps/:id/addons", s.createAddonHandler())
+	v1secured.DELETE("/apps/:id/addons/:addon_id", s.requestAddonCleanupHandler())
+
+	// Feeds are consumed by clients which could only authenticate with the url
+	v1feeds := v1.Group("", s.allowApiKeyInQuery, s.authenticate(true))
+	v1feeds.GET("/feeds/deployments", s.getDeploymentsFeedHandler())
+	v1feeds.GET("/apps/:id/feeds/deployments", s.getDeploymentsFeedHandler())
 
 	// Allow API Key authentication for those routes
 	// FIXME: in the future, maybe all the API should be accessible, but not before https://github.com/YuukanOO/seelf/issues/45
-	v1securedAllowApi := v1.Group("", s.authenticate(true))
+	v1securedAllowApi := v1.Group("", s.authenticate(true), idempotent)
+	v1securedAllowApi.POST("/targets", s.createTargetHandler())
+	v1securedAllowApi.PATCH("/targets/:id", s.updateTargetHandler())
+	v1securedAllowApi.GET("/targets", s.listTargetsHandler())
+	v1securedAllowApi.GET("/targets/capacity", s.getTargetsCapacityHandler())
+	v1securedAllowApi.GET("/targets/:id", s.getTargetByIDHandler())
+	v1securedAllowApi.DELETE("/targets/:id", s.deleteTargetHandler())
+	v1securedAllowApi.POST("/targets/:id/redeploy", s.redeployTargetHandler())
+	v1securedAllowApi.GET("/targets/:id/stats", s.getTargetDeploymentStatsHandler())
+	v1securedAllowApi.GET("/apps", s.listAppsHandler())
+	v1securedAllowApi.POST("/apps", s.createAppHandler())
 	v1securedAllowApi.GET("/apps/:id", s.getAppByIDHandler())
-	v1securedAllowApi.POST("/apps/:id/deployments", s.queueDeploymentHandler())
+	v1securedAllowApi.PATCH("/apps/:id", s.updateAppHandler())
+	v1securedAllowApi.DELETE("/apps/:id", s.requestAppCleanupHandler())
+	v1securedAllowApi.GET("/app-archives", s.listAppArchivesHandler())
+	v1securedAllowApi.GET("/app-archives/:id/download", s.downloadAppArchiveHandler())
+	v1securedAllowApi.GET("/trashed-apps", s.listTrashedAppsHandler())
+	v1securedAllowApi.POST("/trashed-apps/:id/restore", s.restoreAppHandler())
+	v1securedAllowApi.PUT("/apps/:id/protection/:environment", s.configureAccessProtectionHandler())
+	v1securedAllowApi.DELETE("/apps/:id/protection/:environment", s.removeAccessProtectionHandler())
+	v1securedAllowApi.PUT("/apps/:id/proxy-rules/:environment", s.configureProxyRulesHandler())
+	v1securedAllowApi.DELETE("/apps/:id/proxy-rules/:environment", s.removeProxyRulesHandler())
+	v1securedAllowApi.PUT("/apps/:id/compose-override/:environment", s.configureComposeOverrideHandler())
+	v1securedAllowApi.DELETE("/apps/:id/compose-override/:environment", s.removeComposeOverrideHandler())
+	v1securedAllowApi.PUT("/apps/:id/url-aliases/:environment", s.configureUrlAliasesHandler())
+	v1securedAllowApi.DELETE("/apps/:id/url-aliases/:environment", s.removeUrlAliasesHandler())
+	v1securedAllowApi.POST("/apps/:id/scale", s.scaleServiceHandler())
+	v1securedAllowApi.POST("/apps/:id/restart", s.restartServicesHandler())
+	v1securedAllowApi.PUT("/apps/:id/timeouts", s.configureTimeoutsHandler())
+	v1securedAllowApi.DELETE("/apps/:id/build-cache", s.clearBuildCacheHandler())
+	v1securedAllowApi.POST("/apps/:id/badge-token", s.generateBadgeTokenHandler())
+	v1securedAllowApi.DELETE("/apps/:id/badge-token", s.revokeBadgeTokenHandler())
+	v1securedAllowApi.PUT("/apps/:id/deploy-trigger", s.configureDeployTriggerHandler())
+	v1securedAllowApi.DELETE("/apps/:id/deploy-trigger", s.removeDeployTriggerHandler())
+	v1securedAllowApi.PUT("/apps/:id/freeze", s.configureFreezeHandler())
+	v1securedAllowApi.GET("/apps/:id/logs", s.streamAppLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/exec", s.execServiceHandler())
+	v1securedAllowApi.GET("/apps/:id/resource-usage", s.getAppResourceUsageHandler())
+	v1securedAllowApi.GET("/apps/:id/traffic", s.getAppTrafficHandler())
+	v1securedAllowApi.GET("/apps/:id/stats", s.getAppDeploymentStatsHandler())
+	v1securedAllowApi.GET("/apps/:id/incidents", s.listAppIncidentsHandler())
+	v1securedAllowApi.GET("/apps/:id/env-vars", s.exportEnvVarsHandler())
+	v1securedAllowApi.POST("/apps/:id/env-vars", s.importEnvVarsHandler())
+	v1securedAllowApi.GET("/apps/:id/env-revisions", s.listAppEnvRevisionsHandler())
+	v1securedAllowApi.POST("/apps/:id/env-revisions/:revision_id/restore", s.restoreEnvRevisionHandler())
+	v1securedAllowApi.GET("/apps/:id/monitors", s.listAppMonitorsHandler())
+	v1securedAllowApi.GET("/apps/:id/addons", s.listAppAddonsHandler())
+	v1securedAllowApi.GET("/apps/:id/addons/:addon_id/backups", s.listAddonBackupsHandler())
+	v1securedAllowApi.POST("/apps/:id/addons/:addon_id/backups", s.requestAddonBackupHandler())
+	v1securedAllowApi.POST("/apps/:id/addons/:addon_id/backups/:backup_id/restore", s.requestAddonRestoreHandler())
+	uploads.POST("/apps/:id/deployments", s.authenticate(true), idempotent, s.queueDeploymentHandler())
+	uploads.POST("/apps/:id/signed-deployments", s.authenticateDeployTrigger, idempotent, s.queueDeploymentHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments", s.listDeploymentsByAppHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments/:number", s.getDeploymentByIDHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/wait", s.waitDeploymentHandler())
+	v1securedAllowApi.PATCH("/apps/:id/deployments/:number", s.annotateDeploymentHandler())
 	v1securedAllowApi.POST("/apps/:id/deployments/:number/redeploy", s.redeployHandler())
+	v1securedAllowApi.POST("/apps/:id/deployments/:number/retry", s.retryDeploymentHandler())
+	v1securedAllowApi.POST("/apps/:id/deployments/:number/unfreeze", s.unfreezeDeploymentHandler())
 	v1securedAllowApi.POST("/apps/:id/deployments/:number/promote", s.promoteHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs", s.getDeploymentLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs/stream", s.streamDeploymentLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs/steps", s.getDeploymentLogStepsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs/download", s.downloadDeploymentLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/build-context", s.downloadDeploymentBuildContextHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/manifest", s.downloadDeploymentManifestHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/sbom/:service", s.downloadDeploymentSBOMHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/scan/:service", s.downloadDeploymentScanHandler())
+	v1securedAllowApi.GET("/events", s.eventsHandler)
 
 	s.useSPA()
 
 	return s
 }
 
-func (s *server) Listen() (finalErr error) {
+// Serve the API until a termination signal is received. The started function is called
+// once every listener has been bound.
+func (s *server) Listen(started func()) (finalErr error) {
 	srv := &http.Server{
 		Addr:    s.options.ListenAddress(),
 		Handler: s.router,
 	}
 
+	listener, err := net.Listen("tcp", srv.Addr)
+
+	if err != nil {
+		return err
+	}
+
 	s.logger.Infow("launching web server",
 		"address", srv.Addr,
 	)
@@ -131,15 +319,58 @@ func (s *server) Listen() (finalErr error) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	if addr, isSet := s.options.GRPCListenAddress().TryGet(); isSet {
+		grpcListener, err := net.Listen("tcp", addr)
+
+		if err != nil {
+			listener.Close()
+			return err
+		}
+
+		grpcSrv := newGrpcServer(s)
+		defer grpcSrv.GracefulStop()
+
+		s.logger.Infow("launching gRPC server",
+			"address", addr,
+		)
+
+		go func() {
+			if err := grpcSrv.Serve(grpcListener); err != nil {
+				finalErr = err
+				quit <- syscall.SIGTERM
+			}
+		}()
+	}
+
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
 			finalErr = err
 			quit <- syscall.SIGTERM
 		}
 	}()
 
+	started()
+
+	// Reload the configuration on SIGHUP until it's time to shut down
+	for running := true; running; {
+		select {
+		case <-reload:
+			s.logger.Info("reloading the configuration")
+
+			if err := s.reload(); err != nil {
+				s.logger.Errorw("could not reload the configuration",
+					"error", err)
+			}
+		case <-quit:
+			running = false
+		}
+	}
+
 	// Let's handle the graceful shutdown of the http server
-	<-quit
 	s.logger.Info("shutting down the web server, please wait")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
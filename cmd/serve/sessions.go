@@ -1,17 +1,63 @@
 package serve
 
 import (
+	"errors"
+	nethttp "net/http"
+	"net/url"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
 	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_sessions"
 	"github.com/YuukanOO/seelf/internal/auth/app/login"
+	"github.com/YuukanOO/seelf/internal/auth/app/login_external"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	oidcStateCookie       = "seelf-oidc-state"
+	oidcStateLength       = 32
+	oidcStateMaxAge       = 600 // In seconds, time given to the user to authenticate on the provider
+	oidcCallbackPath      = "/api/v1/auth/oidc/callback"
+	oidcFailedCode        = "oidc_failed"
+	oidcInvalidStateCode  = "oidc_invalid_state"
+	signinPath            = "/signin/"
+	afterLoginRedirection = "/"
+)
+
+var errPasswordAuthDisabled = apperr.New("password_auth_disabled")
+
+type authMethods struct {
+	Password bool                `json:"password"`
+	OIDC     monad.Maybe[string] `json:"oidc"` // Name of the OpenID Connect provider if any
+}
+
+func (s *server) getAuthMethodsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var methods authMethods
+
+		methods.Password = s.options.PasswordAuthEnabled()
+
+		if s.oidc != nil {
+			methods.OIDC.Set(s.oidc.Name())
+		}
+
+		return http.Ok(ctx, methods)
+	})
+}
+
 func (s *server) createSessionHandler() gin.HandlerFunc {
 	return http.Bind(s, func(ctx *gin.Context, cmd login.Command) error {
-		sess := sessions.Default(ctx)
+		if !s.options.PasswordAuthEnabled() {
+			return errPasswordAuthDisabled
+		}
+
 		context := ctx.Request.Context()
 		uid, err := bus.Send(s.bus, context, cmd)
 
@@ -19,10 +65,8 @@ func (s *server) createSessionHandler() gin.HandlerFunc {
 			return err
 		}
 
-		// Everything went good, let's set the user cookie
-		sess.Set(userSessionKey, uid)
-
-		if err = sess.Save(); err != nil {
+		// Everything went good, let's open the session
+		if err = s.openSession(ctx, uid); err != nil {
 			return err
 		}
 
@@ -42,6 +86,14 @@ func (s *server) deleteSessionHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
 		sess := sessions.Default(ctx)
 
+		if sid, ok := sess.Get(sessionIDKey).(string); ok {
+			if _, err := bus.Send(s.bus, ctx.Request.Context(), revoke_session.Command{
+				ID: sid,
+			}); err != nil {
+				return err
+			}
+		}
+
 		sess.Clear()
 
 		if err := sess.Save(); err != nil {
@@ -51,3 +103,144 @@ func (s *server) deleteSessionHandler() gin.HandlerFunc {
 		return http.NoContent(ctx)
 	})
 }
+
+func (s *server) listSessionsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		sid, _ := sessions.Default(ctx).Get(sessionIDKey).(string)
+
+		data, err := bus.Send(s.bus, ctx.Request.Context(), get_sessions.Query{
+			CurrentID: sid,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, data)
+	})
+}
+
+func (s *server) revokeSessionHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), revoke_session.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// Open a new session for the given user and store its id in the session cookie.
+func (s *server) openSession(ctx *gin.Context, uid string) error {
+	sid, err := bus.Send(s.bus, ctx.Request.Context(), create_session.Command{
+		UserID: uid,
+		Device: ctx.Request.UserAgent(),
+		IP:     ctx.ClientIP(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sess := sessions.Default(ctx)
+	sess.Set(sessionIDKey, sid)
+
+	return sess.Save()
+}
+
+// Redirect the user to the OpenID Connect provider. A random state is kept in a
+// dedicated cookie since the session one is not sent back by the browser when
+// coming from the provider (SameSite=Strict).
+func (s *server) oidcLoginHandler(ctx *gin.Context) {
+	state, err := crypto.RandomKey[string](oidcStateLength)
+
+	if err != nil {
+		s.oidcFailed(ctx, err)
+		return
+	}
+
+	authURL, err := s.oidc.AuthCodeURL(ctx.Request.Context(), s.oidcRedirectURL(ctx), state)
+
+	if err != nil {
+		s.oidcFailed(ctx, err)
+		return
+	}
+
+	ctx.SetSameSite(nethttp.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, state, oidcStateMaxAge, oidcCallbackPath, "", s.options.IsSecure(), true)
+	ctx.Redirect(nethttp.StatusFound, authURL)
+}
+
+// Handle the user coming back from the OpenID Connect provider, logging it in (and
+// provisioning its account if needed).
+func (s *server) oidcCallbackHandler(ctx *gin.Context) {
+	state, _ := ctx.Cookie(oidcStateCookie)
+
+	ctx.SetSameSite(nethttp.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, "", -1, oidcCallbackPath, "", s.options.IsSecure(), true)
+
+	if state == "" || ctx.Query("state") != state {
+		s.oidcFailed(ctx, apperr.New(oidcInvalidStateCode))
+		return
+	}
+
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		s.oidcFailed(ctx, errors.New(providerErr+": "+ctx.Query("error_description")))
+		return
+	}
+
+	c := ctx.Request.Context()
+	identity, err := s.oidc.Exchange(c, s.oidcRedirectURL(ctx), ctx.Query("code"))
+
+	if err != nil {
+		s.oidcFailed(ctx, err)
+		return
+	}
+
+	uid, err := bus.Send(s.bus, c, login_external.Command{
+		Issuer:        identity.Issuer,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+	})
+
+	if err != nil {
+		s.oidcFailed(ctx, err)
+		return
+	}
+
+	if err = s.openSession(ctx, uid); err != nil {
+		s.oidcFailed(ctx, err)
+		return
+	}
+
+	ctx.Redirect(nethttp.StatusFound, afterLoginRedirection)
+}
+
+// Since the OpenID Connect flow relies on browser redirections, errors are reported by
+// redirecting the user to the sign in page with the error code.
+func (s *server) oidcFailed(ctx *gin.Context, err error) {
+	code := oidcFailedCode
+
+	if appErr, isAppErr := apperr.As[apperr.Error](err); isAppErr {
+		code = appErr.Code
+	} else {
+		s.logger.Errorw("openid connect authentication failed",
+			"correlation_id", bus.CorrelationID(ctx.Request.Context()).Get(""),
+			"error", err)
+	}
+
+	ctx.Redirect(nethttp.StatusFound, signinPath+"?"+url.Values{"error": {code}}.Encode())
+}
+
+func (s *server) oidcRedirectURL(ctx *gin.Context) string {
+	scheme := "http://"
+
+	if s.options.IsSecure() {
+		scheme = "https://"
+	}
+
+	return scheme + ctx.Request.Host + oidcCallbackPath
+}
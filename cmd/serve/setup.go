@@ -0,0 +1,131 @@
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/setup_account"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/health"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	setupTargetName   = "local"
+	setupCheckTimeout = 10 * time.Second
+)
+
+type (
+	setupStatus struct {
+		Required bool `json:"required"` // True until the administrator account has been created
+	}
+
+	setupUrlRequest struct {
+		Url string `json:"url"`
+	}
+)
+
+func (s *server) getSetupHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		_, err := s.usersReader.GetAdminUser(ctx.Request.Context())
+
+		if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return err
+		}
+
+		return http.Ok(ctx, setupStatus{Required: err != nil})
+	})
+}
+
+func (s *server) setupAccountHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd setup_account.Command) error {
+		context := ctx.Request.Context()
+		uid, err := bus.Send(s.bus, context, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		// Sign in the administrator right away so the next setup steps could be completed
+		if err = s.openSession(ctx, uid); err != nil {
+			return err
+		}
+
+		user, err := bus.Send(s.bus, context, get_profile.Query{
+			ID: uid,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, ctx, user, "/api/v1/profile")
+	})
+}
+
+func (s *server) setupChecksHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request setupUrlRequest) error {
+		var url domain.Url
+
+		if err := validate.Struct(validate.Of{
+			"url": validate.Value(request.Url, &url, domain.UrlFrom),
+		}); err != nil {
+			return err
+		}
+
+		host := url.Host()
+
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+
+		// Apps are exposed on subdomains of the target url so a random one is
+		// used to make sure a wildcard DNS record exists.
+		checker := health.NewChecker(setupCheckTimeout)
+		checker.Register("dns", health.Resolvable(host))
+		checker.Register("wildcard", health.Resolvable(fmt.Sprintf("seelf-setup-%d.%s", time.Now().UnixNano(), host)))
+		checker.Register("proxy", health.Reachable(&nethttp.Client{
+			// Any answer from the proxy is enough, redirections may point to apps not deployed yet
+			CheckRedirect: func(*nethttp.Request, []*nethttp.Request) error { return nethttp.ErrUseLastResponse },
+		}, url.Root().String()))
+
+		return http.Ok(ctx, checker.Run(ctx.Request.Context()))
+	})
+}
+
+func (s *server) setupTargetHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request setupUrlRequest) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, create_target.Command{
+			Name:     setupTargetName,
+			Url:      request.Url,
+			Provider: docker.Body{}, // Without host, the docker provider targets the local daemon
+		})
+
+		if err != nil {
+			return err
+		}
+
+		target, err := bus.Send(s.bus, ctx, get_target.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, target, "/api/v1/targets/%s", id)
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets_capacity"
 	"github.com/YuukanOO/seelf/internal/deployment/app/reconfigure_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_proxy_upgrade"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_target_cleanup"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_target"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
@@ -94,6 +97,46 @@ func (s *server) reconfigureTargetHandler() gin.HandlerFunc {
 	})
 }
 
+func (s *server) upgradeTargetProxyHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		_, err := bus.Send(s.bus, c.Request.Context(), request_proxy_upgrade.Command{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.NoContent(c)
+	})
+}
+
+// Filters are given in the query string so the request does not require a body.
+type redeployTargetFilters struct {
+	Labels []string `form:"labels"`
+}
+
+func (s *server) redeployTargetHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		var filters redeployTargetFilters
+
+		if err := c.ShouldBindQuery(&filters); err != nil {
+			return err
+		}
+
+		deployments, err := bus.Send(s.bus, c.Request.Context(), redeploy_target.Command{
+			ID:     c.Param("id"),
+			Labels: filters.Labels,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, deployments)
+	})
+}
+
 func (s *server) deleteTargetHandler() gin.HandlerFunc {
 	return http.Send(s, func(c *gin.Context) error {
 		_, err := bus.Send(s.bus, c.Request.Context(), request_target_cleanup.Command{
@@ -120,6 +163,18 @@ func (s *server) listTargetsHandler() gin.HandlerFunc {
 	})
 }
 
+func (s *server) getTargetsCapacityHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		targets, err := bus.Send(s.bus, c.Request.Context(), get_targets_capacity.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, targets)
+	})
+}
+
 func (s *server) getTargetByIDHandler() gin.HandlerFunc {
 	return http.Send(s, func(c *gin.Context) error {
 		target, err := bus.Send(s.bus, c.Request.Context(), get_target.Query{
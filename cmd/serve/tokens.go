@@ -0,0 +1,80 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/auth/app/create_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_tokens"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_token"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// Token data returned on creation, the only time its value is available.
+type createTokenResult struct {
+	get_token.Token
+	Value string `json:"token"`
+}
+
+func (s *server) listTokensHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_tokens.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) createTokenHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_token.Command) error {
+		ctx := c.Request.Context()
+
+		result, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_token.Query{
+			ID: result.ID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, createTokenResult{
+			Token: data,
+			Value: result.Token,
+		}, "/api/v1/tokens/%s", result.ID)
+	})
+}
+
+func (s *server) getTokenByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_token.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) revokeTokenHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), revoke_token.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
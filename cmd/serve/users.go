@@ -1,10 +1,20 @@
 package serve
 
 import (
+	"github.com/YuukanOO/seelf/internal/auth/app/delete_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/disable_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/enable_user"
 	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_users"
+	"github.com/YuukanOO/seelf/internal/auth/app/grant_role"
+	"github.com/YuukanOO/seelf/internal/auth/app/invite_user"
 	"github.com/YuukanOO/seelf/internal/auth/app/refresh_api_key"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_role"
 	"github.com/YuukanOO/seelf/internal/auth/app/update_user"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_email_preferences"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
 	"github.com/gin-gonic/gin"
@@ -68,3 +78,158 @@ func (s *server) getProfileHandler() gin.HandlerFunc {
 		return http.Ok(ctx, user)
 	})
 }
+
+func (s *server) getProfileNotificationsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		c := ctx.Request.Context()
+		preferences, err := bus.Send(s.bus, c, get_email_preferences.Query{
+			UserID: string(domain.CurrentUser(c).MustGet()),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, preferences)
+	})
+}
+
+func (s *server) updateProfileNotificationsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, cmd update_email_preferences.Command) error {
+		c := ctx.Request.Context()
+		cmd.UserID = string(domain.CurrentUser(c).MustGet())
+
+		if _, err := bus.Send(s.bus, c, cmd); err != nil {
+			return err
+		}
+
+		preferences, err := bus.Send(s.bus, c, get_email_preferences.Query{
+			UserID: cmd.UserID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, preferences)
+	})
+}
+
+func (s *server) inviteUserHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd invite_user.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_user.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/users/%s", id)
+	})
+}
+
+func (s *server) disableUserHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), disable_user.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) enableUserHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), enable_user.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) deleteUserHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_user.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) grantRoleHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd grant_role.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		if _, err := bus.Send(s.bus, ctx, cmd); err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_user.Query{
+			ID: cmd.ID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) revokeRoleHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), revoke_role.Command{
+			ID:           ctx.Param("id"),
+			ResourceType: ctx.Param("type"),
+			ResourceID:   ctx.Param("resource"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listUsersHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_users.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getUserByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_user.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
@@ -0,0 +1,120 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/notification/app/create_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook_deliveries"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhooks"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_webhook"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type listWebhookDeliveriesFilters struct {
+	Page int `form:"page"`
+}
+
+func (s *server) createWebhookHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_webhook.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_webhook.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/webhooks/%s", id)
+	})
+}
+
+func (s *server) updateWebhookHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_webhook.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_webhook.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deleteWebhookHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_webhook.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listWebhooksHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_webhooks.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getWebhookByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_webhook.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) listWebhookDeliveriesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request listWebhookDeliveriesFilters) error {
+		query := get_webhook_deliveries.Query{
+			WebhookID: c.Param("id"),
+		}
+
+		if request.Page != 0 {
+			query.Page.Set(request.Page)
+		}
+
+		data, err := bus.Send(s.bus, c.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
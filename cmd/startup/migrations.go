@@ -0,0 +1,24 @@
+package startup
+
+import (
+	authsqlite "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
+	deploymentsqlite "github.com/YuukanOO/seelf/internal/deployment/infra/sqlite"
+	notificationsqlite "github.com/YuukanOO/seelf/internal/notification/infra/sqlite"
+	bussqlite "github.com/YuukanOO/seelf/pkg/bus/sqlite"
+	httpsqlite "github.com/YuukanOO/seelf/pkg/http/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+// Migrations modules used by seelf in the order they are applied when the server starts.
+var MigrationsModules = []sqlite.MigrationsModule{
+	bussqlite.Migrations,
+	authsqlite.Migrations,
+	deploymentsqlite.Migrations,
+	notificationsqlite.Migrations,
+	httpsqlite.Migrations,
+}
+
+// Columns holding secrets encrypted at rest, re-encrypted when rotating keys.
+var EncryptedColumns = append(append([]sqlite.EncryptedColumn{},
+	deploymentsqlite.EncryptedColumns...),
+	notificationsqlite.EncryptedColumns...)
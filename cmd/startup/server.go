@@ -2,58 +2,170 @@ package startup
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/YuukanOO/seelf/cmd/version"
 	"github.com/YuukanOO/seelf/internal/auth/app/create_first_account"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	authinfra "github.com/YuukanOO/seelf/internal/auth/infra"
+	"github.com/YuukanOO/seelf/internal/deployment/app/backup_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/app/check_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_addon"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_artifacts"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_garbage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_target_capacity"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_traffic"
 	"github.com/YuukanOO/seelf/internal/deployment/app/configure_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/detect_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
+	"github.com/YuukanOO/seelf/internal/deployment/app/provision_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_app_archives"
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_trashed_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_addon_backups"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_garbage_collections"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_proxy_upgrades"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_dns_records"
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_gitops"
+	"github.com/YuukanOO/seelf/internal/deployment/app/upgrade_proxy"
 	deploymentdomain "github.com/YuukanOO/seelf/internal/deployment/domain"
 	deploymentinfra "github.com/YuukanOO/seelf/internal/deployment/infra"
+	"github.com/YuukanOO/seelf/internal/notification/app/check_certificates"
+	"github.com/YuukanOO/seelf/internal/notification/app/deliver_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_incident"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_uptime"
+	"github.com/YuukanOO/seelf/internal/notification/app/send_email"
+	notificationinfra "github.com/YuukanOO/seelf/internal/notification/infra"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/bus/memory"
 	bussqlite "github.com/YuukanOO/seelf/pkg/bus/sqlite"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/health"
+	httputils "github.com/YuukanOO/seelf/pkg/http"
+	httpsqlite "github.com/YuukanOO/seelf/pkg/http/sqlite"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/realtime"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/telemetry"
+)
+
+const (
+	eventPoolSize             = 2
+	eventPoolCapacity         = 256
+	jobProcessedEvent         = "job_processed"
+	notificationRunnersCount  = 2
+	certificatesCheckInterval = 24 * time.Hour
+	addonsBackupCheckInterval = 10 * time.Minute
+	capacityCollectInterval   = 15 * time.Minute
+	appArchivesPurgeInterval  = time.Hour
+	trashedAppsPurgeInterval  = time.Hour
+	idempotencyPurgeInterval  = time.Hour
+	healthCheckTimeout        = 5 * time.Second
+	minSchedulerHeartbeatAge  = time.Minute
 )
 
 type (
 	// Represents a services root containing every services used by a server.
 	ServerRoot interface {
 		Cleanup() error
-		Bus() bus.Dispatcher
+		Bus() bus.Dispatcher // Dispatcher to use for users requests, commands sent through it are audited
 		Logger() log.Logger
 		UsersReader() domain.UsersReader
 		ScheduledJobsStore() bus.ScheduledJobsStore
+		IdempotencyStore() httputils.IdempotencyStore
+		Events() *realtime.Hub
+		Health() *health.Checker // Checks needed by the server to be ready to handle requests
+		Reload() error           // Reload the configuration and apply settings which could be changed while running
+		Maintenance() MaintenanceStatus
+		SetMaintenance(bool) MaintenanceStatus // Enable or disable the maintenance mode, pausing or resuming background jobs
+		WorkerStatus(context.Context) (WorkerStatus, error)
 	}
 
 	ServerOptions interface {
 		deploymentinfra.Options
+		notificationinfra.Options
+		authinfra.Options
+		telemetry.Options
 
 		AppExposedUrl() monad.Maybe[deploymentdomain.Url]
 		DefaultEmail() string
 		DefaultPassword() string
 		RunnersPollInterval() time.Duration
-		RunnersDeploymentCount() int
 		RunnersCleanupCount() int
 		ConnectionString() string
+		DatabaseReadPoolSize() int
+		DatabaseCheckpointInterval() time.Duration
+		Keyring() crypto.Keyring                 // Encrypts secrets stored in the database
+		ArtifactsQuota() int64                   // In bytes, 0 for no limit
+		AppArtifactsQuota() int64                // In bytes, 0 for no limit
+		ArtifactsCollectInterval() time.Duration // 0 to disable the artifacts collection
+		ResourceUsageInterval() time.Duration    // 0 to disable the resource usage collection
+		ResourceUsageRetention() time.Duration   // 0 to keep resource usage forever
+		IncidentsInterval() time.Duration        // 0 to disable the incidents detection
+		TrafficInterval() time.Duration          // 0 to disable the traffic collection
+		TrafficRetention() time.Duration         // 0 to keep traffic samples forever
+		MonitorsInterval() time.Duration         // 0 to disable uptime checks
+		AddonsBackupInterval() time.Duration     // 0 to disable scheduled add-on backups
+		ProxyUpgradeInterval() time.Duration     // 0 to disable automatic proxy upgrades
+		TargetsGCInterval() time.Duration        // 0 to disable the garbage collection on targets
+		DnsSyncInterval() time.Duration          // 0 to disable the periodic check of DNS records
+		Reload() error                           // Reload settings which could be changed while running
+	}
+
+	// Status of the maintenance mode.
+	MaintenanceStatus struct {
+		Enabled     bool                   `json:"enabled"`
+		Since       monad.Maybe[time.Time] `json:"since"`
+		RunningJobs int                    `json:"running_jobs"` // Jobs still running, it's safe to proceed once there is none left
+	}
+
+	// Diagnostics of the background jobs worker built from its persisted heartbeat.
+	WorkerStatus struct {
+		Heartbeat monad.Maybe[bus.WorkerHeartbeat] `json:"heartbeat"` // None if the worker has never run
+		Threshold time.Duration                    `json:"threshold"` // Maximum age of the heartbeat and the last poll before a warning is raised
+		Stalled   bool                             `json:"stalled"`   // The worker has not been seen alive within the threshold
+		Warning   monad.Maybe[string]              `json:"warning"`
+	}
+
+	// Data of the realtime event sent when a job has been processed.
+	processedJob struct {
+		ID    string              `json:"id"`
+		Name  string              `json:"name"`
+		Error monad.Maybe[string] `json:"error"`
 	}
 
 	serverRoot struct {
-		options        ServerOptions
-		bus            bus.Bus
-		logger         log.Logger
-		db             *sqlite.Database
-		usersReader    domain.UsersReader
-		schedulerStore bus.ScheduledJobsStore
-		scheduler      bus.RunnableScheduler
+		options           ServerOptions
+		bus               bus.Bus
+		audited           bus.Dispatcher
+		logger            log.Logger
+		db                *sqlite.Database
+		usersReader       domain.UsersReader
+		schedulerStore    bus.ScheduledJobsStore
+		idempotencyStore  httpsqlite.IdempotencyStore
+		scheduler         bus.RunnableScheduler
+		pool              *event.Pool
+		events            *realtime.Hub
+		health            *health.Checker
+		shutdownTelemetry telemetry.ShutdownFunc
+		done              chan struct{}
+		wg                sync.WaitGroup
+		maintenanceMu     sync.RWMutex
+		maintenanceSince  monad.Maybe[time.Time]
 	}
 )
 
@@ -65,9 +177,25 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 		logger:  logger,
 	}
 
-	s.bus = memory.NewBus()
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), s.options, version.Current())
 
-	db, err := sqlite.Open(s.options.ConnectionString(), s.logger, s.bus)
+	if err != nil {
+		return nil, err
+	}
+
+	s.shutdownTelemetry = shutdownTelemetry
+
+	s.bus = memory.NewBus(bus.Instrument)
+	s.pool = event.NewPool(s.logger.Named("event"), eventPoolSize, eventPoolCapacity)
+	s.events = realtime.NewHub()
+	s.health = health.NewChecker(healthCheckTimeout)
+
+	db, err := sqlite.Open(s.options.ConnectionString(), s.logger.Named("database"), s.bus,
+		sqlite.WithReadPool(s.options.DatabaseReadPoolSize()),
+		sqlite.WithCheckpointInterval(s.options.DatabaseCheckpointInterval()),
+		sqlite.WithEventMiddlewares(event.Logger(s.logger.Named("event"))),
+		sqlite.WithKeyring(s.options.Keyring()),
+	)
 
 	if err != nil {
 		return nil, err
@@ -75,13 +203,24 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 
 	s.db = db
 
+	// Refuse to go further if the database has been migrated by a newer seelf version
+	if err = s.db.EnsureSchemaCompatible(MigrationsModules...); err != nil {
+		return nil, err
+	}
+
 	s.schedulerStore = bussqlite.NewScheduledJobsStore(s.db)
 
 	if err = s.schedulerStore.Setup(); err != nil {
 		return nil, err
 	}
 
-	s.scheduler = bus.NewScheduler(s.schedulerStore, s.logger, s.bus, s.options.RunnersPollInterval(),
+	s.idempotencyStore = httpsqlite.NewIdempotencyStore(s.db)
+
+	if err = s.idempotencyStore.Setup(); err != nil {
+		return nil, err
+	}
+
+	s.scheduler = bus.NewScheduler(s.schedulerStore, s.logger.Named("scheduler"), s.bus, s.options.RunnersPollInterval(),
 		bus.WorkerGroup{
 			Size:     s.options.RunnersDeploymentCount(),
 			Messages: []string{deploy.Command{}.Name_()},
@@ -92,40 +231,99 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 				cleanup_app.Command{}.Name_(),
 				delete_app.Command{}.Name_(),
 				configure_target.Command{}.Name_(),
+				upgrade_proxy.Command{}.Name_(),
+				collect_garbage.Command{}.Name_(),
+				sync_dns_records.Command{}.Name_(),
 				cleanup_target.Command{}.Name_(),
 				delete_target.Command{}.Name_(),
+				provision_addon.Command{}.Name_(),
+				cleanup_addon.Command{}.Name_(),
+				backup_addon.Command{}.Name_(),
+				restore_addon_backup.Command{}.Name_(),
+				export_app.Command{}.Name_(),
+			},
+		},
+		bus.WorkerGroup{
+			Size: notificationRunnersCount,
+			Messages: []string{
+				deliver_webhook.Command{}.Name_(),
+				notify_channel.Command{}.Name_(),
+				notify_incident.Command{}.Name_(),
+				notify_uptime.Command{}.Name_(),
+				send_email.Command{}.Name_(),
 			},
 		},
 	)
 
+	s.health.Register("database", s.db.Ping)
+	s.health.Register("scheduler", func(ctx context.Context) error {
+		return health.Heartbeat(s.scheduler.Heartbeat, s.schedulerHeartbeatMaxAge())(ctx)
+	})
+
 	// Setup auth infrastructure
-	if s.usersReader, err = authinfra.Setup(s.logger, s.db, s.bus); err != nil {
+	if s.usersReader, err = authinfra.Setup(s.options, s.logger.Named("auth"), s.db, s.bus); err != nil {
 		return nil, err
 	}
 
+	s.audited = authinfra.Audited(s.bus, s.logger.Named("auth"), s.db)
+
 	// Setups deployment infrastructure
 	if err = deploymentinfra.Setup(
 		s.options,
-		s.logger,
+		s.logger.Named("deployment"),
 		s.db,
 		s.bus,
 		s.scheduler,
+		s.pool,
+		s.events,
+		s.health,
 	); err != nil {
 		return nil, err
 	}
 
-	// Create the first account if needed
+	// Setups notification infrastructure
+	if err = notificationinfra.Setup(
+		s.options,
+		s.logger.Named("notification"),
+		s.db,
+		s.bus,
+		s.scheduler,
+	); err != nil {
+		return nil, err
+	}
+
+	// Only admins are interested in background jobs
+	event.OnAsync(s.bus, s.pool, func(_ context.Context, evt bus.JobProcessed) error {
+		s.events.Publish(realtime.Event{
+			Type: jobProcessedEvent,
+			Data: processedJob{
+				ID:    evt.ID,
+				Name:  evt.Name,
+				Error: evt.Error,
+			},
+		}, domain.HasAdminRights)
+		return nil
+	})
+
+	// Create the first account if needed, if no credentials were given, the setup should
+	// be completed from the web interface.
 	uid, err := bus.Send(s.bus, context.Background(), create_first_account.Command{
 		Email:    options.DefaultEmail(),
 		Password: options.DefaultPassword(),
 	})
 
-	if err != nil {
+	setupRequired := errors.Is(err, create_first_account.ErrAdminAccountRequired)
+
+	if err != nil && !setupRequired {
 		return nil, err
 	}
 
+	if setupRequired {
+		s.logger.Warn("no account exists yet, complete the setup from the web interface or set the SEELF_ADMIN_EMAIL and SEELF_ADMIN_PASSWORD environment variables")
+	}
+
 	// Create the target needed to expose seelf itself and manage certificates if needed
-	if exposedUrl, isSet := s.options.AppExposedUrl().TryGet(); isSet {
+	if exposedUrl, isSet := s.options.AppExposedUrl().TryGet(); isSet && !setupRequired {
 		container := exposedUrl.User().Get("")
 
 		s.logger.Infow("exposing seelf container using the local target, creating it if needed, the container may restart once done",
@@ -139,20 +337,572 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 		}
 	}
 
+	s.pool.Start()
 	s.scheduler.Start()
 
+	s.done = make(chan struct{})
+
+	// Certificates expiry warnings are sent by email so it's useless to check them otherwise
+	if s.options.SMTP().HasValue() {
+		s.wg.Add(1)
+		go s.checkCertificates(certificatesCheckInterval)
+	}
+
+	if interval := s.options.ArtifactsCollectInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.collectArtifacts(interval)
+	}
+
+	if interval := s.options.ResourceUsageInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.collectResourceUsage(interval)
+	}
+
+	s.wg.Add(1)
+	go s.collectTargetCapacity(capacityCollectInterval)
+
+	if interval := s.options.IncidentsInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.detectIncidents(interval)
+	}
+
+	if interval := s.options.TrafficInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.collectTraffic(interval)
+	}
+
+	if interval := s.options.MonitorsInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.checkMonitors(interval)
+	}
+
+	if interval := s.options.AddonsBackupInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.backupAddons(interval)
+	}
+
+	s.wg.Add(1)
+	go s.purgeAppArchives(appArchivesPurgeInterval)
+
+	s.wg.Add(1)
+	go s.purgeTrashedApps(trashedAppsPurgeInterval)
+
+	s.wg.Add(1)
+	go s.purgeIdempotencyKeys(idempotencyPurgeInterval)
+
+	if interval := s.options.ProxyUpgradeInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.upgradeProxies(interval)
+	}
+
+	if interval := s.options.TargetsGCInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.collectTargetsGarbage(interval)
+	}
+
+	if interval := s.options.DnsSyncInterval(); interval > 0 {
+		s.wg.Add(1)
+		go s.syncDnsRecords(interval)
+	}
+
+	// Apps created from the GitOps repository need an owner so it waits for the setup
+	if gitOps, isSet := s.options.GitOps().TryGet(); isSet && gitOps.Interval > 0 && !setupRequired {
+		s.wg.Add(1)
+		go s.syncGitOps(gitOps.Interval, domain.UserID(uid))
+	}
+
 	return s, nil
 }
 
 func (s *serverRoot) Cleanup() error {
 	s.logger.Debug("cleaning server services")
 
+	close(s.done)
+	s.wg.Wait()
+
 	s.scheduler.Stop()
+	s.pool.Stop()
+
+	if err := s.shutdownTelemetry(context.Background()); err != nil {
+		s.logger.Errorw("could not flush telemetry data",
+			"error", err)
+	}
 
 	return s.db.Close()
 }
 
-func (s *serverRoot) Bus() bus.Dispatcher                        { return s.bus }
-func (s *serverRoot) Logger() log.Logger                         { return s.logger }
-func (s *serverRoot) UsersReader() domain.UsersReader            { return s.usersReader }
-func (s *serverRoot) ScheduledJobsStore() bus.ScheduledJobsStore { return s.schedulerStore }
+func (s *serverRoot) Reload() error {
+	if err := s.options.Reload(); err != nil {
+		return err
+	}
+
+	s.scheduler.SetPollInterval(s.options.RunnersPollInterval())
+
+	return nil
+}
+
+func (s *serverRoot) Maintenance() MaintenanceStatus {
+	s.maintenanceMu.RLock()
+	defer s.maintenanceMu.RUnlock()
+
+	return MaintenanceStatus{
+		Enabled:     s.maintenanceSince.HasValue(),
+		Since:       s.maintenanceSince,
+		RunningJobs: s.scheduler.RunningJobs(),
+	}
+}
+
+func (s *serverRoot) SetMaintenance(enabled bool) MaintenanceStatus {
+	s.maintenanceMu.Lock()
+
+	if enabled && !s.maintenanceSince.HasValue() {
+		s.maintenanceSince.Set(time.Now().UTC())
+		s.scheduler.Pause()
+		s.logger.Info("maintenance mode enabled")
+	} else if !enabled && s.maintenanceSince.HasValue() {
+		s.maintenanceSince.Unset()
+		s.scheduler.Resume()
+		s.logger.Info("maintenance mode disabled")
+	}
+
+	s.maintenanceMu.Unlock()
+
+	return s.Maintenance()
+}
+
+func (s *serverRoot) WorkerStatus(ctx context.Context) (WorkerStatus, error) {
+	status := WorkerStatus{Threshold: s.schedulerHeartbeatMaxAge()}
+	heartbeat, err := s.schedulerStore.GetHeartbeat(ctx)
+
+	if errors.Is(err, apperr.ErrNotFound) {
+		status.Stalled = true
+		status.Warning.Set("the worker has never polled jobs, it may not be running")
+		return status, nil
+	}
+
+	if err != nil {
+		return status, err
+	}
+
+	status.Heartbeat.Set(heartbeat)
+
+	switch {
+	case time.Since(heartbeat.BeatAt) > status.Threshold:
+		status.Stalled = true
+		status.Warning.Set(fmt.Sprintf("the worker has not been seen alive for more than %s, it may be stuck or not running", status.Threshold))
+	case heartbeat.Paused:
+		status.Warning.Set("the worker is paused by the maintenance mode, pending jobs will be processed once it is disabled")
+	case time.Since(heartbeat.LastPoll.Get(heartbeat.StartedAt)) > status.Threshold:
+		status.Warning.Set(fmt.Sprintf("the worker has not polled jobs for more than %s while %d jobs are running, pending jobs are waiting for a free runner",
+			status.Threshold, heartbeat.RunningJobs))
+	}
+
+	return status, nil
+}
+
+// The scheduler beats at least once per poll interval, leave it some slack.
+func (s *serverRoot) schedulerHeartbeatMaxAge() time.Duration {
+	return max(5*s.options.RunnersPollInterval(), minSchedulerHeartbeatAge)
+}
+
+// Periodically check certificates of exposed hosts to warn admins before they expire.
+func (s *serverRoot) checkCertificates(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if _, err := bus.Send(s.bus, context.Background(), check_certificates.Command{}); err != nil {
+				s.logger.Errorw("could not check certificates",
+					"error", err)
+			}
+		}
+	}
+}
+
+// Periodically compute the disk space used by artifacts and enforce quotas. It runs
+// right away so the usage is known soon after startup and is skipped while in maintenance.
+func (s *serverRoot) collectArtifacts(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), collect_artifacts.Command{
+				Quota:    s.options.ArtifactsQuota(),
+				AppQuota: s.options.AppArtifactsQuota(),
+			}); err != nil {
+				s.logger.Errorw("could not collect artifacts",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically collect resources consumed by apps on targets. Like the artifacts
+// collection, it runs right away and is skipped while in maintenance.
+func (s *serverRoot) collectResourceUsage(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), collect_resource_usage.Command{
+				Retention: s.options.ResourceUsageRetention(),
+			}); err != nil {
+				s.logger.Errorw("could not collect resource usage",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically collect the capacity of targets so the best place for a new app could
+// be found. Like the resource usage collection, it runs right away and is skipped while
+// in maintenance.
+func (s *serverRoot) collectTargetCapacity(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), collect_target_capacity.Command{}); err != nil {
+				s.logger.Errorw("could not collect target capacity",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically look for incidents which happened to apps containers since the last check.
+// Incidents occurring while in maintenance are reported once it has been disabled.
+func (s *serverRoot) detectIncidents(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now().UTC()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		until := time.Now().UTC()
+
+		if _, err := bus.Send(s.bus, context.Background(), detect_incidents.Command{
+			Since: since,
+			Until: until,
+		}); err != nil {
+			s.logger.Errorw("could not detect incidents",
+				"error", err)
+		}
+
+		since = until
+	}
+}
+
+// Periodically collect requests served by the proxy of targets since the last collection.
+// Like incidents, requests served while in maintenance are collected once it has been disabled.
+func (s *serverRoot) collectTraffic(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now().UTC()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		until := time.Now().UTC()
+
+		if _, err := bus.Send(s.bus, context.Background(), collect_traffic.Command{
+			Since:     since,
+			Until:     until,
+			Retention: s.options.TrafficRetention(),
+		}); err != nil {
+			s.logger.Errorw("could not collect traffic",
+				"error", err)
+		}
+
+		since = until
+	}
+}
+
+// Periodically check public urls of monitored apps. Each monitor has its own interval so
+// this one only determines how often due monitors are looked for.
+func (s *serverRoot) checkMonitors(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		if _, err := bus.Send(s.bus, context.Background(), check_monitors.Command{}); err != nil {
+			s.logger.Errorw("could not check monitors",
+				"error", err)
+		}
+	}
+}
+
+// Periodically start backups of add-ons which have not been backed up for the given
+// interval. Due add-ons are looked for more often so a restart does not delay them
+// too much.
+func (s *serverRoot) backupAddons(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(min(interval, addonsBackupCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		if _, err := bus.Send(s.bus, context.Background(), queue_addon_backups.Command{
+			Interval: interval,
+		}); err != nil {
+			s.logger.Errorw("could not queue add-on backups",
+				"error", err)
+		}
+	}
+}
+
+// Periodically remove archives of deleted apps whose grace period has ended.
+func (s *serverRoot) purgeAppArchives(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		if _, err := bus.Send(s.bus, context.Background(), purge_app_archives.Command{}); err != nil {
+			s.logger.Errorw("could not purge app archives",
+				"error", err)
+		}
+	}
+}
+
+// Periodically remove trashed apps whose retention has ended.
+func (s *serverRoot) purgeTrashedApps(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if s.Maintenance().Enabled {
+			continue
+		}
+
+		if _, err := bus.Send(s.bus, context.Background(), purge_trashed_apps.Command{}); err != nil {
+			s.logger.Errorw("could not purge trashed apps",
+				"error", err)
+		}
+	}
+}
+
+// Periodically remove idempotency keys which have expired.
+func (s *serverRoot) purgeIdempotencyKeys(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.idempotencyStore.Purge(context.Background(), time.Now().UTC()); err != nil {
+			s.logger.Errorw("could not purge idempotency keys",
+				"error", err)
+		}
+	}
+}
+
+// Periodically request the upgrade of targets proxies which are not running the version
+// supported by this release. It runs right away so proxies are upgraded along with seelf
+// and is skipped while in maintenance.
+func (s *serverRoot) upgradeProxies(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), queue_proxy_upgrades.Command{}); err != nil {
+				s.logger.Errorw("could not queue proxy upgrades",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically request a garbage collection on every ready target to remove resources
+// which are not used anymore. It runs right away and is skipped while in maintenance.
+func (s *serverRoot) collectTargetsGarbage(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), queue_garbage_collections.Command{}); err != nil {
+				s.logger.Errorw("could not queue garbage collections",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically reconcile DNS records of exposed apps to restore the ones modified or
+// removed outside of seelf. It runs right away and is skipped while in maintenance.
+func (s *serverRoot) syncDnsRecords(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, context.Background(), sync_dns_records.Command{}); err != nil {
+				s.logger.Errorw("could not sync dns records",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Periodically reconcile targets and apps with the GitOps repository when new commits
+// have been pushed. It runs right away and is skipped while in maintenance.
+func (s *serverRoot) syncGitOps(interval time.Duration, uid domain.UserID) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := domain.WithUserID(context.Background(), uid)
+
+	for {
+		if !s.Maintenance().Enabled {
+			if _, err := bus.Send(s.bus, ctx, sync_gitops.Command{}); err != nil {
+				s.logger.Errorw("could not sync the gitops repository",
+					"error", err)
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *serverRoot) Bus() bus.Dispatcher                          { return s.audited }
+func (s *serverRoot) Logger() log.Logger                           { return s.logger }
+func (s *serverRoot) UsersReader() domain.UsersReader              { return s.usersReader }
+func (s *serverRoot) ScheduledJobsStore() bus.ScheduledJobsStore   { return s.schedulerStore }
+func (s *serverRoot) IdempotencyStore() httputils.IdempotencyStore { return s.idempotencyStore }
+func (s *serverRoot) Events() *realtime.Hub                        { return s.events }
+func (s *serverRoot) Health() *health.Checker                      { return s.health }
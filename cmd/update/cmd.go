@@ -0,0 +1,175 @@
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/YuukanOO/seelf/cmd/version"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/selfupdate"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+const (
+	releasesURL       = "https://api.github.com/repos/YuukanOO/seelf/releases/latest"
+	binaryName        = "seelf"
+	snapshotExtension = ".pre-update"
+)
+
+// Base64 encoded ed25519 public key used to verify released binaries, set at build time with
+// -ldflags "-X github.com/YuukanOO/seelf/cmd/update.publicKey=<key>". When empty, updates are disabled.
+var publicKey string
+
+type Options interface {
+	ConnectionString() string
+	DatabasePath() string
+}
+
+// Returns the update command which replaces the current binary by the latest release.
+func Command(logger log.Logger) *cobra.Command {
+	var check bool
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update seelf to the latest release, pending migrations are applied on the next start",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			updater, err := newUpdater()
+
+			if err != nil {
+				return err
+			}
+
+			release, err := updater.Latest(cmd.Context())
+
+			if err != nil {
+				return err
+			}
+
+			current := version.Current()
+
+			if !selfupdate.IsNewer(release.Version, current) {
+				fmt.Fprintf(cmd.OutOrStdout(), "seelf is up to date (%s)\n", current)
+				return nil
+			}
+
+			if check {
+				fmt.Fprintf(cmd.OutOrStdout(), "seelf %s is available (current %s)\n", release.Version, current)
+				return nil
+			}
+
+			if err = updater.Install(cmd.Context(), release); err != nil {
+				return err
+			}
+
+			logger.Infow("seelf updated, restart it to use the new version",
+				"from", current,
+				"to", release.Version)
+
+			return nil
+		},
+	}
+
+	updateCmd.Flags().BoolVar(&check, "check", false, "only check if a new release is available")
+
+	return updateCmd
+}
+
+// Calls run and, if a freshly installed version is starting for the first time, makes
+// sure it can be rolled back along with the database if run fails before calling started.
+// The run function should call started once the version is known to work, such as when
+// the server listens, and only return when it stops.
+func WithRollback(opts Options, logger log.Logger, run func(started func()) error) error {
+	updater, err := newUpdater()
+
+	if err != nil || !updater.Pending() {
+		return run(func() {})
+	}
+
+	var (
+		dbPath   = opts.DatabasePath()
+		snapshot = dbPath + snapshotExtension
+	)
+
+	// If a previous attempt has crashed before being able to rollback, the snapshot already exists
+	// and should be kept since the database may have been partially migrated.
+	if _, err = os.Stat(snapshot); os.IsNotExist(err) {
+		if err = snapshotDatabase(opts, logger, snapshot); err != nil {
+			return err
+		}
+	}
+
+	var confirmed bool
+
+	err = run(func() {
+		confirmed = true
+
+		if err := errors.Join(updater.Confirm(), os.Remove(snapshot)); err != nil {
+			logger.Errorw("could not confirm the updated version", "error", err)
+			return
+		}
+
+		logger.Info("updated version started successfully")
+	})
+
+	if err != nil && !confirmed {
+		logger.Errorw("updated version failed to start, rolling back to the previous one",
+			"error", err)
+
+		return errors.Join(err, restoreDatabase(dbPath, snapshot), updater.Rollback())
+	}
+
+	return err
+}
+
+func newUpdater() (*selfupdate.Updater, error) {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exe, err := selfupdate.Executable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return selfupdate.New(releasesURL, binaryName, ed25519.PublicKey(key), exe, version.Current()), nil
+}
+
+func snapshotDatabase(opts Options, logger log.Logger, path string) error {
+	// The database may not exist yet if the update has been installed before the first start
+	if _, err := os.Stat(opts.DatabasePath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := sqlite.Open(opts.ConnectionString(), logger, memory.NewBus())
+
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	return db.Snapshot(context.Background(), path)
+}
+
+func restoreDatabase(path, snapshot string) error {
+	if _, err := os.Stat(snapshot); os.IsNotExist(err) {
+		return nil
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(snapshot, path)
+}
@@ -75,6 +75,26 @@ export default defineConfig({
             text: "Jobs",
             link: "/reference/jobs",
           },
+          {
+            text: "Users",
+            link: "/reference/users",
+          },
+          {
+            text: "Webhooks",
+            link: "/reference/webhooks",
+          },
+          {
+            text: "Channels",
+            link: "/reference/channels",
+          },
+          {
+            text: "Federation",
+            link: "/reference/federation",
+          },
+          {
+            text: "DNS",
+            link: "/reference/dns",
+          },
           {
             text: "API",
             link: "/reference/api",
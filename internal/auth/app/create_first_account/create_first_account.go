@@ -80,6 +80,9 @@ func Handler(
 			return "", err
 		}
 
+		// The first account is the one managing other users.
+		user.HasAdminRights(true)
+
 		if err = writer.Write(ctx, &user); err != nil {
 			return "", err
 		}
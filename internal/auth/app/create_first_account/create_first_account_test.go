@@ -73,4 +73,21 @@ func Test_CreateFirstAccount(t *testing.T) {
 		testutil.IsNil(t, err)
 		testutil.NotEquals(t, "", uid)
 	})
+
+	t.Run("should grant admin rights to the first user account", func(t *testing.T) {
+		store := memory.NewUsersStore()
+		uc := create_first_account.Handler(store, store, hasher, keygen)
+
+		uid, err := uc(ctx, create_first_account.Command{
+			Email:    "admin@example.com",
+			Password: "admin",
+		})
+
+		testutil.IsNil(t, err)
+
+		user, err := store.GetByID(ctx, domain.UserID(uid))
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, user.IsAdmin())
+	})
 }
@@ -0,0 +1,34 @@
+package create_session
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Open a new session for a user who has just logged in.
+type Command struct {
+	bus.Command[string]
+
+	UserID string `json:"-"`
+	Device string `json:"-"`
+	IP     string `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.create_session" }
+
+func Handler(
+	writer domain.SessionsWriter,
+	policy domain.SessionPolicy,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		session := domain.NewSession(domain.UserID(cmd.UserID), cmd.Device, cmd.IP, policy)
+
+		if err := writer.Write(ctx, &session); err != nil {
+			return "", err
+		}
+
+		return string(session.ID()), nil
+	}
+}
@@ -0,0 +1,35 @@
+package create_session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CreateSession(t *testing.T) {
+	t.Run("should open a session expiring according to the policy", func(t *testing.T) {
+		store := memory.NewSessionsStore()
+		uc := create_session.Handler(store, domain.SessionPolicy{Lifetime: time.Hour})
+
+		id, err := uc(context.Background(), create_session.Command{
+			UserID: "auser",
+			Device: "Mozilla/5.0",
+			IP:     "127.0.0.1",
+		})
+
+		testutil.IsNil(t, err)
+
+		session, err := store.GetByID(context.Background(), domain.SessionID(id))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "auser", session.UserID())
+		testutil.Equals(t, "Mozilla/5.0", session.Device())
+		testutil.Equals(t, "127.0.0.1", session.IP())
+		testutil.IsTrue(t, session.ExpiresAt().HasValue())
+	})
+}
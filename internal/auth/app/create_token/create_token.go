@@ -0,0 +1,67 @@
+package create_token
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type (
+	// Create a new API token for the current user. The token value is only returned
+	// once, only its hash is stored.
+	Command struct {
+		bus.Command[Result]
+
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+
+	Result struct {
+		ID    string
+		Token string
+	}
+)
+
+func (Command) Name_() string { return "auth.command.create_token" }
+
+func (Command) AuditResource(result any) string {
+	r, _ := result.(Result)
+	return r.ID
+}
+
+func Handler(
+	writer domain.TokensWriter,
+	generator domain.KeyGenerator,
+	hasher domain.TokenHasher,
+) bus.RequestHandler[Result, Command] {
+	return func(ctx context.Context, cmd Command) (Result, error) {
+		var scopes domain.Scopes
+
+		if err := validate.Struct(validate.Of{
+			"name":   validate.Field(cmd.Name, strings.Required),
+			"scopes": validate.Value(cmd.Scopes, &scopes, domain.ScopesFrom),
+		}); err != nil {
+			return Result{}, err
+		}
+
+		value, err := generator.Generate()
+
+		if err != nil {
+			return Result{}, err
+		}
+
+		token := domain.NewToken(cmd.Name, hasher.Hash(value), scopes, domain.CurrentUser(ctx).MustGet())
+
+		if err = writer.Write(ctx, &token); err != nil {
+			return Result{}, err
+		}
+
+		return Result{
+			ID:    string(token.ID()),
+			Token: string(value),
+		}, nil
+	}
+}
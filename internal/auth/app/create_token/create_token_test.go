@@ -0,0 +1,58 @@
+package create_token_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_token"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreateToken(t *testing.T) {
+	hasher := crypto.NewTokenHasher()
+	sut := func() (bus.RequestHandler[create_token.Result, create_token.Command], memory.TokensStore) {
+		store := memory.NewTokensStore()
+		return create_token.Handler(store, crypto.NewKeyGenerator(), hasher), store
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(domain.WithUserID(context.Background(), "auser"), create_token.Command{
+			Scopes: []string{"manage:anapp"},
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["name"])
+		testutil.ErrorIs(t, domain.ErrInvalidScope, validationErr["scopes"])
+	})
+
+	t.Run("should create a token for the current user", func(t *testing.T) {
+		uc, store := sut()
+		ctx := domain.WithUserID(context.Background(), "auser")
+
+		result, err := uc(ctx, create_token.Command{
+			Name:   "ci",
+			Scopes: []string{"deploy:anapp"},
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", result.Token)
+
+		token, err := store.GetByHash(ctx, hasher.Hash(domain.APIKey(result.Token)))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, result.ID, string(token.ID()))
+		testutil.Equals(t, "ci", token.Name())
+		testutil.Equals(t, "auser", token.CreatedBy())
+		testutil.DeepEquals(t, domain.Scopes{"deploy:anapp"}, token.Scopes())
+	})
+}
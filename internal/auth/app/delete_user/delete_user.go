@@ -0,0 +1,53 @@
+package delete_user
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Delete a user account. Users still owning resources could not be deleted and
+// should be disabled instead.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "auth.command.delete_user" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !domain.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		user, err := reader.GetByID(ctx, domain.UserID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if domain.CurrentUser(ctx).Get("") == user.ID() {
+			return bus.Unit, domain.ErrCannotManageYourself
+		}
+
+		ownsResources, err := reader.HasOwnedResources(ctx, user.ID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = user.Delete(ownsResources); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &user)
+	}
+}
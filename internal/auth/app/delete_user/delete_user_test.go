@@ -0,0 +1,66 @@
+package delete_user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/delete_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteUser(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[bus.UnitType, delete_user.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return delete_user.Handler(store, store), store
+	}
+
+	newUsers := func() (domain.User, domain.User) {
+		admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+		admin.HasAdminRights(true)
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+
+		return admin, john
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), delete_user.Command{
+			ID: string(admin.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should prevent an admin from deleting itself", func(t *testing.T) {
+		admin, _ := newUsers()
+		uc, _ := sut(&admin)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), delete_user.Command{
+			ID: string(admin.ID()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrCannotManageYourself, err)
+	})
+
+	t.Run("should delete the user", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, store := sut(&admin, &john)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		_, err := uc(ctx, delete_user.Command{
+			ID: string(john.ID()),
+		})
+
+		testutil.IsNil(t, err)
+
+		_, err = store.GetByID(ctx, john.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,45 @@
+package disable_user
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Disable a user account, preventing it from logging in or using its API key.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "auth.command.disable_user" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !domain.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		user, err := reader.GetByID(ctx, domain.UserID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Prevent an admin from locking itself out
+		if domain.CurrentUser(ctx).Get("") == user.ID() {
+			return bus.Unit, domain.ErrCannotManageYourself
+		}
+
+		user.Disable()
+
+		return bus.Unit, writer.Write(ctx, &user)
+	}
+}
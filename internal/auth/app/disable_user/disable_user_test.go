@@ -0,0 +1,77 @@
+package disable_user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/disable_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DisableUser(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[bus.UnitType, disable_user.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return disable_user.Handler(store, store), store
+	}
+
+	newUsers := func() (domain.User, domain.User) {
+		admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+		admin.HasAdminRights(true)
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+
+		return admin, john
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), disable_user.Command{
+			ID: string(admin.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if the user does not exist", func(t *testing.T) {
+		admin, _ := newUsers()
+		uc, _ := sut(&admin)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), disable_user.Command{
+			ID: "notexisting",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should prevent an admin from disabling itself", func(t *testing.T) {
+		admin, _ := newUsers()
+		uc, _ := sut(&admin)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), disable_user.Command{
+			ID: string(admin.ID()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrCannotManageYourself, err)
+	})
+
+	t.Run("should disable the user", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, store := sut(&admin, &john)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		_, err := uc(ctx, disable_user.Command{
+			ID: string(john.ID()),
+		})
+
+		testutil.IsNil(t, err)
+
+		user, _ := store.GetByID(ctx, john.ID())
+		testutil.IsTrue(t, user.IsDisabled())
+	})
+}
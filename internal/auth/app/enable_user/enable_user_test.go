@@ -0,0 +1,56 @@
+package enable_user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/enable_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_EnableUser(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[bus.UnitType, enable_user.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return enable_user.Handler(store, store), store
+	}
+
+	newUsers := func() (domain.User, domain.User) {
+		admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+		admin.HasAdminRights(true)
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		john.Disable()
+
+		return admin, john
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), enable_user.Command{
+			ID: string(john.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should enable the user", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, store := sut(&admin, &john)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		_, err := uc(ctx, enable_user.Command{
+			ID: string(john.ID()),
+		})
+
+		testutil.IsNil(t, err)
+
+		user, _ := store.GetByID(ctx, john.ID())
+		testutil.IsFalse(t, user.IsDisabled())
+	})
+}
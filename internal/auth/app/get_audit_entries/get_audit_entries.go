@@ -0,0 +1,41 @@
+package get_audit_entries
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve entries of the audit log, most recent first. Only available to admins.
+	Query struct {
+		bus.Query[storage.Paginated[AuditEntry]]
+
+		Page       monad.Maybe[int]    `form:"page"`
+		UserID     monad.Maybe[string] `form:"user_id"`
+		Action     monad.Maybe[string] `form:"action"`
+		ResourceID monad.Maybe[string] `form:"resource_id"`
+		Outcome    monad.Maybe[string] `form:"outcome"`
+	}
+
+	AuditEntry struct {
+		ID            string                   `json:"id"`
+		User          monad.Maybe[UserSummary] `json:"user"`
+		Action        string                   `json:"action"`
+		ResourceID    monad.Maybe[string]      `json:"resource_id"`
+		Outcome       string                   `json:"outcome"`
+		Error         monad.Maybe[string]      `json:"error"`
+		CorrelationID monad.Maybe[string]      `json:"correlation_id"`
+		OccurredAt    time.Time                `json:"occurred_at"`
+	}
+
+	// Email is only available if the user still exists.
+	UserSummary struct {
+		ID    string              `json:"id"`
+		Email monad.Maybe[string] `json:"email"`
+	}
+)
+
+func (Query) Name_() string { return "auth.query.get_audit_entries" }
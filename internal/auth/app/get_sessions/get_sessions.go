@@ -0,0 +1,29 @@
+package get_sessions
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve active sessions of the current user.
+	Query struct {
+		bus.Query[[]Session]
+
+		CurrentID string `json:"-"` // Session used by the request, flagged as current in the result
+	}
+
+	Session struct {
+		ID         string                 `json:"id"`
+		Device     string                 `json:"device"`
+		IP         string                 `json:"ip"`
+		Current    bool                   `json:"current"`
+		CreatedAt  time.Time              `json:"created_at"`
+		LastSeenAt time.Time              `json:"last_seen_at"`
+		ExpiresAt  monad.Maybe[time.Time] `json:"expires_at"`
+	}
+)
+
+func (Query) Name_() string { return "auth.query.get_sessions" }
@@ -0,0 +1,54 @@
+package get_token
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve one API token of the current user.
+	Query struct {
+		bus.Query[Token]
+
+		ID string `json:"id"`
+	}
+
+	Token struct {
+		ID         string                 `json:"id"`
+		Name       string                 `json:"name"`
+		Scopes     Scopes                 `json:"scopes"`
+		LastUsedAt monad.Maybe[time.Time] `json:"last_used_at"`
+		CreatedAt  time.Time              `json:"created_at"`
+		Usage      Usage                  `json:"usage"`
+	}
+
+	// Usage statistics of the token, limited to the retention period so compromised
+	// or abandoned tokens could be spotted.
+	Usage struct {
+		Total     int             `json:"total"`
+		Days      []DailyUsage    `json:"days"`      // Only days with at least one call, most recent first
+		Endpoints []EndpointUsage `json:"endpoints"` // Most called first
+	}
+
+	DailyUsage struct {
+		Day   string `json:"day"` // In the YYYY-MM-DD format, UTC
+		Count int    `json:"count"`
+	}
+
+	EndpointUsage struct {
+		Endpoint   string    `json:"endpoint"`
+		Count      int       `json:"count"`
+		LastUsedAt time.Time `json:"last_used_at"`
+	}
+
+	Scopes []string
+)
+
+func (Query) Name_() string { return "auth.query.get_token" }
+
+func (s *Scopes) Scan(value any) error {
+	return storage.ScanJSON(value, s)
+}
@@ -0,0 +1,13 @@
+package get_tokens
+
+import (
+	"github.com/YuukanOO/seelf/internal/auth/app/get_token"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve all API tokens of the current user.
+type Query struct {
+	bus.Query[[]get_token.Token]
+}
+
+func (Query) Name_() string { return "auth.query.get_tokens" }
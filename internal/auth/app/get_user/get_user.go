@@ -0,0 +1,36 @@
+package get_user
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve one user. Non admin users will only be able to retrieve themselves.
+	Query struct {
+		bus.Query[User]
+
+		ID string `json:"id"`
+	}
+
+	User struct {
+		ID           string                 `json:"id"`
+		Email        string                 `json:"email"`
+		IsAdmin      bool                   `json:"is_admin"`
+		Grants       Grants                 `json:"grants"`
+		DisabledAt   monad.Maybe[time.Time] `json:"disabled_at"`
+		RegisteredAt time.Time              `json:"registered_at"`
+	}
+
+	// Roles granted per resource type and resource id.
+	Grants map[string]map[string]string
+)
+
+func (Query) Name_() string { return "auth.query.get_user" }
+
+func (g *Grants) Scan(value any) error {
+	return storage.ScanJSON(value, g)
+}
@@ -0,0 +1,13 @@
+package get_users
+
+import (
+	"github.com/YuukanOO/seelf/internal/auth/app/get_user"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve all users. Non admin users will only see themselves.
+type Query struct {
+	bus.Query[[]get_user.User]
+}
+
+func (Query) Name_() string { return "auth.query.get_users" }
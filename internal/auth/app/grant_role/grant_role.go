@@ -0,0 +1,67 @@
+package grant_role
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Grant a role to a user on a specific app, target or team. Only admins are allowed to
+// manage roles.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID           string `json:"-"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	Role         string `json:"role"`
+}
+
+func (Command) Name_() string              { return "auth.command.grant_role" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !domain.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		var (
+			kind domain.ResourceType
+			role domain.Role
+		)
+
+		if err := validate.Struct(validate.Of{
+			"resource_type": validate.Value(cmd.ResourceType, &kind, domain.ResourceTypeFrom),
+			"resource_id":   validate.Field(cmd.ResourceID, strings.Required),
+			"role":          validate.Value(cmd.Role, &role, domain.RoleFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		user, err := reader.GetByID(ctx, domain.UserID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		requirement, err := reader.CheckResourceExistence(ctx, domain.NewResource(kind, cmd.ResourceID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = user.Grant(requirement, role); err != nil {
+			return bus.Unit, validate.Wrap(err, "resource_id")
+		}
+
+		return bus.Unit, writer.Write(ctx, &user)
+	}
+}
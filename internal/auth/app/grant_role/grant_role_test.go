@@ -0,0 +1,80 @@
+package grant_role_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/grant_role"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_GrantRole(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[bus.UnitType, grant_role.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return grant_role.Handler(store, store), store
+	}
+
+	newUsers := func() (domain.User, domain.User) {
+		admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+		admin.HasAdminRights(true)
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+
+		return admin, john
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), grant_role.Command{
+			ID:           string(john.ID()),
+			ResourceType: "app",
+			ResourceID:   "anapp",
+			Role:         "admin",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), grant_role.Command{
+			ID:           string(john.ID()),
+			ResourceType: "registry",
+			Role:         "owner",
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidResourceType, validationErr["resource_type"])
+		testutil.ErrorIs(t, domain.ErrInvalidRole, validationErr["role"])
+	})
+
+	t.Run("should grant the role", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, store := sut(&admin, &john)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		_, err := uc(ctx, grant_role.Command{
+			ID:           string(john.ID()),
+			ResourceType: "target",
+			ResourceID:   "atarget",
+			Role:         "deployer",
+		})
+
+		testutil.IsNil(t, err)
+
+		user, _ := store.GetByID(ctx, john.ID())
+		testutil.DeepEquals(t, domain.Grants{
+			domain.ResourceTarget: {"atarget": domain.RoleDeployer},
+		}, user.Grants())
+	})
+}
@@ -0,0 +1,82 @@
+package invite_user
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Creates a new user account. Only admins are allowed to invite other users.
+type Command struct {
+	bus.Command[string]
+
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Admin    bool   `json:"admin"`
+}
+
+func (Command) Name_() string { return "auth.command.invite_user" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+	hasher domain.PasswordHasher,
+	generator domain.KeyGenerator,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !domain.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var email domain.Email
+
+		if err := validate.Struct(validate.Of{
+			"email":    validate.Value(cmd.Email, &email, domain.EmailFrom),
+			"password": validate.Field(cmd.Password, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		emailRequirement, err := reader.CheckEmailAvailability(ctx, email)
+
+		if err != nil {
+			return "", err
+		}
+
+		password, err := hasher.Hash(cmd.Password)
+
+		if err != nil {
+			return "", err
+		}
+
+		key, err := generator.Generate()
+
+		if err != nil {
+			return "", err
+		}
+
+		user, err := domain.NewUser(emailRequirement, password, key)
+
+		if err != nil {
+			return "", validate.Wrap(err, "email")
+		}
+
+		user.HasAdminRights(cmd.Admin)
+		user.Invited()
+
+		if err = writer.Write(ctx, &user); err != nil {
+			return "", err
+		}
+
+		return string(user.ID()), nil
+	}
+}
@@ -0,0 +1,79 @@
+package invite_user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/invite_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_InviteUser(t *testing.T) {
+	hasher := crypto.NewBCryptHasher()
+	admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+	admin.HasAdminRights(true)
+	john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[string, invite_user.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return invite_user.Handler(store, store, hasher, crypto.NewKeyGenerator()), store
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), invite_user.Command{
+			Email:    "jane@doe.com",
+			Password: "password",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc, _ := sut(&admin)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), invite_user.Command{})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should fail if the email is already taken", func(t *testing.T) {
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), admin), invite_user.Command{
+			Email:    "john@doe.com",
+			Password: "password",
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrEmailAlreadyTaken, validationErr["email"])
+	})
+
+	t.Run("should create the user", func(t *testing.T) {
+		uc, store := sut(&admin)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		id, err := uc(ctx, invite_user.Command{
+			Email:    "jane@doe.com",
+			Password: "password",
+			Admin:    true,
+		})
+
+		testutil.IsNil(t, err)
+
+		user, err := store.GetByID(ctx, domain.UserID(id))
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, user.IsAdmin())
+		testutil.IsNil(t, hasher.Compare("password", user.Password()))
+	})
+}
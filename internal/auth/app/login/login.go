@@ -11,7 +11,7 @@ import (
 	"github.com/YuukanOO/seelf/pkg/validate/strings"
 )
 
-// Log the user in.
+// Log the user in. Emails are temporarily locked out after too many failed attempts.
 type Command struct {
 	bus.Command[string]
 
@@ -23,7 +23,10 @@ func (Command) Name_() string { return "auth.command.login" }
 
 func Handler(
 	reader domain.UsersReader,
+	attemptsReader domain.LoginAttemptsReader,
+	attemptsWriter domain.LoginAttemptsWriter,
 	hasher domain.PasswordHasher,
+	policy domain.LockoutPolicy,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
 		var email domain.Email
@@ -35,18 +38,52 @@ func Handler(
 			return "", err
 		}
 
+		attempts, err := attemptsReader.GetByEmail(ctx, email)
+
+		if err != nil {
+			if !errors.Is(err, apperr.ErrNotFound) {
+				return "", err
+			}
+
+			attempts = domain.NewLoginAttempts(email)
+		}
+
+		if err = attempts.CheckAllowed(); err != nil {
+			return "", err
+		}
+
+		failed := func() (string, error) {
+			attempts.Failed(policy)
+
+			if err := attemptsWriter.Write(ctx, &attempts); err != nil {
+				return "", err
+			}
+
+			return "", validate.Wrap(domain.ErrInvalidEmailOrPassword, "email", "password")
+		}
+
 		user, err := reader.GetByEmail(ctx, email)
 
 		if err != nil {
 			if errors.Is(err, apperr.ErrNotFound) {
-				return "", validate.Wrap(domain.ErrInvalidEmailOrPassword, "email", "password")
+				return failed()
 			}
 
 			return "", err
 		}
 
 		if err = hasher.Compare(cmd.Password, user.Password()); err != nil {
-			return "", validate.Wrap(domain.ErrInvalidEmailOrPassword, "email", "password")
+			return failed()
+		}
+
+		attempts.Succeeded()
+
+		if err = attemptsWriter.Write(ctx, &attempts); err != nil {
+			return "", err
+		}
+
+		if user.IsDisabled() {
+			return "", domain.ErrUserDisabled
 		}
 
 		return string(user.ID()), nil
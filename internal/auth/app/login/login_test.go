@@ -3,6 +3,7 @@ package login_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/auth/app/login"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
@@ -20,9 +21,12 @@ func Test_Login(t *testing.T) {
 	password := must.Panic(hasher.Hash("password")) // Sample password hash for the string "password" for tests
 	existingUser := must.Panic(domain.NewUser(domain.NewEmailRequirement("existing@example.com", true), password, "apikey"))
 
+	policy := domain.LockoutPolicy{MaxAttempts: 2, Window: time.Hour, Duration: time.Hour}
+
 	sut := func(existingUsers ...*domain.User) bus.RequestHandler[string, login.Command] {
 		store := memory.NewUsersStore(existingUsers...)
-		return login.Handler(store, hasher)
+		attemptsStore := memory.NewLoginAttemptsStore()
+		return login.Handler(store, attemptsStore, attemptsStore, hasher, policy)
 	}
 
 	t.Run("should require valid inputs", func(t *testing.T) {
@@ -68,4 +72,50 @@ func Test_Login(t *testing.T) {
 		testutil.IsNil(t, err)
 		testutil.Equals(t, string(existingUser.ID()), uid)
 	})
+
+	t.Run("should fail if the user has been disabled", func(t *testing.T) {
+		disabledUser := must.Panic(domain.NewUser(domain.NewEmailRequirement("disabled@example.com", true), password, "disabledkey"))
+		disabledUser.Disable()
+
+		uc := sut(&disabledUser)
+		_, err := uc(context.Background(), login.Command{
+			Email:    "disabled@example.com",
+			Password: "password",
+		})
+
+		testutil.ErrorIs(t, domain.ErrUserDisabled, err)
+	})
+
+	t.Run("should lock the email out after too many failed attempts", func(t *testing.T) {
+		uc := sut(&existingUser)
+		cmd := login.Command{
+			Email:    "existing@example.com",
+			Password: "nobodycares",
+		}
+
+		for i := 0; i < policy.MaxAttempts; i++ {
+			_, err := uc(context.Background(), cmd)
+			testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		}
+
+		cmd.Password = "password"
+		_, err := uc(context.Background(), cmd)
+
+		testutil.ErrorIs(t, domain.ErrTooManyLoginAttempts, err)
+	})
+
+	t.Run("should clear failed attempts once logged in", func(t *testing.T) {
+		uc := sut(&existingUser)
+
+		for _, password := range []string{"nobodycares", "password", "nobodycares", "password"} {
+			_, err := uc(context.Background(), login.Command{
+				Email:    "existing@example.com",
+				Password: password,
+			})
+
+			if password == "password" {
+				testutil.IsNil(t, err)
+			}
+		}
+	})
 }
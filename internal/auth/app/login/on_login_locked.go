@@ -0,0 +1,17 @@
+package login
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Record lockouts in the audit log so brute-force attempts could be investigated.
+func OnLoginLockedHandler(writer domain.AuditEntriesWriter) bus.SignalHandler[domain.LoginLocked] {
+	return func(ctx context.Context, evt domain.LoginLocked) error {
+		entry := domain.NewAuditEntry(ctx, evt.Name_(), string(evt.Email), domain.ErrTooManyLoginAttempts)
+
+		return writer.Write(ctx, &entry)
+	}
+}
@@ -0,0 +1,110 @@
+package login_external
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Log the user in using an identity verified by an external provider (such as an OpenID
+// Connect one). Unknown users are linked to the account with the same email if it has
+// been verified by the provider, or provisioned on the fly.
+type Command struct {
+	bus.Command[string]
+
+	Issuer        string `json:"issuer"`
+	Subject       string `json:"subject"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+func (Command) Name_() string { return "auth.command.login_external" }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+	generator domain.KeyGenerator,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var email domain.Email
+
+		if err := validate.Struct(validate.Of{
+			"issuer":  validate.Field(cmd.Issuer, strings.Required),
+			"subject": validate.Field(cmd.Subject, strings.Required),
+			"email":   validate.Value(cmd.Email, &email, domain.EmailFrom),
+		}); err != nil {
+			return "", err
+		}
+
+		externalID := domain.NewExternalID(cmd.Issuer, cmd.Subject)
+
+		user, err := reader.GetByExternalID(ctx, externalID)
+
+		if err == nil {
+			return login(user)
+		}
+
+		if !errors.Is(err, apperr.ErrNotFound) {
+			return "", err
+		}
+
+		// Only link to an existing account if the provider says the email belongs to the user
+		if cmd.EmailVerified {
+			user, err = reader.GetByEmail(ctx, email)
+
+			if err == nil {
+				user.LinkTo(externalID)
+
+				if err = writer.Write(ctx, &user); err != nil {
+					return "", err
+				}
+
+				return login(user)
+			}
+
+			if !errors.Is(err, apperr.ErrNotFound) {
+				return "", err
+			}
+		}
+
+		emailRequirement, err := reader.CheckEmailAvailability(ctx, email)
+
+		if err != nil {
+			return "", err
+		}
+
+		key, err := generator.Generate()
+
+		if err != nil {
+			return "", err
+		}
+
+		// Provisioned users have no password, they can only log in using the provider
+		user, err = domain.NewUser(emailRequirement, "", key)
+
+		if err != nil {
+			return "", err
+		}
+
+		user.LinkTo(externalID)
+
+		if err = writer.Write(ctx, &user); err != nil {
+			return "", err
+		}
+
+		return login(user)
+	}
+}
+
+func login(user domain.User) (string, error) {
+	if user.IsDisabled() {
+		return "", domain.ErrUserDisabled
+	}
+
+	return string(user.ID()), nil
+}
@@ -0,0 +1,112 @@
+package login_external_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/login_external"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_LoginExternal(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[string, login_external.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return login_external.Handler(store, store, crypto.NewKeyGenerator()), store
+	}
+
+	externalID := domain.NewExternalID("https://issuer.example.com", "asubject")
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(context.Background(), login_external.Command{})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should log in a linked user", func(t *testing.T) {
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		john.LinkTo(externalID)
+		uc, _ := sut(&john)
+
+		id, err := uc(context.Background(), login_external.Command{
+			Issuer:  "https://issuer.example.com",
+			Subject: "asubject",
+			Email:   "another@doe.com",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(john.ID()), id)
+	})
+
+	t.Run("should fail if the linked user has been disabled", func(t *testing.T) {
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		john.LinkTo(externalID)
+		john.Disable()
+		uc, _ := sut(&john)
+
+		_, err := uc(context.Background(), login_external.Command{
+			Issuer:  "https://issuer.example.com",
+			Subject: "asubject",
+			Email:   "john@doe.com",
+		})
+
+		testutil.ErrorIs(t, domain.ErrUserDisabled, err)
+	})
+
+	t.Run("should link an existing user if the email has been verified", func(t *testing.T) {
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		uc, store := sut(&john)
+
+		id, err := uc(context.Background(), login_external.Command{
+			Issuer:        "https://issuer.example.com",
+			Subject:       "asubject",
+			Email:         "john@doe.com",
+			EmailVerified: true,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(john.ID()), id)
+
+		user, _ := store.GetByID(context.Background(), john.ID())
+		testutil.Equals(t, externalID, user.ExternalID().MustGet())
+	})
+
+	t.Run("should not link an existing user if the email has not been verified", func(t *testing.T) {
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		uc, _ := sut(&john)
+
+		_, err := uc(context.Background(), login_external.Command{
+			Issuer:  "https://issuer.example.com",
+			Subject: "asubject",
+			Email:   "john@doe.com",
+		})
+
+		testutil.ErrorIs(t, domain.ErrEmailAlreadyTaken, err)
+	})
+
+	t.Run("should provision a new user", func(t *testing.T) {
+		uc, store := sut()
+
+		id, err := uc(context.Background(), login_external.Command{
+			Issuer:  "https://issuer.example.com",
+			Subject: "asubject",
+			Email:   "john@doe.com",
+		})
+
+		testutil.IsNil(t, err)
+
+		user, err := store.GetByExternalID(context.Background(), externalID)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, id, string(user.ID()))
+		testutil.Equals(t, "", user.Password())
+		testutil.IsFalse(t, user.IsAdmin())
+	})
+}
@@ -13,7 +13,8 @@ type Command struct {
 	ID string `json:"-"`
 }
 
-func (Command) Name_() string { return "auth.command.refresh_api_key" }
+func (Command) Name_() string              { return "auth.command.refresh_api_key" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.UsersReader,
@@ -0,0 +1,51 @@
+package revoke_role
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Revoke the role granted to a user on a specific app or target.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID           string `json:"-"`
+	ResourceType string `json:"-"`
+	ResourceID   string `json:"-"`
+}
+
+func (Command) Name_() string              { return "auth.command.revoke_role" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !domain.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		var kind domain.ResourceType
+
+		if err := validate.Struct(validate.Of{
+			"resource_type": validate.Value(cmd.ResourceType, &kind, domain.ResourceTypeFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		user, err := reader.GetByID(ctx, domain.UserID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		user.Revoke(domain.NewResource(kind, cmd.ResourceID))
+
+		return bus.Unit, writer.Write(ctx, &user)
+	}
+}
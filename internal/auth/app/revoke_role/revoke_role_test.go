@@ -0,0 +1,60 @@
+package revoke_role_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_role"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RevokeRole(t *testing.T) {
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[bus.UnitType, revoke_role.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return revoke_role.Handler(store, store), store
+	}
+
+	newUsers := func() (domain.User, domain.User) {
+		admin := must.Panic(domain.NewUser(domain.NewEmailRequirement("admin@example.com", true), "password", "adminkey"))
+		admin.HasAdminRights(true)
+		john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+		john.Grant(domain.NewResourceRequirement(domain.NewResource(domain.ResourceApp, "anapp"), true), domain.RoleReadOnly)
+
+		return admin, john
+	}
+
+	t.Run("should be reserved to admins", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, _ := sut(&admin, &john)
+
+		_, err := uc(domain.WithUser(context.Background(), john), revoke_role.Command{
+			ID:           string(john.ID()),
+			ResourceType: "app",
+			ResourceID:   "anapp",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should revoke the role", func(t *testing.T) {
+		admin, john := newUsers()
+		uc, store := sut(&admin, &john)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		_, err := uc(ctx, revoke_role.Command{
+			ID:           string(john.ID()),
+			ResourceType: "app",
+			ResourceID:   "anapp",
+		})
+
+		testutil.IsNil(t, err)
+
+		user, _ := store.GetByID(ctx, john.ID())
+		testutil.DeepEquals(t, domain.Grants{}, user.Grants())
+	})
+}
@@ -0,0 +1,39 @@
+package revoke_session
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Revoke a session. Only its owner or an admin can revoke it.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "auth.command.revoke_session" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.SessionsReader,
+	writer domain.SessionsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		session, err := reader.GetByID(ctx, domain.SessionID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = domain.Authorize(ctx, domain.PermissionManage, session.UserID()); err != nil {
+			return bus.Unit, err
+		}
+
+		session.Revoke()
+
+		return bus.Unit, writer.Write(ctx, &session)
+	}
+}
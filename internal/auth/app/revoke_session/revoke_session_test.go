@@ -0,0 +1,60 @@
+package revoke_session_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RevokeSession(t *testing.T) {
+	sut := func(existingSessions ...*domain.Session) (bus.RequestHandler[bus.UnitType, revoke_session.Command], memory.SessionsStore) {
+		store := memory.NewSessionsStore(existingSessions...)
+		return revoke_session.Handler(store, store), store
+	}
+
+	john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+	jane := must.Panic(domain.NewUser(domain.NewEmailRequirement("jane@doe.com", true), "password", "janekey"))
+
+	t.Run("should fail if the session does not exist", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(domain.WithUser(context.Background(), john), revoke_session.Command{
+			ID: "asession",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not revoke a session of another user", func(t *testing.T) {
+		session := domain.NewSession(jane.ID(), "Mozilla/5.0", "127.0.0.1", domain.SessionPolicy{})
+		uc, _ := sut(&session)
+
+		_, err := uc(domain.WithUser(context.Background(), john), revoke_session.Command{
+			ID: string(session.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should revoke the session", func(t *testing.T) {
+		session := domain.NewSession(john.ID(), "Mozilla/5.0", "127.0.0.1", domain.SessionPolicy{})
+		uc, store := sut(&session)
+		ctx := domain.WithUser(context.Background(), john)
+
+		_, err := uc(ctx, revoke_session.Command{
+			ID: string(session.ID()),
+		})
+
+		testutil.IsNil(t, err)
+
+		_, err = store.GetByID(ctx, session.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,39 @@
+package revoke_token
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Revoke an API token. Only its owner or an admin can revoke it.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "auth.command.revoke_token" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.TokensReader,
+	writer domain.TokensWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		token, err := reader.GetByID(ctx, domain.TokenID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = domain.Authorize(ctx, domain.PermissionManage, token.CreatedBy()); err != nil {
+			return bus.Unit, err
+		}
+
+		token.Revoke()
+
+		return bus.Unit, writer.Write(ctx, &token)
+	}
+}
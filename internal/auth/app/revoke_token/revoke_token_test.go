@@ -0,0 +1,60 @@
+package revoke_token_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_token"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RevokeToken(t *testing.T) {
+	sut := func(existingTokens ...*domain.Token) (bus.RequestHandler[bus.UnitType, revoke_token.Command], memory.TokensStore) {
+		store := memory.NewTokensStore(existingTokens...)
+		return revoke_token.Handler(store, store), store
+	}
+
+	john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+	jane := must.Panic(domain.NewUser(domain.NewEmailRequirement("jane@doe.com", true), "password", "janekey"))
+
+	t.Run("should fail if the token does not exist", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(domain.WithUser(context.Background(), john), revoke_token.Command{
+			ID: "atoken",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not revoke a token of another user", func(t *testing.T) {
+		token := domain.NewToken("ci", "ahash", domain.Scopes{"read:deployments"}, jane.ID())
+		uc, _ := sut(&token)
+
+		_, err := uc(domain.WithUser(context.Background(), john), revoke_token.Command{
+			ID: string(token.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should revoke the token", func(t *testing.T) {
+		token := domain.NewToken("ci", "ahash", domain.Scopes{"read:deployments"}, john.ID())
+		uc, store := sut(&token)
+		ctx := domain.WithUser(context.Background(), john)
+
+		_, err := uc(ctx, revoke_token.Command{
+			ID: string(token.ID()),
+		})
+
+		testutil.IsNil(t, err)
+
+		_, err = store.GetByID(ctx, token.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,87 @@
+package setup_account
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+var ErrSetupCompleted = apperr.New("setup_completed")
+
+// Creates the administrator account from the first-run setup. Contrary to the
+// create_first_account command, it fails if an account already exists.
+type Command struct {
+	bus.Command[string]
+
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (Command) Name_() string { return "auth.command.setup_account" }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+	hasher domain.PasswordHasher,
+	generator domain.KeyGenerator,
+) bus.RequestHandler[string, Command] {
+	// Prevent concurrent setups from creating multiple administrators
+	var mu sync.Mutex
+
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var email domain.Email
+
+		if err := validate.Struct(validate.Of{
+			"email":    validate.Value(cmd.Email, &email, domain.EmailFrom),
+			"password": validate.Field(cmd.Password, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, err := reader.GetAdminUser(ctx)
+
+		if err == nil {
+			return "", ErrSetupCompleted
+		}
+
+		if !errors.Is(err, apperr.ErrNotFound) {
+			return "", err
+		}
+
+		password, err := hasher.Hash(cmd.Password)
+
+		if err != nil {
+			return "", err
+		}
+
+		key, err := generator.Generate()
+
+		if err != nil {
+			return "", err
+		}
+
+		// No account exists yet so the email is guaranteed to be unique.
+		user, err := domain.NewUser(domain.NewEmailRequirement(email, true), password, key)
+
+		if err != nil {
+			return "", err
+		}
+
+		user.HasAdminRights(true)
+
+		if err = writer.Write(ctx, &user); err != nil {
+			return "", err
+		}
+
+		return string(user.ID()), nil
+	}
+}
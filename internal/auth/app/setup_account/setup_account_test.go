@@ -0,0 +1,67 @@
+package setup_account_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/setup_account"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_SetupAccount(t *testing.T) {
+	ctx := context.Background()
+	hasher := crypto.NewBCryptHasher()
+	keygen := crypto.NewKeyGenerator()
+
+	sut := func(existingUsers ...*domain.User) (bus.RequestHandler[string, setup_account.Command], memory.UsersStore) {
+		store := memory.NewUsersStore(existingUsers...)
+		return setup_account.Handler(store, store, hasher, keygen), store
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc, _ := sut()
+
+		uid, err := uc(ctx, setup_account.Command{
+			Email: "notanemail",
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.Equals(t, "", uid)
+	})
+
+	t.Run("should fail if an account already exists", func(t *testing.T) {
+		usr := must.Panic(domain.NewUser(domain.NewEmailRequirement("existing@example.com", true), "password", "apikey"))
+		usr.HasAdminRights(true)
+		uc, _ := sut(&usr)
+
+		uid, err := uc(ctx, setup_account.Command{
+			Email:    "admin@example.com",
+			Password: "admin",
+		})
+
+		testutil.ErrorIs(t, setup_account.ErrSetupCompleted, err)
+		testutil.Equals(t, "", uid)
+	})
+
+	t.Run("should create the administrator account", func(t *testing.T) {
+		uc, store := sut()
+
+		uid, err := uc(ctx, setup_account.Command{
+			Email:    "admin@example.com",
+			Password: "admin",
+		})
+
+		testutil.IsNil(t, err)
+
+		user, err := store.GetByID(ctx, domain.UserID(uid))
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, user.IsAdmin())
+	})
+}
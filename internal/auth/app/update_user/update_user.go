@@ -19,7 +19,8 @@ type Command struct {
 	Password monad.Maybe[string] `json:"password"`
 }
 
-func (Command) Name_() string { return "auth.command.update_user" }
+func (Command) Name_() string              { return "auth.command.update_user" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.UsersReader,
@@ -0,0 +1,52 @@
+package use_session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Authenticate a request using a session, keeping track of its last activity.
+// Expired sessions are removed.
+type Command struct {
+	bus.Command[domain.UserID]
+
+	ID string `json:"-"`
+	IP string `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.use_session" }
+
+func Handler(
+	reader domain.SessionsReader,
+	writer domain.SessionsWriter,
+	policy domain.SessionPolicy,
+) bus.RequestHandler[domain.UserID, Command] {
+	return func(ctx context.Context, cmd Command) (domain.UserID, error) {
+		session, err := reader.GetByID(ctx, domain.SessionID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = session.Seen(cmd.IP, policy); err != nil {
+			if errors.Is(err, domain.ErrSessionExpired) {
+				session.Revoke()
+
+				if writeErr := writer.Write(ctx, &session); writeErr != nil {
+					return "", writeErr
+				}
+			}
+
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &session); err != nil {
+			return "", err
+		}
+
+		return session.UserID(), nil
+	}
+}
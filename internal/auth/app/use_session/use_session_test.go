@@ -0,0 +1,66 @@
+package use_session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/use_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UseSession(t *testing.T) {
+	sut := func(policy domain.SessionPolicy, existingSessions ...*domain.Session) (bus.RequestHandler[domain.UserID, use_session.Command], memory.SessionsStore) {
+		store := memory.NewSessionsStore(existingSessions...)
+		return use_session.Handler(store, store, policy), store
+	}
+
+	t.Run("should fail if the session does not exist", func(t *testing.T) {
+		uc, _ := sut(domain.SessionPolicy{})
+
+		_, err := uc(context.Background(), use_session.Command{
+			ID: "asession",
+			IP: "127.0.0.1",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail and remove the session if it has expired", func(t *testing.T) {
+		policy := domain.SessionPolicy{Lifetime: time.Nanosecond}
+		session := domain.NewSession("auser", "Mozilla/5.0", "127.0.0.1", policy)
+		uc, store := sut(policy, &session)
+
+		time.Sleep(time.Millisecond)
+
+		_, err := uc(context.Background(), use_session.Command{
+			ID: string(session.ID()),
+			IP: "127.0.0.1",
+		})
+
+		testutil.ErrorIs(t, domain.ErrSessionExpired, err)
+
+		_, err = store.GetByID(context.Background(), session.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should returns the session user and keep track of its activity", func(t *testing.T) {
+		session := domain.NewSession("auser", "Mozilla/5.0", "127.0.0.1", domain.SessionPolicy{})
+		uc, store := sut(domain.SessionPolicy{}, &session)
+
+		uid, err := uc(context.Background(), use_session.Command{
+			ID: string(session.ID()),
+			IP: "192.168.1.1",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "auser", uid)
+
+		session, _ = store.GetByID(context.Background(), session.ID())
+		testutil.Equals(t, "192.168.1.1", session.IP())
+	})
+}
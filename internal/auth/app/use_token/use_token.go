@@ -0,0 +1,51 @@
+package use_token
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Authenticate a request using an API token, keeping track of its usage.
+	Command struct {
+		bus.Command[Result]
+
+		Token    string `json:"-"`
+		Endpoint string `json:"-"` // Method and route called with the token
+	}
+
+	// User to which the token is attached and scopes limiting what can be done with it.
+	Result struct {
+		UserID domain.UserID
+		Scopes domain.Scopes
+	}
+)
+
+func (Command) Name_() string { return "auth.command.use_token" }
+
+func Handler(
+	reader domain.TokensReader,
+	writer domain.TokensWriter,
+	hasher domain.TokenHasher,
+) bus.RequestHandler[Result, Command] {
+	return func(ctx context.Context, cmd Command) (Result, error) {
+		token, err := reader.GetByHash(ctx, hasher.Hash(domain.APIKey(cmd.Token)))
+
+		if err != nil {
+			return Result{}, err
+		}
+
+		token.Used(cmd.Endpoint)
+
+		if err = writer.Write(ctx, &token); err != nil {
+			return Result{}, err
+		}
+
+		return Result{
+			UserID: token.CreatedBy(),
+			Scopes: token.Scopes(),
+		}, nil
+	}
+}
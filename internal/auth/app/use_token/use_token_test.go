@@ -0,0 +1,49 @@
+package use_token_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/use_token"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/auth/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UseToken(t *testing.T) {
+	hasher := crypto.NewTokenHasher()
+	sut := func(existingTokens ...*domain.Token) (bus.RequestHandler[use_token.Result, use_token.Command], memory.TokensStore) {
+		store := memory.NewTokensStore(existingTokens...)
+		return use_token.Handler(store, store, hasher), store
+	}
+
+	t.Run("should fail if the token does not exist", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(context.Background(), use_token.Command{
+			Token: "atoken",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should returns the token user and scopes and mark it as used", func(t *testing.T) {
+		token := domain.NewToken("ci", hasher.Hash("atoken"), domain.Scopes{"deploy:anapp"}, "auser")
+		uc, store := sut(&token)
+
+		result, err := uc(context.Background(), use_token.Command{
+			Token:    "atoken",
+			Endpoint: "GET /api/v1/apps",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "auser", result.UserID)
+		testutil.DeepEquals(t, domain.Scopes{"deploy:anapp"}, result.Scopes)
+
+		token, _ = store.GetByID(context.Background(), token.ID())
+		testutil.IsTrue(t, token.LastUsedAt().HasValue())
+	})
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	AuditOutcomeSucceeded AuditOutcome = "succeeded"
+	AuditOutcomeFailed    AuditOutcome = "failed"
+)
+
+type (
+	AuditEntryID string
+	AuditOutcome string
+
+	// Commands targeting a specific resource should implement this interface so the
+	// resource is recorded in the audit log. The command result is given so commands
+	// creating a resource could return its id.
+	Auditable interface {
+		AuditResource(result any) string
+	}
+
+	// Trace of an action performed by a user, kept for security purposes. Entries are
+	// never updated once recorded.
+	AuditEntry struct {
+		event.Emitter
+
+		id AuditEntryID
+	}
+
+	AuditEntriesWriter interface {
+		Write(context.Context, ...*AuditEntry) error
+	}
+
+	AuditEntryRecorded struct {
+		bus.Notification
+
+		ID            AuditEntryID
+		UserID        monad.Maybe[UserID]
+		Action        string
+		ResourceID    monad.Maybe[string]
+		Outcome       AuditOutcome
+		Error         monad.Maybe[string]
+		CorrelationID monad.Maybe[string]
+		OccurredAt    time.Time
+	}
+)
+
+func (AuditEntryRecorded) Name_() string { return "auth.event.audit_entry_recorded" }
+
+// Records the given action performed by the user attached to the context on the given
+// resource. The error returned by the action, if any, determines its outcome.
+func NewAuditEntry(ctx context.Context, action string, resourceID string, err error) (e AuditEntry) {
+	evt := AuditEntryRecorded{
+		ID:            id.New[AuditEntryID](),
+		UserID:        CurrentUser(ctx),
+		Action:        action,
+		Outcome:       AuditOutcomeSucceeded,
+		CorrelationID: bus.CorrelationID(ctx),
+		OccurredAt:    time.Now().UTC(),
+	}
+
+	if resourceID != "" {
+		evt.ResourceID.Set(resourceID)
+	}
+
+	if err != nil {
+		evt.Outcome = AuditOutcomeFailed
+		evt.Error.Set(err.Error())
+	}
+
+	e.apply(evt)
+
+	return e
+}
+
+func (e *AuditEntry) ID() AuditEntryID { return e.id }
+
+func (e *AuditEntry) apply(evt event.Event) {
+	switch evt := evt.(type) {
+	case AuditEntryRecorded:
+		e.id = evt.ID
+	}
+
+	event.Store(e, evt)
+}
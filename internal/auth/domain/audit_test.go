@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AuditEntry(t *testing.T) {
+	t.Run("could be recorded for a successful action", func(t *testing.T) {
+		ctx := domain.WithUserID(context.Background(), "auser")
+
+		entry := domain.NewAuditEntry(ctx, "auth.command.create_token", "atoken", nil)
+
+		evt := testutil.EventIs[domain.AuditEntryRecorded](t, &entry, 0)
+
+		testutil.NotEquals(t, "", entry.ID())
+		testutil.Equals(t, entry.ID(), evt.ID)
+		testutil.Equals(t, "auser", evt.UserID.MustGet())
+		testutil.Equals(t, "auth.command.create_token", evt.Action)
+		testutil.Equals(t, "atoken", evt.ResourceID.MustGet())
+		testutil.Equals(t, domain.AuditOutcomeSucceeded, evt.Outcome)
+		testutil.IsFalse(t, evt.Error.HasValue())
+	})
+
+	t.Run("could be recorded for a failed action", func(t *testing.T) {
+		entry := domain.NewAuditEntry(context.Background(), "auth.command.create_token", "", errors.New("some_error"))
+
+		evt := testutil.EventIs[domain.AuditEntryRecorded](t, &entry, 0)
+
+		testutil.IsFalse(t, evt.UserID.HasValue())
+		testutil.IsFalse(t, evt.ResourceID.HasValue())
+		testutil.Equals(t, domain.AuditOutcomeFailed, evt.Outcome)
+		testutil.Equals(t, "some_error", evt.Error.MustGet())
+	})
+}
@@ -3,12 +3,18 @@ package domain
 import (
 	"context"
 
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
 type contextKey string
 
-const currentUserContextKey contextKey = "current-user"
+const (
+	currentUserContextKey  contextKey = "current-user"
+	restrictedToContextKey contextKey = "restricted-to"
+	grantsContextKey       contextKey = "grants"
+	scopesContextKey       contextKey = "scopes"
+)
 
 // Attach the given UserID to the given context. Will be used everywhere when trying
 // to determine which user is currently executing an action.
@@ -16,6 +22,25 @@ func WithUserID(ctx context.Context, uid UserID) context.Context {
 	return context.WithValue(ctx, currentUserContextKey, uid)
 }
 
+// Attach the given authenticated user to the context. Non admin users will only have
+// access to the resources they own or on which they have been granted a role.
+func WithUser(ctx context.Context, user User) context.Context {
+	ctx = WithUserID(ctx, user.id)
+
+	if !user.isAdmin {
+		ctx = context.WithValue(ctx, restrictedToContextKey, user.id)
+		ctx = context.WithValue(ctx, grantsContextKey, user.grants)
+	}
+
+	return ctx
+}
+
+// Limit what can be done with the given context to the scopes of the API token used
+// to authenticate the request.
+func WithScopes(ctx context.Context, scopes Scopes) context.Context {
+	return context.WithValue(ctx, scopesContextKey, scopes)
+}
+
 // Retrieve the current user attached to the given context if any.
 func CurrentUser(ctx context.Context) (m monad.Maybe[UserID]) {
 	val := ctx.Value(currentUserContextKey)
@@ -28,3 +53,99 @@ func CurrentUser(ctx context.Context) (m monad.Maybe[UserID]) {
 
 	return m
 }
+
+// Retrieve the user to which resources access should be restricted. Contexts without
+// restriction (admins or internal processes such as scheduled jobs) returns an empty
+// monad.Maybe.
+func RestrictedTo(ctx context.Context) (m monad.Maybe[UserID]) {
+	val := ctx.Value(restrictedToContextKey)
+
+	if val == nil {
+		return m
+	}
+
+	m.Set(val.(UserID))
+
+	return m
+}
+
+// Retrieve ids of resources of the given type on which the restricted user attached
+// to the context has been granted a role.
+func GrantedIDs(ctx context.Context, kind ResourceType) []string {
+	grants, _ := ctx.Value(grantsContextKey).(Grants)
+
+	return grants.IDs(kind)
+}
+
+// Retrieve ids of apps to which the API token attached to the context is limited.
+// Contexts without such a limitation returns an empty monad.Maybe.
+func ScopedAppIDs(ctx context.Context) (m monad.Maybe[[]string]) {
+	scopes, scoped := ctx.Value(scopesContextKey).(Scopes)
+
+	if !scoped {
+		return m
+	}
+
+	return scopes.AppIDs()
+}
+
+// Retrieve the permission the context has on a resource owned by the given user.
+// Related resources (such as the targets of an app) can be given since grants on
+// them also apply. Owners and unrestricted contexts can do anything, unless
+// limited by the scopes of an API token.
+func PermissionOn(ctx context.Context, owner UserID, resources ...Resource) (result Permission) {
+	uid, restricted := RestrictedTo(ctx).TryGet()
+
+	if !restricted || uid == owner {
+		result = PermissionManage
+	} else {
+		grants, _ := ctx.Value(grantsContextKey).(Grants)
+		result = grants.Permission(resources...)
+	}
+
+	if scopes, scoped := ctx.Value(scopesContextKey).(Scopes); scoped {
+		result = min(result, scopes.Permission(resources...))
+	}
+
+	return result
+}
+
+// Ensure the context has the required permission on a resource owned by the given user.
+func Authorize(ctx context.Context, required Permission, owner UserID, resources ...Resource) error {
+	if PermissionOn(ctx, owner, resources...) < required {
+		return apperr.ErrForbidden
+	}
+
+	return nil
+}
+
+// Ensure the context could read a resource owned by the given user. Contexts without
+// any permission on it get apperr.ErrNotFound, as if it did not exist, since listings
+// already filter such resources out.
+func AuthorizeRead(ctx context.Context, owner UserID, resources ...Resource) error {
+	if PermissionOn(ctx, owner, resources...) < PermissionRead {
+		return apperr.ErrNotFound
+	}
+
+	return nil
+}
+
+// Ensure the context could create new resources such as targets and apps. Scopes of
+// API tokens only give access to existing apps and never the manage permission, so
+// scoped contexts could not create anything.
+func AuthorizeCreation(ctx context.Context) error {
+	if _, scoped := ctx.Value(scopesContextKey).(Scopes); scoped {
+		return apperr.ErrForbidden
+	}
+
+	return nil
+}
+
+// Check if the context has admin rights, which is the case for admins and internal
+// processes. Admins can manage users and access every resource. Requests authenticated
+// with an API token never have admin rights.
+func HasAdminRights(ctx context.Context) bool {
+	_, scoped := ctx.Value(scopesContextKey).(Scopes)
+
+	return !scoped && !RestrictedTo(ctx).HasValue()
+}
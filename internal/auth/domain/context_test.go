@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
 
@@ -24,3 +26,80 @@ func Test_Auth_Context(t *testing.T) {
 		testutil.IsFalse(t, uid.HasValue())
 	})
 }
+
+func Test_Auth_Context_Restrictions(t *testing.T) {
+	john := must.Panic(domain.NewUser(domain.NewEmailRequirement("john@doe.com", true), "password", "johnkey"))
+	jane := must.Panic(domain.NewUser(domain.NewEmailRequirement("jane@doe.com", true), "password", "janekey"))
+
+	t.Run("should not restrict contexts without a user", func(t *testing.T) {
+		ctx := context.Background()
+
+		testutil.IsFalse(t, domain.RestrictedTo(ctx).HasValue())
+		testutil.Equals(t, domain.PermissionManage, domain.PermissionOn(ctx, john.ID()))
+		testutil.IsTrue(t, domain.HasAdminRights(ctx))
+	})
+
+	t.Run("should restrict non admin users to their own resources", func(t *testing.T) {
+		ctx := domain.WithUser(context.Background(), john)
+
+		testutil.Equals(t, john.ID(), domain.CurrentUser(ctx).MustGet())
+		testutil.Equals(t, john.ID(), domain.RestrictedTo(ctx).MustGet())
+		testutil.Equals(t, domain.PermissionManage, domain.PermissionOn(ctx, john.ID()))
+		testutil.Equals(t, domain.PermissionNone, domain.PermissionOn(ctx, jane.ID()))
+		testutil.IsFalse(t, domain.HasAdminRights(ctx))
+	})
+
+	t.Run("should not restrict admin users", func(t *testing.T) {
+		admin := jane
+		admin.HasAdminRights(true)
+		ctx := domain.WithUser(context.Background(), admin)
+
+		testutil.Equals(t, jane.ID(), domain.CurrentUser(ctx).MustGet())
+		testutil.IsFalse(t, domain.RestrictedTo(ctx).HasValue())
+		testutil.Equals(t, domain.PermissionManage, domain.PermissionOn(ctx, john.ID()))
+		testutil.IsTrue(t, domain.HasAdminRights(ctx))
+	})
+
+	t.Run("should apply roles granted on resources", func(t *testing.T) {
+		var (
+			app    = domain.NewResource(domain.ResourceApp, "app")
+			target = domain.NewResource(domain.ResourceTarget, "target")
+			other  = domain.NewResource(domain.ResourceApp, "other")
+		)
+
+		user := john
+		testutil.IsNil(t, user.Grant(domain.NewResourceRequirement(app, true), domain.RoleReadOnly))
+		testutil.IsNil(t, user.Grant(domain.NewResourceRequirement(target, true), domain.RoleDeployer))
+		ctx := domain.WithUser(context.Background(), user)
+
+		testutil.DeepEquals(t, []string{"app"}, domain.GrantedIDs(ctx, domain.ResourceApp))
+		testutil.Equals(t, domain.PermissionRead, domain.PermissionOn(ctx, jane.ID(), app))
+		testutil.Equals(t, domain.PermissionDeploy, domain.PermissionOn(ctx, jane.ID(), app, target))
+		testutil.IsNil(t, domain.Authorize(ctx, domain.PermissionDeploy, jane.ID(), app, target))
+		testutil.ErrorIs(t, apperr.ErrForbidden, domain.Authorize(ctx, domain.PermissionManage, jane.ID(), app, target))
+		testutil.ErrorIs(t, apperr.ErrForbidden, domain.Authorize(ctx, domain.PermissionRead, jane.ID(), other))
+		testutil.IsNil(t, domain.AuthorizeRead(ctx, jane.ID(), app))
+		testutil.ErrorIs(t, apperr.ErrNotFound, domain.AuthorizeRead(ctx, jane.ID(), other))
+	})
+	t.Run("should limit permissions to the scopes of an API token", func(t *testing.T) {
+		var (
+			app   = domain.NewResource(domain.ResourceApp, "app")
+			other = domain.NewResource(domain.ResourceApp, "other")
+		)
+
+		admin := jane
+		admin.HasAdminRights(true)
+		ctx := domain.WithScopes(domain.WithUser(context.Background(), admin), domain.Scopes{"deploy:app"})
+
+		testutil.DeepEquals(t, []string{"app"}, domain.ScopedAppIDs(ctx).MustGet())
+		testutil.Equals(t, domain.PermissionDeploy, domain.PermissionOn(ctx, jane.ID(), app))
+		testutil.Equals(t, domain.PermissionNone, domain.PermissionOn(ctx, jane.ID(), other))
+		testutil.IsFalse(t, domain.HasAdminRights(ctx))
+
+		ctx = domain.WithScopes(domain.WithUser(context.Background(), john), domain.Scopes{"deploy:deployments"})
+
+		testutil.IsFalse(t, domain.ScopedAppIDs(ctx).HasValue())
+		testutil.Equals(t, domain.PermissionDeploy, domain.PermissionOn(ctx, john.ID(), app))
+		testutil.Equals(t, domain.PermissionNone, domain.PermissionOn(ctx, jane.ID(), app))
+	})
+}
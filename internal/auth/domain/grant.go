@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"database/sql/driver"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidRole         = apperr.New("invalid_role")
+	ErrInvalidResourceType = apperr.New("invalid_resource_type")
+	ErrResourceNotFound    = apperr.New("resource_not_found")
+)
+
+const (
+	RoleReadOnly Role = "read_only" // Can read the resource (app details, deployments and logs)
+	RoleDeployer Role = "deployer"  // Can read and deploy the resource
+	RoleAdmin    Role = "admin"     // Can do anything on the resource, including updating and deleting it
+
+	ResourceApp    ResourceType = "app"
+	ResourceTarget ResourceType = "target" // Grants on a target apply to every app deployed on it
+	ResourceTeam   ResourceType = "team"   // Grants on a team apply to every app belonging to it
+)
+
+const (
+	PermissionNone   Permission = iota // No access at all, the resource should not even be visible
+	PermissionRead                     // Read the resource
+	PermissionDeploy                   // Deploy the resource
+	PermissionManage                   // Update or delete the resource
+)
+
+type (
+	Role         string
+	ResourceType string
+	Permission   uint8
+
+	// Represents a resource managed by another module on which a user can be granted a role.
+	Resource struct {
+		kind ResourceType
+		id   string
+	}
+
+	// Roles granted to a user per resource type and resource id. Stored as a json
+	// string in the database.
+	Grants map[ResourceType]map[string]Role
+)
+
+// Try to parse the given role from a raw string.
+func RoleFrom(value string) (Role, error) {
+	switch Role(value) {
+	case RoleReadOnly:
+		return RoleReadOnly, nil
+	case RoleDeployer:
+		return RoleDeployer, nil
+	case RoleAdmin:
+		return RoleAdmin, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// Permission given by this role.
+func (r Role) Permission() Permission {
+	switch r {
+	case RoleReadOnly:
+		return PermissionRead
+	case RoleDeployer:
+		return PermissionDeploy
+	case RoleAdmin:
+		return PermissionManage
+	default:
+		return PermissionNone
+	}
+}
+
+// Try to parse the given resource type from a raw string.
+func ResourceTypeFrom(value string) (ResourceType, error) {
+	switch ResourceType(value) {
+	case ResourceApp:
+		return ResourceApp, nil
+	case ResourceTarget:
+		return ResourceTarget, nil
+	case ResourceTeam:
+		return ResourceTeam, nil
+	default:
+		return "", ErrInvalidResourceType
+	}
+}
+
+// Builds a new resource reference.
+func NewResource(kind ResourceType, id string) Resource {
+	return Resource{kind, id}
+}
+
+func (r Resource) Type() ResourceType { return r.kind }
+func (r Resource) ID() string         { return r.id }
+
+// Retrieve the role granted on the given resource if any.
+func (g Grants) Of(resource Resource) (Role, bool) {
+	role, found := g[resource.kind][resource.id]
+	return role, found
+}
+
+// Retrieve ids of every resource of the given type on which a role has been granted.
+func (g Grants) IDs(kind ResourceType) []string {
+	ids := make([]string, 0, len(g[kind]))
+
+	for id := range g[kind] {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Highest permission given on any of the given resources.
+func (g Grants) Permission(resources ...Resource) (result Permission) {
+	for _, resource := range resources {
+		if role, found := g.Of(resource); found && role.Permission() > result {
+			result = role.Permission()
+		}
+	}
+
+	return result
+}
+
+func (g Grants) with(resource Resource, role Role) Grants {
+	result := g.clone()
+
+	if result[resource.kind] == nil {
+		result[resource.kind] = make(map[string]Role)
+	}
+
+	result[resource.kind][resource.id] = role
+
+	return result
+}
+
+func (g Grants) without(resource Resource) Grants {
+	result := g.clone()
+
+	delete(result[resource.kind], resource.id)
+
+	if len(result[resource.kind]) == 0 {
+		delete(result, resource.kind)
+	}
+
+	return result
+}
+
+func (g Grants) clone() Grants {
+	result := make(Grants, len(g))
+
+	for kind, roles := range g {
+		result[kind] = make(map[string]Role, len(roles))
+
+		for id, role := range roles {
+			result[kind][id] = role
+		}
+	}
+
+	return result
+}
+
+func (g Grants) Value() (driver.Value, error) { return storage.ValueJSON(g) }
+func (g *Grants) Scan(value any) error        { return storage.ScanJSON(value, g) }
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrTooManyLoginAttempts = apperr.New("too_many_login_attempts")
+
+type (
+	// Rules used to lock an email out after too many failed login attempts. A zero
+	// MaxAttempts disables the lockout.
+	LockoutPolicy struct {
+		MaxAttempts int           // Failed attempts allowed during the window before locking the email out
+		Window      time.Duration // Duration during which failed attempts are counted
+		Duration    time.Duration // How long the email is locked out
+	}
+
+	// Failed login attempts made with a specific email, whether a user exists for
+	// it or not, so attackers could not tell the difference.
+	LoginAttempts struct {
+		event.Emitter
+
+		email           Email
+		failures        int
+		windowStartedAt time.Time
+		lockedUntil     monad.Maybe[time.Time]
+	}
+
+	LoginAttemptsReader interface {
+		GetByEmail(context.Context, Email) (LoginAttempts, error)
+	}
+
+	LoginAttemptsWriter interface {
+		Write(context.Context, ...*LoginAttempts) error
+	}
+
+	LoginAttemptFailed struct {
+		bus.Notification
+
+		Email           Email
+		Failures        int
+		WindowStartedAt time.Time
+	}
+
+	LoginLocked struct {
+		bus.Notification
+
+		Email Email
+		Until time.Time
+	}
+
+	LoginAttemptsCleared struct {
+		bus.Notification
+
+		Email Email
+	}
+)
+
+func (LoginAttemptFailed) Name_() string   { return "auth.event.login_attempt_failed" }
+func (LoginLocked) Name_() string          { return "auth.event.login_locked" }
+func (LoginAttemptsCleared) Name_() string { return "auth.event.login_attempts_cleared" }
+
+// Builds login attempts for an email without any failure yet.
+func NewLoginAttempts(email Email) LoginAttempts {
+	return LoginAttempts{email: email}
+}
+
+// Recreates login attempts from a storage driver
+func LoginAttemptsFrom(scanner storage.Scanner) (a LoginAttempts, err error) {
+	err = scanner.Scan(
+		&a.email,
+		&a.failures,
+		&a.windowStartedAt,
+		&a.lockedUntil,
+	)
+
+	return a, err
+}
+
+// Check if a login attempt could be made right now.
+func (a *LoginAttempts) CheckAllowed() error {
+	if until, isSet := a.lockedUntil.TryGet(); isSet && time.Now().UTC().Before(until) {
+		return ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// Records a failed login attempt, locking the email out if the policy limit has
+// been reached.
+func (a *LoginAttempts) Failed(policy LockoutPolicy) {
+	if policy.MaxAttempts <= 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	evt := LoginAttemptFailed{
+		Email:           a.email,
+		Failures:        a.failures + 1,
+		WindowStartedAt: a.windowStartedAt,
+	}
+
+	if a.failures == 0 || now.Sub(a.windowStartedAt) >= policy.Window {
+		evt.Failures = 1
+		evt.WindowStartedAt = now
+	}
+
+	a.apply(evt)
+
+	if a.failures < policy.MaxAttempts {
+		return
+	}
+
+	a.apply(LoginLocked{
+		Email: a.email,
+		Until: now.Add(policy.Duration),
+	})
+}
+
+// Clear failed attempts after a successful login.
+func (a *LoginAttempts) Succeeded() {
+	if a.failures == 0 && !a.lockedUntil.HasValue() {
+		return
+	}
+
+	a.apply(LoginAttemptsCleared{
+		Email: a.email,
+	})
+}
+
+func (a *LoginAttempts) Email() Email                        { return a.email }
+func (a *LoginAttempts) Failures() int                       { return a.failures }
+func (a *LoginAttempts) LockedUntil() monad.Maybe[time.Time] { return a.lockedUntil }
+
+func (a *LoginAttempts) apply(e event.Event) {
+	switch evt := e.(type) {
+	case LoginAttemptFailed:
+		a.failures = evt.Failures
+		a.windowStartedAt = evt.WindowStartedAt
+	case LoginLocked:
+		a.failures = 0
+		a.lockedUntil.Set(evt.Until)
+	case LoginAttemptsCleared:
+		a.failures = 0
+		a.lockedUntil.Unset()
+	}
+
+	event.Store(a, e)
+}
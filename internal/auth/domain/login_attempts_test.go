@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_LoginAttempts(t *testing.T) {
+	policy := domain.LockoutPolicy{MaxAttempts: 2, Window: time.Hour, Duration: time.Hour}
+
+	t.Run("should count failed attempts", func(t *testing.T) {
+		attempts := domain.NewLoginAttempts("john@doe.com")
+
+		attempts.Failed(policy)
+
+		testutil.Equals(t, 1, attempts.Failures())
+		testutil.IsNil(t, attempts.CheckAllowed())
+
+		evt := testutil.EventIs[domain.LoginAttemptFailed](t, &attempts, 0)
+		testutil.Equals(t, "john@doe.com", evt.Email)
+		testutil.Equals(t, 1, evt.Failures)
+	})
+
+	t.Run("should lock the email out once the limit has been reached", func(t *testing.T) {
+		attempts := domain.NewLoginAttempts("john@doe.com")
+
+		attempts.Failed(policy)
+		attempts.Failed(policy)
+
+		testutil.HasNEvents(t, &attempts, 3)
+		evt := testutil.EventIs[domain.LoginLocked](t, &attempts, 2)
+		testutil.Equals(t, "john@doe.com", evt.Email)
+		testutil.Equals(t, evt.Until, attempts.LockedUntil().MustGet())
+		testutil.ErrorIs(t, domain.ErrTooManyLoginAttempts, attempts.CheckAllowed())
+	})
+
+	t.Run("should restart counting once the window is over", func(t *testing.T) {
+		policy := domain.LockoutPolicy{MaxAttempts: 2, Window: time.Nanosecond, Duration: time.Hour}
+		attempts := domain.NewLoginAttempts("john@doe.com")
+
+		attempts.Failed(policy)
+		time.Sleep(time.Millisecond)
+		attempts.Failed(policy)
+
+		testutil.HasNEvents(t, &attempts, 2)
+		testutil.Equals(t, 1, attempts.Failures())
+		testutil.IsNil(t, attempts.CheckAllowed())
+	})
+
+	t.Run("should do nothing if the lockout is disabled", func(t *testing.T) {
+		attempts := domain.NewLoginAttempts("john@doe.com")
+
+		attempts.Failed(domain.LockoutPolicy{})
+
+		testutil.HasNEvents(t, &attempts, 0)
+	})
+
+	t.Run("should be cleared after a successful login", func(t *testing.T) {
+		attempts := domain.NewLoginAttempts("john@doe.com")
+
+		attempts.Succeeded()
+		testutil.HasNEvents(t, &attempts, 0)
+
+		attempts.Failed(policy)
+		attempts.Succeeded()
+
+		testutil.HasNEvents(t, &attempts, 2)
+		testutil.EventIs[domain.LoginAttemptsCleared](t, &attempts, 1)
+		testutil.Equals(t, 0, attempts.Failures())
+	})
+}
@@ -21,3 +21,25 @@ func (e EmailRequirement) Error() error {
 }
 
 func (e EmailRequirement) Met() (Email, error) { return e.email, e.Error() }
+
+type ResourceRequirement struct {
+	resource Resource
+	exists   bool
+}
+
+func NewResourceRequirement(resource Resource, exists bool) ResourceRequirement {
+	return ResourceRequirement{
+		resource: resource,
+		exists:   exists,
+	}
+}
+
+func (r ResourceRequirement) Error() error {
+	if !r.exists {
+		return ErrResourceNotFound
+	}
+
+	return nil
+}
+
+func (r ResourceRequirement) Met() (Resource, error) { return r.resource, r.Error() }
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrSessionExpired = apperr.New("session_expired")
+
+// Minimum delay between two updates of the last activity of a session to avoid
+// writing to the database on every request.
+const sessionActivityResolution = time.Minute
+
+type (
+	SessionID string
+
+	// Rules used to determine when a session expires. A zero duration disables the
+	// corresponding rule.
+	SessionPolicy struct {
+		Lifetime    time.Duration // Maximum duration of a session, whatever the user activity
+		IdleTimeout time.Duration // Maximum duration without any activity
+	}
+
+	// Browser session opened by a user when logging in.
+	Session struct {
+		event.Emitter
+
+		id         SessionID
+		userID     UserID
+		device     string
+		ip         string
+		createdAt  time.Time
+		lastSeenAt time.Time
+		expiresAt  monad.Maybe[time.Time]
+	}
+
+	SessionsReader interface {
+		GetByID(context.Context, SessionID) (Session, error)
+	}
+
+	SessionsWriter interface {
+		Write(context.Context, ...*Session) error
+	}
+
+	SessionCreated struct {
+		bus.Notification
+
+		ID        SessionID
+		UserID    UserID
+		Device    string
+		IP        string
+		CreatedAt time.Time
+		ExpiresAt monad.Maybe[time.Time]
+	}
+
+	SessionSeen struct {
+		bus.Notification
+
+		ID        SessionID
+		IP        string
+		SeenAt    time.Time
+		ExpiresAt monad.Maybe[time.Time]
+	}
+
+	SessionRevoked struct {
+		bus.Notification
+
+		ID SessionID
+	}
+)
+
+func (SessionCreated) Name_() string { return "auth.event.session_created" }
+func (SessionSeen) Name_() string    { return "auth.event.session_seen" }
+func (SessionRevoked) Name_() string { return "auth.event.session_revoked" }
+
+// Compute the expiration date of a session created and last seen at the given dates.
+func (p SessionPolicy) ExpiresAt(createdAt, lastSeenAt time.Time) (m monad.Maybe[time.Time]) {
+	if p.Lifetime > 0 {
+		m.Set(createdAt.Add(p.Lifetime))
+	}
+
+	if p.IdleTimeout > 0 {
+		idleAt := lastSeenAt.Add(p.IdleTimeout)
+
+		if expiresAt, isSet := m.TryGet(); !isSet || idleAt.Before(expiresAt) {
+			m.Set(idleAt)
+		}
+	}
+
+	return m
+}
+
+// Opens a new session for the given user on the given device.
+func NewSession(userID UserID, device, ip string, policy SessionPolicy) (s Session) {
+	now := time.Now().UTC()
+
+	s.apply(SessionCreated{
+		ID:        id.New[SessionID](),
+		UserID:    userID,
+		Device:    device,
+		IP:        ip,
+		CreatedAt: now,
+		ExpiresAt: policy.ExpiresAt(now, now),
+	})
+
+	return s
+}
+
+// Recreates a session from a storage driver
+func SessionFrom(scanner storage.Scanner) (s Session, err error) {
+	err = scanner.Scan(
+		&s.id,
+		&s.userID,
+		&s.device,
+		&s.ip,
+		&s.createdAt,
+		&s.lastSeenAt,
+		&s.expiresAt,
+	)
+
+	return s, err
+}
+
+// Mark the session as used right now from the given ip. The policy is given again
+// so that configuration changes apply to existing sessions too.
+func (s *Session) Seen(ip string, policy SessionPolicy) error {
+	now := time.Now().UTC()
+
+	if expiresAt, isSet := policy.ExpiresAt(s.createdAt, s.lastSeenAt).TryGet(); isSet && !now.Before(expiresAt) {
+		return ErrSessionExpired
+	}
+
+	// Short idle timeouts need a finer resolution or active sessions would expire
+	resolution := sessionActivityResolution
+
+	if policy.IdleTimeout > 0 {
+		resolution = min(resolution, policy.IdleTimeout/2)
+	}
+
+	if ip == s.ip && now.Sub(s.lastSeenAt) < resolution {
+		return nil
+	}
+
+	s.apply(SessionSeen{
+		ID:        s.id,
+		IP:        ip,
+		SeenAt:    now,
+		ExpiresAt: policy.ExpiresAt(s.createdAt, now),
+	})
+
+	return nil
+}
+
+// Revoke the session, it could not be used anymore.
+func (s *Session) Revoke() {
+	s.apply(SessionRevoked{
+		ID: s.id,
+	})
+}
+
+func (s *Session) ID() SessionID                     { return s.id }
+func (s *Session) UserID() UserID                    { return s.userID }
+func (s *Session) Device() string                    { return s.device }
+func (s *Session) IP() string                        { return s.ip }
+func (s *Session) LastSeenAt() time.Time             { return s.lastSeenAt }
+func (s *Session) ExpiresAt() monad.Maybe[time.Time] { return s.expiresAt }
+
+func (s *Session) apply(e event.Event) {
+	switch evt := e.(type) {
+	case SessionCreated:
+		s.id = evt.ID
+		s.userID = evt.UserID
+		s.device = evt.Device
+		s.ip = evt.IP
+		s.createdAt = evt.CreatedAt
+		s.lastSeenAt = evt.CreatedAt
+		s.expiresAt = evt.ExpiresAt
+	case SessionSeen:
+		s.ip = evt.IP
+		s.lastSeenAt = evt.SeenAt
+		s.expiresAt = evt.ExpiresAt
+	}
+
+	event.Store(s, e)
+}
@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Session(t *testing.T) {
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			uid    = domain.UserID("auser")
+			device = "Mozilla/5.0"
+			ip     = "127.0.0.1"
+		)
+
+		session := domain.NewSession(uid, device, ip, domain.SessionPolicy{})
+
+		testutil.NotEquals(t, "", session.ID())
+		testutil.Equals(t, uid, session.UserID())
+		testutil.Equals(t, device, session.Device())
+		testutil.Equals(t, ip, session.IP())
+		testutil.IsFalse(t, session.ExpiresAt().HasValue())
+
+		evt := testutil.EventIs[domain.SessionCreated](t, &session, 0)
+
+		testutil.Equals(t, session.ID(), evt.ID)
+		testutil.Equals(t, uid, evt.UserID)
+		testutil.Equals(t, device, evt.Device)
+		testutil.Equals(t, ip, evt.IP)
+	})
+
+	t.Run("should keep track of its last activity", func(t *testing.T) {
+		session := domain.NewSession("auser", "Mozilla/5.0", "127.0.0.1", domain.SessionPolicy{})
+
+		testutil.IsNil(t, session.Seen("127.0.0.1", domain.SessionPolicy{}))
+		testutil.HasNEvents(t, &session, 1)
+
+		testutil.IsNil(t, session.Seen("192.168.1.1", domain.SessionPolicy{}))
+		testutil.HasNEvents(t, &session, 2)
+
+		evt := testutil.EventIs[domain.SessionSeen](t, &session, 1)
+		testutil.Equals(t, "192.168.1.1", evt.IP)
+		testutil.Equals(t, evt.SeenAt, session.LastSeenAt())
+		testutil.Equals(t, "192.168.1.1", session.IP())
+	})
+
+	t.Run("should fail to be used once expired", func(t *testing.T) {
+		policy := domain.SessionPolicy{IdleTimeout: time.Nanosecond}
+		session := domain.NewSession("auser", "Mozilla/5.0", "127.0.0.1", policy)
+
+		time.Sleep(time.Millisecond)
+
+		testutil.ErrorIs(t, domain.ErrSessionExpired, session.Seen("127.0.0.1", policy))
+		testutil.HasNEvents(t, &session, 1)
+	})
+
+	t.Run("could be revoked", func(t *testing.T) {
+		session := domain.NewSession("auser", "Mozilla/5.0", "127.0.0.1", domain.SessionPolicy{})
+
+		session.Revoke()
+
+		testutil.HasNEvents(t, &session, 2)
+		evt := testutil.EventIs[domain.SessionRevoked](t, &session, 1)
+		testutil.Equals(t, session.ID(), evt.ID)
+	})
+}
+
+func Test_SessionPolicy(t *testing.T) {
+	var (
+		createdAt  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		lastSeenAt = createdAt.Add(10 * time.Hour)
+	)
+
+	t.Run("should never expire if no rule is set", func(t *testing.T) {
+		testutil.IsFalse(t, domain.SessionPolicy{}.ExpiresAt(createdAt, lastSeenAt).HasValue())
+	})
+
+	t.Run("should expire at the earliest date", func(t *testing.T) {
+		testutil.Equals(t, createdAt.Add(12*time.Hour), domain.SessionPolicy{
+			Lifetime:    12 * time.Hour,
+			IdleTimeout: 4 * time.Hour,
+		}.ExpiresAt(createdAt, lastSeenAt).MustGet())
+
+		testutil.Equals(t, lastSeenAt.Add(time.Hour), domain.SessionPolicy{
+			Lifetime:    12 * time.Hour,
+			IdleTimeout: time.Hour,
+		}.ExpiresAt(createdAt, lastSeenAt).MustGet())
+	})
+}
@@ -0,0 +1,277 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrInvalidScope = apperr.New("invalid_scope")
+
+const (
+	ScopeRead   ScopeAction = "read"   // Read apps, their deployments and logs
+	ScopeDeploy ScopeAction = "deploy" // Read and deploy apps
+
+	// Resource part of a scope to target every app the token owner has access to.
+	ScopeAllApps = "deployments"
+
+	// How long usage statistics of a token are kept.
+	TokenUsageRetention = 30 * 24 * time.Hour
+)
+
+type (
+	TokenID   string
+	TokenHash string
+
+	// Action part of a scope.
+	ScopeAction string
+
+	// Scope given to an API token, in the form <action>:<app id> or <action>:deployments
+	// to target every app the token owner has access to.
+	Scope string
+
+	// Scopes given to an API token. Stored as a json array in the database.
+	Scopes []Scope
+
+	// Long-lived API token attached to a user and limited to some scopes, mostly
+	// used by CI pipelines.
+	Token struct {
+		event.Emitter
+
+		id         TokenID
+		name       string
+		hash       TokenHash
+		scopes     Scopes
+		lastUsedAt monad.Maybe[time.Time]
+		created    shared.Action[UserID]
+	}
+
+	// Hash token values so they are never stored as is.
+	TokenHasher interface {
+		Hash(APIKey) TokenHash
+	}
+
+	TokensReader interface {
+		GetByID(context.Context, TokenID) (Token, error)
+		GetByHash(context.Context, TokenHash) (Token, error)
+	}
+
+	TokensWriter interface {
+		Write(context.Context, ...*Token) error
+	}
+
+	TokenCreated struct {
+		bus.Notification
+
+		ID      TokenID
+		Name    string
+		Hash    TokenHash
+		Scopes  Scopes
+		Created shared.Action[UserID]
+	}
+
+	TokenUsed struct {
+		bus.Notification
+
+		ID       TokenID
+		Endpoint string // Method and route called with the token
+		UsedAt   time.Time
+	}
+
+	TokenRevoked struct {
+		bus.Notification
+
+		ID TokenID
+	}
+)
+
+func (TokenCreated) Name_() string { return "auth.event.token_created" }
+func (TokenUsed) Name_() string    { return "auth.event.token_used" }
+func (TokenRevoked) Name_() string { return "auth.event.token_revoked" }
+
+// Try to parse the given scope from a raw string.
+func ScopeFrom(value string) (Scope, error) {
+	action, resource, found := strings.Cut(value, ":")
+
+	if !found || resource == "" {
+		return "", ErrInvalidScope
+	}
+
+	switch ScopeAction(action) {
+	case ScopeRead, ScopeDeploy:
+		return Scope(value), nil
+	default:
+		return "", ErrInvalidScope
+	}
+}
+
+// Try to parse the given scopes from raw strings. At least one scope is required.
+func ScopesFrom(values []string) (Scopes, error) {
+	if len(values) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	scopes := make(Scopes, len(values))
+
+	for i, value := range values {
+		scope, err := ScopeFrom(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		scopes[i] = scope
+	}
+
+	return scopes, nil
+}
+
+func (s Scope) Action() ScopeAction {
+	action, _, _ := strings.Cut(string(s), ":")
+	return ScopeAction(action)
+}
+
+func (s Scope) Resource() string {
+	_, resource, _ := strings.Cut(string(s), ":")
+	return resource
+}
+
+// Permission given by this scope on the given app.
+func (s Scope) Permission(appID string) Permission {
+	if resource := s.Resource(); resource != ScopeAllApps && resource != appID {
+		return PermissionNone
+	}
+
+	switch s.Action() {
+	case ScopeRead:
+		return PermissionRead
+	case ScopeDeploy:
+		return PermissionDeploy
+	default:
+		return PermissionNone
+	}
+}
+
+// Highest permission given by those scopes on any of the given resources. Scopes only
+// apply to apps so other resources are ignored.
+func (s Scopes) Permission(resources ...Resource) (result Permission) {
+	for _, resource := range resources {
+		if resource.kind != ResourceApp {
+			continue
+		}
+
+		for _, scope := range s {
+			if permission := scope.Permission(resource.id); permission > result {
+				result = permission
+			}
+		}
+	}
+
+	return result
+}
+
+// Retrieve ids of apps targeted by those scopes. If at least one scope targets every
+// app, an empty monad.Maybe is returned.
+func (s Scopes) AppIDs() (m monad.Maybe[[]string]) {
+	ids := make([]string, 0, len(s))
+
+	for _, scope := range s {
+		resource := scope.Resource()
+
+		if resource == ScopeAllApps {
+			return m
+		}
+
+		ids = append(ids, resource)
+	}
+
+	m.Set(ids)
+
+	return m
+}
+
+func (s Scopes) Value() (driver.Value, error) { return storage.ValueJSON(s) }
+func (s *Scopes) Scan(value any) error        { return storage.ScanJSON(value, s) }
+
+// Creates a new API token for the given user. Only the hash of the token value
+// is stored.
+func NewToken(name string, hash TokenHash, scopes Scopes, createdBy UserID) (t Token) {
+	t.apply(TokenCreated{
+		ID:      id.New[TokenID](),
+		Name:    name,
+		Hash:    hash,
+		Scopes:  scopes,
+		Created: shared.NewAction(createdBy),
+	})
+
+	return t
+}
+
+// Recreates a token from a storage driver
+func TokenFrom(scanner storage.Scanner) (t Token, err error) {
+	var (
+		createdAt time.Time
+		createdBy UserID
+	)
+
+	err = scanner.Scan(
+		&t.id,
+		&t.name,
+		&t.hash,
+		&t.scopes,
+		&t.lastUsedAt,
+		&createdAt,
+		&createdBy,
+	)
+
+	t.created = shared.ActionFrom(createdBy, createdAt)
+
+	return t, err
+}
+
+// Mark the token as used right now to call the given endpoint. Every call is recorded
+// so usage statistics could reveal compromised or abandoned tokens.
+func (t *Token) Used(endpoint string) {
+	t.apply(TokenUsed{
+		ID:       t.id,
+		Endpoint: endpoint,
+		UsedAt:   time.Now().UTC(),
+	})
+}
+
+// Revoke the token, it could not be used anymore.
+func (t *Token) Revoke() {
+	t.apply(TokenRevoked{
+		ID: t.id,
+	})
+}
+
+func (t *Token) ID() TokenID                        { return t.id }
+func (t *Token) Name() string                       { return t.name }
+func (t *Token) Scopes() Scopes                     { return t.scopes }
+func (t *Token) LastUsedAt() monad.Maybe[time.Time] { return t.lastUsedAt }
+func (t *Token) CreatedBy() UserID                  { return t.created.By() }
+
+func (t *Token) apply(e event.Event) {
+	switch evt := e.(type) {
+	case TokenCreated:
+		t.id = evt.ID
+		t.name = evt.Name
+		t.hash = evt.Hash
+		t.scopes = evt.Scopes
+		t.created = evt.Created
+	case TokenUsed:
+		t.lastUsedAt.Set(evt.UsedAt)
+	}
+
+	event.Store(t, e)
+}
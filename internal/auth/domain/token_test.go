@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Token(t *testing.T) {
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			name   = "ci"
+			hash   = domain.TokenHash("ahash")
+			scopes = domain.Scopes{"deploy:anapp"}
+			uid    = domain.UserID("auser")
+		)
+
+		token := domain.NewToken(name, hash, scopes, uid)
+
+		testutil.NotEquals(t, "", token.ID())
+		testutil.Equals(t, name, token.Name())
+		testutil.DeepEquals(t, scopes, token.Scopes())
+		testutil.Equals(t, uid, token.CreatedBy())
+		testutil.IsFalse(t, token.LastUsedAt().HasValue())
+
+		evt := testutil.EventIs[domain.TokenCreated](t, &token, 0)
+
+		testutil.Equals(t, token.ID(), evt.ID)
+		testutil.Equals(t, name, evt.Name)
+		testutil.Equals(t, hash, evt.Hash)
+		testutil.DeepEquals(t, scopes, evt.Scopes)
+		testutil.Equals(t, uid, evt.Created.By())
+	})
+
+	t.Run("should keep track of every usage", func(t *testing.T) {
+		token := domain.NewToken("ci", "ahash", domain.Scopes{"read:deployments"}, "auser")
+
+		token.Used("GET /api/v1/apps")
+		token.Used("POST /api/v1/apps/:id/deployments")
+
+		testutil.HasNEvents(t, &token, 3)
+		evt := testutil.EventIs[domain.TokenUsed](t, &token, 2)
+		testutil.Equals(t, "POST /api/v1/apps/:id/deployments", evt.Endpoint)
+		testutil.Equals(t, evt.UsedAt, token.LastUsedAt().MustGet())
+	})
+
+	t.Run("could be revoked", func(t *testing.T) {
+		token := domain.NewToken("ci", "ahash", domain.Scopes{"read:deployments"}, "auser")
+
+		token.Revoke()
+
+		testutil.HasNEvents(t, &token, 2)
+		evt := testutil.EventIs[domain.TokenRevoked](t, &token, 1)
+		testutil.Equals(t, token.ID(), evt.ID)
+	})
+}
+
+func Test_Scopes(t *testing.T) {
+	t.Run("should be parsed from raw strings", func(t *testing.T) {
+		scopes, err := domain.ScopesFrom([]string{"deploy:anapp", "read:deployments"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.Scopes{"deploy:anapp", "read:deployments"}, scopes)
+
+		for _, invalid := range [][]string{nil, {"deploy"}, {"deploy:"}, {"manage:anapp"}} {
+			_, err = domain.ScopesFrom(invalid)
+			testutil.ErrorIs(t, domain.ErrInvalidScope, err)
+		}
+	})
+
+	t.Run("should give the appropriate permission on apps", func(t *testing.T) {
+		var (
+			app    = domain.NewResource(domain.ResourceApp, "anapp")
+			other  = domain.NewResource(domain.ResourceApp, "another")
+			target = domain.NewResource(domain.ResourceTarget, "anapp")
+			scopes = domain.Scopes{"deploy:anapp", "read:deployments"}
+		)
+
+		testutil.Equals(t, domain.PermissionDeploy, scopes.Permission(app, target))
+		testutil.Equals(t, domain.PermissionRead, scopes.Permission(other))
+		testutil.Equals(t, domain.PermissionNone, scopes.Permission(target))
+		testutil.Equals(t, domain.PermissionNone, domain.Scopes{"deploy:anapp"}.Permission(other))
+	})
+
+	t.Run("should returns targeted app ids", func(t *testing.T) {
+		testutil.DeepEquals(t, []string{"anapp", "another"}, domain.Scopes{"deploy:anapp", "read:another"}.AppIDs().MustGet())
+		testutil.IsFalse(t, domain.Scopes{"deploy:anapp", "read:deployments"}.AppIDs().HasValue())
+	})
+}
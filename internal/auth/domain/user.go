@@ -8,12 +8,16 @@ import (
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/event"
 	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
 )
 
 var (
 	ErrEmailAlreadyTaken      = apperr.New("email_already_taken")
 	ErrInvalidEmailOrPassword = apperr.New("invalid_email_or_password")
+	ErrUserDisabled           = apperr.New("user_disabled")
+	ErrUserOwnsResources      = apperr.New("user_owns_resources")
+	ErrCannotManageYourself   = apperr.New("cannot_manage_yourself")
 )
 
 type (
@@ -21,6 +25,9 @@ type (
 	PasswordHash string
 	APIKey       string
 
+	// Identity of a user in an external provider (such as an OpenID Connect one).
+	ExternalID string
+
 	User struct {
 		event.Emitter
 
@@ -28,6 +35,10 @@ type (
 		password     PasswordHash
 		email        Email
 		key          APIKey
+		isAdmin      bool
+		grants       Grants
+		externalID   monad.Maybe[ExternalID]
+		disabledAt   monad.Maybe[time.Time]
 		registeredAt time.Time
 	}
 
@@ -46,6 +57,12 @@ type (
 		CheckEmailAvailability(context.Context, Email, ...UserID) (EmailRequirement, error)
 		GetByEmail(context.Context, Email) (User, error)
 		GetByID(context.Context, UserID) (User, error)
+		GetByExternalID(context.Context, ExternalID) (User, error)
+		// Check if the user still owns resources (apps, targets, registries, deployments)
+		// managed by other modules.
+		HasOwnedResources(context.Context, UserID) (bool, error)
+		// Check if the given resource exists so a role could be granted on it.
+		CheckResourceExistence(context.Context, Resource) (ResourceRequirement, error)
 	}
 
 	UsersWriter interface {
@@ -62,6 +79,14 @@ type (
 		RegisteredAt time.Time
 	}
 
+	// Raised when a user account has been created by an admin.
+	UserInvited struct {
+		bus.Notification
+
+		ID    UserID
+		Email Email
+	}
+
 	UserEmailChanged struct {
 		bus.Notification
 
@@ -82,12 +107,65 @@ type (
 		ID  UserID
 		Key APIKey
 	}
+
+	UserAdminRightsChanged struct {
+		bus.Notification
+
+		ID      UserID
+		IsAdmin bool
+	}
+
+	UserGrantsChanged struct {
+		bus.Notification
+
+		ID     UserID
+		Grants Grants
+	}
+
+	UserLinked struct {
+		bus.Notification
+
+		ID         UserID
+		ExternalID ExternalID
+	}
+
+	UserDisabled struct {
+		bus.Notification
+
+		ID         UserID
+		DisabledAt time.Time
+	}
+
+	UserEnabled struct {
+		bus.Notification
+
+		ID UserID
+	}
+
+	UserDeleted struct {
+		bus.Notification
+
+		ID UserID
+	}
 )
 
-func (UserRegistered) Name_() string      { return "auth.event.user_registered" }
-func (UserEmailChanged) Name_() string    { return "auth.event.user_email_changed" }
-func (UserPasswordChanged) Name_() string { return "auth.event.user_password_changed" }
-func (UserAPIKeyChanged) Name_() string   { return "auth.event.user_api_key_changed" }
+func (UserRegistered) Name_() string         { return "auth.event.user_registered" }
+func (UserInvited) Name_() string            { return "auth.event.user_invited" }
+func (UserEmailChanged) Name_() string       { return "auth.event.user_email_changed" }
+func (UserPasswordChanged) Name_() string    { return "auth.event.user_password_changed" }
+func (UserAPIKeyChanged) Name_() string      { return "auth.event.user_api_key_changed" }
+func (UserAdminRightsChanged) Name_() string { return "auth.event.user_admin_rights_changed" }
+func (UserGrantsChanged) Name_() string      { return "auth.event.user_grants_changed" }
+func (UserLinked) Name_() string             { return "auth.event.user_linked" }
+func (UserDisabled) Name_() string           { return "auth.event.user_disabled" }
+func (UserEnabled) Name_() string            { return "auth.event.user_enabled" }
+func (UserDeleted) Name_() string            { return "auth.event.user_deleted" }
+
+// Builds the external identifier of a user from the issuer of the identity and the
+// subject which uniquely identifies the user for this issuer.
+func NewExternalID(issuer, subject string) ExternalID {
+	return ExternalID(issuer + "#" + subject)
+}
 
 func NewUser(emailRequirement EmailRequirement, password PasswordHash, key APIKey) (u User, err error) {
 	email, err := emailRequirement.Met()
@@ -114,12 +192,24 @@ func UserFrom(scanner storage.Scanner) (u User, err error) {
 		&u.email,
 		&u.password,
 		&u.key,
+		&u.isAdmin,
+		&u.grants,
+		&u.externalID,
+		&u.disabledAt,
 		&u.registeredAt,
 	)
 
 	return u, err
 }
 
+// Mark the user as invited by an admin so it could be notified.
+func (u *User) Invited() {
+	u.apply(UserInvited{
+		ID:    u.id,
+		Email: u.email,
+	})
+}
+
 // Updates the user email
 func (u *User) HasEmail(emailRequirement EmailRequirement) error {
 	email, err := emailRequirement.Met()
@@ -164,8 +254,105 @@ func (u *User) HasAPIKey(key APIKey) {
 	})
 }
 
-func (u *User) ID() UserID             { return u.id }
-func (u *User) Password() PasswordHash { return u.password }
+// Grant or revoke admin rights. Admins can manage other users and see every resource.
+func (u *User) HasAdminRights(isAdmin bool) {
+	if u.isAdmin == isAdmin {
+		return
+	}
+
+	u.apply(UserAdminRightsChanged{
+		ID:      u.id,
+		IsAdmin: isAdmin,
+	})
+}
+
+// Grant a role on the given resource, replacing any previous role on it.
+func (u *User) Grant(requirement ResourceRequirement, role Role) error {
+	resource, err := requirement.Met()
+
+	if err != nil {
+		return err
+	}
+
+	if current, found := u.grants.Of(resource); found && current == role {
+		return nil
+	}
+
+	u.apply(UserGrantsChanged{
+		ID:     u.id,
+		Grants: u.grants.with(resource, role),
+	})
+
+	return nil
+}
+
+// Revoke any role granted on the given resource.
+func (u *User) Revoke(resource Resource) {
+	if _, found := u.grants.Of(resource); !found {
+		return
+	}
+
+	u.apply(UserGrantsChanged{
+		ID:     u.id,
+		Grants: u.grants.without(resource),
+	})
+}
+
+// Link the user to an identity of an external provider so it can log in using it.
+func (u *User) LinkTo(id ExternalID) {
+	if existing, isLinked := u.externalID.TryGet(); isLinked && existing == id {
+		return
+	}
+
+	u.apply(UserLinked{
+		ID:         u.id,
+		ExternalID: id,
+	})
+}
+
+// Disable the user, preventing it from logging in or using its API key.
+func (u *User) Disable() {
+	if u.disabledAt.HasValue() {
+		return
+	}
+
+	u.apply(UserDisabled{
+		ID:         u.id,
+		DisabledAt: time.Now().UTC(),
+	})
+}
+
+// Re-enable a previously disabled user.
+func (u *User) Enable() {
+	if !u.disabledAt.HasValue() {
+		return
+	}
+
+	u.apply(UserEnabled{
+		ID: u.id,
+	})
+}
+
+// Delete the user. Since resources are linked to the user who created them, a user
+// still owning some could not be deleted and should be disabled instead.
+func (u *User) Delete(ownsResources bool) error {
+	if ownsResources {
+		return ErrUserOwnsResources
+	}
+
+	u.apply(UserDeleted{
+		ID: u.id,
+	})
+
+	return nil
+}
+
+func (u *User) ID() UserID                          { return u.id }
+func (u *User) Password() PasswordHash              { return u.password }
+func (u *User) IsAdmin() bool                       { return u.isAdmin }
+func (u *User) IsDisabled() bool                    { return u.disabledAt.HasValue() }
+func (u *User) Grants() Grants                      { return u.grants }
+func (u *User) ExternalID() monad.Maybe[ExternalID] { return u.externalID }
 
 func (u *User) apply(e event.Event) {
 	switch evt := e.(type) {
@@ -181,6 +368,16 @@ func (u *User) apply(e event.Event) {
 		u.password = evt.Password
 	case UserAPIKeyChanged:
 		u.key = evt.Key
+	case UserAdminRightsChanged:
+		u.isAdmin = evt.IsAdmin
+	case UserGrantsChanged:
+		u.grants = evt.Grants
+	case UserLinked:
+		u.externalID.Set(evt.ExternalID)
+	case UserDisabled:
+		u.disabledAt.Set(evt.DisabledAt)
+	case UserEnabled:
+		u.disabledAt.Unset()
 	}
 
 	event.Store(u, e)
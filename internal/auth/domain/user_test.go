@@ -77,4 +77,138 @@ func Test_User(t *testing.T) {
 		testutil.Equals(t, u.ID(), evt.ID)
 		testutil.Equals(t, "anotherKey", evt.Key)
 	})
+
+	t.Run("should be able to change admin rights", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		u.HasAdminRights(false) // no change, should not trigger events
+		u.HasAdminRights(true)
+
+		testutil.IsTrue(t, u.IsAdmin())
+		testutil.HasNEvents(t, &u, 2)
+		evt := testutil.EventIs[domain.UserAdminRightsChanged](t, &u, 1)
+		testutil.Equals(t, u.ID(), evt.ID)
+		testutil.IsTrue(t, evt.IsAdmin)
+	})
+
+	t.Run("could be marked as invited", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		u.Invited()
+
+		testutil.HasNEvents(t, &u, 2)
+		evt := testutil.EventIs[domain.UserInvited](t, &u, 1)
+		testutil.Equals(t, u.ID(), evt.ID)
+		testutil.Equals(t, "some@email.com", evt.Email)
+	})
+
+	t.Run("could be disabled and enabled", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		u.Enable() // not disabled, should not trigger events
+		u.Disable()
+		u.Disable() // already disabled, should not trigger events
+
+		testutil.IsTrue(t, u.IsDisabled())
+		testutil.HasNEvents(t, &u, 2)
+		disabled := testutil.EventIs[domain.UserDisabled](t, &u, 1)
+		testutil.Equals(t, u.ID(), disabled.ID)
+
+		u.Enable()
+
+		testutil.IsFalse(t, u.IsDisabled())
+		testutil.HasNEvents(t, &u, 3)
+		enabled := testutil.EventIs[domain.UserEnabled](t, &u, 2)
+		testutil.Equals(t, u.ID(), enabled.ID)
+	})
+
+	t.Run("could be linked to an external identity", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+		id := domain.NewExternalID("https://issuer.example.com", "asubject")
+
+		u.LinkTo(id)
+		u.LinkTo(id) // already linked, should not trigger events
+
+		testutil.Equals(t, id, u.ExternalID().MustGet())
+		testutil.HasNEvents(t, &u, 2)
+		evt := testutil.EventIs[domain.UserLinked](t, &u, 1)
+		testutil.Equals(t, u.ID(), evt.ID)
+		testutil.Equals(t, id, evt.ExternalID)
+	})
+
+	t.Run("should not be deleted if it still owns resources", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		err := u.Delete(true)
+
+		testutil.ErrorIs(t, domain.ErrUserOwnsResources, err)
+		testutil.HasNEvents(t, &u, 1)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		err := u.Delete(false)
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &u, 2)
+		evt := testutil.EventIs[domain.UserDeleted](t, &u, 1)
+		testutil.Equals(t, u.ID(), evt.ID)
+	})
+
+	t.Run("should not grant a role on a non existing resource", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+
+		err := u.Grant(domain.NewResourceRequirement(domain.NewResource(domain.ResourceApp, "app"), false), domain.RoleDeployer)
+
+		testutil.ErrorIs(t, domain.ErrResourceNotFound, err)
+		testutil.HasNEvents(t, &u, 1)
+	})
+
+	t.Run("could be granted roles on resources", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+		app := domain.NewResource(domain.ResourceApp, "app")
+
+		testutil.IsNil(t, u.Grant(domain.NewResourceRequirement(app, true), domain.RoleDeployer))
+		testutil.IsNil(t, u.Grant(domain.NewResourceRequirement(app, true), domain.RoleDeployer)) // no change, should not trigger events
+
+		testutil.HasNEvents(t, &u, 2)
+		evt := testutil.EventIs[domain.UserGrantsChanged](t, &u, 1)
+		testutil.Equals(t, u.ID(), evt.ID)
+		testutil.DeepEquals(t, domain.Grants{
+			domain.ResourceApp: {"app": domain.RoleDeployer},
+		}, evt.Grants)
+	})
+
+	t.Run("could have its roles revoked", func(t *testing.T) {
+		u := must.Panic(domain.NewUser(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "apikey"))
+		app := domain.NewResource(domain.ResourceApp, "app")
+		target := domain.NewResource(domain.ResourceTarget, "target")
+
+		testutil.IsNil(t, u.Grant(domain.NewResourceRequirement(app, true), domain.RoleDeployer))
+		u.Revoke(target) // not granted, should not trigger events
+		u.Revoke(app)
+
+		testutil.HasNEvents(t, &u, 3)
+		evt := testutil.EventIs[domain.UserGrantsChanged](t, &u, 2)
+		testutil.DeepEquals(t, domain.Grants{}, evt.Grants)
+	})
+}
+
+func Test_Role(t *testing.T) {
+	t.Run("should be parsed from a raw string", func(t *testing.T) {
+		role, err := domain.RoleFrom("deployer")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.RoleDeployer, role)
+
+		_, err = domain.RoleFrom("owner")
+		testutil.ErrorIs(t, domain.ErrInvalidRole, err)
+	})
+
+	t.Run("should give the appropriate permission", func(t *testing.T) {
+		testutil.Equals(t, domain.PermissionRead, domain.RoleReadOnly.Permission())
+		testutil.Equals(t, domain.PermissionDeploy, domain.RoleDeployer.Permission())
+		testutil.Equals(t, domain.PermissionManage, domain.RoleAdmin.Permission())
+	})
 }
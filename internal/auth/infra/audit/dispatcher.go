@@ -0,0 +1,55 @@
+// The package audit records actions performed by users in the audit log.
+package audit
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+type dispatcher struct {
+	bus.Dispatcher
+
+	writer domain.AuditEntriesWriter
+	logger log.Logger
+}
+
+// Wraps the given dispatcher to record every command sent on behalf of a user in the
+// audit log. Commands dispatched by handlers or scheduled jobs do not go through this
+// dispatcher and are therefore not recorded.
+func NewDispatcher(inner bus.Dispatcher, writer domain.AuditEntriesWriter, logger log.Logger) bus.Dispatcher {
+	return &dispatcher{
+		Dispatcher: inner,
+		writer:     writer,
+		logger:     logger,
+	}
+}
+
+func (d *dispatcher) Send(ctx context.Context, msg bus.Request) (any, error) {
+	result, err := d.Dispatcher.Send(ctx, msg)
+
+	if msg.Kind_() != bus.MessageKindCommand || !domain.CurrentUser(ctx).HasValue() {
+		return result, err
+	}
+
+	var resourceID string
+
+	if auditable, isAuditable := msg.(domain.Auditable); isAuditable {
+		resourceID = auditable.AuditResource(result)
+	}
+
+	entry := domain.NewAuditEntry(ctx, msg.Name_(), resourceID, err)
+
+	// The action has already been performed, so failing to record it should not
+	// change the response
+	if writeErr := d.writer.Write(ctx, &entry); writeErr != nil {
+		d.logger.Errorw("error while recording audit entry",
+			"action", msg.Name_(),
+			"correlation_id", bus.CorrelationID(ctx).Get(""),
+			"error", writeErr)
+	}
+
+	return result, err
+}
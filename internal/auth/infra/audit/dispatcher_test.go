@@ -0,0 +1,116 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/audit"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Dispatcher(t *testing.T) {
+	logger := must.Panic(log.NewLogger())
+	errFailed := errors.New("failed")
+
+	sut := func() (bus.Dispatcher, *auditEntriesWriter) {
+		local := memory.NewBus()
+		writer := &auditEntriesWriter{}
+
+		bus.Register(local, func(ctx context.Context, cmd createCommand) (string, error) {
+			if cmd.Fail {
+				return "", errFailed
+			}
+
+			return "aresource", nil
+		})
+		bus.Register(local, func(ctx context.Context, q getQuery) (string, error) {
+			return "aresource", nil
+		})
+
+		return audit.NewDispatcher(local, writer, logger), writer
+	}
+
+	t.Run("should not record commands sent without a user", func(t *testing.T) {
+		dispatcher, writer := sut()
+
+		_, err := bus.Send(dispatcher, context.Background(), createCommand{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, writer.entries, 0)
+	})
+
+	t.Run("should not record queries", func(t *testing.T) {
+		dispatcher, writer := sut()
+
+		_, err := bus.Send(dispatcher, domain.WithUserID(context.Background(), "auser"), getQuery{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, writer.entries, 0)
+	})
+
+	t.Run("should record commands sent on behalf of a user", func(t *testing.T) {
+		dispatcher, writer := sut()
+		ctx := bus.WithCorrelationID(domain.WithUserID(context.Background(), "auser"), "acorrelation")
+
+		result, err := bus.Send(dispatcher, ctx, createCommand{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "aresource", result)
+		testutil.HasLength(t, writer.entries, 1)
+		testutil.Equals(t, "auser", writer.entries[0].UserID.MustGet())
+		testutil.Equals(t, "test.command.create", writer.entries[0].Action)
+		testutil.Equals(t, "aresource", writer.entries[0].ResourceID.MustGet())
+		testutil.Equals(t, domain.AuditOutcomeSucceeded, writer.entries[0].Outcome)
+		testutil.IsFalse(t, writer.entries[0].Error.HasValue())
+		testutil.Equals(t, "acorrelation", writer.entries[0].CorrelationID.MustGet())
+	})
+
+	t.Run("should record failed commands", func(t *testing.T) {
+		dispatcher, writer := sut()
+
+		_, err := bus.Send(dispatcher, domain.WithUserID(context.Background(), "auser"), createCommand{Fail: true})
+
+		testutil.ErrorIs(t, errFailed, err)
+		testutil.HasLength(t, writer.entries, 1)
+		testutil.Equals(t, domain.AuditOutcomeFailed, writer.entries[0].Outcome)
+		testutil.Equals(t, "failed", writer.entries[0].Error.MustGet())
+		testutil.IsFalse(t, writer.entries[0].ResourceID.HasValue())
+	})
+}
+
+type (
+	createCommand struct {
+		bus.Command[string]
+
+		Fail bool
+	}
+
+	getQuery struct {
+		bus.Query[string]
+	}
+
+	auditEntriesWriter struct {
+		entries []domain.AuditEntryRecorded
+	}
+)
+
+func (createCommand) Name_() string                   { return "test.command.create" }
+func (createCommand) AuditResource(result any) string { return result.(string) }
+func (getQuery) Name_() string                        { return "test.query.get" }
+
+func (w *auditEntriesWriter) Write(ctx context.Context, entries ...*domain.AuditEntry) error {
+	for _, entry := range entries {
+		for _, e := range event.Unwrap(entry) {
+			w.entries = append(w.entries, e.(domain.AuditEntryRecorded))
+		}
+	}
+
+	return nil
+}
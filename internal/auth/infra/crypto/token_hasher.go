@@ -0,0 +1,21 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+)
+
+type tokenHasher struct{}
+
+// Builds a new token hasher. Tokens are random high entropy values so a fast and
+// deterministic hash is enough and makes it possible to look them up.
+func NewTokenHasher() domain.TokenHasher {
+	return &tokenHasher{}
+}
+
+func (*tokenHasher) Hash(value domain.APIKey) domain.TokenHash {
+	sum := sha256.Sum256([]byte(value))
+	return domain.TokenHash(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,20 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_TokenHasher(t *testing.T) {
+	t.Run("should hash a token in a deterministic way", func(t *testing.T) {
+		hasher := crypto.NewTokenHasher()
+
+		hash := hasher.Hash("atoken")
+
+		testutil.HasNChars(t, 64, hash)
+		testutil.Equals(t, hash, hasher.Hash("atoken"))
+		testutil.NotEquals(t, hash, hasher.Hash("anothertoken"))
+	})
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	LoginAttemptsStore interface {
+		domain.LoginAttemptsReader
+		domain.LoginAttemptsWriter
+	}
+
+	loginAttemptsStore struct {
+		attempts map[domain.Email]*domain.LoginAttempts
+	}
+)
+
+func NewLoginAttemptsStore(existingAttempts ...*domain.LoginAttempts) LoginAttemptsStore {
+	s := &loginAttemptsStore{
+		attempts: make(map[domain.Email]*domain.LoginAttempts),
+	}
+
+	s.Write(context.Background(), existingAttempts...)
+
+	return s
+}
+
+func (s *loginAttemptsStore) GetByEmail(ctx context.Context, email domain.Email) (domain.LoginAttempts, error) {
+	attempts, found := s.attempts[email]
+
+	if !found {
+		return domain.LoginAttempts{}, apperr.ErrNotFound
+	}
+
+	return *attempts, nil
+}
+
+func (s *loginAttemptsStore) Write(ctx context.Context, attempts ...*domain.LoginAttempts) error {
+	for _, a := range attempts {
+		for _, e := range event.Unwrap(a) {
+			switch e.(type) {
+			case domain.LoginAttemptsCleared:
+				delete(s.attempts, a.Email())
+			default:
+				value := *a
+				s.attempts[a.Email()] = &value
+			}
+		}
+	}
+
+	return nil
+}
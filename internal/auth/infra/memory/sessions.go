@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	SessionsStore interface {
+		domain.SessionsReader
+		domain.SessionsWriter
+	}
+
+	sessionsStore struct {
+		sessions []*sessionData
+	}
+
+	sessionData struct {
+		id    domain.SessionID
+		value *domain.Session
+	}
+)
+
+func NewSessionsStore(existingSessions ...*domain.Session) SessionsStore {
+	s := &sessionsStore{}
+
+	s.Write(context.Background(), existingSessions...)
+
+	return s
+}
+
+func (s *sessionsStore) GetByID(ctx context.Context, id domain.SessionID) (domain.Session, error) {
+	for _, sess := range s.sessions {
+		if sess.id == id {
+			return *sess.value, nil
+		}
+	}
+
+	return domain.Session{}, apperr.ErrNotFound
+}
+
+func (s *sessionsStore) Write(ctx context.Context, sessions ...*domain.Session) error {
+	for _, session := range sessions {
+		for _, e := range event.Unwrap(session) {
+			switch evt := e.(type) {
+			case domain.SessionCreated:
+				if slices.ContainsFunc(s.sessions, func(d *sessionData) bool { return d.id == evt.ID }) {
+					continue
+				}
+
+				s.sessions = append(s.sessions, &sessionData{
+					id:    evt.ID,
+					value: session,
+				})
+			case domain.SessionRevoked:
+				s.sessions = slices.DeleteFunc(s.sessions, func(d *sessionData) bool {
+					return d.id == evt.ID
+				})
+			default:
+				for _, d := range s.sessions {
+					if d.id == session.ID() {
+						*d.value = *session
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	TokensStore interface {
+		domain.TokensReader
+		domain.TokensWriter
+	}
+
+	tokensStore struct {
+		tokens []*tokenData
+	}
+
+	tokenData struct {
+		id    domain.TokenID
+		hash  domain.TokenHash
+		value *domain.Token
+	}
+)
+
+func NewTokensStore(existingTokens ...*domain.Token) TokensStore {
+	s := &tokensStore{}
+
+	s.Write(context.Background(), existingTokens...)
+
+	return s
+}
+
+func (s *tokensStore) GetByID(ctx context.Context, id domain.TokenID) (domain.Token, error) {
+	for _, t := range s.tokens {
+		if t.id == id {
+			return *t.value, nil
+		}
+	}
+
+	return domain.Token{}, apperr.ErrNotFound
+}
+
+func (s *tokensStore) GetByHash(ctx context.Context, hash domain.TokenHash) (domain.Token, error) {
+	for _, t := range s.tokens {
+		if t.hash == hash {
+			return *t.value, nil
+		}
+	}
+
+	return domain.Token{}, apperr.ErrNotFound
+}
+
+func (s *tokensStore) Write(ctx context.Context, tokens ...*domain.Token) error {
+	for _, token := range tokens {
+		for _, e := range event.Unwrap(token) {
+			switch evt := e.(type) {
+			case domain.TokenCreated:
+				if slices.ContainsFunc(s.tokens, func(t *tokenData) bool { return t.id == evt.ID }) {
+					continue
+				}
+
+				s.tokens = append(s.tokens, &tokenData{
+					id:    evt.ID,
+					hash:  evt.Hash,
+					value: token,
+				})
+			case domain.TokenRevoked:
+				s.tokens = slices.DeleteFunc(s.tokens, func(t *tokenData) bool {
+					return t.id == evt.ID
+				})
+			default:
+				for _, t := range s.tokens {
+					if t.id == token.ID() {
+						*t.value = *token
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -70,6 +70,16 @@ func (s *usersStore) GetByEmail(ctx context.Context, email domain.Email) (domain
 	return domain.User{}, apperr.ErrNotFound
 }
 
+func (s *usersStore) GetByExternalID(ctx context.Context, id domain.ExternalID) (domain.User, error) {
+	for _, u := range s.users {
+		if linked, isLinked := u.value.ExternalID().TryGet(); isLinked && linked == id {
+			return *u.value, nil
+		}
+	}
+
+	return domain.User{}, apperr.ErrNotFound
+}
+
 func (s *usersStore) GetIDFromAPIKey(ctx context.Context, key domain.APIKey) (domain.UserID, error) {
 	for _, u := range s.users {
 		if u.key == key {
@@ -80,6 +90,14 @@ func (s *usersStore) GetIDFromAPIKey(ctx context.Context, key domain.APIKey) (do
 	return "", apperr.ErrNotFound
 }
 
+func (s *usersStore) HasOwnedResources(ctx context.Context, id domain.UserID) (bool, error) {
+	return false, nil
+}
+
+func (s *usersStore) CheckResourceExistence(ctx context.Context, resource domain.Resource) (domain.ResourceRequirement, error) {
+	return domain.NewResourceRequirement(resource, true), nil
+}
+
 func (s *usersStore) Write(ctx context.Context, users ...*domain.User) error {
 	for _, user := range users {
 		for _, e := range event.Unwrap(user) {
@@ -111,6 +129,10 @@ func (s *usersStore) Write(ctx context.Context, users ...*domain.User) error {
 						break
 					}
 				}
+			case domain.UserDeleted:
+				s.users = slices.DeleteFunc(s.users, func(u *userData) bool {
+					return u.id == evt.ID
+				})
 			default:
 				for _, u := range s.users {
 					if u.id == user.ID() {
@@ -1,36 +1,102 @@
 package infra
 
 import (
+	"time"
+
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
 
 	"github.com/YuukanOO/seelf/internal/auth/app/create_first_account"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/delete_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/disable_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/enable_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/grant_role"
+	"github.com/YuukanOO/seelf/internal/auth/app/invite_user"
 	"github.com/YuukanOO/seelf/internal/auth/app/login"
+	"github.com/YuukanOO/seelf/internal/auth/app/login_external"
 	"github.com/YuukanOO/seelf/internal/auth/app/refresh_api_key"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_role"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/setup_account"
 	"github.com/YuukanOO/seelf/internal/auth/app/update_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/use_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/use_token"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/audit"
 	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
 	authsqlite "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
 )
 
+type Options interface {
+	SessionLifetime() time.Duration      // Maximum duration of a session, 0 to disable
+	SessionIdleTimeout() time.Duration   // Maximum duration without activity of a session, 0 to disable
+	LoginMaxAttempts() int               // Failed login attempts allowed before locking an email out, 0 to disable
+	LoginAttemptsWindow() time.Duration  // Duration during which failed login attempts are counted
+	LoginLockoutDuration() time.Duration // How long an email is locked out
+}
+
 // Setup the auth module
 func Setup(
+	opts Options,
 	logger log.Logger,
 	db *sqlite.Database,
 	b bus.Bus,
 ) (domain.UsersReader, error) {
 	usersStore := authsqlite.NewUsersStore(db)
-	authQueryHandler := authsqlite.NewGateway(db)
+	tokensStore := authsqlite.NewTokensStore(db)
+	sessionsStore := authsqlite.NewSessionsStore(db)
+	loginAttemptsStore := authsqlite.NewLoginAttemptsStore(db)
+	authQueryHandler := authsqlite.NewGateway(db.ReadOnly())
 
 	passwordHasher := crypto.NewBCryptHasher()
 	keyGenerator := crypto.NewKeyGenerator()
+	tokenHasher := crypto.NewTokenHasher()
+	sessionPolicy := domain.SessionPolicy{
+		Lifetime:    opts.SessionLifetime(),
+		IdleTimeout: opts.SessionIdleTimeout(),
+	}
+	lockoutPolicy := domain.LockoutPolicy{
+		MaxAttempts: opts.LoginMaxAttempts(),
+		Window:      opts.LoginAttemptsWindow(),
+		Duration:    opts.LoginLockoutDuration(),
+	}
 
-	bus.Register(b, login.Handler(usersStore, passwordHasher))
+	bus.Register(b, login.Handler(usersStore, loginAttemptsStore, loginAttemptsStore, passwordHasher, lockoutPolicy))
+	bus.Register(b, login_external.Handler(usersStore, usersStore, keyGenerator))
 	bus.Register(b, create_first_account.Handler(usersStore, usersStore, passwordHasher, keyGenerator))
+	bus.Register(b, setup_account.Handler(usersStore, usersStore, passwordHasher, keyGenerator))
 	bus.Register(b, update_user.Handler(usersStore, usersStore, passwordHasher))
 	bus.Register(b, refresh_api_key.Handler(usersStore, usersStore, keyGenerator))
+	bus.Register(b, invite_user.Handler(usersStore, usersStore, passwordHasher, keyGenerator))
+	bus.Register(b, disable_user.Handler(usersStore, usersStore))
+	bus.Register(b, enable_user.Handler(usersStore, usersStore))
+	bus.Register(b, delete_user.Handler(usersStore, usersStore))
+	bus.Register(b, grant_role.Handler(usersStore, usersStore))
+	bus.Register(b, revoke_role.Handler(usersStore, usersStore))
+	bus.Register(b, create_token.Handler(tokensStore, keyGenerator, tokenHasher))
+	bus.Register(b, revoke_token.Handler(tokensStore, tokensStore))
+	bus.Register(b, use_token.Handler(tokensStore, tokensStore, tokenHasher))
+	bus.Register(b, create_session.Handler(sessionsStore, sessionPolicy))
+	bus.Register(b, use_session.Handler(sessionsStore, sessionsStore, sessionPolicy))
+	bus.Register(b, revoke_session.Handler(sessionsStore, sessionsStore))
 	bus.Register(b, authQueryHandler.GetProfile)
+	bus.Register(b, authQueryHandler.GetUsers)
+	bus.Register(b, authQueryHandler.GetUserByID)
+	bus.Register(b, authQueryHandler.GetTokens)
+	bus.Register(b, authQueryHandler.GetTokenByID)
+	bus.Register(b, authQueryHandler.GetSessions)
+	bus.Register(b, authQueryHandler.GetAuditEntries)
+
+	bus.On(b, login.OnLoginLockedHandler(authsqlite.NewAuditStore(db)))
 
 	return usersStore, db.Migrate(authsqlite.Migrations)
 }
+
+// Wraps the given dispatcher so that commands sent by users are recorded in the audit log.
+func Audited(dispatcher bus.Dispatcher, logger log.Logger, db *sqlite.Database) bus.Dispatcher {
+	return audit.NewDispatcher(dispatcher, authsqlite.NewAuditStore(db), logger)
+}
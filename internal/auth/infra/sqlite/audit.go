@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type auditStore struct {
+	db *sqlite.Database
+}
+
+func NewAuditStore(db *sqlite.Database) domain.AuditEntriesWriter {
+	return &auditStore{db}
+}
+
+func (s *auditStore) Write(c context.Context, entries ...*domain.AuditEntry) error {
+	return sqlite.WriteAndDispatch(s.db, c, entries, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.AuditEntryRecorded:
+			return builder.
+				Insert("audit", builder.Values{
+					"id":             evt.ID,
+					"user_id":        evt.UserID,
+					"action":         evt.Action,
+					"resource_id":    evt.ResourceID,
+					"outcome":        evt.Outcome,
+					"error":          evt.Error,
+					"correlation_id": evt.CorrelationID,
+					"occurred_at":    evt.OccurredAt,
+				}).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
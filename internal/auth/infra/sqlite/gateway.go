@@ -2,8 +2,18 @@ package sqlite
 
 import (
 	"context"
+	"time"
 
+	"github.com/YuukanOO/seelf/internal/auth/app/get_audit_entries"
 	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_sessions"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_token"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_tokens"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/get_users"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
@@ -30,6 +40,156 @@ func (s *gateway) GetProfile(ctx context.Context, q get_profile.Query) (get_prof
 		One(s.db, ctx, profileMapper)
 }
 
+func (s *gateway) GetUsers(ctx context.Context, q get_users.Query) ([]get_user.User, error) {
+	return builder.
+		Query[get_user.User](`
+			SELECT
+				id
+				,email
+				,is_admin
+				,grants
+				,disabled_at
+				,registered_at
+			FROM users
+			WHERE TRUE`).
+		S(builder.MaybeValue(domain.RestrictedTo(ctx), "AND id = ?")).
+		F("ORDER BY registered_at").
+		All(s.db, ctx, userMapper)
+}
+
+func (s *gateway) GetUserByID(ctx context.Context, q get_user.Query) (get_user.User, error) {
+	return builder.
+		Query[get_user.User](`
+			SELECT
+				id
+				,email
+				,is_admin
+				,grants
+				,disabled_at
+				,registered_at
+			FROM users
+			WHERE id = ?`, q.ID).
+		S(builder.MaybeValue(domain.RestrictedTo(ctx), "AND id = ?")).
+		One(s.db, ctx, userMapper)
+}
+
+func (s *gateway) GetTokens(ctx context.Context, q get_tokens.Query) ([]get_token.Token, error) {
+	return builder.
+		Query[get_token.Token](`
+			SELECT
+				id
+				,name
+				,scopes
+				,last_used_at
+				,created_at
+			FROM tokens
+			WHERE created_by = ?
+			ORDER BY created_at`, domain.CurrentUser(ctx).Get("")).
+		All(s.db, ctx, tokenMapper, getTokenDailyUsageDataloader, getTokenEndpointsUsageDataloader)
+}
+
+func (s *gateway) GetTokenByID(ctx context.Context, q get_token.Query) (get_token.Token, error) {
+	return builder.
+		Query[get_token.Token](`
+			SELECT
+				id
+				,name
+				,scopes
+				,last_used_at
+				,created_at
+			FROM tokens
+			WHERE id = ? AND created_by = ?`, q.ID, domain.CurrentUser(ctx).Get("")).
+		One(s.db, ctx, tokenMapper, getTokenDailyUsageDataloader, getTokenEndpointsUsageDataloader)
+}
+
+func (s *gateway) GetSessions(ctx context.Context, q get_sessions.Query) ([]get_sessions.Session, error) {
+	return builder.
+		Query[get_sessions.Session](`
+			SELECT
+				id
+				,device
+				,ip
+				,id = ?
+				,created_at
+				,last_seen_at
+				,expires_at
+			FROM sessions
+			WHERE user_id = ? AND (expires_at IS NULL OR expires_at > ?)
+			ORDER BY last_seen_at DESC`, q.CurrentID, domain.CurrentUser(ctx).Get(""), time.Now().UTC()).
+		All(s.db, ctx, sessionMapper)
+}
+
+func (s *gateway) GetAuditEntries(ctx context.Context, q get_audit_entries.Query) (storage.Paginated[get_audit_entries.AuditEntry], error) {
+	if !domain.HasAdminRights(ctx) {
+		return storage.Paginated[get_audit_entries.AuditEntry]{}, apperr.ErrForbidden
+	}
+
+	return builder.
+		Select[get_audit_entries.AuditEntry](`
+			audit.id
+			,audit.user_id
+			,users.email
+			,audit.action
+			,audit.resource_id
+			,audit.outcome
+			,audit.error
+			,audit.correlation_id
+			,audit.occurred_at`).
+		F(`
+			FROM audit
+			LEFT JOIN users ON users.id = audit.user_id
+			WHERE TRUE`).
+		S(
+			builder.MaybeValue(q.UserID, "AND audit.user_id = ?"),
+			builder.MaybeValue(q.Action, "AND audit.action = ?"),
+			builder.MaybeValue(q.ResourceID, "AND audit.resource_id = ?"),
+			builder.MaybeValue(q.Outcome, "AND audit.outcome = ?"),
+		).
+		F("ORDER BY audit.occurred_at DESC").
+		Paginate(s.db, ctx, auditEntryMapper, q.Page.Get(1), 50)
+}
+
+var getTokenDailyUsageDataloader = builder.NewDataloader(
+	func(t get_token.Token) string { return t.ID },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_token.Token]) error {
+		_, err := builder.
+			Query[get_token.DailyUsage](`
+			SELECT
+				token_id
+				,day
+				,SUM(count)
+			FROM token_usage`).
+			S(builder.Array("WHERE token_id IN", kr.Keys())).
+			F("AND day >= ? GROUP BY token_id, day ORDER BY token_id, day DESC", tokenUsageSince()).
+			All(e, ctx, tokenDailyUsageMapper(kr))
+
+		return err
+	})
+
+var getTokenEndpointsUsageDataloader = builder.NewDataloader(
+	func(t get_token.Token) string { return t.ID },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_token.Token]) error {
+		_, err := builder.
+			Query[get_token.EndpointUsage](`
+			SELECT
+				token_id
+				,endpoint
+				,SUM(count) AS total
+				,last_used_at
+				,MAX(last_used_at) AS max_last_used_at
+			FROM token_usage`).
+			S(builder.Array("WHERE token_id IN", kr.Keys())).
+			F("AND day >= ? GROUP BY token_id, endpoint ORDER BY token_id, total DESC, endpoint", tokenUsageSince()).
+			All(e, ctx, tokenEndpointUsageMapper(kr))
+
+		return err
+	})
+
+// First day for which usage statistics are returned.
+func tokenUsageSince() string {
+	return time.Now().UTC().Add(-domain.TokenUsageRetention).Format(tokenUsageDayLayout)
+}
+
 func profileMapper(row storage.Scanner) (p get_profile.Profile, err error) {
 	err = row.Scan(
 		&p.ID,
@@ -40,3 +200,125 @@ func profileMapper(row storage.Scanner) (p get_profile.Profile, err error) {
 
 	return p, err
 }
+
+func userMapper(row storage.Scanner) (u get_user.User, err error) {
+	err = row.Scan(
+		&u.ID,
+		&u.Email,
+		&u.IsAdmin,
+		&u.Grants,
+		&u.DisabledAt,
+		&u.RegisteredAt,
+	)
+
+	return u, err
+}
+
+func tokenMapper(row storage.Scanner) (t get_token.Token, err error) {
+	err = row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Scopes,
+		&t.LastUsedAt,
+		&t.CreatedAt,
+	)
+
+	t.Usage.Days = make([]get_token.DailyUsage, 0)
+	t.Usage.Endpoints = make([]get_token.EndpointUsage, 0)
+
+	return t, err
+}
+
+func tokenDailyUsageMapper(kr storage.KeyedResult[get_token.Token]) storage.Mapper[get_token.DailyUsage] {
+	return func(scanner storage.Scanner) (u get_token.DailyUsage, err error) {
+		var tokenID string
+
+		err = scanner.Scan(
+			&tokenID,
+			&u.Day,
+			&u.Count,
+		)
+
+		if err != nil {
+			return u, err
+		}
+
+		kr.Update(tokenID, func(t get_token.Token) get_token.Token {
+			t.Usage.Total += u.Count
+			t.Usage.Days = append(t.Usage.Days, u)
+			return t
+		})
+
+		return u, err
+	}
+}
+
+func tokenEndpointUsageMapper(kr storage.KeyedResult[get_token.Token]) storage.Mapper[get_token.EndpointUsage] {
+	return func(scanner storage.Scanner) (u get_token.EndpointUsage, err error) {
+		var (
+			tokenID       string
+			maxLastUsedAt string
+		)
+
+		err = scanner.Scan(
+			&tokenID,
+			&u.Endpoint,
+			&u.Count,
+			&u.LastUsedAt,
+			&maxLastUsedAt,
+		)
+
+		if err != nil {
+			return u, err
+		}
+
+		kr.Update(tokenID, func(t get_token.Token) get_token.Token {
+			t.Usage.Endpoints = append(t.Usage.Endpoints, u)
+			return t
+		})
+
+		return u, err
+	}
+}
+
+func sessionMapper(row storage.Scanner) (s get_sessions.Session, err error) {
+	err = row.Scan(
+		&s.ID,
+		&s.Device,
+		&s.IP,
+		&s.Current,
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+	)
+
+	return s, err
+}
+
+func auditEntryMapper(row storage.Scanner) (e get_audit_entries.AuditEntry, err error) {
+	var (
+		userID    monad.Maybe[string]
+		userEmail monad.Maybe[string]
+	)
+
+	err = row.Scan(
+		&e.ID,
+		&userID,
+		&userEmail,
+		&e.Action,
+		&e.ResourceID,
+		&e.Outcome,
+		&e.Error,
+		&e.CorrelationID,
+		&e.OccurredAt,
+	)
+
+	if id, isSet := userID.TryGet(); isSet {
+		e.User.Set(get_audit_entries.UserSummary{
+			ID:    id,
+			Email: userEmail,
+		})
+	}
+
+	return e, err
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	LoginAttemptsStore interface {
+		domain.LoginAttemptsReader
+		domain.LoginAttemptsWriter
+	}
+
+	loginAttemptsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewLoginAttemptsStore(db *sqlite.Database) LoginAttemptsStore {
+	return &loginAttemptsStore{db}
+}
+
+func (s *loginAttemptsStore) GetByEmail(ctx context.Context, email domain.Email) (domain.LoginAttempts, error) {
+	return builder.
+		Query[domain.LoginAttempts](`
+			SELECT
+				email
+				,failures
+				,window_started_at
+				,locked_until
+			FROM login_attempts
+			WHERE email = ?`, email).
+		One(s.db, ctx, domain.LoginAttemptsFrom)
+}
+
+func (s *loginAttemptsStore) Write(c context.Context, attempts ...*domain.LoginAttempts) error {
+	return sqlite.WriteAndDispatch(s.db, c, attempts, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.LoginAttemptFailed:
+			return builder.
+				Command(`
+					INSERT INTO login_attempts (email, failures, window_started_at)
+					VALUES (?, ?, ?)
+					ON CONFLICT(email) DO UPDATE SET
+						failures = excluded.failures
+						,window_started_at = excluded.window_started_at`,
+					evt.Email, evt.Failures, evt.WindowStartedAt).
+				Exec(s.db, ctx)
+		case domain.LoginLocked:
+			return builder.
+				Update("login_attempts", builder.Values{
+					"failures":     0,
+					"locked_until": evt.Until,
+				}).
+				F("WHERE email = ?", evt.Email).
+				Exec(s.db, ctx)
+		case domain.LoginAttemptsCleared:
+			return builder.
+				Command("DELETE FROM login_attempts WHERE email = ?", evt.Email).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
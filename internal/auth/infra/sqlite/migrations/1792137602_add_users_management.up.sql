@@ -0,0 +1,5 @@
+ALTER TABLE users ADD is_admin BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD disabled_at DATETIME NULL;
+
+-- The oldest user is the one created on first launch and becomes the admin.
+UPDATE users SET is_admin = true WHERE id = (SELECT id FROM users ORDER BY registered_at LIMIT 1);
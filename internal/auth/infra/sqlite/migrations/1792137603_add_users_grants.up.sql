@@ -0,0 +1 @@
+ALTER TABLE users ADD grants TEXT NOT NULL DEFAULT '{}';
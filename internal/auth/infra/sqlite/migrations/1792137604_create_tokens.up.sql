@@ -0,0 +1,13 @@
+CREATE TABLE tokens (
+    id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    last_used_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    created_by TEXT NOT NULL,
+
+    CONSTRAINT pk_tokens PRIMARY KEY(id),
+    CONSTRAINT unique_tokens_hash UNIQUE(hash),
+    CONSTRAINT fk_tokens_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
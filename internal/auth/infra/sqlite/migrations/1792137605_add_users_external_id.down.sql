@@ -0,0 +1,3 @@
+DROP INDEX unique_users_external_id;
+
+ALTER TABLE users DROP COLUMN external_id;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD external_id TEXT NULL;
+
+CREATE UNIQUE INDEX unique_users_external_id ON users(external_id);
@@ -0,0 +1,14 @@
+CREATE TABLE audit (
+    id TEXT NOT NULL,
+    user_id TEXT NULL, -- No foreign key, entries must outlive deleted users
+    action TEXT NOT NULL,
+    resource_id TEXT NULL,
+    outcome TEXT NOT NULL,
+    error TEXT NULL,
+    correlation_id TEXT NULL,
+    occurred_at DATETIME NOT NULL,
+
+    CONSTRAINT pk_audit PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_audit_occurred_at ON audit(occurred_at);
@@ -0,0 +1,8 @@
+CREATE TABLE login_attempts (
+    email TEXT NOT NULL,
+    failures INTEGER NOT NULL,
+    window_started_at DATETIME NOT NULL,
+    locked_until DATETIME NULL,
+
+    CONSTRAINT pk_login_attempts PRIMARY KEY(email)
+);
@@ -0,0 +1 @@
+DROP TABLE token_usage;
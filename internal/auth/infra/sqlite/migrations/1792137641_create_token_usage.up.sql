@@ -0,0 +1,10 @@
+CREATE TABLE token_usage (
+    token_id TEXT NOT NULL,
+    day TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    count INTEGER NOT NULL,
+    last_used_at DATETIME NOT NULL,
+
+    CONSTRAINT pk_token_usage PRIMARY KEY(token_id, day, endpoint),
+    CONSTRAINT fk_token_usage_token_id FOREIGN KEY(token_id) REFERENCES tokens(id) ON DELETE CASCADE
+);
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	SessionsStore interface {
+		domain.SessionsReader
+		domain.SessionsWriter
+	}
+
+	sessionsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewSessionsStore(db *sqlite.Database) SessionsStore {
+	return &sessionsStore{db}
+}
+
+func (s *sessionsStore) GetByID(ctx context.Context, id domain.SessionID) (domain.Session, error) {
+	return builder.
+		Query[domain.Session](`
+			SELECT
+				id
+				,user_id
+				,device
+				,ip
+				,created_at
+				,last_seen_at
+				,expires_at
+			FROM sessions
+			WHERE id = ?`, id).
+		One(s.db, ctx, domain.SessionFrom)
+}
+
+func (s *sessionsStore) Write(c context.Context, sessions ...*domain.Session) error {
+	return sqlite.WriteAndDispatch(s.db, c, sessions, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.SessionCreated:
+			// Take this opportunity to purge expired sessions of this user
+			if err := builder.
+				Command("DELETE FROM sessions WHERE user_id = ? AND expires_at <= ?", evt.UserID, time.Now().UTC()).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			return builder.
+				Insert("sessions", builder.Values{
+					"id":           evt.ID,
+					"user_id":      evt.UserID,
+					"device":       evt.Device,
+					"ip":           evt.IP,
+					"created_at":   evt.CreatedAt,
+					"last_seen_at": evt.CreatedAt,
+					"expires_at":   evt.ExpiresAt,
+				}).
+				Exec(s.db, ctx)
+		case domain.SessionSeen:
+			return builder.
+				Update("sessions", builder.Values{
+					"ip":           evt.IP,
+					"last_seen_at": evt.SeenAt,
+					"expires_at":   evt.ExpiresAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.SessionRevoked:
+			return builder.
+				Command("DELETE FROM sessions WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+// Usage statistics are aggregated by day, stored using this layout.
+const tokenUsageDayLayout = time.DateOnly
+
+type (
+	TokensStore interface {
+		domain.TokensReader
+		domain.TokensWriter
+	}
+
+	tokensStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewTokensStore(db *sqlite.Database) TokensStore {
+	return &tokensStore{db}
+}
+
+func (s *tokensStore) GetByID(ctx context.Context, id domain.TokenID) (domain.Token, error) {
+	return builder.
+		Query[domain.Token](`
+			SELECT
+				id
+				,name
+				,hash
+				,scopes
+				,last_used_at
+				,created_at
+				,created_by
+			FROM tokens
+			WHERE id = ?`, id).
+		One(s.db, ctx, domain.TokenFrom)
+}
+
+func (s *tokensStore) GetByHash(ctx context.Context, hash domain.TokenHash) (domain.Token, error) {
+	return builder.
+		Query[domain.Token](`
+			SELECT
+				id
+				,name
+				,hash
+				,scopes
+				,last_used_at
+				,created_at
+				,created_by
+			FROM tokens
+			WHERE hash = ?`, hash).
+		One(s.db, ctx, domain.TokenFrom)
+}
+
+func (s *tokensStore) Write(c context.Context, tokens ...*domain.Token) error {
+	return sqlite.WriteAndDispatch(s.db, c, tokens, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.TokenCreated:
+			return builder.
+				Insert("tokens", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"hash":       evt.Hash,
+					"scopes":     evt.Scopes,
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.TokenUsed:
+			if err := builder.
+				Update("tokens", builder.Values{
+					"last_used_at": evt.UsedAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			if err := builder.
+				Command(`
+					INSERT INTO token_usage (token_id, day, endpoint, count, last_used_at)
+					VALUES (?, ?, ?, 1, ?)
+					ON CONFLICT(token_id, day, endpoint) DO UPDATE SET
+						count = count + 1
+						,last_used_at = excluded.last_used_at`,
+					evt.ID, evt.UsedAt.Format(tokenUsageDayLayout), evt.Endpoint, evt.UsedAt).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			return builder.
+				Command("DELETE FROM token_usage WHERE token_id = ? AND day < ?",
+					evt.ID, evt.UsedAt.Add(-domain.TokenUsageRetention).Format(tokenUsageDayLayout)).
+				Exec(s.db, ctx)
+		case domain.TokenRevoked:
+			if err := builder.
+				Command("DELETE FROM token_usage WHERE token_id = ?", evt.ID).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			return builder.
+				Command("DELETE FROM tokens WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -32,6 +32,10 @@ func (s *usersStore) GetAdminUser(ctx context.Context) (domain.User, error) {
 			,email
 			,password_hash
 			,api_key
+			,is_admin
+			,grants
+			,external_id
+			,disabled_at
 			,registered_at
 		FROM users
 		ORDER BY registered_at ASC
@@ -57,6 +61,10 @@ func (s *usersStore) GetByID(ctx context.Context, id domain.UserID) (u domain.Us
 				,email
 				,password_hash
 				,api_key
+				,is_admin
+				,grants
+				,external_id
+				,disabled_at
 				,registered_at
 			FROM users
 			WHERE id = ?`, id).
@@ -71,18 +79,85 @@ func (s *usersStore) GetByEmail(ctx context.Context, email domain.Email) (u doma
 				,email
 				,password_hash
 				,api_key
+				,is_admin
+				,grants
+				,external_id
+				,disabled_at
 				,registered_at
 			FROM users
 			WHERE email = ?`, email).
 		One(s.db, ctx, domain.UserFrom)
 }
 
+func (s *usersStore) GetByExternalID(ctx context.Context, id domain.ExternalID) (u domain.User, err error) {
+	return builder.
+		Query[domain.User](`
+			SELECT
+				id
+				,email
+				,password_hash
+				,api_key
+				,is_admin
+				,grants
+				,external_id
+				,disabled_at
+				,registered_at
+			FROM users
+			WHERE external_id = ?`, id).
+		One(s.db, ctx, domain.UserFrom)
+}
+
 func (s *usersStore) GetIDFromAPIKey(ctx context.Context, key domain.APIKey) (domain.UserID, error) {
 	return builder.
 		Query[domain.UserID]("SELECT id FROM users WHERE api_key = ?", key).
 		Extract(s.db, ctx)
 }
 
+// Resources shared with other users are checked here. Personal ones (sessions, API tokens,
+// saved filters and email preferences) are deleted along with the user by foreign keys.
+func (s *usersStore) HasOwnedResources(ctx context.Context, id domain.UserID) (bool, error) {
+	return builder.
+		Query[bool](`
+			SELECT
+				EXISTS(SELECT 1 FROM apps WHERE created_by = ?1 OR cleanup_requested_by = ?1 OR deploy_trigger_by = ?1)
+				OR EXISTS(SELECT 1 FROM targets WHERE created_by = ?1 OR cleanup_requested_by = ?1)
+				OR EXISTS(SELECT 1 FROM registries WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM peers WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM dns_zones WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM teams WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM deployments WHERE requested_by = ?1 OR unfrozen_by = ?1)
+				OR EXISTS(SELECT 1 FROM webhooks WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM channels WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM monitors WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM addons WHERE created_by = ?1 OR cleanup_requested_by = ?1)
+				OR EXISTS(SELECT 1 FROM addon_backups WHERE restore_requested_by = ?1)
+				OR EXISTS(SELECT 1 FROM env_revisions WHERE created_by = ?1)
+				OR EXISTS(SELECT 1 FROM app_archives WHERE requested_by = ?1)
+				OR EXISTS(SELECT 1 FROM trashed_apps WHERE created_by = ?1 OR deleted_by = ?1)`, id).
+		Extract(s.db, ctx)
+}
+
+func (s *usersStore) CheckResourceExistence(ctx context.Context, resource domain.Resource) (domain.ResourceRequirement, error) {
+	var table string
+
+	switch resource.Type() {
+	case domain.ResourceApp:
+		table = "apps"
+	case domain.ResourceTarget:
+		table = "targets"
+	case domain.ResourceTeam:
+		table = "teams"
+	default:
+		return domain.NewResourceRequirement(resource, false), nil
+	}
+
+	exists, err := builder.
+		Query[bool]("SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = ?)", resource.ID()).
+		Extract(s.db, ctx)
+
+	return domain.NewResourceRequirement(resource, exists), err
+}
+
 func (s *usersStore) Write(c context.Context, users ...*domain.User) error {
 	return sqlite.WriteAndDispatch(s.db, c, users, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
@@ -117,6 +192,45 @@ func (s *usersStore) Write(c context.Context, users ...*domain.User) error {
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
+		case domain.UserAdminRightsChanged:
+			return builder.
+				Update("users", builder.Values{
+					"is_admin": evt.IsAdmin,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.UserGrantsChanged:
+			return builder.
+				Update("users", builder.Values{
+					"grants": evt.Grants,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.UserLinked:
+			return builder.
+				Update("users", builder.Values{
+					"external_id": evt.ExternalID,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.UserDisabled:
+			return builder.
+				Update("users", builder.Values{
+					"disabled_at": evt.DisabledAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.UserEnabled:
+			return builder.
+				Update("users", builder.Values{
+					"disabled_at": nil,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.UserDeleted:
+			return builder.
+				Command("DELETE FROM users WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
 		default:
 			return nil
 		}
@@ -0,0 +1,277 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	authsqlite "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UsersStore_HasOwnedResources(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Foreign keys are not enforced here so each resource could be inserted on its own.
+	open := func(t *testing.T) *sqlite.Database {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db"), logger, memory.NewBus())
+
+		testutil.IsNil(t, err)
+
+		t.Cleanup(func() {
+			db.Close()
+		})
+
+		testutil.IsNil(t, db.Migrate(startup.MigrationsModules...))
+
+		return db
+	}
+
+	app := func(column string) builder.Values {
+		values := builder.Values{
+			"id":                 "app",
+			"name":               "my-app",
+			"production_target":  "target",
+			"production_version": now,
+			"staging_target":     "target",
+			"staging_version":    now,
+			"created_at":         now,
+			"created_by":         "other",
+		}
+		values[column] = "uid"
+
+		return values
+	}
+
+	target := func(column string) builder.Values {
+		values := builder.Values{
+			"id":                   "target",
+			"name":                 "my-target",
+			"url":                  "http://docker.localhost",
+			"provider_kind":        "docker",
+			"provider_fingerprint": "fingerprint",
+			"provider":             "{}",
+			"state_status":         0,
+			"state_version":        now,
+			"created_at":           now,
+			"created_by":           "other",
+		}
+		values[column] = "uid"
+
+		return values
+	}
+
+	deployment := func(column string) builder.Values {
+		values := builder.Values{
+			"app_id":               "app",
+			"deployment_number":    1,
+			"config_appid":         "app",
+			"config_appname":       "my-app",
+			"config_environment":   "production",
+			"config_target":        "target",
+			"config_vars":          "{}",
+			"state_status":         0,
+			"source_discriminator": "raw",
+			"source":               "{}",
+			"requested_at":         now,
+			"requested_by":         "other",
+		}
+		values[column] = "uid"
+
+		return values
+	}
+
+	addon := func(column string) builder.Values {
+		values := builder.Values{
+			"id":            "addon",
+			"app_id":        "app",
+			"environment":   "production",
+			"kind":          "postgres",
+			"variable":      "DATABASE_URL",
+			"username":      "user",
+			"password":      "password",
+			"target_id":     "target",
+			"state_status":  0,
+			"state_version": now,
+			"created_at":    now,
+			"created_by":    "other",
+		}
+		values[column] = "uid"
+
+		return values
+	}
+
+	trashedApp := func(column string) builder.Values {
+		values := builder.Values{
+			"id":                 "app",
+			"name":               "my-app",
+			"production_target":  "target",
+			"production_version": now,
+			"staging_target":     "target",
+			"staging_version":    now,
+			"created_by":         "other",
+			"deleted_at":         now,
+			"deleted_by":         "other",
+			"expires_at":         now,
+		}
+		values[column] = "uid"
+
+		return values
+	}
+
+	tests := []struct {
+		name   string
+		table  string
+		values builder.Values
+	}{
+		{"created apps", "apps", app("created_by")},
+		{"apps for which the cleanup has been requested", "apps", app("cleanup_requested_by")},
+		{"apps whose deploy trigger has been configured", "apps", app("deploy_trigger_by")},
+		{"created targets", "targets", target("created_by")},
+		{"targets for which the cleanup has been requested", "targets", target("cleanup_requested_by")},
+		{"created registries", "registries", builder.Values{
+			"id":         "registry",
+			"name":       "my-registry",
+			"url":        "https://registry.example.com",
+			"created_at": now,
+			"created_by": "uid",
+		}},
+		{"created peers", "peers", builder.Values{
+			"id":         "peer",
+			"name":       "my-peer",
+			"url":        "https://peer.example.com",
+			"token":      "token",
+			"created_at": now,
+			"created_by": "uid",
+		}},
+		{"created dns zones", "dns_zones", builder.Values{
+			"id":          "zone",
+			"name":        "example.com",
+			"provider":    "cloudflare",
+			"credentials": "credentials",
+			"created_at":  now,
+			"created_by":  "uid",
+		}},
+		{"created teams", "teams", builder.Values{
+			"id":         "team",
+			"name":       "my-team",
+			"created_at": now,
+			"created_by": "uid",
+		}},
+		{"requested deployments", "deployments", deployment("requested_by")},
+		{"deployments allowed during a freeze window", "deployments", deployment("unfrozen_by")},
+		{"created webhooks", "webhooks", builder.Values{
+			"id":         "webhook",
+			"name":       "my-webhook",
+			"url":        "https://example.com/hook",
+			"secret":     "secret",
+			"events":     "[]",
+			"created_at": now,
+			"created_by": "uid",
+		}},
+		{"created notification channels", "channels", builder.Values{
+			"id":         "channel",
+			"name":       "my-channel",
+			"config":     "{}",
+			"created_at": now,
+			"created_by": "uid",
+		}},
+		{"created monitors", "monitors", builder.Values{
+			"id":            "monitor",
+			"app_id":        "app",
+			"environment":   "production",
+			"interval":      60,
+			"status_code":   200,
+			"status":        0,
+			"next_check_at": now,
+			"created_at":    now,
+			"created_by":    "uid",
+		}},
+		{"created addons", "addons", addon("created_by")},
+		{"addons for which the cleanup has been requested", "addons", addon("cleanup_requested_by")},
+		{"addon backups for which a restore has been requested", "addon_backups", builder.Values{
+			"id":                   "backup",
+			"addon_id":             "addon",
+			"app_id":               "app",
+			"status":               0,
+			"size":                 0,
+			"restore_requested_at": now,
+			"restore_requested_by": "uid",
+			"created_at":           now,
+		}},
+		{"created environment variables revisions", "env_revisions", builder.Values{
+			"id":          "revision",
+			"app_id":      "app",
+			"environment": "production",
+			"changes":     "[]",
+			"created_at":  now,
+			"created_by":  "uid",
+		}},
+		{"requested app archives", "app_archives", builder.Values{
+			"id":           "archive",
+			"app_id":       "app",
+			"app_name":     "my-app",
+			"status":       0,
+			"size":         0,
+			"requested_at": now,
+			"requested_by": "uid",
+		}},
+		{"created trashed apps", "trashed_apps", trashedApp("created_by")},
+		{"deleted trashed apps", "trashed_apps", trashedApp("deleted_by")},
+	}
+
+	for _, test := range tests {
+		t.Run("should consider "+test.name+" as owned resources", func(t *testing.T) {
+			db := open(t)
+			store := authsqlite.NewUsersStore(db)
+
+			testutil.IsNil(t, builder.Insert(test.table, test.values).Exec(db, ctx))
+
+			owned, err := store.HasOwnedResources(ctx, domain.UserID("uid"))
+
+			testutil.IsNil(t, err)
+			testutil.IsTrue(t, owned)
+
+			owned, err = store.HasOwnedResources(ctx, domain.UserID("another"))
+
+			testutil.IsNil(t, err)
+			testutil.IsFalse(t, owned)
+		})
+	}
+
+	t.Run("should not consider personal resources as owned ones", func(t *testing.T) {
+		db := open(t)
+		store := authsqlite.NewUsersStore(db)
+
+		testutil.IsNil(t, builder.Insert("tokens", builder.Values{
+			"id":         "token",
+			"name":       "my-token",
+			"hash":       "hash",
+			"scopes":     "[]",
+			"created_at": now,
+			"created_by": "uid",
+		}).Exec(db, ctx))
+		testutil.IsNil(t, builder.Insert("saved_filters", builder.Values{
+			"id":         "filter",
+			"name":       "my-filter",
+			"kind":       "apps",
+			"criteria":   "{}",
+			"created_at": now,
+			"created_by": "uid",
+		}).Exec(db, ctx))
+
+		owned, err := store.HasOwnedResources(ctx, domain.UserID("uid"))
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, owned)
+	})
+}
@@ -0,0 +1,21 @@
+package export_data
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Export the instance data as a portable bundle.
+type Command struct {
+	bus.Command[domain.Bundle]
+}
+
+func (Command) Name_() string { return "backup.command.export_data" }
+
+func Handler(exporter domain.Exporter) bus.RequestHandler[domain.Bundle, Command] {
+	return func(ctx context.Context, cmd Command) (domain.Bundle, error) {
+		return exporter.Export(ctx)
+	}
+}
@@ -0,0 +1,35 @@
+package export_data_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/backup/app/export_data"
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	"github.com/YuukanOO/seelf/internal/backup/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ExportData(t *testing.T) {
+	sut := func(existing domain.Bundle) bus.RequestHandler[domain.Bundle, export_data.Command] {
+		return export_data.Handler(memory.NewStore(existing))
+	}
+
+	t.Run("should export the instance data with the current bundle version", func(t *testing.T) {
+		uc := sut(domain.Bundle{
+			Users: []domain.Record{{"id": "uid", "email": "john@doe.com"}},
+			Apps:  []domain.Record{{"id": "aid", "name": "my-app"}},
+		})
+
+		bundle, err := uc(context.Background(), export_data.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.BundleVersion, bundle.Version)
+		testutil.IsFalse(t, bundle.ExportedAt.IsZero())
+		testutil.HasLength(t, bundle.Users, 1)
+		testutil.HasLength(t, bundle.Apps, 1)
+		testutil.HasLength(t, bundle.Targets, 0)
+		testutil.HasLength(t, bundle.Registries, 0)
+	})
+}
@@ -0,0 +1,37 @@
+package import_data
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Import a bundle previously exported into an empty instance.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Bundle domain.Bundle `json:"bundle"`
+}
+
+func (Command) Name_() string { return "backup.command.import_data" }
+
+func Handler(importer domain.Importer) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !cmd.Bundle.IsSupported() {
+			return bus.Unit, domain.ErrUnsupportedBundleVersion
+		}
+
+		empty, err := importer.IsEmpty(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if !empty {
+			return bus.Unit, domain.ErrInstanceNotEmpty
+		}
+
+		return bus.Unit, importer.Import(ctx, cmd.Bundle)
+	}
+}
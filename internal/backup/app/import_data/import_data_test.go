@@ -0,0 +1,58 @@
+package import_data_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/backup/app/import_data"
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	"github.com/YuukanOO/seelf/internal/backup/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ImportData(t *testing.T) {
+	sut := func(existing domain.Bundle) (bus.RequestHandler[bus.UnitType, import_data.Command], memory.Store) {
+		store := memory.NewStore(existing)
+		return import_data.Handler(store), store
+	}
+
+	t.Run("should require a supported bundle version", func(t *testing.T) {
+		uc, _ := sut(domain.Bundle{})
+
+		_, err := uc(context.Background(), import_data.Command{
+			Bundle: domain.Bundle{Version: domain.BundleVersion + 1},
+		})
+
+		testutil.ErrorIs(t, domain.ErrUnsupportedBundleVersion, err)
+	})
+
+	t.Run("should require an empty instance", func(t *testing.T) {
+		uc, _ := sut(domain.Bundle{
+			Users: []domain.Record{{"id": "uid"}},
+		})
+
+		_, err := uc(context.Background(), import_data.Command{
+			Bundle: domain.NewBundle(),
+		})
+
+		testutil.ErrorIs(t, domain.ErrInstanceNotEmpty, err)
+	})
+
+	t.Run("should import the given bundle", func(t *testing.T) {
+		uc, store := sut(domain.Bundle{})
+		bundle := domain.NewBundle()
+		bundle.Users = []domain.Record{{"id": "uid", "email": "john@doe.com"}}
+
+		_, err := uc(context.Background(), import_data.Command{
+			Bundle: bundle,
+		})
+
+		testutil.IsNil(t, err)
+
+		empty, err := store.IsEmpty(context.Background())
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, empty)
+	})
+}
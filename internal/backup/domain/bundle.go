@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+// Current version of the bundle format. It must be incremented when the bundle structure
+// changes in a way older versions could not understand.
+const BundleVersion = 2
+
+var (
+	ErrUnsupportedBundleVersion = apperr.New("unsupported_bundle_version")
+	ErrInstanceNotEmpty         = apperr.New("instance_not_empty")
+)
+
+type (
+	// Single row of exported data represented by a column name to value map so it stays
+	// portable across storage backends.
+	Record map[string]any
+
+	// Portable representation of a seelf instance data. Artifacts, logs and deployments
+	// history are not part of it since they are tied to the host which produced them, and
+	// neither are add-ons whose databases live on targets: they must be provisioned again
+	// and restored from their backups.
+	Bundle struct {
+		Version    int       `json:"version"`
+		ExportedAt time.Time `json:"exported_at"`
+		Users      []Record  `json:"users"`
+		Teams      []Record  `json:"teams"` // Since version 2
+		Targets    []Record  `json:"targets"`
+		Registries []Record  `json:"registries"`
+		Apps       []Record  `json:"apps"`
+		Webhooks   []Record  `json:"webhooks"`  // Since version 2
+		Channels   []Record  `json:"channels"`  // Since version 2
+		Peers      []Record  `json:"peers"`     // Since version 2
+		DnsZones   []Record  `json:"dns_zones"` // Since version 2
+	}
+
+	Exporter interface {
+		Export(context.Context) (Bundle, error)
+	}
+
+	Importer interface {
+		// Returns true if the instance does not contain any data yet.
+		IsEmpty(context.Context) (bool, error)
+		// Import the given bundle. Imported targets must be marked as needing a reconfiguration
+		// since the host they will run on has changed.
+		Import(context.Context, Bundle) error
+	}
+)
+
+// Builds a new empty bundle with the current version.
+func NewBundle() Bundle {
+	return Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+}
+
+// Check if the bundle could be imported by this version of seelf.
+func (b Bundle) IsSupported() bool {
+	return b.Version > 0 && b.Version <= BundleVersion
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+)
+
+type (
+	Store interface {
+		domain.Exporter
+		domain.Importer
+	}
+
+	store struct {
+		bundle domain.Bundle
+	}
+)
+
+// Builds a new in-memory store initialized with the given data.
+func NewStore(existing domain.Bundle) Store {
+	return &store{existing}
+}
+
+func (s *store) Export(ctx context.Context) (domain.Bundle, error) {
+	bundle := domain.NewBundle()
+
+	bundle.Users = s.bundle.Users
+	bundle.Teams = s.bundle.Teams
+	bundle.Targets = s.bundle.Targets
+	bundle.Registries = s.bundle.Registries
+	bundle.Apps = s.bundle.Apps
+	bundle.Webhooks = s.bundle.Webhooks
+	bundle.Channels = s.bundle.Channels
+	bundle.Peers = s.bundle.Peers
+	bundle.DnsZones = s.bundle.DnsZones
+
+	return bundle, nil
+}
+
+func (s *store) IsEmpty(ctx context.Context) (bool, error) {
+	return len(s.bundle.Users) == 0 &&
+		len(s.bundle.Teams) == 0 &&
+		len(s.bundle.Targets) == 0 &&
+		len(s.bundle.Registries) == 0 &&
+		len(s.bundle.Apps) == 0 &&
+		len(s.bundle.Webhooks) == 0 &&
+		len(s.bundle.Channels) == 0 &&
+		len(s.bundle.Peers) == 0 &&
+		len(s.bundle.DnsZones) == 0, nil
+}
+
+func (s *store) Import(ctx context.Context, bundle domain.Bundle) error {
+	s.bundle = bundle
+	return nil
+}
@@ -0,0 +1,18 @@
+package infra
+
+import (
+	"github.com/YuukanOO/seelf/internal/backup/app/export_data"
+	"github.com/YuukanOO/seelf/internal/backup/app/import_data"
+	backupsqlite "github.com/YuukanOO/seelf/internal/backup/infra/sqlite"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+// Setup the backup module. It does not own any table and relies on the other modules
+// migrations to be applied first.
+func Setup(db *sqlite.Database, b bus.Bus, encrypted ...sqlite.EncryptedColumn) {
+	store := backupsqlite.NewStore(db, encrypted...)
+
+	bus.Register(b, export_data.Handler(store))
+	bus.Register(b, import_data.Handler(store))
+}
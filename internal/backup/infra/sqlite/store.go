@@ -0,0 +1,215 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	Store interface {
+		domain.Exporter
+		domain.Importer
+	}
+
+	store struct {
+		db        *sqlite.Database
+		encrypted []sqlite.EncryptedColumn
+	}
+)
+
+// Builds a store exporting data of the given database. Values of the encrypted columns
+// are exported in plain text, other ones are exported as stored.
+func NewStore(db *sqlite.Database, encrypted ...sqlite.EncryptedColumn) Store {
+	return &store{db, encrypted}
+}
+
+func (s *store) Export(ctx context.Context) (bundle domain.Bundle, err error) {
+	bundle = domain.NewBundle()
+
+	for _, table := range bundleTables(&bundle) {
+		if *table.records, err = s.records(ctx, table.name); err != nil {
+			return bundle, err
+		}
+	}
+
+	return bundle, nil
+}
+
+func (s *store) IsEmpty(ctx context.Context) (bool, error) {
+	return builder.
+		Query[bool](`
+		SELECT
+			NOT EXISTS(SELECT 1 FROM users)
+			AND NOT EXISTS(SELECT 1 FROM teams)
+			AND NOT EXISTS(SELECT 1 FROM targets)
+			AND NOT EXISTS(SELECT 1 FROM registries)
+			AND NOT EXISTS(SELECT 1 FROM apps)
+			AND NOT EXISTS(SELECT 1 FROM webhooks)
+			AND NOT EXISTS(SELECT 1 FROM channels)
+			AND NOT EXISTS(SELECT 1 FROM peers)
+			AND NOT EXISTS(SELECT 1 FROM dns_zones)`).
+		Extract(s.db, ctx)
+}
+
+func (s *store) Import(ctx context.Context, bundle domain.Bundle) (finalErr error) {
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	// Since the host has changed, every target must be configured again.
+	// The status 0 represents the configuring state and jobs will be queued at startup.
+	now := time.Now().UTC()
+
+	for _, target := range bundle.Targets {
+		target["state_status"] = 0
+		target["state_version"] = now
+		target["state_errcode"] = nil
+		target["state_last_ready_version"] = nil
+	}
+
+	for _, table := range bundleTables(&bundle) {
+		for _, record := range *table.records {
+			values := make(builder.Values, len(record))
+
+			for column, value := range record {
+				values[column] = normalize(value)
+			}
+
+			if finalErr = builder.Insert(table.name, values).Exec(s.db, ctx); finalErr != nil {
+				return
+			}
+		}
+	}
+
+	// Bundles exported before users management was introduced have no admin, so promote
+	// the oldest user as the migration does.
+	finalErr = builder.
+		Command(`
+			UPDATE users SET is_admin = true
+			WHERE NOT EXISTS(SELECT 1 FROM users WHERE is_admin)
+				AND id = (SELECT id FROM users ORDER BY registered_at LIMIT 1)`).
+		Exec(s.db, ctx)
+
+	return
+}
+
+type bundleTable struct {
+	name    string
+	records *[]domain.Record
+}
+
+// Tables part of a bundle. Order matters because of foreign keys.
+func bundleTables(bundle *domain.Bundle) []bundleTable {
+	return []bundleTable{
+		{"users", &bundle.Users},
+		{"teams", &bundle.Teams},
+		{"targets", &bundle.Targets},
+		{"registries", &bundle.Registries},
+		{"apps", &bundle.Apps},
+		{"webhooks", &bundle.Webhooks},
+		{"channels", &bundle.Channels},
+		{"peers", &bundle.Peers},
+		{"dns_zones", &bundle.DnsZones},
+	}
+}
+
+func (s *store) records(ctx context.Context, table string) ([]domain.Record, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT * FROM "+table)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.Record, 0)
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		record := make(domain.Record, len(columns))
+
+		for i, column := range columns {
+			if b, isBytes := values[i].([]byte); isBytes {
+				values[i] = string(b)
+			}
+
+			// Bundles are portable so secrets encrypted with the keys of this instance are exported in plain text
+			if str, isString := values[i].(string); isString && slices.Contains(s.encrypted, sqlite.EncryptedColumn{Table: table, Column: column}) {
+				if values[i], _, err = s.db.Decrypt(str); err != nil {
+					return nil, err
+				}
+			}
+
+			record[column] = values[i]
+		}
+
+		results = append(results, record)
+	}
+
+	return results, rows.Err()
+}
+
+// Normalize a value which may have been decoded from JSON so it will be persisted
+// with the appropriate type.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := v.Float64()
+		return f
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v)
+		}
+
+		return v
+	case string:
+		// Dates are exported as RFC3339 strings, parse them back so the driver
+		// persist them in its own format.
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t
+		}
+
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,157 @@
+package sqlite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/internal/backup/domain"
+	backupsqlite "github.com/YuukanOO/seelf/internal/backup/infra/sqlite"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Store(t *testing.T) {
+	ctx := context.Background()
+
+	open := func(t *testing.T, secret string) *sqlite.Database {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db"), logger, memory.NewBus(),
+			sqlite.WithKeyring(must.Panic(crypto.NewKeyring([]byte(secret)))))
+
+		testutil.IsNil(t, err)
+
+		t.Cleanup(func() {
+			db.Close()
+		})
+
+		testutil.IsNil(t, db.Migrate(startup.MigrationsModules...))
+
+		return db
+	}
+
+	insert := func(t *testing.T, db *sqlite.Database, table string, values builder.Values) {
+		testutil.IsNil(t, builder.Insert(table, values).Exec(db, ctx))
+	}
+
+	// Encode and decode the bundle as the export and import commands do.
+	transfer := func(t *testing.T, bundle domain.Bundle) domain.Bundle {
+		data, err := json.Marshal(bundle)
+
+		testutil.IsNil(t, err)
+
+		var result domain.Bundle
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		testutil.IsNil(t, decoder.Decode(&result))
+
+		return result
+	}
+
+	t.Run("should restore the exported data into another instance", func(t *testing.T) {
+		source := open(t, "source secret")
+		now := time.Now().UTC()
+
+		insert(t, source, "users", builder.Values{
+			"id":            "uid",
+			"email":         "john@doe.com",
+			"password_hash": "hash",
+			"api_key":       "apikey",
+			"registered_at": now,
+		})
+		insert(t, source, "teams", builder.Values{
+			"id":         "team",
+			"name":       "my-team",
+			"created_at": now,
+			"created_by": "uid",
+		})
+		insert(t, source, "apps", builder.Values{
+			"id":                 "app",
+			"name":               "my-app",
+			"team_id":            "team",
+			"production_target":  "target",
+			"production_version": now,
+			"staging_target":     "target",
+			"staging_version":    now,
+			"created_at":         now,
+			"created_by":         "uid",
+		})
+		insert(t, source, "webhooks", builder.Values{
+			"id":         "webhook",
+			"name":       "my-webhook",
+			"url":        "https://example.com/hook",
+			"secret":     source.Encrypted("webhook secret"),
+			"events":     "[]",
+			"created_at": now,
+			"created_by": "uid",
+		})
+		insert(t, source, "channels", builder.Values{
+			"id":         "channel",
+			"name":       "my-channel",
+			"config":     source.Encrypted(`{"kind":"slack"}`),
+			"app_id":     "app",
+			"created_at": now,
+			"created_by": "uid",
+		})
+		insert(t, source, "peers", builder.Values{
+			"id":         "peer",
+			"name":       "my-peer",
+			"url":        "https://peer.example.com",
+			"token":      source.Encrypted("peer token"),
+			"created_at": now,
+			"created_by": "uid",
+		})
+		insert(t, source, "dns_zones", builder.Values{
+			"id":          "zone",
+			"name":        "example.com",
+			"provider":    "cloudflare",
+			"credentials": source.Encrypted("zone credentials"),
+			"created_at":  now,
+			"created_by":  "uid",
+		})
+
+		exported, err := backupsqlite.NewStore(source, startup.EncryptedColumns...).Export(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.BundleVersion, exported.Version)
+
+		destination := open(t, "destination secret")
+		store := backupsqlite.NewStore(destination, startup.EncryptedColumns...)
+
+		testutil.IsNil(t, store.Import(ctx, transfer(t, exported)))
+
+		empty, err := store.IsEmpty(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, empty)
+
+		restored, err := store.Export(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, restored.Users, 1)
+		testutil.HasLength(t, restored.Teams, 1)
+		testutil.HasLength(t, restored.Apps, 1)
+		testutil.HasLength(t, restored.Webhooks, 1)
+		testutil.HasLength(t, restored.Channels, 1)
+		testutil.HasLength(t, restored.Peers, 1)
+		testutil.HasLength(t, restored.DnsZones, 1)
+		testutil.Equals[any](t, "team", restored.Teams[0]["id"])
+		testutil.Equals[any](t, "team", restored.Apps[0]["team_id"])
+		testutil.Equals[any](t, "webhook secret", restored.Webhooks[0]["secret"])
+		testutil.Equals[any](t, `{"kind":"slack"}`, restored.Channels[0]["config"])
+		testutil.Equals[any](t, "app", restored.Channels[0]["app_id"])
+		testutil.Equals[any](t, "peer token", restored.Peers[0]["token"])
+		testutil.Equals[any](t, "zone credentials", restored.DnsZones[0]["credentials"])
+	})
+}
@@ -0,0 +1,77 @@
+package annotate_deployment
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Update the note and metadata of a deployment, used to correlate it with change
+// management tools. Only given fields are updated, an empty value removes them.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string                         `json:"-"`
+	DeploymentNumber int                            `json:"-"`
+	Note             monad.Maybe[string]            `json:"note"`
+	Metadata         monad.Maybe[map[string]string] `json:"metadata"`
+}
+
+func (Command) Name_() string              { return "deployment.command.annotate_deployment" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			note     domain.DeploymentNote
+			metadata domain.DeploymentMetadata
+		)
+
+		if err := validate.Struct(validate.Of{
+			"note": validate.Maybe(cmd.Note, func(value string) error {
+				return validate.Value(value, &note, domain.DeploymentNoteFrom)
+			}),
+			"metadata": validate.Maybe(cmd.Metadata, func(values map[string]string) error {
+				return validate.Value(values, &metadata, domain.DeploymentMetadataFrom)
+			}),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(app.ID(), domain.DeploymentNumber(cmd.DeploymentNumber)))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if !cmd.Note.HasValue() {
+			note = depl.Note()
+		}
+
+		if !cmd.Metadata.HasValue() {
+			metadata = depl.Metadata()
+		}
+
+		depl.Annotate(note, metadata)
+
+		return bus.Unit, writer.Write(ctx, &depl)
+	}
+}
@@ -0,0 +1,108 @@
+package annotate_deployment_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/annotate_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_AnnotateDeployment(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+
+	sut := func(existingDeployments ...*domain.Deployment) bus.RequestHandler[bus.UnitType, annotate_deployment.Command] {
+		deploymentsStore := memory.NewDeploymentsStore(existingDeployments...)
+		return annotate_deployment.Handler(memory.NewAppsStore(&app), deploymentsStore, deploymentsStore)
+	}
+
+	newDeployment := func() domain.Deployment {
+		depl := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+		depl.Annotate("Initial release", domain.DeploymentMetadata{"release": "1.0.0"})
+		return depl
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, annotate_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+			Metadata:         monad.Value(map[string]string{"release": " "}),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should fail if the deployment does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, annotate_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+			Note:             monad.Value("some note"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to deploy the app", func(t *testing.T) {
+		depl := newDeployment()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&depl)
+
+		_, err := uc(auth.WithUser(context.Background(), user), annotate_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+			Note:             monad.Value("some note"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should update only the given fields", func(t *testing.T) {
+		depl := newDeployment()
+		uc := sut(&depl)
+
+		r, err := uc(ctx, annotate_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+			Metadata:         monad.Value(map[string]string{"release": "1.0.1", "ticket": "OPS-42"}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		evt := testutil.EventIs[domain.DeploymentAnnotated](t, &depl, 2)
+		testutil.Equals(t, "Initial release", evt.Note)
+		testutil.DeepEquals(t, domain.DeploymentMetadata{"release": "1.0.1", "ticket": "OPS-42"}, evt.Metadata)
+	})
+
+	t.Run("should remove the note if empty", func(t *testing.T) {
+		depl := newDeployment()
+		uc := sut(&depl)
+
+		_, err := uc(ctx, annotate_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+			Note:             monad.Value(""),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentAnnotated](t, &depl, 2)
+		testutil.Equals(t, "", evt.Note)
+		testutil.DeepEquals(t, domain.DeploymentMetadata{"release": "1.0.0"}, evt.Metadata)
+	})
+}
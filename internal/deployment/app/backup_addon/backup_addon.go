@@ -0,0 +1,127 @@
+package backup_addon
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Dump the data of an add-on to the backups storage. Once done, backups exceeding
+// the retention are removed.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID    string `json:"id"`
+	AppID string `json:"app_id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.backup_addon" }
+func (c Command) ResourceID() string { return c.AppID } // Makes the app deletion wait for its backups to be taken
+
+func Handler(
+	reader domain.AddonBackupsReader,
+	writer domain.AddonBackupsWriter,
+	addonsReader domain.AddonsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+	storage domain.AddonBackupsStorage,
+	retention int,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		backup, err := reader.GetByID(ctx, domain.AddonBackupID(cmd.ID))
+
+		if err != nil {
+			// Backup not found, its add-on has been deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		if backup.Status() != domain.AddonBackupStatusRunning {
+			return bus.Unit, nil
+		}
+
+		addon, err := addonsReader.GetByID(ctx, backup.AddonID())
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		var size int64
+
+		backupErr := addon.CanBeBackedUp()
+
+		if backupErr == nil {
+			var target domain.Target
+
+			target, backupErr = targetsReader.GetByID(ctx, addon.Target())
+
+			if backupErr != nil && !errors.Is(backupErr, apperr.ErrNotFound) {
+				return bus.Unit, backupErr
+			}
+
+			if backupErr == nil {
+				backupErr = target.CheckAvailability()
+
+				// Target configuration is in progress, just retry the job later
+				if errors.Is(backupErr, domain.ErrTargetConfigurationInProgress) {
+					return bus.Unit, backupErr
+				}
+			}
+
+			if backupErr == nil {
+				size, backupErr = storage.Save(ctx, backup, func(w io.Writer) error {
+					return provider.BackupAddon(ctx, target, addon, w)
+				})
+			}
+		}
+
+		backup.Completed(size, backupErr)
+
+		if err = writer.Write(ctx, &backup); err != nil || backupErr != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, prune(ctx, reader, writer, storage, addon.ID(), retention)
+	}
+}
+
+// Remove backups of the given add-on which exceed the retention.
+func prune(
+	ctx context.Context,
+	reader domain.AddonBackupsReader,
+	writer domain.AddonBackupsWriter,
+	storage domain.AddonBackupsStorage,
+	addon domain.AddonID,
+	retention int,
+) error {
+	backups, err := reader.GetByAddon(ctx, addon)
+
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range domain.AddonBackupsToPrune(backups, retention) {
+		if err = storage.Remove(ctx, backup); err != nil {
+			return err
+		}
+
+		backup.Delete()
+
+		if err = writer.Write(ctx, &backup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
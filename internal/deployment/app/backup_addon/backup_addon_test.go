@@ -0,0 +1,170 @@
+package backup_addon_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/backup_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	backups []*domain.AddonBackup
+	addons  []*domain.Addon
+	targets []*domain.Target
+}
+
+func Test_BackupAddon(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData, backupErr error) (bus.RequestHandler[bus.UnitType, backup_addon.Command], memory.AddonBackupsStore, *dummyStorage) {
+		store := memory.NewAddonBackupsStore(data.backups...)
+		storage := &dummyStorage{}
+		return backup_addon.Handler(store, store, memory.NewAddonsStore(data.addons...), memory.NewTargetsStore(data.targets...),
+			&dummyProvider{err: backupErr}, storage, 1), store, storage
+	}
+
+	newTarget := func() domain.Target {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		return target
+	}
+
+	newAddon := func(target domain.TargetID) domain.Addon {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "uid"))
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		return addon
+	}
+
+	t.Run("should fail silently if the backup does not exist anymore", func(t *testing.T) {
+		uc, _, storage := sut(initialData{}, nil)
+
+		r, err := uc(ctx, backup_addon.Command{ID: "some-id"})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.HasLength(t, storage.saved, 0)
+	})
+
+	t.Run("should retry later if the target is configuring", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+		addon := newAddon(target.ID())
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		uc, _, storage := sut(initialData{
+			backups: []*domain.AddonBackup{&backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, nil)
+
+		_, err := uc(ctx, backup_addon.Command{ID: string(backup.ID())})
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, err)
+		testutil.HasLength(t, storage.saved, 0)
+	})
+
+	t.Run("should mark the backup as failed if the provider fails", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		providerErr := errors.New("some_error")
+		uc, store, _ := sut(initialData{
+			backups: []*domain.AddonBackup{&backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, providerErr)
+
+		_, err := uc(ctx, backup_addon.Command{ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		backup = must.Panic(store.GetByID(ctx, backup.ID()))
+		testutil.Equals(t, domain.AddonBackupStatusFailed, backup.Status())
+	})
+
+	t.Run("should store the add-on dump and remove backups exceeding the retention", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		old := must.Panic(domain.NewAddonBackup(addon))
+		old.Completed(12, nil)
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		uc, store, storage := sut(initialData{
+			backups: []*domain.AddonBackup{&old, &backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, nil)
+
+		_, err := uc(ctx, backup_addon.Command{ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.AddonBackupID{backup.ID()}, storage.saved)
+		testutil.DeepEquals(t, []domain.AddonBackupID{old.ID()}, storage.removed)
+		backup = must.Panic(store.GetByID(ctx, backup.ID()))
+		testutil.Equals(t, domain.AddonBackupStatusSucceeded, backup.Status())
+		testutil.Equals(t, int64(len(dump)), backup.Size())
+		_, err = store.GetByID(ctx, old.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
+
+const dump = "CREATE TABLE some_table;"
+
+type (
+	dummyProvider struct {
+		domain.Provider
+		err error
+	}
+
+	dummyStorage struct {
+		domain.AddonBackupsStorage
+		saved   []domain.AddonBackupID
+		removed []domain.AddonBackupID
+	}
+)
+
+func (d *dummyProvider) BackupAddon(_ context.Context, _ domain.Target, _ domain.Addon, w io.Writer) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	_, err := io.WriteString(w, dump)
+	return err
+}
+
+func (d *dummyStorage) Save(_ context.Context, backup domain.AddonBackup, fn func(io.Writer) error) (int64, error) {
+	w := &countingWriter{}
+
+	if err := fn(w); err != nil {
+		return 0, err
+	}
+
+	d.saved = append(d.saved, backup.ID())
+	return w.n, nil
+}
+
+func (d *dummyStorage) Remove(_ context.Context, backup domain.AddonBackup) error {
+	d.removed = append(d.removed, backup.ID())
+	return nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
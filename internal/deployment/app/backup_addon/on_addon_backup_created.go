@@ -0,0 +1,18 @@
+package backup_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAddonBackupCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AddonBackupCreated] {
+	return func(ctx context.Context, evt domain.AddonBackupCreated) error {
+		return scheduler.Queue(ctx, Command{
+			ID:    string(evt.ID),
+			AppID: string(evt.AppID),
+		}, bus.WithGroup(app.AddonBackupGroup(evt.AddonID)))
+	}
+}
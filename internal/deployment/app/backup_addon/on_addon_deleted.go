@@ -0,0 +1,15 @@
+package backup_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an add-on has been deleted, its backups are of no use anymore.
+func OnAddonDeletedHandler(storage domain.AddonBackupsStorage) bus.SignalHandler[domain.AddonDeleted] {
+	return func(ctx context.Context, evt domain.AddonDeleted) error {
+		return storage.RemoveAll(ctx, evt.ID)
+	}
+}
@@ -0,0 +1,73 @@
+package broadcast_changes
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/realtime"
+)
+
+// Realtime event types published when deployments and targets change.
+const (
+	DeploymentCreatedEvent      = "deployment_created"
+	DeploymentStateChangedEvent = "deployment_state_changed"
+	TargetStateChangedEvent     = "target_state_changed"
+)
+
+type (
+	Deployment struct {
+		AppID            string              `json:"app_id"`
+		DeploymentNumber int                 `json:"deployment_number"`
+		Environment      string              `json:"environment"`
+		TargetID         string              `json:"target_id"`
+		Status           uint8               `json:"status"`
+		ErrCode          monad.Maybe[string] `json:"error_code"`
+		FailureReason    monad.Maybe[string] `json:"failure_reason"`
+	}
+
+	Target struct {
+		ID      string              `json:"id"`
+		Status  uint8               `json:"status"`
+		ErrCode monad.Maybe[string] `json:"error_code"`
+	}
+)
+
+func publishDeployment(
+	ctx context.Context,
+	reader domain.AppsReader,
+	publisher realtime.Publisher,
+	eventType string,
+	id domain.DeploymentID,
+	config domain.DeploymentConfig,
+	state domain.DeploymentState,
+) error {
+	app, err := reader.GetByID(ctx, id.AppID())
+
+	if err != nil {
+		return err
+	}
+
+	publisher.Publish(realtime.Event{
+		Type: eventType,
+		Data: Deployment{
+			AppID:            string(id.AppID()),
+			DeploymentNumber: int(id.DeploymentNumber()),
+			Environment:      string(config.Environment()),
+			TargetID:         string(config.Target()),
+			Status:           uint8(state.Status()),
+			ErrCode:          state.ErrCode(),
+			FailureReason:    monad.Map(state.FailureReason(), domain.DeploymentFailureReason.String),
+		},
+	}, canRead(app.CreatedBy(), app.Resources()...))
+
+	return nil
+}
+
+// Only subscribers allowed to read the resource should receive its changes.
+func canRead(owner auth.UserID, resources ...auth.Resource) realtime.Filter {
+	return func(ctx context.Context) bool {
+		return auth.PermissionOn(ctx, owner, resources...) >= auth.PermissionRead
+	}
+}
@@ -0,0 +1,16 @@
+package broadcast_changes
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/realtime"
+)
+
+// Publish new deployments to subscribers allowed to read the app.
+func OnDeploymentCreatedHandler(reader domain.AppsReader, publisher realtime.Publisher) bus.SignalHandler[domain.DeploymentCreated] {
+	return func(ctx context.Context, evt domain.DeploymentCreated) error {
+		return publishDeployment(ctx, reader, publisher, DeploymentCreatedEvent, evt.ID, evt.Config, evt.State)
+	}
+}
@@ -0,0 +1,16 @@
+package broadcast_changes
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/realtime"
+)
+
+// Publish deployment state changes to subscribers allowed to read the app.
+func OnDeploymentStateChangedHandler(reader domain.AppsReader, publisher realtime.Publisher) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		return publishDeployment(ctx, reader, publisher, DeploymentStateChangedEvent, evt.ID, evt.Config, evt.State)
+	}
+}
@@ -0,0 +1,32 @@
+package broadcast_changes
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/realtime"
+)
+
+// Publish target state changes, such as a configuration success or failure, to
+// subscribers allowed to read the target.
+func OnTargetStateChangedHandler(reader domain.TargetsReader, publisher realtime.Publisher) bus.SignalHandler[domain.TargetStateChanged] {
+	return func(ctx context.Context, evt domain.TargetStateChanged) error {
+		target, err := reader.GetByID(ctx, evt.ID)
+
+		if err != nil {
+			return err
+		}
+
+		publisher.Publish(realtime.Event{
+			Type: TargetStateChangedEvent,
+			Data: Target{
+				ID:      string(evt.ID),
+				Status:  uint8(evt.State.Status()),
+				ErrCode: evt.State.ErrCode(),
+			},
+		}, canRead(target.CreatedBy(), target.Resources()...))
+
+		return nil
+	}
+}
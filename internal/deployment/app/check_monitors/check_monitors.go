@@ -0,0 +1,87 @@
+package check_monitors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Check the public url of every monitored app environment which is due and record the
+// results. A monitor which could not be checked does not prevent other ones to be,
+// its error is returned afterward. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.check_monitors" }
+
+func Handler(
+	reader domain.MonitorsReader,
+	writer domain.MonitorsWriter,
+	appsReader domain.AppsReader,
+	targetsReader domain.TargetsReader,
+	probe domain.UptimeProbe,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		monitors, err := reader.GetDue(ctx, time.Now().UTC())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var monitorErrs []error
+
+		for _, monitor := range monitors {
+			if err = check(ctx, &monitor, appsReader, targetsReader, probe); err != nil {
+				monitorErrs = append(monitorErrs, fmt.Errorf("monitor %s: %w", monitor.ID(), err))
+				continue
+			}
+
+			if err = writer.Write(ctx, &monitor); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, errors.Join(monitorErrs...)
+	}
+}
+
+func check(
+	ctx context.Context,
+	monitor *domain.Monitor,
+	appsReader domain.AppsReader,
+	targetsReader domain.TargetsReader,
+	probe domain.UptimeProbe,
+) error {
+	app, err := appsReader.GetByID(ctx, monitor.AppID())
+
+	if err != nil {
+		return err
+	}
+
+	config, err := app.ConfigSnapshotFor(monitor.Environment())
+
+	if err != nil {
+		return err
+	}
+
+	target, err := targetsReader.GetByID(ctx, config.Target())
+
+	if err != nil {
+		// The target may have been deleted in the meantime, nothing to check
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	monitor.Checked(config, probe.Probe(ctx, config.PublicUrl(target.Url())))
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package check_monitors_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/check_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CheckMonitors(t *testing.T) {
+	ctx := context.Background()
+	target := must.Panic(domain.NewTarget("my-target",
+		domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://docker.localhost")), true),
+		domain.NewProviderConfigRequirement(nil, true), "uid"))
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "uid"))
+	check := must.Panic(domain.NewUptimeCheck(time.Minute, 200, monad.None[string]()))
+
+	sut := func(probe *dummyProbe, existingMonitors ...*domain.Monitor) (bus.RequestHandler[bus.UnitType, check_monitors.Command], memory.MonitorsStore) {
+		store := memory.NewMonitorsStore(existingMonitors...)
+		return check_monitors.Handler(store, store, memory.NewAppsStore(&app), memory.NewTargetsStore(&target), probe), store
+	}
+
+	t.Run("should do nothing if no monitor is due", func(t *testing.T) {
+		probe := &dummyProbe{statusCode: 200}
+		uc, _ := sut(probe)
+
+		_, err := uc(ctx, check_monitors.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, probe.urls, 0)
+	})
+
+	t.Run("should probe the public url of due monitors and record the result", func(t *testing.T) {
+		production := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+		staging := must.Panic(domain.NewMonitor(app, domain.Staging, check, "uid"))
+		probe := &dummyProbe{statusCode: 503}
+		uc, store := sut(probe, &production, &staging)
+
+		_, err := uc(ctx, check_monitors.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, probe.urls, 2)
+
+		monitor := must.Panic(store.GetByID(ctx, production.ID()))
+		testutil.Equals(t, domain.MonitorStatusDown, monitor.Status())
+		testutil.IsTrue(t, monitor.NextCheckAt().After(time.Now().UTC()))
+	})
+
+	t.Run("should skip monitors whose target does not exist anymore", func(t *testing.T) {
+		orphan := must.Panic(domain.NewApp("orphan",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("unknown"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("unknown"), true, true), "uid"))
+		monitor := must.Panic(domain.NewMonitor(orphan, domain.Production, check, "uid"))
+		probe := &dummyProbe{statusCode: 200}
+		store := memory.NewMonitorsStore(&monitor)
+		uc := check_monitors.Handler(store, store, memory.NewAppsStore(&orphan), memory.NewTargetsStore(&target), probe)
+
+		_, err := uc(ctx, check_monitors.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, probe.urls, 0)
+	})
+}
+
+type dummyProbe struct {
+	statusCode int
+	urls       []string
+}
+
+func (p *dummyProbe) Probe(_ context.Context, url domain.Url) domain.ProbeResult {
+	p.urls = append(p.urls, url.String())
+
+	return domain.ProbeResult{
+		Url:        url,
+		CheckedAt:  time.Now().UTC(),
+		StatusCode: monad.Value(p.statusCode),
+	}
+}
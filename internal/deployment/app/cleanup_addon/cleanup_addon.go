@@ -0,0 +1,77 @@
+package cleanup_addon
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Remove the service of an add-on from the given target. If the add-on has been
+// requested for cleanup and lives on this target, it is then deleted.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID       string `json:"id"`
+	AppID    string `json:"app_id"`
+	TargetID string `json:"target_id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.cleanup_addon" }
+func (c Command) ResourceID() string { return c.AppID } // Makes the app deletion wait for its add-ons to be removed
+
+func Handler(
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		target, err := targetsReader.GetByID(ctx, domain.TargetID(cmd.TargetID))
+
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			// Target already deleted, nothing to remove
+		case err != nil:
+			return bus.Unit, err
+		default:
+			strategy, err := target.AddonCleanupStrategy()
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			if err = provider.RemoveAddon(ctx, target, domain.AddonID(cmd.ID), strategy); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		addon, err := reader.GetByID(ctx, domain.AddonID(cmd.ID))
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		// The add-on has only been moved to another target
+		if addon.Target() != domain.TargetID(cmd.TargetID) {
+			return bus.Unit, nil
+		}
+
+		if err = addon.Delete(true); err != nil {
+			// Not requested for cleanup, it has been moved back to this target in the meantime
+			if errors.Is(err, domain.ErrAddonCleanupNeeded) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &addon)
+	}
+}
@@ -0,0 +1,121 @@
+package cleanup_addon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	addons  []*domain.Addon
+	targets []*domain.Target
+}
+
+func Test_CleanupAddon(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData) (bus.RequestHandler[bus.UnitType, cleanup_addon.Command], memory.AddonsStore, *dummyProvider) {
+		store := memory.NewAddonsStore(data.addons...)
+		provider := &dummyProvider{}
+		return cleanup_addon.Handler(store, store, memory.NewTargetsStore(data.targets...), provider), store, provider
+	}
+
+	newTarget := func() domain.Target {
+		return must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+	}
+
+	newAddon := func(target domain.TargetID) domain.Addon {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "uid"))
+		return must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+	}
+
+	t.Run("should fail silently if the target and the add-on do not exist anymore", func(t *testing.T) {
+		uc, _, provider := sut(initialData{})
+
+		r, err := uc(ctx, cleanup_addon.Command{ID: "some-id", TargetID: "some-target"})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should fail if the target is configuring", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		addon.RequestCleanup("uid")
+		uc, _, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}})
+
+		_, err := uc(ctx, cleanup_addon.Command{ID: string(addon.ID()), TargetID: string(target.ID())})
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should skip the removal if the target is being deleted", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.RequestCleanup(false, "uid")
+		addon := newAddon(target.ID())
+		addon.RequestCleanup("uid")
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}})
+
+		_, err := uc(ctx, cleanup_addon.Command{ID: string(addon.ID()), TargetID: string(target.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+		_, err = store.GetByID(ctx, addon.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should only remove the service from the old target of a moved add-on", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		addon := newAddon(target.ID())
+		addon.MovedTo("another-target")
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}})
+
+		_, err := uc(ctx, cleanup_addon.Command{ID: string(addon.ID()), TargetID: string(target.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.called)
+		_, err = store.GetByID(ctx, addon.ID())
+		testutil.IsNil(t, err)
+	})
+
+	t.Run("should remove the add-on and delete it", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		addon := newAddon(target.ID())
+		addon.RequestCleanup("uid")
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}})
+
+		_, err := uc(ctx, cleanup_addon.Command{ID: string(addon.ID()), TargetID: string(target.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.called)
+		_, err = store.GetByID(ctx, addon.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	called bool
+}
+
+func (d *dummyProvider) RemoveAddon(_ context.Context, _ domain.Target, _ domain.AddonID, s domain.CleanupStrategy) error {
+	d.called = s != domain.CleanupStrategySkip
+	return nil
+}
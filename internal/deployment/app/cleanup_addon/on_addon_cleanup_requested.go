@@ -0,0 +1,18 @@
+package cleanup_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAddonCleanupRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AddonCleanupRequested] {
+	return func(ctx context.Context, evt domain.AddonCleanupRequested) error {
+		return scheduler.Queue(ctx, Command{
+			ID:       string(evt.ID),
+			AppID:    string(evt.AppID),
+			TargetID: string(evt.Target),
+		}, bus.WithPolicy(bus.JobPolicyCancellable))
+	}
+}
@@ -0,0 +1,19 @@
+package cleanup_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an add-on has been moved to another target, remove its service from the old one.
+func OnAddonTargetChangedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AddonTargetChanged] {
+	return func(ctx context.Context, evt domain.AddonTargetChanged) error {
+		return scheduler.Queue(ctx, Command{
+			ID:       string(evt.ID),
+			AppID:    string(evt.AppID),
+			TargetID: string(evt.OldTarget),
+		}, bus.WithPolicy(bus.JobPolicyCancellable))
+	}
+}
@@ -0,0 +1,38 @@
+package clear_build_cache
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Remove the build cache of an application so its next deployment starts from scratch.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.clear_build_cache" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.AppsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, artifactManager.ClearCache(ctx, app.ID())
+	}
+}
@@ -0,0 +1,75 @@
+package clear_build_cache_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/clear_build_cache"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ClearBuildCache(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(existingApps ...*domain.App) (bus.RequestHandler[bus.UnitType, clear_build_cache.Command], *dummyArtifactManager) {
+		manager := &dummyArtifactManager{}
+		return clear_build_cache.Handler(memory.NewAppsStore(existingApps...), manager), manager
+	}
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		uc, manager := sut()
+
+		_, err := uc(ctx, clear_build_cache.Command{
+			ID: "some-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+		testutil.Equals(t, "", manager.cleared)
+	})
+
+	t.Run("should fail if the user is not allowed to deploy the application", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc, manager := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), clear_build_cache.Command{
+			ID: string(app.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", manager.cleared)
+	})
+
+	t.Run("should clear the application build cache", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		uc, manager := sut(&app)
+
+		r, err := uc(ctx, clear_build_cache.Command{
+			ID: string(app.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.Equals(t, app.ID(), manager.cleared)
+	})
+}
+
+type dummyArtifactManager struct {
+	domain.ArtifactManager
+	cleared domain.AppID
+}
+
+func (m *dummyArtifactManager) ClearCache(_ context.Context, id domain.AppID) error {
+	m.cleared = id
+	return nil
+}
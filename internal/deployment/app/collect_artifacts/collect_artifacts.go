@@ -0,0 +1,121 @@
+package collect_artifacts
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Computes the disk space used by artifacts and prune those of the oldest deployments
+// until quotas are enforced. The last deployment of each app environment is never
+// pruned. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Quota    int64 `json:"quota"`     // Maximum size of all artifacts in bytes, 0 for no limit
+	AppQuota int64 `json:"app_quota"` // Maximum size of a single app artifacts in bytes, 0 for no limit
+}
+
+func (Command) Name_() string { return "deployment.command.collect_artifacts" }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+	writer domain.ArtifactsUsageWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		usage, err := artifactManager.Usage(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if cmd.Quota > 0 || cmd.AppQuota > 0 {
+			deployments, err := reader.GetPrunableDeployments(ctx)
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			collector := collector{
+				reader:          reader,
+				artifactManager: artifactManager,
+				usage:           usage,
+				current:         make(map[string]domain.Deployment),
+				pruned:          make(map[domain.DeploymentID]bool),
+			}
+
+			// Per app quotas first since they may free enough space for the global one
+			if cmd.AppQuota > 0 {
+				for _, depl := range deployments {
+					if usage[depl.ID().AppID()] <= cmd.AppQuota {
+						continue
+					}
+
+					if err = collector.prune(ctx, depl); err != nil {
+						return bus.Unit, err
+					}
+				}
+			}
+
+			if cmd.Quota > 0 {
+				for _, depl := range deployments {
+					if usage.Total() <= cmd.Quota {
+						break
+					}
+
+					if err = collector.prune(ctx, depl); err != nil {
+						return bus.Unit, err
+					}
+				}
+			}
+		}
+
+		return bus.Unit, writer.WriteArtifactsUsage(ctx, usage)
+	}
+}
+
+type collector struct {
+	reader          domain.DeploymentsReader
+	artifactManager domain.ArtifactManager
+	usage           domain.ArtifactsUsage
+	current         map[string]domain.Deployment // Last deployment of each app environment
+	pruned          map[domain.DeploymentID]bool
+}
+
+// Prune artifacts of the given deployment and update the usage accordingly.
+func (c *collector) prune(ctx context.Context, depl domain.Deployment) error {
+	if c.pruned[depl.ID()] {
+		return nil
+	}
+
+	var (
+		appID = depl.ID().AppID()
+		env   = depl.Config().Environment()
+		key   = string(appID) + string(env)
+	)
+
+	current, found := c.current[key]
+
+	if !found {
+		var err error
+
+		if current, err = c.reader.GetLastDeployment(ctx, appID, env); err != nil {
+			return err
+		}
+
+		c.current[key] = current
+	}
+
+	freed, err := c.artifactManager.Prune(ctx, depl, current)
+
+	if err != nil {
+		return err
+	}
+
+	c.usage[appID] -= freed
+	c.pruned[depl.ID()] = true
+
+	return nil
+}
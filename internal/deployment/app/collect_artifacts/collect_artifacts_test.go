@@ -0,0 +1,129 @@
+package collect_artifacts_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/YuukanOO/seelf/cmd/config"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_artifacts"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CollectArtifacts(t *testing.T) {
+	ctx := context.Background()
+	logger := must.Panic(log.NewLogger())
+	env := domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true)
+	app := must.Panic(domain.NewApp("my-app", env, env, "uid"))
+
+	sut := func(deployments ...*domain.Deployment) (
+		bus.RequestHandler[bus.UnitType, collect_artifacts.Command],
+		domain.ArtifactManager,
+		*usageWriter,
+	) {
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		writer := &usageWriter{}
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		// Produce some logs for each deployment
+		for _, depl := range deployments {
+			deploymentCtx, err := artifactManager.PrepareBuild(ctx, *depl)
+			testutil.IsNil(t, err)
+			deploymentCtx.Logger().Infof("deploying %d", depl.ID().DeploymentNumber())
+			testutil.IsNil(t, deploymentCtx.Logger().Close())
+		}
+
+		return collect_artifacts.Handler(memory.NewDeploymentsStore(deployments...), artifactManager, writer), artifactManager, writer
+	}
+
+	deployment := func(number domain.DeploymentNumber, finished bool) *domain.Deployment {
+		depl := must.Panic(app.NewDeployment(number, raw.Data(""), domain.Production, "uid"))
+
+		if finished {
+			depl.HasStarted()
+			depl.HasEnded(domain.Services{}, nil)
+		}
+
+		return &depl
+	}
+
+	exists := func(manager domain.ArtifactManager, depl *domain.Deployment) bool {
+		_, err := os.Stat(manager.Log(ctx, *depl).Path)
+		return err == nil
+	}
+
+	t.Run("should write the usage without pruning anything if no quota is set", func(t *testing.T) {
+		first, second := deployment(1, true), deployment(2, true)
+		uc, manager, writer := sut(first, second)
+
+		r, err := uc(ctx, collect_artifacts.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.IsTrue(t, writer.usage[app.ID()] > 0)
+		testutil.IsTrue(t, exists(manager, first))
+		testutil.IsTrue(t, exists(manager, second))
+	})
+
+	t.Run("should prune the oldest deployments of an app exceeding its quota", func(t *testing.T) {
+		first, second, last := deployment(1, true), deployment(2, true), deployment(3, true)
+		uc, manager, writer := sut(first, second, last)
+
+		_, err := uc(ctx, collect_artifacts.Command{AppQuota: 1})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, exists(manager, first))
+		testutil.IsFalse(t, exists(manager, second))
+		testutil.IsTrue(t, exists(manager, last))
+
+		usage, err := manager.Usage(ctx)
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, usage, writer.usage)
+	})
+
+	t.Run("should only prune what is needed to enforce the global quota", func(t *testing.T) {
+		first, second, last := deployment(1, true), deployment(2, true), deployment(3, true)
+		uc, manager, _ := sut(first, second, last)
+
+		usage, err := manager.Usage(ctx)
+		testutil.IsNil(t, err)
+
+		_, err = uc(ctx, collect_artifacts.Command{Quota: usage.Total() - 1})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, exists(manager, first))
+		testutil.IsTrue(t, exists(manager, second))
+		testutil.IsTrue(t, exists(manager, last))
+	})
+
+	t.Run("should not prune deployments which are not finished", func(t *testing.T) {
+		running, last := deployment(1, false), deployment(2, false)
+		uc, manager, _ := sut(running, last)
+
+		_, err := uc(ctx, collect_artifacts.Command{Quota: 1, AppQuota: 1})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, exists(manager, running))
+		testutil.IsTrue(t, exists(manager, last))
+	})
+}
+
+type usageWriter struct {
+	usage domain.ArtifactsUsage
+}
+
+func (w *usageWriter) WriteArtifactsUsage(_ context.Context, usage domain.ArtifactsUsage) error {
+	w.usage = usage
+	return nil
+}
@@ -0,0 +1,79 @@
+package collect_garbage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Remove resources which are not used anymore on a target, such as dangling images,
+// stopped application containers and unused networks, and record the space reclaimed.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.collect_garbage" }
+func (c Command) ResourceID() string { return c.ID }
+
+func Handler(
+	reader domain.TargetsReader,
+	writer domain.TargetsWriter,
+	provider domain.Provider,
+	options domain.GarbageCollectionOptions,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		target, err := reader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			// Target not found, already deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		// Being configured or not reachable, the next scheduled collection will take care of it.
+		if target.CheckAvailability() != nil {
+			return bus.Unit, nil
+		}
+
+		if options.DiskThreshold > 0 {
+			capacity, err := provider.Capacity(ctx, target)
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			if !options.ShouldCollect(capacity) {
+				return bus.Unit, nil
+			}
+		}
+
+		report, err := provider.CollectGarbage(ctx, target, options.MinAge)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Since the collection can take some time, retrieve the latest target version before updating it.
+		target, err = reader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		target.GarbageCollected(report)
+
+		return bus.Unit, writer.Write(ctx, &target)
+	}
+}
@@ -0,0 +1,121 @@
+package collect_garbage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_garbage"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CollectGarbage(t *testing.T) {
+	sut := func(options domain.GarbageCollectionOptions, existingTargets ...*domain.Target) (bus.RequestHandler[bus.UnitType, collect_garbage.Command], *dummyProvider) {
+		provider := &dummyProvider{
+			capacity: domain.TargetCapacity{DiskTotal: 100, DiskFree: 40},
+		}
+		store := memory.NewTargetsStore(existingTargets...)
+		return collect_garbage.Handler(store, store, provider, options), provider
+	}
+
+	t.Run("should fail silently if the target is not found", func(t *testing.T) {
+		uc, provider := sut(domain.GarbageCollectionOptions{})
+
+		_, err := uc(context.Background(), collect_garbage.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.collected)
+	})
+
+	t.Run("should skip the collection if the target is not available", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		uc, provider := sut(domain.GarbageCollectionOptions{}, &target)
+
+		_, err := uc(context.Background(), collect_garbage.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.collected)
+	})
+
+	t.Run("should skip the collection if the disk usage is below the threshold", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		uc, provider := sut(domain.GarbageCollectionOptions{DiskThreshold: 80}, &target)
+
+		_, err := uc(context.Background(), collect_garbage.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.collected)
+		testutil.HasNEvents(t, &target, 2)
+	})
+
+	t.Run("should not record anything if the collection fails", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		uc, provider := sut(domain.GarbageCollectionOptions{}, &target)
+		providerErr := errors.New("prune failed")
+		provider.err = providerErr
+
+		_, err := uc(context.Background(), collect_garbage.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.ErrorIs(t, providerErr, err)
+		testutil.HasNEvents(t, &target, 2)
+	})
+
+	t.Run("should collect garbage and record the report", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		uc, provider := sut(domain.GarbageCollectionOptions{MinAge: time.Hour, DiskThreshold: 60}, &target)
+
+		_, err := uc(context.Background(), collect_garbage.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.collected)
+		testutil.Equals(t, time.Hour, provider.minAge)
+		testutil.HasNEvents(t, &target, 3)
+		collected := testutil.EventIs[domain.TargetGarbageCollected](t, &target, 2)
+		testutil.Equals(t, 3, collected.Report.Images)
+		testutil.Equals(t, 2048, collected.Report.SpaceReclaimed)
+		testutil.Equals(t, collected.Report, target.LastGarbageCollection().MustGet())
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	capacity  domain.TargetCapacity
+	err       error
+	collected bool
+	minAge    time.Duration
+}
+
+func (d *dummyProvider) Capacity(context.Context, domain.Target) (domain.TargetCapacity, error) {
+	return d.capacity, nil
+}
+
+func (d *dummyProvider) CollectGarbage(_ context.Context, _ domain.Target, minAge time.Duration) (domain.GarbageCollectionReport, error) {
+	d.collected = true
+	d.minAge = minAge
+
+	if d.err != nil {
+		return domain.GarbageCollectionReport{}, d.err
+	}
+
+	return domain.GarbageCollectionReport{
+		Images:         3,
+		SpaceReclaimed: 2048,
+		CollectedAt:    time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,17 @@
+package collect_garbage
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnTargetGarbageCollectionRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.TargetGarbageCollectionRequested] {
+	return func(ctx context.Context, evt domain.TargetGarbageCollectionRequested) error {
+		return scheduler.Queue(ctx, Command{
+			ID: string(evt.ID),
+		}, bus.WithGroup(app.TargetConfigurationGroup(evt.ID)), bus.WithPolicy(bus.JobPolicyMerge))
+	}
+}
@@ -0,0 +1,66 @@
+package collect_resource_usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve resources consumed by applications on every ready target and store them,
+// removing stats older than the retention. An unreachable target does not prevent stats
+// of other ones to be stored, its error is returned afterward. Periodically sent by the
+// server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Retention time.Duration `json:"retention"` // How long stats are kept, 0 to keep them forever
+}
+
+func (Command) Name_() string { return "deployment.command.collect_resource_usage" }
+
+func Handler(
+	reader domain.TargetsReader,
+	provider domain.Provider,
+	writer domain.ResourceUsageWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := reader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			stats      []domain.ServiceStats
+			targetErrs []error
+			now        = time.Now().UTC()
+		)
+
+		for _, target := range targets {
+			targetStats, err := provider.Stats(ctx, target)
+
+			if err != nil {
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+				continue
+			}
+
+			stats = append(stats, targetStats...)
+		}
+
+		if err = writer.WriteResourceUsage(ctx, now, stats); err != nil {
+			return bus.Unit, err
+		}
+
+		if cmd.Retention > 0 {
+			if err = writer.PruneResourceUsage(ctx, now.Add(-cmd.Retention)); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
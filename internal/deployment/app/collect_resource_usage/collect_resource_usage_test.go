@@ -0,0 +1,99 @@
+package collect_resource_usage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CollectResourceUsage(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(provider domain.Provider, targets ...*domain.Target) (bus.RequestHandler[bus.UnitType, collect_resource_usage.Command], *usageWriter) {
+		writer := &usageWriter{}
+		return collect_resource_usage.Handler(memory.NewTargetsStore(targets...), provider, writer), writer
+	}
+
+	t.Run("should store stats of applications running on ready targets", func(t *testing.T) {
+		ready := fixture.Target("http://ready.localhost", true)
+		configuring := fixture.Target("http://configuring.localhost", false)
+		provider := &dummyProvider{stats: map[domain.TargetID][]domain.ServiceStats{
+			ready.ID():       {{AppID: "my-app", Environment: domain.Production, Service: "app", CPU: 12.5, Memory: 1024}},
+			configuring.ID(): {{AppID: "another-app", Environment: domain.Production, Service: "app"}},
+		}}
+		uc, writer := sut(provider, &ready, &configuring)
+
+		_, err := uc(ctx, collect_resource_usage.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, provider.stats[ready.ID()], writer.stats)
+		testutil.IsFalse(t, writer.collectedAt.IsZero())
+		testutil.IsTrue(t, writer.prunedBefore.IsZero())
+	})
+
+	t.Run("should prune stats older than the retention", func(t *testing.T) {
+		uc, writer := sut(&dummyProvider{})
+
+		_, err := uc(ctx, collect_resource_usage.Command{
+			Retention: time.Hour,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, writer.collectedAt.Add(-time.Hour), writer.prunedBefore)
+	})
+
+	t.Run("should store stats of reachable targets and return errors of unreachable ones", func(t *testing.T) {
+		reachable := fixture.Target("http://reachable.localhost", true)
+		unreachable := fixture.Target("http://unreachable.localhost", true)
+		targetErr := errors.New("could not connect")
+		provider := &dummyProvider{
+			stats: map[domain.TargetID][]domain.ServiceStats{
+				reachable.ID(): {{AppID: "my-app", Environment: domain.Staging, Service: "app"}},
+			},
+			errs: map[domain.TargetID]error{
+				unreachable.ID(): targetErr,
+			},
+		}
+		uc, writer := sut(provider, &reachable, &unreachable)
+
+		_, err := uc(ctx, collect_resource_usage.Command{})
+
+		testutil.ErrorIs(t, targetErr, err)
+		testutil.DeepEquals(t, provider.stats[reachable.ID()], writer.stats)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	stats map[domain.TargetID][]domain.ServiceStats
+	errs  map[domain.TargetID]error
+}
+
+func (p *dummyProvider) Stats(_ context.Context, target domain.Target) ([]domain.ServiceStats, error) {
+	return p.stats[target.ID()], p.errs[target.ID()]
+}
+
+type usageWriter struct {
+	collectedAt  time.Time
+	stats        []domain.ServiceStats
+	prunedBefore time.Time
+}
+
+func (w *usageWriter) WriteResourceUsage(_ context.Context, collectedAt time.Time, stats []domain.ServiceStats) error {
+	w.collectedAt = collectedAt
+	w.stats = stats
+	return nil
+}
+
+func (w *usageWriter) PruneResourceUsage(_ context.Context, before time.Time) error {
+	w.prunedBefore = before
+	return nil
+}
@@ -0,0 +1,55 @@
+package collect_target_capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve the capacity of every ready target and store it, replacing the one previously
+// collected. An unreachable target keeps its last known capacity and does not prevent
+// other ones to be stored, its error is returned afterward. Periodically sent by the
+// server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.collect_target_capacity" }
+
+func Handler(
+	reader domain.TargetsReader,
+	provider domain.Provider,
+	writer domain.TargetCapacityWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := reader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			targetErrs []error
+			now        = time.Now().UTC()
+		)
+
+		for _, target := range targets {
+			capacity, err := provider.Capacity(ctx, target)
+
+			if err != nil {
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+				continue
+			}
+
+			if err = writer.WriteTargetCapacity(ctx, target.ID(), now, capacity); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
@@ -0,0 +1,85 @@
+package collect_target_capacity_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_target_capacity"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CollectTargetCapacity(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(provider domain.Provider, targets ...*domain.Target) (bus.RequestHandler[bus.UnitType, collect_target_capacity.Command], *capacityWriter) {
+		writer := &capacityWriter{capacities: make(map[domain.TargetID]domain.TargetCapacity)}
+		return collect_target_capacity.Handler(memory.NewTargetsStore(targets...), provider, writer), writer
+	}
+
+	t.Run("should store the capacity of ready targets", func(t *testing.T) {
+		ready := fixture.Target("http://ready.localhost", true)
+		configuring := fixture.Target("http://configuring.localhost", false)
+		provider := &dummyProvider{capacities: map[domain.TargetID]domain.TargetCapacity{
+			ready.ID():       {RunningContainers: 3, ImagesSize: 1024, DiskFree: 2048, DiskTotal: 4096},
+			configuring.ID(): {RunningContainers: 1},
+		}}
+		uc, writer := sut(provider, &ready, &configuring)
+
+		_, err := uc(ctx, collect_target_capacity.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, map[domain.TargetID]domain.TargetCapacity{
+			ready.ID(): provider.capacities[ready.ID()],
+		}, writer.capacities)
+		testutil.IsFalse(t, writer.collectedAt.IsZero())
+	})
+
+	t.Run("should store the capacity of reachable targets and return errors of unreachable ones", func(t *testing.T) {
+		reachable := fixture.Target("http://reachable.localhost", true)
+		unreachable := fixture.Target("http://unreachable.localhost", true)
+		targetErr := errors.New("could not connect")
+		provider := &dummyProvider{
+			capacities: map[domain.TargetID]domain.TargetCapacity{
+				reachable.ID(): {RunningContainers: 2},
+			},
+			errs: map[domain.TargetID]error{
+				unreachable.ID(): targetErr,
+			},
+		}
+		uc, writer := sut(provider, &reachable, &unreachable)
+
+		_, err := uc(ctx, collect_target_capacity.Command{})
+
+		testutil.ErrorIs(t, targetErr, err)
+		testutil.DeepEquals(t, map[domain.TargetID]domain.TargetCapacity{
+			reachable.ID(): {RunningContainers: 2},
+		}, writer.capacities)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	capacities map[domain.TargetID]domain.TargetCapacity
+	errs       map[domain.TargetID]error
+}
+
+func (p *dummyProvider) Capacity(_ context.Context, target domain.Target) (domain.TargetCapacity, error) {
+	return p.capacities[target.ID()], p.errs[target.ID()]
+}
+
+type capacityWriter struct {
+	collectedAt time.Time
+	capacities  map[domain.TargetID]domain.TargetCapacity
+}
+
+func (w *capacityWriter) WriteTargetCapacity(_ context.Context, id domain.TargetID, collectedAt time.Time, capacity domain.TargetCapacity) error {
+	w.collectedAt = collectedAt
+	w.capacities[id] = capacity
+	return nil
+}
@@ -0,0 +1,67 @@
+package collect_traffic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve requests served by the proxy of every ready target during the given period,
+// aggregate them by application service and store them, removing samples older than the
+// retention. Like the resource usage collection, an unreachable target does not prevent
+// samples of other ones to be stored. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Since     time.Time     `json:"since"`
+	Until     time.Time     `json:"until"`
+	Retention time.Duration `json:"retention"` // How long samples are kept, 0 to keep them forever
+}
+
+func (Command) Name_() string { return "deployment.command.collect_traffic" }
+
+func Handler(
+	reader domain.TargetsReader,
+	provider domain.Provider,
+	writer domain.TrafficWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := reader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			logs       []domain.AccessLog
+			targetErrs []error
+		)
+
+		for _, target := range targets {
+			targetLogs, err := provider.AccessLogs(ctx, target, cmd.Since, cmd.Until)
+
+			if err != nil {
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+				continue
+			}
+
+			logs = append(logs, targetLogs...)
+		}
+
+		if err = writer.WriteTraffic(ctx, cmd.Until, domain.AggregateAccessLogs(logs)); err != nil {
+			return bus.Unit, err
+		}
+
+		if cmd.Retention > 0 {
+			if err = writer.PruneTraffic(ctx, cmd.Until.Add(-cmd.Retention)); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
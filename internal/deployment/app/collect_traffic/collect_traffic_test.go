@@ -0,0 +1,112 @@
+package collect_traffic_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_traffic"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CollectTraffic(t *testing.T) {
+	ctx := context.Background()
+	until := time.Now().UTC()
+	since := until.Add(-time.Minute)
+
+	sut := func(provider domain.Provider, targets ...*domain.Target) (bus.RequestHandler[bus.UnitType, collect_traffic.Command], *trafficWriter) {
+		writer := &trafficWriter{}
+		return collect_traffic.Handler(memory.NewTargetsStore(targets...), provider, writer), writer
+	}
+
+	t.Run("should store requests served on ready targets aggregated by service", func(t *testing.T) {
+		ready := fixture.Target("http://ready.localhost", true)
+		configuring := fixture.Target("http://configuring.localhost", false)
+		provider := &dummyProvider{logs: map[domain.TargetID][]domain.AccessLog{
+			ready.ID(): {
+				{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 200, Duration: 3 * time.Millisecond},
+				{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 502, Duration: 2 * time.Second},
+			},
+			configuring.ID(): {{AppID: "another-app", Environment: domain.Production, Service: "app", Status: 200}},
+		}}
+		uc, writer := sut(provider, &ready, &configuring)
+
+		_, err := uc(ctx, collect_traffic.Command{Since: since, Until: until})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, since, provider.since)
+		testutil.Equals(t, until, writer.collectedAt)
+		testutil.HasLength(t, writer.samples, 1)
+		testutil.Equals(t, 2, writer.samples[0].Requests)
+		testutil.Equals(t, domain.StatusCounts{Success: 1, ServerError: 1}, writer.samples[0].Statuses)
+		testutil.IsTrue(t, writer.prunedBefore.IsZero())
+	})
+
+	t.Run("should prune samples older than the retention", func(t *testing.T) {
+		uc, writer := sut(&dummyProvider{})
+
+		_, err := uc(ctx, collect_traffic.Command{
+			Since:     since,
+			Until:     until,
+			Retention: time.Hour,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, until.Add(-time.Hour), writer.prunedBefore)
+	})
+
+	t.Run("should store samples of reachable targets and return errors of unreachable ones", func(t *testing.T) {
+		reachable := fixture.Target("http://reachable.localhost", true)
+		unreachable := fixture.Target("http://unreachable.localhost", true)
+		targetErr := errors.New("could not connect")
+		provider := &dummyProvider{
+			logs: map[domain.TargetID][]domain.AccessLog{
+				reachable.ID(): {{AppID: "my-app", Environment: domain.Staging, Service: "app", Status: 404}},
+			},
+			errs: map[domain.TargetID]error{
+				unreachable.ID(): targetErr,
+			},
+		}
+		uc, writer := sut(provider, &reachable, &unreachable)
+
+		_, err := uc(ctx, collect_traffic.Command{Since: since, Until: until})
+
+		testutil.ErrorIs(t, targetErr, err)
+		testutil.HasLength(t, writer.samples, 1)
+		testutil.Equals(t, domain.Staging, writer.samples[0].Environment)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	logs  map[domain.TargetID][]domain.AccessLog
+	errs  map[domain.TargetID]error
+	since time.Time
+}
+
+func (p *dummyProvider) AccessLogs(_ context.Context, target domain.Target, since, _ time.Time) ([]domain.AccessLog, error) {
+	p.since = since
+	return p.logs[target.ID()], p.errs[target.ID()]
+}
+
+type trafficWriter struct {
+	collectedAt  time.Time
+	samples      []domain.TrafficSample
+	prunedBefore time.Time
+}
+
+func (w *trafficWriter) WriteTraffic(_ context.Context, collectedAt time.Time, samples []domain.TrafficSample) error {
+	w.collectedAt = collectedAt
+	w.samples = samples
+	return nil
+}
+
+func (w *trafficWriter) PruneTraffic(_ context.Context, before time.Time) error {
+	w.prunedBefore = before
+	return nil
+}
@@ -0,0 +1,136 @@
+package configure_access_protection
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type (
+	// Protect exposed services of an app environment, replacing the existing protection
+	// if any.
+	Command struct {
+		bus.Command[bus.UnitType]
+
+		AppID       string                 `json:"-"`
+		Environment string                 `json:"-"`
+		BasicAuth   monad.Maybe[BasicAuth] `json:"basic_auth"`
+		IPAllowList []string               `json:"ip_allowlist"`
+		ForwardAuth monad.Maybe[string]    `json:"forward_auth"`
+	}
+
+	BasicAuth struct {
+		Username string              `json:"username"`
+		Password monad.Maybe[string] `json:"password"` // If not given, keep the current one
+	}
+)
+
+func (Command) Name_() string              { return "deployment.command.configure_access_protection" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+	hasher auth.PasswordHasher,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env         domain.Environment
+			username    string
+			forwardAuth monad.Maybe[domain.Url]
+			ipAllowList = make([]string, len(cmd.IPAllowList))
+			ipRanges    = make(validate.Of, len(cmd.IPAllowList))
+		)
+
+		for i, value := range cmd.IPAllowList {
+			ipRanges[strconv.Itoa(i)] = validate.Value(value, &ipAllowList[i], domain.IPRangeFrom)
+		}
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"basic_auth": validate.Maybe(cmd.BasicAuth, func(basicAuth BasicAuth) error {
+				return validate.Struct(validate.Of{
+					"username": validate.Value(basicAuth.Username, &username, domain.BasicAuthUsernameFrom),
+					"password": validate.Maybe(basicAuth.Password, strings.Required),
+				})
+			}),
+			"ip_allowlist": validate.Struct(ipRanges),
+			"forward_auth": validate.Maybe(cmd.ForwardAuth, func(value string) error {
+				url, err := domain.UrlFrom(value)
+				forwardAuth.Set(url)
+				return err
+			}),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		var basicAuth monad.Maybe[domain.BasicAuth]
+
+		if credentials, isSet := cmd.BasicAuth.TryGet(); isSet {
+			hash, err := passwordHash(env, app, credentials, hasher)
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			basicAuth.Set(domain.BasicAuth{
+				Username:     username,
+				PasswordHash: hash,
+			})
+		}
+
+		protection, err := domain.NewAccessProtection(basicAuth, ipAllowList, forwardAuth)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.ProtectEnvironment(env, monad.Value(protection)); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
+
+// Hash the given password or retrieve the current one of the environment if no
+// password has been given.
+func passwordHash(
+	env domain.Environment,
+	app domain.App,
+	credentials BasicAuth,
+	hasher auth.PasswordHasher,
+) (string, error) {
+	if password, isSet := credentials.Password.TryGet(); isSet {
+		hash, err := hasher.Hash(password)
+		return string(hash), err
+	}
+
+	config := app.Production()
+
+	if !env.IsProduction() {
+		config = app.Staging()
+	}
+
+	if current, isSet := config.Protection().Get(domain.AccessProtection{}).BasicAuth.TryGet(); isSet {
+		return current.PasswordHash, nil
+	}
+
+	return "", validate.Wrap(strings.ErrRequired, "basic_auth.password")
+}
@@ -0,0 +1,127 @@
+package configure_access_protection_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureAccessProtection(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	hasher := crypto.NewBCryptHasher()
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+	sut := func(app *domain.App) bus.RequestHandler[bus.UnitType, configure_access_protection.Command] {
+		store := memory.NewAppsStore(app)
+		return configure_access_protection.Handler(store, store, hasher)
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			BasicAuth:   monad.Value(configure_access_protection.BasicAuth{Username: "john:doe"}),
+			IPAllowList: []string{"10.0.0.0/8", "not an ip"},
+			ForwardAuth: monad.Value("not an url"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 4)
+	})
+
+	t.Run("should require at least one protection", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+		})
+
+		testutil.ErrorIs(t, domain.ErrAccessProtectionMissing, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			IPAllowList: []string{"10.0.0.0/8"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require a password if the environment has no credentials yet", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			BasicAuth:   monad.Value(configure_access_protection.BasicAuth{Username: "john"}),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+	})
+
+	t.Run("should protect the environment and keep the current password if not given", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			BasicAuth: monad.Value(configure_access_protection.BasicAuth{
+				Username: "john",
+				Password: monad.Value("secret"),
+			}),
+			ForwardAuth: monad.Value("http://oauth2-proxy:4180/oauth2/auth"),
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Staging, changed.Environment)
+		protection := changed.Config.Protection().MustGet()
+		credentials := protection.BasicAuth.MustGet()
+		testutil.Equals(t, "john", credentials.Username)
+		testutil.IsNil(t, hasher.Compare("secret", auth.PasswordHash(credentials.PasswordHash)))
+		testutil.Equals(t, "http://oauth2-proxy:4180/oauth2/auth", protection.ForwardAuth.MustGet().String())
+
+		_, err = uc(ctx, configure_access_protection.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			BasicAuth:   monad.Value(configure_access_protection.BasicAuth{Username: "jane"}),
+			IPAllowList: []string{"192.168.1.1"},
+		})
+
+		testutil.IsNil(t, err)
+		changed = testutil.EventIs[domain.AppEnvChanged](t, &app, 2)
+		protection = changed.Config.Protection().MustGet()
+		testutil.DeepEquals(t, domain.BasicAuth{Username: "jane", PasswordHash: credentials.PasswordHash}, protection.BasicAuth.MustGet())
+		testutil.DeepEquals(t, []string{"192.168.1.1"}, protection.IPAllowList)
+		testutil.IsFalse(t, protection.ForwardAuth.HasValue())
+	})
+}
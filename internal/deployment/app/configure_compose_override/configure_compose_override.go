@@ -0,0 +1,59 @@
+package configure_compose_override
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Merge the given compose file content with the one of the next deployments of an app
+// environment, replacing the existing override if any.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string `json:"-"`
+	Environment string `json:"-"`
+	Content     string `json:"content"`
+}
+
+func (Command) Name_() string              { return "deployment.command.configure_compose_override" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env      domain.Environment
+			override domain.ComposeOverride
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"content":     validate.Value(cmd.Content, &override, domain.ComposeOverrideFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.OverrideCompose(env, monad.Value(override)); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
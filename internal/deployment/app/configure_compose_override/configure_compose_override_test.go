@@ -0,0 +1,79 @@
+package configure_compose_override_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureComposeOverride(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+	sut := func(app *domain.App) bus.RequestHandler[bus.UnitType, configure_compose_override.Command] {
+		store := memory.NewAppsStore(app)
+		return configure_compose_override.Handler(store, store)
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_compose_override.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			Content:     "services: [",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 2)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_compose_override.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Content:     "services: {}",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should configure the compose override of the environment", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+		content := `services:
+  app:
+    deploy:
+      replicas: 2`
+
+		_, err := uc(ctx, configure_compose_override.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Content:     content,
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Staging, changed.Environment)
+		testutil.Equals(t, domain.ComposeOverride(content), changed.Config.ComposeOverride().MustGet())
+	})
+}
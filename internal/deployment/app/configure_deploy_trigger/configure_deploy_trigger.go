@@ -0,0 +1,73 @@
+package configure_deploy_trigger
+
+import (
+	"context"
+	"crypto/ed25519"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Configure how deployment triggers signed by external systems are verified for an
+// application. When no public key is given, a new secret is generated and returned,
+// else an empty string is returned. Triggers act on behalf of the current user.
+type Command struct {
+	bus.Command[string]
+
+	ID        string              `json:"-"`
+	PublicKey monad.Maybe[string] `json:"public_key"`
+}
+
+func (Command) Name_() string              { return "deployment.command.configure_deploy_trigger" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var publicKey ed25519.PublicKey
+
+		if err := validate.Struct(validate.Of{
+			"public_key": validate.Maybe(cmd.PublicKey, func(value string) error {
+				return validate.Value(value, &publicKey, domain.Ed25519PublicKeyFrom)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		var (
+			secret string
+			by     = auth.CurrentUser(ctx).MustGet()
+		)
+
+		if cmd.PublicKey.HasValue() {
+			err = app.UseDeployTriggerPublicKey(publicKey, by)
+		} else {
+			secret, err = app.GenerateDeployTriggerSecret(by)
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &app); err != nil {
+			return "", err
+		}
+
+		return secret, nil
+	}
+}
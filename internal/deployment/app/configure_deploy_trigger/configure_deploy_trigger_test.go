@@ -0,0 +1,100 @@
+package configure_deploy_trigger_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureDeployTrigger(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+
+	sut := func(existingApps ...*domain.App) bus.RequestHandler[string, configure_deploy_trigger.Command] {
+		store := memory.NewAppsStore(existingApps...)
+		return configure_deploy_trigger.Handler(store, store)
+	}
+
+	t.Run("should fail if the app does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, configure_deploy_trigger.Command{ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the public key is invalid", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_deploy_trigger.Command{
+			ID:        string(app.ID()),
+			PublicKey: monad.Value("not a key"),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 1)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the app", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_deploy_trigger.Command{ID: string(app.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should generate a secret if no public key is given", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		secret, err := uc(ctx, configure_deploy_trigger.Command{ID: string(app.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", secret)
+
+		evt := testutil.EventIs[domain.AppDeployTriggerChanged](t, &app, 1)
+		trigger := evt.Trigger.MustGet()
+		testutil.Equals(t, domain.DeployTriggerHMAC, trigger.Kind())
+		testutil.Equals(t, auth.UserID("some-uid"), trigger.By())
+	})
+
+	t.Run("should use the given public key", func(t *testing.T) {
+		app := newApp()
+		publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+		uc := sut(&app)
+
+		secret, err := uc(ctx, configure_deploy_trigger.Command{
+			ID:        string(app.ID()),
+			PublicKey: monad.Value(base64.StdEncoding.EncodeToString(publicKey)),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "", secret)
+
+		evt := testutil.EventIs[domain.AppDeployTriggerChanged](t, &app, 1)
+		testutil.Equals(t, domain.DeployTriggerEd25519, evt.Trigger.MustGet().Kind())
+	})
+}
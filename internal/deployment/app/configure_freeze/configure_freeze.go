@@ -0,0 +1,118 @@
+package configure_freeze
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type (
+	// Replace the freeze windows of an application during which its production deployments
+	// are not processed, in addition to the instance wide ones. Giving no window removes them.
+	Command struct {
+		bus.Command[bus.UnitType]
+
+		ID      string   `json:"-"`
+		Windows []Window `json:"windows"`
+	}
+
+	Window struct {
+		Name     string                 `json:"name"`
+		Policy   string                 `json:"policy"` // queue (default) or reject
+		Days     []string               `json:"days"`
+		From     string                 `json:"from"`
+		To       string                 `json:"to"`
+		Timezone string                 `json:"timezone"`
+		Start    monad.Maybe[time.Time] `json:"start"`
+		End      monad.Maybe[time.Time] `json:"end"`
+	}
+)
+
+func (Command) Name_() string              { return "deployment.command.configure_freeze" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			windows     = make(domain.FreezeWindows, len(cmd.Windows))
+			windowsErrs = make(validate.Of, len(cmd.Windows))
+		)
+
+		for i, w := range cmd.Windows {
+			windowsErrs[strconv.Itoa(i)] = w.parse(&windows[i])
+		}
+
+		if err := validate.Struct(validate.Of{
+			"windows": validate.Struct(windowsErrs),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.ConfigureFreezeWindows(windows); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
+
+func (w Window) parse(target *domain.FreezeWindow) error {
+	var (
+		policy   domain.FreezePolicy
+		days     = make([]string, len(w.Days))
+		daysErrs = make(validate.Of, len(w.Days))
+		from, to string
+		timezone string
+	)
+
+	for i, day := range w.Days {
+		daysErrs[strconv.Itoa(i)] = validate.Value(day, &days[i], domain.FreezeDayFrom)
+	}
+
+	if err := validate.Struct(validate.Of{
+		"name":   strings.Required(w.Name),
+		"policy": validate.Value(w.Policy, &policy, domain.FreezePolicyFrom),
+		"days":   validate.Struct(daysErrs),
+		"from": validate.If(w.From != "", func() error {
+			return validate.Value(w.From, &from, domain.FreezeTimeFrom)
+		}),
+		"to": validate.If(w.To != "", func() error {
+			return validate.Value(w.To, &to, domain.FreezeTimeFrom)
+		}),
+		"timezone": validate.If(w.Timezone != "", func() error {
+			return validate.Value(w.Timezone, &timezone, domain.TimezoneFrom)
+		}),
+	}); err != nil {
+		return err
+	}
+
+	window, err := domain.NewFreezeWindow(w.Name, policy, days, from, to, timezone, w.Start, w.End)
+
+	if err != nil {
+		return err
+	}
+
+	*target = window
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package configure_freeze_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_freeze"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureFreeze(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+
+	sut := func(existingApps ...*domain.App) bus.RequestHandler[bus.UnitType, configure_freeze.Command] {
+		store := memory.NewAppsStore(existingApps...)
+		return configure_freeze.Handler(store, store)
+	}
+
+	t.Run("should fail if the app does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, configure_freeze.Command{ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require valid windows", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+		start := time.Now()
+
+		_, err := uc(ctx, configure_freeze.Command{
+			ID: string(app.ID()),
+			Windows: []configure_freeze.Window{
+				{Name: "weekend", Policy: "ignore", Days: []string{"someday"}, From: "25:00", Timezone: "Somewhere/Else"},
+				{Name: "release", Start: monad.Value(start), End: monad.Value(start.Add(-time.Hour))},
+			},
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.HasNEvents(t, &app, 1)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the app", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_freeze.Command{ID: string(app.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should replace the app freeze windows", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_freeze.Command{
+			ID: string(app.ID()),
+			Windows: []configure_freeze.Window{
+				{Name: "weekend", Days: []string{"Saturday", "sun"}, Timezone: "Europe/Paris"},
+				{Name: "evenings", Policy: "reject", From: "18:00", To: "08:00"},
+			},
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.AppFreezeWindowsChanged](t, &app, 1)
+		testutil.DeepEquals(t, domain.FreezeWindows{
+			{Name: "weekend", Policy: domain.FreezePolicyQueue, Days: []string{"sat", "sun"}, Timezone: "Europe/Paris"},
+			{Name: "evenings", Policy: domain.FreezePolicyReject, Days: []string{}, From: "18:00", To: "08:00"},
+		}, evt.Windows)
+	})
+}
@@ -0,0 +1,87 @@
+package configure_monitor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/numbers"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Monitor the public url of an application environment, or update how it is checked
+// if it is already monitored.
+type Command struct {
+	bus.Command[string]
+
+	AppID       string              `json:"-"`
+	Environment string              `json:"-"`
+	Interval    int                 `json:"interval"` // In seconds
+	StatusCode  monad.Maybe[int]    `json:"status_code"`
+	Keyword     monad.Maybe[string] `json:"keyword"`
+}
+
+func (Command) Name_() string              { return "deployment.command.configure_monitor" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.MonitorsReader,
+	writer domain.MonitorsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var env domain.Environment
+
+		statusCode := cmd.StatusCode.Get(domain.DefaultMonitorStatusCode)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"interval":    validate.Field(cmd.Interval, numbers.Min(int(domain.MinMonitorInterval/time.Second))),
+			"status_code": validate.Field(statusCode, numbers.Min(100), numbers.Max(599)),
+			"keyword":     validate.Maybe(cmd.Keyword, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		check, err := domain.NewUptimeCheck(time.Duration(cmd.Interval)*time.Second, statusCode, cmd.Keyword)
+
+		if err != nil {
+			return "", err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		monitor, err := reader.GetByApp(ctx, app.ID(), env)
+
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			if monitor, err = domain.NewMonitor(app, env, check, auth.CurrentUser(ctx).MustGet()); err != nil {
+				return "", err
+			}
+		case err != nil:
+			return "", err
+		default:
+			monitor.HasCheck(check)
+		}
+
+		if err = writer.Write(ctx, &monitor); err != nil {
+			return "", err
+		}
+
+		return string(monitor.ID()), nil
+	}
+}
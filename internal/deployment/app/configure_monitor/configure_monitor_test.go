@@ -0,0 +1,113 @@
+package configure_monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureMonitor(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	sut := func(existingMonitors ...*domain.Monitor) (bus.RequestHandler[string, configure_monitor.Command], memory.MonitorsStore) {
+		store := memory.NewMonitorsStore(existingMonitors...)
+		return configure_monitor.Handler(memory.NewAppsStore(&app), store, store), store
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, configure_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			Interval:    10,
+			StatusCode:  monad.Value(42),
+			Keyword:     monad.Value(""),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 4)
+	})
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, configure_monitor.Command{
+			AppID:       "some-id",
+			Environment: "production",
+			Interval:    60,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc, _ := sut()
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Interval:    60,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should monitor an app environment with sensible defaults", func(t *testing.T) {
+		uc, store := sut()
+
+		id, err := uc(ctx, configure_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Interval:    60,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+
+		monitor := must.Panic(store.GetByApp(ctx, app.ID(), domain.Production))
+		testutil.Equals(t, domain.MonitorID(id), monitor.ID())
+		testutil.Equals(t, "some-uid", monitor.CreatedBy())
+		testutil.Equals(t, time.Minute, monitor.Check().Interval())
+		testutil.Equals(t, domain.DefaultMonitorStatusCode, monitor.Check().StatusCode())
+		testutil.IsFalse(t, monitor.Check().Keyword().HasValue())
+	})
+
+	t.Run("should update the check of an already monitored environment", func(t *testing.T) {
+		existing := must.Panic(domain.NewMonitor(app, domain.Staging,
+			must.Panic(domain.NewUptimeCheck(time.Minute, 200, monad.None[string]())), "some-uid"))
+		uc, store := sut(&existing)
+
+		id, err := uc(ctx, configure_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Interval:    300,
+			StatusCode:  monad.Value(204),
+			Keyword:     monad.Value("ok"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(existing.ID()), id)
+
+		monitor := must.Panic(store.GetByID(ctx, existing.ID()))
+		testutil.Equals(t, 5*time.Minute, monitor.Check().Interval())
+		testutil.Equals(t, 204, monitor.Check().StatusCode())
+		testutil.Equals(t, monad.Value("ok"), monitor.Check().Keyword())
+	})
+}
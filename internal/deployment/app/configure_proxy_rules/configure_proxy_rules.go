@@ -0,0 +1,103 @@
+package configure_proxy_rules
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type (
+	// Apply custom proxy rules to exposed services of an app environment, replacing
+	// the existing ones if any.
+	Command struct {
+		bus.Command[bus.UnitType]
+
+		AppID         string            `json:"-"`
+		Environment   string            `json:"-"`
+		WWWRedirect   bool              `json:"www_redirect"`
+		InsecurePaths []string          `json:"insecure_paths"`
+		Redirects     []Redirect        `json:"redirects"`
+		Headers       map[string]string `json:"headers"`
+	}
+
+	Redirect struct {
+		Regex       string `json:"regex"`
+		Replacement string `json:"replacement"`
+		Permanent   bool   `json:"permanent"`
+	}
+)
+
+func (Command) Name_() string              { return "deployment.command.configure_proxy_rules" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env             domain.Environment
+			insecurePaths   = make([]string, len(cmd.InsecurePaths))
+			redirects       = make([]domain.Redirect, len(cmd.Redirects))
+			headers         = make(map[string]string, len(cmd.Headers))
+			insecurePathsOf = make(validate.Of, len(cmd.InsecurePaths))
+			redirectsOf     = make(validate.Of, len(cmd.Redirects))
+			headersOf       = make(validate.Of, len(cmd.Headers))
+		)
+
+		for i, value := range cmd.InsecurePaths {
+			insecurePathsOf[strconv.Itoa(i)] = validate.Value(value, &insecurePaths[i], domain.PathPrefixFrom)
+		}
+
+		for i, redirect := range cmd.Redirects {
+			r, err := domain.RedirectFrom(redirect.Regex, redirect.Replacement, redirect.Permanent)
+			redirects[i] = r
+			redirectsOf[strconv.Itoa(i)] = err
+		}
+
+		for name, value := range cmd.Headers {
+			var headerName string
+			headersOf[name] = validate.Value(name, &headerName, domain.HeaderNameFrom)
+
+			if headersOf[name] == nil {
+				headers[headerName] = value
+			}
+		}
+
+		if err := validate.Struct(validate.Of{
+			"environment":    validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"insecure_paths": validate.Struct(insecurePathsOf),
+			"redirects":      validate.Struct(redirectsOf),
+			"headers":        validate.Struct(headersOf),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		rules, err := domain.NewProxyRules(cmd.WWWRedirect, insecurePaths, redirects, headers)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.ConfigureProxyRules(env, monad.Value(rules)); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
@@ -0,0 +1,101 @@
+package configure_proxy_rules_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_proxy_rules"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureProxyRules(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+	sut := func(app *domain.App) bus.RequestHandler[bus.UnitType, configure_proxy_rules.Command] {
+		store := memory.NewAppsStore(app)
+		return configure_proxy_rules.Handler(store, store)
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_proxy_rules.Command{
+			AppID:         string(app.ID()),
+			Environment:   "dev",
+			InsecurePaths: []string{"/.well-known", "no-slash"},
+			Redirects:     []configure_proxy_rules.Redirect{{Regex: "^(.*", Replacement: "https://example.com"}},
+			Headers:       map[string]string{"X Frame": "DENY"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 4)
+	})
+
+	t.Run("should require at least one rule", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_proxy_rules.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+		})
+
+		testutil.ErrorIs(t, domain.ErrProxyRulesMissing, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_proxy_rules.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			WWWRedirect: true,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should configure proxy rules of the environment", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_proxy_rules.Command{
+			AppID:         string(app.ID()),
+			Environment:   "production",
+			WWWRedirect:   true,
+			InsecurePaths: []string{"/.well-known/"},
+			Redirects: []configure_proxy_rules.Redirect{
+				{Regex: "^https?://([^/]+)/old/(.*)", Replacement: "https://${1}/new/${2}", Permanent: true},
+			},
+			Headers: map[string]string{"X-Frame-Options": "DENY"},
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, changed.Environment)
+		testutil.DeepEquals(t, domain.ProxyRules{
+			WWWRedirect:   true,
+			InsecurePaths: []string{"/.well-known"},
+			Redirects: []domain.Redirect{
+				{Regex: "^https?://([^/]+)/old/(.*)", Replacement: "https://${1}/new/${2}", Permanent: true},
+			},
+			Headers: map[string]string{"X-Frame-Options": "DENY"},
+		}, changed.Config.ProxyRules().MustGet())
+	})
+}
@@ -41,7 +41,11 @@ func Handler(
 			return bus.Unit, nil
 		}
 
-		var assigned domain.TargetEntrypointsAssigned
+		var (
+			assigned domain.TargetEntrypointsAssigned
+			platform domain.Platform
+			proxy    string
+		)
 
 		// Same as for the deployment, since the configuration can take some time, retrieve the latest
 		// target version before updating its state.
@@ -60,10 +64,23 @@ func Handler(
 
 			target.Configured(cmd.Version, assigned, finalErr)
 
+			if finalErr == nil {
+				target.RunsOn(platform)
+				target.ProxyRunning(proxy)
+			}
+
 			finalErr = writer.Write(ctx, &target)
 		}()
 
-		assigned, finalErr = provider.Setup(ctx, target)
+		if assigned, finalErr = provider.Setup(ctx, target); finalErr != nil {
+			return
+		}
+
+		if platform, finalErr = provider.Platform(ctx, target); finalErr != nil {
+			return
+		}
+
+		proxy, finalErr = provider.ProxyVersion(target)
 
 		return
 	}
@@ -83,6 +83,10 @@ func Test_ConfigureTarget(t *testing.T) {
 		testutil.IsTrue(t, provider.called)
 		evt := testutil.EventIs[domain.TargetStateChanged](t, &target, 1)
 		testutil.Equals(t, domain.TargetStatusReady, evt.State.Status())
+		platformChanged := testutil.EventIs[domain.TargetPlatformChanged](t, &target, 2)
+		testutil.Equals(t, domain.Platform("linux/amd64"), platformChanged.Platform)
+		proxyChanged := testutil.EventIs[domain.TargetProxyVersionChanged](t, &target, 3)
+		testutil.Equals(t, "v2.11", proxyChanged.Version)
 	})
 }
 
@@ -96,3 +100,11 @@ func (d *dummyProvider) Setup(context.Context, domain.Target) (domain.TargetEntr
 	d.called = true
 	return nil, d.err
 }
+
+func (d *dummyProvider) Platform(context.Context, domain.Target) (domain.Platform, error) {
+	return "linux/amd64", nil
+}
+
+func (d *dummyProvider) ProxyVersion(domain.Target) (string, error) {
+	return "v2.11", nil
+}
@@ -0,0 +1,59 @@
+package configure_timeouts
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Replace the timeouts of the deployment pipeline stages of an application, in seconds.
+// Stages set to 0 fall back to the instance wide timeouts.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID          string `json:"-"`
+	Fetch       uint   `json:"fetch"`
+	Build       uint   `json:"build"`
+	Deploy      uint   `json:"deploy"`
+	HealthCheck uint   `json:"health_check"`
+}
+
+func (Command) Name_() string              { return "deployment.command.configure_timeouts" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var timeouts domain.DeploymentTimeouts
+
+		if err := validate.Struct(validate.Of{
+			"fetch":        validate.Value(cmd.Fetch, &timeouts.Fetch, domain.DeploymentTimeoutFrom),
+			"build":        validate.Value(cmd.Build, &timeouts.Build, domain.DeploymentTimeoutFrom),
+			"deploy":       validate.Value(cmd.Deploy, &timeouts.Deploy, domain.DeploymentTimeoutFrom),
+			"health_check": validate.Value(cmd.HealthCheck, &timeouts.HealthCheck, domain.DeploymentTimeoutFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.UseTimeouts(timeouts); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
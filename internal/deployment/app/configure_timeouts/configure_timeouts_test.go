@@ -0,0 +1,74 @@
+package configure_timeouts_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_timeouts"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureTimeouts(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+
+	sut := func(existingApps ...*domain.App) bus.RequestHandler[bus.UnitType, configure_timeouts.Command] {
+		store := memory.NewAppsStore(existingApps...)
+		return configure_timeouts.Handler(store, store)
+	}
+
+	t.Run("should require valid timeouts", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_timeouts.Command{
+			ID:    string(app.ID()),
+			Build: 25 * 60 * 60,
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.HasNEvents(t, &app, 1)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the app", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_timeouts.Command{ID: string(app.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should replace the app timeouts", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_timeouts.Command{
+			ID:          string(app.ID()),
+			Build:       900,
+			HealthCheck: 60,
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.AppTimeoutsChanged](t, &app, 1)
+		testutil.Equals(t, domain.DeploymentTimeouts{
+			Build:       15 * time.Minute,
+			HealthCheck: time.Minute,
+		}, evt.Timeouts)
+	})
+}
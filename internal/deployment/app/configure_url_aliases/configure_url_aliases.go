@@ -0,0 +1,74 @@
+package configure_url_aliases
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Route additional hosts, such as apex domains, to the service exposed on the default
+// subdomain of an app environment, replacing existing aliases if any.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string   `json:"-"`
+	Environment string   `json:"-"`
+	Aliases     []string `json:"aliases"`
+}
+
+func (Command) Name_() string              { return "deployment.command.configure_url_aliases" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env       domain.Environment
+			aliases   = make(domain.UrlAliases, len(cmd.Aliases))
+			aliasesOf = make(validate.Of, len(cmd.Aliases))
+		)
+
+		for i, value := range cmd.Aliases {
+			aliasesOf[strconv.Itoa(i)] = validate.Value(value, &aliases[i], domain.UrlAliasFrom)
+		}
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"aliases":     validate.Struct(aliasesOf),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		requirement, err := reader.CheckUrlAliasesAvailability(ctx, app.ID(), env, aliases)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = validate.Wrap(requirement.Error(), "aliases"); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.UseUrlAliases(env, requirement); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
@@ -0,0 +1,94 @@
+package configure_url_aliases_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ConfigureUrlAliases(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newApp := func(name domain.AppName, target domain.TargetID) domain.App {
+		return must.Panic(domain.NewApp(name,
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "some-uid"))
+	}
+	sut := func(existingApps ...*domain.App) bus.RequestHandler[bus.UnitType, configure_url_aliases.Command] {
+		store := memory.NewAppsStore(existingApps...)
+		return configure_url_aliases.Handler(store, store)
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		app := newApp("my-app", "1")
+		uc := sut(&app)
+
+		_, err := uc(ctx, configure_url_aliases.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			Aliases:     []string{"example.com", "localhost"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 2)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp("my-app", "1")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), configure_url_aliases.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Aliases:     []string{"example.com"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if an alias is already routed to another environment on the same target", func(t *testing.T) {
+		app := newApp("my-app", "1")
+		other := newApp("other-app", "1")
+		testutil.IsNil(t, other.UseUrlAliases(domain.Staging, domain.NewUrlAliasesRequirement(domain.UrlAliases{"example.com"}, true)))
+		uc := sut(&app, &other)
+
+		_, err := uc(ctx, configure_url_aliases.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Aliases:     []string{"Example.com", "www.example.com"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.DeepEquals(t, []validate.FieldError{{Field: "aliases", Code: domain.ErrUrlAliasTaken.Error()}}, fieldErrs)
+	})
+
+	t.Run("should configure URL aliases of the environment", func(t *testing.T) {
+		app := newApp("my-app", "1")
+		other := newApp("other-app", "2")
+		testutil.IsNil(t, other.UseUrlAliases(domain.Production, domain.NewUrlAliasesRequirement(domain.UrlAliases{"example.com"}, true)))
+		uc := sut(&app, &other)
+
+		_, err := uc(ctx, configure_url_aliases.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Aliases:     []string{"www.example.com", "example.com."},
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, changed.Environment)
+		testutil.DeepEquals(t, domain.UrlAliases{"example.com", "www.example.com"}, changed.Config.UrlAliases().MustGet())
+	})
+}
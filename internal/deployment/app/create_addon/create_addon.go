@@ -0,0 +1,75 @@
+package create_addon
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Provision a managed service, such as a database, for an application environment.
+// Its connection string will be given to the app services on the next deployment.
+type Command struct {
+	bus.Command[string]
+
+	AppID       string              `json:"-"`
+	Environment string              `json:"environment"`
+	Kind        string              `json:"kind"`
+	Variable    monad.Maybe[string] `json:"variable"`
+}
+
+func (Command) Name_() string              { return "deployment.command.create_addon" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			env  domain.Environment
+			kind domain.AddonKind
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"kind":        validate.Value(cmd.Kind, &kind, domain.AddonKindFrom),
+			"variable":    validate.Maybe(cmd.Variable, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		existing, err := reader.GetByAppEnv(ctx, app.ID(), env)
+
+		if err != nil {
+			return "", err
+		}
+
+		addon, err := domain.NewAddon(app, env, kind, cmd.Variable, existing, auth.CurrentUser(ctx).MustGet())
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &addon); err != nil {
+			return "", err
+		}
+
+		return string(addon.ID()), nil
+	}
+}
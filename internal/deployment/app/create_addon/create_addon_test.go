@@ -0,0 +1,103 @@
+package create_addon_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_CreateAddon(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true), "some-uid"))
+	sut := func(existingAddons ...*domain.Addon) (bus.RequestHandler[string, create_addon.Command], memory.AddonsStore) {
+		store := memory.NewAddonsStore(existingAddons...)
+		return create_addon.Handler(memory.NewAppsStore(&app), store, store), store
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, create_addon.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			Kind:        "mongodb",
+			Variable:    monad.Value(""),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 3)
+	})
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, create_addon.Command{
+			AppID:       "some-id",
+			Environment: "production",
+			Kind:        "postgres",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc, _ := sut()
+
+		_, err := uc(auth.WithUser(context.Background(), user), create_addon.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Kind:        "postgres",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if the kind is already used by the app environment", func(t *testing.T) {
+		existing := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "some-uid"))
+		uc, _ := sut(&existing)
+
+		_, err := uc(ctx, create_addon.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Kind:        "postgres",
+			Variable:    monad.Value("OTHER_URL"),
+		})
+
+		testutil.ErrorIs(t, domain.ErrAddonKindAlreadyUsed, err)
+	})
+
+	t.Run("should create an add-on on the target of the app environment", func(t *testing.T) {
+		existing := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "some-uid"))
+		uc, store := sut(&existing)
+
+		id, err := uc(ctx, create_addon.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Kind:        "postgres",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+
+		addon := must.Panic(store.GetByID(ctx, domain.AddonID(id)))
+		testutil.Equals(t, domain.Staging, addon.Environment())
+		testutil.Equals(t, domain.AddonPostgres, addon.Kind())
+		testutil.Equals(t, "DATABASE_URL", addon.Variable())
+		testutil.Equals(t, "staging-target", addon.Target())
+	})
+}
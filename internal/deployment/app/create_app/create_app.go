@@ -2,9 +2,12 @@ package create_app
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/validate"
@@ -20,11 +23,38 @@ type (
 		VersionControl monad.Maybe[VersionControl] `json:"version_control"`
 		Production     EnvironmentConfig           `json:"production"`
 		Staging        EnvironmentConfig           `json:"staging"`
+		TeamID         monad.Maybe[string]         `json:"team_id"`
+		Dependencies   monad.Maybe[[]string]       `json:"dependencies"` // Apps deployed before this one
+		Labels         monad.Maybe[[]string]       `json:"labels"`
+		Platforms      monad.Maybe[[]string]       `json:"platforms"`         // Platforms the app can run on, any if empty
+		BuildRegistry  monad.Maybe[string]         `json:"build_registry_id"` // Registry to push built images to
+		StaticSite     monad.Maybe[StaticSite]     `json:"static_site"`       // Serve the app with the shared static web server of its targets
+	}
+
+	StaticSite struct {
+		Service   string `json:"service"`   // Compose service building the assets
+		Directory string `json:"directory"` // Build output directory in the image of the service
 	}
 
 	EnvironmentConfig struct {
-		Target string                                    `json:"target"`
-		Vars   monad.Maybe[map[string]map[string]string] `json:"vars"`
+		Target string `json:"target"` // Chosen using the target selection rules if empty
+
+		Vars     monad.Maybe[map[string]map[string]string] `json:"vars"`
+		Exposure monad.Maybe[map[string]ServiceExposure]   `json:"exposure"`
+	}
+
+	ServiceExposure struct {
+		Disabled   bool                `json:"disabled"`
+		Port       monad.Maybe[uint]   `json:"port"`
+		Subdomain  monad.Maybe[string] `json:"subdomain"`
+		PathPrefix monad.Maybe[string] `json:"path_prefix"`
+		Ports      []ExposedPort       `json:"ports"`
+	}
+
+	ExposedPort struct {
+		Router    string            `json:"router"`
+		Port      uint              `json:"port"`
+		Published monad.Maybe[uint] `json:"published"`
 	}
 
 	VersionControl struct {
@@ -35,16 +65,34 @@ type (
 
 func (Command) Name_() string { return "deployment.command.create_app" }
 
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
 func Handler(
 	reader domain.AppsReader,
 	writer domain.AppsWriter,
+	teamsReader domain.TeamsReader,
+	registriesReader domain.RegistriesReader,
+	targetsReader domain.TargetsReader,
+	selection domain.TargetSelection,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
+		if err := auth.AuthorizeCreation(ctx); err != nil {
+			return "", err
+		}
+
 		var (
-			appname          domain.AppName
-			url              domain.Url
-			productionTarget = domain.TargetID(cmd.Production.Target)
-			stagingTarget    = domain.TargetID(cmd.Staging.Target)
+			appname            domain.AppName
+			url                domain.Url
+			productionExposure monad.Maybe[domain.ServicesExposure]
+			stagingExposure    monad.Maybe[domain.ServicesExposure]
+			labels             domain.Labels
+			platforms          domain.Platforms
+			staticSite         monad.Maybe[domain.StaticSite]
+			productionTarget   = domain.TargetID(cmd.Production.Target)
+			stagingTarget      = domain.TargetID(cmd.Staging.Target)
 		)
 
 		if err := validate.Struct(validate.Of{
@@ -56,20 +104,57 @@ func Handler(
 				})
 			}),
 			"production": validate.Struct(validate.Of{
-				"target": validate.Field(cmd.Production.Target, strings.Required),
+				"exposure": ValidateServicesExposure(cmd.Production.Exposure, &productionExposure),
 			}),
 			"staging": validate.Struct(validate.Of{
-				"target": validate.Field(cmd.Staging.Target, strings.Required),
+				"exposure": ValidateServicesExposure(cmd.Staging.Exposure, &stagingExposure),
+			}),
+			"team_id":           validate.Maybe(cmd.TeamID, strings.Required),
+			"build_registry_id": validate.Maybe(cmd.BuildRegistry, strings.Required),
+			"labels": validate.Maybe(cmd.Labels, func(values []string) error {
+				return validate.Value(values, &labels, domain.LabelsFrom)
+			}),
+			"platforms": validate.Maybe(cmd.Platforms, func(values []string) error {
+				return validate.Value(values, &platforms, domain.PlatformsFrom)
+			}),
+			"static_site": validate.Maybe(cmd.StaticSite, func(site StaticSite) error {
+				return ValidateStaticSite(site, &staticSite)
 			}),
 		}); err != nil {
 			return "", err
 		}
 
+		// Environments without an explicit target are assigned one using the selection rules.
+		if productionTarget == "" || stagingTarget == "" {
+			candidates, err := targetsReader.GetCandidates(ctx)
+
+			if err != nil {
+				return "", err
+			}
+
+			selected, err := selection.Select(candidates)
+
+			if err != nil {
+				return "", validate.Struct(validate.Of{
+					"production.target": validate.If(productionTarget == "", func() error { return err }),
+					"staging.target":    validate.If(stagingTarget == "", func() error { return err }),
+				})
+			}
+
+			if productionTarget == "" {
+				productionTarget = selected
+			}
+
+			if stagingTarget == "" {
+				stagingTarget = selected
+			}
+		}
+
 		productionRequirement, stagingRequirement, err := reader.CheckAppNamingAvailability(
 			ctx,
 			appname,
-			BuildEnvironmentConfig(productionTarget, cmd.Production.Vars),
-			BuildEnvironmentConfig(stagingTarget, cmd.Staging.Vars),
+			BuildEnvironmentConfig(productionTarget, cmd.Production.Vars, productionExposure),
+			BuildEnvironmentConfig(stagingTarget, cmd.Staging.Vars, stagingExposure),
 		)
 
 		if err != nil {
@@ -105,6 +190,54 @@ func Handler(
 			app.UseVersionControl(vcs)
 		}
 
+		if teamID, isSet := cmd.TeamID.TryGet(); isSet {
+			team, err := GetTeam(ctx, teamsReader, domain.TeamID(teamID))
+
+			if err != nil {
+				return "", err
+			}
+
+			if err = app.JoinTeam(team); err != nil {
+				return "", err
+			}
+		}
+
+		if dependencies, isSet := cmd.Dependencies.TryGet(); isSet {
+			if err = DependsOn(ctx, reader, &app, dependencies); err != nil {
+				return "", err
+			}
+		}
+
+		if cmd.Labels.HasValue() {
+			if err = app.HasLabels(labels); err != nil {
+				return "", err
+			}
+		}
+
+		if cmd.Platforms.HasValue() {
+			if err = app.HasPlatforms(platforms); err != nil {
+				return "", err
+			}
+		}
+
+		if registryID, isSet := cmd.BuildRegistry.TryGet(); isSet {
+			registry, err := GetBuildRegistry(ctx, registriesReader, domain.RegistryID(registryID))
+
+			if err != nil {
+				return "", err
+			}
+
+			if err = app.UseBuildRegistry(registry); err != nil {
+				return "", err
+			}
+		}
+
+		if staticSite.HasValue() {
+			if err = app.HasStaticSite(staticSite); err != nil {
+				return "", err
+			}
+		}
+
 		if err := writer.Write(ctx, &app); err != nil {
 			return "", err
 		}
@@ -113,13 +246,144 @@ func Handler(
 	}
 }
 
+// Helper method to retrieve a team in which an app could be moved by the current user,
+// which should be allowed to deploy apps of this team.
+func GetTeam(ctx context.Context, reader domain.TeamsReader, id domain.TeamID) (domain.Team, error) {
+	team, err := reader.GetByID(ctx, id)
+
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return team, validate.Wrap(err, "team_id")
+		}
+
+		return team, err
+	}
+
+	return team, auth.Authorize(ctx, auth.PermissionDeploy, team.CreatedBy(), team.Resources()...)
+}
+
+// Helper method to retrieve the registry to which images built by an app are pushed.
+func GetBuildRegistry(ctx context.Context, reader domain.RegistriesReader, id domain.RegistryID) (domain.Registry, error) {
+	registry, err := reader.GetByID(ctx, id)
+
+	if errors.Is(err, apperr.ErrNotFound) {
+		return registry, validate.Wrap(err, "build_registry_id")
+	}
+
+	return registry, err
+}
+
+// Helper method to declare the apps the given one depends on, errors are reported
+// on the dependencies field.
+func DependsOn(ctx context.Context, reader domain.AppsReader, app *domain.App, ids []string) error {
+	graph, err := reader.GetDependencyGraph(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	dependencies := make(domain.AppDependencies, len(ids))
+
+	for i, id := range ids {
+		dependencies[i] = domain.AppID(id)
+	}
+
+	if err = app.DependsOn(graph, dependencies); err != nil {
+		return validate.Wrap(err, "dependencies")
+	}
+
+	return nil
+}
+
 // Helper method to build a domain.EnvironmentConfig from a raw command value.
-func BuildEnvironmentConfig(target domain.TargetID, env monad.Maybe[map[string]map[string]string]) domain.EnvironmentConfig {
+func BuildEnvironmentConfig(
+	target domain.TargetID,
+	env monad.Maybe[map[string]map[string]string],
+	exposure monad.Maybe[domain.ServicesExposure],
+) domain.EnvironmentConfig {
 	config := domain.NewEnvironmentConfig(target)
 
 	if vars, hasVars := env.TryGet(); hasVars {
 		config.HasEnvironmentVariables(domain.ServicesEnvFrom(vars))
 	}
 
+	if overrides, hasOverrides := exposure.TryGet(); hasOverrides {
+		config.HasServicesExposure(overrides)
+	}
+
 	return config
 }
+
+// Helper method to validate a static site configuration of a raw command value and
+// write it to the given target if it is valid.
+func ValidateStaticSite(raw StaticSite, target *monad.Maybe[domain.StaticSite]) error {
+	site, err := domain.StaticSiteFrom(raw.Service, raw.Directory)
+
+	if err != nil {
+		return err
+	}
+
+	target.Set(site)
+
+	return nil
+}
+
+// Helper method to validate services exposure overrides of a raw command value and
+// write them to the given target if they are valid.
+func ValidateServicesExposure(
+	raw monad.Maybe[map[string]ServiceExposure],
+	target *monad.Maybe[domain.ServicesExposure],
+) error {
+	overrides, isSet := raw.TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	var (
+		exposure = make(domain.ServicesExposure, len(overrides))
+		fields   = make(validate.Of, len(overrides))
+	)
+
+	for service, override := range overrides {
+		var result domain.ServiceExposure
+
+		result.Disabled = override.Disabled
+		portsOf := make(validate.Of, len(override.Ports))
+
+		for i, port := range override.Ports {
+			exposed, err := domain.ExposedPortFrom(port.Router, port.Port, port.Published)
+			result.Ports = append(result.Ports, exposed)
+			portsOf[strconv.Itoa(i)] = err
+		}
+
+		fields[service] = validate.Struct(validate.Of{
+			"port": validate.Maybe(override.Port, func(value uint) error {
+				port, err := domain.PortFrom(value)
+				result.Port.Set(port)
+				return err
+			}),
+			"subdomain": validate.Maybe(override.Subdomain, func(value string) error {
+				subdomain, err := domain.SubdomainFrom(value)
+				result.Subdomain.Set(subdomain)
+				return err
+			}),
+			"path_prefix": validate.Maybe(override.PathPrefix, func(value string) error {
+				prefix, err := domain.PathPrefixFrom(value)
+				result.PathPrefix.Set(prefix)
+				return err
+			}),
+			"ports": validate.Struct(portsOf),
+		})
+
+		exposure[service] = result
+	}
+
+	if err := validate.Struct(fields); err != nil {
+		return err
+	}
+
+	target.Set(exposure)
+
+	return nil
+}
@@ -7,9 +7,11 @@ import (
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
 	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 	"github.com/YuukanOO/seelf/pkg/validate"
@@ -17,11 +19,30 @@ import (
 
 func Test_CreateApp(t *testing.T) {
 	ctx := auth.WithUserID(context.Background(), "some-uid")
+	selection := must.Panic(domain.TargetSelectionFrom(domain.Labels{"region=eu"}, ""))
 	sut := func(existingApps ...*domain.App) bus.RequestHandler[string, create_app.Command] {
 		store := memory.NewAppsStore(existingApps...)
-		return create_app.Handler(store, store)
+		return create_app.Handler(store, store, memory.NewTeamsStore(), memory.NewRegistriesStore(), memory.NewTargetsStore(), selection)
 	}
 
+	t.Run("should not be allowed with a scoped API token", func(t *testing.T) {
+		uc := sut()
+		scopedCtx := auth.WithScopes(ctx, auth.Scopes{"deploy:deployments"})
+
+		id, err := uc(scopedCtx, create_app.Command{
+			Name: "my-app",
+			Production: create_app.EnvironmentConfig{
+				Target: "production-target",
+			},
+			Staging: create_app.EnvironmentConfig{
+				Target: "staging-target",
+			},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
 	t.Run("should require valid inputs", func(t *testing.T) {
 		uc := sut()
 		id, err := uc(ctx, create_app.Command{})
@@ -30,6 +51,39 @@ func Test_CreateApp(t *testing.T) {
 		testutil.Equals(t, "", id)
 	})
 
+	t.Run("should require valid services exposure overrides", func(t *testing.T) {
+		uc := sut()
+		id, err := uc(ctx, create_app.Command{
+			Name: "my-app",
+			Production: create_app.EnvironmentConfig{
+				Target: "production-target",
+				Exposure: monad.Value(map[string]create_app.ServiceExposure{
+					"app": {
+						Port:       monad.Value[uint](70000),
+						Subdomain:  monad.Value("Not a subdomain"),
+						PathPrefix: monad.Value("api"),
+						Ports: []create_app.ExposedPort{
+							{Router: "http", Port: 80},
+							{Router: "tcp", Port: 5432, Published: monad.Value[uint](0)},
+						},
+					},
+				}),
+			},
+			Staging: create_app.EnvironmentConfig{
+				Target: "staging-target",
+			},
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.Equals(t, "", id)
+		testutil.ErrorIs(t, domain.ErrInvalidPort, validationErr["production.exposure.app.port"])
+		testutil.ErrorIs(t, domain.ErrInvalidSubdomain, validationErr["production.exposure.app.subdomain"])
+		testutil.ErrorIs(t, domain.ErrInvalidPathPrefix, validationErr["production.exposure.app.path_prefix"])
+		testutil.ErrorIs(t, domain.ErrInvalidRouter, validationErr["production.exposure.app.ports.0"])
+		testutil.ErrorIs(t, domain.ErrInvalidPort, validationErr["production.exposure.app.ports.1"])
+	})
+
 	t.Run("should fail if the name is already taken", func(t *testing.T) {
 		a := must.Panic(domain.NewApp("my-app",
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
@@ -68,4 +122,105 @@ func Test_CreateApp(t *testing.T) {
 		testutil.IsNil(t, err)
 		testutil.NotEquals(t, "", id)
 	})
+
+	t.Run("should fail if no target matches the selection rules when none is given", func(t *testing.T) {
+		uc := sut()
+		id, err := uc(ctx, create_app.Command{
+			Name:    "my-app",
+			Staging: create_app.EnvironmentConfig{Target: "staging-target"},
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.Equals(t, "", id)
+		testutil.ErrorIs(t, domain.ErrNoTargetAvailable, validationErr["production.target"])
+		testutil.IsNil(t, validationErr["staging.target"])
+	})
+
+	t.Run("should select the target of environments without one", func(t *testing.T) {
+		eu := fixture.Target("http://eu.docker.localhost", true, fixture.WithLabels(domain.Labels{"region=eu"}))
+		us := fixture.Target("http://us.docker.localhost", true, fixture.WithLabels(domain.Labels{"region=us"}))
+		store := memory.NewAppsStore()
+		uc := create_app.Handler(store, store, memory.NewTeamsStore(), memory.NewRegistriesStore(), memory.NewTargetsStore(&us, &eu), selection)
+
+		id, err := uc(ctx, create_app.Command{
+			Name:    "my-app",
+			Staging: create_app.EnvironmentConfig{Target: "staging-target"},
+		})
+
+		testutil.IsNil(t, err)
+
+		app := must.Panic(store.GetByID(context.Background(), domain.AppID(id)))
+		testutil.Equals(t, eu.ID(), app.Production().Target())
+		testutil.Equals(t, domain.TargetID("staging-target"), app.Staging().Target())
+	})
+
+	t.Run("should fail if the team does not exist", func(t *testing.T) {
+		uc := sut()
+		id, err := uc(ctx, create_app.Command{
+			Name:       "my-app",
+			Production: create_app.EnvironmentConfig{Target: "production-target"},
+			Staging:    create_app.EnvironmentConfig{Target: "staging-target"},
+			TeamID:     monad.Value("non-existing-team"),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.Equals(t, "", id)
+		testutil.ErrorIs(t, apperr.ErrNotFound, validationErr["team_id"])
+	})
+
+	t.Run("should fail if the user is not allowed to deploy apps of the team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "admin-uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceTeam, string(team.ID())), true), auth.RoleReadOnly)
+		store := memory.NewAppsStore()
+		uc := create_app.Handler(store, store, memory.NewTeamsStore(&team), memory.NewRegistriesStore(), memory.NewTargetsStore(), selection)
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_app.Command{
+			Name:       "my-app",
+			Production: create_app.EnvironmentConfig{Target: "production-target"},
+			Staging:    create_app.EnvironmentConfig{Target: "staging-target"},
+			TeamID:     monad.Value(string(team.ID())),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should create the app in the given team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "admin-uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceTeam, string(team.ID())), true), auth.RoleDeployer)
+		store := memory.NewAppsStore()
+		uc := create_app.Handler(store, store, memory.NewTeamsStore(&team), memory.NewRegistriesStore(), memory.NewTargetsStore(), selection)
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_app.Command{
+			Name:       "my-app",
+			Production: create_app.EnvironmentConfig{Target: "production-target"},
+			Staging:    create_app.EnvironmentConfig{Target: "staging-target"},
+			TeamID:     monad.Value(string(team.ID())),
+		})
+
+		testutil.IsNil(t, err)
+
+		app := must.Panic(store.GetByID(context.Background(), domain.AppID(id)))
+		testutil.Equals(t, team.ID(), app.Team().MustGet())
+	})
+
+	t.Run("should fail if the build registry does not exist", func(t *testing.T) {
+		uc := sut()
+
+		id, err := uc(ctx, create_app.Command{
+			Name:          "my-app",
+			Production:    create_app.EnvironmentConfig{Target: "production-target"},
+			Staging:       create_app.EnvironmentConfig{Target: "staging-target"},
+			BuildRegistry: monad.Value("unknown"),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.Equals(t, "", id)
+		testutil.ErrorIs(t, apperr.ErrNotFound, validationErr["build_registry_id"])
+	})
 }
@@ -0,0 +1,95 @@
+package create_dns_zone
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type (
+	// Register a DNS zone hosted by a provider so records of apps exposed under its
+	// domain are automatically managed. Records point to the given addresses or alias the
+	// target host if none are given.
+	Command struct {
+		bus.Command[string]
+
+		Name        string      `json:"name"`
+		Provider    string      `json:"provider"`
+		Credentials Credentials `json:"credentials"`
+		Addresses   []string    `json:"addresses"`
+	}
+
+	Credentials struct {
+		Token     string `json:"token"`      // API token for cloudflare and desec
+		AccessKey string `json:"access_key"` // Access key ID for route53
+		SecretKey string `json:"secret_key"` // Secret access key for route53
+	}
+)
+
+func (Command) Name_() string { return "deployment.command.create_dns_zone" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	reader domain.DnsZonesReader,
+	writer domain.DnsZonesWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var (
+			name           string
+			credentials    domain.DnsCredentials
+			credentialsErr error
+			addresses      = make(domain.DnsAddresses, len(cmd.Addresses))
+			addressesOf    = make(validate.Of, len(cmd.Addresses))
+		)
+
+		provider, providerErr := domain.DnsProviderKindFrom(cmd.Provider)
+
+		if providerErr == nil {
+			credentials, credentialsErr = domain.DnsCredentialsFrom(provider, cmd.Credentials.Token, cmd.Credentials.AccessKey, cmd.Credentials.SecretKey)
+		}
+
+		for i, value := range cmd.Addresses {
+			addressesOf[strconv.Itoa(i)] = validate.Value(value, &addresses[i], domain.DnsAddressFrom)
+		}
+
+		if err := validate.Struct(validate.Of{
+			"name":        validate.Value(cmd.Name, &name, domain.DnsZoneNameFrom),
+			"provider":    providerErr,
+			"credentials": credentialsErr,
+			"addresses":   validate.Struct(addressesOf),
+		}); err != nil {
+			return "", err
+		}
+
+		nameRequirement, err := reader.CheckNameAvailability(ctx, name)
+
+		if err != nil {
+			return "", err
+		}
+
+		zone, err := domain.NewDnsZone(nameRequirement, provider, credentials, addresses, auth.CurrentUser(ctx).MustGet())
+
+		if err != nil {
+			return "", err
+		}
+
+		if err := writer.Write(ctx, &zone); err != nil {
+			return "", err
+		}
+
+		return string(zone.ID()), nil
+	}
+}
@@ -0,0 +1,104 @@
+package create_dns_zone_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_CreateDnsZone(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(existing ...*domain.DnsZone) (bus.RequestHandler[string, create_dns_zone.Command], memory.DnsZonesStore) {
+		store := memory.NewDnsZonesStore(existing...)
+		return create_dns_zone.Handler(store, store), store
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc, _ := sut()
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_dns_zone.Command{
+			Name:        "example.com",
+			Provider:    "cloudflare",
+			Credentials: create_dns_zone.Credentials{Token: "a token"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc, _ := sut()
+
+		id, err := uc(ctx, create_dns_zone.Command{
+			Name:      "localhost",
+			Provider:  "route53",
+			Addresses: []string{"203.0.113.10", "not an ip"},
+		})
+
+		testutil.Equals(t, "", id)
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidDnsZone, validationErr["name"])
+		testutil.ErrorIs(t, domain.ErrInvalidDnsCredentials, validationErr["credentials"])
+		testutil.ErrorIs(t, domain.ErrInvalidDnsAddress, validationErr["addresses.1"])
+	})
+
+	t.Run("should require a supported provider", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, create_dns_zone.Command{
+			Name:     "example.com",
+			Provider: "gandi",
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidDnsProvider, validationErr["provider"])
+	})
+
+	t.Run("should require a unique name", func(t *testing.T) {
+		existing := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare,
+			domain.DnsCredentials{Token: "a token"}, nil, "uid"))
+		uc, _ := sut(&existing)
+
+		_, err := uc(ctx, create_dns_zone.Command{
+			Name:        "Example.com",
+			Provider:    "desec",
+			Credentials: create_dns_zone.Credentials{Token: "another token"},
+		})
+
+		testutil.ErrorIs(t, domain.ErrDnsZoneNameTaken, err)
+	})
+
+	t.Run("should register a new zone", func(t *testing.T) {
+		uc, store := sut()
+
+		id, err := uc(ctx, create_dns_zone.Command{
+			Name:        "example.com",
+			Provider:    "route53",
+			Credentials: create_dns_zone.Credentials{AccessKey: "access", SecretKey: "secret"},
+			Addresses:   []string{"203.0.113.10"},
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+
+		zone, err := store.GetByID(ctx, domain.DnsZoneID(id))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "example.com", zone.Name())
+		testutil.Equals(t, domain.DnsProviderRoute53, zone.Provider())
+		testutil.Equals(t, domain.DnsCredentials{AccessKey: "access", SecretKey: "secret"}, zone.Credentials())
+		testutil.DeepEquals(t, domain.DnsAddresses{"203.0.113.10"}, zone.Addresses())
+		testutil.Equals(t, auth.UserID("some-uid"), zone.CreatedBy())
+	})
+}
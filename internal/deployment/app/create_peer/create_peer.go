@@ -0,0 +1,58 @@
+package create_peer
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Register another seelf instance so its apps could be listed alongside local ones. The
+// token is an API key or token of one of its users, only apps readable by this user
+// are listed.
+type Command struct {
+	bus.Command[string]
+
+	Name  string `json:"name"`
+	Url   string `json:"url"`
+	Token string `json:"token"`
+}
+
+func (Command) Name_() string { return "deployment.command.create_peer" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	writer domain.PeersWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var url domain.Url
+
+		if err := validate.Struct(validate.Of{
+			"name":  validate.Field(cmd.Name, strings.Required),
+			"url":   validate.Value(cmd.Url, &url, domain.UrlFrom),
+			"token": validate.Field(cmd.Token, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		peer := domain.NewPeer(cmd.Name, url, cmd.Token, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &peer); err != nil {
+			return "", err
+		}
+
+		return string(peer.ID()), nil
+	}
+}
@@ -0,0 +1,70 @@
+package create_peer_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreatePeer(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := create_peer.Handler(memory.NewPeersStore())
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_peer.Command{
+			Name:  "vps",
+			Url:   "https://seelf.example.com",
+			Token: "a token",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := create_peer.Handler(memory.NewPeersStore())
+
+		id, err := uc(ctx, create_peer.Command{
+			Url: "not an url",
+		})
+
+		testutil.Equals(t, "", id)
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["name"])
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["token"])
+	})
+
+	t.Run("should register a new peer", func(t *testing.T) {
+		store := memory.NewPeersStore()
+		uc := create_peer.Handler(store)
+
+		id, err := uc(ctx, create_peer.Command{
+			Name:  "vps",
+			Url:   "https://seelf.example.com",
+			Token: "a token",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+
+		peer, err := store.GetByID(ctx, domain.PeerID(id))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "vps", peer.Name())
+		testutil.Equals(t, "https://seelf.example.com", peer.Url().String())
+		testutil.Equals(t, "a token", peer.Token())
+		testutil.Equals(t, auth.UserID("some-uid"), peer.CreatedBy())
+	})
+}
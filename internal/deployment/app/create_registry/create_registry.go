@@ -28,6 +28,11 @@ type (
 
 func (Command) Name_() string { return "deployment.command.create_registry" }
 
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
 func Handler(
 	reader domain.RegistriesReader,
 	writer domain.RegistriesWriter,
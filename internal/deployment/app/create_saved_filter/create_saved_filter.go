@@ -0,0 +1,68 @@
+package create_saved_filter
+
+import (
+	"context"
+	"strings"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	vstrings "github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Save list criteria for the current user so apps or deployments could be quickly
+// filtered the same way later on.
+type Command struct {
+	bus.Command[string]
+
+	Name        string              `json:"name"`
+	Kind        string              `json:"kind"`
+	Labels      []string            `json:"labels"`
+	TeamID      monad.Maybe[string] `json:"team_id"`
+	Environment monad.Maybe[string] `json:"environment"`
+	Search      monad.Maybe[string] `json:"search"`
+}
+
+func (Command) Name_() string { return "deployment.command.create_saved_filter" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	writer domain.SavedFiltersWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			kind     domain.SavedFilterKind
+			env      domain.Environment
+			criteria domain.SavedFilterCriteria
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name":   validate.Field(cmd.Name, vstrings.Required),
+			"kind":   validate.Value(cmd.Kind, &kind, domain.SavedFilterKindFrom),
+			"labels": validate.Value(cmd.Labels, &criteria.Labels, domain.LabelsFrom),
+			"environment": validate.Maybe(cmd.Environment, func(value string) error {
+				return validate.Value(value, &env, domain.EnvironmentFrom)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		criteria.TeamID = cmd.TeamID.Get("")
+		criteria.Environment = string(env)
+		criteria.Search = strings.TrimSpace(cmd.Search.Get(""))
+
+		filter := domain.NewSavedFilter(cmd.Name, kind, criteria, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &filter); err != nil {
+			return "", err
+		}
+
+		return string(filter.ID()), nil
+	}
+}
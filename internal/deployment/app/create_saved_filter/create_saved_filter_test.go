@@ -0,0 +1,57 @@
+package create_saved_filter_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_saved_filter"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_CreateSavedFilter(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := create_saved_filter.Handler(memory.NewSavedFiltersStore())
+
+		id, err := uc(ctx, create_saved_filter.Command{
+			Kind:        "targets",
+			Labels:      []string{" "},
+			Environment: monad.Value("dev"),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should save the filter for the current user", func(t *testing.T) {
+		store := memory.NewSavedFiltersStore()
+		uc := create_saved_filter.Handler(store)
+
+		id, err := uc(ctx, create_saved_filter.Command{
+			Name:        "team a",
+			Kind:        "deployments",
+			Labels:      []string{"team-a"},
+			Environment: monad.Value("staging"),
+			Search:      monad.Value(" OPS-42 "),
+		})
+
+		testutil.IsNil(t, err)
+
+		filter, err := store.GetByID(ctx, domain.SavedFilterID(id))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.SavedFilterDeployments, filter.Kind())
+		testutil.Equals(t, "some-uid", filter.CreatedBy())
+		testutil.DeepEquals(t, domain.SavedFilterCriteria{
+			Labels:      domain.Labels{"team-a"},
+			Environment: "staging",
+			Search:      "OPS-42",
+		}, filter.Criteria())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/YuukanOO/seelf/pkg/validate/strings"
 )
@@ -13,24 +14,41 @@ import (
 type Command struct {
 	bus.Command[string]
 
-	Name     string `json:"name"`
-	Url      string `json:"url"`
-	Provider any    `json:"-"`
+	Name     string                         `json:"name"`
+	Url      string                         `json:"url"`
+	Labels   monad.Maybe[[]string]          `json:"labels"` // Used to select a target for new apps
+	Vars     monad.Maybe[map[string]string] `json:"vars"`   // Shared by every app deployed on the target
+	Provider any                            `json:"-"`
 }
 
 func (Command) Name_() string { return "deployment.command.create_target" }
 
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
 func Handler(
 	reader domain.TargetsReader,
 	writer domain.TargetsWriter,
 	provider domain.Provider,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var targetUrl domain.Url
+		if err := auth.AuthorizeCreation(ctx); err != nil {
+			return "", err
+		}
+
+		var (
+			targetUrl domain.Url
+			labels    domain.Labels
+		)
 
 		if err := validate.Struct(validate.Of{
 			"name": validate.Field(cmd.Name, strings.Required),
 			"url":  validate.Value(cmd.Url, &targetUrl, domain.UrlFrom),
+			"labels": validate.Maybe(cmd.Labels, func(values []string) error {
+				return validate.Value(values, &labels, domain.LabelsFrom)
+			}),
 		}); err != nil {
 			return "", err
 		}
@@ -72,6 +90,18 @@ func Handler(
 			return "", err
 		}
 
+		if cmd.Labels.HasValue() {
+			if err = target.HasLabels(labels); err != nil {
+				return "", err
+			}
+		}
+
+		if vars, isSet := cmd.Vars.TryGet(); isSet {
+			if err = target.HasSharedVariables(vars); err != nil {
+				return "", err
+			}
+		}
+
 		if err = writer.Write(ctx, &target); err != nil {
 			return "", err
 		}
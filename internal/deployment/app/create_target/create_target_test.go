@@ -28,6 +28,18 @@ func Test_CreateTarget(t *testing.T) {
 		return create_target.Handler(store, store, &dummyProvider{})
 	}
 
+	t.Run("should not be allowed with a scoped API token", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(auth.WithScopes(ctx, auth.Scopes{"read:some-app"}), create_target.Command{
+			Name:     "target",
+			Url:      "http://example.com",
+			Provider: config,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
 	t.Run("should require valid inputs", func(t *testing.T) {
 		uc := sut()
 
@@ -0,0 +1,51 @@
+package create_team
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Create a new team. Only admins are allowed to create teams since members are
+// added by granting them a role on it.
+type Command struct {
+	bus.Command[string]
+
+	Name string `json:"name"`
+}
+
+func (Command) Name_() string { return "deployment.command.create_team" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	writer domain.TeamsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Field(cmd.Name, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		team := domain.NewTeam(cmd.Name, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &team); err != nil {
+			return "", err
+		}
+
+		return string(team.ID()), nil
+	}
+}
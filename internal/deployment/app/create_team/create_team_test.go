@@ -0,0 +1,50 @@
+package create_team_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_team"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_CreateTeam(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := create_team.Handler(memory.NewTeamsStore())
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_team.Command{
+			Name: "my-team",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := create_team.Handler(memory.NewTeamsStore())
+
+		id, err := uc(ctx, create_team.Command{})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should create a new team", func(t *testing.T) {
+		uc := create_team.Handler(memory.NewTeamsStore())
+
+		id, err := uc(ctx, create_team.Command{
+			Name: "my-team",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+	})
+}
@@ -3,13 +3,16 @@ package delete_app
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
 
-// Cleanup an application artifacts, images, networks, volumes and so on...
+// Cleanup an application artifacts, images, networks, volumes and so on... When a
+// trash retention is configured, the application is moved to the trash so it could
+// be restored later on.
 type Command struct {
 	bus.Command[bus.UnitType]
 
@@ -23,6 +26,9 @@ func Handler(
 	reader domain.AppsReader,
 	writer domain.AppsWriter,
 	artifactManager domain.ArtifactManager,
+	deploymentsReader domain.DeploymentsReader,
+	trashWriter domain.TrashedAppsWriter,
+	trashRetention time.Duration,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
 		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
@@ -44,6 +50,27 @@ func Handler(
 			return bus.Unit, err
 		}
 
-		return bus.Unit, writer.Write(ctx, &app)
+		if trashRetention <= 0 {
+			return bus.Unit, writer.Write(ctx, &app)
+		}
+
+		// Deployments are removed along with the app so they must be read beforehand
+		deployments, err := deploymentsReader.GetByApp(ctx, app.ID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		trashed, err := domain.NewTrashedApp(app, deployments, trashRetention)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = writer.Write(ctx, &app); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, trashWriter.Write(ctx, &trashed)
 	}
 }
@@ -4,36 +4,40 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
 
-func DeleteApp(t *testing.T) {
+func Test_DeleteApp(t *testing.T) {
 	ctx := context.Background()
 	logger, _ := log.NewLogger()
 
-	sut := func(initialApps ...*domain.App) bus.RequestHandler[bus.UnitType, delete_app.Command] {
+	sut := func(retention time.Duration, initialApps ...*domain.App) (bus.RequestHandler[bus.UnitType, delete_app.Command], memory.TrashedAppsStore) {
 		opts := config.Default(config.WithTestDefaults())
 		appsStore := memory.NewAppsStore(initialApps...)
-		artifactManager := artifact.NewLocal(opts, logger)
+		trashStore := memory.NewTrashedAppsStore()
+		deploymentsStore := memory.NewDeploymentsStore()
+		artifactManager := artifact.NewLocal(opts, logger, nil)
 
 		t.Cleanup(func() {
 			os.RemoveAll(opts.DataDir())
 		})
 
-		return delete_app.Handler(appsStore, appsStore, artifactManager)
+		return delete_app.Handler(appsStore, appsStore, artifactManager, deploymentsStore, trashStore, retention), trashStore
 	}
 
 	t.Run("should fail silently if the application does not exist anymore", func(t *testing.T) {
-		uc := sut()
+		uc, _ := sut(0)
 
 		r, err := uc(ctx, delete_app.Command{
 			ID: "some-id",
@@ -47,7 +51,7 @@ func DeleteApp(t *testing.T) {
 		app := must.Panic(domain.NewApp("my-app",
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
-		uc := sut(&app)
+		uc, _ := sut(0, &app)
 
 		r, err := uc(ctx, delete_app.Command{
 			ID: string(app.ID()),
@@ -63,7 +67,7 @@ func DeleteApp(t *testing.T) {
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
 		app.RequestCleanup("uid")
 
-		uc := sut(&app)
+		uc, trashStore := sut(0, &app)
 
 		r, err := uc(ctx, delete_app.Command{
 			ID: string(app.ID()),
@@ -73,5 +77,30 @@ func DeleteApp(t *testing.T) {
 		testutil.Equals(t, bus.Unit, r)
 		testutil.HasNEvents(t, &app, 3)
 		testutil.EventIs[domain.AppDeleted](t, &app, 2)
+
+		_, err = trashStore.GetByID(ctx, app.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should move the application to the trash if a retention is set", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+		app.RequestCleanup("uid")
+
+		uc, trashStore := sut(time.Hour, &app)
+
+		r, err := uc(ctx, delete_app.Command{
+			ID: string(app.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.EventIs[domain.AppDeleted](t, &app, 2)
+
+		trashed, err := trashStore.GetByID(ctx, app.ID())
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "my-app", trashed.Name())
+		testutil.Equals(t, "uid", trashed.Deleted().By())
 	})
 }
@@ -0,0 +1,42 @@
+package delete_dns_zone
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Stop managing records of a DNS zone. Records already created on the provider are
+// left untouched.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.delete_dns_zone" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.DnsZonesReader,
+	writer domain.DnsZonesWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !auth.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		zone, err := reader.GetByID(ctx, domain.DnsZoneID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		zone.Delete()
+
+		return bus.Unit, writer.Write(ctx, &zone)
+	}
+}
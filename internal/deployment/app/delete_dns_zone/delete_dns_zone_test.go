@@ -0,0 +1,62 @@
+package delete_dns_zone_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteDnsZone(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newZone := func() domain.DnsZone {
+		return must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare,
+			domain.DnsCredentials{Token: "a token"}, nil, "uid"))
+	}
+	sut := func(existing ...*domain.DnsZone) bus.RequestHandler[bus.UnitType, delete_dns_zone.Command] {
+		store := memory.NewDnsZonesStore(existing...)
+		return delete_dns_zone.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		z := newZone()
+		uc := sut(&z)
+
+		_, err := uc(auth.WithUser(context.Background(), user), delete_dns_zone.Command{
+			ID: string(z.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing zone", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, delete_dns_zone.Command{
+			ID: "another-zone",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the zone", func(t *testing.T) {
+		z := newZone()
+		uc := sut(&z)
+
+		_, err := uc(ctx, delete_dns_zone.Command{
+			ID: string(z.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DnsZoneDeleted](t, &z, 1)
+		testutil.Equals(t, z.ID(), evt.ID)
+	})
+}
@@ -0,0 +1,57 @@
+package delete_monitor
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Stop monitoring the public url of an application environment.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string `json:"-"`
+	Environment string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.delete_monitor" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.MonitorsReader,
+	writer domain.MonitorsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var env domain.Environment
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		monitor, err := reader.GetByApp(ctx, app.ID(), env)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		monitor.Delete()
+
+		return bus.Unit, writer.Write(ctx, &monitor)
+	}
+}
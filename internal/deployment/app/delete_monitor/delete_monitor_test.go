@@ -0,0 +1,56 @@
+package delete_monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteMonitor(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	sut := func(existingMonitors ...*domain.Monitor) (bus.RequestHandler[bus.UnitType, delete_monitor.Command], memory.MonitorsStore) {
+		store := memory.NewMonitorsStore(existingMonitors...)
+		return delete_monitor.Handler(memory.NewAppsStore(&app), store, store), store
+	}
+
+	t.Run("should fail if the environment is not monitored", func(t *testing.T) {
+		uc, _ := sut()
+
+		_, err := uc(ctx, delete_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should stop monitoring the app environment", func(t *testing.T) {
+		monitor := must.Panic(domain.NewMonitor(app, domain.Production,
+			must.Panic(domain.NewUptimeCheck(time.Minute, 200, monad.None[string]())), "some-uid"))
+		uc, store := sut(&monitor)
+
+		r, err := uc(ctx, delete_monitor.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+
+		_, err = store.GetByID(ctx, monitor.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,40 @@
+package delete_peer
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.delete_peer" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.PeersReader,
+	writer domain.PeersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !auth.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		peer, err := reader.GetByID(ctx, domain.PeerID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		peer.Delete()
+
+		return bus.Unit, writer.Write(ctx, &peer)
+	}
+}
@@ -0,0 +1,59 @@
+package delete_peer_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeletePeer(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	url := must.Panic(domain.UrlFrom("https://seelf.example.com"))
+	sut := func(existing ...*domain.Peer) bus.RequestHandler[bus.UnitType, delete_peer.Command] {
+		store := memory.NewPeersStore(existing...)
+		return delete_peer.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		uc := sut(&p)
+
+		_, err := uc(auth.WithUser(context.Background(), user), delete_peer.Command{
+			ID: string(p.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing peer", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, delete_peer.Command{
+			ID: "another-peer",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the peer", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		uc := sut(&p)
+
+		_, err := uc(ctx, delete_peer.Command{
+			ID: string(p.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.PeerDeleted](t, &p, 1)
+		testutil.Equals(t, p.ID(), evt.ID)
+	})
+}
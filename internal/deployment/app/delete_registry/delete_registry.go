@@ -3,8 +3,10 @@ package delete_registry
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
 type Command struct {
@@ -13,21 +15,25 @@ type Command struct {
 	ID string `json:"id"`
 }
 
-func (Command) Name_() string { return "deployment.command.delete_registry" }
+func (Command) Name_() string              { return "deployment.command.delete_registry" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.RegistriesReader,
 	writer domain.RegistriesWriter,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
-		registry, err := reader.GetByID(ctx, domain.RegistryID(cmd.ID))
-
-		if err != nil {
-			return bus.Unit, err
-		}
-
-		registry.Delete()
-
-		return bus.Unit, writer.Write(ctx, &registry)
+		return monad.Bind(
+			monad.ResultFrom(reader.GetByID(ctx, domain.RegistryID(cmd.ID))),
+			func(registry domain.Registry) monad.Result[bus.UnitType] {
+				if err := auth.Authorize(ctx, auth.PermissionManage, registry.CreatedBy()); err != nil {
+					return monad.Fail[bus.UnitType](err)
+				}
+
+				registry.Delete()
+
+				return monad.ResultFrom(bus.Unit, writer.Write(ctx, &registry))
+			},
+		).Get()
 	}
 }
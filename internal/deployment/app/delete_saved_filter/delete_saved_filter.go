@@ -0,0 +1,42 @@
+package delete_saved_filter
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Delete a saved filter of the current user.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.delete_saved_filter" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.SavedFiltersReader,
+	writer domain.SavedFiltersWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		filter, err := reader.GetByID(ctx, domain.SavedFilterID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Saved filters are personal, filters of other users are not even visible
+		if filter.CreatedBy() != auth.CurrentUser(ctx).MustGet() {
+			return bus.Unit, apperr.ErrNotFound
+		}
+
+		filter.Delete()
+
+		return bus.Unit, writer.Write(ctx, &filter)
+	}
+}
@@ -0,0 +1,59 @@
+package delete_saved_filter_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_saved_filter"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteSavedFilter(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	sut := func(filter *domain.SavedFilter) bus.RequestHandler[bus.UnitType, delete_saved_filter.Command] {
+		store := memory.NewSavedFiltersStore(filter)
+		return delete_saved_filter.Handler(store, store)
+	}
+
+	t.Run("should require an existing filter", func(t *testing.T) {
+		filter := domain.NewSavedFilter("my filter", domain.SavedFilterApps, domain.SavedFilterCriteria{}, "some-uid")
+		uc := sut(&filter)
+
+		_, err := uc(ctx, delete_saved_filter.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not delete a filter saved by another user", func(t *testing.T) {
+		filter := domain.NewSavedFilter("my filter", domain.SavedFilterApps, domain.SavedFilterCriteria{}, "another-uid")
+		uc := sut(&filter)
+
+		_, err := uc(ctx, delete_saved_filter.Command{
+			ID: string(filter.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+		testutil.HasNEvents(t, &filter, 1)
+	})
+
+	t.Run("should delete the filter", func(t *testing.T) {
+		filter := domain.NewSavedFilter("my filter", domain.SavedFilterApps, domain.SavedFilterCriteria{}, "some-uid")
+		uc := sut(&filter)
+
+		_, err := uc(ctx, delete_saved_filter.Command{
+			ID: string(filter.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.SavedFilterDeleted](t, &filter, 1)
+		testutil.Equals(t, filter.ID(), evt.ID)
+	})
+}
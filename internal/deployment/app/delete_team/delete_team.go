@@ -0,0 +1,49 @@
+package delete_team
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Delete a team which does not have any app anymore.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.delete_team" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.TeamsReader,
+	writer domain.TeamsWriter,
+	appsReader domain.AppsReader,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		team, err := reader.GetByID(ctx, domain.TeamID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, team.CreatedBy(), team.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		apps, err := appsReader.HasAppsInTeam(ctx, team.ID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = team.Delete(apps); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &team)
+	}
+}
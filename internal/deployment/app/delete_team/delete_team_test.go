@@ -0,0 +1,60 @@
+package delete_team_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_team"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteTeam(t *testing.T) {
+	sut := func(team *domain.Team, existingApps ...*domain.App) bus.RequestHandler[bus.UnitType, delete_team.Command] {
+		store := memory.NewTeamsStore(team)
+		return delete_team.Handler(store, store, memory.NewAppsStore(existingApps...))
+	}
+
+	t.Run("should require an existing team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+		uc := sut(&team)
+
+		_, err := uc(context.Background(), delete_team.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the team still has apps", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+		testutil.IsNil(t, app.JoinTeam(team))
+		uc := sut(&team, &app)
+
+		_, err := uc(context.Background(), delete_team.Command{
+			ID: string(team.ID()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrTeamInUse, err)
+	})
+
+	t.Run("should delete the team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+		uc := sut(&team)
+
+		_, err := uc(context.Background(), delete_team.Command{
+			ID: string(team.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.TeamDeleted](t, &team, 1)
+		testutil.Equals(t, team.ID(), evt.ID)
+	})
+}
@@ -3,11 +3,14 @@ package deploy
 import (
 	"context"
 	"errors"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
 // Process a deployment, this is where the magic happen!
@@ -16,10 +19,22 @@ type Command struct {
 
 	AppID            string `json:"app_id"`
 	DeploymentNumber int    `json:"deployment_number"`
+	ResumeFrom       string `json:"resume_from,omitempty"` // Step from which a retried deployment is resumed
 }
 
 func (Command) Name_() string        { return "deployment.command.deploy" }
 func (c Command) ResourceID() string { return c.AppID + "-" + strconv.Itoa(c.DeploymentNumber) }
+func (Command) PayloadVersion_() int { return 2 }
+
+// Version 1 payloads were queued before deployments could be resumed, they always
+// start from the beginning.
+func (Command) UpgradePayload_(from int, payload map[string]any) error {
+	if from == bus.InitialPayloadVersion {
+		payload["resume_from"] = ""
+	}
+
+	return nil
+}
 
 // Handle the deployment process.
 // If an unexpected error occurs during this process, it uses the bus.PreserveOrder function
@@ -32,6 +47,14 @@ func Handler(
 	provider domain.Provider,
 	targetsReader domain.TargetsReader,
 	registriesReader domain.RegistriesReader,
+	addonsReader domain.AddonsReader,
+	appsReader domain.AppsReader,
+	monitorsReader domain.MonitorsReader,
+	hooks domain.Hooks,
+	secrets domain.SecretResolver,
+	freezeWindows domain.FreezeWindows,
+	timeouts domain.DeploymentTimeouts,
+	transitionsWriter domain.DeploymentTransitionsWriter,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (result bus.UnitType, finalErr error) {
 		result = bus.Unit
@@ -65,6 +88,32 @@ func Handler(
 			return result, nil
 		}
 
+		// The app is loaded once for its freeze windows, timeouts and dependencies. It may
+		// not exist anymore if it is being deleted, its deployment will be cancelled anyway.
+		var app monad.Maybe[domain.App]
+
+		if a, appErr := appsReader.GetByID(ctx, depl.ID().AppID()); appErr == nil {
+			app.Set(a)
+		} else if !errors.Is(appErr, apperr.ErrNotFound) {
+			return result, appErr
+		}
+
+		// Production deployments are not processed during freeze windows, they are kept
+		// pending until the window ends or failed right away depending on its policy
+		frozen := activeFreeze(app, depl, freezeWindows)
+
+		var freezeErr error
+
+		if window, isFrozen := frozen.TryGet(); isFrozen {
+			if window.Policy != domain.FreezePolicyReject {
+				return result, domain.ErrDeploymentFrozen
+			}
+
+			freezeErr = domain.NewDeploymentFailure(domain.DeploymentFailureFrozen, domain.ErrDeploymentFrozen)
+		}
+
+		stageTimeouts := pipelineTimeouts(app, timeouts)
+
 		var targetAvailabilityErr error
 
 		if targetErr == nil {
@@ -77,6 +126,15 @@ func Handler(
 			}
 		}
 
+		// Dependencies still being deployed or not checked yet are waited for, keeping
+		// the deployment in pending state
+		dependenciesErr := checkDependencies(ctx, app, reader, monitorsReader, &depl)
+
+		if dependenciesErr != nil && !errors.Is(dependenciesErr, domain.ErrAppDependencyFailed) &&
+			!errors.Is(dependenciesErr, domain.ErrAppDependencyUnhealthy) {
+			return result, dependenciesErr
+		}
+
 		if err = writer.Write(ctx, &depl); err != nil {
 			return result, err
 		}
@@ -85,13 +143,24 @@ func Handler(
 			deploymentCtx domain.DeploymentContext
 			services      domain.Services
 			registries    []domain.Registry
+			addons        []domain.Addon
 		)
 
+		// Context of the pipeline stages, cancelled when one of them exceeds its timeout
+		pipelineCtx, cancelPipeline := context.WithCancelCause(ctx)
+		defer cancelPipeline(nil)
+
 		// This one is a special case to avoid to avoid many branches
 		// checking for errors when writing the domain.
 		// Based on wether or not there was an error, it will update the deployment
 		// accordingly.
 		defer func() {
+			var timeoutErr stepTimeoutError
+
+			if finalErr != nil && errors.As(context.Cause(pipelineCtx), &timeoutErr) {
+				finalErr = domain.NewDeploymentFailure(domain.DeploymentFailureTimeout, domain.ErrDeploymentTimedOut)
+			}
+
 			// Since the deployment process could take some time, retrieve a fresh version of the
 			// deployment right now
 			if depl, err = reader.GetByID(ctx, depl.ID()); err != nil {
@@ -118,12 +187,36 @@ func Handler(
 		}()
 
 		// Prepare the build directory
-		if deploymentCtx, finalErr = artifactManager.PrepareBuild(ctx, depl); finalErr != nil {
+		if deploymentCtx, finalErr = prepareBuild(ctx, artifactManager, depl, domain.DeploymentStep(cmd.ResumeFrom)); finalErr != nil {
 			return
 		}
 
+		// Build tools frequently echo environment variables so known secrets are redacted
+		// from the output before being persisted
+		redactor := domain.NewLogRedactor()
+		redactor.Add(secretValues(depl.Config().Vars().Get(nil))...)
+
+		deploymentCtx = deploymentCtx.
+			WithLogger(newTimeoutsLogger(domain.NewRedactedLogger(
+				newTransitionsLogger(ctx, deploymentCtx.Logger(), depl.ID(), transitionsWriter),
+				redactor,
+			), stageTimeouts, cancelPipeline)).
+			WithHealthCheckTimeout(stageTimeouts.HealthCheck)
+
 		defer deploymentCtx.Logger().Close()
 
+		// Makes it easy to find the request which triggered this deployment in the server logs
+		if correlationID, isSet := bus.CorrelationID(ctx).TryGet(); isSet {
+			deploymentCtx.Logger().Infof("correlation id: %s", correlationID)
+		}
+
+		// A freeze window rejecting deployments is active, fail the deployment
+		if freezeErr != nil {
+			deploymentCtx.Logger().Warnf("production deployments are rejected by the %s freeze window", frozen.MustGet().Name)
+			finalErr = freezeErr
+			return
+		}
+
 		// If the target does not exist, let's fail the deployment correctly
 		if targetErr != nil {
 			finalErr = targetErr
@@ -136,21 +229,201 @@ func Handler(
 			return
 		}
 
-		// Fetch deployment files
-		if finalErr = source.Fetch(ctx, deploymentCtx, depl); finalErr != nil {
+		// The app could not run on the platform of the target, fail the deployment
+		if finalErr = target.CheckPlatform(depl.Config().Platforms()); finalErr != nil {
+			deploymentCtx.Logger().Warnf("target platform %s is not one of %v", target.Platform().Get(""), depl.Config().Platforms())
 			return
 		}
 
+		// A published port is already reserved on the target, fail the deployment
+		if finalErr = target.CheckPortsAvailability(
+			depl.ID().AppID(),
+			depl.Config().Environment(),
+			depl.Config().Exposure().Get(nil).PublishedPorts(),
+		); finalErr != nil {
+			deploymentCtx.Logger().Warnf("a published port is already reserved on the target")
+			return
+		}
+
+		// A dependency could not be deployed or is down, fail the deployment
+		if dependenciesErr != nil {
+			finalErr = dependenciesErr
+			return
+		}
+
+		if step, resumed := deploymentCtx.ResumeFrom().TryGet(); resumed {
+			deploymentCtx.Logger().Infof("resuming the deployment from the %s step, files fetched by the previous run are reused", step)
+		} else {
+			// Fetch deployment files
+			if finalErr = source.Fetch(pipelineCtx, deploymentCtx, depl); finalErr != nil {
+				finalErr = domain.NewDeploymentFailure(domain.DeploymentFailureSourceFetch, finalErr)
+				return
+			}
+
+			if finalErr = hooks.Run(pipelineCtx, deploymentCtx, domain.HookStagePostFetch, depl, nil); finalErr != nil {
+				return
+			}
+		}
+
+		redactor.Add(target.Vars().SecretValues()...)
+
 		// Fetch custom registries
 		if registries, finalErr = registriesReader.GetAll(ctx); finalErr != nil {
 			return
 		}
 
-		// Ask the provider to actually deploy the app
-		if services, finalErr = provider.Deploy(ctx, deploymentCtx, depl, target, registries); finalErr != nil {
+		for _, registry := range registries {
+			if credentials, isSet := registry.Credentials().TryGet(); isSet {
+				redactor.Add(credentials.Password())
+			}
+		}
+
+		// Fetch add-ons which should be made available to the app services
+		if addons, finalErr = addonsReader.GetByAppEnv(ctx, depl.ID().AppID(), depl.Config().Environment()); finalErr != nil {
+			return
+		}
+
+		addons = slices.DeleteFunc(addons, func(addon domain.Addon) bool {
+			return !addon.IsAvailableFor(depl.Config())
+		})
+
+		for _, addon := range addons {
+			redactor.Add(addon.Credentials().Password())
+		}
+
+		if finalErr = hooks.Run(pipelineCtx, deploymentCtx, domain.HookStagePreDeploy, depl, nil); finalErr != nil {
+			return
+		}
+
+		// Secrets referenced by environment variables are only resolved for the provider so
+		// they are never persisted with the deployment
+		resolved, resolvedSecrets, err := depl.WithResolvedSecrets(pipelineCtx, secrets)
+
+		if err != nil {
+			deploymentCtx.Logger().Error(err)
+			finalErr = domain.NewDeploymentFailure(domain.DeploymentFailureSecretResolution, err)
 			return
 		}
 
+		redactor.Add(resolvedSecrets...)
+
+		// Ask the provider to actually deploy the app, it will mark the following steps itself
+		deploymentCtx.Logger().Begin(domain.DeploymentStepBuild)
+
+		if services, finalErr = provider.Deploy(pipelineCtx, deploymentCtx, resolved, target, registries, addons); finalErr != nil {
+			return
+		}
+
+		// The app is already running, a failing hook should not mark the deployment as failed
+		if err := hooks.Run(pipelineCtx, deploymentCtx, domain.HookStagePostDeploy, depl, services); err != nil {
+			deploymentCtx.Logger().Warnf("post-deploy hooks failed, the deployment is kept: %v", err)
+		}
+
 		return
 	}
 }
+
+// Prepare the build directory of the deployment. A retried deployment resumed after the
+// fetch step keeps the files of its previous run, if they are still there.
+func prepareBuild(
+	ctx context.Context,
+	artifactManager domain.ArtifactManager,
+	depl domain.Deployment,
+	resumeFrom domain.DeploymentStep,
+) (domain.DeploymentContext, error) {
+	if resumeFrom == "" || resumeFrom == domain.DeploymentStepFetch {
+		return artifactManager.PrepareBuild(ctx, depl)
+	}
+
+	deploymentCtx, err := artifactManager.ResumeBuild(ctx, depl)
+
+	if errors.Is(err, domain.ErrBuildDirectoryMissing) {
+		return artifactManager.PrepareBuild(ctx, depl)
+	}
+
+	if err != nil {
+		return deploymentCtx, err
+	}
+
+	return deploymentCtx.ResumingFrom(resumeFrom), nil
+}
+
+// Retrieve values of variables which look like they hold a secret for every service.
+func secretValues(env domain.ServicesEnv) []string {
+	var values []string
+
+	for _, vars := range env {
+		values = append(values, vars.SecretValues()...)
+	}
+
+	return values
+}
+
+// Retrieve the freeze window, instance wide or specific to the deployed app, which
+// prevents the deployment from being processed right now if any.
+func activeFreeze(
+	app monad.Maybe[domain.App],
+	depl domain.Deployment,
+	windows domain.FreezeWindows,
+) monad.Maybe[domain.FreezeWindow] {
+	if a, isSet := app.TryGet(); isSet {
+		windows = append(slices.Clone(windows), a.FreezeWindows()...)
+	}
+
+	return depl.ActiveFreeze(windows, time.Now())
+}
+
+// Retrieve timeouts of the pipeline stages, the ones of the deployed app taking precedence
+// over the instance wide ones.
+func pipelineTimeouts(
+	app monad.Maybe[domain.App],
+	timeouts domain.DeploymentTimeouts,
+) domain.DeploymentTimeouts {
+	if a, isSet := app.TryGet(); isSet {
+		return timeouts.Merge(a.Timeouts())
+	}
+
+	return timeouts
+}
+
+// Checks every dependency of the deployed app is ready on the deployment environment.
+func checkDependencies(
+	ctx context.Context,
+	app monad.Maybe[domain.App],
+	deploymentsReader domain.DeploymentsReader,
+	monitorsReader domain.MonitorsReader,
+	depl *domain.Deployment,
+) error {
+	a, isSet := app.TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	env := depl.Config().Environment()
+
+	for _, dependency := range a.Dependencies() {
+		var (
+			latest  monad.Maybe[domain.Deployment]
+			monitor monad.Maybe[domain.Monitor]
+		)
+
+		if d, err := deploymentsReader.GetLastDeployment(ctx, dependency, env); err == nil {
+			latest.Set(d)
+		} else if !errors.Is(err, apperr.ErrNotFound) {
+			return err
+		}
+
+		if m, err := monitorsReader.GetByApp(ctx, dependency, env); err == nil {
+			monitor.Set(m)
+		} else if !errors.Is(err, apperr.ErrNotFound) {
+			return err
+		}
+
+		if err := domain.CheckAppDependencyReady(latest, monitor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
@@ -15,14 +17,40 @@ import (
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
 	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	busmemory "github.com/YuukanOO/seelf/pkg/bus/memory"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
 
+func Test_Command_Payload(t *testing.T) {
+	b := busmemory.NewBus()
+	bus.Register(b, func(context.Context, deploy.Command) (bus.UnitType, error) { return bus.Unit, nil })
+
+	t.Run("should upgrade a payload queued before deployments could be resumed", func(t *testing.T) {
+		msg, err := bus.Unmarshal(deploy.Command{}.Name_(), bus.InitialPayloadVersion, `{"app_id":"app","deployment_number":2}`)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, deploy.Command{AppID: "app", DeploymentNumber: 2}, msg.(deploy.Command))
+	})
+
+	t.Run("should unmarshal a payload at the current version as is", func(t *testing.T) {
+		msg, err := bus.Unmarshal(deploy.Command{}.Name_(), bus.PayloadVersion(deploy.Command{}), `{"app_id":"app","deployment_number":2,"resume_from":"deploy"}`)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, deploy.Command{AppID: "app", DeploymentNumber: 2, ResumeFrom: "deploy"}, msg.(deploy.Command))
+	})
+}
+
 type initialData struct {
+	apps        []*domain.App
 	deployments []*domain.Deployment
 	targets     []*domain.Target
+	transitions *dummyTransitions
+	secrets     domain.SecretResolver
+	freeze      domain.FreezeWindows
+	timeouts    domain.DeploymentTimeouts
 }
 
 func Test_Deploy(t *testing.T) {
@@ -32,23 +60,35 @@ func Test_Deploy(t *testing.T) {
 	sut := func(
 		source domain.Source,
 		provider domain.Provider,
+		hooks domain.Hooks,
 		data initialData,
 	) bus.RequestHandler[bus.UnitType, deploy.Command] {
 		opts := config.Default(config.WithTestDefaults())
 		store := memory.NewDeploymentsStore(data.deployments...)
 		targetsStore := memory.NewTargetsStore(data.targets...)
 		registriesStore := memory.NewRegistriesStore()
-		artifactManager := artifact.NewLocal(opts, logger)
+		addonsStore := memory.NewAddonsStore()
+		appsStore := memory.NewAppsStore(data.apps...)
+		monitorsStore := memory.NewMonitorsStore()
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+
+		if data.transitions == nil {
+			data.transitions = &dummyTransitions{}
+		}
+
+		if data.secrets == nil {
+			data.secrets = &dummySecrets{}
+		}
 
 		t.Cleanup(func() {
 			os.RemoveAll(opts.DataDir())
 		})
 
-		return deploy.Handler(store, store, artifactManager, source, provider, targetsStore, registriesStore)
+		return deploy.Handler(store, store, artifactManager, source, provider, targetsStore, registriesStore, addonsStore, appsStore, monitorsStore, hooks, data.secrets, data.freeze, data.timeouts, data.transitions)
 	}
 
 	t.Run("should fail silently if the deployment does not exists", func(t *testing.T) {
-		uc := sut(source(nil), provider(nil), initialData{})
+		uc := sut(source(nil), provider(nil), hooks(""), initialData{})
 		r, err := uc(ctx, deploy.Command{})
 
 		testutil.IsNil(t, err)
@@ -63,7 +103,7 @@ func Test_Deploy(t *testing.T) {
 		meta := must.Panic(src.Prepare(ctx, app, 42))
 		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
 
-		uc := sut(src, provider(nil), initialData{
+		uc := sut(src, provider(nil), hooks(""), initialData{
 			deployments: []*domain.Deployment{&depl},
 		})
 
@@ -90,7 +130,7 @@ func Test_Deploy(t *testing.T) {
 		src := source(srcErr)
 		meta := must.Panic(src.Prepare(ctx, app, 42))
 		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
-		uc := sut(src, provider(nil), initialData{
+		uc := sut(src, provider(nil), hooks(""), initialData{
 			deployments: []*domain.Deployment{&depl},
 			targets:     []*domain.Target{&target},
 		})
@@ -107,9 +147,82 @@ func Test_Deploy(t *testing.T) {
 		testutil.IsTrue(t, evt.State.StartedAt().HasValue())
 		testutil.IsTrue(t, evt.State.FinishedAt().HasValue())
 		testutil.Equals(t, srcErr.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailureSourceFetch, evt.State.FailureReason().MustGet())
 		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
 	})
 
+	t.Run("should mark the deployment has failed if the app could not run on the target platform", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.RunsOn("windows/amd64")
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		testutil.IsNil(t, app.HasPlatforms(domain.Platforms{"linux/amd64"}))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+		testutil.Equals(t, domain.ErrPlatformNotSupported.Error(), evt.State.ErrCode().MustGet())
+	})
+
+	t.Run("should mark the deployment has failed if a published port is already reserved on the target", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+
+		other := must.Panic(domain.NewApp("other-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		otherConfig := must.Panic(other.ConfigSnapshotFor(domain.Production))
+		db := otherConfig.NewService("db", "postgres:14-alpine")
+		db.AddTCPEntrypoint(5432, 15432)
+		target.ExposeEntrypoints(other.ID(), domain.Production, domain.Services{db})
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		production := domain.NewEnvironmentConfig(target.ID())
+		production.HasServicesExposure(domain.ServicesExposure{
+			"db": {Ports: []domain.ExposedPort{
+				{Router: domain.RouterTcp, Port: 5432, Published: monad.Value[domain.Port](15432)},
+			}},
+		})
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(production, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+		testutil.Equals(t, domain.ErrPortAlreadyReserved.Error(), evt.State.ErrCode().MustGet())
+	})
+
 	t.Run("should mark the deployment has failed if provider does not run the deployment successfully", func(t *testing.T) {
 		target := must.Panic(domain.NewTarget("my-target",
 			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
@@ -124,7 +237,7 @@ func Test_Deploy(t *testing.T) {
 		src := source(nil)
 		meta := must.Panic(src.Prepare(ctx, app, 42))
 		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
-		uc := sut(src, be, initialData{
+		uc := sut(src, be, hooks(""), initialData{
 			deployments: []*domain.Deployment{&depl},
 			targets:     []*domain.Target{&target},
 		})
@@ -155,9 +268,11 @@ func Test_Deploy(t *testing.T) {
 		src := source(nil)
 		meta := must.Panic(src.Prepare(ctx, app, 42))
 		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
-		uc := sut(src, provider(nil), initialData{
+		transitions := &dummyTransitions{}
+		uc := sut(src, provider(nil), hooks(""), initialData{
 			deployments: []*domain.Deployment{&depl},
 			targets:     []*domain.Target{&target},
+			transitions: transitions,
 		})
 
 		r, err := uc(ctx, deploy.Command{
@@ -171,28 +286,440 @@ func Test_Deploy(t *testing.T) {
 		testutil.IsTrue(t, evt.State.StartedAt().HasValue())
 		testutil.IsTrue(t, evt.State.FinishedAt().HasValue())
 		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+		testutil.DeepEquals(t, []domain.DeploymentTransitionState{
+			domain.DeploymentTransitionStep(domain.DeploymentStepBuild),
+		}, transitions.states)
+	})
+
+	t.Run("should give resolved secrets to the provider without persisting them", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		production := domain.NewEnvironmentConfig(target.ID())
+		production.HasEnvironmentVariables(domain.ServicesEnv{"app": {
+			"DEBUG":        "false",
+			"DATABASE_URL": "postgres://db:5432",
+			"API_KEY":      "vault://secret/data/app#api_key",
+		}})
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(production, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		prov := &dummyProvider{}
+		uc := sut(src, prov, hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+			secrets:     &dummySecrets{values: map[string]string{"secret/data/app#api_key": "a sensitive value"}},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.EnvVars{
+			"DEBUG":        "false",
+			"DATABASE_URL": "postgres://db:5432",
+			"API_KEY":      "a sensitive value",
+		}, prov.vars.MustGet()["app"])
+		testutil.Equals(t, "vault://secret/data/app#api_key", depl.Config().Vars().MustGet()["app"]["API_KEY"])
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+	})
+
+	t.Run("should mark the deployment has failed if a secret could not be resolved", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		production := domain.NewEnvironmentConfig(target.ID())
+		production.HasEnvironmentVariables(domain.ServicesEnv{"app": {"API_KEY": "vault://secret/data/app#missing"}})
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(production, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		prov := &dummyProvider{}
+		uc := sut(src, prov, hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, prov.vars.HasValue())
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+		testutil.Equals(t, domain.ErrSecretNotFound.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailureSecretResolution, evt.State.FailureReason().MustGet())
+	})
+
+	t.Run("should resume a retried deployment by reusing the files fetched by its previous run", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := &dummySource{}
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		prov := &dummyProvider{err: errors.New("deploy_failed")}
+		uc := sut(src, prov, hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+		cmd := deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		}
+
+		_, err := uc(ctx, cmd)
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, prov.resumeFrom.HasValue())
+
+		testutil.IsNil(t, app.RetryDeployment(&depl, depl, monad.Value(domain.DeploymentStepDeploy), "some-uid"))
+		prov.err = nil
+		cmd.ResumeFrom = string(domain.DeploymentStepDeploy)
+
+		_, err = uc(ctx, cmd)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, src.fetched)
+		testutil.Equals(t, monad.Value(domain.DeploymentStepDeploy), prov.resumeFrom)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 6)
+		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+	})
+
+	t.Run("should run a retried deployment from scratch if the files of its previous run are missing", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := &dummySource{}
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		prov := &dummyProvider{}
+		uc := sut(src, prov, hooks(""), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+			ResumeFrom:       string(domain.DeploymentStepDeploy),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, src.fetched)
+		testutil.IsFalse(t, prov.resumeFrom.HasValue())
+	})
+
+	t.Run("should mark the deployment has failed if a pre-deploy hook fails", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(domain.HookStagePreDeploy), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, errHookFailed.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+	})
+
+	t.Run("should not mark the deployment has failed if a post-deploy hook fails", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(domain.HookStagePostDeploy), initialData{
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+	})
+
+	t.Run("should wait for dependencies still being deployed", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		dependency := must.Panic(domain.NewApp("my-db",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		testutil.IsNil(t, app.DependsOn(domain.AppDependencyGraph{dependency.ID(): nil}, domain.AppDependencies{dependency.ID()}))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		dependencyDepl := must.Panic(dependency.NewDeployment(1, meta, domain.Production, "some-uid"))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			apps:        []*domain.App{&dependency, &app},
+			deployments: []*domain.Deployment{&dependencyDepl, &depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrAppDependenciesNotReady, err)
+		testutil.HasNEvents(t, &depl, 1)
+	})
+
+	t.Run("should mark the deployment has failed if a dependency could not be deployed", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		dependency := must.Panic(domain.NewApp("my-db",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		testutil.IsNil(t, app.DependsOn(domain.AppDependencyGraph{dependency.ID(): nil}, domain.AppDependencies{dependency.ID()}))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		dependencyDepl := must.Panic(dependency.NewDeployment(1, meta, domain.Production, "some-uid"))
+		testutil.IsNil(t, dependencyDepl.HasStarted())
+		testutil.IsNil(t, dependencyDepl.HasEnded(nil, errors.New("build_failed")))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			apps:        []*domain.App{&dependency, &app},
+			deployments: []*domain.Deployment{&dependencyDepl, &depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.ErrAppDependencyFailed.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+	})
+
+	t.Run("should keep production deployments pending during a queuing freeze window", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		staging := must.Panic(app.NewDeployment(2, meta, domain.Staging, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&depl, &staging},
+			targets:     []*domain.Target{&target},
+			freeze:      domain.FreezeWindows{{Name: "always", Policy: domain.FreezePolicyQueue}},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrDeploymentFrozen, err)
+		testutil.HasNEvents(t, &depl, 1)
+
+		_, err = uc(ctx, deploy.Command{
+			AppID:            string(staging.ID().AppID()),
+			DeploymentNumber: int(staging.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &staging, 2)
+		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+	})
+
+	t.Run("should mark the deployment has failed during a rejecting freeze window of its app", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		testutil.IsNil(t, app.ConfigureFreezeWindows(domain.FreezeWindows{{Name: "release", Policy: domain.FreezePolicyReject}}))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+		testutil.Equals(t, domain.ErrDeploymentFrozen.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailureFrozen, evt.State.FailureReason().MustGet())
+	})
+
+	t.Run("should process unfrozen deployments during freeze windows", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		src := source(nil)
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		testutil.IsNil(t, depl.Unfreeze("some-uid"))
+		uc := sut(src, provider(nil), hooks(""), initialData{
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+			freeze:      domain.FreezeWindows{{Name: "always", Policy: domain.FreezePolicyReject}},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 3)
+		testutil.Equals(t, domain.DeploymentStatusSucceeded, evt.State.Status())
+	})
+
+	t.Run("should mark the deployment has failed if a stage exceeds its timeout", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		testutil.IsNil(t, app.UseTimeouts(domain.DeploymentTimeouts{Build: 10 * time.Millisecond}))
+		src := source(nil)
+		be := &dummyProvider{hang: true}
+		meta := must.Panic(src.Prepare(ctx, app, 42))
+		depl := must.Panic(app.NewDeployment(1, meta, domain.Production, "some-uid"))
+		uc := sut(src, be, hooks(""), initialData{
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&depl},
+			targets:     []*domain.Target{&target},
+			timeouts:    domain.DeploymentTimeouts{Build: time.Hour, HealthCheck: time.Minute},
+		})
+
+		_, err := uc(ctx, deploy.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, time.Minute, be.healthCheckTimeout)
+		evt := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 2)
+		testutil.Equals(t, domain.DeploymentStatusFailed, evt.State.Status())
+		testutil.Equals(t, domain.ErrDeploymentTimedOut.Error(), evt.State.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailureTimeout, evt.State.FailureReason().MustGet())
 	})
 }
 
 type dummySource struct {
-	err error
+	err     error
+	fetched int
 }
 
 func source(failedWithErr error) domain.Source {
-	return &dummySource{failedWithErr}
+	return &dummySource{err: failedWithErr}
 }
 
 func (*dummySource) Prepare(context.Context, domain.App, any) (domain.SourceData, error) {
 	return raw.Data(""), nil
 }
 
-func (t *dummySource) Fetch(context.Context, domain.DeploymentContext, domain.Deployment) error {
-	return t.err
+func (t *dummySource) Fetch(_ context.Context, ctx domain.DeploymentContext, _ domain.Deployment) error {
+	t.fetched++
+
+	if t.err != nil {
+		return t.err
+	}
+
+	return os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services: {}"), 0644)
 }
 
 type dummyProvider struct {
 	domain.Provider
-	err error
+	err                error
+	hang               bool // Wait for the context to be cancelled
+	resumeFrom         monad.Maybe[domain.DeploymentStep]
+	vars               monad.Maybe[domain.ServicesEnv]
+	healthCheckTimeout time.Duration
 }
 
 func provider(failedWithErr error) domain.Provider {
@@ -205,6 +732,61 @@ func (b *dummyProvider) Prepare(context.Context, any, ...domain.ProviderConfig)
 	return nil, nil
 }
 
-func (b *dummyProvider) Deploy(context.Context, domain.DeploymentContext, domain.Deployment, domain.Target, []domain.Registry) (domain.Services, error) {
+func (b *dummyProvider) Deploy(ctx context.Context, deploymentCtx domain.DeploymentContext, depl domain.Deployment, _ domain.Target, _ []domain.Registry, _ []domain.Addon) (domain.Services, error) {
+	b.resumeFrom = deploymentCtx.ResumeFrom()
+	b.vars = depl.Config().Vars()
+	b.healthCheckTimeout = deploymentCtx.HealthCheckTimeout()
+
+	if b.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
 	return domain.Services{}, b.err
 }
+
+var errHookFailed = errors.New("hook_failed")
+
+type dummyHooks struct {
+	failingStage domain.HookStage
+}
+
+func hooks(failingStage domain.HookStage) domain.Hooks {
+	return &dummyHooks{failingStage}
+}
+
+func (h *dummyHooks) Run(_ context.Context, _ domain.DeploymentContext, stage domain.HookStage, _ domain.Deployment, _ domain.Services) error {
+	if stage == h.failingStage {
+		return errHookFailed
+	}
+
+	return nil
+}
+
+type dummySecrets struct {
+	values map[string]string
+}
+
+func (*dummySecrets) CanResolve(store string) bool { return store == "vault" }
+
+func (s *dummySecrets) Resolve(_ context.Context, ref domain.SecretReference) (string, error) {
+	value, exists := s.values[ref.Path()]
+
+	if !exists {
+		return "", domain.ErrSecretNotFound
+	}
+
+	return value, nil
+}
+
+type dummyTransitions struct {
+	states []domain.DeploymentTransitionState
+}
+
+func (d *dummyTransitions) Write(_ context.Context, transitions ...*domain.DeploymentTransition) error {
+	for _, transition := range transitions {
+		d.states = append(d.states, transition.State())
+	}
+
+	return nil
+}
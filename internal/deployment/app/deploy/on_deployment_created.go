@@ -17,3 +17,14 @@ func OnDeploymentCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domai
 		}, bus.WithGroup(app.DeploymentGroup(evt.Config)), bus.WithPolicy(bus.JobPolicyRetryPreserveOrder))
 	}
 }
+
+// Upon receiving a deployment retried event, queue a job to resume the deployment.
+func OnDeploymentRetriedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DeploymentRetried] {
+	return func(ctx context.Context, evt domain.DeploymentRetried) error {
+		return scheduler.Queue(ctx, Command{
+			AppID:            string(evt.ID.AppID()),
+			DeploymentNumber: int(evt.ID.DeploymentNumber()),
+			ResumeFrom:       string(evt.ResumeFrom),
+		}, bus.WithGroup(app.DeploymentGroup(evt.Config)), bus.WithPolicy(bus.JobPolicyRetryPreserveOrder))
+	}
+}
@@ -0,0 +1,100 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+type (
+	// Deployment logger which stops the deployment pipeline, by cancelling its context,
+	// when a step exceeds the timeout of its stage. The timer is reset each time a new step
+	// begins and since the logger of a running deployment starts at the fetch step, the
+	// fetch timeout applies right away.
+	timeoutsLogger struct {
+		domain.DeploymentLogger
+
+		timeouts domain.DeploymentTimeouts
+		cancel   context.CancelCauseFunc
+		mu       sync.Mutex
+		timer    *time.Timer
+		expired  error
+	}
+
+	// Cause of the cancellation of a pipeline which step has exceeded its timeout.
+	stepTimeoutError struct {
+		step    domain.DeploymentStep
+		timeout time.Duration
+	}
+)
+
+func newTimeoutsLogger(
+	logger domain.DeploymentLogger,
+	timeouts domain.DeploymentTimeouts,
+	cancel context.CancelCauseFunc,
+) domain.DeploymentLogger {
+	l := &timeoutsLogger{
+		DeploymentLogger: logger,
+		timeouts:         timeouts,
+		cancel:           cancel,
+	}
+
+	l.watch(domain.DeploymentStepFetch)
+
+	return l
+}
+
+func (l *timeoutsLogger) Begin(step domain.DeploymentStep) {
+	l.DeploymentLogger.Begin(step)
+	l.watch(step)
+}
+
+func (l *timeoutsLogger) Close() error {
+	l.watch("")
+
+	// Logged here since the timer fires outside of the pipeline goroutine
+	l.mu.Lock()
+	expired := l.expired
+	l.mu.Unlock()
+
+	if expired != nil {
+		l.DeploymentLogger.Error(expired)
+	}
+
+	return l.DeploymentLogger.Close()
+}
+
+// Stops the timer of the previous step and starts the one of the given step if its
+// stage has a timeout.
+func (l *timeoutsLogger) watch(step domain.DeploymentStep) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+
+	timeout := l.timeouts.Step(step)
+
+	if timeout <= 0 {
+		return
+	}
+
+	l.timer = time.AfterFunc(timeout, func() {
+		err := stepTimeoutError{step, timeout}
+
+		l.mu.Lock()
+		l.expired = err
+		l.mu.Unlock()
+
+		l.cancel(err)
+	})
+}
+
+func (e stepTimeoutError) Error() string {
+	return fmt.Sprintf("the %s step has been stopped after exceeding its timeout of %s", e.step, e.timeout)
+}
@@ -0,0 +1,38 @@
+package deploy
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+// Deployment logger which records a transition each time the deployment enters a new
+// pipeline step so it appears in the deployment timeline while it is running.
+type transitionsLogger struct {
+	domain.DeploymentLogger
+
+	ctx        context.Context
+	deployment domain.DeploymentID
+	writer     domain.DeploymentTransitionsWriter
+}
+
+func newTransitionsLogger(
+	ctx context.Context,
+	logger domain.DeploymentLogger,
+	deployment domain.DeploymentID,
+	writer domain.DeploymentTransitionsWriter,
+) domain.DeploymentLogger {
+	return &transitionsLogger{logger, ctx, deployment, writer}
+}
+
+func (l *transitionsLogger) Begin(step domain.DeploymentStep) {
+	l.DeploymentLogger.Begin(step)
+
+	transition := domain.NewDeploymentTransition(l.deployment, domain.DeploymentTransitionStep(step), time.Now())
+
+	// The timeline is informative only, it should never fail the deployment
+	if err := l.writer.Write(l.ctx, &transition); err != nil {
+		l.DeploymentLogger.Warnf("could not record the %s step in the deployment timeline: %v", step, err)
+	}
+}
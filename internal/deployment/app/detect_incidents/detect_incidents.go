@@ -0,0 +1,76 @@
+package detect_incidents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve incidents which happened to applications containers on every ready target
+// during the given period and record them against the deployment whose services were
+// running at that time. Like the resource usage collection, an unreachable target does
+// not prevent incidents of other ones to be recorded. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
+
+func (Command) Name_() string { return "deployment.command.detect_incidents" }
+
+func Handler(
+	targetsReader domain.TargetsReader,
+	deploymentsReader domain.DeploymentsReader,
+	provider domain.Provider,
+	writer domain.IncidentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := targetsReader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			incidents  []*domain.Incident
+			targetErrs []error
+		)
+
+		for _, target := range targets {
+			containerIncidents, err := provider.Incidents(ctx, target, cmd.Since, cmd.Until)
+
+			if err != nil {
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+				continue
+			}
+
+			for _, containerIncident := range containerIncidents {
+				depl, err := deploymentsReader.GetDeploymentRunningAt(ctx, containerIncident.AppID, containerIncident.Environment, containerIncident.OccurredAt)
+
+				if err != nil {
+					// The app may have been deleted in the meantime
+					if errors.Is(err, apperr.ErrNotFound) {
+						continue
+					}
+
+					return bus.Unit, err
+				}
+
+				incident := domain.NewIncident(depl, containerIncident)
+				incidents = append(incidents, &incident)
+			}
+		}
+
+		if err = writer.Write(ctx, incidents...); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
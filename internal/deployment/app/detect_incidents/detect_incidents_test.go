@@ -0,0 +1,130 @@
+package detect_incidents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/detect_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DetectIncidents(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(provider domain.Provider, deployments []*domain.Deployment, targets ...*domain.Target) (bus.RequestHandler[bus.UnitType, detect_incidents.Command], *incidentsWriter) {
+		writer := &incidentsWriter{}
+		return detect_incidents.Handler(memory.NewTargetsStore(targets...), memory.NewDeploymentsStore(deployments...), provider, writer), writer
+	}
+
+	t.Run("should record incidents against the deployment running when they occurred", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", true)
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		succeeded := createDeployment(app, 1, true)
+		failed := createDeployment(app, 2, false)
+		now := time.Now().UTC()
+		incident := domain.ContainerIncident{
+			AppID:       app.ID(),
+			Environment: domain.Production,
+			Service:     "app",
+			Container:   "my-app-production-app-1",
+			Kind:        domain.IncidentCrashed,
+			ExitCode:    monad.Value(1),
+			OccurredAt:  now.Add(time.Second),
+		}
+		provider := &dummyProvider{incidents: map[domain.TargetID][]domain.ContainerIncident{
+			target.ID(): {
+				incident,
+				{AppID: "deleted-app", Environment: domain.Production, Service: "app", Kind: domain.IncidentOutOfMemory, OccurredAt: now},
+			},
+		}}
+		uc, writer := sut(provider, []*domain.Deployment{&succeeded, &failed}, &target)
+
+		_, err := uc(ctx, detect_incidents.Command{
+			Since: now.Add(-time.Minute),
+			Until: now.Add(time.Minute),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, writer.incidents, 1)
+		testutil.Equals(t, succeeded.ID(), writer.incidents[0].DeploymentID())
+		testutil.Equals(t, "app", writer.incidents[0].Service())
+		testutil.Equals(t, "my-app-production-app-1", writer.incidents[0].Container())
+		testutil.Equals(t, domain.IncidentCrashed, writer.incidents[0].Kind())
+		testutil.Equals(t, monad.Value(1), writer.incidents[0].ExitCode())
+		testutil.Equals(t, incident.OccurredAt, writer.incidents[0].OccurredAt())
+		testutil.Equals(t, now.Add(-time.Minute), provider.since)
+		testutil.Equals(t, now.Add(time.Minute), provider.until)
+	})
+
+	t.Run("should record incidents of reachable targets and return errors of unreachable ones", func(t *testing.T) {
+		reachable := fixture.Target("http://reachable.localhost", true)
+		unreachable := fixture.Target("http://unreachable.localhost", true)
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(reachable.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(reachable.ID()), true, true), "some-uid"))
+		depl := createDeployment(app, 1, true)
+		targetErr := errors.New("could not connect")
+		provider := &dummyProvider{
+			incidents: map[domain.TargetID][]domain.ContainerIncident{
+				reachable.ID(): {{AppID: app.ID(), Environment: domain.Production, Service: "app", Kind: domain.IncidentRestarted, OccurredAt: time.Now().UTC()}},
+			},
+			errs: map[domain.TargetID]error{
+				unreachable.ID(): targetErr,
+			},
+		}
+		uc, writer := sut(provider, []*domain.Deployment{&depl}, &reachable, &unreachable)
+
+		_, err := uc(ctx, detect_incidents.Command{})
+
+		testutil.ErrorIs(t, targetErr, err)
+		testutil.HasLength(t, writer.incidents, 1)
+		testutil.Equals(t, depl.ID(), writer.incidents[0].DeploymentID())
+	})
+}
+
+func createDeployment(app domain.App, number domain.DeploymentNumber, succeeded bool) domain.Deployment {
+	depl := must.Panic(app.NewDeployment(number, raw.Data(""), domain.Production, "some-uid"))
+	depl.HasStarted()
+
+	if succeeded {
+		depl.HasEnded(domain.Services{}, nil)
+	} else {
+		depl.HasEnded(domain.Services{}, errors.New("some error"))
+	}
+
+	return depl
+}
+
+type dummyProvider struct {
+	domain.Provider
+	incidents map[domain.TargetID][]domain.ContainerIncident
+	errs      map[domain.TargetID]error
+	since     time.Time
+	until     time.Time
+}
+
+func (p *dummyProvider) Incidents(_ context.Context, target domain.Target, since, until time.Time) ([]domain.ContainerIncident, error) {
+	p.since = since
+	p.until = until
+	return p.incidents[target.ID()], p.errs[target.ID()]
+}
+
+type incidentsWriter struct {
+	incidents []*domain.Incident
+}
+
+func (w *incidentsWriter) Write(_ context.Context, incidents ...*domain.Incident) error {
+	w.incidents = append(w.incidents, incidents...)
+	return nil
+}
@@ -0,0 +1,78 @@
+package exec_service
+
+import (
+	"context"
+	"io"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Run a one-off command, such as a database migration or a console, inside a running
+// container of an application service and return its exit code. The command being audited,
+// the audit entry is only recorded once it has exited.
+type Command struct {
+	bus.Command[int]
+
+	AppID       string    `json:"-"`
+	Environment string    `json:"environment"`
+	Service     string    `json:"service"`
+	Cmd         []string  `json:"cmd"`
+	Tty         bool      `json:"tty"`
+	Stdin       io.Reader `json:"-"`
+	Stdout      io.Writer `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.exec_service" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[int, Command] {
+	return func(ctx context.Context, cmd Command) (int, error) {
+		var env domain.Environment
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"service":     validate.Field(cmd.Service, strings.Required),
+			"cmd":         validate.If(len(cmd.Cmd) == 0, func() error { return strings.ErrRequired }),
+		}); err != nil {
+			return 0, err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return 0, err
+		}
+
+		config, err := app.ConfigSnapshotFor(env)
+
+		if err != nil {
+			return 0, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, config.Target())
+
+		if err != nil {
+			return 0, err
+		}
+
+		return provider.Exec(ctx, config, target, domain.ServiceExec{
+			Service: cmd.Service,
+			Command: cmd.Cmd,
+			Tty:     cmd.Tty,
+			Stdin:   cmd.Stdin,
+			Stdout:  cmd.Stdout,
+		})
+	}
+}
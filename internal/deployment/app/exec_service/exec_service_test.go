@@ -0,0 +1,139 @@
+package exec_service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/exec_service"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type initialData struct {
+	apps    []*domain.App
+	targets []*domain.Target
+}
+
+func Test_ExecService(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(provider domain.Provider, data initialData) bus.RequestHandler[int, exec_service.Command] {
+		return exec_service.Handler(memory.NewAppsStore(data.apps...), memory.NewTargetsStore(data.targets...), provider)
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, exec_service.Command{
+			Environment: "invalid",
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		uc := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, exec_service.Command{
+			AppID:       "some-id",
+			Environment: string(domain.Production),
+			Service:     "app",
+			Cmd:         []string{"sh"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to deploy the application", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "another-uid"))
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		provider := &dummyProvider{}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		_, err := uc(auth.WithUser(context.Background(), user), exec_service.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Service:     "app",
+			Cmd:         []string{"sh"},
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.HasLength(t, provider.exec.Command, 0)
+	})
+
+	t.Run("should run the command in the service container and return its exit code", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		provider := &dummyProvider{exitCode: 3}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+		var stdout bytes.Buffer
+
+		code, err := uc(ctx, exec_service.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Staging),
+			Service:     "db",
+			Cmd:         []string{"cat"},
+			Tty:         true,
+			Stdin:       strings.NewReader("some input"),
+			Stdout:      &stdout,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 3, code)
+		testutil.Equals(t, "some input", stdout.String())
+		testutil.Equals(t, target.ID(), provider.target)
+		testutil.Equals(t, domain.Staging, provider.environment)
+		testutil.Equals(t, "db", provider.exec.Service)
+		testutil.DeepEquals(t, []string{"cat"}, provider.exec.Command)
+		testutil.IsTrue(t, provider.exec.Tty)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	exitCode    int
+	target      domain.TargetID
+	environment domain.Environment
+	exec        domain.ServiceExec
+}
+
+func (p *dummyProvider) Exec(
+	_ context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	exec domain.ServiceExec,
+) (int, error) {
+	p.target = target.ID()
+	p.environment = config.Environment()
+	p.exec = exec
+
+	if _, err := io.Copy(exec.Stdout, exec.Stdin); err != nil {
+		return 0, err
+	}
+
+	return p.exitCode, nil
+}
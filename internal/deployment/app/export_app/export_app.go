@@ -0,0 +1,228 @@
+package export_app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Export an application to its archive: configuration, environment variables,
+// deployment history and add-ons data. Once it has succeeded, the application
+// cleanup is requested. If it fails, the application is kept untouched.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID    string `json:"id"`
+	AppID string `json:"app_id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.export_app" }
+func (c Command) ResourceID() string { return c.AppID }
+
+type (
+	// Content of the app.json file of an archive.
+	AppExport struct {
+		ID         string            `json:"id"`
+		Name       string            `json:"name"`
+		Labels     domain.Labels     `json:"labels"`
+		Production EnvironmentExport `json:"production"`
+		Staging    EnvironmentExport `json:"staging"`
+	}
+
+	EnvironmentExport struct {
+		Target string                          `json:"target"`
+		Vars   monad.Maybe[domain.ServicesEnv] `json:"vars"`
+	}
+
+	// Entry of the deployments.json file of an archive.
+	DeploymentExport struct {
+		DeploymentNumber int                    `json:"deployment_number"`
+		Environment      string                 `json:"environment"`
+		Target           string                 `json:"target"`
+		Source           string                 `json:"source"`
+		Status           uint8                  `json:"status"`
+		ErrCode          monad.Maybe[string]    `json:"error_code"`
+		Labels           domain.Labels          `json:"labels"`
+		RequestedAt      time.Time              `json:"requested_at"`
+		RequestedBy      string                 `json:"requested_by"`
+		StartedAt        monad.Maybe[time.Time] `json:"started_at"`
+		FinishedAt       monad.Maybe[time.Time] `json:"finished_at"`
+	}
+)
+
+func Handler(
+	reader domain.AppArchivesReader,
+	writer domain.AppArchivesWriter,
+	appsReader domain.AppsReader,
+	appsWriter domain.AppsWriter,
+	deploymentsReader domain.DeploymentsReader,
+	addonsReader domain.AddonsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+	storage domain.AppArchivesStorage,
+	gracePeriod time.Duration,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		archive, err := reader.GetByID(ctx, domain.AppArchiveID(cmd.ID))
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		if archive.Status() != domain.AppArchiveStatusRunning {
+			return bus.Unit, nil
+		}
+
+		app, err := appsReader.GetByID(ctx, archive.AppID())
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		deployments, err := deploymentsReader.GetByApp(ctx, app.ID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		addons, err := addonsReader.GetByApp(ctx, app.ID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Resolve targets of add-ons to backup beforehand so the job could be retried
+		// if one of them is being configured.
+		targets := make(map[domain.TargetID]domain.Target)
+
+		for _, addon := range addons {
+			if addon.CanBeBackedUp() != nil {
+				continue
+			}
+
+			if _, found := targets[addon.Target()]; found {
+				continue
+			}
+
+			target, err := targetsReader.GetByID(ctx, addon.Target())
+
+			if err != nil {
+				if errors.Is(err, apperr.ErrNotFound) {
+					continue
+				}
+
+				return bus.Unit, err
+			}
+
+			if err = target.CheckAvailability(); err != nil {
+				if errors.Is(err, domain.ErrTargetConfigurationInProgress) {
+					return bus.Unit, err
+				}
+
+				continue
+			}
+
+			targets[target.ID()] = target
+		}
+
+		size, exportErr := storage.Save(ctx, archive, func(w domain.AppArchiveWriter) error {
+			if err := addJSON(w, "app.json", exportApp(archive, app)); err != nil {
+				return err
+			}
+
+			if err := addJSON(w, "deployments.json", exportDeployments(deployments)); err != nil {
+				return err
+			}
+
+			for _, addon := range addons {
+				target, found := targets[addon.Target()]
+
+				if !found || addon.CanBeBackedUp() != nil {
+					continue
+				}
+
+				if err := w.Add("addons/"+string(addon.Environment())+"/"+addon.Variable()+".sql", func(out io.Writer) error {
+					return provider.BackupAddon(ctx, target, addon, out)
+				}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		archive.Completed(size, exportErr, gracePeriod)
+
+		if err = writer.Write(ctx, &archive); err != nil || exportErr != nil {
+			return bus.Unit, err
+		}
+
+		app.RequestCleanup(archive.Requested().By())
+
+		return bus.Unit, appsWriter.Write(ctx, &app)
+	}
+}
+
+func addJSON(w domain.AppArchiveWriter, name string, data any) error {
+	return w.Add(name, func(out io.Writer) error {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	})
+}
+
+func exportApp(archive domain.AppArchive, app domain.App) AppExport {
+	return AppExport{
+		ID:     string(app.ID()),
+		Name:   string(archive.AppName()),
+		Labels: app.Labels(),
+		Production: EnvironmentExport{
+			Target: string(app.Production().Target()),
+			Vars:   app.Production().Vars(),
+		},
+		Staging: EnvironmentExport{
+			Target: string(app.Staging().Target()),
+			Vars:   app.Staging().Vars(),
+		},
+	}
+}
+
+func exportDeployments(deployments []domain.Deployment) []DeploymentExport {
+	result := make([]DeploymentExport, len(deployments))
+
+	for i, deployment := range deployments {
+		state := deployment.State()
+		requested := deployment.Requested()
+
+		result[i] = DeploymentExport{
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+			Environment:      string(deployment.Config().Environment()),
+			Target:           string(deployment.Config().Target()),
+			Source:           deployment.Source().Kind(),
+			Status:           uint8(state.Status()),
+			ErrCode:          state.ErrCode(),
+			Labels:           deployment.Labels(),
+			RequestedAt:      requested.At(),
+			RequestedBy:      string(requested.By()),
+			StartedAt:        state.StartedAt(),
+			FinishedAt:       state.FinishedAt(),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,202 @@
+package export_app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	archives    []*domain.AppArchive
+	apps        []*domain.App
+	deployments []*domain.Deployment
+	addons      []*domain.Addon
+	targets     []*domain.Target
+}
+
+func Test_ExportApp(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData, saveErr error) (export_app.Command, func(export_app.Command) error, memory.AppArchivesStore, memory.AppsStore, *dummyStorage) {
+		store := memory.NewAppArchivesStore(data.archives...)
+		appsStore := memory.NewAppsStore(data.apps...)
+		storage := &dummyStorage{err: saveErr}
+		handler := export_app.Handler(store, store, appsStore, appsStore, memory.NewDeploymentsStore(data.deployments...),
+			memory.NewAddonsStore(data.addons...), memory.NewTargetsStore(data.targets...), &dummyProvider{}, storage, time.Hour)
+
+		var cmd export_app.Command
+
+		if len(data.archives) > 0 {
+			cmd = export_app.Command{ID: string(data.archives[0].ID()), AppID: string(data.archives[0].AppID())}
+		}
+
+		return cmd, func(c export_app.Command) error {
+			_, err := handler(ctx, c)
+			return err
+		}, store, appsStore, storage
+	}
+
+	newTarget := func() domain.Target {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		return target
+	}
+
+	newApp := func(target domain.TargetID) domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "uid"))
+	}
+
+	t.Run("should fail silently if the archive does not exist anymore", func(t *testing.T) {
+		_, uc, _, _, storage := sut(initialData{}, nil)
+
+		err := uc(export_app.Command{ID: "some-id"})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, storage.files, 0)
+	})
+
+	t.Run("should retry later if the target of an add-on is configuring", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+		app := newApp(target.ID())
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		archive := must.Panic(domain.NewAppArchive(app, "uid"))
+		cmd, uc, _, _, storage := sut(initialData{
+			archives: []*domain.AppArchive{&archive},
+			apps:     []*domain.App{&app},
+			addons:   []*domain.Addon{&addon},
+			targets:  []*domain.Target{&target},
+		}, nil)
+
+		err := uc(cmd)
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, err)
+		testutil.HasLength(t, storage.files, 0)
+	})
+
+	t.Run("should keep the application if the export has failed", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target.ID())
+		archive := must.Panic(domain.NewAppArchive(app, "uid"))
+		saveErr := errors.New("disk full")
+		cmd, uc, store, appsStore, _ := sut(initialData{
+			archives: []*domain.AppArchive{&archive},
+			apps:     []*domain.App{&app},
+			targets:  []*domain.Target{&target},
+		}, saveErr)
+
+		err := uc(cmd)
+
+		testutil.IsNil(t, err)
+		archive = must.Panic(store.GetByID(ctx, archive.ID()))
+		testutil.Equals(t, domain.AppArchiveStatusFailed, archive.Status())
+		app = must.Panic(appsStore.GetByID(ctx, app.ID()))
+		testutil.HasNEvents(t, &app, 1)
+	})
+
+	t.Run("should export the application and request its cleanup", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target.ID())
+		deployment := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "uid"))
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		archive := must.Panic(domain.NewAppArchive(app, "another-uid"))
+		cmd, uc, store, appsStore, storage := sut(initialData{
+			archives:    []*domain.AppArchive{&archive},
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&deployment},
+			addons:      []*domain.Addon{&addon},
+			targets:     []*domain.Target{&target},
+		}, nil)
+
+		err := uc(cmd)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"app.json", "deployments.json", "addons/production/" + addon.Variable() + ".sql"}, storage.files)
+		testutil.Contains(t, `"deployment_number": 1`, storage.content["deployments.json"])
+		testutil.Equals(t, dump, storage.content["addons/production/"+addon.Variable()+".sql"])
+
+		archive = must.Panic(store.GetByID(ctx, archive.ID()))
+		testutil.Equals(t, domain.AppArchiveStatusSucceeded, archive.Status())
+		testutil.IsTrue(t, archive.ExpiresAt().HasValue())
+
+		app = must.Panic(appsStore.GetByID(ctx, app.ID()))
+		requested := testutil.EventIs[domain.AppCleanupRequested](t, &app, 1)
+		testutil.Equals(t, "another-uid", requested.Requested.By())
+	})
+}
+
+const dump = "CREATE TABLE some_table;"
+
+type (
+	dummyProvider struct {
+		domain.Provider
+	}
+
+	dummyStorage struct {
+		domain.AppArchivesStorage
+		err     error
+		files   []string
+		content map[string]string
+	}
+
+	dummyWriter struct {
+		storage *dummyStorage
+	}
+
+	stringWriter struct {
+		content []byte
+	}
+)
+
+func (d *dummyProvider) BackupAddon(_ context.Context, _ domain.Target, _ domain.Addon, w io.Writer) error {
+	_, err := io.WriteString(w, dump)
+	return err
+}
+
+func (d *dummyStorage) Save(_ context.Context, _ domain.AppArchive, fill func(domain.AppArchiveWriter) error) (int64, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	d.content = make(map[string]string)
+
+	if err := fill(&dummyWriter{d}); err != nil {
+		return 0, err
+	}
+
+	return int64(len(d.files)), nil
+}
+
+func (w *dummyWriter) Add(name string, write func(io.Writer) error) error {
+	var out stringWriter
+
+	if err := write(&out); err != nil {
+		return err
+	}
+
+	w.storage.files = append(w.storage.files, name)
+	w.storage.content[name] = string(out.content)
+	return nil
+}
+
+func (w *stringWriter) Write(p []byte) (int, error) {
+	w.content = append(w.content, p...)
+	return len(p), nil
+}
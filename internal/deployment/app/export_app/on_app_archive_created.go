@@ -0,0 +1,17 @@
+package export_app
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAppArchiveCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AppArchiveCreated] {
+	return func(ctx context.Context, evt domain.AppArchiveCreated) error {
+		return scheduler.Queue(ctx, Command{
+			ID:    string(evt.ID),
+			AppID: string(evt.AppID),
+		})
+	}
+}
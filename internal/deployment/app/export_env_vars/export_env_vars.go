@@ -0,0 +1,69 @@
+package export_env_vars
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+	"github.com/joho/godotenv"
+)
+
+// Export environment variables of an app service in the dotenv format. Values of
+// variables which look like secrets are masked.
+type Query struct {
+	bus.Query[string]
+
+	AppID       string `json:"-"`
+	Environment string `form:"environment"`
+	Service     string `form:"service"`
+}
+
+func (Query) Name_() string { return "deployment.query.export_env_vars" }
+
+func Handler(
+	reader domain.AppsReader,
+) bus.RequestHandler[string, Query] {
+	return func(ctx context.Context, cmd Query) (string, error) {
+		var env domain.Environment
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"service":     validate.Field(cmd.Service, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		config := app.Production()
+
+		if env == domain.Staging {
+			config = app.Staging()
+		}
+
+		vars := config.Vars().Get(nil)[cmd.Service].Masked()
+
+		if len(vars) == 0 {
+			return "", nil
+		}
+
+		content, err := godotenv.Marshal(vars)
+
+		if err != nil {
+			return "", err
+		}
+
+		return content + "\n", nil
+	}
+}
@@ -87,10 +87,20 @@ func Handler(
 			return bus.Unit, err
 		}
 
+		var platform domain.Platform
+
 		assigned, err := provider.Setup(ctx, target)
 
+		if err == nil {
+			platform, err = provider.Platform(ctx, target)
+		}
+
 		target.Configured(target.CurrentVersion(), assigned, err)
 
+		if err == nil {
+			target.RunsOn(platform)
+		}
+
 		if err := writer.Write(ctx, &target); err != nil {
 			return bus.Unit, err
 		}
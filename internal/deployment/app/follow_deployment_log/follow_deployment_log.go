@@ -0,0 +1,49 @@
+package follow_deployment_log
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Follow a deployment log while it is being written. The stream ends when the
+// deployment is done or the given context is done.
+type Query struct {
+	bus.Query[domain.DeploymentLogStream]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.follow_deployment_log" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[domain.DeploymentLogStream, Query] {
+	return func(ctx context.Context, cmd Query) (domain.DeploymentLogStream, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return domain.DeploymentLogStream{}, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return domain.DeploymentLogStream{}, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return domain.DeploymentLogStream{}, err
+		}
+
+		return artifactManager.Follow(ctx, depl), nil
+	}
+}
@@ -0,0 +1,50 @@
+package generate_badge_token
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Generate a new badge token for an application, needed to embed its deployment
+// badges. Previously generated tokens are revoked.
+type Command struct {
+	bus.Command[string]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.generate_badge_token" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+	secret []byte,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		token, err := app.GenerateBadgeToken(secret)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &app); err != nil {
+			return "", err
+		}
+
+		return token, nil
+	}
+}
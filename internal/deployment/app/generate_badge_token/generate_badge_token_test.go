@@ -0,0 +1,61 @@
+package generate_badge_token_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/generate_badge_token"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_GenerateBadgeToken(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	secret := []byte("some-secret")
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+
+	sut := func(existingApps ...*domain.App) bus.RequestHandler[string, generate_badge_token.Command] {
+		store := memory.NewAppsStore(existingApps...)
+		return generate_badge_token.Handler(store, store, secret)
+	}
+
+	t.Run("should fail if the app does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, generate_badge_token.Command{ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the app", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), generate_badge_token.Command{ID: string(app.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should generate a token granting access to the app badges", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		token, err := uc(ctx, generate_badge_token.Command{ID: string(app.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, app.CheckBadgeToken(secret, token))
+		testutil.EventIs[domain.AppBadgeKeyChanged](t, &app, 1)
+	})
+}
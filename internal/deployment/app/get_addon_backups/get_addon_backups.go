@@ -0,0 +1,37 @@
+package get_addon_backups
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve backups of an add-on, most recent first.
+	Query struct {
+		bus.Query[[]Backup]
+
+		AppID   string `json:"-"`
+		AddonID string `json:"-"`
+	}
+
+	Backup struct {
+		ID        string               `json:"id"`
+		Status    uint8                `json:"status"`
+		Size      int64                `json:"size"`
+		ErrCode   monad.Maybe[string]  `json:"error_code"`
+		Restore   monad.Maybe[Restore] `json:"restore"`
+		CreatedAt time.Time            `json:"created_at"`
+	}
+
+	Restore struct {
+		RequestedAt time.Time              `json:"requested_at"`
+		RequestedBy app.UserSummary        `json:"requested_by"`
+		FinishedAt  monad.Maybe[time.Time] `json:"finished_at"`
+		ErrCode     monad.Maybe[string]    `json:"error_code"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_addon_backups" }
@@ -0,0 +1,40 @@
+package get_app_addons
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve add-ons of an application along with their connection string.
+	Query struct {
+		bus.Query[[]Addon]
+
+		AppID string `json:"-"`
+	}
+
+	Addon struct {
+		ID                 string                 `json:"id"`
+		Environment        string                 `json:"environment"`
+		Kind               string                 `json:"kind"`
+		Variable           string                 `json:"variable"`
+		Host               string                 `json:"host"`
+		ConnectionString   string                 `json:"connection_string"`
+		Target             app.TargetSummary      `json:"target"`
+		State              State                  `json:"state"`
+		CleanupRequestedAt monad.Maybe[time.Time] `json:"cleanup_requested_at"`
+		CreatedAt          time.Time              `json:"created_at"`
+		CreatedBy          app.UserSummary        `json:"created_by"`
+	}
+
+	State struct {
+		Status  uint8               `json:"status"`
+		Version time.Time           `json:"version"`
+		ErrCode monad.Maybe[string] `json:"error_code"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_addons" }
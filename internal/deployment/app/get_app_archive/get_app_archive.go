@@ -0,0 +1,43 @@
+package get_app_archive
+
+import (
+	"context"
+	"io"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve the gzipped tarball of an application archive. The caller MUST close it.
+type Query struct {
+	bus.Query[io.ReadCloser]
+
+	ID string `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_app_archive" }
+
+func Handler(
+	reader domain.AppArchivesReader,
+	storage domain.AppArchivesStorage,
+) bus.RequestHandler[io.ReadCloser, Query] {
+	return func(ctx context.Context, cmd Query) (io.ReadCloser, error) {
+		archive, err := reader.GetByID(ctx, domain.AppArchiveID(cmd.ID))
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Archives contain secrets so only people allowed to delete the app can retrieve them
+		if err = auth.Authorize(ctx, auth.PermissionManage, archive.Requested().By(), archive.Resources()...); err != nil {
+			return nil, err
+		}
+
+		if err = archive.CanBeDownloaded(); err != nil {
+			return nil, err
+		}
+
+		return storage.Open(ctx, archive)
+	}
+}
@@ -0,0 +1,31 @@
+package get_app_archives
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve archives of deleted applications, most recent first. Restricted users
+	// only see the ones they have requested.
+	Query struct {
+		bus.Query[[]Archive]
+	}
+
+	Archive struct {
+		ID          string                 `json:"id"`
+		AppID       string                 `json:"app_id"`
+		AppName     string                 `json:"app_name"`
+		Status      uint8                  `json:"status"`
+		Size        int64                  `json:"size"`
+		ErrCode     monad.Maybe[string]    `json:"error_code"`
+		ExpiresAt   monad.Maybe[time.Time] `json:"expires_at"`
+		RequestedAt time.Time              `json:"requested_at"`
+		RequestedBy app.UserSummary        `json:"requested_by"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_archives" }
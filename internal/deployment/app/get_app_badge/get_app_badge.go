@@ -0,0 +1,78 @@
+package get_app_badge
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Name of the deployment metadata key used as the badge version when set.
+const VersionMetadataKey = "release"
+
+type (
+	// Retrieve the latest deployment status of an app environment to render a badge.
+	// No user is authenticated, access is granted by the app badge token instead.
+	Query struct {
+		bus.Query[Badge]
+
+		AppID       string `json:"-"`
+		Environment string `json:"-"`
+		Token       string `form:"token"`
+	}
+
+	Badge struct {
+		Environment domain.Environment
+		Status      monad.Maybe[domain.DeploymentStatus] // Unset if the environment has never been deployed
+		Version     string
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_badge" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	deploymentsReader domain.DeploymentsReader,
+	secret []byte,
+) bus.RequestHandler[Badge, Query] {
+	return func(ctx context.Context, query Query) (b Badge, err error) {
+		if b.Environment, err = domain.EnvironmentFrom(query.Environment); err != nil {
+			return b, apperr.ErrNotFound
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(query.AppID))
+
+		if err != nil {
+			return b, err
+		}
+
+		// Invalid tokens are reported as missing apps so they could not be used to probe them
+		if app.CheckBadgeToken(secret, query.Token) != nil {
+			return b, apperr.ErrNotFound
+		}
+
+		depl, err := deploymentsReader.GetLastDeployment(ctx, app.ID(), b.Environment)
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return b, nil
+			}
+
+			return b, err
+		}
+
+		b.Status.Set(depl.State().Status())
+
+		if version, found := depl.Metadata()[VersionMetadataKey]; found {
+			b.Version = version
+		} else {
+			b.Version = "#" + strconv.Itoa(int(depl.ID().DeploymentNumber()))
+		}
+
+		return b, nil
+	}
+}
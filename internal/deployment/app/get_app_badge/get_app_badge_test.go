@@ -0,0 +1,96 @@
+package get_app_badge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_badge"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_GetAppBadge(t *testing.T) {
+	secret := []byte("some-secret")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	token := must.Panic(app.GenerateBadgeToken(secret))
+
+	sut := func(existingDeployments ...*domain.Deployment) bus.RequestHandler[get_app_badge.Badge, get_app_badge.Query] {
+		return get_app_badge.Handler(memory.NewAppsStore(&app), memory.NewDeploymentsStore(existingDeployments...), secret)
+	}
+
+	t.Run("should not be found if the token is invalid", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), get_app_badge.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Token:       "invalid",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not be found if the environment is invalid", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), get_app_badge.Query{
+			AppID:       string(app.ID()),
+			Environment: "preview",
+			Token:       token,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should return an empty status if the environment has never been deployed", func(t *testing.T) {
+		uc := sut()
+
+		badge, err := uc(context.Background(), get_app_badge.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Token:       token,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.Production, badge.Environment)
+		testutil.IsFalse(t, badge.Status.HasValue())
+	})
+
+	t.Run("should return the latest deployment status and version", func(t *testing.T) {
+		first := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+		second := must.Panic(app.NewDeployment(2, raw.Data(""), domain.Production, "some-uid"))
+		uc := sut(&first, &second)
+
+		badge, err := uc(context.Background(), get_app_badge.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Token:       token,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.DeploymentStatusPending, badge.Status.MustGet())
+		testutil.Equals(t, "#2", badge.Version)
+	})
+
+	t.Run("should use the release metadata as the version if set", func(t *testing.T) {
+		depl := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Staging, "some-uid"))
+		depl.Annotate("", domain.DeploymentMetadata{get_app_badge.VersionMetadataKey: "1.2.0"})
+		uc := sut(&depl)
+
+		badge, err := uc(context.Background(), get_app_badge.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Staging),
+			Token:       token,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "1.2.0", badge.Version)
+	})
+}
@@ -18,6 +18,8 @@ type (
 		AppID       string              `json:"-"`
 		Page        monad.Maybe[int]    `form:"page"`
 		Environment monad.Maybe[string] `form:"environment"`
+		Labels      []string            `form:"labels"`
+		Search      monad.Maybe[string] `form:"search"` // Matches the note and metadata of deployments
 	}
 
 	Deployment struct {
@@ -29,13 +31,17 @@ type (
 		State            State                        `json:"state"`
 		RequestedAt      time.Time                    `json:"requested_at"`
 		RequestedBy      app.UserSummary              `json:"requested_by"`
+		Labels           app.Labels                   `json:"labels"`
+		Note             string                       `json:"note"`
+		Metadata         app.Metadata                 `json:"metadata"`
 	}
 
 	State struct {
-		Status     uint8                  `json:"status"`
-		ErrCode    monad.Maybe[string]    `json:"error_code"`
-		StartedAt  monad.Maybe[time.Time] `json:"started_at"`
-		FinishedAt monad.Maybe[time.Time] `json:"finished_at"`
+		Status        uint8                  `json:"status"`
+		ErrCode       monad.Maybe[string]    `json:"error_code"`
+		FailureReason monad.Maybe[string]    `json:"failure_reason"`
+		StartedAt     monad.Maybe[time.Time] `json:"started_at"`
+		FinishedAt    monad.Maybe[time.Time] `json:"finished_at"`
 	}
 )
 
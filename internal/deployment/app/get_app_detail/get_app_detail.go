@@ -1,10 +1,13 @@
 package get_app_detail
 
 import (
+	"context"
 	"time"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
@@ -29,6 +32,54 @@ type (
 		Production         EnvironmentConfig                                `json:"production"`
 		Staging            EnvironmentConfig                                `json:"staging"`
 		VersionControl     monad.Maybe[VersionControl]                      `json:"version_control"`
+		Team               monad.Maybe[app.TeamSummary]                     `json:"team"`
+		Dependencies       Dependencies                                     `json:"dependencies"` // IDs of apps deployed before this one
+		Labels             app.Labels                                       `json:"labels"`
+		Platforms          Platforms                                        `json:"platforms"` // Platforms the app can run on, any if empty
+		BuildRegistry      monad.Maybe[BuildRegistry]                       `json:"build_registry"`
+		StaticSite         monad.Maybe[StaticSite]                          `json:"static_site"`
+		BadgeEnabled       bool                                             `json:"badge_enabled"`  // A badge token has been generated for this app
+		DeployTrigger      monad.Maybe[string]                              `json:"deploy_trigger"` // Kind of key used to verify signed deployment triggers, if any
+		FreezeWindows      FreezeWindows                                    `json:"freeze_windows"` // Periods during which production deployments are not processed
+		Timeouts           Timeouts                                         `json:"timeouts"`       // Stages timeouts taking precedence over the instance wide ones
+	}
+
+	// Set when the app is served by the shared static web server of its targets.
+	StaticSite struct {
+		Service   string `json:"service"`
+		Directory string `json:"directory"`
+	}
+
+	// Registry to which images built by the app are pushed.
+	BuildRegistry struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	}
+
+	Dependencies []string
+
+	Platforms []string
+
+	FreezeWindows []FreezeWindow
+
+	FreezeWindow struct {
+		Name     string                 `json:"name"`
+		Policy   string                 `json:"policy"`
+		Days     []string               `json:"days"`
+		From     string                 `json:"from"`
+		To       string                 `json:"to"`
+		Timezone string                 `json:"timezone"`
+		Start    monad.Maybe[time.Time] `json:"start"`
+		End      monad.Maybe[time.Time] `json:"end"`
+	}
+
+	// Timeouts of the deployment pipeline stages in seconds, 0 when not set.
+	Timeouts struct {
+		Fetch       int64 `json:"fetch"`
+		Build       int64 `json:"build"`
+		Deploy      int64 `json:"deploy"`
+		HealthCheck int64 `json:"health_check"`
 	}
 
 	VersionControl struct {
@@ -37,15 +88,188 @@ type (
 	}
 
 	EnvironmentConfig struct {
-		Target app.TargetSummary        `json:"target"`
-		Vars   monad.Maybe[ServicesEnv] `json:"vars"`
+		Target          app.TargetSummary             `json:"target"`
+		Vars            monad.Maybe[ServicesEnv]      `json:"vars"`
+		Exposure        monad.Maybe[ServicesExposure] `json:"exposure"`
+		Protection      monad.Maybe[AccessProtection] `json:"protection"`
+		ProxyRules      monad.Maybe[ProxyRules]       `json:"proxy_rules"`
+		ComposeOverride monad.Maybe[string]           `json:"compose_override"`
+		UrlAliases      monad.Maybe[UrlAliases]       `json:"url_aliases"`
+		Scale           monad.Maybe[ServicesScale]    `json:"scale"`
 	}
 
+	UrlAliases []string
+
+	ServicesScale map[string]uint
+
 	ServicesEnv map[string]map[string]string
+
+	ServicesExposure map[string]ServiceExposure
+
+	ServiceExposure struct {
+		Disabled   bool                `json:"disabled"`
+		Port       monad.Maybe[uint]   `json:"port"`
+		Subdomain  monad.Maybe[string] `json:"subdomain"`
+		PathPrefix monad.Maybe[string] `json:"path_prefix"`
+		Ports      []ExposedPort       `json:"ports"`
+	}
+
+	ExposedPort struct {
+		Router    string            `json:"router"`
+		Port      uint              `json:"port"`
+		Published monad.Maybe[uint] `json:"published"`
+	}
+
+	// Access protection of an environment, password hashes are never exposed.
+	AccessProtection struct {
+		BasicAuth   monad.Maybe[BasicAuth] `json:"basic_auth"`
+		IPAllowList []string               `json:"ip_allowlist"`
+		ForwardAuth monad.Maybe[string]    `json:"forward_auth"`
+	}
+
+	BasicAuth struct {
+		Username string `json:"username"`
+	}
+
+	ProxyRules struct {
+		WWWRedirect   bool              `json:"www_redirect"`
+		InsecurePaths []string          `json:"insecure_paths"`
+		Redirects     []Redirect        `json:"redirects"`
+		Headers       map[string]string `json:"headers"`
+	}
+
+	Redirect struct {
+		Regex       string `json:"regex"`
+		Replacement string `json:"replacement"`
+		Permanent   bool   `json:"permanent"`
+	}
 )
 
 func (Query) Name_() string { return "deployment.query.get_app_detail" }
 
+// Masks values of secret environment variables, the same way exports do, when the
+// context could not manage the app. Read only grantees and API tokens should not be
+// able to retrieve them.
+func (a *App) MaskSecrets(ctx context.Context) {
+	resources := []auth.Resource{
+		auth.NewResource(auth.ResourceApp, a.ID),
+		auth.NewResource(auth.ResourceTarget, a.Production.Target.ID),
+		auth.NewResource(auth.ResourceTarget, a.Staging.Target.ID),
+	}
+
+	if team, isSet := a.Team.TryGet(); isSet {
+		resources = append(resources, auth.NewResource(auth.ResourceTeam, team.ID))
+	}
+
+	if auth.PermissionOn(ctx, auth.UserID(a.CreatedBy.ID), resources...) >= auth.PermissionManage {
+		return
+	}
+
+	for _, config := range []*EnvironmentConfig{&a.Production, &a.Staging} {
+		if vars, isSet := config.Vars.TryGet(); isSet {
+			config.Vars.Set(vars.Masked())
+		}
+	}
+}
+
+func (d *Dependencies) Scan(value any) error {
+	if err := storage.ScanJSON(value, d); err != nil {
+		return err
+	}
+
+	// Always return an array to ease the client work
+	if *d == nil {
+		*d = Dependencies{}
+	}
+
+	return nil
+}
+
+func (p *Platforms) Scan(value any) error {
+	if err := storage.ScanJSON(value, p); err != nil {
+		return err
+	}
+
+	if *p == nil {
+		*p = Platforms{}
+	}
+
+	return nil
+}
+
+func (w *FreezeWindows) Scan(value any) error {
+	if err := storage.ScanJSON(value, w); err != nil {
+		return err
+	}
+
+	if *w == nil {
+		*w = FreezeWindows{}
+	}
+
+	for i := range *w {
+		if (*w)[i].Days == nil {
+			(*w)[i].Days = []string{}
+		}
+	}
+
+	return nil
+}
+
+func (t *Timeouts) Scan(value any) error {
+	var timeouts struct {
+		Fetch       time.Duration `json:"fetch"`
+		Build       time.Duration `json:"build"`
+		Deploy      time.Duration `json:"deploy"`
+		HealthCheck time.Duration `json:"health_check"`
+	}
+
+	if err := storage.ScanJSON(value, &timeouts); err != nil {
+		return err
+	}
+
+	t.Fetch = int64(timeouts.Fetch.Seconds())
+	t.Build = int64(timeouts.Build.Seconds())
+	t.Deploy = int64(timeouts.Deploy.Seconds())
+	t.HealthCheck = int64(timeouts.HealthCheck.Seconds())
+
+	return nil
+}
+
 func (e *ServicesEnv) Scan(value any) error {
 	return storage.ScanJSON(value, e)
 }
+
+// Returns a copy of the variables where values of secret ones are masked.
+func (e ServicesEnv) Masked() ServicesEnv {
+	result := make(ServicesEnv, len(e))
+
+	for service, vars := range e {
+		result[service] = domain.EnvVars(vars).Masked()
+	}
+
+	return result
+}
+
+func (e *ServicesExposure) Scan(value any) error {
+	return storage.ScanJSON(value, e)
+}
+
+func (p *AccessProtection) Scan(value any) error {
+	return storage.ScanJSON(value, p)
+}
+
+func (s *StaticSite) Scan(value any) error {
+	return storage.ScanJSON(value, s)
+}
+
+func (r *ProxyRules) Scan(value any) error {
+	return storage.ScanJSON(value, r)
+}
+
+func (a *UrlAliases) Scan(value any) error {
+	return storage.ScanJSON(value, a)
+}
+
+func (s *ServicesScale) Scan(value any) error {
+	return storage.ScanJSON(value, s)
+}
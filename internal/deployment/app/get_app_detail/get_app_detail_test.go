@@ -0,0 +1,52 @@
+package get_app_detail_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_App_MaskSecrets(t *testing.T) {
+	admin := must.Panic(auth.NewUser(auth.NewEmailRequirement("admin@doe.com", true), "password", "adminkey"))
+	admin.HasAdminRights(true)
+
+	createApp := func() get_app_detail.App {
+		var a get_app_detail.App
+
+		a.ID = "app"
+		a.CreatedBy.ID = string(admin.ID())
+		a.Production.Target.ID = "target"
+		a.Staging.Target.ID = "target"
+		a.Production.Vars.Set(get_app_detail.ServicesEnv{
+			"app": {"PORT": "8080", "DB_PASSWORD": "secret"},
+		})
+
+		return a
+	}
+
+	t.Run("should keep values of secret variables when the context could manage the app", func(t *testing.T) {
+		a := createApp()
+
+		a.MaskSecrets(auth.WithUser(context.Background(), admin))
+
+		testutil.DeepEquals(t, get_app_detail.ServicesEnv{
+			"app": {"PORT": "8080", "DB_PASSWORD": "secret"},
+		}, a.Production.Vars.MustGet())
+	})
+
+	t.Run("should mask values of secret variables when the context could only read the app", func(t *testing.T) {
+		a := createApp()
+
+		a.MaskSecrets(auth.WithScopes(auth.WithUser(context.Background(), admin), auth.Scopes{"read:app"}))
+
+		testutil.DeepEquals(t, get_app_detail.ServicesEnv{
+			"app": {"PORT": "8080", "DB_PASSWORD": domain.MaskedEnvVarValue},
+		}, a.Production.Vars.MustGet())
+		testutil.IsFalse(t, a.Staging.Vars.HasValue())
+	})
+}
@@ -0,0 +1,49 @@
+package get_app_env_revisions
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve the history of an app environment variables, most recent first.
+	Query struct {
+		bus.Query[storage.Paginated[Revision]]
+
+		AppID       string              `json:"-"`
+		Page        monad.Maybe[int]    `form:"page"`
+		Environment monad.Maybe[string] `form:"environment"`
+	}
+
+	Revision struct {
+		ID          string                       `json:"id"`
+		Environment string                       `json:"environment"`
+		Vars        monad.Maybe[ServicesEnv]     `json:"vars"`
+		Changes     Changes                      `json:"changes"`
+		CreatedAt   time.Time                    `json:"created_at"`
+		CreatedBy   monad.Maybe[app.UserSummary] `json:"created_by"`
+	}
+
+	Change struct {
+		Service string `json:"service"`
+		Name    string `json:"name"`
+		Kind    string `json:"kind"`
+	}
+
+	Changes     []Change
+	ServicesEnv map[string]map[string]string
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_env_revisions" }
+
+func (e *ServicesEnv) Scan(value any) error {
+	return storage.ScanJSON(value, e)
+}
+
+func (c *Changes) Scan(value any) error {
+	return storage.ScanJSON(value, c)
+}
@@ -0,0 +1,33 @@
+package get_app_incidents
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve incidents which happened to an app containers, most recent first.
+	Query struct {
+		bus.Query[storage.Paginated[Incident]]
+
+		AppID       string              `json:"-"`
+		Page        monad.Maybe[int]    `form:"page"`
+		Environment monad.Maybe[string] `form:"environment"`
+	}
+
+	Incident struct {
+		ID               string           `json:"id"`
+		DeploymentNumber int              `json:"deployment_number"`
+		Environment      string           `json:"environment"`
+		Service          string           `json:"service"`
+		Container        string           `json:"container"`
+		Kind             string           `json:"kind"`
+		ExitCode         monad.Maybe[int] `json:"exit_code"`
+		OccurredAt       time.Time        `json:"occurred_at"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_incidents" }
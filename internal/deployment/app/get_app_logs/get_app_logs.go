@@ -0,0 +1,100 @@
+package get_app_logs
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/numbers"
+)
+
+// Number of lines which could be read from the target before being consumed.
+const linesBufferSize = 256
+
+// Retrieve the runtime logs of an application services running on the target of the
+// given environment. Those are not the deployment logs but the ones written by containers.
+// The stream ends when every line has been sent or, when following logs, the given
+// context is done.
+type Query struct {
+	bus.Query[domain.ServiceLogsStream]
+
+	AppID       string                 `json:"-"`
+	Environment string                 `json:"environment"`
+	Services    []string               `json:"services"`
+	Tail        monad.Maybe[int]       `json:"tail"`
+	Since       monad.Maybe[time.Time] `json:"since"`
+	Follow      bool                   `json:"follow"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_app_logs" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[domain.ServiceLogsStream, Query] {
+	return func(ctx context.Context, cmd Query) (domain.ServiceLogsStream, error) {
+		var env domain.Environment
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"tail":        validate.Maybe(cmd.Tail, numbers.Min(0)),
+		}); err != nil {
+			return domain.ServiceLogsStream{}, err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return domain.ServiceLogsStream{}, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return domain.ServiceLogsStream{}, err
+		}
+
+		config, err := app.ConfigSnapshotFor(env)
+
+		if err != nil {
+			return domain.ServiceLogsStream{}, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, config.Target())
+
+		if err != nil {
+			return domain.ServiceLogsStream{}, err
+		}
+
+		var (
+			lines = make(chan domain.ServiceLog, linesBufferSize)
+			errs  = make(chan error, 1)
+		)
+
+		go func() {
+			defer close(errs)
+
+			errs <- provider.Logs(ctx, config, target, domain.ServiceLogsOptions{
+				Services: cmd.Services,
+				Tail:     cmd.Tail,
+				Since:    cmd.Since,
+				Follow:   cmd.Follow,
+			}, func(line domain.ServiceLog) {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+				}
+			})
+
+			close(lines)
+		}()
+
+		return domain.ServiceLogsStream{
+			Lines: lines,
+			Err:   errs,
+		}, nil
+	}
+}
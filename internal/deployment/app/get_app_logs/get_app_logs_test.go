@@ -0,0 +1,178 @@
+package get_app_logs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_logs"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type initialData struct {
+	apps    []*domain.App
+	targets []*domain.Target
+}
+
+func Test_GetAppLogs(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(provider domain.Provider, data initialData) bus.RequestHandler[domain.ServiceLogsStream, get_app_logs.Query] {
+		return get_app_logs.Handler(memory.NewAppsStore(data.apps...), memory.NewTargetsStore(data.targets...), provider)
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, get_app_logs.Query{
+			Environment: "invalid",
+			Tail:        monad.Value(-1),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		uc := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, get_app_logs.Query{
+			AppID:       "some-id",
+			Environment: string(domain.Production),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not reveal the application to users not allowed to read it", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "another-uid"))
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&dummyProvider{}, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		_, err := uc(auth.WithUser(context.Background(), user), get_app_logs.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should stream the logs of the application services", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		provider := &dummyProvider{
+			lines: []domain.ServiceLog{
+				{Container: "app-1", Message: "starting"},
+				{Container: "db-1", Message: "ready"},
+			},
+		}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		stream, err := uc(ctx, get_app_logs.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Staging),
+			Services:    []string{"app"},
+			Tail:        monad.Value(10),
+			Follow:      true,
+		})
+
+		testutil.IsNil(t, err)
+
+		var lines []domain.ServiceLog
+
+		for line := range stream.Lines {
+			lines = append(lines, line)
+		}
+
+		testutil.DeepEquals(t, provider.lines, lines)
+		testutil.IsNil(t, <-stream.Err)
+		testutil.Equals(t, target.ID(), provider.target)
+		testutil.Equals(t, domain.Staging, provider.environment)
+		testutil.DeepEquals(t, domain.ServiceLogsOptions{
+			Services: []string{"app"},
+			Tail:     monad.Value(10),
+			Follow:   true,
+		}, provider.options)
+	})
+
+	t.Run("should forward the provider error once every line has been sent", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "some-uid"))
+		providerErr := errors.New("connection lost")
+		uc := sut(&dummyProvider{
+			lines: []domain.ServiceLog{{Container: "app-1", Message: "starting"}},
+			err:   providerErr,
+		}, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		stream, err := uc(ctx, get_app_logs.Query{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+		})
+
+		testutil.IsNil(t, err)
+
+		var count int
+
+		for range stream.Lines {
+			count++
+		}
+
+		testutil.Equals(t, 1, count)
+		testutil.ErrorIs(t, providerErr, <-stream.Err)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	lines       []domain.ServiceLog
+	err         error
+	target      domain.TargetID
+	environment domain.Environment
+	options     domain.ServiceLogsOptions
+}
+
+func (p *dummyProvider) Logs(
+	_ context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	options domain.ServiceLogsOptions,
+	handler func(domain.ServiceLog),
+) error {
+	p.target = target.ID()
+	p.environment = config.Environment()
+	p.options = options
+
+	for _, line := range p.lines {
+		handler(line)
+	}
+
+	return p.err
+}
@@ -0,0 +1,38 @@
+package get_app_monitors
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve monitors of an application with their most recent checks, most recent first.
+	Query struct {
+		bus.Query[[]Monitor]
+
+		AppID string `json:"-"`
+	}
+
+	Monitor struct {
+		ID          string              `json:"id"`
+		Environment string              `json:"environment"`
+		Interval    int                 `json:"interval"` // In seconds
+		StatusCode  int                 `json:"status_code"`
+		Keyword     monad.Maybe[string] `json:"keyword"`
+		Status      uint8               `json:"status"`
+		NextCheckAt time.Time           `json:"next_check_at"`
+		Checks      []Check             `json:"checks"`
+	}
+
+	Check struct {
+		CheckedAt    time.Time           `json:"checked_at"`
+		Status       uint8               `json:"status"`
+		StatusCode   monad.Maybe[int]    `json:"status_code"`
+		ResponseTime int64               `json:"response_time"` // In milliseconds
+		Reason       monad.Maybe[string] `json:"reason"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_monitors" }
@@ -0,0 +1,34 @@
+package get_app_resource_usage
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve resources consumed by an application services, oldest first, as collected
+	// periodically from its targets.
+	Query struct {
+		bus.Query[[]Sample]
+
+		AppID       string                 `json:"-"`
+		Environment monad.Maybe[string]    `json:"environment"`
+		Service     monad.Maybe[string]    `json:"service"`
+		Since       monad.Maybe[time.Time] `json:"since"`
+	}
+
+	Sample struct {
+		Environment string    `json:"environment"`
+		Service     string    `json:"service"`
+		CollectedAt time.Time `json:"collected_at"`
+		CPU         float64   `json:"cpu"`          // Percentage of a single CPU
+		Memory      int64     `json:"memory"`       // In bytes
+		MemoryLimit int64     `json:"memory_limit"` // In bytes
+		NetworkRx   int64     `json:"network_rx"`   // Bytes received since the containers have started
+		NetworkTx   int64     `json:"network_tx"`   // Bytes sent since the containers have started
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_resource_usage" }
@@ -0,0 +1,47 @@
+package get_app_traffic
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve requests served to an application services by the proxy of their targets,
+	// as collected periodically from its access logs.
+	Query struct {
+		bus.Query[Traffic]
+
+		AppID       string                 `json:"-"`
+		Environment monad.Maybe[string]    `json:"environment"`
+		Service     monad.Maybe[string]    `json:"service"`
+		Since       monad.Maybe[time.Time] `json:"since"`
+	}
+
+	Traffic struct {
+		Requests   int64               `json:"requests"`
+		Statuses   domain.StatusCounts `json:"statuses"`
+		P95Latency int64               `json:"p95_latency"` // In milliseconds, estimated from latency buckets
+		Services   []ServiceTraffic    `json:"services"`
+		Samples    []Sample            `json:"samples"` // Oldest first
+	}
+
+	ServiceTraffic struct {
+		Environment string              `json:"environment"`
+		Service     string              `json:"service"`
+		Requests    int64               `json:"requests"`
+		Statuses    domain.StatusCounts `json:"statuses"`
+		P95Latency  int64               `json:"p95_latency"` // In milliseconds, estimated from latency buckets
+	}
+
+	// Requests served to every matching service during a collection period.
+	Sample struct {
+		CollectedAt time.Time           `json:"collected_at"`
+		Requests    int64               `json:"requests"`
+		Statuses    domain.StatusCounts `json:"statuses"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_app_traffic" }
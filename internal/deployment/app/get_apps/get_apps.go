@@ -10,9 +10,13 @@ import (
 )
 
 type (
-	// Retrieve all apps.
+	// Retrieve all apps, optionally limited to the ones belonging to a team and
+	// having every given label.
 	Query struct {
 		bus.Query[[]App]
+
+		TeamID monad.Maybe[string]
+		Labels []string
 	}
 
 	App struct {
@@ -25,6 +29,8 @@ type (
 		LatestDeployments  app.LatestDeployments[get_app_deployments.Deployment] `json:"latest_deployments"`
 		ProductionTarget   app.TargetSummary                                     `json:"production_target"`
 		StagingTarget      app.TargetSummary                                     `json:"staging_target"`
+		Team               monad.Maybe[app.TeamSummary]                          `json:"team"`
+		Labels             app.Labels                                            `json:"labels"`
 	}
 )
 
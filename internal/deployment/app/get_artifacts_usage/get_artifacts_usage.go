@@ -0,0 +1,29 @@
+package get_artifacts_usage
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve the disk space used by artifacts as computed by the last collection.
+	Query struct {
+		bus.Query[Usage]
+	}
+
+	Usage struct {
+		Total      int64                  `json:"total"`       // In bytes
+		ComputedAt monad.Maybe[time.Time] `json:"computed_at"` // Empty until the first collection has been made
+		Apps       []App                  `json:"apps"`        // Biggest first
+	}
+
+	App struct {
+		ID   string              `json:"id"`
+		Name monad.Maybe[string] `json:"name"` // Empty if the app has been deleted since the last collection
+		Size int64               `json:"size"` // In bytes
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_artifacts_usage" }
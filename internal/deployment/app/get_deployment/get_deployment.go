@@ -24,14 +24,40 @@ type (
 	}
 
 	Deployment struct {
-		AppID            string          `json:"app_id"`
-		DeploymentNumber int             `json:"deployment_number"`
-		Environment      string          `json:"environment"`
-		Target           TargetSummary   `json:"target"`
-		Source           Source          `json:"source"`
-		State            State           `json:"state"`
-		RequestedAt      time.Time       `json:"requested_at"`
-		RequestedBy      app.UserSummary `json:"requested_by"`
+		AppID            string             `json:"app_id"`
+		DeploymentNumber int                `json:"deployment_number"`
+		Environment      string             `json:"environment"`
+		Target           TargetSummary      `json:"target"`
+		Source           Source             `json:"source"`
+		State            State              `json:"state"`
+		RequestedAt      time.Time          `json:"requested_at"`
+		RequestedBy      app.UserSummary    `json:"requested_by"`
+		Labels           app.Labels         `json:"labels"`
+		Note             string             `json:"note"`
+		Metadata         app.Metadata       `json:"metadata"`
+		Timeline         []Transition       `json:"timeline"`
+		Queue            monad.Maybe[Queue] `json:"queue"` // Only set for pending deployments
+	}
+
+	// Position of a pending deployment in the queue and when it is expected to start.
+	Queue struct {
+		Position          int                    `json:"position"` // 1 for the next deployment to be started
+		EstimatedStartAt  monad.Maybe[time.Time] `json:"estimated_start_at"`
+		EstimatedDuration monad.Maybe[int64]     `json:"estimated_duration"` // In milliseconds
+	}
+
+	// Running or pending deployment used to estimate when a queued one will start.
+	QueuedDeployment struct {
+		Group     string                 // Deployments of the same group are processed one after the other
+		StartedAt monad.Maybe[time.Time] // Only set for running deployments
+		Duration  monad.Maybe[int64]     // Expected duration in milliseconds, based on previous deployments
+	}
+
+	// State reached by the deployment, either queued, a pipeline step, succeeded or failed.
+	Transition struct {
+		State      string             `json:"state"`
+		OccurredAt time.Time          `json:"occurred_at"`
+		Duration   monad.Maybe[int64] `json:"duration"` // In milliseconds, until the next transition
 	}
 
 	// This summary is specific in the sense that it represents a target which may
@@ -54,11 +80,12 @@ type (
 	}
 
 	State struct {
-		Status     uint8                  `json:"status"`
-		Services   monad.Maybe[Services]  `json:"services"`
-		ErrCode    monad.Maybe[string]    `json:"error_code"`
-		StartedAt  monad.Maybe[time.Time] `json:"started_at"`
-		FinishedAt monad.Maybe[time.Time] `json:"finished_at"`
+		Status        uint8                  `json:"status"`
+		Services      monad.Maybe[Services]  `json:"services"`
+		ErrCode       monad.Maybe[string]    `json:"error_code"`
+		FailureReason monad.Maybe[string]    `json:"failure_reason"`
+		StartedAt     monad.Maybe[time.Time] `json:"started_at"`
+		FinishedAt    monad.Maybe[time.Time] `json:"finished_at"`
 	}
 
 	Services []Service
@@ -69,6 +96,7 @@ type (
 		Name          string              `json:"name"`
 		Router        string              `json:"router"`
 		Subdomain     monad.Maybe[string] `json:"subdomain"`
+		PathPrefix    monad.Maybe[string] `json:"path_prefix"`
 		IsCustom      bool                `json:"is_custom"`
 		Port          uint                `json:"port"`
 		Url           monad.Maybe[string] `json:"url"`
@@ -137,8 +165,10 @@ func (d *Deployment) ResolveServicesUrls() {
 				host = subdomain + "." + targetHost
 			}
 
+			path := entrypoint.PathPrefix.Get("")
+
 			if !entrypoint.IsCustom {
-				entrypoint.Url.Set(targetScheme + host)
+				entrypoint.Url.Set(targetScheme + host + path)
 				services[i].Entrypoints[j] = entrypoint
 				continue
 			}
@@ -150,9 +180,82 @@ func (d *Deployment) ResolveServicesUrls() {
 			}
 
 			entrypoint.PublishedPort.Set(publishedPort)
-			entrypoint.Url.Set(entrypoint.Router + "://" + host + ":" + strconv.FormatUint(uint64(publishedPort), 10))
+			entrypoint.Url.Set(entrypoint.Router + "://" + host + ":" + strconv.FormatUint(uint64(publishedPort), 10) + path)
 
 			services[i].Entrypoints[j] = entrypoint
 		}
 	}
 }
+
+// Estimates the queue position and start time of a pending deployment given the ones
+// ahead of it, running ones first and then pending ones in the order they have been
+// requested, and the number of deployments processed concurrently.
+//
+// The start time could not be estimated if the duration of a deployment ahead is unknown.
+func EstimateQueue(now time.Time, runners int, ahead []QueuedDeployment, deployment QueuedDeployment) Queue {
+	queue := Queue{
+		Position:          1,
+		EstimatedDuration: deployment.Duration,
+	}
+
+	var (
+		estimated = true
+		available = make([]time.Time, max(runners, 1)) // When each runner will be available
+		groups    = make(map[string]time.Time)         // When the last deployment of each group will end
+	)
+
+	for i := range available {
+		available[i] = now
+	}
+
+	nextRunner := func() int {
+		next := 0
+
+		for i, at := range available {
+			if at.Before(available[next]) {
+				next = i
+			}
+		}
+
+		return next
+	}
+
+	for _, d := range ahead {
+		startedAt, isRunning := d.StartedAt.TryGet()
+
+		if !isRunning {
+			queue.Position++
+		}
+
+		duration, isKnown := d.Duration.TryGet()
+
+		if !isKnown {
+			estimated = false
+			continue
+		}
+
+		runner := nextRunner()
+
+		if !isRunning {
+			startedAt = latest(available[runner], groups[d.Group])
+		}
+
+		endAt := latest(now, startedAt.Add(time.Duration(duration)*time.Millisecond))
+		available[runner] = endAt
+		groups[d.Group] = endAt
+	}
+
+	if estimated {
+		queue.EstimatedStartAt.Set(latest(available[nextRunner()], groups[deployment.Group]))
+	}
+
+	return queue
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
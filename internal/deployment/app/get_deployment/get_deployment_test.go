@@ -2,6 +2,7 @@ package get_deployment_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
 	"github.com/YuukanOO/seelf/pkg/monad"
@@ -279,4 +280,90 @@ func Test_Deployment(t *testing.T) {
 			},
 		}, d.State.Services.Get(get_deployment.Services{}))
 	})
+
+	t.Run("should append the path prefix of entrypoints to their url", func(t *testing.T) {
+		d := get_deployment.Deployment{
+			AppID:       "app-id",
+			Environment: "production",
+			Target: get_deployment.TargetSummary{
+				ID:          "target-id",
+				Url:         monad.Value("https://docker.localhost"),
+				Entrypoints: monad.Value(get_deployment.Entrypoints{}),
+			},
+			State: get_deployment.State{
+				Services: monad.Value(get_deployment.Services{
+					{
+						Name:  "api",
+						Image: "api-image",
+						Entrypoints: []get_deployment.Entrypoint{
+							{
+								Name:       "http1",
+								Router:     "http",
+								Subdomain:  monad.Value("app"),
+								PathPrefix: monad.Value("/api"),
+								Port:       80,
+							},
+						},
+					},
+				}),
+			},
+		}
+
+		d.ResolveServicesUrls()
+
+		testutil.Equals(t, "https://app.docker.localhost/api", d.State.Services.MustGet()[0].Entrypoints[0].Url.Get(""))
+	})
+}
+
+func Test_EstimateQueue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	minutes := func(n int) int64 { return (time.Duration(n) * time.Minute).Milliseconds() }
+
+	t.Run("should start right away if nothing is ahead", func(t *testing.T) {
+		queue := get_deployment.EstimateQueue(now, 2, nil, get_deployment.QueuedDeployment{
+			Group:    "app/production",
+			Duration: monad.Value(minutes(3)),
+		})
+
+		testutil.DeepEquals(t, get_deployment.Queue{
+			Position:          1,
+			EstimatedStartAt:  monad.Value(now),
+			EstimatedDuration: monad.Value(minutes(3)),
+		}, queue)
+	})
+
+	t.Run("should wait for a runner to be available", func(t *testing.T) {
+		queue := get_deployment.EstimateQueue(now, 2, []get_deployment.QueuedDeployment{
+			{Group: "api/production", StartedAt: monad.Value(now.Add(-2 * time.Minute)), Duration: monad.Value(minutes(5))},
+			{Group: "web/production", StartedAt: monad.Value(now.Add(-time.Minute)), Duration: monad.Value(minutes(2))},
+			{Group: "docs/staging", Duration: monad.Value(minutes(4))},
+		}, get_deployment.QueuedDeployment{Group: "app/production"})
+
+		testutil.DeepEquals(t, get_deployment.Queue{
+			Position:         2,
+			EstimatedStartAt: monad.Value(now.Add(3 * time.Minute)),
+		}, queue)
+	})
+
+	t.Run("should wait for deployments of the same group to end", func(t *testing.T) {
+		queue := get_deployment.EstimateQueue(now, 4, []get_deployment.QueuedDeployment{
+			{Group: "app/production", StartedAt: monad.Value(now), Duration: monad.Value(minutes(2))},
+			{Group: "app/production", Duration: monad.Value(minutes(2))},
+		}, get_deployment.QueuedDeployment{Group: "app/production"})
+
+		testutil.DeepEquals(t, get_deployment.Queue{
+			Position:         2,
+			EstimatedStartAt: monad.Value(now.Add(4 * time.Minute)),
+		}, queue)
+	})
+
+	t.Run("should not estimate the start if the duration of a deployment ahead is unknown", func(t *testing.T) {
+		queue := get_deployment.EstimateQueue(now, 1, []get_deployment.QueuedDeployment{
+			{Group: "api/production", StartedAt: monad.Value(now)},
+		}, get_deployment.QueuedDeployment{Group: "app/production"})
+
+		testutil.DeepEquals(t, get_deployment.Queue{
+			Position: 1,
+		}, queue)
+	})
 }
@@ -0,0 +1,56 @@
+package get_deployment_build_context
+
+import (
+	"context"
+	"io"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve a gzipped tarball of the files fetched and generated by a deployment.
+// The caller MUST close it.
+type Query struct {
+	bus.Query[io.ReadCloser]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_deployment_build_context" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[io.ReadCloser, Query] {
+	return func(ctx context.Context, cmd Query) (io.ReadCloser, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return nil, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return nil, err
+		}
+
+		latest, err := reader.GetLastDeployment(ctx, depl.ID().AppID(), depl.Config().Environment())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return artifactManager.BuildContext(ctx, depl, latest)
+	}
+}
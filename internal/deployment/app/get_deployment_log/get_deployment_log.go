@@ -3,13 +3,14 @@ package get_deployment_log
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
 
-// Retrieve a deployment log absolute path.
+// Retrieve a deployment log file.
 type Query struct {
-	bus.Query[string]
+	bus.Query[domain.DeploymentLog]
 
 	AppID            string `json:"-"`
 	DeploymentNumber int    `json:"-"`
@@ -18,19 +19,30 @@ type Query struct {
 func (Query) Name_() string { return "deployment.query.get_deployment_log" }
 
 func Handler(
+	appsReader domain.AppsReader,
 	reader domain.DeploymentsReader,
 	artifactManager domain.ArtifactManager,
-) bus.RequestHandler[string, Query] {
-	return func(ctx context.Context, cmd Query) (string, error) {
+) bus.RequestHandler[domain.DeploymentLog, Query] {
+	return func(ctx context.Context, cmd Query) (domain.DeploymentLog, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return domain.DeploymentLog{}, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return domain.DeploymentLog{}, err
+		}
+
 		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
 			domain.AppID(cmd.AppID),
 			domain.DeploymentNumber(cmd.DeploymentNumber),
 		))
 
 		if err != nil {
-			return "", err
+			return domain.DeploymentLog{}, err
 		}
 
-		return artifactManager.LogPath(ctx, depl), nil
+		return artifactManager.Log(ctx, depl), nil
 	}
 }
@@ -0,0 +1,102 @@
+package get_deployment_log_steps
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve a deployment log grouped by pipeline step.
+	Query struct {
+		bus.Query[[]Step]
+
+		AppID            string `json:"-"`
+		DeploymentNumber int    `json:"-"`
+	}
+
+	Step struct {
+		Step      string                 `json:"step"`
+		StartedAt monad.Maybe[time.Time] `json:"started_at"`
+		Duration  int64                  `json:"duration"` // In milliseconds
+		Records   []Record               `json:"records"`
+	}
+
+	Record struct {
+		Time    monad.Maybe[time.Time] `json:"time"`
+		Level   string                 `json:"level"`
+		Stream  string                 `json:"stream"`
+		Message string                 `json:"message"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_deployment_log_steps" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[[]Step, Query] {
+	return func(ctx context.Context, cmd Query) ([]Step, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return nil, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := artifactManager.Records(ctx, depl)
+
+		if err != nil {
+			return nil, err
+		}
+
+		groups := domain.GroupDeploymentLogRecords(records)
+		steps := make([]Step, len(groups))
+
+		for i, group := range groups {
+			steps[i] = Step{
+				Step:      string(group.Step),
+				StartedAt: maybeTime(group.StartedAt),
+				Duration:  group.Duration.Milliseconds(),
+				Records:   make([]Record, len(group.Records)),
+			}
+
+			for j, record := range group.Records {
+				steps[i].Records[j] = Record{
+					Time:    maybeTime(record.Time),
+					Level:   string(record.Level),
+					Stream:  string(record.Stream),
+					Message: record.Message,
+				}
+			}
+		}
+
+		return steps, nil
+	}
+}
+
+// Records written before logs were structured do not have a time.
+func maybeTime(t time.Time) (m monad.Maybe[time.Time]) {
+	if !t.IsZero() {
+		m.Set(t)
+	}
+
+	return m
+}
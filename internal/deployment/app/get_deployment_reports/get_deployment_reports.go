@@ -0,0 +1,49 @@
+package get_deployment_reports
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve the directory containing the manifest and software bills of materials
+// recorded by a deployment.
+type Query struct {
+	bus.Query[domain.DeploymentReports]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_deployment_reports" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[domain.DeploymentReports, Query] {
+	return func(ctx context.Context, cmd Query) (domain.DeploymentReports, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return domain.DeploymentReports{}, err
+		}
+
+		if err = auth.AuthorizeRead(ctx, app.CreatedBy(), app.Resources()...); err != nil {
+			return domain.DeploymentReports{}, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return domain.DeploymentReports{}, err
+		}
+
+		return artifactManager.Reports(ctx, depl), nil
+	}
+}
@@ -0,0 +1,129 @@
+package get_deployment_stats
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Compute DORA-style metrics of deployments ended during the given period, for an app
+	// or every app deployed on a target.
+	Query struct {
+		bus.Query[Stats]
+
+		AppID       monad.Maybe[string] `json:"-"`
+		TargetID    monad.Maybe[string] `json:"-"`
+		Environment monad.Maybe[string] `json:"environment"`
+		Since       time.Time           `json:"since"`
+		Until       time.Time           `json:"until"`
+	}
+
+	Stats struct {
+		Since                 time.Time          `json:"since"`
+		Until                 time.Time          `json:"until"`
+		Deployments           int                `json:"deployments"`
+		Succeeded             int                `json:"succeeded"`
+		Failed                int                `json:"failed"`
+		Failures              map[string]int     `json:"failures"`         // Failed deployments per failure reason
+		Frequency             float64            `json:"frequency"`        // Deployments per day
+		SuccessRate           float64            `json:"success_rate"`     // Between 0 and 1
+		AverageDuration       monad.Maybe[int64] `json:"average_duration"` // All durations are in milliseconds
+		AverageBuildDuration  monad.Maybe[int64] `json:"average_build_duration"`
+		AverageDeployDuration monad.Maybe[int64] `json:"average_deploy_duration"`
+		TimeToRestore         monad.Maybe[int64] `json:"time_to_restore"` // Mean time between a failed deployment and the next successful one of the same app environment
+	}
+
+	// Ended deployment as kept in the deployments history.
+	Entry struct {
+		AppID          string
+		Environment    string
+		Succeeded      bool
+		FailureReason  monad.Maybe[string]
+		FinishedAt     time.Time
+		Duration       int64
+		BuildDuration  monad.Maybe[int64]
+		DeployDuration monad.Maybe[int64]
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_deployment_stats" }
+
+// Computes stats from history entries ordered by their end date.
+func Compute(since, until time.Time, entries []Entry) Stats {
+	stats := Stats{
+		Since:       since,
+		Until:       until,
+		Deployments: len(entries),
+		Failures:    make(map[string]int),
+	}
+
+	var (
+		duration, build, deploy, restore average
+		failedSince                      = make(map[string]time.Time) // Per app environment
+	)
+
+	for _, entry := range entries {
+		duration.add(monad.Value(entry.Duration))
+		build.add(entry.BuildDuration)
+		deploy.add(entry.DeployDuration)
+
+		key := entry.AppID + "/" + entry.Environment
+		failedAt, failing := failedSince[key]
+
+		if !entry.Succeeded {
+			stats.Failed++
+			stats.Failures[entry.FailureReason.Get(string(domain.DeploymentFailureUnknown))]++
+
+			if !failing {
+				failedSince[key] = entry.FinishedAt
+			}
+
+			continue
+		}
+
+		stats.Succeeded++
+
+		if failing {
+			restore.add(monad.Value(entry.FinishedAt.Sub(failedAt).Milliseconds()))
+			delete(failedSince, key)
+		}
+	}
+
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		stats.Frequency = float64(stats.Deployments) / days
+	}
+
+	if stats.Deployments > 0 {
+		stats.SuccessRate = float64(stats.Succeeded) / float64(stats.Deployments)
+	}
+
+	stats.AverageDuration = duration.result()
+	stats.AverageBuildDuration = build.result()
+	stats.AverageDeployDuration = deploy.result()
+	stats.TimeToRestore = restore.result()
+
+	return stats
+}
+
+type average struct {
+	sum   int64
+	count int64
+}
+
+func (a *average) add(value monad.Maybe[int64]) {
+	if v, isSet := value.TryGet(); isSet {
+		a.sum += v
+		a.count++
+	}
+}
+
+func (a *average) result() (m monad.Maybe[int64]) {
+	if a.count > 0 {
+		m.Set(a.sum / a.count)
+	}
+
+	return m
+}
@@ -0,0 +1,54 @@
+package get_deployment_stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_stats"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Compute(t *testing.T) {
+	until := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -10)
+
+	at := func(hours int) time.Time {
+		return since.Add(time.Duration(hours) * time.Hour)
+	}
+
+	t.Run("should return empty stats without any deployment", func(t *testing.T) {
+		stats := get_deployment_stats.Compute(since, until, nil)
+
+		testutil.DeepEquals(t, get_deployment_stats.Stats{
+			Since:    since,
+			Until:    until,
+			Failures: map[string]int{},
+		}, stats)
+	})
+
+	t.Run("should compute metrics of the given deployments", func(t *testing.T) {
+		stats := get_deployment_stats.Compute(since, until, []get_deployment_stats.Entry{
+			{AppID: "api", Environment: "production", Succeeded: true, FinishedAt: at(0), Duration: 1000, BuildDuration: monad.Value[int64](600)},
+			{AppID: "api", Environment: "production", Succeeded: false, FailureReason: monad.Value("image_pull_failed"), FinishedAt: at(1), Duration: 2000},
+			{AppID: "api", Environment: "staging", Succeeded: true, FinishedAt: at(2), Duration: 3000, BuildDuration: monad.Value[int64](1000), DeployDuration: monad.Value[int64](500)},
+			{AppID: "api", Environment: "production", Succeeded: false, FinishedAt: at(3), Duration: 1000},
+			{AppID: "api", Environment: "production", Succeeded: true, FinishedAt: at(5), Duration: 3000, DeployDuration: monad.Value[int64](1500)},
+		})
+
+		testutil.DeepEquals(t, get_deployment_stats.Stats{
+			Since:                 since,
+			Until:                 until,
+			Deployments:           5,
+			Succeeded:             3,
+			Failed:                2,
+			Failures:              map[string]int{"image_pull_failed": 1, "unknown": 1},
+			Frequency:             0.5,
+			SuccessRate:           0.6,
+			AverageDuration:       monad.Value[int64](2000),
+			AverageBuildDuration:  monad.Value[int64](800),
+			AverageDeployDuration: monad.Value[int64](1000),
+			TimeToRestore:         monad.Value((4 * time.Hour).Milliseconds()),
+		}, stats)
+	})
+}
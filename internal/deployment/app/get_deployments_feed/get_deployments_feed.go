@@ -0,0 +1,38 @@
+package get_deployments_feed
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Maximum number of deployments returned in a feed.
+const Limit = 50
+
+type (
+	// Retrieve the most recently requested deployments of an app, or of every readable app
+	// if not set, to be published as a feed.
+	Query struct {
+		bus.Query[[]Deployment]
+
+		AppID monad.Maybe[string] `json:"-"`
+	}
+
+	Deployment struct {
+		AppID            string                 `json:"app_id"`
+		AppName          string                 `json:"app_name"`
+		DeploymentNumber int                    `json:"deployment_number"`
+		Environment      string                 `json:"environment"`
+		Status           uint8                  `json:"status"`
+		ErrCode          monad.Maybe[string]    `json:"error_code"`
+		Note             string                 `json:"note"`
+		RequestedAt      time.Time              `json:"requested_at"`
+		RequestedBy      app.UserSummary        `json:"requested_by"`
+		StartedAt        monad.Maybe[time.Time] `json:"started_at"`
+		FinishedAt       monad.Maybe[time.Time] `json:"finished_at"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_deployments_feed" }
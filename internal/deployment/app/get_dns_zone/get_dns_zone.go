@@ -0,0 +1,55 @@
+package get_dns_zone
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve one DNS zone with records managed in it, its credentials are never
+	// returned.
+	Query struct {
+		bus.Query[Zone]
+
+		ID string `json:"id"`
+	}
+
+	Zone struct {
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Provider  string          `json:"provider"`
+		Addresses Addresses       `json:"addresses"`
+		CreatedAt time.Time       `json:"created_at"`
+		CreatedBy app.UserSummary `json:"created_by"`
+		Records   []Record        `json:"records"`
+	}
+
+	Record struct {
+		Host        string                 `json:"host"`
+		AppID       string                 `json:"app_id"`
+		AppName     string                 `json:"app_name"`
+		Environment string                 `json:"environment"`
+		Records     RecordSets             `json:"records"`
+		Status      string                 `json:"status"`
+		Error       monad.Maybe[string]    `json:"error"`
+		DriftedAt   monad.Maybe[time.Time] `json:"drifted_at"` // Last time records were modified outside of seelf and restored
+		CheckedAt   time.Time              `json:"checked_at"`
+	}
+
+	Addresses  []string
+	RecordSets []RecordSet
+
+	RecordSet struct {
+		Type   string   `json:"type"`
+		Values []string `json:"values"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_dns_zone" }
+
+func (a *Addresses) Scan(value any) error  { return storage.ScanJSON(value, a) }
+func (r *RecordSets) Scan(value any) error { return storage.ScanJSON(value, r) }
@@ -0,0 +1,13 @@
+package get_dns_zones
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zone"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve every DNS zone with records managed in each of them.
+type Query struct {
+	bus.Query[[]get_dns_zone.Zone]
+}
+
+func (Query) Name_() string { return "deployment.query.get_dns_zones" }
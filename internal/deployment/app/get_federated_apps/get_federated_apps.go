@@ -0,0 +1,125 @@
+package get_federated_apps
+
+import (
+	"context"
+	"sync"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve apps of this instance and of every registered peer, optionally limited
+	// to the ones having every given label. A peer which could not be reached does not
+	// fail the whole query, its error is reported on the matching instance instead.
+	Query struct {
+		bus.Query[Result]
+
+		Labels []string
+	}
+
+	Result struct {
+		Instances []Instance `json:"instances"`
+		Apps      []App      `json:"apps"`
+	}
+
+	Instance struct {
+		ID    monad.Maybe[string] `json:"id"` // Not set for the local instance
+		Name  string              `json:"name"`
+		Url   monad.Maybe[string] `json:"url"`
+		Error monad.Maybe[string] `json:"error"`
+	}
+
+	App struct {
+		get_apps.App
+		Instance monad.Maybe[string] `json:"instance"` // Peer from which the app comes, not set for local ones
+	}
+
+	// Client used to retrieve apps of a peer instance.
+	Client interface {
+		GetApps(context.Context, domain.Peer, []string) ([]get_apps.App, error)
+	}
+)
+
+const LocalInstanceName = "local"
+
+func (Query) Name_() string { return "deployment.query.get_federated_apps" }
+
+func Handler(
+	reader domain.PeersReader,
+	local bus.RequestHandler[[]get_apps.App, get_apps.Query],
+	client Client,
+) bus.RequestHandler[Result, Query] {
+	return func(ctx context.Context, query Query) (Result, error) {
+		if !auth.HasAdminRights(ctx) {
+			return Result{}, apperr.ErrForbidden
+		}
+
+		peers, err := reader.GetAll(ctx)
+
+		if err != nil {
+			return Result{}, err
+		}
+
+		localApps, err := local(ctx, get_apps.Query{Labels: query.Labels})
+
+		if err != nil {
+			return Result{}, err
+		}
+
+		var (
+			wg        sync.WaitGroup
+			instances = make([]Instance, len(peers))
+			peersApps = make([][]get_apps.App, len(peers))
+		)
+
+		for i, peer := range peers {
+			instances[i] = Instance{
+				ID:   monad.Value(string(peer.ID())),
+				Name: peer.Name(),
+				Url:  monad.Value(peer.Url().String()),
+			}
+
+			wg.Add(1)
+
+			go func(i int, peer domain.Peer) {
+				defer wg.Done()
+
+				apps, err := client.GetApps(ctx, peer, query.Labels)
+
+				if err != nil {
+					instances[i].Error.Set(err.Error())
+					return
+				}
+
+				peersApps[i] = apps
+			}(i, peer)
+		}
+
+		wg.Wait()
+
+		result := Result{
+			Instances: append([]Instance{{Name: LocalInstanceName}}, instances...),
+			Apps:      make([]App, 0, len(localApps)),
+		}
+
+		for _, a := range localApps {
+			result.Apps = append(result.Apps, App{App: a})
+		}
+
+		for i, apps := range peersApps {
+			for _, a := range apps {
+				result.Apps = append(result.Apps, App{
+					App:      a,
+					Instance: instances[i].ID,
+				})
+			}
+		}
+
+		return result, nil
+	}
+}
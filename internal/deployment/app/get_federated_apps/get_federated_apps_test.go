@@ -0,0 +1,98 @@
+package get_federated_apps_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_federated_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_GetFederatedApps(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	errUnreachable := errors.New("unreachable")
+
+	local := func(ctx context.Context, query get_apps.Query) ([]get_apps.App, error) {
+		return []get_apps.App{{ID: "local-app", Name: "local-app", Labels: nil}}, nil
+	}
+
+	sut := func(client get_federated_apps.Client, existing ...*domain.Peer) bus.RequestHandler[get_federated_apps.Result, get_federated_apps.Query] {
+		return get_federated_apps.Handler(memory.NewPeersStore(existing...), local, client)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&client{})
+
+		_, err := uc(auth.WithUser(context.Background(), user), get_federated_apps.Query{})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should aggregate apps of the local instance and peers", func(t *testing.T) {
+		reachable := domain.NewPeer("vps", must.Panic(domain.UrlFrom("https://vps.example.com")), "token", "uid")
+		unreachable := domain.NewPeer("home lab", must.Panic(domain.UrlFrom("http://192.168.1.10")), "token", "uid")
+		c := &client{
+			apps: map[domain.PeerID][]get_apps.App{
+				reachable.ID(): {{ID: "remote-app", Name: "remote-app"}},
+			},
+			errs: map[domain.PeerID]error{
+				unreachable.ID(): errUnreachable,
+			},
+		}
+		uc := sut(c, &reachable, &unreachable)
+
+		result, err := uc(ctx, get_federated_apps.Query{Labels: []string{"env=prod"}})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"env=prod"}, c.labels)
+		testutil.HasLength(t, result.Instances, 3)
+		testutil.Equals(t, get_federated_apps.LocalInstanceName, result.Instances[0].Name)
+		testutil.IsFalse(t, result.Instances[0].ID.HasValue())
+
+		for _, instance := range result.Instances[1:] {
+			switch instance.ID.MustGet() {
+			case string(reachable.ID()):
+				testutil.IsFalse(t, instance.Error.HasValue())
+			case string(unreachable.ID()):
+				testutil.Equals(t, errUnreachable.Error(), instance.Error.MustGet())
+			default:
+				t.Fatalf("unexpected instance %s", instance.ID.MustGet())
+			}
+		}
+
+		testutil.HasLength(t, result.Apps, 2)
+		testutil.Equals(t, "local-app", result.Apps[0].ID)
+		testutil.IsFalse(t, result.Apps[0].Instance.HasValue())
+		testutil.Equals(t, "remote-app", result.Apps[1].ID)
+		testutil.Equals(t, string(reachable.ID()), result.Apps[1].Instance.MustGet())
+	})
+}
+
+type client struct {
+	mu     sync.Mutex
+	apps   map[domain.PeerID][]get_apps.App
+	errs   map[domain.PeerID]error
+	labels []string
+}
+
+func (c *client) GetApps(_ context.Context, peer domain.Peer, labels []string) ([]get_apps.App, error) {
+	c.mu.Lock()
+	c.labels = labels
+	c.mu.Unlock()
+
+	if err, found := c.errs[peer.ID()]; found {
+		return nil, err
+	}
+
+	return c.apps[peer.ID()], nil
+}
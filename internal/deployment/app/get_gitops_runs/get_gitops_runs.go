@@ -0,0 +1,40 @@
+package get_gitops_runs
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve reconciliations with the GitOps repository, most recent first.
+	Query struct {
+		bus.Query[storage.Paginated[Run]]
+
+		Page monad.Maybe[int] `form:"page"`
+	}
+
+	Run struct {
+		ID        string              `json:"id"`
+		Commit    string              `json:"commit"`
+		Changes   Changes             `json:"changes"`
+		Error     monad.Maybe[string] `json:"error"`
+		CreatedAt time.Time           `json:"created_at"`
+	}
+
+	Change struct {
+		Resource string `json:"resource"`
+		Name     string `json:"name"`
+		Kind     string `json:"kind"`
+	}
+
+	Changes []Change
+)
+
+func (Query) Name_() string { return "deployment.query.get_gitops_runs" }
+
+func (c *Changes) Scan(value any) error {
+	return storage.ScanJSON(value, c)
+}
@@ -0,0 +1,27 @@
+package get_peer
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve one peer instance, its token is never returned.
+	Query struct {
+		bus.Query[Peer]
+
+		ID string `json:"id"`
+	}
+
+	Peer struct {
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Url       string          `json:"url"`
+		CreatedAt time.Time       `json:"created_at"`
+		CreatedBy app.UserSummary `json:"created_by"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_peer" }
@@ -0,0 +1,13 @@
+package get_peers
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peer"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve every peer instance registered on this one.
+type Query struct {
+	bus.Query[[]get_peer.Peer]
+}
+
+func (Query) Name_() string { return "deployment.query.get_peers" }
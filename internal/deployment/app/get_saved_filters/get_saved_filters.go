@@ -0,0 +1,28 @@
+package get_saved_filters
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve filters saved by the current user, optionally limited to the given kind.
+	Query struct {
+		bus.Query[[]SavedFilter]
+
+		Kind monad.Maybe[string] `form:"kind"`
+	}
+
+	SavedFilter struct {
+		ID        string                     `json:"id"`
+		Name      string                     `json:"name"`
+		Kind      string                     `json:"kind"`
+		Criteria  domain.SavedFilterCriteria `json:"criteria"`
+		CreatedAt time.Time                  `json:"created_at"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_saved_filters" }
@@ -20,15 +20,20 @@ type (
 	}
 
 	Target struct {
-		ID                 string                       `json:"id"`
-		Name               string                       `json:"name"`
-		Url                string                       `json:"url"`
-		Provider           Provider                     `json:"provider"`
-		State              State                        `json:"state"`
-		CleanupRequestedAt monad.Maybe[time.Time]       `json:"cleanup_requested_at"`
-		CleanupRequestedBy monad.Maybe[app.UserSummary] `json:"cleanup_requested_by"`
-		CreatedAt          time.Time                    `json:"created_at"`
-		CreatedBy          app.UserSummary              `json:"created_by"`
+		ID                 string                         `json:"id"`
+		Name               string                         `json:"name"`
+		Url                string                         `json:"url"`
+		Provider           Provider                       `json:"provider"`
+		State              State                          `json:"state"`
+		Vars               map[string]string              `json:"vars"` // Shared variables, secret values are masked
+		Platform           monad.Maybe[string]            `json:"platform"`
+		ProxyVersion       monad.Maybe[string]            `json:"proxy_version"`
+		GarbageCollection  monad.Maybe[GarbageCollection] `json:"last_garbage_collection"`
+		Labels             app.Labels                     `json:"labels"`
+		CleanupRequestedAt monad.Maybe[time.Time]         `json:"cleanup_requested_at"`
+		CleanupRequestedBy monad.Maybe[app.UserSummary]   `json:"cleanup_requested_by"`
+		CreatedAt          time.Time                      `json:"created_at"`
+		CreatedBy          app.UserSummary                `json:"created_by"`
 	}
 
 	State struct {
@@ -37,6 +42,15 @@ type (
 		LastReadyVersion monad.Maybe[time.Time] `json:"last_ready_version"`
 	}
 
+	// Report of the last garbage collection made on the target.
+	GarbageCollection struct {
+		Images         int       `json:"images"`
+		Containers     int       `json:"containers"`
+		Networks       int       `json:"networks"`
+		SpaceReclaimed int64     `json:"space_reclaimed"`
+		CollectedAt    time.Time `json:"collected_at"`
+	}
+
 	Provider struct {
 		Kind string         `json:"kind"`
 		Data ProviderConfig `json:"data"`
@@ -48,3 +62,5 @@ type (
 )
 
 func (Query) Name_() string { return "deployment.query.get_target" }
+
+func (g *GarbageCollection) Scan(value any) error { return storage.ScanJSON(value, g) }
@@ -0,0 +1,37 @@
+package get_targets_capacity
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve the capacity of active targets to find where there is room to place a new
+	// application. Targets with the most free disk space come first, then the ones
+	// hosting the fewest applications.
+	Query struct {
+		bus.Query[[]Target]
+	}
+
+	Target struct {
+		ID            string                 `json:"id"`
+		Name          string                 `json:"name"`
+		Url           string                 `json:"url"`
+		Apps          int                    `json:"apps"`           // Applications deployed on this target in at least one environment
+		ReservedPorts []string               `json:"reserved_ports"` // Ports assigned to custom entrypoints, such as 5432/tcp
+		Resources     monad.Maybe[Resources] `json:"resources"`      // Empty until the first collection has been made
+	}
+
+	// Resources reported by the target during the last collection.
+	Resources struct {
+		RunningContainers int       `json:"running_containers"`
+		ImagesSize        int64     `json:"images_size"` // In bytes
+		DiskFree          int64     `json:"disk_free"`   // In bytes
+		DiskTotal         int64     `json:"disk_total"`  // In bytes
+		CollectedAt       time.Time `json:"collected_at"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_targets_capacity" }
@@ -0,0 +1,34 @@
+package get_team
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve one team along with its members.
+	Query struct {
+		bus.Query[Team]
+
+		ID string `json:"id"`
+	}
+
+	Team struct {
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Members   []Member        `json:"members"`
+		CreatedAt time.Time       `json:"created_at"`
+		CreatedBy app.UserSummary `json:"created_by"`
+	}
+
+	// User which has been granted a role on the team.
+	Member struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_team" }
@@ -0,0 +1,25 @@
+package get_teams
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve teams readable by the current user.
+	Query struct {
+		bus.Query[[]Team]
+	}
+
+	Team struct {
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		AppsCount int             `json:"apps_count"`
+		CreatedAt time.Time       `json:"created_at"`
+		CreatedBy app.UserSummary `json:"created_by"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_teams" }
@@ -0,0 +1,29 @@
+package get_trashed_apps
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve deleted applications which could still be restored, most recently deleted
+	// first. Restricted users only see the ones they have created or deleted.
+	Query struct {
+		bus.Query[[]TrashedApp]
+	}
+
+	TrashedApp struct {
+		ID                       string          `json:"id"`
+		Name                     string          `json:"name"`
+		ProductionTarget         string          `json:"production_target"`
+		StagingTarget            string          `json:"staging_target"`
+		RedeployableEnvironments []string        `json:"redeployable_environments"` // Environments with a successful deployment to redeploy on restore
+		DeletedAt                time.Time       `json:"deleted_at"`
+		DeletedBy                app.UserSummary `json:"deleted_by"`
+		ExpiresAt                time.Time       `json:"expires_at"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_trashed_apps" }
@@ -14,3 +14,19 @@ func DeploymentGroup(config domain.DeploymentConfig) string {
 func TargetConfigurationGroup(id domain.TargetID) string {
 	return "deployment.target.configure." + string(id)
 }
+
+// Group for add-on operation to prevent multiple provisioning at the same time.
+func AddonProvisioningGroup(id domain.AddonID) string {
+	return "deployment.addon.provision." + string(id)
+}
+
+// Group for add-on backups and restorations so they never run at the same time on
+// the same add-on.
+func AddonBackupGroup(id domain.AddonID) string {
+	return "deployment.addon.backup." + string(id)
+}
+
+// Group for DNS records synchronization so records are never reconciled concurrently.
+func DnsSyncGroup() string {
+	return "deployment.dns.sync"
+}
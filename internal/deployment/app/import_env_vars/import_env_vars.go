@@ -0,0 +1,78 @@
+package import_env_vars
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+	"github.com/joho/godotenv"
+)
+
+// Import environment variables of an app service from a dotenv payload. Variables are
+// merged with the existing ones unless the replace mode is given.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string              `json:"-"`
+	Environment string              `json:"environment"`
+	Service     string              `json:"service"`
+	Mode        monad.Maybe[string] `json:"mode"`
+	Content     string              `json:"content"`
+}
+
+func (Command) Name_() string              { return "deployment.command.import_env_vars" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env  domain.Environment
+			vars domain.EnvVars
+			mode = domain.EnvImportMerge
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"service":     validate.Field(cmd.Service, strings.Required),
+			"mode": validate.Maybe(cmd.Mode, func(value string) error {
+				return validate.Value(value, &mode, domain.EnvImportModeFrom)
+			}),
+			"content": validate.Value(cmd.Content, &vars, parseDotenv),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.ImportEnvironmentVariables(env, cmd.Service, vars, mode); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
+
+func parseDotenv(content string) (domain.EnvVars, error) {
+	vars, err := godotenv.Unmarshal(content)
+
+	if err != nil {
+		return nil, domain.ErrInvalidDotenv
+	}
+
+	return vars, nil
+}
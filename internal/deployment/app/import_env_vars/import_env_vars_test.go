@@ -0,0 +1,101 @@
+package import_env_vars_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/import_env_vars"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_ImportEnvVars(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newApp := func() domain.App {
+		production := domain.NewEnvironmentConfig("1")
+		production.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "false", "API_KEY": "secret"}})
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(production, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+	sut := func(app *domain.App) bus.RequestHandler[bus.UnitType, import_env_vars.Command] {
+		store := memory.NewAppsStore(app)
+		return import_env_vars.Handler(store, store)
+	}
+
+	t.Run("should validate the command", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, import_env_vars.Command{
+			AppID:       string(app.ID()),
+			Environment: "dev",
+			Mode:        monad.Value("append"),
+			Content:     `DEBUG="unterminated`,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 4)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		uc := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), import_env_vars.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Service:     "app",
+			Content:     "DEBUG=true",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should merge imported variables by default", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, import_env_vars.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Service:     "app",
+			Content:     "# Some comment\nDEBUG=true\nAPI_KEY=\"********\"\nPORT=8080\n",
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.DeepEquals(t, domain.ServicesEnv{
+			"app": {"DEBUG": "true", "API_KEY": "secret", "PORT": "8080"},
+		}, changed.Config.Vars().MustGet())
+	})
+
+	t.Run("should replace variables if asked to", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, import_env_vars.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Service:     "app",
+			Mode:        monad.Value("replace"),
+			Content:     "PORT=8080",
+		})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.DeepEquals(t, domain.ServicesEnv{
+			"app": {"PORT": "8080"},
+		}, changed.Config.Vars().MustGet())
+	})
+}
@@ -0,0 +1,18 @@
+package provision_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAddonCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AddonCreated] {
+	return func(ctx context.Context, evt domain.AddonCreated) error {
+		return scheduler.Queue(ctx, Command{
+			ID:      string(evt.ID),
+			Version: evt.State.Version(),
+		}, bus.WithGroup(app.AddonProvisioningGroup(evt.ID)), bus.WithPolicy(bus.JobPolicyMerge))
+	}
+}
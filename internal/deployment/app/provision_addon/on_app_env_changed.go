@@ -0,0 +1,36 @@
+package provision_addon
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When the target of an application environment has changed, move its add-ons to the
+// new target. Their services on the old target are removed by the app cleanup.
+func OnAppEnvChangedHandler(
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+) bus.SignalHandler[domain.AppEnvChanged] {
+	return func(ctx context.Context, evt domain.AppEnvChanged) error {
+		if !evt.TargetHasChanged() {
+			return nil
+		}
+
+		addons, err := reader.GetByAppEnv(ctx, evt.ID, evt.Environment)
+
+		if err != nil {
+			return err
+		}
+
+		updated := make([]*domain.Addon, len(addons))
+
+		for i := range addons {
+			addons[i].MovedTo(evt.Config.Target())
+			updated[i] = &addons[i]
+		}
+
+		return writer.Write(ctx, updated...)
+	}
+}
@@ -0,0 +1,82 @@
+package provision_addon
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Provision the service of an add-on on its target.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID      string    `json:"id"`
+	Version time.Time `json:"version"`
+}
+
+func (Command) Name_() string        { return "deployment.command.provision_addon" }
+func (c Command) ResourceID() string { return c.ID }
+
+func Handler(
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		addon, err := reader.GetByID(ctx, domain.AddonID(cmd.ID))
+
+		if err != nil {
+			// Add-on not found, already deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		if addon.IsOutdated(cmd.Version) {
+			return bus.Unit, nil
+		}
+
+		target, err := targetsReader.GetByID(ctx, addon.Target())
+
+		if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return bus.Unit, err
+		}
+
+		// If the target does not exist anymore or is not available, fail the provisioning
+		provisionErr := err
+
+		if provisionErr == nil {
+			provisionErr = target.CheckAvailability()
+
+			// Target configuration is in progress, just retry the job later
+			if errors.Is(provisionErr, domain.ErrTargetConfigurationInProgress) {
+				return bus.Unit, provisionErr
+			}
+		}
+
+		if provisionErr == nil {
+			provisionErr = provider.ProvisionAddon(ctx, target, addon)
+		}
+
+		// Since the provisioning can take some time, retrieve the latest version of the
+		// add-on before updating its state.
+		if addon, err = reader.GetByID(ctx, addon.ID()); err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		addon.Provisioned(cmd.Version, provisionErr)
+
+		return bus.Unit, writer.Write(ctx, &addon)
+	}
+}
@@ -0,0 +1,130 @@
+package provision_addon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/provision_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	addons  []*domain.Addon
+	targets []*domain.Target
+}
+
+func Test_ProvisionAddon(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData, provisionErr error) (bus.RequestHandler[bus.UnitType, provision_addon.Command], memory.AddonsStore, *dummyProvider) {
+		store := memory.NewAddonsStore(data.addons...)
+		provider := &dummyProvider{err: provisionErr}
+		return provision_addon.Handler(store, store, memory.NewTargetsStore(data.targets...), provider), store, provider
+	}
+
+	newTarget := func() domain.Target {
+		return must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+	}
+
+	newAddon := func(target domain.TargetID) domain.Addon {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "uid"))
+		return must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+	}
+
+	t.Run("should fail silently if the add-on does not exist anymore", func(t *testing.T) {
+		uc, _, provider := sut(initialData{}, nil)
+
+		r, err := uc(ctx, provision_addon.Command{ID: "some-id"})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should skip outdated versions", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		addon := newAddon(target.ID())
+		version := addon.CurrentVersion()
+		addon.MovedTo("another-target")
+		uc, _, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}}, nil)
+
+		_, err := uc(ctx, provision_addon.Command{ID: string(addon.ID()), Version: version})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should retry later if the target is configuring", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		uc, _, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}}, nil)
+
+		_, err := uc(ctx, provision_addon.Command{ID: string(addon.ID()), Version: addon.CurrentVersion()})
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should mark the add-on as failed if the target does not exist anymore", func(t *testing.T) {
+		addon := newAddon("some-target")
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}}, nil)
+
+		_, err := uc(ctx, provision_addon.Command{ID: string(addon.ID()), Version: addon.CurrentVersion()})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+		addon = must.Panic(store.GetByID(ctx, addon.ID()))
+		testutil.Equals(t, domain.AddonStatusFailed, addon.Status())
+	})
+
+	t.Run("should mark the add-on as failed if the provider fails", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		addon := newAddon(target.ID())
+		providerErr := errors.New("some error")
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}}, providerErr)
+
+		_, err := uc(ctx, provision_addon.Command{ID: string(addon.ID()), Version: addon.CurrentVersion()})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.called)
+		addon = must.Panic(store.GetByID(ctx, addon.ID()))
+		testutil.Equals(t, domain.AddonStatusFailed, addon.Status())
+	})
+
+	t.Run("should provision the add-on on its target", func(t *testing.T) {
+		target := newTarget()
+		target.Configured(target.CurrentVersion(), nil, nil)
+		addon := newAddon(target.ID())
+		uc, store, provider := sut(initialData{addons: []*domain.Addon{&addon}, targets: []*domain.Target{&target}}, nil)
+
+		_, err := uc(ctx, provision_addon.Command{ID: string(addon.ID()), Version: addon.CurrentVersion()})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.called)
+		addon = must.Panic(store.GetByID(ctx, addon.ID()))
+		testutil.Equals(t, domain.AddonStatusReady, addon.Status())
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	called bool
+	err    error
+}
+
+func (d *dummyProvider) ProvisionAddon(context.Context, domain.Target, domain.Addon) error {
+	d.called = true
+	return d.err
+}
@@ -0,0 +1,45 @@
+package purge_app_archives
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Remove archives of deleted applications whose grace period has ended. Periodically
+// sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.purge_app_archives" }
+
+func Handler(
+	reader domain.AppArchivesReader,
+	writer domain.AppArchivesWriter,
+	storage domain.AppArchivesStorage,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		archives, err := reader.GetExpired(ctx, time.Now().UTC())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		for _, archive := range archives {
+			if err = storage.Remove(ctx, archive); err != nil {
+				return bus.Unit, err
+			}
+
+			archive.Delete()
+
+			if err = writer.Write(ctx, &archive); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, nil
+	}
+}
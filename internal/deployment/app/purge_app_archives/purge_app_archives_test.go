@@ -0,0 +1,60 @@
+package purge_app_archives_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_app_archives"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_PurgeAppArchives(t *testing.T) {
+	ctx := context.Background()
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+
+	newArchive := func(gracePeriod time.Duration) domain.AppArchive {
+		archive := must.Panic(domain.NewAppArchive(app, "uid"))
+		archive.Completed(42, nil, gracePeriod)
+		return archive
+	}
+
+	t.Run("should remove archives whose grace period has ended", func(t *testing.T) {
+		expired := newArchive(-time.Hour)
+		kept := newArchive(time.Hour)
+		running := must.Panic(domain.NewAppArchive(app, "uid"))
+		store := memory.NewAppArchivesStore(&expired, &kept, &running)
+		storage := &dummyStorage{}
+		uc := purge_app_archives.Handler(store, store, storage)
+
+		_, err := uc(ctx, purge_app_archives.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.AppArchiveID{expired.ID()}, storage.removed)
+
+		_, err = store.GetByID(ctx, expired.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+
+		_, err = store.GetByID(ctx, kept.ID())
+		testutil.IsNil(t, err)
+
+		_, err = store.GetByID(ctx, running.ID())
+		testutil.IsNil(t, err)
+	})
+}
+
+type dummyStorage struct {
+	domain.AppArchivesStorage
+	removed []domain.AppArchiveID
+}
+
+func (d *dummyStorage) Remove(_ context.Context, archive domain.AppArchive) error {
+	d.removed = append(d.removed, archive.ID())
+	return nil
+}
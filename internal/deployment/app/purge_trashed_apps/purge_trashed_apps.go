@@ -0,0 +1,40 @@
+package purge_trashed_apps
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Remove for good trashed applications whose retention has ended. Periodically sent
+// by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.purge_trashed_apps" }
+
+func Handler(
+	reader domain.TrashedAppsReader,
+	writer domain.TrashedAppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		apps, err := reader.GetExpired(ctx, time.Now().UTC())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		for _, app := range apps {
+			app.Purge()
+
+			if err = writer.Write(ctx, &app); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, nil
+	}
+}
@@ -0,0 +1,43 @@
+package purge_trashed_apps_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_trashed_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_PurgeTrashedApps(t *testing.T) {
+	ctx := context.Background()
+
+	newTrashedApp := func(name domain.AppName, retention time.Duration) domain.TrashedApp {
+		app := must.Panic(domain.NewApp(name,
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+		app.RequestCleanup("uid")
+		return must.Panic(domain.NewTrashedApp(app, nil, retention))
+	}
+
+	t.Run("should remove trashed apps whose retention has ended", func(t *testing.T) {
+		expired := newTrashedApp("expired-app", -time.Hour)
+		kept := newTrashedApp("kept-app", time.Hour)
+		store := memory.NewTrashedAppsStore(&expired, &kept)
+		uc := purge_trashed_apps.Handler(store, store)
+
+		_, err := uc(ctx, purge_trashed_apps.Command{})
+
+		testutil.IsNil(t, err)
+
+		_, err = store.GetByID(ctx, expired.ID())
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+
+		_, err = store.GetByID(ctx, kept.ID())
+		testutil.IsNil(t, err)
+	})
+}
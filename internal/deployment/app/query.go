@@ -1,6 +1,9 @@
 package app
 
-import "github.com/YuukanOO/seelf/pkg/monad"
+import (
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
 
 type (
 	UserSummary struct {
@@ -8,6 +11,11 @@ type (
 		Email string `json:"email"`
 	}
 
+	TeamSummary struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
 	TargetSummary struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
@@ -18,4 +26,35 @@ type (
 		Production monad.Maybe[T] `json:"production"`
 		Staging    monad.Maybe[T] `json:"staging"`
 	}
+
+	Labels []string
+
+	// Structured data attached to a deployment, such as a ticket identifier.
+	Metadata map[string]string
 )
+
+func (l *Labels) Scan(value any) error {
+	if err := storage.ScanJSON(value, l); err != nil {
+		return err
+	}
+
+	// Always return an array to ease the client work
+	if *l == nil {
+		*l = Labels{}
+	}
+
+	return nil
+}
+
+func (m *Metadata) Scan(value any) error {
+	if err := storage.ScanJSON(value, m); err != nil {
+		return err
+	}
+
+	// Always return an object to ease the client work
+	if *m == nil {
+		*m = Metadata{}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package queue_addon_backups
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Start a backup of every add-on which has not been backed up for the given interval.
+// Backups themselves are taken by a dedicated job. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Interval time.Duration `json:"-"`
+}
+
+func (Command) Name_() string { return "deployment.command.queue_addon_backups" }
+
+func Handler(
+	addonsReader domain.AddonsReader,
+	writer domain.AddonBackupsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		addons, err := addonsReader.GetDueForBackup(ctx, time.Now().UTC().Add(-cmd.Interval))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		backups := make([]*domain.AddonBackup, 0, len(addons))
+
+		for _, addon := range addons {
+			backup, err := domain.NewAddonBackup(addon)
+
+			// Some kinds do not hold data worth a backup
+			if errors.Is(err, domain.ErrAddonBackupNotSupported) {
+				continue
+			}
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			backups = append(backups, &backup)
+		}
+
+		return bus.Unit, writer.Write(ctx, backups...)
+	}
+}
@@ -0,0 +1,38 @@
+package queue_addon_backups_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_addon_backups"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_QueueAddonBackups(t *testing.T) {
+	ctx := context.Background()
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+
+	t.Run("should start a backup of every ready add-on which supports it", func(t *testing.T) {
+		postgres := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		postgres.Provisioned(postgres.CurrentVersion(), nil)
+		redis := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "uid"))
+		redis.Provisioned(redis.CurrentVersion(), nil)
+		pending := must.Panic(domain.NewAddon(app, domain.Staging, domain.AddonMySQL, monad.None[string](), nil, "uid"))
+		store := memory.NewAddonBackupsStore()
+		uc := queue_addon_backups.Handler(memory.NewAddonsStore(&postgres, &redis, &pending), store)
+
+		_, err := uc(ctx, queue_addon_backups.Command{Interval: 24 * time.Hour})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, must.Panic(store.GetByAddon(ctx, postgres.ID())), 1)
+		testutil.HasLength(t, must.Panic(store.GetByAddon(ctx, redis.ID())), 0)
+		testutil.HasLength(t, must.Panic(store.GetByAddon(ctx, pending.ID())), 0)
+	})
+}
@@ -14,12 +14,16 @@ import (
 type Command struct {
 	bus.Command[int]
 
-	AppID       string `json:"-"`
-	Environment string `json:"environment" form:"environment"`
-	Source      any    `json:"-"`
+	AppID       string            `json:"-"`
+	Environment string            `json:"environment" form:"environment"`
+	Labels      []string          `json:"labels" form:"labels"`
+	Note        string            `json:"note" form:"note"`
+	Metadata    map[string]string `json:"metadata" form:"metadata"` // Given as a JSON object when sent as a form
+	Source      any               `json:"-"`
 }
 
-func (Command) Name_() string { return "deployment.command.queue_deployment" }
+func (Command) Name_() string              { return "deployment.command.queue_deployment" }
+func (c Command) AuditResource(any) string { return c.AppID }
 
 func Handler(
 	appsReader domain.AppsReader,
@@ -28,10 +32,18 @@ func Handler(
 	source domain.Source,
 ) bus.RequestHandler[int, Command] {
 	return func(ctx context.Context, cmd Command) (int, error) {
-		var env domain.Environment
+		var (
+			env      domain.Environment
+			labels   domain.Labels
+			note     domain.DeploymentNote
+			metadata domain.DeploymentMetadata
+		)
 
 		if err := validate.Struct(validate.Of{
 			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"labels":      validate.Value(cmd.Labels, &labels, domain.LabelsFrom),
+			"note":        validate.Value(cmd.Note, &note, domain.DeploymentNoteFrom),
+			"metadata":    validate.Value(cmd.Metadata, &metadata, domain.DeploymentMetadataFrom),
 		}); err != nil {
 			return 0, err
 		}
@@ -42,6 +54,10 @@ func Handler(
 			return 0, err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return 0, err
+		}
+
 		meta, err := source.Prepare(ctx, app, cmd.Source)
 
 		if err != nil {
@@ -60,6 +76,9 @@ func Handler(
 			return 0, err
 		}
 
+		dpl.HasLabels(labels)
+		dpl.Annotate(note, metadata)
+
 		if err := writer.Write(ctx, &dpl); err != nil {
 			return 0, err
 		}
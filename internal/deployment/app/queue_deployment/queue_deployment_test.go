@@ -64,6 +64,36 @@ func Test_QueueDeployment(t *testing.T) {
 		testutil.Equals(t, 0, num)
 	})
 
+	t.Run("should fail if the user is not allowed to deploy the app", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+
+		uc := sut()
+		num, err := uc(auth.WithUser(context.Background(), user), queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, 0, num)
+	})
+
+	t.Run("should succeed if the user has been granted the deployer role on one of the app targets", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceTarget, "1"), true), auth.RoleDeployer)
+
+		uc := sut()
+		num, err := uc(auth.WithUser(context.Background(), user), queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, num)
+	})
+
 	t.Run("should succeed if everything is good", func(t *testing.T) {
 		uc := sut()
 		num, err := uc(ctx, queue_deployment.Command{
@@ -75,4 +105,50 @@ func Test_QueueDeployment(t *testing.T) {
 		testutil.IsNil(t, err)
 		testutil.Equals(t, 1, num)
 	})
+
+	t.Run("should fail if labels are invalid", func(t *testing.T) {
+		uc := sut()
+		num, err := uc(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+			Labels:      []string{" "},
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.Equals(t, 0, num)
+	})
+
+	t.Run("should fail if metadata are invalid", func(t *testing.T) {
+		uc := sut()
+		num, err := uc(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+			Metadata:    map[string]string{"Not a key": "1.0.0"},
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		testutil.Equals(t, 0, num)
+	})
+
+	t.Run("should attach the note and metadata to the deployment", func(t *testing.T) {
+		deploymentsStore := memory.NewDeploymentsStore()
+		uc := queue_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, raw.New())
+
+		num, err := uc(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+			Note:        " Fix the login page ",
+			Metadata:    map[string]string{"ticket": "OPS-42", "release": "1.2.0"},
+		})
+
+		testutil.IsNil(t, err)
+
+		deployment, err := deploymentsStore.GetByID(ctx, domain.DeploymentIDFrom(app.ID(), domain.DeploymentNumber(num)))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "Fix the login page", deployment.Note())
+		testutil.DeepEquals(t, domain.DeploymentMetadata{"ticket": "OPS-42", "release": "1.2.0"}, deployment.Metadata())
+	})
 }
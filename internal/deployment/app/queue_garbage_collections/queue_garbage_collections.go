@@ -0,0 +1,52 @@
+package queue_garbage_collections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Request a garbage collection on every ready target. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.queue_garbage_collections" }
+
+func Handler(
+	reader domain.TargetsReader,
+	writer domain.TargetsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := reader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			requested  []*domain.Target
+			targetErrs []error
+		)
+
+		for i := range targets {
+			target := &targets[i]
+
+			if err = target.RequestGarbageCollection(); err != nil {
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+				continue
+			}
+
+			requested = append(requested, target)
+		}
+
+		if err = writer.Write(ctx, requested...); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
@@ -0,0 +1,29 @@
+package queue_garbage_collections_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_garbage_collections"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_QueueGarbageCollections(t *testing.T) {
+	t.Run("should request a garbage collection on ready targets", func(t *testing.T) {
+		ready := fixture.Target("http://ready.localhost", true)
+		configuring := fixture.Target("http://configuring.localhost", false)
+		store := memory.NewTargetsStore(&ready, &configuring)
+		uc := queue_garbage_collections.Handler(store, store)
+
+		_, err := uc(context.Background(), queue_garbage_collections.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &ready, 3)
+		requested := testutil.EventIs[domain.TargetGarbageCollectionRequested](t, &ready, 2)
+		testutil.Equals(t, ready.ID(), requested.ID)
+		testutil.HasNEvents(t, &configuring, 1)
+	})
+}
@@ -0,0 +1,59 @@
+package queue_proxy_upgrades
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Request the proxy upgrade of every ready target whose proxy is not running the version
+// supported by this seelf release. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.queue_proxy_upgrades" }
+
+func Handler(
+	reader domain.TargetsReader,
+	writer domain.TargetsWriter,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		targets, err := reader.GetReady(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			outdated   []*domain.Target
+			targetErrs []error
+		)
+
+		for i := range targets {
+			target := &targets[i]
+			version, err := provider.ProxyVersion(*target)
+
+			if err == nil {
+				err = target.RequestProxyUpgrade(version)
+			}
+
+			switch {
+			case err == nil:
+				outdated = append(outdated, target)
+			case !errors.Is(err, domain.ErrProxyUpToDate):
+				targetErrs = append(targetErrs, fmt.Errorf("target %s: %w", target.ID(), err))
+			}
+		}
+
+		if err = writer.Write(ctx, outdated...); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, errors.Join(targetErrs...)
+	}
+}
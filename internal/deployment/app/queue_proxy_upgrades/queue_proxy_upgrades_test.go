@@ -0,0 +1,43 @@
+package queue_proxy_upgrades_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_proxy_upgrades"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_QueueProxyUpgrades(t *testing.T) {
+	t.Run("should request the proxy upgrade of ready targets running an outdated proxy", func(t *testing.T) {
+		outdated := fixture.Target("http://outdated.localhost", true)
+		outdated.ProxyRunning("v2.10")
+		unknown := fixture.Target("http://unknown.localhost", true)
+		upToDate := fixture.Target("http://uptodate.localhost", true)
+		upToDate.ProxyRunning("v2.11")
+		configuring := fixture.Target("http://configuring.localhost", false)
+		store := memory.NewTargetsStore(&outdated, &unknown, &upToDate, &configuring)
+		uc := queue_proxy_upgrades.Handler(store, store, dummyProvider{})
+
+		_, err := uc(context.Background(), queue_proxy_upgrades.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &outdated, 4)
+		testutil.EventIs[domain.TargetProxyUpgradeRequested](t, &outdated, 3)
+		testutil.HasNEvents(t, &unknown, 3)
+		testutil.EventIs[domain.TargetProxyUpgradeRequested](t, &unknown, 2)
+		testutil.HasNEvents(t, &upToDate, 3)
+		testutil.HasNEvents(t, &configuring, 1)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+}
+
+func (dummyProvider) ProxyVersion(domain.Target) (string, error) {
+	return "v2.11", nil
+}
@@ -3,8 +3,10 @@ package reconfigure_target
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
 type Command struct {
@@ -13,23 +15,27 @@ type Command struct {
 	ID string `json:"id"`
 }
 
-func (Command) Name_() string { return "deployment.command.reconfigure_target" }
+func (Command) Name_() string              { return "deployment.command.reconfigure_target" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.TargetsReader,
 	writer domain.TargetsWriter,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
-		target, err := reader.GetByID(ctx, domain.TargetID(cmd.ID))
-
-		if err != nil {
-			return bus.Unit, err
-		}
-
-		if err = target.Reconfigure(); err != nil {
-			return bus.Unit, err
-		}
-
-		return bus.Unit, writer.Write(ctx, &target)
+		return monad.Bind(
+			monad.ResultFrom(reader.GetByID(ctx, domain.TargetID(cmd.ID))),
+			func(target domain.Target) monad.Result[bus.UnitType] {
+				if err := auth.Authorize(ctx, auth.PermissionManage, target.CreatedBy(), target.Resources()...); err != nil {
+					return monad.Fail[bus.UnitType](err)
+				}
+
+				if err := target.Reconfigure(); err != nil {
+					return monad.Fail[bus.UnitType](err)
+				}
+
+				return monad.ResultFrom(bus.Unit, writer.Write(ctx, &target))
+			},
+		).Get()
 	}
 }
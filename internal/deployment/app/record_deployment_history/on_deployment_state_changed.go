@@ -0,0 +1,40 @@
+package record_deployment_history
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Keeps the outcome of every ended deployment in the deployments history used to
+// compute deployment metrics.
+func OnDeploymentStateChangedHandler(
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+	writer domain.DeploymentHistoryWriter,
+) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		if !evt.State.FinishedAt().HasValue() {
+			return nil
+		}
+
+		depl, err := reader.GetByID(ctx, evt.ID)
+
+		if err != nil {
+			return err
+		}
+
+		// Logs may not be available, for example when the build directory could not be
+		// prepared, the entry is still recorded without steps durations
+		records, _ := artifactManager.Records(ctx, depl)
+
+		entry, err := domain.NewDeploymentHistoryEntry(depl, domain.GroupDeploymentLogRecords(records))
+
+		if err != nil {
+			return err
+		}
+
+		return writer.Write(ctx, &entry)
+	}
+}
@@ -0,0 +1,16 @@
+package record_deployment_transition
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnDeploymentCreatedHandler(writer domain.DeploymentTransitionsWriter) bus.SignalHandler[domain.DeploymentCreated] {
+	return func(ctx context.Context, evt domain.DeploymentCreated) error {
+		transition := domain.NewDeploymentTransition(evt.ID, domain.DeploymentTransitionQueued, evt.Requested.At())
+
+		return writer.Write(ctx, &transition)
+	}
+}
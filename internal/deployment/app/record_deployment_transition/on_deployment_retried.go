@@ -0,0 +1,16 @@
+package record_deployment_transition
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnDeploymentRetriedHandler(writer domain.DeploymentTransitionsWriter) bus.SignalHandler[domain.DeploymentRetried] {
+	return func(ctx context.Context, evt domain.DeploymentRetried) error {
+		transition := domain.NewDeploymentTransition(evt.ID, domain.DeploymentTransitionQueued, evt.Retried.At())
+
+		return writer.Write(ctx, &transition)
+	}
+}
@@ -0,0 +1,26 @@
+package record_deployment_transition
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnDeploymentStateChangedHandler(writer domain.DeploymentTransitionsWriter) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		at, isSet := evt.State.FinishedAt().TryGet()
+
+		if !isSet {
+			at, isSet = evt.State.StartedAt().TryGet()
+		}
+
+		if !isSet {
+			return nil
+		}
+
+		transition := domain.NewDeploymentTransition(evt.ID, domain.DeploymentTransitionStateFrom(evt.State), at)
+
+		return writer.Write(ctx, &transition)
+	}
+}
@@ -0,0 +1,21 @@
+package record_env_revision
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+func OnAppCreatedHandler(writer domain.EnvRevisionsWriter) bus.SignalHandler[domain.AppCreated] {
+	return func(ctx context.Context, evt domain.AppCreated) error {
+		createdBy := monad.Value(evt.Created.By())
+
+		if err := record(ctx, writer, evt.ID, domain.Production, domain.EnvironmentConfig{}, evt.Production, createdBy); err != nil {
+			return err
+		}
+
+		return record(ctx, writer, evt.ID, domain.Staging, domain.EnvironmentConfig{}, evt.Staging, createdBy)
+	}
+}
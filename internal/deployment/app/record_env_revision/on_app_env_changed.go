@@ -0,0 +1,15 @@
+package record_env_revision
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAppEnvChangedHandler(writer domain.EnvRevisionsWriter) bus.SignalHandler[domain.AppEnvChanged] {
+	return func(ctx context.Context, evt domain.AppEnvChanged) error {
+		return record(ctx, writer, evt.ID, evt.Environment, evt.OldConfig, evt.Config, auth.CurrentUser(ctx))
+	}
+}
@@ -0,0 +1,31 @@
+package record_env_revision
+
+import (
+	"context"
+	"errors"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+func record(
+	ctx context.Context,
+	writer domain.EnvRevisionsWriter,
+	app domain.AppID,
+	env domain.Environment,
+	previous, current domain.EnvironmentConfig,
+	createdBy monad.Maybe[auth.UserID],
+) error {
+	revision, err := domain.NewEnvRevision(app, env, previous, current, createdBy)
+
+	if errors.Is(err, domain.ErrEnvVarsUnchanged) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, &revision)
+}
@@ -16,7 +16,8 @@ type Command struct {
 	DeploymentNumber int    `json:"-"`
 }
 
-func (Command) Name_() string { return "deployment.command.redeploy" }
+func (Command) Name_() string              { return "deployment.command.redeploy" }
+func (c Command) AuditResource(any) string { return c.AppID }
 
 func Handler(
 	appsReader domain.AppsReader,
@@ -30,6 +31,10 @@ func Handler(
 			return 0, err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return 0, err
+		}
+
 		sourceDeployment, err := reader.GetByID(ctx, domain.DeploymentIDFrom(app.ID(), domain.DeploymentNumber(cmd.DeploymentNumber)))
 
 		if err != nil {
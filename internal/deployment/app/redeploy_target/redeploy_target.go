@@ -0,0 +1,142 @@
+package redeploy_target
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type (
+	// Queue a redeployment of the latest successful deployment of every app environment
+	// on the given target, in the order of their dependencies. Used to apply a change
+	// of the target configuration to apps already running on it. When labels are given,
+	// only apps having all of them are redeployed.
+	Command struct {
+		bus.Command[[]Deployment]
+
+		ID     string   `json:"-"`
+		Labels []string `json:"labels"`
+	}
+
+	Deployment struct {
+		AppID            string `json:"app_id"`
+		DeploymentNumber int    `json:"deployment_number"`
+	}
+)
+
+func (Command) Name_() string              { return "deployment.command.redeploy_target" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	targetsReader domain.TargetsReader,
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[[]Deployment, Command] {
+	return func(ctx context.Context, cmd Command) ([]Deployment, error) {
+		var labels domain.Labels
+
+		if err := validate.Struct(validate.Of{
+			"labels": validate.Value(cmd.Labels, &labels, domain.LabelsFrom),
+		}); err != nil {
+			return nil, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, target.CreatedBy(), target.Resources()...); err != nil {
+			return nil, err
+		}
+
+		apps, err := appsReader.GetAppsOnTarget(ctx, target.ID())
+
+		if err != nil {
+			return nil, err
+		}
+
+		graph, err := appsReader.GetDependencyGraph(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		appsByID := make(map[domain.AppID]domain.App, len(apps))
+		ids := make([]domain.AppID, 0, len(apps))
+
+		for _, app := range apps {
+			if !app.Labels().Contains(labels) {
+				continue
+			}
+
+			appsByID[app.ID()] = app
+			ids = append(ids, app.ID())
+		}
+
+		var (
+			now    = time.Now()
+			result = make([]Deployment, 0, len(apps))
+		)
+
+		for _, id := range graph.Sort(ids) {
+			app := appsByID[id]
+
+			for _, env := range []domain.Environment{domain.Production, domain.Staging} {
+				config := app.Production()
+
+				if !env.IsProduction() {
+					config = app.Staging()
+				}
+
+				if config.Target() != target.ID() {
+					continue
+				}
+
+				source, err := reader.GetDeploymentRunningAt(ctx, app.ID(), env, now)
+
+				if err != nil {
+					// Never deployed successfully, nothing to redeploy
+					if errors.Is(err, apperr.ErrNotFound) {
+						continue
+					}
+
+					return nil, err
+				}
+
+				number, err := reader.GetNextDeploymentNumber(ctx, app.ID())
+
+				if err != nil {
+					return nil, err
+				}
+
+				depl, err := app.Redeploy(source, number, auth.CurrentUser(ctx).MustGet())
+
+				// Same as when the environment of an app changes, a deployment which could not be
+				// redeployed anymore is skipped
+				if err != nil {
+					continue
+				}
+
+				if err = writer.Write(ctx, &depl); err != nil {
+					return nil, err
+				}
+
+				result = append(result, Deployment{
+					AppID:            string(app.ID()),
+					DeploymentNumber: int(number),
+				})
+			}
+		}
+
+		return result, nil
+	}
+}
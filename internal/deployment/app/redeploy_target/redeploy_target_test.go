@@ -0,0 +1,128 @@
+package redeploy_target_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy_target"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	targets     []*domain.Target
+	apps        []*domain.App
+	deployments []*domain.Deployment
+}
+
+func Test_RedeployTarget(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	sut := func(data initialData) bus.RequestHandler[[]redeploy_target.Deployment, redeploy_target.Command] {
+		targetsStore := memory.NewTargetsStore(data.targets...)
+		appsStore := memory.NewAppsStore(data.apps...)
+		deploymentsStore := memory.NewDeploymentsStore(data.deployments...)
+		return redeploy_target.Handler(targetsStore, appsStore, deploymentsStore, deploymentsStore)
+	}
+
+	t.Run("should fail if the target does not exist", func(t *testing.T) {
+		uc := sut(initialData{})
+
+		_, err := uc(ctx, redeploy_target.Command{ID: "some-target"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should redeploy the latest successful deployment of every app environment on the target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false)
+		other := fixture.Target("http://other.localhost", false)
+		app := createApp("my-app", target.ID(), other.ID())
+		productionSucceeded := createDeployment(app, 1, domain.Production, nil)
+		productionFailed := createDeployment(app, 2, domain.Production, errors.New("some error"))
+		stagingSucceeded := createDeployment(app, 3, domain.Staging, nil)
+
+		uc := sut(initialData{
+			targets:     []*domain.Target{&target, &other},
+			apps:        []*domain.App{&app},
+			deployments: []*domain.Deployment{&productionSucceeded, &productionFailed, &stagingSucceeded},
+		})
+
+		deployments, err := uc(ctx, redeploy_target.Command{ID: string(target.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []redeploy_target.Deployment{
+			{AppID: string(app.ID()), DeploymentNumber: 4},
+		}, deployments)
+	})
+
+	t.Run("should only redeploy apps having every given label", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false)
+		api := createApp("api", target.ID(), target.ID())
+		db := createApp("db", target.ID(), target.ID())
+		testutil.IsNil(t, api.HasLabels(domain.Labels{"stack:go", "team-a"}))
+		testutil.IsNil(t, db.HasLabels(domain.Labels{"team-a"}))
+		apiDeployment := createDeployment(api, 1, domain.Production, nil)
+		dbDeployment := createDeployment(db, 1, domain.Production, nil)
+
+		uc := sut(initialData{
+			targets:     []*domain.Target{&target},
+			apps:        []*domain.App{&api, &db},
+			deployments: []*domain.Deployment{&apiDeployment, &dbDeployment},
+		})
+
+		deployments, err := uc(ctx, redeploy_target.Command{
+			ID:     string(target.ID()),
+			Labels: []string{"team-a", "stack:go"},
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []redeploy_target.Deployment{
+			{AppID: string(api.ID()), DeploymentNumber: 2},
+		}, deployments)
+	})
+
+	t.Run("should redeploy dependencies first", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false)
+		api := createApp("api", target.ID(), target.ID())
+		db := createApp("db", target.ID(), target.ID())
+		testutil.IsNil(t, api.DependsOn(domain.AppDependencyGraph{api.ID(): nil, db.ID(): nil}, domain.AppDependencies{db.ID()}))
+		apiDeployment := createDeployment(api, 1, domain.Production, nil)
+		dbDeployment := createDeployment(db, 1, domain.Production, nil)
+
+		uc := sut(initialData{
+			targets:     []*domain.Target{&target},
+			apps:        []*domain.App{&api, &db},
+			deployments: []*domain.Deployment{&apiDeployment, &dbDeployment},
+		})
+
+		deployments, err := uc(ctx, redeploy_target.Command{ID: string(target.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []redeploy_target.Deployment{
+			{AppID: string(db.ID()), DeploymentNumber: 2},
+			{AppID: string(api.ID()), DeploymentNumber: 2},
+		}, deployments)
+	})
+}
+
+func createApp(name domain.AppName, production, staging domain.TargetID) domain.App {
+	return must.Panic(domain.NewApp(name,
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(production), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(staging), true, true), "some-uid"))
+}
+
+func createDeployment(app domain.App, number domain.DeploymentNumber, env domain.Environment, deployErr error) domain.Deployment {
+	depl := must.Panic(app.NewDeployment(number, raw.Data(""), env, "some-uid"))
+	depl.HasStarted()
+	depl.HasEnded(domain.Services{}, deployErr)
+
+	return depl
+}
@@ -0,0 +1,61 @@
+package request_addon_backup
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Start a backup of an add-on right now, without waiting for the scheduled one.
+type Command struct {
+	bus.Command[string]
+
+	AppID   string `json:"-"`
+	AddonID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.request_addon_backup" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	addonsReader domain.AddonsReader,
+	writer domain.AddonBackupsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		addon, err := addonsReader.GetByID(ctx, domain.AddonID(cmd.AddonID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if addon.AppID() != domain.AppID(cmd.AppID) {
+			return "", apperr.ErrNotFound
+		}
+
+		app, err := appsReader.GetByID(ctx, addon.AppID())
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
+		backup, err := domain.NewAddonBackup(addon)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &backup); err != nil {
+			return "", err
+		}
+
+		return string(backup.ID()), nil
+	}
+}
@@ -0,0 +1,73 @@
+package request_addon_backup_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RequestAddonBackup(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	sut := func(existingAddons ...*domain.Addon) (bus.RequestHandler[string, request_addon_backup.Command], memory.AddonBackupsStore) {
+		store := memory.NewAddonBackupsStore()
+		return request_addon_backup.Handler(memory.NewAppsStore(&app), memory.NewAddonsStore(existingAddons...), store), store
+	}
+	newAddon := func(kind domain.AddonKind) domain.Addon {
+		addon := must.Panic(domain.NewAddon(app, domain.Production, kind, monad.None[string](), nil, "some-uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		return addon
+	}
+
+	t.Run("should fail if the add-on does not belong to the given app", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		uc, _ := sut(&addon)
+
+		_, err := uc(ctx, request_addon_backup.Command{AppID: "another-app", AddonID: string(addon.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		addon := newAddon(domain.AddonPostgres)
+		uc, _ := sut(&addon)
+
+		_, err := uc(auth.WithUser(context.Background(), user), request_addon_backup.Command{AppID: string(app.ID()), AddonID: string(addon.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if the add-on kind could not be backed up", func(t *testing.T) {
+		addon := newAddon(domain.AddonRedis)
+		uc, _ := sut(&addon)
+
+		_, err := uc(ctx, request_addon_backup.Command{AppID: string(app.ID()), AddonID: string(addon.ID())})
+
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSupported, err)
+	})
+
+	t.Run("should start a backup of the add-on", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		uc, store := sut(&addon)
+
+		id, err := uc(ctx, request_addon_backup.Command{AppID: string(app.ID()), AddonID: string(addon.ID())})
+
+		testutil.IsNil(t, err)
+		backup := must.Panic(store.GetByID(ctx, domain.AddonBackupID(id)))
+		testutil.Equals(t, addon.ID(), backup.AddonID())
+		testutil.Equals(t, domain.AddonBackupStatusRunning, backup.Status())
+	})
+}
@@ -0,0 +1,31 @@
+package request_addon_cleanup
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an application is being deleted, remove its add-ons too.
+func OnAppCleanupRequestedHandler(
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+) bus.SignalHandler[domain.AppCleanupRequested] {
+	return func(ctx context.Context, evt domain.AppCleanupRequested) error {
+		addons, err := reader.GetByApp(ctx, evt.ID)
+
+		if err != nil {
+			return err
+		}
+
+		updated := make([]*domain.Addon, len(addons))
+
+		for i := range addons {
+			addons[i].RequestCleanup(evt.Requested.By())
+			updated[i] = &addons[i]
+		}
+
+		return writer.Write(ctx, updated...)
+	}
+}
@@ -0,0 +1,53 @@
+package request_addon_cleanup
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Mark an add-on for deletion, its service and data will be removed from its target.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID string `json:"-"`
+	ID    string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.request_addon_cleanup" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.AddonsReader,
+	writer domain.AddonsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		addon, err := reader.GetByID(ctx, domain.AddonID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if addon.AppID() != domain.AppID(cmd.AppID) {
+			return bus.Unit, apperr.ErrNotFound
+		}
+
+		app, err := appsReader.GetByID(ctx, addon.AppID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		addon.RequestCleanup(auth.CurrentUser(ctx).MustGet())
+
+		return bus.Unit, writer.Write(ctx, &addon)
+	}
+}
@@ -0,0 +1,67 @@
+package request_addon_cleanup_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RequestAddonCleanup(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	sut := func(existingAddons ...*domain.Addon) bus.RequestHandler[bus.UnitType, request_addon_cleanup.Command] {
+		store := memory.NewAddonsStore(existingAddons...)
+		return request_addon_cleanup.Handler(memory.NewAppsStore(&app), store, store)
+	}
+
+	t.Run("should fail if the add-on does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, request_addon_cleanup.Command{AppID: string(app.ID()), ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the add-on does not belong to the given app", func(t *testing.T) {
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "some-uid"))
+		uc := sut(&addon)
+
+		_, err := uc(ctx, request_addon_cleanup.Command{AppID: "another-app", ID: string(addon.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "some-uid"))
+		uc := sut(&addon)
+
+		_, err := uc(auth.WithUser(context.Background(), user), request_addon_cleanup.Command{AppID: string(app.ID()), ID: string(addon.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should request the add-on cleanup", func(t *testing.T) {
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "some-uid"))
+		uc := sut(&addon)
+
+		_, err := uc(ctx, request_addon_cleanup.Command{AppID: string(app.ID()), ID: string(addon.ID())})
+
+		testutil.IsNil(t, err)
+		requested := testutil.EventIs[domain.AddonCleanupRequested](t, &addon, 1)
+		testutil.Equals(t, addon.ID(), requested.ID)
+		testutil.Equals(t, "some-uid", requested.Requested.By())
+	})
+}
@@ -0,0 +1,64 @@
+package request_addon_restore
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Request the restoration of an add-on backup. The current data of the add-on will be
+// replaced by the backup content.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID   string `json:"-"`
+	AddonID string `json:"-"`
+	ID      string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.request_addon_restore" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	addonsReader domain.AddonsReader,
+	reader domain.AddonBackupsReader,
+	writer domain.AddonBackupsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		backup, err := reader.GetByID(ctx, domain.AddonBackupID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if backup.AppID() != domain.AppID(cmd.AppID) || backup.AddonID() != domain.AddonID(cmd.AddonID) {
+			return bus.Unit, apperr.ErrNotFound
+		}
+
+		app, err := appsReader.GetByID(ctx, backup.AppID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		addon, err := addonsReader.GetByID(ctx, backup.AddonID())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = backup.RequestRestore(addon, auth.CurrentUser(ctx).MustGet()); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &backup)
+	}
+}
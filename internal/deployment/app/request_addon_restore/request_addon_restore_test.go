@@ -0,0 +1,80 @@
+package request_addon_restore_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_restore"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RequestAddonRestore(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "some-uid"))
+	addon.Provisioned(addon.CurrentVersion(), nil)
+	sut := func(existingBackups ...*domain.AddonBackup) bus.RequestHandler[bus.UnitType, request_addon_restore.Command] {
+		store := memory.NewAddonBackupsStore(existingBackups...)
+		return request_addon_restore.Handler(memory.NewAppsStore(&app), memory.NewAddonsStore(&addon), store, store)
+	}
+
+	t.Run("should fail if the backup does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, request_addon_restore.Command{AppID: string(app.ID()), AddonID: string(addon.ID()), ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the backup does not belong to the given add-on", func(t *testing.T) {
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		uc := sut(&backup)
+
+		_, err := uc(ctx, request_addon_restore.Command{AppID: string(app.ID()), AddonID: "another-addon", ID: string(backup.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		backup.Completed(42, nil)
+		uc := sut(&backup)
+
+		_, err := uc(auth.WithUser(context.Background(), user), request_addon_restore.Command{AppID: string(app.ID()), AddonID: string(addon.ID()), ID: string(backup.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if the backup has not succeeded", func(t *testing.T) {
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		uc := sut(&backup)
+
+		_, err := uc(ctx, request_addon_restore.Command{AppID: string(app.ID()), AddonID: string(addon.ID()), ID: string(backup.ID())})
+
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSucceeded, err)
+	})
+
+	t.Run("should request the backup restoration", func(t *testing.T) {
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		backup.Completed(42, nil)
+		uc := sut(&backup)
+
+		_, err := uc(ctx, request_addon_restore.Command{AppID: string(app.ID()), AddonID: string(addon.ID()), ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		requested := testutil.EventIs[domain.AddonRestoreRequested](t, &backup, 2)
+		testutil.Equals(t, backup.ID(), requested.ID)
+		testutil.Equals(t, "some-uid", requested.Restore.Requested().By())
+	})
+}
@@ -8,18 +8,22 @@ import (
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
 
-// Mark the application for deletion.
+// Mark the application for deletion. When Export is set, the application is first
+// exported to an archive and only deleted once it has succeeded.
 type Command struct {
 	bus.Command[bus.UnitType]
 
-	ID string `json:"-"`
+	ID     string `json:"-"`
+	Export bool   `json:"-" form:"export"`
 }
 
-func (Command) Name_() string { return "deployment.command.request_app_cleanup" }
+func (Command) Name_() string              { return "deployment.command.request_app_cleanup" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.AppsReader,
 	writer domain.AppsWriter,
+	archivesWriter domain.AppArchivesWriter,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
 		app, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
@@ -28,6 +32,20 @@ func Handler(
 			return bus.Unit, err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if cmd.Export {
+			archive, err := domain.NewAppArchive(app, auth.CurrentUser(ctx).MustGet())
+
+			if err != nil {
+				return bus.Unit, err
+			}
+
+			return bus.Unit, archivesWriter.Write(ctx, &archive)
+		}
+
 		app.RequestCleanup(auth.CurrentUser(ctx).MustGet())
 
 		return bus.Unit, writer.Write(ctx, &app)
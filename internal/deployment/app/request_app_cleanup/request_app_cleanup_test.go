@@ -16,13 +16,14 @@ import (
 
 func Test_RequestAppCleanup(t *testing.T) {
 	ctx := auth.WithUserID(context.Background(), "some-uid")
-	sut := func(existingApps ...*domain.App) bus.RequestHandler[bus.UnitType, request_app_cleanup.Command] {
+	sut := func(existingApps ...*domain.App) (bus.RequestHandler[bus.UnitType, request_app_cleanup.Command], *archivesWriter) {
 		store := memory.NewAppsStore(existingApps...)
-		return request_app_cleanup.Handler(store, store)
+		archives := &archivesWriter{}
+		return request_app_cleanup.Handler(store, store, archives), archives
 	}
 
 	t.Run("should fail if the application does not exist", func(t *testing.T) {
-		uc := sut()
+		uc, _ := sut()
 
 		r, err := uc(ctx, request_app_cleanup.Command{
 			ID: "some-id",
@@ -32,11 +33,27 @@ func Test_RequestAppCleanup(t *testing.T) {
 		testutil.Equals(t, bus.Unit, r)
 	})
 
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleDeployer)
+		uc, _ := sut(&app)
+
+		_, err := uc(auth.WithUser(context.Background(), user), request_app_cleanup.Command{
+			ID: string(app.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.HasNEvents(t, &app, 1)
+	})
+
 	t.Run("should mark an application has ready for deletion", func(t *testing.T) {
 		app := must.Panic(domain.NewApp("my-app",
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
 			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
-		uc := sut(&app)
+		uc, _ := sut(&app)
 
 		r, err := uc(ctx, request_app_cleanup.Command{
 			ID: string(app.ID()),
@@ -47,4 +64,33 @@ func Test_RequestAppCleanup(t *testing.T) {
 
 		testutil.EventIs[domain.AppCleanupRequested](t, &app, 1)
 	})
+
+	t.Run("should export the application before deleting it if asked to", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		uc, archives := sut(&app)
+
+		_, err := uc(ctx, request_app_cleanup.Command{
+			ID:     string(app.ID()),
+			Export: true,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &app, 1)
+		testutil.HasLength(t, archives.written, 1)
+
+		evt := testutil.EventIs[domain.AppArchiveCreated](t, archives.written[0], 0)
+		testutil.Equals(t, app.ID(), evt.AppID)
+		testutil.Equals(t, "some-uid", evt.Requested.By())
+	})
+}
+
+type archivesWriter struct {
+	written []*domain.AppArchive
+}
+
+func (w *archivesWriter) Write(_ context.Context, archives ...*domain.AppArchive) error {
+	w.written = append(w.written, archives...)
+	return nil
 }
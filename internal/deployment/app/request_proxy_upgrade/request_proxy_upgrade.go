@@ -0,0 +1,50 @@
+package request_proxy_upgrade
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Request the upgrade of the proxy running on a target to the version supported by
+// this seelf release.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.request_proxy_upgrade" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.TargetsReader,
+	writer domain.TargetsWriter,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		target, err := reader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, target.CreatedBy(), target.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		version, err := provider.ProxyVersion(target)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = target.RequestProxyUpgrade(version); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &target)
+	}
+}
@@ -0,0 +1,63 @@
+package request_proxy_upgrade_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_proxy_upgrade"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RequestProxyUpgrade(t *testing.T) {
+	sut := func(existingTargets ...*domain.Target) bus.RequestHandler[bus.UnitType, request_proxy_upgrade.Command] {
+		store := memory.NewTargetsStore(existingTargets...)
+		return request_proxy_upgrade.Handler(store, store, dummyProvider{})
+	}
+
+	t.Run("should returns an err if the target does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), request_proxy_upgrade.Command{})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should returns an err if the proxy is already up to date", func(t *testing.T) {
+		target := fixture.Target("http://localhost", true)
+		target.ProxyRunning("v2.11")
+		uc := sut(&target)
+
+		_, err := uc(context.Background(), request_proxy_upgrade.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.ErrorIs(t, domain.ErrProxyUpToDate, err)
+	})
+
+	t.Run("should request the proxy upgrade of the target", func(t *testing.T) {
+		target := fixture.Target("http://localhost", true)
+		uc := sut(&target)
+
+		_, err := uc(context.Background(), request_proxy_upgrade.Command{
+			ID: string(target.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &target, 3)
+		requested := testutil.EventIs[domain.TargetProxyUpgradeRequested](t, &target, 2)
+		testutil.Equals(t, "v2.11", requested.Version)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+}
+
+func (dummyProvider) ProxyVersion(domain.Target) (string, error) {
+	return "v2.11", nil
+}
@@ -14,7 +14,8 @@ type Command struct {
 	ID string `json:"id"`
 }
 
-func (Command) Name_() string { return "deployment.command.request_target_cleanup" }
+func (Command) Name_() string              { return "deployment.command.request_target_cleanup" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.TargetsReader,
@@ -28,6 +29,10 @@ func Handler(
 			return bus.Unit, err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionManage, target.CreatedBy(), target.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
 		apps, err := appsReader.HasAppsOnTarget(ctx, target.ID())
 
 		if err != nil {
@@ -0,0 +1,84 @@
+package restart_services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Restart services of the deployment currently running for an application environment
+// without redeploying it. The restart is recorded in the timeline of that deployment.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string   `json:"-"`
+	Environment string   `json:"environment"`
+	Services    []string `json:"services"` // Restart every service of the environment if empty
+}
+
+func (Command) Name_() string              { return "deployment.command.restart_services" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	deploymentsReader domain.DeploymentsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+	transitionsWriter domain.DeploymentTransitionsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var env domain.Environment
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		now := time.Now()
+		depl, err := deploymentsReader.GetDeploymentRunningAt(ctx, app.ID(), env, now)
+
+		if err != nil {
+			// Never deployed successfully, nothing to restart
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, domain.ErrServiceNotRunning
+			}
+
+			return bus.Unit, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, depl.Config().Target())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		services := slices.Clone(cmd.Services)
+		slices.Sort(services)
+
+		if err = provider.Restart(ctx, depl.Config(), target, slices.Compact(services)); err != nil {
+			return bus.Unit, err
+		}
+
+		transition := domain.NewDeploymentTransition(depl.ID(), domain.DeploymentTransitionRestarted, now)
+
+		return bus.Unit, transitionsWriter.Write(ctx, &transition)
+	}
+}
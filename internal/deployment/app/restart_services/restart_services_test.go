@@ -0,0 +1,184 @@
+package restart_services_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restart_services"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type initialData struct {
+	apps        []*domain.App
+	targets     []*domain.Target
+	deployments []*domain.Deployment
+}
+
+func Test_RestartServices(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(provider domain.Provider, data initialData) (bus.RequestHandler[bus.UnitType, restart_services.Command], *dummyTransitionsWriter) {
+		writer := &dummyTransitionsWriter{}
+		return restart_services.Handler(
+			memory.NewAppsStore(data.apps...),
+			memory.NewDeploymentsStore(data.deployments...),
+			memory.NewTargetsStore(data.targets...),
+			provider,
+			writer,
+		), writer
+	}
+	newTarget := func() domain.Target {
+		return must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+	}
+	newApp := func(target domain.Target, uid auth.UserID) domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), uid))
+	}
+	newDeployment := func(app domain.App, number domain.DeploymentNumber, env domain.Environment) domain.Deployment {
+		depl := must.Panic(app.NewDeployment(number, raw.Data(""), env, "some-uid"))
+		depl.HasStarted()
+		depl.HasEnded(domain.Services{}, nil)
+		return depl
+	}
+
+	t.Run("should require a valid environment", func(t *testing.T) {
+		uc, _ := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, restart_services.Command{Environment: "invalid"})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should fail if the user is not allowed to deploy the application", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "another-uid")
+		depl := newDeployment(app, 1, domain.Production)
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		provider := &dummyProvider{}
+		uc, writer := sut(provider, initialData{
+			apps:        []*domain.App{&app},
+			targets:     []*domain.Target{&target},
+			deployments: []*domain.Deployment{&depl},
+		})
+
+		_, err := uc(auth.WithUser(context.Background(), user), restart_services.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.IsFalse(t, provider.called)
+		testutil.HasLength(t, writer.transitions, 0)
+	})
+
+	t.Run("should fail if the environment has never been deployed successfully", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "some-uid")
+		depl := newDeployment(app, 1, domain.Staging)
+		provider := &dummyProvider{}
+		uc, _ := sut(provider, initialData{
+			apps:        []*domain.App{&app},
+			targets:     []*domain.Target{&target},
+			deployments: []*domain.Deployment{&depl},
+		})
+
+		_, err := uc(ctx, restart_services.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+		})
+
+		testutil.ErrorIs(t, domain.ErrServiceNotRunning, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should not record the restart if it failed on the target", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "some-uid")
+		depl := newDeployment(app, 1, domain.Production)
+		provider := &dummyProvider{err: domain.ErrServiceNotRunning}
+		uc, writer := sut(provider, initialData{
+			apps:        []*domain.App{&app},
+			targets:     []*domain.Target{&target},
+			deployments: []*domain.Deployment{&depl},
+		})
+
+		_, err := uc(ctx, restart_services.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Services:    []string{"unknown"},
+		})
+
+		testutil.ErrorIs(t, domain.ErrServiceNotRunning, err)
+		testutil.HasLength(t, writer.transitions, 0)
+	})
+
+	t.Run("should restart services of the running deployment and record it in its timeline", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "some-uid")
+		first := newDeployment(app, 1, domain.Production)
+		running := newDeployment(app, 2, domain.Production)
+		provider := &dummyProvider{}
+		uc, writer := sut(provider, initialData{
+			apps:        []*domain.App{&app},
+			targets:     []*domain.Target{&target},
+			deployments: []*domain.Deployment{&first, &running},
+		})
+
+		_, err := uc(ctx, restart_services.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Services:    []string{"worker", "app", "worker"},
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, target.ID(), provider.target)
+		testutil.Equals(t, running.Config().ProjectName(), provider.project)
+		testutil.DeepEquals(t, []string{"app", "worker"}, provider.services)
+		testutil.HasLength(t, writer.transitions, 1)
+		testutil.Equals(t, running.ID(), writer.transitions[0].DeploymentID())
+		testutil.Equals(t, domain.DeploymentTransitionRestarted, writer.transitions[0].State())
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	err      error
+	called   bool
+	target   domain.TargetID
+	project  string
+	services []string
+}
+
+func (p *dummyProvider) Restart(
+	_ context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	services []string,
+) error {
+	p.called = true
+	p.target = target.ID()
+	p.project = config.ProjectName()
+	p.services = services
+
+	return p.err
+}
+
+type dummyTransitionsWriter struct {
+	transitions []*domain.DeploymentTransition
+}
+
+func (w *dummyTransitionsWriter) Write(_ context.Context, transitions ...*domain.DeploymentTransition) error {
+	w.transitions = append(w.transitions, transitions...)
+	return nil
+}
@@ -0,0 +1,18 @@
+package restore_addon_backup
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnAddonRestoreRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AddonRestoreRequested] {
+	return func(ctx context.Context, evt domain.AddonRestoreRequested) error {
+		return scheduler.Queue(ctx, Command{
+			ID:    string(evt.ID),
+			AppID: string(evt.AppID),
+		}, bus.WithGroup(app.AddonBackupGroup(evt.AddonID)))
+	}
+}
@@ -0,0 +1,106 @@
+package restore_addon_backup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Replace the data of an add-on by the content of one of its backups.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID    string `json:"id"`
+	AppID string `json:"app_id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.restore_addon_backup" }
+func (c Command) ResourceID() string { return c.AppID }
+
+func Handler(
+	reader domain.AddonBackupsReader,
+	writer domain.AddonBackupsWriter,
+	addonsReader domain.AddonsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+	storage domain.AddonBackupsStorage,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		backup, err := reader.GetByID(ctx, domain.AddonBackupID(cmd.ID))
+
+		if err != nil {
+			// Backup not found, its add-on has been deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		if !backup.IsRestoring() {
+			return bus.Unit, nil
+		}
+
+		addon, err := addonsReader.GetByID(ctx, backup.AddonID())
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		// The add-on may have been moved or requested for cleanup since
+		restoreErr := addon.CanBeBackedUp()
+
+		if restoreErr == nil {
+			var target domain.Target
+
+			target, restoreErr = targetsReader.GetByID(ctx, addon.Target())
+
+			if restoreErr != nil && !errors.Is(restoreErr, apperr.ErrNotFound) {
+				return bus.Unit, restoreErr
+			}
+
+			if restoreErr == nil {
+				restoreErr = target.CheckAvailability()
+
+				// Target configuration is in progress, just retry the job later
+				if errors.Is(restoreErr, domain.ErrTargetConfigurationInProgress) {
+					return bus.Unit, restoreErr
+				}
+			}
+
+			if restoreErr == nil {
+				restoreErr = restore(ctx, storage, provider, target, addon, backup)
+			}
+		}
+
+		backup.Restored(restoreErr)
+
+		return bus.Unit, writer.Write(ctx, &backup)
+	}
+}
+
+func restore(
+	ctx context.Context,
+	storage domain.AddonBackupsStorage,
+	provider domain.Provider,
+	target domain.Target,
+	addon domain.Addon,
+	backup domain.AddonBackup,
+) error {
+	content, err := storage.Open(ctx, backup)
+
+	if err != nil {
+		return err
+	}
+
+	defer content.Close()
+
+	return provider.RestoreAddon(ctx, target, addon, content)
+}
@@ -0,0 +1,154 @@
+package restore_addon_backup_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	backups []*domain.AddonBackup
+	addons  []*domain.Addon
+	targets []*domain.Target
+}
+
+func Test_RestoreAddonBackup(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData, restoreErr error) (bus.RequestHandler[bus.UnitType, restore_addon_backup.Command], memory.AddonBackupsStore, *dummyProvider) {
+		store := memory.NewAddonBackupsStore(data.backups...)
+		provider := &dummyProvider{err: restoreErr}
+		return restore_addon_backup.Handler(store, store, memory.NewAddonsStore(data.addons...), memory.NewTargetsStore(data.targets...),
+			provider, &dummyStorage{}), store, provider
+	}
+
+	newTarget := func() domain.Target {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		return target
+	}
+
+	newAddon := func(target domain.TargetID) domain.Addon {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true), "uid"))
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		return addon
+	}
+
+	newRestoringBackup := func(addon domain.Addon) domain.AddonBackup {
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		backup.Completed(42, nil)
+		testutil.IsNil(t, backup.RequestRestore(addon, "uid"))
+		return backup
+	}
+
+	t.Run("should fail silently if the backup does not exist anymore", func(t *testing.T) {
+		uc, _, provider := sut(initialData{}, nil)
+
+		r, err := uc(ctx, restore_addon_backup.Command{ID: "some-id"})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, bus.Unit, r)
+		testutil.Equals(t, "", provider.restored)
+	})
+
+	t.Run("should mark the restoration as failed if the add-on is not ready anymore", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		backup := newRestoringBackup(addon)
+		addon.RequestCleanup("uid")
+		uc, store, provider := sut(initialData{
+			backups: []*domain.AddonBackup{&backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, nil)
+
+		_, err := uc(ctx, restore_addon_backup.Command{ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "", provider.restored)
+		backup = must.Panic(store.GetByID(ctx, backup.ID()))
+		testutil.IsFalse(t, backup.IsRestoring())
+	})
+
+	t.Run("should mark the restoration as failed if the provider fails", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		backup := newRestoringBackup(addon)
+		uc, store, _ := sut(initialData{
+			backups: []*domain.AddonBackup{&backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, errors.New("some_error"))
+
+		_, err := uc(ctx, restore_addon_backup.Command{ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		backup = must.Panic(store.GetByID(ctx, backup.ID()))
+		testutil.IsFalse(t, backup.IsRestoring())
+		finished := testutil.EventIs[domain.AddonRestoreFinished](t, &backup, 3)
+		testutil.Equals(t, "some_error", finished.Restore.ErrCode().MustGet())
+	})
+
+	t.Run("should restore the backup content in the add-on", func(t *testing.T) {
+		target := newTarget()
+		addon := newAddon(target.ID())
+		backup := newRestoringBackup(addon)
+		uc, store, provider := sut(initialData{
+			backups: []*domain.AddonBackup{&backup},
+			addons:  []*domain.Addon{&addon},
+			targets: []*domain.Target{&target},
+		}, nil)
+
+		_, err := uc(ctx, restore_addon_backup.Command{ID: string(backup.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, dump, provider.restored)
+		backup = must.Panic(store.GetByID(ctx, backup.ID()))
+		testutil.IsFalse(t, backup.IsRestoring())
+		finished := testutil.EventIs[domain.AddonRestoreFinished](t, &backup, 3)
+		testutil.IsFalse(t, finished.Restore.ErrCode().HasValue())
+	})
+}
+
+const dump = "CREATE TABLE some_table;"
+
+type (
+	dummyProvider struct {
+		domain.Provider
+		err      error
+		restored string
+	}
+
+	dummyStorage struct {
+		domain.AddonBackupsStorage
+	}
+)
+
+func (d *dummyProvider) RestoreAddon(_ context.Context, _ domain.Target, _ domain.Addon, r io.Reader) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	content, err := io.ReadAll(r)
+	d.restored = string(content)
+	return err
+}
+
+func (d *dummyStorage) Open(context.Context, domain.AddonBackup) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(dump)), nil
+}
@@ -0,0 +1,101 @@
+package restore_app
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Recreate a deleted application from the trash, as long as its name is still available
+// on its targets. When Redeploy is set, the last successful deployment of each environment
+// is deployed again. Returns the identifier of the recreated application.
+type Command struct {
+	bus.Command[string]
+
+	ID       string `json:"-"`
+	Redeploy bool   `json:"redeploy"`
+}
+
+func (Command) Name_() string              { return "deployment.command.restore_app" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.TrashedAppsReader,
+	writer domain.TrashedAppsWriter,
+	appsReader domain.AppsReader,
+	appsWriter domain.AppsWriter,
+	deploymentsReader domain.DeploymentsReader,
+	deploymentsWriter domain.DeploymentsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		trashed, err := reader.GetByID(ctx, domain.AppID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, trashed.CreatedBy(), trashed.Resources()...); err != nil {
+			return "", err
+		}
+
+		productionRequirement, stagingRequirement, err := appsReader.CheckAppNamingAvailability(
+			ctx,
+			trashed.Name(),
+			trashed.Production(),
+			trashed.Staging(),
+		)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = validate.Struct(validate.Of{
+			"production.target": productionRequirement.Error(),
+			"staging.target":    stagingRequirement.Error(),
+		}); err != nil {
+			return "", err
+		}
+
+		user := auth.CurrentUser(ctx).MustGet()
+		app, err := trashed.Restore(productionRequirement, stagingRequirement, user)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = appsWriter.Write(ctx, &app); err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &trashed); err != nil {
+			return "", err
+		}
+
+		if !cmd.Redeploy {
+			return string(app.ID()), nil
+		}
+
+		for _, trashedDeployment := range trashed.Deployments() {
+			number, err := deploymentsReader.GetNextDeploymentNumber(ctx, app.ID())
+
+			if err != nil {
+				return "", err
+			}
+
+			deployment, err := trashedDeployment.Redeploy(app, number, user)
+
+			if err != nil {
+				return "", err
+			}
+
+			if err = deploymentsWriter.Write(ctx, &deployment); err != nil {
+				return "", err
+			}
+		}
+
+		return string(app.ID()), nil
+	}
+}
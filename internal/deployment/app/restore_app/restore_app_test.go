@@ -0,0 +1,126 @@
+package restore_app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type initialData struct {
+	apps    []*domain.App
+	trashed []*domain.TrashedApp
+}
+
+func Test_RestoreApp(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(data initialData) (bus.RequestHandler[string, restore_app.Command], memory.AppsStore, memory.DeploymentsStore) {
+		trashStore := memory.NewTrashedAppsStore(data.trashed...)
+		appsStore := memory.NewAppsStore(data.apps...)
+		deploymentsStore := memory.NewDeploymentsStore()
+		return restore_app.Handler(trashStore, trashStore, appsStore, appsStore, deploymentsStore, deploymentsStore), appsStore, deploymentsStore
+	}
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+
+	newTrashedApp := func() domain.TrashedApp {
+		app := newApp()
+		deployment := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+		deployment.HasStarted()
+		deployment.HasEnded(domain.Services{}, nil)
+		app.RequestCleanup("some-uid")
+		return must.Panic(domain.NewTrashedApp(app, []domain.Deployment{deployment}, time.Hour))
+	}
+
+	t.Run("should fail if the trashed app does not exist", func(t *testing.T) {
+		uc, _, _ := sut(initialData{})
+
+		_, err := uc(ctx, restore_app.Command{
+			ID: "some-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		trashed := newTrashedApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(trashed.ID())), true), auth.RoleReadOnly)
+		uc, _, _ := sut(initialData{trashed: []*domain.TrashedApp{&trashed}})
+
+		_, err := uc(auth.WithUser(context.Background(), user), restore_app.Command{
+			ID: string(trashed.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should fail if the name is not available anymore", func(t *testing.T) {
+		trashed := newTrashedApp()
+		existing := newApp()
+		uc, _, _ := sut(initialData{
+			apps:    []*domain.App{&existing},
+			trashed: []*domain.TrashedApp{&trashed},
+		})
+
+		_, err := uc(ctx, restore_app.Command{
+			ID: string(trashed.ID()),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+	})
+
+	t.Run("should restore the application", func(t *testing.T) {
+		trashed := newTrashedApp()
+		uc, appsStore, deploymentsStore := sut(initialData{trashed: []*domain.TrashedApp{&trashed}})
+
+		id, err := uc(ctx, restore_app.Command{
+			ID: string(trashed.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", id)
+		testutil.NotEquals(t, string(trashed.ID()), id)
+		testutil.EventIs[domain.TrashedAppRestored](t, &trashed, 1)
+
+		app, err := appsStore.GetByID(ctx, domain.AppID(id))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "1", app.Production().Target())
+
+		deployments, err := deploymentsStore.GetByApp(ctx, domain.AppID(id))
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, deployments, 0)
+	})
+
+	t.Run("should redeploy the last successful deployments if asked to", func(t *testing.T) {
+		trashed := newTrashedApp()
+		uc, _, deploymentsStore := sut(initialData{trashed: []*domain.TrashedApp{&trashed}})
+
+		id, err := uc(ctx, restore_app.Command{
+			ID:       string(trashed.ID()),
+			Redeploy: true,
+		})
+
+		testutil.IsNil(t, err)
+
+		deployments, err := deploymentsStore.GetByApp(ctx, domain.AppID(id))
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, deployments, 1)
+		testutil.Equals(t, domain.Production, deployments[0].Config().Environment())
+		testutil.Equals(t, "some-uid", deployments[0].Requested().By())
+	})
+}
@@ -0,0 +1,51 @@
+package restore_env_revision
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Restore environment variables of an app environment as they were recorded by the
+// given revision.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID string `json:"-"`
+	ID    string `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.restore_env_revision" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.EnvRevisionsReader,
+	appsReader domain.AppsReader,
+	appsWriter domain.AppsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		revision, err := reader.GetByID(ctx, domain.EnvRevisionID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.RestoreEnvRevision(revision); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, appsWriter.Write(ctx, &app)
+	}
+}
@@ -0,0 +1,81 @@
+package restore_env_revision_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_env_revision"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RestoreEnvRevision(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	production := domain.NewEnvironmentConfig("1")
+	production.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+	sut := func(app *domain.App, existingRevisions ...*domain.EnvRevision) bus.RequestHandler[bus.UnitType, restore_env_revision.Command] {
+		store := memory.NewAppsStore(app)
+		return restore_env_revision.Handler(memory.NewEnvRevisionsStore(existingRevisions...), store, store)
+	}
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(production, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	}
+	newRevision := func(app domain.App, vars domain.ServicesEnv) domain.EnvRevision {
+		config := app.Production()
+		config.HasEnvironmentVariables(vars)
+		return must.Panic(domain.NewEnvRevision(app.ID(), domain.Production, app.Production(), config, monad.None[auth.UserID]()))
+	}
+
+	t.Run("should fail if the revision does not exist", func(t *testing.T) {
+		app := newApp()
+		uc := sut(&app)
+
+		_, err := uc(ctx, restore_env_revision.Command{AppID: string(app.ID()), ID: "some-id"})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the revision does not belong to the given app", func(t *testing.T) {
+		app := newApp()
+		other := newApp()
+		revision := newRevision(other, domain.ServicesEnv{"app": {"DEBUG": "false"}})
+		uc := sut(&app, &revision)
+
+		_, err := uc(ctx, restore_env_revision.Command{AppID: string(app.ID()), ID: string(revision.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the user is not allowed to manage the application", func(t *testing.T) {
+		app := newApp()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		revision := newRevision(app, domain.ServicesEnv{"app": {"DEBUG": "false"}})
+		uc := sut(&app, &revision)
+
+		_, err := uc(auth.WithUser(context.Background(), user), restore_env_revision.Command{AppID: string(app.ID()), ID: string(revision.ID())})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should restore the revision variables", func(t *testing.T) {
+		app := newApp()
+		revision := newRevision(app, domain.ServicesEnv{"app": {"DEBUG": "false"}})
+		uc := sut(&app, &revision)
+
+		_, err := uc(ctx, restore_env_revision.Command{AppID: string(app.ID()), ID: string(revision.ID())})
+
+		testutil.IsNil(t, err)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, changed.Environment)
+		testutil.DeepEquals(t, revision.Vars(), changed.Config.Vars())
+	})
+}
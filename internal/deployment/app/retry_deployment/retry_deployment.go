@@ -0,0 +1,69 @@
+package retry_deployment
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Retry a failed deployment, resuming it from the step at which it has failed when its
+// fetched files and built images could be reused.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.retry_deployment" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(app.ID(), domain.DeploymentNumber(cmd.DeploymentNumber)))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		latest, err := reader.GetLastDeployment(ctx, app.ID(), depl.Config().Environment())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Without logs, the failed step could not be determined and the deployment will
+		// be run from scratch
+		var failedStep monad.Maybe[domain.DeploymentStep]
+
+		if records, err := artifactManager.Records(ctx, depl); err == nil {
+			if steps := domain.GroupDeploymentLogRecords(records); len(steps) > 0 {
+				failedStep.Set(steps[len(steps)-1].Step)
+			}
+		}
+
+		if err = app.RetryDeployment(&depl, latest, failedStep, auth.CurrentUser(ctx).MustGet()); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &depl)
+	}
+}
@@ -0,0 +1,105 @@
+package retry_deployment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/retry_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_RetryDeployment(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	appsStore := memory.NewAppsStore(&app)
+
+	sut := func(records []domain.DeploymentLogRecord, existingDeployments ...*domain.Deployment) bus.RequestHandler[bus.UnitType, retry_deployment.Command] {
+		deploymentsStore := memory.NewDeploymentsStore(existingDeployments...)
+		return retry_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, &dummyArtifactManager{records: records})
+	}
+
+	failed := func(number domain.DeploymentNumber) domain.Deployment {
+		depl := must.Panic(app.NewDeployment(number, raw.Data(""), domain.Production, "some-uid"))
+		must.Panic(0, depl.HasStarted())
+		must.Panic(0, depl.HasEnded(nil, errors.New("some_error")))
+		return depl
+	}
+
+	t.Run("should fail if the deployment does not exist", func(t *testing.T) {
+		uc := sut(nil)
+
+		_, err := uc(ctx, retry_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the deployment has been superseded by a newer one", func(t *testing.T) {
+		first := failed(1)
+		second := failed(2)
+		uc := sut(nil, &first, &second)
+
+		_, err := uc(ctx, retry_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+		})
+
+		testutil.ErrorIs(t, domain.ErrDeploymentSuperseded, err)
+	})
+
+	t.Run("should resume the deployment from the step at which it has failed", func(t *testing.T) {
+		depl := failed(1)
+		uc := sut([]domain.DeploymentLogRecord{
+			{Step: domain.DeploymentStepFetch, Message: "fetching"},
+			{Step: domain.DeploymentStepBuild, Message: "building"},
+			{Step: domain.DeploymentStepDeploy, Message: "deploying"},
+		}, &depl)
+
+		_, err := uc(ctx, retry_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentRetried](t, &depl, 3)
+		testutil.Equals(t, domain.DeploymentStepDeploy, evt.ResumeFrom)
+		testutil.Equals(t, "some-uid", evt.Retried.By())
+		changed := testutil.EventIs[domain.DeploymentStateChanged](t, &depl, 4)
+		testutil.Equals(t, domain.DeploymentStatusPending, changed.State.Status())
+	})
+
+	t.Run("should run the deployment from scratch if its logs are not available", func(t *testing.T) {
+		depl := failed(1)
+		uc := sut(nil, &depl)
+
+		_, err := uc(ctx, retry_deployment.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: 1,
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentRetried](t, &depl, 3)
+		testutil.Equals(t, domain.DeploymentStepFetch, evt.ResumeFrom)
+	})
+}
+
+type dummyArtifactManager struct {
+	domain.ArtifactManager
+	records []domain.DeploymentLogRecord
+}
+
+func (m *dummyArtifactManager) Records(context.Context, domain.Deployment) ([]domain.DeploymentLogRecord, error) {
+	return m.records, nil
+}
@@ -0,0 +1,81 @@
+package scale_service
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Change the number of running containers of an application service on its target
+// without redeploying it. The number is kept for future deployments of the environment.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID       string `json:"-"`
+	Environment string `json:"environment"`
+	Service     string `json:"service"`
+	Replicas    uint   `json:"replicas"`
+}
+
+func (Command) Name_() string              { return "deployment.command.scale_service" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.AppsReader,
+	writer domain.AppsWriter,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var (
+			env      domain.Environment
+			replicas uint
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"service":     validate.Field(cmd.Service, strings.Required),
+			"replicas":    validate.Value(cmd.Replicas, &replicas, domain.ReplicasFrom),
+		}); err != nil {
+			return bus.Unit, err
+		}
+
+		app, err := reader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionDeploy, app.CreatedBy(), app.Resources()...); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = app.Scale(env, cmd.Service, replicas); err != nil {
+			return bus.Unit, err
+		}
+
+		config, err := app.ConfigSnapshotFor(env)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, config.Target())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Only persist the scale once applied so a service which is not running on the
+		// target does not end up scaled.
+		if err = provider.Scale(ctx, config, target, cmd.Service, replicas); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &app)
+	}
+}
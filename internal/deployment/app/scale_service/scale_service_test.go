@@ -0,0 +1,143 @@
+package scale_service_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/scale_service"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type initialData struct {
+	apps    []*domain.App
+	targets []*domain.Target
+}
+
+func Test_ScaleService(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	sut := func(provider domain.Provider, data initialData) bus.RequestHandler[bus.UnitType, scale_service.Command] {
+		store := memory.NewAppsStore(data.apps...)
+		return scale_service.Handler(store, store, memory.NewTargetsStore(data.targets...), provider)
+	}
+	newTarget := func() domain.Target {
+		return must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "some-uid"))
+	}
+	newApp := func(target domain.Target, uid auth.UserID) domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), uid))
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := sut(&dummyProvider{}, initialData{})
+
+		_, err := uc(ctx, scale_service.Command{
+			Environment: "invalid",
+			Replicas:    100,
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		fieldErrs, _ := validate.Errors(err)
+		testutil.HasLength(t, fieldErrs, 3)
+	})
+
+	t.Run("should fail if the user is not allowed to deploy the application", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "another-uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceApp, string(app.ID())), true), auth.RoleReadOnly)
+		provider := &dummyProvider{}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		_, err := uc(auth.WithUser(context.Background(), user), scale_service.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Service:     "app",
+			Replicas:    3,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", provider.service)
+	})
+
+	t.Run("should not persist the scale if it could not be applied on the target", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "some-uid")
+		provider := &dummyProvider{err: domain.ErrServiceNotRunning}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, scale_service.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Production),
+			Service:     "app",
+			Replicas:    3,
+		})
+
+		testutil.ErrorIs(t, domain.ErrServiceNotRunning, err)
+		testutil.HasNEvents(t, &app, 1)
+	})
+
+	t.Run("should scale the service on the target and keep it for future deployments", func(t *testing.T) {
+		target := newTarget()
+		app := newApp(target, "some-uid")
+		provider := &dummyProvider{}
+		uc := sut(provider, initialData{
+			apps:    []*domain.App{&app},
+			targets: []*domain.Target{&target},
+		})
+
+		_, err := uc(ctx, scale_service.Command{
+			AppID:       string(app.ID()),
+			Environment: string(domain.Staging),
+			Service:     "worker",
+			Replicas:    3,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, target.ID(), provider.target)
+		testutil.Equals(t, domain.Staging, provider.environment)
+		testutil.Equals(t, "worker", provider.service)
+		testutil.Equals(t, uint(3), provider.replicas)
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.DeepEquals(t, domain.ServicesScale{"worker": 3}, changed.Config.ServicesScale().MustGet())
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	err         error
+	target      domain.TargetID
+	environment domain.Environment
+	service     string
+	replicas    uint
+}
+
+func (p *dummyProvider) Scale(
+	_ context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	service string,
+	replicas uint,
+) error {
+	p.target = target.ID()
+	p.environment = config.Environment()
+	p.service = service
+	p.replicas = replicas
+
+	return p.err
+}
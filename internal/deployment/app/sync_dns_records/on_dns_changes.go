@@ -0,0 +1,41 @@
+package sync_dns_records
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnDeploymentStateChangedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		if !evt.HasSucceeded() {
+			return nil
+		}
+
+		return queue(ctx, scheduler)
+	}
+}
+
+func OnAppCleanupRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.AppCleanupRequested] {
+	return func(ctx context.Context, evt domain.AppCleanupRequested) error {
+		return queue(ctx, scheduler)
+	}
+}
+
+func OnDnsZoneCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DnsZoneCreated] {
+	return func(ctx context.Context, evt domain.DnsZoneCreated) error {
+		return queue(ctx, scheduler)
+	}
+}
+
+func OnDnsZoneAddressesChangedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DnsZoneAddressesChanged] {
+	return func(ctx context.Context, evt domain.DnsZoneAddressesChanged) error {
+		return queue(ctx, scheduler)
+	}
+}
+
+func queue(ctx context.Context, scheduler bus.Scheduler) error {
+	return scheduler.Queue(ctx, Command{}, bus.WithGroup(app.DnsSyncGroup()), bus.WithPolicy(bus.JobPolicyMerge))
+}
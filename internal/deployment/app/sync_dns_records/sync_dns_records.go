@@ -0,0 +1,172 @@
+package sync_dns_records
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Reconcile records of hosts exposed by apps with DNS zones they belong to. Missing or
+// modified records are written again and records of hosts not exposed anymore are removed.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string      { return "deployment.command.sync_dns_records" }
+func (Command) ResourceID() string { return "" }
+
+func Handler(
+	zonesReader domain.DnsZonesReader,
+	reader domain.DnsRecordsReader,
+	writer domain.DnsRecordsWriter,
+	provider domain.DnsProvider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		zones, err := zonesReader.GetAll(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		hosts, err := reader.GetExposedHosts(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		records, err := reader.GetDnsRecords(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			now      = time.Now().UTC()
+			hostErrs []error
+			exposed  = make(map[string]bool, len(hosts))
+			tracked  = make(map[string]domain.DnsRecord, len(records))
+		)
+
+		for _, record := range records {
+			tracked[record.Host] = record
+		}
+
+		for _, host := range hosts {
+			zone, found := domain.DnsZones(zones).For(host.Host)
+
+			if !found || exposed[host.Host] {
+				continue
+			}
+
+			exposed[host.Host] = true
+
+			record := domain.DnsRecord{
+				Host:        host.Host,
+				ZoneID:      zone.ID(),
+				AppID:       host.AppID,
+				Environment: host.Environment,
+				Records:     zone.ExpectedRecords(host.TargetHost),
+				Status:      domain.DnsRecordStatusSynced,
+				CheckedAt:   now,
+			}
+
+			previous, wasTracked := tracked[host.Host]
+
+			if wasTracked {
+				record.DriftedAt = previous.DriftedAt
+			}
+
+			changed, err := reconcile(ctx, provider, zone, host.Host, record.Records)
+
+			if err != nil {
+				record.Status = domain.DnsRecordStatusFailed
+				record.Error.Set(err.Error())
+				hostErrs = append(hostErrs, fmt.Errorf("host %s: %w", host.Host, err))
+			} else if changed && wasTracked && previous.Status == domain.DnsRecordStatusSynced && sameRecords(previous.Records, record.Records) {
+				// Records were in sync and have not been updated by seelf, someone else
+				// has modified them.
+				record.DriftedAt.Set(now)
+			}
+
+			if err = writer.WriteDnsRecord(ctx, record); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		for _, record := range records {
+			if exposed[record.Host] {
+				continue
+			}
+
+			if err = remove(ctx, provider, zones, record); err != nil {
+				hostErrs = append(hostErrs, fmt.Errorf("host %s: %w", record.Host, err))
+				continue
+			}
+
+			if err = writer.DeleteDnsRecord(ctx, record.Host); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, errors.Join(hostErrs...)
+	}
+}
+
+// Makes records of the host match the expected ones and returns true if something
+// had to be changed.
+func reconcile(
+	ctx context.Context,
+	provider domain.DnsProvider,
+	zone domain.DnsZone,
+	host string,
+	expected domain.DnsRecordSets,
+) (bool, error) {
+	actual, err := provider.Records(ctx, zone, host)
+
+	if err != nil {
+		return false, err
+	}
+
+	removed, changed := expected.Diff(actual)
+
+	for _, recordType := range removed {
+		if err = provider.DeleteRecords(ctx, zone, host, recordType); err != nil {
+			return false, err
+		}
+	}
+
+	for _, set := range changed {
+		if err = provider.SetRecords(ctx, zone, host, set); err != nil {
+			return false, err
+		}
+	}
+
+	return len(removed) > 0 || len(changed) > 0, nil
+}
+
+// Removes records managed by seelf for a host which is not exposed anymore. If its zone
+// has been deleted, records are left untouched on the provider.
+func remove(ctx context.Context, provider domain.DnsProvider, zones domain.DnsZones, record domain.DnsRecord) error {
+	for _, zone := range zones {
+		if zone.ID() != record.ZoneID {
+			continue
+		}
+
+		for _, set := range record.Records {
+			if err := provider.DeleteRecords(ctx, zone, record.Host, set.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sameRecords(a, b domain.DnsRecordSets) bool {
+	removed, changed := a.Diff(b)
+	return len(removed) == 0 && len(changed) == 0
+}
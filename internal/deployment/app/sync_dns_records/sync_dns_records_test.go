@@ -0,0 +1,165 @@
+package sync_dns_records_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_dns_records"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_SyncDnsRecords(t *testing.T) {
+	ctx := context.Background()
+	sut := func(
+		zones []*domain.DnsZone,
+		hosts []domain.ExposedHost,
+		records ...domain.DnsRecord,
+	) (bus.RequestHandler[bus.UnitType, sync_dns_records.Command], memory.DnsRecordsStore, *dummyProvider) {
+		provider := &dummyProvider{records: make(map[string]domain.DnsRecordSets)}
+		zonesStore := memory.NewDnsZonesStore(zones...)
+		recordsStore := memory.NewDnsRecordsStore(hosts, records...)
+		return sync_dns_records.Handler(zonesStore, recordsStore, recordsStore, provider), recordsStore, provider
+	}
+
+	t.Run("should ignore hosts outside of managed zones", func(t *testing.T) {
+		zone := createZone(nil)
+		uc, store, provider := sut([]*domain.DnsZone{&zone}, []domain.ExposedHost{
+			{AppID: "app", Environment: domain.Production, Host: "my-app.docker.localhost", TargetHost: "docker.localhost"},
+		})
+
+		_, err := uc(ctx, sync_dns_records.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 0, len(provider.records))
+		records, _ := store.GetDnsRecords(ctx)
+		testutil.HasLength(t, records, 0)
+	})
+
+	t.Run("should create missing records of exposed hosts", func(t *testing.T) {
+		zone := createZone(domain.DnsAddresses{"203.0.113.10"})
+		uc, store, provider := sut([]*domain.DnsZone{&zone}, []domain.ExposedHost{
+			{AppID: "app", Environment: domain.Production, Host: "my-app.example.com", TargetHost: "docker.example.com"},
+		})
+		provider.records["my-app.example.com"] = domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"old.example.com."}},
+		}
+
+		_, err := uc(ctx, sync_dns_records.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"203.0.113.10"}},
+		}, provider.records["my-app.example.com"])
+
+		records, _ := store.GetDnsRecords(ctx)
+		testutil.HasLength(t, records, 1)
+		testutil.Equals(t, zone.ID(), records[0].ZoneID)
+		testutil.Equals(t, domain.DnsRecordStatusSynced, records[0].Status)
+		testutil.IsFalse(t, records[0].DriftedAt.HasValue())
+	})
+
+	t.Run("should restore drifted records", func(t *testing.T) {
+		zone := createZone(nil)
+		expected := domain.DnsRecordSets{{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}}}
+		uc, store, provider := sut([]*domain.DnsZone{&zone}, []domain.ExposedHost{
+			{AppID: "app", Environment: domain.Production, Host: "my-app.example.com", TargetHost: "docker.example.com"},
+		}, domain.DnsRecord{
+			Host:        "my-app.example.com",
+			ZoneID:      zone.ID(),
+			AppID:       "app",
+			Environment: domain.Production,
+			Records:     expected,
+			Status:      domain.DnsRecordStatusSynced,
+			CheckedAt:   time.Now().UTC(),
+		})
+		provider.records["my-app.example.com"] = domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"198.51.100.1"}},
+		}
+
+		_, err := uc(ctx, sync_dns_records.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, expected, provider.records["my-app.example.com"])
+		records, _ := store.GetDnsRecords(ctx)
+		testutil.IsTrue(t, records[0].DriftedAt.HasValue())
+	})
+
+	t.Run("should mark records as failed if the provider returns an error", func(t *testing.T) {
+		zone := createZone(nil)
+		uc, store, provider := sut([]*domain.DnsZone{&zone}, []domain.ExposedHost{
+			{AppID: "app", Environment: domain.Production, Host: "my-app.example.com", TargetHost: "docker.example.com"},
+		})
+		providerErr := errors.New("unauthorized")
+		provider.err = providerErr
+
+		_, err := uc(ctx, sync_dns_records.Command{})
+
+		testutil.ErrorIs(t, providerErr, err)
+		records, _ := store.GetDnsRecords(ctx)
+		testutil.Equals(t, domain.DnsRecordStatusFailed, records[0].Status)
+		testutil.Equals(t, "unauthorized", records[0].Error.Get(""))
+	})
+
+	t.Run("should remove records of hosts not exposed anymore", func(t *testing.T) {
+		zone := createZone(nil)
+		uc, store, provider := sut([]*domain.DnsZone{&zone}, nil, domain.DnsRecord{
+			Host:        "my-app.example.com",
+			ZoneID:      zone.ID(),
+			AppID:       "app",
+			Environment: domain.Production,
+			Records:     domain.DnsRecordSets{{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}}},
+			Status:      domain.DnsRecordStatusSynced,
+			CheckedAt:   time.Now().UTC(),
+		})
+		provider.records["my-app.example.com"] = domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}},
+		}
+
+		_, err := uc(ctx, sync_dns_records.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, provider.records["my-app.example.com"], 0)
+		records, _ := store.GetDnsRecords(ctx)
+		testutil.HasLength(t, records, 0)
+	})
+}
+
+func createZone(addresses domain.DnsAddresses) domain.DnsZone {
+	return must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare,
+		domain.DnsCredentials{Token: "a token"}, addresses, "uid"))
+}
+
+type dummyProvider struct {
+	records map[string]domain.DnsRecordSets
+	err     error
+}
+
+func (d *dummyProvider) Records(_ context.Context, _ domain.DnsZone, host string) (domain.DnsRecordSets, error) {
+	return d.records[host], d.err
+}
+
+func (d *dummyProvider) SetRecords(_ context.Context, _ domain.DnsZone, host string, set domain.DnsRecordSet) error {
+	d.DeleteRecords(context.Background(), domain.DnsZone{}, host, set.Type)
+	d.records[host] = append(d.records[host], set)
+	return nil
+}
+
+func (d *dummyProvider) DeleteRecords(_ context.Context, _ domain.DnsZone, host string, recordType string) error {
+	var sets domain.DnsRecordSets
+
+	for _, set := range d.records[host] {
+		if set.Type != recordType {
+			sets = append(sets, set)
+		}
+	}
+
+	d.records[host] = sets
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package sync_gitops
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Reconcile targets and apps with the spec of the GitOps repository when a new commit
+// has been pushed since the last run, or right away when forced. Each reconciliation
+// is recorded along with the changes it made. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	Force bool `json:"-"`
+}
+
+func (Command) Name_() string { return "deployment.command.sync_gitops" }
+
+func Handler(
+	reader domain.GitOpsRunsReader,
+	writer domain.GitOpsRunsWriter,
+	repository domain.GitOpsRepository,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		commit, err := repository.LatestCommit(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if !cmd.Force {
+			latest, err := reader.GetLatest(ctx)
+
+			if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, err
+			}
+
+			// Failed runs are not retried until a new commit is pushed, the spec
+			// probably needs to be fixed
+			if err == nil && latest.Commit() == commit {
+				return bus.Unit, nil
+			}
+		}
+
+		applied, changes, applyErr := repository.Apply(ctx)
+
+		if applied == "" {
+			applied = commit
+		}
+
+		run := domain.NewGitOpsRun(applied, changes, applyErr)
+
+		return bus.Unit, writer.Write(ctx, &run)
+	}
+}
@@ -0,0 +1,86 @@
+package sync_gitops_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_gitops"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type dummyRepository struct {
+	commit  string
+	changes domain.SpecChanges
+	err     error
+	applied int
+}
+
+func (r *dummyRepository) LatestCommit(context.Context) (string, error) { return r.commit, nil }
+
+func (r *dummyRepository) Apply(context.Context) (string, domain.SpecChanges, error) {
+	r.applied++
+	return r.commit, r.changes, r.err
+}
+
+func Test_SyncGitOps(t *testing.T) {
+	ctx := context.Background()
+	changes := domain.SpecChanges{{Resource: "app", Name: "my-app", Kind: domain.SpecResourceCreated}}
+	sut := func(repository domain.GitOpsRepository, existingRuns ...*domain.GitOpsRun) (bus.RequestHandler[bus.UnitType, sync_gitops.Command], memory.GitOpsRunsStore) {
+		store := memory.NewGitOpsRunsStore(existingRuns...)
+		return sync_gitops.Handler(store, store, repository), store
+	}
+
+	t.Run("should apply the spec of a new commit", func(t *testing.T) {
+		previous := domain.NewGitOpsRun("a1b2c3", nil, nil)
+		repository := &dummyRepository{commit: "d4e5f6", changes: changes}
+		uc, store := sut(repository, &previous)
+
+		_, err := uc(ctx, sync_gitops.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, repository.applied)
+		latest, _ := store.GetLatest(ctx)
+		testutil.Equals(t, "d4e5f6", latest.Commit())
+		testutil.IsTrue(t, latest.Succeeded())
+		testutil.DeepEquals(t, changes, latest.Changes())
+	})
+
+	t.Run("should skip a commit which has already been applied", func(t *testing.T) {
+		previous := domain.NewGitOpsRun("a1b2c3", nil, nil)
+		repository := &dummyRepository{commit: "a1b2c3"}
+		uc, _ := sut(repository, &previous)
+
+		_, err := uc(ctx, sync_gitops.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 0, repository.applied)
+	})
+
+	t.Run("should apply the same commit again if forced", func(t *testing.T) {
+		previous := domain.NewGitOpsRun("a1b2c3", nil, nil)
+		repository := &dummyRepository{commit: "a1b2c3"}
+		uc, _ := sut(repository, &previous)
+
+		_, err := uc(ctx, sync_gitops.Command{Force: true})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, repository.applied)
+	})
+
+	t.Run("should record a failed run with changes made before the failure", func(t *testing.T) {
+		repository := &dummyRepository{commit: "a1b2c3", changes: changes, err: errors.New("app other-app: unknown target")}
+		uc, store := sut(repository)
+
+		_, err := uc(ctx, sync_gitops.Command{})
+
+		testutil.IsNil(t, err)
+		latest, _ := store.GetLatest(ctx)
+		testutil.IsFalse(t, latest.Succeeded())
+		testutil.Equals(t, "app other-app: unknown target", latest.Err().MustGet())
+		testutil.DeepEquals(t, changes, latest.Changes())
+	})
+}
@@ -0,0 +1,48 @@
+package unfreeze_deployment
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Allow a pending production deployment to be processed even if a freeze window is
+// active. Only administrators could override freeze windows.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Command) Name_() string              { return "deployment.command.unfreeze_deployment" }
+func (c Command) AuditResource(any) string { return c.AppID }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !auth.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = depl.Unfreeze(auth.CurrentUser(ctx).MustGet()); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, writer.Write(ctx, &depl)
+	}
+}
@@ -0,0 +1,72 @@
+package unfreeze_deployment_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/unfreeze_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UnfreezeDeployment(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	sut := func(existingDeployments ...*domain.Deployment) bus.RequestHandler[bus.UnitType, unfreeze_deployment.Command] {
+		store := memory.NewDeploymentsStore(existingDeployments...)
+		return unfreeze_deployment.Handler(store, store)
+	}
+
+	newDeployment := func() domain.Deployment {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+
+		return must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		depl := newDeployment()
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&depl)
+
+		_, err := uc(auth.WithUser(context.Background(), user), unfreeze_deployment.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.HasNEvents(t, &depl, 1)
+	})
+
+	t.Run("should fail if the deployment does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, unfreeze_deployment.Command{
+			AppID:            "some-id",
+			DeploymentNumber: 1,
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should allow the deployment to be processed during freeze windows", func(t *testing.T) {
+		depl := newDeployment()
+		uc := sut(&depl)
+
+		_, err := uc(ctx, unfreeze_deployment.Command{
+			AppID:            string(depl.ID().AppID()),
+			DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentUnfrozen](t, &depl, 1)
+		testutil.Equals(t, auth.UserID("some-uid"), evt.Unfrozen.By())
+	})
+}
@@ -3,6 +3,7 @@ package update_app
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
@@ -20,8 +21,16 @@ type (
 		VersionControl monad.Patch[VersionControl]    `json:"version_control"`
 		Production     monad.Maybe[EnvironmentConfig] `json:"production"`
 		Staging        monad.Maybe[EnvironmentConfig] `json:"staging"`
+		TeamID         monad.Patch[string]            `json:"team_id"`
+		Dependencies   monad.Maybe[[]string]          `json:"dependencies"` // Apps deployed before this one
+		Labels         monad.Maybe[[]string]          `json:"labels"`
+		Platforms      monad.Maybe[[]string]          `json:"platforms"`         // Platforms the app can run on, any if empty
+		BuildRegistry  monad.Patch[string]            `json:"build_registry_id"` // Registry to push built images to
+		StaticSite     monad.Patch[StaticSite]        `json:"static_site"`       // Serve the app with the shared static web server of its targets
 	}
 
+	StaticSite create_app.StaticSite
+
 	EnvironmentConfig create_app.EnvironmentConfig
 
 	VersionControl struct {
@@ -30,14 +39,24 @@ type (
 	}
 )
 
-func (Command) Name_() string { return "deployment.command.update_app" }
+func (Command) Name_() string              { return "deployment.command.update_app" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.AppsReader,
 	writer domain.AppsWriter,
+	teamsReader domain.TeamsReader,
+	registriesReader domain.RegistriesReader,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var url domain.Url
+		var (
+			url                domain.Url
+			productionExposure monad.Maybe[domain.ServicesExposure]
+			stagingExposure    monad.Maybe[domain.ServicesExposure]
+			labels             domain.Labels
+			platforms          domain.Platforms
+			staticSite         monad.Maybe[domain.StaticSite]
+		)
 
 		if err := validate.Struct(validate.Of{
 			"version_control": validate.Patch(cmd.VersionControl, func(config VersionControl) error {
@@ -48,14 +67,27 @@ func Handler(
 			}),
 			"production": validate.Maybe(cmd.Production, func(conf EnvironmentConfig) error {
 				return validate.Struct(validate.Of{
-					"target": validate.Field(conf.Target, strings.Required),
+					"target":   validate.Field(conf.Target, strings.Required),
+					"exposure": create_app.ValidateServicesExposure(conf.Exposure, &productionExposure),
 				})
 			}),
 			"staging": validate.Maybe(cmd.Staging, func(conf EnvironmentConfig) error {
 				return validate.Struct(validate.Of{
-					"target": validate.Field(conf.Target, strings.Required),
+					"target":   validate.Field(conf.Target, strings.Required),
+					"exposure": create_app.ValidateServicesExposure(conf.Exposure, &stagingExposure),
 				})
 			}),
+			"team_id":           validate.Patch(cmd.TeamID, strings.Required),
+			"build_registry_id": validate.Patch(cmd.BuildRegistry, strings.Required),
+			"labels": validate.Maybe(cmd.Labels, func(values []string) error {
+				return validate.Value(values, &labels, domain.LabelsFrom)
+			}),
+			"platforms": validate.Maybe(cmd.Platforms, func(values []string) error {
+				return validate.Value(values, &platforms, domain.PlatformsFrom)
+			}),
+			"static_site": validate.Patch(cmd.StaticSite, func(site StaticSite) error {
+				return create_app.ValidateStaticSite(create_app.StaticSite(site), &staticSite)
+			}),
 		}); err != nil {
 			return "", err
 		}
@@ -66,15 +98,19 @@ func Handler(
 			return "", err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionManage, app.CreatedBy(), app.Resources()...); err != nil {
+			return "", err
+		}
+
 		// Determine the availability of updated targets
 		var productionConfig, stagingConfig monad.Maybe[domain.EnvironmentConfig]
 
 		if conf, isUpdated := cmd.Production.TryGet(); isUpdated {
-			productionConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars))
+			productionConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars, productionExposure))
 		}
 
 		if conf, isUpdated := cmd.Staging.TryGet(); isUpdated {
-			stagingConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars))
+			stagingConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars, stagingExposure))
 		}
 
 		productionRequirement, stagingRequirement, err := reader.CheckAppNamingAvailabilityByID(ctx, app.ID(), productionConfig, stagingConfig)
@@ -126,6 +162,66 @@ func Handler(
 			}
 		}
 
+		if teamPatch, isSet := cmd.TeamID.TryGet(); isSet {
+			if teamID, hasValue := teamPatch.TryGet(); hasValue {
+				team, err := create_app.GetTeam(ctx, teamsReader, domain.TeamID(teamID))
+
+				if err != nil {
+					return "", err
+				}
+
+				err = app.JoinTeam(team)
+			} else {
+				err = app.LeaveTeam()
+			}
+
+			if err != nil {
+				return "", err
+			}
+		}
+
+		if dependencies, isSet := cmd.Dependencies.TryGet(); isSet {
+			if err = create_app.DependsOn(ctx, reader, &app, dependencies); err != nil {
+				return "", err
+			}
+		}
+
+		if cmd.Labels.HasValue() {
+			if err = app.HasLabels(labels); err != nil {
+				return "", err
+			}
+		}
+
+		if cmd.Platforms.HasValue() {
+			if err = app.HasPlatforms(platforms); err != nil {
+				return "", err
+			}
+		}
+
+		if registryPatch, isSet := cmd.BuildRegistry.TryGet(); isSet {
+			if registryID, hasValue := registryPatch.TryGet(); hasValue {
+				registry, err := create_app.GetBuildRegistry(ctx, registriesReader, domain.RegistryID(registryID))
+
+				if err != nil {
+					return "", err
+				}
+
+				err = app.UseBuildRegistry(registry)
+			} else {
+				err = app.RemoveBuildRegistry()
+			}
+
+			if err != nil {
+				return "", err
+			}
+		}
+
+		if cmd.StaticSite.IsSet() {
+			if err = app.HasStaticSite(staticSite); err != nil {
+				return "", err
+			}
+		}
+
 		if err = writer.Write(ctx, &app); err != nil {
 			return "", err
 		}
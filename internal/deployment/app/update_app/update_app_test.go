@@ -25,7 +25,7 @@ func Test_UpdateApp(t *testing.T) {
 
 	sut := func(existingApps ...*domain.App) bus.RequestHandler[string, update_app.Command] {
 		store := memory.NewAppsStore(existingApps...)
-		return update_app.Handler(store, store)
+		return update_app.Handler(store, store, memory.NewTeamsStore(), memory.NewRegistriesStore())
 	}
 
 	t.Run("should require a valid application id", func(t *testing.T) {
@@ -317,4 +317,90 @@ func Test_UpdateApp(t *testing.T) {
 		testutil.Equals(t, "https://some.url", evt.Config.Url().String())
 		testutil.Equals(t, "new token", evt.Config.Token().Get(""))
 	})
+
+	t.Run("should move the app to another team and remove it from its team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "some-uid")
+		a := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		store := memory.NewAppsStore(&a)
+		uc := update_app.Handler(store, store, memory.NewTeamsStore(&team), memory.NewRegistriesStore())
+
+		_, err := uc(ctx, update_app.Command{
+			ID:     string(a.ID()),
+			TeamID: monad.PatchValue(string(team.ID())),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.AppTeamChanged](t, &a, 1)
+		testutil.Equals(t, team.ID(), evt.Team.MustGet())
+
+		_, err = uc(ctx, update_app.Command{
+			ID:     string(a.ID()),
+			TeamID: monad.Nil[string](),
+		})
+
+		testutil.IsNil(t, err)
+		evt = testutil.EventIs[domain.AppTeamChanged](t, &a, 2)
+		testutil.IsFalse(t, evt.Team.HasValue())
+	})
+	t.Run("should update the app dependencies", func(t *testing.T) {
+		db := must.Panic(domain.NewApp("my-db",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		a := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		uc := sut(&db, &a)
+
+		_, err := uc(ctx, update_app.Command{
+			ID:           string(a.ID()),
+			Dependencies: monad.Value([]string{"unknown"}),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+
+		_, err = uc(ctx, update_app.Command{
+			ID:           string(a.ID()),
+			Dependencies: monad.Value([]string{string(db.ID())}),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.AppDependenciesChanged](t, &a, 1)
+		testutil.DeepEquals(t, domain.AppDependencies{db.ID()}, evt.Dependencies)
+	})
+
+	t.Run("should serve the app as a static site and stop serving it when nil given", func(t *testing.T) {
+		a := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		uc := sut(&a)
+
+		_, err := uc(ctx, update_app.Command{
+			ID:         string(a.ID()),
+			StaticSite: monad.PatchValue(update_app.StaticSite{Service: "web", Directory: "dist"}),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidStaticSiteDirectory, validationErr["static_site"])
+
+		_, err = uc(ctx, update_app.Command{
+			ID:         string(a.ID()),
+			StaticSite: monad.PatchValue(update_app.StaticSite{Service: "web", Directory: "/app/dist"}),
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.AppStaticSiteChanged](t, &a, 1)
+		testutil.Equals(t, domain.StaticSite{Service: "web", Directory: "/app/dist"}, evt.StaticSite.MustGet())
+
+		_, err = uc(ctx, update_app.Command{
+			ID:         string(a.ID()),
+			StaticSite: monad.Nil[update_app.StaticSite](),
+		})
+
+		testutil.IsNil(t, err)
+		evt = testutil.EventIs[domain.AppStaticSiteChanged](t, &a, 2)
+		testutil.IsFalse(t, evt.StaticSite.HasValue())
+	})
 }
@@ -0,0 +1,78 @@
+package update_dns_zone
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+type Command struct {
+	bus.Command[string]
+
+	ID          string                                   `json:"-"`
+	Credentials monad.Maybe[create_dns_zone.Credentials] `json:"credentials"` // Not set to keep current credentials
+	Addresses   monad.Maybe[[]string]                    `json:"addresses"`   // Empty to alias the target host
+}
+
+func (Command) Name_() string              { return "deployment.command.update_dns_zone" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.DnsZonesReader,
+	writer domain.DnsZonesWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var (
+			rawAddresses = cmd.Addresses.Get(nil)
+			addresses    = make(domain.DnsAddresses, len(rawAddresses))
+			addressesOf  = make(validate.Of, len(rawAddresses))
+		)
+
+		for i, value := range rawAddresses {
+			addressesOf[strconv.Itoa(i)] = validate.Value(value, &addresses[i], domain.DnsAddressFrom)
+		}
+
+		if err := validate.Struct(validate.Of{
+			"addresses": validate.Struct(addressesOf),
+		}); err != nil {
+			return "", err
+		}
+
+		zone, err := reader.GetByID(ctx, domain.DnsZoneID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if creds, isSet := cmd.Credentials.TryGet(); isSet {
+			credentials, err := domain.DnsCredentialsFrom(zone.Provider(), creds.Token, creds.AccessKey, creds.SecretKey)
+
+			if err != nil {
+				return "", validate.Wrap(err, "credentials")
+			}
+
+			zone.UseCredentials(credentials)
+		}
+
+		if cmd.Addresses.HasValue() {
+			zone.PointTo(addresses)
+		}
+
+		if err = writer.Write(ctx, &zone); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
@@ -0,0 +1,100 @@
+package update_dns_zone_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_UpdateDnsZone(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	newZone := func() domain.DnsZone {
+		return must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderRoute53,
+			domain.DnsCredentials{AccessKey: "access", SecretKey: "secret"}, nil, "uid"))
+	}
+	sut := func(existing ...*domain.DnsZone) bus.RequestHandler[string, update_dns_zone.Command] {
+		store := memory.NewDnsZonesStore(existing...)
+		return update_dns_zone.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		z := newZone()
+		uc := sut(&z)
+
+		_, err := uc(auth.WithUser(context.Background(), user), update_dns_zone.Command{
+			ID:        string(z.ID()),
+			Addresses: monad.Value([]string{"203.0.113.10"}),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.HasNEvents(t, &z, 1)
+	})
+
+	t.Run("should require valid addresses", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, update_dns_zone.Command{
+			Addresses: monad.Value([]string{"not an ip"}),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidDnsAddress, validationErr["addresses.0"])
+	})
+
+	t.Run("should require an existing zone", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, update_dns_zone.Command{
+			ID: "another-zone",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require credentials expected by the zone provider", func(t *testing.T) {
+		z := newZone()
+		uc := sut(&z)
+
+		_, err := uc(ctx, update_dns_zone.Command{
+			ID:          string(z.ID()),
+			Credentials: monad.Value(create_dns_zone.Credentials{Token: "a token"}),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidDnsCredentials, validationErr["credentials"])
+		testutil.HasNEvents(t, &z, 1)
+	})
+
+	t.Run("should update the zone", func(t *testing.T) {
+		z := newZone()
+		uc := sut(&z)
+
+		id, err := uc(ctx, update_dns_zone.Command{
+			ID:          string(z.ID()),
+			Credentials: monad.Value(create_dns_zone.Credentials{AccessKey: "another access", SecretKey: "secret"}),
+			Addresses:   monad.Value([]string{"203.0.113.10"}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(z.ID()), id)
+		testutil.HasNEvents(t, &z, 3)
+		credentialsChanged := testutil.EventIs[domain.DnsZoneCredentialsChanged](t, &z, 1)
+		testutil.Equals(t, "another access", credentialsChanged.Credentials.AccessKey)
+		addressesChanged := testutil.EventIs[domain.DnsZoneAddressesChanged](t, &z, 2)
+		testutil.DeepEquals(t, domain.DnsAddresses{"203.0.113.10"}, addressesChanged.Addresses)
+	})
+}
@@ -0,0 +1,72 @@
+package update_peer
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type Command struct {
+	bus.Command[string]
+
+	ID    string              `json:"-"`
+	Name  monad.Maybe[string] `json:"name"`
+	Url   monad.Maybe[string] `json:"url"`
+	Token monad.Maybe[string] `json:"token"` // Not set to keep the current token
+}
+
+func (Command) Name_() string              { return "deployment.command.update_peer" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.PeersReader,
+	writer domain.PeersWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var url domain.Url
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Maybe(cmd.Name, strings.Required),
+			"url": validate.Maybe(cmd.Url, func(u string) error {
+				return validate.Value(u, &url, domain.UrlFrom)
+			}),
+			"token": validate.Maybe(cmd.Token, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		peer, err := reader.GetByID(ctx, domain.PeerID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if name, isSet := cmd.Name.TryGet(); isSet {
+			peer.Rename(name)
+		}
+
+		if cmd.Url.HasValue() {
+			peer.HasUrl(url)
+		}
+
+		if token, isSet := cmd.Token.TryGet(); isSet {
+			peer.UseToken(token)
+		}
+
+		if err = writer.Write(ctx, &peer); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
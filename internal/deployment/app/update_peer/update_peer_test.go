@@ -0,0 +1,95 @@
+package update_peer_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_UpdatePeer(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+	url := must.Panic(domain.UrlFrom("https://seelf.example.com"))
+	sut := func(existing ...*domain.Peer) bus.RequestHandler[string, update_peer.Command] {
+		store := memory.NewPeersStore(existing...)
+		return update_peer.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		uc := sut(&p)
+
+		_, err := uc(auth.WithUser(context.Background(), user), update_peer.Command{
+			ID:   string(p.ID()),
+			Name: monad.Value("home lab"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.HasNEvents(t, &p, 1)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, update_peer.Command{
+			Url: monad.Value("not an url"),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+	})
+
+	t.Run("should require an existing peer", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(ctx, update_peer.Command{
+			ID:   "another-peer",
+			Name: monad.Value("home lab"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should update the given fields only", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		uc := sut(&p)
+
+		id, err := uc(ctx, update_peer.Command{
+			ID:  string(p.ID()),
+			Url: monad.Value("http://192.168.1.10:8080"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(p.ID()), id)
+		testutil.HasNEvents(t, &p, 2)
+		evt := testutil.EventIs[domain.PeerUrlChanged](t, &p, 1)
+		testutil.Equals(t, "http://192.168.1.10:8080", evt.Url.String())
+	})
+
+	t.Run("should replace the token if given", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		uc := sut(&p)
+
+		_, err := uc(ctx, update_peer.Command{
+			ID:    string(p.ID()),
+			Name:  monad.Value("home lab"),
+			Token: monad.Value("another token"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.EventIs[domain.PeerRenamed](t, &p, 1)
+		evt := testutil.EventIs[domain.PeerTokenChanged](t, &p, 2)
+		testutil.Equals(t, "another token", evt.Token)
+	})
+}
@@ -3,6 +3,7 @@ package update_registry
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
@@ -26,7 +27,8 @@ type (
 	}
 )
 
-func (Command) Name_() string { return "deployment.command.update_registry" }
+func (Command) Name_() string              { return "deployment.command.update_registry" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.RegistriesReader,
@@ -56,6 +58,10 @@ func Handler(
 			return "", err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionManage, registry.CreatedBy()); err != nil {
+			return "", err
+		}
+
 		if name, isSet := cmd.Name.TryGet(); isSet {
 			registry.Rename(name)
 		}
@@ -3,6 +3,7 @@ package update_target
 import (
 	"context"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
@@ -13,13 +14,16 @@ import (
 type Command struct {
 	bus.Command[string]
 
-	ID       string              `json:"-"`
-	Name     monad.Maybe[string] `json:"name"`
-	Url      monad.Maybe[string] `json:"url"`
-	Provider any                 `json:"-"`
+	ID       string                         `json:"-"`
+	Name     monad.Maybe[string]            `json:"name"`
+	Url      monad.Maybe[string]            `json:"url"`
+	Labels   monad.Maybe[[]string]          `json:"labels"` // Used to select a target for new apps
+	Vars     monad.Maybe[map[string]string] `json:"vars"`   // Shared by every app deployed on the target, masked values are kept as is
+	Provider any                            `json:"-"`
 }
 
-func (Command) Name_() string { return "deployment.command.update_target" }
+func (Command) Name_() string              { return "deployment.command.update_target" }
+func (c Command) AuditResource(any) string { return c.ID }
 
 func Handler(
 	reader domain.TargetsReader,
@@ -27,13 +31,19 @@ func Handler(
 	provider domain.Provider,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var targetUrl domain.Url
+		var (
+			targetUrl domain.Url
+			labels    domain.Labels
+		)
 
 		if err := validate.Struct(validate.Of{
 			"name": validate.Maybe(cmd.Name, strings.Required),
 			"url": validate.Maybe(cmd.Url, func(s string) error {
 				return validate.Value(s, &targetUrl, domain.UrlFrom)
 			}),
+			"labels": validate.Maybe(cmd.Labels, func(values []string) error {
+				return validate.Value(values, &labels, domain.LabelsFrom)
+			}),
 		}); err != nil {
 			return "", err
 		}
@@ -44,6 +54,10 @@ func Handler(
 			return "", err
 		}
 
+		if err = auth.Authorize(ctx, auth.PermissionManage, target.CreatedBy(), target.Resources()...); err != nil {
+			return "", err
+		}
+
 		// Validate both requirements at once if the value has been updated
 		var (
 			urlRequirement    domain.TargetUrlRequirement
@@ -99,6 +113,18 @@ func Handler(
 			}
 		}
 
+		if cmd.Labels.HasValue() {
+			if err = target.HasLabels(labels); err != nil {
+				return "", err
+			}
+		}
+
+		if vars, isSet := cmd.Vars.TryGet(); isSet {
+			if err = target.HasSharedVariables(vars); err != nil {
+				return "", err
+			}
+		}
+
 		if err = writer.Write(ctx, &target); err != nil {
 			return "", err
 		}
@@ -78,6 +78,23 @@ func Test_UpdateTarget(t *testing.T) {
 		testutil.EventIs[domain.TargetStateChanged](t, &target, 3)
 		testutil.EventIs[domain.TargetStateChanged](t, &target, 5)
 	})
+
+	t.Run("should update shared variables of the target", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(dummyConfig{"1"}, true), "uid"))
+		uc := sut(&target)
+
+		_, err := uc(context.Background(), update_target.Command{
+			ID:   string(target.ID()),
+			Vars: monad.Value(map[string]string{"SMTP_HOST": "smtp.example.com"}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &target, 2)
+		evt := testutil.EventIs[domain.TargetVarsChanged](t, &target, 1)
+		testutil.DeepEquals(t, domain.EnvVars{"SMTP_HOST": "smtp.example.com"}, evt.Vars)
+	})
 }
 
 type (
@@ -0,0 +1,55 @@
+package update_team
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type Command struct {
+	bus.Command[string]
+
+	ID   string              `json:"-"`
+	Name monad.Maybe[string] `json:"name"`
+}
+
+func (Command) Name_() string              { return "deployment.command.update_team" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.TeamsReader,
+	writer domain.TeamsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if err := validate.Struct(validate.Of{
+			"name": validate.Maybe(cmd.Name, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		team, err := reader.GetByID(ctx, domain.TeamID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = auth.Authorize(ctx, auth.PermissionManage, team.CreatedBy(), team.Resources()...); err != nil {
+			return "", err
+		}
+
+		if name, isSet := cmd.Name.TryGet(); isSet {
+			team.Rename(name)
+		}
+
+		if err = writer.Write(ctx, &team); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
@@ -0,0 +1,62 @@
+package update_team_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_team"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UpdateTeam(t *testing.T) {
+	sut := func(existing ...*domain.Team) bus.RequestHandler[string, update_team.Command] {
+		store := memory.NewTeamsStore(existing...)
+		return update_team.Handler(store, store)
+	}
+
+	t.Run("should require an existing team", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), update_team.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require the team admin role", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		user.Grant(auth.NewResourceRequirement(auth.NewResource(auth.ResourceTeam, string(team.ID())), true), auth.RoleDeployer)
+		uc := sut(&team)
+
+		_, err := uc(auth.WithUser(context.Background(), user), update_team.Command{
+			ID:   string(team.ID()),
+			Name: monad.Value("new team"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should rename the team", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+		uc := sut(&team)
+
+		id, err := uc(context.Background(), update_team.Command{
+			ID:   string(team.ID()),
+			Name: monad.Value("new team"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(team.ID()), id)
+		evt := testutil.EventIs[domain.TeamRenamed](t, &team, 1)
+		testutil.Equals(t, "new team", evt.Name)
+	})
+}
@@ -0,0 +1,18 @@
+package upgrade_proxy
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func OnTargetProxyUpgradeRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.TargetProxyUpgradeRequested] {
+	return func(ctx context.Context, evt domain.TargetProxyUpgradeRequested) error {
+		return scheduler.Queue(ctx, Command{
+			ID:      string(evt.ID),
+			Version: evt.Version,
+		}, bus.WithGroup(app.TargetConfigurationGroup(evt.ID)), bus.WithPolicy(bus.JobPolicyMerge))
+	}
+}
@@ -0,0 +1,65 @@
+package upgrade_proxy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Upgrade the proxy running on a target to the given version.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+func (Command) Name_() string        { return "deployment.command.upgrade_proxy" }
+func (c Command) ResourceID() string { return c.ID }
+
+func Handler(
+	reader domain.TargetsReader,
+	writer domain.TargetsWriter,
+	provider domain.Provider,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		target, err := reader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			// Target not found, already deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		// Being configured or not reachable, the next successful configuration will deploy
+		// the latest proxy anyway.
+		if target.CheckAvailability() != nil || target.IsProxyUpToDate(cmd.Version) {
+			return bus.Unit, nil
+		}
+
+		if err = provider.UpgradeProxy(ctx, target); err != nil {
+			return bus.Unit, err
+		}
+
+		// Since the upgrade can take some time, retrieve the latest target version before updating it.
+		target, err = reader.GetByID(ctx, domain.TargetID(cmd.ID))
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		target.ProxyRunning(cmd.Version)
+
+		return bus.Unit, writer.Write(ctx, &target)
+	}
+}
@@ -0,0 +1,106 @@
+package upgrade_proxy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/upgrade_proxy"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UpgradeProxy(t *testing.T) {
+	sut := func(existingTargets ...*domain.Target) (bus.RequestHandler[bus.UnitType, upgrade_proxy.Command], *dummyProvider) {
+		provider := &dummyProvider{}
+		store := memory.NewTargetsStore(existingTargets...)
+		return upgrade_proxy.Handler(store, store, provider), provider
+	}
+
+	t.Run("should fail silently if the target is not found", func(t *testing.T) {
+		uc, provider := sut()
+
+		_, err := uc(context.Background(), upgrade_proxy.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should skip the upgrade if the target is not available", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		uc, provider := sut(&target)
+
+		_, err := uc(context.Background(), upgrade_proxy.Command{
+			ID:      string(target.ID()),
+			Version: "v3.0",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should skip the upgrade if the proxy is already up to date", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.ProxyRunning("v3.0")
+		uc, provider := sut(&target)
+
+		_, err := uc(context.Background(), upgrade_proxy.Command{
+			ID:      string(target.ID()),
+			Version: "v3.0",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, provider.called)
+	})
+
+	t.Run("should not record the new version if the upgrade fails", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.ProxyRunning("v2.11")
+		uc, provider := sut(&target)
+		providerErr := errors.New("upgrade failed")
+		provider.err = providerErr
+
+		_, err := uc(context.Background(), upgrade_proxy.Command{
+			ID:      string(target.ID()),
+			Version: "v3.0",
+		})
+
+		testutil.ErrorIs(t, providerErr, err)
+		testutil.IsTrue(t, provider.called)
+		testutil.IsTrue(t, target.IsProxyUpToDate("v2.11"))
+	})
+
+	t.Run("should upgrade the proxy and record its new version", func(t *testing.T) {
+		target := fixture.Target("http://localhost", false)
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.ProxyRunning("v2.11")
+		uc, provider := sut(&target)
+
+		_, err := uc(context.Background(), upgrade_proxy.Command{
+			ID:      string(target.ID()),
+			Version: "v3.0",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, provider.called)
+		testutil.HasNEvents(t, &target, 4)
+		changed := testutil.EventIs[domain.TargetProxyVersionChanged](t, &target, 3)
+		testutil.Equals(t, "v3.0", changed.Version)
+	})
+}
+
+type dummyProvider struct {
+	domain.Provider
+	err    error
+	called bool
+}
+
+func (d *dummyProvider) UpgradeProxy(context.Context, domain.Target) error {
+	d.called = true
+	return d.err
+}
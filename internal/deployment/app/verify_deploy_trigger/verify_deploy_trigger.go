@@ -0,0 +1,53 @@
+package verify_deploy_trigger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+var ErrInvalidDeployTrigger = apperr.New("invalid_deploy_trigger")
+
+// Verify a deployment trigger signed by an external system and returns the user on
+// behalf of whom it should be processed. No user is authenticated, access is granted
+// by the signature instead.
+type Query struct {
+	bus.Query[auth.UserID]
+
+	AppID     string `json:"-"`
+	Timestamp string `json:"-"`
+	Signature string `json:"-"`
+	Payload   []byte `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.verify_deploy_trigger" }
+
+func Handler(
+	reader domain.AppsReader,
+) bus.RequestHandler[auth.UserID, Query] {
+	return func(ctx context.Context, query Query) (auth.UserID, error) {
+		// Every failure is reported the same way so triggers could not be used to probe apps
+		app, err := reader.GetByID(ctx, domain.AppID(query.AppID))
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return "", ErrInvalidDeployTrigger
+			}
+
+			return "", err
+		}
+
+		uid, err := app.VerifyDeployTrigger(query.Timestamp, query.Signature, query.Payload, time.Now().UTC())
+
+		if err != nil {
+			return "", ErrInvalidDeployTrigger
+		}
+
+		return uid, nil
+	}
+}
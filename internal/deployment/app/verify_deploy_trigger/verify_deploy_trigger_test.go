@@ -0,0 +1,76 @@
+package verify_deploy_trigger_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/verify_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_VerifyDeployTrigger(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+	secret := must.Panic(app.GenerateDeployTriggerSecret("some-uid"))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	payload := []byte(`{"environment":"production"}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(payload)))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	sut := func() bus.RequestHandler[auth.UserID, verify_deploy_trigger.Query] {
+		return verify_deploy_trigger.Handler(memory.NewAppsStore(&app))
+	}
+
+	t.Run("should be rejected if the app does not exist", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), verify_deploy_trigger.Query{
+			AppID:     "another-app",
+			Timestamp: timestamp,
+			Signature: signature,
+			Payload:   payload,
+		})
+
+		testutil.ErrorIs(t, verify_deploy_trigger.ErrInvalidDeployTrigger, err)
+	})
+
+	t.Run("should be rejected if the signature is invalid", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), verify_deploy_trigger.Query{
+			AppID:     string(app.ID()),
+			Timestamp: timestamp,
+			Signature: signature,
+			Payload:   []byte("{}"),
+		})
+
+		testutil.ErrorIs(t, verify_deploy_trigger.ErrInvalidDeployTrigger, err)
+	})
+
+	t.Run("should return the user who configured the trigger", func(t *testing.T) {
+		uc := sut()
+
+		uid, err := uc(context.Background(), verify_deploy_trigger.Query{
+			AppID:     string(app.ID()),
+			Timestamp: timestamp,
+			Signature: signature,
+			Payload:   payload,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, auth.UserID("some-uid"), uid)
+	})
+}
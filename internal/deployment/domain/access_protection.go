@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"net"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidIPRange          = apperr.New("invalid_ip_range")
+	ErrInvalidBasicAuthUser    = apperr.New("invalid_basic_auth_username")
+	ErrAccessProtectionMissing = apperr.New("access_protection_missing")
+)
+
+type (
+	// Protects exposed HTTP services of an environment. Every configured protection
+	// must be satisfied for a request to reach a service.
+	AccessProtection struct {
+		BasicAuth   monad.Maybe[BasicAuth] `json:"basic_auth"`
+		IPAllowList []string               `json:"ip_allowlist"` // IP addresses or CIDR ranges allowed to reach services
+		ForwardAuth monad.Maybe[Url]       `json:"forward_auth"` // Authentication server asked to validate requests, such as an OAuth proxy
+	}
+
+	// Credentials asked to reach services. Only the hash of the password is kept.
+	BasicAuth struct {
+		Username     string `json:"username"`
+		PasswordHash string `json:"password_hash"`
+	}
+)
+
+// Builds a new access protection. At least one protection should be given.
+func NewAccessProtection(
+	basicAuth monad.Maybe[BasicAuth],
+	ipAllowList []string,
+	forwardAuth monad.Maybe[Url],
+) (AccessProtection, error) {
+	if !basicAuth.HasValue() && len(ipAllowList) == 0 && !forwardAuth.HasValue() {
+		return AccessProtection{}, ErrAccessProtectionMissing
+	}
+
+	return AccessProtection{
+		BasicAuth:   basicAuth,
+		IPAllowList: ipAllowList,
+		ForwardAuth: forwardAuth,
+	}, nil
+}
+
+// Parses an IP address or a CIDR range allowed to reach protected services.
+func IPRangeFrom(value string) (string, error) {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return value, nil
+	}
+
+	if net.ParseIP(value) != nil {
+		return value, nil
+	}
+
+	return "", ErrInvalidIPRange
+}
+
+// Parses a basic auth username which could not contain a colon since it is used
+// as the separator with the password.
+func BasicAuthUsernameFrom(value string) (string, error) {
+	if value == "" || strings.Contains(value, ":") {
+		return "", ErrInvalidBasicAuthUser
+	}
+
+	return value, nil
+}
+
+func (p AccessProtection) Value() (driver.Value, error) { return storage.ValueJSON(p) }
+func (p *AccessProtection) Scan(value any) error        { return storage.ScanJSON(value, p) }
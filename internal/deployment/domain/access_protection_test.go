@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AccessProtection(t *testing.T) {
+	t.Run("should require at least one protection", func(t *testing.T) {
+		_, err := domain.NewAccessProtection(monad.None[domain.BasicAuth](), nil, monad.None[domain.Url]())
+
+		testutil.ErrorIs(t, domain.ErrAccessProtectionMissing, err)
+	})
+
+	t.Run("should validates IP addresses and ranges", func(t *testing.T) {
+		tests := []struct {
+			input string
+			valid bool
+		}{
+			{"", false},
+			{"localhost", false},
+			{"10.0.0.0/33", false},
+			{"192.168.1.1", true},
+			{"10.0.0.0/8", true},
+			{"2001:db8::/32", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				r, err := domain.IPRangeFrom(test.input)
+
+				if test.valid {
+					testutil.IsNil(t, err)
+					testutil.Equals(t, test.input, r)
+				} else {
+					testutil.ErrorIs(t, domain.ErrInvalidIPRange, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validates basic auth usernames", func(t *testing.T) {
+		_, err := domain.BasicAuthUsernameFrom("")
+		testutil.ErrorIs(t, domain.ErrInvalidBasicAuthUser, err)
+
+		_, err = domain.BasicAuthUsernameFrom("john:doe")
+		testutil.ErrorIs(t, domain.ErrInvalidBasicAuthUser, err)
+
+		username, err := domain.BasicAuthUsernameFrom("john")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "john", username)
+	})
+}
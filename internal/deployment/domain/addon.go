@@ -0,0 +1,431 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidAddonKind          = apperr.New("invalid_addon_kind")
+	ErrAddonKindAlreadyUsed      = apperr.New("addon_kind_already_used")
+	ErrAddonVariableAlreadyTaken = apperr.New("addon_variable_already_taken")
+	ErrAddonCleanupNeeded        = apperr.New("addon_cleanup_needed")
+)
+
+const (
+	AddonPostgres AddonKind = "postgres"
+	AddonMySQL    AddonKind = "mysql"
+	AddonRedis    AddonKind = "redis"
+
+	addonPasswordLength = 32
+	addonUsername       = "seelf"
+	addonDatabase       = "app"
+)
+
+const (
+	AddonStatusProvisioning AddonStatus = iota
+	AddonStatusFailed
+	AddonStatusReady
+)
+
+type (
+	AddonID     string
+	AddonKind   string // Kind of service provisioned by an add-on
+	AddonStatus uint8
+
+	// Service, such as a database, provisioned and managed by seelf on the target of an
+	// app environment. Its connection string is given to every service of the app
+	// environment at deployment time.
+	Addon struct {
+		event.Emitter
+
+		id               AddonID
+		app              AppID
+		environment      Environment
+		kind             AddonKind
+		variable         string
+		credentials      Credentials
+		target           TargetID
+		state            AddonState
+		cleanupRequested monad.Maybe[shared.Action[auth.UserID]]
+		created          shared.Action[auth.UserID]
+	}
+
+	AddonState struct {
+		status  AddonStatus
+		version time.Time
+		errcode monad.Maybe[string]
+	}
+
+	AddonsReader interface {
+		GetByID(context.Context, AddonID) (Addon, error)
+		GetByApp(context.Context, AppID) ([]Addon, error)
+		GetByAppEnv(context.Context, AppID, Environment) ([]Addon, error)
+		// Retrieve ready add-ons for which no backup has been taken since the given date.
+		GetDueForBackup(context.Context, time.Time) ([]Addon, error)
+	}
+
+	AddonsWriter interface {
+		Write(context.Context, ...*Addon) error
+	}
+
+	AddonCreated struct {
+		bus.Notification
+
+		ID          AddonID
+		AppID       AppID
+		Environment Environment
+		Kind        AddonKind
+		Variable    string
+		Credentials Credentials
+		Target      TargetID
+		State       AddonState
+		Created     shared.Action[auth.UserID]
+	}
+
+	AddonTargetChanged struct {
+		bus.Notification
+
+		ID        AddonID
+		AppID     AppID
+		Target    TargetID
+		OldTarget TargetID // Target from which the add-on service should be removed
+		State     AddonState
+	}
+
+	AddonStateChanged struct {
+		bus.Notification
+
+		ID    AddonID
+		State AddonState
+	}
+
+	AddonCleanupRequested struct {
+		bus.Notification
+
+		ID        AddonID
+		AppID     AppID
+		Target    TargetID
+		Requested shared.Action[auth.UserID]
+	}
+
+	AddonDeleted struct {
+		bus.Notification
+
+		ID AddonID
+	}
+)
+
+func (AddonCreated) Name_() string          { return "deployment.event.addon_created" }
+func (AddonTargetChanged) Name_() string    { return "deployment.event.addon_target_changed" }
+func (AddonStateChanged) Name_() string     { return "deployment.event.addon_state_changed" }
+func (AddonCleanupRequested) Name_() string { return "deployment.event.addon_cleanup_requested" }
+func (AddonDeleted) Name_() string          { return "deployment.event.addon_deleted" }
+
+// Creates a new add-on kind from a raw value.
+func AddonKindFrom(value string) (AddonKind, error) {
+	switch AddonKind(value) {
+	case AddonPostgres:
+		return AddonPostgres, nil
+	case AddonMySQL:
+		return AddonMySQL, nil
+	case AddonRedis:
+		return AddonRedis, nil
+	default:
+		return "", ErrInvalidAddonKind
+	}
+}
+
+// Port on which the add-on service listens.
+func (k AddonKind) Port() Port {
+	switch k {
+	case AddonPostgres:
+		return 5432
+	case AddonMySQL:
+		return 3306
+	default:
+		return 6379
+	}
+}
+
+// Name of the environment variable holding the connection string when none is given.
+func (k AddonKind) DefaultVariable() string {
+	switch k {
+	case AddonPostgres:
+		return "DATABASE_URL"
+	case AddonMySQL:
+		return "MYSQL_URL"
+	default:
+		return "REDIS_URL"
+	}
+}
+
+// Provision a new add-on for the given app environment, on the target of this environment.
+// Existing add-ons of the app environment are given to make sure a kind and a variable
+// are used only once.
+func NewAddon(
+	app App,
+	env Environment,
+	kind AddonKind,
+	variable monad.Maybe[string],
+	existing []Addon,
+	createdBy auth.UserID,
+) (a Addon, err error) {
+	if app.cleanupRequested.HasValue() {
+		return a, ErrAppCleanupRequested
+	}
+
+	config, err := app.ConfigSnapshotFor(env)
+
+	if err != nil {
+		return a, err
+	}
+
+	name := variable.Get(kind.DefaultVariable())
+
+	for _, other := range existing {
+		if other.kind == kind {
+			return a, ErrAddonKindAlreadyUsed
+		}
+
+		if other.variable == name {
+			return a, ErrAddonVariableAlreadyTaken
+		}
+	}
+
+	password, err := crypto.RandomKey[string](addonPasswordLength)
+
+	if err != nil {
+		return a, err
+	}
+
+	a.apply(AddonCreated{
+		ID:          id.New[AddonID](),
+		AppID:       app.ID(),
+		Environment: env,
+		Kind:        kind,
+		Variable:    name,
+		Credentials: NewCredentials(addonUsername, password),
+		Target:      config.Target(),
+		State:       newAddonState(),
+		Created:     shared.NewAction(createdBy),
+	})
+
+	return a, nil
+}
+
+// Recreates an add-on from the persistent storage.
+func AddonFrom(scanner storage.Scanner) (a Addon, err error) {
+	var (
+		username           string
+		password           string
+		cleanupRequestedAt monad.Maybe[time.Time]
+		cleanupRequestedBy monad.Maybe[string]
+		createdAt          time.Time
+		createdBy          auth.UserID
+	)
+
+	err = scanner.Scan(
+		&a.id,
+		&a.app,
+		&a.environment,
+		&a.kind,
+		&a.variable,
+		&username,
+		&password,
+		&a.target,
+		&a.state.status,
+		&a.state.version,
+		&a.state.errcode,
+		&cleanupRequestedAt,
+		&cleanupRequestedBy,
+		&createdAt,
+		&createdBy,
+	)
+
+	a.credentials = NewCredentials(username, password)
+	a.created = shared.ActionFrom(createdBy, createdAt)
+
+	if requestedAt, isSet := cleanupRequestedAt.TryGet(); isSet {
+		a.cleanupRequested.Set(
+			shared.ActionFrom(auth.UserID(cleanupRequestedBy.MustGet()), requestedAt),
+		)
+	}
+
+	return a, err
+}
+
+// Move the add-on to the given target, it will be provisioned again with an empty
+// storage since data are not migrated between targets.
+func (a *Addon) MovedTo(target TargetID) {
+	if a.cleanupRequested.HasValue() || a.target == target {
+		return
+	}
+
+	a.apply(AddonTargetChanged{
+		ID:        a.id,
+		AppID:     a.app,
+		Target:    target,
+		OldTarget: a.target,
+		State:     newAddonState(),
+	})
+}
+
+// Mark the add-on (in the given version) as provisioned. If the version does not match
+// the current one, the result is outdated and nothing will be done.
+func (a *Addon) Provisioned(version time.Time, err error) {
+	if a.IsOutdated(version) {
+		return
+	}
+
+	state := a.state
+
+	if err != nil {
+		state.status = AddonStatusFailed
+		state.errcode.Set(err.Error())
+	} else {
+		state.status = AddonStatusReady
+		state.errcode.Unset()
+	}
+
+	a.apply(AddonStateChanged{
+		ID:    a.id,
+		State: state,
+	})
+}
+
+// Request the removal of the add-on, its service and data will be removed from the target.
+func (a *Addon) RequestCleanup(requestedBy auth.UserID) {
+	if a.cleanupRequested.HasValue() {
+		return
+	}
+
+	a.apply(AddonCleanupRequested{
+		ID:        a.id,
+		AppID:     a.app,
+		Target:    a.target,
+		Requested: shared.NewAction(requestedBy),
+	})
+}
+
+// Deletes the add-on once it has been removed from its target.
+func (a *Addon) Delete(cleanedUp bool) error {
+	if !a.cleanupRequested.HasValue() || !cleanedUp {
+		return ErrAddonCleanupNeeded
+	}
+
+	a.apply(AddonDeleted{
+		ID: a.id,
+	})
+
+	return nil
+}
+
+// Returns true if the given provisioning version is different from the current one,
+// if it has already been processed or if the add-on is being removed.
+func (a *Addon) IsOutdated(version time.Time) bool {
+	return version != a.state.version ||
+		a.state.status != AddonStatusProvisioning ||
+		a.cleanupRequested.HasValue()
+}
+
+// Returns true if the add-on should be used by deployments made with the given config.
+func (a *Addon) IsAvailableFor(config DeploymentConfig) bool {
+	return !a.cleanupRequested.HasValue() &&
+		a.app == config.AppID() &&
+		a.environment == config.Environment() &&
+		a.target == config.Target()
+}
+
+// Hostname of the add-on service, reachable by the app services.
+func (a *Addon) Host() string { return AddonHost(a.id) }
+
+// Connection string given to the app services in the add-on variable.
+func (a *Addon) ConnectionString() string {
+	return AddonConnectionString(a.id, a.kind, a.credentials)
+}
+
+func (a *Addon) ID() AddonID               { return a.id }
+func (a *Addon) AppID() AppID              { return a.app }
+func (a *Addon) Environment() Environment  { return a.environment }
+func (a *Addon) Kind() AddonKind           { return a.kind }
+func (a *Addon) Variable() string          { return a.variable }
+func (a *Addon) Credentials() Credentials  { return a.credentials }
+func (a *Addon) Database() string          { return addonDatabase }
+func (a *Addon) Target() TargetID          { return a.target }
+func (a *Addon) Status() AddonStatus       { return a.state.status }
+func (a *Addon) CurrentVersion() time.Time { return a.state.version }
+
+// Hostname of the add-on with the given ID.
+func AddonHost(id AddonID) string {
+	return "seelf-addon-" + strings.ToLower(string(id))
+}
+
+// Builds the connection string of the add-on with the given ID.
+func AddonConnectionString(id AddonID, kind AddonKind, credentials Credentials) string {
+	u := url.URL{
+		Scheme: string(kind),
+		Host:   AddonHost(id) + ":" + kind.Port().String(),
+	}
+
+	switch kind {
+	case AddonRedis:
+		u.User = url.UserPassword("", credentials.Password())
+	default:
+		u.User = url.UserPassword(credentials.Username(), credentials.Password())
+		u.Path = "/" + addonDatabase
+	}
+
+	return u.String()
+}
+
+func (a *Addon) isReady() bool {
+	return !a.cleanupRequested.HasValue() && a.state.status == AddonStatusReady
+}
+
+func newAddonState() AddonState {
+	return AddonState{
+		status:  AddonStatusProvisioning,
+		version: time.Now().UTC(),
+	}
+}
+
+func (s AddonState) Status() AddonStatus          { return s.status }
+func (s AddonState) Version() time.Time           { return s.version }
+func (s AddonState) ErrCode() monad.Maybe[string] { return s.errcode }
+
+func (a *Addon) apply(e event.Event) {
+	switch evt := e.(type) {
+	case AddonCreated:
+		a.id = evt.ID
+		a.app = evt.AppID
+		a.environment = evt.Environment
+		a.kind = evt.Kind
+		a.variable = evt.Variable
+		a.credentials = evt.Credentials
+		a.target = evt.Target
+		a.state = evt.State
+		a.created = evt.Created
+	case AddonTargetChanged:
+		a.target = evt.Target
+		a.state = evt.State
+	case AddonStateChanged:
+		a.state = evt.State
+	case AddonCleanupRequested:
+		a.cleanupRequested.Set(evt.Requested)
+	}
+
+	event.Store(a, e)
+}
@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrAddonBackupNotSupported = apperr.New("addon_backup_not_supported")
+	ErrAddonNotReady           = apperr.New("addon_not_ready")
+	ErrAddonBackupNotSucceeded = apperr.New("addon_backup_not_succeeded")
+	ErrAddonRestoreInProgress  = apperr.New("addon_restore_in_progress")
+)
+
+const (
+	AddonBackupStatusRunning AddonBackupStatus = iota
+	AddonBackupStatusFailed
+	AddonBackupStatusSucceeded
+)
+
+type (
+	AddonBackupID     string
+	AddonBackupStatus uint8
+
+	// Dump of the data of an add-on, kept in the artifacts storage so it could be
+	// restored later on.
+	AddonBackup struct {
+		event.Emitter
+
+		id        AddonBackupID
+		addon     AddonID
+		app       AppID
+		status    AddonBackupStatus
+		size      int64
+		errcode   monad.Maybe[string]
+		restore   monad.Maybe[AddonRestore]
+		createdAt time.Time
+	}
+
+	// Restoration of a backup requested by a user.
+	AddonRestore struct {
+		requested  shared.Action[auth.UserID]
+		finishedAt monad.Maybe[time.Time]
+		errcode    monad.Maybe[string]
+	}
+
+	AddonBackupsReader interface {
+		GetByID(context.Context, AddonBackupID) (AddonBackup, error)
+		// Retrieve backups of an add-on, most recent first.
+		GetByAddon(context.Context, AddonID) ([]AddonBackup, error)
+	}
+
+	AddonBackupsWriter interface {
+		Write(context.Context, ...*AddonBackup) error
+	}
+
+	// Where backups content lives. Backups are compressed before being stored.
+	AddonBackupsStorage interface {
+		// Store the content written by the dump function and returns its size.
+		Save(ctx context.Context, backup AddonBackup, dump func(io.Writer) error) (int64, error)
+		// Open the uncompressed content of a backup. You MUST close it.
+		Open(context.Context, AddonBackup) (io.ReadCloser, error)
+		Remove(context.Context, AddonBackup) error
+		// Remove every backup of an add-on.
+		RemoveAll(context.Context, AddonID) error
+	}
+
+	AddonBackupCreated struct {
+		bus.Notification
+
+		ID        AddonBackupID
+		AddonID   AddonID
+		AppID     AppID
+		CreatedAt time.Time
+	}
+
+	AddonBackupCompleted struct {
+		bus.Notification
+
+		ID      AddonBackupID
+		Status  AddonBackupStatus
+		Size    int64
+		ErrCode monad.Maybe[string]
+	}
+
+	AddonRestoreRequested struct {
+		bus.Notification
+
+		ID      AddonBackupID
+		AddonID AddonID
+		AppID   AppID
+		Restore AddonRestore
+	}
+
+	AddonRestoreFinished struct {
+		bus.Notification
+
+		ID      AddonBackupID
+		Restore AddonRestore
+	}
+
+	AddonBackupDeleted struct {
+		bus.Notification
+
+		ID AddonBackupID
+	}
+)
+
+func (AddonBackupCreated) Name_() string    { return "deployment.event.addon_backup_created" }
+func (AddonBackupCompleted) Name_() string  { return "deployment.event.addon_backup_completed" }
+func (AddonRestoreRequested) Name_() string { return "deployment.event.addon_restore_requested" }
+func (AddonRestoreFinished) Name_() string  { return "deployment.event.addon_restore_finished" }
+func (AddonBackupDeleted) Name_() string    { return "deployment.event.addon_backup_deleted" }
+
+// Returns true if add-ons of this kind could be backed up.
+func (k AddonKind) SupportsBackup() bool {
+	return k == AddonPostgres || k == AddonMySQL
+}
+
+// Returns nil if the add-on data could be backed up right now.
+func (a *Addon) CanBeBackedUp() error {
+	if !a.kind.SupportsBackup() {
+		return ErrAddonBackupNotSupported
+	}
+
+	if !a.isReady() {
+		return ErrAddonNotReady
+	}
+
+	return nil
+}
+
+// Starts a new backup of the given add-on. Its data should then be dumped and the
+// result given to the Completed method.
+func NewAddonBackup(addon Addon) (b AddonBackup, err error) {
+	if err = addon.CanBeBackedUp(); err != nil {
+		return b, err
+	}
+
+	b.apply(AddonBackupCreated{
+		ID:        id.New[AddonBackupID](),
+		AddonID:   addon.id,
+		AppID:     addon.app,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return b, nil
+}
+
+// Recreates a backup from the persistent storage.
+func AddonBackupFrom(scanner storage.Scanner) (b AddonBackup, err error) {
+	var (
+		restoreRequestedAt monad.Maybe[time.Time]
+		restoreRequestedBy monad.Maybe[string]
+		restoreFinishedAt  monad.Maybe[time.Time]
+		restoreErrCode     monad.Maybe[string]
+	)
+
+	err = scanner.Scan(
+		&b.id,
+		&b.addon,
+		&b.app,
+		&b.status,
+		&b.size,
+		&b.errcode,
+		&restoreRequestedAt,
+		&restoreRequestedBy,
+		&restoreFinishedAt,
+		&restoreErrCode,
+		&b.createdAt,
+	)
+
+	if requestedAt, isSet := restoreRequestedAt.TryGet(); isSet {
+		b.restore.Set(AddonRestore{
+			requested:  shared.ActionFrom(auth.UserID(restoreRequestedBy.MustGet()), requestedAt),
+			finishedAt: restoreFinishedAt,
+			errcode:    restoreErrCode,
+		})
+	}
+
+	return b, err
+}
+
+// Mark the backup as completed with the size of the stored dump or the error which
+// prevented it.
+func (b *AddonBackup) Completed(size int64, err error) {
+	if b.status != AddonBackupStatusRunning {
+		return
+	}
+
+	evt := AddonBackupCompleted{
+		ID:     b.id,
+		Status: AddonBackupStatusSucceeded,
+		Size:   size,
+	}
+
+	if err != nil {
+		evt.Status = AddonBackupStatusFailed
+		evt.Size = 0
+		evt.ErrCode.Set(err.Error())
+	}
+
+	b.apply(evt)
+}
+
+// Request the restoration of this backup in the add-on it has been taken from. Its
+// current data will be replaced.
+func (b *AddonBackup) RequestRestore(addon Addon, requestedBy auth.UserID) error {
+	if b.status != AddonBackupStatusSucceeded {
+		return ErrAddonBackupNotSucceeded
+	}
+
+	if b.IsRestoring() {
+		return ErrAddonRestoreInProgress
+	}
+
+	if !addon.isReady() {
+		return ErrAddonNotReady
+	}
+
+	b.apply(AddonRestoreRequested{
+		ID:      b.id,
+		AddonID: b.addon,
+		AppID:   b.app,
+		Restore: AddonRestore{
+			requested: shared.NewAction(requestedBy),
+		},
+	})
+
+	return nil
+}
+
+// Mark the pending restoration as done, successfully or not.
+func (b *AddonBackup) Restored(err error) {
+	if !b.IsRestoring() {
+		return
+	}
+
+	restore := b.restore.MustGet()
+	restore.finishedAt.Set(time.Now().UTC())
+
+	if err != nil {
+		restore.errcode.Set(err.Error())
+	}
+
+	b.apply(AddonRestoreFinished{
+		ID:      b.id,
+		Restore: restore,
+	})
+}
+
+// Deletes the backup, its content should have been removed from the storage.
+func (b *AddonBackup) Delete() {
+	b.apply(AddonBackupDeleted{
+		ID: b.id,
+	})
+}
+
+// Returns true if a restoration of this backup is pending.
+func (b *AddonBackup) IsRestoring() bool {
+	restore, isSet := b.restore.TryGet()
+
+	return isSet && !restore.finishedAt.HasValue()
+}
+
+func (b *AddonBackup) ID() AddonBackupID         { return b.id }
+func (b *AddonBackup) AddonID() AddonID          { return b.addon }
+func (b *AddonBackup) AppID() AppID              { return b.app }
+func (b *AddonBackup) Status() AddonBackupStatus { return b.status }
+func (b *AddonBackup) Size() int64               { return b.size }
+func (b *AddonBackup) CreatedAt() time.Time      { return b.createdAt }
+
+// Returns backups which exceed the retention, given backups of an add-on sorted from the
+// most recent one. Only successful backups are counted so failed ones never push out a
+// backup which could be restored. Backups being taken or restored are always kept.
+func AddonBackupsToPrune(backups []AddonBackup, retention int) []AddonBackup {
+	var (
+		pruned    []AddonBackup
+		succeeded int
+	)
+
+	for _, backup := range backups {
+		if backup.status == AddonBackupStatusRunning || backup.IsRestoring() {
+			continue
+		}
+
+		if succeeded >= retention {
+			pruned = append(pruned, backup)
+			continue
+		}
+
+		if backup.status == AddonBackupStatusSucceeded {
+			succeeded++
+		}
+	}
+
+	return pruned
+}
+
+func (r AddonRestore) Requested() shared.Action[auth.UserID] { return r.requested }
+func (r AddonRestore) FinishedAt() monad.Maybe[time.Time]    { return r.finishedAt }
+func (r AddonRestore) ErrCode() monad.Maybe[string]          { return r.errcode }
+
+func (b *AddonBackup) apply(e event.Event) {
+	switch evt := e.(type) {
+	case AddonBackupCreated:
+		b.id = evt.ID
+		b.addon = evt.AddonID
+		b.app = evt.AppID
+		b.status = AddonBackupStatusRunning
+		b.createdAt = evt.CreatedAt
+	case AddonBackupCompleted:
+		b.status = evt.Status
+		b.size = evt.Size
+		b.errcode = evt.ErrCode
+	case AddonRestoreRequested:
+		b.restore.Set(evt.Restore)
+	case AddonRestoreFinished:
+		b.restore.Set(evt.Restore)
+	}
+
+	event.Store(b, e)
+}
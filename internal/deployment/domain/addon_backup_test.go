@@ -0,0 +1,157 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AddonBackup(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+		"uid",
+	))
+
+	newAddon := func(kind domain.AddonKind) domain.Addon {
+		addon := must.Panic(domain.NewAddon(app, domain.Production, kind, monad.None[string](), nil, "uid"))
+		addon.Provisioned(addon.CurrentVersion(), nil)
+		return addon
+	}
+
+	newBackup := func(addon domain.Addon) domain.AddonBackup {
+		backup := must.Panic(domain.NewAddonBackup(addon))
+		backup.Completed(42, nil)
+		return backup
+	}
+
+	t.Run("should not be taken for add-ons without data worth it", func(t *testing.T) {
+		_, err := domain.NewAddonBackup(newAddon(domain.AddonRedis))
+
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSupported, err)
+	})
+
+	t.Run("should not be taken until the add-on is ready", func(t *testing.T) {
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		_, err := domain.NewAddonBackup(addon)
+
+		testutil.ErrorIs(t, domain.ErrAddonNotReady, err)
+	})
+
+	t.Run("could be taken from a ready add-on", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+
+		backup, err := domain.NewAddonBackup(addon)
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.AddonBackupCreated](t, &backup, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, addon.ID(), created.AddonID)
+		testutil.Equals(t, app.ID(), created.AppID)
+		testutil.Equals(t, domain.AddonBackupStatusRunning, backup.Status())
+	})
+
+	t.Run("could be marked as succeeded", func(t *testing.T) {
+		backup := must.Panic(domain.NewAddonBackup(newAddon(domain.AddonPostgres)))
+
+		backup.Completed(42, nil)
+
+		completed := testutil.EventIs[domain.AddonBackupCompleted](t, &backup, 1)
+		testutil.Equals(t, domain.AddonBackupStatusSucceeded, completed.Status)
+		testutil.Equals(t, 42, completed.Size)
+		testutil.IsFalse(t, completed.ErrCode.HasValue())
+	})
+
+	t.Run("could be marked as failed", func(t *testing.T) {
+		backup := must.Panic(domain.NewAddonBackup(newAddon(domain.AddonPostgres)))
+
+		backup.Completed(42, errors.New("some_error"))
+
+		completed := testutil.EventIs[domain.AddonBackupCompleted](t, &backup, 1)
+		testutil.Equals(t, domain.AddonBackupStatusFailed, completed.Status)
+		testutil.Equals(t, 0, completed.Size)
+		testutil.Equals(t, "some_error", completed.ErrCode.MustGet())
+	})
+
+	t.Run("should only be completed once", func(t *testing.T) {
+		backup := newBackup(newAddon(domain.AddonPostgres))
+
+		backup.Completed(12, errors.New("some_error"))
+
+		testutil.HasNEvents(t, &backup, 2)
+	})
+
+	t.Run("should not be restored if it has not succeeded", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		backup := must.Panic(domain.NewAddonBackup(addon))
+
+		err := backup.RequestRestore(addon, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSucceeded, err)
+	})
+
+	t.Run("should not be restored if the add-on is not ready", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		backup := newBackup(addon)
+		addon.RequestCleanup("uid")
+
+		err := backup.RequestRestore(addon, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAddonNotReady, err)
+	})
+
+	t.Run("could be restored", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		backup := newBackup(addon)
+
+		err := backup.RequestRestore(addon, "uid")
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, backup.IsRestoring())
+		requested := testutil.EventIs[domain.AddonRestoreRequested](t, &backup, 2)
+		testutil.Equals(t, backup.ID(), requested.ID)
+		testutil.Equals(t, addon.ID(), requested.AddonID)
+		testutil.Equals(t, "uid", requested.Restore.Requested().By())
+
+		testutil.ErrorIs(t, domain.ErrAddonRestoreInProgress, backup.RequestRestore(addon, "uid"))
+
+		backup.Restored(errors.New("some_error"))
+
+		testutil.IsFalse(t, backup.IsRestoring())
+		finished := testutil.EventIs[domain.AddonRestoreFinished](t, &backup, 3)
+		testutil.IsTrue(t, finished.Restore.FinishedAt().HasValue())
+		testutil.Equals(t, "some_error", finished.Restore.ErrCode().MustGet())
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		backup := newBackup(newAddon(domain.AddonPostgres))
+
+		backup.Delete()
+
+		deleted := testutil.EventIs[domain.AddonBackupDeleted](t, &backup, 2)
+		testutil.Equals(t, backup.ID(), deleted.ID)
+	})
+
+	t.Run("should prune backups exceeding the retention", func(t *testing.T) {
+		addon := newAddon(domain.AddonPostgres)
+		running := must.Panic(domain.NewAddonBackup(addon))
+		failed := must.Panic(domain.NewAddonBackup(addon))
+		failed.Completed(0, errors.New("some_error"))
+		recent := newBackup(addon)
+		restoring := newBackup(addon)
+		testutil.IsNil(t, restoring.RequestRestore(addon, "uid"))
+		old := newBackup(addon)
+		older := newBackup(addon)
+
+		pruned := domain.AddonBackupsToPrune([]domain.AddonBackup{running, failed, recent, restoring, old, older}, 1)
+
+		testutil.HasLength(t, pruned, 2)
+		testutil.Equals(t, old.ID(), pruned[0].ID())
+		testutil.Equals(t, older.ID(), pruned[1].ID())
+	})
+}
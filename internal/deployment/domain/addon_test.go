@@ -0,0 +1,178 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AddonKind(t *testing.T) {
+	t.Run("should be created from a known value", func(t *testing.T) {
+		kind, err := domain.AddonKindFrom("postgres")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.AddonPostgres, kind)
+		testutil.Equals(t, "DATABASE_URL", kind.DefaultVariable())
+		testutil.Equals(t, domain.Port(5432), kind.Port())
+	})
+
+	t.Run("should fail on unknown values", func(t *testing.T) {
+		_, err := domain.AddonKindFrom("mongodb")
+
+		testutil.ErrorIs(t, domain.ErrInvalidAddonKind, err)
+	})
+}
+
+func Test_Addon(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+		"uid",
+	))
+
+	t.Run("should require a valid environment", func(t *testing.T) {
+		_, err := domain.NewAddon(app, "dev", domain.AddonPostgres, monad.None[string](), nil, "uid")
+
+		testutil.ErrorIs(t, domain.ErrInvalidEnvironmentName, err)
+	})
+
+	t.Run("should not be created if the app cleanup has been requested", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		app.RequestCleanup("uid")
+
+		_, err := domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+
+	t.Run("could be created on the target of an app environment", func(t *testing.T) {
+		a, err := domain.NewAddon(app, domain.Staging, domain.AddonPostgres, monad.None[string](), nil, "uid")
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.AddonCreated](t, &a, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, app.ID(), created.AppID)
+		testutil.Equals(t, domain.Staging, created.Environment)
+		testutil.Equals(t, domain.AddonPostgres, created.Kind)
+		testutil.Equals(t, "DATABASE_URL", created.Variable)
+		testutil.Equals(t, "staging-target", created.Target)
+		testutil.Equals(t, "seelf", created.Credentials.Username())
+		testutil.Equals(t, 32, len(created.Credentials.Password()))
+		testutil.Equals(t, domain.AddonStatusProvisioning, created.State.Status())
+		testutil.Equals(t, "uid", created.Created.By())
+	})
+
+	t.Run("should use the given variable name", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.Value("CACHE_URL"), nil, "uid"))
+
+		testutil.Equals(t, "CACHE_URL", a.Variable())
+	})
+
+	t.Run("should fail if the kind is already used by the app environment", func(t *testing.T) {
+		existing := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		_, err := domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.Value("OTHER_URL"), []domain.Addon{existing}, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAddonKindAlreadyUsed, err)
+	})
+
+	t.Run("should fail if the variable is already taken by another add-on", func(t *testing.T) {
+		existing := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		_, err := domain.NewAddon(app, domain.Production, domain.AddonMySQL, monad.Value("DATABASE_URL"), []domain.Addon{existing}, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAddonVariableAlreadyTaken, err)
+	})
+
+	t.Run("should build its connection string", func(t *testing.T) {
+		postgres := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		redis := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "uid"))
+
+		testutil.Equals(t, "postgres://seelf:"+postgres.Credentials().Password()+"@"+postgres.Host()+":5432/app", postgres.ConnectionString())
+		testutil.Equals(t, "redis://:"+redis.Credentials().Password()+"@"+redis.Host()+":6379", redis.ConnectionString())
+	})
+
+	t.Run("could be marked as provisioned", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		version := a.CurrentVersion()
+
+		a.Provisioned(version, nil)
+		a.Provisioned(version, nil)
+
+		testutil.HasNEvents(t, &a, 2)
+		changed := testutil.EventIs[domain.AddonStateChanged](t, &a, 1)
+		testutil.Equals(t, domain.AddonStatusReady, changed.State.Status())
+		testutil.IsFalse(t, changed.State.ErrCode().HasValue())
+	})
+
+	t.Run("could be marked as failed", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		a.Provisioned(a.CurrentVersion(), errors.New("some error"))
+
+		changed := testutil.EventIs[domain.AddonStateChanged](t, &a, 1)
+		testutil.Equals(t, domain.AddonStatusFailed, changed.State.Status())
+		testutil.Equals(t, monad.Value("some error"), changed.State.ErrCode())
+	})
+
+	t.Run("could be moved to another target and provisioned again", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+		a.Provisioned(a.CurrentVersion(), nil)
+
+		a.MovedTo("production-target")
+		a.MovedTo("another-target")
+
+		testutil.HasNEvents(t, &a, 3)
+		changed := testutil.EventIs[domain.AddonTargetChanged](t, &a, 2)
+		testutil.Equals(t, "another-target", changed.Target)
+		testutil.Equals(t, "production-target", changed.OldTarget)
+		testutil.Equals(t, domain.AddonStatusProvisioning, changed.State.Status())
+		testutil.IsFalse(t, a.IsOutdated(changed.State.Version()))
+	})
+
+	t.Run("should only be available for deployments on the same app environment and target", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		testutil.IsTrue(t, a.IsAvailableFor(must.Panic(app.ConfigSnapshotFor(domain.Production))))
+		testutil.IsFalse(t, a.IsAvailableFor(must.Panic(app.ConfigSnapshotFor(domain.Staging))))
+
+		a.RequestCleanup("uid")
+
+		testutil.IsFalse(t, a.IsAvailableFor(must.Panic(app.ConfigSnapshotFor(domain.Production))))
+	})
+
+	t.Run("should not be moved once its cleanup has been requested", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		a.RequestCleanup("uid")
+		a.RequestCleanup("uid")
+		a.MovedTo("another-target")
+
+		testutil.HasNEvents(t, &a, 2)
+		requested := testutil.EventIs[domain.AddonCleanupRequested](t, &a, 1)
+		testutil.Equals(t, a.ID(), requested.ID)
+		testutil.Equals(t, "production-target", requested.Target)
+		testutil.Equals(t, "uid", requested.Requested.By())
+	})
+
+	t.Run("should not be deleted until its cleanup has been requested", func(t *testing.T) {
+		a := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		testutil.ErrorIs(t, domain.ErrAddonCleanupNeeded, a.Delete(true))
+
+		a.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAddonCleanupNeeded, a.Delete(false))
+		testutil.IsNil(t, a.Delete(true))
+		deleted := testutil.EventIs[domain.AddonDeleted](t, &a, 2)
+		testutil.Equals(t, a.ID(), deleted.ID)
+	})
+}
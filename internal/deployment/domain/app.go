@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
@@ -20,6 +21,8 @@ var (
 	ErrAppCleanupNeeded            = apperr.New("app_cleanup_needed")
 	ErrAppCleanupRequested         = apperr.New("app_cleanup_requested")
 	ErrAppTargetChanged            = apperr.New("app_target_changed")
+	ErrAppDependencyNotFound       = apperr.New("app_dependency_not_found")
+	ErrAppDependencyCycle          = apperr.New("app_dependency_cycle")
 )
 
 type (
@@ -28,12 +31,23 @@ type (
 
 	App struct {
 		event.Emitter
+		storage.Versioned
 
 		id               AppID
 		name             AppName
 		versionControl   monad.Maybe[VersionControl]
 		production       EnvironmentConfig
 		staging          EnvironmentConfig
+		team             monad.Maybe[TeamID]
+		dependencies     AppDependencies
+		labels           Labels
+		platforms        Platforms
+		buildRegistry    monad.Maybe[RegistryID]    // Registry to which built images are pushed, if any
+		staticSite       monad.Maybe[StaticSite]    // Set when the app is served by the shared static web server of its targets
+		badgeKey         monad.Maybe[string]        // Random key from which badge tokens are signed, unset when badges are disabled
+		deployTrigger    monad.Maybe[DeployTrigger] // Key used to verify signed deployment triggers, unset when they are disabled
+		freezeWindows    FreezeWindows              // Periods during which production deployments of this app are not processed
+		timeouts         DeploymentTimeouts         // Stages timeouts taking precedence over the instance wide ones
 		cleanupRequested monad.Maybe[shared.Action[domain.UserID]]
 		created          shared.Action[domain.UserID]
 	}
@@ -53,8 +67,17 @@ type (
 			production monad.Maybe[EnvironmentConfig],
 			staging monad.Maybe[EnvironmentConfig],
 		) (EnvironmentConfigRequirement, EnvironmentConfigRequirement, error)
+		// Check if the given aliases are not already routed to another app environment on the
+		// target of the given one.
+		CheckUrlAliasesAvailability(context.Context, AppID, Environment, UrlAliases) (UrlAliasesRequirement, error)
 		// Check if a specific target is used by an application.
 		HasAppsOnTarget(context.Context, TargetID) (HasAppsOnTarget, error)
+		// Check if a specific team still has applications.
+		HasAppsInTeam(context.Context, TeamID) (HasAppsInTeam, error)
+		// Retrieve dependencies of every app which has not been requested for cleanup.
+		GetDependencyGraph(context.Context) (AppDependencyGraph, error)
+		// Retrieve apps, not requested for cleanup, with at least one environment on the given target.
+		GetAppsOnTarget(context.Context, TargetID) ([]App, error)
 		GetByID(context.Context, AppID) (App, error)
 	}
 
@@ -94,6 +117,69 @@ type (
 		ID AppID
 	}
 
+	AppTeamChanged struct {
+		bus.Notification
+
+		ID   AppID
+		Team monad.Maybe[TeamID]
+	}
+
+	AppDependenciesChanged struct {
+		bus.Notification
+
+		ID           AppID
+		Dependencies AppDependencies
+	}
+
+	AppLabelsChanged struct {
+		bus.Notification
+
+		ID     AppID
+		Labels Labels
+	}
+
+	AppPlatformsChanged struct {
+		bus.Notification
+
+		ID        AppID
+		Platforms Platforms
+	}
+
+	AppBuildRegistryChanged struct {
+		bus.Notification
+
+		ID       AppID
+		Registry monad.Maybe[RegistryID]
+	}
+
+	AppStaticSiteChanged struct {
+		bus.Notification
+
+		ID         AppID
+		StaticSite monad.Maybe[StaticSite]
+	}
+
+	AppBadgeKeyChanged struct {
+		bus.Notification
+
+		ID  AppID
+		Key monad.Maybe[string]
+	}
+
+	AppDeployTriggerChanged struct {
+		bus.Notification
+
+		ID      AppID
+		Trigger monad.Maybe[DeployTrigger]
+	}
+
+	AppFreezeWindowsChanged struct {
+		bus.Notification
+
+		ID      AppID
+		Windows FreezeWindows
+	}
+
 	AppCleanupRequested struct {
 		bus.Notification
 
@@ -116,6 +202,15 @@ func (AppVersionControlConfigured) Name_() string {
 	return "deployment.event.app_version_control_configured"
 }
 func (AppVersionControlRemoved) Name_() string { return "deployment.event.app_version_control_removed" }
+func (AppTeamChanged) Name_() string           { return "deployment.event.app_team_changed" }
+func (AppDependenciesChanged) Name_() string   { return "deployment.event.app_dependencies_changed" }
+func (AppLabelsChanged) Name_() string         { return "deployment.event.app_labels_changed" }
+func (AppPlatformsChanged) Name_() string      { return "deployment.event.app_platforms_changed" }
+func (AppBuildRegistryChanged) Name_() string  { return "deployment.event.app_build_registry_changed" }
+func (AppStaticSiteChanged) Name_() string     { return "deployment.event.app_static_site_changed" }
+func (AppBadgeKeyChanged) Name_() string       { return "deployment.event.app_badge_key_changed" }
+func (AppDeployTriggerChanged) Name_() string  { return "deployment.event.app_deploy_trigger_changed" }
+func (AppFreezeWindowsChanged) Name_() string  { return "deployment.event.app_freeze_windows_changed" }
 func (AppCleanupRequested) Name_() string      { return "deployment.event.app_cleanup_requested" }
 func (AppDeleted) Name_() string               { return "deployment.event.app_deleted" }
 
@@ -160,6 +255,9 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 		createdBy          domain.UserID
 		cleanupRequestedAt monad.Maybe[time.Time]
 		cleanupRequestedBy monad.Maybe[string]
+		triggerKind        monad.Maybe[DeployTriggerKind]
+		triggerKey         monad.Maybe[string]
+		triggerBy          monad.Maybe[string]
 	)
 
 	err = scanner.Scan(
@@ -170,13 +268,38 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 		&a.production.target,
 		&a.production.version,
 		&a.production.vars,
+		&a.production.exposure,
+		&a.production.protection,
+		&a.production.rules,
+		&a.production.override,
+		&a.production.aliases,
+		&a.production.scale,
 		&a.staging.target,
 		&a.staging.version,
 		&a.staging.vars,
+		&a.staging.exposure,
+		&a.staging.protection,
+		&a.staging.rules,
+		&a.staging.override,
+		&a.staging.aliases,
+		&a.staging.scale,
+		&a.team,
+		&a.dependencies,
+		&a.labels,
+		&a.platforms,
+		&a.buildRegistry,
+		&a.staticSite,
+		&a.badgeKey,
+		&triggerKind,
+		&triggerKey,
+		&triggerBy,
+		&a.freezeWindows,
+		&a.timeouts,
 		&cleanupRequestedAt,
 		&cleanupRequestedBy,
 		&createdAt,
 		&createdBy,
+		&a.Versioned,
 	)
 
 	a.created = shared.ActionFrom(createdBy, createdAt)
@@ -187,6 +310,10 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 		)
 	}
 
+	if kind, isSet := triggerKind.TryGet(); isSet {
+		a.deployTrigger.Set(DeployTriggerFrom(kind, triggerKey.Get(""), domain.UserID(triggerBy.Get(""))))
+	}
+
 	// vcs url has been set, reconstitute the vcs config
 	if u, isSet := url.TryGet(); isSet {
 		vcs := NewVersionControl(u)
@@ -236,14 +363,303 @@ func (a *App) RemoveVersionControl() error {
 	return nil
 }
 
-// Updates the production configuration for this application.
+// Moves the application to the given team, members of this team will have access to it.
+func (a *App) JoinTeam(team Team) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if existing, isSet := a.team.TryGet(); isSet && existing == team.ID() {
+		return nil
+	}
+
+	a.apply(AppTeamChanged{
+		ID:   a.id,
+		Team: monad.Value(team.ID()),
+	})
+
+	return nil
+}
+
+// Removes the application from its team if any.
+func (a *App) LeaveTeam() error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if !a.team.HasValue() {
+		return nil
+	}
+
+	a.apply(AppTeamChanged{
+		ID: a.id,
+	})
+
+	return nil
+}
+
+// Declares apps which should be successfully deployed and healthy before this one
+// is deployed on the same environment. The graph of existing dependencies is used to
+// make sure those apps exist and no cycle is introduced.
+func (a *App) DependsOn(graph AppDependencyGraph, dependencies AppDependencies) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	dependencies = dependencies.normalized()
+
+	for _, dependency := range dependencies {
+		if _, exists := graph[dependency]; !exists {
+			return ErrAppDependencyNotFound
+		}
+
+		if dependency == a.id || graph.reaches(dependency, a.id) {
+			return ErrAppDependencyCycle
+		}
+	}
+
+	if slices.Equal(a.dependencies, dependencies) {
+		return nil
+	}
+
+	a.apply(AppDependenciesChanged{
+		ID:           a.id,
+		Dependencies: dependencies,
+	})
+
+	return nil
+}
+
+// Replaces labels of this application.
+func (a *App) HasLabels(labels Labels) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if slices.Equal(a.labels, labels) {
+		return nil
+	}
+
+	a.apply(AppLabelsChanged{
+		ID:     a.id,
+		Labels: labels,
+	})
+
+	return nil
+}
+
+// Replaces platforms on which this application can run, an empty list meaning
+// any platform.
+func (a *App) HasPlatforms(platforms Platforms) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if slices.Equal(a.platforms, platforms) {
+		return nil
+	}
+
+	a.apply(AppPlatformsChanged{
+		ID:        a.id,
+		Platforms: platforms,
+	})
+
+	return nil
+}
+
+// Pushes images built by deployments of this application to the given registry, so
+// they could be built for multiple platforms at once.
+func (a *App) UseBuildRegistry(registry Registry) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if existing, isSet := a.buildRegistry.TryGet(); isSet && existing == registry.ID() {
+		return nil
+	}
+
+	a.apply(AppBuildRegistryChanged{
+		ID:       a.id,
+		Registry: monad.Value(registry.ID()),
+	})
+
+	return nil
+}
+
+// Stops pushing built images to a registry, they are only built on the target.
+func (a *App) RemoveBuildRegistry() error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if !a.buildRegistry.HasValue() {
+		return nil
+	}
+
+	a.apply(AppBuildRegistryChanged{
+		ID: a.id,
+	})
+
+	return nil
+}
+
+// Serves this application as a static site or, when empty, as a regular one running
+// its own containers.
+func (a *App) HasStaticSite(site monad.Maybe[StaticSite]) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if a.staticSite == site {
+		return nil
+	}
+
+	a.apply(AppStaticSiteChanged{
+		ID:         a.id,
+		StaticSite: site,
+	})
+
+	return nil
+}
+
+// Updates the production configuration for this application. The access protection,
+// proxy rules, compose override, URL aliases and services scale are managed separately
+// and kept as is.
 func (a *App) HasProductionConfig(configRequirement EnvironmentConfigRequirement) error {
-	return a.tryUpdateEnvironmentConfig(Production, configRequirement)
+	return a.tryUpdateEnvironmentConfig(Production, configRequirement.withSettingsOf(a.production))
 }
 
-// Updates the staging configuration for this application.
+// Updates the staging configuration for this application. The access protection,
+// proxy rules, compose override, URL aliases and services scale are managed separately
+// and kept as is.
 func (a *App) HasStagingConfig(configRequirement EnvironmentConfigRequirement) error {
-	return a.tryUpdateEnvironmentConfig(Staging, configRequirement)
+	return a.tryUpdateEnvironmentConfig(Staging, configRequirement.withSettingsOf(a.staging))
+}
+
+// Protects exposed services of the given environment, or removes the protection if
+// none is given. The environment target is left untouched.
+func (a *App) ProtectEnvironment(env Environment, protection monad.Maybe[AccessProtection]) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.protection = protection
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Applies custom proxy rules to exposed services of the given environment, or removes
+// them if none are given. The environment target is left untouched.
+func (a *App) ConfigureProxyRules(env Environment, rules monad.Maybe[ProxyRules]) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.rules = rules
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Merges the given compose override with the compose file of the next deployments of
+// the given environment, or removes it if none is given. The environment target is
+// left untouched.
+func (a *App) OverrideCompose(env Environment, override monad.Maybe[ComposeOverride]) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.override = override
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Routes the given aliases to the service exposed on the default subdomain of the
+// given environment, replacing existing ones. The environment target is left untouched.
+func (a *App) UseUrlAliases(env Environment, aliasesRequirement UrlAliasesRequirement) error {
+	aliases, err := aliasesRequirement.Met()
+
+	if err != nil {
+		return err
+	}
+
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.HasUrlAliases(aliases)
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Removes URL aliases of the given environment.
+func (a *App) RemoveUrlAliases(env Environment) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.aliases = monad.None[UrlAliases]()
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Sets the number of containers to run for a service of the given environment so next
+// deployments keep it. The environment target is left untouched.
+func (a *App) Scale(env Environment, service string, replicas uint) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	config.HasServicesScale(config.scale.Get(nil).with(service, replicas))
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Restores environment variables recorded by the given revision. The environment
+// target is left untouched.
+func (a *App) RestoreEnvRevision(revision EnvRevision) error {
+	if revision.app != a.id {
+		return apperr.ErrNotFound
+	}
+
+	config, err := a.environmentConfig(revision.environment)
+
+	if err != nil {
+		return err
+	}
+
+	config.vars = revision.vars
+
+	return a.tryUpdateEnvironmentConfig(revision.environment, NewEnvironmentConfigRequirement(config, true, true))
+}
+
+// Imports variables of a service in the given environment, combined with the existing
+// ones depending on the mode. The environment target is left untouched.
+func (a *App) ImportEnvironmentVariables(env Environment, service string, vars EnvVars, mode EnvImportMode) error {
+	config, err := a.environmentConfig(env)
+
+	if err != nil {
+		return err
+	}
+
+	if imported := config.vars.Get(nil).Import(service, vars, mode); len(imported) > 0 {
+		config.vars.Set(imported)
+	} else {
+		config.vars.Unset()
+	}
+
+	return a.tryUpdateEnvironmentConfig(env, NewEnvironmentConfigRequirement(config, true, true))
 }
 
 // Request cleaning for this application. This marks the application for deletion.
@@ -274,9 +690,32 @@ func (a *App) Delete(cleanedUp bool) error {
 }
 
 func (a *App) ID() AppID                                   { return a.id }
+func (a *App) CreatedBy() domain.UserID                    { return a.created.By() }
 func (a *App) VersionControl() monad.Maybe[VersionControl] { return a.versionControl }
 func (a *App) Production() EnvironmentConfig               { return a.production }
 func (a *App) Staging() EnvironmentConfig                  { return a.staging }
+func (a *App) Team() monad.Maybe[TeamID]                   { return a.team }
+func (a *App) Dependencies() AppDependencies               { return a.dependencies }
+func (a *App) Labels() Labels                              { return a.labels }
+func (a *App) Platforms() Platforms                        { return a.platforms }
+func (a *App) BuildRegistry() monad.Maybe[RegistryID]      { return a.buildRegistry }
+func (a *App) StaticSite() monad.Maybe[StaticSite]         { return a.staticSite }
+
+// Resources on which a role could be granted to access this app: the app itself,
+// its targets and its team.
+func (a *App) Resources() []domain.Resource {
+	resources := []domain.Resource{
+		domain.NewResource(domain.ResourceApp, string(a.id)),
+		domain.NewResource(domain.ResourceTarget, string(a.production.Target())),
+		domain.NewResource(domain.ResourceTarget, string(a.staging.Target())),
+	}
+
+	if team, isSet := a.team.TryGet(); isSet {
+		resources = append(resources, domain.NewResource(domain.ResourceTeam, string(team)))
+	}
+
+	return resources
+}
 
 func (a *App) tryUpdateEnvironmentConfig(
 	env Environment,
@@ -286,15 +725,10 @@ func (a *App) tryUpdateEnvironmentConfig(
 		return ErrAppCleanupRequested
 	}
 
-	var existingConfig EnvironmentConfig
+	existingConfig, err := a.environmentConfig(env)
 
-	switch env {
-	case Production:
-		existingConfig = a.production
-	case Staging:
-		existingConfig = a.staging
-	default:
-		return ErrInvalidEnvironmentName
+	if err != nil {
+		return err
 	}
 
 	updatedConfig, err := updatedConfigRequirement.Met()
@@ -323,6 +757,17 @@ func (a *App) tryUpdateEnvironmentConfig(
 	return nil
 }
 
+func (a *App) environmentConfig(env Environment) (EnvironmentConfig, error) {
+	switch env {
+	case Production:
+		return a.production, nil
+	case Staging:
+		return a.staging, nil
+	default:
+		return EnvironmentConfig{}, ErrInvalidEnvironmentName
+	}
+}
+
 func (a *App) apply(e event.Event) {
 	switch evt := e.(type) {
 	case AppCreated:
@@ -342,6 +787,26 @@ func (a *App) apply(e event.Event) {
 		a.versionControl.Set(evt.Config)
 	case AppVersionControlRemoved:
 		a.versionControl.Unset()
+	case AppTeamChanged:
+		a.team = evt.Team
+	case AppDependenciesChanged:
+		a.dependencies = evt.Dependencies
+	case AppLabelsChanged:
+		a.labels = evt.Labels
+	case AppPlatformsChanged:
+		a.platforms = evt.Platforms
+	case AppBuildRegistryChanged:
+		a.buildRegistry = evt.Registry
+	case AppStaticSiteChanged:
+		a.staticSite = evt.StaticSite
+	case AppBadgeKeyChanged:
+		a.badgeKey = evt.Key
+	case AppDeployTriggerChanged:
+		a.deployTrigger = evt.Trigger
+	case AppFreezeWindowsChanged:
+		a.freezeWindows = evt.Windows
+	case AppTimeoutsChanged:
+		a.timeouts = evt.Timeouts
 	case AppCleanupRequested:
 		a.cleanupRequested.Set(evt.Requested)
 	}
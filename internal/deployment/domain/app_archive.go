@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrAppArchiveNotSucceeded = apperr.New("app_archive_not_succeeded")
+
+const (
+	AppArchiveStatusRunning AppArchiveStatus = iota
+	AppArchiveStatusFailed
+	AppArchiveStatusSucceeded
+)
+
+type (
+	AppArchiveID     string
+	AppArchiveStatus uint8
+
+	// Export of an application taken before deleting it, with its deployment history,
+	// environment variables and add-ons data. It is kept for a grace period once the
+	// application has been deleted.
+	AppArchive struct {
+		event.Emitter
+
+		id        AppArchiveID
+		app       AppID
+		name      AppName
+		status    AppArchiveStatus
+		size      int64
+		errcode   monad.Maybe[string]
+		expiresAt monad.Maybe[time.Time] // Set once the export has succeeded
+		requested shared.Action[auth.UserID]
+	}
+
+	AppArchivesReader interface {
+		GetByID(context.Context, AppArchiveID) (AppArchive, error)
+		// Retrieve succeeded archives whose grace period has ended at the given date.
+		GetExpired(context.Context, time.Time) ([]AppArchive, error)
+	}
+
+	AppArchivesWriter interface {
+		Write(context.Context, ...*AppArchive) error
+	}
+
+	// Where archives content lives. Archives are gzipped tarballs.
+	AppArchivesStorage interface {
+		// Store the files added by the fill function and returns the archive size.
+		Save(ctx context.Context, archive AppArchive, fill func(AppArchiveWriter) error) (int64, error)
+		// Open the gzipped tarball of an archive. You MUST close it.
+		Open(context.Context, AppArchive) (io.ReadCloser, error)
+		Remove(context.Context, AppArchive) error
+	}
+
+	// Used to add files to an archive being saved.
+	AppArchiveWriter interface {
+		Add(name string, write func(io.Writer) error) error
+	}
+
+	AppArchiveCreated struct {
+		bus.Notification
+
+		ID        AppArchiveID
+		AppID     AppID
+		AppName   AppName
+		Requested shared.Action[auth.UserID]
+	}
+
+	AppArchiveCompleted struct {
+		bus.Notification
+
+		ID        AppArchiveID
+		AppID     AppID
+		Status    AppArchiveStatus
+		Size      int64
+		ErrCode   monad.Maybe[string]
+		ExpiresAt monad.Maybe[time.Time]
+		Requested shared.Action[auth.UserID]
+	}
+
+	AppArchiveDeleted struct {
+		bus.Notification
+
+		ID AppArchiveID
+	}
+)
+
+func (AppArchiveCreated) Name_() string   { return "deployment.event.app_archive_created" }
+func (AppArchiveCompleted) Name_() string { return "deployment.event.app_archive_completed" }
+func (AppArchiveDeleted) Name_() string   { return "deployment.event.app_archive_deleted" }
+
+// Starts the export of an application which will be deleted once it has succeeded.
+// Its content should then be saved and the result given to the Completed method.
+func NewAppArchive(app App, requestedBy auth.UserID) (a AppArchive, err error) {
+	if app.cleanupRequested.HasValue() {
+		return a, ErrAppCleanupRequested
+	}
+
+	a.apply(AppArchiveCreated{
+		ID:        id.New[AppArchiveID](),
+		AppID:     app.id,
+		AppName:   app.name,
+		Requested: shared.NewAction(requestedBy),
+	})
+
+	return a, nil
+}
+
+// Recreates an archive from the persistent storage.
+func AppArchiveFrom(scanner storage.Scanner) (a AppArchive, err error) {
+	var (
+		requestedAt time.Time
+		requestedBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&a.id,
+		&a.app,
+		&a.name,
+		&a.status,
+		&a.size,
+		&a.errcode,
+		&a.expiresAt,
+		&requestedAt,
+		&requestedBy,
+	)
+
+	a.requested = shared.ActionFrom(requestedBy, requestedAt)
+
+	return a, err
+}
+
+// Mark the archive as completed with the size of the stored content or the error which
+// prevented it. A succeeded archive expires once the given grace period has ended.
+func (a *AppArchive) Completed(size int64, err error, gracePeriod time.Duration) {
+	if a.status != AppArchiveStatusRunning {
+		return
+	}
+
+	evt := AppArchiveCompleted{
+		ID:        a.id,
+		AppID:     a.app,
+		Status:    AppArchiveStatusSucceeded,
+		Size:      size,
+		Requested: a.requested,
+	}
+
+	if err != nil {
+		evt.Status = AppArchiveStatusFailed
+		evt.Size = 0
+		evt.ErrCode.Set(err.Error())
+	} else {
+		evt.ExpiresAt.Set(time.Now().UTC().Add(gracePeriod))
+	}
+
+	a.apply(evt)
+}
+
+// Returns nil if the archive content could be retrieved.
+func (a *AppArchive) CanBeDownloaded() error {
+	if a.status != AppArchiveStatusSucceeded {
+		return ErrAppArchiveNotSucceeded
+	}
+
+	return nil
+}
+
+// Deletes the archive, its content should have been removed from the storage.
+func (a *AppArchive) Delete() {
+	a.apply(AppArchiveDeleted{
+		ID: a.id,
+	})
+}
+
+func (a *AppArchive) ID() AppArchiveID                      { return a.id }
+func (a *AppArchive) AppID() AppID                          { return a.app }
+func (a *AppArchive) AppName() AppName                      { return a.name }
+func (a *AppArchive) Status() AppArchiveStatus              { return a.status }
+func (a *AppArchive) Size() int64                           { return a.size }
+func (a *AppArchive) ExpiresAt() monad.Maybe[time.Time]     { return a.expiresAt }
+func (a *AppArchive) Requested() shared.Action[auth.UserID] { return a.requested }
+
+// Resources on which a role could be granted to access this archive, the application
+// it has been taken from.
+func (a *AppArchive) Resources() []auth.Resource {
+	return []auth.Resource{auth.NewResource(auth.ResourceApp, string(a.app))}
+}
+
+func (a *AppArchive) apply(e event.Event) {
+	switch evt := e.(type) {
+	case AppArchiveCreated:
+		a.id = evt.ID
+		a.app = evt.AppID
+		a.name = evt.AppName
+		a.status = AppArchiveStatusRunning
+		a.requested = evt.Requested
+	case AppArchiveCompleted:
+		a.status = evt.Status
+		a.size = evt.Size
+		a.errcode = evt.ErrCode
+		a.expiresAt = evt.ExpiresAt
+	}
+
+	event.Store(a, e)
+}
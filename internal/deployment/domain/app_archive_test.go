@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AppArchive(t *testing.T) {
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+	}
+
+	t.Run("should not be taken if the application cleanup has already been requested", func(t *testing.T) {
+		app := newApp()
+		app.RequestCleanup("uid")
+
+		_, err := domain.NewAppArchive(app, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+
+	t.Run("could be taken from an application", func(t *testing.T) {
+		app := newApp()
+
+		archive, err := domain.NewAppArchive(app, "another-uid")
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.AppArchiveCreated](t, &archive, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, app.ID(), created.AppID)
+		testutil.Equals(t, "my-app", created.AppName)
+		testutil.Equals(t, "another-uid", created.Requested.By())
+		testutil.Equals(t, domain.AppArchiveStatusRunning, archive.Status())
+		testutil.ErrorIs(t, domain.ErrAppArchiveNotSucceeded, archive.CanBeDownloaded())
+	})
+
+	t.Run("could be marked as succeeded and expires after the grace period", func(t *testing.T) {
+		archive := must.Panic(domain.NewAppArchive(newApp(), "uid"))
+		before := time.Now().UTC()
+
+		archive.Completed(42, nil, time.Hour)
+
+		completed := testutil.EventIs[domain.AppArchiveCompleted](t, &archive, 1)
+		testutil.Equals(t, domain.AppArchiveStatusSucceeded, completed.Status)
+		testutil.Equals(t, 42, completed.Size)
+		testutil.IsFalse(t, completed.ErrCode.HasValue())
+		testutil.IsTrue(t, !completed.ExpiresAt.MustGet().Before(before.Add(time.Hour)))
+		testutil.IsNil(t, archive.CanBeDownloaded())
+	})
+
+	t.Run("could be marked as failed", func(t *testing.T) {
+		archive := must.Panic(domain.NewAppArchive(newApp(), "uid"))
+
+		archive.Completed(42, errors.New("some error"), time.Hour)
+
+		completed := testutil.EventIs[domain.AppArchiveCompleted](t, &archive, 1)
+		testutil.Equals(t, domain.AppArchiveStatusFailed, completed.Status)
+		testutil.Equals(t, 0, completed.Size)
+		testutil.Equals(t, "some error", completed.ErrCode.MustGet())
+		testutil.IsFalse(t, completed.ExpiresAt.HasValue())
+		testutil.ErrorIs(t, domain.ErrAppArchiveNotSucceeded, archive.CanBeDownloaded())
+	})
+
+	t.Run("should not be completed twice", func(t *testing.T) {
+		archive := must.Panic(domain.NewAppArchive(newApp(), "uid"))
+		archive.Completed(42, nil, time.Hour)
+
+		archive.Completed(0, errors.New("some error"), time.Hour)
+
+		testutil.HasNEvents(t, &archive, 2)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		archive := must.Panic(domain.NewAppArchive(newApp(), "uid"))
+
+		archive.Delete()
+
+		deleted := testutil.EventIs[domain.AppArchiveDeleted](t, &archive, 1)
+		testutil.Equals(t, archive.ID(), deleted.ID)
+	})
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var (
+	ErrAppBadgeDisabled    = apperr.New("app_badge_disabled")
+	ErrInvalidBadgeToken   = apperr.New("invalid_badge_token")
+	badgeTokenEncoding     = base64.RawURLEncoding
+	badgeKeyLengthInBytes  = 32
+	badgeTokenKeySeparator = "."
+)
+
+// Generates a new badge key for this application, revoking every token issued with
+// the previous one, and returns the token needed to retrieve its deployment badges.
+// Tokens are signed with the given secret so they could not be forged from the
+// stored key alone.
+func (a *App) GenerateBadgeToken(secret []byte) (string, error) {
+	if a.cleanupRequested.HasValue() {
+		return "", ErrAppCleanupRequested
+	}
+
+	key, err := crypto.RandomKey[string](badgeKeyLengthInBytes)
+
+	if err != nil {
+		return "", err
+	}
+
+	a.apply(AppBadgeKeyChanged{
+		ID:  a.id,
+		Key: monad.Value(key),
+	})
+
+	return a.BadgeToken(secret)
+}
+
+// Revokes the badge token of this application, its badges could not be retrieved
+// anymore until a new token is generated.
+func (a *App) RevokeBadgeToken() {
+	if !a.badgeKey.HasValue() {
+		return
+	}
+
+	a.apply(AppBadgeKeyChanged{
+		ID: a.id,
+	})
+}
+
+// Retrieve the current badge token of this application signed with the given secret.
+func (a *App) BadgeToken(secret []byte) (string, error) {
+	key, isSet := a.badgeKey.TryGet()
+
+	if !isSet {
+		return "", ErrAppBadgeDisabled
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(string(a.id) + badgeTokenKeySeparator + key))
+
+	return badgeTokenEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Checks the given token grants access to this application badges.
+func (a *App) CheckBadgeToken(secret []byte, token string) error {
+	expected, err := a.BadgeToken(secret)
+
+	if err != nil || !hmac.Equal([]byte(expected), []byte(token)) {
+		return ErrInvalidBadgeToken
+	}
+
+	return nil
+}
+
+func (a *App) HasBadge() bool { return a.badgeKey.HasValue() }
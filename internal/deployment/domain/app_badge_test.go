@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AppBadge(t *testing.T) {
+	secret := []byte("some-secret")
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+	}
+
+	t.Run("should be disabled by default", func(t *testing.T) {
+		app := newApp()
+
+		_, err := app.BadgeToken(secret)
+
+		testutil.IsFalse(t, app.HasBadge())
+		testutil.ErrorIs(t, domain.ErrAppBadgeDisabled, err)
+		testutil.ErrorIs(t, domain.ErrInvalidBadgeToken, app.CheckBadgeToken(secret, ""))
+	})
+
+	t.Run("should generate a token signed with the given secret", func(t *testing.T) {
+		app := newApp()
+
+		token, err := app.GenerateBadgeToken(secret)
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, app.HasBadge())
+		testutil.NotEquals(t, "", token)
+		testutil.IsNil(t, app.CheckBadgeToken(secret, token))
+		testutil.ErrorIs(t, domain.ErrInvalidBadgeToken, app.CheckBadgeToken([]byte("another-secret"), token))
+		testutil.ErrorIs(t, domain.ErrInvalidBadgeToken, app.CheckBadgeToken(secret, token+"a"))
+
+		evt := testutil.EventIs[domain.AppBadgeKeyChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.IsTrue(t, evt.Key.HasValue())
+	})
+
+	t.Run("should revoke the previous token when generating a new one", func(t *testing.T) {
+		app := newApp()
+
+		first := must.Panic(app.GenerateBadgeToken(secret))
+		second := must.Panic(app.GenerateBadgeToken(secret))
+
+		testutil.NotEquals(t, first, second)
+		testutil.ErrorIs(t, domain.ErrInvalidBadgeToken, app.CheckBadgeToken(secret, first))
+		testutil.IsNil(t, app.CheckBadgeToken(secret, second))
+	})
+
+	t.Run("could revoke its token and raise the event only if it has one", func(t *testing.T) {
+		app := newApp()
+		token := must.Panic(app.GenerateBadgeToken(secret))
+
+		app.RevokeBadgeToken()
+		app.RevokeBadgeToken()
+
+		testutil.HasNEvents(t, &app, 3)
+		evt := testutil.EventIs[domain.AppBadgeKeyChanged](t, &app, 2)
+		testutil.IsFalse(t, evt.Key.HasValue())
+		testutil.IsFalse(t, app.HasBadge())
+		testutil.ErrorIs(t, domain.ErrInvalidBadgeToken, app.CheckBadgeToken(secret, token))
+	})
+
+	t.Run("does not allow to generate a token if the app is marked for deletion", func(t *testing.T) {
+		app := newApp()
+		app.RequestCleanup("uid")
+
+		_, err := app.GenerateBadgeToken(secret)
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"slices"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrAppDependenciesNotReady = apperr.New("app_dependencies_not_ready")
+	ErrAppDependencyFailed     = apperr.New("app_dependency_failed")
+	ErrAppDependencyUnhealthy  = apperr.New("app_dependency_unhealthy")
+)
+
+type (
+	// Apps which should be deployed and healthy before an app is deployed.
+	AppDependencies []AppID
+
+	// Dependencies of every app, used to detect cycles and to order operations
+	// involving multiple apps.
+	AppDependencyGraph map[AppID]AppDependencies
+)
+
+func (d AppDependencies) Value() (driver.Value, error) { return storage.ValueJSON(d) }
+func (d *AppDependencies) Scan(value any) error        { return storage.ScanJSON(value, d) }
+
+// Sorted without duplicates so dependencies could be easily compared.
+func (d AppDependencies) normalized() AppDependencies {
+	if len(d) == 0 {
+		return nil
+	}
+
+	result := slices.Clone(d)
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// Checks if the given app depends, directly or not, on the target one.
+func (g AppDependencyGraph) reaches(from, target AppID) bool {
+	visited := make(map[AppID]bool, len(g))
+	stack := []AppID{from}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current == target {
+			return true
+		}
+
+		if visited[current] {
+			continue
+		}
+
+		visited[current] = true
+		stack = append(stack, g[current]...)
+	}
+
+	return false
+}
+
+// Sorts the given apps so each one comes after its dependencies, keeping the given
+// order otherwise. Dependencies which are not part of the given apps are ignored.
+func (g AppDependencyGraph) Sort(apps []AppID) []AppID {
+	var (
+		wanted  = make(map[AppID]bool, len(apps))
+		visited = make(map[AppID]bool, len(apps))
+		result  = make([]AppID, 0, len(apps))
+		visit   func(AppID)
+	)
+
+	for _, app := range apps {
+		wanted[app] = true
+	}
+
+	visit = func(app AppID) {
+		// Cycles are prevented when declaring dependencies so a visited app is always done
+		if visited[app] {
+			return
+		}
+
+		visited[app] = true
+
+		for _, dependency := range g[app] {
+			if wanted[dependency] {
+				visit(dependency)
+			}
+		}
+
+		result = append(result, app)
+	}
+
+	for _, app := range apps {
+		visit(app)
+	}
+
+	return result
+}
+
+// Checks if a dependency is ready for its dependents to be deployed on an environment
+// given its latest deployment and uptime monitor there, if any.
+// A dependency which has never been deployed on this environment is not waited for.
+func CheckAppDependencyReady(latest monad.Maybe[Deployment], monitor monad.Maybe[Monitor]) error {
+	deployment, isSet := latest.TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	switch deployment.state.status {
+	case DeploymentStatusPending, DeploymentStatusRunning:
+		return ErrAppDependenciesNotReady
+	case DeploymentStatusFailed:
+		return ErrAppDependencyFailed
+	}
+
+	m, isSet := monitor.TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	// Wait for the monitor to check the dependency once it has been deployed
+	lastCheckedAt := m.nextCheckAt.Add(-m.check.interval)
+
+	if m.status == MonitorStatusUnknown || lastCheckedAt.Before(deployment.state.finishedAt.Get(lastCheckedAt)) {
+		return ErrAppDependenciesNotReady
+	}
+
+	if m.status == MonitorStatusDown {
+		return ErrAppDependencyUnhealthy
+	}
+
+	return nil
+}
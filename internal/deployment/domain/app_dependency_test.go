@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AppDependencyGraph(t *testing.T) {
+	t.Run("should sort apps after their dependencies", func(t *testing.T) {
+		graph := domain.AppDependencyGraph{
+			"web":    {"api"},
+			"api":    {"db", "cache"},
+			"db":     nil,
+			"cache":  nil,
+			"worker": {"db"},
+		}
+
+		sorted := graph.Sort([]domain.AppID{"web", "worker", "api", "db", "cache"})
+
+		testutil.DeepEquals(t, []domain.AppID{"db", "cache", "api", "web", "worker"}, sorted)
+	})
+
+	t.Run("should ignore dependencies which are not part of the given apps", func(t *testing.T) {
+		graph := domain.AppDependencyGraph{
+			"web": {"api"},
+			"api": {"db"},
+		}
+
+		testutil.DeepEquals(t, []domain.AppID{"api", "web"}, graph.Sort([]domain.AppID{"web", "api"}))
+	})
+}
+
+func Test_CheckAppDependencyReady(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-db",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "uid"))
+	check := must.Panic(domain.NewUptimeCheck(domain.MinMonitorInterval, domain.DefaultMonitorStatusCode, monad.None[string]()))
+	newMonitor := func() domain.Monitor {
+		return must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+	}
+	succeeded := func() domain.Deployment {
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		depl.HasStarted()
+		depl.HasEnded(domain.Services{}, nil)
+		return depl
+	}
+
+	t.Run("should not wait for a dependency which has never been deployed", func(t *testing.T) {
+		testutil.IsNil(t, domain.CheckAppDependencyReady(monad.None[domain.Deployment](), monad.None[domain.Monitor]()))
+	})
+
+	t.Run("should wait for a dependency being deployed", func(t *testing.T) {
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+
+		testutil.ErrorIs(t, domain.ErrAppDependenciesNotReady, domain.CheckAppDependencyReady(monad.Value(depl), monad.None[domain.Monitor]()))
+	})
+
+	t.Run("should fail if the dependency could not be deployed", func(t *testing.T) {
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		depl.HasStarted()
+		depl.HasEnded(nil, errors.New("build_failed"))
+
+		testutil.ErrorIs(t, domain.ErrAppDependencyFailed, domain.CheckAppDependencyReady(monad.Value(depl), monad.None[domain.Monitor]()))
+	})
+
+	t.Run("should be ready once deployed if the dependency has no monitor", func(t *testing.T) {
+		testutil.IsNil(t, domain.CheckAppDependencyReady(monad.Value(succeeded()), monad.None[domain.Monitor]()))
+	})
+
+	t.Run("should wait for the monitor to check the dependency once deployed", func(t *testing.T) {
+		depl := succeeded()
+		monitor := newMonitor()
+
+		testutil.ErrorIs(t, domain.ErrAppDependenciesNotReady, domain.CheckAppDependencyReady(monad.Value(depl), monad.Value(monitor)))
+
+		monitor.Checked(depl.Config(), domain.ProbeResult{StatusCode: monad.Value(200), CheckedAt: time.Now().UTC()})
+
+		testutil.IsNil(t, domain.CheckAppDependencyReady(monad.Value(depl), monad.Value(monitor)))
+	})
+
+	t.Run("should fail if the dependency is down", func(t *testing.T) {
+		depl := succeeded()
+		monitor := newMonitor()
+		monitor.Checked(depl.Config(), domain.ProbeResult{StatusCode: monad.Value(502), CheckedAt: time.Now().UTC()})
+
+		testutil.ErrorIs(t, domain.ErrAppDependencyUnhealthy, domain.CheckAppDependencyReady(monad.Value(depl), monad.Value(monitor)))
+	})
+}
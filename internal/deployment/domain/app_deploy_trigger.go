@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var (
+	ErrDeployTriggerDisabled         = apperr.New("deploy_trigger_disabled")
+	ErrInvalidDeployTriggerKey       = apperr.New("invalid_deploy_trigger_key")
+	ErrInvalidDeployTriggerSignature = apperr.New("invalid_deploy_trigger_signature")
+	ErrDeployTriggerExpired          = apperr.New("deploy_trigger_expired")
+)
+
+const (
+	DeployTriggerHMAC    DeployTriggerKind = "hmac"    // Payloads are signed with a secret shared with seelf
+	DeployTriggerEd25519 DeployTriggerKind = "ed25519" // Payloads are signed with a private key only known by the caller
+
+	// How far the timestamp of a signed trigger could be from the current time, in
+	// both directions to account for clock drifts.
+	DeployTriggerTolerance = 5 * time.Minute
+
+	deployTriggerSecretLengthInBytes = 32
+	deployTriggerSeparator           = "."
+	deployTriggerPublicKeyPemType    = "PUBLIC KEY"
+)
+
+type (
+	DeployTriggerKind string
+
+	// Key used to verify deployment triggers signed by systems which could not store an
+	// API token securely. Accepted triggers act on behalf of the user who configured it.
+	DeployTrigger struct {
+		kind DeployTriggerKind
+		key  string // Shared secret for hmac triggers, base64 encoded public key for ed25519 ones
+		by   domain.UserID
+	}
+)
+
+// Recreates a deploy trigger from the persistent storage.
+func DeployTriggerFrom(kind DeployTriggerKind, key string, by domain.UserID) DeployTrigger {
+	return DeployTrigger{kind, key, by}
+}
+
+// Parses an ed25519 public key given either as a base64 encoded raw key or as a
+// PEM encoded one, as generated by `openssl pkey -pubout`.
+func Ed25519PublicKeyFrom(value string) (ed25519.PublicKey, error) {
+	value = strings.TrimSpace(value)
+
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		if block.Type != deployTriggerPublicKeyPemType {
+			return nil, ErrInvalidDeployTriggerKey
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+
+		if err != nil {
+			return nil, ErrInvalidDeployTriggerKey
+		}
+
+		publicKey, ok := key.(ed25519.PublicKey)
+
+		if !ok {
+			return nil, ErrInvalidDeployTriggerKey
+		}
+
+		return publicKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidDeployTriggerKey
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
+
+// Generates a new secret used to sign deployment triggers of this application,
+// replacing any previous trigger key, and returns it. It is only returned once.
+func (a *App) GenerateDeployTriggerSecret(by domain.UserID) (string, error) {
+	if a.cleanupRequested.HasValue() {
+		return "", ErrAppCleanupRequested
+	}
+
+	secret, err := crypto.RandomKey[string](deployTriggerSecretLengthInBytes)
+
+	if err != nil {
+		return "", err
+	}
+
+	a.apply(AppDeployTriggerChanged{
+		ID:      a.id,
+		Trigger: monad.Value(DeployTrigger{DeployTriggerHMAC, secret, by}),
+	})
+
+	return secret, nil
+}
+
+// Verifies deployment triggers of this application with the given ed25519 public key,
+// replacing any previous trigger key. The private key never leaves the caller.
+func (a *App) UseDeployTriggerPublicKey(publicKey ed25519.PublicKey, by domain.UserID) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	a.apply(AppDeployTriggerChanged{
+		ID:      a.id,
+		Trigger: monad.Value(DeployTrigger{DeployTriggerEd25519, base64.StdEncoding.EncodeToString(publicKey), by}),
+	})
+
+	return nil
+}
+
+// Removes the deploy trigger of this application, signed triggers are rejected until
+// a new one is configured.
+func (a *App) RemoveDeployTrigger() {
+	if !a.deployTrigger.HasValue() {
+		return
+	}
+
+	a.apply(AppDeployTriggerChanged{
+		ID: a.id,
+	})
+}
+
+// Verifies the given payload has been signed with the deploy trigger of this application
+// and is recent enough, returning the user on behalf of whom the deployment should be
+// queued.
+func (a *App) VerifyDeployTrigger(timestamp, signature string, payload []byte, now time.Time) (domain.UserID, error) {
+	if a.cleanupRequested.HasValue() {
+		return "", ErrAppCleanupRequested
+	}
+
+	trigger, isSet := a.deployTrigger.TryGet()
+
+	if !isSet {
+		return "", ErrDeployTriggerDisabled
+	}
+
+	return trigger.Verify(timestamp, signature, payload, now)
+}
+
+// Verifies the signature of the given payload. The signed message is made of the unix
+// timestamp, a dot and the raw payload so a signature could not be reused with another
+// timestamp. Signatures are given as sha256=<hex hmac> or ed25519=<base64 signature>.
+func (t DeployTrigger) Verify(timestamp, signature string, payload []byte, now time.Time) (domain.UserID, error) {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return "", ErrInvalidDeployTriggerSignature
+	}
+
+	if drift := now.Sub(time.Unix(seconds, 0)).Abs(); drift > DeployTriggerTolerance {
+		return "", ErrDeployTriggerExpired
+	}
+
+	message := append([]byte(timestamp+deployTriggerSeparator), payload...)
+
+	switch t.kind {
+	case DeployTriggerHMAC:
+		value, found := strings.CutPrefix(signature, "sha256=")
+		expected, err := hex.DecodeString(value)
+
+		if !found || err != nil {
+			return "", ErrInvalidDeployTriggerSignature
+		}
+
+		mac := hmac.New(sha256.New, []byte(t.key))
+		mac.Write(message)
+
+		if !hmac.Equal(expected, mac.Sum(nil)) {
+			return "", ErrInvalidDeployTriggerSignature
+		}
+	case DeployTriggerEd25519:
+		value, found := strings.CutPrefix(signature, "ed25519=")
+		sig, err := base64.StdEncoding.DecodeString(value)
+
+		if !found || err != nil {
+			return "", ErrInvalidDeployTriggerSignature
+		}
+
+		publicKey, err := base64.StdEncoding.DecodeString(t.key)
+
+		if err != nil || len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, message, sig) {
+			return "", ErrInvalidDeployTriggerSignature
+		}
+	default:
+		return "", ErrInvalidDeployTriggerSignature
+	}
+
+	return t.by, nil
+}
+
+func (t DeployTrigger) Kind() DeployTriggerKind { return t.kind }
+func (t DeployTrigger) Key() string             { return t.key }
+func (t DeployTrigger) By() domain.UserID       { return t.by }
+
+func (a *App) DeployTrigger() monad.Maybe[DeployTrigger] { return a.deployTrigger }
@@ -0,0 +1,173 @@
+package domain_test
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"strconv"
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AppDeployTrigger(t *testing.T) {
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	payload := []byte(`{"environment":"production"}`)
+
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+	}
+
+	signHMAC := func(secret, timestamp string, payload []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(payload)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	signEd25519 := func(key ed25519.PrivateKey, timestamp string, payload []byte) string {
+		return "ed25519=" + base64.StdEncoding.EncodeToString(ed25519.Sign(key, append([]byte(timestamp+"."), payload...)))
+	}
+
+	t.Run("should be disabled by default", func(t *testing.T) {
+		app := newApp()
+
+		_, err := app.VerifyDeployTrigger(timestamp, "", payload, now)
+
+		testutil.IsFalse(t, app.DeployTrigger().HasValue())
+		testutil.ErrorIs(t, domain.ErrDeployTriggerDisabled, err)
+	})
+
+	t.Run("should parse ed25519 public keys given as base64 or PEM", func(t *testing.T) {
+		publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+		der := must.Panic(x509.MarshalPKIXPublicKey(publicKey))
+
+		key, err := domain.Ed25519PublicKeyFrom(base64.StdEncoding.EncodeToString(publicKey))
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, publicKey, key)
+
+		key, err = domain.Ed25519PublicKeyFrom(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, publicKey, key)
+
+		_, err = domain.Ed25519PublicKeyFrom("not a key")
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerKey, err)
+
+		_, err = domain.Ed25519PublicKeyFrom(base64.StdEncoding.EncodeToString([]byte("too short")))
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerKey, err)
+	})
+
+	t.Run("should generate a secret and verify payloads signed with it", func(t *testing.T) {
+		app := newApp()
+
+		secret, err := app.GenerateDeployTriggerSecret("uid")
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, "", secret)
+
+		uid, err := app.VerifyDeployTrigger(timestamp, signHMAC(secret, timestamp, payload), payload, now)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, auth.UserID("uid"), uid)
+
+		_, err = app.VerifyDeployTrigger(timestamp, signHMAC("another-secret", timestamp, payload), payload, now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+
+		_, err = app.VerifyDeployTrigger(timestamp, signHMAC(secret, timestamp, payload), []byte("{}"), now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+
+		_, err = app.VerifyDeployTrigger(timestamp, signHMAC(secret, timestamp, payload)[len("sha256="):], payload, now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+
+		evt := testutil.EventIs[domain.AppDeployTriggerChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.Equals(t, domain.DeployTriggerHMAC, evt.Trigger.MustGet().Kind())
+	})
+
+	t.Run("should verify payloads signed with the private key of the given public key", func(t *testing.T) {
+		app := newApp()
+		publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+		_, anotherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+		testutil.IsNil(t, app.UseDeployTriggerPublicKey(publicKey, "uid"))
+
+		uid, err := app.VerifyDeployTrigger(timestamp, signEd25519(privateKey, timestamp, payload), payload, now)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, auth.UserID("uid"), uid)
+
+		_, err = app.VerifyDeployTrigger(timestamp, signEd25519(anotherKey, timestamp, payload), payload, now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+
+		evt := testutil.EventIs[domain.AppDeployTriggerChanged](t, &app, 1)
+		testutil.Equals(t, domain.DeployTriggerEd25519, evt.Trigger.MustGet().Kind())
+	})
+
+	t.Run("should reject payloads with a timestamp too far from now", func(t *testing.T) {
+		app := newApp()
+		secret := must.Panic(app.GenerateDeployTriggerSecret("uid"))
+
+		for _, at := range []time.Time{
+			now.Add(-domain.DeployTriggerTolerance - time.Minute),
+			now.Add(domain.DeployTriggerTolerance + time.Minute),
+		} {
+			ts := strconv.FormatInt(at.Unix(), 10)
+
+			_, err := app.VerifyDeployTrigger(ts, signHMAC(secret, ts, payload), payload, now)
+			testutil.ErrorIs(t, domain.ErrDeployTriggerExpired, err)
+		}
+
+		_, err := app.VerifyDeployTrigger("not-a-timestamp", signHMAC(secret, "not-a-timestamp", payload), payload, now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+	})
+
+	t.Run("should replace the previous key when configuring a new one", func(t *testing.T) {
+		app := newApp()
+
+		first := must.Panic(app.GenerateDeployTriggerSecret("uid"))
+		second := must.Panic(app.GenerateDeployTriggerSecret("uid"))
+
+		testutil.NotEquals(t, first, second)
+
+		_, err := app.VerifyDeployTrigger(timestamp, signHMAC(first, timestamp, payload), payload, now)
+		testutil.ErrorIs(t, domain.ErrInvalidDeployTriggerSignature, err)
+	})
+
+	t.Run("could remove its deploy trigger and raise the event only if it has one", func(t *testing.T) {
+		app := newApp()
+
+		app.RemoveDeployTrigger()
+
+		testutil.HasNEvents(t, &app, 1)
+
+		secret := must.Panic(app.GenerateDeployTriggerSecret("uid"))
+		app.RemoveDeployTrigger()
+
+		evt := testutil.EventIs[domain.AppDeployTriggerChanged](t, &app, 2)
+		testutil.IsFalse(t, evt.Trigger.HasValue())
+
+		_, err := app.VerifyDeployTrigger(timestamp, signHMAC(secret, timestamp, payload), payload, now)
+		testutil.ErrorIs(t, domain.ErrDeployTriggerDisabled, err)
+	})
+
+	t.Run("should not be configured if the app cleanup has been requested", func(t *testing.T) {
+		app := newApp()
+		app.RequestCleanup("uid")
+
+		_, err := app.GenerateDeployTriggerSecret("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+}
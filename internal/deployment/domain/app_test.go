@@ -6,6 +6,7 @@ import (
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
@@ -192,6 +193,118 @@ func Test_App(t *testing.T) {
 		testutil.DeepEquals(t, newConfig, evt.Config)
 	})
 
+	t.Run("could protect an environment and keep the protection when its config is updated", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		protection := must.Panic(domain.NewAccessProtection(monad.None[domain.BasicAuth](), []string{"10.0.0.0/8"}, monad.None[domain.Url]()))
+
+		testutil.IsNil(t, app.ProtectEnvironment(domain.Staging, monad.Value(protection)))
+		testutil.IsNil(t, app.ProtectEnvironment(domain.Staging, monad.Value(protection)))
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Staging, evt.Environment)
+		testutil.DeepEquals(t, protection, evt.Config.Protection().MustGet())
+
+		newConfig := domain.NewEnvironmentConfig(staging.Target())
+		newConfig.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		testutil.IsNil(t, app.HasStagingConfig(domain.NewEnvironmentConfigRequirement(newConfig, true, true)))
+		testutil.DeepEquals(t, protection, app.Staging().Protection().MustGet())
+
+		testutil.IsNil(t, app.ProtectEnvironment(domain.Staging, monad.None[domain.AccessProtection]()))
+		testutil.IsFalse(t, app.Staging().Protection().HasValue())
+		testutil.HasNEvents(t, &app, 4)
+	})
+
+	t.Run("could configure proxy rules of an environment and keep them when its config is updated", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		rules := must.Panic(domain.NewProxyRules(true, nil, nil, map[string]string{"X-Frame-Options": "DENY"}))
+
+		testutil.IsNil(t, app.ConfigureProxyRules(domain.Production, monad.Value(rules)))
+		testutil.IsNil(t, app.ConfigureProxyRules(domain.Production, monad.Value(rules)))
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, evt.Environment)
+		testutil.DeepEquals(t, rules, evt.Config.ProxyRules().MustGet())
+
+		newConfig := domain.NewEnvironmentConfig(production.Target())
+		newConfig.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		testutil.IsNil(t, app.HasProductionConfig(domain.NewEnvironmentConfigRequirement(newConfig, true, true)))
+		testutil.DeepEquals(t, rules, app.Production().ProxyRules().MustGet())
+
+		testutil.IsNil(t, app.ConfigureProxyRules(domain.Production, monad.None[domain.ProxyRules]()))
+		testutil.IsFalse(t, app.Production().ProxyRules().HasValue())
+		testutil.HasNEvents(t, &app, 4)
+	})
+
+	t.Run("could route URL aliases to an environment and keep them when its config is updated", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.ErrorIs(t, domain.ErrUrlAliasTaken, app.UseUrlAliases(domain.Production,
+			domain.NewUrlAliasesRequirement(domain.UrlAliases{"example.com"}, false)))
+		testutil.ErrorIs(t, domain.ErrInvalidUrlAlias, app.UseUrlAliases(domain.Production,
+			domain.NewUrlAliasesRequirement(domain.UrlAliases{}, true)))
+
+		testutil.IsNil(t, app.UseUrlAliases(domain.Production,
+			domain.NewUrlAliasesRequirement(domain.UrlAliases{"www.example.com", "example.com", "example.com"}, true)))
+		testutil.IsNil(t, app.UseUrlAliases(domain.Production,
+			domain.NewUrlAliasesRequirement(domain.UrlAliases{"example.com", "www.example.com"}, true)))
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, evt.Environment)
+		testutil.DeepEquals(t, domain.UrlAliases{"example.com", "www.example.com"}, evt.Config.UrlAliases().MustGet())
+
+		newConfig := domain.NewEnvironmentConfig(production.Target())
+		newConfig.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		testutil.IsNil(t, app.HasProductionConfig(domain.NewEnvironmentConfigRequirement(newConfig, true, true)))
+		testutil.DeepEquals(t, domain.UrlAliases{"example.com", "www.example.com"}, app.Production().UrlAliases().MustGet())
+
+		testutil.IsNil(t, app.RemoveUrlAliases(domain.Production))
+		testutil.IsFalse(t, app.Production().UrlAliases().HasValue())
+		testutil.HasNEvents(t, &app, 4)
+	})
+
+	t.Run("could scale a service of an environment and keep it when its config is updated", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.IsNil(t, app.Scale(domain.Production, "app", 3))
+		testutil.IsNil(t, app.Scale(domain.Production, "app", 3))
+		testutil.IsNil(t, app.Scale(domain.Production, "worker", 0))
+		testutil.HasNEvents(t, &app, 3)
+		evt := testutil.EventIs[domain.AppEnvChanged](t, &app, 2)
+		testutil.DeepEquals(t, domain.ServicesScale{"app": 3, "worker": 0}, evt.Config.ServicesScale().MustGet())
+
+		newConfig := domain.NewEnvironmentConfig(production.Target())
+		newConfig.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		testutil.IsNil(t, app.HasProductionConfig(domain.NewEnvironmentConfigRequirement(newConfig, true, true)))
+		testutil.DeepEquals(t, domain.ServicesScale{"app": 3, "worker": 0}, app.Production().ServicesScale().MustGet())
+		testutil.IsFalse(t, app.Staging().ServicesScale().HasValue())
+	})
+
+	t.Run("could override the compose file of an environment and keep it when its config is updated", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		override := must.Panic(domain.ComposeOverrideFrom("services: {app: {restart: always}}"))
+
+		testutil.IsNil(t, app.OverrideCompose(domain.Staging, monad.Value(override)))
+		testutil.IsNil(t, app.OverrideCompose(domain.Staging, monad.Value(override)))
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Staging, evt.Environment)
+		testutil.Equals(t, override, evt.Config.ComposeOverride().MustGet())
+
+		newConfig := domain.NewEnvironmentConfig(staging.Target())
+		newConfig.HasEnvironmentVariables(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		testutil.IsNil(t, app.HasStagingConfig(domain.NewEnvironmentConfigRequirement(newConfig, true, true)))
+		testutil.Equals(t, override, app.Staging().ComposeOverride().MustGet())
+
+		testutil.IsNil(t, app.OverrideCompose(domain.Staging, monad.None[domain.ComposeOverride]()))
+		testutil.IsFalse(t, app.Staging().ComposeOverride().HasValue())
+		testutil.HasNEvents(t, &app, 4)
+	})
+
 	t.Run("does not allow to modify the environment config if the app is marked for deletion", func(t *testing.T) {
 		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
 		app.RequestCleanup("uid")
@@ -200,6 +313,171 @@ func Test_App(t *testing.T) {
 		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.HasStagingConfig(stagingAvailable))
 	})
 
+	t.Run("could join a team and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		team := domain.NewTeam("my-team", uid)
+
+		testutil.IsNil(t, app.JoinTeam(team))
+		testutil.IsNil(t, app.JoinTeam(team))
+
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppTeamChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.Equals(t, team.ID(), evt.Team.MustGet())
+		testutil.Equals(t, auth.NewResource(auth.ResourceTeam, string(team.ID())), app.Resources()[3])
+	})
+
+	t.Run("could leave its team and raise the event only if it has one", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.IsNil(t, app.LeaveTeam())
+		testutil.HasNEvents(t, &app, 1)
+
+		testutil.IsNil(t, app.JoinTeam(domain.NewTeam("my-team", uid)))
+		testutil.IsNil(t, app.LeaveTeam())
+
+		testutil.HasNEvents(t, &app, 3)
+		evt := testutil.EventIs[domain.AppTeamChanged](t, &app, 2)
+		testutil.IsFalse(t, evt.Team.HasValue())
+		testutil.HasLength(t, app.Resources(), 3)
+	})
+
+	t.Run("does not allow to change the team if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.JoinTeam(domain.NewTeam("my-team", uid)))
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.LeaveTeam())
+	})
+
+	t.Run("could depend on other apps and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		graph := domain.AppDependencyGraph{app.ID(): nil, "db": nil, "cache": nil}
+
+		testutil.IsNil(t, app.DependsOn(graph, domain.AppDependencies{"db", "cache", "db"}))
+		testutil.IsNil(t, app.DependsOn(graph, domain.AppDependencies{"cache", "db"}))
+
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppDependenciesChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.DeepEquals(t, domain.AppDependencies{"cache", "db"}, evt.Dependencies)
+	})
+
+	t.Run("should not depend on unknown apps", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.ErrorIs(t, domain.ErrAppDependencyNotFound, app.DependsOn(domain.AppDependencyGraph{app.ID(): nil}, domain.AppDependencies{"db"}))
+	})
+
+	t.Run("should not introduce a dependency cycle", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		graph := domain.AppDependencyGraph{
+			app.ID(): nil,
+			"api":    {"db"},
+			"db":     {app.ID()},
+		}
+
+		testutil.ErrorIs(t, domain.ErrAppDependencyCycle, app.DependsOn(graph, domain.AppDependencies{app.ID()}))
+		testutil.ErrorIs(t, domain.ErrAppDependencyCycle, app.DependsOn(graph, domain.AppDependencies{"api"}))
+	})
+
+	t.Run("does not allow to change dependencies if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.DependsOn(domain.AppDependencyGraph{"db": nil}, domain.AppDependencies{"db"}))
+	})
+
+	t.Run("could have labels and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.IsNil(t, app.HasLabels(domain.Labels{"team-a"}))
+		testutil.IsNil(t, app.HasLabels(domain.Labels{"team-a"}))
+
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppLabelsChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.DeepEquals(t, domain.Labels{"team-a"}, evt.Labels)
+	})
+
+	t.Run("does not allow to change labels if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.HasLabels(domain.Labels{"team-a"}))
+	})
+
+	t.Run("could have platforms and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+
+		testutil.IsNil(t, app.HasPlatforms(domain.Platforms{"linux/arm64"}))
+		testutil.IsNil(t, app.HasPlatforms(domain.Platforms{"linux/arm64"}))
+
+		testutil.HasNEvents(t, &app, 2)
+		evt := testutil.EventIs[domain.AppPlatformsChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.DeepEquals(t, domain.Platforms{"linux/arm64"}, evt.Platforms)
+
+		config := must.Panic(app.ConfigSnapshotFor(domain.Production))
+		testutil.DeepEquals(t, domain.Platforms{"linux/arm64"}, config.Platforms())
+	})
+
+	t.Run("does not allow to change platforms if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.HasPlatforms(domain.Platforms{"linux/arm64"}))
+	})
+
+	t.Run("could push its images to a build registry and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		registry := must.Panic(domain.NewRegistry("my-registry",
+			domain.NewRegistryUrlRequirement(must.Panic(domain.UrlFrom("https://registry.example.com")), true), uid))
+
+		testutil.IsNil(t, app.UseBuildRegistry(registry))
+		testutil.IsNil(t, app.UseBuildRegistry(registry))
+		testutil.IsNil(t, app.RemoveBuildRegistry())
+		testutil.IsNil(t, app.RemoveBuildRegistry())
+
+		testutil.HasNEvents(t, &app, 3)
+		evt := testutil.EventIs[domain.AppBuildRegistryChanged](t, &app, 1)
+		testutil.Equals(t, registry.ID(), evt.Registry.MustGet())
+		evt = testutil.EventIs[domain.AppBuildRegistryChanged](t, &app, 2)
+		testutil.IsFalse(t, evt.Registry.HasValue())
+	})
+
+	t.Run("does not allow to change the build registry if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		registry := must.Panic(domain.NewRegistry("my-registry",
+			domain.NewRegistryUrlRequirement(must.Panic(domain.UrlFrom("https://registry.example.com")), true), uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.UseBuildRegistry(registry))
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.RemoveBuildRegistry())
+	})
+
+	t.Run("could be served as a static site and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		site := must.Panic(domain.StaticSiteFrom("web", "/app/dist"))
+
+		testutil.IsNil(t, app.HasStaticSite(monad.Value(site)))
+		testutil.IsNil(t, app.HasStaticSite(monad.Value(site)))
+		testutil.IsNil(t, app.HasStaticSite(monad.None[domain.StaticSite]()))
+
+		testutil.HasNEvents(t, &app, 3)
+		evt := testutil.EventIs[domain.AppStaticSiteChanged](t, &app, 1)
+		testutil.Equals(t, site, evt.StaticSite.MustGet())
+		evt = testutil.EventIs[domain.AppStaticSiteChanged](t, &app, 2)
+		testutil.IsFalse(t, evt.StaticSite.HasValue())
+	})
+
+	t.Run("does not allow to change the static site if the app is marked for deletion", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.HasStaticSite(monad.None[domain.StaticSite]()))
+	})
+
 	t.Run("could be marked for deletion only if not already the case", func(t *testing.T) {
 		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
 
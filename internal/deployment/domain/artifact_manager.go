@@ -2,13 +2,24 @@ package domain
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
+var ErrBuildDirectoryMissing = apperr.New("build_directory_missing")
+
 type (
 	// Specific context for a deployment.
 	DeploymentContext struct {
-		directory string
-		logger    DeploymentLogger
+		directory     string
+		cache         monad.Maybe[string]
+		reports       DeploymentReports
+		logger        DeploymentLogger
+		resume        monad.Maybe[DeploymentStep]
+		healthTimeout time.Duration
 	}
 
 	// Manage all build artifacts.
@@ -16,20 +27,115 @@ type (
 		// Prepare the build directory and logger for the given deployment.
 		// You MUST close the Logger if no err has been returned.
 		PrepareBuild(context.Context, Deployment) (DeploymentContext, error)
+		// Same as PrepareBuild but keeps the build directory of a previous run of the
+		// deployment. Returns ErrBuildDirectoryMissing if there is nothing to keep.
+		ResumeBuild(context.Context, Deployment) (DeploymentContext, error)
 		// Cleanup an application artifacts.
 		Cleanup(context.Context, AppID) error
-		// Returns the absolute path to a deployment log file.
-		LogPath(context.Context, Deployment) string
+		// Remove the build cache of an application, it will be rebuilt from scratch
+		// by the next deployment.
+		ClearCache(context.Context, AppID) error
+		// Returns the deployment log file, retrieving it from the remote storage first
+		// if it is missing from the disk and one is configured.
+		Log(context.Context, Deployment) DeploymentLog
+		// Returns the records of the deployment log, empty if it does not exist yet.
+		Records(context.Context, Deployment) ([]DeploymentLogRecord, error)
+		// Follow a deployment log while it is being written. The stream chunks channel
+		// is closed when the deployment logger is closed or the context is done.
+		Follow(context.Context, Deployment) DeploymentLogStream
+		// Computes the disk space used by artifacts of every application.
+		Usage(context.Context) (ArtifactsUsage, error)
+		// Remove artifacts of a deployment superseded by the current one of the same app
+		// environment and returns the number of bytes freed. Files shared with the current
+		// deployment, such as a build directory which does not depend on the deployment
+		// number, are kept.
+		Prune(ctx context.Context, depl Deployment, current Deployment) (int64, error)
+		// Returns the directory containing the manifest and software bills of materials
+		// recorded by a deployment.
+		Reports(context.Context, Deployment) DeploymentReports
+		// Open a gzipped tarball of the files fetched and generated by a deployment in its
+		// build directory, retrieving it from the remote storage if it is missing from the
+		// disk and one is configured. If the build directory is shared with the latest
+		// deployment of the same app environment, its files are only available for the
+		// latest one. You MUST close it.
+		BuildContext(ctx context.Context, depl Deployment, latest Deployment) (io.ReadCloser, error)
+	}
+
+	// Disk space used by artifacts of each application, in bytes.
+	ArtifactsUsage map[AppID]int64
+
+	// Store the disk usage computed during the last artifacts collection so it could
+	// be displayed without walking the data directory.
+	ArtifactsUsageWriter interface {
+		WriteArtifactsUsage(context.Context, ArtifactsUsage) error
+	}
+
+	// Deployment log file on the disk.
+	DeploymentLog struct {
+		Path       string // Absolute path to the log file, it does not exist until the deployment has started
+		Compressed bool   // Logs are gzipped once the deployment is done
+	}
+
+	// Live view of a deployment log.
+	DeploymentLogStream struct {
+		DeploymentLog
+		Offset int64         // Size of the log file when the stream was opened, chunks are written after it
+		Chunks <-chan []byte // Chunks written after the offset, nil if the deployment is not running
 	}
 )
 
-// Builds up a new DeploymentContext used by deployment participants.
-func NewDeploymentContext(buildDirectory string, logger DeploymentLogger) DeploymentContext {
+// Builds up a new DeploymentContext used by deployment participants. The cache directory,
+// if any, is kept between deployments of the same app so builds could reuse it.
+func NewDeploymentContext(
+	buildDirectory string,
+	cacheDirectory monad.Maybe[string],
+	reports DeploymentReports,
+	logger DeploymentLogger,
+) DeploymentContext {
 	return DeploymentContext{
 		directory: buildDirectory,
+		cache:     cacheDirectory,
+		reports:   reports,
 		logger:    logger,
 	}
 }
 
-func (d DeploymentContext) BuildDirectory() string   { return d.directory }
-func (d DeploymentContext) Logger() DeploymentLogger { return d.logger }
+func (d DeploymentContext) BuildDirectory() string              { return d.directory }
+func (d DeploymentContext) CacheDirectory() monad.Maybe[string] { return d.cache }
+func (d DeploymentContext) Reports() DeploymentReports          { return d.reports }
+func (d DeploymentContext) Logger() DeploymentLogger            { return d.logger }
+
+// Step from which a previous run of the deployment is resumed, if any. Steps before it
+// have been skipped and their artifacts are reused.
+func (d DeploymentContext) ResumeFrom() monad.Maybe[DeploymentStep] { return d.resume }
+
+// Returns a copy of the context using the given logger, used to decorate the one
+// prepared by the artifact manager.
+func (d DeploymentContext) WithLogger(logger DeploymentLogger) DeploymentContext {
+	d.logger = logger
+	return d
+}
+
+// Returns a copy of the context resuming the deployment from the given step.
+func (d DeploymentContext) ResumingFrom(step DeploymentStep) DeploymentContext {
+	d.resume.Set(step)
+	return d
+}
+
+// Maximum duration to wait for services to be healthy once started, 0 for no limit.
+func (d DeploymentContext) HealthCheckTimeout() time.Duration { return d.healthTimeout }
+
+// Returns a copy of the context waiting at most the given duration for services to be healthy.
+func (d DeploymentContext) WithHealthCheckTimeout(timeout time.Duration) DeploymentContext {
+	d.healthTimeout = timeout
+	return d
+}
+
+// Total disk space used by artifacts of every application.
+func (u ArtifactsUsage) Total() (total int64) {
+	for _, size := range u {
+		total += size
+	}
+
+	return total
+}
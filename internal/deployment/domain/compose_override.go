@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidComposeOverride = apperr.New("invalid_compose_override")
+
+const maxComposeOverrideSize = 64 * 1024
+
+// Compose file content merged with the one of the deployment source for a specific
+// environment, using compose merge semantics, so environment specific tweaks do not
+// have to live in the repository.
+type ComposeOverride string
+
+// Builds a compose override from its raw content which should be a non empty YAML
+// mapping of at most 64KB.
+func ComposeOverrideFrom(value string) (ComposeOverride, error) {
+	if strings.TrimSpace(value) == "" || len(value) > maxComposeOverrideSize {
+		return "", ErrInvalidComposeOverride
+	}
+
+	var content map[string]any
+
+	if err := yaml.Unmarshal([]byte(value), &content); err != nil || len(content) == 0 {
+		return "", ErrInvalidComposeOverride
+	}
+
+	return ComposeOverride(value), nil
+}
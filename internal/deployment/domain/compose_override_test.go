@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ComposeOverride(t *testing.T) {
+	t.Run("should be a non empty YAML mapping", func(t *testing.T) {
+		tests := []string{
+			"",
+			"   ",
+			"- a list",
+			"services: [",
+			"services: " + strings.Repeat("a", 64*1024),
+		}
+
+		for _, value := range tests {
+			t.Run(value[:min(len(value), 16)], func(t *testing.T) {
+				_, err := domain.ComposeOverrideFrom(value)
+
+				testutil.ErrorIs(t, domain.ErrInvalidComposeOverride, err)
+			})
+		}
+	})
+
+	t.Run("could be created from a valid compose file content", func(t *testing.T) {
+		value := `services:
+  app:
+    environment:
+      - DEBUG=false`
+
+		override, err := domain.ComposeOverrideFrom(value)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.ComposeOverride(value), override)
+	})
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
@@ -17,6 +19,7 @@ var (
 	ErrCouldNotPromoteProductionDeployment = apperr.New("could_not_promote_production_deployment")
 	ErrRunningOrPendingDeployments         = apperr.New("running_or_pending_deployments")
 	ErrInvalidSourceDeployment             = apperr.New("invalid_source_deployment")
+	ErrDeploymentSuperseded                = apperr.New("deployment_superseded")
 )
 
 type (
@@ -26,22 +29,34 @@ type (
 
 	Deployment struct {
 		event.Emitter
+		storage.Versioned
 
 		id        DeploymentID
 		config    DeploymentConfig
 		state     DeploymentState
 		source    SourceData
+		labels    Labels
+		note      DeploymentNote
+		metadata  DeploymentMetadata
 		requested shared.Action[domain.UserID]
+		unfrozen  monad.Maybe[shared.Action[domain.UserID]] // Set when an admin allowed it to run during freeze windows
 	}
 
 	DeploymentsReader interface {
 		GetByID(context.Context, DeploymentID) (Deployment, error)
 		GetLastDeployment(context.Context, AppID, Environment) (Deployment, error)
+		// Retrieve the last successful deployment of an app environment started before the given date,
+		// the one whose services were running at that time.
+		GetDeploymentRunningAt(context.Context, AppID, Environment, time.Time) (Deployment, error)
 		GetNextDeploymentNumber(context.Context, AppID) (DeploymentNumber, error)
 		HasRunningOrPendingDeploymentsOnTarget(context.Context, TargetID) (HasRunningOrPendingDeploymentsOnTarget, error)
 		// Retrieve running or pending deployments count for a specific app, target and environment and the successful deployments count
 		// during the specified interval.
 		HasDeploymentsOnAppTargetEnv(context.Context, AppID, TargetID, Environment, shared.TimeInterval) (HasRunningOrPendingDeploymentsOnAppTargetEnv, HasSuccessfulDeploymentsOnAppTargetEnv, error)
+		// Retrieve finished deployments which are not the last one of their app environment anymore, oldest first.
+		GetPrunableDeployments(context.Context) ([]Deployment, error)
+		// Retrieve every deployment of an app, oldest first.
+		GetByApp(context.Context, AppID) ([]Deployment, error)
 	}
 
 	FailCriterias struct {
@@ -73,10 +88,45 @@ type (
 		Config DeploymentConfig
 		State  DeploymentState
 	}
+
+	DeploymentLabelsChanged struct {
+		bus.Notification
+
+		ID     DeploymentID
+		Labels Labels
+	}
+
+	DeploymentAnnotated struct {
+		bus.Notification
+
+		ID       DeploymentID
+		Note     DeploymentNote
+		Metadata DeploymentMetadata
+	}
+
+	DeploymentUnfrozen struct {
+		bus.Notification
+
+		ID       DeploymentID
+		Unfrozen shared.Action[domain.UserID]
+	}
+
+	DeploymentRetried struct {
+		bus.Notification
+
+		ID         DeploymentID
+		Config     DeploymentConfig
+		ResumeFrom DeploymentStep
+		Retried    shared.Action[domain.UserID]
+	}
 )
 
-func (DeploymentCreated) Name_() string      { return "deployment.event.deployment_created" }
-func (DeploymentStateChanged) Name_() string { return "deployment.event.deployment_state_changed" }
+func (DeploymentCreated) Name_() string       { return "deployment.event.deployment_created" }
+func (DeploymentStateChanged) Name_() string  { return "deployment.event.deployment_state_changed" }
+func (DeploymentLabelsChanged) Name_() string { return "deployment.event.deployment_labels_changed" }
+func (DeploymentAnnotated) Name_() string     { return "deployment.event.deployment_annotated" }
+func (DeploymentRetried) Name_() string       { return "deployment.event.deployment_retried" }
+func (DeploymentUnfrozen) Name_() string      { return "deployment.event.deployment_unfrozen" }
 
 func (e DeploymentStateChanged) HasSucceeded() bool {
 	return e.State.status == DeploymentStatusSucceeded
@@ -120,6 +170,8 @@ func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 		requestedBy             domain.UserID
 		sourceMetaDiscriminator string
 		sourceMetaData          string
+		unfrozenAt              monad.Maybe[time.Time]
+		unfrozenBy              monad.Maybe[string]
 	)
 
 	err = scanner.Scan(
@@ -130,8 +182,18 @@ func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 		&d.config.environment,
 		&d.config.target,
 		&d.config.vars,
+		&d.config.exposure,
+		&d.config.protection,
+		&d.config.rules,
+		&d.config.override,
+		&d.config.aliases,
+		&d.config.scale,
+		&d.config.platforms,
+		&d.config.buildRegistry,
+		&d.config.staticSite,
 		&d.state.status,
 		&d.state.errcode,
+		&d.state.reason,
 		&d.state.services,
 		&d.state.startedAt,
 		&d.state.finishedAt,
@@ -139,12 +201,22 @@ func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 		&sourceMetaData,
 		&requestedAt,
 		&requestedBy,
+		&d.labels,
+		&d.note,
+		&d.metadata,
+		&unfrozenAt,
+		&unfrozenBy,
+		&d.Versioned,
 	)
 
 	if err != nil {
 		return d, err
 	}
 
+	if at, isSet := unfrozenAt.TryGet(); isSet {
+		d.unfrozen.Set(shared.ActionFrom(domain.UserID(unfrozenBy.MustGet()), at))
+	}
+
 	d.source, err = SourceDataTypes.From(sourceMetaDiscriminator, sourceMetaData)
 	d.requested = shared.ActionFrom(requestedBy, requestedAt)
 
@@ -161,7 +233,14 @@ func (a *App) Redeploy(
 		return d, ErrInvalidSourceDeployment
 	}
 
-	return a.NewDeployment(deployNumber, source.source, source.config.environment, requestedBy)
+	if d, err = a.NewDeployment(deployNumber, source.source, source.config.environment, requestedBy); err != nil {
+		return d, err
+	}
+
+	d.HasLabels(source.labels)
+	d.Annotate(source.note, source.metadata)
+
+	return d, nil
 }
 
 // Promote the given deployment to the production environment
@@ -178,14 +257,111 @@ func (a *App) Promote(
 		return d, ErrInvalidSourceDeployment
 	}
 
-	return a.NewDeployment(deployNumber, source.source, Production, requestedBy)
+	if d, err = a.NewDeployment(deployNumber, source.source, Production, requestedBy); err != nil {
+		return d, err
+	}
+
+	d.HasLabels(source.labels)
+	d.Annotate(source.note, source.metadata)
+
+	return d, nil
+}
+
+// Retry a failed deployment of this app. Only the latest deployment of an environment
+// could be retried since its build directory and images are shared with the next ones.
+// The step at which it has failed, if known, determines where it will be resumed.
+func (a *App) RetryDeployment(
+	d *Deployment,
+	latest Deployment,
+	failedStep monad.Maybe[DeploymentStep],
+	requestedBy domain.UserID,
+) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if d.id.appID != a.id {
+		return ErrInvalidSourceDeployment
+	}
+
+	if latest.id != d.id {
+		return ErrDeploymentSuperseded
+	}
+
+	conf, err := a.ConfigSnapshotFor(d.config.environment)
+
+	if err != nil {
+		return err
+	}
+
+	if conf.target != d.config.target {
+		return ErrAppTargetChanged
+	}
+
+	if err = d.state.Retried(); err != nil {
+		return err
+	}
+
+	d.apply(DeploymentRetried{
+		ID:         d.id,
+		Config:     d.config,
+		ResumeFrom: DeploymentResumeStep(failedStep),
+		Retried:    shared.NewAction(requestedBy),
+	})
+
+	d.stateChanged()
+
+	return nil
+}
+
+// Step from which a deployment which has failed at the given step should be resumed.
+// Fetched files are reused once they have been retrieved and built images once the
+// deploy step has been reached, everything is done again if the step is not known.
+func DeploymentResumeStep(failedStep monad.Maybe[DeploymentStep]) DeploymentStep {
+	switch failedStep.Get(DeploymentStepFetch) {
+	case DeploymentStepBuild, DeploymentStepScan:
+		return DeploymentStepBuild
+	case DeploymentStepDeploy, DeploymentStepCleanup:
+		return DeploymentStepDeploy
+	default:
+		return DeploymentStepFetch
+	}
 }
 
 func (d *Deployment) ID() DeploymentID                        { return d.id }
 func (d *Deployment) Config() DeploymentConfig                { return d.config }
+func (d *Deployment) State() DeploymentState                  { return d.state }
 func (d *Deployment) Source() SourceData                      { return d.source }
+func (d *Deployment) Labels() Labels                          { return d.labels }
+func (d *Deployment) Note() DeploymentNote                    { return d.note }
+func (d *Deployment) Metadata() DeploymentMetadata            { return d.metadata }
 func (d *Deployment) Requested() shared.Action[domain.UserID] { return d.requested }
 
+// Replaces labels of the deployment.
+func (d *Deployment) HasLabels(labels Labels) {
+	if slices.Equal(d.labels, labels) {
+		return
+	}
+
+	d.apply(DeploymentLabelsChanged{
+		ID:     d.id,
+		Labels: labels,
+	})
+}
+
+// Replaces the note and metadata of the deployment.
+func (d *Deployment) Annotate(note DeploymentNote, metadata DeploymentMetadata) {
+	if d.note == note && maps.Equal(d.metadata, metadata) {
+		return
+	}
+
+	d.apply(DeploymentAnnotated{
+		ID:       d.id,
+		Note:     note,
+		Metadata: metadata,
+	})
+}
+
 // Mark a deployment has started.
 func (d *Deployment) HasStarted() error {
 	err := d.state.Started()
@@ -242,6 +418,13 @@ func (d *Deployment) apply(e event.Event) {
 		d.requested = evt.Requested
 	case DeploymentStateChanged:
 		d.state = evt.State
+	case DeploymentLabelsChanged:
+		d.labels = evt.Labels
+	case DeploymentAnnotated:
+		d.note = evt.Note
+		d.metadata = evt.Metadata
+	case DeploymentUnfrozen:
+		d.unfrozen.Set(evt.Unfrozen)
 	}
 
 	event.Store(d, e)
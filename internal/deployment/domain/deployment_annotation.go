@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidDeploymentNote     = apperr.New("invalid_deployment_note")
+	ErrInvalidDeploymentMetadata = apperr.New("invalid_deployment_metadata")
+
+	metadataKeyRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+)
+
+const (
+	maxDeploymentNoteLength    = 2000
+	maxMetadataValueLength     = 255
+	maxDeploymentMetadataCount = 20
+)
+
+type (
+	// Free-text note attached to a deployment, such as the description of the changes it
+	// brings. Empty when not set.
+	DeploymentNote string
+
+	// Structured key/value data attached to a deployment to correlate it with change
+	// management tools, such as a ticket identifier or a release version.
+	DeploymentMetadata map[string]string
+)
+
+// Builds a note from a raw value. It is trimmed and should not be longer than 2000 characters.
+func DeploymentNoteFrom(value string) (DeploymentNote, error) {
+	note := strings.TrimSpace(value)
+
+	if len(note) > maxDeploymentNoteLength {
+		return "", ErrInvalidDeploymentNote
+	}
+
+	return DeploymentNote(note), nil
+}
+
+// Builds metadata from raw values. Keys should be lowercase alphanumeric characters, dots,
+// dashes or underscores and values, once trimmed, should not be empty nor longer than 255
+// characters. At most 20 entries are allowed.
+func DeploymentMetadataFrom(values map[string]string) (DeploymentMetadata, error) {
+	if len(values) > maxDeploymentMetadataCount {
+		return nil, ErrInvalidDeploymentMetadata
+	}
+
+	metadata := make(DeploymentMetadata, len(values))
+
+	for key, value := range values {
+		value = strings.TrimSpace(value)
+
+		if !metadataKeyRegex.MatchString(key) || value == "" || len(value) > maxMetadataValueLength {
+			return nil, ErrInvalidDeploymentMetadata
+		}
+
+		metadata[key] = value
+	}
+
+	return metadata, nil
+}
+
+func (m DeploymentMetadata) Value() (driver.Value, error) {
+	// Always stored as an object so it could be queried with json_each
+	if m == nil {
+		m = DeploymentMetadata{}
+	}
+
+	return storage.ValueJSON(m)
+}
+
+func (m *DeploymentMetadata) Scan(value any) error { return storage.ScanJSON(value, m) }
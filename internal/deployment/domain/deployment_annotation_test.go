@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentNote(t *testing.T) {
+	t.Run("should be trimmed", func(t *testing.T) {
+		note, err := domain.DeploymentNoteFrom("  Fix the login page ")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "Fix the login page", note)
+	})
+
+	t.Run("should reject too long notes", func(t *testing.T) {
+		_, err := domain.DeploymentNoteFrom(strings.Repeat("a", 2001))
+
+		testutil.ErrorIs(t, domain.ErrInvalidDeploymentNote, err)
+	})
+}
+
+func Test_DeploymentMetadata(t *testing.T) {
+	t.Run("should trim values", func(t *testing.T) {
+		metadata, err := domain.DeploymentMetadataFrom(map[string]string{"ticket": " OPS-42 ", "release.version": "1.0.0"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.DeploymentMetadata{"ticket": "OPS-42", "release.version": "1.0.0"}, metadata)
+	})
+
+	t.Run("should reject invalid keys or values", func(t *testing.T) {
+		_, err := domain.DeploymentMetadataFrom(map[string]string{"Ticket ID": "OPS-42"})
+		testutil.ErrorIs(t, domain.ErrInvalidDeploymentMetadata, err)
+
+		_, err = domain.DeploymentMetadataFrom(map[string]string{"ticket": " "})
+		testutil.ErrorIs(t, domain.ErrInvalidDeploymentMetadata, err)
+
+		_, err = domain.DeploymentMetadataFrom(map[string]string{"ticket": strings.Repeat("a", 256)})
+		testutil.ErrorIs(t, domain.ErrInvalidDeploymentMetadata, err)
+	})
+}
@@ -10,11 +10,20 @@ import (
 // have everything needed to resolve service and image names and is the primarly used
 // structure during the deployment by a provider.
 type DeploymentConfig struct {
-	appid       AppID
-	appname     AppName
-	environment Environment
-	target      TargetID
-	vars        monad.Maybe[ServicesEnv]
+	appid         AppID
+	appname       AppName
+	environment   Environment
+	target        TargetID
+	vars          monad.Maybe[ServicesEnv]
+	exposure      monad.Maybe[ServicesExposure]
+	protection    monad.Maybe[AccessProtection]
+	rules         monad.Maybe[ProxyRules]
+	override      monad.Maybe[ComposeOverride]
+	aliases       monad.Maybe[UrlAliases]
+	scale         monad.Maybe[ServicesScale]
+	platforms     Platforms
+	buildRegistry monad.Maybe[RegistryID]
+	staticSite    monad.Maybe[StaticSite]
 }
 
 // Builds a new config snapshot for the given environment.
@@ -38,15 +47,37 @@ func (a *App) ConfigSnapshotFor(env Environment) (DeploymentConfig, error) {
 	snapshot.environment = env
 	snapshot.target = conf.Target()
 	snapshot.vars = conf.Vars()
+	snapshot.exposure = conf.Exposure()
+	snapshot.protection = conf.Protection()
+	snapshot.rules = conf.ProxyRules()
+	snapshot.override = conf.ComposeOverride()
+	snapshot.aliases = conf.UrlAliases()
+	snapshot.scale = conf.ServicesScale()
+	snapshot.platforms = a.platforms
+	snapshot.buildRegistry = a.buildRegistry
+	snapshot.staticSite = a.staticSite
 
 	return snapshot, nil
 }
 
-func (c DeploymentConfig) AppID() AppID                   { return c.appid }
-func (c DeploymentConfig) AppName() AppName               { return c.appname }
-func (c DeploymentConfig) Environment() Environment       { return c.environment }
-func (c DeploymentConfig) Target() TargetID               { return c.target }
-func (c DeploymentConfig) Vars() monad.Maybe[ServicesEnv] { return c.vars } // FIXME: If I want to follow my mantra, it should returns a readonly map
+func (c DeploymentConfig) AppID() AppID                              { return c.appid }
+func (c DeploymentConfig) AppName() AppName                          { return c.appname }
+func (c DeploymentConfig) Environment() Environment                  { return c.environment }
+func (c DeploymentConfig) Target() TargetID                          { return c.target }
+func (c DeploymentConfig) Vars() monad.Maybe[ServicesEnv]            { return c.vars } // FIXME: If I want to follow my mantra, it should returns a readonly map
+func (c DeploymentConfig) Exposure() monad.Maybe[ServicesExposure]   { return c.exposure }
+func (c DeploymentConfig) Protection() monad.Maybe[AccessProtection] { return c.protection }
+func (c DeploymentConfig) ProxyRules() monad.Maybe[ProxyRules]       { return c.rules }
+func (c DeploymentConfig) ComposeOverride() monad.Maybe[ComposeOverride] {
+	return c.override
+}
+func (c DeploymentConfig) UrlAliases() monad.Maybe[UrlAliases]       { return c.aliases }
+func (c DeploymentConfig) ServicesScale() monad.Maybe[ServicesScale] { return c.scale }
+func (c DeploymentConfig) Platforms() Platforms                      { return c.platforms }
+func (c DeploymentConfig) BuildRegistry() monad.Maybe[RegistryID] {
+	return c.buildRegistry
+}
+func (c DeploymentConfig) StaticSite() monad.Maybe[StaticSite] { return c.staticSite }
 
 // Retrieve environment variables associated with the given service name.
 // FIXME: If I want to follow my mantra, it should returns a readonly map
@@ -68,6 +99,32 @@ func (c DeploymentConfig) EnvironmentVariablesFor(service string) (m monad.Maybe
 	return m
 }
 
+// Retrieve the exposure overrides associated with the given service name.
+func (c DeploymentConfig) ExposureFor(service string) (m monad.Maybe[ServiceExposure]) {
+	exposure, exists := c.exposure.Get(nil)[service]
+
+	if !exists {
+		return m
+	}
+
+	m.Set(exposure)
+
+	return m
+}
+
+// Retrieve the number of containers to run for the given service name if it has been scaled.
+func (c DeploymentConfig) ReplicasFor(service string) (m monad.Maybe[uint]) {
+	replicas, exists := c.scale.Get(nil)[service]
+
+	if !exists {
+		return m
+	}
+
+	m.Set(replicas)
+
+	return m
+}
+
 // Returns the subdomain that will be used to expose a specific service.
 func (c DeploymentConfig) SubDomain(service string, isDefault bool) string {
 	subdomain := string(c.appname)
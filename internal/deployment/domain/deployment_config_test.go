@@ -16,6 +16,9 @@ func Test_Config(t *testing.T) {
 		"app": {"DEBUG": "false"},
 		"db":  {"USERNAME": "prodadmin"},
 	})
+	production.HasServicesExposure(domain.ServicesExposure{
+		"db": {Disabled: true},
+	})
 
 	staging := domain.NewEnvironmentConfig("staging-target")
 	app := must.Panic(domain.NewApp("my-app",
@@ -56,6 +59,17 @@ func Test_Config(t *testing.T) {
 		testutil.IsFalse(t, conf.EnvironmentVariablesFor("app").HasValue())
 	})
 
+	t.Run("should provide a way to retrieve exposure overrides for a service name", func(t *testing.T) {
+		conf, _ := app.ConfigSnapshotFor(domain.Production)
+
+		testutil.IsFalse(t, conf.ExposureFor("app").HasValue())
+		testutil.DeepEquals(t, domain.ServiceExposure{Disabled: true}, conf.ExposureFor("db").MustGet())
+
+		conf, _ = app.ConfigSnapshotFor(domain.Staging)
+
+		testutil.IsFalse(t, conf.ExposureFor("db").HasValue())
+	})
+
 	t.Run("should generate a subdomain equals to app name if env is production", func(t *testing.T) {
 		conf, _ := app.ConfigSnapshotFor(domain.Production)
 
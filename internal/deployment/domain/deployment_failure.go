@@ -0,0 +1,62 @@
+package domain
+
+import "errors"
+
+const (
+	DeploymentFailureUnknown          DeploymentFailureReason = "unknown"
+	DeploymentFailureSourceFetch      DeploymentFailureReason = "source_fetch_failed"
+	DeploymentFailureComposeInvalid   DeploymentFailureReason = "compose_invalid"
+	DeploymentFailureImagePull        DeploymentFailureReason = "image_pull_failed"
+	DeploymentFailurePortConflict     DeploymentFailureReason = "port_conflict"
+	DeploymentFailureQuotaExceeded    DeploymentFailureReason = "quota_exceeded"
+	DeploymentFailureSecretResolution DeploymentFailureReason = "secret_resolution_failed"
+	DeploymentFailureFrozen           DeploymentFailureReason = "deployment_frozen"
+	DeploymentFailureTimeout          DeploymentFailureReason = "timed_out"
+)
+
+type (
+	// Category of a deployment failure so remediation hints could be given to the user
+	// and failures grouped together. The error code still holds the precise error.
+	DeploymentFailureReason string
+
+	// Error which has been classified with a failure reason. It behaves exactly as the
+	// error it wraps.
+	DeploymentFailure struct {
+		reason DeploymentFailureReason
+		err    error
+	}
+)
+
+// Classifies the given error with a failure reason. If the error has already been
+// classified, the original reason is kept since it is the most precise one.
+func NewDeploymentFailure(reason DeploymentFailureReason, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var failure DeploymentFailure
+
+	if errors.As(err, &failure) {
+		return err
+	}
+
+	return DeploymentFailure{reason, err}
+}
+
+// Retrieve the failure reason of the given error, DeploymentFailureUnknown if it
+// has not been classified.
+func DeploymentFailureReasonOf(err error) DeploymentFailureReason {
+	var failure DeploymentFailure
+
+	if !errors.As(err, &failure) {
+		return DeploymentFailureUnknown
+	}
+
+	return failure.reason
+}
+
+func (r DeploymentFailureReason) String() string { return string(r) }
+
+func (f DeploymentFailure) Reason() DeploymentFailureReason { return f.reason }
+func (f DeploymentFailure) Error() string                   { return f.err.Error() }
+func (f DeploymentFailure) Unwrap() error                   { return f.err }
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentFailure(t *testing.T) {
+	t.Run("should behave as the error it classifies", func(t *testing.T) {
+		err := errors.New("git_clone_failed")
+
+		failure := domain.NewDeploymentFailure(domain.DeploymentFailureSourceFetch, err)
+
+		testutil.Equals(t, "git_clone_failed", failure.Error())
+		testutil.ErrorIs(t, err, failure)
+		testutil.Equals(t, domain.DeploymentFailureSourceFetch, domain.DeploymentFailureReasonOf(failure))
+	})
+
+	t.Run("should return nil if there is no error to classify", func(t *testing.T) {
+		testutil.IsNil(t, domain.NewDeploymentFailure(domain.DeploymentFailureSourceFetch, nil))
+	})
+
+	t.Run("should keep the reason of an already classified error", func(t *testing.T) {
+		failure := domain.NewDeploymentFailure(domain.DeploymentFailureImagePull, errors.New("compose_failed"))
+
+		failure = domain.NewDeploymentFailure(domain.DeploymentFailureSourceFetch, fmt.Errorf("wrapped: %w", failure))
+
+		testutil.Equals(t, domain.DeploymentFailureImagePull, domain.DeploymentFailureReasonOf(failure))
+	})
+
+	t.Run("should return an unknown reason for an unclassified error", func(t *testing.T) {
+		testutil.Equals(t, domain.DeploymentFailureUnknown, domain.DeploymentFailureReasonOf(errors.New("some error")))
+	})
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var ErrDeploymentNotEnded = apperr.New("deployment_not_ended")
+
+type (
+	// Outcome of an ended deployment kept apart from the deployment itself so metrics
+	// could be computed over a period without parsing every deployment log.
+	DeploymentHistoryEntry struct {
+		event.Emitter
+
+		deployment     DeploymentID
+		config         DeploymentConfig
+		succeeded      bool
+		failureReason  monad.Maybe[DeploymentFailureReason]
+		requestedAt    time.Time
+		startedAt      time.Time
+		finishedAt     time.Time
+		buildDuration  monad.Maybe[time.Duration] // Empty if the build step has never been reached
+		deployDuration monad.Maybe[time.Duration] // Empty if the deploy step has never been reached
+	}
+
+	DeploymentHistoryWriter interface {
+		Write(context.Context, ...*DeploymentHistoryEntry) error
+	}
+
+	DeploymentHistoryRecorded struct {
+		bus.Notification
+
+		DeploymentID   DeploymentID
+		Config         DeploymentConfig
+		Succeeded      bool
+		FailureReason  monad.Maybe[DeploymentFailureReason]
+		RequestedAt    time.Time
+		StartedAt      time.Time
+		FinishedAt     time.Time
+		BuildDuration  monad.Maybe[time.Duration]
+		DeployDuration monad.Maybe[time.Duration]
+	}
+)
+
+func (DeploymentHistoryRecorded) Name_() string {
+	return "deployment.event.deployment_history_recorded"
+}
+
+// Records the outcome of an ended deployment. Durations of the build and deploy steps
+// are retrieved from the deployment log steps.
+func NewDeploymentHistoryEntry(depl Deployment, steps []DeploymentLogStep) (e DeploymentHistoryEntry, err error) {
+	state := depl.state
+	finishedAt, ended := state.FinishedAt().TryGet()
+
+	if !ended {
+		return e, ErrDeploymentNotEnded
+	}
+
+	var build, deploy monad.Maybe[time.Duration]
+
+	for _, step := range steps {
+		switch step.Step {
+		case DeploymentStepBuild:
+			build.Set(build.Get(0) + step.Duration)
+		case DeploymentStepDeploy:
+			deploy.Set(deploy.Get(0) + step.Duration)
+		}
+	}
+
+	e.apply(DeploymentHistoryRecorded{
+		DeploymentID:   depl.ID(),
+		Config:         depl.Config(),
+		Succeeded:      state.Status() == DeploymentStatusSucceeded,
+		FailureReason:  state.FailureReason(),
+		RequestedAt:    depl.Requested().At(),
+		StartedAt:      state.StartedAt().Get(finishedAt),
+		FinishedAt:     finishedAt,
+		BuildDuration:  build,
+		DeployDuration: deploy,
+	})
+
+	return e, nil
+}
+
+func (e *DeploymentHistoryEntry) DeploymentID() DeploymentID { return e.deployment }
+func (e *DeploymentHistoryEntry) Succeeded() bool            { return e.succeeded }
+func (e *DeploymentHistoryEntry) FailureReason() monad.Maybe[DeploymentFailureReason] {
+	return e.failureReason
+}
+func (e *DeploymentHistoryEntry) Duration() time.Duration                    { return e.finishedAt.Sub(e.startedAt) }
+func (e *DeploymentHistoryEntry) BuildDuration() monad.Maybe[time.Duration]  { return e.buildDuration }
+func (e *DeploymentHistoryEntry) DeployDuration() monad.Maybe[time.Duration] { return e.deployDuration }
+
+func (e *DeploymentHistoryEntry) apply(evt event.Event) {
+	switch evt := evt.(type) {
+	case DeploymentHistoryRecorded:
+		e.deployment = evt.DeploymentID
+		e.config = evt.Config
+		e.succeeded = evt.Succeeded
+		e.failureReason = evt.FailureReason
+		e.requestedAt = evt.RequestedAt
+		e.startedAt = evt.StartedAt
+		e.finishedAt = evt.FinishedAt
+		e.buildDuration = evt.BuildDuration
+		e.deployDuration = evt.DeployDuration
+	}
+
+	event.Store(e, evt)
+}
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentHistoryEntry(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true), "uid"))
+
+	t.Run("should require an ended deployment", func(t *testing.T) {
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		depl.HasStarted()
+
+		_, err := domain.NewDeploymentHistoryEntry(depl, nil)
+
+		testutil.ErrorIs(t, domain.ErrDeploymentNotEnded, err)
+	})
+
+	t.Run("should record the outcome of an ended deployment with its steps durations", func(t *testing.T) {
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		depl.HasStarted()
+		depl.HasEnded(nil, errors.New("some error"))
+
+		entry, err := domain.NewDeploymentHistoryEntry(depl, []domain.DeploymentLogStep{
+			{Step: domain.DeploymentStepFetch, Duration: time.Second},
+			{Step: domain.DeploymentStepBuild, Duration: 2 * time.Second},
+			{Step: domain.DeploymentStepScan, Duration: time.Second},
+			{Step: domain.DeploymentStepBuild, Duration: 3 * time.Second},
+		})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.DeploymentHistoryRecorded](t, &entry, 0)
+		testutil.Equals(t, depl.ID(), evt.DeploymentID)
+		testutil.DeepEquals(t, depl.Config(), evt.Config)
+		testutil.IsFalse(t, evt.Succeeded)
+		testutil.Equals(t, monad.Value(domain.DeploymentFailureUnknown), evt.FailureReason)
+		testutil.Equals(t, depl.Requested().At(), evt.RequestedAt)
+		testutil.IsFalse(t, evt.FinishedAt.Before(evt.StartedAt))
+		testutil.Equals(t, monad.Value(5*time.Second), evt.BuildDuration)
+		testutil.IsFalse(t, evt.DeployDuration.HasValue())
+	})
+}
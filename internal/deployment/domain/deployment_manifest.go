@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+var ErrUnknownVulnerabilitySeverity = apperr.New("unknown_vulnerability_severity")
+
+const (
+	manifestFilename = "manifest.json"
+
+	SeverityUnknown    VulnerabilitySeverity = "unknown"
+	SeverityNegligible VulnerabilitySeverity = "negligible"
+	SeverityLow        VulnerabilitySeverity = "low"
+	SeverityMedium     VulnerabilitySeverity = "medium"
+	SeverityHigh       VulnerabilitySeverity = "high"
+	SeverityCritical   VulnerabilitySeverity = "critical"
+)
+
+var severitiesRank = map[VulnerabilitySeverity]int{
+	SeverityUnknown:    0,
+	SeverityNegligible: 1,
+	SeverityLow:        2,
+	SeverityMedium:     3,
+	SeverityHigh:       4,
+	SeverityCritical:   5,
+}
+
+type (
+	// Directory where files describing what has been deployed are stored, alongside
+	// other artifacts of a deployment.
+	DeploymentReports struct {
+		Directory string
+	}
+
+	// Record of the images deployed by a successful deployment, used for supply-chain
+	// auditing.
+	DeploymentManifest struct {
+		GeneratedAt time.Time       `json:"generated_at"`
+		Images      []ImageManifest `json:"images"`
+	}
+
+	// Image deployed for a service.
+	ImageManifest struct {
+		Service     string            `json:"service"`
+		Image       string            `json:"image"`
+		ID          string            `json:"id,omitempty"`
+		RepoDigests []string          `json:"repo_digests,omitempty"`
+		Built       bool              `json:"built"`
+		BaseImages  []string          `json:"base_images,omitempty"`
+		BuildArgs   map[string]string `json:"build_args,omitempty"`
+		SBOM        bool              `json:"sbom"` // True if a software bill of materials has been generated for this image
+		// Number of vulnerabilities found per severity if the image has been scanned
+		Vulnerabilities VulnerabilitiesSummary `json:"vulnerabilities,omitempty"`
+	}
+
+	VulnerabilitySeverity  string
+	VulnerabilitiesSummary map[VulnerabilitySeverity]int
+)
+
+// Parses a severity, case insensitive.
+func VulnerabilitySeverityFrom(value string) (VulnerabilitySeverity, error) {
+	severity := VulnerabilitySeverity(strings.ToLower(value))
+
+	if _, isKnown := severitiesRank[severity]; !isKnown {
+		return "", ErrUnknownVulnerabilitySeverity
+	}
+
+	return severity, nil
+}
+
+// Returns true if the severity is the same or higher than the given one.
+func (s VulnerabilitySeverity) AtLeast(other VulnerabilitySeverity) bool {
+	return severitiesRank[s] >= severitiesRank[other]
+}
+
+// Number of vulnerabilities with a severity the same or higher than the given one.
+func (s VulnerabilitiesSummary) AtLeast(threshold VulnerabilitySeverity) (count int) {
+	for severity, found := range s {
+		if severity.AtLeast(threshold) {
+			count += found
+		}
+	}
+
+	return count
+}
+
+func (r DeploymentReports) ManifestPath() string {
+	return filepath.Join(r.Directory, manifestFilename)
+}
+
+// Path to the vulnerability scanner report of the given service image.
+func (r DeploymentReports) ScanPath(service string) string {
+	return filepath.Join(r.Directory, "scan-"+filepath.Base(service)+".json")
+}
+
+// Path to the syft compatible software bill of materials of the given service image.
+func (r DeploymentReports) SBOMPath(service string) string {
+	return filepath.Join(r.Directory, "sbom-"+filepath.Base(service)+".json")
+}
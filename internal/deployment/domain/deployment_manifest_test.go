@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_VulnerabilitySeverity(t *testing.T) {
+	t.Run("should parse a severity regardless of its case", func(t *testing.T) {
+		severity, err := domain.VulnerabilitySeverityFrom("HIGH")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.SeverityHigh, severity)
+	})
+
+	t.Run("should fail on an unknown severity", func(t *testing.T) {
+		_, err := domain.VulnerabilitySeverityFrom("severe")
+
+		testutil.ErrorIs(t, domain.ErrUnknownVulnerabilitySeverity, err)
+	})
+
+	t.Run("should compare severities", func(t *testing.T) {
+		testutil.IsTrue(t, domain.SeverityCritical.AtLeast(domain.SeverityHigh))
+		testutil.IsTrue(t, domain.SeverityHigh.AtLeast(domain.SeverityHigh))
+		testutil.IsFalse(t, domain.SeverityMedium.AtLeast(domain.SeverityHigh))
+	})
+
+	t.Run("should count vulnerabilities at or above a severity", func(t *testing.T) {
+		summary := domain.VulnerabilitiesSummary{
+			domain.SeverityCritical: 1,
+			domain.SeverityHigh:     2,
+			domain.SeverityLow:      5,
+		}
+
+		testutil.Equals(t, 3, summary.AtLeast(domain.SeverityHigh))
+		testutil.Equals(t, 8, summary.AtLeast(domain.SeverityUnknown))
+		testutil.Equals(t, 0, domain.VulnerabilitiesSummary{}.AtLeast(domain.SeverityLow))
+	})
+}
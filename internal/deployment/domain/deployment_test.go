@@ -6,6 +6,7 @@ import (
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
@@ -157,6 +158,35 @@ func Test_Deployment(t *testing.T) {
 		testutil.Equals(t, dpl.Source(), redpl.Source())
 	})
 
+	t.Run("should keep the labels of the redeployed deployment", func(t *testing.T) {
+		dpl := must.Panic(app.NewDeployment(number, nonVcsMeta, domain.Production, uid))
+		dpl.HasLabels(domain.Labels{"release"})
+		dpl.HasLabels(domain.Labels{"release"})
+
+		testutil.HasNEvents(t, &dpl, 2)
+
+		redpl, err := app.Redeploy(dpl, 2, "another-user")
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.Labels{"release"}, redpl.Labels())
+	})
+
+	t.Run("should keep the note and metadata of the redeployed deployment", func(t *testing.T) {
+		dpl := must.Panic(app.NewDeployment(number, nonVcsMeta, domain.Production, uid))
+		dpl.Annotate("Initial release", domain.DeploymentMetadata{"release": "1.0.0"})
+		dpl.Annotate("Initial release", domain.DeploymentMetadata{"release": "1.0.0"})
+
+		testutil.HasNEvents(t, &dpl, 2)
+		evt := testutil.EventIs[domain.DeploymentAnnotated](t, &dpl, 1)
+		testutil.Equals(t, dpl.ID(), evt.ID)
+
+		redpl, err := app.Redeploy(dpl, 2, "another-user")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "Initial release", redpl.Note())
+		testutil.DeepEquals(t, domain.DeploymentMetadata{"release": "1.0.0"}, redpl.Metadata())
+	})
+
 	t.Run("should err if trying to redeploy a deployment on the wrong app", func(t *testing.T) {
 		source := must.Panic(app.NewDeployment(1, nonVcsMeta, domain.Production, uid))
 		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
@@ -192,6 +222,86 @@ func Test_Deployment(t *testing.T) {
 		testutil.Equals(t, domain.Production, promoted.Config().Environment())
 		testutil.Equals(t, dpl.Source(), promoted.Source())
 	})
+
+	failed := func(app domain.App, number domain.DeploymentNumber) domain.Deployment {
+		dpl := must.Panic(app.NewDeployment(number, nonVcsMeta, domain.Production, uid))
+		testutil.IsNil(t, dpl.HasStarted())
+		testutil.IsNil(t, dpl.HasEnded(nil, errors.New("some_error")))
+		return dpl
+	}
+
+	t.Run("could not retry a deployment which has not failed", func(t *testing.T) {
+		dpl := must.Panic(app.NewDeployment(number, nonVcsMeta, domain.Production, uid))
+
+		err := app.RetryDeployment(&dpl, dpl, monad.None[domain.DeploymentStep](), uid)
+
+		testutil.ErrorIs(t, domain.ErrNotInFailedState, err)
+	})
+
+	t.Run("could not retry a deployment superseded by a newer one", func(t *testing.T) {
+		dpl := failed(app, number)
+		latest := failed(app, number+1)
+
+		err := app.RetryDeployment(&dpl, latest, monad.None[domain.DeploymentStep](), uid)
+
+		testutil.ErrorIs(t, domain.ErrDeploymentSuperseded, err)
+	})
+
+	t.Run("could not retry a deployment if the app target has changed", func(t *testing.T) {
+		app := must.Panic(domain.NewApp(appname, productionAvailable, stagingAvailable, uid))
+		dpl := failed(app, number)
+		testutil.IsNil(t, app.HasProductionConfig(domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("another-target"), true, true)))
+
+		err := app.RetryDeployment(&dpl, dpl, monad.None[domain.DeploymentStep](), uid)
+
+		testutil.ErrorIs(t, domain.ErrAppTargetChanged, err)
+	})
+
+	t.Run("should err if trying to retry a deployment on the wrong app", func(t *testing.T) {
+		other := must.Panic(domain.NewApp("another-app", productionAvailable, stagingAvailable, uid))
+		dpl := failed(other, number)
+
+		err := app.RetryDeployment(&dpl, dpl, monad.None[domain.DeploymentStep](), uid)
+
+		testutil.ErrorIs(t, domain.ErrInvalidSourceDeployment, err)
+	})
+
+	t.Run("could retry a failed deployment", func(t *testing.T) {
+		dpl := failed(app, number)
+
+		err := app.RetryDeployment(&dpl, dpl, monad.Value(domain.DeploymentStepScan), "another-user")
+
+		testutil.IsNil(t, err)
+		testutil.HasNEvents(t, &dpl, 5)
+		evt := testutil.EventIs[domain.DeploymentRetried](t, &dpl, 3)
+		testutil.Equals(t, dpl.ID(), evt.ID)
+		testutil.Equals(t, domain.DeploymentStepBuild, evt.ResumeFrom)
+		testutil.Equals(t, "another-user", evt.Retried.By())
+		changed := testutil.EventIs[domain.DeploymentStateChanged](t, &dpl, 4)
+		testutil.Equals(t, domain.DeploymentStatusPending, changed.State.Status())
+		testutil.IsFalse(t, changed.State.ErrCode().HasValue())
+		testutil.IsFalse(t, changed.State.FinishedAt().HasValue())
+	})
+}
+
+func Test_DeploymentResumeStep(t *testing.T) {
+	tests := []struct {
+		failed   monad.Maybe[domain.DeploymentStep]
+		expected domain.DeploymentStep
+	}{
+		{monad.None[domain.DeploymentStep](), domain.DeploymentStepFetch},
+		{monad.Value(domain.DeploymentStepFetch), domain.DeploymentStepFetch},
+		{monad.Value(domain.DeploymentStepBuild), domain.DeploymentStepBuild},
+		{monad.Value(domain.DeploymentStepScan), domain.DeploymentStepBuild},
+		{monad.Value(domain.DeploymentStepDeploy), domain.DeploymentStepDeploy},
+		{monad.Value(domain.DeploymentStepCleanup), domain.DeploymentStepDeploy},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.failed.Get("unknown")), func(t *testing.T) {
+			testutil.Equals(t, tt.expected, domain.DeploymentResumeStep(tt.failed))
+		})
+	}
 }
 
 func Test_DeploymentEvents(t *testing.T) {
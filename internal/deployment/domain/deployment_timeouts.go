@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+const maxDeploymentTimeout = 24 * time.Hour
+
+var (
+	ErrInvalidDeploymentTimeout = apperr.New("invalid_deployment_timeout")
+	ErrDeploymentTimedOut       = apperr.New("deployment_timed_out")
+)
+
+type (
+	// Maximum durations of the deployment pipeline stages after which the deployment is
+	// failed, 0 meaning no limit.
+	DeploymentTimeouts struct {
+		Fetch       time.Duration `json:"fetch,omitempty"`        // From the start of the deployment until its build step
+		Build       time.Duration `json:"build,omitempty"`        // Build and scan steps
+		Deploy      time.Duration `json:"deploy,omitempty"`       // Deploy step, images built by compose included
+		HealthCheck time.Duration `json:"health_check,omitempty"` // Wait for services to be healthy once started
+	}
+
+	AppTimeoutsChanged struct {
+		bus.Notification
+
+		ID       AppID
+		Timeouts DeploymentTimeouts
+	}
+)
+
+func (AppTimeoutsChanged) Name_() string { return "deployment.event.app_timeouts_changed" }
+
+// Builds a stage timeout from a number of seconds, 0 meaning no limit. It could not
+// exceed 24 hours.
+func DeploymentTimeoutFrom(seconds uint) (time.Duration, error) {
+	timeout := time.Duration(seconds) * time.Second
+
+	if timeout > maxDeploymentTimeout {
+		return 0, ErrInvalidDeploymentTimeout
+	}
+
+	return timeout, nil
+}
+
+// Returns those timeouts with the ones set in the given overrides taking precedence.
+func (t DeploymentTimeouts) Merge(overrides DeploymentTimeouts) DeploymentTimeouts {
+	return DeploymentTimeouts{
+		Fetch:       overrideTimeout(t.Fetch, overrides.Fetch),
+		Build:       overrideTimeout(t.Build, overrides.Build),
+		Deploy:      overrideTimeout(t.Deploy, overrides.Deploy),
+		HealthCheck: overrideTimeout(t.HealthCheck, overrides.HealthCheck),
+	}
+}
+
+// Retrieve the timeout of the stage the given pipeline step belongs to, 0 if it has
+// no limit.
+func (t DeploymentTimeouts) Step(step DeploymentStep) time.Duration {
+	switch step {
+	case DeploymentStepFetch:
+		return t.Fetch
+	case DeploymentStepBuild, DeploymentStepScan:
+		return t.Build
+	case DeploymentStepDeploy:
+		return t.Deploy
+	default:
+		return 0
+	}
+}
+
+func (t DeploymentTimeouts) Value() (driver.Value, error) { return storage.ValueJSON(t) }
+func (t *DeploymentTimeouts) Scan(value any) error        { return storage.ScanJSON(value, t) }
+
+// Replaces the timeouts of the deployment pipeline stages of this application, taking
+// precedence over the instance wide ones.
+func (a *App) UseTimeouts(timeouts DeploymentTimeouts) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if a.timeouts == timeouts {
+		return nil
+	}
+
+	a.apply(AppTimeoutsChanged{
+		ID:       a.id,
+		Timeouts: timeouts,
+	})
+
+	return nil
+}
+
+func (a *App) Timeouts() DeploymentTimeouts { return a.timeouts }
+
+func overrideTimeout(value, override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+
+	return value
+}
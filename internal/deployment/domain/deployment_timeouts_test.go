@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentTimeouts(t *testing.T) {
+	t.Run("should not exceed 24 hours", func(t *testing.T) {
+		_, err := domain.DeploymentTimeoutFrom(24*60*60 + 1)
+		testutil.ErrorIs(t, domain.ErrInvalidDeploymentTimeout, err)
+
+		timeout, err := domain.DeploymentTimeoutFrom(90)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 90*time.Second, timeout)
+	})
+
+	t.Run("should be merged with overrides", func(t *testing.T) {
+		timeouts := domain.DeploymentTimeouts{Fetch: time.Minute, Build: time.Hour}
+
+		merged := timeouts.Merge(domain.DeploymentTimeouts{Build: 10 * time.Minute, HealthCheck: time.Minute})
+
+		testutil.Equals(t, domain.DeploymentTimeouts{
+			Fetch:       time.Minute,
+			Build:       10 * time.Minute,
+			HealthCheck: time.Minute,
+		}, merged)
+	})
+
+	t.Run("should retrieve the timeout of a pipeline step", func(t *testing.T) {
+		timeouts := domain.DeploymentTimeouts{Fetch: time.Minute, Build: time.Hour, Deploy: 2 * time.Hour}
+
+		testutil.Equals(t, time.Minute, timeouts.Step(domain.DeploymentStepFetch))
+		testutil.Equals(t, time.Hour, timeouts.Step(domain.DeploymentStepBuild))
+		testutil.Equals(t, time.Hour, timeouts.Step(domain.DeploymentStepScan))
+		testutil.Equals(t, 2*time.Hour, timeouts.Step(domain.DeploymentStepDeploy))
+	})
+
+	t.Run("could be changed on an app and raise the event only if different", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid"))
+		timeouts := domain.DeploymentTimeouts{Build: time.Minute}
+
+		testutil.IsNil(t, app.UseTimeouts(timeouts))
+		testutil.IsNil(t, app.UseTimeouts(timeouts))
+
+		testutil.HasNEvents(t, &app, 2)
+		changed := testutil.EventIs[domain.AppTimeoutsChanged](t, &app, 1)
+		testutil.Equals(t, timeouts, changed.Timeouts)
+		testutil.Equals(t, timeouts, app.Timeouts())
+	})
+}
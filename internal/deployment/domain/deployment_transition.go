@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+const (
+	DeploymentTransitionQueued    DeploymentTransitionState = "queued"
+	DeploymentTransitionSucceeded DeploymentTransitionState = "succeeded"
+	DeploymentTransitionFailed    DeploymentTransitionState = "failed"
+	DeploymentTransitionRestarted DeploymentTransitionState = "restarted" // Services of a running deployment have been restarted
+)
+
+type (
+	// State reached by a deployment, either its status or the pipeline step it is running.
+	DeploymentTransitionState string
+
+	// Moment a deployment has reached a new state, kept to build its timeline.
+	DeploymentTransition struct {
+		event.Emitter
+
+		deployment DeploymentID
+		state      DeploymentTransitionState
+		occurredAt time.Time
+	}
+
+	DeploymentTransitionsWriter interface {
+		Write(context.Context, ...*DeploymentTransition) error
+	}
+
+	DeploymentTransitionRecorded struct {
+		bus.Notification
+
+		DeploymentID DeploymentID
+		State        DeploymentTransitionState
+		OccurredAt   time.Time
+	}
+)
+
+func (DeploymentTransitionRecorded) Name_() string {
+	return "deployment.event.deployment_transition_recorded"
+}
+
+// Transition state of a running deployment entering the given pipeline step.
+func DeploymentTransitionStep(step DeploymentStep) DeploymentTransitionState {
+	return DeploymentTransitionState(step)
+}
+
+// Transition state matching the given deployment state. Since the logger of a running
+// deployment starts at the fetch step, it is the one reached when the deployment starts.
+func DeploymentTransitionStateFrom(state DeploymentState) DeploymentTransitionState {
+	switch state.Status() {
+	case DeploymentStatusRunning:
+		return DeploymentTransitionStep(DeploymentStepFetch)
+	case DeploymentStatusSucceeded:
+		return DeploymentTransitionSucceeded
+	case DeploymentStatusFailed:
+		return DeploymentTransitionFailed
+	default:
+		return DeploymentTransitionQueued
+	}
+}
+
+// Records the deployment has reached the given state at the given time.
+func NewDeploymentTransition(id DeploymentID, state DeploymentTransitionState, at time.Time) (t DeploymentTransition) {
+	t.apply(DeploymentTransitionRecorded{
+		DeploymentID: id,
+		State:        state,
+		OccurredAt:   at,
+	})
+
+	return t
+}
+
+func (t *DeploymentTransition) DeploymentID() DeploymentID       { return t.deployment }
+func (t *DeploymentTransition) State() DeploymentTransitionState { return t.state }
+func (t *DeploymentTransition) OccurredAt() time.Time            { return t.occurredAt }
+
+func (t *DeploymentTransition) apply(e event.Event) {
+	switch evt := e.(type) {
+	case DeploymentTransitionRecorded:
+		t.deployment = evt.DeploymentID
+		t.state = evt.State
+		t.occurredAt = evt.OccurredAt
+	}
+
+	event.Store(t, e)
+}
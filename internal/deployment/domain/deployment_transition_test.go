@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentTransition(t *testing.T) {
+	t.Run("should map a deployment state to a transition state", func(t *testing.T) {
+		var state domain.DeploymentState
+
+		testutil.Equals(t, domain.DeploymentTransitionQueued, domain.DeploymentTransitionStateFrom(state))
+
+		testutil.IsNil(t, state.Started())
+		testutil.Equals(t, domain.DeploymentTransitionStep(domain.DeploymentStepFetch), domain.DeploymentTransitionStateFrom(state))
+
+		failed := state
+		testutil.IsNil(t, failed.Failed(errors.New("some error")))
+		testutil.Equals(t, domain.DeploymentTransitionFailed, domain.DeploymentTransitionStateFrom(failed))
+
+		testutil.IsNil(t, state.Succeeded(nil))
+		testutil.Equals(t, domain.DeploymentTransitionSucceeded, domain.DeploymentTransitionStateFrom(state))
+	})
+
+	t.Run("should record a transition", func(t *testing.T) {
+		at := time.Now()
+		id := domain.DeploymentIDFrom("an-app", 1)
+
+		transition := domain.NewDeploymentTransition(id, domain.DeploymentTransitionStep(domain.DeploymentStepBuild), at)
+
+		evt := testutil.EventIs[domain.DeploymentTransitionRecorded](t, &transition, 0)
+		testutil.Equals(t, id, evt.DeploymentID)
+		testutil.Equals(t, "build", evt.State)
+		testutil.Equals(t, at, evt.OccurredAt)
+	})
+}
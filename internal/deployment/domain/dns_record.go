@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+const (
+	DnsRecordA     = "A"
+	DnsRecordAAAA  = "AAAA"
+	DnsRecordCNAME = "CNAME"
+
+	DnsRecordStatusSynced DnsRecordStatus = "synced"
+	DnsRecordStatusFailed DnsRecordStatus = "failed"
+)
+
+// Types of records managed for apps hosts.
+var DnsRecordTypes = []string{DnsRecordA, DnsRecordAAAA, DnsRecordCNAME}
+
+type (
+	DnsRecordStatus string
+
+	// Values of a given type of record for a host.
+	DnsRecordSet struct {
+		Type   string   `json:"type"`
+		Values []string `json:"values"`
+	}
+
+	DnsRecordSets []DnsRecordSet
+
+	// Host at which an app environment is reachable, as exposed by its latest successful
+	// deployment.
+	ExposedHost struct {
+		AppID       AppID
+		Environment Environment
+		Host        string
+		TargetHost  string // Host of the target the app is deployed on
+	}
+
+	// Records managed by seelf for an app host, with the result of their last check.
+	DnsRecord struct {
+		Host        string
+		ZoneID      DnsZoneID
+		AppID       AppID
+		Environment Environment
+		Records     DnsRecordSets
+		Status      DnsRecordStatus
+		Error       monad.Maybe[string]
+		DriftedAt   monad.Maybe[time.Time] // Last time records were found modified outside of seelf and restored
+		CheckedAt   time.Time
+	}
+
+	DnsRecordsReader interface {
+		GetExposedHosts(context.Context) ([]ExposedHost, error)
+		GetDnsRecords(context.Context) ([]DnsRecord, error)
+	}
+
+	DnsRecordsWriter interface {
+		WriteDnsRecord(context.Context, DnsRecord) error
+		DeleteDnsRecord(context.Context, string) error
+	}
+
+	// Manages records of zones hosted by DNS providers.
+	DnsProvider interface {
+		// Retrieve A, AAAA and CNAME records of the given host.
+		Records(context.Context, DnsZone, string) (DnsRecordSets, error)
+		// Create or replace records of the given type for the host.
+		SetRecords(context.Context, DnsZone, string, DnsRecordSet) error
+		// Remove records of the given type for the host.
+		DeleteRecords(context.Context, DnsZone, string, string) error
+	}
+)
+
+// Checks if both sets hold the same values, regardless of their order, case and the
+// trailing dot of fully qualified names.
+func (s DnsRecordSet) Equals(other DnsRecordSet) bool {
+	return s.Type == other.Type && slices.Equal(normalizedDnsValues(s.Values), normalizedDnsValues(other.Values))
+}
+
+// Retrieve the set of the given type.
+func (s DnsRecordSets) Get(recordType string) (DnsRecordSet, bool) {
+	idx := slices.IndexFunc(s, func(set DnsRecordSet) bool { return set.Type == recordType })
+
+	if idx < 0 {
+		return DnsRecordSet{}, false
+	}
+
+	return s[idx], true
+}
+
+// Compares expected sets with the actual ones and returns record types to remove and
+// sets to write. Removals come first since a CNAME could not coexist with other records.
+func (s DnsRecordSets) Diff(actual DnsRecordSets) (removed []string, changed DnsRecordSets) {
+	for _, recordType := range DnsRecordTypes {
+		expected, isExpected := s.Get(recordType)
+		current, exists := actual.Get(recordType)
+
+		switch {
+		case isExpected && (!exists || !expected.Equals(current)):
+			changed = append(changed, expected)
+		case !isExpected && exists:
+			removed = append(removed, recordType)
+		}
+	}
+
+	return removed, changed
+}
+
+func (s DnsRecordSets) Value() (driver.Value, error) { return storage.ValueJSON(s) }
+func (s *DnsRecordSets) Scan(value any) error        { return storage.ScanJSON(value, s) }
+
+// Retrieve hosts at which HTTP entrypoints of those services are exposed on a target
+// reachable at the given url.
+func (s Services) Hosts(targetUrl Url) []string {
+	var hosts []string
+
+	root := targetUrl.Root()
+
+	for _, entry := range s.Entrypoints() {
+		subdomain, isSet := entry.subdomain.TryGet()
+
+		if entry.router != RouterHttp || !isSet {
+			continue
+		}
+
+		host := root.SubDomain(subdomain).Hostname()
+
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+func normalizedDnsValues(values []string) []string {
+	result := make([]string, len(values))
+
+	for i, v := range values {
+		result[i] = strings.TrimSuffix(strings.ToLower(v), ".")
+	}
+
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,324 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidDnsProvider    = apperr.New("invalid_dns_provider")
+	ErrInvalidDnsZone        = apperr.New("invalid_dns_zone")
+	ErrInvalidDnsAddress     = apperr.New("invalid_dns_address")
+	ErrInvalidDnsCredentials = apperr.New("invalid_dns_credentials")
+	ErrDnsZoneNameTaken      = apperr.New("dns_zone_name_taken")
+)
+
+const (
+	DnsProviderCloudflare DnsProviderKind = "cloudflare"
+	DnsProviderRoute53    DnsProviderKind = "route53"
+	DnsProviderDesec      DnsProviderKind = "desec"
+)
+
+type (
+	DnsZoneID       string
+	DnsProviderKind string
+
+	// Credentials used to manage records of a zone on its DNS provider. Cloudflare and deSEC
+	// use an API token, Route53 an access key.
+	DnsCredentials struct {
+		Token     string `json:"token,omitempty"`
+		AccessKey string `json:"access_key,omitempty"`
+		SecretKey string `json:"secret_key,omitempty"`
+	}
+
+	// DNS zone hosted by a provider in which records of apps exposed under its domain are
+	// automatically managed.
+	DnsZone struct {
+		event.Emitter
+
+		id          DnsZoneID
+		name        string // Domain of the zone, such as example.com
+		provider    DnsProviderKind
+		credentials DnsCredentials
+		addresses   DnsAddresses // Addresses apps point to, the target host is aliased if empty
+		created     shared.Action[auth.UserID]
+	}
+
+	DnsAddresses []string
+
+	DnsZones []DnsZone
+
+	DnsZonesReader interface {
+		CheckNameAvailability(context.Context, string) (DnsZoneNameRequirement, error)
+		GetByID(context.Context, DnsZoneID) (DnsZone, error)
+		GetAll(context.Context) ([]DnsZone, error)
+	}
+
+	DnsZonesWriter interface {
+		Write(context.Context, ...*DnsZone) error
+	}
+
+	DnsZoneCreated struct {
+		bus.Notification
+
+		ID          DnsZoneID
+		Name        string
+		Provider    DnsProviderKind
+		Credentials DnsCredentials
+		Addresses   DnsAddresses
+		Created     shared.Action[auth.UserID]
+	}
+
+	DnsZoneCredentialsChanged struct {
+		bus.Notification
+
+		ID          DnsZoneID
+		Credentials DnsCredentials
+	}
+
+	DnsZoneAddressesChanged struct {
+		bus.Notification
+
+		ID        DnsZoneID
+		Addresses DnsAddresses
+	}
+
+	DnsZoneDeleted struct {
+		bus.Notification
+
+		ID DnsZoneID
+	}
+)
+
+func (DnsZoneCreated) Name_() string { return "deployment.event.dns_zone_created" }
+func (DnsZoneDeleted) Name_() string { return "deployment.event.dns_zone_deleted" }
+
+func (DnsZoneCredentialsChanged) Name_() string {
+	return "deployment.event.dns_zone_credentials_changed"
+}
+func (DnsZoneAddressesChanged) Name_() string {
+	return "deployment.event.dns_zone_addresses_changed"
+}
+
+// Parses a DNS provider kind.
+func DnsProviderKindFrom(value string) (DnsProviderKind, error) {
+	switch kind := DnsProviderKind(value); kind {
+	case DnsProviderCloudflare, DnsProviderRoute53, DnsProviderDesec:
+		return kind, nil
+	default:
+		return "", ErrInvalidDnsProvider
+	}
+}
+
+// Builds credentials needed by the given provider, only keeping relevant values.
+func DnsCredentialsFrom(kind DnsProviderKind, token, accessKey, secretKey string) (DnsCredentials, error) {
+	switch kind {
+	case DnsProviderRoute53:
+		if accessKey == "" || secretKey == "" {
+			return DnsCredentials{}, ErrInvalidDnsCredentials
+		}
+
+		return DnsCredentials{AccessKey: accessKey, SecretKey: secretKey}, nil
+	default:
+		if token == "" {
+			return DnsCredentials{}, ErrInvalidDnsCredentials
+		}
+
+		return DnsCredentials{Token: token}, nil
+	}
+}
+
+// Parses the domain of a DNS zone, such as example.com.
+func DnsZoneNameFrom(value string) (string, error) {
+	value = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+
+	if !strings.Contains(value, ".") || !subdomainRegex.MatchString(value) {
+		return "", ErrInvalidDnsZone
+	}
+
+	return value, nil
+}
+
+// Parses an IPv4 or IPv6 address records should point to.
+func DnsAddressFrom(value string) (string, error) {
+	ip := net.ParseIP(strings.TrimSpace(value))
+
+	if ip == nil {
+		return "", ErrInvalidDnsAddress
+	}
+
+	return ip.String(), nil
+}
+
+// Registers a new DNS zone managed by the given provider.
+func NewDnsZone(
+	nameRequirement DnsZoneNameRequirement,
+	provider DnsProviderKind,
+	credentials DnsCredentials,
+	addresses DnsAddresses,
+	uid auth.UserID,
+) (z DnsZone, err error) {
+	name, err := nameRequirement.Met()
+
+	if err != nil {
+		return z, err
+	}
+
+	z.apply(DnsZoneCreated{
+		ID:          id.New[DnsZoneID](),
+		Name:        name,
+		Provider:    provider,
+		Credentials: credentials,
+		Addresses:   addresses,
+		Created:     shared.NewAction(uid),
+	})
+
+	return z, nil
+}
+
+// Recreates a DNS zone from the persistent storage.
+func DnsZoneFrom(scanner storage.Scanner) (z DnsZone, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&z.id,
+		&z.name,
+		&z.provider,
+		&z.credentials,
+		&z.addresses,
+		&createdAt,
+		&createdBy,
+	)
+
+	z.created = shared.ActionFrom(createdBy, createdAt)
+
+	return z, err
+}
+
+// Updates credentials used to reach the DNS provider.
+func (z *DnsZone) UseCredentials(credentials DnsCredentials) {
+	if z.credentials == credentials {
+		return
+	}
+
+	z.apply(DnsZoneCredentialsChanged{
+		ID:          z.id,
+		Credentials: credentials,
+	})
+}
+
+// Updates addresses records should point to, the target host being aliased if empty.
+func (z *DnsZone) PointTo(addresses DnsAddresses) {
+	if slices.Equal(z.addresses, addresses) {
+		return
+	}
+
+	z.apply(DnsZoneAddressesChanged{
+		ID:        z.id,
+		Addresses: addresses,
+	})
+}
+
+func (z *DnsZone) Delete() {
+	z.apply(DnsZoneDeleted{
+		ID: z.id,
+	})
+}
+
+// Checks if the given host belongs to this zone.
+func (z *DnsZone) Contains(host string) bool {
+	return host == z.name || strings.HasSuffix(host, "."+z.name)
+}
+
+// Builds records an host of this zone must have to reach an app deployed on a target
+// exposed at the given host: A and AAAA records when the zone has addresses, a CNAME
+// to the target host otherwise.
+func (z *DnsZone) ExpectedRecords(targetHost string) DnsRecordSets {
+	if len(z.addresses) == 0 {
+		return DnsRecordSets{{Type: DnsRecordCNAME, Values: []string{targetHost}}}
+	}
+
+	var v4, v6 []string
+
+	for _, addr := range z.addresses {
+		if strings.Contains(addr, ":") {
+			v6 = append(v6, addr)
+		} else {
+			v4 = append(v4, addr)
+		}
+	}
+
+	var sets DnsRecordSets
+
+	if len(v4) > 0 {
+		sets = append(sets, DnsRecordSet{Type: DnsRecordA, Values: v4})
+	}
+
+	if len(v6) > 0 {
+		sets = append(sets, DnsRecordSet{Type: DnsRecordAAAA, Values: v6})
+	}
+
+	return sets
+}
+
+func (z *DnsZone) ID() DnsZoneID               { return z.id }
+func (z *DnsZone) Name() string                { return z.name }
+func (z *DnsZone) Provider() DnsProviderKind   { return z.provider }
+func (z *DnsZone) Credentials() DnsCredentials { return z.credentials }
+func (z *DnsZone) Addresses() DnsAddresses     { return z.addresses }
+func (z *DnsZone) CreatedBy() auth.UserID      { return z.created.By() }
+
+func (z *DnsZone) apply(e event.Event) {
+	switch evt := e.(type) {
+	case DnsZoneCreated:
+		z.id = evt.ID
+		z.name = evt.Name
+		z.provider = evt.Provider
+		z.credentials = evt.Credentials
+		z.addresses = evt.Addresses
+		z.created = evt.Created
+	case DnsZoneCredentialsChanged:
+		z.credentials = evt.Credentials
+	case DnsZoneAddressesChanged:
+		z.addresses = evt.Addresses
+	}
+
+	event.Store(z, e)
+}
+
+// Retrieve the zone the given host belongs to, the most specific one if several match.
+func (zones DnsZones) For(host string) (DnsZone, bool) {
+	var (
+		found DnsZone
+		isSet bool
+	)
+
+	for _, z := range zones {
+		if z.Contains(host) && (!isSet || len(z.name) > len(found.name)) {
+			found, isSet = z, true
+		}
+	}
+
+	return found, isSet
+}
+
+func (c DnsCredentials) Value() (driver.Value, error) { return storage.ValueJSON(c) }
+func (c *DnsCredentials) Scan(value any) error        { return storage.ScanJSON(value, c) }
+
+func (a DnsAddresses) Value() (driver.Value, error) { return storage.ValueJSON(a) }
+func (a *DnsAddresses) Scan(value any) error        { return storage.ScanJSON(value, a) }
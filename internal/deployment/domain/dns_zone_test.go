@@ -0,0 +1,191 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DnsZone(t *testing.T) {
+	credentials := domain.DnsCredentials{Token: "a token"}
+
+	t.Run("should validate its name", func(t *testing.T) {
+		_, err := domain.DnsZoneNameFrom("localhost")
+		testutil.ErrorIs(t, domain.ErrInvalidDnsZone, err)
+
+		_, err = domain.DnsZoneNameFrom("not valid.com")
+		testutil.ErrorIs(t, domain.ErrInvalidDnsZone, err)
+
+		name, err := domain.DnsZoneNameFrom(" Example.COM. ")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "example.com", name)
+	})
+
+	t.Run("should validate its addresses", func(t *testing.T) {
+		_, err := domain.DnsAddressFrom("not an ip")
+		testutil.ErrorIs(t, domain.ErrInvalidDnsAddress, err)
+
+		addr, err := domain.DnsAddressFrom("2001:DB8::1")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "2001:db8::1", addr)
+	})
+
+	t.Run("should require credentials expected by the provider", func(t *testing.T) {
+		_, err := domain.DnsCredentialsFrom(domain.DnsProviderCloudflare, "", "access", "secret")
+		testutil.ErrorIs(t, domain.ErrInvalidDnsCredentials, err)
+
+		_, err = domain.DnsCredentialsFrom(domain.DnsProviderRoute53, "a token", "access", "")
+		testutil.ErrorIs(t, domain.ErrInvalidDnsCredentials, err)
+
+		creds, err := domain.DnsCredentialsFrom(domain.DnsProviderRoute53, "a token", "access", "secret")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.DnsCredentials{AccessKey: "access", SecretKey: "secret"}, creds)
+
+		creds, err = domain.DnsCredentialsFrom(domain.DnsProviderDesec, "a token", "access", "secret")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.DnsCredentials{Token: "a token"}, creds)
+	})
+
+	t.Run("should require a unique name", func(t *testing.T) {
+		_, err := domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", false), domain.DnsProviderCloudflare, credentials, nil, "uid")
+
+		testutil.ErrorIs(t, domain.ErrDnsZoneNameTaken, err)
+	})
+
+	t.Run("could be created", func(t *testing.T) {
+		z, err := domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, domain.DnsAddresses{"203.0.113.10"}, "uid")
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.DnsZoneCreated](t, &z, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "example.com", created.Name)
+		testutil.Equals(t, domain.DnsProviderCloudflare, created.Provider)
+		testutil.Equals(t, credentials, created.Credentials)
+		testutil.DeepEquals(t, domain.DnsAddresses{"203.0.113.10"}, created.Addresses)
+		testutil.Equals(t, "uid", created.Created.By())
+	})
+
+	t.Run("could have its credentials changed and raise the event only if different", func(t *testing.T) {
+		z := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, nil, "uid"))
+
+		z.UseCredentials(credentials)
+		z.UseCredentials(domain.DnsCredentials{Token: "another token"})
+
+		testutil.HasNEvents(t, &z, 2)
+		changed := testutil.EventIs[domain.DnsZoneCredentialsChanged](t, &z, 1)
+		testutil.Equals(t, "another token", changed.Credentials.Token)
+	})
+
+	t.Run("could have its addresses changed and raise the event only if different", func(t *testing.T) {
+		z := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, nil, "uid"))
+
+		z.PointTo(nil)
+		z.PointTo(domain.DnsAddresses{"203.0.113.10"})
+
+		testutil.HasNEvents(t, &z, 2)
+		changed := testutil.EventIs[domain.DnsZoneAddressesChanged](t, &z, 1)
+		testutil.DeepEquals(t, domain.DnsAddresses{"203.0.113.10"}, changed.Addresses)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		z := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, nil, "uid"))
+
+		z.Delete()
+
+		deleted := testutil.EventIs[domain.DnsZoneDeleted](t, &z, 1)
+		testutil.Equals(t, z.ID(), deleted.ID)
+	})
+
+	t.Run("should alias the target host when no addresses are set", func(t *testing.T) {
+		z := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, nil, "uid"))
+
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}},
+		}, z.ExpectedRecords("docker.example.com"))
+	})
+
+	t.Run("should point to its addresses when set", func(t *testing.T) {
+		z := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials,
+			domain.DnsAddresses{"203.0.113.10", "2001:db8::1", "203.0.113.11"}, "uid"))
+
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"203.0.113.10", "203.0.113.11"}},
+			{Type: domain.DnsRecordAAAA, Values: []string{"2001:db8::1"}},
+		}, z.ExpectedRecords("docker.example.com"))
+	})
+
+	t.Run("should find the most specific zone of an host", func(t *testing.T) {
+		root := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), domain.DnsProviderCloudflare, credentials, nil, "uid"))
+		sub := must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("apps.example.com", true), domain.DnsProviderDesec, credentials, nil, "uid"))
+		zones := domain.DnsZones{root, sub}
+
+		z, found := zones.For("my-app.apps.example.com")
+		testutil.IsTrue(t, found)
+		testutil.Equals(t, sub.ID(), z.ID())
+
+		z, found = zones.For("my-app.example.com")
+		testutil.IsTrue(t, found)
+		testutil.Equals(t, root.ID(), z.ID())
+
+		_, found = zones.For("my-app.notexample.com")
+		testutil.IsFalse(t, found)
+	})
+}
+
+func Test_DnsRecordSets(t *testing.T) {
+	t.Run("should compute changes needed to match expected records", func(t *testing.T) {
+		expected := domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"203.0.113.10", "203.0.113.11"}},
+			{Type: domain.DnsRecordAAAA, Values: []string{"2001:db8::1"}},
+		}
+
+		removed, changed := expected.Diff(domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"203.0.113.11", "203.0.113.10"}},
+			{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com."}},
+		})
+
+		testutil.DeepEquals(t, []string{domain.DnsRecordCNAME}, removed)
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordAAAA, Values: []string{"2001:db8::1"}},
+		}, changed)
+	})
+
+	t.Run("should ignore case and trailing dots when comparing records", func(t *testing.T) {
+		expected := domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}},
+		}
+
+		removed, changed := expected.Diff(domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"Docker.Example.com."}},
+		})
+
+		testutil.HasLength(t, removed, 0)
+		testutil.HasLength(t, changed, 0)
+	})
+}
+
+func Test_Services_Hosts(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+		"uid"))
+	config := must.Panic(app.ConfigSnapshotFor(domain.Production))
+	targetUrl := must.Panic(domain.UrlFrom("https://docker.example.com:8443/some/path"))
+
+	t.Run("should return hosts of HTTP entrypoints", func(t *testing.T) {
+		app := config.NewService("app", "")
+		app.AddHttpEntrypoint(config, 80, domain.HttpEntrypointOptions{
+			Managed:             true,
+			UseDefaultSubdomain: true,
+		})
+		app.AddHttpEntrypoint(config, 8080, domain.HttpEntrypointOptions{})
+		db := config.NewService("db", "postgres:14-alpine")
+		db.AddTCPEntrypoint(5432)
+
+		hosts := domain.Services{app, db}.Hosts(targetUrl)
+
+		testutil.DeepEquals(t, []string{"my-app.docker.example.com"}, hosts)
+	})
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"database/sql/driver"
+	"slices"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrEnvVarsUnchanged = apperr.New("env_vars_unchanged")
+
+const (
+	EnvVarAdded   EnvVarChangeKind = "added"
+	EnvVarUpdated EnvVarChangeKind = "updated"
+	EnvVarRemoved EnvVarChangeKind = "removed"
+)
+
+type (
+	EnvRevisionID    string
+	EnvVarChangeKind string
+
+	// Change made to a single environment variable of a service. Values are not part
+	// of it, they are kept by the revisions themselves.
+	EnvVarChange struct {
+		Service string           `json:"service"`
+		Name    string           `json:"name"`
+		Kind    EnvVarChangeKind `json:"kind"`
+	}
+
+	EnvVarChanges []EnvVarChange
+
+	// Snapshot of the environment variables of an app environment taken each time they
+	// change so a previous set could be restored.
+	EnvRevision struct {
+		event.Emitter
+
+		id          EnvRevisionID
+		app         AppID
+		environment Environment
+		vars        monad.Maybe[ServicesEnv]
+		changes     EnvVarChanges
+		createdBy   monad.Maybe[auth.UserID] // Empty if the change has not been made by a user
+		createdAt   time.Time
+	}
+
+	EnvRevisionsReader interface {
+		GetByID(context.Context, EnvRevisionID) (EnvRevision, error)
+	}
+
+	EnvRevisionsWriter interface {
+		Write(context.Context, ...*EnvRevision) error
+	}
+
+	EnvRevisionRecorded struct {
+		bus.Notification
+
+		ID          EnvRevisionID
+		AppID       AppID
+		Environment Environment
+		Vars        monad.Maybe[ServicesEnv]
+		Changes     EnvVarChanges
+		CreatedBy   monad.Maybe[auth.UserID]
+		CreatedAt   time.Time
+	}
+)
+
+func (EnvRevisionRecorded) Name_() string { return "deployment.event.env_revision_recorded" }
+
+// Records the environment variables of an app environment which have changed from
+// the previous configuration to the current one.
+func NewEnvRevision(
+	app AppID,
+	env Environment,
+	previous EnvironmentConfig,
+	current EnvironmentConfig,
+	createdBy monad.Maybe[auth.UserID],
+) (r EnvRevision, err error) {
+	changes := DiffEnvVars(previous.vars, current.vars)
+
+	if len(changes) == 0 {
+		return r, ErrEnvVarsUnchanged
+	}
+
+	r.apply(EnvRevisionRecorded{
+		ID:          id.New[EnvRevisionID](),
+		AppID:       app,
+		Environment: env,
+		Vars:        current.vars,
+		Changes:     changes,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now().UTC(),
+	})
+
+	return r, nil
+}
+
+// Recreates a revision from the persistent storage.
+func EnvRevisionFrom(scanner storage.Scanner) (r EnvRevision, err error) {
+	err = scanner.Scan(
+		&r.id,
+		&r.app,
+		&r.environment,
+		&r.vars,
+		&r.changes,
+		&r.createdBy,
+		&r.createdAt,
+	)
+
+	return r, err
+}
+
+// Computes changes made to variables of every service, sorted by service and name.
+func DiffEnvVars(previous, current monad.Maybe[ServicesEnv]) EnvVarChanges {
+	var (
+		changes EnvVarChanges
+		before  = previous.Get(nil)
+		after   = current.Get(nil)
+	)
+
+	for service, vars := range after {
+		for name, value := range vars {
+			old, existed := before[service][name]
+
+			if !existed {
+				changes = append(changes, EnvVarChange{Service: service, Name: name, Kind: EnvVarAdded})
+			} else if old != value {
+				changes = append(changes, EnvVarChange{Service: service, Name: name, Kind: EnvVarUpdated})
+			}
+		}
+	}
+
+	for service, vars := range before {
+		for name := range vars {
+			if _, exists := after[service][name]; !exists {
+				changes = append(changes, EnvVarChange{Service: service, Name: name, Kind: EnvVarRemoved})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b EnvVarChange) int {
+		if a.Service != b.Service {
+			return cmp.Compare(a.Service, b.Service)
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return changes
+}
+
+func (r EnvRevision) ID() EnvRevisionID                   { return r.id }
+func (r EnvRevision) AppID() AppID                        { return r.app }
+func (r EnvRevision) Environment() Environment            { return r.environment }
+func (r EnvRevision) Vars() monad.Maybe[ServicesEnv]      { return r.vars }
+func (r EnvRevision) Changes() EnvVarChanges              { return r.changes }
+func (r EnvRevision) CreatedBy() monad.Maybe[auth.UserID] { return r.createdBy }
+func (r EnvRevision) CreatedAt() time.Time                { return r.createdAt }
+
+func (c EnvVarChanges) Value() (driver.Value, error) { return storage.ValueJSON(c) }
+func (c *EnvVarChanges) Scan(value any) error        { return storage.ScanJSON(value, c) }
+
+func (r *EnvRevision) apply(e event.Event) {
+	switch evt := e.(type) {
+	case EnvRevisionRecorded:
+		r.id = evt.ID
+		r.app = evt.AppID
+		r.environment = evt.Environment
+		r.vars = evt.Vars
+		r.changes = evt.Changes
+		r.createdBy = evt.CreatedBy
+		r.createdAt = evt.CreatedAt
+	}
+
+	event.Store(r, e)
+}
@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_EnvRevision(t *testing.T) {
+	withVars := func(vars domain.ServicesEnv) domain.EnvironmentConfig {
+		config := domain.NewEnvironmentConfig("production-target")
+		config.HasEnvironmentVariables(vars)
+		return config
+	}
+
+	t.Run("should compute changes made to variables sorted by service and name", func(t *testing.T) {
+		changes := domain.DiffEnvVars(
+			monad.Value(domain.ServicesEnv{
+				"app": {"DEBUG": "false", "SECRET": "old"},
+				"db":  {"PASSWORD": "pass"},
+			}),
+			monad.Value(domain.ServicesEnv{
+				"app":    {"DEBUG": "false", "SECRET": "new", "PORT": "8080"},
+				"worker": {"QUEUE": "default"},
+			}),
+		)
+
+		testutil.DeepEquals(t, domain.EnvVarChanges{
+			{Service: "app", Name: "PORT", Kind: domain.EnvVarAdded},
+			{Service: "app", Name: "SECRET", Kind: domain.EnvVarUpdated},
+			{Service: "db", Name: "PASSWORD", Kind: domain.EnvVarRemoved},
+			{Service: "worker", Name: "QUEUE", Kind: domain.EnvVarAdded},
+		}, changes)
+	})
+
+	t.Run("should not be recorded if variables have not changed", func(t *testing.T) {
+		vars := domain.ServicesEnv{"app": {"DEBUG": "false"}}
+
+		_, err := domain.NewEnvRevision("my-app", domain.Production, withVars(vars), withVars(vars), monad.None[auth.UserID]())
+
+		testutil.ErrorIs(t, domain.ErrEnvVarsUnchanged, err)
+	})
+
+	t.Run("should record the new variables and what changed", func(t *testing.T) {
+		current := withVars(domain.ServicesEnv{"app": {"DEBUG": "true"}})
+
+		revision, err := domain.NewEnvRevision("my-app", domain.Production, domain.NewEnvironmentConfig("production-target"), current, monad.Value[auth.UserID]("uid"))
+
+		testutil.IsNil(t, err)
+		recorded := testutil.EventIs[domain.EnvRevisionRecorded](t, &revision, 0)
+		testutil.NotEquals(t, "", recorded.ID)
+		testutil.Equals(t, "my-app", recorded.AppID)
+		testutil.Equals(t, domain.Production, recorded.Environment)
+		testutil.DeepEquals(t, current.Vars(), recorded.Vars)
+		testutil.DeepEquals(t, domain.EnvVarChanges{
+			{Service: "app", Name: "DEBUG", Kind: domain.EnvVarAdded},
+		}, recorded.Changes)
+		testutil.Equals(t, "uid", recorded.CreatedBy.MustGet())
+	})
+
+	t.Run("could be restored on the app it belongs to", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(withVars(domain.ServicesEnv{"app": {"DEBUG": "true"}}), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		other := must.Panic(domain.NewEnvRevision("another-app", domain.Production, domain.EnvironmentConfig{}, withVars(domain.ServicesEnv{"app": {"DEBUG": "false"}}), monad.None[auth.UserID]()))
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, app.RestoreEnvRevision(other))
+
+		revision := must.Panic(domain.NewEnvRevision(app.ID(), domain.Production, app.Production(), withVars(domain.ServicesEnv{"app": {"DEBUG": "false"}}), monad.None[auth.UserID]()))
+
+		testutil.IsNil(t, app.RestoreEnvRevision(revision))
+
+		changed := testutil.EventIs[domain.AppEnvChanged](t, &app, 1)
+		testutil.Equals(t, domain.Production, changed.Environment)
+		testutil.Equals(t, "production-target", changed.Config.Target())
+		testutil.Equals(t, changed.OldConfig.Version(), changed.Config.Version())
+		testutil.DeepEquals(t, revision.Vars(), changed.Config.Vars())
+	})
+
+	t.Run("should not change the app if variables are the same", func(t *testing.T) {
+		vars := domain.ServicesEnv{"app": {"DEBUG": "true"}}
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(withVars(vars), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		revision := must.Panic(domain.NewEnvRevision(app.ID(), domain.Production, domain.EnvironmentConfig{}, withVars(vars), monad.None[auth.UserID]()))
+
+		testutil.IsNil(t, app.RestoreEnvRevision(revision))
+		testutil.HasNEvents(t, &app, 1)
+	})
+}
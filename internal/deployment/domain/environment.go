@@ -3,6 +3,7 @@ package domain
 import (
 	"database/sql/driver"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/YuukanOO/seelf/pkg/apperr"
@@ -12,6 +13,8 @@ import (
 
 var (
 	ErrInvalidEnvironmentName = apperr.New("invalid_environment_name")
+	ErrInvalidEnvImportMode   = apperr.New("invalid_env_import_mode")
+	ErrInvalidDotenv          = apperr.New("invalid_dotenv")
 )
 
 const (
@@ -19,20 +22,37 @@ const (
 	Production Environment = "production"
 	// Staging environment
 	Staging Environment = "staging"
+
+	EnvImportMerge   EnvImportMode = "merge"   // Add or update given variables, keep other ones
+	EnvImportReplace EnvImportMode = "replace" // Given variables become the only ones of the service
+
+	// Value exported in place of secret variables. Importing it keeps the current value
+	// so an exported file could be edited and imported back.
+	MaskedEnvVarValue = "********"
 )
 
+// Parts of a variable name which denote a secret value.
+var secretEnvVarNameParts = []string{"PASS", "SECRET", "TOKEN", "KEY", "PRIVATE", "CREDENTIAL", "DSN"}
+
 type (
-	Environment string             // Represents a valid environment name
-	EnvVars     map[string]string  // Environment variables key pair
-	ServicesEnv map[string]EnvVars // Environment variables per service name
+	Environment   string             // Represents a valid environment name
+	EnvVars       map[string]string  // Environment variables key pair
+	ServicesEnv   map[string]EnvVars // Environment variables per service name
+	EnvImportMode string             // How imported variables are combined with existing ones
 
 	// Represents a specific environment configuration.
 	// The version field is used during the cleanup process to check for successfull deployments
 	// during a specific interval (the last target change).
 	EnvironmentConfig struct {
-		target  TargetID
-		version time.Time
-		vars    monad.Maybe[ServicesEnv]
+		target     TargetID
+		version    time.Time
+		vars       monad.Maybe[ServicesEnv]
+		exposure   monad.Maybe[ServicesExposure]
+		protection monad.Maybe[AccessProtection]
+		rules      monad.Maybe[ProxyRules]
+		override   monad.Maybe[ComposeOverride]
+		aliases    monad.Maybe[UrlAliases]
+		scale      monad.Maybe[ServicesScale]
 	}
 )
 
@@ -64,14 +84,61 @@ func (e *EnvironmentConfig) HasEnvironmentVariables(vars ServicesEnv) {
 	e.vars.Set(vars)
 }
 
+// Add the given exposure overrides per service to this configuration.
+func (e *EnvironmentConfig) HasServicesExposure(exposure ServicesExposure) {
+	e.exposure.Set(exposure)
+}
+
+// Protects exposed services of this configuration.
+func (e *EnvironmentConfig) IsProtectedBy(protection AccessProtection) {
+	e.protection.Set(protection)
+}
+
+// Applies the given proxy rules to exposed services of this configuration.
+func (e *EnvironmentConfig) HasProxyRules(rules ProxyRules) {
+	e.rules.Set(rules)
+}
+
+// Merges the given compose override with the compose file of deployments made with
+// this configuration.
+func (e *EnvironmentConfig) HasComposeOverride(override ComposeOverride) {
+	e.override.Set(override)
+}
+
+// Routes the given aliases to the service exposed on the default subdomain.
+func (e *EnvironmentConfig) HasUrlAliases(aliases UrlAliases) {
+	e.aliases.Set(aliases.normalize())
+}
+
+// Runs the given number of containers per service for deployments made with this
+// configuration.
+func (e *EnvironmentConfig) HasServicesScale(scale ServicesScale) {
+	e.scale.Set(scale)
+}
+
 // Check if two environment config are equals, does not compare version.
 func (e EnvironmentConfig) Equals(other EnvironmentConfig) bool {
-	return e.target == other.target && reflect.DeepEqual(e.vars, other.vars)
+	return e.target == other.target &&
+		reflect.DeepEqual(e.vars, other.vars) &&
+		reflect.DeepEqual(e.exposure, other.exposure) &&
+		reflect.DeepEqual(e.protection, other.protection) &&
+		reflect.DeepEqual(e.rules, other.rules) &&
+		e.override == other.override &&
+		reflect.DeepEqual(e.aliases, other.aliases) &&
+		reflect.DeepEqual(e.scale, other.scale)
 }
 
-func (e EnvironmentConfig) Target() TargetID               { return e.target }
-func (e EnvironmentConfig) Version() time.Time             { return e.version }
-func (e EnvironmentConfig) Vars() monad.Maybe[ServicesEnv] { return e.vars }
+func (e EnvironmentConfig) Target() TargetID                          { return e.target }
+func (e EnvironmentConfig) Version() time.Time                        { return e.version }
+func (e EnvironmentConfig) Vars() monad.Maybe[ServicesEnv]            { return e.vars }
+func (e EnvironmentConfig) Exposure() monad.Maybe[ServicesExposure]   { return e.exposure }
+func (e EnvironmentConfig) Protection() monad.Maybe[AccessProtection] { return e.protection }
+func (e EnvironmentConfig) ProxyRules() monad.Maybe[ProxyRules]       { return e.rules }
+func (e EnvironmentConfig) ComposeOverride() monad.Maybe[ComposeOverride] {
+	return e.override
+}
+func (e EnvironmentConfig) UrlAliases() monad.Maybe[UrlAliases]       { return e.aliases }
+func (e EnvironmentConfig) ServicesScale() monad.Maybe[ServicesScale] { return e.scale }
 
 // Builds the map of services variables from a raw value.
 func ServicesEnvFrom(raw map[string]map[string]string) ServicesEnv {
@@ -88,5 +155,98 @@ func ServicesEnvFrom(raw map[string]map[string]string) ServicesEnv {
 	return result
 }
 
+// Builds a copy of the services variables with the given ones imported for a service.
+// Masked values keep the existing variable value and are skipped if it does not exist.
+func (e ServicesEnv) Import(service string, vars EnvVars, mode EnvImportMode) ServicesEnv {
+	var (
+		result   = make(ServicesEnv, len(e)+1)
+		existing = e[service]
+		imported = make(EnvVars, len(vars))
+	)
+
+	for name, serviceVars := range e {
+		result[name] = serviceVars
+	}
+
+	if mode == EnvImportMerge {
+		for name, value := range existing {
+			imported[name] = value
+		}
+	}
+
+	for name, value := range vars {
+		if value != MaskedEnvVarValue {
+			imported[name] = value
+		} else if current, exists := existing[name]; exists {
+			imported[name] = current
+		}
+	}
+
+	if len(imported) == 0 {
+		delete(result, service)
+	} else {
+		result[service] = imported
+	}
+
+	return result
+}
+
+// Returns a copy of the variables where values of secret ones are masked.
+func (v EnvVars) Masked() EnvVars {
+	result := make(EnvVars, len(v))
+
+	for name, value := range v {
+		if IsSecretEnvVar(name) {
+			value = MaskedEnvVarValue
+		}
+
+		result[name] = value
+	}
+
+	return result
+}
+
+// Returns values of variables which look like they hold a secret.
+func (v EnvVars) SecretValues() []string {
+	var values []string
+
+	for name, value := range v {
+		if IsSecretEnvVar(name) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Returns true if the variable name looks like it holds a secret value such as a
+// password or an API key.
+func IsSecretEnvVar(name string) bool {
+	upper := strings.ToUpper(name)
+
+	for _, part := range secretEnvVarNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parses an import mode.
+func EnvImportModeFrom(value string) (EnvImportMode, error) {
+	switch EnvImportMode(value) {
+	case EnvImportMerge:
+		return EnvImportMerge, nil
+	case EnvImportReplace:
+		return EnvImportReplace, nil
+	default:
+		return "", ErrInvalidEnvImportMode
+	}
+}
+
 func (e ServicesEnv) Value() (driver.Value, error) { return storage.ValueJSON(e) }
 func (e *ServicesEnv) Scan(value any) error        { return storage.ScanJSON(value, e) }
+
+func (v EnvVars) Value() (driver.Value, error) { return storage.ValueJSON(v) }
+func (v *EnvVars) Scan(value any) error        { return storage.ScanJSON(value, v) }
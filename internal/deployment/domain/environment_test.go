@@ -86,6 +86,75 @@ func Test_ServicesEnv(t *testing.T) {
 		}, r)
 	})
 
+	t.Run("should merge imported variables of a service", func(t *testing.T) {
+		env := domain.ServicesEnv{
+			"app": {"DEBUG": "false", "API_KEY": "secret", "PORT": "8080"},
+			"db":  {"USERNAME": "admin"},
+		}
+
+		r := env.Import("app", domain.EnvVars{"DEBUG": "true", "API_KEY": domain.MaskedEnvVarValue, "OTHER_TOKEN": domain.MaskedEnvVarValue}, domain.EnvImportMerge)
+
+		testutil.DeepEquals(t, domain.ServicesEnv{
+			"app": {"DEBUG": "true", "API_KEY": "secret", "PORT": "8080"},
+			"db":  {"USERNAME": "admin"},
+		}, r)
+		testutil.Equals(t, "false", env["app"]["DEBUG"])
+	})
+
+	t.Run("should replace variables of a service by imported ones", func(t *testing.T) {
+		env := domain.ServicesEnv{
+			"app": {"DEBUG": "false", "API_KEY": "secret", "PORT": "8080"},
+		}
+
+		r := env.Import("app", domain.EnvVars{"DEBUG": "true", "API_KEY": domain.MaskedEnvVarValue}, domain.EnvImportReplace)
+
+		testutil.DeepEquals(t, domain.ServicesEnv{
+			"app": {"DEBUG": "true", "API_KEY": "secret"},
+		}, r)
+	})
+
+	t.Run("should remove the service if no variables remain after an import", func(t *testing.T) {
+		env := domain.ServicesEnv{
+			"app": {"DEBUG": "false"},
+			"db":  {"USERNAME": "admin"},
+		}
+
+		r := env.Import("app", domain.EnvVars{}, domain.EnvImportReplace)
+
+		testutil.DeepEquals(t, domain.ServicesEnv{
+			"db": {"USERNAME": "admin"},
+		}, r)
+	})
+
+	t.Run("should mask values of secret variables", func(t *testing.T) {
+		r := domain.EnvVars{
+			"DEBUG":             "false",
+			"DB_PASSWORD":       "pass",
+			"stripe_secret_key": "sk",
+			"GITHUB_TOKEN":      "ghp",
+			"DATABASE_DSN":      "postgres://",
+		}.Masked()
+
+		testutil.DeepEquals(t, domain.EnvVars{
+			"DEBUG":             "false",
+			"DB_PASSWORD":       domain.MaskedEnvVarValue,
+			"stripe_secret_key": domain.MaskedEnvVarValue,
+			"GITHUB_TOKEN":      domain.MaskedEnvVarValue,
+			"DATABASE_DSN":      domain.MaskedEnvVarValue,
+		}, r)
+	})
+
+	t.Run("should parse an import mode", func(t *testing.T) {
+		mode, err := domain.EnvImportModeFrom("replace")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.EnvImportReplace, mode)
+
+		_, err = domain.EnvImportModeFrom("append")
+
+		testutil.ErrorIs(t, domain.ErrInvalidEnvImportMode, err)
+	})
+
 	t.Run("should implement the Valuer interface", func(t *testing.T) {
 		str, err := domain.ServicesEnv{
 			"app": {"DEBUG": "false"},
@@ -186,6 +255,15 @@ func Test_EnvironmentConfig(t *testing.T) {
 				},
 				expected: false,
 			},
+			{
+				a: func() domain.EnvironmentConfig {
+					conf := domain.NewEnvironmentConfig("1")
+					conf.HasServicesExposure(domain.ServicesExposure{"app": {Disabled: true}})
+					return conf
+				},
+				b:        func() domain.EnvironmentConfig { return domain.NewEnvironmentConfig("1") },
+				expected: false,
+			},
 		}
 
 		for _, test := range tests {
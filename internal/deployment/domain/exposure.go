@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidSubdomain  = apperr.New("invalid_subdomain")
+	ErrInvalidPathPrefix = apperr.New("invalid_path_prefix")
+	ErrInvalidRouter     = apperr.New("invalid_router")
+
+	subdomainRegex  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+	pathPrefixRegex = regexp.MustCompile(`^(/[A-Za-z0-9._~!$&'()*+,;=:@%-]+)+$`)
+)
+
+type (
+	// Overrides how a service is exposed, taking precedence over the ports declared
+	// in the compose file.
+	ServiceExposure struct {
+		Disabled   bool                `json:"disabled"`    // Do not expose the service at all, even if it declares ports
+		Port       monad.Maybe[Port]   `json:"port"`        // Container port exposed over HTTP, replaces HTTP ports of the compose file
+		Subdomain  monad.Maybe[string] `json:"subdomain"`   // Subdomain of the target url to use instead of the default one
+		PathPrefix monad.Maybe[string] `json:"path_prefix"` // Only route requests whose path starts with this prefix
+		Ports      []ExposedPort       `json:"ports"`       // Raw TCP / UDP ports exposed through the target
+	}
+
+	// Raw TCP / UDP container port exposed through the target, such as a database or a game server.
+	ExposedPort struct {
+		Router    Router            `json:"router"`    // Either tcp or udp
+		Port      Port              `json:"port"`      // Container port
+		Published monad.Maybe[Port] `json:"published"` // Port reserved on the target, assigned by the target if not set
+	}
+
+	ServicesExposure map[string]ServiceExposure // Exposure overrides per service name
+)
+
+// Parses a subdomain of a target url, such as `api` or `api.my-app`.
+func SubdomainFrom(value string) (string, error) {
+	if !subdomainRegex.MatchString(value) {
+		return "", ErrInvalidSubdomain
+	}
+
+	return value, nil
+}
+
+// Parses a path prefix used to route requests, trailing slashes are removed.
+func PathPrefixFrom(value string) (string, error) {
+	prefix := strings.TrimRight(value, "/")
+
+	if !pathPrefixRegex.MatchString(prefix) {
+		return "", ErrInvalidPathPrefix
+	}
+
+	return prefix, nil
+}
+
+// Builds a raw TCP / UDP port exposure.
+func ExposedPortFrom(router string, port uint, published monad.Maybe[uint]) (p ExposedPort, err error) {
+	p.Router = Router(router)
+
+	if p.Router != RouterTcp && p.Router != RouterUdp {
+		return p, ErrInvalidRouter
+	}
+
+	if p.Port, err = PortFrom(port); err != nil {
+		return p, err
+	}
+
+	if value, isSet := published.TryGet(); isSet {
+		publishedPort, err := PortFrom(value)
+
+		if err != nil {
+			return p, err
+		}
+
+		p.Published.Set(publishedPort)
+	}
+
+	return p, nil
+}
+
+// Retrieve ports which should be reserved on the target for every service.
+func (e ServicesExposure) PublishedPorts() []Port {
+	var ports []Port
+
+	for _, exposure := range e {
+		if exposure.Disabled {
+			continue
+		}
+
+		for _, exposed := range exposure.Ports {
+			if published, isSet := exposed.Published.TryGet(); isSet {
+				ports = append(ports, published)
+			}
+		}
+	}
+
+	return ports
+}
+
+func (e ServicesExposure) Value() (driver.Value, error) { return storage.ValueJSON(e) }
+func (e *ServicesExposure) Scan(value any) error        { return storage.ScanJSON(value, e) }
@@ -0,0 +1,121 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ServiceExposure(t *testing.T) {
+	t.Run("should validates a subdomain", func(t *testing.T) {
+		tests := []struct {
+			input string
+			valid bool
+		}{
+			{"", false},
+			{"API", false},
+			{"-api", false},
+			{"api-", false},
+			{"my api", false},
+			{"api", true},
+			{"my-api", true},
+			{"api.my-app", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				r, err := domain.SubdomainFrom(test.input)
+
+				if test.valid {
+					testutil.IsNil(t, err)
+					testutil.Equals(t, test.input, r)
+				} else {
+					testutil.ErrorIs(t, domain.ErrInvalidSubdomain, err)
+					testutil.Equals(t, "", r)
+				}
+			})
+		}
+	})
+
+	t.Run("should validates a path prefix and remove trailing slashes", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+			valid    bool
+		}{
+			{"", "", false},
+			{"/", "", false},
+			{"api", "", false},
+			{"/my api", "", false},
+			{"/api", "/api", true},
+			{"/api/", "/api", true},
+			{"/api/v1", "/api/v1", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				r, err := domain.PathPrefixFrom(test.input)
+
+				if test.valid {
+					testutil.IsNil(t, err)
+				} else {
+					testutil.ErrorIs(t, domain.ErrInvalidPathPrefix, err)
+				}
+
+				testutil.Equals(t, test.expected, r)
+			})
+		}
+	})
+
+	t.Run("should validates a raw exposed port", func(t *testing.T) {
+		_, err := domain.ExposedPortFrom("http", 80, monad.None[uint]())
+		testutil.ErrorIs(t, domain.ErrInvalidRouter, err)
+
+		_, err = domain.ExposedPortFrom("tcp", 0, monad.None[uint]())
+		testutil.ErrorIs(t, domain.ErrInvalidPort, err)
+
+		_, err = domain.ExposedPortFrom("udp", 27015, monad.Value[uint](70000))
+		testutil.ErrorIs(t, domain.ErrInvalidPort, err)
+
+		p, err := domain.ExposedPortFrom("tcp", 5432, monad.Value[uint](15432))
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.ExposedPort{
+			Router:    domain.RouterTcp,
+			Port:      5432,
+			Published: monad.Value[domain.Port](15432),
+		}, p)
+	})
+
+	t.Run("should retrieve published ports of enabled services", func(t *testing.T) {
+		exposure := domain.ServicesExposure{
+			"db": {Ports: []domain.ExposedPort{
+				{Router: domain.RouterTcp, Port: 5432, Published: monad.Value[domain.Port](15432)},
+				{Router: domain.RouterTcp, Port: 5433},
+			}},
+			"game": {Disabled: true, Ports: []domain.ExposedPort{
+				{Router: domain.RouterUdp, Port: 27015, Published: monad.Value[domain.Port](27015)},
+			}},
+		}
+
+		testutil.DeepEquals(t, []domain.Port{15432}, exposure.PublishedPorts())
+	})
+
+	t.Run("should implement the valuer and scanner interfaces", func(t *testing.T) {
+		exposure := domain.ServicesExposure{
+			"api": {PathPrefix: monad.Value("/api")},
+			"db":  {Disabled: true},
+		}
+
+		value, err := exposure.Value()
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, `{"api":{"disabled":false,"port":null,"subdomain":null,"path_prefix":"/api","ports":null},"db":{"disabled":true,"port":null,"subdomain":null,"path_prefix":null,"ports":null}}`, value.(string))
+
+		var scanned domain.ServicesExposure
+
+		testutil.IsNil(t, scanned.Scan(value))
+		testutil.DeepEquals(t, exposure, scanned)
+	})
+}
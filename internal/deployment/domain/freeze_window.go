@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidFreezeWindow = apperr.New("invalid_freeze_window")
+	ErrInvalidFreezePolicy = apperr.New("invalid_freeze_policy")
+	ErrInvalidFreezeDay    = apperr.New("invalid_freeze_day")
+	ErrInvalidFreezeTime   = apperr.New("invalid_freeze_time")
+	ErrInvalidTimezone     = apperr.New("invalid_timezone")
+	ErrDeploymentFrozen    = apperr.New("deployment_frozen")
+
+	freezeDays = map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+)
+
+const (
+	FreezePolicyQueue  FreezePolicy = "queue"  // Deployments stay pending until the window ends
+	FreezePolicyReject FreezePolicy = "reject" // Deployments fail right away
+
+	freezeTimeLayout = "15:04"
+	minutesInDay     = 24 * 60
+)
+
+type (
+	FreezePolicy string
+
+	// Period during which new production deployments are not processed. It is either
+	// recurring on some days of the week, optionally between two times of the day, or a
+	// one-off period between two dates such as a release freeze.
+	FreezeWindow struct {
+		Name     string                 `json:"name"`
+		Policy   FreezePolicy           `json:"policy"`
+		Days     []string               `json:"days,omitempty"`     // Days of the week (mon, tue...), every day if empty
+		From     string                 `json:"from,omitempty"`     // Time of the day (HH:MM) at which it starts, midnight if empty
+		To       string                 `json:"to,omitempty"`       // Time of the day (HH:MM) at which it ends, on the next day if not after from
+		Timezone string                 `json:"timezone,omitempty"` // Location in which days and times are expressed, UTC if empty
+		Start    monad.Maybe[time.Time] `json:"start"`              // Start of a one-off window
+		End      monad.Maybe[time.Time] `json:"end"`                // End of a one-off window
+	}
+
+	FreezeWindows []FreezeWindow
+)
+
+// Parses a freeze policy, queuing deployments by default.
+func FreezePolicyFrom(value string) (FreezePolicy, error) {
+	switch policy := FreezePolicy(value); policy {
+	case "":
+		return FreezePolicyQueue, nil
+	case FreezePolicyQueue, FreezePolicyReject:
+		return policy, nil
+	default:
+		return "", ErrInvalidFreezePolicy
+	}
+}
+
+// Parses a day of the week given as its full or abbreviated english name.
+func FreezeDayFrom(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if len(value) < 3 {
+		return "", ErrInvalidFreezeDay
+	}
+
+	day := value[:3]
+
+	if weekday, found := freezeDays[day]; !found || !strings.HasPrefix(strings.ToLower(weekday.String()), value) {
+		return "", ErrInvalidFreezeDay
+	}
+
+	return day, nil
+}
+
+// Parses a time of the day given as HH:MM.
+func FreezeTimeFrom(value string) (string, error) {
+	if _, err := time.Parse(freezeTimeLayout, value); err != nil {
+		return "", ErrInvalidFreezeTime
+	}
+
+	return value, nil
+}
+
+// Parses a timezone name, such as Europe/Paris.
+func TimezoneFrom(value string) (string, error) {
+	if _, err := time.LoadLocation(value); err != nil {
+		return "", ErrInvalidTimezone
+	}
+
+	return value, nil
+}
+
+// Builds a new freeze window from already parsed values. One-off windows need both
+// a start and an end and could not have days or times.
+func NewFreezeWindow(
+	name string,
+	policy FreezePolicy,
+	days []string,
+	from, to, timezone string,
+	start, end monad.Maybe[time.Time],
+) (FreezeWindow, error) {
+	w := FreezeWindow{
+		Name:     name,
+		Policy:   policy,
+		Days:     days,
+		From:     from,
+		To:       to,
+		Timezone: timezone,
+		Start:    start,
+		End:      end,
+	}
+
+	if !start.HasValue() && !end.HasValue() {
+		return w, nil
+	}
+
+	startAt, hasStart := start.TryGet()
+	endAt, hasEnd := end.TryGet()
+
+	if !hasStart || !hasEnd || !endAt.After(startAt) || len(days) > 0 || from != "" || to != "" {
+		return FreezeWindow{}, ErrInvalidFreezeWindow
+	}
+
+	return w, nil
+}
+
+// Checks if the given time is inside this window.
+func (w FreezeWindow) Contains(t time.Time) bool {
+	if start, isOneOff := w.Start.TryGet(); isOneOff {
+		return !t.Before(start) && t.Before(w.End.Get(start))
+	}
+
+	location, err := time.LoadLocation(w.Timezone)
+
+	if err != nil {
+		location = time.UTC
+	}
+
+	t = t.In(location)
+
+	var (
+		from    = w.minutes(w.From, 0)
+		to      = w.minutes(w.To, minutesInDay)
+		current = t.Hour()*60 + t.Minute()
+	)
+
+	if from < to {
+		return w.on(t.Weekday()) && current >= from && current < to
+	}
+
+	// The window ends on the next day
+	return (w.on(t.Weekday()) && current >= from) ||
+		(w.on(t.AddDate(0, 0, -1).Weekday()) && current < to)
+}
+
+func (w FreezeWindow) on(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.ContainsFunc(w.Days, func(d string) bool {
+		return freezeDays[d] == day
+	})
+}
+
+func (w FreezeWindow) minutes(value string, fallback int) int {
+	t, err := time.Parse(freezeTimeLayout, value)
+
+	if err != nil {
+		return fallback
+	}
+
+	return t.Hour()*60 + t.Minute()
+}
+
+// Retrieve the window containing the given time, windows rejecting deployments taking
+// precedence over queuing ones.
+func (w FreezeWindows) Active(t time.Time) (active monad.Maybe[FreezeWindow]) {
+	for _, window := range w {
+		if !window.Contains(t) {
+			continue
+		}
+
+		if window.Policy == FreezePolicyReject {
+			return monad.Value(window)
+		}
+
+		if !active.HasValue() {
+			active.Set(window)
+		}
+	}
+
+	return active
+}
+
+func (w FreezeWindows) Value() (driver.Value, error) { return storage.ValueJSON(w) }
+func (w *FreezeWindows) Scan(value any) error        { return storage.ScanJSON(value, w) }
+
+// Replaces the freeze windows of this application, production deployments will not be
+// processed during those periods in addition to the instance wide ones. Giving no window
+// removes them.
+func (a *App) ConfigureFreezeWindows(windows FreezeWindows) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	a.apply(AppFreezeWindowsChanged{
+		ID:      a.id,
+		Windows: windows,
+	})
+
+	return nil
+}
+
+func (a *App) FreezeWindows() FreezeWindows { return a.freezeWindows }
+
+// Allows this deployment to be processed even if a freeze window is active.
+func (d *Deployment) Unfreeze(by domain.UserID) error {
+	if d.state.status != DeploymentStatusPending {
+		return ErrNotInPendingState
+	}
+
+	if d.unfrozen.HasValue() {
+		return nil
+	}
+
+	d.apply(DeploymentUnfrozen{
+		ID:       d.id,
+		Unfrozen: shared.NewAction(by),
+	})
+
+	return nil
+}
+
+// Retrieve the freeze window which prevents this deployment from being processed at the
+// given time if any. Only production deployments which have not been unfrozen are affected.
+func (d *Deployment) ActiveFreeze(windows FreezeWindows, now time.Time) monad.Maybe[FreezeWindow] {
+	if !d.config.environment.IsProduction() || d.unfrozen.HasValue() {
+		return monad.None[FreezeWindow]()
+	}
+
+	return windows.Active(now)
+}
+
+func (d *Deployment) Unfrozen() monad.Maybe[shared.Action[domain.UserID]] { return d.unfrozen }
@@ -0,0 +1,182 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_FreezeWindow(t *testing.T) {
+	saturday := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC) // June 15, 2024 is a saturday
+
+	t.Run("should parse policies, queuing deployments by default", func(t *testing.T) {
+		policy, err := domain.FreezePolicyFrom("")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.FreezePolicyQueue, policy)
+
+		policy, err = domain.FreezePolicyFrom("reject")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.FreezePolicyReject, policy)
+
+		_, err = domain.FreezePolicyFrom("ignore")
+		testutil.ErrorIs(t, domain.ErrInvalidFreezePolicy, err)
+	})
+
+	t.Run("should parse days given as their full or abbreviated name", func(t *testing.T) {
+		tests := []struct {
+			value    string
+			expected string
+			valid    bool
+		}{
+			{"mon", "mon", true},
+			{"Saturday", "sat", true},
+			{" THU ", "thu", true},
+			{"sa", "", false},
+			{"satday", "", false},
+			{"someday", "", false},
+		}
+
+		for _, test := range tests {
+			t.Run(test.value, func(t *testing.T) {
+				day, err := domain.FreezeDayFrom(test.value)
+
+				if !test.valid {
+					testutil.ErrorIs(t, domain.ErrInvalidFreezeDay, err)
+					return
+				}
+
+				testutil.IsNil(t, err)
+				testutil.Equals(t, test.expected, day)
+			})
+		}
+	})
+
+	t.Run("should parse times and timezones", func(t *testing.T) {
+		_, err := domain.FreezeTimeFrom("18:00")
+		testutil.IsNil(t, err)
+
+		_, err = domain.FreezeTimeFrom("25:00")
+		testutil.ErrorIs(t, domain.ErrInvalidFreezeTime, err)
+
+		_, err = domain.TimezoneFrom("Europe/Paris")
+		testutil.IsNil(t, err)
+
+		_, err = domain.TimezoneFrom("Somewhere/Else")
+		testutil.ErrorIs(t, domain.ErrInvalidTimezone, err)
+	})
+
+	t.Run("should require both dates of one-off windows without days or times", func(t *testing.T) {
+		start := monad.Value(saturday)
+		end := monad.Value(saturday.Add(time.Hour))
+
+		_, err := domain.NewFreezeWindow("release", domain.FreezePolicyQueue, nil, "", "", "", start, monad.None[time.Time]())
+		testutil.ErrorIs(t, domain.ErrInvalidFreezeWindow, err)
+
+		_, err = domain.NewFreezeWindow("release", domain.FreezePolicyQueue, nil, "", "", "", end, start)
+		testutil.ErrorIs(t, domain.ErrInvalidFreezeWindow, err)
+
+		_, err = domain.NewFreezeWindow("release", domain.FreezePolicyQueue, []string{"sat"}, "", "", "", start, end)
+		testutil.ErrorIs(t, domain.ErrInvalidFreezeWindow, err)
+
+		w, err := domain.NewFreezeWindow("release", domain.FreezePolicyQueue, nil, "", "", "", start, end)
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, w.Contains(saturday))
+		testutil.IsFalse(t, w.Contains(saturday.Add(time.Hour)))
+	})
+
+	t.Run("should check if a time is inside a recurring window", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			window   domain.FreezeWindow
+			at       time.Time
+			expected bool
+		}{
+			{"every day", domain.FreezeWindow{}, saturday, true},
+			{"matching day", domain.FreezeWindow{Days: []string{"sat", "sun"}}, saturday, true},
+			{"other day", domain.FreezeWindow{Days: []string{"mon"}}, saturday, false},
+			{"inside times", domain.FreezeWindow{From: "09:00", To: "12:00"}, saturday, true},
+			{"outside times", domain.FreezeWindow{From: "12:00", To: "18:00"}, saturday, false},
+			{"overnight after start", domain.FreezeWindow{Days: []string{"fri"}, From: "18:00", To: "08:00"}, saturday.Add(-13 * time.Hour), true},
+			{"overnight on the next day", domain.FreezeWindow{Days: []string{"fri"}, From: "18:00", To: "08:00"}, saturday.Add(-3 * time.Hour), true},
+			{"overnight after end", domain.FreezeWindow{Days: []string{"fri"}, From: "18:00", To: "08:00"}, saturday, false},
+			{"timezone", domain.FreezeWindow{From: "12:00", To: "13:00", Timezone: "Europe/Paris"}, saturday, true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				testutil.Equals(t, test.expected, test.window.Contains(test.at))
+			})
+		}
+	})
+
+	t.Run("should give precedence to windows rejecting deployments", func(t *testing.T) {
+		windows := domain.FreezeWindows{
+			{Name: "weekend", Policy: domain.FreezePolicyQueue, Days: []string{"sat", "sun"}},
+			{Name: "monday", Policy: domain.FreezePolicyReject, Days: []string{"mon"}},
+			{Name: "release", Policy: domain.FreezePolicyReject, Days: []string{"sat"}},
+		}
+
+		testutil.Equals(t, "release", windows.Active(saturday).MustGet().Name)
+		testutil.Equals(t, "weekend", windows[:1].Active(saturday).MustGet().Name)
+		testutil.IsFalse(t, windows.Active(saturday.AddDate(0, 0, 3)).HasValue())
+	})
+
+	t.Run("could be configured on an app", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		windows := domain.FreezeWindows{{Name: "weekend", Days: []string{"sat", "sun"}}}
+
+		testutil.IsNil(t, app.ConfigureFreezeWindows(windows))
+
+		evt := testutil.EventIs[domain.AppFreezeWindowsChanged](t, &app, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.DeepEquals(t, windows, evt.Windows)
+		testutil.DeepEquals(t, windows, app.FreezeWindows())
+
+		app.RequestCleanup("uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, app.ConfigureFreezeWindows(nil))
+	})
+
+	t.Run("should only affect production deployments which have not been unfrozen", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		windows := domain.FreezeWindows{{Name: "always"}}
+		production := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		staging := must.Panic(app.NewDeployment(2, meta{false}, domain.Staging, "uid"))
+
+		testutil.IsTrue(t, production.ActiveFreeze(windows, saturday).HasValue())
+		testutil.IsFalse(t, staging.ActiveFreeze(windows, saturday).HasValue())
+
+		testutil.IsNil(t, production.Unfreeze("admin"))
+		testutil.IsNil(t, production.Unfreeze("admin"))
+
+		evt := testutil.EventIs[domain.DeploymentUnfrozen](t, &production, 1)
+		testutil.Equals(t, auth.UserID("admin"), evt.Unfrozen.By())
+		testutil.HasNEvents(t, &production, 2)
+		testutil.IsFalse(t, production.ActiveFreeze(windows, saturday).HasValue())
+	})
+
+	t.Run("should not unfreeze deployments which are not pending", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, meta{false}, domain.Production, "uid"))
+		testutil.IsNil(t, depl.HasStarted())
+
+		testutil.ErrorIs(t, domain.ErrNotInPendingState, depl.Unfreeze("admin"))
+	})
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrGitOpsNotConfigured = apperr.New("gitops_not_configured")
+
+const (
+	SpecResourceCreated SpecChangeKind = "created"
+	SpecResourceUpdated SpecChangeKind = "updated"
+	SpecResourceDeleted SpecChangeKind = "deleted"
+)
+
+type (
+	GitOpsRunID    string
+	SpecChangeKind string
+
+	// Change made to a single target or app to match the GitOps spec.
+	SpecChange struct {
+		Resource string         `json:"resource"` // target or app
+		Name     string         `json:"name"`
+		Kind     SpecChangeKind `json:"kind"`
+	}
+
+	SpecChanges []SpecChange
+
+	// Reconciliation of the instance with the GitOps repository at a given commit.
+	// Changes made before a failure are kept since they have been applied anyway.
+	GitOpsRun struct {
+		event.Emitter
+
+		id        GitOpsRunID
+		commit    string
+		changes   SpecChanges
+		err       monad.Maybe[string]
+		createdAt time.Time
+	}
+
+	GitOpsRunsReader interface {
+		GetLatest(context.Context) (GitOpsRun, error)
+	}
+
+	GitOpsRunsWriter interface {
+		Write(context.Context, ...*GitOpsRun) error
+	}
+
+	// Git repository containing the spec describing targets and apps of the instance.
+	GitOpsRepository interface {
+		// Retrieve the latest commit of the watched branch.
+		LatestCommit(context.Context) (string, error)
+		// Reconcile the instance with the spec at the latest commit, returning the commit
+		// used and changes made, even partially, to match the spec.
+		Apply(context.Context) (string, SpecChanges, error)
+	}
+
+	GitOpsRunRecorded struct {
+		bus.Notification
+
+		ID        GitOpsRunID
+		Commit    string
+		Changes   SpecChanges
+		Err       monad.Maybe[string]
+		CreatedAt time.Time
+	}
+)
+
+func (GitOpsRunRecorded) Name_() string { return "deployment.event.gitops_run_recorded" }
+
+// Records the result of a reconciliation with the GitOps repository.
+func NewGitOpsRun(commit string, changes SpecChanges, err error) (r GitOpsRun) {
+	var errMessage monad.Maybe[string]
+
+	if err != nil {
+		errMessage.Set(err.Error())
+	}
+
+	r.apply(GitOpsRunRecorded{
+		ID:        id.New[GitOpsRunID](),
+		Commit:    commit,
+		Changes:   changes,
+		Err:       errMessage,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return r
+}
+
+// Recreates a run from the persistent storage.
+func GitOpsRunFrom(scanner storage.Scanner) (r GitOpsRun, err error) {
+	err = scanner.Scan(
+		&r.id,
+		&r.commit,
+		&r.changes,
+		&r.err,
+		&r.createdAt,
+	)
+
+	return r, err
+}
+
+func (r GitOpsRun) ID() GitOpsRunID          { return r.id }
+func (r GitOpsRun) Commit() string           { return r.commit }
+func (r GitOpsRun) Changes() SpecChanges     { return r.changes }
+func (r GitOpsRun) Err() monad.Maybe[string] { return r.err }
+func (r GitOpsRun) Succeeded() bool          { return !r.err.HasValue() }
+func (r GitOpsRun) CreatedAt() time.Time     { return r.createdAt }
+
+func (c SpecChanges) Value() (driver.Value, error) { return storage.ValueJSON(c) }
+func (c *SpecChanges) Scan(value any) error        { return storage.ScanJSON(value, c) }
+
+func (r *GitOpsRun) apply(e event.Event) {
+	switch evt := e.(type) {
+	case GitOpsRunRecorded:
+		r.id = evt.ID
+		r.commit = evt.Commit
+		r.changes = evt.Changes
+		r.err = evt.Err
+		r.createdAt = evt.CreatedAt
+	}
+
+	event.Store(r, e)
+}
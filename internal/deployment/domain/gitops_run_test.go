@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_GitOpsRun(t *testing.T) {
+	changes := domain.SpecChanges{
+		{Resource: "target", Name: "production", Kind: domain.SpecResourceCreated},
+		{Resource: "app", Name: "my-app", Kind: domain.SpecResourceUpdated},
+	}
+
+	t.Run("should record a successful run", func(t *testing.T) {
+		run := domain.NewGitOpsRun("a1b2c3", changes, nil)
+
+		testutil.IsTrue(t, run.Succeeded())
+		recorded := testutil.EventIs[domain.GitOpsRunRecorded](t, &run, 0)
+		testutil.NotEquals(t, "", recorded.ID)
+		testutil.Equals(t, "a1b2c3", recorded.Commit)
+		testutil.DeepEquals(t, changes, recorded.Changes)
+		testutil.IsFalse(t, recorded.Err.HasValue())
+		testutil.IsFalse(t, recorded.CreatedAt.IsZero())
+	})
+
+	t.Run("should keep changes made before a failure", func(t *testing.T) {
+		run := domain.NewGitOpsRun("a1b2c3", changes, errors.New("app other-app: unknown target"))
+
+		testutil.IsFalse(t, run.Succeeded())
+		testutil.Equals(t, "app other-app: unknown target", run.Err().MustGet())
+		testutil.DeepEquals(t, changes, run.Changes())
+	})
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+const (
+	HookStagePostFetch  HookStage = "post-fetch"  // Deployment files have been fetched
+	HookStagePreDeploy  HookStage = "pre-deploy"  // Right before the provider builds and runs the project
+	HookStagePostDeploy HookStage = "post-deploy" // The project is running on the target
+)
+
+var ErrInvalidHookStage = apperr.New("invalid_hook_stage")
+
+type (
+	// Stage of the deployment pipeline where external hooks could be plugged.
+	HookStage string
+
+	// Runs external hooks registered for a given stage of the deployment pipeline.
+	// Services are only known at the HookStagePostDeploy stage.
+	Hooks interface {
+		Run(context.Context, DeploymentContext, HookStage, Deployment, Services) error
+	}
+)
+
+// Parses a hook stage from a raw value.
+func HookStageFrom(value string) (HookStage, error) {
+	switch HookStage(value) {
+	case HookStagePostFetch, HookStagePreDeploy, HookStagePostDeploy:
+		return HookStage(value), nil
+	default:
+		return "", ErrInvalidHookStage
+	}
+}
@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_HookStage(t *testing.T) {
+	t.Run("should validates input string", func(t *testing.T) {
+		tests := []struct {
+			input string
+			valid bool
+		}{
+			{"", false},
+			{"pre-fetch", false},
+			{"post-fetch", true},
+			{"pre-deploy", true},
+			{"post-deploy", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				r, err := domain.HookStageFrom(test.input)
+
+				if test.valid {
+					testutil.IsNil(t, err)
+					testutil.Equals(t, domain.HookStage(test.input), r)
+				} else {
+					testutil.ErrorIs(t, domain.ErrInvalidHookStage, err)
+				}
+			})
+		}
+	})
+}
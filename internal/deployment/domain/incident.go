@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	IncidentCrashed     IncidentKind = "crashed"    // The container exited with a non-zero code without being asked to
+	IncidentOutOfMemory IncidentKind = "oom_killed" // The container has been killed because it ran out of memory
+	IncidentRestarted   IncidentKind = "restarted"  // The container has been restarted
+)
+
+type (
+	IncidentID string
+
+	// Kind of abnormal event which happened to a running container.
+	IncidentKind string
+
+	// Abnormal event reported by a provider for a container of an application service.
+	ContainerIncident struct {
+		AppID       AppID
+		Environment Environment
+		Service     string
+		Container   string
+		Kind        IncidentKind
+		ExitCode    monad.Maybe[int]
+		OccurredAt  time.Time
+	}
+
+	// Container incident recorded against the deployment whose services were running
+	// when it occurred.
+	Incident struct {
+		event.Emitter
+
+		id         IncidentID
+		deployment DeploymentID
+		service    string
+		container  string
+		kind       IncidentKind
+		exitCode   monad.Maybe[int]
+		occurredAt time.Time
+	}
+
+	IncidentsWriter interface {
+		Write(context.Context, ...*Incident) error
+	}
+
+	IncidentRecorded struct {
+		bus.Notification
+
+		ID           IncidentID
+		DeploymentID DeploymentID
+		Config       DeploymentConfig
+		Service      string
+		Container    string
+		Kind         IncidentKind
+		ExitCode     monad.Maybe[int]
+		OccurredAt   time.Time
+	}
+)
+
+func (IncidentRecorded) Name_() string { return "deployment.event.incident_recorded" }
+
+// Records an incident which happened to a container started by the given deployment.
+func NewIncident(depl Deployment, incident ContainerIncident) (i Incident) {
+	i.apply(IncidentRecorded{
+		ID:           id.New[IncidentID](),
+		DeploymentID: depl.ID(),
+		Config:       depl.Config(),
+		Service:      incident.Service,
+		Container:    incident.Container,
+		Kind:         incident.Kind,
+		ExitCode:     incident.ExitCode,
+		OccurredAt:   incident.OccurredAt,
+	})
+
+	return i
+}
+
+func (i *Incident) ID() IncidentID             { return i.id }
+func (i *Incident) DeploymentID() DeploymentID { return i.deployment }
+func (i *Incident) Service() string            { return i.service }
+func (i *Incident) Container() string          { return i.container }
+func (i *Incident) Kind() IncidentKind         { return i.kind }
+func (i *Incident) ExitCode() monad.Maybe[int] { return i.exitCode }
+func (i *Incident) OccurredAt() time.Time      { return i.occurredAt }
+
+func (i *Incident) apply(e event.Event) {
+	switch evt := e.(type) {
+	case IncidentRecorded:
+		i.id = evt.ID
+		i.deployment = evt.DeploymentID
+		i.service = evt.Service
+		i.container = evt.Container
+		i.kind = evt.Kind
+		i.exitCode = evt.ExitCode
+		i.occurredAt = evt.OccurredAt
+	}
+
+	event.Store(i, e)
+}
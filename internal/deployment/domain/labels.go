@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrInvalidLabel = apperr.New("invalid_label")
+
+const maxLabelLength = 64
+
+// Free-form labels used to organize apps and deployments, such as the project or the
+// stack they belong to. Always sorted and without duplicates.
+type Labels []string
+
+// Builds labels from raw values. Values are trimmed and should not be empty nor
+// longer than 64 characters.
+func LabelsFrom(values []string) (Labels, error) {
+	labels := make(Labels, 0, len(values))
+
+	for _, value := range values {
+		label := strings.TrimSpace(value)
+
+		if label == "" || len(label) > maxLabelLength {
+			return nil, ErrInvalidLabel
+		}
+
+		labels = append(labels, label)
+	}
+
+	slices.Sort(labels)
+
+	return slices.Compact(labels), nil
+}
+
+// Returns true if every given label is part of those ones.
+func (l Labels) Contains(labels Labels) bool {
+	for _, label := range labels {
+		if _, found := slices.BinarySearch(l, label); !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l Labels) Value() (driver.Value, error) {
+	// Always stored as an array so it could be queried with json_each
+	if l == nil {
+		l = Labels{}
+	}
+
+	return storage.ValueJSON(l)
+}
+
+func (l *Labels) Scan(value any) error { return storage.ScanJSON(value, l) }
@@ -0,0 +1,33 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Labels(t *testing.T) {
+	t.Run("should be trimmed, sorted and without duplicates", func(t *testing.T) {
+		labels, err := domain.LabelsFrom([]string{" team-a", "stack:go ", "team-a"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.Labels{"stack:go", "team-a"}, labels)
+	})
+
+	t.Run("should reject empty or too long labels", func(t *testing.T) {
+		_, err := domain.LabelsFrom([]string{" "})
+		testutil.ErrorIs(t, domain.ErrInvalidLabel, err)
+
+		_, err = domain.LabelsFrom([]string{"a-label-which-is-definitely-way-too-long-to-be-accepted-as-a-label-by-seelf"})
+		testutil.ErrorIs(t, domain.ErrInvalidLabel, err)
+	})
+
+	t.Run("should check if it contains every given label", func(t *testing.T) {
+		labels := domain.Labels{"stack:go", "team-a"}
+
+		testutil.IsTrue(t, labels.Contains(nil))
+		testutil.IsTrue(t, labels.Contains(domain.Labels{"team-a"}))
+		testutil.IsFalse(t, labels.Contains(domain.Labels{"team-a", "team-b"}))
+	})
+}
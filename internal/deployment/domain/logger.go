@@ -1,13 +1,111 @@
 package domain
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
-// Specific logger interface use by deployment jobs to document the deployment process.
-type DeploymentLogger interface {
-	io.WriteCloser
+const (
+	DeploymentStepFetch   DeploymentStep = "fetch"   // Retrieve the deployment source files
+	DeploymentStepBuild   DeploymentStep = "build"   // Prepare the project to run on the target
+	DeploymentStepScan    DeploymentStep = "scan"    // Look for known vulnerabilities in built images
+	DeploymentStepDeploy  DeploymentStep = "deploy"  // Actually run the project on the target
+	DeploymentStepCleanup DeploymentStep = "cleanup" // Remove resources left behind by previous deployments
 
-	Stepf(string, ...any)
-	Warnf(string, ...any)
-	Infof(string, ...any)
-	Error(error)
+	LogLevelStep  LogLevel = "step"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+
+	LogStreamSeelf  LogStream = "seelf"  // Messages emitted by seelf itself
+	LogStreamOutput LogStream = "output" // Raw output of the tools used during the deployment
+)
+
+type (
+	// Pipeline step of a deployment.
+	DeploymentStep string
+	// Severity of a deployment log record.
+	LogLevel string
+	// Where a deployment log record comes from.
+	LogStream string
+
+	// Specific logger interface use by deployment jobs to document the deployment process.
+	// Every message and every line written to it is recorded with the current pipeline step
+	// which starts as DeploymentStepFetch.
+	DeploymentLogger interface {
+		io.WriteCloser
+
+		Begin(DeploymentStep)
+		Stepf(string, ...any)
+		Warnf(string, ...any)
+		Infof(string, ...any)
+		Error(error)
+	}
+
+	// Single entry of a deployment log. Logs written before records were structured only
+	// have a message and the output stream.
+	DeploymentLogRecord struct {
+		Time    time.Time
+		Step    DeploymentStep
+		Level   LogLevel
+		Stream  LogStream
+		Message string
+	}
+
+	// Consecutive records of the same pipeline step.
+	DeploymentLogStep struct {
+		Step      DeploymentStep
+		StartedAt time.Time
+		Duration  time.Duration // Until the next step starts or the last record of this one if it is the last
+		Records   []DeploymentLogRecord
+	}
+)
+
+// Renders the record as a plain text line, without the trailing new line.
+func (r DeploymentLogRecord) String() string {
+	if r.Stream == LogStreamOutput {
+		return r.Message
+	}
+
+	switch r.Level {
+	case LogLevelStep:
+		return "[STEP] " + r.Message
+	case LogLevelWarn:
+		return "[WARN] " + r.Message
+	case LogLevelError:
+		return "[ERROR] " + r.Message
+	default:
+		return "[INFO] " + r.Message
+	}
+}
+
+// Groups the given records by pipeline step. A step appearing several times, because
+// the deployment has been retried for example, results in multiple groups.
+func GroupDeploymentLogRecords(records []DeploymentLogRecord) []DeploymentLogStep {
+	var steps []DeploymentLogStep
+
+	for _, record := range records {
+		if len(steps) == 0 || steps[len(steps)-1].Step != record.Step {
+			steps = append(steps, DeploymentLogStep{
+				Step:      record.Step,
+				StartedAt: record.Time,
+			})
+		}
+
+		steps[len(steps)-1].Records = append(steps[len(steps)-1].Records, record)
+	}
+
+	for i := range steps {
+		end := steps[i].Records[len(steps[i].Records)-1].Time
+
+		if i < len(steps)-1 {
+			end = steps[i+1].StartedAt
+		}
+
+		if !steps[i].StartedAt.IsZero() && end.After(steps[i].StartedAt) {
+			steps[i].Duration = end.Sub(steps[i].StartedAt)
+		}
+	}
+
+	return steps
 }
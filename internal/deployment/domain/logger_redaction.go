@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	RedactedLogValue = "[REDACTED]" // Replaces secrets found in deployment logs
+
+	minRedactedSecretLength  = 4         // Shorter values are too likely to appear in legitimate output
+	maxRedactedPendingLength = 64 * 1024 // Partial output longer than this is written anyway to keep the memory bounded
+)
+
+// Common credential patterns redacted even if the secret is not known, matched groups
+// being kept as is.
+var redactedLogPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]+:)[^@\s]+(@)`),                                                       // Url credentials
+	regexp.MustCompile(`(?i)(\b(?:bearer|basic)\s+)[a-z0-9._~+/=-]{8,}`),                                                         // Authorization headers
+	regexp.MustCompile(`(?i)(\b[a-z0-9_.-]*(?:password|passwd|secret|token|api_?key)[a-z0-9_.-]*["']?\s*[=:]\s*)["']?[^\s"',]+`), // Assignments
+	regexp.MustCompile(`()\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),                                                                        // AWS access keys
+	regexp.MustCompile(`()\b(?:gh[pousr]_[a-zA-Z0-9]{36,}|github_pat_[a-zA-Z0-9_]{22,}|glpat-[a-zA-Z0-9_-]{20,})`),               // GitHub and GitLab tokens
+}
+
+type (
+	// Masks known secret values and common credential patterns in deployment logs.
+	// Secrets could be added while the deployment is running, once they are known.
+	LogRedactor struct {
+		mu       sync.RWMutex
+		secrets  []string
+		replacer *strings.Replacer
+	}
+
+	// Deployment logger which redacts messages and output before they are written.
+	redactedLogger struct {
+		DeploymentLogger
+
+		mu       sync.Mutex
+		redactor *LogRedactor
+		pending  []byte // Raw output not terminated by a new line or carriage return yet
+	}
+)
+
+func NewLogRedactor() *LogRedactor {
+	return &LogRedactor{}
+}
+
+// Adds the given secrets to the values to redact.
+func (r *LogRedactor) Add(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, secret := range secrets {
+		if len(secret) >= minRedactedSecretLength && !slices.Contains(r.secrets, secret) {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+
+	// Longest secrets first so a secret containing another one is entirely redacted
+	slices.SortFunc(r.secrets, func(a, b string) int { return len(b) - len(a) })
+
+	pairs := make([]string, 0, len(r.secrets)*2)
+
+	for _, secret := range r.secrets {
+		pairs = append(pairs, secret, RedactedLogValue)
+	}
+
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redacts known secrets and credential patterns from the given text.
+func (r *LogRedactor) Redact(text string) string {
+	r.mu.RLock()
+	replacer := r.replacer
+	r.mu.RUnlock()
+
+	if replacer != nil {
+		text = replacer.Replace(text)
+	}
+
+	for _, pattern := range redactedLogPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+RedactedLogValue+"${2}")
+	}
+
+	return text
+}
+
+// Wraps the given logger so everything written to it is redacted first. Raw output is
+// redacted line by line, progress output rewriting the same line with carriage returns
+// included, so secrets split across writes are still caught.
+func NewRedactedLogger(logger DeploymentLogger, redactor *LogRedactor) DeploymentLogger {
+	return &redactedLogger{DeploymentLogger: logger, redactor: redactor}
+}
+
+func (l *redactedLogger) Begin(step DeploymentStep) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	l.DeploymentLogger.Begin(step)
+}
+
+func (l *redactedLogger) Stepf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	l.DeploymentLogger.Stepf("%s", l.redactor.Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactedLogger) Warnf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	l.DeploymentLogger.Warnf("%s", l.redactor.Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactedLogger) Infof(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	l.DeploymentLogger.Infof("%s", l.redactor.Redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactedLogger) Error(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	l.DeploymentLogger.Error(errors.New(l.redactor.Redact(err.Error())))
+}
+
+func (l *redactedLogger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = append(l.pending, p...)
+
+	idx := bytes.LastIndexAny(l.pending, "\r\n")
+
+	if idx < 0 {
+		if len(l.pending) > maxRedactedPendingLength {
+			l.flush()
+		}
+
+		return len(p), nil
+	}
+
+	lines := l.pending[:idx+1]
+	l.pending = append([]byte(nil), l.pending[idx+1:]...)
+
+	if _, err := l.DeploymentLogger.Write([]byte(l.redactor.Redact(string(lines)))); err != nil {
+		return 0, err
+	}
+
+	if len(l.pending) > maxRedactedPendingLength {
+		l.flush()
+	}
+
+	return len(p), nil
+}
+
+func (l *redactedLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+	return l.DeploymentLogger.Close()
+}
+
+// Writes the remaining partial output line if any. The mutex must be held.
+func (l *redactedLogger) flush() {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	line := l.pending
+	l.pending = nil
+	l.DeploymentLogger.Write([]byte(l.redactor.Redact(string(line))))
+}
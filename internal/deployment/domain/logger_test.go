@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeploymentLogRecord(t *testing.T) {
+	t.Run("should render as a plain text line", func(t *testing.T) {
+		tests := []struct {
+			record   domain.DeploymentLogRecord
+			expected string
+		}{
+			{domain.DeploymentLogRecord{Level: domain.LogLevelStep, Stream: domain.LogStreamSeelf, Message: "msg"}, "[STEP] msg"},
+			{domain.DeploymentLogRecord{Level: domain.LogLevelInfo, Stream: domain.LogStreamSeelf, Message: "msg"}, "[INFO] msg"},
+			{domain.DeploymentLogRecord{Level: domain.LogLevelWarn, Stream: domain.LogStreamSeelf, Message: "msg"}, "[WARN] msg"},
+			{domain.DeploymentLogRecord{Level: domain.LogLevelError, Stream: domain.LogStreamSeelf, Message: "msg"}, "[ERROR] msg"},
+			{domain.DeploymentLogRecord{Level: domain.LogLevelInfo, Stream: domain.LogStreamOutput, Message: "msg"}, "msg"},
+		}
+
+		for _, test := range tests {
+			t.Run(test.expected, func(t *testing.T) {
+				testutil.Equals(t, test.expected, test.record.String())
+			})
+		}
+	})
+}
+
+func Test_GroupDeploymentLogRecords(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	t.Run("should return nothing if there is no record", func(t *testing.T) {
+		testutil.HasLength(t, domain.GroupDeploymentLogRecords(nil), 0)
+	})
+
+	t.Run("should group consecutive records by step and compute durations", func(t *testing.T) {
+		records := []domain.DeploymentLogRecord{
+			{Time: at(0), Step: domain.DeploymentStepFetch, Message: "1"},
+			{Time: at(2), Step: domain.DeploymentStepFetch, Message: "2"},
+			{Time: at(5), Step: domain.DeploymentStepBuild, Message: "3"},
+			{Time: at(9), Step: domain.DeploymentStepDeploy, Message: "4"},
+			{Time: at(12), Step: domain.DeploymentStepDeploy, Message: "5"},
+			{Time: at(20), Step: domain.DeploymentStepFetch, Message: "6"},
+		}
+
+		steps := domain.GroupDeploymentLogRecords(records)
+
+		testutil.DeepEquals(t, []domain.DeploymentLogStep{
+			{Step: domain.DeploymentStepFetch, StartedAt: at(0), Duration: 5 * time.Second, Records: records[0:2]},
+			{Step: domain.DeploymentStepBuild, StartedAt: at(5), Duration: 4 * time.Second, Records: records[2:3]},
+			{Step: domain.DeploymentStepDeploy, StartedAt: at(9), Duration: 11 * time.Second, Records: records[3:5]},
+			{Step: domain.DeploymentStepFetch, StartedAt: at(20), Duration: 0, Records: records[5:6]},
+		}, steps)
+	})
+
+	t.Run("should not compute durations of records without time", func(t *testing.T) {
+		records := []domain.DeploymentLogRecord{
+			{Message: "1"},
+			{Message: "2"},
+		}
+
+		testutil.DeepEquals(t, []domain.DeploymentLogStep{
+			{Records: records},
+		}, domain.GroupDeploymentLogRecords(records))
+	})
+}
@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+const (
+	MonitorStatusUnknown MonitorStatus = iota // Not checked yet
+	MonitorStatusUp
+	MonitorStatusDown
+)
+
+const (
+	MinMonitorInterval       = 30 * time.Second // Prevent apps from being flooded by seelf itself
+	DefaultMonitorStatusCode = 200
+)
+
+var (
+	ErrInvalidMonitorInterval   = apperr.New("invalid_monitor_interval")
+	ErrInvalidMonitorStatusCode = apperr.New("invalid_monitor_status_code")
+)
+
+type (
+	MonitorID     string
+	MonitorStatus uint8
+
+	// Describe how the public url of an app environment should be checked.
+	UptimeCheck struct {
+		interval   time.Duration
+		statusCode int
+		keyword    monad.Maybe[string]
+	}
+
+	// Outcome of a request made to the public url of an app environment.
+	ProbeResult struct {
+		Url          Url
+		CheckedAt    time.Time
+		StatusCode   monad.Maybe[int]    // Not set if no response has been received
+		ResponseTime time.Duration       // Time taken to receive the response headers
+		Body         string              // Beginning of the response body, used to look for the keyword
+		Error        monad.Maybe[string] // Set if the request could not be made
+	}
+
+	// Periodically checks the public url of an application environment to make sure
+	// it is up.
+	Monitor struct {
+		event.Emitter
+
+		id          MonitorID
+		app         AppID
+		environment Environment
+		check       UptimeCheck
+		status      MonitorStatus
+		nextCheckAt time.Time
+		created     shared.Action[auth.UserID]
+	}
+
+	MonitorsReader interface {
+		GetByID(context.Context, MonitorID) (Monitor, error)
+		GetByApp(context.Context, AppID, Environment) (Monitor, error)
+		// Retrieve monitors which should be checked at the given date.
+		GetDue(context.Context, time.Time) ([]Monitor, error)
+	}
+
+	MonitorsWriter interface {
+		Write(context.Context, ...*Monitor) error
+	}
+
+	// Checks the public url of an app environment. It never fails, errors are reported
+	// in the result.
+	UptimeProbe interface {
+		Probe(context.Context, Url) ProbeResult
+	}
+
+	MonitorCreated struct {
+		bus.Notification
+
+		ID          MonitorID
+		AppID       AppID
+		Environment Environment
+		Check       UptimeCheck
+		NextCheckAt time.Time
+		Created     shared.Action[auth.UserID]
+	}
+
+	MonitorCheckChanged struct {
+		bus.Notification
+
+		ID          MonitorID
+		Check       UptimeCheck
+		NextCheckAt time.Time
+	}
+
+	MonitorChecked struct {
+		bus.Notification
+
+		ID           MonitorID
+		Status       MonitorStatus
+		CheckedAt    time.Time
+		StatusCode   monad.Maybe[int]
+		ResponseTime time.Duration
+		Reason       monad.Maybe[string]
+		NextCheckAt  time.Time
+	}
+
+	// Raised when an app environment goes down or comes back up. It is not raised when
+	// the first check of a monitor succeeds.
+	MonitorStatusChanged struct {
+		bus.Notification
+
+		ID        MonitorID
+		Config    DeploymentConfig
+		Url       Url
+		Status    MonitorStatus
+		Reason    monad.Maybe[string]
+		ChangedAt time.Time
+	}
+
+	MonitorDeleted struct {
+		bus.Notification
+
+		ID MonitorID
+	}
+)
+
+func (MonitorCreated) Name_() string       { return "deployment.event.monitor_created" }
+func (MonitorCheckChanged) Name_() string  { return "deployment.event.monitor_check_changed" }
+func (MonitorChecked) Name_() string       { return "deployment.event.monitor_checked" }
+func (MonitorStatusChanged) Name_() string { return "deployment.event.monitor_status_changed" }
+func (MonitorDeleted) Name_() string       { return "deployment.event.monitor_deleted" }
+
+// Builds a new uptime check. The public url is considered up if it responds with the
+// given status code and, if set, its body contains the keyword.
+func NewUptimeCheck(interval time.Duration, statusCode int, keyword monad.Maybe[string]) (UptimeCheck, error) {
+	if interval < MinMonitorInterval {
+		return UptimeCheck{}, ErrInvalidMonitorInterval
+	}
+
+	if statusCode < 100 || statusCode > 599 {
+		return UptimeCheck{}, ErrInvalidMonitorStatusCode
+	}
+
+	return UptimeCheck{
+		interval:   interval,
+		statusCode: statusCode,
+		keyword:    keyword,
+	}, nil
+}
+
+func (c UptimeCheck) Interval() time.Duration      { return c.interval }
+func (c UptimeCheck) StatusCode() int              { return c.statusCode }
+func (c UptimeCheck) Keyword() monad.Maybe[string] { return c.keyword }
+
+// Evaluates the given result and returns the reason why it is considered down if any.
+func (c UptimeCheck) Evaluate(result ProbeResult) (reason monad.Maybe[string]) {
+	if err, isSet := result.Error.TryGet(); isSet {
+		reason.Set(err)
+		return reason
+	}
+
+	if code := result.StatusCode.Get(0); code != c.statusCode {
+		reason.Set(fmt.Sprintf("unexpected status code %d", code))
+		return reason
+	}
+
+	if keyword, isSet := c.keyword.TryGet(); isSet && !strings.Contains(result.Body, keyword) {
+		reason.Set("keyword not found")
+	}
+
+	return reason
+}
+
+// Starts monitoring the public url of an app environment. The first check is made
+// as soon as possible.
+func NewMonitor(app App, env Environment, check UptimeCheck, uid auth.UserID) (m Monitor, err error) {
+	if _, err = EnvironmentFrom(string(env)); err != nil {
+		return m, err
+	}
+
+	if app.cleanupRequested.HasValue() {
+		return m, ErrAppCleanupRequested
+	}
+
+	m.apply(MonitorCreated{
+		ID:          id.New[MonitorID](),
+		AppID:       app.ID(),
+		Environment: env,
+		Check:       check,
+		NextCheckAt: time.Now().UTC(),
+		Created:     shared.NewAction(uid),
+	})
+
+	return m, nil
+}
+
+// Recreates a monitor from the persistent storage.
+func MonitorFrom(scanner storage.Scanner) (m Monitor, err error) {
+	var (
+		interval  int64
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&m.id,
+		&m.app,
+		&m.environment,
+		&interval,
+		&m.check.statusCode,
+		&m.check.keyword,
+		&m.status,
+		&m.nextCheckAt,
+		&createdAt,
+		&createdBy,
+	)
+
+	m.check.interval = time.Duration(interval) * time.Second
+	m.created = shared.ActionFrom(createdBy, createdAt)
+
+	return m, err
+}
+
+// Updates how the public url is checked. The next check is made as soon as possible.
+func (m *Monitor) HasCheck(check UptimeCheck) {
+	if m.check == check {
+		return
+	}
+
+	m.apply(MonitorCheckChanged{
+		ID:          m.id,
+		Check:       check,
+		NextCheckAt: time.Now().UTC(),
+	})
+}
+
+// Records the result of a check made on the public url of the app environment
+// represented by the given config.
+func (m *Monitor) Checked(config DeploymentConfig, result ProbeResult) {
+	reason := m.check.Evaluate(result)
+	status := MonitorStatusUp
+
+	if reason.HasValue() {
+		status = MonitorStatusDown
+	}
+
+	previous := m.status
+
+	m.apply(MonitorChecked{
+		ID:           m.id,
+		Status:       status,
+		CheckedAt:    result.CheckedAt,
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime,
+		Reason:       reason,
+		NextCheckAt:  result.CheckedAt.Add(m.check.interval),
+	})
+
+	if status == previous || (previous == MonitorStatusUnknown && status == MonitorStatusUp) {
+		return
+	}
+
+	m.apply(MonitorStatusChanged{
+		ID:        m.id,
+		Config:    config,
+		Url:       result.Url,
+		Status:    status,
+		Reason:    reason,
+		ChangedAt: result.CheckedAt,
+	})
+}
+
+// Stops monitoring the app environment.
+func (m *Monitor) Delete() {
+	m.apply(MonitorDeleted{
+		ID: m.id,
+	})
+}
+
+func (m *Monitor) ID() MonitorID            { return m.id }
+func (m *Monitor) AppID() AppID             { return m.app }
+func (m *Monitor) Environment() Environment { return m.environment }
+func (m *Monitor) Check() UptimeCheck       { return m.check }
+func (m *Monitor) Status() MonitorStatus    { return m.status }
+func (m *Monitor) NextCheckAt() time.Time   { return m.nextCheckAt }
+func (m *Monitor) CreatedBy() auth.UserID   { return m.created.By() }
+
+func (m *Monitor) apply(e event.Event) {
+	switch evt := e.(type) {
+	case MonitorCreated:
+		m.id = evt.ID
+		m.app = evt.AppID
+		m.environment = evt.Environment
+		m.check = evt.Check
+		m.nextCheckAt = evt.NextCheckAt
+		m.created = evt.Created
+	case MonitorCheckChanged:
+		m.check = evt.Check
+		m.nextCheckAt = evt.NextCheckAt
+	case MonitorChecked:
+		m.status = evt.Status
+		m.nextCheckAt = evt.NextCheckAt
+	}
+
+	event.Store(m, e)
+}
+
+// Public url of the app environment represented by this config when deployed on a
+// target exposed at the given url.
+func (c DeploymentConfig) PublicUrl(targetUrl Url) Url {
+	return targetUrl.Root().WithoutUser().SubDomain(c.SubDomain("", true))
+}
@@ -0,0 +1,169 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UptimeCheck(t *testing.T) {
+	t.Run("should require a minimum interval", func(t *testing.T) {
+		_, err := domain.NewUptimeCheck(10*time.Second, 200, monad.None[string]())
+
+		testutil.ErrorIs(t, domain.ErrInvalidMonitorInterval, err)
+	})
+
+	t.Run("should require a valid status code", func(t *testing.T) {
+		_, err := domain.NewUptimeCheck(time.Minute, 600, monad.None[string]())
+
+		testutil.ErrorIs(t, domain.ErrInvalidMonitorStatusCode, err)
+	})
+
+	t.Run("should evaluate a probe result", func(t *testing.T) {
+		check := must.Panic(domain.NewUptimeCheck(time.Minute, 200, monad.Value("Welcome")))
+
+		tests := []struct {
+			result domain.ProbeResult
+			reason monad.Maybe[string]
+		}{
+			{domain.ProbeResult{StatusCode: monad.Value(200), Body: "<h1>Welcome</h1>"}, monad.None[string]()},
+			{domain.ProbeResult{Error: monad.Value("connection refused")}, monad.Value("connection refused")},
+			{domain.ProbeResult{StatusCode: monad.Value(502), Body: "Welcome"}, monad.Value("unexpected status code 502")},
+			{domain.ProbeResult{StatusCode: monad.Value(200), Body: "Not found"}, monad.Value("keyword not found")},
+		}
+
+		for _, test := range tests {
+			t.Run(test.reason.Get("up"), func(t *testing.T) {
+				testutil.Equals(t, test.reason, check.Evaluate(test.result))
+			})
+		}
+	})
+}
+
+func Test_Monitor(t *testing.T) {
+	app := must.Panic(domain.NewApp("my-app",
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+		"uid",
+	))
+	config := must.Panic(app.ConfigSnapshotFor(domain.Production))
+	check := must.Panic(domain.NewUptimeCheck(time.Minute, 200, monad.None[string]()))
+	url := config.PublicUrl(must.Panic(domain.UrlFrom("https://docker.localhost")))
+	up := domain.ProbeResult{Url: url, CheckedAt: time.Now().UTC(), StatusCode: monad.Value(200)}
+	down := domain.ProbeResult{Url: url, CheckedAt: time.Now().UTC(), StatusCode: monad.Value(503)}
+
+	t.Run("should require a valid environment", func(t *testing.T) {
+		_, err := domain.NewMonitor(app, "dev", check, "uid")
+
+		testutil.ErrorIs(t, domain.ErrInvalidEnvironmentName, err)
+	})
+
+	t.Run("should not be created if the app cleanup has been requested", func(t *testing.T) {
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+		app.RequestCleanup("uid")
+
+		_, err := domain.NewMonitor(app, domain.Production, check, "uid")
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+
+	t.Run("could be created for an app environment", func(t *testing.T) {
+		m, err := domain.NewMonitor(app, domain.Production, check, "uid")
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.MonitorCreated](t, &m, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, app.ID(), created.AppID)
+		testutil.Equals(t, domain.Production, created.Environment)
+		testutil.Equals(t, check, created.Check)
+		testutil.IsFalse(t, created.NextCheckAt.IsZero())
+		testutil.Equals(t, "uid", created.Created.By())
+		testutil.Equals(t, domain.MonitorStatusUnknown, m.Status())
+	})
+
+	t.Run("could have its check changed and raise the event only if different", func(t *testing.T) {
+		m := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+		updated := must.Panic(domain.NewUptimeCheck(5*time.Minute, 204, monad.Value("ok")))
+
+		m.HasCheck(check)
+		m.HasCheck(updated)
+
+		testutil.HasNEvents(t, &m, 2)
+		changed := testutil.EventIs[domain.MonitorCheckChanged](t, &m, 1)
+		testutil.Equals(t, m.ID(), changed.ID)
+		testutil.Equals(t, updated, changed.Check)
+	})
+
+	t.Run("should not raise a status change when the first check succeeds", func(t *testing.T) {
+		m := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+
+		m.Checked(config, up)
+
+		testutil.HasNEvents(t, &m, 2)
+		checked := testutil.EventIs[domain.MonitorChecked](t, &m, 1)
+		testutil.Equals(t, domain.MonitorStatusUp, checked.Status)
+		testutil.Equals(t, monad.Value(200), checked.StatusCode)
+		testutil.IsFalse(t, checked.Reason.HasValue())
+		testutil.Equals(t, up.CheckedAt.Add(time.Minute), checked.NextCheckAt)
+		testutil.Equals(t, domain.MonitorStatusUp, m.Status())
+	})
+
+	t.Run("should raise a status change when going down and back up", func(t *testing.T) {
+		m := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+
+		m.Checked(config, up)
+		m.Checked(config, down)
+		m.Checked(config, down)
+		m.Checked(config, up)
+
+		testutil.HasNEvents(t, &m, 7)
+
+		wentDown := testutil.EventIs[domain.MonitorStatusChanged](t, &m, 3)
+		testutil.Equals(t, m.ID(), wentDown.ID)
+		testutil.DeepEquals(t, config, wentDown.Config)
+		testutil.Equals(t, "https://my-app.docker.localhost", wentDown.Url.String())
+		testutil.Equals(t, domain.MonitorStatusDown, wentDown.Status)
+		testutil.Equals(t, monad.Value("unexpected status code 503"), wentDown.Reason)
+
+		testutil.EventIs[domain.MonitorChecked](t, &m, 4)
+		testutil.EventIs[domain.MonitorChecked](t, &m, 5)
+
+		backUp := testutil.EventIs[domain.MonitorStatusChanged](t, &m, 6)
+		testutil.Equals(t, domain.MonitorStatusUp, backUp.Status)
+		testutil.IsFalse(t, backUp.Reason.HasValue())
+	})
+
+	t.Run("should raise a status change when the first check fails", func(t *testing.T) {
+		m := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+
+		m.Checked(config, down)
+
+		testutil.HasNEvents(t, &m, 3)
+		testutil.Equals(t, domain.MonitorStatusDown, testutil.EventIs[domain.MonitorStatusChanged](t, &m, 2).Status)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		m := must.Panic(domain.NewMonitor(app, domain.Production, check, "uid"))
+
+		m.Delete()
+
+		deleted := testutil.EventIs[domain.MonitorDeleted](t, &m, 1)
+		testutil.Equals(t, m.ID(), deleted.ID)
+	})
+
+	t.Run("should build the public url of an app environment", func(t *testing.T) {
+		targetUrl := must.Panic(domain.UrlFrom("http://user@docker.localhost/some/path"))
+		staging := must.Panic(app.ConfigSnapshotFor(domain.Staging))
+
+		testutil.Equals(t, "http://my-app.docker.localhost", config.PublicUrl(targetUrl).String())
+		testutil.Equals(t, "http://my-app-staging.docker.localhost", staging.PublicUrl(targetUrl).String())
+	})
+}
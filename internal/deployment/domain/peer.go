@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	PeerID string
+
+	// Another seelf instance registered on this one so its apps and deployments could be
+	// listed alongside local ones. It is reached with an API token of one of its users.
+	Peer struct {
+		event.Emitter
+
+		id      PeerID
+		name    string
+		url     Url
+		token   string
+		created shared.Action[auth.UserID]
+	}
+
+	PeersReader interface {
+		GetByID(context.Context, PeerID) (Peer, error)
+		GetAll(context.Context) ([]Peer, error)
+	}
+
+	PeersWriter interface {
+		Write(context.Context, ...*Peer) error
+	}
+
+	PeerCreated struct {
+		bus.Notification
+
+		ID      PeerID
+		Name    string
+		Url     Url
+		Token   string
+		Created shared.Action[auth.UserID]
+	}
+
+	PeerRenamed struct {
+		bus.Notification
+
+		ID   PeerID
+		Name string
+	}
+
+	PeerUrlChanged struct {
+		bus.Notification
+
+		ID  PeerID
+		Url Url
+	}
+
+	PeerTokenChanged struct {
+		bus.Notification
+
+		ID    PeerID
+		Token string
+	}
+
+	PeerDeleted struct {
+		bus.Notification
+
+		ID PeerID
+	}
+)
+
+func (PeerCreated) Name_() string      { return "deployment.event.peer_created" }
+func (PeerRenamed) Name_() string      { return "deployment.event.peer_renamed" }
+func (PeerUrlChanged) Name_() string   { return "deployment.event.peer_url_changed" }
+func (PeerTokenChanged) Name_() string { return "deployment.event.peer_token_changed" }
+func (PeerDeleted) Name_() string      { return "deployment.event.peer_deleted" }
+
+// Registers a new peer instance reachable at the given URL with the given API token.
+func NewPeer(name string, url Url, token string, uid auth.UserID) (p Peer) {
+	p.apply(PeerCreated{
+		ID:      id.New[PeerID](),
+		Name:    name,
+		Url:     url,
+		Token:   token,
+		Created: shared.NewAction(uid),
+	})
+
+	return p
+}
+
+// Recreates a peer from the persistent storage.
+func PeerFrom(scanner storage.Scanner) (p Peer, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&p.id,
+		&p.name,
+		&p.url,
+		&p.token,
+		&createdAt,
+		&createdBy,
+	)
+
+	p.created = shared.ActionFrom(createdBy, createdAt)
+
+	return p, err
+}
+
+// Renames the peer.
+func (p *Peer) Rename(name string) {
+	if p.name == name {
+		return
+	}
+
+	p.apply(PeerRenamed{
+		ID:   p.id,
+		Name: name,
+	})
+}
+
+// Updates the URL at which the peer is reached.
+func (p *Peer) HasUrl(url Url) {
+	if p.url == url {
+		return
+	}
+
+	p.apply(PeerUrlChanged{
+		ID:  p.id,
+		Url: url,
+	})
+}
+
+// Updates the API token used to authenticate on the peer.
+func (p *Peer) UseToken(token string) {
+	if p.token == token {
+		return
+	}
+
+	p.apply(PeerTokenChanged{
+		ID:    p.id,
+		Token: token,
+	})
+}
+
+func (p *Peer) Delete() {
+	p.apply(PeerDeleted{
+		ID: p.id,
+	})
+}
+
+func (p *Peer) ID() PeerID             { return p.id }
+func (p *Peer) Name() string           { return p.name }
+func (p *Peer) Url() Url               { return p.url }
+func (p *Peer) Token() string          { return p.token }
+func (p *Peer) CreatedBy() auth.UserID { return p.created.By() }
+
+func (p *Peer) apply(e event.Event) {
+	switch evt := e.(type) {
+	case PeerCreated:
+		p.id = evt.ID
+		p.name = evt.Name
+		p.url = evt.Url
+		p.token = evt.Token
+		p.created = evt.Created
+	case PeerRenamed:
+		p.name = evt.Name
+	case PeerUrlChanged:
+		p.url = evt.Url
+	case PeerTokenChanged:
+		p.token = evt.Token
+	}
+
+	event.Store(p, e)
+}
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Peer(t *testing.T) {
+	url := must.Panic(domain.UrlFrom("https://seelf.example.com"))
+
+	t.Run("could be created", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+
+		created := testutil.EventIs[domain.PeerCreated](t, &p, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "vps", created.Name)
+		testutil.Equals(t, "https://seelf.example.com", created.Url.String())
+		testutil.Equals(t, "a token", created.Token)
+		testutil.Equals(t, "uid", created.Created.By())
+		testutil.IsFalse(t, created.Created.At().IsZero())
+	})
+
+	t.Run("could be renamed and raise the event only if different", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+
+		p.Rename("home lab")
+		p.Rename("home lab")
+
+		testutil.HasNEvents(t, &p, 2)
+		renamed := testutil.EventIs[domain.PeerRenamed](t, &p, 1)
+		testutil.Equals(t, p.ID(), renamed.ID)
+		testutil.Equals(t, "home lab", renamed.Name)
+	})
+
+	t.Run("could have its url changed and raise the event only if different", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+		newUrl := must.Panic(domain.UrlFrom("http://192.168.1.10:8080"))
+
+		p.HasUrl(url)
+		p.HasUrl(newUrl)
+		p.HasUrl(newUrl)
+
+		testutil.HasNEvents(t, &p, 2)
+		changed := testutil.EventIs[domain.PeerUrlChanged](t, &p, 1)
+		testutil.Equals(t, newUrl, changed.Url)
+	})
+
+	t.Run("could have its token changed and raise the event only if different", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+
+		p.UseToken("a token")
+		p.UseToken("another token")
+
+		testutil.HasNEvents(t, &p, 2)
+		changed := testutil.EventIs[domain.PeerTokenChanged](t, &p, 1)
+		testutil.Equals(t, "another token", changed.Token)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		p := domain.NewPeer("vps", url, "a token", "uid")
+
+		p.Delete()
+
+		deleted := testutil.EventIs[domain.PeerDeleted](t, &p, 1)
+		testutil.Equals(t, p.ID(), deleted.ID)
+	})
+}
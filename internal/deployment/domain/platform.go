@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidPlatform      = apperr.New("invalid_platform")
+	ErrPlatformNotSupported = apperr.New("platform_not_supported")
+)
+
+type (
+	// Operating system and architecture on which containers run, in the form
+	// os/arch[/variant] such as linux/amd64 or linux/arm64/v8.
+	Platform string
+
+	// Platforms supported by an application. Always sorted and without duplicates,
+	// an empty list means the application can run anywhere.
+	Platforms []Platform
+)
+
+// Builds a platform from a raw value. The value is trimmed and lowercased.
+func PlatformFrom(value string) (Platform, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	parts := strings.Split(value, "/")
+
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return "", ErrInvalidPlatform
+	}
+
+	return Platform(value), nil
+}
+
+// Returns the operating system part of the platform.
+func (p Platform) OS() string {
+	os, _, _ := p.parts()
+	return os
+}
+
+// Returns the architecture part of the platform.
+func (p Platform) Arch() string {
+	_, arch, _ := p.parts()
+	return arch
+}
+
+func (p Platform) parts() (os, arch, variant string) {
+	parts := strings.SplitN(string(p), "/", 3)
+
+	os = parts[0]
+
+	if len(parts) > 1 {
+		arch = parts[1]
+	}
+
+	if len(parts) > 2 {
+		variant = parts[2]
+	}
+
+	return os, arch, variant
+}
+
+// Builds platforms from raw values.
+func PlatformsFrom(values []string) (Platforms, error) {
+	platforms := make(Platforms, 0, len(values))
+
+	for _, value := range values {
+		platform, err := PlatformFrom(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		platforms = append(platforms, platform)
+	}
+
+	slices.Sort(platforms)
+
+	return slices.Compact(platforms), nil
+}
+
+// Returns true if the given platform is supported. A required platform without variant
+// matches every variant of the same os and architecture.
+func (p Platforms) Supports(platform Platform) bool {
+	if len(p) == 0 {
+		return true
+	}
+
+	os, arch, variant := platform.parts()
+
+	for _, required := range p {
+		ros, rarch, rvariant := required.parts()
+
+		if ros == os && rarch == arch && (rvariant == "" || rvariant == variant) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Platforms) Value() (driver.Value, error) {
+	if p == nil {
+		p = Platforms{}
+	}
+
+	return storage.ValueJSON(p)
+}
+
+func (p *Platforms) Scan(value any) error { return storage.ScanJSON(value, p) }
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Platform(t *testing.T) {
+	t.Run("should be trimmed and lowercased", func(t *testing.T) {
+		platform, err := domain.PlatformFrom(" Linux/ARM64/v8 ")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "linux/arm64/v8", platform)
+		testutil.Equals(t, "linux", platform.OS())
+		testutil.Equals(t, "arm64", platform.Arch())
+	})
+
+	t.Run("should reject values not in the os/arch[/variant] form", func(t *testing.T) {
+		for _, value := range []string{"", "linux", "linux/", "/amd64", "linux/arm/v7/extra"} {
+			_, err := domain.PlatformFrom(value)
+			testutil.ErrorIs(t, domain.ErrInvalidPlatform, err)
+		}
+	})
+}
+
+func Test_Platforms(t *testing.T) {
+	t.Run("should be sorted and without duplicates", func(t *testing.T) {
+		platforms, err := domain.PlatformsFrom([]string{"linux/arm64", "linux/amd64", "LINUX/arm64"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.Platforms{"linux/amd64", "linux/arm64"}, platforms)
+	})
+
+	t.Run("should support any platform if empty", func(t *testing.T) {
+		var platforms domain.Platforms
+
+		testutil.IsTrue(t, platforms.Supports("windows/amd64"))
+	})
+
+	t.Run("should match every variant of a platform required without one", func(t *testing.T) {
+		platforms := domain.Platforms{"linux/amd64", "linux/arm/v7"}
+
+		testutil.IsTrue(t, platforms.Supports("linux/amd64"))
+		testutil.IsTrue(t, platforms.Supports("linux/amd64/v2"))
+		testutil.IsTrue(t, platforms.Supports("linux/arm/v7"))
+		testutil.IsFalse(t, platforms.Supports("linux/arm/v6"))
+		testutil.IsFalse(t, platforms.Supports("windows/amd64"))
+	})
+}
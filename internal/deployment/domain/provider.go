@@ -2,14 +2,18 @@ package domain
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
 )
 
 var (
 	ErrNoValidProviderFound   = apperr.New("no_valid_provider_found")
 	ErrInvalidProviderPayload = apperr.New("invalid_provider_payload")
+	ErrServiceNotRunning      = apperr.New("service_not_running")
 
 	ProviderConfigTypes = storage.NewDiscriminatedMapper(func(c ProviderConfig) string { return c.Kind() })
 )
@@ -28,14 +32,88 @@ type (
 		// Prepare the given payload representing a Provider specific configuration.
 		Prepare(ctx context.Context, payload any, existing ...ProviderConfig) (ProviderConfig, error)
 		// Deploy a deployment on the specified target and return services that has been deployed.
-		Deploy(context.Context, DeploymentContext, Deployment, Target, []Registry) (Services, error)
+		// Connection strings of the given add-ons are made available to every service.
+		Deploy(context.Context, DeploymentContext, Deployment, Target, []Registry, []Addon) (Services, error)
 		// Setup a target by deploying the needed stuff to actually serve deployments.
 		Setup(context.Context, Target) (TargetEntrypointsAssigned, error)
+		// Retrieve the platform (operating system and architecture) of the given target.
+		Platform(context.Context, Target) (Platform, error)
+		// Retrieve the version of the proxy which Setup and UpgradeProxy deploy on the given target.
+		ProxyVersion(Target) (string, error)
+		// Upgrade the proxy running on the given target to the version returned by ProxyVersion
+		// without dropping in-flight requests. The previous proxy is restored if the new one fails to start.
+		UpgradeProxy(context.Context, Target) error
 		// Remove target related configuration.
 		RemoveConfiguration(context.Context, Target) error
 		// Cleanup a target, removing every resources managed by seelf on it.
 		CleanupTarget(context.Context, Target, CleanupStrategy) error
 		// Cleanup an application on the specified target and environment, which means removing every possible stuff related to it
 		Cleanup(context.Context, AppID, Target, Environment, CleanupStrategy) error
+		// Provision (or update) the service of an add-on on the specified target.
+		ProvisionAddon(context.Context, Target, Addon) error
+		// Remove the service of an add-on from the specified target along with its data.
+		RemoveAddon(context.Context, Target, AddonID, CleanupStrategy) error
+		// Dump the data of an add-on, which supports backups, to the given writer.
+		BackupAddon(context.Context, Target, Addon, io.Writer) error
+		// Replace the data of an add-on with a dump previously made by BackupAddon.
+		RestoreAddon(context.Context, Target, Addon, io.Reader) error
+		// Retrieve the runtime logs of an application services deployed with the given config
+		// and call the handler for each line. When following logs, it returns once the
+		// context is done.
+		Logs(context.Context, DeploymentConfig, Target, ServiceLogsOptions, func(ServiceLog)) error
+		// Run a one-off command inside a running container of an application service deployed
+		// with the given config and return its exit code once it has exited.
+		Exec(context.Context, DeploymentConfig, Target, ServiceExec) (int, error)
+		// Retrieve resources currently consumed by every container of applications running
+		// on the given target.
+		Stats(context.Context, Target) ([]ServiceStats, error)
+		// Retrieve incidents which happened to containers of applications running on the
+		// given target between the two given dates.
+		Incidents(context.Context, Target, time.Time, time.Time) ([]ContainerIncident, error)
+		// Retrieve the capacity of the given target: running application containers, disk
+		// space used by images and free disk space.
+		Capacity(context.Context, Target) (TargetCapacity, error)
+		// Remove dangling images, stopped application containers and unused networks which
+		// have been created at least the given duration ago on the given target.
+		CollectGarbage(context.Context, Target, time.Duration) (GarbageCollectionReport, error)
+		// Retrieve requests served by the proxy of the given target to applications services
+		// between the two given dates.
+		AccessLogs(context.Context, Target, time.Time, time.Time) ([]AccessLog, error)
+		// Change the number of running containers of an application service deployed with
+		// the given config, stopping it if 0 is given.
+		Scale(context.Context, DeploymentConfig, Target, string, uint) error
+		// Restart containers of the given services of an application deployed with the given
+		// config, or all of them if no service is given.
+		Restart(context.Context, DeploymentConfig, Target, []string) error
+	}
+
+	// One-off command to run inside a service container.
+	ServiceExec struct {
+		Service string
+		Command []string
+		Tty     bool      // Allocate a pseudo terminal, the output is then a single stream
+		Stdin   io.Reader // Input sent to the command until EOF, nil to not attach it
+		Stdout  io.Writer // Receives the standard output, and the error one if Stderr is nil
+		Stderr  io.Writer // Receives the error output, ignored when a pseudo terminal is allocated
+	}
+
+	// Options used to retrieve the runtime logs of an application services.
+	ServiceLogsOptions struct {
+		Services []string               // Services to retrieve logs from, every one of them if empty
+		Tail     monad.Maybe[int]       // Number of lines to retrieve from the end of each container logs
+		Since    monad.Maybe[time.Time] // Only retrieve logs written after this date
+		Follow   bool                   // Keep sending new lines as they are written
+	}
+
+	// Single line written by a running service container.
+	ServiceLog struct {
+		Container string
+		Message   string
+	}
+
+	// Runtime logs of an application services sent as they are read from the target.
+	ServiceLogsStream struct {
+		Lines <-chan ServiceLog // Closed once every line has been sent
+		Err   <-chan error      // Receives the error which has ended the stream, if any, once lines is closed
 	}
 )
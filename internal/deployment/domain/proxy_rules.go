@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"regexp"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidRedirect   = apperr.New("invalid_redirect")
+	ErrInvalidHeaderName = apperr.New("invalid_header_name")
+	ErrProxyRulesMissing = apperr.New("proxy_rules_missing")
+
+	headerNameRegex = regexp.MustCompile("^[A-Za-z0-9-]+$") // Restricted to what could be safely used in a proxy label
+)
+
+type (
+	// Custom rules applied by the proxy to exposed HTTP services of an environment.
+	ProxyRules struct {
+		WWWRedirect   bool              `json:"www_redirect"`   // Also route the www subdomain and redirect it to the apex one
+		InsecurePaths []string          `json:"insecure_paths"` // Path prefixes served over plain HTTP instead of being redirected to HTTPS
+		Redirects     []Redirect        `json:"redirects"`
+		Headers       map[string]string `json:"headers"` // Custom headers added to every response
+	}
+
+	// Redirects requests whose url matches the regex to the replacement one, which
+	// could reference captured groups such as `${1}`.
+	Redirect struct {
+		Regex       string `json:"regex"`
+		Replacement string `json:"replacement"`
+		Permanent   bool   `json:"permanent"`
+	}
+)
+
+// Builds new proxy rules. At least one rule should be given.
+func NewProxyRules(
+	wwwRedirect bool,
+	insecurePaths []string,
+	redirects []Redirect,
+	headers map[string]string,
+) (ProxyRules, error) {
+	if !wwwRedirect && len(insecurePaths) == 0 && len(redirects) == 0 && len(headers) == 0 {
+		return ProxyRules{}, ErrProxyRulesMissing
+	}
+
+	return ProxyRules{
+		WWWRedirect:   wwwRedirect,
+		InsecurePaths: insecurePaths,
+		Redirects:     redirects,
+		Headers:       headers,
+	}, nil
+}
+
+// Builds a new redirect rule, making sure the regex compiles.
+func RedirectFrom(regex, replacement string, permanent bool) (Redirect, error) {
+	if regex == "" || replacement == "" {
+		return Redirect{}, ErrInvalidRedirect
+	}
+
+	if _, err := regexp.Compile(regex); err != nil {
+		return Redirect{}, ErrInvalidRedirect
+	}
+
+	return Redirect{
+		Regex:       regex,
+		Replacement: replacement,
+		Permanent:   permanent,
+	}, nil
+}
+
+// Parses a HTTP header name.
+func HeaderNameFrom(value string) (string, error) {
+	if !headerNameRegex.MatchString(value) {
+		return "", ErrInvalidHeaderName
+	}
+
+	return value, nil
+}
+
+func (r ProxyRules) Value() (driver.Value, error) { return storage.ValueJSON(r) }
+func (r *ProxyRules) Scan(value any) error        { return storage.ScanJSON(value, r) }
@@ -0,0 +1,64 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ProxyRules(t *testing.T) {
+	t.Run("should require at least one rule", func(t *testing.T) {
+		_, err := domain.NewProxyRules(false, nil, nil, nil)
+
+		testutil.ErrorIs(t, domain.ErrProxyRulesMissing, err)
+	})
+
+	t.Run("should validates redirects", func(t *testing.T) {
+		tests := []struct {
+			regex       string
+			replacement string
+			valid       bool
+		}{
+			{"", "https://example.com", false},
+			{"^https?://old.example.com/(.*)", "", false},
+			{"^https?://(.*", "https://example.com", false},
+			{"^https?://old.example.com/(.*)", "https://new.example.com/${1}", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.regex, func(t *testing.T) {
+				r, err := domain.RedirectFrom(test.regex, test.replacement, true)
+
+				if test.valid {
+					testutil.IsNil(t, err)
+					testutil.Equals(t, domain.Redirect{
+						Regex:       test.regex,
+						Replacement: test.replacement,
+						Permanent:   true,
+					}, r)
+				} else {
+					testutil.ErrorIs(t, domain.ErrInvalidRedirect, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validates header names", func(t *testing.T) {
+		_, err := domain.HeaderNameFrom("")
+		testutil.ErrorIs(t, domain.ErrInvalidHeaderName, err)
+
+		_, err = domain.HeaderNameFrom("X Frame: Options")
+		testutil.ErrorIs(t, domain.ErrInvalidHeaderName, err)
+
+		_, err = domain.HeaderNameFrom("X.Frame-Options")
+		testutil.ErrorIs(t, domain.ErrInvalidHeaderName, err)
+
+		_, err = domain.HeaderNameFrom("X-Frame`Options")
+		testutil.ErrorIs(t, domain.ErrInvalidHeaderName, err)
+
+		name, err := domain.HeaderNameFrom("X-Frame-Options")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "X-Frame-Options", name)
+	})
+}
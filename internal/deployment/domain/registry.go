@@ -5,6 +5,7 @@ import (
 	"time"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	shared "github.com/YuukanOO/seelf/pkg/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
@@ -13,6 +14,8 @@ import (
 	"github.com/YuukanOO/seelf/pkg/storage"
 )
 
+var ErrBuildRegistryNotFound = apperr.New("build_registry_not_found")
+
 type (
 	RegistryID string
 
@@ -208,6 +211,7 @@ func (r *Registry) ID() RegistryID                        { return r.id }
 func (r *Registry) Name() string                          { return r.name }
 func (r *Registry) Url() Url                              { return r.url }
 func (r *Registry) Credentials() monad.Maybe[Credentials] { return r.credentials }
+func (r *Registry) CreatedBy() auth.UserID                { return r.created.By() }
 
 func (r *Registry) apply(e event.Event) {
 	switch v := e.(type) {
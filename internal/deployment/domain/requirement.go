@@ -30,6 +30,16 @@ func (e EnvironmentConfigRequirement) Error() error {
 
 func (e EnvironmentConfigRequirement) Met() (EnvironmentConfig, error) { return e.config, e.Error() }
 
+// Keeps settings of the given config which are managed separately.
+func (e EnvironmentConfigRequirement) withSettingsOf(config EnvironmentConfig) EnvironmentConfigRequirement {
+	e.config.protection = config.protection
+	e.config.rules = config.rules
+	e.config.override = config.override
+	e.config.aliases = config.aliases
+	e.config.scale = config.scale
+	return e
+}
+
 type TargetUrlRequirement struct {
 	url    Url
 	unique bool
@@ -95,3 +105,51 @@ func (e ProviderConfigRequirement) Error() error {
 }
 
 func (e ProviderConfigRequirement) Met() (ProviderConfig, error) { return e.config, e.Error() }
+
+type DnsZoneNameRequirement struct {
+	name   string
+	unique bool
+}
+
+func NewDnsZoneNameRequirement(name string, unique bool) DnsZoneNameRequirement {
+	return DnsZoneNameRequirement{
+		name:   name,
+		unique: unique,
+	}
+}
+
+func (e DnsZoneNameRequirement) Error() error {
+	if !e.unique {
+		return ErrDnsZoneNameTaken
+	}
+
+	return nil
+}
+
+func (e DnsZoneNameRequirement) Met() (string, error) { return e.name, e.Error() }
+
+type UrlAliasesRequirement struct {
+	aliases   UrlAliases
+	available bool
+}
+
+func NewUrlAliasesRequirement(aliases UrlAliases, available bool) UrlAliasesRequirement {
+	return UrlAliasesRequirement{
+		aliases:   aliases,
+		available: available,
+	}
+}
+
+func (e UrlAliasesRequirement) Error() error {
+	if len(e.aliases) == 0 {
+		return ErrInvalidUrlAlias
+	}
+
+	if !e.available {
+		return ErrUrlAliasTaken
+	}
+
+	return nil
+}
+
+func (e UrlAliasesRequirement) Met() (UrlAliases, error) { return e.aliases, e.Error() }
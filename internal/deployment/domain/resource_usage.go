@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Resources consumed by an application service as reported by a provider. When
+	// a service runs multiple containers, their consumption is summed.
+	ServiceStats struct {
+		AppID       AppID
+		Environment Environment
+		Service     string
+		CPU         float64 // Percentage of a single CPU, could exceed 100 on multi-core hosts
+		Memory      int64   // In bytes
+		MemoryLimit int64   // In bytes
+		NetworkRx   int64   // Bytes received since the containers have started
+		NetworkTx   int64   // Bytes sent since the containers have started
+	}
+
+	ResourceUsageWriter interface {
+		// Store the given stats as collected at the given date.
+		WriteResourceUsage(context.Context, time.Time, []ServiceStats) error
+		// Remove stats collected before the given date.
+		PruneResourceUsage(context.Context, time.Time) error
+	}
+)
+
+// Sums the stats of containers belonging to the same application service.
+func MergeServiceStats(stats []ServiceStats) []ServiceStats {
+	type key struct {
+		app     AppID
+		env     Environment
+		service string
+	}
+
+	var (
+		result  []ServiceStats
+		indexes = make(map[key]int)
+	)
+
+	for _, s := range stats {
+		k := key{s.AppID, s.Environment, s.Service}
+		idx, exists := indexes[k]
+
+		if !exists {
+			indexes[k] = len(result)
+			result = append(result, s)
+			continue
+		}
+
+		result[idx].CPU += s.CPU
+		result[idx].Memory += s.Memory
+		result[idx].MemoryLimit += s.MemoryLimit
+		result[idx].NetworkRx += s.NetworkRx
+		result[idx].NetworkTx += s.NetworkTx
+	}
+
+	return result
+}
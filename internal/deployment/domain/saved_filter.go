@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrInvalidSavedFilterKind = apperr.New("invalid_saved_filter_kind")
+
+const (
+	SavedFilterApps        SavedFilterKind = "apps"
+	SavedFilterDeployments SavedFilterKind = "deployments"
+)
+
+type (
+	SavedFilterID   string
+	SavedFilterKind string // List of resources a saved filter applies to
+
+	// Criteria of a list query saved by a user to quickly find back apps or deployments.
+	SavedFilter struct {
+		event.Emitter
+
+		id       SavedFilterID
+		name     string
+		kind     SavedFilterKind
+		criteria SavedFilterCriteria
+		created  shared.Action[auth.UserID]
+	}
+
+	// Criteria which do not apply to the kind of the filter are ignored.
+	SavedFilterCriteria struct {
+		Labels      Labels `json:"labels"`
+		TeamID      string `json:"team_id,omitempty"`     // Apps only
+		Environment string `json:"environment,omitempty"` // Deployments only
+		Search      string `json:"search,omitempty"`      // Deployments only
+	}
+
+	SavedFiltersReader interface {
+		GetByID(context.Context, SavedFilterID) (SavedFilter, error)
+	}
+
+	SavedFiltersWriter interface {
+		Write(context.Context, ...*SavedFilter) error
+	}
+
+	SavedFilterCreated struct {
+		bus.Notification
+
+		ID       SavedFilterID
+		Name     string
+		Kind     SavedFilterKind
+		Criteria SavedFilterCriteria
+		Created  shared.Action[auth.UserID]
+	}
+
+	SavedFilterDeleted struct {
+		bus.Notification
+
+		ID SavedFilterID
+	}
+)
+
+func (SavedFilterCreated) Name_() string { return "deployment.event.saved_filter_created" }
+func (SavedFilterDeleted) Name_() string { return "deployment.event.saved_filter_deleted" }
+
+// Parses a saved filter kind.
+func SavedFilterKindFrom(value string) (SavedFilterKind, error) {
+	switch SavedFilterKind(value) {
+	case SavedFilterApps:
+		return SavedFilterApps, nil
+	case SavedFilterDeployments:
+		return SavedFilterDeployments, nil
+	default:
+		return "", ErrInvalidSavedFilterKind
+	}
+}
+
+// Saves a new filter for the given user.
+func NewSavedFilter(name string, kind SavedFilterKind, criteria SavedFilterCriteria, uid auth.UserID) (f SavedFilter) {
+	f.apply(SavedFilterCreated{
+		ID:       id.New[SavedFilterID](),
+		Name:     name,
+		Kind:     kind,
+		Criteria: criteria,
+		Created:  shared.NewAction(uid),
+	})
+
+	return f
+}
+
+// Recreates a saved filter from the persistent storage.
+func SavedFilterFrom(scanner storage.Scanner) (f SavedFilter, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&f.id,
+		&f.name,
+		&f.kind,
+		&f.criteria,
+		&createdAt,
+		&createdBy,
+	)
+
+	f.created = shared.ActionFrom(createdBy, createdAt)
+
+	return f, err
+}
+
+// Deletes the saved filter.
+func (f *SavedFilter) Delete() {
+	f.apply(SavedFilterDeleted{
+		ID: f.id,
+	})
+}
+
+func (f *SavedFilter) ID() SavedFilterID             { return f.id }
+func (f *SavedFilter) Kind() SavedFilterKind         { return f.kind }
+func (f *SavedFilter) Criteria() SavedFilterCriteria { return f.criteria }
+func (f *SavedFilter) CreatedBy() auth.UserID        { return f.created.By() }
+
+func (f *SavedFilter) apply(e event.Event) {
+	switch evt := e.(type) {
+	case SavedFilterCreated:
+		f.id = evt.ID
+		f.name = evt.Name
+		f.kind = evt.Kind
+		f.criteria = evt.Criteria
+		f.created = evt.Created
+	}
+
+	event.Store(f, e)
+}
+
+func (c SavedFilterCriteria) Value() (driver.Value, error) { return storage.ValueJSON(c) }
+func (c *SavedFilterCriteria) Scan(value any) error        { return storage.ScanJSON(value, c) }
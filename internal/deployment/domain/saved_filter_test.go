@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_SavedFilter(t *testing.T) {
+	t.Run("should validate the kind", func(t *testing.T) {
+		_, err := domain.SavedFilterKindFrom("targets")
+		testutil.ErrorIs(t, domain.ErrInvalidSavedFilterKind, err)
+
+		kind, err := domain.SavedFilterKindFrom("deployments")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.SavedFilterDeployments, kind)
+	})
+
+	t.Run("could be created", func(t *testing.T) {
+		criteria := domain.SavedFilterCriteria{Labels: domain.Labels{"team-a"}}
+		filter := domain.NewSavedFilter("my filter", domain.SavedFilterApps, criteria, "uid")
+
+		created := testutil.EventIs[domain.SavedFilterCreated](t, &filter, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "my filter", created.Name)
+		testutil.Equals(t, domain.SavedFilterApps, created.Kind)
+		testutil.DeepEquals(t, criteria, created.Criteria)
+		testutil.Equals(t, "uid", created.Created.By())
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		filter := domain.NewSavedFilter("my filter", domain.SavedFilterApps, domain.SavedFilterCriteria{}, "uid")
+
+		filter.Delete()
+
+		deleted := testutil.EventIs[domain.SavedFilterDeleted](t, &filter, 1)
+		testutil.Equals(t, filter.ID(), deleted.ID)
+	})
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"maps"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrInvalidReplicas = apperr.New("invalid_replicas")
+
+const maxReplicas = 32
+
+// Number of containers to run per service name, services not in there run a single one
+// unless their compose definition states otherwise.
+type ServicesScale map[string]uint
+
+// Validates the number of containers to run for a service, 0 stops it.
+func ReplicasFrom(value uint) (uint, error) {
+	if value > maxReplicas {
+		return 0, ErrInvalidReplicas
+	}
+
+	return value, nil
+}
+
+// Returns a copy of this scale with the given service scaled to the given replicas.
+func (s ServicesScale) with(service string, replicas uint) ServicesScale {
+	result := maps.Clone(s)
+
+	if result == nil {
+		result = make(ServicesScale, 1)
+	}
+
+	result[service] = replicas
+
+	return result
+}
+
+func (s ServicesScale) Value() (driver.Value, error) { return storage.ValueJSON(s) }
+func (s *ServicesScale) Scan(value any) error        { return storage.ScanJSON(value, s) }
@@ -0,0 +1,33 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ServicesScale(t *testing.T) {
+	t.Run("should limit the number of replicas", func(t *testing.T) {
+		_, err := domain.ReplicasFrom(33)
+		testutil.ErrorIs(t, domain.ErrInvalidReplicas, err)
+
+		replicas, err := domain.ReplicasFrom(0)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, uint(0), replicas)
+	})
+
+	t.Run("should be part of the deployment config", func(t *testing.T) {
+		config := domain.NewEnvironmentConfig("production-target")
+		config.HasServicesScale(domain.ServicesScale{"app": 2})
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(config, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true), "uid"))
+
+		snapshot := must.Panic(app.ConfigSnapshotFor(domain.Production))
+
+		testutil.Equals(t, uint(2), snapshot.ReplicasFor("app").MustGet())
+		testutil.IsFalse(t, snapshot.ReplicasFor("worker").HasValue())
+	})
+}
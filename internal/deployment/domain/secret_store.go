@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+var (
+	ErrSecretStoreNotConfigured = apperr.New("secret_store_not_configured")
+	ErrSecretNotFound           = apperr.New("secret_not_found")
+	ErrInvalidSecretReference   = apperr.New("invalid_secret_reference")
+)
+
+// Separates the store of a secret reference from the path of the secret in this store,
+// ie. vault://secret/data/myapp#password
+const secretReferenceSeparator = "://"
+
+type (
+	// Reference to a secret kept in an external store, resolved when deploying so its
+	// value is never stored by seelf.
+	SecretReference struct {
+		store string
+		path  string
+	}
+
+	// Resolves secrets references found in environment variables values.
+	SecretResolver interface {
+		// Returns true if the given store is handled by this resolver.
+		CanResolve(store string) bool
+		// Retrieve the current value of the referenced secret.
+		Resolve(context.Context, SecretReference) (string, error)
+	}
+)
+
+// Parses the given environment variable value as a secret reference, returning false
+// if it does not look like one.
+func SecretReferenceFrom(value string) (SecretReference, bool) {
+	store, path, found := strings.Cut(value, secretReferenceSeparator)
+
+	if !found || store == "" || path == "" || strings.ContainsAny(store, " /:") {
+		return SecretReference{}, false
+	}
+
+	return SecretReference{store, path}, true
+}
+
+func (r SecretReference) Store() string  { return r.store }
+func (r SecretReference) Path() string   { return r.path }
+func (r SecretReference) String() string { return r.store + secretReferenceSeparator + r.path }
+
+// Builds a copy of the variables where values referencing a store handled by the
+// resolver are replaced by the secret they reference. Other values, such as urls, are
+// kept as is. Resolved values are also returned so they could be redacted from logs.
+func (v EnvVars) Resolve(ctx context.Context, resolver SecretResolver) (EnvVars, []string, error) {
+	var (
+		result   = make(EnvVars, len(v))
+		resolved []string
+	)
+
+	for name, value := range v {
+		if ref, isRef := SecretReferenceFrom(value); isRef && resolver.CanResolve(ref.Store()) {
+			secret, err := resolver.Resolve(ctx, ref)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			value = secret
+			resolved = append(resolved, secret)
+		}
+
+		result[name] = value
+	}
+
+	return result, resolved, nil
+}
+
+// Builds a copy of the deployment where environment variables referencing external
+// secrets are resolved. The copy is meant to be given to a provider and never persisted.
+// Resolved values are also returned so they could be redacted from logs.
+func (d Deployment) WithResolvedSecrets(ctx context.Context, resolver SecretResolver) (Deployment, []string, error) {
+	env, isSet := d.config.vars.TryGet()
+
+	if !isSet {
+		return d, nil, nil
+	}
+
+	var (
+		result   = make(ServicesEnv, len(env))
+		resolved []string
+	)
+
+	for service, vars := range env {
+		serviceVars, secrets, err := vars.Resolve(ctx, resolver)
+
+		if err != nil {
+			return d, nil, err
+		}
+
+		result[service] = serviceVars
+		resolved = append(resolved, secrets...)
+	}
+
+	d.config.vars.Set(result)
+
+	return d, resolved, nil
+}
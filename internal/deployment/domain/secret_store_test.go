@@ -0,0 +1,79 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_SecretReference(t *testing.T) {
+	t.Run("should parse references to external secrets", func(t *testing.T) {
+		tests := []struct {
+			input string
+			store string
+			path  string
+			valid bool
+		}{
+			{"some value", "", "", false},
+			{"://secret", "", "", false},
+			{"vault://", "", "", false},
+			{"some value://secret", "", "", false},
+			{"vault://secret/data/app#password", "vault", "secret/data/app#password", true},
+			{"ssm:///app/db-password", "ssm", "/app/db-password", true},
+			{"postgres://user:pass@db:5432", "postgres", "user:pass@db:5432", true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				ref, isRef := domain.SecretReferenceFrom(test.input)
+
+				testutil.Equals(t, test.valid, isRef)
+				testutil.Equals(t, test.store, ref.Store())
+				testutil.Equals(t, test.path, ref.Path())
+			})
+		}
+	})
+
+	t.Run("should only resolve values referencing a store handled by the resolver", func(t *testing.T) {
+		vars := domain.EnvVars{
+			"DEBUG":        "false",
+			"DATABASE_URL": "postgres://db:5432",
+			"API_KEY":      "vault://secret/data/app#api_key",
+		}
+
+		resolved, secrets, err := vars.Resolve(context.Background(), vaultResolver{"secret/data/app#api_key": "a sensitive value"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"a sensitive value"}, secrets)
+		testutil.DeepEquals(t, domain.EnvVars{
+			"DEBUG":        "false",
+			"DATABASE_URL": "postgres://db:5432",
+			"API_KEY":      "a sensitive value",
+		}, resolved)
+		testutil.Equals(t, "vault://secret/data/app#api_key", vars["API_KEY"])
+	})
+
+	t.Run("should fail if a referenced secret could not be resolved", func(t *testing.T) {
+		vars := domain.EnvVars{"API_KEY": "vault://secret/data/app#missing"}
+
+		_, _, err := vars.Resolve(context.Background(), vaultResolver{})
+
+		testutil.ErrorIs(t, domain.ErrSecretNotFound, err)
+	})
+}
+
+type vaultResolver map[string]string
+
+func (vaultResolver) CanResolve(store string) bool { return store == "vault" }
+
+func (r vaultResolver) Resolve(_ context.Context, ref domain.SecretReference) (string, error) {
+	value, exists := r[ref.Path()]
+
+	if !exists {
+		return "", domain.ErrSecretNotFound
+	}
+
+	return value, nil
+}
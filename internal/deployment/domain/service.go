@@ -26,11 +26,13 @@ type (
 	EntrypointName string
 
 	Entrypoint struct {
-		name      EntrypointName // Unique name of the entrypoint
-		isCustom  bool           // Wether or not this entrypoint should be manually configured by the target
-		router    Router
-		subdomain monad.Maybe[string]
-		port      Port
+		name       EntrypointName // Unique name of the entrypoint
+		isCustom   bool           // Wether or not this entrypoint should be manually configured by the target
+		router     Router
+		subdomain  monad.Maybe[string]
+		pathPrefix monad.Maybe[string] // Only requests starting with this path are routed to the entrypoint
+		port       Port
+		published  monad.Maybe[Port] // Port reserved on the target for custom entrypoints, assigned by the target if not set
 	}
 
 	HttpEntrypointOptions struct {
@@ -38,6 +40,11 @@ type (
 		UseDefaultSubdomain bool
 		// True if this entrypoint is natively managed by the target and does not require specific port exposure.
 		Managed bool
+		// Subdomain to use instead of the generated one.
+		Subdomain monad.Maybe[string]
+		// Path prefix of requests to route to this entrypoint. Without an explicit subdomain,
+		// the default application one is used.
+		PathPrefix monad.Maybe[string]
 	}
 
 	// Custom types to hold Service array which implements the Scanner and Valuer
@@ -64,6 +71,15 @@ func ParsePort(raw string) (Port, error) {
 	return Port(v), nil
 }
 
+// Builds a port from a raw number, ensuring it is a valid TCP / UDP port.
+func PortFrom(value uint) (Port, error) {
+	if value == 0 || value > 65535 {
+		return 0, ErrInvalidPort
+	}
+
+	return Port(value), nil
+}
+
 func (p Port) String() string { return strconv.FormatUint(uint64(p), 10) }
 func (p Port) Uint32() uint32 { return uint32(p) }
 
@@ -93,24 +109,36 @@ func (s *Service) AddHttpEntrypoint(conf DeploymentConfig, port Port, options Ht
 	for _, entry := range s.entrypoints {
 		// Already have an HTTP endpoint on this service, copy the subdomain and add it as a custom one.
 		if entry.router == RouterHttp {
-			return s.addEntrypoint(RouterHttp, !options.Managed, port, entry.subdomain.Get(""))
+			return s.addEntrypoint(RouterHttp, !options.Managed, port, entry.subdomain, entry.pathPrefix, monad.None[Port]())
 		}
 	}
 
-	return s.addEntrypoint(RouterHttp, !options.Managed, port, conf.SubDomain(s.name, options.UseDefaultSubdomain))
+	subdomain := options.Subdomain
+
+	if !subdomain.HasValue() {
+		subdomain.Set(conf.SubDomain(s.name, options.UseDefaultSubdomain || options.PathPrefix.HasValue()))
+	}
+
+	return s.addEntrypoint(RouterHttp, !options.Managed, port, subdomain, options.PathPrefix, monad.None[Port]())
 }
 
-// Adds a custom TCP entrypoint.
-func (s *Service) AddTCPEntrypoint(port Port) Entrypoint {
-	return s.addEntrypoint(RouterTcp, true, port)
+// Adds a custom TCP entrypoint, optionally published on the given target port.
+func (s *Service) AddTCPEntrypoint(port Port, published ...Port) Entrypoint {
+	return s.addEntrypoint(RouterTcp, true, port, monad.None[string](), monad.None[string](), publishedPort(published))
 }
 
-// Adds a custom UDP entrypoint.
-func (s *Service) AddUDPEntrypoint(port Port) Entrypoint {
-	return s.addEntrypoint(RouterUdp, true, port)
+// Adds a custom UDP entrypoint, optionally published on the given target port.
+func (s *Service) AddUDPEntrypoint(port Port, published ...Port) Entrypoint {
+	return s.addEntrypoint(RouterUdp, true, port, monad.None[string](), monad.None[string](), publishedPort(published))
 }
 
-func (s *Service) addEntrypoint(router Router, isCustom bool, port Port, subdomain ...string) (e Entrypoint) {
+func (s *Service) addEntrypoint(
+	router Router,
+	isCustom bool,
+	port Port,
+	subdomain, pathPrefix monad.Maybe[string],
+	published monad.Maybe[Port],
+) (e Entrypoint) {
 	// Check if the entrypoint already exists
 	for _, entry := range s.entrypoints {
 		if entry.port == port && entry.router == router {
@@ -122,23 +150,32 @@ func (s *Service) addEntrypoint(router Router, isCustom bool, port Port, subdoma
 	e.isCustom = isCustom
 	e.router = router
 	e.port = port
-
-	if len(subdomain) > 0 {
-		e.subdomain.Set(subdomain[0])
-	}
+	e.subdomain = subdomain
+	e.pathPrefix = pathPrefix
+	e.published = published
 
 	s.entrypoints = append(s.entrypoints, e)
 
 	return e
 }
 
+func publishedPort(published []Port) (m monad.Maybe[Port]) {
+	if len(published) > 0 {
+		m.Set(published[0])
+	}
+
+	return m
+}
+
 func (s Service) Name() string  { return s.name }
 func (s Service) Image() string { return s.image }
 
-func (e Entrypoint) Name() EntrypointName           { return e.name }
-func (e Entrypoint) Router() Router                 { return e.router }
-func (e Entrypoint) Subdomain() monad.Maybe[string] { return e.subdomain }
-func (e Entrypoint) Port() Port                     { return e.port }
+func (e Entrypoint) Name() EntrypointName            { return e.name }
+func (e Entrypoint) Router() Router                  { return e.router }
+func (e Entrypoint) Subdomain() monad.Maybe[string]  { return e.subdomain }
+func (e Entrypoint) PathPrefix() monad.Maybe[string] { return e.pathPrefix }
+func (e Entrypoint) Port() Port                      { return e.port }
+func (e Entrypoint) Published() monad.Maybe[Port]    { return e.published }
 
 // Check if this entrypoint should be manually configured by the target.
 // This is needed because default HTTP entrypoints are mostly managed automatically by the proxy
@@ -182,11 +219,13 @@ func isNotCustom(entrypoint Entrypoint) bool {
 // Types needed to marshal an unexposed Service data.
 type (
 	marshalledEntrypoint struct {
-		Name      string              `json:"name"`
-		IsCustom  bool                `json:"is_custom"`
-		Router    Router              `json:"router"`
-		Subdomain monad.Maybe[string] `json:"subdomain"`
-		Port      Port                `json:"port"`
+		Name       string              `json:"name"`
+		IsCustom   bool                `json:"is_custom"`
+		Router     Router              `json:"router"`
+		Subdomain  monad.Maybe[string] `json:"subdomain"`
+		PathPrefix monad.Maybe[string] `json:"path_prefix"`
+		Port       Port                `json:"port"`
+		Published  monad.Maybe[Port]   `json:"published"`
 	}
 
 	marshalledService struct {
@@ -207,11 +246,13 @@ func (s Service) MarshalJSON() ([]byte, error) {
 
 	for i, entry := range s.entrypoints {
 		serv.Entrypoints[i] = marshalledEntrypoint{
-			Name:      string(entry.name),
-			IsCustom:  entry.isCustom,
-			Router:    entry.router,
-			Subdomain: entry.subdomain,
-			Port:      entry.port,
+			Name:       string(entry.name),
+			IsCustom:   entry.isCustom,
+			Router:     entry.router,
+			Subdomain:  entry.subdomain,
+			PathPrefix: entry.pathPrefix,
+			Port:       entry.port,
+			Published:  entry.published,
 		}
 	}
 
@@ -232,11 +273,13 @@ func (s *Service) UnmarshalJSON(b []byte) error {
 
 	for i, entry := range m.Entrypoints {
 		s.entrypoints[i] = Entrypoint{
-			name:      EntrypointName(entry.Name),
-			isCustom:  entry.IsCustom,
-			router:    entry.Router,
-			subdomain: entry.Subdomain,
-			port:      entry.Port,
+			name:       EntrypointName(entry.Name),
+			isCustom:   entry.IsCustom,
+			router:     entry.Router,
+			subdomain:  entry.Subdomain,
+			pathPrefix: entry.PathPrefix,
+			port:       entry.Port,
+			published:  entry.Published,
 		}
 	}
 
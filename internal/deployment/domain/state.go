@@ -10,6 +10,7 @@ import (
 var (
 	ErrNotInPendingState = apperr.New("not_in_pending_state")
 	ErrNotInRunningState = apperr.New("not_in_running_state")
+	ErrNotInFailedState  = apperr.New("not_in_failed_state")
 )
 
 const (
@@ -28,6 +29,7 @@ type (
 	DeploymentState struct {
 		status     DeploymentStatus
 		errcode    monad.Maybe[string]
+		reason     monad.Maybe[DeploymentFailureReason]
 		services   monad.Maybe[Services]
 		startedAt  monad.Maybe[time.Time]
 		finishedAt monad.Maybe[time.Time]
@@ -52,6 +54,7 @@ func (s *DeploymentState) Failed(err error) error {
 
 	s.status = DeploymentStatusFailed
 	s.errcode.Set(err.Error())
+	s.reason.Set(DeploymentFailureReasonOf(err))
 	s.finishedAt.Set(time.Now().UTC())
 
 	return nil
@@ -69,11 +72,23 @@ func (s *DeploymentState) Succeeded(services Services) error {
 	return nil
 }
 
-func (s DeploymentState) Status() DeploymentStatus           { return s.status }
-func (s DeploymentState) ErrCode() monad.Maybe[string]       { return s.errcode }
-func (s DeploymentState) Services() monad.Maybe[Services]    { return s.services }
-func (s DeploymentState) StartedAt() monad.Maybe[time.Time]  { return s.startedAt }
-func (s DeploymentState) FinishedAt() monad.Maybe[time.Time] { return s.finishedAt }
+// Resets a failed deployment to the pending state.
+func (s *DeploymentState) Retried() error {
+	if s.status != DeploymentStatusFailed {
+		return ErrNotInFailedState
+	}
+
+	*s = DeploymentState{}
+
+	return nil
+}
+
+func (s DeploymentState) Status() DeploymentStatus                            { return s.status }
+func (s DeploymentState) ErrCode() monad.Maybe[string]                        { return s.errcode }
+func (s DeploymentState) FailureReason() monad.Maybe[DeploymentFailureReason] { return s.reason }
+func (s DeploymentState) Services() monad.Maybe[Services]                     { return s.services }
+func (s DeploymentState) StartedAt() monad.Maybe[time.Time]                   { return s.startedAt }
+func (s DeploymentState) FinishedAt() monad.Maybe[time.Time]                  { return s.finishedAt }
 
 const (
 	TargetStatusConfiguring TargetStatus = iota
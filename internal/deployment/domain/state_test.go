@@ -47,10 +47,21 @@ func Test_DeploymentState(t *testing.T) {
 		testutil.IsNil(t, err)
 		testutil.Equals(t, domain.DeploymentStatusFailed, state.Status())
 		testutil.Equals(t, "some error", state.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailureUnknown, state.FailureReason().MustGet())
 		testutil.IsTrue(t, state.StartedAt().HasValue())
 		testutil.IsTrue(t, state.FinishedAt().HasValue())
 	})
 
+	t.Run("should keep the reason of a classified failure", func(t *testing.T) {
+		var state domain.DeploymentState
+
+		testutil.IsNil(t, state.Started())
+		testutil.IsNil(t, state.Failed(domain.NewDeploymentFailure(domain.DeploymentFailurePortConflict, errors.New("compose_failed"))))
+
+		testutil.Equals(t, "compose_failed", state.ErrCode().MustGet())
+		testutil.Equals(t, domain.DeploymentFailurePortConflict, state.FailureReason().MustGet())
+	})
+
 	t.Run("could succeed", func(t *testing.T) {
 		var (
 			state domain.DeploymentState
@@ -87,6 +98,21 @@ func Test_DeploymentState(t *testing.T) {
 		testutil.ErrorIs(t, domain.ErrNotInPendingState, err)
 	})
 
+	t.Run("could be retried once failed", func(t *testing.T) {
+		var state domain.DeploymentState
+
+		testutil.ErrorIs(t, domain.ErrNotInFailedState, state.Retried())
+		testutil.IsNil(t, state.Started())
+		testutil.IsNil(t, state.Failed(errors.New("some error")))
+
+		testutil.IsNil(t, state.Retried())
+		testutil.Equals(t, domain.DeploymentStatusPending, state.Status())
+		testutil.IsFalse(t, state.ErrCode().HasValue())
+		testutil.IsFalse(t, state.FailureReason().HasValue())
+		testutil.IsFalse(t, state.StartedAt().HasValue())
+		testutil.IsFalse(t, state.FinishedAt().HasValue())
+	})
+
 	t.Run("should err if trying to fail but not in runing state", func(t *testing.T) {
 		var state domain.DeploymentState
 
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidStaticSiteService   = apperr.New("invalid_static_site_service")
+	ErrInvalidStaticSiteDirectory = apperr.New("invalid_static_site_directory")
+
+	staticSiteServiceRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+)
+
+// Marks an application as a static site. Its assets are built by a compose service and
+// served by a web server shared by every static site of a target instead of running a
+// dedicated container per application.
+type StaticSite struct {
+	Service   string `json:"service"`   // Compose service building the assets
+	Directory string `json:"directory"` // Absolute path of the build output directory in the image of the service
+}
+
+// Builds a static site configuration from a compose service name and the absolute
+// path of its build output directory.
+func StaticSiteFrom(service, directory string) (StaticSite, error) {
+	if !staticSiteServiceRegex.MatchString(service) {
+		return StaticSite{}, ErrInvalidStaticSiteService
+	}
+
+	directory = strings.TrimSpace(directory)
+
+	if !path.IsAbs(directory) || strings.Contains(directory, "..") {
+		return StaticSite{}, ErrInvalidStaticSiteDirectory
+	}
+
+	directory = path.Clean(directory)
+
+	if directory == "/" {
+		return StaticSite{}, ErrInvalidStaticSiteDirectory
+	}
+
+	return StaticSite{
+		Service:   service,
+		Directory: directory,
+	}, nil
+}
+
+func (s StaticSite) Value() (driver.Value, error) { return storage.ValueJSON(s) }
+func (s *StaticSite) Scan(value any) error        { return storage.ScanJSON(value, s) }
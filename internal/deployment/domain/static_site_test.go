@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_StaticSite(t *testing.T) {
+	t.Run("should require a valid compose service name", func(t *testing.T) {
+		for _, service := range []string{"", "-web", "my web"} {
+			_, err := domain.StaticSiteFrom(service, "/app/dist")
+			testutil.ErrorIs(t, domain.ErrInvalidStaticSiteService, err)
+		}
+	})
+
+	t.Run("should require an absolute build output directory", func(t *testing.T) {
+		for _, directory := range []string{"", "dist", "/", "/app/../etc"} {
+			_, err := domain.StaticSiteFrom("web", directory)
+			testutil.ErrorIs(t, domain.ErrInvalidStaticSiteDirectory, err)
+		}
+	})
+
+	t.Run("should clean the build output directory", func(t *testing.T) {
+		site, err := domain.StaticSiteFrom("web", " /app/dist/ ")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, domain.StaticSite{Service: "web", Directory: "/app/dist"}, site)
+	})
+}
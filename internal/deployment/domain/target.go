@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"database/sql/driver"
+	"maps"
+	"slices"
 	"time"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
@@ -24,6 +26,8 @@ var (
 	ErrTargetProviderUpdateNotPermitted = apperr.New("target_provider_update_not_permitted")
 	ErrTargetCleanupNeeded              = apperr.New("target_cleanup_needed")
 	ErrTargetCleanupRequested           = apperr.New("target_cleanup_requested")
+	ErrProxyUpToDate                    = apperr.New("proxy_up_to_date")
+	ErrPortAlreadyReserved              = apperr.New("port_already_reserved")
 )
 
 const (
@@ -40,6 +44,7 @@ type (
 	// Represents a target where application could be deployed.
 	Target struct {
 		event.Emitter
+		storage.Versioned
 
 		id                TargetID
 		name              string
@@ -47,7 +52,12 @@ type (
 		provider          ProviderConfig
 		state             TargetState
 		customEntrypoints TargetEntrypoints
+		vars              EnvVars               // Shared variables given to every app deployed on this target
+		platform          monad.Maybe[Platform] // Platform reported by the target engine, known once configured
+		proxyVersion      monad.Maybe[string]   // Version of the proxy running on the target, known once configured
+		labels            Labels                // Used to select a target for new apps
 		cleanupRequested  monad.Maybe[shared.Action[auth.UserID]]
+		lastGC            monad.Maybe[GarbageCollectionReport]
 		created           shared.Action[auth.UserID]
 	}
 
@@ -56,6 +66,8 @@ type (
 		CheckConfigAvailability(context.Context, ProviderConfig, ...TargetID) (ProviderConfigRequirement, error)
 		GetByID(context.Context, TargetID) (Target, error)
 		GetLocalTarget(context.Context) (Target, error)
+		GetReady(context.Context) ([]Target, error)               // Retrieve targets ready to serve deployments
+		GetCandidates(context.Context) ([]TargetCandidate, error) // Retrieve targets which could host new apps
 	}
 
 	TargetsWriter interface {
@@ -109,6 +121,54 @@ type (
 		Entrypoints TargetEntrypoints
 	}
 
+	TargetVarsChanged struct {
+		bus.Notification
+
+		ID   TargetID
+		Vars EnvVars
+	}
+
+	TargetLabelsChanged struct {
+		bus.Notification
+
+		ID     TargetID
+		Labels Labels
+	}
+
+	TargetPlatformChanged struct {
+		bus.Notification
+
+		ID       TargetID
+		Platform Platform
+	}
+
+	TargetProxyVersionChanged struct {
+		bus.Notification
+
+		ID      TargetID
+		Version string
+	}
+
+	TargetProxyUpgradeRequested struct {
+		bus.Notification
+
+		ID      TargetID
+		Version string
+	}
+
+	TargetGarbageCollectionRequested struct {
+		bus.Notification
+
+		ID TargetID
+	}
+
+	TargetGarbageCollected struct {
+		bus.Notification
+
+		ID     TargetID
+		Report GarbageCollectionReport
+	}
+
 	TargetCleanupRequested struct {
 		bus.Notification
 
@@ -129,8 +189,23 @@ func (TargetRenamed) Name_() string            { return "deployment.event.target
 func (TargetUrlChanged) Name_() string         { return "deployment.event.target_url_changed" }
 func (TargetProviderChanged) Name_() string    { return "deployment.event.target_provider_changed" }
 func (TargetEntrypointsChanged) Name_() string { return "deployment.event.target_entrypoints_changed" }
-func (TargetCleanupRequested) Name_() string   { return "deployment.event.target_cleanup_requested" }
-func (TargetDeleted) Name_() string            { return "deployment.event.target_deleted" }
+func (TargetVarsChanged) Name_() string        { return "deployment.event.target_vars_changed" }
+func (TargetLabelsChanged) Name_() string      { return "deployment.event.target_labels_changed" }
+func (TargetPlatformChanged) Name_() string    { return "deployment.event.target_platform_changed" }
+func (TargetProxyVersionChanged) Name_() string {
+	return "deployment.event.target_proxy_version_changed"
+}
+func (TargetProxyUpgradeRequested) Name_() string {
+	return "deployment.event.target_proxy_upgrade_requested"
+}
+func (TargetGarbageCollectionRequested) Name_() string {
+	return "deployment.event.target_garbage_collection_requested"
+}
+func (TargetGarbageCollected) Name_() string {
+	return "deployment.event.target_garbage_collected"
+}
+func (TargetCleanupRequested) Name_() string { return "deployment.event.target_cleanup_requested" }
+func (TargetDeleted) Name_() string          { return "deployment.event.target_deleted" }
 
 func (e TargetStateChanged) WentToConfiguringState() bool {
 	return e.State.status == TargetStatusConfiguring
@@ -189,10 +264,16 @@ func TargetFrom(scanner storage.Scanner) (t Target, err error) {
 		&t.state.errcode,
 		&t.state.lastReadyVersion,
 		&t.customEntrypoints,
+		&t.vars,
+		&t.platform,
+		&t.proxyVersion,
+		&t.lastGC,
+		&t.labels,
 		&deleteRequestedAt,
 		&deleteRequestedBy,
 		&createdAt,
 		&createdBy,
+		&t.Versioned,
 	)
 
 	if err != nil {
@@ -289,6 +370,138 @@ func (t *Target) HasProvider(providerRequirement ProviderConfigRequirement) erro
 	return nil
 }
 
+// Update variables shared by every app deployed on this target. Masked values keep
+// the existing variable value, the same way as when importing app variables.
+func (t *Target) HasSharedVariables(vars EnvVars) error {
+	if t.cleanupRequested.HasValue() {
+		return ErrTargetCleanupRequested
+	}
+
+	updated := make(EnvVars, len(vars))
+
+	for name, value := range vars {
+		if value != MaskedEnvVarValue {
+			updated[name] = value
+		} else if current, exists := t.vars[name]; exists {
+			updated[name] = current
+		}
+	}
+
+	if maps.Equal(t.vars, updated) {
+		return nil
+	}
+
+	t.apply(TargetVarsChanged{
+		ID:   t.id,
+		Vars: updated,
+	})
+
+	return nil
+}
+
+// Replaces labels of this target.
+func (t *Target) HasLabels(labels Labels) error {
+	if t.cleanupRequested.HasValue() {
+		return ErrTargetCleanupRequested
+	}
+
+	if slices.Equal(t.labels, labels) {
+		return nil
+	}
+
+	t.apply(TargetLabelsChanged{
+		ID:     t.id,
+		Labels: labels,
+	})
+
+	return nil
+}
+
+// Records the platform reported by the target engine.
+func (t *Target) RunsOn(platform Platform) {
+	if current, isSet := t.platform.TryGet(); isSet && current == platform {
+		return
+	}
+
+	t.apply(TargetPlatformChanged{
+		ID:       t.id,
+		Platform: platform,
+	})
+}
+
+// Check if the target platform, when known, is part of the given supported platforms.
+func (t *Target) CheckPlatform(platforms Platforms) error {
+	if platform, isSet := t.platform.TryGet(); isSet && !platforms.Supports(platform) {
+		return ErrPlatformNotSupported
+	}
+
+	return nil
+}
+
+// Check if the given ports can be published on the target for the given application
+// environment. A port can only be reserved once, either in the list itself or by
+// another application environment deployed on this target.
+func (t *Target) CheckPortsAvailability(app AppID, env Environment, ports []Port) error {
+	for i, port := range ports {
+		if slices.Contains(ports[:i], port) {
+			return ErrPortAlreadyReserved
+		}
+	}
+
+	for entryApp, appEntries := range t.customEntrypoints {
+		for entryEnv, envEntries := range appEntries {
+			if entryApp == app && entryEnv == env {
+				continue
+			}
+
+			for _, assigned := range envEntries {
+				if port, isSet := assigned.TryGet(); isSet && slices.Contains(ports, port) {
+					return ErrPortAlreadyReserved
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Records the version of the proxy running on the target.
+func (t *Target) ProxyRunning(version string) {
+	if t.IsProxyUpToDate(version) {
+		return
+	}
+
+	t.apply(TargetProxyVersionChanged{
+		ID:      t.id,
+		Version: version,
+	})
+}
+
+// Returns true if the proxy running on the target is known to be in the given version.
+func (t *Target) IsProxyUpToDate(version string) bool {
+	current, isSet := t.proxyVersion.TryGet()
+	return isSet && current == version
+}
+
+// Request the upgrade of the proxy running on the target to the given version.
+// The target must be available since the proxy will be swapped.
+func (t *Target) RequestProxyUpgrade(version string) error {
+	if err := t.CheckAvailability(); err != nil {
+		return err
+	}
+
+	if t.IsProxyUpToDate(version) {
+		return ErrProxyUpToDate
+	}
+
+	t.apply(TargetProxyUpgradeRequested{
+		ID:      t.id,
+		Version: version,
+	})
+
+	return nil
+}
+
 // Check the target availability and returns an appropriate error.
 func (t *Target) CheckAvailability() error {
 	if t.state.status == TargetStatusConfiguring {
@@ -447,6 +660,23 @@ func (t *Target) AppCleanupStrategy(
 	}
 }
 
+// Determine how an add-on service should be removed from this target.
+func (t *Target) AddonCleanupStrategy() (CleanupStrategy, error) {
+	// Target will be deleted, skip the cleanup right away
+	if t.cleanupRequested.HasValue() {
+		return CleanupStrategySkip, nil
+	}
+
+	switch t.state.status {
+	case TargetStatusConfiguring:
+		return CleanupStrategyDefault, ErrTargetConfigurationInProgress
+	case TargetStatusReady:
+		return CleanupStrategyDefault, nil
+	default:
+		return CleanupStrategyDefault, ErrTargetConfigurationFailed
+	}
+}
+
 // Deletes the target.
 func (t *Target) Delete(cleanedUp bool) error {
 	if !t.cleanupRequested.HasValue() || !cleanedUp {
@@ -464,7 +694,17 @@ func (t *Target) ID() TargetID                         { return t.id }
 func (t *Target) Url() Url                             { return t.url }
 func (t *Target) Provider() ProviderConfig             { return t.provider }
 func (t *Target) CustomEntrypoints() TargetEntrypoints { return t.customEntrypoints } // FIXME: Should we return a copy?
+func (t *Target) Vars() EnvVars                        { return t.vars }
+func (t *Target) Platform() monad.Maybe[Platform]      { return t.platform }
+func (t *Target) ProxyVersion() monad.Maybe[string]    { return t.proxyVersion }
+func (t *Target) Labels() Labels                       { return t.labels }
 func (t *Target) CurrentVersion() time.Time            { return t.state.version }
+func (t *Target) CreatedBy() auth.UserID               { return t.created.By() }
+
+// Resources on which a role could be granted to access this target.
+func (t *Target) Resources() []auth.Resource {
+	return []auth.Resource{auth.NewResource(auth.ResourceTarget, string(t.id))}
+}
 
 // Returns true if the given configuration version is different from the current one.
 func (t *Target) IsOutdated(version time.Time) bool {
@@ -507,6 +747,16 @@ func (t *Target) apply(e event.Event) {
 		t.provider = evt.Provider
 	case TargetEntrypointsChanged:
 		t.customEntrypoints = evt.Entrypoints
+	case TargetVarsChanged:
+		t.vars = evt.Vars
+	case TargetLabelsChanged:
+		t.labels = evt.Labels
+	case TargetPlatformChanged:
+		t.platform.Set(evt.Platform)
+	case TargetProxyVersionChanged:
+		t.proxyVersion.Set(evt.Version)
+	case TargetGarbageCollected:
+		t.lastGC.Set(evt.Report)
 	case TargetCleanupRequested:
 		t.cleanupRequested.Set(evt.Requested)
 	case TargetStateChanged:
@@ -553,14 +803,16 @@ func (e TargetEntrypoints) merge(app AppID, env Environment, entrypoints []Entry
 		delete(envEntries, existing)
 	}
 
-	// Add new entries but do not overwrite existing ones
+	// Add new entries but do not overwrite existing ones unless a published port is requested
 	for _, entrypoint := range entrypoints {
-		if _, found := envEntries[entrypoint.name]; found {
+		existing, found := envEntries[entrypoint.name]
+
+		if found && (!entrypoint.published.HasValue() || existing == entrypoint.published) {
 			continue
 		}
 
 		updated = true
-		envEntries[entrypoint.name] = monad.None[Port]()
+		envEntries[entrypoint.name] = entrypoint.published
 	}
 
 	// Clean useless entries
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Resources of a target as reported by a provider, used to find where there is
+	// room to place a new application.
+	TargetCapacity struct {
+		RunningContainers int   // Containers of applications currently running
+		ImagesSize        int64 // Disk space used by images, in bytes
+		DiskFree          int64 // Free space of the disk holding images and volumes, in bytes
+		DiskTotal         int64 // Size of the disk holding images and volumes, in bytes
+	}
+
+	TargetCapacityWriter interface {
+		// Store the capacity of a target as collected at the given date, replacing
+		// the previous one.
+		WriteTargetCapacity(context.Context, TargetID, time.Time, TargetCapacity) error
+	}
+)
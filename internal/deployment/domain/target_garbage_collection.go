@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Thresholds applied when collecting garbage on targets.
+	GarbageCollectionOptions struct {
+		MinAge        time.Duration // Only resources created at least this long ago are removed
+		DiskThreshold int           // Percentage of used disk space from which garbage is collected, 0 to always collect
+	}
+
+	// Resources removed from a target by a garbage collection.
+	GarbageCollectionReport struct {
+		Images         int       `json:"images"`          // Dangling images removed
+		Containers     int       `json:"containers"`      // Stopped containers of applications removed
+		Networks       int       `json:"networks"`        // Unused networks removed
+		SpaceReclaimed int64     `json:"space_reclaimed"` // Disk space freed, in bytes
+		CollectedAt    time.Time `json:"collected_at"`
+	}
+)
+
+// Checks if garbage should be collected on a target given its current capacity. When
+// the disk size is unknown, garbage is always collected.
+func (o GarbageCollectionOptions) ShouldCollect(capacity TargetCapacity) bool {
+	if o.DiskThreshold <= 0 || capacity.DiskTotal <= 0 {
+		return true
+	}
+
+	used := (capacity.DiskTotal - capacity.DiskFree) * 100 / capacity.DiskTotal
+
+	return used >= int64(o.DiskThreshold)
+}
+
+func (r GarbageCollectionReport) Value() (driver.Value, error) { return storage.ValueJSON(r) }
+func (r *GarbageCollectionReport) Scan(value any) error        { return storage.ScanJSON(value, r) }
+
+// Request a garbage collection on the target to remove resources which are not used
+// anymore. The target must be available.
+func (t *Target) RequestGarbageCollection() error {
+	if err := t.CheckAvailability(); err != nil {
+		return err
+	}
+
+	t.apply(TargetGarbageCollectionRequested{
+		ID: t.id,
+	})
+
+	return nil
+}
+
+// Records the result of a garbage collection made on the target.
+func (t *Target) GarbageCollected(report GarbageCollectionReport) {
+	t.apply(TargetGarbageCollected{
+		ID:     t.id,
+		Report: report,
+	})
+}
+
+func (t *Target) LastGarbageCollection() monad.Maybe[GarbageCollectionReport] {
+	return t.lastGC
+}
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_TargetGarbageCollection(t *testing.T) {
+	newTarget := func() domain.Target {
+		return must.Panic(domain.NewTarget("my-target",
+			domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom("http://localhost")), true),
+			domain.NewProviderConfigRequirement(nil, true), "uid"))
+	}
+
+	t.Run("should collect garbage only when the disk usage reaches the threshold", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			threshold int
+			capacity  domain.TargetCapacity
+			expected  bool
+		}{
+			{"no threshold", 0, domain.TargetCapacity{DiskTotal: 100, DiskFree: 90}, true},
+			{"unknown disk size", 80, domain.TargetCapacity{}, true},
+			{"below the threshold", 80, domain.TargetCapacity{DiskTotal: 100, DiskFree: 30}, false},
+			{"reaching the threshold", 80, domain.TargetCapacity{DiskTotal: 100, DiskFree: 20}, true},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				options := domain.GarbageCollectionOptions{DiskThreshold: test.threshold}
+				testutil.Equals(t, test.expected, options.ShouldCollect(test.capacity))
+			})
+		}
+	})
+
+	t.Run("should require the target to be available to request a garbage collection", func(t *testing.T) {
+		target := newTarget()
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, target.RequestGarbageCollection())
+
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		testutil.IsNil(t, target.RequestGarbageCollection())
+		requested := testutil.EventIs[domain.TargetGarbageCollectionRequested](t, &target, 2)
+		testutil.Equals(t, target.ID(), requested.ID)
+	})
+
+	t.Run("should record the last garbage collection", func(t *testing.T) {
+		target := newTarget()
+		report := domain.GarbageCollectionReport{
+			Images:         2,
+			Containers:     1,
+			SpaceReclaimed: 4096,
+			CollectedAt:    time.Now().UTC(),
+		}
+
+		testutil.IsFalse(t, target.LastGarbageCollection().HasValue())
+
+		target.GarbageCollected(report)
+
+		collected := testutil.EventIs[domain.TargetGarbageCollected](t, &target, 1)
+		testutil.Equals(t, target.ID(), collected.ID)
+		testutil.Equals(t, report, collected.Report)
+		testutil.Equals(t, report, target.LastGarbageCollection().MustGet())
+	})
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var (
+	ErrInvalidTargetSelectionStrategy = apperr.New("invalid_target_selection_strategy")
+	ErrNoTargetAvailable              = apperr.New("no_target_available")
+)
+
+const (
+	TargetSelectionLeastLoaded TargetSelectionStrategy = "least_loaded" // Target hosting the fewest app environments
+	TargetSelectionRoundRobin  TargetSelectionStrategy = "round_robin"  // Target which has not been chosen for the longest time
+)
+
+type (
+	TargetSelectionStrategy string
+
+	// Rules used to choose the target of an app environment when none is given.
+	TargetSelection struct {
+		labels   Labels // Only targets with all those labels are considered
+		strategy TargetSelectionStrategy
+	}
+
+	// Target ready to host new apps, with what is needed to select it.
+	TargetCandidate struct {
+		ID             TargetID
+		Labels         Labels
+		Apps           int                    // Number of app environments using this target
+		LastAssignedAt monad.Maybe[time.Time] // When an app environment has been created on this target for the last time
+	}
+)
+
+// Builds target selection rules. Without strategy, the least loaded target is chosen.
+func TargetSelectionFrom(labels Labels, strategy string) (TargetSelection, error) {
+	s := TargetSelection{
+		labels:   labels,
+		strategy: TargetSelectionStrategy(strategy),
+	}
+
+	switch s.strategy {
+	case "":
+		s.strategy = TargetSelectionLeastLoaded
+	case TargetSelectionLeastLoaded, TargetSelectionRoundRobin:
+	default:
+		return TargetSelection{}, ErrInvalidTargetSelectionStrategy
+	}
+
+	return s, nil
+}
+
+// Choose a target among the given candidates. Ties are broken by choosing the target
+// which has not been assigned an app for the longest time and then by its ID, so the
+// selection is always the same for the same candidates.
+func (s TargetSelection) Select(candidates []TargetCandidate) (TargetID, error) {
+	var matching []TargetCandidate
+
+	for _, candidate := range candidates {
+		if candidate.Labels.Contains(s.labels) {
+			matching = append(matching, candidate)
+		}
+	}
+
+	if len(matching) == 0 {
+		return "", ErrNoTargetAvailable
+	}
+
+	chosen := slices.MinFunc(matching, func(a, b TargetCandidate) int {
+		if s.strategy == TargetSelectionLeastLoaded {
+			if c := cmp.Compare(a.Apps, b.Apps); c != 0 {
+				return c
+			}
+		}
+
+		if c := compareAssignment(a.LastAssignedAt, b.LastAssignedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return chosen.ID, nil
+}
+
+func (s TargetSelection) Labels() Labels                    { return s.labels }
+func (s TargetSelection) Strategy() TargetSelectionStrategy { return s.strategy }
+
+// Targets which have never been assigned an app come first.
+func compareAssignment(a, b monad.Maybe[time.Time]) int {
+	aAt, aIsSet := a.TryGet()
+	bAt, bIsSet := b.TryGet()
+
+	switch {
+	case !aIsSet && !bIsSet:
+		return 0
+	case !aIsSet:
+		return -1
+	case !bIsSet:
+		return 1
+	default:
+		return aAt.Compare(bAt)
+	}
+}
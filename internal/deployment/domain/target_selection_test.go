@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_TargetSelection(t *testing.T) {
+	now := time.Now()
+	candidates := []domain.TargetCandidate{
+		{ID: "target-a", Labels: domain.Labels{"region=eu"}, Apps: 3, LastAssignedAt: monad.Value(now)},
+		{ID: "target-b", Labels: domain.Labels{"region=eu", "ssd"}, Apps: 1, LastAssignedAt: monad.Value(now.Add(-time.Hour))},
+		{ID: "target-c", Labels: domain.Labels{"region=us"}},
+		{ID: "target-d", Labels: domain.Labels{"region=eu"}, Apps: 1, LastAssignedAt: monad.Value(now.Add(-2 * time.Hour))},
+	}
+
+	t.Run("should require a valid strategy", func(t *testing.T) {
+		_, err := domain.TargetSelectionFrom(nil, "random")
+
+		testutil.ErrorIs(t, domain.ErrInvalidTargetSelectionStrategy, err)
+	})
+
+	t.Run("should default to the least loaded strategy", func(t *testing.T) {
+		selection := must.Panic(domain.TargetSelectionFrom(nil, ""))
+
+		testutil.Equals(t, domain.TargetSelectionLeastLoaded, selection.Strategy())
+	})
+
+	t.Run("should fail if no candidate has the expected labels", func(t *testing.T) {
+		selection := must.Panic(domain.TargetSelectionFrom(domain.Labels{"region=asia"}, ""))
+
+		_, err := selection.Select(candidates)
+
+		testutil.ErrorIs(t, domain.ErrNoTargetAvailable, err)
+	})
+
+	t.Run("should choose the least loaded target with the expected labels", func(t *testing.T) {
+		selection := must.Panic(domain.TargetSelectionFrom(domain.Labels{"region=eu"}, string(domain.TargetSelectionLeastLoaded)))
+
+		id, err := selection.Select(candidates)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "target-d", id)
+	})
+
+	t.Run("should choose the target which has not been assigned an app for the longest time", func(t *testing.T) {
+		selection := must.Panic(domain.TargetSelectionFrom(domain.Labels{"region=eu"}, string(domain.TargetSelectionRoundRobin)))
+
+		id, err := selection.Select(candidates)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "target-d", id)
+
+		selection = must.Panic(domain.TargetSelectionFrom(nil, string(domain.TargetSelectionRoundRobin)))
+
+		id, err = selection.Select(candidates)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "target-c", id)
+	})
+}
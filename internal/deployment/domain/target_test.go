@@ -76,6 +76,50 @@ func Test_Target(t *testing.T) {
 		testutil.ErrorIs(t, domain.ErrTargetCleanupRequested, target.Rename("new-name"))
 	})
 
+	t.Run("could have shared variables and raise the event only if different", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		err := target.HasSharedVariables(domain.EnvVars{"SMTP_HOST": "smtp.example.com", "SMTP_PASSWORD": "secret"})
+
+		testutil.IsNil(t, err)
+		evt := testutil.EventIs[domain.TargetVarsChanged](t, &target, 1)
+		testutil.DeepEquals(t, domain.EnvVars{"SMTP_HOST": "smtp.example.com", "SMTP_PASSWORD": "secret"}, evt.Vars)
+
+		testutil.IsNil(t, target.HasSharedVariables(domain.EnvVars{"SMTP_HOST": "smtp.example.com", "SMTP_PASSWORD": domain.MaskedEnvVarValue}))
+		testutil.HasNEvents(t, &target, 2)
+
+		testutil.IsNil(t, target.HasSharedVariables(domain.EnvVars{"SMTP_PASSWORD": domain.MaskedEnvVarValue, "API_KEY": domain.MaskedEnvVarValue}))
+		evt = testutil.EventIs[domain.TargetVarsChanged](t, &target, 2)
+		testutil.DeepEquals(t, domain.EnvVars{"SMTP_PASSWORD": "secret"}, evt.Vars)
+	})
+
+	t.Run("could not have its shared variables changed if delete requested", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		testutil.IsNil(t, target.RequestCleanup(false, uid))
+
+		testutil.ErrorIs(t, domain.ErrTargetCleanupRequested, target.HasSharedVariables(domain.EnvVars{"SMTP_HOST": "smtp.example.com"}))
+	})
+
+	t.Run("could have labels and raise the event only if different", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		testutil.IsNil(t, target.HasLabels(domain.Labels{"region=eu", "ssd"}))
+		evt := testutil.EventIs[domain.TargetLabelsChanged](t, &target, 1)
+		testutil.DeepEquals(t, domain.Labels{"region=eu", "ssd"}, evt.Labels)
+
+		testutil.IsNil(t, target.HasLabels(domain.Labels{"region=eu", "ssd"}))
+		testutil.HasNEvents(t, &target, 2)
+	})
+
+	t.Run("could not have its labels changed if delete requested", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		testutil.IsNil(t, target.RequestCleanup(false, uid))
+
+		testutil.ErrorIs(t, domain.ErrTargetCleanupRequested, target.HasLabels(domain.Labels{"ssd"}))
+	})
+
 	t.Run("could have its domain changed if available and raise the event only if different", func(t *testing.T) {
 		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
 		newUrl := must.Panic(domain.UrlFrom("http://new-url.com"))
@@ -162,6 +206,70 @@ func Test_Target(t *testing.T) {
 		testutil.Equals(t, domain.TargetStatusReady, changed.State.Status())
 	})
 
+	t.Run("could record its platform and raise the event only if different", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		target.RunsOn("linux/amd64")
+		target.RunsOn("linux/amd64")
+
+		testutil.HasNEvents(t, &target, 2)
+		changed := testutil.EventIs[domain.TargetPlatformChanged](t, &target, 1)
+		testutil.Equals(t, target.ID(), changed.ID)
+		testutil.Equals(t, "linux/amd64", changed.Platform)
+	})
+
+	t.Run("should check its platform against supported ones only if known", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		testutil.IsNil(t, target.CheckPlatform(domain.Platforms{"linux/arm64"}))
+
+		target.RunsOn("linux/amd64")
+
+		testutil.IsNil(t, target.CheckPlatform(nil))
+		testutil.IsNil(t, target.CheckPlatform(domain.Platforms{"linux/amd64", "linux/arm64"}))
+		testutil.ErrorIs(t, domain.ErrPlatformNotSupported, target.CheckPlatform(domain.Platforms{"linux/arm64"}))
+	})
+
+	t.Run("could record its proxy version and raise the event only if different", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		testutil.IsFalse(t, target.IsProxyUpToDate("v2.11"))
+
+		target.ProxyRunning("v2.11")
+		target.ProxyRunning("v2.11")
+
+		testutil.HasNEvents(t, &target, 2)
+		testutil.IsTrue(t, target.IsProxyUpToDate("v2.11"))
+		changed := testutil.EventIs[domain.TargetProxyVersionChanged](t, &target, 1)
+		testutil.Equals(t, target.ID(), changed.ID)
+		testutil.Equals(t, "v2.11", changed.Version)
+	})
+
+	t.Run("should require the target to be available to request a proxy upgrade", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationInProgress, target.RequestProxyUpgrade("v3.0"))
+
+		target.Configured(target.CurrentVersion(), nil, errors.New("configuration failed"))
+
+		testutil.ErrorIs(t, domain.ErrTargetConfigurationFailed, target.RequestProxyUpgrade("v3.0"))
+	})
+
+	t.Run("could request a proxy upgrade only if the version differs", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		target.ProxyRunning("v2.11")
+
+		testutil.ErrorIs(t, domain.ErrProxyUpToDate, target.RequestProxyUpgrade("v2.11"))
+		testutil.IsNil(t, target.RequestProxyUpgrade("v3.0"))
+
+		testutil.HasNEvents(t, &target, 4)
+		requested := testutil.EventIs[domain.TargetProxyUpgradeRequested](t, &target, 3)
+		testutil.Equals(t, target.ID(), requested.ID)
+		testutil.Equals(t, "v3.0", requested.Version)
+		testutil.IsFalse(t, target.IsProxyUpToDate("v3.0"))
+	})
+
 	t.Run("should handle entrypoints assignment on configuration", func(t *testing.T) {
 		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
 
@@ -619,6 +727,52 @@ func Test_Target(t *testing.T) {
 		testutil.DeepEquals(t, domain.TargetEntrypoints{}, target.CustomEntrypoints())
 	})
 
+	t.Run("should reserve published ports when exposing entrypoints", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+		dbService := deployConfig.NewService("db", "postgres:14-alpine")
+		tcp := dbService.AddTCPEntrypoint(5432, 15432)
+
+		target.ExposeEntrypoints(app.ID(), deployConfig.Environment(), domain.Services{dbService})
+
+		testutil.DeepEquals(t, domain.TargetEntrypoints{
+			app.ID(): {
+				deployConfig.Environment(): {
+					tcp.Name(): monad.Value[domain.Port](15432),
+				},
+			},
+		}, target.CustomEntrypoints())
+
+		// Changing the published port should update the reservation
+		dbService = deployConfig.NewService("db", "postgres:14-alpine")
+		dbService.AddTCPEntrypoint(5432, 25432)
+
+		target.ExposeEntrypoints(app.ID(), deployConfig.Environment(), domain.Services{dbService})
+
+		testutil.HasNEvents(t, &target, 5)
+		evt := testutil.EventIs[domain.TargetEntrypointsChanged](t, &target, 3)
+		testutil.DeepEquals(t, domain.TargetEntrypoints{
+			app.ID(): {
+				deployConfig.Environment(): {
+					tcp.Name(): monad.Value[domain.Port](25432),
+				},
+			},
+		}, evt.Entrypoints)
+	})
+
+	t.Run("should check published ports availability", func(t *testing.T) {
+		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
+		dbService := deployConfig.NewService("db", "postgres:14-alpine")
+		dbService.AddTCPEntrypoint(5432, 15432)
+
+		target.ExposeEntrypoints(app.ID(), domain.Production, domain.Services{dbService})
+
+		testutil.ErrorIs(t, domain.ErrPortAlreadyReserved, target.CheckPortsAvailability(app.ID(), domain.Production, []domain.Port{8080, 8080}))
+		testutil.ErrorIs(t, domain.ErrPortAlreadyReserved, target.CheckPortsAvailability(app.ID(), domain.Staging, []domain.Port{15432}))
+		testutil.ErrorIs(t, domain.ErrPortAlreadyReserved, target.CheckPortsAvailability("another-app", domain.Production, []domain.Port{15432}))
+		testutil.IsNil(t, target.CheckPortsAvailability(app.ID(), domain.Production, []domain.Port{15432}))
+		testutil.IsNil(t, target.CheckPortsAvailability("another-app", domain.Production, []domain.Port{8080}))
+	})
+
 	t.Run("should not be removed if no cleanup request has been set", func(t *testing.T) {
 		target := must.Panic(domain.NewTarget(name, urlUnique, configUnique, uid))
 
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrTeamInUse = apperr.New("team_in_use")
+
+type (
+	TeamID        string
+	HasAppsInTeam bool
+
+	// Group of apps. Users become members of a team when they are granted a role
+	// on it and this role applies to every app belonging to the team.
+	Team struct {
+		event.Emitter
+
+		id      TeamID
+		name    string
+		created shared.Action[auth.UserID]
+	}
+
+	TeamsReader interface {
+		GetByID(context.Context, TeamID) (Team, error)
+	}
+
+	TeamsWriter interface {
+		Write(context.Context, ...*Team) error
+	}
+
+	TeamCreated struct {
+		bus.Notification
+
+		ID      TeamID
+		Name    string
+		Created shared.Action[auth.UserID]
+	}
+
+	TeamRenamed struct {
+		bus.Notification
+
+		ID   TeamID
+		Name string
+	}
+
+	TeamDeleted struct {
+		bus.Notification
+
+		ID TeamID
+	}
+)
+
+func (TeamCreated) Name_() string { return "deployment.event.team_created" }
+func (TeamRenamed) Name_() string { return "deployment.event.team_renamed" }
+func (TeamDeleted) Name_() string { return "deployment.event.team_deleted" }
+
+// Creates a new team.
+func NewTeam(name string, uid auth.UserID) (t Team) {
+	t.apply(TeamCreated{
+		ID:      id.New[TeamID](),
+		Name:    name,
+		Created: shared.NewAction(uid),
+	})
+
+	return t
+}
+
+// Recreates a team from the persistent storage.
+func TeamFrom(scanner storage.Scanner) (t Team, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&t.id,
+		&t.name,
+		&createdAt,
+		&createdBy,
+	)
+
+	t.created = shared.ActionFrom(createdBy, createdAt)
+
+	return t, err
+}
+
+// Renames the team.
+func (t *Team) Rename(name string) {
+	if t.name == name {
+		return
+	}
+
+	t.apply(TeamRenamed{
+		ID:   t.id,
+		Name: name,
+	})
+}
+
+// Deletes the team. Apps should have been moved out of it first.
+func (t *Team) Delete(apps HasAppsInTeam) error {
+	if apps {
+		return ErrTeamInUse
+	}
+
+	t.apply(TeamDeleted{
+		ID: t.id,
+	})
+
+	return nil
+}
+
+func (t *Team) ID() TeamID             { return t.id }
+func (t *Team) Name() string           { return t.name }
+func (t *Team) CreatedBy() auth.UserID { return t.created.By() }
+
+// Resource on which a role should be granted to become a member of this team.
+func (t *Team) Resources() []auth.Resource {
+	return []auth.Resource{auth.NewResource(auth.ResourceTeam, string(t.id))}
+}
+
+func (t *Team) apply(e event.Event) {
+	switch evt := e.(type) {
+	case TeamCreated:
+		t.id = evt.ID
+		t.name = evt.Name
+		t.created = evt.Created
+	case TeamRenamed:
+		t.name = evt.Name
+	}
+
+	event.Store(t, e)
+}
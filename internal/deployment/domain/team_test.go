@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Team(t *testing.T) {
+	t.Run("could be created", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+
+		created := testutil.EventIs[domain.TeamCreated](t, &team, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "my-team", created.Name)
+		testutil.Equals(t, "uid", created.Created.By())
+		testutil.IsFalse(t, created.Created.At().IsZero())
+	})
+
+	t.Run("could be renamed and raise the event only if different", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+
+		team.Rename("new team")
+		team.Rename("new team")
+
+		testutil.HasNEvents(t, &team, 2)
+		renamed := testutil.EventIs[domain.TeamRenamed](t, &team, 1)
+		testutil.Equals(t, team.ID(), renamed.ID)
+		testutil.Equals(t, "new team", renamed.Name)
+	})
+
+	t.Run("should not be deleted if it still has apps", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+
+		err := team.Delete(true)
+
+		testutil.ErrorIs(t, domain.ErrTeamInUse, err)
+		testutil.HasNEvents(t, &team, 1)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		team := domain.NewTeam("my-team", "uid")
+
+		err := team.Delete(false)
+
+		testutil.IsNil(t, err)
+		deleted := testutil.EventIs[domain.TeamDeleted](t, &team, 1)
+		testutil.Equals(t, team.ID(), deleted.ID)
+	})
+}
@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+// Upper bounds of the buckets in which request durations are counted. Requests slower
+// than the last one fall in an additional bucket.
+var LatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type (
+	// Request served by the proxy of a target to an application service.
+	AccessLog struct {
+		AppID       AppID
+		Environment Environment
+		Service     string
+		Status      int
+		Duration    time.Duration
+	}
+
+	// Requests served to an application service aggregated over a collection period.
+	TrafficSample struct {
+		AppID       AppID
+		Environment Environment
+		Service     string
+		Requests    int64
+		Statuses    StatusCounts
+		Latencies   LatencyHistogram
+	}
+
+	// Number of responses by status class.
+	StatusCounts struct {
+		Success     int64 `json:"2xx"`
+		Redirect    int64 `json:"3xx"`
+		ClientError int64 `json:"4xx"`
+		ServerError int64 `json:"5xx"`
+	}
+
+	// Number of requests whose duration fall in each of the LatencyBuckets.
+	LatencyHistogram []int64
+
+	TrafficWriter interface {
+		// Store the given samples as collected at the given date.
+		WriteTraffic(context.Context, time.Time, []TrafficSample) error
+		// Remove samples collected before the given date.
+		PruneTraffic(context.Context, time.Time) error
+	}
+)
+
+// Aggregates access logs by application service.
+func AggregateAccessLogs(logs []AccessLog) []TrafficSample {
+	type key struct {
+		app     AppID
+		env     Environment
+		service string
+	}
+
+	var (
+		result  []TrafficSample
+		indexes = make(map[key]int)
+	)
+
+	for _, l := range logs {
+		k := key{l.AppID, l.Environment, l.Service}
+		idx, exists := indexes[k]
+
+		if !exists {
+			idx = len(result)
+			indexes[k] = idx
+			result = append(result, TrafficSample{
+				AppID:       l.AppID,
+				Environment: l.Environment,
+				Service:     l.Service,
+			})
+		}
+
+		result[idx].Requests++
+		result[idx].Statuses.Add(l.Status)
+		result[idx].Latencies = result[idx].Latencies.Add(l.Duration)
+	}
+
+	return result
+}
+
+// Count a response with the given status code, informational ones are ignored.
+func (s *StatusCounts) Add(status int) {
+	switch status / 100 {
+	case 2:
+		s.Success++
+	case 3:
+		s.Redirect++
+	case 4:
+		s.ClientError++
+	case 5:
+		s.ServerError++
+	}
+}
+
+func (s *StatusCounts) Merge(other StatusCounts) {
+	s.Success += other.Success
+	s.Redirect += other.Redirect
+	s.ClientError += other.ClientError
+	s.ServerError += other.ServerError
+}
+
+// Count a request which took the given duration.
+func (h LatencyHistogram) Add(d time.Duration) LatencyHistogram {
+	h = h.sized()
+
+	for i, bound := range LatencyBuckets {
+		if d <= bound {
+			h[i]++
+			return h
+		}
+	}
+
+	h[len(LatencyBuckets)]++
+
+	return h
+}
+
+// Sums counts of both histograms.
+func (h LatencyHistogram) Merge(other LatencyHistogram) LatencyHistogram {
+	h = h.sized()
+
+	for i, count := range other {
+		if i < len(h) {
+			h[i] += count
+		}
+	}
+
+	return h
+}
+
+// Estimates the duration under which the given percentage of requests have been served
+// as the upper bound of the bucket it falls in. For requests slower than the last bucket,
+// its bound is returned.
+func (h LatencyHistogram) Percentile(percent float64) time.Duration {
+	var total int64
+
+	for _, count := range h {
+		total += count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	var (
+		rank       = float64(total) * percent / 100
+		cumulative int64
+	)
+
+	for i, count := range h {
+		cumulative += count
+
+		if float64(cumulative) >= rank {
+			return LatencyBuckets[min(i, len(LatencyBuckets)-1)]
+		}
+	}
+
+	return LatencyBuckets[len(LatencyBuckets)-1]
+}
+
+func (h LatencyHistogram) sized() LatencyHistogram {
+	if len(h) == len(LatencyBuckets)+1 {
+		return h
+	}
+
+	sized := make(LatencyHistogram, len(LatencyBuckets)+1)
+	copy(sized, h)
+
+	return sized
+}
+
+func (h LatencyHistogram) Value() (driver.Value, error) { return storage.ValueJSON(h) }
+func (h *LatencyHistogram) Scan(value any) error        { return storage.ScanJSON(value, h) }
+func (s StatusCounts) Value() (driver.Value, error)     { return storage.ValueJSON(s) }
+func (s *StatusCounts) Scan(value any) error            { return storage.ScanJSON(value, s) }
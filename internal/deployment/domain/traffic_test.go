@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AggregateAccessLogs(t *testing.T) {
+	t.Run("should aggregate requests by service", func(t *testing.T) {
+		samples := domain.AggregateAccessLogs([]domain.AccessLog{
+			{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 200, Duration: 3 * time.Millisecond},
+			{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 301, Duration: 40 * time.Millisecond},
+			{AppID: "my-app", Environment: domain.Staging, Service: "app", Status: 404, Duration: time.Millisecond},
+			{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 500, Duration: time.Minute},
+		})
+
+		testutil.HasLength(t, samples, 2)
+		testutil.Equals(t, domain.Production, samples[0].Environment)
+		testutil.Equals(t, 3, samples[0].Requests)
+		testutil.Equals(t, domain.StatusCounts{Success: 1, Redirect: 1, ServerError: 1}, samples[0].Statuses)
+		testutil.DeepEquals(t, domain.LatencyHistogram{1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}, samples[0].Latencies)
+		testutil.Equals(t, domain.Staging, samples[1].Environment)
+		testutil.Equals(t, domain.StatusCounts{ClientError: 1}, samples[1].Statuses)
+	})
+}
+
+func Test_LatencyHistogram(t *testing.T) {
+	t.Run("should return 0 when empty", func(t *testing.T) {
+		var h domain.LatencyHistogram
+
+		testutil.Equals(t, 0, h.Percentile(95))
+	})
+
+	t.Run("should estimate percentiles from its buckets", func(t *testing.T) {
+		var h domain.LatencyHistogram
+
+		for i := 0; i < 95; i++ {
+			h = h.Add(20 * time.Millisecond)
+		}
+
+		for i := 0; i < 5; i++ {
+			h = h.Add(700 * time.Millisecond)
+		}
+
+		testutil.Equals(t, 25*time.Millisecond, h.Percentile(95))
+		testutil.Equals(t, time.Second, h.Percentile(99))
+	})
+
+	t.Run("should be merged with another one", func(t *testing.T) {
+		a := domain.LatencyHistogram{}.Add(time.Millisecond)
+		b := domain.LatencyHistogram{}.Add(time.Millisecond).Add(time.Hour)
+
+		merged := a.Merge(b)
+
+		testutil.DeepEquals(t, domain.LatencyHistogram{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, merged)
+		testutil.Equals(t, 10*time.Second, merged.Percentile(95))
+	})
+}
@@ -0,0 +1,332 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrTrashedAppRestored = apperr.New("trashed_app_restored")
+
+type (
+	// Snapshot of a deleted application kept for a retention period so it could be
+	// restored. It holds what is needed to recreate the application and to redeploy
+	// the last successful deployment of each environment.
+	TrashedApp struct {
+		event.Emitter
+
+		id             AppID
+		name           AppName
+		versionControl monad.Maybe[VersionControl]
+		production     EnvironmentConfig
+		staging        EnvironmentConfig
+		labels         Labels
+		deployments    []TrashedDeployment
+		createdBy      auth.UserID
+		deleted        shared.Action[auth.UserID]
+		expiresAt      time.Time
+		restored       bool
+	}
+
+	// Last successful deployment of an environment, redeployed when restoring a
+	// trashed application if asked to.
+	TrashedDeployment struct {
+		environment Environment
+		source      SourceData
+		labels      Labels
+	}
+
+	TrashedAppsReader interface {
+		GetByID(context.Context, AppID) (TrashedApp, error)
+		// Retrieve trashed apps whose retention has ended at the given date.
+		GetExpired(context.Context, time.Time) ([]TrashedApp, error)
+	}
+
+	TrashedAppsWriter interface {
+		Write(context.Context, ...*TrashedApp) error
+	}
+
+	TrashedAppCreated struct {
+		bus.Notification
+
+		ID             AppID
+		Name           AppName
+		VersionControl monad.Maybe[VersionControl]
+		Production     EnvironmentConfig
+		Staging        EnvironmentConfig
+		Labels         Labels
+		Deployments    []TrashedDeployment
+		CreatedBy      auth.UserID
+		Deleted        shared.Action[auth.UserID]
+		ExpiresAt      time.Time
+	}
+
+	TrashedAppRestored struct {
+		bus.Notification
+
+		ID    AppID
+		AppID AppID // Identifier of the recreated application
+	}
+
+	TrashedAppPurged struct {
+		bus.Notification
+
+		ID AppID
+	}
+)
+
+func (TrashedAppCreated) Name_() string  { return "deployment.event.trashed_app_created" }
+func (TrashedAppRestored) Name_() string { return "deployment.event.trashed_app_restored" }
+func (TrashedAppPurged) Name_() string   { return "deployment.event.trashed_app_purged" }
+
+// Moves a deleted application to the trash until the given retention has ended. The
+// given deployments are used to find the last successful one of each environment.
+func NewTrashedApp(app App, deployments []Deployment, retention time.Duration) (t TrashedApp, err error) {
+	cleanup, requested := app.cleanupRequested.TryGet()
+
+	if !requested {
+		return t, ErrAppCleanupNeeded
+	}
+
+	var (
+		trashedDeployments []TrashedDeployment
+		found              = make(map[Environment]bool, 2)
+	)
+
+	// Look for the most recent successful deployment of each environment
+	for i := len(deployments) - 1; i >= 0; i-- {
+		depl := deployments[i]
+
+		if depl.id.appID != app.id ||
+			depl.state.status != DeploymentStatusSucceeded ||
+			found[depl.config.environment] {
+			continue
+		}
+
+		found[depl.config.environment] = true
+		trashedDeployments = append(trashedDeployments, TrashedDeployment{
+			environment: depl.config.environment,
+			source:      depl.source,
+			labels:      depl.labels,
+		})
+	}
+
+	t.apply(TrashedAppCreated{
+		ID:             app.id,
+		Name:           app.name,
+		VersionControl: app.versionControl,
+		Production:     app.production,
+		Staging:        app.staging,
+		Labels:         app.labels,
+		Deployments:    trashedDeployments,
+		CreatedBy:      app.created.By(),
+		Deleted:        shared.NewAction(cleanup.By()),
+		ExpiresAt:      time.Now().UTC().Add(retention),
+	})
+
+	return t, nil
+}
+
+// Recreates a trashed app from the persistent storage.
+func TrashedAppFrom(scanner storage.Scanner) (t TrashedApp, err error) {
+	var (
+		url                           monad.Maybe[Url]
+		token                         monad.Maybe[string]
+		deletedAt                     time.Time
+		deletedBy                     auth.UserID
+		productionSourceDiscriminator monad.Maybe[string]
+		productionSource              monad.Maybe[string]
+		productionLabels              Labels
+		stagingSourceDiscriminator    monad.Maybe[string]
+		stagingSource                 monad.Maybe[string]
+		stagingLabels                 Labels
+	)
+
+	err = scanner.Scan(
+		&t.id,
+		&t.name,
+		&url,
+		&token,
+		&t.production.target,
+		&t.production.version,
+		&t.production.vars,
+		&t.production.exposure,
+		&t.production.protection,
+		&t.production.rules,
+		&t.production.override,
+		&t.production.aliases,
+		&t.production.scale,
+		&t.staging.target,
+		&t.staging.version,
+		&t.staging.vars,
+		&t.staging.exposure,
+		&t.staging.protection,
+		&t.staging.rules,
+		&t.staging.override,
+		&t.staging.aliases,
+		&t.staging.scale,
+		&t.labels,
+		&productionSourceDiscriminator,
+		&productionSource,
+		&productionLabels,
+		&stagingSourceDiscriminator,
+		&stagingSource,
+		&stagingLabels,
+		&t.createdBy,
+		&deletedAt,
+		&deletedBy,
+		&t.expiresAt,
+	)
+
+	if err != nil {
+		return t, err
+	}
+
+	t.deleted = shared.ActionFrom(deletedBy, deletedAt)
+
+	if u, isSet := url.TryGet(); isSet {
+		vcs := NewVersionControl(u)
+
+		if tok, isSet := token.TryGet(); isSet {
+			vcs.Authenticated(tok)
+		}
+
+		t.versionControl.Set(vcs)
+	}
+
+	for _, env := range []struct {
+		environment   Environment
+		discriminator monad.Maybe[string]
+		source        monad.Maybe[string]
+		labels        Labels
+	}{
+		{Production, productionSourceDiscriminator, productionSource, productionLabels},
+		{Staging, stagingSourceDiscriminator, stagingSource, stagingLabels},
+	} {
+		discriminator, isSet := env.discriminator.TryGet()
+
+		if !isSet {
+			continue
+		}
+
+		source, err := SourceDataTypes.From(discriminator, env.source.Get(""))
+
+		if err != nil {
+			return t, err
+		}
+
+		t.deployments = append(t.deployments, TrashedDeployment{
+			environment: env.environment,
+			source:      source,
+			labels:      env.labels,
+		})
+	}
+
+	return t, nil
+}
+
+// Recreates the application from this trashed one. Requirements must be built from
+// the Production and Staging configurations to make sure the name is still available
+// on their targets. The recreated application gets a new identifier.
+func (t *TrashedApp) Restore(
+	productionRequirement EnvironmentConfigRequirement,
+	stagingRequirement EnvironmentConfigRequirement,
+	restoredBy auth.UserID,
+) (app App, err error) {
+	if t.restored {
+		return app, ErrTrashedAppRestored
+	}
+
+	if app, err = NewApp(t.name, productionRequirement, stagingRequirement, restoredBy); err != nil {
+		return app, err
+	}
+
+	if vcs, isSet := t.versionControl.TryGet(); isSet {
+		if err = app.UseVersionControl(vcs); err != nil {
+			return app, err
+		}
+	}
+
+	if err = app.HasLabels(t.labels); err != nil {
+		return app, err
+	}
+
+	t.apply(TrashedAppRestored{
+		ID:    t.id,
+		AppID: app.id,
+	})
+
+	return app, nil
+}
+
+// Removes the trashed app for good once its retention has ended.
+func (t *TrashedApp) Purge() {
+	if t.restored {
+		return
+	}
+
+	t.apply(TrashedAppPurged{
+		ID: t.id,
+	})
+}
+
+func (t *TrashedApp) ID() AppID                           { return t.id }
+func (t *TrashedApp) Name() AppName                       { return t.name }
+func (t *TrashedApp) Production() EnvironmentConfig       { return t.production }
+func (t *TrashedApp) Staging() EnvironmentConfig          { return t.staging }
+func (t *TrashedApp) Deployments() []TrashedDeployment    { return t.deployments }
+func (t *TrashedApp) CreatedBy() auth.UserID              { return t.createdBy }
+func (t *TrashedApp) Deleted() shared.Action[auth.UserID] { return t.deleted }
+func (t *TrashedApp) ExpiresAt() time.Time                { return t.expiresAt }
+
+// Resources on which a role could be granted to manage this trashed app, the ones
+// of the application it has been made from.
+func (t *TrashedApp) Resources() []auth.Resource {
+	return []auth.Resource{
+		auth.NewResource(auth.ResourceApp, string(t.id)),
+		auth.NewResource(auth.ResourceTarget, string(t.production.Target())),
+		auth.NewResource(auth.ResourceTarget, string(t.staging.Target())),
+	}
+}
+
+func (t *TrashedApp) apply(e event.Event) {
+	switch evt := e.(type) {
+	case TrashedAppCreated:
+		t.id = evt.ID
+		t.name = evt.Name
+		t.versionControl = evt.VersionControl
+		t.production = evt.Production
+		t.staging = evt.Staging
+		t.labels = evt.Labels
+		t.deployments = evt.Deployments
+		t.createdBy = evt.CreatedBy
+		t.deleted = evt.Deleted
+		t.expiresAt = evt.ExpiresAt
+	case TrashedAppRestored:
+		t.restored = true
+	}
+
+	event.Store(t, e)
+}
+
+// Deploys again this deployment on the given restored application.
+func (d TrashedDeployment) Redeploy(app App, deployNumber DeploymentNumber, requestedBy auth.UserID) (depl Deployment, err error) {
+	if depl, err = app.NewDeployment(deployNumber, d.source, d.environment, requestedBy); err != nil {
+		return depl, err
+	}
+
+	depl.HasLabels(d.labels)
+
+	return depl, nil
+}
+
+func (d TrashedDeployment) Environment() Environment { return d.environment }
+func (d TrashedDeployment) Source() SourceData       { return d.source }
+func (d TrashedDeployment) Labels() Labels           { return d.labels }
@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_TrashedApp(t *testing.T) {
+	newApp := func() domain.App {
+		return must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("production-target"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("staging-target"), true, true),
+			"uid",
+		))
+	}
+
+	newDeployment := func(app domain.App, number domain.DeploymentNumber, env domain.Environment, deploymentErr error) domain.Deployment {
+		depl := must.Panic(app.NewDeployment(number, meta{}, env, "uid"))
+		depl.HasLabels(domain.Labels{"number=" + string(rune('0'+number))})
+		depl.HasStarted()
+		depl.HasEnded(domain.Services{}, deploymentErr)
+		return depl
+	}
+
+	restore := func(trashed *domain.TrashedApp) (domain.App, error) {
+		return trashed.Restore(
+			domain.NewEnvironmentConfigRequirement(trashed.Production(), true, true),
+			domain.NewEnvironmentConfigRequirement(trashed.Staging(), true, true),
+			"another-uid",
+		)
+	}
+
+	t.Run("should require the application cleanup to have been requested", func(t *testing.T) {
+		_, err := domain.NewTrashedApp(newApp(), nil, time.Hour)
+
+		testutil.ErrorIs(t, domain.ErrAppCleanupNeeded, err)
+	})
+
+	t.Run("could be created from an application and keep its last successful deployments", func(t *testing.T) {
+		app := newApp()
+		app.HasLabels(domain.Labels{"tier=web"})
+		deployments := []domain.Deployment{
+			newDeployment(app, 1, domain.Production, nil),
+			newDeployment(app, 2, domain.Production, nil),
+			newDeployment(app, 3, domain.Production, errors.New("some error")),
+			newDeployment(app, 4, domain.Staging, errors.New("some error")),
+		}
+		app.RequestCleanup("another-uid")
+		before := time.Now().UTC()
+
+		trashed, err := domain.NewTrashedApp(app, deployments, time.Hour)
+
+		testutil.IsNil(t, err)
+		created := testutil.EventIs[domain.TrashedAppCreated](t, &trashed, 0)
+		testutil.Equals(t, app.ID(), created.ID)
+		testutil.Equals(t, "my-app", created.Name)
+		testutil.DeepEquals(t, domain.Labels{"tier=web"}, created.Labels)
+		testutil.Equals(t, "uid", created.CreatedBy)
+		testutil.Equals(t, "another-uid", created.Deleted.By())
+		testutil.IsTrue(t, !created.ExpiresAt.Before(before.Add(time.Hour)))
+		testutil.HasLength(t, created.Deployments, 1)
+		testutil.Equals(t, domain.Production, created.Deployments[0].Environment())
+		testutil.DeepEquals(t, domain.Labels{"number=2"}, created.Deployments[0].Labels())
+	})
+
+	t.Run("could be restored as a new application", func(t *testing.T) {
+		app := newApp()
+		app.HasLabels(domain.Labels{"tier=web"})
+		app.RequestCleanup("uid")
+		trashed := must.Panic(domain.NewTrashedApp(app, nil, time.Hour))
+
+		restored, err := restore(&trashed)
+
+		testutil.IsNil(t, err)
+		testutil.NotEquals(t, app.ID(), restored.ID())
+		testutil.DeepEquals(t, domain.Labels{"tier=web"}, restored.Labels())
+		testutil.Equals(t, "production-target", restored.Production().Target())
+		testutil.Equals(t, "staging-target", restored.Staging().Target())
+		evt := testutil.EventIs[domain.TrashedAppRestored](t, &trashed, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+		testutil.Equals(t, restored.ID(), evt.AppID)
+	})
+
+	t.Run("should not be restored twice", func(t *testing.T) {
+		app := newApp()
+		app.RequestCleanup("uid")
+		trashed := must.Panic(domain.NewTrashedApp(app, nil, time.Hour))
+		_, _ = restore(&trashed)
+
+		_, err := restore(&trashed)
+
+		testutil.ErrorIs(t, domain.ErrTrashedAppRestored, err)
+		testutil.HasNEvents(t, &trashed, 2)
+	})
+
+	t.Run("could be purged", func(t *testing.T) {
+		app := newApp()
+		app.RequestCleanup("uid")
+		trashed := must.Panic(domain.NewTrashedApp(app, nil, time.Hour))
+
+		trashed.Purge()
+
+		evt := testutil.EventIs[domain.TrashedAppPurged](t, &trashed, 1)
+		testutil.Equals(t, app.ID(), evt.ID)
+	})
+
+	t.Run("could redeploy a trashed deployment on the restored application", func(t *testing.T) {
+		app := newApp()
+		deployment := newDeployment(app, 1, domain.Staging, nil)
+		app.RequestCleanup("uid")
+		trashed := must.Panic(domain.NewTrashedApp(app, []domain.Deployment{deployment}, time.Hour))
+		restored := must.Panic(restore(&trashed))
+
+		depl, err := trashed.Deployments()[0].Redeploy(restored, 1, "another-uid")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, restored.ID(), depl.ID().AppID())
+		testutil.Equals(t, domain.Staging, depl.Config().Environment())
+		testutil.DeepEquals(t, domain.Labels{"number=1"}, depl.Labels())
+	})
+}
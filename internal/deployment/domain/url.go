@@ -40,8 +40,9 @@ func UrlFrom(raw string) (Url, error) {
 	return result, nil
 }
 
-func (u Url) Host() string { return u.value.Host }
-func (u Url) UseSSL() bool { return u.value.Scheme == schemeHttps }
+func (u Url) Host() string     { return u.value.Host }
+func (u Url) Hostname() string { return u.value.Hostname() }
+func (u Url) UseSSL() bool     { return u.value.Scheme == schemeHttps }
 
 // Returns the user part of the url if any.
 func (u Url) User() (m monad.Maybe[string]) {
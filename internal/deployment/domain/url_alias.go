@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidUrlAlias = apperr.New("invalid_url_alias")
+	ErrUrlAliasTaken   = apperr.New("url_alias_taken")
+)
+
+// Additional hosts, such as apex or vanity domains, routed to the service exposed on
+// the default subdomain of an environment.
+type UrlAliases []string
+
+// Parses a fully qualified host name used as an alias, with at least two labels so
+// apex domains are accepted.
+func UrlAliasFrom(value string) (string, error) {
+	value = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+
+	if !strings.Contains(value, ".") || !subdomainRegex.MatchString(value) {
+		return "", ErrInvalidUrlAlias
+	}
+
+	return value, nil
+}
+
+// Returns sorted aliases without duplicates.
+func (a UrlAliases) normalize() UrlAliases {
+	result := slices.Clone(a)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+func (a UrlAliases) Value() (driver.Value, error) { return storage.ValueJSON(a) }
+func (a *UrlAliases) Scan(value any) error        { return storage.ScanJSON(value, a) }
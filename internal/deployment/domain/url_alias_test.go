@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UrlAlias(t *testing.T) {
+	t.Run("should be a fully qualified host name", func(t *testing.T) {
+		tests := []string{
+			"",
+			"localhost",
+			"not valid.com",
+			"https://example.com",
+			"example.com/path",
+			"-example.com",
+		}
+
+		for _, value := range tests {
+			t.Run(value, func(t *testing.T) {
+				_, err := domain.UrlAliasFrom(value)
+
+				testutil.ErrorIs(t, domain.ErrInvalidUrlAlias, err)
+			})
+		}
+	})
+
+	t.Run("could be created from an apex or sub domain", func(t *testing.T) {
+		alias, err := domain.UrlAliasFrom(" Example.COM. ")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "example.com", alias)
+
+		alias, err = domain.UrlAliasFrom("shop.example.co.uk")
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "shop.example.co.uk", alias)
+	})
+}
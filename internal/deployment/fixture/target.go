@@ -0,0 +1,54 @@
+// Package fixture builds domain objects in a given state so tests of the deployment
+// usecases do not have to repeat how to get them there.
+package fixture
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+type (
+	TargetOption func(*targetOptions)
+
+	targetOptions struct {
+		provider domain.ProviderConfig
+		labels   domain.Labels
+	}
+)
+
+// Builds a target reachable at the given url, configured successfully if ready is true.
+func Target(url string, ready bool, options ...TargetOption) domain.Target {
+	var opts targetOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	target := must.Panic(domain.NewTarget("my-target",
+		domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom(url)), true),
+		domain.NewProviderConfigRequirement(opts.provider, true), "some-uid"))
+
+	if ready {
+		target.Configured(target.CurrentVersion(), nil, nil)
+	}
+
+	if len(opts.labels) > 0 {
+		_ = target.HasLabels(opts.labels)
+	}
+
+	return target
+}
+
+// Sets the provider configuration of the target, none by default.
+func WithProvider(config domain.ProviderConfig) TargetOption {
+	return func(o *targetOptions) {
+		o.provider = config
+	}
+}
+
+// Sets labels of the target.
+func WithLabels(labels domain.Labels) TargetOption {
+	return func(o *targetOptions) {
+		o.labels = labels
+	}
+}
@@ -0,0 +1,172 @@
+package artifact
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/ostools"
+)
+
+const (
+	backupsDir       = "backups"
+	backupFileSuffix = ".sql" + compressedExt
+)
+
+type localAddonBackups struct {
+	directory string
+	logger    log.Logger
+	storage   Storage
+}
+
+// Instantiate a new AddonBackupsStorage which keeps gzipped backups in the data
+// directory. If a storage is given, backups are also copied to it and retrieved from
+// it when missing from the disk.
+func NewAddonBackups(options LocalOptions, logger log.Logger, storage Storage) domain.AddonBackupsStorage {
+	return &localAddonBackups{
+		directory: filepath.Join(options.DataDir(), backupsDir),
+		logger:    logger,
+		storage:   storage,
+	}
+}
+
+func (s *localAddonBackups) Save(ctx context.Context, backup domain.AddonBackup, dump func(io.Writer) error) (int64, error) {
+	name := s.backupPath(backup)
+
+	if err := ostools.MkdirAll(filepath.Dir(name)); err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	gzw := gzip.NewWriter(file)
+	err = dump(gzw)
+
+	if closeErr := gzw.Close(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(name) // Do not leave a truncated backup behind
+		return 0, err
+	}
+
+	info, err := os.Stat(name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if s.storage == nil {
+		return info.Size(), nil
+	}
+
+	if err = s.upload(ctx, backupKey(backup), name, info.Size()); err != nil {
+		s.logger.Errorw("could not upload add-on backup", "path", name, "error", err)
+	}
+
+	return info.Size(), nil
+}
+
+func (s *localAddonBackups) Open(ctx context.Context, backup domain.AddonBackup) (io.ReadCloser, error) {
+	name := s.backupPath(backup)
+
+	if _, err := os.Stat(name); err == nil || s.storage == nil {
+		return ostools.OpenGzip(name)
+	}
+
+	body, err := s.storage.Get(ctx, backupKey(backup))
+
+	if err != nil {
+		return nil, err
+	}
+
+	gzr, err := gzip.NewReader(body)
+
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+
+	return &remoteGzipReadCloser{gzr, body}, nil
+}
+
+func (s *localAddonBackups) Remove(ctx context.Context, backup domain.AddonBackup) error {
+	name := s.backupPath(backup)
+	s.logger.Debugw("removing add-on backup", "path", name)
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if s.storage == nil {
+		return nil
+	}
+
+	return s.storage.Delete(ctx, backupKey(backup))
+}
+
+func (s *localAddonBackups) RemoveAll(ctx context.Context, id domain.AddonID) error {
+	dir := filepath.Join(s.directory, string(id))
+	s.logger.Debugw("removing add-on backups", "path", dir)
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if s.storage == nil {
+		return nil
+	}
+
+	return s.storage.DeletePrefix(ctx, backupsKey(id))
+}
+
+func (s *localAddonBackups) backupPath(backup domain.AddonBackup) string {
+	return filepath.Join(s.directory, string(backup.AddonID()), string(backup.ID())+backupFileSuffix)
+}
+
+func (s *localAddonBackups) upload(ctx context.Context, key, name string, size int64) error {
+	file, err := os.Open(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return s.storage.Put(ctx, key, file, size)
+}
+
+func backupsKey(id domain.AddonID) string { return path.Join(backupsDir, string(id)) + "/" }
+func backupKey(backup domain.AddonBackup) string {
+	return backupsKey(backup.AddonID()) + string(backup.ID()) + backupFileSuffix
+}
+
+// Reader of a gzipped content retrieved from the remote storage.
+type remoteGzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (r *remoteGzipReadCloser) Close() error {
+	err := r.Reader.Close()
+
+	if closeErr := r.body.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,79 @@
+package artifact_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YuukanOO/seelf/cmd/config"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AddonBackups(t *testing.T) {
+	ctx := context.Background()
+	logger := must.Panic(log.NewLogger())
+	env := domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true)
+	app := must.Panic(domain.NewApp("my-app", env, env, "some-uid"))
+	addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "some-uid"))
+	addon.Provisioned(addon.CurrentVersion(), nil)
+	backup := must.Panic(domain.NewAddonBackup(addon))
+	dump := func(w io.Writer) error {
+		_, err := io.WriteString(w, "CREATE TABLE some_table;")
+		return err
+	}
+
+	sut := func(storage artifact.Storage) (domain.AddonBackupsStorage, string) {
+		opts := config.Default(config.WithTestDefaults())
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		return artifact.NewAddonBackups(opts, logger, storage), opts.DataDir()
+	}
+
+	read := func(t testing.TB, backups domain.AddonBackupsStorage) string {
+		r, err := backups.Open(ctx, backup)
+		testutil.IsNil(t, err)
+		defer r.Close()
+
+		return string(must.Panic(io.ReadAll(r)))
+	}
+
+	t.Run("should compress and store a backup on the disk", func(t *testing.T) {
+		backups, dir := sut(nil)
+
+		size, err := backups.Save(ctx, backup, dump)
+
+		testutil.IsNil(t, err)
+		info := must.Panic(os.Stat(filepath.Join(dir, "backups", string(addon.ID()), string(backup.ID())+".sql.gz")))
+		testutil.Equals(t, info.Size(), size)
+		testutil.Equals(t, "CREATE TABLE some_table;", read(t, backups))
+
+		testutil.IsNil(t, backups.Remove(ctx, backup))
+		_, err = backups.Open(ctx, backup)
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+
+	t.Run("should copy backups to the storage and retrieve them from it", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		backups, dir := sut(storage)
+
+		_, err := backups.Save(ctx, backup, dump)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, len(storage.objects))
+
+		testutil.IsNil(t, os.RemoveAll(filepath.Join(dir, "backups")))
+		testutil.Equals(t, "CREATE TABLE some_table;", read(t, backups))
+
+		testutil.IsNil(t, backups.RemoveAll(ctx, addon.ID()))
+		testutil.Equals(t, 0, len(storage.objects))
+	})
+}
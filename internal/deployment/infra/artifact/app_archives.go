@@ -0,0 +1,175 @@
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/ostools"
+)
+
+const (
+	archivesDir       = "archives"
+	archiveFileSuffix = ".tar" + compressedExt
+	archiveEntryTemp  = "seelf-archive-entry-*"
+)
+
+type (
+	localAppArchives struct {
+		directory string
+		logger    log.Logger
+		storage   Storage
+	}
+
+	// Adds files to a tarball. Since the size of a file must be known before writing its
+	// content, it is first written to a temporary file.
+	tarArchiveWriter struct {
+		tw *tar.Writer
+	}
+)
+
+// Instantiate a new AppArchivesStorage which keeps archives in the data directory. If
+// a storage is given, archives are also copied to it and retrieved from it when missing
+// from the disk.
+func NewAppArchives(options LocalOptions, logger log.Logger, storage Storage) domain.AppArchivesStorage {
+	return &localAppArchives{
+		directory: filepath.Join(options.DataDir(), archivesDir),
+		logger:    logger,
+		storage:   storage,
+	}
+}
+
+func (s *localAppArchives) Save(ctx context.Context, archive domain.AppArchive, fill func(domain.AppArchiveWriter) error) (int64, error) {
+	name := s.archivePath(archive)
+
+	if err := ostools.MkdirAll(filepath.Dir(name)); err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+	err = fill(&tarArchiveWriter{tw})
+
+	for _, closer := range []io.Closer{tw, gzw, file} {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		_ = os.Remove(name) // Do not leave a truncated archive behind
+		return 0, err
+	}
+
+	info, err := os.Stat(name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if s.storage == nil {
+		return info.Size(), nil
+	}
+
+	if err = s.upload(ctx, archiveKey(archive), name, info.Size()); err != nil {
+		s.logger.Errorw("could not upload app archive", "path", name, "error", err)
+	}
+
+	return info.Size(), nil
+}
+
+func (s *localAppArchives) Open(ctx context.Context, archive domain.AppArchive) (io.ReadCloser, error) {
+	name := s.archivePath(archive)
+
+	if _, err := os.Stat(name); err == nil || s.storage == nil {
+		return os.Open(name)
+	}
+
+	return s.storage.Get(ctx, archiveKey(archive))
+}
+
+func (s *localAppArchives) Remove(ctx context.Context, archive domain.AppArchive) error {
+	name := s.archivePath(archive)
+	s.logger.Debugw("removing app archive", "path", name)
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if s.storage == nil {
+		return nil
+	}
+
+	return s.storage.Delete(ctx, archiveKey(archive))
+}
+
+func (s *localAppArchives) archivePath(archive domain.AppArchive) string {
+	return filepath.Join(s.directory, string(archive.ID())+archiveFileSuffix)
+}
+
+func (s *localAppArchives) upload(ctx context.Context, key, name string, size int64) error {
+	file, err := os.Open(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return s.storage.Put(ctx, key, file, size)
+}
+
+func (w *tarArchiveWriter) Add(name string, write func(io.Writer) error) error {
+	temp, err := os.CreateTemp("", archiveEntryTemp)
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	if err = write(temp); err != nil {
+		return err
+	}
+
+	size, err := temp.Seek(0, io.SeekCurrent)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = temp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err = w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600, // Archives contain secrets such as environment variables
+		Size:    size,
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w.tw, temp)
+
+	return err
+}
+
+func archiveKey(archive domain.AppArchive) string {
+	return path.Join(archivesDir, string(archive.ID())) + archiveFileSuffix
+}
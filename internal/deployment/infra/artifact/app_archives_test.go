@@ -0,0 +1,115 @@
+package artifact_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YuukanOO/seelf/cmd/config"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_AppArchives(t *testing.T) {
+	ctx := context.Background()
+	logger := must.Panic(log.NewLogger())
+	env := domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true)
+	app := must.Panic(domain.NewApp("my-app", env, env, "some-uid"))
+	archive := must.Panic(domain.NewAppArchive(app, "some-uid"))
+	fill := func(w domain.AppArchiveWriter) error {
+		if err := w.Add("app.json", func(out io.Writer) error {
+			_, err := io.WriteString(out, `{"name":"my-app"}`)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		return w.Add("addons/production/DATABASE_URL.sql", func(out io.Writer) error {
+			_, err := io.WriteString(out, "CREATE TABLE some_table;")
+			return err
+		})
+	}
+
+	sut := func(storage artifact.Storage) (domain.AppArchivesStorage, string) {
+		opts := config.Default(config.WithTestDefaults())
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		return artifact.NewAppArchives(opts, logger, storage), opts.DataDir()
+	}
+
+	read := func(t testing.TB, archives domain.AppArchivesStorage) map[string]string {
+		r, err := archives.Open(ctx, archive)
+		testutil.IsNil(t, err)
+		defer r.Close()
+
+		gzr := must.Panic(gzip.NewReader(r))
+		tr := tar.NewReader(gzr)
+		files := make(map[string]string)
+
+		for {
+			header, err := tr.Next()
+
+			if errors.Is(err, io.EOF) {
+				return files
+			}
+
+			testutil.IsNil(t, err)
+			files[header.Name] = string(must.Panic(io.ReadAll(tr)))
+		}
+	}
+
+	t.Run("should store a gzipped tarball on the disk", func(t *testing.T) {
+		archives, dir := sut(nil)
+
+		size, err := archives.Save(ctx, archive, fill)
+
+		testutil.IsNil(t, err)
+		info := must.Panic(os.Stat(filepath.Join(dir, "archives", string(archive.ID())+".tar.gz")))
+		testutil.Equals(t, info.Size(), size)
+		testutil.DeepEquals(t, map[string]string{
+			"app.json":                           `{"name":"my-app"}`,
+			"addons/production/DATABASE_URL.sql": "CREATE TABLE some_table;",
+		}, read(t, archives))
+
+		testutil.IsNil(t, archives.Remove(ctx, archive))
+		_, err = archives.Open(ctx, archive)
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+
+	t.Run("should not keep a partial archive if it could not be filled", func(t *testing.T) {
+		archives, dir := sut(nil)
+		fillErr := errors.New("some error")
+
+		_, err := archives.Save(ctx, archive, func(domain.AppArchiveWriter) error { return fillErr })
+
+		testutil.ErrorIs(t, fillErr, err)
+		_, err = os.Stat(filepath.Join(dir, "archives", string(archive.ID())+".tar.gz"))
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+
+	t.Run("should copy archives to the storage and retrieve them from it", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		archives, dir := sut(storage)
+
+		_, err := archives.Save(ctx, archive, fill)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, len(storage.objects))
+
+		testutil.IsNil(t, os.RemoveAll(filepath.Join(dir, "archives")))
+		testutil.Equals(t, "CREATE TABLE some_table;", read(t, archives)["addons/production/DATABASE_URL.sql"])
+
+		testutil.IsNil(t, archives.Remove(ctx, archive))
+		testutil.Equals(t, 0, len(storage.objects))
+	})
+}
@@ -0,0 +1,151 @@
+package artifact
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Number of chunks a subscriber could lag behind before being dropped.
+const subscriberBufferSize = 256
+
+type (
+	// In-memory broker used to follow deployment logs while they are written. Each
+	// running deployment has its own topic keyed by its log file path.
+	logBroker struct {
+		mu     sync.Mutex
+		topics map[string]*logTopic
+	}
+
+	logTopic struct {
+		mu          sync.Mutex
+		written     int64
+		subscribers map[chan []byte]struct{}
+	}
+
+	// Writer which tees everything written to the log file into a broker topic.
+	teeWriter struct {
+		key    string
+		file   io.WriteCloser
+		topic  *logTopic
+		broker *logBroker
+	}
+)
+
+func newLogBroker() *logBroker {
+	return &logBroker{
+		topics: make(map[string]*logTopic),
+	}
+}
+
+// Opens a topic for the given key and returns a writer which will publish everything
+// written to the file on it. Closing the writer closes the topic.
+func (b *logBroker) tee(key string, file io.WriteCloser, size int64) io.WriteCloser {
+	topic := &logTopic{
+		written:     size,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+
+	b.mu.Lock()
+	b.topics[key] = topic
+	b.mu.Unlock()
+
+	return &teeWriter{
+		key:    key,
+		file:   file,
+		topic:  topic,
+		broker: b,
+	}
+}
+
+// Subscribe to the given topic if it exists. It returns the number of bytes written
+// before the subscription and a nil channel if no topic exists. The subscription
+// ends when the context is done.
+func (b *logBroker) subscribe(ctx context.Context, key string) (int64, <-chan []byte) {
+	b.mu.Lock()
+	topic, exists := b.topics[key]
+	b.mu.Unlock()
+
+	if !exists {
+		return 0, nil
+	}
+
+	ch := make(chan []byte, subscriberBufferSize)
+
+	topic.mu.Lock()
+	offset := topic.written
+	topic.subscribers[ch] = struct{}{}
+	topic.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		topic.unsubscribe(ch)
+	}()
+
+	return offset, ch
+}
+
+func (b *logBroker) remove(key string, topic *logTopic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The file may have been opened again since
+	if b.topics[key] == topic {
+		delete(b.topics, key)
+	}
+}
+
+func (t *logTopic) unsubscribe(ch chan []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.subscribers[ch]; !exists {
+		return
+	}
+
+	delete(t.subscribers, ch)
+	close(ch)
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.topic.mu.Lock()
+	defer w.topic.mu.Unlock()
+
+	n, err := w.file.Write(p)
+	w.topic.written += int64(n)
+
+	if n == 0 || len(w.topic.subscribers) == 0 {
+		return n, err
+	}
+
+	chunk := make([]byte, n)
+	copy(chunk, p[:n])
+
+	for ch := range w.topic.subscribers {
+		select {
+		case ch <- chunk:
+		default:
+			// Never block the deployment because of a slow subscriber, it will have
+			// to read the log file again
+			delete(w.topic.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return n, err
+}
+
+func (w *teeWriter) Close() error {
+	w.broker.remove(w.key, w.topic)
+
+	w.topic.mu.Lock()
+
+	for ch := range w.topic.subscribers {
+		delete(w.topic.subscribers, ch)
+		close(ch)
+	}
+
+	w.topic.mu.Unlock()
+
+	return w.file.Close()
+}
@@ -3,6 +3,7 @@ package artifact
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,7 +11,9 @@ import (
 	"text/template"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/ostools"
 )
 
@@ -20,21 +23,32 @@ var (
 )
 
 const (
-	logsDir = "logs"
-	appsDir = "apps"
+	logsDir       = "logs"
+	appsDir       = "apps"
+	cacheDir      = "cache"
+	reportsDir    = "reports"
+	logFileSuffix = ".deployment.log"
+	compressedExt = ".gz"
 )
 
 type (
 	LocalOptions interface {
 		DeploymentDirTemplate() *template.Template
 		DataDir() string
+		MaxLogSize() int64 // In bytes, 0 for no limit
+		BuildCacheEnabled() bool
+		MaxBuildCacheSize() int64 // In bytes per app, 0 for no limit
 	}
 
 	localArtifactManager struct {
-		options       LocalOptions
-		appsDirectory string
-		logsDirectory string
-		logger        log.Logger
+		options          LocalOptions
+		appsDirectory    string
+		logsDirectory    string
+		cacheDirectory   string
+		reportsDirectory string
+		logger           log.Logger
+		broker           *logBroker
+		storage          Storage
 	}
 
 	deploymentTemplateData struct {
@@ -43,13 +57,19 @@ type (
 	}
 )
 
-// Instantiate a new ArtifactManager which will store all the artifacts locally.
-func NewLocal(options LocalOptions, logger log.Logger) domain.ArtifactManager {
+// Instantiate a new ArtifactManager which will store all the artifacts locally. Logs
+// are compressed once a deployment is done. If a storage is given, logs and build
+// contexts are also copied to it and logs missing from the disk are retrieved from it.
+func NewLocal(options LocalOptions, logger log.Logger, storage Storage) domain.ArtifactManager {
 	return &localArtifactManager{
-		options:       options,
-		appsDirectory: filepath.Join(options.DataDir(), appsDir),
-		logsDirectory: filepath.Join(options.DataDir(), logsDir),
-		logger:        logger,
+		options:          options,
+		appsDirectory:    filepath.Join(options.DataDir(), appsDir),
+		logsDirectory:    filepath.Join(options.DataDir(), logsDir),
+		cacheDirectory:   filepath.Join(options.DataDir(), cacheDir),
+		reportsDirectory: filepath.Join(options.DataDir(), reportsDir),
+		logger:           logger,
+		broker:           newLogBroker(),
+		storage:          storage,
 	}
 }
 
@@ -57,14 +77,68 @@ func (a *localArtifactManager) PrepareBuild(
 	ctx context.Context,
 	depl domain.Deployment,
 ) (domain.DeploymentContext, error) {
-	logfile, err := ostools.OpenAppend(a.LogPath(ctx, depl))
+	return a.prepare(ctx, depl, false)
+}
+
+func (a *localArtifactManager) ResumeBuild(
+	ctx context.Context,
+	depl domain.Deployment,
+) (domain.DeploymentContext, error) {
+	buildDirectory, err := a.deploymentPath(depl)
+
+	if err != nil {
+		a.logger.Error(err)
+		return domain.DeploymentContext{}, ErrArtifactPrepareBuildDirectoryFailed
+	}
+
+	if entries, err := os.ReadDir(buildDirectory); err != nil || len(entries) == 0 {
+		return domain.DeploymentContext{}, domain.ErrBuildDirectoryMissing
+	}
+
+	return a.prepare(ctx, depl, true)
+}
+
+// Prepare the logger and the build directory of a deployment. When resuming, files
+// of the previous run are kept.
+func (a *localArtifactManager) prepare(
+	ctx context.Context,
+	depl domain.Deployment,
+	resume bool,
+) (domain.DeploymentContext, error) {
+	logpath := a.logPath(depl)
+	a.restore(ctx, depl, logpath)
+
+	// The log may have already been compressed if the deployment has been retried
+	if _, err := os.Stat(logpath + compressedExt); err == nil {
+		if err = ostools.Gunzip(logpath+compressedExt, logpath); err != nil {
+			a.logger.Error(err)
+			return domain.DeploymentContext{}, ErrArtifactOpenLoggerFailed
+		}
+	}
+
+	logfile, err := ostools.OpenAppend(logpath)
 
 	if err != nil {
 		a.logger.Error(err)
 		return domain.DeploymentContext{}, ErrArtifactOpenLoggerFailed
 	}
 
-	logger := newLogger(logfile)
+	info, err := logfile.Stat()
+
+	if err != nil {
+		a.logger.Error(err)
+		logfile.Close()
+		return domain.DeploymentContext{}, ErrArtifactOpenLoggerFailed
+	}
+
+	var buildDirectory string
+
+	writer := &closeNotifier{
+		a.broker.tee(logpath, logfile, info.Size()),
+		func() { a.complete(depl, logpath, buildDirectory) },
+	}
+
+	logger := newLogger(writer, a.options.MaxLogSize(), info.Size())
 
 	defer func() {
 		if err == nil {
@@ -76,19 +150,50 @@ func (a *localArtifactManager) PrepareBuild(
 		logger.Close()                               // And close the logger right now
 	}()
 
-	buildDirectory, err := a.deploymentPath(depl)
+	buildDirectory, err = a.deploymentPath(depl)
 
 	if err != nil {
 		return domain.DeploymentContext{}, err
 	}
 
-	logger.Infof("preparing build directory %s", buildDirectory)
+	if resume {
+		logger.Infof("reusing build directory %s", buildDirectory)
+	} else {
+		logger.Infof("preparing build directory %s", buildDirectory)
+
+		if err = ostools.EmptyDir(buildDirectory); err != nil {
+			return domain.DeploymentContext{}, err
+		}
+	}
+
+	var cacheDirectory monad.Maybe[string]
+
+	if a.options.BuildCacheEnabled() {
+		dir := a.cachePath(depl.ID().AppID())
+
+		if err = ostools.MkdirAll(dir); err != nil {
+			return domain.DeploymentContext{}, err
+		}
+
+		logger.Infof("using build cache directory %s", dir)
+		cacheDirectory.Set(dir)
+	}
+
+	// Reports of a retried deployment are recorded again, unless it is resumed in which
+	// case the ones of the skipped steps are kept
+	reports := a.Reports(ctx, depl)
+
+	if resume {
+		err = ostools.MkdirAll(reports.Directory)
+	} else {
+		err = ostools.EmptyDir(reports.Directory)
+	}
 
-	if err = ostools.EmptyDir(buildDirectory); err != nil {
+	if err != nil {
 		return domain.DeploymentContext{}, err
 	}
 
-	return domain.NewDeploymentContext(buildDirectory, logger), nil
+	return domain.NewDeploymentContext(buildDirectory, cacheDirectory, reports, logger), nil
 }
 
 func (a *localArtifactManager) Cleanup(ctx context.Context, id domain.AppID) error {
@@ -99,13 +204,119 @@ func (a *localArtifactManager) Cleanup(ctx context.Context, id domain.AppID) err
 		return err
 	}
 
+	if err := a.ClearCache(ctx, id); err != nil {
+		return err
+	}
+
+	reportsDir := a.appReportsPath(id)
+	a.logger.Debugw("removing app reports", "path", reportsDir)
+	if err := os.RemoveAll(reportsDir); err != nil {
+		return err
+	}
+
 	// Remove all logs for this app
-	logsPattern := filepath.Join(a.logsDirectory, "*"+string(id)+"*.deployment.log")
+	logsPattern := filepath.Join(a.logsDirectory, "*"+string(id)+"*"+logFileSuffix+"*")
 	a.logger.Debugw("removing app logs", "pattern", logsPattern)
-	return ostools.RemovePattern(logsPattern)
+	if err := ostools.RemovePattern(logsPattern); err != nil || a.storage == nil {
+		return err
+	}
+
+	// And their copies in the remote storage
+	if err := a.storage.DeletePrefix(ctx, appKey(id)); err != nil {
+		return err
+	}
+
+	return a.storage.DeletePrefix(ctx, logsKey(id))
+}
+
+func (a *localArtifactManager) ClearCache(_ context.Context, id domain.AppID) error {
+	cacheDir := a.cachePath(id)
+	a.logger.Debugw("removing app build cache", "path", cacheDir)
+	return os.RemoveAll(cacheDir)
+}
+
+func (a *localArtifactManager) Log(ctx context.Context, depl domain.Deployment) domain.DeploymentLog {
+	logpath := a.logPath(depl)
+	a.restore(ctx, depl, logpath)
+
+	if _, err := os.Stat(logpath + compressedExt); err == nil {
+		return domain.DeploymentLog{Path: logpath + compressedExt, Compressed: true}
+	}
+
+	return domain.DeploymentLog{Path: logpath}
+}
+
+func (a *localArtifactManager) Records(ctx context.Context, depl domain.Deployment) ([]domain.DeploymentLogRecord, error) {
+	var (
+		log  = a.Log(ctx, depl)
+		file io.ReadCloser
+		err  error
+	)
+
+	if log.Compressed {
+		file, err = ostools.OpenGzip(log.Path)
+	} else {
+		file, err = os.Open(log.Path)
+	}
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadRecords(file)
+}
+
+// Compress the log of a deployment which has just been processed and copy its
+// artifacts to the storage if any.
+func (a *localArtifactManager) complete(depl domain.Deployment, logpath, buildDirectory string) {
+	if err := ostools.Gzip(logpath, logpath+compressedExt); err != nil {
+		a.logger.Errorw("could not compress deployment log", "path", logpath, "error", err)
+	} else {
+		logpath += compressedExt
+	}
+
+	if a.storage != nil {
+		a.store(depl, logpath, buildDirectory)
+	}
+
+	a.trimCache(depl.ID().AppID())
+}
+
+// Remove the build cache of the given app if it exceeds the maximum size. Exported
+// layers accumulate over time so it is rebuilt from scratch by the next deployment.
+func (a *localArtifactManager) trimCache(id domain.AppID) {
+	limit := a.options.MaxBuildCacheSize()
+
+	if !a.options.BuildCacheEnabled() || limit <= 0 {
+		return
+	}
+
+	cacheDir := a.cachePath(id)
+	size, err := ostools.Size(cacheDir)
+
+	if err != nil {
+		a.logger.Errorw("could not compute build cache size", "path", cacheDir, "error", err)
+		return
+	}
+
+	if size <= limit {
+		return
+	}
+
+	a.logger.Infow("build cache exceeds its maximum size, removing it", "path", cacheDir, "size", size, "limit", limit)
+
+	if err = os.RemoveAll(cacheDir); err != nil {
+		a.logger.Errorw("could not remove build cache", "path", cacheDir, "error", err)
+	}
 }
 
-func (a *localArtifactManager) LogPath(ctx context.Context, depl domain.Deployment) string {
+func (a *localArtifactManager) logPath(depl domain.Deployment) string {
 	return filepath.Join(
 		a.logsDirectory,
 		strconv.FormatInt(depl.Requested().At().Unix(), 10)+
@@ -113,14 +324,230 @@ func (a *localArtifactManager) LogPath(ctx context.Context, depl domain.Deployme
 			string(depl.ID().AppID())+
 			"-"+
 			strconv.Itoa(int(depl.ID().DeploymentNumber()))+
-			".deployment.log",
+			logFileSuffix,
 	)
 }
 
+func (a *localArtifactManager) Follow(ctx context.Context, depl domain.Deployment) domain.DeploymentLogStream {
+	offset, chunks := a.broker.subscribe(ctx, a.logPath(depl))
+
+	return domain.DeploymentLogStream{
+		DeploymentLog: a.Log(ctx, depl),
+		Offset:        offset,
+		Chunks:        chunks,
+	}
+}
+
+func (a *localArtifactManager) Usage(ctx context.Context) (domain.ArtifactsUsage, error) {
+	usage := make(domain.ArtifactsUsage)
+
+	// Build directories, caches and reports are all stored in a directory named after the app
+	for _, root := range []string{a.appsDirectory, a.cacheDirectory, a.reportsDirectory} {
+		apps, err := os.ReadDir(root)
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, entry := range apps {
+			if !entry.IsDir() {
+				continue
+			}
+
+			size, err := ostools.Size(filepath.Join(root, entry.Name()))
+
+			if err != nil {
+				return nil, err
+			}
+
+			usage[domain.AppID(entry.Name())] += size
+		}
+	}
+
+	logs, err := os.ReadDir(a.logsDirectory)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, entry := range logs {
+		appID, isLog := logAppID(entry.Name())
+
+		if !isLog {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Removed in the meantime
+			}
+
+			return nil, err
+		}
+
+		usage[appID] += info.Size()
+	}
+
+	return usage, nil
+}
+
+func (a *localArtifactManager) Prune(ctx context.Context, depl, current domain.Deployment) (int64, error) {
+	var (
+		freed   int64
+		logpath = a.logPath(depl)
+		appID   = depl.ID().AppID()
+	)
+
+	for _, name := range []string{logpath, logpath + compressedExt} {
+		if info, err := os.Stat(name); err == nil {
+			if err = os.Remove(name); err != nil {
+				return freed, err
+			}
+
+			freed += info.Size()
+		} else if !os.IsNotExist(err) {
+			return freed, err
+		}
+	}
+
+	reports := a.Reports(ctx, depl)
+	reportsSize, err := ostools.Size(reports.Directory)
+
+	if err != nil {
+		return freed, err
+	}
+
+	if err = os.RemoveAll(reports.Directory); err != nil {
+		return freed, err
+	}
+
+	freed += reportsSize
+
+	buildDirectory, err := a.deploymentPath(depl)
+
+	if err != nil {
+		return freed, err
+	}
+
+	currentDirectory, err := a.deploymentPath(current)
+
+	if err != nil {
+		return freed, err
+	}
+
+	// With the default template, the build directory is reused by every deployment of the environment
+	shared := buildDirectory == currentDirectory
+
+	if !shared {
+		size, err := ostools.Size(buildDirectory)
+
+		if err != nil {
+			return freed, err
+		}
+
+		a.logger.Debugw("removing deployment directory", "path", buildDirectory)
+
+		if err = os.RemoveAll(buildDirectory); err != nil {
+			return freed, err
+		}
+
+		freed += size
+	}
+
+	if a.storage == nil {
+		return freed, nil
+	}
+
+	for _, name := range []string{logpath, logpath + compressedExt} {
+		if err = a.storage.Delete(ctx, logKey(appID, name)); err != nil {
+			return freed, err
+		}
+	}
+
+	if shared {
+		return freed, nil
+	}
+
+	key, err := a.buildContextKey(appID, buildDirectory)
+
+	if err != nil {
+		return freed, err
+	}
+
+	return freed, a.storage.Delete(ctx, key)
+}
+
+func (a *localArtifactManager) Reports(_ context.Context, depl domain.Deployment) domain.DeploymentReports {
+	return domain.DeploymentReports{
+		Directory: filepath.Join(
+			a.appReportsPath(depl.ID().AppID()),
+			strconv.Itoa(int(depl.ID().DeploymentNumber())),
+		),
+	}
+}
+
+func (a *localArtifactManager) BuildContext(ctx context.Context, depl, latest domain.Deployment) (io.ReadCloser, error) {
+	buildDirectory, err := a.deploymentPath(depl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	latestDirectory, err := a.deploymentPath(latest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Files of a shared build directory have been overwritten by the latest deployment
+	if buildDirectory == latestDirectory && depl.ID() != latest.ID() {
+		return nil, apperr.ErrNotFound
+	}
+
+	if entries, err := os.ReadDir(buildDirectory); err == nil && len(entries) > 0 {
+		r, w := io.Pipe()
+
+		go func() {
+			w.CloseWithError(archiveDirectory(w, buildDirectory))
+		}()
+
+		return r, nil
+	}
+
+	if a.storage == nil {
+		return nil, apperr.ErrNotFound
+	}
+
+	key, err := a.buildContextKey(depl.ID().AppID(), buildDirectory)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := a.storage.Get(ctx, key)
+
+	if err != nil {
+		a.logger.Debugw("could not retrieve build context", "key", key, "error", err)
+		return nil, apperr.ErrNotFound
+	}
+
+	return body, nil
+}
+
+func (a *localArtifactManager) appReportsPath(appID domain.AppID) string {
+	return filepath.Join(a.reportsDirectory, string(appID))
+}
+
 func (a *localArtifactManager) appPath(appID domain.AppID) string {
 	return filepath.Join(a.appsDirectory, string(appID))
 }
 
+func (a *localArtifactManager) cachePath(appID domain.AppID) string {
+	return filepath.Join(a.cacheDirectory, string(appID))
+}
+
 func (a *localArtifactManager) deploymentPath(depl domain.Deployment) (string, error) {
 	var w strings.Builder
 
@@ -133,3 +560,20 @@ func (a *localArtifactManager) deploymentPath(depl domain.Deployment) (string, e
 
 	return filepath.Join(a.appPath(depl.ID().AppID()), w.String()), nil
 }
+
+// Extracts the app id from a log file name built by logPath, compressed or not.
+func logAppID(name string) (domain.AppID, bool) {
+	name, isLog := strings.CutSuffix(strings.TrimSuffix(name, compressedExt), logFileSuffix)
+
+	if !isLog {
+		return "", false
+	}
+
+	parts := strings.Split(name, "-")
+
+	if len(parts) != 3 {
+		return "", false
+	}
+
+	return domain.AppID(parts[1]), true
+}
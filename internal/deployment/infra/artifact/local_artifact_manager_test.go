@@ -1,16 +1,25 @@
 package artifact_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/ostools"
 	"github.com/YuukanOO/seelf/pkg/testutil"
 )
 
@@ -19,15 +28,20 @@ func Test_LocalArtifactManager(t *testing.T) {
 	env := domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true)
 	app := must.Panic(domain.NewApp("my-app", env, env, "some-uid"))
 	depl := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+	nextDepl := must.Panic(app.NewDeployment(2, raw.Data(""), domain.Production, "some-uid"))
 
-	sut := func() domain.ArtifactManager {
+	sutWithStorage := func(storage artifact.Storage) domain.ArtifactManager {
 		opts := config.Default(config.WithTestDefaults())
 
 		t.Cleanup(func() {
 			os.RemoveAll(opts.DataDir())
 		})
 
-		return artifact.NewLocal(opts, logger)
+		return artifact.NewLocal(opts, logger, storage)
+	}
+
+	sut := func() domain.ArtifactManager {
+		return sutWithStorage(nil)
 	}
 
 	t.Run("should correctly prepare a build directory", func(t *testing.T) {
@@ -57,4 +71,451 @@ func Test_LocalArtifactManager(t *testing.T) {
 		_, err = os.ReadDir(ctx.BuildDirectory())
 		testutil.IsTrue(t, os.IsNotExist(err))
 	})
+	t.Run("should compress the log once done and decompress it when retried", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		ctx.Logger().Infof("first attempt")
+		testutil.IsFalse(t, manager.Log(context.Background(), depl).Compressed)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		log := manager.Log(context.Background(), depl)
+		testutil.IsTrue(t, log.Compressed)
+		testutil.IsTrue(t, strings.HasSuffix(log.Path, ".deployment.log.gz"))
+
+		ctx, err = manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		ctx.Logger().Infof("second attempt")
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		data, err := os.ReadFile(manager.Log(context.Background(), depl).Path)
+		testutil.IsNil(t, err)
+
+		content := gunzip(t, data)
+		testutil.Contains(t, "[INFO] first attempt", content)
+		testutil.Contains(t, "[INFO] second attempt", content)
+	})
+
+	t.Run("should discard the output once the log exceeds its maximum size", func(t *testing.T) {
+		opts := config.Default(config.WithTestDefaults())
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		manager := artifact.NewLocal(limitedOptions{opts, 256}, logger, nil)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		for i := 0; i < 10; i++ {
+			n, err := ctx.Logger().Write([]byte(strings.Repeat("a", 63) + "\n"))
+			testutil.IsNil(t, err)
+			testutil.Equals(t, 64, n)
+		}
+
+		ctx.Logger().Stepf("deployment done")
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		data, err := os.ReadFile(manager.Log(context.Background(), depl).Path)
+		testutil.IsNil(t, err)
+
+		content := gunzip(t, data)
+		testutil.Contains(t, "[WARN] log truncated", content)
+		testutil.Equals(t, 1, strings.Count(content, "[WARN] log truncated"))
+		testutil.IsTrue(t, strings.HasSuffix(content, "[STEP] deployment done\n"))
+		testutil.IsTrue(t, strings.Count(content, strings.Repeat("a", 63)) < 4)
+	})
+
+	t.Run("should write structured records with the current step", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		ctx.Logger().Stepf("fetching")
+		ctx.Logger().Begin(domain.DeploymentStepBuild)
+		ctx.Logger().Write([]byte("some output\nwithout"))
+		ctx.Logger().Write([]byte(" new line"))
+		ctx.Logger().Begin(domain.DeploymentStepDeploy)
+		ctx.Logger().Error(errors.New("failed"))
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		records, err := manager.Records(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, records, 5)
+
+		for _, r := range records {
+			testutil.IsFalse(t, r.Time.IsZero())
+		}
+
+		testutil.Equals(t, domain.DeploymentStepFetch, records[0].Step)
+		testutil.Equals(t, domain.LogStreamSeelf, records[0].Stream)
+		testutil.Equals(t, "[INFO] preparing build directory "+ctx.BuildDirectory(), records[0].String())
+		testutil.Equals(t, "[STEP] fetching", records[1].String())
+		testutil.Equals(t, domain.DeploymentStepBuild, records[2].Step)
+		testutil.Equals(t, domain.LogStreamOutput, records[2].Stream)
+		testutil.Equals(t, "some output", records[2].Message)
+		testutil.Equals(t, domain.DeploymentStepBuild, records[3].Step)
+		testutil.Equals(t, "without new line", records[3].Message)
+		testutil.Equals(t, domain.DeploymentStepDeploy, records[4].Step)
+		testutil.Equals(t, domain.LogLevelError, records[4].Level)
+		testutil.Equals(t, "failed", records[4].Message)
+	})
+
+	t.Run("should read logs written before records were structured", func(t *testing.T) {
+		manager := sut()
+		logpath := manager.Log(context.Background(), depl).Path
+
+		testutil.IsNil(t, ostools.WriteFile(logpath, []byte("[STEP] old step\nsome output\n")))
+
+		records, err := manager.Records(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.DeploymentLogRecord{
+			{Level: domain.LogLevelInfo, Stream: domain.LogStreamOutput, Message: "[STEP] old step"},
+			{Level: domain.LogLevelInfo, Stream: domain.LogStreamOutput, Message: "some output"},
+		}, records)
+	})
+
+	t.Run("should not provide a build cache directory if disabled", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		defer ctx.Logger().Close()
+
+		testutil.IsFalse(t, ctx.CacheDirectory().HasValue())
+	})
+
+	t.Run("should keep the build cache between deployments and clear it when asked to", func(t *testing.T) {
+		opts := config.Default(config.WithTestDefaults())
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		manager := artifact.NewLocal(cacheOptions{opts, 0}, logger, nil)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		cacheDir := ctx.CacheDirectory().MustGet()
+		testutil.IsNil(t, os.WriteFile(filepath.Join(cacheDir, "index.json"), []byte("{}"), 0644))
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		ctx, err = manager.PrepareBuild(context.Background(), nextDepl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		testutil.Equals(t, cacheDir, ctx.CacheDirectory().MustGet())
+		_, err = os.Stat(filepath.Join(cacheDir, "index.json"))
+		testutil.IsNil(t, err)
+
+		usage, err := manager.Usage(context.Background())
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, usage[app.ID()] > int64(len("{}")))
+
+		testutil.IsNil(t, manager.ClearCache(context.Background(), app.ID()))
+
+		_, err = os.Stat(cacheDir)
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+
+	t.Run("should remove the build cache once it exceeds its maximum size", func(t *testing.T) {
+		opts := config.Default(config.WithTestDefaults())
+
+		t.Cleanup(func() {
+			os.RemoveAll(opts.DataDir())
+		})
+
+		manager := artifact.NewLocal(cacheOptions{opts, 16}, logger, nil)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		cacheDir := ctx.CacheDirectory().MustGet()
+		testutil.IsNil(t, os.WriteFile(filepath.Join(cacheDir, "blob"), []byte(strings.Repeat("a", 32)), 0644))
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		_, err = os.Stat(cacheDir)
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+
+	t.Run("should compute the disk usage of every app", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		testutil.IsNil(t, os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services:"), 0644))
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		info, err := os.Stat(manager.Log(context.Background(), depl).Path)
+		testutil.IsNil(t, err)
+
+		usage, err := manager.Usage(context.Background())
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.ArtifactsUsage{
+			app.ID(): info.Size() + int64(len("services:")),
+		}, usage)
+	})
+
+	t.Run("should prune a superseded deployment but keep its build directory if shared", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		manager := sutWithStorage(storage)
+
+		for _, d := range []domain.Deployment{depl, nextDepl} {
+			ctx, err := manager.PrepareBuild(context.Background(), d)
+			testutil.IsNil(t, err)
+			testutil.IsNil(t, os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services:"), 0644))
+			testutil.IsNil(t, os.WriteFile(ctx.Reports().ManifestPath(), []byte("{}"), 0644))
+			testutil.IsNil(t, ctx.Logger().Close())
+		}
+
+		logpath := manager.Log(context.Background(), depl).Path
+		info, err := os.Stat(logpath)
+		testutil.IsNil(t, err)
+
+		freed, err := manager.Prune(context.Background(), depl, nextDepl)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, info.Size()+int64(len("{}")), freed)
+
+		_, err = os.Stat(logpath)
+		testutil.IsTrue(t, os.IsNotExist(err))
+
+		_, err = os.Stat(manager.Reports(context.Background(), depl).Directory)
+		testutil.IsTrue(t, os.IsNotExist(err))
+
+		_, found := storage.objects["logs/"+string(app.ID())+"/"+filepath.Base(logpath)]
+		testutil.IsFalse(t, found)
+
+		_, err = os.Stat(manager.Log(context.Background(), nextDepl).Path)
+		testutil.IsNil(t, err)
+
+		// The default template uses the same directory for every deployment of an environment
+		usage, err := manager.Usage(context.Background())
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, usage[app.ID()] > int64(len("services:")))
+	})
+
+	t.Run("should not stream logs of a deployment which is not running", func(t *testing.T) {
+		manager := sut()
+
+		stream := manager.Follow(context.Background(), depl)
+
+		testutil.Equals(t, manager.Log(context.Background(), depl), stream.DeploymentLog)
+		testutil.IsTrue(t, stream.Chunks == nil)
+	})
+
+	t.Run("should stream logs written while the deployment is running", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		stream := manager.Follow(context.Background(), depl)
+		info, err := os.Stat(stream.Path)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, info.Size(), stream.Offset)
+
+		ctx.Logger().Stepf("building %s", "app")
+		record := artifact.DecodeRecord(bytes.TrimSuffix(<-stream.Chunks, []byte("\n")))
+		testutil.Equals(t, domain.DeploymentStepFetch, record.Step)
+		testutil.Equals(t, domain.LogLevelStep, record.Level)
+		testutil.Equals(t, "[STEP] building app", record.String())
+
+		ctx.Logger().Close()
+
+		_, open := <-stream.Chunks
+		testutil.IsFalse(t, open)
+	})
+
+	t.Run("should stop streaming logs when the context is done", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		defer ctx.Logger().Close()
+
+		followCtx, cancel := context.WithCancel(context.Background())
+		stream := manager.Follow(followCtx, depl)
+		cancel()
+
+		_, open := <-stream.Chunks
+		testutil.IsFalse(t, open)
+	})
+
+	t.Run("should copy the log and the build context to the storage once done", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		manager := sutWithStorage(storage)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		testutil.IsNil(t, os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services:"), 0644))
+		ctx.Logger().Infof("building %s", "app")
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		logpath := manager.Log(context.Background(), depl).Path
+		logKey := "logs/" + string(app.ID()) + "/" + filepath.Base(logpath)
+
+		testutil.Contains(t, "[INFO] building app", gunzip(t, storage.objects[logKey]))
+
+		var contextKey string
+
+		for key := range storage.objects {
+			if strings.HasPrefix(key, "apps/"+string(app.ID())+"/") {
+				contextKey = key
+			}
+		}
+
+		testutil.IsTrue(t, strings.HasSuffix(contextKey, ".tar.gz"))
+
+		gzr, err := gzip.NewReader(bytes.NewReader(storage.objects[contextKey]))
+		testutil.IsNil(t, err)
+
+		header, err := tar.NewReader(gzr).Next()
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "compose.yml", header.Name)
+	})
+
+	t.Run("should archive the build directory of a deployment", func(t *testing.T) {
+		manager := sut()
+
+		_, err := manager.BuildContext(context.Background(), depl, depl)
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		testutil.IsNil(t, os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services:"), 0644))
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		archive, err := manager.BuildContext(context.Background(), depl, depl)
+		testutil.IsNil(t, err)
+
+		defer archive.Close()
+
+		gzr, err := gzip.NewReader(archive)
+		testutil.IsNil(t, err)
+
+		header, err := tar.NewReader(gzr).Next()
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "compose.yml", header.Name)
+
+		_, err = manager.BuildContext(context.Background(), depl, nextDepl)
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should retrieve the build context from the storage if missing from the disk", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		manager := sutWithStorage(storage)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		testutil.IsNil(t, os.WriteFile(filepath.Join(ctx.BuildDirectory(), "compose.yml"), []byte("services:"), 0644))
+		testutil.IsNil(t, ctx.Logger().Close())
+		testutil.IsNil(t, os.RemoveAll(ctx.BuildDirectory()))
+
+		archive, err := manager.BuildContext(context.Background(), depl, depl)
+		testutil.IsNil(t, err)
+
+		defer archive.Close()
+
+		gzr, err := gzip.NewReader(archive)
+		testutil.IsNil(t, err)
+
+		header, err := tar.NewReader(gzr).Next()
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "compose.yml", header.Name)
+	})
+
+	t.Run("should restore a log missing from the disk and remove copies on cleanup", func(t *testing.T) {
+		storage := &memoryStorage{objects: make(map[string][]byte)}
+		manager := sutWithStorage(storage)
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+
+		ctx.Logger().Infof("building %s", "app")
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		logpath := manager.Log(context.Background(), depl).Path
+		testutil.IsNil(t, os.Remove(logpath))
+
+		testutil.Equals(t, logpath, manager.Log(context.Background(), depl).Path)
+		restored, err := os.ReadFile(logpath)
+		testutil.IsNil(t, err)
+		testutil.Contains(t, "[INFO] building app", gunzip(t, restored))
+
+		testutil.IsNil(t, manager.Cleanup(context.Background(), app.ID()))
+		testutil.Equals(t, 0, len(storage.objects))
+	})
+}
+
+type memoryStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memoryStorage) Put(_ context.Context, key string, body io.Reader, _ int64) error {
+	data, err := io.ReadAll(body)
+	s.objects[key] = data
+	return err
+}
+
+func (s *memoryStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, found := s.objects[key]
+
+	if !found {
+		return nil, os.ErrNotExist
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStorage) DeletePrefix(_ context.Context, prefix string) error {
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.objects, key)
+		}
+	}
+
+	return nil
+}
+
+type limitedOptions struct {
+	artifact.LocalOptions
+	maxLogSize int64
+}
+
+func (o limitedOptions) MaxLogSize() int64 { return o.maxLogSize }
+
+type cacheOptions struct {
+	artifact.LocalOptions
+	maxBuildCacheSize int64
+}
+
+func (o cacheOptions) BuildCacheEnabled() bool  { return true }
+func (o cacheOptions) MaxBuildCacheSize() int64 { return o.maxBuildCacheSize }
+
+// Decompress the given log and renders it as plain text.
+func gunzip(t testing.TB, data []byte) string {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	testutil.IsNil(t, err)
+
+	content, err := io.ReadAll(artifact.NewTextReader(gzr))
+	testutil.IsNil(t, err)
+
+	return string(content)
 }
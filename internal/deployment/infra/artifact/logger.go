@@ -1,45 +1,215 @@
 package artifact
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 )
 
-type stepLogger struct {
-	writer io.WriteCloser
-}
+type (
+	stepLogger struct {
+		writer    io.WriteCloser
+		limit     int64 // Maximum size of the log, 0 for no limit
+		written   int64
+		truncated bool
+		step      domain.DeploymentStep
+		pending   []byte // Raw output not terminated by a new line yet
+	}
+
+	// Line of a deployment log file as written by the step logger.
+	record struct {
+		Time    time.Time             `json:"time"`
+		Step    domain.DeploymentStep `json:"step"`
+		Level   domain.LogLevel       `json:"level"`
+		Stream  domain.LogStream      `json:"stream"`
+		Message string                `json:"message"`
+	}
+
+	// Reader rendering each record of a deployment log as a plain text line.
+	textReader struct {
+		lines   *bufio.Reader
+		pending []byte
+		err     error
+	}
+)
 
 // Instantiates a new step logger to provide a simple way to build a deployment logfile.
-func newLogger(writer io.WriteCloser) domain.DeploymentLogger {
-	return &stepLogger{writer}
+// Each message and each line of raw output is written as a JSON record on its own line.
+// Once the log reaches the given limit, the raw output written to it is discarded but
+// steps and messages are still logged so the deployment outcome is never lost.
+func newLogger(writer io.WriteCloser, limit, written int64) domain.DeploymentLogger {
+	return &stepLogger{
+		writer:  writer,
+		limit:   limit,
+		written: written,
+		step:    domain.DeploymentStepFetch,
+	}
+}
+
+func (l *stepLogger) Begin(step domain.DeploymentStep) {
+	l.flush()
+	l.step = step
 }
 
 func (l *stepLogger) Stepf(format string, args ...any) {
-	l.print("[STEP]", format, args)
+	l.print(domain.LogLevelStep, format, args)
 }
 
 func (l *stepLogger) Warnf(format string, args ...any) {
-	l.print("[WARN]", format, args)
+	l.print(domain.LogLevelWarn, format, args)
 }
 
 func (l *stepLogger) Infof(format string, args ...any) {
-	l.print("[INFO]", format, args)
+	l.print(domain.LogLevelInfo, format, args)
 }
 
 func (l *stepLogger) Error(err error) {
-	l.print("[ERROR]", err.Error(), nil)
+	l.print(domain.LogLevelError, "%s", []any{err.Error()})
 }
 
 func (l *stepLogger) Write(p []byte) (n int, err error) {
-	return l.writer.Write(p)
+	l.pending = append(l.pending, p...)
+
+	for {
+		idx := bytes.IndexByte(l.pending, '\n')
+
+		if idx < 0 {
+			break
+		}
+
+		line := l.pending[:idx]
+		l.pending = l.pending[idx+1:]
+
+		if err = l.output(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
 }
 
 func (l *stepLogger) Close() error {
+	l.flush()
 	return l.writer.Close()
 }
 
-func (l *stepLogger) print(prefix string, format string, args []any) {
-	l.Write([]byte(prefix + " " + fmt.Sprintf(format, args...) + "\n"))
+// Writes the remaining partial output line if any.
+func (l *stepLogger) flush() {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	line := l.pending
+	l.pending = nil
+	l.output(line)
+}
+
+func (l *stepLogger) output(line []byte) error {
+	data := l.encode(domain.LogLevelInfo, domain.LogStreamOutput, string(bytes.TrimSuffix(line, []byte("\r"))))
+
+	if l.limit > 0 && l.written+int64(len(data)) > l.limit {
+		if !l.truncated {
+			l.truncated = true
+			l.Warnf("log truncated since it exceeds %d bytes, the remaining output is discarded", l.limit)
+		}
+
+		return nil // Pretend it has been written to not fail the deployment
+	}
+
+	return l.write(data)
+}
+
+func (l *stepLogger) print(level domain.LogLevel, format string, args []any) {
+	l.flush()
+	l.write(l.encode(level, domain.LogStreamSeelf, fmt.Sprintf(format, args...)))
+}
+
+func (l *stepLogger) encode(level domain.LogLevel, stream domain.LogStream, message string) []byte {
+	data, _ := json.Marshal(record{
+		Time:    time.Now().UTC(),
+		Step:    l.step,
+		Level:   level,
+		Stream:  stream,
+		Message: message,
+	})
+
+	return append(data, '\n')
+}
+
+func (l *stepLogger) write(p []byte) error {
+	n, err := l.writer.Write(p)
+	l.written += int64(n)
+	return err
+}
+
+// Decodes a single line of a deployment log file. Lines which are not records, written
+// before logs were structured, are returned as raw output.
+func DecodeRecord(line []byte) domain.DeploymentLogRecord {
+	var r record
+
+	if err := json.Unmarshal(line, &r); err != nil || r.Level == "" {
+		return domain.DeploymentLogRecord{
+			Level:   domain.LogLevelInfo,
+			Stream:  domain.LogStreamOutput,
+			Message: strings.TrimSuffix(string(line), "\r"),
+		}
+	}
+
+	return domain.DeploymentLogRecord(r)
+}
+
+// Reads every record of the given deployment log.
+func ReadRecords(r io.Reader) ([]domain.DeploymentLogRecord, error) {
+	var (
+		records []domain.DeploymentLogRecord
+		reader  = bufio.NewReader(r)
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if len(line) > 0 {
+			records = append(records, DecodeRecord(bytes.TrimSuffix(line, []byte("\n"))))
+		}
+
+		if err == io.EOF {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Returns a reader rendering the given deployment log as plain text.
+func NewTextReader(r io.Reader) io.Reader {
+	return &textReader{lines: bufio.NewReader(r)}
+}
+
+func (t *textReader) Read(p []byte) (int, error) {
+	for len(t.pending) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+
+		var line []byte
+
+		line, t.err = t.lines.ReadBytes('\n')
+
+		if len(line) > 0 {
+			t.pending = []byte(DecodeRecord(bytes.TrimSuffix(line, []byte("\n"))).String() + "\n")
+		}
+	}
+
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+
+	return n, nil
 }
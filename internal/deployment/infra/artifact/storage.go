@@ -0,0 +1,251 @@
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const (
+	storageTimeout   = 10 * time.Minute
+	buildContextExt  = ".tar.gz"
+	buildContextTemp = "seelf-build-context-*" + buildContextExt
+)
+
+type (
+	// Remote storage backend where artifacts are copied once a deployment is done, so
+	// they survive the loss of the host disk. Keys use "/" as a separator.
+	Storage interface {
+		Put(ctx context.Context, key string, body io.Reader, size int64) error
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+		DeletePrefix(ctx context.Context, prefix string) error
+	}
+
+	// Writer which calls the given function once closed.
+	closeNotifier struct {
+		io.WriteCloser
+		onClose func()
+	}
+)
+
+func (w *closeNotifier) Close() error {
+	err := w.WriteCloser.Close()
+	w.onClose()
+	return err
+}
+
+func appKey(id domain.AppID) string                 { return path.Join(appsDir, string(id)) + "/" }
+func logsKey(id domain.AppID) string                { return path.Join(logsDir, string(id)) + "/" }
+func logKey(id domain.AppID, logpath string) string { return logsKey(id) + filepath.Base(logpath) }
+
+// Key of the archived build context of the given build directory.
+func (a *localArtifactManager) buildContextKey(id domain.AppID, buildDirectory string) (string, error) {
+	relative, err := filepath.Rel(a.appPath(id), buildDirectory)
+
+	if err != nil {
+		return "", err
+	}
+
+	return appKey(id) + filepath.ToSlash(relative) + buildContextExt, nil
+}
+
+// Upload the log and the build context of a deployment which has just been processed.
+func (a *localArtifactManager) store(depl domain.Deployment, logpath, buildDirectory string) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	appID := depl.ID().AppID()
+
+	if err := a.upload(ctx, logKey(appID, logpath), logpath); err != nil {
+		a.logger.Errorw("could not upload deployment log", "path", logpath, "error", err)
+	}
+
+	if buildDirectory == "" {
+		return
+	}
+
+	key, err := a.buildContextKey(appID, buildDirectory)
+
+	if err != nil {
+		a.logger.Errorw("could not determine build context key", "path", buildDirectory, "error", err)
+		return
+	}
+
+	if err = a.uploadDirectory(ctx, key, buildDirectory); err != nil {
+		a.logger.Errorw("could not upload build context", "path", buildDirectory, "error", err)
+	}
+}
+
+// Download the given log from the storage if it does not exist locally, compressed
+// or not. Logs uploaded before being compressed are still retrieved.
+func (a *localArtifactManager) restore(ctx context.Context, depl domain.Deployment, logpath string) {
+	if a.storage == nil {
+		return
+	}
+
+	for _, name := range []string{logpath, logpath + compressedExt} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			return
+		}
+	}
+
+	for _, name := range []string{logpath + compressedExt, logpath} {
+		body, err := a.storage.Get(ctx, logKey(depl.ID().AppID(), name))
+
+		if err != nil {
+			a.logger.Debugw("could not restore deployment log", "path", name, "error", err)
+			continue
+		}
+
+		err = writeFile(name, body)
+		body.Close()
+
+		if err != nil {
+			a.logger.Errorw("could not restore deployment log", "path", name, "error", err)
+		}
+
+		return
+	}
+}
+
+func (a *localArtifactManager) upload(ctx context.Context, key, name string) error {
+	file, err := os.Open(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	return a.storage.Put(ctx, key, file, info.Size())
+}
+
+// Archive the given directory in a temporary file and upload it.
+func (a *localArtifactManager) uploadDirectory(ctx context.Context, key, dir string) error {
+	tmp, err := os.CreateTemp("", buildContextTemp)
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	err = archiveDirectory(tmp, dir)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.upload(ctx, key, tmp.Name())
+}
+
+// Write a gzipped tarball of the given directory content to w.
+func archiveDirectory(w io.Writer, dir string) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || name == dir {
+			return err
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		var link string
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(name); err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil // Sockets, devices and such are not part of a build context
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(dir, name)
+
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relative)
+
+		if err = tw.WriteHeader(header); err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		file, err := os.Open(name)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gzw.Close()
+}
+
+func writeFile(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, r)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(name) // Do not leave a truncated log behind
+	}
+
+	return err
+}
@@ -0,0 +1,173 @@
+package dns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const defaultCloudflareEndpoint = "https://api.cloudflare.com/client/v4"
+
+type (
+	cloudflare struct {
+		endpoint string
+		client   *http.Client
+	}
+
+	cloudflareZone struct {
+		ID string `json:"id"`
+	}
+
+	cloudflareRecord struct {
+		ID      string `json:"id,omitempty"`
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Content string `json:"content"`
+		TTL     int    `json:"ttl"`
+		Proxied bool   `json:"proxied"`
+	}
+
+	cloudflareResponse[T any] struct {
+		Result T `json:"result"`
+	}
+)
+
+// Builds a client for zones hosted by Cloudflare, authenticated with an API token
+// allowed to edit DNS records of the zone. The endpoint defaults to the public API.
+func NewCloudflare(endpoint string) Client {
+	return &cloudflare{
+		endpoint: endpointOrDefault(endpoint, defaultCloudflareEndpoint),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (*cloudflare) CanHandle(kind domain.DnsProviderKind) bool {
+	return kind == domain.DnsProviderCloudflare
+}
+
+func (c *cloudflare) Records(ctx context.Context, zone domain.DnsZone, host string) (domain.DnsRecordSets, error) {
+	_, records, err := c.records(ctx, zone, host)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sets domain.DnsRecordSets
+
+	for _, r := range records {
+		sets = appendRecord(sets, r.Type, r.Content)
+	}
+
+	return sets, nil
+}
+
+func (c *cloudflare) SetRecords(ctx context.Context, zone domain.DnsZone, host string, set domain.DnsRecordSet) error {
+	zoneID, err := c.deleteRecords(ctx, zone, host, set.Type)
+
+	if err != nil {
+		return err
+	}
+
+	for _, value := range set.Values {
+		req, err := c.request(ctx, zone, http.MethodPost, "/zones/"+zoneID+"/dns_records", cloudflareRecord{
+			Type:    set.Type,
+			Name:    host,
+			Content: value,
+			TTL:     1, // Automatic
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if err = sendJSON(c.client, req, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *cloudflare) DeleteRecords(ctx context.Context, zone domain.DnsZone, host string, recordType string) error {
+	_, err := c.deleteRecords(ctx, zone, host, recordType)
+	return err
+}
+
+// Removes records of the given type and returns the zone identifier for convenience.
+func (c *cloudflare) deleteRecords(ctx context.Context, zone domain.DnsZone, host string, recordType string) (string, error) {
+	zoneID, records, err := c.records(ctx, zone, host)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range records {
+		if r.Type != recordType {
+			continue
+		}
+
+		req, err := c.request(ctx, zone, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+r.ID, nil)
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = sendJSON(c.client, req, nil); err != nil {
+			return "", err
+		}
+	}
+
+	return zoneID, nil
+}
+
+// Retrieve the zone identifier and records of the given host.
+func (c *cloudflare) records(ctx context.Context, zone domain.DnsZone, host string) (string, []cloudflareRecord, error) {
+	req, err := c.request(ctx, zone, http.MethodGet, "/zones?"+url.Values{"name": {zone.Name()}}.Encode(), nil)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var zones cloudflareResponse[[]cloudflareZone]
+
+	if err = sendJSON(c.client, req, &zones); err != nil {
+		return "", nil, err
+	}
+
+	if len(zones.Result) == 0 {
+		return "", nil, ErrZoneNotFound
+	}
+
+	zoneID := zones.Result[0].ID
+
+	req, err = c.request(ctx, zone, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+url.Values{
+		"name":     {host},
+		"per_page": {"100"},
+	}.Encode(), nil)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var records cloudflareResponse[[]cloudflareRecord]
+
+	if err = sendJSON(c.client, req, &records); err != nil {
+		return "", nil, err
+	}
+
+	return zoneID, records.Result, nil
+}
+
+func (c *cloudflare) request(ctx context.Context, zone domain.DnsZone, method, path string, body any) (*http.Request, error) {
+	req, err := newJSONRequest(ctx, method, c.endpoint+path, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+zone.Credentials().Token)
+
+	return req, nil
+}
@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const (
+	defaultDesecEndpoint = "https://desec.io/api/v1"
+	desecTTL             = 3600 // Minimum TTL accepted by deSEC
+)
+
+type (
+	desec struct {
+		endpoint string
+		client   *http.Client
+	}
+
+	desecRRSet struct {
+		Subname string   `json:"subname"`
+		Type    string   `json:"type"`
+		TTL     int      `json:"ttl,omitempty"`
+		Records []string `json:"records"`
+	}
+)
+
+// Builds a client for domains hosted by deSEC, authenticated with an API token. The
+// endpoint defaults to the public API.
+func NewDesec(endpoint string) Client {
+	return &desec{
+		endpoint: endpointOrDefault(endpoint, defaultDesecEndpoint),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (*desec) CanHandle(kind domain.DnsProviderKind) bool {
+	return kind == domain.DnsProviderDesec
+}
+
+func (d *desec) Records(ctx context.Context, zone domain.DnsZone, host string) (domain.DnsRecordSets, error) {
+	req, err := d.request(ctx, zone, http.MethodGet, "/rrsets/?"+url.Values{"subname": {subname(zone, host)}}.Encode(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rrsets []desecRRSet
+
+	if err = sendJSON(d.client, req, &rrsets); err != nil {
+		return nil, err
+	}
+
+	var sets domain.DnsRecordSets
+
+	for _, rrset := range rrsets {
+		for _, value := range rrset.Records {
+			sets = appendRecord(sets, rrset.Type, value)
+		}
+	}
+
+	return sets, nil
+}
+
+func (d *desec) SetRecords(ctx context.Context, zone domain.DnsZone, host string, set domain.DnsRecordSet) error {
+	records := make([]string, len(set.Values))
+
+	for i, value := range set.Values {
+		// deSEC expects fully qualified names
+		if set.Type == domain.DnsRecordCNAME && !strings.HasSuffix(value, ".") {
+			value += "."
+		}
+
+		records[i] = value
+	}
+
+	return d.patch(ctx, zone, desecRRSet{
+		Subname: subname(zone, host),
+		Type:    set.Type,
+		TTL:     desecTTL,
+		Records: records,
+	})
+}
+
+func (d *desec) DeleteRecords(ctx context.Context, zone domain.DnsZone, host string, recordType string) error {
+	// An empty list of records removes the whole set
+	return d.patch(ctx, zone, desecRRSet{
+		Subname: subname(zone, host),
+		Type:    recordType,
+		Records: []string{},
+	})
+}
+
+func (d *desec) patch(ctx context.Context, zone domain.DnsZone, rrset desecRRSet) error {
+	req, err := d.request(ctx, zone, http.MethodPatch, "/rrsets/", []desecRRSet{rrset})
+
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(d.client, req, nil)
+}
+
+func (d *desec) request(ctx context.Context, zone domain.DnsZone, method, path string, body any) (*http.Request, error) {
+	req, err := newJSONRequest(ctx, method, d.endpoint+"/domains/"+zone.Name()+path, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Token "+zone.Credentials().Token)
+
+	return req, nil
+}
+
+// Retrieve the part of the host relative to the zone, empty for the zone apex.
+func subname(zone domain.DnsZone, host string) string {
+	if host == zone.Name() {
+		return ""
+	}
+
+	return strings.TrimSuffix(host, "."+zone.Name())
+}
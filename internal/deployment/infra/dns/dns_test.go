@@ -0,0 +1,187 @@
+package dns_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/dns"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Dns(t *testing.T) {
+	ctx := context.Background()
+	zone := func(provider domain.DnsProviderKind, credentials domain.DnsCredentials) domain.DnsZone {
+		return must.Panic(domain.NewDnsZone(domain.NewDnsZoneNameRequirement("example.com", true), provider, credentials, nil, "uid"))
+	}
+
+	serve := func(t *testing.T, handler http.HandlerFunc) string {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	t.Run("should fail for unsupported providers", func(t *testing.T) {
+		facade := dns.NewFacade(dns.NewCloudflare(""))
+
+		_, err := facade.Records(ctx, zone(domain.DnsProviderDesec, domain.DnsCredentials{Token: "a token"}), "my-app.example.com")
+
+		testutil.ErrorIs(t, domain.ErrInvalidDnsProvider, err)
+	})
+
+	t.Run("should manage records of a Cloudflare zone", func(t *testing.T) {
+		var (
+			deleted []string
+			created []map[string]any
+		)
+
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "Bearer a token", r.Header.Get("Authorization"))
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/zones":
+				testutil.Equals(t, "example.com", r.URL.Query().Get("name"))
+				w.Write([]byte(`{"result":[{"id":"zone-id"}]}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/zones/zone-id/dns_records":
+				testutil.Equals(t, "my-app.example.com", r.URL.Query().Get("name"))
+				w.Write([]byte(`{"result":[
+					{"id":"1","type":"CNAME","name":"my-app.example.com","content":"docker.example.com"},
+					{"id":"2","type":"TXT","name":"my-app.example.com","content":"verification"}
+				]}`))
+			case r.Method == http.MethodDelete:
+				deleted = append(deleted, r.URL.Path)
+				w.Write([]byte(`{}`))
+			case r.Method == http.MethodPost && r.URL.Path == "/zones/zone-id/dns_records":
+				var body map[string]any
+				json.NewDecoder(r.Body).Decode(&body)
+				created = append(created, body)
+				w.Write([]byte(`{}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		client := dns.NewCloudflare(url)
+		z := zone(domain.DnsProviderCloudflare, domain.DnsCredentials{Token: "a token"})
+
+		sets, err := client.Records(ctx, z, "my-app.example.com")
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordCNAME, Values: []string{"docker.example.com"}},
+		}, sets)
+
+		err = client.SetRecords(ctx, z, "my-app.example.com", domain.DnsRecordSet{
+			Type:   domain.DnsRecordCNAME,
+			Values: []string{"other.example.com"},
+		})
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"/zones/zone-id/dns_records/1"}, deleted)
+		testutil.HasLength(t, created, 1)
+		testutil.Equals(t, "CNAME", created[0]["type"].(string))
+		testutil.Equals(t, "my-app.example.com", created[0]["name"].(string))
+		testutil.Equals(t, "other.example.com", created[0]["content"].(string))
+	})
+
+	t.Run("should fail if the Cloudflare zone does not exist", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"result":[]}`))
+		})
+		client := dns.NewCloudflare(url)
+
+		_, err := client.Records(ctx, zone(domain.DnsProviderCloudflare, domain.DnsCredentials{Token: "a token"}), "my-app.example.com")
+
+		testutil.ErrorIs(t, dns.ErrZoneNotFound, err)
+	})
+
+	t.Run("should manage records of a deSEC domain", func(t *testing.T) {
+		var patches []string
+
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "Token a token", r.Header.Get("Authorization"))
+			testutil.Equals(t, "/domains/example.com/rrsets/", r.URL.Path)
+
+			switch r.Method {
+			case http.MethodGet:
+				testutil.Equals(t, "my-app", r.URL.Query().Get("subname"))
+				w.Write([]byte(`[
+					{"subname":"my-app","type":"A","ttl":3600,"records":["203.0.113.10","203.0.113.11"]},
+					{"subname":"my-app","type":"MX","ttl":3600,"records":["10 mail.example.com."]}
+				]`))
+			case http.MethodPatch:
+				body, _ := io.ReadAll(r.Body)
+				patches = append(patches, string(body))
+				w.Write([]byte(`[]`))
+			}
+		})
+		client := dns.NewDesec(url)
+		z := zone(domain.DnsProviderDesec, domain.DnsCredentials{Token: "a token"})
+
+		sets, err := client.Records(ctx, z, "my-app.example.com")
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordA, Values: []string{"203.0.113.10", "203.0.113.11"}},
+		}, sets)
+
+		testutil.IsNil(t, client.DeleteRecords(ctx, z, "my-app.example.com", domain.DnsRecordA))
+		testutil.IsNil(t, client.SetRecords(ctx, z, "my-app.example.com", domain.DnsRecordSet{
+			Type:   domain.DnsRecordCNAME,
+			Values: []string{"docker.example.com"},
+		}))
+
+		testutil.DeepEquals(t, []string{
+			`[{"subname":"my-app","type":"A","records":[]}]`,
+			`[{"subname":"my-app","type":"CNAME","ttl":3600,"records":["docker.example.com."]}]`,
+		}, patches)
+	})
+
+	t.Run("should manage records of a Route53 hosted zone", func(t *testing.T) {
+		var changes []string
+
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Contains(t, "Credential=access/", r.Header.Get("Authorization"))
+			testutil.Contains(t, "/us-east-1/route53/aws4_request", r.Header.Get("Authorization"))
+
+			switch {
+			case r.URL.Path == "/2013-04-01/hostedzonesbyname":
+				testutil.Equals(t, "example.com.", r.URL.Query().Get("dnsname"))
+				w.Write([]byte(`<ListHostedZonesByNameResponse><HostedZones><HostedZone><Id>/hostedzone/Z123</Id><Name>example.com.</Name></HostedZone></HostedZones></ListHostedZonesByNameResponse>`))
+			case r.Method == http.MethodGet && r.URL.Path == "/2013-04-01/hostedzone/Z123/rrset":
+				testutil.Equals(t, "my-app.example.com.", r.URL.Query().Get("name"))
+				w.Write([]byte(`<ListResourceRecordSetsResponse><ResourceRecordSets>
+					<ResourceRecordSet><Name>my-app.example.com.</Name><Type>AAAA</Type><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>2001:db8::1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+					<ResourceRecordSet><Name>other.example.com.</Name><Type>A</Type><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>203.0.113.10</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+				</ResourceRecordSets></ListResourceRecordSetsResponse>`))
+			case r.Method == http.MethodPost && r.URL.Path == "/2013-04-01/hostedzone/Z123/rrset/":
+				body, _ := io.ReadAll(r.Body)
+				changes = append(changes, string(body))
+				w.Write([]byte(`<ChangeResourceRecordSetsResponse></ChangeResourceRecordSetsResponse>`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		client := dns.NewRoute53(url)
+		z := zone(domain.DnsProviderRoute53, domain.DnsCredentials{AccessKey: "access", SecretKey: "secret"})
+
+		sets, err := client.Records(ctx, z, "my-app.example.com")
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.DnsRecordSets{
+			{Type: domain.DnsRecordAAAA, Values: []string{"2001:db8::1"}},
+		}, sets)
+
+		testutil.IsNil(t, client.DeleteRecords(ctx, z, "my-app.example.com", domain.DnsRecordAAAA))
+		testutil.IsNil(t, client.SetRecords(ctx, z, "my-app.example.com", domain.DnsRecordSet{
+			Type:   domain.DnsRecordA,
+			Values: []string{"203.0.113.10"},
+		}))
+
+		testutil.HasLength(t, changes, 2)
+		testutil.Contains(t, "<Action>DELETE</Action>", changes[0])
+		testutil.Contains(t, "<TTL>60</TTL>", changes[0])
+		testutil.Contains(t, "<Action>UPSERT</Action>", changes[1])
+		testutil.Contains(t, "<Name>my-app.example.com.</Name><Type>A</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>203.0.113.10</Value></ResourceRecord></ResourceRecords>", changes[1])
+	})
+}
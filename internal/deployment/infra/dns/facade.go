@@ -0,0 +1,174 @@
+// Package dns manages records of zones hosted by DNS providers (Cloudflare, deSEC,
+// AWS Route53) through their HTTP API.
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const timeout = 30 * time.Second
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected_status")
+	ErrZoneNotFound     = errors.New("dns_zone_not_found")
+)
+
+type (
+	// Client of a specific DNS provider.
+	Client interface {
+		domain.DnsProvider
+		CanHandle(domain.DnsProviderKind) bool
+	}
+
+	facade struct {
+		clients []Client
+	}
+)
+
+// Creates a new facade which will call the appropriate client based on the provider
+// of the zone.
+func NewFacade(clients ...Client) domain.DnsProvider {
+	return &facade{clients}
+}
+
+func (f *facade) Records(ctx context.Context, zone domain.DnsZone, host string) (domain.DnsRecordSets, error) {
+	client, err := f.clientOf(zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Records(ctx, zone, host)
+}
+
+func (f *facade) SetRecords(ctx context.Context, zone domain.DnsZone, host string, set domain.DnsRecordSet) error {
+	client, err := f.clientOf(zone)
+
+	if err != nil {
+		return err
+	}
+
+	return client.SetRecords(ctx, zone, host, set)
+}
+
+func (f *facade) DeleteRecords(ctx context.Context, zone domain.DnsZone, host string, recordType string) error {
+	client, err := f.clientOf(zone)
+
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteRecords(ctx, zone, host, recordType)
+}
+
+func (f *facade) clientOf(zone domain.DnsZone) (Client, error) {
+	for _, c := range f.clients {
+		if c.CanHandle(zone.Provider()) {
+			return c, nil
+		}
+	}
+
+	return nil, domain.ErrInvalidDnsProvider
+}
+
+// Builds a request with an optional JSON body.
+func newJSONRequest(ctx context.Context, method, url string, body any) (*http.Request, error) {
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// Sends the request and returns the response if successful. The caller must close it.
+func send(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, detail)
+}
+
+// Sends the request and decodes the JSON response in the given target if not nil.
+func sendJSON(client *http.Client, req *http.Request, target any) error {
+	resp, err := send(client, req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if target == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Appends a record value to the set of the given type, ignoring unmanaged types.
+func appendRecord(sets domain.DnsRecordSets, recordType, value string) domain.DnsRecordSets {
+	if !slices.Contains(domain.DnsRecordTypes, recordType) {
+		return sets
+	}
+
+	value = strings.TrimSuffix(value, ".")
+
+	for i := range sets {
+		if sets[i].Type == recordType {
+			sets[i].Values = append(sets[i].Values, value)
+			return sets
+		}
+	}
+
+	return append(sets, domain.DnsRecordSet{Type: recordType, Values: []string{value}})
+}
+
+func endpointOrDefault(endpoint, fallback string) string {
+	if endpoint == "" {
+		endpoint = fallback
+	}
+
+	return strings.TrimSuffix(endpoint, "/")
+}
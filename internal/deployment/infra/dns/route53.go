@@ -0,0 +1,238 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	defaultRoute53Endpoint = "https://route53.amazonaws.com"
+	route53Service         = "route53"
+	route53Region          = "us-east-1" // Route53 is a global service signed for this region
+	route53Namespace       = "https://route53.amazonaws.com/doc/2013-04-01/"
+	route53TTL             = 300
+	route53ActionUpsert    = "UPSERT"
+	route53ActionDelete    = "DELETE"
+)
+
+type (
+	route53 struct {
+		endpoint string
+		client   *http.Client
+		signer   *v4.Signer
+	}
+
+	route53HostedZones struct {
+		HostedZones []struct {
+			ID   string `xml:"Id"`
+			Name string `xml:"Name"`
+		} `xml:"HostedZones>HostedZone"`
+	}
+
+	route53RecordSets struct {
+		RecordSets []route53RecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+	}
+
+	route53RecordSet struct {
+		Name    string   `xml:"Name"`
+		Type    string   `xml:"Type"`
+		TTL     int      `xml:"TTL,omitempty"`
+		Records []string `xml:"ResourceRecords>ResourceRecord>Value"`
+	}
+
+	route53ChangeRequest struct {
+		XMLName xml.Name        `xml:"ChangeResourceRecordSetsRequest"`
+		Xmlns   string          `xml:"xmlns,attr"`
+		Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+	}
+
+	route53Change struct {
+		Action    string           `xml:"Action"`
+		RecordSet route53RecordSet `xml:"ResourceRecordSet"`
+	}
+)
+
+// Builds a client for hosted zones of AWS Route53, authenticated with an access key
+// allowed to list and change record sets. The endpoint defaults to the public API.
+func NewRoute53(endpoint string) Client {
+	return &route53{
+		endpoint: endpointOrDefault(endpoint, defaultRoute53Endpoint),
+		client:   &http.Client{Timeout: timeout},
+		signer:   v4.NewSigner(),
+	}
+}
+
+func (*route53) CanHandle(kind domain.DnsProviderKind) bool {
+	return kind == domain.DnsProviderRoute53
+}
+
+func (r *route53) Records(ctx context.Context, zone domain.DnsZone, host string) (domain.DnsRecordSets, error) {
+	_, recordSets, err := r.records(ctx, zone, host)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sets domain.DnsRecordSets
+
+	for _, rs := range recordSets {
+		for _, value := range rs.Records {
+			sets = appendRecord(sets, rs.Type, value)
+		}
+	}
+
+	return sets, nil
+}
+
+func (r *route53) SetRecords(ctx context.Context, zone domain.DnsZone, host string, set domain.DnsRecordSet) error {
+	zoneID, err := r.hostedZoneID(ctx, zone)
+
+	if err != nil {
+		return err
+	}
+
+	return r.change(ctx, zone, zoneID, route53Change{
+		Action: route53ActionUpsert,
+		RecordSet: route53RecordSet{
+			Name:    fqdn(host),
+			Type:    set.Type,
+			TTL:     route53TTL,
+			Records: set.Values,
+		},
+	})
+}
+
+func (r *route53) DeleteRecords(ctx context.Context, zone domain.DnsZone, host string, recordType string) error {
+	zoneID, recordSets, err := r.records(ctx, zone, host)
+
+	if err != nil {
+		return err
+	}
+
+	// Deletions must match the existing set exactly, including its TTL
+	for _, rs := range recordSets {
+		if rs.Type != recordType {
+			continue
+		}
+
+		return r.change(ctx, zone, zoneID, route53Change{
+			Action:    route53ActionDelete,
+			RecordSet: rs,
+		})
+	}
+
+	return nil
+}
+
+// Retrieve the hosted zone identifier and record sets of the given host.
+func (r *route53) records(ctx context.Context, zone domain.DnsZone, host string) (string, []route53RecordSet, error) {
+	zoneID, err := r.hostedZoneID(ctx, zone)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var output route53RecordSets
+
+	// Record sets are listed in order starting from the given name so only keep the
+	// ones of the host.
+	if err = r.send(ctx, zone, http.MethodGet, "/2013-04-01/hostedzone/"+zoneID+"/rrset?"+url.Values{
+		"name":     {fqdn(host)},
+		"maxitems": {"10"},
+	}.Encode(), nil, &output); err != nil {
+		return "", nil, err
+	}
+
+	var recordSets []route53RecordSet
+
+	for _, rs := range output.RecordSets {
+		if strings.EqualFold(rs.Name, fqdn(host)) {
+			recordSets = append(recordSets, rs)
+		}
+	}
+
+	return zoneID, recordSets, nil
+}
+
+func (r *route53) hostedZoneID(ctx context.Context, zone domain.DnsZone) (string, error) {
+	var output route53HostedZones
+
+	if err := r.send(ctx, zone, http.MethodGet, "/2013-04-01/hostedzonesbyname?"+url.Values{
+		"dnsname":  {fqdn(zone.Name())},
+		"maxitems": {"1"},
+	}.Encode(), nil, &output); err != nil {
+		return "", err
+	}
+
+	if len(output.HostedZones) == 0 || !strings.EqualFold(output.HostedZones[0].Name, fqdn(zone.Name())) {
+		return "", ErrZoneNotFound
+	}
+
+	return strings.TrimPrefix(output.HostedZones[0].ID, "/hostedzone/"), nil
+}
+
+func (r *route53) change(ctx context.Context, zone domain.DnsZone, zoneID string, change route53Change) error {
+	body, err := xml.Marshal(route53ChangeRequest{
+		Xmlns:   route53Namespace,
+		Changes: []route53Change{change},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return r.send(ctx, zone, http.MethodPost, "/2013-04-01/hostedzone/"+zoneID+"/rrset/", body, nil)
+}
+
+// Sends a signed request to the Route53 API and decodes the XML response in the
+// given target if not nil.
+func (r *route53) send(ctx context.Context, zone domain.DnsZone, method, path string, body []byte, target any) error {
+	req, err := http.NewRequestWithContext(ctx, method, r.endpoint+path, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	hash := sha256.Sum256(body)
+	credentials := zone.Credentials()
+
+	if err = r.signer.SignHTTP(ctx, aws.Credentials{
+		AccessKeyID:     credentials.AccessKey,
+		SecretAccessKey: credentials.SecretKey,
+	}, req, hex.EncodeToString(hash[:]), route53Service, route53Region, time.Now()); err != nil {
+		return err
+	}
+
+	resp, err := send(r.client, req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if target == nil {
+		return nil
+	}
+
+	return xml.NewDecoder(resp.Body).Decode(target)
+}
+
+func fqdn(host string) string {
+	return strings.TrimSuffix(host, ".") + "."
+}
@@ -0,0 +1,77 @@
+// Package federation retrieves data exposed by the API of other seelf instances
+// registered as peers.
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_federated_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const timeout = 10 * time.Second
+
+var ErrUnexpectedStatus = errors.New("unexpected_status")
+
+type client struct {
+	client *http.Client
+}
+
+// Builds a client calling the API of peers authenticated with their token. A peer
+// slow to respond does not hold the whole federated listing for too long.
+func NewClient() get_federated_apps.Client {
+	return &client{&http.Client{Timeout: timeout}}
+}
+
+func (c *client) GetApps(ctx context.Context, peer domain.Peer, labels []string) ([]get_apps.App, error) {
+	query := url.Values{}
+
+	for _, label := range labels {
+		query.Add("labels", label)
+	}
+
+	endpoint := strings.TrimSuffix(peer.Url().String(), "/") + "/api/v1/apps"
+
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+peer.Token())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, detail)
+	}
+
+	var apps []get_apps.App
+
+	if err = json.NewDecoder(resp.Body).Decode(&apps); err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,50 @@
+package federation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/federation"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Client(t *testing.T) {
+	serve := func(t *testing.T, handler http.HandlerFunc) domain.Url {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return must.Panic(domain.UrlFrom(srv.URL))
+	}
+
+	t.Run("should retrieve apps of a peer", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "/api/v1/apps", r.URL.Path)
+			testutil.DeepEquals(t, []string{"team-a", "stack:go"}, r.URL.Query()["labels"])
+			testutil.Equals(t, "Bearer a token", r.Header.Get("Authorization"))
+
+			w.Write([]byte(`[{"id":"an-app","name":"my-app","labels":["team-a","stack:go"]}]`))
+		})
+		peer := domain.NewPeer("vps", url, "a token", "uid")
+
+		apps, err := federation.NewClient().GetApps(context.Background(), peer, []string{"team-a", "stack:go"})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, apps, 1)
+		testutil.Equals(t, "an-app", apps[0].ID)
+		testutil.Equals(t, "my-app", apps[0].Name)
+	})
+
+	t.Run("should fail on unexpected status", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		peer := domain.NewPeer("vps", url, "an invalid token", "uid")
+
+		_, err := federation.NewClient().GetApps(context.Background(), peer, nil)
+
+		testutil.ErrorIs(t, federation.ErrUnexpectedStatus, err)
+	})
+}
@@ -0,0 +1,175 @@
+// Package gitops watches a git repository describing targets and apps of the
+// instance and reconciles them with it.
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+var (
+	ErrRemoteNotReachable = errors.New("gitops_remote_not_reachable")
+	ErrBranchNotFound     = errors.New("gitops_branch_not_found")
+	ErrCloneFailed        = errors.New("gitops_clone_failed")
+	ErrSpecNotFound       = errors.New("gitops_spec_not_found")
+)
+
+const basicAuthUser = "seelf"
+
+type (
+	// Repository watched for changes.
+	Options struct {
+		Url      string
+		Branch   string
+		Path     string // Path of the spec file in the repository
+		Token    monad.Maybe[string]
+		Prune    bool          // Delete targets and apps which are not part of the spec
+		Interval time.Duration // How often the branch is checked for new commits
+	}
+
+	repository struct {
+		opts       Options
+		logger     log.Logger
+		dispatcher bus.Dispatcher
+	}
+
+	disabled struct{}
+)
+
+// Builds a new GitOps repository. Reconciliations are made by sending the same commands
+// as users would through the given dispatcher so every rule still applies.
+func New(opts monad.Maybe[Options], logger log.Logger, dispatcher bus.Dispatcher) domain.GitOpsRepository {
+	options, isSet := opts.TryGet()
+
+	if !isSet {
+		return disabled{}
+	}
+
+	return &repository{
+		opts:       options,
+		logger:     logger,
+		dispatcher: dispatcher,
+	}
+}
+
+func (r *repository) LatestCommit(ctx context.Context) (string, error) {
+	branchRef := plumbing.NewBranchReferenceName(r.opts.Branch)
+	refs, err := git.NewRemote(nil, &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{r.opts.Url},
+	}).ListContext(ctx, &git.ListOptions{
+		Auth: r.auth(),
+	})
+
+	if err != nil {
+		r.logger.Errorw("could not list gitops repository references",
+			"url", r.opts.Url,
+			"error", err)
+		return "", ErrRemoteNotReachable
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == branchRef {
+			return ref.Hash().String(), nil
+		}
+	}
+
+	return "", ErrBranchNotFound
+}
+
+func (r *repository) Apply(ctx context.Context) (string, domain.SpecChanges, error) {
+	// The spec is all we need so the branch head is cloned in memory without a worktree
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           r.opts.Url,
+		Auth:          r.auth(),
+		ReferenceName: plumbing.NewBranchReferenceName(r.opts.Branch),
+		SingleBranch:  true,
+		Depth:         1,
+	})
+
+	if err != nil {
+		r.logger.Errorw("could not clone gitops repository",
+			"url", r.opts.Url,
+			"error", err)
+		return "", nil, ErrCloneFailed
+	}
+
+	head, err := repo.Head()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	commit := head.Hash().String()
+
+	content, err := readFile(repo, head.Hash(), r.opts.Path)
+
+	if err != nil {
+		return commit, nil, err
+	}
+
+	spec, err := Parse(content)
+
+	if err != nil {
+		return commit, nil, fmt.Errorf("%s: %w", r.opts.Path, err)
+	}
+
+	changes, err := reconcile(ctx, r.dispatcher, spec, r.opts.Prune)
+
+	return commit, changes, err
+}
+
+func (r *repository) auth() transport.AuthMethod {
+	if token, isSet := r.opts.Token.TryGet(); isSet {
+		return &http.BasicAuth{
+			Username: basicAuthUser,
+			Password: token,
+		}
+	}
+
+	return nil
+}
+
+func readFile(repo *git.Repository, hash plumbing.Hash, path string) ([]byte, error) {
+	commit, err := repo.CommitObject(hash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := commit.File(path)
+
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, ErrSpecNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := file.Contents()
+
+	return []byte(content), err
+}
+
+func (disabled) LatestCommit(context.Context) (string, error) {
+	return "", domain.ErrGitOpsNotConfigured
+}
+
+func (disabled) Apply(context.Context) (string, domain.SpecChanges, error) {
+	return "", nil, domain.ErrGitOpsNotConfigured
+}
@@ -0,0 +1,379 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_target_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_target"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrUnknownTarget = errors.New("unknown target")
+
+const (
+	targetResource = "target"
+	appResource    = "app"
+
+	// Same defaults as the docker provider, needed to know if a target has changed.
+	defaultDockerUser = "docker"
+	defaultDockerPort = 22
+)
+
+type reconciler struct {
+	dispatcher bus.Dispatcher
+	targets    map[string]string // Target ids by name
+	changes    domain.SpecChanges
+}
+
+// Creates, updates and, if prune is set, deletes targets and apps to match the given spec.
+// It stops at the first error and returns changes made so far.
+func reconcile(ctx context.Context, dispatcher bus.Dispatcher, spec Spec, prune bool) (domain.SpecChanges, error) {
+	r := &reconciler{
+		dispatcher: dispatcher,
+		targets:    make(map[string]string),
+	}
+
+	err := r.run(ctx, spec, prune)
+
+	return r.changes, err
+}
+
+func (r *reconciler) run(ctx context.Context, spec Spec, prune bool) error {
+	targets, err := bus.Send(r.dispatcher, ctx, get_targets.Query{})
+
+	if err != nil {
+		return err
+	}
+
+	apps, err := bus.Send(r.dispatcher, ctx, get_apps.Query{})
+
+	if err != nil {
+		return err
+	}
+
+	// Resources being deleted are ignored so they will be created again if needed
+	existingTargets := make(map[string]get_target.Target, len(targets))
+
+	for _, t := range targets {
+		if !t.CleanupRequestedAt.HasValue() {
+			existingTargets[t.Name] = t
+			r.targets[t.Name] = t.ID
+		}
+	}
+
+	existingApps := make(map[string]string, len(apps))
+
+	for _, a := range apps {
+		if !a.CleanupRequestedAt.HasValue() {
+			existingApps[a.Name] = a.ID
+		}
+	}
+
+	for _, t := range spec.Targets {
+		existing, found := existingTargets[t.Name]
+
+		if err = r.target(ctx, t, existing, found); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+
+		delete(existingTargets, t.Name)
+	}
+
+	for _, a := range spec.Apps {
+		id, found := existingApps[a.Name]
+
+		if err = r.app(ctx, a, id, found); err != nil {
+			return fmt.Errorf("app %s: %w", a.Name, err)
+		}
+
+		delete(existingApps, a.Name)
+	}
+
+	if !prune {
+		return nil
+	}
+
+	// Apps are removed first since a target can not be deleted while still in use
+	for name, id := range existingApps {
+		if _, err = bus.Send(r.dispatcher, ctx, request_app_cleanup.Command{ID: id}); err != nil {
+			return fmt.Errorf("app %s: %w", name, err)
+		}
+
+		r.record(appResource, name, domain.SpecResourceDeleted)
+	}
+
+	for name, t := range existingTargets {
+		if _, err = bus.Send(r.dispatcher, ctx, request_target_cleanup.Command{ID: t.ID}); err != nil {
+			return fmt.Errorf("target %s: %w", name, err)
+		}
+
+		r.record(targetResource, name, domain.SpecResourceDeleted)
+	}
+
+	return nil
+}
+
+func (r *reconciler) target(ctx context.Context, t Target, existing get_target.Target, found bool) error {
+	provider := docker.Body{}
+
+	if t.Docker.Host != "" {
+		provider.Host.Set(t.Docker.Host)
+	}
+
+	if t.Docker.Port != 0 {
+		provider.Port.Set(t.Docker.Port)
+	}
+
+	if t.Docker.User != "" {
+		provider.User.Set(t.Docker.User)
+	}
+
+	if t.Docker.PrivateKey != "" {
+		provider.PrivateKey = monad.PatchValue(t.Docker.PrivateKey)
+	}
+
+	if !found {
+		id, err := bus.Send(r.dispatcher, ctx, create_target.Command{
+			Name:     t.Name,
+			Url:      t.Url,
+			Provider: provider,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		r.targets[t.Name] = id
+		r.record(targetResource, t.Name, domain.SpecResourceCreated)
+
+		return nil
+	}
+
+	// Updating a target triggers its reconfiguration so only do it when needed
+	if current, isDocker := existing.Provider.Data.(docker.QueryProviderConfig); isDocker &&
+		existing.Url == t.Url &&
+		t.Docker.Matches(current.Host.Get(""), current.Port.Get(0), current.User.Get("")) &&
+		(t.Docker.PrivateKey == "" || current.PrivateKey.Get("") == storage.SecretString(t.Docker.PrivateKey)) {
+		return nil
+	}
+
+	if _, err := bus.Send(r.dispatcher, ctx, update_target.Command{
+		ID:       existing.ID,
+		Name:     monad.Value(t.Name),
+		Url:      monad.Value(t.Url),
+		Provider: provider,
+	}); err != nil {
+		return err
+	}
+
+	r.record(targetResource, t.Name, domain.SpecResourceUpdated)
+
+	return nil
+}
+
+func (r *reconciler) app(ctx context.Context, a App, id string, found bool) error {
+	production, err := r.environment(a.Production)
+
+	if err != nil {
+		return err
+	}
+
+	staging, err := r.environment(a.Staging)
+
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		cmd := create_app.Command{
+			Name:       a.Name,
+			Production: production,
+			Staging:    staging,
+		}
+
+		if vcs := a.VersionControl; vcs != nil {
+			config := create_app.VersionControl{Url: vcs.Url}
+
+			if vcs.Token != "" {
+				config.Token.Set(vcs.Token)
+			}
+
+			cmd.VersionControl.Set(config)
+		}
+
+		if _, err = bus.Send(r.dispatcher, ctx, cmd); err != nil {
+			return err
+		}
+
+		r.record(appResource, a.Name, domain.SpecResourceCreated)
+
+		return nil
+	}
+
+	current, err := bus.Send(r.dispatcher, ctx, get_app_detail.Query{ID: id})
+
+	if err != nil {
+		return err
+	}
+
+	if a.VersionControl.matches(current.VersionControl) &&
+		a.Production.matches(production.Target, current.Production) &&
+		a.Staging.matches(staging.Target, current.Staging) {
+		return nil
+	}
+
+	cmd := update_app.Command{
+		ID:         id,
+		Production: monad.Value(update_app.EnvironmentConfig(production)),
+		Staging:    monad.Value(update_app.EnvironmentConfig(staging)),
+	}
+
+	if vcs := a.VersionControl; vcs != nil {
+		config := update_app.VersionControl{Url: vcs.Url}
+
+		if vcs.Token != "" {
+			config.Token = monad.PatchValue(vcs.Token)
+		}
+
+		cmd.VersionControl = monad.PatchValue(config)
+	} else {
+		cmd.VersionControl = monad.Nil[update_app.VersionControl]()
+	}
+
+	if _, err = bus.Send(r.dispatcher, ctx, cmd); err != nil {
+		return err
+	}
+
+	r.record(appResource, a.Name, domain.SpecResourceUpdated)
+
+	return nil
+}
+
+func (r *reconciler) environment(env Environment) (config create_app.EnvironmentConfig, err error) {
+	id, found := r.targets[env.Target]
+
+	if !found {
+		return config, fmt.Errorf("%w %s", ErrUnknownTarget, env.Target)
+	}
+
+	config.Target = id
+
+	if len(env.Vars) > 0 {
+		config.Vars.Set(env.Vars)
+	}
+
+	if len(env.Exposure) > 0 {
+		exposure := make(map[string]create_app.ServiceExposure, len(env.Exposure))
+
+		for service, e := range env.Exposure {
+			var value create_app.ServiceExposure
+
+			value.Disabled = e.Disabled
+
+			if e.Port != 0 {
+				value.Port.Set(e.Port)
+			}
+
+			if e.Subdomain != "" {
+				value.Subdomain.Set(e.Subdomain)
+			}
+
+			if e.PathPrefix != "" {
+				value.PathPrefix.Set(e.PathPrefix)
+			}
+
+			for _, port := range e.Ports {
+				exposed := create_app.ExposedPort{Router: port.Router, Port: port.Port}
+
+				if port.Published != 0 {
+					exposed.Published.Set(port.Published)
+				}
+
+				value.Ports = append(value.Ports, exposed)
+			}
+
+			exposure[service] = value
+		}
+
+		config.Exposure.Set(exposure)
+	}
+
+	return config, nil
+}
+
+func (r *reconciler) record(resource, name string, kind domain.SpecChangeKind) {
+	r.changes = append(r.changes, domain.SpecChange{
+		Resource: resource,
+		Name:     name,
+		Kind:     kind,
+	})
+}
+
+// Checks if the docker settings match the current ones of a target, applying the
+// provider defaults. The private key could not be checked here since it is never exposed.
+func (d Docker) Matches(host string, port int, user string) bool {
+	if d.Host == "" {
+		return host == ""
+	}
+
+	expectedPort := d.Port
+
+	if expectedPort == 0 {
+		expectedPort = defaultDockerPort
+	}
+
+	expectedUser := d.User
+
+	if expectedUser == "" {
+		expectedUser = defaultDockerUser
+	}
+
+	return d.Host == host && expectedPort == port && expectedUser == user
+}
+
+func (v *VersionControl) matches(current monad.Maybe[get_app_detail.VersionControl]) bool {
+	config, isSet := current.TryGet()
+
+	if v == nil || !isSet {
+		return v == nil && !isSet
+	}
+
+	return v.Url == config.Url && (v.Token == "" || storage.SecretString(v.Token) == config.Token.Get(""))
+}
+
+func (e Environment) matches(targetID string, current get_app_detail.EnvironmentConfig) bool {
+	if current.Target.ID != targetID ||
+		!maps.EqualFunc(e.Vars, current.Vars.Get(nil), maps.Equal[map[string]string]) {
+		return false
+	}
+
+	exposure := current.Exposure.Get(nil)
+
+	return maps.EqualFunc(e.Exposure, exposure, func(expected ServiceExposure, actual get_app_detail.ServiceExposure) bool {
+		return expected.Disabled == actual.Disabled &&
+			expected.Port == actual.Port.Get(0) &&
+			expected.Subdomain == actual.Subdomain.Get("") &&
+			expected.PathPrefix == actual.PathPrefix.Get("") &&
+			slices.EqualFunc(expected.Ports, actual.Ports, func(expected ExposedPort, actual get_app_detail.ExposedPort) bool {
+				return expected.Router == actual.Router &&
+					expected.Port == actual.Port &&
+					expected.Published == actual.Published.Get(0)
+			})
+	})
+}
@@ -0,0 +1,116 @@
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrMissingName   = errors.New("every target and app of the spec must have a name")
+	ErrDuplicateName = errors.New("duplicate name in the spec")
+)
+
+type (
+	// Declarative description of the targets and apps a seelf instance should have.
+	// Targets and apps are matched by name with existing ones.
+	Spec struct {
+		Targets []Target `yaml:"targets"`
+		Apps    []App    `yaml:"apps"`
+	}
+
+	Target struct {
+		Name   string `yaml:"name" json:"name"`
+		Url    string `yaml:"url" json:"url"`
+		Docker Docker `yaml:"docker" json:"docker"`
+	}
+
+	// Docker provider settings, empty for the local docker daemon. The private key is
+	// kept as it is when not given.
+	Docker struct {
+		Host       string `yaml:"host" json:"host,omitempty"`
+		Port       int    `yaml:"port" json:"port,omitempty"`
+		User       string `yaml:"user" json:"user,omitempty"`
+		PrivateKey string `yaml:"private_key" json:"private_key,omitempty"`
+	}
+
+	App struct {
+		Name           string          `yaml:"name"`
+		VersionControl *VersionControl `yaml:"version_control"` // Removed from existing apps when nil
+		Production     Environment     `yaml:"production"`
+		Staging        Environment     `yaml:"staging"`
+	}
+
+	// Version control of an app, the token is kept as it is when not given.
+	VersionControl struct {
+		Url   string `yaml:"url" json:"url"`
+		Token string `yaml:"token" json:"token,omitempty"`
+	}
+
+	Environment struct {
+		Target   string                       `yaml:"target"` // Name of the target
+		Vars     map[string]map[string]string `yaml:"vars"`
+		Exposure map[string]ServiceExposure   `yaml:"exposure"`
+	}
+
+	ServiceExposure struct {
+		Disabled   bool          `yaml:"disabled" json:"disabled"`
+		Port       uint          `yaml:"port" json:"port,omitempty"`
+		Subdomain  string        `yaml:"subdomain" json:"subdomain,omitempty"`
+		PathPrefix string        `yaml:"path_prefix" json:"path_prefix,omitempty"`
+		Ports      []ExposedPort `yaml:"ports" json:"ports,omitempty"`
+	}
+
+	ExposedPort struct {
+		Router    string `yaml:"router" json:"router"`
+		Port      uint   `yaml:"port" json:"port"`
+		Published uint   `yaml:"published" json:"published,omitempty"`
+	}
+)
+
+// Parses a YAML spec, unknown fields are rejected to catch typos early.
+func Parse(content []byte) (s Spec, err error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return s, err
+	}
+
+	return s, s.validate()
+}
+
+func (s Spec) validate() error {
+	targets := make(map[string]bool, len(s.Targets))
+
+	for _, t := range s.Targets {
+		if t.Name == "" {
+			return ErrMissingName
+		}
+
+		if targets[t.Name] {
+			return fmt.Errorf("%w: target %s", ErrDuplicateName, t.Name)
+		}
+
+		targets[t.Name] = true
+	}
+
+	apps := make(map[string]bool, len(s.Apps))
+
+	for _, a := range s.Apps {
+		if a.Name == "" {
+			return ErrMissingName
+		}
+
+		if apps[a.Name] {
+			return fmt.Errorf("%w: app %s", ErrDuplicateName, a.Name)
+		}
+
+		apps[a.Name] = true
+	}
+
+	return nil
+}
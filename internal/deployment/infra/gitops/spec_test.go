@@ -0,0 +1,96 @@
+package gitops_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/infra/gitops"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Run("should parse a valid spec", func(t *testing.T) {
+		spec, err := gitops.Parse([]byte(`
+targets:
+  - name: production
+    url: https://example.com
+    docker:
+      host: 192.168.1.10
+apps:
+  - name: my-app
+    version_control:
+      url: https://github.com/someone/my-app
+    production:
+      target: production
+      vars:
+        app:
+          DEBUG: "false"
+      exposure:
+        db:
+          disabled: true
+    staging:
+      target: production
+`))
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, spec.Targets, 1)
+		testutil.Equals(t, "192.168.1.10", spec.Targets[0].Docker.Host)
+		testutil.HasLength(t, spec.Apps, 1)
+		testutil.Equals(t, "https://github.com/someone/my-app", spec.Apps[0].VersionControl.Url)
+		testutil.Equals(t, "false", spec.Apps[0].Production.Vars["app"]["DEBUG"])
+		testutil.IsTrue(t, spec.Apps[0].Production.Exposure["db"].Disabled)
+		testutil.Equals(t, "production", spec.Apps[0].Staging.Target)
+	})
+
+	t.Run("should accept an empty spec", func(t *testing.T) {
+		spec, err := gitops.Parse(nil)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, spec.Targets, 0)
+		testutil.HasLength(t, spec.Apps, 0)
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		_, err := gitops.Parse([]byte(`
+targets:
+  - name: production
+    uri: https://example.com
+`))
+
+		testutil.IsTrue(t, err != nil)
+	})
+
+	t.Run("should reject resources without a name", func(t *testing.T) {
+		_, err := gitops.Parse([]byte(`
+apps:
+  - production:
+      target: production
+`))
+
+		testutil.ErrorIs(t, gitops.ErrMissingName, err)
+	})
+
+	t.Run("should reject duplicate names", func(t *testing.T) {
+		_, err := gitops.Parse([]byte(`
+targets:
+  - name: production
+  - name: production
+`))
+
+		testutil.ErrorIs(t, gitops.ErrDuplicateName, err)
+	})
+}
+
+func Test_Docker(t *testing.T) {
+	t.Run("should apply provider defaults when matching a remote target", func(t *testing.T) {
+		docker := gitops.Docker{Host: "192.168.1.10"}
+
+		testutil.IsTrue(t, docker.Matches("192.168.1.10", 22, "docker"))
+		testutil.IsFalse(t, docker.Matches("192.168.1.10", 2222, "docker"))
+		testutil.IsFalse(t, docker.Matches("", 0, ""))
+	})
+
+	t.Run("should match a local target", func(t *testing.T) {
+		testutil.IsTrue(t, gitops.Docker{}.Matches("", 0, ""))
+		testutil.IsFalse(t, gitops.Docker{}.Matches("192.168.1.10", 22, "docker"))
+	})
+}
@@ -0,0 +1,125 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+var ErrHookFailed = errors.New("hook_failed")
+
+type (
+	// External binary or script plugged into stages of the deployment pipeline. It
+	// receives a JSON description of the deployment on its standard input and its
+	// output is appended to the deployment logs.
+	Hook struct {
+		Name    string
+		Command []string           // Program and its arguments
+		Stages  []domain.HookStage // Stages for which the hook should run
+		Timeout time.Duration      // Maximum duration of a run, 0 for no limit
+	}
+
+	// JSON description of the deployment given to hooks.
+	payload struct {
+		Stage            domain.HookStage `json:"stage"`
+		AppID            string           `json:"app_id"`
+		AppName          string           `json:"app_name"`
+		DeploymentNumber int              `json:"deployment_number"`
+		Environment      string           `json:"environment"`
+		Target           string           `json:"target"`
+		Source           string           `json:"source"`
+		RequestedBy      string           `json:"requested_by"`
+		BuildDirectory   string           `json:"build_directory"`
+		Services         domain.Services  `json:"services,omitempty"` // Only given at the post-deploy stage
+	}
+
+	runner struct {
+		hooks []Hook
+	}
+)
+
+// Builds a runner for the given hooks, run in the order they are given.
+func New(hooks ...Hook) domain.Hooks {
+	return &runner{hooks}
+}
+
+func (r *runner) Run(
+	ctx context.Context,
+	deploymentCtx domain.DeploymentContext,
+	stage domain.HookStage,
+	depl domain.Deployment,
+	services domain.Services,
+) error {
+	var (
+		logger = deploymentCtx.Logger()
+		data   []byte
+	)
+
+	for _, hook := range r.hooks {
+		if !slices.Contains(hook.Stages, stage) {
+			continue
+		}
+
+		if data == nil {
+			var err error
+
+			if data, err = json.Marshal(newPayload(stage, deploymentCtx, depl, services)); err != nil {
+				return err
+			}
+		}
+
+		logger.Stepf("running %s hook %s", stage, hook.Name)
+
+		if err := runHook(ctx, hook, data, deploymentCtx.BuildDirectory(), logger); err != nil {
+			logger.Error(fmt.Errorf("hook %s failed: %w", hook.Name, err))
+			return ErrHookFailed
+		}
+	}
+
+	return nil
+}
+
+func runHook(ctx context.Context, hook Hook, data []byte, dir string, logger domain.DeploymentLogger) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = logger
+	cmd.Stderr = logger
+
+	return cmd.Run()
+}
+
+func newPayload(
+	stage domain.HookStage,
+	deploymentCtx domain.DeploymentContext,
+	depl domain.Deployment,
+	services domain.Services,
+) payload {
+	config := depl.Config()
+
+	return payload{
+		Stage:            stage,
+		AppID:            string(depl.ID().AppID()),
+		AppName:          string(config.AppName()),
+		DeploymentNumber: int(depl.ID().DeploymentNumber()),
+		Environment:      string(config.Environment()),
+		Target:           string(config.Target()),
+		Source:           depl.Source().Kind(),
+		RequestedBy:      string(depl.Requested().By()),
+		BuildDirectory:   deploymentCtx.BuildDirectory(),
+		Services:         services,
+	}
+}
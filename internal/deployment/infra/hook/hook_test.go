@@ -0,0 +1,85 @@
+package hook_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YuukanOO/seelf/cmd/config"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/hook"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Hooks(t *testing.T) {
+	logger, _ := log.NewLogger()
+
+	arrange := func(t *testing.T) (domain.DeploymentContext, domain.Deployment) {
+		opts := config.Default(config.WithTestDefaults())
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig("1"), true, true), "some-uid"))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(""), domain.Production, "some-uid"))
+		ctx := must.Panic(artifact.NewLocal(opts, logger, nil).PrepareBuild(context.Background(), depl))
+
+		t.Cleanup(func() {
+			ctx.Logger().Close()
+			os.RemoveAll(opts.DataDir())
+		})
+
+		return ctx, depl
+	}
+
+	t.Run("should give a JSON description of the deployment to hooks of the stage", func(t *testing.T) {
+		ctx, depl := arrange(t)
+		sut := hook.New(
+			hook.Hook{Name: "other", Command: []string{"sh", "-c", "touch other"}, Stages: []domain.HookStage{domain.HookStagePostDeploy}},
+			hook.Hook{Name: "dump", Command: []string{"sh", "-c", "cat > payload.json"}, Stages: []domain.HookStage{domain.HookStagePostFetch, domain.HookStagePreDeploy}},
+		)
+
+		err := sut.Run(context.Background(), ctx, domain.HookStagePreDeploy, depl, nil)
+
+		testutil.IsNil(t, err)
+
+		_, err = os.Stat(filepath.Join(ctx.BuildDirectory(), "other"))
+		testutil.IsTrue(t, os.IsNotExist(err))
+
+		var payload struct {
+			Stage            string `json:"stage"`
+			AppID            string `json:"app_id"`
+			AppName          string `json:"app_name"`
+			DeploymentNumber int    `json:"deployment_number"`
+			Environment      string `json:"environment"`
+			BuildDirectory   string `json:"build_directory"`
+		}
+
+		testutil.IsNil(t, json.Unmarshal(must.Panic(os.ReadFile(filepath.Join(ctx.BuildDirectory(), "payload.json"))), &payload))
+		testutil.Equals(t, "pre-deploy", payload.Stage)
+		testutil.Equals(t, string(depl.ID().AppID()), payload.AppID)
+		testutil.Equals(t, "my-app", payload.AppName)
+		testutil.Equals(t, 1, payload.DeploymentNumber)
+		testutil.Equals(t, "production", payload.Environment)
+		testutil.Equals(t, ctx.BuildDirectory(), payload.BuildDirectory)
+	})
+
+	t.Run("should fail if a hook does not exit successfully", func(t *testing.T) {
+		ctx, depl := arrange(t)
+		sut := hook.New(
+			hook.Hook{Name: "failing", Command: []string{"sh", "-c", "exit 1"}, Stages: []domain.HookStage{domain.HookStagePostFetch}},
+			hook.Hook{Name: "next", Command: []string{"sh", "-c", "touch next"}, Stages: []domain.HookStage{domain.HookStagePostFetch}},
+		)
+
+		err := sut.Run(context.Background(), ctx, domain.HookStagePostFetch, depl, nil)
+
+		testutil.ErrorIs(t, hook.ErrHookFailed, err)
+
+		_, err = os.Stat(filepath.Join(ctx.BuildDirectory(), "next"))
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+}
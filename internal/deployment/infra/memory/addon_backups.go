@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	AddonBackupsStore interface {
+		domain.AddonBackupsReader
+		domain.AddonBackupsWriter
+	}
+
+	addonBackupsStore struct {
+		backups []*addonBackupData
+	}
+
+	addonBackupData struct {
+		id    domain.AddonBackupID
+		value *domain.AddonBackup
+	}
+)
+
+func NewAddonBackupsStore(existingBackups ...*domain.AddonBackup) AddonBackupsStore {
+	s := &addonBackupsStore{}
+
+	s.Write(context.Background(), existingBackups...)
+
+	return s
+}
+
+func (s *addonBackupsStore) GetByID(ctx context.Context, id domain.AddonBackupID) (domain.AddonBackup, error) {
+	for _, b := range s.backups {
+		if b.id == id {
+			return *b.value, nil
+		}
+	}
+
+	return domain.AddonBackup{}, apperr.ErrNotFound
+}
+
+func (s *addonBackupsStore) GetByAddon(ctx context.Context, addon domain.AddonID) ([]domain.AddonBackup, error) {
+	var backups []domain.AddonBackup
+
+	// Most recent first
+	for i := len(s.backups) - 1; i >= 0; i-- {
+		if b := s.backups[i]; b.value.AddonID() == addon {
+			backups = append(backups, *b.value)
+		}
+	}
+
+	return backups, nil
+}
+
+func (s *addonBackupsStore) Write(ctx context.Context, backups ...*domain.AddonBackup) error {
+	for _, backup := range backups {
+		for _, e := range event.Unwrap(backup) {
+			switch evt := e.(type) {
+			case domain.AddonBackupCreated:
+				var exist bool
+				for _, b := range s.backups {
+					if b.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.backups = append(s.backups, &addonBackupData{
+					id:    evt.ID,
+					value: backup,
+				})
+			case domain.AddonBackupDeleted:
+				for i, b := range s.backups {
+					if b.id == backup.ID() {
+						*b.value = *backup
+						s.backups = append(s.backups[:i], s.backups[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, b := range s.backups {
+					if b.id == backup.ID() {
+						*b.value = *backup
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
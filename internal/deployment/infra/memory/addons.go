@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	AddonsStore interface {
+		domain.AddonsReader
+		domain.AddonsWriter
+	}
+
+	addonsStore struct {
+		addons []*addonData
+	}
+
+	addonData struct {
+		id    domain.AddonID
+		value *domain.Addon
+	}
+)
+
+func NewAddonsStore(existingAddons ...*domain.Addon) AddonsStore {
+	s := &addonsStore{}
+
+	s.Write(context.Background(), existingAddons...)
+
+	return s
+}
+
+func (s *addonsStore) GetByID(ctx context.Context, id domain.AddonID) (domain.Addon, error) {
+	for _, a := range s.addons {
+		if a.id == id {
+			return *a.value, nil
+		}
+	}
+
+	return domain.Addon{}, apperr.ErrNotFound
+}
+
+func (s *addonsStore) GetByApp(ctx context.Context, app domain.AppID) ([]domain.Addon, error) {
+	var addons []domain.Addon
+
+	for _, a := range s.addons {
+		if a.value.AppID() == app {
+			addons = append(addons, *a.value)
+		}
+	}
+
+	return addons, nil
+}
+
+func (s *addonsStore) GetByAppEnv(ctx context.Context, app domain.AppID, env domain.Environment) ([]domain.Addon, error) {
+	var addons []domain.Addon
+
+	for _, a := range s.addons {
+		if a.value.AppID() == app && a.value.Environment() == env {
+			addons = append(addons, *a.value)
+		}
+	}
+
+	return addons, nil
+}
+
+// Backups are not tracked by this store so every ready add-on is considered due.
+func (s *addonsStore) GetDueForBackup(ctx context.Context, before time.Time) ([]domain.Addon, error) {
+	var addons []domain.Addon
+
+	for _, a := range s.addons {
+		if a.value.Status() == domain.AddonStatusReady {
+			addons = append(addons, *a.value)
+		}
+	}
+
+	return addons, nil
+}
+
+func (s *addonsStore) Write(ctx context.Context, addons ...*domain.Addon) error {
+	for _, addon := range addons {
+		for _, e := range event.Unwrap(addon) {
+			switch evt := e.(type) {
+			case domain.AddonCreated:
+				var exist bool
+				for _, a := range s.addons {
+					if a.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.addons = append(s.addons, &addonData{
+					id:    evt.ID,
+					value: addon,
+				})
+			case domain.AddonDeleted:
+				for i, a := range s.addons {
+					if a.id == addon.ID() {
+						*a.value = *addon
+						s.addons = append(s.addons[:i], s.addons[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, a := range s.addons {
+					if a.id == addon.ID() {
+						*a.value = *addon
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	AppArchivesStore interface {
+		domain.AppArchivesReader
+		domain.AppArchivesWriter
+	}
+
+	appArchivesStore struct {
+		archives []*appArchiveData
+	}
+
+	appArchiveData struct {
+		id    domain.AppArchiveID
+		value *domain.AppArchive
+	}
+)
+
+func NewAppArchivesStore(existingArchives ...*domain.AppArchive) AppArchivesStore {
+	s := &appArchivesStore{}
+
+	s.Write(context.Background(), existingArchives...)
+
+	return s
+}
+
+func (s *appArchivesStore) GetByID(ctx context.Context, id domain.AppArchiveID) (domain.AppArchive, error) {
+	for _, a := range s.archives {
+		if a.id == id {
+			return *a.value, nil
+		}
+	}
+
+	return domain.AppArchive{}, apperr.ErrNotFound
+}
+
+func (s *appArchivesStore) GetExpired(ctx context.Context, at time.Time) ([]domain.AppArchive, error) {
+	var archives []domain.AppArchive
+
+	for _, a := range s.archives {
+		if expiresAt, isSet := a.value.ExpiresAt().TryGet(); isSet && !expiresAt.After(at) {
+			archives = append(archives, *a.value)
+		}
+	}
+
+	return archives, nil
+}
+
+func (s *appArchivesStore) Write(ctx context.Context, archives ...*domain.AppArchive) error {
+	for _, archive := range archives {
+		for _, e := range event.Unwrap(archive) {
+			switch evt := e.(type) {
+			case domain.AppArchiveCreated:
+				var exist bool
+				for _, a := range s.archives {
+					if a.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.archives = append(s.archives, &appArchiveData{
+					id:    evt.ID,
+					value: archive,
+				})
+			case domain.AppArchiveDeleted:
+				for i, a := range s.archives {
+					if a.id == archive.ID() {
+						*a.value = *archive
+						s.archives = append(s.archives[:i], s.archives[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, a := range s.archives {
+					if a.id == archive.ID() {
+						*a.value = *archive
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"slices"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
@@ -123,6 +124,44 @@ func (s *appsStore) CheckAppNamingAvailabilityByID(
 	return productionRequirement, stagingRequirement, nil
 }
 
+func (s *appsStore) CheckUrlAliasesAvailability(
+	ctx context.Context,
+	id domain.AppID,
+	env domain.Environment,
+	aliases domain.UrlAliases,
+) (domain.UrlAliasesRequirement, error) {
+	var target domain.TargetID
+
+	for _, app := range s.apps {
+		if app.id == id {
+			target = environmentOf(app.value, env).Target()
+			break
+		}
+	}
+
+	if target == "" {
+		return domain.UrlAliasesRequirement{}, apperr.ErrNotFound
+	}
+
+	for _, app := range s.apps {
+		for _, other := range []domain.Environment{domain.Production, domain.Staging} {
+			config := environmentOf(app.value, other)
+
+			if (app.id == id && other == env) || config.Target() != target {
+				continue
+			}
+
+			for _, alias := range config.UrlAliases().Get(nil) {
+				if slices.Contains(aliases, alias) {
+					return domain.NewUrlAliasesRequirement(aliases, false), nil
+				}
+			}
+		}
+	}
+
+	return domain.NewUrlAliasesRequirement(aliases, true), nil
+}
+
 func (s *appsStore) HasAppsOnTarget(ctx context.Context, target domain.TargetID) (domain.HasAppsOnTarget, error) {
 	for _, app := range s.apps {
 		if app.productionTarget == target || app.stagingTarget == target {
@@ -133,6 +172,38 @@ func (s *appsStore) HasAppsOnTarget(ctx context.Context, target domain.TargetID)
 	return false, nil
 }
 
+func (s *appsStore) HasAppsInTeam(ctx context.Context, team domain.TeamID) (domain.HasAppsInTeam, error) {
+	for _, app := range s.apps {
+		if id, isSet := app.value.Team().TryGet(); isSet && id == team {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *appsStore) GetDependencyGraph(ctx context.Context) (domain.AppDependencyGraph, error) {
+	graph := make(domain.AppDependencyGraph, len(s.apps))
+
+	for _, app := range s.apps {
+		graph[app.id] = app.value.Dependencies()
+	}
+
+	return graph, nil
+}
+
+func (s *appsStore) GetAppsOnTarget(ctx context.Context, target domain.TargetID) ([]domain.App, error) {
+	var apps []domain.App
+
+	for _, app := range s.apps {
+		if app.productionTarget == target || app.stagingTarget == target {
+			apps = append(apps, *app.value)
+		}
+	}
+
+	return apps, nil
+}
+
 func (s *appsStore) GetByID(ctx context.Context, id domain.AppID) (domain.App, error) {
 	for _, app := range s.apps {
 		if app.id == id {
@@ -201,3 +272,11 @@ func (s *appsStore) Write(ctx context.Context, apps ...*domain.App) error {
 
 	return nil
 }
+
+func environmentOf(app *domain.App, env domain.Environment) domain.EnvironmentConfig {
+	if env.IsProduction() {
+		return app.Production()
+	}
+
+	return app.Staging()
+}
@@ -1,7 +1,10 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
@@ -63,6 +66,29 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 
 }
 
+func (s *deploymentsStore) GetDeploymentRunningAt(ctx context.Context, id domain.AppID, env domain.Environment, date time.Time) (domain.Deployment, error) {
+	var last *deploymentData
+
+	for _, depl := range s.deployments {
+		if depl.id.AppID() != id ||
+			depl.value.Config().Environment() != env ||
+			depl.state.Status() != domain.DeploymentStatusSucceeded ||
+			depl.state.StartedAt().Get(date).After(date) {
+			continue
+		}
+
+		if last == nil || last.id.DeploymentNumber() < depl.id.DeploymentNumber() {
+			last = depl
+		}
+	}
+
+	if last == nil {
+		return domain.Deployment{}, apperr.ErrNotFound
+	}
+
+	return *last.value, nil
+}
+
 func (s *deploymentsStore) GetNextDeploymentNumber(ctx context.Context, appid domain.AppID) (domain.DeploymentNumber, error) {
 	count := 0
 
@@ -113,6 +139,48 @@ func (s *deploymentsStore) HasDeploymentsOnAppTargetEnv(ctx context.Context, app
 	return ongoing, successful, nil
 }
 
+func (s *deploymentsStore) GetByApp(ctx context.Context, id domain.AppID) ([]domain.Deployment, error) {
+	var result []domain.Deployment
+
+	for _, depl := range s.deployments {
+		if depl.id.AppID() == id {
+			result = append(result, *depl.value)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b domain.Deployment) int {
+		return cmp.Compare(a.ID().DeploymentNumber(), b.ID().DeploymentNumber())
+	})
+
+	return result, nil
+}
+
+func (s *deploymentsStore) GetPrunableDeployments(ctx context.Context) ([]domain.Deployment, error) {
+	var result []domain.Deployment
+
+	for _, depl := range s.deployments {
+		if depl.state.Status() != domain.DeploymentStatusSucceeded && depl.state.Status() != domain.DeploymentStatusFailed {
+			continue
+		}
+
+		last, err := s.GetLastDeployment(ctx, depl.id.AppID(), depl.value.Config().Environment())
+
+		if err != nil {
+			return nil, err
+		}
+
+		if last.ID() != depl.id {
+			result = append(result, *depl.value)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b domain.Deployment) int {
+		return a.Requested().At().Compare(b.Requested().At())
+	})
+
+	return result, nil
+}
+
 func (s *deploymentsStore) FailDeployments(ctx context.Context, reason error, criterias domain.FailCriterias) error {
 	panic("not implemented")
 }
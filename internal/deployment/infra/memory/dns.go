@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	DnsZonesStore interface {
+		domain.DnsZonesReader
+		domain.DnsZonesWriter
+	}
+
+	DnsRecordsStore interface {
+		domain.DnsRecordsReader
+		domain.DnsRecordsWriter
+	}
+
+	dnsZonesStore struct {
+		zones []*dnsZoneData
+	}
+
+	dnsZoneData struct {
+		id    domain.DnsZoneID
+		value *domain.DnsZone
+	}
+
+	dnsRecordsStore struct {
+		exposed []domain.ExposedHost
+		records []domain.DnsRecord
+	}
+)
+
+func NewDnsZonesStore(existingZones ...*domain.DnsZone) DnsZonesStore {
+	s := &dnsZonesStore{}
+
+	s.Write(context.Background(), existingZones...)
+
+	return s
+}
+
+func (s *dnsZonesStore) CheckNameAvailability(ctx context.Context, name string) (domain.DnsZoneNameRequirement, error) {
+	unique := !slices.ContainsFunc(s.zones, func(z *dnsZoneData) bool {
+		return z.value.Name() == name
+	})
+
+	return domain.NewDnsZoneNameRequirement(name, unique), nil
+}
+
+func (s *dnsZonesStore) GetByID(ctx context.Context, id domain.DnsZoneID) (domain.DnsZone, error) {
+	for _, z := range s.zones {
+		if z.id == id {
+			return *z.value, nil
+		}
+	}
+
+	return domain.DnsZone{}, apperr.ErrNotFound
+}
+
+func (s *dnsZonesStore) GetAll(ctx context.Context) ([]domain.DnsZone, error) {
+	var zones []domain.DnsZone
+
+	for _, z := range s.zones {
+		zones = append(zones, *z.value)
+	}
+
+	return zones, nil
+}
+
+func (s *dnsZonesStore) Write(ctx context.Context, zones ...*domain.DnsZone) error {
+	for _, zone := range zones {
+		for _, e := range event.Unwrap(zone) {
+			switch evt := e.(type) {
+			case domain.DnsZoneCreated:
+				var exist bool
+				for _, z := range s.zones {
+					if z.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.zones = append(s.zones, &dnsZoneData{
+					id:    evt.ID,
+					value: zone,
+				})
+			case domain.DnsZoneDeleted:
+				for i, z := range s.zones {
+					if z.id == zone.ID() {
+						*z.value = *zone
+						s.zones = append(s.zones[:i], s.zones[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, z := range s.zones {
+					if z.id == zone.ID() {
+						*z.value = *zone
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Builds a records store in which the given hosts are considered exposed by apps.
+func NewDnsRecordsStore(exposed []domain.ExposedHost, existingRecords ...domain.DnsRecord) DnsRecordsStore {
+	return &dnsRecordsStore{
+		exposed: exposed,
+		records: existingRecords,
+	}
+}
+
+func (s *dnsRecordsStore) GetExposedHosts(ctx context.Context) ([]domain.ExposedHost, error) {
+	return s.exposed, nil
+}
+
+func (s *dnsRecordsStore) GetDnsRecords(ctx context.Context) ([]domain.DnsRecord, error) {
+	return s.records, nil
+}
+
+func (s *dnsRecordsStore) WriteDnsRecord(ctx context.Context, record domain.DnsRecord) error {
+	idx := slices.IndexFunc(s.records, func(r domain.DnsRecord) bool { return r.Host == record.Host })
+
+	if idx < 0 {
+		s.records = append(s.records, record)
+	} else {
+		s.records[idx] = record
+	}
+
+	return nil
+}
+
+func (s *dnsRecordsStore) DeleteDnsRecord(ctx context.Context, host string) error {
+	s.records = slices.DeleteFunc(s.records, func(r domain.DnsRecord) bool { return r.Host == host })
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	EnvRevisionsStore interface {
+		domain.EnvRevisionsReader
+		domain.EnvRevisionsWriter
+	}
+
+	envRevisionsStore struct {
+		revisions []*domain.EnvRevision
+	}
+)
+
+func NewEnvRevisionsStore(existingRevisions ...*domain.EnvRevision) EnvRevisionsStore {
+	s := &envRevisionsStore{}
+
+	s.Write(context.Background(), existingRevisions...)
+
+	return s
+}
+
+func (s *envRevisionsStore) GetByID(ctx context.Context, id domain.EnvRevisionID) (domain.EnvRevision, error) {
+	for _, r := range s.revisions {
+		if r.ID() == id {
+			return *r, nil
+		}
+	}
+
+	return domain.EnvRevision{}, apperr.ErrNotFound
+}
+
+func (s *envRevisionsStore) Write(ctx context.Context, revisions ...*domain.EnvRevision) error {
+	for _, revision := range revisions {
+		for _, e := range event.Unwrap(revision) {
+			switch e.(type) {
+			case domain.EnvRevisionRecorded:
+				s.revisions = append(s.revisions, revision)
+			}
+		}
+	}
+
+	return nil
+}
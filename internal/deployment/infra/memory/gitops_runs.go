@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	GitOpsRunsStore interface {
+		domain.GitOpsRunsReader
+		domain.GitOpsRunsWriter
+	}
+
+	gitOpsRunsStore struct {
+		runs []*domain.GitOpsRun
+	}
+)
+
+func NewGitOpsRunsStore(existingRuns ...*domain.GitOpsRun) GitOpsRunsStore {
+	s := &gitOpsRunsStore{}
+
+	s.Write(context.Background(), existingRuns...)
+
+	return s
+}
+
+func (s *gitOpsRunsStore) GetLatest(ctx context.Context) (domain.GitOpsRun, error) {
+	if len(s.runs) == 0 {
+		return domain.GitOpsRun{}, apperr.ErrNotFound
+	}
+
+	return *s.runs[len(s.runs)-1], nil
+}
+
+func (s *gitOpsRunsStore) Write(ctx context.Context, runs ...*domain.GitOpsRun) error {
+	for _, run := range runs {
+		for _, e := range event.Unwrap(run) {
+			switch e.(type) {
+			case domain.GitOpsRunRecorded:
+				s.runs = append(s.runs, run)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	MonitorsStore interface {
+		domain.MonitorsReader
+		domain.MonitorsWriter
+	}
+
+	monitorsStore struct {
+		monitors []*monitorData
+	}
+
+	monitorData struct {
+		id    domain.MonitorID
+		value *domain.Monitor
+	}
+)
+
+func NewMonitorsStore(existingMonitors ...*domain.Monitor) MonitorsStore {
+	s := &monitorsStore{}
+
+	s.Write(context.Background(), existingMonitors...)
+
+	return s
+}
+
+func (s *monitorsStore) GetByID(ctx context.Context, id domain.MonitorID) (domain.Monitor, error) {
+	for _, m := range s.monitors {
+		if m.id == id {
+			return *m.value, nil
+		}
+	}
+
+	return domain.Monitor{}, apperr.ErrNotFound
+}
+
+func (s *monitorsStore) GetByApp(ctx context.Context, app domain.AppID, env domain.Environment) (domain.Monitor, error) {
+	for _, m := range s.monitors {
+		if m.value.AppID() == app && m.value.Environment() == env {
+			return *m.value, nil
+		}
+	}
+
+	return domain.Monitor{}, apperr.ErrNotFound
+}
+
+func (s *monitorsStore) GetDue(ctx context.Context, at time.Time) ([]domain.Monitor, error) {
+	var monitors []domain.Monitor
+
+	for _, m := range s.monitors {
+		if !m.value.NextCheckAt().After(at) {
+			monitors = append(monitors, *m.value)
+		}
+	}
+
+	return monitors, nil
+}
+
+func (s *monitorsStore) Write(ctx context.Context, monitors ...*domain.Monitor) error {
+	for _, monitor := range monitors {
+		for _, e := range event.Unwrap(monitor) {
+			switch evt := e.(type) {
+			case domain.MonitorCreated:
+				var exist bool
+				for _, m := range s.monitors {
+					if m.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.monitors = append(s.monitors, &monitorData{
+					id:    evt.ID,
+					value: monitor,
+				})
+			case domain.MonitorDeleted:
+				for i, m := range s.monitors {
+					if m.id == monitor.ID() {
+						*m.value = *monitor
+						s.monitors = append(s.monitors[:i], s.monitors[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, m := range s.monitors {
+					if m.id == monitor.ID() {
+						*m.value = *monitor
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
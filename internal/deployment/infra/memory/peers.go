@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	PeersStore interface {
+		domain.PeersReader
+		domain.PeersWriter
+	}
+
+	peersStore struct {
+		peers []*peerData
+	}
+
+	peerData struct {
+		id    domain.PeerID
+		value *domain.Peer
+	}
+)
+
+func NewPeersStore(existingPeers ...*domain.Peer) PeersStore {
+	s := &peersStore{}
+
+	s.Write(context.Background(), existingPeers...)
+
+	return s
+}
+
+func (s *peersStore) GetByID(ctx context.Context, id domain.PeerID) (domain.Peer, error) {
+	for _, p := range s.peers {
+		if p.id == id {
+			return *p.value, nil
+		}
+	}
+
+	return domain.Peer{}, apperr.ErrNotFound
+}
+
+func (s *peersStore) GetAll(ctx context.Context) ([]domain.Peer, error) {
+	var peers []domain.Peer
+
+	for _, p := range s.peers {
+		peers = append(peers, *p.value)
+	}
+
+	return peers, nil
+}
+
+func (s *peersStore) Write(ctx context.Context, peers ...*domain.Peer) error {
+	for _, peer := range peers {
+		for _, e := range event.Unwrap(peer) {
+			switch evt := e.(type) {
+			case domain.PeerCreated:
+				var exist bool
+				for _, p := range s.peers {
+					if p.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.peers = append(s.peers, &peerData{
+					id:    evt.ID,
+					value: peer,
+				})
+			case domain.PeerDeleted:
+				for i, p := range s.peers {
+					if p.id == peer.ID() {
+						*p.value = *peer
+						s.peers = append(s.peers[:i], s.peers[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, p := range s.peers {
+					if p.id == peer.ID() {
+						*p.value = *peer
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
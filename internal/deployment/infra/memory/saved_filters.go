@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	SavedFiltersStore interface {
+		domain.SavedFiltersReader
+		domain.SavedFiltersWriter
+	}
+
+	savedFiltersStore struct {
+		filters []*savedFilterData
+	}
+
+	savedFilterData struct {
+		id    domain.SavedFilterID
+		value *domain.SavedFilter
+	}
+)
+
+func NewSavedFiltersStore(existingFilters ...*domain.SavedFilter) SavedFiltersStore {
+	s := &savedFiltersStore{}
+
+	s.Write(context.Background(), existingFilters...)
+
+	return s
+}
+
+func (s *savedFiltersStore) GetByID(ctx context.Context, id domain.SavedFilterID) (domain.SavedFilter, error) {
+	for _, f := range s.filters {
+		if f.id == id {
+			return *f.value, nil
+		}
+	}
+
+	return domain.SavedFilter{}, apperr.ErrNotFound
+}
+
+func (s *savedFiltersStore) Write(ctx context.Context, filters ...*domain.SavedFilter) error {
+	for _, filter := range filters {
+		for _, e := range event.Unwrap(filter) {
+			switch evt := e.(type) {
+			case domain.SavedFilterCreated:
+				var exist bool
+				for _, f := range s.filters {
+					if f.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.filters = append(s.filters, &savedFilterData{
+					id:    evt.ID,
+					value: filter,
+				})
+			case domain.SavedFilterDeleted:
+				for i, f := range s.filters {
+					if f.id == filter.ID() {
+						*f.value = *filter
+						s.filters = append(s.filters[:i], s.filters[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -80,6 +80,34 @@ func (s *targetsStore) GetByID(ctx context.Context, id domain.TargetID) (domain.
 	return domain.Target{}, apperr.ErrNotFound
 }
 
+func (s *targetsStore) GetReady(ctx context.Context) ([]domain.Target, error) {
+	var targets []domain.Target
+
+	for _, t := range s.targets {
+		if t.value.CheckAvailability() == nil {
+			targets = append(targets, *t.value)
+		}
+	}
+
+	return targets, nil
+}
+
+// Apps are not known by this store so every candidate is reported as unused.
+func (s *targetsStore) GetCandidates(ctx context.Context) ([]domain.TargetCandidate, error) {
+	var candidates []domain.TargetCandidate
+
+	for _, t := range s.targets {
+		if t.value.CheckAvailability() == nil {
+			candidates = append(candidates, domain.TargetCandidate{
+				ID:     t.id,
+				Labels: t.value.Labels(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
 func (s *targetsStore) Write(ctx context.Context, targets ...*domain.Target) error {
 	for _, target := range targets {
 		for _, e := range event.Unwrap(target) {
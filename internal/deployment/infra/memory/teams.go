@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	TeamsStore interface {
+		domain.TeamsReader
+		domain.TeamsWriter
+	}
+
+	teamsStore struct {
+		teams []*teamData
+	}
+
+	teamData struct {
+		id    domain.TeamID
+		value *domain.Team
+	}
+)
+
+func NewTeamsStore(existingTeams ...*domain.Team) TeamsStore {
+	s := &teamsStore{}
+
+	s.Write(context.Background(), existingTeams...)
+
+	return s
+}
+
+func (s *teamsStore) GetByID(ctx context.Context, id domain.TeamID) (domain.Team, error) {
+	for _, t := range s.teams {
+		if t.id == id {
+			return *t.value, nil
+		}
+	}
+
+	return domain.Team{}, apperr.ErrNotFound
+}
+
+func (s *teamsStore) Write(ctx context.Context, teams ...*domain.Team) error {
+	for _, team := range teams {
+		for _, e := range event.Unwrap(team) {
+			switch evt := e.(type) {
+			case domain.TeamCreated:
+				var exist bool
+				for _, t := range s.teams {
+					if t.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.teams = append(s.teams, &teamData{
+					id:    evt.ID,
+					value: team,
+				})
+			case domain.TeamDeleted:
+				for i, t := range s.teams {
+					if t.id == team.ID() {
+						*t.value = *team
+						s.teams = append(s.teams[:i], s.teams[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, t := range s.teams {
+					if t.id == team.ID() {
+						*t.value = *team
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	TrashedAppsStore interface {
+		domain.TrashedAppsReader
+		domain.TrashedAppsWriter
+	}
+
+	trashedAppsStore struct {
+		apps []*trashedAppData
+	}
+
+	trashedAppData struct {
+		id    domain.AppID
+		value *domain.TrashedApp
+	}
+)
+
+func NewTrashedAppsStore(existingApps ...*domain.TrashedApp) TrashedAppsStore {
+	s := &trashedAppsStore{}
+
+	s.Write(context.Background(), existingApps...)
+
+	return s
+}
+
+func (s *trashedAppsStore) GetByID(ctx context.Context, id domain.AppID) (domain.TrashedApp, error) {
+	for _, a := range s.apps {
+		if a.id == id {
+			return *a.value, nil
+		}
+	}
+
+	return domain.TrashedApp{}, apperr.ErrNotFound
+}
+
+func (s *trashedAppsStore) GetExpired(ctx context.Context, at time.Time) ([]domain.TrashedApp, error) {
+	var apps []domain.TrashedApp
+
+	for _, a := range s.apps {
+		if !a.value.ExpiresAt().After(at) {
+			apps = append(apps, *a.value)
+		}
+	}
+
+	return apps, nil
+}
+
+func (s *trashedAppsStore) Write(ctx context.Context, apps ...*domain.TrashedApp) error {
+	for _, app := range apps {
+		for _, e := range event.Unwrap(app) {
+			switch evt := e.(type) {
+			case domain.TrashedAppCreated:
+				var exist bool
+				for _, a := range s.apps {
+					if a.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.apps = append(s.apps, &trashedAppData{
+					id:    evt.ID,
+					value: app,
+				})
+			case domain.TrashedAppRestored, domain.TrashedAppPurged:
+				for i, a := range s.apps {
+					if a.id == app.ID() {
+						*a.value = *app
+						s.apps = append(s.apps[:i], s.apps[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, a := range s.apps {
+					if a.id == app.ID() {
+						*a.value = *app
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
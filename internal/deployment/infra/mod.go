@@ -3,64 +3,210 @@ package infra
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/annotate_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/backup_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/app/broadcast_changes"
+	"github.com/YuukanOO/seelf/internal/deployment/app/check_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_addon"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/clear_build_cache"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_artifacts"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_garbage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_target_capacity"
+	"github.com/YuukanOO/seelf/internal/deployment/app/collect_traffic"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_freeze"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_proxy_rules"
 	"github.com/YuukanOO/seelf/internal/deployment/app/configure_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_timeouts"
+	"github.com/YuukanOO/seelf/internal/deployment/app/configure_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_addon"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_peer"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_saved_filter"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_team"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_monitor"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_peer"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_saved_filter"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_team"
 	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/detect_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/app/exec_service"
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/export_env_vars"
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
 	"github.com/YuukanOO/seelf/internal/deployment/app/fail_pending_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/app/follow_deployment_log"
+	"github.com/YuukanOO/seelf/internal/deployment/app/generate_badge_token"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_archive"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_badge"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_logs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_build_context"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log_steps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_reports"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_federated_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/import_env_vars"
 	"github.com/YuukanOO/seelf/internal/deployment/app/promote"
+	"github.com/YuukanOO/seelf/internal/deployment/app/provision_addon"
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_app_archives"
+	"github.com/YuukanOO/seelf/internal/deployment/app/purge_trashed_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_addon_backups"
 	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_garbage_collections"
+	"github.com/YuukanOO/seelf/internal/deployment/app/queue_proxy_upgrades"
 	"github.com/YuukanOO/seelf/internal/deployment/app/reconfigure_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/record_deployment_history"
+	"github.com/YuukanOO/seelf/internal/deployment/app/record_deployment_transition"
+	"github.com/YuukanOO/seelf/internal/deployment/app/record_env_revision"
 	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_access_protection"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_compose_override"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_deploy_trigger"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_proxy_rules"
+	"github.com/YuukanOO/seelf/internal/deployment/app/remove_url_aliases"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_addon_restore"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/request_proxy_upgrade"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_target_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restart_services"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_addon_backup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/restore_env_revision"
+	"github.com/YuukanOO/seelf/internal/deployment/app/retry_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/revoke_badge_token"
+	"github.com/YuukanOO/seelf/internal/deployment/app/scale_service"
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_dns_records"
+	"github.com/YuukanOO/seelf/internal/deployment/app/sync_gitops"
+	"github.com/YuukanOO/seelf/internal/deployment/app/unfreeze_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_peer"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_registry"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_team"
+	"github.com/YuukanOO/seelf/internal/deployment/app/upgrade_proxy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/verify_deploy_trigger"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/dns"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/federation"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/gitops"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/hook"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/secret"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/archive"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/git"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
 	deploymentsqlite "github.com/YuukanOO/seelf/internal/deployment/infra/sqlite"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/uptime"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/health"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/realtime"
+	"github.com/YuukanOO/seelf/pkg/s3"
+	"github.com/YuukanOO/seelf/pkg/ssh"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
 )
 
 type Options interface {
 	artifact.LocalOptions
+	S3() monad.Maybe[s3.Options]
+	AddonsBackupRetention() int                         // Number of successful backups kept per add-on
+	SBOMImage() monad.Maybe[string]                     // Syft compatible image used to generate bills of materials, if enabled
+	VulnerabilityScan() monad.Maybe[docker.ScanOptions] // How images built by deployments are scanned, if enabled
+	Hooks() []hook.Hook                                 // External programs plugged into stages of the deployment pipeline
+	GitOps() monad.Maybe[gitops.Options]                // Repository describing targets and apps to reconcile with, if enabled
+	Vault() monad.Maybe[secret.VaultOptions]            // HashiCorp Vault server secrets could be referenced from, if enabled
+	SSM() monad.Maybe[secret.SSMOptions]                // AWS Parameter Store secrets could be referenced from, if enabled
+	Doppler() monad.Maybe[secret.DopplerOptions]        // Doppler account secrets could be referenced from, if enabled
+	Secret() []byte                                     // Secret used to encrypt sensitive values at rest
+	SSHKeepAlive() ssh.KeepAlive                        // How connections to SSH targets are kept alive and shared
+	RunnersDeploymentCount() int                        // Number of deployments processed concurrently
+	TargetSelection() domain.TargetSelection            // Rules used to choose the target of apps created without one
+	TargetsGC() domain.GarbageCollectionOptions         // Thresholds applied when collecting garbage on targets
+	AppArchiveGracePeriod() time.Duration               // How long archives of deleted apps are kept
+	TrashRetention() time.Duration                      // How long deleted apps could be restored, 0 to disable the trash
+	FreezeWindows() domain.FreezeWindows                // Instance wide periods during which production deployments are not processed
+	DeploymentTimeouts() domain.DeploymentTimeouts      // Instance wide timeouts of the deployment pipeline stages
 }
 
 // Setup the deployment module and register everything needed in the given
-// bus.
+// bus. Changes are broadcasted to realtime subscribers using the given pool and
+// checks needed for the module to work are added to the given checker.
 func Setup(
 	opts Options,
 	logger log.Logger,
 	db *sqlite.Database,
 	b bus.Bus,
 	scheduler bus.Scheduler,
+	pool *event.Pool,
+	publisher realtime.Publisher,
+	checker *health.Checker,
 ) error {
 	appsStore := deploymentsqlite.NewAppsStore(db)
 	deploymentsStore := deploymentsqlite.NewDeploymentsStore(db)
 	targetsStore := deploymentsqlite.NewTargetsStore(db)
 	registriesStore := deploymentsqlite.NewRegistriesStore(db)
-	deploymentQueryHandler := deploymentsqlite.NewGateway(db)
+	teamsStore := deploymentsqlite.NewTeamsStore(db)
+	artifactsUsageStore := deploymentsqlite.NewArtifactsUsageStore(db)
+	resourceUsageStore := deploymentsqlite.NewResourceUsageStore(db)
+	trafficStore := deploymentsqlite.NewTrafficStore(db)
+	targetCapacityStore := deploymentsqlite.NewTargetCapacityStore(db)
+	incidentsStore := deploymentsqlite.NewIncidentsStore(db)
+	monitorsStore := deploymentsqlite.NewMonitorsStore(db)
+	addonsStore := deploymentsqlite.NewAddonsStore(db)
+	addonBackupsStore := deploymentsqlite.NewAddonBackupsStore(db)
+	appArchivesStore := deploymentsqlite.NewAppArchivesStore(db)
+	trashedAppsStore := deploymentsqlite.NewTrashedAppsStore(db)
+	envRevisionsStore := deploymentsqlite.NewEnvRevisionsStore(db)
+	gitOpsRunsStore := deploymentsqlite.NewGitOpsRunsStore(db)
+	savedFiltersStore := deploymentsqlite.NewSavedFiltersStore(db)
+	deploymentHistoryStore := deploymentsqlite.NewDeploymentHistoryStore(db)
+	deploymentTransitionsStore := deploymentsqlite.NewDeploymentTransitionsStore(db)
+	peersStore := deploymentsqlite.NewPeersStore(db)
+	dnsZonesStore := deploymentsqlite.NewDnsZonesStore(db)
+	dnsRecordsStore := deploymentsqlite.NewDnsRecordsStore(db)
+	deploymentQueryHandler := deploymentsqlite.NewGateway(db.ReadOnly(), opts.RunnersDeploymentCount())
 
-	artifactManager := artifact.NewLocal(opts, logger)
+	var artifactsStorage artifact.Storage
+
+	if s3Options, isSet := opts.S3().TryGet(); isSet {
+		client, err := s3.New(s3Options)
+
+		if err != nil {
+			return err
+		}
+
+		artifactsStorage = client
+	}
+
+	artifactManager := artifact.NewLocal(opts, logger, artifactsStorage)
+	addonBackups := artifact.NewAddonBackups(opts, logger, artifactsStorage)
+	appArchives := artifact.NewAppArchives(opts, logger, artifactsStorage)
 
 	sourceFacade := source.NewFacade(
 		raw.New(),
@@ -68,25 +214,103 @@ func Setup(
 		git.New(appsStore),
 	)
 
-	dock := docker.New(logger)
+	secretFacade := secret.NewFacade(
+		secret.NewVault(opts.Vault()),
+		secret.NewSSM(opts.SSM()),
+		secret.NewDoppler(opts.Doppler()),
+	)
+
+	dockerOptions := []docker.DockerOptions{
+		docker.WithLegacySecret(opts.Secret()),
+		docker.WithSSHKeepAlive(opts.SSHKeepAlive()),
+	}
+
+	if image, isSet := opts.SBOMImage().TryGet(); isSet {
+		dockerOptions = append(dockerOptions, docker.WithSBOM(image))
+	}
+
+	if scan, isSet := opts.VulnerabilityScan().TryGet(); isSet {
+		dockerOptions = append(dockerOptions, docker.WithVulnerabilityScan(scan))
+	}
+
+	dock := docker.New(logger, dockerOptions...)
 	providerFacade := provider.NewFacade(
 		dock,
 	)
 
 	bus.Register(b, expose_seelf_container.Handler(targetsStore, targetsStore, dock))
-	bus.Register(b, create_app.Handler(appsStore, appsStore))
-	bus.Register(b, update_app.Handler(appsStore, appsStore))
+	bus.Register(b, create_app.Handler(appsStore, appsStore, teamsStore, registriesStore, targetsStore, opts.TargetSelection()))
+	bus.Register(b, update_app.Handler(appsStore, appsStore, teamsStore, registriesStore))
 	bus.Register(b, queue_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, sourceFacade))
-	bus.Register(b, deploy.Handler(deploymentsStore, deploymentsStore, artifactManager, sourceFacade, providerFacade, targetsStore, registriesStore))
-	bus.Register(b, request_app_cleanup.Handler(appsStore, appsStore))
-	bus.Register(b, delete_app.Handler(appsStore, appsStore, artifactManager))
+	bus.Register(b, deploy.Handler(deploymentsStore, deploymentsStore, artifactManager, sourceFacade, providerFacade, targetsStore, registriesStore, addonsStore, appsStore, monitorsStore, hook.New(opts.Hooks()...), secretFacade, opts.FreezeWindows(), opts.DeploymentTimeouts(), deploymentTransitionsStore))
+	bus.Register(b, request_app_cleanup.Handler(appsStore, appsStore, appArchivesStore))
+	bus.Register(b, export_app.Handler(appArchivesStore, appArchivesStore, appsStore, appsStore, deploymentsStore, addonsStore, targetsStore, providerFacade, appArchives, opts.AppArchiveGracePeriod()))
+	bus.Register(b, purge_app_archives.Handler(appArchivesStore, appArchivesStore, appArchives))
+	bus.Register(b, get_app_archive.Handler(appArchivesStore, appArchives))
+	bus.Register(b, clear_build_cache.Handler(appsStore, artifactManager))
+	bus.Register(b, delete_app.Handler(appsStore, appsStore, artifactManager, deploymentsStore, trashedAppsStore, opts.TrashRetention()))
+	bus.Register(b, restore_app.Handler(trashedAppsStore, trashedAppsStore, appsStore, appsStore, deploymentsStore, deploymentsStore))
+	bus.Register(b, purge_trashed_apps.Handler(trashedAppsStore, trashedAppsStore))
 	bus.Register(b, cleanup_app.Handler(targetsStore, deploymentsStore, providerFacade))
-	bus.Register(b, get_deployment_log.Handler(deploymentsStore, artifactManager))
+	bus.Register(b, collect_artifacts.Handler(deploymentsStore, artifactManager, artifactsUsageStore))
+	bus.Register(b, collect_resource_usage.Handler(targetsStore, providerFacade, resourceUsageStore))
+	bus.Register(b, collect_traffic.Handler(targetsStore, providerFacade, trafficStore))
+	bus.Register(b, collect_target_capacity.Handler(targetsStore, providerFacade, targetCapacityStore))
+	bus.Register(b, detect_incidents.Handler(targetsStore, deploymentsStore, providerFacade, incidentsStore))
+	bus.Register(b, configure_monitor.Handler(appsStore, monitorsStore, monitorsStore))
+	bus.Register(b, delete_monitor.Handler(appsStore, monitorsStore, monitorsStore))
+	bus.Register(b, check_monitors.Handler(monitorsStore, monitorsStore, appsStore, targetsStore, uptime.NewProbe()))
+	bus.Register(b, create_addon.Handler(appsStore, addonsStore, addonsStore))
+	bus.Register(b, provision_addon.Handler(addonsStore, addonsStore, targetsStore, providerFacade))
+	bus.Register(b, request_addon_cleanup.Handler(appsStore, addonsStore, addonsStore))
+	bus.Register(b, cleanup_addon.Handler(addonsStore, addonsStore, targetsStore, providerFacade))
+	bus.Register(b, queue_addon_backups.Handler(addonsStore, addonBackupsStore))
+	bus.Register(b, request_addon_backup.Handler(appsStore, addonsStore, addonBackupsStore))
+	bus.Register(b, backup_addon.Handler(addonBackupsStore, addonBackupsStore, addonsStore, targetsStore, providerFacade, addonBackups, opts.AddonsBackupRetention()))
+	bus.Register(b, request_addon_restore.Handler(appsStore, addonsStore, addonBackupsStore, addonBackupsStore))
+	bus.Register(b, restore_addon_backup.Handler(addonBackupsStore, addonBackupsStore, addonsStore, targetsStore, providerFacade, addonBackups))
+	bus.Register(b, restore_env_revision.Handler(envRevisionsStore, appsStore, appsStore))
+	bus.Register(b, import_env_vars.Handler(appsStore, appsStore))
+	bus.Register(b, configure_access_protection.Handler(appsStore, appsStore, crypto.NewBCryptHasher()))
+	bus.Register(b, remove_access_protection.Handler(appsStore, appsStore))
+	bus.Register(b, configure_proxy_rules.Handler(appsStore, appsStore))
+	bus.Register(b, remove_proxy_rules.Handler(appsStore, appsStore))
+	bus.Register(b, configure_compose_override.Handler(appsStore, appsStore))
+	bus.Register(b, remove_compose_override.Handler(appsStore, appsStore))
+	bus.Register(b, configure_url_aliases.Handler(appsStore, appsStore))
+	bus.Register(b, remove_url_aliases.Handler(appsStore, appsStore))
+	bus.Register(b, scale_service.Handler(appsStore, appsStore, targetsStore, providerFacade))
+	bus.Register(b, restart_services.Handler(appsStore, deploymentsStore, targetsStore, providerFacade, deploymentTransitionsStore))
+	bus.Register(b, export_env_vars.Handler(appsStore))
+	bus.Register(b, get_deployment_log.Handler(appsStore, deploymentsStore, artifactManager))
+	bus.Register(b, get_deployment_reports.Handler(appsStore, deploymentsStore, artifactManager))
+	bus.Register(b, get_deployment_build_context.Handler(appsStore, deploymentsStore, artifactManager))
+	bus.Register(b, get_deployment_log_steps.Handler(appsStore, deploymentsStore, artifactManager))
+	bus.Register(b, follow_deployment_log.Handler(appsStore, deploymentsStore, artifactManager))
+	bus.Register(b, get_app_logs.Handler(appsStore, targetsStore, providerFacade))
+	bus.Register(b, exec_service.Handler(appsStore, targetsStore, providerFacade))
 	bus.Register(b, redeploy.Handler(appsStore, deploymentsStore, deploymentsStore))
 	bus.Register(b, promote.Handler(appsStore, deploymentsStore, deploymentsStore))
+	bus.Register(b, redeploy_target.Handler(targetsStore, appsStore, deploymentsStore, deploymentsStore))
+	bus.Register(b, retry_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, artifactManager))
+	bus.Register(b, annotate_deployment.Handler(appsStore, deploymentsStore, deploymentsStore))
+	bus.Register(b, generate_badge_token.Handler(appsStore, appsStore, opts.Secret()))
+	bus.Register(b, revoke_badge_token.Handler(appsStore, appsStore))
+	bus.Register(b, get_app_badge.Handler(appsStore, deploymentsStore, opts.Secret()))
+	bus.Register(b, configure_deploy_trigger.Handler(appsStore, appsStore))
+	bus.Register(b, remove_deploy_trigger.Handler(appsStore, appsStore))
+	bus.Register(b, configure_freeze.Handler(appsStore, appsStore))
+	bus.Register(b, configure_timeouts.Handler(appsStore, appsStore))
+	bus.Register(b, unfreeze_deployment.Handler(deploymentsStore, deploymentsStore))
+	bus.Register(b, verify_deploy_trigger.Handler(appsStore))
 	bus.Register(b, create_target.Handler(targetsStore, targetsStore, providerFacade))
 	bus.Register(b, configure_target.Handler(targetsStore, targetsStore, providerFacade))
 	bus.Register(b, reconfigure_target.Handler(targetsStore, targetsStore))
+	bus.Register(b, request_proxy_upgrade.Handler(targetsStore, targetsStore, providerFacade))
+	bus.Register(b, upgrade_proxy.Handler(targetsStore, targetsStore, providerFacade))
+	bus.Register(b, queue_proxy_upgrades.Handler(targetsStore, targetsStore, providerFacade))
+	bus.Register(b, queue_garbage_collections.Handler(targetsStore, targetsStore))
+	bus.Register(b, collect_garbage.Handler(targetsStore, targetsStore, providerFacade, opts.TargetsGC()))
 	bus.Register(b, update_target.Handler(targetsStore, targetsStore, providerFacade))
 	bus.Register(b, request_target_cleanup.Handler(targetsStore, targetsStore, appsStore))
 	bus.Register(b, cleanup_target.Handler(targetsStore, deploymentsStore, providerFacade))
@@ -94,16 +318,56 @@ func Setup(
 	bus.Register(b, create_registry.Handler(registriesStore, registriesStore))
 	bus.Register(b, update_registry.Handler(registriesStore, registriesStore))
 	bus.Register(b, delete_registry.Handler(registriesStore, registriesStore))
+	bus.Register(b, create_team.Handler(teamsStore))
+	bus.Register(b, update_team.Handler(teamsStore, teamsStore))
+	bus.Register(b, delete_team.Handler(teamsStore, teamsStore, appsStore))
+	bus.Register(b, create_peer.Handler(peersStore))
+	bus.Register(b, update_peer.Handler(peersStore, peersStore))
+	bus.Register(b, delete_peer.Handler(peersStore, peersStore))
+	bus.Register(b, create_dns_zone.Handler(dnsZonesStore, dnsZonesStore))
+	bus.Register(b, update_dns_zone.Handler(dnsZonesStore, dnsZonesStore))
+	bus.Register(b, delete_dns_zone.Handler(dnsZonesStore, dnsZonesStore))
+	bus.Register(b, sync_dns_records.Handler(dnsZonesStore, dnsRecordsStore, dnsRecordsStore, dns.NewFacade(
+		dns.NewCloudflare(""),
+		dns.NewDesec(""),
+		dns.NewRoute53(""),
+	)))
+	bus.Register(b, get_federated_apps.Handler(peersStore, deploymentQueryHandler.GetAllApps, federation.NewClient()))
+	bus.Register(b, create_saved_filter.Handler(savedFiltersStore))
+	bus.Register(b, delete_saved_filter.Handler(savedFiltersStore, savedFiltersStore))
+	bus.Register(b, sync_gitops.Handler(gitOpsRunsStore, gitOpsRunsStore, gitops.New(opts.GitOps(), logger, b)))
 	bus.Register(b, deploymentQueryHandler.GetAllApps)
 	bus.Register(b, deploymentQueryHandler.GetAppByID)
 	bus.Register(b, deploymentQueryHandler.GetAllDeploymentsByApp)
+	bus.Register(b, deploymentQueryHandler.GetDeploymentsFeed)
 	bus.Register(b, deploymentQueryHandler.GetDeploymentByID)
 	bus.Register(b, deploymentQueryHandler.GetAllTargets)
 	bus.Register(b, deploymentQueryHandler.GetTargetByID)
+	bus.Register(b, deploymentQueryHandler.GetTargetsCapacity)
 	bus.Register(b, deploymentQueryHandler.GetRegistries)
 	bus.Register(b, deploymentQueryHandler.GetRegistryByID)
+	bus.Register(b, deploymentQueryHandler.GetTeams)
+	bus.Register(b, deploymentQueryHandler.GetTeamByID)
+	bus.Register(b, deploymentQueryHandler.GetArtifactsUsage)
+	bus.Register(b, deploymentQueryHandler.GetAppResourceUsage)
+	bus.Register(b, deploymentQueryHandler.GetAppTraffic)
+	bus.Register(b, deploymentQueryHandler.GetAppIncidents)
+	bus.Register(b, deploymentQueryHandler.GetAppMonitors)
+	bus.Register(b, deploymentQueryHandler.GetAppAddons)
+	bus.Register(b, deploymentQueryHandler.GetAddonBackups)
+	bus.Register(b, deploymentQueryHandler.GetAppArchives)
+	bus.Register(b, deploymentQueryHandler.GetTrashedApps)
+	bus.Register(b, deploymentQueryHandler.GetAppEnvRevisions)
+	bus.Register(b, deploymentQueryHandler.GetGitOpsRuns)
+	bus.Register(b, deploymentQueryHandler.GetSavedFilters)
+	bus.Register(b, deploymentQueryHandler.GetDeploymentStats)
+	bus.Register(b, deploymentQueryHandler.GetPeers)
+	bus.Register(b, deploymentQueryHandler.GetPeerByID)
+	bus.Register(b, deploymentQueryHandler.GetDnsZones)
+	bus.Register(b, deploymentQueryHandler.GetDnsZoneByID)
 
 	bus.On(b, deploy.OnDeploymentCreatedHandler(scheduler))
+	bus.On(b, deploy.OnDeploymentRetriedHandler(scheduler))
 	bus.On(b, redeploy.OnAppEnvChangedHandler(appsStore, deploymentsStore, deploymentsStore))
 	bus.On(b, delete_app.OnAppCleanupRequestedHandler(scheduler))
 	bus.On(b, cleanup_app.OnAppEnvChangedHandler(scheduler))
@@ -118,13 +382,63 @@ func Setup(
 	bus.On(b, configure_target.OnAppEnvChangedHandler(targetsStore, targetsStore))
 	bus.On(b, configure_target.OnAppCleanupRequestedHandler(targetsStore, targetsStore))
 	bus.On(b, delete_target.OnTargetCleanupRequestedHandler(scheduler))
+	bus.On(b, upgrade_proxy.OnTargetProxyUpgradeRequestedHandler(scheduler))
+	bus.On(b, collect_garbage.OnTargetGarbageCollectionRequestedHandler(scheduler))
+	bus.On(b, sync_dns_records.OnDeploymentStateChangedHandler(scheduler))
+	bus.On(b, sync_dns_records.OnAppCleanupRequestedHandler(scheduler))
+	bus.On(b, sync_dns_records.OnDnsZoneCreatedHandler(scheduler))
+	bus.On(b, sync_dns_records.OnDnsZoneAddressesChangedHandler(scheduler))
+	bus.On(b, provision_addon.OnAddonCreatedHandler(scheduler))
+	bus.On(b, provision_addon.OnAddonTargetChangedHandler(scheduler))
+	bus.On(b, provision_addon.OnAppEnvChangedHandler(addonsStore, addonsStore))
+	bus.On(b, request_addon_cleanup.OnAppCleanupRequestedHandler(addonsStore, addonsStore))
+	bus.On(b, cleanup_addon.OnAddonCleanupRequestedHandler(scheduler))
+	bus.On(b, cleanup_addon.OnAddonTargetChangedHandler(scheduler))
+	bus.On(b, backup_addon.OnAddonBackupCreatedHandler(scheduler))
+	bus.On(b, backup_addon.OnAddonDeletedHandler(addonBackups))
+	bus.On(b, export_app.OnAppArchiveCreatedHandler(scheduler))
+	bus.On(b, restore_addon_backup.OnAddonRestoreRequestedHandler(scheduler))
+	bus.On(b, record_env_revision.OnAppCreatedHandler(envRevisionsStore))
+	bus.On(b, record_env_revision.OnAppEnvChangedHandler(envRevisionsStore))
+	bus.On(b, record_deployment_history.OnDeploymentStateChangedHandler(deploymentsStore, artifactManager, deploymentHistoryStore))
+	bus.On(b, record_deployment_transition.OnDeploymentCreatedHandler(deploymentTransitionsStore))
+	bus.On(b, record_deployment_transition.OnDeploymentStateChangedHandler(deploymentTransitionsStore))
+	bus.On(b, record_deployment_transition.OnDeploymentRetriedHandler(deploymentTransitionsStore))
+
+	event.OnAsync(b, pool, broadcast_changes.OnDeploymentCreatedHandler(appsStore, publisher))
+	event.OnAsync(b, pool, broadcast_changes.OnDeploymentStateChangedHandler(appsStore, publisher))
+	event.OnAsync(b, pool, broadcast_changes.OnTargetStateChangedHandler(targetsStore, publisher))
+
+	checker.Register("artifacts", health.Writable(opts.DataDir()))
+	checker.Register("docker", dock.Ping)
 
 	if err := db.Migrate(deploymentsqlite.Migrations); err != nil {
 		return err
 	}
 
 	// Fail running deployments in case of a hard reset.
-	return deploymentsStore.FailDeployments(context.Background(), errors.New("server_reset"), domain.FailCriterias{
+	if err := deploymentsStore.FailDeployments(context.Background(), errors.New("server_reset"), domain.FailCriterias{
 		Status: monad.Value(domain.DeploymentStatusRunning),
-	})
+	}); err != nil {
+		return err
+	}
+
+	// Make sure every target waiting for a configuration has a job to do it (after a data import for example).
+	// Thanks to the merge policy, already queued jobs will not be duplicated.
+	targets, err := targetsStore.GetConfiguring(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if err = scheduler.Queue(context.Background(), configure_target.Command{
+			ID:      string(target.ID()),
+			Version: target.CurrentVersion(),
+		}, bus.WithGroup(app.TargetConfigurationGroup(target.ID())), bus.WithPolicy(bus.JobPolicyMerge)); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/docker/compose/v2/pkg/api"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	routerLabelPrefix      = "traefik.http.routers."
+	routerLabelSuffix      = ".service"
+	dockerProviderSuffix   = "@docker"
+	maxAccessLogLineLength = 1024 * 1024
+)
+
+type (
+	// Entry written by the proxy for each request when access logs are enabled in the
+	// json format. Only the fields needed are decoded.
+	accessLogEntry struct {
+		RouterName       string `json:"RouterName"`
+		DownstreamStatus int    `json:"DownstreamStatus"`
+		Duration         int64  `json:"Duration"` // In nanoseconds
+	}
+
+	routedService struct {
+		app     domain.AppID
+		env     domain.Environment
+		service string
+	}
+)
+
+func (d *docker) AccessLogs(ctx context.Context, target domain.Target, since, until time.Time) ([]domain.AccessLog, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	containers, err := client.api.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", TargetLabel+"="+string(target.ID()))),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		proxyID     string
+		projectName = proxyProjectName(target.ID())
+		routers     = make(map[string]routedService)
+	)
+
+	// Routers are named after entrypoints so the labels of running containers are
+	// used to know which application service a request has been served to.
+	for _, cont := range containers {
+		if cont.Labels[api.ProjectLabel] == projectName && cont.Labels[api.ServiceLabel] == proxyServiceName {
+			proxyID = cont.ID
+			continue
+		}
+
+		app, isApp := cont.Labels[AppLabel]
+
+		if !isApp {
+			continue
+		}
+
+		for label := range cont.Labels {
+			if !strings.HasPrefix(label, routerLabelPrefix) || !strings.HasSuffix(label, routerLabelSuffix) {
+				continue
+			}
+
+			routers[strings.TrimSuffix(strings.TrimPrefix(label, routerLabelPrefix), routerLabelSuffix)+dockerProviderSuffix] = routedService{
+				app:     domain.AppID(app),
+				env:     domain.Environment(cont.Labels[EnvironmentLabel]),
+				service: cont.Labels[api.ServiceLabel],
+			}
+		}
+	}
+
+	if proxyID == "" {
+		return nil, nil
+	}
+
+	logs, err := client.api.ContainerLogs(ctx, proxyID, container.LogsOptions{
+		ShowStdout: true,
+		Since:      eventsTimestamp(since),
+		Until:      eventsTimestamp(until),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer logs.Close()
+
+	var stdout bytes.Buffer
+
+	if _, err = stdcopy.StdCopy(&stdout, io.Discard, logs); err != nil {
+		return nil, err
+	}
+
+	var (
+		result  []domain.AccessLog
+		scanner = bufio.NewScanner(&stdout)
+	)
+
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxAccessLogLineLength)
+
+	for scanner.Scan() {
+		var entry accessLogEntry
+
+		// The proxy own logs are written to the same output and are not json encoded
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		routed, found := routers[entry.RouterName]
+
+		if !found {
+			continue
+		}
+
+		result = append(result, domain.AccessLog{
+			AppID:       routed.app,
+			Environment: routed.env,
+			Service:     routed.service,
+			Status:      entry.DownstreamStatus,
+			Duration:    time.Duration(entry.Duration),
+		})
+	}
+
+	return result, scanner.Err()
+}
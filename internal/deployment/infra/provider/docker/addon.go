@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+const AddonLabel = "app.seelf.addon" // ID of the add-on managed by seelf
+
+// Builds the compose project running the service of an add-on. The add-on gets its own
+// network, named after its host, which app services join to reach it.
+func newAddonProject(addon domain.Addon) *types.Project {
+	var (
+		host        = addon.Host()
+		kind        = addon.Kind()
+		serviceName = string(kind)
+		credentials = addon.Credentials()
+		labels      = types.Labels{
+			AppLabel:         string(addon.AppID()),
+			TargetLabel:      string(addon.Target()),
+			EnvironmentLabel: string(addon.Environment()),
+			AddonLabel:       string(addon.ID()),
+		}
+		service = types.ServiceConfig{
+			Name:          serviceName,
+			ContainerName: host,
+			Labels:        labels,
+			Restart:       types.RestartPolicyUnlessStopped,
+			CustomLabels:  getProjectCustomLabels(host, serviceName, ""),
+			Networks: map[string]*types.ServiceNetworkConfig{
+				"default": nil,
+			},
+		}
+		dataPath string
+	)
+
+	switch kind {
+	case domain.AddonPostgres:
+		service.Image = "postgres:16-alpine"
+		service.Environment = types.NewMappingWithEquals([]string{
+			"POSTGRES_USER=" + credentials.Username(),
+			"POSTGRES_PASSWORD=" + credentials.Password(),
+			"POSTGRES_DB=" + addon.Database(),
+		})
+		dataPath = "/var/lib/postgresql/data"
+	case domain.AddonMySQL:
+		service.Image = "mysql:8.4"
+		service.Environment = types.NewMappingWithEquals([]string{
+			"MYSQL_RANDOM_ROOT_PASSWORD=yes",
+			"MYSQL_USER=" + credentials.Username(),
+			"MYSQL_PASSWORD=" + credentials.Password(),
+			"MYSQL_DATABASE=" + addon.Database(),
+		})
+		dataPath = "/var/lib/mysql"
+	case domain.AddonRedis:
+		service.Image = "redis:7-alpine"
+		service.Command = types.ShellCommand{"redis-server", "--appendonly", "yes", "--requirepass", credentials.Password()}
+		dataPath = "/data"
+	}
+
+	service.Volumes = []types.ServiceVolumeConfig{
+		{Type: types.VolumeTypeVolume, Source: "data", Target: dataPath},
+	}
+
+	return &types.Project{
+		Name: host,
+		Services: types.Services{
+			serviceName: service,
+		},
+		Networks: types.Networks{
+			"default": types.NetworkConfig{
+				Name:   host,
+				Labels: labels,
+			},
+		},
+		Volumes: types.Volumes{
+			"data": types.VolumeConfig{
+				Name:   host + "_data",
+				Labels: labels,
+			},
+		},
+	}
+}
+
+// Shell command, run inside the add-on container, which dumps its data to the standard
+// output. Credentials are taken from the container environment.
+func addonBackupCommand(kind domain.AddonKind) []string {
+	switch kind {
+	case domain.AddonPostgres:
+		return []string{"sh", "-c", `exec pg_dump --clean --if-exists --no-owner -U "$POSTGRES_USER" "$POSTGRES_DB"`}
+	default:
+		return []string{"sh", "-c", `MYSQL_PWD="$MYSQL_PASSWORD" exec mysqldump --single-transaction --no-tablespaces -u "$MYSQL_USER" "$MYSQL_DATABASE"`}
+	}
+}
+
+// Shell command, run inside the add-on container, which restores a dump made by the
+// backup command read from the standard input.
+func addonRestoreCommand(kind domain.AddonKind) []string {
+	switch kind {
+	case domain.AddonPostgres:
+		return []string{"sh", "-c", `exec psql -q -v ON_ERROR_STOP=1 -U "$POSTGRES_USER" -d "$POSTGRES_DB"`}
+	default:
+		return []string{"sh", "-c", `MYSQL_PWD="$MYSQL_PASSWORD" exec mysql -u "$MYSQL_USER" "$MYSQL_DATABASE"`}
+	}
+}
@@ -5,8 +5,29 @@ import "github.com/YuukanOO/seelf/pkg/monad"
 // Request payload when wanting to instantiate a ProviderConfig
 // If the Host field is omitted, the provider will consider it's a local target.
 type Body struct {
-	Host       monad.Maybe[string] `json:"host"`
+	Host       monad.Maybe[string]      `json:"host"`
+	Port       monad.Maybe[int]         `json:"port"`
+	User       monad.Maybe[string]      `json:"user"`
+	PrivateKey monad.Patch[string]      `json:"private_key"`
+	HostKey    monad.Maybe[string]      `json:"host_key"`
+	Bastion    monad.Maybe[BastionBody] `json:"bastion"`
+	Tls        monad.Maybe[TlsBody]     `json:"tls"`
+}
+
+// Jump host through which the docker host is reached over SSH. If the PrivateKey
+// field is omitted, the existing one will be kept.
+type BastionBody struct {
+	Host       string              `json:"host"`
 	Port       monad.Maybe[int]    `json:"port"`
 	User       monad.Maybe[string] `json:"user"`
 	PrivateKey monad.Patch[string] `json:"private_key"`
+	HostKey    monad.Maybe[string] `json:"host_key"`
+}
+
+// TLS material used to connect to a docker daemon over TCP instead of SSH.
+// If the Key field is omitted, the existing one will be kept.
+type TlsBody struct {
+	CA   string              `json:"ca"`
+	Cert string              `json:"cert"`
+	Key  monad.Maybe[string] `json:"key"`
 }
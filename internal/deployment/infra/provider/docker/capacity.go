@@ -0,0 +1,115 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const (
+	diskUsageImage = "busybox:1.36"
+	diskMountPath  = "/data"
+)
+
+var ErrDiskUsageFailed = errors.New("disk_usage_failed")
+
+func (d *docker) Capacity(ctx context.Context, target domain.Target) (capacity domain.TargetCapacity, err error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return capacity, err
+	}
+
+	defer client.Close()
+
+	containers, err := client.api.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", AppLabel),
+			filters.Arg("label", TargetLabel+"="+string(target.ID())),
+			filters.Arg("status", "running"),
+		),
+	})
+
+	if err != nil {
+		return capacity, err
+	}
+
+	capacity.RunningContainers = len(containers)
+
+	usage, err := client.api.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.ImageObject},
+	})
+
+	if err != nil {
+		return capacity, err
+	}
+
+	capacity.ImagesSize = usage.LayersSize
+
+	capacity.DiskTotal, capacity.DiskFree, err = diskSpace(ctx, client)
+
+	return capacity, err
+}
+
+// Retrieve the size and free space of the disk holding the docker data directory, which
+// contains images and volumes, by running df in a one-off container since the daemon
+// does not expose them.
+func diskSpace(ctx context.Context, client *client) (total, free int64, err error) {
+	info, err := client.api.Info(ctx)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var stdout, stderr strings.Builder
+
+	code, err := client.Run(ctx, diskUsageImage,
+		[]string{"df", "-Pk", diskMountPath},
+		[]string{info.DockerRootDir + ":" + diskMountPath + ":ro"},
+		&stdout, &stderr)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if code != 0 {
+		return 0, 0, fmt.Errorf("%w: df exited with code %d: %s", ErrDiskUsageFailed, code, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseDf(stdout.String())
+}
+
+// Parses the POSIX output of df, sizes being given in 1024-byte blocks:
+//
+//	Filesystem     1024-blocks     Used Available Capacity Mounted on
+//	/dev/sda1         40581564 12345678  28235886      31% /data
+func parseDf(output string) (total, free int64, err error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("%w: unexpected df output %q", ErrDiskUsageFailed, output)
+	}
+
+	fields := strings.Fields(lines[len(lines)-1])
+
+	if len(fields) < 6 {
+		return 0, 0, fmt.Errorf("%w: unexpected df output %q", ErrDiskUsageFailed, output)
+	}
+
+	if total, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrDiskUsageFailed, err)
+	}
+
+	if free, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrDiskUsageFailed, err)
+	}
+
+	return total * 1024, free * 1024, nil
+}
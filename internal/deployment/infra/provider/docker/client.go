@@ -2,7 +2,18 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"net"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/monad"
@@ -14,10 +25,15 @@ import (
 	"github.com/docker/compose/v2/pkg/compose"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	dclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/tlsconfig"
 )
 
 // Wraps a docker API client and compose service and expose some utility methods.
@@ -29,7 +45,42 @@ type client struct {
 	registries []string
 }
 
-func connect(ctx context.Context, out io.Writer, host monad.Maybe[ssh.Host], registries ...domain.Registry) (*client, error) {
+// Archive read from an image which removes the container used to read it when closed.
+type imageArchive struct {
+	io.ReadCloser
+	remove func() error
+}
+
+func (a *imageArchive) Close() error {
+	return errors.Join(a.ReadCloser.Close(), a.remove())
+}
+
+// Where to reach a docker daemon. The zero value represents the local one.
+type endpoint struct {
+	host   monad.Maybe[ssh.Host]
+	tlsDir monad.Maybe[string] // Directory containing the TLS material if reached over TCP
+	port   int
+}
+
+func newEndpoint(id domain.TargetID, config Data) endpoint {
+	e := endpoint{host: config.Host}
+
+	if config.Tls.HasValue() {
+		e.tlsDir.Set(targetTlsDir(id))
+		e.port = config.Port.Get(defaultTlsPort)
+	}
+
+	return e
+}
+
+// Returns the host to reach over SSH, if any.
+func (e endpoint) sshHost() (ssh.Host, bool) {
+	host, isRemote := e.host.TryGet()
+
+	return host, isRemote && !e.tlsDir.HasValue()
+}
+
+func connect(ctx context.Context, out io.Writer, to endpoint, registries ...domain.Registry) (*client, error) {
 	stream := io.Discard
 
 	if out != nil {
@@ -44,8 +95,19 @@ func connect(ctx context.Context, out io.Writer, host monad.Maybe[ssh.Host], reg
 
 	opts := flags.NewClientOptions()
 
-	if h, isRemote := host.TryGet(); isRemote {
-		opts.Hosts = append(opts.Hosts, "ssh://"+h.String())
+	if h, isRemote := to.host.TryGet(); isRemote {
+		if dir, useTls := to.tlsDir.TryGet(); useTls {
+			opts.Hosts = append(opts.Hosts, "tcp://"+net.JoinHostPort(h.String(), strconv.Itoa(to.port)))
+			opts.TLS = true
+			opts.TLSVerify = true
+			opts.TLSOptions = &tlsconfig.Options{
+				CAFile:   filepath.Join(dir, tlsCAFilename),
+				CertFile: filepath.Join(dir, tlsCertFilename),
+				KeyFile:  filepath.Join(dir, tlsKeyFilename),
+			}
+		} else {
+			opts.Hosts = append(opts.Hosts, "ssh://"+h.String())
+		}
 	}
 
 	if err = dockerCli.Initialize(opts); err != nil {
@@ -178,6 +240,588 @@ func (c *client) RemoveResources(ctx context.Context, criteria filters.Args) err
 	return nil
 }
 
+// Run a command inside the first running container of the given compose service and
+// return its exit code.
+func (c *client) Exec(ctx context.Context, project string, exec domain.ServiceExec) (int, error) {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.ServiceLabel+"="+exec.Service),
+			filters.Arg("status", "running"),
+		),
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(containers) == 0 {
+		return 0, domain.ErrServiceNotRunning
+	}
+
+	created, err := c.api.ContainerExecCreate(ctx, containers[0].ID, types.ExecConfig{
+		Cmd:          exec.Command,
+		Tty:          exec.Tty,
+		AttachStdin:  exec.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	attached, err := c.api.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{
+		Tty: exec.Tty,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer attached.Close()
+
+	if exec.Stdin != nil {
+		go func() {
+			io.Copy(attached.Conn, exec.Stdin)
+			attached.CloseWrite()
+		}()
+	}
+
+	stdout := exec.Stdout
+
+	if stdout == nil {
+		stdout = io.Discard
+	}
+
+	stderr := exec.Stderr
+
+	if stderr == nil {
+		stderr = stdout
+	}
+
+	// Without a pseudo terminal, outputs are multiplexed on the same connection
+	if exec.Tty {
+		_, err = io.Copy(stdout, attached.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, attached.Reader)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := c.api.ContainerExecInspect(ctx, created.ID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ExitCode, nil
+}
+
+// Change the number of containers of the given compose service by cloning the first one
+// or removing the last ones, as compose would do, so the project does not have to be
+// loaded again. When scaled to 0, the first container is stopped but kept around to be
+// started again later.
+func (c *client) Scale(ctx context.Context, project, service string, replicas uint) error {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.ServiceLabel+"="+service),
+			filters.Arg("label", api.OneoffLabel+"=False"),
+		),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(containers) == 0 {
+		return domain.ErrServiceNotRunning
+	}
+
+	slices.SortFunc(containers, func(a, b types.Container) int {
+		return containerNumber(a) - containerNumber(b)
+	})
+
+	// Remove extra containers, the first one is always kept as a template
+	for len(containers) > max(int(replicas), 1) {
+		last := containers[len(containers)-1]
+
+		if err = c.api.ContainerRemove(ctx, last.ID, container.RemoveOptions{Force: true}); err != nil {
+			return err
+		}
+
+		containers = containers[:len(containers)-1]
+	}
+
+	first := containers[0]
+
+	if replicas == 0 {
+		if first.State != "running" {
+			return nil
+		}
+
+		return c.api.ContainerStop(ctx, first.ID, container.StopOptions{})
+	}
+
+	if first.State != "running" {
+		if err = c.api.ContainerStart(ctx, first.ID, container.StartOptions{}); err != nil {
+			return err
+		}
+	}
+
+	if len(containers) >= int(replicas) {
+		return nil
+	}
+
+	template, err := c.api.ContainerInspect(ctx, first.ID)
+
+	if err != nil {
+		return err
+	}
+
+	number := containerNumber(containers[len(containers)-1])
+
+	for i := len(containers); i < int(replicas); i++ {
+		number++
+
+		if err = c.cloneContainer(ctx, template, project+"-"+service+"-"+strconv.Itoa(number), number); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restart containers of the given services, stopped ones included, or every service of
+// the project if none is given.
+func (c *client) Restart(ctx context.Context, project string, services []string) error {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.OneoffLabel+"=False"),
+		),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(services) > 0 {
+		containers = slices.DeleteFunc(containers, func(cont types.Container) bool {
+			return !slices.Contains(services, cont.Labels[api.ServiceLabel])
+		})
+	}
+
+	// Every requested service should have at least one container
+	for _, service := range services {
+		if !slices.ContainsFunc(containers, func(cont types.Container) bool {
+			return cont.Labels[api.ServiceLabel] == service
+		}) {
+			return domain.ErrServiceNotRunning
+		}
+	}
+
+	if len(containers) == 0 {
+		return domain.ErrServiceNotRunning
+	}
+
+	for _, cont := range containers {
+		if err = c.api.ContainerRestart(ctx, cont.ID, container.StopOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *client) cloneContainer(ctx context.Context, template types.ContainerJSON, name string, number int) error {
+	config := *template.Config
+	config.Hostname = "" // Let the engine assign one based on the new container ID
+	config.Labels = maps.Clone(config.Labels)
+	config.Labels[api.ContainerNumberLabel] = strconv.Itoa(number)
+
+	var (
+		primary   = string(template.HostConfig.NetworkMode)
+		endpoints = make(map[string]*network.EndpointSettings)
+	)
+
+	if template.NetworkSettings != nil {
+		for networkName, endpoint := range template.NetworkSettings.Networks {
+			// Aliases specific to the template container must not be given to the clone
+			endpoints[networkName] = &network.EndpointSettings{
+				Aliases: slices.DeleteFunc(slices.Clone(endpoint.Aliases), func(alias string) bool {
+					return strings.HasPrefix(template.ID, alias) || "/"+alias == template.Name
+				}),
+			}
+		}
+	}
+
+	var networking *network.NetworkingConfig
+
+	if endpoint, found := endpoints[primary]; found {
+		networking = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{primary: endpoint},
+		}
+	}
+
+	created, err := c.api.ContainerCreate(ctx, &config, template.HostConfig, networking, nil, name)
+
+	if err != nil {
+		return err
+	}
+
+	// Older engines only accept a single network when creating a container
+	for networkName, endpoint := range endpoints {
+		if networkName == primary {
+			continue
+		}
+
+		if err = c.api.NetworkConnect(ctx, networkName, created.ID, endpoint); err != nil {
+			return err
+		}
+	}
+
+	return c.api.ContainerStart(ctx, created.ID, container.StartOptions{})
+}
+
+func containerNumber(c types.Container) int {
+	number, _ := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
+	return number
+}
+
+// Run a one-off container of the given image, pulled if missing, and return its exit
+// code once it has stopped.
+func (c *client) Run(ctx context.Context, ref string, cmd []string, binds []string, stdout, stderr io.Writer) (int, error) {
+	if _, _, err := c.api.ImageInspectWithRaw(ctx, ref); errdefs.IsNotFound(err) {
+		progress, err := c.api.ImagePull(ctx, ref, image.PullOptions{})
+
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = io.Copy(io.Discard, progress)
+		progress.Close()
+
+		if err != nil {
+			return 0, err
+		}
+	} else if err != nil {
+		return 0, err
+	}
+
+	created, err := c.api.ContainerCreate(ctx, &container.Config{
+		Image:        ref,
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	}, &container.HostConfig{
+		Binds: binds,
+	}, nil, nil, "")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.api.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+
+	attached, err := c.api.ContainerAttach(ctx, created.ID, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer attached.Close()
+
+	// Wait must be requested before starting the container to not miss its exit
+	statusC, errC := c.api.ContainerWait(ctx, created.ID, container.WaitConditionNextExit)
+
+	if err = c.api.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return 0, err
+	}
+
+	if _, err = stdcopy.StdCopy(stdout, stderr, attached.Reader); err != nil {
+		return 0, err
+	}
+
+	select {
+	case status := <-statusC:
+		return int(status.StatusCode), nil
+	case err = <-errC:
+		return 0, err
+	}
+}
+
+// Read the given path of an image, without running it, as a tar archive. The returned
+// reader must be closed to remove the container created to read it.
+func (c *client) CopyFromImage(ctx context.Context, ref, path string) (io.ReadCloser, error) {
+	created, err := c.api.ContainerCreate(ctx, &container.Config{Image: ref}, nil, nil, nil, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	remove := func() error {
+		return c.api.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+	}
+
+	content, _, err := c.api.CopyFromContainer(ctx, created.ID, path)
+
+	if err != nil {
+		_ = remove()
+		return nil, err
+	}
+
+	return &imageArchive{content, remove}, nil
+}
+
+// Extract the given tar archive at the given path of a container.
+func (c *client) CopyToContainer(ctx context.Context, name, path string, content io.Reader) error {
+	return c.api.CopyToContainer(ctx, name, path, content, types.CopyToContainerOptions{})
+}
+
+// Retrieve resources consumed by running containers matching the given filters. Since the
+// daemon waits for two samples to compute the CPU usage, containers are read concurrently.
+func (c *client) Stats(ctx context.Context, criteria filters.Args) ([]domain.ServiceStats, error) {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		Filters: criteria,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		stats []domain.ServiceStats
+		errs  []error
+	)
+
+	for _, cont := range containers {
+		wg.Add(1)
+
+		go func(cont types.Container) {
+			defer wg.Done()
+
+			s, err := c.containerStats(ctx, cont)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				stats = append(stats, s)
+			case !errdefs.IsNotFound(err): // The container may have been removed since it was listed
+				errs = append(errs, err)
+			}
+		}(cont)
+	}
+
+	wg.Wait()
+
+	if err = errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return domain.MergeServiceStats(stats), nil
+}
+
+func (c *client) containerStats(ctx context.Context, cont types.Container) (domain.ServiceStats, error) {
+	response, err := c.api.ContainerStats(ctx, cont.ID, false)
+
+	if err != nil {
+		return domain.ServiceStats{}, err
+	}
+
+	defer response.Body.Close()
+
+	var data types.StatsJSON
+
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return domain.ServiceStats{}, err
+	}
+
+	result := domain.ServiceStats{
+		AppID:       domain.AppID(cont.Labels[AppLabel]),
+		Environment: domain.Environment(cont.Labels[EnvironmentLabel]),
+		Service:     cont.Labels[api.ServiceLabel],
+		CPU:         cpuPercent(data.Stats),
+		Memory:      memoryUsage(data.MemoryStats),
+		MemoryLimit: int64(data.MemoryStats.Limit),
+	}
+
+	for _, network := range data.Networks {
+		result.NetworkRx += int64(network.RxBytes)
+		result.NetworkTx += int64(network.TxBytes)
+	}
+
+	return result, nil
+}
+
+// Retrieve incidents which happened to containers matching the given filters between
+// the two dates. Containers exiting after being asked to, by a kill or a stop, are not
+// reported and neither are those exiting successfully unless they have been restarted.
+func (c *client) Incidents(ctx context.Context, criteria filters.Args, since, until time.Time) ([]domain.ContainerIncident, error) {
+	criteria.Add("type", string(events.ContainerEventType))
+
+	for _, action := range []events.Action{events.ActionKill, events.ActionOOM, events.ActionDie, events.ActionStart} {
+		criteria.Add("event", string(action))
+	}
+
+	messages, errs := c.api.Events(ctx, types.EventsOptions{
+		Since:   eventsTimestamp(since),
+		Until:   eventsTimestamp(until),
+		Filters: criteria,
+	})
+
+	var (
+		incidents []domain.ContainerIncident
+		killed    = make(map[string]bool)
+		oomKilled = make(map[string]bool)
+		exited    = make(map[string]int) // Index of the last incident of each container waiting for a restart
+	)
+
+	for {
+		select {
+		case msg := <-messages:
+			id := msg.Actor.ID
+
+			switch msg.Action {
+			case events.ActionKill:
+				killed[id] = true
+			case events.ActionOOM:
+				oomKilled[id] = true
+			case events.ActionDie:
+				if killed[id] && !oomKilled[id] {
+					delete(killed, id)
+					continue
+				}
+
+				incident := domain.ContainerIncident{
+					AppID:       domain.AppID(msg.Actor.Attributes[AppLabel]),
+					Environment: domain.Environment(msg.Actor.Attributes[EnvironmentLabel]),
+					Service:     msg.Actor.Attributes[api.ServiceLabel],
+					Container:   msg.Actor.Attributes["name"],
+					Kind:        domain.IncidentCrashed,
+					OccurredAt:  time.Unix(0, msg.TimeNano).UTC(),
+				}
+
+				if code, err := strconv.Atoi(msg.Actor.Attributes["exitCode"]); err == nil {
+					incident.ExitCode.Set(code)
+				}
+
+				if oomKilled[id] {
+					incident.Kind = domain.IncidentOutOfMemory
+				}
+
+				delete(killed, id)
+				delete(oomKilled, id)
+				exited[id] = len(incidents)
+				incidents = append(incidents, incident)
+			case events.ActionStart:
+				if idx, found := exited[id]; found && incidents[idx].Kind == domain.IncidentCrashed {
+					incidents[idx].Kind = domain.IncidentRestarted
+				}
+
+				delete(exited, id)
+			}
+		case err := <-errs:
+			if !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+
+			// Containers which have exited successfully on their own are not incidents
+			return slices.DeleteFunc(incidents, func(incident domain.ContainerIncident) bool {
+				return incident.Kind == domain.IncidentCrashed && incident.ExitCode.Get(-1) == 0
+			}), nil
+		}
+	}
+}
+
+// Retrieve the image of the running container of the given compose project service, if any.
+func (c *client) ServiceImage(ctx context.Context, project, service string) (monad.Maybe[string], error) {
+	var image monad.Maybe[string]
+
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.ServiceLabel+"="+service),
+			filters.Arg("status", "running"),
+		),
+	})
+
+	if err != nil || len(containers) == 0 {
+		return image, err
+	}
+
+	image.Set(containers[0].Image)
+
+	return image, nil
+}
+
+// Retrieve the platform of the docker engine.
+func (c *client) Platform(ctx context.Context) (domain.Platform, error) {
+	version, err := c.api.ServerVersion(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	return domain.PlatformFrom(version.Os + "/" + version.Arch)
+}
+
 func (c *client) Close() error {
 	return c.api.Close()
 }
+
+// Computes the CPU usage the same way the docker CLI does, as a percentage of a single CPU.
+func cpuPercent(stats types.Stats) float64 {
+	var (
+		cpuDelta    = float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+		systemDelta = float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+		onlineCPUs  = float64(stats.CPUStats.OnlineCPUs)
+	)
+
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// Formats a date as expected by the events endpoint of the daemon.
+func eventsTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// Memory used by the container without the page cache which could be reclaimed, the
+// same way the docker CLI does.
+func memoryUsage(stats types.MemoryStats) int64 {
+	usage := stats.Usage
+
+	// cgroup v1 exposes total_inactive_file whereas v2 exposes inactive_file
+	inactive, found := stats.Stats["total_inactive_file"]
+
+	if !found {
+		inactive = stats.Stats["inactive_file"]
+	}
+
+	if inactive < usage {
+		usage -= inactive
+	}
+
+	return int64(usage)
+}
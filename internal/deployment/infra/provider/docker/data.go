@@ -12,9 +12,10 @@ import (
 )
 
 const (
-	providerKind = "docker"
-	defaultUser  = providerKind
-	defaultPort  = 22
+	providerKind   = "docker"
+	defaultUser    = providerKind
+	defaultPort    = 22
+	defaultTlsPort = 2376
 )
 
 // Docker provider config stored in a target to allow remote deployments.
@@ -23,6 +24,26 @@ type Data struct {
 	Port       monad.Maybe[int]            `json:"port"`
 	User       monad.Maybe[string]         `json:"user"`
 	PrivateKey monad.Maybe[ssh.PrivateKey] `json:"private_key"`
+	HostKey    monad.Maybe[ssh.HostKey]    `json:"host_key"`
+	Bastion    monad.Maybe[BastionData]    `json:"bastion"`
+	Tls        monad.Maybe[TlsData]        `json:"tls"`
+}
+
+// Jump host used to reach a docker host living in a private network.
+type BastionData struct {
+	Host       ssh.Host                    `json:"host"`
+	Port       int                         `json:"port"`
+	User       string                      `json:"user"`
+	PrivateKey monad.Maybe[ssh.PrivateKey] `json:"private_key"`
+	HostKey    monad.Maybe[ssh.HostKey]    `json:"host_key"`
+}
+
+// Mutual TLS material used to reach a docker daemon exposed over TCP.
+// The private key is stored encrypted.
+type TlsData struct {
+	CA   string `json:"ca"`
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
 }
 
 func (Data) Kind() string                              { return providerKind }
@@ -31,7 +52,13 @@ func (c Data) Value() (driver.Value, error)            { return storage.ValueJSO
 func (c Data) Equals(other domain.ProviderConfig) bool { return c == other }
 
 func (c Data) String() string {
-	if host, isRemote := c.Host.TryGet(); isRemote {
+	host, isRemote := c.Host.TryGet()
+
+	if isRemote && c.Tls.HasValue() {
+		return "tcp://" + string(host) + ":" + strconv.Itoa(c.Port.Get(defaultTlsPort))
+	}
+
+	if isRemote {
 		return c.User.Get(defaultUser) + "@" + string(host) + ":" + strconv.Itoa(c.Port.Get(defaultPort))
 	}
 
@@ -44,6 +71,23 @@ type QueryProviderConfig struct {
 	Port       monad.Maybe[int]                  `json:"port"`
 	User       monad.Maybe[string]               `json:"user"`
 	PrivateKey monad.Maybe[storage.SecretString] `json:"private_key"`
+	HostKey    monad.Maybe[string]               `json:"host_key"`
+	Bastion    monad.Maybe[QueryBastionConfig]   `json:"bastion"`
+	Tls        monad.Maybe[QueryTlsConfig]       `json:"tls"`
+}
+
+type QueryBastionConfig struct {
+	Host       string                            `json:"host"`
+	Port       int                               `json:"port"`
+	User       string                            `json:"user"`
+	PrivateKey monad.Maybe[storage.SecretString] `json:"private_key"`
+	HostKey    monad.Maybe[string]               `json:"host_key"`
+}
+
+type QueryTlsConfig struct {
+	CA   string               `json:"ca"`
+	Cert string               `json:"cert"`
+	Key  storage.SecretString `json:"key"`
 }
 
 func (QueryProviderConfig) Kind() string { return providerKind }
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/interpolation"
 	"github.com/compose-spec/compose-go/v2/loader"
@@ -18,25 +20,57 @@ import (
 	"golang.org/x/exp/maps"
 )
 
+// Name of the file, written in the build directory, holding the compose override of the
+// deployment environment so it could be merged with the project compose file.
+const composeOverrideFile = "compose.seelf.override.yml"
+
 type deploymentProjectBuilder struct {
 	sourceDir                   string
+	cacheDir                    monad.Maybe[string]
 	composePath                 string
 	networkName                 string
 	services                    domain.Services
+	addons                      []domain.Addon
+	targetVars                  domain.EnvVars
+	platform                    monad.Maybe[domain.Platform]
+	buildRegistry               monad.Maybe[domain.Registry]
 	project                     *types.Project
 	config                      domain.DeploymentConfig
 	logger                      domain.DeploymentLogger
 	labels                      types.Labels
 	isDefaultSubdomainAvailable bool
 	routersByPort               map[string]domain.Router
+	host                        string
+	secure                      bool
+}
+
+// Port exposed by a service once exposure overrides have been applied.
+type exposedPort struct {
+	router    domain.Router
+	port      domain.Port
+	protocol  string
+	published monad.Maybe[domain.Port]
 }
 
-func newDeploymentProjectBuilder(ctx domain.DeploymentContext, depl domain.Deployment) *deploymentProjectBuilder {
+func newDeploymentProjectBuilder(
+	ctx domain.DeploymentContext,
+	depl domain.Deployment,
+	target domain.Target,
+	addons []domain.Addon,
+	buildRegistry monad.Maybe[domain.Registry],
+) *deploymentProjectBuilder {
 	config := depl.Config()
 
 	return &deploymentProjectBuilder{
 		isDefaultSubdomainAvailable: true,
+		host:                        target.Url().Host(),
+		secure:                      target.Url().UseSSL(),
+		addons:                      addons,
+		targetVars:                  target.Vars(),
+		platform:                    target.Platform(),
+		buildRegistry:               buildRegistry,
 		sourceDir:                   ctx.BuildDirectory(),
+		cacheDir:                    ctx.CacheDirectory(),
 		config:                      config,
 		networkName:                 targetPublicNetworkName(config.Target()),
 		logger:                      ctx.Logger(),
@@ -111,7 +145,21 @@ func (b *deploymentProjectBuilder) findComposeFile() error {
 func (b *deploymentProjectBuilder) loadProject(ctx context.Context) error {
 	b.logger.Stepf("reading project from %s", b.composePath)
 
-	opts, err := cli.NewProjectOptions([]string{b.composePath},
+	configFiles := []string{b.composePath}
+
+	if override, isSet := b.config.ComposeOverride().TryGet(); isSet {
+		overridePath := filepath.Join(b.sourceDir, composeOverrideFile)
+
+		if err := os.WriteFile(overridePath, []byte(override), 0644); err != nil {
+			b.logger.Error(err)
+			return ErrLoadProjectFailed
+		}
+
+		b.logger.Infof("merging compose override of the %s environment", b.config.Environment())
+		configFiles = append(configFiles, overridePath)
+	}
+
+	opts, err := cli.NewProjectOptions(configFiles,
 		cli.WithName(b.config.ProjectName()),
 		cli.WithNormalization(true),
 		cli.WithProfiles([]string{string(b.config.Environment())}),
@@ -146,6 +194,65 @@ func (b *deploymentProjectBuilder) loadProject(ctx context.Context) error {
 	return nil
 }
 
+// Import and export the image build cache from the app cache directory if any. A cache
+// explicitly exported by the compose file is left untouched.
+func (b *deploymentProjectBuilder) configureBuildCache(build *types.BuildConfig, serviceName string) {
+	cacheDir, enabled := b.cacheDir.TryGet()
+
+	if !enabled {
+		return
+	}
+
+	dir := filepath.Join(cacheDir, serviceName)
+
+	// Importing a local cache which has never been exported fails the build
+	if _, err := os.Stat(filepath.Join(dir, "index.json")); err == nil {
+		build.CacheFrom = append(build.CacheFrom, "type=local,src="+dir)
+	}
+
+	if len(build.CacheTo) == 0 {
+		build.CacheTo = types.StringList{"type=local,dest=" + dir + ",mode=max"}
+	}
+}
+
+// Name the image after the build registry, if any, so it could be pushed there and build
+// it for every platform supported by the app unless the compose file lists its own.
+func (b *deploymentProjectBuilder) configureBuildRegistry(service *types.ServiceConfig) {
+	registry, enabled := b.buildRegistry.TryGet()
+
+	if !enabled {
+		return
+	}
+
+	service.Image = registry.Url().Host() + "/" + service.Image
+
+	if len(service.Build.Platforms) > 0 {
+		return
+	}
+
+	for _, platform := range b.config.Platforms() {
+		service.Build.Platforms = append(service.Build.Platforms, string(platform))
+	}
+}
+
+// Build the image for the platform of the target, if known, so it could run there even
+// if the image is built elsewhere. A platform explicitly set by the compose file is kept.
+func (b *deploymentProjectBuilder) configureBuildPlatform(service *types.ServiceConfig, serviceName string) {
+	platform, known := b.platform.TryGet()
+
+	if !known || service.Platform != "" {
+		return
+	}
+
+	b.logger.Infof("image of service %s will be built for the %s platform", serviceName, platform)
+
+	service.Platform = string(platform)
+
+	if len(service.Build.Platforms) > 0 && !slices.Contains(service.Build.Platforms, service.Platform) {
+		service.Build.Platforms = append(service.Build.Platforms, service.Platform)
+	}
+}
+
 func (b *deploymentProjectBuilder) transform() {
 	b.logger.Stepf("configuring seelf docker project for environment: %s", b.config.Environment())
 
@@ -170,6 +277,27 @@ func (b *deploymentProjectBuilder) transform() {
 			serviceDefinition.Image = service.Image() // Since the image name may have been generated, override it
 			serviceDefinition.PullPolicy = types.PullPolicyBuild
 			serviceDefinition.Build.Labels = appendLabels(serviceDefinition.Build.Labels, b.labels)
+			b.configureBuildCache(serviceDefinition.Build, serviceName)
+			b.configureBuildRegistry(&serviceDefinition)
+			b.configureBuildPlatform(&serviceDefinition, serviceName)
+		}
+
+		// Variables shared by the target come first so add-ons and the app could override them
+		for name, value := range b.targetVars {
+			localValue := value
+			serviceDefinition.Environment[name] = &localValue
+		}
+
+		// Give add-ons connection strings first so they could be overridden by the user
+		for _, addon := range b.addons {
+			connectionString := addon.ConnectionString()
+			serviceDefinition.Environment[addon.Variable()] = &connectionString
+
+			if serviceDefinition.Networks == nil {
+				serviceDefinition.Networks = map[string]*types.ServiceNetworkConfig{}
+			}
+
+			serviceDefinition.Networks[addon.Host()] = nil
 		}
 
 		// Attach environment variables if any
@@ -187,6 +315,11 @@ func (b *deploymentProjectBuilder) transform() {
 			b.logger.Infof("using %s environment variable(s) for service %s", strings.Join(envNames, ", "), serviceName)
 		}
 
+		if replicas, isScaled := b.config.ReplicasFor(serviceName).TryGet(); isScaled {
+			serviceDefinition.SetScale(int(replicas))
+			b.logger.Infof("running %d container(s) for service %s", replicas, serviceName)
+		}
+
 		serviceDefinition.Labels = appendLabels(serviceDefinition.Labels, b.labels)
 
 		for _, volume := range serviceDefinition.Volumes {
@@ -195,8 +328,10 @@ func (b *deploymentProjectBuilder) transform() {
 			}
 		}
 
+		exposure := b.config.ExposureFor(serviceName).Get(domain.ServiceExposure{})
+
 		// No ports mapped, nothing to do
-		if len(serviceDefinition.Ports) == 0 {
+		if len(serviceDefinition.Ports) == 0 && (exposure.Disabled || (!exposure.Port.HasValue() && len(exposure.Ports) == 0)) {
 			b.project.Services[serviceName] = serviceDefinition
 			b.services = append(b.services, service)
 			continue
@@ -207,27 +342,23 @@ func (b *deploymentProjectBuilder) transform() {
 			entrypoint                  domain.Entrypoint
 		)
 
-		for _, portConfig := range serviceDefinition.Ports {
-			router, err := b.routerFor(portConfig)
-
-			if err != nil {
-				b.logger.Warnf("skipping port exposure: %s", err.Error())
-				continue
-			}
-
-			switch router {
+		for _, exposed := range b.exposedPortsFor(serviceName, serviceDefinition.Ports, exposure) {
+			switch exposed.router {
 			case domain.RouterHttp:
-				entrypoint = service.AddHttpEntrypoint(b.config, domain.Port(portConfig.Target), domain.HttpEntrypointOptions{
+				entrypoint = service.AddHttpEntrypoint(b.config, exposed.port, domain.HttpEntrypointOptions{
 					Managed:             httpMainEntryPointAvailable,
 					UseDefaultSubdomain: b.isDefaultSubdomainAvailable,
+					Subdomain:           exposure.Subdomain,
+					PathPrefix:          exposure.PathPrefix,
 				})
 				httpMainEntryPointAvailable = false
 				serviceDefinition.Labels[SubdomainLabel] = entrypoint.Subdomain().MustGet()
+				b.route(serviceDefinition.Labels, entrypoint)
 			case domain.RouterTcp:
-				entrypoint = service.AddTCPEntrypoint(domain.Port(portConfig.Target))
+				entrypoint = service.AddTCPEntrypoint(exposed.port, exposed.publishedPorts()...)
 				serviceDefinition.Labels["traefik.tcp.routers."+string(entrypoint.Name())+".rule"] = "HostSNI(`*`)"
 			case domain.RouterUdp:
-				entrypoint = service.AddUDPEntrypoint(domain.Port(portConfig.Target))
+				entrypoint = service.AddUDPEntrypoint(exposed.port, exposed.publishedPorts()...)
 			default:
 				b.logger.Warnf("unsupported router type for service %s, the service will not be exposed", serviceName)
 				continue
@@ -235,16 +366,20 @@ func (b *deploymentProjectBuilder) transform() {
 
 			var (
 				entrypointName = string(entrypoint.Name())
-				routerName     = string(router)
+				routerName     = string(exposed.router)
 			)
 
 			if !entrypoint.IsCustom() {
 				serviceDefinition.Labels["traefik."+routerName+".routers."+entrypointName+".entrypoints"] = httpMainEntryPoint
-				b.isDefaultSubdomainAvailable = false
+
+				// Overridden subdomains and path prefixes do not take the default subdomain for themselves
+				if !exposure.Subdomain.HasValue() && !exposure.PathPrefix.HasValue() {
+					b.isDefaultSubdomainAvailable = false
+				}
 			} else {
 				serviceDefinition.Labels[CustomEntrypointsLabel] = "true"
 				serviceDefinition.Labels["traefik."+routerName+".routers."+entrypointName+".entrypoints"] = entrypointName
-				b.logger.Infof("using custom entrypoint for service %s (%d/%s)", serviceName, portConfig.Target, portConfig.Protocol)
+				b.logger.Infof("using custom entrypoint for service %s (%d/%s)", serviceName, exposed.port, exposed.protocol)
 			}
 
 			serviceDefinition.Labels["traefik."+routerName+".routers."+entrypointName+".service"] = entrypointName
@@ -253,11 +388,13 @@ func (b *deploymentProjectBuilder) transform() {
 
 		serviceDefinition.Ports = []types.ServicePortConfig{} // Remove them since traefik will expose this service
 
-		if serviceDefinition.Networks == nil {
-			serviceDefinition.Networks = map[string]*types.ServiceNetworkConfig{}
-		}
+		if !exposure.Disabled {
+			if serviceDefinition.Networks == nil {
+				serviceDefinition.Networks = map[string]*types.ServiceNetworkConfig{}
+			}
 
-		serviceDefinition.Networks[b.networkName] = nil // nil here because there's no additional options to give
+			serviceDefinition.Networks[b.networkName] = nil // nil here because there's no additional options to give
+		}
 
 		// Update the project definition and state
 		b.project.Services[serviceName] = serviceDefinition
@@ -285,6 +422,199 @@ func (b *deploymentProjectBuilder) transform() {
 		Name:     b.networkName,
 		External: true,
 	}
+
+	if len(b.targetVars) > 0 {
+		b.logger.Infof("using %s shared variable(s) of the target for every service", strings.Join(maps.Keys(b.targetVars), ", "))
+	}
+
+	for _, addon := range b.addons {
+		b.logger.Infof("using %s add-on for every service through the %s environment variable", addon.Kind(), addon.Variable())
+
+		b.project.Networks[addon.Host()] = types.NetworkConfig{
+			Name:     addon.Host(),
+			External: true,
+		}
+	}
+}
+
+// Configure the HTTP router of the given entrypoint by applying the access protection
+// and proxy rules of the environment if any.
+func (b *deploymentProjectBuilder) route(labels types.Labels, entrypoint domain.Entrypoint) {
+	var (
+		router      = string(entrypoint.Name())
+		host        = entrypoint.Subdomain().MustGet() + "." + b.host
+		rule        = fmt.Sprintf("Host(`%s`)", host)
+		hasRule     bool
+		middlewares []string
+		rules       = b.config.ProxyRules().Get(domain.ProxyRules{})
+	)
+
+	// Aliases are only routed to the service exposed on the default subdomain
+	if aliases, isSet := b.config.UrlAliases().TryGet(); isSet && entrypoint.Subdomain().MustGet() == b.config.SubDomain("", true) {
+		for _, alias := range aliases {
+			rule = fmt.Sprintf("%s || Host(`%s`)", rule, alias)
+		}
+
+		rule = "(" + rule + ")"
+		hasRule = true
+	}
+
+	if rules.WWWRedirect {
+		rule = fmt.Sprintf("(%s || Host(`www.%s`))", rule, host)
+		hasRule = true
+
+		name := router + "-www"
+		labels["traefik.http.middlewares."+name+".redirectregex.regex"] = `^(https?)://www\.(.*)`
+		labels["traefik.http.middlewares."+name+".redirectregex.replacement"] = "${1}://${2}"
+		labels["traefik.http.middlewares."+name+".redirectregex.permanent"] = "true"
+		middlewares = append(middlewares, name)
+	}
+
+	// Path based routing needs an explicit rule since the default one only matches the host
+	if prefix, isSet := entrypoint.PathPrefix().TryGet(); isSet {
+		rule = fmt.Sprintf("%s && PathPrefix(`%s`)", rule, prefix)
+		hasRule = true
+	}
+
+	if hasRule {
+		labels["traefik.http.routers."+router+".rule"] = rule
+	}
+
+	for i, redirect := range rules.Redirects {
+		name := fmt.Sprintf("%s-redirect%d", router, i)
+		labels["traefik.http.middlewares."+name+".redirectregex.regex"] = redirect.Regex
+		labels["traefik.http.middlewares."+name+".redirectregex.replacement"] = redirect.Replacement
+		labels["traefik.http.middlewares."+name+".redirectregex.permanent"] = strconv.FormatBool(redirect.Permanent)
+		middlewares = append(middlewares, name)
+	}
+
+	middlewares = append(middlewares, b.protect(labels, router)...)
+
+	if len(rules.Headers) > 0 {
+		name := router + "-headers"
+
+		for header, value := range rules.Headers {
+			labels["traefik.http.middlewares."+name+".headers.customresponseheaders."+header] = value
+		}
+
+		middlewares = append(middlewares, name)
+	}
+
+	if len(middlewares) > 0 {
+		labels["traefik.http.routers."+router+".middlewares"] = strings.Join(middlewares, ",")
+	}
+
+	// Insecure paths are only meaningful when requests are redirected to HTTPS
+	if !b.secure || entrypoint.IsCustom() || len(rules.InsecurePaths) == 0 {
+		return
+	}
+
+	prefixes := make([]string, len(rules.InsecurePaths))
+
+	for i, path := range rules.InsecurePaths {
+		prefixes[i] = fmt.Sprintf("PathPrefix(`%s`)", path)
+	}
+
+	insecureRouter := router + "-insecure"
+	labels["traefik.http.routers."+insecureRouter+".entrypoints"] = httpInsecureEntryPoint
+	labels["traefik.http.routers."+insecureRouter+".rule"] = fmt.Sprintf("%s && (%s)", rule, strings.Join(prefixes, " || "))
+	labels["traefik.http.routers."+insecureRouter+".service"] = router
+
+	if len(middlewares) > 0 {
+		labels["traefik.http.routers."+insecureRouter+".middlewares"] = strings.Join(middlewares, ",")
+	}
+}
+
+// Configure middlewares enforcing the access protection of the environment if any
+// and returns their names.
+func (b *deploymentProjectBuilder) protect(labels types.Labels, router string) []string {
+	protection, isProtected := b.config.Protection().TryGet()
+
+	if !isProtected {
+		return nil
+	}
+
+	var middlewares []string
+
+	if len(protection.IPAllowList) > 0 {
+		name := router + "-ipallowlist"
+		labels["traefik.http.middlewares."+name+".ipallowlist.sourcerange"] = strings.Join(protection.IPAllowList, ",")
+		middlewares = append(middlewares, name)
+	}
+
+	if basicAuth, isSet := protection.BasicAuth.TryGet(); isSet {
+		name := router + "-basicauth"
+		labels["traefik.http.middlewares."+name+".basicauth.users"] = basicAuth.Username + ":" + basicAuth.PasswordHash
+		middlewares = append(middlewares, name)
+	}
+
+	if forwardAuth, isSet := protection.ForwardAuth.TryGet(); isSet {
+		name := router + "-forwardauth"
+		labels["traefik.http.middlewares."+name+".forwardauth.address"] = forwardAuth.String()
+		labels["traefik.http.middlewares."+name+".forwardauth.trustforwardheader"] = "true"
+		middlewares = append(middlewares, name)
+	}
+
+	return middlewares
+}
+
+// Resolve ports to expose for the given service, applying exposure overrides of the app.
+// An overridden port replaces every HTTP port of the compose file.
+func (b *deploymentProjectBuilder) exposedPortsFor(
+	serviceName string,
+	ports []types.ServicePortConfig,
+	exposure domain.ServiceExposure,
+) []exposedPort {
+	if exposure.Disabled {
+		b.logger.Infof("exposure of service %s disabled by the app configuration", serviceName)
+		return nil
+	}
+
+	var (
+		result                  []exposedPort
+		httpPort, isHttpPortSet = exposure.Port.TryGet()
+	)
+
+	if isHttpPortSet {
+		b.logger.Infof("exposing port %d of service %s over HTTP as configured by the app", httpPort, serviceName)
+		result = append(result, exposedPort{router: domain.RouterHttp, port: httpPort, protocol: "tcp"})
+	}
+
+	for _, port := range exposure.Ports {
+		b.logger.Infof("exposing port %d of service %s over %s as configured by the app", port.Port, serviceName, port.Router)
+		result = append(result, exposedPort{router: port.Router, port: port.Port, protocol: string(port.Router), published: port.Published})
+	}
+
+	for _, portConfig := range ports {
+		router, err := b.routerFor(portConfig)
+
+		if err != nil {
+			b.logger.Warnf("skipping port exposure: %s", err.Error())
+			continue
+		}
+
+		if router == domain.RouterHttp && isHttpPortSet {
+			continue
+		}
+
+		if slices.ContainsFunc(exposure.Ports, func(p domain.ExposedPort) bool {
+			return p.Router == router && p.Port == domain.Port(portConfig.Target)
+		}) {
+			continue
+		}
+
+		result = append(result, exposedPort{router: router, port: domain.Port(portConfig.Target), protocol: portConfig.Protocol})
+	}
+
+	return result
+}
+
+func (p exposedPort) publishedPorts() []domain.Port {
+	if published, isSet := p.published.TryGet(); isSet {
+		return []domain.Port{published}
+	}
+
+	return nil
 }
 
 func (b *deploymentProjectBuilder) parsePortDefinition(rawValue string) error {
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+// Known fragments of docker error messages used to classify a compose failure. Docker
+// does not expose typed errors for those cases once they have gone through compose.
+var composeFailurePatterns = []struct {
+	reason    domain.DeploymentFailureReason
+	fragments []string
+}{
+	{domain.DeploymentFailurePortConflict, []string{
+		"port is already allocated",
+		"address already in use",
+	}},
+	{domain.DeploymentFailureQuotaExceeded, []string{
+		"toomanyrequests",
+		"rate limit",
+		"quota exceeded",
+		"no space left on device",
+	}},
+	{domain.DeploymentFailureImagePull, []string{
+		"pull access denied",
+		"manifest unknown",
+		"failed to resolve reference",
+		"error pulling image",
+	}},
+}
+
+// Classifies the error returned by compose and returns the generic ErrComposeFailed
+// since the error itself has already been logged.
+func composeFailure(err error) error {
+	msg := strings.ToLower(err.Error())
+
+	// Returned by compose when services are not healthy before the health check timeout
+	if strings.Contains(msg, "application not healthy after") {
+		return domain.NewDeploymentFailure(domain.DeploymentFailureTimeout, domain.ErrDeploymentTimedOut)
+	}
+
+	for _, pattern := range composeFailurePatterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(msg, fragment) {
+				return domain.NewDeploymentFailure(pattern.reason, ErrComposeFailed)
+			}
+		}
+	}
+
+	return ErrComposeFailed
+}
@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func (d *docker) CollectGarbage(ctx context.Context, target domain.Target, minAge time.Duration) (report domain.GarbageCollectionReport, err error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return report, err
+	}
+
+	defer client.Close()
+
+	// Only containers and networks created by seelf for this target are considered
+	// so resources managed by someone else on the same host are left untouched.
+	containers, err := client.api.ContainersPrune(ctx, pruneFilters(minAge,
+		filters.Arg("label", AppLabel),
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+	))
+
+	if err != nil {
+		return report, err
+	}
+
+	report.Containers = len(containers.ContainersDeleted)
+	report.SpaceReclaimed += int64(containers.SpaceReclaimed)
+
+	images, err := client.api.ImagesPrune(ctx, pruneFilters(minAge,
+		filters.Arg("dangling", "true"),
+	))
+
+	if err != nil {
+		return report, err
+	}
+
+	report.Images = len(images.ImagesDeleted)
+	report.SpaceReclaimed += int64(images.SpaceReclaimed)
+
+	networks, err := client.api.NetworksPrune(ctx, pruneFilters(minAge,
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+	))
+
+	if err != nil {
+		return report, err
+	}
+
+	report.Networks = len(networks.NetworksDeleted)
+	report.CollectedAt = time.Now().UTC()
+
+	return report, nil
+}
+
+// Builds prune filters matching resources created at least minAge ago.
+func pruneFilters(minAge time.Duration, args ...filters.KeyValuePair) filters.Args {
+	if minAge > 0 {
+		args = append(args, filters.Arg("until", minAge.String()))
+	}
+
+	return filters.NewArgs(args...)
+}
@@ -0,0 +1,247 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+const dockerSocket = "/var/run/docker.sock"
+
+// Record images of the deployed project in the deployment reports and generate their
+// software bill of materials if enabled. Failures are only reported as warnings since
+// the project is already running.
+func (d *docker) recordManifest(
+	ctx context.Context,
+	client *client,
+	logger domain.DeploymentLogger,
+	reports domain.DeploymentReports,
+	project *types.Project,
+	vulnerabilities map[string]domain.VulnerabilitiesSummary,
+) {
+	logger.Stepf("recording deployment manifest")
+
+	manifest := domain.DeploymentManifest{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		image := domain.ImageManifest{
+			Service:         name,
+			Image:           service.Image,
+			Built:           service.Build != nil,
+			Vulnerabilities: vulnerabilities[name],
+		}
+
+		inspected, _, inspectErr := client.api.ImageInspectWithRaw(ctx, service.Image)
+
+		if inspectErr != nil {
+			logger.Warnf("could not inspect image %s of service %s: %s", service.Image, name, inspectErr.Error())
+		} else {
+			image.ID = inspected.ID
+			image.RepoDigests = inspected.RepoDigests
+		}
+
+		if service.Build != nil {
+			var err error
+
+			image.BuildArgs = buildArgs(service.Build)
+			image.BaseImages, err = baseImages(service.Build, image.BuildArgs)
+
+			if err != nil {
+				logger.Warnf("could not read base images of service %s: %s", name, err.Error())
+			}
+		}
+
+		// The image must exist on the target for its content to be analyzed
+		if sbomImage, enabled := d.sbomImage.TryGet(); enabled && inspectErr == nil {
+			image.SBOM = d.generateSBOM(ctx, client, logger, sbomImage, reports.SBOMPath(name), service.Image)
+		}
+
+		manifest.Images = append(manifest.Images, image)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+
+	if err == nil {
+		err = os.WriteFile(reports.ManifestPath(), data, 0644)
+	}
+
+	if err != nil {
+		logger.Warnf("could not write deployment manifest: %s", err.Error())
+	}
+}
+
+// Run the syft compatible image on the target to write the bill of materials of the
+// given image at path. Returns true on success.
+func (d *docker) generateSBOM(
+	ctx context.Context,
+	client *client,
+	logger domain.DeploymentLogger,
+	sbomImage, path, image string,
+) bool {
+	logger.Infof("generating software bill of materials of image %s", image)
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		logger.Warnf("could not create software bill of materials file: %s", err.Error())
+		return false
+	}
+
+	defer file.Close()
+
+	var stderr strings.Builder
+
+	code, err := client.Run(ctx, sbomImage,
+		[]string{"docker:" + image, "--output", "syft-json", "--quiet"},
+		[]string{dockerSocket + ":" + dockerSocket},
+		file, &stderr)
+
+	if err == nil && code != 0 {
+		err = fmt.Errorf("exited with code %d: %s", code, strings.TrimSpace(stderr.String()))
+	}
+
+	if err != nil {
+		logger.Warnf("could not generate software bill of materials of image %s: %s", image, err.Error())
+		file.Close()
+		os.Remove(path)
+		return false
+	}
+
+	return true
+}
+
+// Build arguments given to an image build, arguments without value are taken from
+// the builder environment and are not known here.
+func buildArgs(build *types.BuildConfig) map[string]string {
+	if len(build.Args) == 0 {
+		return nil
+	}
+
+	args := make(map[string]string, len(build.Args))
+
+	for name, value := range build.Args {
+		if value != nil {
+			args[name] = *value
+		}
+	}
+
+	return args
+}
+
+// Parse the Dockerfile of a build to retrieve images its stages are based on. Stages
+// based on previous ones and scratch are not considered.
+func baseImages(build *types.BuildConfig, args map[string]string) ([]string, error) {
+	var reader io.Reader
+
+	if build.DockerfileInline != "" {
+		reader = strings.NewReader(build.DockerfileInline)
+	} else {
+		dockerfile := build.Dockerfile
+
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(build.Context, dockerfile)
+		}
+
+		file, err := os.Open(dockerfile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer file.Close()
+
+		reader = file
+	}
+
+	return parseBaseImages(reader, args)
+}
+
+func parseBaseImages(r io.Reader, buildArgs map[string]string) ([]string, error) {
+	var (
+		images  []string
+		stages  = make(map[string]bool)
+		args    = make(map[string]string) // Arguments declared before the first stage
+		inStage bool
+		line    string
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// Handle instructions spanning multiple lines
+		if continued, isContinued := strings.CutSuffix(text, "\\"); isContinued {
+			line += continued + " "
+			continue
+		}
+
+		line, text = "", line+text
+		fields := strings.Fields(text)
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			if inStage {
+				continue
+			}
+
+			for _, arg := range fields[1:] {
+				name, value, _ := strings.Cut(arg, "=")
+				args[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			inStage = true
+			fields = fields[1:]
+
+			// Skip flags such as --platform
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:]
+			}
+
+			if len(fields) == 0 {
+				continue
+			}
+
+			image := os.Expand(fields[0], func(name string) string {
+				if value, isSet := buildArgs[name]; isSet {
+					return value
+				}
+
+				return args[name]
+			})
+
+			if image != "scratch" && !stages[strings.ToLower(image)] {
+				images = append(images, image)
+			}
+
+			if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+				stages[strings.ToLower(fields[2])] = true
+			}
+		}
+	}
+
+	return images, scanner.Err()
+}
@@ -1,16 +1,21 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider"
+	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
@@ -32,8 +37,12 @@ var (
 	ErrOpenComposeFileFailed = errors.New("compose_file_open_failed")
 	ErrComposeFailed         = errors.New("compose_failed")
 	ErrTargetConnectFailed   = errors.New("target_connect_failed")
+	ErrAddonCommandFailed    = errors.New("addon_command_failed")
+	ErrProxyUpgradeFailed    = errors.New("proxy_upgrade_failed")
+	ErrProxyNotRunning       = errors.New("proxy_not_running")
 
 	sshConfigPath = filepath.Join(must.Panic(os.UserHomeDir()), ".ssh", "config")
+	tlsConfigDir  = filepath.Join(must.Panic(os.UserHomeDir()), ".docker", "seelf")
 )
 
 const (
@@ -51,12 +60,18 @@ type (
 	Docker interface {
 		provider.Provider
 		expose_seelf_container.LocalProvider
+		Ping(context.Context) error // Make sure the local docker daemon is reachable
 	}
 
 	docker struct {
 		client    *client // Client to use, mostly for testing
 		logger    log.Logger
 		sshConfig ssh.Configurator
+		sshPool   ssh.Pool
+		keepAlive monad.Maybe[ssh.KeepAlive]
+		legacy    monad.Maybe[crypto.Cipher]
+		sbomImage monad.Maybe[string]
+		scan      monad.Maybe[ScanOptions]
 	}
 )
 
@@ -74,6 +89,8 @@ func New(logger log.Logger, configuration ...DockerOptions) Docker {
 		opt(d)
 	}
 
+	d.sshPool = ssh.NewPool(d.keepAlive.Get(ssh.KeepAlive{}))
+
 	return d
 }
 
@@ -88,6 +105,31 @@ func WithDockerAndCompose(cli command.Cli, composeService api.Service) DockerOpt
 	}
 }
 
+// Keep connections to SSH targets alive and share them between deployments, logs
+// streaming and commands execution.
+func WithSSHKeepAlive(options ssh.KeepAlive) DockerOptions {
+	return func(d *docker) {
+		d.keepAlive.Set(options)
+	}
+}
+
+// Secret used by previous versions to encrypt TLS private keys on their own. Keys are now
+// stored with the rest of the target config, encrypted by the database keyring, so this
+// one is only needed to read the keys which have not been configured since.
+func WithLegacySecret(secret []byte) DockerOptions {
+	return func(d *docker) {
+		d.legacy.Set(must.Panic(crypto.NewCipher(secret)))
+	}
+}
+
+// Generate a software bill of materials of each deployed image by running the given
+// syft compatible image on the target.
+func WithSBOM(image string) DockerOptions {
+	return func(d *docker) {
+		d.sbomImage.Set(image)
+	}
+}
+
 func (d *docker) CanPrepare(payload any) bool                 { return ptypes.Is[Body](payload) }
 func (d *docker) CanHandle(config domain.ProviderConfig) bool { return ptypes.Is[Data](config) }
 
@@ -99,8 +141,12 @@ func (d *docker) Prepare(ctx context.Context, payload any, existing ...domain.Pr
 	}
 
 	var (
-		host    ssh.Host
-		privKey ssh.PrivateKey
+		host           ssh.Host
+		privKey        ssh.PrivateKey
+		hostKey        monad.Maybe[ssh.HostKey]
+		bastionHost    ssh.Host
+		bastionPrivKey ssh.PrivateKey
+		bastionHostKey monad.Maybe[ssh.HostKey]
 	)
 
 	if err := validate.Struct(validate.Of{
@@ -112,6 +158,31 @@ func (d *docker) Prepare(ctx context.Context, payload any, existing ...domain.Pr
 		"docker.private_key": validate.Patch(config.PrivateKey, func(s string) error {
 			return validate.Value(s, &privKey, ssh.ParsePrivateKey)
 		}),
+		"docker.host_key": validate.Maybe(config.HostKey, func(s string) error {
+			return validate.Value(s, &hostKey, parseHostKey)
+		}),
+		"docker.bastion": validate.Maybe(config.Bastion, func(b BastionBody) error {
+			return validate.FieldErrors{
+				"host": validate.Value(b.Host, &bastionHost, ssh.ParseHost),
+				"user": validate.Maybe(b.User, vstrings.Required),
+				"port": validate.Maybe(b.Port, numbers.Min(0)),
+				"private_key": validate.Patch(b.PrivateKey, func(s string) error {
+					return validate.Value(s, &bastionPrivKey, ssh.ParsePrivateKey)
+				}),
+				"host_key": validate.Maybe(b.HostKey, func(s string) error {
+					return validate.Value(s, &bastionHostKey, parseHostKey)
+				}),
+			}
+		}),
+		"docker.tls": validate.Maybe(config.Tls, func(t TlsBody) error {
+			return validate.FieldErrors{
+				"ca":   validateTlsCA(t.CA),
+				"cert": validateTlsCertificate(t.Cert),
+				"key": validate.Maybe(t.Key, func(key string) error {
+					return validateTlsKey(t.Cert, key)
+				}),
+			}
+		}),
 	}); err != nil {
 		return nil, err
 	}
@@ -124,30 +195,32 @@ func (d *docker) Prepare(ctx context.Context, payload any, existing ...domain.Pr
 	}
 
 	data.Host.Set(host)
-	data.User.Set(config.User.Get(defaultUser))
-	data.Port.Set(config.Port.Get(defaultPort))
-
-	// Private key nil, we're done
-	if config.PrivateKey.IsNil() {
-		return data, nil
-	}
 
-	// Private key set, we're done
-	if config.PrivateKey.HasValue() {
-		data.PrivateKey.Set(privKey)
-		return data, nil
+	if tlsConfig, useTls := config.Tls.TryGet(); useTls {
+		return d.prepareTLS(data, config.Port, tlsConfig, existing...)
 	}
 
-	// Else try to retrieve it from the existing one.
-	// (This is needed because the existing private key is never exposed to the end user)
-	for _, existingConfig := range existing {
-		d, isDockerData := existingConfig.(Data)
-
-		if !isDockerData {
-			continue
-		}
+	data.User.Set(config.User.Get(defaultUser))
+	data.Port.Set(config.Port.Get(defaultPort))
+	data.HostKey = hostKey
+	data.PrivateKey = patchPrivateKey(config.PrivateKey, privKey, existing, func(d Data) monad.Maybe[ssh.PrivateKey] {
+		return d.PrivateKey
+	})
 
-		data.PrivateKey = d.PrivateKey
+	if bastion, hasBastion := config.Bastion.TryGet(); hasBastion {
+		data.Bastion.Set(BastionData{
+			Host:    bastionHost,
+			User:    bastion.User.Get(data.User.MustGet()),
+			Port:    bastion.Port.Get(defaultPort),
+			HostKey: bastionHostKey,
+			PrivateKey: patchPrivateKey(bastion.PrivateKey, bastionPrivKey, existing, func(d Data) monad.Maybe[ssh.PrivateKey] {
+				if b, isSet := d.Bastion.TryGet(); isSet {
+					return b.PrivateKey
+				}
+
+				return monad.None[ssh.PrivateKey]()
+			}),
+		})
 	}
 
 	return data, nil
@@ -160,11 +233,15 @@ func (d *docker) Setup(ctx context.Context, target domain.Target) (domain.Target
 		return nil, domain.ErrInvalidProviderPayload
 	}
 
-	if err := d.configureTargetSSH(target.ID(), config); err != nil {
+	if err := d.configureTargetSSH(ctx, target.ID(), config); err != nil {
+		return nil, err
+	}
+
+	if err := d.configureTargetTLS(target.ID(), config); err != nil {
 		return nil, err
 	}
 
-	client, err := d.tryConnect(ctx, nil, config.Host)
+	client, err := d.tryConnect(ctx, nil, newEndpoint(target.ID(), config))
 
 	if err != nil {
 		return nil, err
@@ -189,14 +266,114 @@ func (d *docker) Setup(ctx context.Context, target domain.Target) (domain.Target
 	})
 }
 
-func (d *docker) RemoveConfiguration(_ context.Context, target domain.Target) error {
-	return d.sshConfig.Remove(string(target.ID()))
+func (d *docker) Platform(ctx context.Context, target domain.Target) (domain.Platform, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer client.Close()
+
+	return client.Platform(ctx)
+}
+
+func (d *docker) ProxyVersion(target domain.Target) (string, error) {
+	if _, ok := target.Provider().(Data); !ok {
+		return "", domain.ErrInvalidProviderPayload
+	}
+
+	return proxyVersion, nil
+}
+
+func (d *docker) UpgradeProxy(ctx context.Context, target domain.Target) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	project, _, err := newProxyProjectBuilder(client, target).Build(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	previous, err := client.ServiceImage(ctx, project.Name, proxyServiceName)
+
+	if err != nil {
+		return err
+	}
+
+	// Pull the new image beforehand so the proxy is only unavailable while containers are swapped
+	if err = client.compose.Pull(ctx, project, api.PullOptions{Quiet: true}); err != nil {
+		return err
+	}
+
+	if err = swapProxy(ctx, client, project); err == nil {
+		return nil
+	}
+
+	image, isSet := previous.TryGet()
+
+	if !isSet || image == project.Services[proxyServiceName].Image {
+		return err
+	}
+
+	d.logger.Warnw("proxy upgrade failed, rolling back to the previous version",
+		"target", target.ID(),
+		"image", image,
+		"error", err)
+
+	rollback := *project
+	rollback.Services = maps.Clone(project.Services)
+	proxy := rollback.Services[proxyServiceName]
+	proxy.Image = image
+	rollback.Services[proxyServiceName] = proxy
+
+	if rollbackErr := swapProxy(ctx, client, &rollback); rollbackErr != nil {
+		return errors.Join(ErrProxyUpgradeFailed, err, rollbackErr)
+	}
+
+	return errors.Join(ErrProxyUpgradeFailed, err)
+}
+
+func (d *docker) RemoveConfiguration(ctx context.Context, target domain.Target) error {
+	if config, ok := target.Provider().(Data); ok {
+		if host, isRemote := config.Host.TryGet(); isRemote {
+			if err := d.sshPool.Release(ctx, host); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.sshConfig.Remove(string(target.ID())); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(targetTlsDir(target.ID()))
 }
 
 func (d *docker) PrepareLocal(context.Context) (domain.ProviderConfig, error) {
 	return Data{}, nil
 }
 
+func (d *docker) Ping(ctx context.Context) error {
+	client, err := d.tryConnect(ctx, nil, endpoint{})
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	_, err = client.api.Ping(ctx)
+
+	return err
+}
+
 func (d *docker) Expose(ctx context.Context, target domain.Target, container string) error {
 	client, err := d.connect(ctx, nil, target)
 
@@ -227,6 +404,7 @@ func (d *docker) Deploy(
 	depl domain.Deployment,
 	target domain.Target,
 	registries []domain.Registry,
+	addons []domain.Addon,
 ) (domain.Services, error) {
 	logger := deploymentCtx.Logger()
 	client, err := d.connect(ctx, logger, target, registries...)
@@ -244,27 +422,80 @@ func (d *docker) Deploy(
 		logger.Infof("using custom registries: %s", strings.Join(client.registries, ", "))
 	}
 
-	project, services, err := newDeploymentProjectBuilder(deploymentCtx, depl).Build(ctx)
+	buildRegistry, err := findBuildRegistry(depl.Config(), registries)
 
 	if err != nil {
+		logger.Error(err)
 		return nil, err
 	}
 
-	logger.Stepf("launching docker compose project (pulling, building and running)")
+	project, services, err := newDeploymentProjectBuilder(deploymentCtx, depl, target, addons, buildRegistry).Build(ctx)
 
-	if err = client.compose.Up(ctx, project, api.UpOptions{
-		Create: api.CreateOptions{
-			Build: &api.BuildOptions{
-				Quiet: true,
+	if err != nil {
+		return nil, domain.NewDeploymentFailure(domain.DeploymentFailureComposeInvalid, err)
+	}
+
+	var vulnerabilities map[string]domain.VulnerabilitiesSummary
+
+	// Images built by the previous run of a resumed deployment have already been scanned
+	// and are reused, compose will only build the missing ones
+	if deploymentCtx.ResumeFrom().Get("") == domain.DeploymentStepDeploy {
+		logger.Infof("reusing images built by the previous run of this deployment")
+
+		for name, service := range project.Services {
+			if service.Build != nil {
+				service.PullPolicy = types.PullPolicyMissing
+				project.Services[name] = service
+			}
+		}
+	} else {
+		if registry, isSet := buildRegistry.TryGet(); isSet {
+			if err = pushImages(ctx, client, logger, registry, project); err != nil {
+				return nil, err
+			}
+		}
+
+		if d.scan.HasValue() {
+			logger.Begin(domain.DeploymentStepScan)
+
+			if vulnerabilities, err = d.scanImages(ctx, client, logger, deploymentCtx.Reports(), project); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	logger.Begin(domain.DeploymentStepDeploy)
+
+	var staticVersion monad.Maybe[string]
+
+	if site, isStatic := depl.Config().StaticSite().TryGet(); isStatic {
+		var version string
+
+		if services, version, err = d.deployStaticSite(ctx, client, logger, depl, target, project, site); err != nil {
+			return nil, err
+		}
+
+		staticVersion.Set(version)
+	} else {
+		logger.Stepf("launching docker compose project (pulling, building and running)")
+
+		if err = client.compose.Up(ctx, project, api.UpOptions{
+			Create: api.CreateOptions{
+				Build: &api.BuildOptions{
+					Quiet: true,
+				},
+				RemoveOrphans: true,
 			},
-			RemoveOrphans: true,
-		},
-		Start: api.StartOptions{
-			Wait: true,
-		},
-	}); err != nil {
-		logger.Error(err)
-		return nil, ErrComposeFailed
+			Start: api.StartOptions{
+				Wait:        true,
+				WaitTimeout: deploymentCtx.HealthCheckTimeout(),
+			},
+		}); err != nil {
+			logger.Error(err)
+			return nil, composeFailure(err)
+		}
+
+		d.recordManifest(ctx, client, logger, deploymentCtx.Reports(), project, vulnerabilities)
 	}
 
 	if target.Url().UseSSL() {
@@ -275,6 +506,14 @@ func (d *docker) Deploy(
 		logger.Infof("this deployment uses custom entrypoints. If this is the first time, you may have to wait a few seconds for the target to find available ports and expose them appropriately")
 	}
 
+	logger.Begin(domain.DeploymentStepCleanup)
+
+	// Files of previous versions of a static site are not needed anymore, and the whole
+	// site is removed when the app is not served as a static one anymore
+	if err = removeStaticSite(ctx, client, target.ID(), staticSiteName(depl.ID().AppID(), depl.Config().Environment()), staticVersion); err != nil {
+		logger.Warnf(err.Error())
+	}
+
 	prunedCount, err := client.PruneImages(ctx, filters.NewArgs(
 		filters.Arg("dangling", "true"),
 		filters.Arg("label", AppLabel+"="+string(depl.ID().AppID())),
@@ -323,6 +562,10 @@ func (d *docker) Cleanup(ctx context.Context, app domain.AppID, target domain.Ta
 
 	defer client.Close()
 
+	if err = removeStaticSite(ctx, client, target.ID(), staticSiteName(app, env), monad.None[string]()); err != nil {
+		return err
+	}
+
 	return client.RemoveResources(ctx, filters.NewArgs(
 		filters.Arg("label", AppLabel+"="+string(app)),
 		filters.Arg("label", TargetLabel+"="+string(target.ID())),
@@ -330,13 +573,327 @@ func (d *docker) Cleanup(ctx context.Context, app domain.AppID, target domain.Ta
 	))
 }
 
-func (d *docker) tryConnect(ctx context.Context, out io.Writer, host monad.Maybe[ssh.Host], registries ...domain.Registry) (*client, error) {
+func (d *docker) ProvisionAddon(ctx context.Context, target domain.Target, addon domain.Addon) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	return client.compose.Up(ctx, newAddonProject(addon), api.UpOptions{
+		Create: api.CreateOptions{
+			RemoveOrphans: true,
+			QuietPull:     true,
+		},
+		Start: api.StartOptions{
+			Wait: true,
+		},
+	})
+}
+
+func (d *docker) RemoveAddon(ctx context.Context, target domain.Target, addon domain.AddonID, strategy domain.CleanupStrategy) error {
+	if strategy == domain.CleanupStrategySkip {
+		return nil
+	}
+
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	return client.RemoveResources(ctx, filters.NewArgs(
+		filters.Arg("label", AddonLabel+"="+string(addon)),
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+	))
+}
+
+func (d *docker) BackupAddon(ctx context.Context, target domain.Target, addon domain.Addon, w io.Writer) error {
+	if !addon.Kind().SupportsBackup() {
+		return domain.ErrAddonBackupNotSupported
+	}
+
+	return d.execAddon(ctx, target, addon, addonBackupCommand(addon.Kind()), nil, w)
+}
+
+func (d *docker) RestoreAddon(ctx context.Context, target domain.Target, addon domain.Addon, r io.Reader) error {
+	if !addon.Kind().SupportsBackup() {
+		return domain.ErrAddonBackupNotSupported
+	}
+
+	return d.execAddon(ctx, target, addon, addonRestoreCommand(addon.Kind()), r, io.Discard)
+}
+
+func (d *docker) Logs(
+	ctx context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	options domain.ServiceLogsOptions,
+	handler func(domain.ServiceLog),
+) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	logOptions := api.LogOptions{
+		Services: options.Services,
+		Follow:   options.Follow,
+		Tail:     "all",
+	}
+
+	if tail, isSet := options.Tail.TryGet(); isSet {
+		logOptions.Tail = strconv.Itoa(tail)
+	}
+
+	if since, isSet := options.Since.TryGet(); isSet {
+		logOptions.Since = since.Format(time.RFC3339Nano)
+	}
+
+	return client.compose.Logs(ctx, config.ProjectName(), serviceLogsConsumer(handler), logOptions)
+}
+
+func (d *docker) Exec(
+	ctx context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	exec domain.ServiceExec,
+) (int, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer client.Close()
+
+	return client.Exec(ctx, config.ProjectName(), exec)
+}
+
+func (d *docker) Scale(
+	ctx context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	service string,
+	replicas uint,
+) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	return client.Scale(ctx, config.ProjectName(), service, replicas)
+}
+
+func (d *docker) Restart(
+	ctx context.Context,
+	config domain.DeploymentConfig,
+	target domain.Target,
+	services []string,
+) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	return client.Restart(ctx, config.ProjectName(), services)
+}
+
+func (d *docker) Stats(ctx context.Context, target domain.Target) ([]domain.ServiceStats, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	return client.Stats(ctx, filters.NewArgs(
+		filters.Arg("label", AppLabel),
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+		filters.Arg("status", "running"),
+	))
+}
+
+func (d *docker) Incidents(ctx context.Context, target domain.Target, since, until time.Time) ([]domain.ContainerIncident, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	return client.Incidents(ctx, filters.NewArgs(
+		filters.Arg("label", AppLabel),
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+	), since, until)
+}
+
+// Recreates the proxy container of the given project, giving the old one some time to
+// drain in-flight requests, and makes sure the new one is running the expected image.
+func swapProxy(ctx context.Context, client *client, project *types.Project) error {
+	drain := proxyDrainTimeout
+
+	if err := client.compose.Up(ctx, project, api.UpOptions{
+		Create: api.CreateOptions{
+			RemoveOrphans: true,
+			QuietPull:     true,
+			Timeout:       &drain,
+		},
+		Start: api.StartOptions{
+			Wait:        true,
+			WaitTimeout: proxyStartTimeout,
+		},
+	}); err != nil {
+		return err
+	}
+
+	running, err := client.ServiceImage(ctx, project.Name, proxyServiceName)
+
+	if err != nil {
+		return err
+	}
+
+	if image, isSet := running.TryGet(); !isSet || image != project.Services[proxyServiceName].Image {
+		return ErrProxyNotRunning
+	}
+
+	return nil
+}
+
+// Retrieve the registry to which images built by the given deployment config should
+// be pushed, if any.
+func findBuildRegistry(config domain.DeploymentConfig, registries []domain.Registry) (monad.Maybe[domain.Registry], error) {
+	var result monad.Maybe[domain.Registry]
+
+	id, isSet := config.BuildRegistry().TryGet()
+
+	if !isSet {
+		return result, nil
+	}
+
+	for _, registry := range registries {
+		if registry.ID() == id {
+			result.Set(registry)
+			return result, nil
+		}
+	}
+
+	return result, domain.ErrBuildRegistryNotFound
+}
+
+// Build images of the project for every platform they target and push them to the given
+// registry. The target then pulls the variant matching its own platform instead of
+// building them again.
+func pushImages(
+	ctx context.Context,
+	client *client,
+	logger domain.DeploymentLogger,
+	registry domain.Registry,
+	project *types.Project,
+) error {
+	var built []string
+
+	for _, name := range project.ServiceNames() {
+		if project.Services[name].Build != nil {
+			built = append(built, name)
+		}
+	}
+
+	if len(built) == 0 {
+		return nil
+	}
+
+	logger.Stepf("building and pushing images of services %s to the %s registry", strings.Join(built, ", "), registry.Name())
+
+	if err := client.compose.Build(ctx, project, api.BuildOptions{
+		Quiet:    true,
+		Push:     true,
+		Services: built,
+	}); err != nil {
+		logger.Error(err)
+		return ErrComposeFailed
+	}
+
+	for _, name := range built {
+		service := project.Services[name]
+		service.PullPolicy = types.PullPolicyAlways
+		project.Services[name] = service
+	}
+
+	return nil
+}
+
+// Run a command in the container of an add-on, its error output is logged if it did
+// not succeed.
+func (d *docker) execAddon(
+	ctx context.Context,
+	target domain.Target,
+	addon domain.Addon,
+	command []string,
+	stdin io.Reader,
+	stdout io.Writer,
+) error {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	var stderr bytes.Buffer
+
+	code, err := client.Exec(ctx, addon.Host(), domain.ServiceExec{
+		Service: string(addon.Kind()),
+		Command: command,
+		Stdin:   stdin,
+		Stdout:  stdout,
+		Stderr:  &stderr,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		d.logger.Errorw("add-on command failed",
+			"addon", addon.ID(),
+			"exit_code", code,
+			"output", strings.TrimSpace(stderr.String()))
+		return ErrAddonCommandFailed
+	}
+
+	return nil
+}
+
+func (d *docker) tryConnect(ctx context.Context, out io.Writer, to endpoint, registries ...domain.Registry) (*client, error) {
 	// For tests, bypass the initialization and use the provided one
 	if d.client != nil {
 		return d.client, nil
 	}
 
-	return connect(ctx, out, host, registries...)
+	if host, overSSH := to.sshHost(); overSSH {
+		if err := d.sshPool.Acquire(ctx, host); err != nil {
+			return nil, err
+		}
+	}
+
+	return connect(ctx, out, to, registries...)
 }
 
 // Connect to the docker daemon and return a new docker cli and compose service.
@@ -347,16 +904,120 @@ func (d *docker) connect(ctx context.Context, logger domain.DeploymentLogger, ta
 		return nil, domain.ErrInvalidProviderPayload
 	}
 
-	return d.tryConnect(ctx, logger, data.Host, registries...)
+	return d.tryConnect(ctx, logger, newEndpoint(target.ID(), data), registries...)
+}
+
+// Resolves a private key from the given patch. When omitted, it is retrieved from the
+// existing config since the private key is never exposed to the end user.
+func patchPrivateKey(
+	patch monad.Patch[string],
+	parsed ssh.PrivateKey,
+	existing []domain.ProviderConfig,
+	from func(Data) monad.Maybe[ssh.PrivateKey],
+) (key monad.Maybe[ssh.PrivateKey]) {
+	if patch.IsNil() {
+		return key
+	}
+
+	if patch.HasValue() {
+		key.Set(parsed)
+		return key
+	}
+
+	for _, existingConfig := range existing {
+		d, isDockerData := existingConfig.(Data)
+
+		if !isDockerData {
+			continue
+		}
+
+		key = from(d)
+	}
+
+	return key
 }
 
-func (d *docker) configureTargetSSH(id domain.TargetID, config Data) error {
+func parseHostKey(value string) (monad.Maybe[ssh.HostKey], error) {
+	key, err := ssh.ParseHostKey(value)
+
+	if err != nil {
+		return monad.None[ssh.HostKey](), err
+	}
+
+	return monad.Value(key), nil
+}
+
+// Builds the TLS part of a docker config. The private key, if omitted, is retrieved from
+// the existing config since it is never exposed to the end user.
+func (d *docker) prepareTLS(
+	data Data,
+	port monad.Maybe[int],
+	config TlsBody,
+	existing ...domain.ProviderConfig,
+) (domain.ProviderConfig, error) {
+	data.Port.Set(port.Get(defaultTlsPort))
+
+	material := TlsData{
+		CA:   config.CA,
+		Cert: config.Cert,
+	}
+
+	if key, isSet := config.Key.TryGet(); isSet {
+		material.Key = key
+		data.Tls.Set(material)
+
+		return data, nil
+	}
+
+	for _, existingConfig := range existing {
+		existingData, isDockerData := existingConfig.(Data)
+
+		if !isDockerData {
+			continue
+		}
+
+		existingMaterial, hasTls := existingData.Tls.TryGet()
+
+		if !hasTls {
+			continue
+		}
+
+		key, err := d.tlsKey(existingMaterial.Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = validateTlsKey(config.Cert, key); err != nil {
+			return nil, validate.Wrap(err, "docker.tls.key")
+		}
+
+		material.Key = key
+		data.Tls.Set(material)
+
+		return data, nil
+	}
+
+	return nil, validate.Wrap(ErrInvalidTlsKey, "docker.tls.key")
+}
+
+func (d *docker) configureTargetSSH(ctx context.Context, id domain.TargetID, config Data) error {
 	host, isRemote := config.Host.TryGet()
 
 	if !isRemote {
 		return nil
 	}
 
+	// Settings may have changed, drop the pooled connection so a new one will be opened
+	if err := d.sshPool.Release(ctx, host); err != nil {
+		return err
+	}
+
+	// Reached over TCP, make sure a previous SSH configuration does not linger
+	if config.Tls.HasValue() {
+		return d.sshConfig.Remove(string(id))
+	}
+
 	var key monad.Maybe[ssh.ConnectionKey]
 
 	if privKey, hasKey := config.PrivateKey.TryGet(); hasKey {
@@ -366,15 +1027,49 @@ func (d *docker) configureTargetSSH(id domain.TargetID, config Data) error {
 		})
 	}
 
-	return d.sshConfig.Upsert(ssh.Connection{
+	conn := ssh.Connection{
 		Identifier: string(id),
 		Host:       host,
 		User:       config.User,
 		Port:       config.Port,
 		PrivateKey: key,
-	})
+		KeepAlive:  d.keepAlive,
+		HostKey:    config.HostKey,
+	}
+
+	if b, hasBastion := config.Bastion.TryGet(); hasBastion {
+		bastion := ssh.Bastion{
+			Host:    b.Host,
+			User:    monad.Value(b.User),
+			Port:    monad.Value(b.Port),
+			HostKey: b.HostKey,
+		}
+
+		if privKey, hasKey := b.PrivateKey.TryGet(); hasKey {
+			bastion.PrivateKey.Set(ssh.ConnectionKey{
+				Name: string(id) + "_bastion",
+				Key:  privKey,
+			})
+		}
+
+		conn.Bastion.Set(bastion)
+	}
+
+	return d.sshConfig.Upsert(conn)
+}
+
+// Compose log consumer forwarding lines written by containers to the given handler.
+// It may be called concurrently by each container being followed.
+type serviceLogsConsumer func(domain.ServiceLog)
+
+func (c serviceLogsConsumer) Log(container, message string) {
+	c(domain.ServiceLog{Container: container, Message: message})
 }
 
+func (c serviceLogsConsumer) Err(container, message string) { c.Log(container, message) }
+func (serviceLogsConsumer) Status(string, string)           {}
+func (serviceLogsConsumer) Register(string)                 {}
+
 // add some labels to a given target.
 func appendLabels(target types.Labels, labelsToAdd types.Labels) types.Labels {
 	if target == nil {
@@ -1,38 +1,100 @@
 package docker_test
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/ostools"
 	"github.com/YuukanOO/seelf/pkg/ssh"
 	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/compose/v2/pkg/api"
 	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/exp/maps"
 )
 
+// Generates a self-signed certificate valid until the given date and returns it as
+// the CA, the client certificate and its private key, all PEM encoded.
+func generateTlsMaterial(t *testing.T, notAfter time.Time) (string, string, string) {
+	privKey := must.Panic(ecdsa.GenerateKey(elliptic.P256(), rand.Reader))
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "seelf"},
+		NotBefore:             notAfter.Add(-2 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	testutil.IsNil(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(privKey)
+	testutil.IsNil(t, err)
+
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+
+	return cert, cert, key
+}
+
 type options interface {
 	artifact.LocalOptions
 }
 
+type buildCacheOptions struct {
+	artifact.LocalOptions
+}
+
+func (buildCacheOptions) BuildCacheEnabled() bool { return true }
+
 func Test_Provider(t *testing.T) {
 	logger := must.Panic(log.NewLogger())
 
@@ -43,7 +105,7 @@ func Test_Provider(t *testing.T) {
 			os.RemoveAll(opts.DataDir())
 		})
 
-		return docker.New(logger, docker.WithDockerAndCompose(mock, mock)), mock
+		return docker.New(logger, docker.WithDockerAndCompose(mock, mock), docker.WithLegacySecret([]byte("secret"))), mock
 	}
 
 	t.Run("should be able to prepare a docker provider config from a raw payload", func(t *testing.T) {
@@ -212,8 +274,151 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 		}
 	})
 
+	t.Run("should be able to prepare a docker provider config reached through a bastion", func(t *testing.T) {
+		hostKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINRBWdhdCjoIEj4Uq+12WVbRLVwL/SH9IeluylpbbS08"
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		data, err := provider.Prepare(context.Background(), docker.Body{
+			Host:    monad.Value("10.0.0.2"),
+			User:    monad.Value("test"),
+			HostKey: monad.Value(hostKey + " root@host"),
+			Bastion: monad.Value(docker.BastionBody{
+				Host:    "bastion.example.com",
+				HostKey: monad.Value(hostKey),
+			}),
+		}, docker.Data{
+			Host: monad.Value[ssh.Host]("10.0.0.2"),
+			Bastion: monad.Value(docker.BastionData{
+				Host:       "bastion.example.com",
+				PrivateKey: monad.Value[ssh.PrivateKey]("bastion"),
+			}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, data.Equals(docker.Data{
+			Host:    monad.Value[ssh.Host]("10.0.0.2"),
+			User:    monad.Value("test"),
+			Port:    monad.Value(22),
+			HostKey: monad.Value(ssh.HostKey(hostKey)),
+			Bastion: monad.Value(docker.BastionData{
+				Host:       "bastion.example.com",
+				User:       "test",
+				Port:       22,
+				PrivateKey: monad.Value[ssh.PrivateKey]("bastion"),
+				HostKey:    monad.Value(ssh.HostKey(hostKey)),
+			}),
+		}))
+
+		_, err = provider.Prepare(context.Background(), docker.Body{
+			Host:    monad.Value("10.0.0.2"),
+			HostKey: monad.Value("invalid"),
+			Bastion: monad.Value(docker.BastionBody{
+				Host: "not a host",
+			}),
+		})
+
+		fieldErrs, _ := apperr.As[validate.FieldErrors](err)
+		testutil.ErrorIs(t, ssh.ErrInvalidHostKey, fieldErrs["docker.host_key"])
+		testutil.ErrorIs(t, ssh.ErrInvalidHost, fieldErrs["docker.bastion.host"])
+	})
+
+	t.Run("should be able to prepare a docker provider config reached over TCP with mutual TLS", func(t *testing.T) {
+		ca, cert, key := generateTlsMaterial(t, time.Now().Add(time.Hour))
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		data, err := provider.Prepare(context.Background(), docker.Body{
+			Host: monad.Value("localhost"),
+			Tls: monad.Value(docker.TlsBody{
+				CA:   ca,
+				Cert: cert,
+				Key:  monad.Value(key),
+			}),
+		})
+
+		testutil.IsNil(t, err)
+
+		tlsData := data.(docker.Data)
+		material := tlsData.Tls.MustGet()
+		testutil.Equals(t, 2376, tlsData.Port.Get(0))
+		testutil.IsFalse(t, tlsData.User.HasValue())
+		testutil.Equals(t, ca, material.CA)
+		testutil.Equals(t, cert, material.Cert)
+		testutil.Equals(t, key, material.Key)
+		testutil.Equals(t, "tcp://localhost:2376", data.String())
+
+		updated, err := provider.Prepare(context.Background(), docker.Body{
+			Host: monad.Value("localhost"),
+			Tls: monad.Value(docker.TlsBody{
+				CA:   ca,
+				Cert: cert,
+			}),
+		}, data)
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, updated.Equals(data))
+	})
+
+	t.Run("should read TLS private keys encrypted by previous versions", func(t *testing.T) {
+		ca, cert, key := generateTlsMaterial(t, time.Now().Add(time.Hour))
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+		encrypted, err := must.Panic(crypto.NewCipher([]byte("secret"))).Encrypt(key)
+
+		testutil.IsNil(t, err)
+
+		data, err := provider.Prepare(context.Background(), docker.Body{
+			Host: monad.Value("localhost"),
+			Tls: monad.Value(docker.TlsBody{
+				CA:   ca,
+				Cert: cert,
+			}),
+		}, docker.Data{
+			Host: monad.Value[ssh.Host]("localhost"),
+			Tls: monad.Value(docker.TlsData{
+				CA:   ca,
+				Cert: cert,
+				Key:  encrypted,
+			}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, key, data.(docker.Data).Tls.MustGet().Key)
+	})
+
+	t.Run("should reject invalid TLS material", func(t *testing.T) {
+		_, cert, key := generateTlsMaterial(t, time.Now().Add(-time.Hour))
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		_, err := provider.Prepare(context.Background(), docker.Body{
+			Host: monad.Value("localhost"),
+			Tls: monad.Value(docker.TlsBody{
+				CA:   "not a certificate",
+				Cert: cert,
+				Key:  monad.Value(key),
+			}),
+		})
+
+		testutil.ErrorIs(t, validate.ErrValidationFailed, err)
+		fieldErrs, _ := apperr.As[validate.FieldErrors](err)
+		testutil.ErrorIs(t, docker.ErrInvalidTlsCA, fieldErrs["docker.tls.ca"])
+		testutil.ErrorIs(t, docker.ErrTlsCertificateExpired, fieldErrs["docker.tls.cert"])
+
+		_, otherCert, _ := generateTlsMaterial(t, time.Now().Add(time.Hour))
+
+		_, err = provider.Prepare(context.Background(), docker.Body{
+			Host: monad.Value("localhost"),
+			Tls: monad.Value(docker.TlsBody{
+				CA:   otherCert,
+				Cert: otherCert,
+				Key:  monad.Value(key),
+			}),
+		})
+
+		fieldErrs, _ = apperr.As[validate.FieldErrors](err)
+		testutil.ErrorIs(t, docker.ErrInvalidTlsKey, fieldErrs["docker.tls.key"])
+	})
+
 	t.Run("should setup a new non-ssl target without custom entrypoints", func(t *testing.T) {
-		target := createTarget("http://docker.localhost")
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
 		targetIdLower := strings.ToLower(string(target.ID()))
 
 		provider, mock := sut(config.Default(config.WithTestDefaults()))
@@ -234,18 +439,23 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					Image:   "traefik:v2.11",
 					Restart: types.RestartPolicyUnlessStopped,
 					Command: types.ShellCommand{
+						"--accesslog.format=json",
+						"--accesslog=true",
 						"--entrypoints.http.address=:80",
 						"--providers.docker",
 						fmt.Sprintf("--providers.docker.constraints=(Label(`%s`, `%s`) && (Label(`%s`, `true`) || LabelRegex(`%s`, `.+`))) || Label(`%s`, `true`)",
 							docker.TargetLabel, target.ID(), docker.CustomEntrypointsLabel, docker.SubdomainLabel, docker.ExposedLabel),
 						fmt.Sprintf("--providers.docker.defaultrule=Host(`{{ index .Labels %s}}.docker.localhost`)", fmt.Sprintf(`"%s"`, docker.SubdomainLabel)),
 						"--providers.docker.network=seelf-gateway-" + targetIdLower,
+						"--providers.file.directory=/seelf-static/routes",
+						"--providers.file.watch=true",
 					},
 					Ports: []types.ServicePortConfig{
 						{Target: 80, Published: "80"},
 					},
 					Volumes: []types.ServiceVolumeConfig{
 						{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+						{Type: types.VolumeTypeVolume, Source: "static-routes", Target: "/seelf-static/routes", ReadOnly: true},
 					},
 					CustomLabels: types.Labels{
 						api.ProjectLabel:     "seelf-internal-" + targetIdLower,
@@ -264,11 +474,19 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					},
 				},
 			},
+			Volumes: types.Volumes{
+				"static-routes": types.VolumeConfig{
+					Name: "seelf-static-" + targetIdLower + "_routes",
+					Labels: types.Labels{
+						docker.TargetLabel: string(target.ID()),
+					},
+				},
+			},
 		}, mock.ups[0].project)
 	})
 
 	t.Run("should setup a new ssl target without custom entrypoints", func(t *testing.T) {
-		target := createTarget("https://docker.localhost")
+		target := fixture.Target("https://docker.localhost", false, fixture.WithProvider(docker.Data{}))
 		targetIdLower := strings.ToLower(string(target.ID()))
 
 		provider, mock := sut(config.Default(config.WithTestDefaults()))
@@ -289,11 +507,14 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					Image:   "traefik:v2.11",
 					Restart: types.RestartPolicyUnlessStopped,
 					Command: types.ShellCommand{
+						"--accesslog.format=json",
+						"--accesslog=true",
 						fmt.Sprintf("--certificatesresolvers.%s.acme.storage=/letsencrypt/acme.json", "seelf-resolver-"+targetIdLower),
 						fmt.Sprintf("--certificatesresolvers.%s.acme.tlschallenge=true", "seelf-resolver-"+targetIdLower),
 						"--entrypoints.http.address=:443",
 						"--entrypoints.http.http.tls.certresolver=seelf-resolver-" + targetIdLower,
 						"--entrypoints.insecure.address=:80",
+						"--entrypoints.insecure.http.redirections.entryPoint.priority=1",
 						"--entrypoints.insecure.http.redirections.entryPoint.scheme=https",
 						"--entrypoints.insecure.http.redirections.entryPoint.to=http",
 						"--providers.docker",
@@ -301,6 +522,8 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 							docker.TargetLabel, target.ID(), docker.CustomEntrypointsLabel, docker.SubdomainLabel, docker.ExposedLabel),
 						fmt.Sprintf("--providers.docker.defaultrule=Host(`{{ index .Labels %s}}.docker.localhost`)", fmt.Sprintf(`"%s"`, docker.SubdomainLabel)),
 						"--providers.docker.network=seelf-gateway-" + targetIdLower,
+						"--providers.file.directory=/seelf-static/routes",
+						"--providers.file.watch=true",
 					},
 					Ports: []types.ServicePortConfig{
 						{Target: 80, Published: "80"},
@@ -308,6 +531,7 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					},
 					Volumes: []types.ServiceVolumeConfig{
 						{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+						{Type: types.VolumeTypeVolume, Source: "static-routes", Target: "/seelf-static/routes", ReadOnly: true},
 						{Type: types.VolumeTypeVolume, Source: "letsencrypt", Target: "/letsencrypt"},
 					},
 					CustomLabels: types.Labels{
@@ -334,12 +558,18 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 						docker.TargetLabel: string(target.ID()),
 					},
 				},
+				"static-routes": types.VolumeConfig{
+					Name: "seelf-static-" + targetIdLower + "_routes",
+					Labels: types.Labels{
+						docker.TargetLabel: string(target.ID()),
+					},
+				},
 			},
 		}, mock.ups[0].project)
 	})
 
 	t.Run("should setup a target with custom entrypoints by finding available ports", func(t *testing.T) {
-		target := createTarget("http://docker.localhost")
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
 		targetIdLower := strings.ToLower(string(target.ID()))
 		depl := createDeployment(target.ID(), "")
 
@@ -374,6 +604,8 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					Image:   "traefik:v2.11",
 					Restart: types.RestartPolicyUnlessStopped,
 					Command: types.ShellCommand{
+						"--accesslog.format=json",
+						"--accesslog=true",
 						"--entrypoints.http.address=:80",
 						fmt.Sprintf("--entrypoints.%s.address=:%d/tcp", tcp.Name(), tcpPort),
 						fmt.Sprintf("--entrypoints.%s.address=:%d/udp", udp.Name(), udpPort),
@@ -382,6 +614,8 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 							docker.TargetLabel, target.ID(), docker.CustomEntrypointsLabel, docker.SubdomainLabel, docker.ExposedLabel),
 						fmt.Sprintf("--providers.docker.defaultrule=Host(`{{ index .Labels %s}}.docker.localhost`)", fmt.Sprintf(`"%s"`, docker.SubdomainLabel)),
 						"--providers.docker.network=seelf-gateway-" + targetIdLower,
+						"--providers.file.directory=/seelf-static/routes",
+						"--providers.file.watch=true",
 					},
 					Ports: sortedPorts([]types.ServicePortConfig{
 						{Target: 80, Published: "80"},
@@ -390,6 +624,7 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					}),
 					Volumes: []types.ServiceVolumeConfig{
 						{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+						{Type: types.VolumeTypeVolume, Source: "static-routes", Target: "/seelf-static/routes", ReadOnly: true},
 					},
 					CustomLabels: types.Labels{
 						api.ProjectLabel:     "seelf-internal-" + targetIdLower,
@@ -408,11 +643,19 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					},
 				},
 			},
+			Volumes: types.Volumes{
+				"static-routes": types.VolumeConfig{
+					Name: "seelf-static-" + targetIdLower + "_routes",
+					Labels: types.Labels{
+						docker.TargetLabel: string(target.ID()),
+					},
+				},
+			},
 		}, mock.ups[1].project)
 	})
 
 	t.Run("should setup a target with custom entrypoints by using provided ports if any", func(t *testing.T) {
-		target := createTarget("http://docker.localhost")
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
 		targetIdLower := strings.ToLower(string(target.ID()))
 		depl := createDeployment(target.ID(), "")
 
@@ -460,6 +703,8 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					Image:   "traefik:v2.11",
 					Restart: types.RestartPolicyUnlessStopped,
 					Command: types.ShellCommand{
+						"--accesslog.format=json",
+						"--accesslog=true",
 						"--entrypoints.http.address=:80",
 						fmt.Sprintf("--entrypoints.%s.address=:5432/tcp", tcp.Name()),
 						fmt.Sprintf("--entrypoints.%s.address=:5433/udp", udp.Name()),
@@ -470,6 +715,8 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 							docker.TargetLabel, target.ID(), docker.CustomEntrypointsLabel, docker.SubdomainLabel, docker.ExposedLabel),
 						fmt.Sprintf("--providers.docker.defaultrule=Host(`{{ index .Labels %s}}.docker.localhost`)", fmt.Sprintf(`"%s"`, docker.SubdomainLabel)),
 						"--providers.docker.network=seelf-gateway-" + targetIdLower,
+						"--providers.file.directory=/seelf-static/routes",
+						"--providers.file.watch=true",
 					},
 					Ports: sortedPorts([]types.ServicePortConfig{
 						{Target: 80, Published: "80"},
@@ -480,6 +727,7 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					}),
 					Volumes: []types.ServiceVolumeConfig{
 						{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+						{Type: types.VolumeTypeVolume, Source: "static-routes", Target: "/seelf-static/routes", ReadOnly: true},
 					},
 					CustomLabels: types.Labels{
 						api.ProjectLabel:     "seelf-internal-" + targetIdLower,
@@ -498,11 +746,19 @@ wSD0v0RcmkITP1ZR0AAAAYcHF1ZXJuYUBMdWNreUh5ZHJvLmxvY2FsAQID
 					},
 				},
 			},
+			Volumes: types.Volumes{
+				"static-routes": types.VolumeConfig{
+					Name: "seelf-static-" + targetIdLower + "_routes",
+					Labels: types.Labels{
+						docker.TargetLabel: string(target.ID()),
+					},
+				},
+			},
 		}, mock.ups[1].project)
 	})
 
 	t.Run("should expose services from a compose file", func(t *testing.T) {
-		target := createTarget("http://docker.localhost")
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
 		depl := createDeployment(target.ID(), `services:
   sidecar:
     image: traefik/whoami
@@ -541,14 +797,14 @@ volumes:
 
 		// Prepare the build
 		opts := config.Default(config.WithTestDefaults())
-		artifactManager := artifact.NewLocal(opts, logger)
+		artifactManager := artifact.NewLocal(opts, logger, nil)
 		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
 		testutil.IsNil(t, err)
 		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
 
 		provider, mock := sut(opts)
 
-		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil)
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
 
 		testutil.IsNil(t, err)
 		testutil.HasLength(t, mock.ups, 1)
@@ -695,136 +951,1576 @@ volumes:
 			filters.Arg("label", fmt.Sprintf("%s=%s", docker.EnvironmentLabel, depl.Config().Environment())),
 		), mock.pruneFilters)
 	})
-}
 
-func createTarget(url string) domain.Target {
-	return must.Panic(domain.NewTarget(
-		"a target",
-		domain.NewTargetUrlRequirement(must.Panic(domain.UrlFrom(url)), true),
-		domain.NewProviderConfigRequirement(docker.Data{}, true),
-		"uid",
-	))
-}
+	t.Run("should import and export the build cache of the app if enabled", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    build: .
+  cached:
+    build:
+      context: .
+      cache_to:
+        - type=registry,ref=example.com/cache
+  db:
+    image: postgres:14-alpine`)
 
-func createDeployment(target domain.TargetID, data string) domain.Deployment {
-	productionConfig := domain.NewEnvironmentConfig(target)
-	productionConfig.HasEnvironmentVariables(domain.ServicesEnv{
-		"app": domain.EnvVars{
-			"DSN": "postgres://prodapp:passprod@db/app?sslmode=disable",
-		},
-		"db": domain.EnvVars{
-			"POSTGRES_USER":     "prodapp",
-			"POSTGRES_PASSWORD": "passprod",
-		},
+		opts := buildCacheOptions{config.Default(config.WithTestDefaults())}
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		provider, mock := sut(opts)
+
+		for i := 0; i < 2; i++ {
+			ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+			testutil.IsNil(t, err)
+			testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+			_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+			testutil.IsNil(t, err)
+			testutil.IsNil(t, ctx.Logger().Close())
+
+			cacheDir := filepath.Join(ctx.CacheDirectory().MustGet(), "app")
+			project := mock.ups[i].project
+
+			testutil.DeepEquals(t, types.StringList{"type=local,dest=" + cacheDir + ",mode=max"}, project.Services["app"].Build.CacheTo)
+			testutil.DeepEquals(t, types.StringList{"type=registry,ref=example.com/cache"}, project.Services["cached"].Build.CacheTo)
+			testutil.IsTrue(t, project.Services["db"].Build == nil)
+
+			if i == 0 {
+				testutil.HasLength(t, project.Services["app"].Build.CacheFrom, 0)
+				// Simulates the cache export done by the builder
+				testutil.IsNil(t, ostools.WriteFile(filepath.Join(cacheDir, "index.json"), []byte("{}")))
+			} else {
+				testutil.DeepEquals(t, types.StringList{"type=local,src=" + cacheDir}, project.Services["app"].Build.CacheFrom)
+			}
+		}
 	})
 
-	app := must.Panic(domain.NewApp(
-		"my-app",
-		domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
-		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
-		"uid",
-	))
+	t.Run("should build images for the platform of the target if known", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		target.RunsOn("linux/arm64")
+		depl := createDeployment(target.ID(), `services:
+  app:
+    build: .
+  pinned:
+    build: .
+    platform: linux/amd64
+  db:
+    image: postgres:14-alpine`)
 
-	return must.Panic(app.NewDeployment(1, raw.Data(data), domain.Production, "uid"))
-}
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		provider, mock := sut(opts)
 
-func sortedPorts(ports []types.ServicePortConfig) []types.ServicePortConfig {
-	slices.SortFunc(ports, docker.ServicePortSortFunc)
-	return ports
-}
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		defer ctx.Logger().Close()
 
-type (
-	dockerMockService struct {
-		api.Service
-		command.Cli
-		containers   map[string]types.ServiceConfig
-		ups          []up
-		downs        []down
-		pruneFilters filters.Args
-	}
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.IsNil(t, err)
 
-	dockerMockCli struct {
-		client.APIClient
-		parent *dockerMockService
-	}
+		project := mock.ups[0].project
+		testutil.Equals(t, "linux/arm64", project.Services["app"].Platform)
+		testutil.Equals(t, "linux/amd64", project.Services["pinned"].Platform)
+		testutil.Equals(t, "", project.Services["db"].Platform)
+	})
 
-	up struct {
-		project *types.Project
-		options api.UpOptions
-	}
+	t.Run("should build multi-platform images and push them to the build registry of the app", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		target.RunsOn("linux/arm64")
+		registry := must.Panic(domain.NewRegistry("my-registry",
+			domain.NewRegistryUrlRequirement(must.Panic(domain.UrlFrom("https://registry.example.com")), true), "uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "uid"))
+		testutil.IsNil(t, app.HasPlatforms(domain.Platforms{"linux/amd64", "linux/arm64"}))
+		testutil.IsNil(t, app.UseBuildRegistry(registry))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    build: .
+  db:
+    image: postgres:14-alpine`), domain.Production, "uid"))
 
-	down struct {
-		projectName string
-		options     api.DownOptions
-	}
-)
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		provider, mock := sut(opts)
 
-func newMockService() *dockerMockService {
-	return &dockerMockService{
-		containers: make(map[string]types.ServiceConfig),
-	}
-}
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		defer ctx.Logger().Close()
 
-func (c *dockerMockService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error {
-	for _, service := range project.Services {
-		if service.ContainerName != "" {
-			c.containers[service.ContainerName] = service
-		}
-	}
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, []domain.Registry{registry}, nil)
+		testutil.IsNil(t, err)
 
-	c.ups = append(c.ups, up{
-		project: project,
-		options: options,
+		testutil.DeepEquals(t, [][]string{{"app"}}, mock.pushes)
+		project := mock.ups[0].project
+		testutil.Equals(t, "registry.example.com/"+depl.Config().ImageName("app"), project.Services["app"].Image)
+		testutil.DeepEquals(t, types.StringList{"linux/amd64", "linux/arm64"}, project.Services["app"].Build.Platforms)
+		testutil.Equals(t, "linux/arm64", project.Services["app"].Platform)
+		testutil.Equals(t, types.PullPolicyAlways, project.Services["app"].PullPolicy)
+		testutil.Equals(t, "postgres:14-alpine", project.Services["db"].Image)
 	})
-	return nil
-}
 
-func (c *dockerMockService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
-	c.downs = append(c.downs, down{
-		projectName: projectName,
-		options:     options,
+	t.Run("should fail if the build registry of the app does not exist anymore", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		registry := must.Panic(domain.NewRegistry("my-registry",
+			domain.NewRegistryUrlRequirement(must.Panic(domain.UrlFrom("https://registry.example.com")), true), "uid"))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "uid"))
+		testutil.IsNil(t, app.UseBuildRegistry(registry))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    build: .`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		provider, mock := sut(opts)
+
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		defer ctx.Logger().Close()
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.ErrorIs(t, domain.ErrBuildRegistryNotFound, err)
+		testutil.HasLength(t, mock.ups, 0)
 	})
-	return nil
-}
 
-func (d *dockerMockService) Client() client.APIClient {
-	return &dockerMockCli{parent: d}
-}
+	t.Run("should apply services exposure overrides of the app", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.HasServicesExposure(domain.ServicesExposure{
+			"admin": {Port: monad.Value[domain.Port](3000), Subdomain: monad.Value("backoffice")},
+			"api":   {PathPrefix: monad.Value("/api")},
+			"db":    {Disabled: true},
+		})
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  admin:
+    image: traefik/whoami
+  api:
+    image: traefik/whoami
+    ports:
+      - "8081:80"
+  app:
+    image: traefik/whoami
+    ports:
+      - "8080:8080"
+  db:
+    image: postgres:14-alpine
+    ports:
+      - "5432:5432/tcp"`), domain.Production, "uid"))
 
-func (d *dockerMockService) Apply(ops ...command.CLIOption) error {
-	return nil
-}
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
 
-func (d *dockerMockCli) Close() error { return nil }
+		provider, mock := sut(opts)
 
-func (d *dockerMockCli) ContainerInspect(_ context.Context, containerName string) (dockertypes.ContainerJSON, error) {
-	container, found := d.parent.containers[containerName]
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
 
-	if !found {
-		return dockertypes.ContainerJSON{}, errors.New("not found")
-	}
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, services, 4)
 
-	result := dockertypes.ContainerJSON{
-		NetworkSettings: &dockertypes.NetworkSettings{
-			NetworkSettingsBase: dockertypes.NetworkSettingsBase{
-				Ports: nat.PortMap{},
-			},
-		},
-	}
+		entrypoints := services.Entrypoints()
+		testutil.HasLength(t, entrypoints, 3)
+		testutil.Equals(t, 3000, entrypoints[0].Port())
+		testutil.IsFalse(t, entrypoints[0].IsCustom())
+		testutil.Equals(t, "backoffice", entrypoints[0].Subdomain().Get(""))
+		testutil.Equals(t, 80, entrypoints[1].Port())
+		testutil.Equals(t, "my-app", entrypoints[1].Subdomain().Get(""))
+		testutil.Equals(t, "/api", entrypoints[1].PathPrefix().Get(""))
+		testutil.Equals(t, 8080, entrypoints[2].Port())
+		testutil.Equals(t, "my-app", entrypoints[2].Subdomain().Get(""))
+		testutil.IsFalse(t, entrypoints[2].PathPrefix().HasValue())
 
-	// For this mock, only assign the host port to the target one
-	for _, port := range container.Ports {
-		result.NetworkSettings.Ports[nat.Port(fmt.Sprintf("%d/%s", port.Target, port.Protocol))] = []nat.PortBinding{
-			{HostPort: strconv.FormatUint(uint64(port.Target), 10)},
-		}
-	}
+		project := mock.ups[0].project
+		expectedGatewayNetworkName := "seelf-gateway-" + strings.ToLower(string(target.ID()))
 
-	return result, nil
-}
+		testutil.Equals(t, "Host(`my-app.docker.localhost`) && PathPrefix(`/api`)",
+			project.Services["api"].Labels[fmt.Sprintf("traefik.http.routers.%s.rule", entrypoints[1].Name())])
+		testutil.HasLength(t, project.Services["db"].Ports, 0)
+		testutil.DeepEquals(t, map[string]*types.ServiceNetworkConfig{
+			"default": nil,
+		}, project.Services["db"].Networks)
+		testutil.DeepEquals(t, map[string]*types.ServiceNetworkConfig{
+			"default":                  nil,
+			expectedGatewayNetworkName: nil,
+		}, project.Services["admin"].Networks)
+	})
 
-func (d *dockerMockCli) ImagesPrune(_ context.Context, criteria filters.Args) (dockertypes.ImagesPruneReport, error) {
-	d.parent.pruneFilters = criteria
-	return dockertypes.ImagesPruneReport{}, nil
+	t.Run("should expose raw TCP and UDP ports of services configured by the app", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.HasServicesExposure(domain.ServicesExposure{
+			"db": {Ports: []domain.ExposedPort{
+				{Router: domain.RouterTcp, Port: 5432, Published: monad.Value[domain.Port](15432)},
+			}},
+			"game": {Ports: []domain.ExposedPort{
+				{Router: domain.RouterUdp, Port: 27015},
+			}},
+		})
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  db:
+    image: postgres:14-alpine
+    ports:
+      - "5432:5432/tcp"
+  game:
+    image: game-server`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+
+		entrypoints := services.Entrypoints()
+		testutil.HasLength(t, entrypoints, 2)
+		testutil.Equals(t, domain.RouterTcp, entrypoints[0].Router())
+		testutil.Equals(t, 5432, entrypoints[0].Port())
+		testutil.Equals(t, 15432, entrypoints[0].Published().Get(0))
+		testutil.Equals(t, domain.RouterUdp, entrypoints[1].Router())
+		testutil.Equals(t, 27015, entrypoints[1].Port())
+		testutil.IsFalse(t, entrypoints[1].Published().HasValue())
+
+		project := mock.ups[0].project
+
+		testutil.HasLength(t, project.Services["db"].Ports, 0)
+		testutil.Equals(t, "true", project.Services["game"].Labels[docker.CustomEntrypointsLabel])
+		testutil.Equals(t, "27015",
+			project.Services["game"].Labels[fmt.Sprintf("traefik.udp.services.%s.loadbalancer.server.port", entrypoints[1].Name())])
+	})
+
+	t.Run("should serve a static site with the shared static web server of the target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		envConfig := domain.NewEnvironmentConfig(target.ID())
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(envConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(envConfig, true, true),
+			"uid",
+		))
+		testutil.IsNil(t, app.HasStaticSite(monad.Value(must.Panic(domain.StaticSiteFrom("web", "/app/dist")))))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  web:
+    build: .`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, services, 1)
+
+		entrypoints := services.Entrypoints()
+		testutil.HasLength(t, entrypoints, 1)
+		testutil.Equals(t, domain.RouterHttp, entrypoints[0].Router())
+		testutil.Equals(t, "my-app", entrypoints[0].Subdomain().Get(""))
+
+		var (
+			server = "seelf-static-" + strings.ToLower(string(target.ID()))
+			name   = strings.ToLower(string(app.ID())) + "-production"
+		)
+
+		testutil.DeepEquals(t, [][]string{{"web"}}, mock.builds)
+		testutil.HasLength(t, mock.downs, 1)
+		testutil.Equals(t, depl.Config().ProjectName(), mock.downs[0].projectName)
+		testutil.HasLength(t, mock.ups, 1)
+		testutil.Equals(t, server, mock.ups[0].project.Name)
+		testutil.DeepEquals(t, []copied{
+			{container: server, path: "/seelf-static/sites", files: []string{name + "-1", name + "-1/index.html"}},
+			{container: server, path: "/seelf-static/routes", files: []string{name + ".yml"}},
+		}, mock.copies)
+	})
+
+	t.Run("should protect HTTP entrypoints of a protected environment", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.IsProtectedBy(must.Panic(domain.NewAccessProtection(
+			monad.Value(domain.BasicAuth{Username: "john", PasswordHash: "$2a$10$hash"}),
+			[]string{"10.0.0.0/8", "192.168.1.1"},
+			monad.Value(must.Panic(domain.UrlFrom("http://oauth2-proxy:4180/oauth2/auth"))),
+		)))
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    image: traefik/whoami
+    ports:
+      - "8080:8080"
+  db:
+    image: postgres:14-alpine
+    ports:
+      - "5432:5432/tcp"`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+
+		entrypoints := services.Entrypoints()
+		testutil.HasLength(t, entrypoints, 2)
+
+		var (
+			project = mock.ups[0].project
+			name    = string(entrypoints[0].Name())
+			labels  = project.Services["app"].Labels
+		)
+
+		testutil.Equals(t, fmt.Sprintf("%[1]s-ipallowlist,%[1]s-basicauth,%[1]s-forwardauth", name), labels[fmt.Sprintf("traefik.http.routers.%s.middlewares", name)])
+		testutil.Equals(t, "10.0.0.0/8,192.168.1.1", labels[fmt.Sprintf("traefik.http.middlewares.%s-ipallowlist.ipallowlist.sourcerange", name)])
+		testutil.Equals(t, "john:$2a$10$hash", labels[fmt.Sprintf("traefik.http.middlewares.%s-basicauth.basicauth.users", name)])
+		testutil.Equals(t, "http://oauth2-proxy:4180/oauth2/auth", labels[fmt.Sprintf("traefik.http.middlewares.%s-forwardauth.forwardauth.address", name)])
+
+		for label := range project.Services["db"].Labels {
+			testutil.IsFalse(t, strings.Contains(label, "middlewares"))
+		}
+	})
+
+	t.Run("should apply proxy rules of the environment to HTTP entrypoints", func(t *testing.T) {
+		target := fixture.Target("https://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.HasProxyRules(must.Panic(domain.NewProxyRules(
+			true,
+			[]string{"/.well-known"},
+			[]domain.Redirect{must.Panic(domain.RedirectFrom("^https://([^/]+)/old/(.*)", "https://${1}/new/${2}", false))},
+			map[string]string{"X-Frame-Options": "DENY"},
+		)))
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    image: traefik/whoami
+    ports:
+      - "8080:8080"`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+
+		var (
+			name   = string(services.Entrypoints()[0].Name())
+			labels = mock.ups[0].project.Services["app"].Labels
+			rule   = "(Host(`my-app.docker.localhost`) || Host(`www.my-app.docker.localhost`))"
+		)
+
+		testutil.Equals(t, rule, labels[fmt.Sprintf("traefik.http.routers.%s.rule", name)])
+		testutil.Equals(t, fmt.Sprintf("%[1]s-www,%[1]s-redirect0,%[1]s-headers", name), labels[fmt.Sprintf("traefik.http.routers.%s.middlewares", name)])
+		testutil.Equals(t, "${1}://${2}", labels[fmt.Sprintf("traefik.http.middlewares.%s-www.redirectregex.replacement", name)])
+		testutil.Equals(t, "^https://([^/]+)/old/(.*)", labels[fmt.Sprintf("traefik.http.middlewares.%s-redirect0.redirectregex.regex", name)])
+		testutil.Equals(t, "false", labels[fmt.Sprintf("traefik.http.middlewares.%s-redirect0.redirectregex.permanent", name)])
+		testutil.Equals(t, "DENY", labels[fmt.Sprintf("traefik.http.middlewares.%s-headers.headers.customresponseheaders.X-Frame-Options", name)])
+		testutil.Equals(t, "insecure", labels[fmt.Sprintf("traefik.http.routers.%s-insecure.entrypoints", name)])
+		testutil.Equals(t, rule+" && (PathPrefix(`/.well-known`))", labels[fmt.Sprintf("traefik.http.routers.%s-insecure.rule", name)])
+		testutil.Equals(t, name, labels[fmt.Sprintf("traefik.http.routers.%s-insecure.service", name)])
+	})
+
+	t.Run("should route URL aliases of the environment to the service exposed on the default subdomain", func(t *testing.T) {
+		target := fixture.Target("https://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.HasUrlAliases(domain.UrlAliases{"www.example.com", "example.com"})
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    image: traefik/whoami
+    ports:
+      - "8080:8080"
+  docs:
+    image: traefik/whoami
+    ports:
+      - "8081:8080"`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		services, err := provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+
+		var (
+			name    = string(services.Entrypoints()[0].Name())
+			project = mock.ups[0].project
+		)
+
+		testutil.Equals(t, "(Host(`my-app.docker.localhost`) || Host(`example.com`) || Host(`www.example.com`))",
+			project.Services["app"].Labels[fmt.Sprintf("traefik.http.routers.%s.rule", name)])
+
+		for label := range project.Services["docs"].Labels {
+			testutil.IsFalse(t, strings.HasSuffix(label, ".rule"))
+		}
+	})
+
+	t.Run("should merge the compose override of the environment with the project compose file", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		productionConfig := domain.NewEnvironmentConfig(target.ID())
+		productionConfig.HasComposeOverride(must.Panic(domain.ComposeOverrideFrom(`services:
+  app:
+    environment:
+      - DEBUG=false
+  worker:
+    image: traefik/whoami`)))
+		app := must.Panic(domain.NewApp(
+			"my-app",
+			domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			"uid",
+		))
+		depl := must.Panic(app.NewDeployment(1, raw.Data(`services:
+  app:
+    image: traefik/whoami
+    environment:
+      - DEBUG=true
+      - PORT=8080`), domain.Production, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+
+		testutil.IsNil(t, err)
+
+		project := mock.ups[0].project
+		testutil.Equals(t, 2, len(project.Services))
+		testutil.Equals(t, "false", *project.Services["app"].Environment["DEBUG"])
+		testutil.Equals(t, "8080", *project.Services["app"].Environment["PORT"])
+	})
+
+	t.Run("should provision add-ons and give their connection string to every service", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    image: traefik/whoami
+    environment:
+      - DATABASE_URL=overridden
+  worker:
+    image: traefik/whoami`)
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "uid"))
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonPostgres, monad.None[string](), nil, "uid"))
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		testutil.IsNil(t, provider.ProvisionAddon(context.Background(), target, addon))
+
+		addonProject := mock.ups[0].project
+		testutil.Equals(t, addon.Host(), addonProject.Name)
+		testutil.Equals(t, "postgres:16-alpine", addonProject.Services["postgres"].Image)
+		testutil.Equals(t, addon.Host(), addonProject.Services["postgres"].ContainerName)
+		testutil.Equals(t, string(addon.ID()), addonProject.Services["postgres"].Labels[docker.AddonLabel])
+		testutil.Equals(t, addon.Host(), addonProject.Networks["default"].Name)
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, []domain.Addon{addon})
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		project := mock.ups[1].project
+		testutil.Equals(t, addon.Host(), project.Networks[addon.Host()].Name)
+		testutil.IsTrue(t, bool(project.Networks[addon.Host()].External))
+
+		for _, service := range project.Services {
+			testutil.Equals(t, addon.ConnectionString(), *service.Environment["DATABASE_URL"])
+			_, joined := service.Networks[addon.Host()]
+			testutil.IsTrue(t, joined)
+		}
+	})
+
+	t.Run("should give shared variables of the target to every service unless overridden by the app", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		testutil.IsNil(t, target.HasSharedVariables(domain.EnvVars{
+			"SMTP_HOST": "smtp.example.com",
+			"DSN":       "shared",
+		}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    image: traefik/whoami
+  worker:
+    image: traefik/whoami`)
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, mock := sut(opts)
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		project := mock.ups[0].project
+		testutil.Equals(t, "smtp.example.com", *project.Services["app"].Environment["SMTP_HOST"])
+		testutil.Equals(t, "postgres://prodapp:passprod@db/app?sslmode=disable", *project.Services["app"].Environment["DSN"])
+		testutil.Equals(t, "smtp.example.com", *project.Services["worker"].Environment["SMTP_HOST"])
+		testutil.Equals(t, "shared", *project.Services["worker"].Environment["DSN"])
+	})
+
+	t.Run("should record a manifest of deployed images", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    restart: unless-stopped
+    build:
+      context: .
+      args:
+        - VERSION=1.23
+      dockerfile_inline: |
+        ARG VERSION=1.22
+        FROM --platform=linux/amd64 golang:$${VERSION} AS builder
+        FROM builder AS test
+        FROM scratch
+        COPY --from=builder /app /app
+  db:
+    restart: unless-stopped
+    image: postgres:14-alpine`)
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+
+		provider, _ := sut(opts)
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		data, err := os.ReadFile(artifactManager.Reports(context.Background(), depl).ManifestPath())
+		testutil.IsNil(t, err)
+
+		var manifest domain.DeploymentManifest
+		testutil.IsNil(t, json.Unmarshal(data, &manifest))
+		testutil.HasLength(t, manifest.Images, 2)
+
+		app := manifest.Images[0]
+		testutil.Equals(t, "app", app.Service)
+		testutil.Equals(t, "sha256:"+app.Image, app.ID)
+		testutil.DeepEquals(t, []string{app.Image + "@sha256:digest"}, app.RepoDigests)
+		testutil.IsTrue(t, app.Built)
+		testutil.DeepEquals(t, []string{"golang:1.23"}, app.BaseImages)
+		testutil.DeepEquals(t, map[string]string{"VERSION": "1.23"}, app.BuildArgs)
+		testutil.IsFalse(t, app.SBOM)
+
+		db := manifest.Images[1]
+		testutil.Equals(t, "db", db.Service)
+		testutil.Equals(t, "postgres:14-alpine", db.Image)
+		testutil.IsFalse(t, db.Built)
+		testutil.HasLength(t, db.BaseImages, 0)
+	})
+
+	t.Run("should scan built images and fail the deployment above the severity threshold", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    restart: unless-stopped
+    build:
+      context: .
+      dockerfile_inline: FROM alpine
+  db:
+    restart: unless-stopped
+    image: postgres:14-alpine`)
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		t.Cleanup(func() { os.RemoveAll(opts.DataDir()) })
+
+		mock := newMockService()
+		mock.runOutput = `{"matches":[{"vulnerability":{"severity":"Critical"}},{"vulnerability":{"severity":"Low"}}]}`
+		provider := docker.New(logger, docker.WithDockerAndCompose(mock, mock), docker.WithVulnerabilityScan(docker.ScanOptions{
+			Scanner: docker.ScannerGrype,
+			FailOn:  monad.Value(domain.SeverityHigh),
+		}))
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.ErrorIs(t, docker.ErrVulnerabilitiesFound, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		testutil.DeepEquals(t, [][]string{{"app"}}, mock.builds)
+		testutil.HasLength(t, mock.ups, 0)
+		testutil.HasLength(t, mock.runs, 1)
+		testutil.Equals(t, "anchore/grype:latest", mock.runs[0].Image)
+		testutil.Equals(t, "docker:"+depl.Config().ImageName("app"), mock.runs[0].Cmd[0])
+
+		report, err := os.ReadFile(artifactManager.Reports(context.Background(), depl).ScanPath("app"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, mock.runOutput, string(report))
+	})
+
+	t.Run("should classify a malformed compose file failure", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services: [`)
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		t.Cleanup(func() { os.RemoveAll(opts.DataDir()) })
+
+		mock := newMockService()
+		provider := docker.New(logger, docker.WithDockerAndCompose(mock, mock))
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.ErrorIs(t, docker.ErrLoadProjectFailed, err)
+		testutil.Equals(t, domain.DeploymentFailureComposeInvalid, domain.DeploymentFailureReasonOf(err))
+		testutil.IsNil(t, ctx.Logger().Close())
+	})
+
+	t.Run("should classify compose failures from the docker error", func(t *testing.T) {
+		tests := []struct {
+			err      error
+			expected domain.DeploymentFailureReason
+		}{
+			{errors.New("Bind for 0.0.0.0:8080 failed: port is already allocated"), domain.DeploymentFailurePortConflict},
+			{errors.New("toomanyrequests: You have reached your pull rate limit"), domain.DeploymentFailureQuotaExceeded},
+			{errors.New("write /var/lib/docker/tmp: no space left on device"), domain.DeploymentFailureQuotaExceeded},
+			{errors.New("pull access denied for unknown/image, repository does not exist"), domain.DeploymentFailureImagePull},
+			{errors.New("container app exited (1)"), domain.DeploymentFailureUnknown},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.err.Error(), func(t *testing.T) {
+				target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+				depl := createDeployment(target.ID(), `services:
+  app:
+    image: traefik/whoami`)
+
+				opts := config.Default(config.WithTestDefaults())
+				artifactManager := artifact.NewLocal(opts, logger, nil)
+				ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+				testutil.IsNil(t, err)
+				testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+				t.Cleanup(func() { os.RemoveAll(opts.DataDir()) })
+
+				mock := newMockService()
+				mock.upErr = tt.err
+				provider := docker.New(logger, docker.WithDockerAndCompose(mock, mock))
+
+				_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+				testutil.ErrorIs(t, docker.ErrComposeFailed, err)
+				testutil.Equals(t, tt.expected, domain.DeploymentFailureReasonOf(err))
+				testutil.IsNil(t, ctx.Logger().Close())
+			})
+		}
+	})
+
+	t.Run("should record vulnerabilities found in built images in the manifest", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), `services:
+  app:
+    restart: unless-stopped
+    build:
+      context: .
+      dockerfile_inline: FROM alpine`)
+
+		opts := config.Default(config.WithTestDefaults())
+		artifactManager := artifact.NewLocal(opts, logger, nil)
+		ctx, err := artifactManager.PrepareBuild(context.Background(), depl)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, raw.New().Fetch(context.Background(), ctx, depl))
+		t.Cleanup(func() { os.RemoveAll(opts.DataDir()) })
+
+		mock := newMockService()
+		mock.runOutput = `{"Results":[{"Vulnerabilities":[{"Severity":"MEDIUM"},{"Severity":"LOW"},{"Severity":"LOW"}]}]}`
+		provider := docker.New(logger, docker.WithDockerAndCompose(mock, mock), docker.WithVulnerabilityScan(docker.ScanOptions{
+			Scanner: docker.ScannerTrivy,
+			Image:   monad.Value("my-registry/trivy"),
+			FailOn:  monad.Value(domain.SeverityHigh),
+		}))
+
+		_, err = provider.Deploy(context.Background(), ctx, depl, target, nil, nil)
+		testutil.IsNil(t, err)
+		testutil.IsNil(t, ctx.Logger().Close())
+
+		testutil.HasLength(t, mock.ups, 1)
+		testutil.Equals(t, "my-registry/trivy", mock.runs[0].Image)
+
+		data, err := os.ReadFile(artifactManager.Reports(context.Background(), depl).ManifestPath())
+		testutil.IsNil(t, err)
+
+		var manifest domain.DeploymentManifest
+		testutil.IsNil(t, json.Unmarshal(data, &manifest))
+		testutil.DeepEquals(t, domain.VulnerabilitiesSummary{
+			domain.SeverityMedium: 1,
+			domain.SeverityLow:    2,
+		}, manifest.Images[0].Vulnerabilities)
+	})
+
+	t.Run("should not back up or restore add-ons which do not hold data worth it", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		app := must.Panic(domain.NewApp("my-app",
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true),
+			domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target.ID()), true, true), "uid"))
+		addon := must.Panic(domain.NewAddon(app, domain.Production, domain.AddonRedis, monad.None[string](), nil, "uid"))
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSupported, provider.BackupAddon(context.Background(), target, addon, io.Discard))
+		testutil.ErrorIs(t, domain.ErrAddonBackupNotSupported, provider.RestoreAddon(context.Background(), target, addon, strings.NewReader("")))
+	})
+
+	t.Run("should retrieve the platform of a target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		platform, err := provider.Platform(context.Background(), target)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "linux/arm64", platform)
+	})
+
+	t.Run("should upgrade the proxy of a target by pulling its image before swapping containers", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		projectName := "seelf-internal-" + strings.ToLower(string(target.ID()))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{{ID: "proxy", Image: "traefik:v2.11"}}
+
+		version, err := provider.ProxyVersion(target)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "v2.11", version)
+
+		err = provider.UpgradeProxy(context.Background(), target)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{projectName}, mock.pulls)
+		testutil.HasLength(t, mock.ups, 1)
+		testutil.Equals(t, "traefik:v2.11", mock.ups[0].project.Services["proxy"].Image)
+		testutil.Equals(t, 30*time.Second, *mock.ups[0].options.Create.Timeout)
+		testutil.IsTrue(t, mock.ups[0].options.Start.Wait)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+projectName),
+			filters.Arg("label", api.ServiceLabel+"=proxy"),
+			filters.Arg("status", "running"),
+		), mock.listFilters)
+	})
+
+	t.Run("should roll back to the previous proxy if the upgraded one is not running", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{{ID: "proxy", Image: "traefik:v2.10"}}
+
+		err := provider.UpgradeProxy(context.Background(), target)
+
+		testutil.ErrorIs(t, docker.ErrProxyUpgradeFailed, err)
+		testutil.ErrorIs(t, docker.ErrProxyNotRunning, err)
+		testutil.HasLength(t, mock.ups, 2)
+		testutil.Equals(t, "traefik:v2.11", mock.ups[0].project.Services["proxy"].Image)
+		testutil.Equals(t, "traefik:v2.10", mock.ups[1].project.Services["proxy"].Image)
+	})
+
+	t.Run("should retrieve the resources consumed by apps running on a target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		labels := func(app, env, service string) map[string]string {
+			return map[string]string{
+				docker.AppLabel:         app,
+				docker.EnvironmentLabel: env,
+				api.ServiceLabel:        service,
+			}
+		}
+		stats := func(cpu, precpu, system, presystem, memory, inactive uint64, rx uint64) dockertypes.StatsJSON {
+			var s dockertypes.StatsJSON
+			s.CPUStats.CPUUsage.TotalUsage = cpu
+			s.CPUStats.SystemUsage = system
+			s.CPUStats.OnlineCPUs = 2
+			s.PreCPUStats.CPUUsage.TotalUsage = precpu
+			s.PreCPUStats.SystemUsage = presystem
+			s.MemoryStats.Usage = memory
+			s.MemoryStats.Limit = 1000
+			s.MemoryStats.Stats = map[string]uint64{"inactive_file": inactive}
+			s.Networks = map[string]dockertypes.NetworkStats{"eth0": {RxBytes: rx, TxBytes: rx * 2}}
+			return s
+		}
+
+		mock.running = []dockertypes.Container{
+			{ID: "app-1", Labels: labels("my-app", "production", "app")},
+			{ID: "app-2", Labels: labels("my-app", "production", "app")},
+			{ID: "db-1", Labels: labels("my-app", "production", "db")},
+			{ID: "gone", Labels: labels("my-app", "staging", "app")},
+		}
+		mock.stats = map[string]dockertypes.StatsJSON{
+			"app-1": stats(200, 100, 2000, 1000, 300, 100, 10),
+			"app-2": stats(150, 100, 2000, 1000, 100, 0, 5),
+			"db-1":  stats(100, 100, 2000, 1000, 50, 0, 1),
+		}
+
+		result, err := provider.Stats(context.Background(), target)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", docker.AppLabel),
+			filters.Arg("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())),
+			filters.Arg("status", "running"),
+		), mock.listFilters)
+
+		slices.SortFunc(result, func(a, b domain.ServiceStats) int { return strings.Compare(a.Service, b.Service) })
+
+		testutil.DeepEquals(t, []domain.ServiceStats{
+			{
+				AppID:       "my-app",
+				Environment: domain.Production,
+				Service:     "app",
+				CPU:         30,
+				Memory:      300,
+				MemoryLimit: 2000,
+				NetworkRx:   15,
+				NetworkTx:   30,
+			},
+			{
+				AppID:       "my-app",
+				Environment: domain.Production,
+				Service:     "db",
+				Memory:      50,
+				MemoryLimit: 1000,
+				NetworkRx:   1,
+				NetworkTx:   2,
+			},
+		}, result)
+	})
+
+	t.Run("should restart every container of a project", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		project := depl.Config().ProjectName()
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{
+			{ID: "app-1", State: "exited", Labels: map[string]string{api.ServiceLabel: "app"}},
+			{ID: "db-1", State: "running", Labels: map[string]string{api.ServiceLabel: "db"}},
+		}
+
+		err := provider.Restart(context.Background(), depl.Config(), target, nil)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.OneoffLabel+"=False"),
+		), mock.listFilters)
+		testutil.DeepEquals(t, []string{"app-1", "db-1"}, mock.restarted)
+	})
+
+	t.Run("should only restart containers of the given services", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{
+			{ID: "app-1", Labels: map[string]string{api.ServiceLabel: "app"}},
+			{ID: "db-1", Labels: map[string]string{api.ServiceLabel: "db"}},
+			{ID: "app-2", Labels: map[string]string{api.ServiceLabel: "app"}},
+		}
+
+		err := provider.Restart(context.Background(), depl.Config(), target, []string{"app"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"app-1", "app-2"}, mock.restarted)
+	})
+
+	t.Run("should fail to restart a service without containers", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{
+			{ID: "app-1", Labels: map[string]string{api.ServiceLabel: "app"}},
+		}
+
+		err := provider.Restart(context.Background(), depl.Config(), target, []string{"app", "worker"})
+
+		testutil.ErrorIs(t, domain.ErrServiceNotRunning, err)
+		testutil.HasLength(t, mock.restarted, 0)
+	})
+
+	t.Run("should fail to scale a service without containers", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		provider, _ := sut(config.Default(config.WithTestDefaults()))
+
+		err := provider.Scale(context.Background(), depl.Config(), target, "app", 2)
+
+		testutil.ErrorIs(t, domain.ErrServiceNotRunning, err)
+	})
+
+	t.Run("should scale up a service by cloning its first container", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		project := depl.Config().ProjectName()
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		labels := types.Labels{
+			api.ProjectLabel:         project,
+			api.ServiceLabel:         "app",
+			api.ContainerNumberLabel: "1",
+		}
+		mock.running = []dockertypes.Container{{ID: "app-1", State: "running", Labels: labels}}
+		mock.containers["app-1"] = types.ServiceConfig{
+			Name:     "app",
+			Labels:   labels,
+			Networks: map[string]*types.ServiceNetworkConfig{project + "_default": nil, "seelf-public": nil},
+		}
+
+		err := provider.Scale(context.Background(), depl.Config(), target, "app", 3)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", api.ProjectLabel+"="+project),
+			filters.Arg("label", api.ServiceLabel+"=app"),
+			filters.Arg("label", api.OneoffLabel+"=False"),
+		), mock.listFilters)
+		testutil.HasLength(t, mock.runs, 2)
+		testutil.Equals(t, "2", mock.runs[0].Labels[api.ContainerNumberLabel])
+		testutil.Equals(t, "3", mock.runs[1].Labels[api.ContainerNumberLabel])
+		testutil.Equals(t, "1", labels[api.ContainerNumberLabel])
+		testutil.DeepEquals(t, []string{"seelf-public", "seelf-public"}, mock.connected)
+	})
+
+	t.Run("should scale down a service by removing its last containers", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{
+			{ID: "app-3", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "3"}},
+			{ID: "app-1", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "1"}},
+			{ID: "app-2", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "2"}},
+		}
+
+		err := provider.Scale(context.Background(), depl.Config(), target, "app", 2)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"app-3"}, mock.removed)
+		testutil.HasLength(t, mock.stopped, 0)
+		testutil.HasLength(t, mock.runs, 0)
+	})
+
+	t.Run("should stop the first container of a service scaled to 0", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		depl := createDeployment(target.ID(), "")
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{
+			{ID: "app-2", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "2"}},
+			{ID: "app-1", State: "running", Labels: map[string]string{api.ContainerNumberLabel: "1"}},
+		}
+
+		err := provider.Scale(context.Background(), depl.Config(), target, "app", 0)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"app-2"}, mock.removed)
+		testutil.DeepEquals(t, []string{"app-1"}, mock.stopped)
+	})
+
+	t.Run("should retrieve the capacity of a target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.running = []dockertypes.Container{{ID: "app-1"}, {ID: "app-2"}}
+		mock.imagesSize = 2048
+		mock.runOutput = `Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/sda1         40000000 10000000  30000000      25% /data
+`
+
+		capacity, err := provider.Capacity(context.Background(), target)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", docker.AppLabel),
+			filters.Arg("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())),
+			filters.Arg("status", "running"),
+		), mock.listFilters)
+		testutil.DeepEquals(t, []string{"df", "-Pk", "/data"}, []string(mock.runs[0].Cmd))
+		testutil.Equals(t, domain.TargetCapacity{
+			RunningContainers: 2,
+			ImagesSize:        2048,
+			DiskFree:          30000000 * 1024,
+			DiskTotal:         40000000 * 1024,
+		}, capacity)
+	})
+
+	t.Run("should fail to retrieve the capacity of a target if the disk usage could not be read", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.runOutput = "df: /data: No such file or directory"
+
+		_, err := provider.Capacity(context.Background(), target)
+
+		testutil.ErrorIs(t, docker.ErrDiskUsageFailed, err)
+	})
+
+	t.Run("should collect garbage of a target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		mock.prunedImages = []image.DeleteResponse{{Deleted: "sha256:dangling"}}
+
+		report, err := provider.CollectGarbage(context.Background(), target, 24*time.Hour)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", docker.AppLabel),
+			filters.Arg("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())),
+			filters.Arg("until", "24h0m0s"),
+		), mock.prunes["containers"])
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("dangling", "true"),
+			filters.Arg("until", "24h0m0s"),
+		), mock.pruneFilters)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())),
+			filters.Arg("until", "24h0m0s"),
+		), mock.prunes["networks"])
+		testutil.Equals(t, 1, report.Images)
+		testutil.Equals(t, 1, report.Containers)
+		testutil.Equals(t, 2, report.Networks)
+		testutil.Equals(t, 1536, report.SpaceReclaimed)
+		testutil.IsFalse(t, report.CollectedAt.IsZero())
+	})
+
+	t.Run("should collect garbage of a target regardless of its age if no minimum is given", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+
+		_, err := provider.CollectGarbage(context.Background(), target, 0)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("dangling", "true"),
+		), mock.pruneFilters)
+	})
+
+	t.Run("should retrieve incidents which happened to apps containers on a target", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		evt := func(action events.Action, container string, seconds int, exitCode string) events.Message {
+			attributes := map[string]string{
+				docker.AppLabel:         "my-app",
+				docker.EnvironmentLabel: "production",
+				api.ServiceLabel:        strings.Split(container, "-")[0],
+				"name":                  "my-app-production-" + container,
+			}
+
+			if exitCode != "" {
+				attributes["exitCode"] = exitCode
+			}
+
+			return events.Message{
+				Type:     events.ContainerEventType,
+				Action:   action,
+				Actor:    events.Actor{ID: container, Attributes: attributes},
+				TimeNano: since.Add(time.Duration(seconds) * time.Second).UnixNano(),
+			}
+		}
+
+		mock.events = []events.Message{
+			evt(events.ActionDie, "app-1", 1, "1"),   // crashed
+			evt(events.ActionKill, "db-1", 2, ""),    // stopped on purpose
+			evt(events.ActionDie, "db-1", 3, "137"),  // -
+			evt(events.ActionOOM, "worker-1", 4, ""), // out of memory
+			evt(events.ActionDie, "worker-1", 5, "137"),
+			evt(events.ActionDie, "cron-1", 6, "0"), // exited successfully
+			evt(events.ActionDie, "app-2", 7, "2"),  // restarted by its policy
+			evt(events.ActionStart, "app-2", 8, ""),
+			evt(events.ActionStart, "db-1", 9, ""),
+		}
+
+		result, err := provider.Incidents(context.Background(), target, since, since.Add(time.Minute))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, fmt.Sprintf("%d.%09d", since.Unix(), 0), mock.eventsOptions.Since)
+		testutil.Equals(t, fmt.Sprintf("%d.%09d", since.Add(time.Minute).Unix(), 0), mock.eventsOptions.Until)
+		testutil.IsTrue(t, mock.eventsOptions.Filters.ExactMatch("label", docker.AppLabel))
+		testutil.IsTrue(t, mock.eventsOptions.Filters.ExactMatch("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())))
+		testutil.IsTrue(t, mock.eventsOptions.Filters.ExactMatch("type", string(events.ContainerEventType)))
+		testutil.DeepEquals(t, []domain.ContainerIncident{
+			{
+				AppID:       "my-app",
+				Environment: domain.Production,
+				Service:     "app",
+				Container:   "my-app-production-app-1",
+				Kind:        domain.IncidentCrashed,
+				ExitCode:    monad.Value(1),
+				OccurredAt:  since.Add(time.Second),
+			},
+			{
+				AppID:       "my-app",
+				Environment: domain.Production,
+				Service:     "worker",
+				Container:   "my-app-production-worker-1",
+				Kind:        domain.IncidentOutOfMemory,
+				ExitCode:    monad.Value(137),
+				OccurredAt:  since.Add(5 * time.Second),
+			},
+			{
+				AppID:       "my-app",
+				Environment: domain.Production,
+				Service:     "app",
+				Container:   "my-app-production-app-2",
+				Kind:        domain.IncidentRestarted,
+				ExitCode:    monad.Value(2),
+				OccurredAt:  since.Add(7 * time.Second),
+			},
+		}, result)
+	})
+
+	t.Run("should retrieve requests served by the proxy to apps services", func(t *testing.T) {
+		target := fixture.Target("http://docker.localhost", false, fixture.WithProvider(docker.Data{}))
+		provider, mock := sut(config.Default(config.WithTestDefaults()))
+		since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		mock.running = []dockertypes.Container{
+			{ID: "proxy", Labels: map[string]string{
+				api.ProjectLabel: "seelf-internal-" + strings.ToLower(string(target.ID())),
+				api.ServiceLabel: "proxy",
+			}},
+			{ID: "app-1", Labels: map[string]string{
+				docker.AppLabel:         "my-app",
+				docker.EnvironmentLabel: "production",
+				api.ServiceLabel:        "app",
+				"traefik.http.routers.my-app-production-app-80-http.service":          "my-app-production-app-80-http",
+				"traefik.http.routers.my-app-production-app-80-http-insecure.service": "my-app-production-app-80-http",
+			}},
+		}
+		mock.logs = map[string]string{
+			"proxy": `time="2024-01-01T12:00:00Z" level=info msg="Configuration loaded"
+{"RouterName":"my-app-production-app-80-http@docker","DownstreamStatus":200,"Duration":3000000}
+{"RouterName":"my-app-production-app-80-http-insecure@docker","DownstreamStatus":404,"Duration":1000000}
+{"RouterName":"static-site@file","DownstreamStatus":200,"Duration":1000000}
+`,
+		}
+
+		result, err := provider.AccessLogs(context.Background(), target, since, since.Add(time.Minute))
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("%s=%s", docker.TargetLabel, target.ID())),
+		), mock.listFilters)
+		testutil.Equals(t, fmt.Sprintf("%d.%09d", since.Unix(), 0), mock.logsOptions.Since)
+		testutil.Equals(t, fmt.Sprintf("%d.%09d", since.Add(time.Minute).Unix(), 0), mock.logsOptions.Until)
+		testutil.DeepEquals(t, []domain.AccessLog{
+			{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 200, Duration: 3 * time.Millisecond},
+			{AppID: "my-app", Environment: domain.Production, Service: "app", Status: 404, Duration: time.Millisecond},
+		}, result)
+	})
+}
+
+func createDeployment(target domain.TargetID, data string) domain.Deployment {
+	productionConfig := domain.NewEnvironmentConfig(target)
+	productionConfig.HasEnvironmentVariables(domain.ServicesEnv{
+		"app": domain.EnvVars{
+			"DSN": "postgres://prodapp:passprod@db/app?sslmode=disable",
+		},
+		"db": domain.EnvVars{
+			"POSTGRES_USER":     "prodapp",
+			"POSTGRES_PASSWORD": "passprod",
+		},
+	})
+
+	app := must.Panic(domain.NewApp(
+		"my-app",
+		domain.NewEnvironmentConfigRequirement(productionConfig, true, true),
+		domain.NewEnvironmentConfigRequirement(domain.NewEnvironmentConfig(target), true, true),
+		"uid",
+	))
+
+	return must.Panic(app.NewDeployment(1, raw.Data(data), domain.Production, "uid"))
+}
+
+func sortedPorts(ports []types.ServicePortConfig) []types.ServicePortConfig {
+	slices.SortFunc(ports, docker.ServicePortSortFunc)
+	return ports
+}
+
+type (
+	dockerMockService struct {
+		api.Service
+		command.Cli
+		containers    map[string]types.ServiceConfig
+		ups           []up
+		pulls         []string
+		upErr         error
+		builds        [][]string
+		pushes        [][]string
+		runs          []*container.Config
+		runOutput     string
+		downs         []down
+		pruneFilters  filters.Args
+		listFilters   filters.Args
+		running       []dockertypes.Container
+		stats         map[string]dockertypes.StatsJSON
+		imagesSize    int64
+		prunedImages  []image.DeleteResponse
+		prunes        map[string]filters.Args
+		events        []events.Message
+		eventsOptions dockertypes.EventsOptions
+		logs          map[string]string
+		logsOptions   container.LogsOptions
+		copies        []copied
+		removed       []string
+		stopped       []string
+		connected     []string
+		restarted     []string
+	}
+
+	dockerMockCli struct {
+		client.APIClient
+		parent *dockerMockService
+	}
+
+	up struct {
+		project *types.Project
+		options api.UpOptions
+	}
+
+	down struct {
+		projectName string
+		options     api.DownOptions
+	}
+
+	copied struct {
+		container string
+		path      string
+		files     []string
+	}
+)
+
+func newMockService() *dockerMockService {
+	return &dockerMockService{
+		containers: make(map[string]types.ServiceConfig),
+		prunes:     make(map[string]filters.Args),
+	}
+}
+
+func (c *dockerMockService) Up(ctx context.Context, project *types.Project, options api.UpOptions) error {
+	for _, service := range project.Services {
+		if service.ContainerName != "" {
+			c.containers[service.ContainerName] = service
+		}
+	}
+
+	c.ups = append(c.ups, up{
+		project: project,
+		options: options,
+	})
+	return c.upErr
+}
+
+func (c *dockerMockService) Pull(_ context.Context, project *types.Project, _ api.PullOptions) error {
+	c.pulls = append(c.pulls, project.Name)
+	return nil
+}
+
+func (c *dockerMockService) Build(_ context.Context, _ *types.Project, options api.BuildOptions) error {
+	if options.Push {
+		c.pushes = append(c.pushes, options.Services)
+	} else {
+		c.builds = append(c.builds, options.Services)
+	}
+	return nil
+}
+
+func (c *dockerMockService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
+	c.downs = append(c.downs, down{
+		projectName: projectName,
+		options:     options,
+	})
+	return nil
+}
+
+func (d *dockerMockService) Client() client.APIClient {
+	return &dockerMockCli{parent: d}
+}
+
+func (d *dockerMockService) Apply(ops ...command.CLIOption) error {
+	return nil
+}
+
+func (d *dockerMockCli) Close() error { return nil }
+
+func (d *dockerMockCli) ServerVersion(context.Context) (dockertypes.Version, error) {
+	return dockertypes.Version{Os: "linux", Arch: "arm64"}, nil
+}
+
+func (d *dockerMockCli) ContainerInspect(_ context.Context, containerName string) (dockertypes.ContainerJSON, error) {
+	definition, found := d.parent.containers[containerName]
+
+	if !found {
+		return dockertypes.ContainerJSON{}, errors.New("not found")
+	}
+
+	result := dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			ID:         containerName,
+			Name:       "/" + containerName,
+			HostConfig: &container.HostConfig{},
+		},
+		Config: &container.Config{Labels: definition.Labels},
+		NetworkSettings: &dockertypes.NetworkSettings{
+			NetworkSettingsBase: dockertypes.NetworkSettingsBase{
+				Ports: nat.PortMap{},
+			},
+			Networks: map[string]*network.EndpointSettings{},
+		},
+	}
+
+	// For this mock, only assign the host port to the target one
+	for _, port := range definition.Ports {
+		result.NetworkSettings.Ports[nat.Port(fmt.Sprintf("%d/%s", port.Target, port.Protocol))] = []nat.PortBinding{
+			{HostPort: strconv.FormatUint(uint64(port.Target), 10)},
+		}
+	}
+
+	// And the first network, by name, as the primary one
+	networks := maps.Keys(definition.Networks)
+	slices.Sort(networks)
+
+	for _, name := range networks {
+		if result.HostConfig.NetworkMode == "" {
+			result.HostConfig.NetworkMode = container.NetworkMode(name)
+		}
+
+		result.NetworkSettings.Networks[name] = &network.EndpointSettings{
+			Aliases: []string{definition.Name, containerName},
+		}
+	}
+
+	return result, nil
+}
+
+func (d *dockerMockCli) ImagesPrune(_ context.Context, criteria filters.Args) (dockertypes.ImagesPruneReport, error) {
+	d.parent.pruneFilters = criteria
+	return dockertypes.ImagesPruneReport{ImagesDeleted: d.parent.prunedImages, SpaceReclaimed: 1024}, nil
+}
+
+func (d *dockerMockCli) ContainersPrune(_ context.Context, criteria filters.Args) (dockertypes.ContainersPruneReport, error) {
+	d.parent.prunes["containers"] = criteria
+	return dockertypes.ContainersPruneReport{ContainersDeleted: []string{"stopped-app"}, SpaceReclaimed: 512}, nil
+}
+
+func (d *dockerMockCli) NetworksPrune(_ context.Context, criteria filters.Args) (dockertypes.NetworksPruneReport, error) {
+	d.parent.prunes["networks"] = criteria
+	return dockertypes.NetworksPruneReport{NetworksDeleted: []string{"app_default", "app_internal"}}, nil
+}
+
+func (d *dockerMockCli) ImageInspectWithRaw(_ context.Context, image string) (dockertypes.ImageInspect, []byte, error) {
+	return dockertypes.ImageInspect{
+		ID:          "sha256:" + image,
+		RepoDigests: []string{image + "@sha256:digest"},
+	}, nil, nil
+}
+
+func (d *dockerMockCli) ContainerCreate(_ context.Context, config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *ocispec.Platform, _ string) (container.CreateResponse, error) {
+	d.parent.runs = append(d.parent.runs, config)
+	return container.CreateResponse{ID: "run"}, nil
+}
+
+func (d *dockerMockCli) ContainerAttach(context.Context, string, container.AttachOptions) (dockertypes.HijackedResponse, error) {
+	var output bytes.Buffer
+
+	if _, err := stdcopy.NewStdWriter(&output, stdcopy.Stdout).Write([]byte(d.parent.runOutput)); err != nil {
+		return dockertypes.HijackedResponse{}, err
+	}
+
+	conn, _ := net.Pipe()
+
+	return dockertypes.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&output)}, nil
+}
+
+func (d *dockerMockCli) ContainerWait(context.Context, string, container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	status := make(chan container.WaitResponse, 1)
+	status <- container.WaitResponse{StatusCode: 0}
+	return status, make(chan error)
+}
+
+func (d *dockerMockCli) ContainerStart(context.Context, string, container.StartOptions) error {
+	return nil
+}
+
+func (d *dockerMockCli) ContainerRemove(_ context.Context, id string, _ container.RemoveOptions) error {
+	d.parent.removed = append(d.parent.removed, id)
+	return nil
+}
+
+func (d *dockerMockCli) ContainerStop(_ context.Context, id string, _ container.StopOptions) error {
+	d.parent.stopped = append(d.parent.stopped, id)
+	return nil
+}
+
+func (d *dockerMockCli) ContainerRestart(_ context.Context, id string, _ container.StopOptions) error {
+	d.parent.restarted = append(d.parent.restarted, id)
+	return nil
+}
+
+func (d *dockerMockCli) NetworkConnect(_ context.Context, networkName, _ string, _ *network.EndpointSettings) error {
+	d.parent.connected = append(d.parent.connected, networkName)
+	return nil
+}
+
+func (d *dockerMockCli) CopyFromContainer(_ context.Context, _ string, srcPath string) (io.ReadCloser, dockertypes.ContainerPathStat, error) {
+	var (
+		archive bytes.Buffer
+		w       = tar.NewWriter(&archive)
+		root    = filepath.Base(srcPath)
+		content = []byte("<h1>Hello</h1>")
+	)
+
+	if err := w.WriteHeader(&tar.Header{Name: root + "/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+
+	if err := w.WriteHeader(&tar.Header{Name: root + "/index.html", Mode: 0644, Size: int64(len(content))}); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+
+	return io.NopCloser(&archive), dockertypes.ContainerPathStat{}, w.Close()
+}
+
+func (d *dockerMockCli) CopyToContainer(_ context.Context, container, path string, content io.Reader, _ dockertypes.CopyToContainerOptions) error {
+	r := tar.NewReader(content)
+	entry := copied{container: container, path: path}
+
+	for {
+		header, err := r.Next()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		entry.files = append(entry.files, header.Name)
+	}
+
+	d.parent.copies = append(d.parent.copies, entry)
+
+	return nil
+}
+
+func (d *dockerMockCli) ContainerList(_ context.Context, options container.ListOptions) ([]dockertypes.Container, error) {
+	d.parent.listFilters = options.Filters
+	return d.parent.running, nil
+}
+
+func (d *dockerMockCli) ContainerStats(_ context.Context, id string, _ bool) (dockertypes.ContainerStats, error) {
+	stats, found := d.parent.stats[id]
+
+	if !found {
+		return dockertypes.ContainerStats{}, errdefs.NotFound(errors.New("not found"))
+	}
+
+	data, err := json.Marshal(stats)
+
+	return dockertypes.ContainerStats{Body: io.NopCloser(bytes.NewReader(data))}, err
+}
+
+func (d *dockerMockCli) DiskUsage(context.Context, dockertypes.DiskUsageOptions) (dockertypes.DiskUsage, error) {
+	return dockertypes.DiskUsage{LayersSize: d.parent.imagesSize}, nil
+}
+
+func (d *dockerMockCli) Info(context.Context) (system.Info, error) {
+	return system.Info{DockerRootDir: "/var/lib/docker"}, nil
+}
+
+func (d *dockerMockCli) ContainerLogs(_ context.Context, id string, options container.LogsOptions) (io.ReadCloser, error) {
+	d.parent.logsOptions = options
+
+	var output bytes.Buffer
+
+	if _, err := stdcopy.NewStdWriter(&output, stdcopy.Stdout).Write([]byte(d.parent.logs[id])); err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(&output), nil
+}
+
+func (d *dockerMockCli) Events(_ context.Context, options dockertypes.EventsOptions) (<-chan events.Message, <-chan error) {
+	d.parent.eventsOptions = options
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		for _, msg := range d.parent.events {
+			messages <- msg
+		}
+
+		errs <- io.EOF
+	}()
+
+	return messages, errs
 }
 
 // func (d *dockerMockService) ContainerList(context.Context, container.ListOptions) ([]dockertypes.Container, error) {
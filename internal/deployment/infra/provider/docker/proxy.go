@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/compose-spec/compose-go/v2/types"
@@ -13,7 +14,12 @@ import (
 
 const (
 	httpMainEntryPoint      = "http"
+	httpInsecureEntryPoint  = "insecure"
 	portsFinderStartingPort = 8080
+	proxyServiceName        = "proxy"
+	proxyVersion            = "v2.11"          // Version of traefik deployed on targets, bump it to upgrade them
+	proxyDrainTimeout       = 30 * time.Second // Time given to the old proxy to finish in-flight requests when swapped
+	proxyStartTimeout       = 2 * time.Minute
 )
 
 type (
@@ -55,7 +61,7 @@ func newProxyProjectBuilder(client *client, target domain.Target) *proxyProjectB
 		entrypoints: target.CustomEntrypoints(),
 		assigned:    make(domain.TargetEntrypointsAssigned),
 		networkName: targetPublicNetworkName(target.ID()),
-		projectName: "seelf-internal-" + idLower,
+		projectName: proxyProjectName(id),
 		labels:      types.Labels{TargetLabel: string(id)},
 	}
 
@@ -96,12 +102,18 @@ func (b *proxyProjectBuilder) createComposeProject() {
 				Labels: b.labels,
 			},
 		},
+		Volumes: types.Volumes{
+			"static-routes": types.VolumeConfig{
+				Name:   staticRoutesVolumeName(domain.TargetID(b.target)),
+				Labels: b.labels,
+			},
+		},
 	}
 
 	b.proxy = types.ServiceConfig{
-		Name:    "proxy",
+		Name:    proxyServiceName,
 		Labels:  b.labels,
-		Image:   "traefik:v2.11",
+		Image:   "traefik:" + proxyVersion,
 		Restart: types.RestartPolicyUnlessStopped,
 		Command: types.ShellCommand{
 			// "--api.insecure=true",
@@ -109,6 +121,12 @@ func (b *proxyProjectBuilder) createComposeProject() {
 			"--providers.docker.network=" + b.networkName,
 			"--providers.docker.constraints=(Label(`" + TargetLabel + "`, `" + b.target + "`) && (Label(`" + CustomEntrypointsLabel + "`, `true`) || LabelRegex(`" + SubdomainLabel + "`, `.+`))) || Label(`" + ExposedLabel + "`, `true`)",
 			"--providers.docker.defaultrule=Host(`{{ index .Labels " + `"` + SubdomainLabel + `"` + "}}." + b.host + "`)",
+			// Routes of static sites served by the shared static web server of the target
+			"--providers.file.directory=" + staticRoutesPath,
+			"--providers.file.watch=true",
+			// Access logs are read back to compute the traffic of apps
+			"--accesslog=true",
+			"--accesslog.format=json",
 			"--entrypoints." + httpMainEntryPoint + ".address=:80",
 		},
 		Ports: []types.ServicePortConfig{
@@ -117,15 +135,18 @@ func (b *proxyProjectBuilder) createComposeProject() {
 		},
 		Volumes: []types.ServiceVolumeConfig{
 			{Type: types.VolumeTypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+			{Type: types.VolumeTypeVolume, Source: "static-routes", Target: staticRoutesPath, ReadOnly: true},
 		},
 		CustomLabels: getProjectCustomLabels(b.projectName, "proxy", ""),
 	}
 
 	if b.certResolverName != "" {
 		b.proxy.Command = append(b.proxy.Command[:len(b.proxy.Command)-1],
-			"--entrypoints.insecure.address=:80",
-			"--entrypoints.insecure.http.redirections.entryPoint.to="+httpMainEntryPoint,
-			"--entrypoints.insecure.http.redirections.entryPoint.scheme=https",
+			"--entrypoints."+httpInsecureEntryPoint+".address=:80",
+			"--entrypoints."+httpInsecureEntryPoint+".http.redirections.entryPoint.to="+httpMainEntryPoint,
+			"--entrypoints."+httpInsecureEntryPoint+".http.redirections.entryPoint.scheme=https",
+			// Lowest priority so routers of insecure paths defined by apps take precedence
+			"--entrypoints."+httpInsecureEntryPoint+".http.redirections.entryPoint.priority=1",
 			"--entrypoints."+httpMainEntryPoint+".address=:443",
 			"--certificatesresolvers."+b.certResolverName+".acme.tlschallenge=true",
 			"--certificatesresolvers."+b.certResolverName+".acme.storage=/letsencrypt/acme.json",
@@ -142,11 +163,9 @@ func (b *proxyProjectBuilder) createComposeProject() {
 			Target: "/letsencrypt",
 		})
 
-		b.project.Volumes = types.Volumes{
-			"letsencrypt": types.VolumeConfig{
-				Name:   b.projectName + "_letsencrypt",
-				Labels: b.labels,
-			},
+		b.project.Volumes["letsencrypt"] = types.VolumeConfig{
+			Name:   b.projectName + "_letsencrypt",
+			Labels: b.labels,
 		}
 	}
 }
@@ -287,6 +306,11 @@ func ServicePortSortFunc(a, b types.ServicePortConfig) int {
 	return int(a.Target) - int(b.Target)
 }
 
+// Retrieve the compose project name of the proxy deployed on a specific target
+func proxyProjectName(id domain.TargetID) string {
+	return "seelf-internal-" + strings.ToLower(string(id))
+}
+
 // Retrieve the network name of a specific target
 func targetPublicNetworkName(id domain.TargetID) string {
 	return "seelf-gateway-" + strings.ToLower(string(id))
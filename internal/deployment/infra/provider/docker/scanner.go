@@ -0,0 +1,265 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+)
+
+var (
+	ErrVulnerabilityScanFailed = errors.New("vulnerability_scan_failed")
+	ErrVulnerabilitiesFound    = errors.New("vulnerabilities_found")
+)
+
+const (
+	ScannerGrype = "grype"
+	ScannerTrivy = "trivy"
+)
+
+var severitiesOrder = []domain.VulnerabilitySeverity{
+	domain.SeverityCritical,
+	domain.SeverityHigh,
+	domain.SeverityMedium,
+	domain.SeverityLow,
+	domain.SeverityNegligible,
+	domain.SeverityUnknown,
+}
+
+type (
+	// Configure how built images are scanned for known vulnerabilities.
+	ScanOptions struct {
+		Scanner string                                    // One of ScannerGrype or ScannerTrivy
+		Image   monad.Maybe[string]                       // Image of the scanner to run, default to the official one
+		FailOn  monad.Maybe[domain.VulnerabilitySeverity] // Fail the deployment if vulnerabilities of this severity or higher are found
+	}
+
+	scanner struct {
+		image   string
+		command func(image string) []string
+		parse   func(io.Reader) (domain.VulnerabilitiesSummary, error)
+	}
+
+	grypeReport struct {
+		Matches []struct {
+			Vulnerability struct {
+				Severity string `json:"severity"`
+			} `json:"vulnerability"`
+		} `json:"matches"`
+	}
+
+	trivyReport struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				Severity string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+)
+
+var scanners = map[string]scanner{
+	ScannerGrype: {
+		image: "anchore/grype:latest",
+		command: func(image string) []string {
+			return []string{"docker:" + image, "--output", "json", "--quiet"}
+		},
+		parse: func(r io.Reader) (domain.VulnerabilitiesSummary, error) {
+			var report grypeReport
+
+			if err := json.NewDecoder(r).Decode(&report); err != nil {
+				return nil, err
+			}
+
+			summary := make(domain.VulnerabilitiesSummary)
+
+			for _, match := range report.Matches {
+				summary[severity(match.Vulnerability.Severity)]++
+			}
+
+			return summary, nil
+		},
+	},
+	ScannerTrivy: {
+		image: "aquasec/trivy:latest",
+		command: func(image string) []string {
+			return []string{"image", "--format", "json", "--quiet", image}
+		},
+		parse: func(r io.Reader) (domain.VulnerabilitiesSummary, error) {
+			var report trivyReport
+
+			if err := json.NewDecoder(r).Decode(&report); err != nil {
+				return nil, err
+			}
+
+			summary := make(domain.VulnerabilitiesSummary)
+
+			for _, result := range report.Results {
+				for _, vulnerability := range result.Vulnerabilities {
+					summary[severity(vulnerability.Severity)]++
+				}
+			}
+
+			return summary, nil
+		},
+	},
+}
+
+// Scan every image built by a deployment for known vulnerabilities. Images are built
+// first so the deployment could be stopped before running anything if the threshold
+// is exceeded.
+func WithVulnerabilityScan(options ScanOptions) DockerOptions {
+	return func(d *docker) {
+		d.scan.Set(options)
+	}
+}
+
+// Build images of the project and scan them using the configured scanner. Reports are
+// written in the deployment reports directory and summaries returned by service name.
+func (d *docker) scanImages(
+	ctx context.Context,
+	client *client,
+	logger domain.DeploymentLogger,
+	reports domain.DeploymentReports,
+	project *types.Project,
+) (map[string]domain.VulnerabilitiesSummary, error) {
+	options := d.scan.MustGet()
+	tool := scanners[options.Scanner]
+	var built, toBuild []string
+
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+
+		if service.Build == nil {
+			continue
+		}
+
+		built = append(built, name)
+
+		// Images already pushed to a build registry are scanned from there
+		if service.PullPolicy == types.PullPolicyBuild {
+			toBuild = append(toBuild, name)
+		}
+	}
+
+	if len(built) == 0 {
+		logger.Infof("no image built by this deployment, nothing to scan")
+		return nil, nil
+	}
+
+	if len(toBuild) > 0 {
+		logger.Stepf("building images to scan for known vulnerabilities")
+
+		if err := client.compose.Build(ctx, project, api.BuildOptions{
+			Quiet:    true,
+			Services: toBuild,
+		}); err != nil {
+			logger.Error(err)
+			return nil, ErrComposeFailed
+		}
+	}
+
+	var (
+		summaries = make(map[string]domain.VulnerabilitiesSummary, len(built))
+		failing   []string
+	)
+
+	for _, name := range built {
+		image := project.Services[name].Image
+
+		logger.Stepf("scanning image %s with %s", image, options.Scanner)
+
+		summary, err := d.scanImage(ctx, client, tool, options.Image.Get(tool.image), reports.ScanPath(name), image)
+
+		if err != nil {
+			logger.Error(err)
+			return summaries, ErrVulnerabilityScanFailed
+		}
+
+		summaries[name] = summary
+		logger.Infof("found %s in image %s", formatSummary(summary), image)
+
+		if threshold, isSet := options.FailOn.TryGet(); isSet && summary.AtLeast(threshold) > 0 {
+			failing = append(failing, name)
+		}
+	}
+
+	if len(failing) > 0 {
+		logger.Error(fmt.Errorf("vulnerabilities of severity %s or higher found in services %s, see the scan reports for details",
+			options.FailOn.MustGet(), strings.Join(failing, ", ")))
+		return summaries, ErrVulnerabilitiesFound
+	}
+
+	return summaries, nil
+}
+
+func (d *docker) scanImage(
+	ctx context.Context,
+	client *client,
+	scanner scanner,
+	scannerImage, path, image string,
+) (domain.VulnerabilitiesSummary, error) {
+	// The report is read back once written to compute the summary
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var stderr strings.Builder
+
+	code, err := client.Run(ctx, scannerImage,
+		scanner.command(image),
+		[]string{dockerSocket + ":" + dockerSocket},
+		file, &stderr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("scanner exited with code %d: %s", code, strings.TrimSpace(stderr.String()))
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return scanner.parse(file)
+}
+
+// Normalize a severity returned by a scanner.
+func severity(value string) domain.VulnerabilitySeverity {
+	s, err := domain.VulnerabilitySeverityFrom(value)
+
+	if err != nil {
+		return domain.SeverityUnknown
+	}
+
+	return s
+}
+
+func formatSummary(summary domain.VulnerabilitiesSummary) string {
+	var parts []string
+
+	for _, s := range severitiesOrder {
+		if count := summary[s]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, s))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "no known vulnerability"
+	}
+
+	return "vulnerabilities (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,354 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v2/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrStaticSiteServiceNotFound = errors.New("static_site_service_not_found")
+	ErrStaticSitePublishFailed   = errors.New("static_site_publish_failed")
+)
+
+const (
+	staticServiceName = "static"
+	staticServerImage = "nginx:1.27-alpine"
+	staticRoot        = "/seelf-static"
+	staticSitesPath   = staticRoot + "/sites"
+	staticRoutesPath  = staticRoot + "/routes"
+	staticSiteHeader  = "X-Seelf-Static-Site" // Header set by the proxy to tell the web server which site to serve
+)
+
+// Configuration of the shared web server. The site is given by the proxy, each site
+// being served from its own directory.
+const staticServerConfig = `server {
+	listen 80;
+	server_tokens off;
+
+	if ($http_x_seelf_static_site !~ "^[a-z0-9-]+$") {
+		return 404;
+	}
+
+	root ` + staticSitesPath + `/$http_x_seelf_static_site;
+
+	location / {
+		try_files $uri $uri/ $uri.html =404;
+	}
+}
+`
+
+// Builds the compose project running the web server shared by every static site of
+// a target. Routes of the sites are read by the proxy from the routes volume.
+func newStaticServerProject(target domain.TargetID) *types.Project {
+	var (
+		name        = staticProjectName(target)
+		networkName = targetPublicNetworkName(target)
+		labels      = types.Labels{TargetLabel: string(target)}
+	)
+
+	return &types.Project{
+		Name: name,
+		Services: types.Services{
+			staticServiceName: {
+				Name:          staticServiceName,
+				ContainerName: name,
+				Image:         staticServerImage,
+				Labels:        labels,
+				Restart:       types.RestartPolicyUnlessStopped,
+				Environment:   types.NewMappingWithEquals([]string{"SEELF_STATIC_CONFIG=" + staticServerConfig}),
+				Entrypoint:    types.ShellCommand{"/bin/sh", "-c"},
+				Command: types.ShellCommand{
+					`printf '%s' "$SEELF_STATIC_CONFIG" > /etc/nginx/conf.d/default.conf && exec nginx -g 'daemon off;'`,
+				},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "sites", Target: staticSitesPath},
+					{Type: types.VolumeTypeVolume, Source: "routes", Target: staticRoutesPath},
+				},
+				Networks: map[string]*types.ServiceNetworkConfig{
+					networkName: nil,
+				},
+				CustomLabels: getProjectCustomLabels(name, staticServiceName, ""),
+			},
+		},
+		Networks: types.Networks{
+			networkName: types.NetworkConfig{
+				Name:     networkName,
+				External: true,
+			},
+		},
+		Volumes: types.Volumes{
+			"sites": types.VolumeConfig{
+				Name:   staticSitesVolumeName(target),
+				Labels: labels,
+			},
+			"routes": types.VolumeConfig{
+				Name:   staticRoutesVolumeName(target),
+				Labels: labels,
+			},
+		},
+	}
+}
+
+// Publish assets built by the static site service to the web server shared by every
+// static site of the target. Each deployment gets its own directory and the route of
+// the site is switched to it once copied so visitors never see a partially updated site.
+func (d *docker) deployStaticSite(
+	ctx context.Context,
+	client *client,
+	logger domain.DeploymentLogger,
+	depl domain.Deployment,
+	target domain.Target,
+	project *types.Project,
+	site domain.StaticSite,
+) (services domain.Services, version string, err error) {
+	definition, found := project.Services[site.Service]
+
+	if !found {
+		return nil, "", domain.NewDeploymentFailure(domain.DeploymentFailureComposeInvalid, ErrStaticSiteServiceNotFound)
+	}
+
+	var (
+		config = depl.Config()
+		server = staticProjectName(target.ID())
+		name   = staticSiteName(config.AppID(), config.Environment())
+	)
+
+	version = fmt.Sprintf("%s-%d", name, depl.ID().DeploymentNumber())
+
+	logger.Stepf("building assets of static site service %s", site.Service)
+
+	// Images pushed to the build registry of the app are pulled instead of being built again
+	if definition.Build != nil && definition.PullPolicy != types.PullPolicyAlways {
+		err = client.compose.Build(ctx, project, api.BuildOptions{
+			Quiet:    true,
+			Services: []string{site.Service},
+		})
+	} else {
+		err = client.compose.Pull(ctx, project, api.PullOptions{Quiet: true})
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return nil, "", composeFailure(err)
+	}
+
+	// Containers of a previous regular deployment of this environment are not needed anymore
+	if err = client.compose.Down(ctx, project.Name, api.DownOptions{RemoveOrphans: true}); err != nil {
+		logger.Error(err)
+		return nil, "", ErrComposeFailed
+	}
+
+	logger.Stepf("starting the shared static web server of the target")
+
+	if err = client.compose.Up(ctx, newStaticServerProject(target.ID()), api.UpOptions{
+		Create: api.CreateOptions{
+			QuietPull: true,
+		},
+		Start: api.StartOptions{
+			Wait: true,
+		},
+	}); err != nil {
+		logger.Error(err)
+		return nil, "", composeFailure(err)
+	}
+
+	logger.Stepf("copying %s of service %s to the shared static web server", site.Directory, site.Service)
+
+	content, err := client.CopyFromImage(ctx, definition.Image, site.Directory)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, "", ErrStaticSitePublishFailed
+	}
+
+	defer content.Close()
+
+	if err = client.CopyToContainer(ctx, server, staticSitesPath, rerootArchive(content, version)); err != nil {
+		logger.Error(err)
+		return nil, "", ErrStaticSitePublishFailed
+	}
+
+	service := config.NewService(site.Service, definition.Image)
+	entrypoint := service.AddHttpEntrypoint(config, 80, domain.HttpEntrypointOptions{
+		Managed:             true,
+		UseDefaultSubdomain: true,
+		Subdomain:           config.ExposureFor(site.Service).Get(domain.ServiceExposure{}).Subdomain,
+	})
+
+	routes, err := staticSiteRoutes(target, entrypoint, name, version)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, "", ErrStaticSitePublishFailed
+	}
+
+	if err = client.CopyToContainer(ctx, server, staticRoutesPath, routes); err != nil {
+		logger.Error(err)
+		return nil, "", ErrStaticSitePublishFailed
+	}
+
+	return domain.Services{service}, version, nil
+}
+
+// Remove files and route of a static site, except the files of the version being served
+// if any. Nothing is done if the shared static web server is not running on the target.
+func removeStaticSite(ctx context.Context, client *client, target domain.TargetID, name string, serving monad.Maybe[string]) error {
+	image, err := client.ServiceImage(ctx, staticProjectName(target), staticServiceName)
+
+	if err != nil || !image.HasValue() {
+		return err
+	}
+
+	script := fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 -name '%s-*'", staticSitesPath, name)
+
+	if version, isSet := serving.TryGet(); isSet {
+		script += fmt.Sprintf(" ! -name '%s' -exec rm -rf {} +", version)
+	} else {
+		script = fmt.Sprintf("rm -f %s/%s.yml && %s -exec rm -rf {} +", staticRoutesPath, name, script)
+	}
+
+	var output bytes.Buffer
+
+	code, err := client.Run(ctx, image.MustGet(), []string{"/bin/sh", "-c", script}, []string{
+		staticSitesVolumeName(target) + ":" + staticSitesPath,
+		staticRoutesVolumeName(target) + ":" + staticRoutesPath,
+	}, &output, &output)
+
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("could not remove static site files: %s", strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
+
+// Builds the archive of the dynamic proxy configuration routing requests of a static
+// site to the shared web server.
+func staticSiteRoutes(target domain.Target, entrypoint domain.Entrypoint, name, version string) (io.Reader, error) {
+	router := string(entrypoint.Name())
+	host := entrypoint.Subdomain().MustGet() + "." + target.Url().Host()
+
+	content, err := yaml.Marshal(map[string]any{
+		"http": map[string]any{
+			"routers": map[string]any{
+				router: map[string]any{
+					"rule":        fmt.Sprintf("Host(`%s`)", host),
+					"entryPoints": []string{httpMainEntryPoint},
+					"middlewares": []string{router},
+					"service":     router,
+				},
+			},
+			"middlewares": map[string]any{
+				router: map[string]any{
+					"headers": map[string]any{
+						"customRequestHeaders": map[string]string{
+							staticSiteHeader: version,
+						},
+					},
+				},
+			},
+			"services": map[string]any{
+				router: map[string]any{
+					"loadBalancer": map[string]any{
+						"servers": []map[string]string{
+							{"url": "http://" + staticProjectName(target.ID())},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+
+	w := tar.NewWriter(&archive)
+
+	if err = w.WriteHeader(&tar.Header{
+		Name: name + ".yml",
+		Mode: 0644,
+		Size: int64(len(content)),
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(content); err != nil {
+		return nil, err
+	}
+
+	return &archive, w.Close()
+}
+
+// Rewrites entries of an archive read from an image so they are rooted in the given
+// directory instead of the base name of the copied path.
+func rerootArchive(content io.Reader, root string) io.Reader {
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(reroot(tar.NewReader(content), tar.NewWriter(writer), root))
+	}()
+
+	return reader
+}
+
+func reroot(r *tar.Reader, w *tar.Writer, root string) error {
+	for {
+		header, err := r.Next()
+
+		if errors.Is(err, io.EOF) {
+			return w.Close()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		header.Name = rerootPath(header.Name, root)
+
+		if header.Typeflag == tar.TypeLink {
+			header.Linkname = rerootPath(header.Linkname, root)
+		}
+
+		if err = w.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(w, r); err != nil {
+			return err
+		}
+	}
+}
+
+func rerootPath(name, root string) string {
+	_, rest, _ := strings.Cut(name, "/")
+	return path.Join(root, rest)
+}
+
+func staticProjectName(id domain.TargetID) string {
+	return "seelf-static-" + strings.ToLower(string(id))
+}
+
+func staticSitesVolumeName(id domain.TargetID) string  { return staticProjectName(id) + "_sites" }
+func staticRoutesVolumeName(id domain.TargetID) string { return staticProjectName(id) + "_routes" }
+
+func staticSiteName(app domain.AppID, env domain.Environment) string {
+	return strings.ToLower(string(app)) + "-" + string(env)
+}
@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/ostools"
+)
+
+var (
+	ErrInvalidTlsCA          = apperr.New("invalid_tls_ca")
+	ErrInvalidTlsCertificate = apperr.New("invalid_tls_certificate")
+	ErrTlsCertificateExpired = apperr.New("tls_certificate_expired")
+	ErrInvalidTlsKey         = apperr.New("invalid_tls_key")
+)
+
+const (
+	tlsCAFilename   = "ca.pem"
+	tlsCertFilename = "cert.pem"
+	tlsKeyFilename  = "key.pem"
+)
+
+// Checks the given PEM encoded certificate authority can be used to verify the daemon.
+func validateTlsCA(value string) error {
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(value)) {
+		return ErrInvalidTlsCA
+	}
+
+	return nil
+}
+
+// Checks the given PEM encoded client certificate is valid and not expired.
+func validateTlsCertificate(value string) error {
+	block, _ := pem.Decode([]byte(value))
+
+	if block == nil {
+		return ErrInvalidTlsCertificate
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+
+	if err != nil {
+		return ErrInvalidTlsCertificate
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		return ErrTlsCertificateExpired
+	}
+
+	return nil
+}
+
+// Checks the given PEM encoded private key matches the client certificate.
+func validateTlsKey(cert, key string) error {
+	if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+		return ErrInvalidTlsKey
+	}
+
+	return nil
+}
+
+// Retrieve the PEM encoded private key from the stored one, decrypting it if it has been
+// written by a previous version which encrypted it on its own.
+func (d *docker) tlsKey(stored string) (string, error) {
+	if block, _ := pem.Decode([]byte(stored)); block != nil {
+		return stored, nil
+	}
+
+	legacy, isSet := d.legacy.TryGet()
+
+	if !isSet {
+		return "", ErrInvalidTlsKey
+	}
+
+	return legacy.Decrypt(stored)
+}
+
+// Writes the decrypted TLS material of a target in its own directory so the docker
+// cli can use it. Any previous material is removed first.
+func (d *docker) configureTargetTLS(id domain.TargetID, config Data) error {
+	dir := targetTlsDir(id)
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	material, isSet := config.Tls.TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	key, err := d.tlsKey(material.Key)
+
+	if err != nil {
+		return err
+	}
+
+	for name, content := range map[string]string{
+		tlsCAFilename:   material.CA,
+		tlsCertFilename: material.Cert,
+		tlsKeyFilename:  key,
+	} {
+		if err = ostools.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func targetTlsDir(id domain.TargetID) string {
+	return filepath.Join(tlsConfigDir, string(id))
+}
@@ -2,6 +2,8 @@ package provider
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 )
@@ -33,14 +35,14 @@ func (f *facade) Prepare(ctx context.Context, payload any, existing ...domain.Pr
 	return nil, domain.ErrNoValidProviderFound
 }
 
-func (f *facade) Deploy(ctx context.Context, info domain.DeploymentContext, depl domain.Deployment, target domain.Target, registries []domain.Registry) (domain.Services, error) {
+func (f *facade) Deploy(ctx context.Context, info domain.DeploymentContext, depl domain.Deployment, target domain.Target, registries []domain.Registry, addons []domain.Addon) (domain.Services, error) {
 	provider, err := f.providerForTarget(target)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return provider.Deploy(ctx, info, depl, target, registries)
+	return provider.Deploy(ctx, info, depl, target, registries, addons)
 }
 
 func (f *facade) Setup(ctx context.Context, target domain.Target) (domain.TargetEntrypointsAssigned, error) {
@@ -53,6 +55,36 @@ func (f *facade) Setup(ctx context.Context, target domain.Target) (domain.Target
 	return provider.Setup(ctx, target)
 }
 
+func (f *facade) Platform(ctx context.Context, target domain.Target) (domain.Platform, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return "", err
+	}
+
+	return provider.Platform(ctx, target)
+}
+
+func (f *facade) ProxyVersion(target domain.Target) (string, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return "", err
+	}
+
+	return provider.ProxyVersion(target)
+}
+
+func (f *facade) UpgradeProxy(ctx context.Context, target domain.Target) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.UpgradeProxy(ctx, target)
+}
+
 func (f *facade) RemoveConfiguration(ctx context.Context, target domain.Target) error {
 	provider, err := f.providerForTarget(target)
 
@@ -83,6 +115,136 @@ func (f *facade) Cleanup(ctx context.Context, app domain.AppID, target domain.Ta
 	return provider.Cleanup(ctx, app, target, env, strategy)
 }
 
+func (f *facade) ProvisionAddon(ctx context.Context, target domain.Target, addon domain.Addon) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.ProvisionAddon(ctx, target, addon)
+}
+
+func (f *facade) RemoveAddon(ctx context.Context, target domain.Target, addon domain.AddonID, strategy domain.CleanupStrategy) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.RemoveAddon(ctx, target, addon, strategy)
+}
+
+func (f *facade) BackupAddon(ctx context.Context, target domain.Target, addon domain.Addon, w io.Writer) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.BackupAddon(ctx, target, addon, w)
+}
+
+func (f *facade) RestoreAddon(ctx context.Context, target domain.Target, addon domain.Addon, r io.Reader) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.RestoreAddon(ctx, target, addon, r)
+}
+
+func (f *facade) Logs(ctx context.Context, config domain.DeploymentConfig, target domain.Target, options domain.ServiceLogsOptions, handler func(domain.ServiceLog)) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.Logs(ctx, config, target, options, handler)
+}
+
+func (f *facade) Exec(ctx context.Context, config domain.DeploymentConfig, target domain.Target, exec domain.ServiceExec) (int, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return provider.Exec(ctx, config, target, exec)
+}
+
+func (f *facade) Stats(ctx context.Context, target domain.Target) ([]domain.ServiceStats, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Stats(ctx, target)
+}
+
+func (f *facade) Incidents(ctx context.Context, target domain.Target, since, until time.Time) ([]domain.ContainerIncident, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Incidents(ctx, target, since, until)
+}
+
+func (f *facade) Capacity(ctx context.Context, target domain.Target) (domain.TargetCapacity, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return domain.TargetCapacity{}, err
+	}
+
+	return provider.Capacity(ctx, target)
+}
+
+func (f *facade) CollectGarbage(ctx context.Context, target domain.Target, minAge time.Duration) (domain.GarbageCollectionReport, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return domain.GarbageCollectionReport{}, err
+	}
+
+	return provider.CollectGarbage(ctx, target, minAge)
+}
+
+func (f *facade) Scale(ctx context.Context, config domain.DeploymentConfig, target domain.Target, service string, replicas uint) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.Scale(ctx, config, target, service, replicas)
+}
+
+func (f *facade) Restart(ctx context.Context, config domain.DeploymentConfig, target domain.Target, services []string) error {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return err
+	}
+
+	return provider.Restart(ctx, config, target, services)
+}
+
+func (f *facade) AccessLogs(ctx context.Context, target domain.Target, since, until time.Time) ([]domain.AccessLog, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.AccessLogs(ctx, target, since, until)
+}
+
 func (f *facade) providerForTarget(target domain.Target) (Provider, error) {
 	config := target.Provider()
 
@@ -29,7 +29,7 @@ func Test_Facade(t *testing.T) {
 	t.Run("should return an error if no provider can handle the deployment", func(t *testing.T) {
 		sut := provider.NewFacade()
 
-		_, err := sut.Deploy(context.Background(), domain.DeploymentContext{}, depl, target, nil)
+		_, err := sut.Deploy(context.Background(), domain.DeploymentContext{}, depl, target, nil, nil)
 
 		testutil.ErrorIs(t, domain.ErrNoValidProviderFound, err)
 	})
@@ -42,6 +42,15 @@ func Test_Facade(t *testing.T) {
 		testutil.ErrorIs(t, domain.ErrNoValidProviderFound, err)
 	})
 
+	t.Run("should return an error if no provider can upgrade the target proxy", func(t *testing.T) {
+		sut := provider.NewFacade()
+
+		_, err := sut.ProxyVersion(target)
+
+		testutil.ErrorIs(t, domain.ErrNoValidProviderFound, err)
+		testutil.ErrorIs(t, domain.ErrNoValidProviderFound, sut.UpgradeProxy(context.Background(), target))
+	})
+
 	t.Run("should return an error if no provider can unconfigure the target", func(t *testing.T) {
 		sut := provider.NewFacade()
 
@@ -0,0 +1,103 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	DopplerStore = "doppler"
+
+	defaultDopplerEndpoint = "https://api.doppler.com"
+)
+
+type (
+	// Options needed to reach Doppler.
+	DopplerOptions struct {
+		Token    string // Service or personal token
+		Endpoint string // Defaults to the Doppler API
+	}
+
+	doppler struct {
+		options monad.Maybe[DopplerOptions]
+		client  *http.Client
+	}
+
+	dopplerSecret struct {
+		Value struct {
+			Computed string `json:"computed"`
+		} `json:"value"`
+	}
+)
+
+// Builds a resolver for references such as doppler://myproject/prd/DATABASE_URL. Service
+// tokens being scoped to a config, the project and config could be omitted with them,
+// ie. doppler://DATABASE_URL.
+func NewDoppler(options monad.Maybe[DopplerOptions]) domain.SecretResolver {
+	return &doppler{options, &http.Client{}}
+}
+
+func (*doppler) CanResolve(store string) bool { return store == DopplerStore }
+
+func (d *doppler) Resolve(ctx context.Context, ref domain.SecretReference) (string, error) {
+	options, isSet := d.options.TryGet()
+
+	if !isSet {
+		return "", domain.ErrSecretStoreNotConfigured
+	}
+
+	query := url.Values{}
+
+	switch parts := strings.Split(ref.Path(), "/"); len(parts) {
+	case 1:
+		query.Set("name", parts[0])
+	case 3:
+		query.Set("project", parts[0])
+		query.Set("config", parts[1])
+		query.Set("name", parts[2])
+	default:
+		return "", domain.ErrInvalidSecretReference
+	}
+
+	if query.Get("name") == "" {
+		return "", domain.ErrInvalidSecretReference
+	}
+
+	endpoint := options.Endpoint
+
+	if endpoint == "" {
+		endpoint = defaultDopplerEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(endpoint, "/")+"/v3/configs/config/secret?"+query.Encode(), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+options.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := send(d.client, req)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	var secret dopplerSecret
+
+	if err = json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+
+	return secret.Value.Computed, nil
+}
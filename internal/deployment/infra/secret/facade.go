@@ -0,0 +1,68 @@
+// Package secret resolves references to secrets kept in external stores (HashiCorp
+// Vault, AWS SSM Parameter Store, Doppler) found in environment variables values.
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected_status")
+
+type facade struct {
+	resolvers []domain.SecretResolver
+}
+
+// Creates a new facade which will call the appropriate resolver based on the store
+// of the reference.
+func NewFacade(resolvers ...domain.SecretResolver) domain.SecretResolver {
+	return &facade{resolvers}
+}
+
+func (f *facade) CanResolve(store string) bool {
+	for _, r := range f.resolvers {
+		if r.CanResolve(store) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *facade) Resolve(ctx context.Context, ref domain.SecretReference) (string, error) {
+	for _, r := range f.resolvers {
+		if r.CanResolve(ref.Store()) {
+			return r.Resolve(ctx, ref)
+		}
+	}
+
+	return "", domain.ErrSecretStoreNotConfigured
+}
+
+// Sends the request and returns the response if successful. The caller must close it.
+func send(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrSecretNotFound
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, detail)
+}
@@ -0,0 +1,156 @@
+package secret_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/secret"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Facade(t *testing.T) {
+	ref := func(value string) domain.SecretReference {
+		r, _ := domain.SecretReferenceFrom(value)
+		return r
+	}
+
+	serve := func(t *testing.T, handler http.HandlerFunc) string {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	t.Run("should only handle known stores", func(t *testing.T) {
+		facade := secret.NewFacade(
+			secret.NewVault(monad.None[secret.VaultOptions]()),
+			secret.NewSSM(monad.None[secret.SSMOptions]()),
+			secret.NewDoppler(monad.None[secret.DopplerOptions]()),
+		)
+
+		testutil.IsTrue(t, facade.CanResolve(secret.VaultStore))
+		testutil.IsTrue(t, facade.CanResolve(secret.SSMStore))
+		testutil.IsTrue(t, facade.CanResolve(secret.DopplerStore))
+		testutil.IsFalse(t, facade.CanResolve("postgres"))
+	})
+
+	t.Run("should fail if the store has not been configured", func(t *testing.T) {
+		facade := secret.NewFacade(secret.NewVault(monad.None[secret.VaultOptions]()))
+
+		_, err := facade.Resolve(context.Background(), ref("vault://secret/data/app#password"))
+
+		testutil.ErrorIs(t, domain.ErrSecretStoreNotConfigured, err)
+	})
+
+	t.Run("should resolve secrets from a Vault KV v2 engine", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "/v1/secret/data/app", r.URL.Path)
+			testutil.Equals(t, "a token", r.Header.Get("X-Vault-Token"))
+
+			w.Write([]byte(`{"data":{"data":{"password":"a sensitive value","port":5432},"metadata":{"version":1}}}`))
+		})
+		resolver := secret.NewVault(monad.Value(secret.VaultOptions{Address: url, Token: "a token"}))
+
+		value, err := resolver.Resolve(context.Background(), ref("vault://secret/data/app#password"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "a sensitive value", value)
+
+		value, err = resolver.Resolve(context.Background(), ref("vault://secret/data/app#port"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "5432", value)
+
+		_, err = resolver.Resolve(context.Background(), ref("vault://secret/data/app#missing"))
+		testutil.ErrorIs(t, domain.ErrSecretNotFound, err)
+
+		_, err = resolver.Resolve(context.Background(), ref("vault://secret/data/app"))
+		testutil.ErrorIs(t, domain.ErrInvalidSecretReference, err)
+	})
+
+	t.Run("should resolve secrets from a Vault KV v1 engine", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/kv/app" {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Write([]byte(`{"data":{"password":"a sensitive value"}}`))
+		})
+		resolver := secret.NewVault(monad.Value(secret.VaultOptions{Address: url, Token: "a token"}))
+
+		value, err := resolver.Resolve(context.Background(), ref("vault://kv/app#password"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "a sensitive value", value)
+
+		_, err = resolver.Resolve(context.Background(), ref("vault://kv/other#password"))
+		testutil.ErrorIs(t, domain.ErrSecretNotFound, err)
+	})
+
+	t.Run("should resolve secrets from the AWS Parameter Store", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "AmazonSSM.GetParameter", r.Header.Get("X-Amz-Target"))
+			testutil.IsTrue(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/"))
+
+			var input struct {
+				Name           string
+				WithDecryption bool
+			}
+
+			testutil.IsNil(t, json.NewDecoder(r.Body).Decode(&input))
+			testutil.IsTrue(t, input.WithDecryption)
+
+			if input.Name != "/app/db-password" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type":"ParameterNotFound"}`))
+				return
+			}
+
+			w.Write([]byte(`{"Parameter":{"Name":"/app/db-password","Value":"a sensitive value"}}`))
+		})
+		resolver := secret.NewSSM(monad.Value(secret.SSMOptions{
+			Region:    "eu-west-3",
+			Endpoint:  url,
+			AccessKey: "access",
+			SecretKey: "secret",
+		}))
+
+		value, err := resolver.Resolve(context.Background(), ref("ssm:///app/db-password"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "a sensitive value", value)
+
+		_, err = resolver.Resolve(context.Background(), ref("ssm:///app/missing"))
+		testutil.ErrorIs(t, domain.ErrSecretNotFound, err)
+	})
+
+	t.Run("should resolve secrets from Doppler", func(t *testing.T) {
+		url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			testutil.Equals(t, "/v3/configs/config/secret", r.URL.Path)
+			testutil.Equals(t, "Bearer a token", r.Header.Get("Authorization"))
+
+			if r.URL.Query().Get("name") != "API_KEY" {
+				http.NotFound(w, r)
+				return
+			}
+
+			testutil.Equals(t, "app", r.URL.Query().Get("project"))
+			testutil.Equals(t, "prd", r.URL.Query().Get("config"))
+
+			w.Write([]byte(`{"name":"API_KEY","value":{"raw":"a sensitive value","computed":"a sensitive value"}}`))
+		})
+		resolver := secret.NewDoppler(monad.Value(secret.DopplerOptions{Token: "a token", Endpoint: url}))
+
+		value, err := resolver.Resolve(context.Background(), ref("doppler://app/prd/API_KEY"))
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "a sensitive value", value)
+
+		_, err = resolver.Resolve(context.Background(), ref("doppler://app/prd/MISSING"))
+		testutil.ErrorIs(t, domain.ErrSecretNotFound, err)
+
+		_, err = resolver.Resolve(context.Background(), ref("doppler://app/API_KEY"))
+		testutil.ErrorIs(t, domain.ErrInvalidSecretReference, err)
+	})
+}
@@ -0,0 +1,143 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	SSMStore = "ssm"
+
+	ssmService           = "ssm"
+	ssmGetParameter      = "AmazonSSM.GetParameter"
+	ssmParameterNotFound = "ParameterNotFound"
+	defaultSSMRegion     = "us-east-1"
+)
+
+type (
+	// Options needed to reach the AWS Systems Manager Parameter Store.
+	SSMOptions struct {
+		Region    string // Defaults to us-east-1
+		Endpoint  string // Defaults to the regional AWS endpoint
+		AccessKey string
+		SecretKey string
+	}
+
+	ssm struct {
+		options monad.Maybe[SSMOptions]
+		client  *http.Client
+		signer  *v4.Signer
+	}
+
+	ssmGetParameterInput struct {
+		Name           string
+		WithDecryption bool
+	}
+
+	ssmGetParameterOutput struct {
+		Parameter struct {
+			Value string
+		}
+	}
+
+	ssmError struct {
+		Type string `json:"__type"`
+	}
+)
+
+// Builds a resolver for references such as ssm:///myapp/prod/db-password, where the
+// path is the name of the parameter. Secure strings are decrypted.
+func NewSSM(options monad.Maybe[SSMOptions]) domain.SecretResolver {
+	return &ssm{options, &http.Client{}, v4.NewSigner()}
+}
+
+func (*ssm) CanResolve(store string) bool { return store == SSMStore }
+
+func (s *ssm) Resolve(ctx context.Context, ref domain.SecretReference) (string, error) {
+	options, isSet := s.options.TryGet()
+
+	if !isSet {
+		return "", domain.ErrSecretStoreNotConfigured
+	}
+
+	region := options.Region
+
+	if region == "" {
+		region = defaultSSMRegion
+	}
+
+	endpoint := options.Endpoint
+
+	if endpoint == "" {
+		endpoint = "https://ssm." + region + ".amazonaws.com"
+	}
+
+	body, err := json.Marshal(ssmGetParameterInput{
+		Name:           ref.Path(),
+		WithDecryption: true,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", ssmGetParameter)
+
+	hash := sha256.Sum256(body)
+
+	if err = s.signer.SignHTTP(ctx, aws.Credentials{
+		AccessKeyID:     options.AccessKey,
+		SecretAccessKey: options.SecretKey,
+	}, req, hex.EncodeToString(hash[:]), ssmService, region, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.Do(req)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		var apiErr ssmError
+
+		// Missing parameters are reported as bad requests with a dedicated error type
+		if json.Unmarshal(detail, &apiErr) == nil && strings.HasSuffix(apiErr.Type, ssmParameterNotFound) {
+			return "", domain.ErrSecretNotFound
+		}
+
+		return "", fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, detail)
+	}
+
+	var output ssmGetParameterOutput
+
+	if err = json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		return "", err
+	}
+
+	return output.Parameter.Value, nil
+}
@@ -0,0 +1,113 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	VaultStore = "vault"
+
+	vaultKeySeparator = "#"
+)
+
+type (
+	// Options needed to reach a HashiCorp Vault server.
+	VaultOptions struct {
+		Address   string // Such as https://vault.example.com:8200
+		Token     string
+		Namespace string // Enterprise namespace, if any
+	}
+
+	vault struct {
+		options monad.Maybe[VaultOptions]
+		client  *http.Client
+	}
+
+	vaultSecret struct {
+		Data map[string]any `json:"data"`
+	}
+)
+
+// Builds a resolver for references such as vault://secret/data/myapp#password, where
+// the path is the API path of the secret and the fragment the key to read. Both KV v1
+// and v2 engines are supported.
+func NewVault(options monad.Maybe[VaultOptions]) domain.SecretResolver {
+	return &vault{options, &http.Client{}}
+}
+
+func (*vault) CanResolve(store string) bool { return store == VaultStore }
+
+func (v *vault) Resolve(ctx context.Context, ref domain.SecretReference) (string, error) {
+	options, isSet := v.options.TryGet()
+
+	if !isSet {
+		return "", domain.ErrSecretStoreNotConfigured
+	}
+
+	path, key, found := strings.Cut(ref.Path(), vaultKeySeparator)
+
+	if !found || path == "" || key == "" {
+		return "", domain.ErrInvalidSecretReference
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(options.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", options.Token)
+
+	if options.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", options.Namespace)
+	}
+
+	resp, err := send(v.client, req)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	var secret vaultSecret
+
+	if err = json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+
+	data := secret.Data
+
+	// KV v2 engines nest the secret data along with its metadata
+	if nested, isNested := data["data"].(map[string]any); isNested {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	return stringValue(data, key)
+}
+
+// Retrieve the value of the given key, non string values are returned as JSON.
+func stringValue(data map[string]any, key string) (string, error) {
+	value, exists := data[key]
+
+	if !exists || value == nil {
+		return "", domain.ErrSecretNotFound
+	}
+
+	if str, isString := value.(string); isString {
+		return str, nil
+	}
+
+	raw, err := json.Marshal(value)
+
+	return string(raw), err
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	AddonBackupsStore interface {
+		domain.AddonBackupsReader
+		domain.AddonBackupsWriter
+	}
+
+	addonBackupsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewAddonBackupsStore(db *sqlite.Database) AddonBackupsStore {
+	return &addonBackupsStore{db}
+}
+
+func (s *addonBackupsStore) GetByID(ctx context.Context, id domain.AddonBackupID) (domain.AddonBackup, error) {
+	return builder.
+		Query[domain.AddonBackup](`
+		SELECT
+			id
+			,addon_id
+			,app_id
+			,status
+			,size
+			,errcode
+			,restore_requested_at
+			,restore_requested_by
+			,restore_finished_at
+			,restore_errcode
+			,created_at
+		FROM addon_backups
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.AddonBackupFrom)
+}
+
+func (s *addonBackupsStore) GetByAddon(ctx context.Context, addon domain.AddonID) ([]domain.AddonBackup, error) {
+	return builder.
+		Query[domain.AddonBackup](`
+		SELECT
+			id
+			,addon_id
+			,app_id
+			,status
+			,size
+			,errcode
+			,restore_requested_at
+			,restore_requested_by
+			,restore_finished_at
+			,restore_errcode
+			,created_at
+		FROM addon_backups
+		WHERE addon_id = ?
+		ORDER BY created_at DESC`, addon).
+		All(s.db, ctx, domain.AddonBackupFrom)
+}
+
+func (s *addonBackupsStore) Write(ctx context.Context, backups ...*domain.AddonBackup) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, backups, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.AddonBackupCreated:
+			return builder.
+				Insert("addon_backups", builder.Values{
+					"id":         evt.ID,
+					"addon_id":   evt.AddonID,
+					"app_id":     evt.AppID,
+					"status":     domain.AddonBackupStatusRunning,
+					"size":       0,
+					"created_at": evt.CreatedAt,
+				}).
+				Exec(s.db, ctx)
+		case domain.AddonBackupCompleted:
+			return builder.
+				Update("addon_backups", builder.Values{
+					"status":  evt.Status,
+					"size":    evt.Size,
+					"errcode": evt.ErrCode,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonRestoreRequested:
+			return builder.
+				Update("addon_backups", builder.Values{
+					"restore_requested_at": evt.Restore.Requested().At(),
+					"restore_requested_by": evt.Restore.Requested().By(),
+					"restore_finished_at":  evt.Restore.FinishedAt(),
+					"restore_errcode":      evt.Restore.ErrCode(),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonRestoreFinished:
+			return builder.
+				Update("addon_backups", builder.Values{
+					"restore_finished_at": evt.Restore.FinishedAt(),
+					"restore_errcode":     evt.Restore.ErrCode(),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonBackupDeleted:
+			return builder.
+				Command("DELETE FROM addon_backups WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
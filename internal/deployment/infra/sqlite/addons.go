@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	AddonsStore interface {
+		domain.AddonsReader
+		domain.AddonsWriter
+	}
+
+	addonsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewAddonsStore(db *sqlite.Database) AddonsStore {
+	return &addonsStore{db}
+}
+
+func (s *addonsStore) GetByID(ctx context.Context, id domain.AddonID) (domain.Addon, error) {
+	return builder.
+		Query[domain.Addon](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,kind
+			,variable
+			,username
+			,password
+			,target_id
+			,state_status
+			,state_version
+			,state_errcode
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+		FROM addons
+		WHERE id = ?`, id).
+		Decrypt("password").
+		One(s.db, ctx, domain.AddonFrom)
+}
+
+func (s *addonsStore) GetByApp(ctx context.Context, app domain.AppID) ([]domain.Addon, error) {
+	return builder.
+		Query[domain.Addon](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,kind
+			,variable
+			,username
+			,password
+			,target_id
+			,state_status
+			,state_version
+			,state_errcode
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+		FROM addons
+		WHERE app_id = ?`, app).
+		Decrypt("password").
+		All(s.db, ctx, domain.AddonFrom)
+}
+
+func (s *addonsStore) GetByAppEnv(ctx context.Context, app domain.AppID, env domain.Environment) ([]domain.Addon, error) {
+	return builder.
+		Query[domain.Addon](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,kind
+			,variable
+			,username
+			,password
+			,target_id
+			,state_status
+			,state_version
+			,state_errcode
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+		FROM addons
+		WHERE app_id = ? AND environment = ?
+		ORDER BY kind`, app, env).
+		Decrypt("password").
+		All(s.db, ctx, domain.AddonFrom)
+}
+
+func (s *addonsStore) GetDueForBackup(ctx context.Context, before time.Time) ([]domain.Addon, error) {
+	return builder.
+		Query[domain.Addon](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,kind
+			,variable
+			,username
+			,password
+			,target_id
+			,state_status
+			,state_version
+			,state_errcode
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+		FROM addons
+		WHERE state_status = ? AND cleanup_requested_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM addon_backups
+				WHERE addon_backups.addon_id = addons.id AND addon_backups.created_at > ?
+			)`, domain.AddonStatusReady, before).
+		Decrypt("password").
+		All(s.db, ctx, domain.AddonFrom)
+}
+
+func (s *addonsStore) Write(ctx context.Context, addons ...*domain.Addon) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, addons, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.AddonCreated:
+			return builder.
+				Insert("addons", builder.Values{
+					"id":            evt.ID,
+					"app_id":        evt.AppID,
+					"environment":   evt.Environment,
+					"kind":          evt.Kind,
+					"variable":      evt.Variable,
+					"username":      evt.Credentials.Username(),
+					"password":      s.db.Encrypted(evt.Credentials.Password()),
+					"target_id":     evt.Target,
+					"state_status":  evt.State.Status(),
+					"state_version": evt.State.Version(),
+					"state_errcode": evt.State.ErrCode(),
+					"created_at":    evt.Created.At(),
+					"created_by":    evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.AddonTargetChanged:
+			return builder.
+				Update("addons", builder.Values{
+					"target_id":     evt.Target,
+					"state_status":  evt.State.Status(),
+					"state_version": evt.State.Version(),
+					"state_errcode": evt.State.ErrCode(),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonStateChanged:
+			return builder.
+				Update("addons", builder.Values{
+					"state_status":  evt.State.Status(),
+					"state_version": evt.State.Version(),
+					"state_errcode": evt.State.ErrCode(),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonCleanupRequested:
+			return builder.
+				Update("addons", builder.Values{
+					"cleanup_requested_at": evt.Requested.At(),
+					"cleanup_requested_by": evt.Requested.By(),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AddonDeleted:
+			return builder.
+				Command("DELETE FROM addons WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
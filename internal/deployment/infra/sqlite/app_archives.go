@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	AppArchivesStore interface {
+		domain.AppArchivesReader
+		domain.AppArchivesWriter
+	}
+
+	appArchivesStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewAppArchivesStore(db *sqlite.Database) AppArchivesStore {
+	return &appArchivesStore{db}
+}
+
+func (s *appArchivesStore) GetByID(ctx context.Context, id domain.AppArchiveID) (domain.AppArchive, error) {
+	return builder.
+		Query[domain.AppArchive](`
+		SELECT
+			id
+			,app_id
+			,app_name
+			,status
+			,size
+			,errcode
+			,expires_at
+			,requested_at
+			,requested_by
+		FROM app_archives
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.AppArchiveFrom)
+}
+
+func (s *appArchivesStore) GetExpired(ctx context.Context, at time.Time) ([]domain.AppArchive, error) {
+	return builder.
+		Query[domain.AppArchive](`
+		SELECT
+			id
+			,app_id
+			,app_name
+			,status
+			,size
+			,errcode
+			,expires_at
+			,requested_at
+			,requested_by
+		FROM app_archives
+		WHERE expires_at <= ?`, at).
+		All(s.db, ctx, domain.AppArchiveFrom)
+}
+
+func (s *appArchivesStore) Write(ctx context.Context, archives ...*domain.AppArchive) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, archives, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.AppArchiveCreated:
+			return builder.
+				Insert("app_archives", builder.Values{
+					"id":           evt.ID,
+					"app_id":       evt.AppID,
+					"app_name":     evt.AppName,
+					"status":       domain.AppArchiveStatusRunning,
+					"size":         0,
+					"requested_at": evt.Requested.At(),
+					"requested_by": evt.Requested.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.AppArchiveCompleted:
+			return builder.
+				Update("app_archives", builder.Values{
+					"status":     evt.Status,
+					"size":       evt.Size,
+					"errcode":    evt.ErrCode,
+					"expires_at": evt.ExpiresAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppArchiveDeleted:
+			return builder.
+				Command("DELETE FROM app_archives WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
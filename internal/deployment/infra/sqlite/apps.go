@@ -117,6 +117,38 @@ func (s *appsStore) CheckAppNamingAvailabilityByID(
 	return productionRequirement, stagingRequirement, err
 }
 
+func (s *appsStore) CheckUrlAliasesAvailability(
+	ctx context.Context,
+	id domain.AppID,
+	env domain.Environment,
+	aliases domain.UrlAliases,
+) (domain.UrlAliasesRequirement, error) {
+	// This is safe to interpolate the column name here since environments are validated
+	// by our own code.
+	available, err := builder.
+		Query[bool](`
+		SELECT
+			NOT EXISTS(
+				SELECT 1 FROM apps, json_each(apps.production_url_aliases) AS aliases
+				WHERE apps.production_target = src.`+string(env)+`_target
+					AND NOT (apps.id = src.id AND ? = 'production')
+					AND aliases.value IN (SELECT value FROM json_each(?))
+			) AND NOT EXISTS(
+				SELECT 1 FROM apps, json_each(apps.staging_url_aliases) AS aliases
+				WHERE apps.staging_target = src.`+string(env)+`_target
+					AND NOT (apps.id = src.id AND ? = 'staging')
+					AND aliases.value IN (SELECT value FROM json_each(?))
+			)
+		FROM apps src WHERE src.id = ?`, env, aliases, env, aliases, id).
+		Extract(s.db, ctx)
+
+	if err != nil {
+		return domain.UrlAliasesRequirement{}, err
+	}
+
+	return domain.NewUrlAliasesRequirement(aliases, available), nil
+}
+
 func (s *appsStore) HasAppsOnTarget(ctx context.Context, target domain.TargetID) (domain.HasAppsOnTarget, error) {
 	r, err := builder.
 		Query[bool](`
@@ -127,45 +159,82 @@ func (s *appsStore) HasAppsOnTarget(ctx context.Context, target domain.TargetID)
 	return domain.HasAppsOnTarget(r), err
 }
 
-func (s *appsStore) GetByID(ctx context.Context, id domain.AppID) (domain.App, error) {
-	return builder.
-		Query[domain.App](`
+func (s *appsStore) HasAppsInTeam(ctx context.Context, team domain.TeamID) (domain.HasAppsInTeam, error) {
+	r, err := builder.
+		Query[bool]("SELECT EXISTS(SELECT 1 FROM apps WHERE team_id = ?)", team).
+		Extract(s.db, ctx)
+
+	return domain.HasAppsInTeam(r), err
+}
+
+func (s *appsStore) GetDependencyGraph(ctx context.Context) (domain.AppDependencyGraph, error) {
+	nodes, err := builder.
+		Query[appDependencyNode](`
 		SELECT
 			id
-			,name
-			,version_control_url
-			,version_control_token
-			,production_target
-			,production_version
-			,production_vars
-			,staging_target
-			,staging_version
-			,staging_vars
-			,cleanup_requested_at
-			,cleanup_requested_by
-			,created_at
-			,created_by
+			,dependencies
 		FROM apps
+		WHERE cleanup_requested_at IS NULL`).
+		All(s.db, ctx, appDependencyNodeMapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	graph := make(domain.AppDependencyGraph, len(nodes))
+
+	for _, node := range nodes {
+		graph[node.id] = node.dependencies
+	}
+
+	return graph, nil
+}
+
+func (s *appsStore) GetAppsOnTarget(ctx context.Context, target domain.TargetID) ([]domain.App, error) {
+	return builder.
+		Query[domain.App](appsSelect+`
+		WHERE (production_target = ? OR staging_target = ?) AND cleanup_requested_at IS NULL
+		ORDER BY name`, target, target).
+		Decrypt("version_control_token", "production_vars", "staging_vars", "deploy_trigger_key").
+		All(s.db, ctx, domain.AppFrom)
+}
+
+func (s *appsStore) GetByID(ctx context.Context, id domain.AppID) (domain.App, error) {
+	return builder.
+		Query[domain.App](appsSelect+`
 		WHERE id = ?`, id).
+		Decrypt("version_control_token", "production_vars", "staging_vars", "deploy_trigger_key").
 		One(s.db, ctx, domain.AppFrom)
 }
 
 func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
-	return sqlite.WriteAndDispatch(s.db, c, apps, func(ctx context.Context, e event.Event) error {
+	return sqlite.WriteVersionedAndDispatch(s.db, c, "apps", apps, appKey, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.AppCreated:
 			return builder.
 				Insert("apps", builder.Values{
-					"id":                 evt.ID,
-					"name":               evt.Name,
-					"production_target":  evt.Production.Target(),
-					"production_version": evt.Production.Version(),
-					"production_vars":    evt.Production.Vars(),
-					"staging_target":     evt.Staging.Target(),
-					"staging_version":    evt.Staging.Version(),
-					"staging_vars":       evt.Staging.Vars(),
-					"created_at":         evt.Created.At(),
-					"created_by":         evt.Created.By(),
+					"id":                          evt.ID,
+					"name":                        evt.Name,
+					"production_target":           evt.Production.Target(),
+					"production_version":          evt.Production.Version(),
+					"production_vars":             s.db.Encrypted(evt.Production.Vars()),
+					"production_exposure":         evt.Production.Exposure(),
+					"production_protection":       evt.Production.Protection(),
+					"production_proxy_rules":      evt.Production.ProxyRules(),
+					"production_compose_override": evt.Production.ComposeOverride(),
+					"production_url_aliases":      evt.Production.UrlAliases(),
+					"production_scale":            evt.Production.ServicesScale(),
+					"staging_target":              evt.Staging.Target(),
+					"staging_version":             evt.Staging.Version(),
+					"staging_vars":                s.db.Encrypted(evt.Staging.Vars()),
+					"staging_exposure":            evt.Staging.Exposure(),
+					"staging_protection":          evt.Staging.Protection(),
+					"staging_proxy_rules":         evt.Staging.ProxyRules(),
+					"staging_compose_override":    evt.Staging.ComposeOverride(),
+					"staging_url_aliases":         evt.Staging.UrlAliases(),
+					"staging_scale":               evt.Staging.ServicesScale(),
+					"created_at":                  evt.Created.At(),
+					"created_by":                  evt.Created.By(),
 				}).
 				Exec(s.db, ctx)
 		case domain.AppEnvChanged:
@@ -173,9 +242,15 @@ func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
 			// own code.
 			return builder.
 				Update("apps", builder.Values{
-					string(evt.Environment) + "_target":  evt.Config.Target(),
-					string(evt.Environment) + "_version": evt.Config.Version(),
-					string(evt.Environment) + "_vars":    evt.Config.Vars(),
+					string(evt.Environment) + "_target":           evt.Config.Target(),
+					string(evt.Environment) + "_version":          evt.Config.Version(),
+					string(evt.Environment) + "_vars":             s.db.Encrypted(evt.Config.Vars()),
+					string(evt.Environment) + "_exposure":         evt.Config.Exposure(),
+					string(evt.Environment) + "_protection":       evt.Config.Protection(),
+					string(evt.Environment) + "_proxy_rules":      evt.Config.ProxyRules(),
+					string(evt.Environment) + "_compose_override": evt.Config.ComposeOverride(),
+					string(evt.Environment) + "_url_aliases":      evt.Config.UrlAliases(),
+					string(evt.Environment) + "_scale":            evt.Config.ServicesScale(),
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
@@ -183,7 +258,7 @@ func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
 			return builder.
 				Update("apps", builder.Values{
 					"version_control_url":   evt.Config.Url(),
-					"version_control_token": evt.Config.Token(),
+					"version_control_token": s.db.Encrypted(evt.Config.Token()),
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
@@ -195,6 +270,90 @@ func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
+		case domain.AppTeamChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"team_id": evt.Team,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppDependenciesChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"dependencies": evt.Dependencies,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppLabelsChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"labels": evt.Labels,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppPlatformsChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"platforms": evt.Platforms,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppBuildRegistryChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"build_registry_id": evt.Registry,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppStaticSiteChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"static_site": evt.StaticSite,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppBadgeKeyChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"badge_key": evt.Key,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppDeployTriggerChanged:
+			var (
+				kind monad.Maybe[domain.DeployTriggerKind]
+				key  monad.Maybe[string]
+				by   monad.Maybe[string]
+			)
+
+			if trigger, isSet := evt.Trigger.TryGet(); isSet {
+				kind.Set(trigger.Kind())
+				key.Set(trigger.Key())
+				by.Set(string(trigger.By()))
+			}
+
+			return builder.
+				Update("apps", builder.Values{
+					"deploy_trigger_kind": kind,
+					"deploy_trigger_key":  s.db.Encrypted(key),
+					"deploy_trigger_by":   by,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppFreezeWindowsChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"freeze_windows": evt.Windows,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppTimeoutsChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"timeouts": evt.Timeouts,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
 		case domain.AppCleanupRequested:
 			return builder.
 				Update("apps", builder.Values{
@@ -213,6 +372,53 @@ func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
 	})
 }
 
+func appKey(a *domain.App) (string, []any) {
+	return "id = ?", []any{a.ID()}
+}
+
+const appsSelect = `
+		SELECT
+			id
+			,name
+			,version_control_url
+			,version_control_token
+			,production_target
+			,production_version
+			,production_vars
+			,production_exposure
+			,production_protection
+			,production_proxy_rules
+			,production_compose_override
+			,production_url_aliases
+			,production_scale
+			,staging_target
+			,staging_version
+			,staging_vars
+			,staging_exposure
+			,staging_protection
+			,staging_proxy_rules
+			,staging_compose_override
+			,staging_url_aliases
+			,staging_scale
+			,team_id
+			,dependencies
+			,labels
+			,platforms
+			,build_registry_id
+			,static_site
+			,badge_key
+			,deploy_trigger_kind
+			,deploy_trigger_key
+			,deploy_trigger_by
+			,freeze_windows
+			,timeouts
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+			,version
+		FROM apps`
+
 type appNamingResult struct {
 	productionAvailable   bool
 	productionTargetFound bool
@@ -230,3 +436,17 @@ func appNameUniquenessResultMapper(s storage.Scanner) (r appNamingResult, err er
 
 	return r, err
 }
+
+type appDependencyNode struct {
+	id           domain.AppID
+	dependencies domain.AppDependencies
+}
+
+func appDependencyNodeMapper(s storage.Scanner) (n appDependencyNode, err error) {
+	err = s.Scan(
+		&n.id,
+		&n.dependencies,
+	)
+
+	return n, err
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type artifactsUsageStore struct {
+	db *sqlite.Database
+}
+
+func NewArtifactsUsageStore(db *sqlite.Database) domain.ArtifactsUsageWriter {
+	return &artifactsUsageStore{db}
+}
+
+// Replace the previously computed usage by the given one.
+func (s *artifactsUsageStore) WriteArtifactsUsage(ctx context.Context, usage domain.ArtifactsUsage) (finalErr error) {
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	if finalErr = builder.Command("DELETE FROM artifacts_usage").Exec(s.db, ctx); finalErr != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	for id, size := range usage {
+		if finalErr = builder.Insert("artifacts_usage", builder.Values{
+			"app_id":      id,
+			"size":        size,
+			"computed_at": now,
+		}).Exec(s.db, ctx); finalErr != nil {
+			return
+		}
+	}
+
+	return
+}
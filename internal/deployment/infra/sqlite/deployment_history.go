@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type deploymentHistoryStore struct {
+	db *sqlite.Database
+}
+
+func NewDeploymentHistoryStore(db *sqlite.Database) domain.DeploymentHistoryWriter {
+	return &deploymentHistoryStore{db}
+}
+
+func (s *deploymentHistoryStore) Write(ctx context.Context, entries ...*domain.DeploymentHistoryEntry) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, entries, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.DeploymentHistoryRecorded:
+			return builder.
+				Insert("deployments_history", builder.Values{
+					"app_id":            evt.DeploymentID.AppID(),
+					"deployment_number": evt.DeploymentID.DeploymentNumber(),
+					"environment":       evt.Config.Environment(),
+					"target_id":         evt.Config.Target(),
+					"succeeded":         evt.Succeeded,
+					"failure_reason":    evt.FailureReason,
+					"requested_at":      evt.RequestedAt,
+					"started_at":        evt.StartedAt,
+					"finished_at":       evt.FinishedAt,
+					"duration":          evt.FinishedAt.Sub(evt.StartedAt).Milliseconds(),
+					"build_duration":    monad.Map(evt.BuildDuration, time.Duration.Milliseconds),
+					"deploy_duration":   monad.Map(evt.DeployDuration, time.Duration.Milliseconds),
+				}).
+				// A retried deployment replaces the outcome of its previous run
+				F(`ON CONFLICT(app_id, deployment_number) DO UPDATE SET
+					succeeded = excluded.succeeded
+					,failure_reason = excluded.failure_reason
+					,started_at = excluded.started_at
+					,finished_at = excluded.finished_at
+					,duration = excluded.duration
+					,build_duration = excluded.build_duration
+					,deploy_duration = excluded.deploy_duration`).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type deploymentTransitionsStore struct {
+	db *sqlite.Database
+}
+
+func NewDeploymentTransitionsStore(db *sqlite.Database) domain.DeploymentTransitionsWriter {
+	return &deploymentTransitionsStore{db}
+}
+
+func (s *deploymentTransitionsStore) Write(ctx context.Context, transitions ...*domain.DeploymentTransition) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, transitions, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.DeploymentTransitionRecorded:
+			return builder.
+				Insert("deployment_transitions", builder.Values{
+					"app_id":            evt.DeploymentID.AppID(),
+					"deployment_number": evt.DeploymentID.DeploymentNumber(),
+					"state":             evt.State,
+					"occurred_at":       evt.OccurredAt,
+				}).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -38,8 +38,18 @@ func (s *deploymentsStore) GetByID(ctx context.Context, id domain.DeploymentID)
 			,config_environment
 			,config_target
 			,config_vars
+			,config_exposure
+			,config_protection
+			,config_proxy_rules
+			,config_compose_override
+			,config_url_aliases
+			,config_scale
+			,config_platforms
+			,config_build_registry
+			,config_static_site
 			,state_status
 			,state_errcode
+			,state_failure_reason
 			,state_services
 			,state_started_at
 			,state_finished_at
@@ -47,8 +57,15 @@ func (s *deploymentsStore) GetByID(ctx context.Context, id domain.DeploymentID)
 			,source
 			,requested_at
 			,requested_by
+			,labels
+			,note
+			,metadata
+			,unfrozen_at
+			,unfrozen_by
+			,version
 		FROM deployments
 		WHERE app_id = ? AND deployment_number = ?`, id.AppID(), id.DeploymentNumber()).
+		Decrypt("config_vars").
 		One(s.db, ctx, domain.DeploymentFrom)
 }
 
@@ -63,8 +80,18 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 			,config_environment
 			,config_target
 			,config_vars
+			,config_exposure
+			,config_protection
+			,config_proxy_rules
+			,config_compose_override
+			,config_url_aliases
+			,config_scale
+			,config_platforms
+			,config_build_registry
+			,config_static_site
 			,state_status
 			,state_errcode
+			,state_failure_reason
 			,state_services
 			,state_started_at
 			,state_finished_at
@@ -72,10 +99,61 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 			,source
 			,requested_at
 			,requested_by
+			,labels
+			,note
+			,metadata
+			,unfrozen_at
+			,unfrozen_by
+			,version
 		FROM deployments
 		WHERE app_id = ? AND config_environment = ?
 		ORDER BY deployment_number DESC
 		LIMIT 1`, id, env).
+		Decrypt("config_vars").
+		One(s.db, ctx, domain.DeploymentFrom)
+}
+
+func (s *deploymentsStore) GetDeploymentRunningAt(ctx context.Context, id domain.AppID, env domain.Environment, date time.Time) (domain.Deployment, error) {
+	return builder.
+		Query[domain.Deployment](`
+		SELECT
+			app_id
+			,deployment_number
+			,config_appid
+			,config_appname
+			,config_environment
+			,config_target
+			,config_vars
+			,config_exposure
+			,config_protection
+			,config_proxy_rules
+			,config_compose_override
+			,config_url_aliases
+			,config_scale
+			,config_platforms
+			,config_build_registry
+			,config_static_site
+			,state_status
+			,state_errcode
+			,state_failure_reason
+			,state_services
+			,state_started_at
+			,state_finished_at
+			,source_discriminator
+			,source
+			,requested_at
+			,requested_by
+			,labels
+			,note
+			,metadata
+			,unfrozen_at
+			,unfrozen_by
+			,version
+		FROM deployments
+		WHERE app_id = ? AND config_environment = ? AND state_status = ? AND state_started_at <= ?
+		ORDER BY deployment_number DESC
+		LIMIT 1`, id, env, domain.DeploymentStatusSucceeded, date).
+		Decrypt("config_vars").
 		One(s.db, ctx, domain.DeploymentFrom)
 }
 
@@ -131,16 +209,108 @@ func (s *deploymentsStore) HasDeploymentsOnAppTargetEnv(ctx context.Context, app
 		domain.HasSuccessfulDeploymentsOnAppTargetEnv(c.successful), err
 }
 
+func (s *deploymentsStore) GetPrunableDeployments(ctx context.Context) ([]domain.Deployment, error) {
+	return builder.
+		Query[domain.Deployment](`
+		SELECT
+			app_id
+			,deployment_number
+			,config_appid
+			,config_appname
+			,config_environment
+			,config_target
+			,config_vars
+			,config_exposure
+			,config_protection
+			,config_proxy_rules
+			,config_compose_override
+			,config_url_aliases
+			,config_scale
+			,config_platforms
+			,config_build_registry
+			,config_static_site
+			,state_status
+			,state_errcode
+			,state_failure_reason
+			,state_services
+			,state_started_at
+			,state_finished_at
+			,source_discriminator
+			,source
+			,requested_at
+			,requested_by
+			,labels
+			,note
+			,metadata
+			,unfrozen_at
+			,unfrozen_by
+			,version
+		FROM deployments
+		WHERE
+			state_status IN (?, ?)
+			AND deployment_number < (
+				SELECT MAX(last.deployment_number) FROM deployments last
+				WHERE last.app_id = deployments.app_id AND last.config_environment = deployments.config_environment
+			)
+		ORDER BY requested_at`, domain.DeploymentStatusSucceeded, domain.DeploymentStatusFailed).
+		Decrypt("config_vars").
+		All(s.db, ctx, domain.DeploymentFrom)
+}
+
+func (s *deploymentsStore) GetByApp(ctx context.Context, id domain.AppID) ([]domain.Deployment, error) {
+	return builder.
+		Query[domain.Deployment](`
+		SELECT
+			app_id
+			,deployment_number
+			,config_appid
+			,config_appname
+			,config_environment
+			,config_target
+			,config_vars
+			,config_exposure
+			,config_protection
+			,config_proxy_rules
+			,config_compose_override
+			,config_url_aliases
+			,config_scale
+			,config_platforms
+			,config_build_registry
+			,config_static_site
+			,state_status
+			,state_errcode
+			,state_failure_reason
+			,state_services
+			,state_started_at
+			,state_finished_at
+			,source_discriminator
+			,source
+			,requested_at
+			,requested_by
+			,labels
+			,note
+			,metadata
+			,unfrozen_at
+			,unfrozen_by
+			,version
+		FROM deployments
+		WHERE app_id = ?
+		ORDER BY deployment_number`, id).
+		Decrypt("config_vars").
+		All(s.db, ctx, domain.DeploymentFrom)
+}
+
 func (s *deploymentsStore) FailDeployments(ctx context.Context, reason error, criterias domain.FailCriterias) error {
 	now := time.Now().UTC()
 
 	return builder.Update("deployments", builder.Values{
-		"state_status":      domain.DeploymentStatusFailed,
-		"state_errcode":     reason.Error(),
-		"state_started_at":  now,
-		"state_finished_at": now,
+		"state_status":         domain.DeploymentStatusFailed,
+		"state_errcode":        reason.Error(),
+		"state_failure_reason": domain.DeploymentFailureReasonOf(reason),
+		"state_started_at":     now,
+		"state_finished_at":    now,
 	}).
-		F("WHERE TRUE").
+		F(",version = version + 1 WHERE TRUE").
 		S(
 			builder.MaybeValue(criterias.App, "AND app_id = ?"),
 			builder.MaybeValue(criterias.Target, "AND config_target = ?"),
@@ -151,37 +321,71 @@ func (s *deploymentsStore) FailDeployments(ctx context.Context, reason error, cr
 }
 
 func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deployment) error {
-	return sqlite.WriteAndDispatch(s.db, c, deployments, func(ctx context.Context, e event.Event) error {
+	return sqlite.WriteVersionedAndDispatch(s.db, c, "deployments", deployments, deploymentKey, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.DeploymentCreated:
 			return builder.
 				Insert("deployments", builder.Values{
-					"app_id":               evt.ID.AppID(),
-					"deployment_number":    evt.ID.DeploymentNumber(),
-					"config_appid":         evt.Config.AppID(),
-					"config_appname":       evt.Config.AppName(),
-					"config_environment":   evt.Config.Environment(),
-					"config_target":        evt.Config.Target(),
-					"config_vars":          evt.Config.Vars(),
+					"app_id":                  evt.ID.AppID(),
+					"deployment_number":       evt.ID.DeploymentNumber(),
+					"config_appid":            evt.Config.AppID(),
+					"config_appname":          evt.Config.AppName(),
+					"config_environment":      evt.Config.Environment(),
+					"config_target":           evt.Config.Target(),
+					"config_vars":             s.db.Encrypted(evt.Config.Vars()),
+					"config_exposure":         evt.Config.Exposure(),
+					"config_protection":       evt.Config.Protection(),
+					"config_proxy_rules":      evt.Config.ProxyRules(),
+					"config_compose_override": evt.Config.ComposeOverride(),
+					"config_url_aliases":      evt.Config.UrlAliases(),
+					"config_scale":            evt.Config.ServicesScale(),
+					"config_platforms":        evt.Config.Platforms(),
+					"config_build_registry":   evt.Config.BuildRegistry(),
+					"config_static_site":      evt.Config.StaticSite(),
+					"state_status":            evt.State.Status(),
+					"state_errcode":           evt.State.ErrCode(),
+					"state_failure_reason":    evt.State.FailureReason(),
+					"state_services":          evt.State.Services(),
+					"state_started_at":        evt.State.StartedAt(),
+					"state_finished_at":       evt.State.FinishedAt(),
+					"source_discriminator":    evt.Source.Kind(),
+					"source":                  evt.Source,
+					"requested_at":            evt.Requested.At(),
+					"requested_by":            evt.Requested.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.DeploymentStateChanged:
+			return builder.
+				Update("deployments", builder.Values{
 					"state_status":         evt.State.Status(),
 					"state_errcode":        evt.State.ErrCode(),
+					"state_failure_reason": evt.State.FailureReason(),
 					"state_services":       evt.State.Services(),
 					"state_started_at":     evt.State.StartedAt(),
 					"state_finished_at":    evt.State.FinishedAt(),
-					"source_discriminator": evt.Source.Kind(),
-					"source":               evt.Source,
-					"requested_at":         evt.Requested.At(),
-					"requested_by":         evt.Requested.By(),
 				}).
+				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
 				Exec(s.db, ctx)
-		case domain.DeploymentStateChanged:
+		case domain.DeploymentLabelsChanged:
 			return builder.
 				Update("deployments", builder.Values{
-					"state_status":      evt.State.Status(),
-					"state_errcode":     evt.State.ErrCode(),
-					"state_services":    evt.State.Services(),
-					"state_started_at":  evt.State.StartedAt(),
-					"state_finished_at": evt.State.FinishedAt(),
+					"labels": evt.Labels,
+				}).
+				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
+				Exec(s.db, ctx)
+		case domain.DeploymentAnnotated:
+			return builder.
+				Update("deployments", builder.Values{
+					"note":     evt.Note,
+					"metadata": evt.Metadata,
+				}).
+				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
+				Exec(s.db, ctx)
+		case domain.DeploymentUnfrozen:
+			return builder.
+				Update("deployments", builder.Values{
+					"unfrozen_at": evt.Unfrozen.At(),
+					"unfrozen_by": evt.Unfrozen.By(),
 				}).
 				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
 				Exec(s.db, ctx)
@@ -191,6 +395,10 @@ func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deplo
 	})
 }
 
+func deploymentKey(d *domain.Deployment) (string, []any) {
+	return "app_id = ? AND deployment_number = ?", []any{d.ID().AppID(), d.ID().DeploymentNumber()}
+}
+
 type deploymentsOnAppTargetEnv struct {
 	runningOrPending bool
 	successful       bool
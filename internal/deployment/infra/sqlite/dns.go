@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	DnsZonesStore interface {
+		domain.DnsZonesReader
+		domain.DnsZonesWriter
+	}
+
+	DnsRecordsStore interface {
+		domain.DnsRecordsReader
+		domain.DnsRecordsWriter
+	}
+
+	dnsZonesStore struct {
+		db *sqlite.Database
+	}
+
+	dnsRecordsStore struct {
+		db *sqlite.Database
+	}
+
+	exposedDeployment struct {
+		appID       domain.AppID
+		environment domain.Environment
+		services    domain.Services
+		targetUrl   domain.Url
+	}
+)
+
+func NewDnsZonesStore(db *sqlite.Database) DnsZonesStore {
+	return &dnsZonesStore{db}
+}
+
+func (s *dnsZonesStore) CheckNameAvailability(ctx context.Context, name string) (domain.DnsZoneNameRequirement, error) {
+	unique, err := builder.
+		Query[bool]("SELECT NOT EXISTS(SELECT 1 FROM dns_zones WHERE name = ?)", name).
+		Extract(s.db, ctx)
+
+	return domain.NewDnsZoneNameRequirement(name, unique), err
+}
+
+func (s *dnsZonesStore) GetByID(ctx context.Context, id domain.DnsZoneID) (domain.DnsZone, error) {
+	return builder.
+		Query[domain.DnsZone](`
+		SELECT
+			id
+			,name
+			,provider
+			,credentials
+			,addresses
+			,created_at
+			,created_by
+		FROM dns_zones
+		WHERE id = ?`, id).
+		Decrypt("credentials").
+		One(s.db, ctx, domain.DnsZoneFrom)
+}
+
+func (s *dnsZonesStore) GetAll(ctx context.Context) ([]domain.DnsZone, error) {
+	return builder.
+		Query[domain.DnsZone](`
+		SELECT
+			id
+			,name
+			,provider
+			,credentials
+			,addresses
+			,created_at
+			,created_by
+		FROM dns_zones
+		ORDER BY name`).
+		Decrypt("credentials").
+		All(s.db, ctx, domain.DnsZoneFrom)
+}
+
+func (s *dnsZonesStore) Write(ctx context.Context, zones ...*domain.DnsZone) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, zones, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.DnsZoneCreated:
+			return builder.
+				Insert("dns_zones", builder.Values{
+					"id":          evt.ID,
+					"name":        evt.Name,
+					"provider":    evt.Provider,
+					"credentials": s.db.Encrypted(evt.Credentials),
+					"addresses":   evt.Addresses,
+					"created_at":  evt.Created.At(),
+					"created_by":  evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.DnsZoneCredentialsChanged:
+			return builder.
+				Update("dns_zones", builder.Values{
+					"credentials": s.db.Encrypted(evt.Credentials),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.DnsZoneAddressesChanged:
+			return builder.
+				Update("dns_zones", builder.Values{
+					"addresses": evt.Addresses,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.DnsZoneDeleted:
+			return builder.
+				Command("DELETE FROM dns_zones WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
+
+func NewDnsRecordsStore(db *sqlite.Database) DnsRecordsStore {
+	return &dnsRecordsStore{db}
+}
+
+func (s *dnsRecordsStore) GetExposedHosts(ctx context.Context) ([]domain.ExposedHost, error) {
+	// Hosts are exposed by the latest successful deployment of each app environment
+	// as long as the app has not been requested for cleanup.
+	deployments, err := builder.
+		Query[exposedDeployment](`
+		SELECT
+			deployments.app_id
+			,deployments.config_environment
+			,deployments.state_services
+			,targets.url
+		FROM deployments
+		INNER JOIN apps ON apps.id = deployments.app_id
+		INNER JOIN targets ON targets.id = deployments.config_target
+		WHERE
+			apps.cleanup_requested_at IS NULL
+			AND deployments.deployment_number = (
+				SELECT MAX(last.deployment_number) FROM deployments last
+				WHERE
+					last.app_id = deployments.app_id
+					AND last.config_environment = deployments.config_environment
+					AND last.state_status = ?
+			)
+		ORDER BY deployments.app_id, deployments.config_environment`, domain.DeploymentStatusSucceeded).
+		All(s.db, ctx, exposedDeploymentMapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []domain.ExposedHost
+
+	for _, depl := range deployments {
+		for _, host := range depl.services.Hosts(depl.targetUrl) {
+			hosts = append(hosts, domain.ExposedHost{
+				AppID:       depl.appID,
+				Environment: depl.environment,
+				Host:        host,
+				TargetHost:  depl.targetUrl.Hostname(),
+			})
+		}
+	}
+
+	return hosts, nil
+}
+
+func (s *dnsRecordsStore) GetDnsRecords(ctx context.Context) ([]domain.DnsRecord, error) {
+	return builder.
+		Query[domain.DnsRecord](`
+		SELECT
+			host
+			,zone_id
+			,app_id
+			,environment
+			,records
+			,status
+			,error
+			,drifted_at
+			,checked_at
+		FROM dns_records
+		ORDER BY host`).
+		All(s.db, ctx, dnsRecordMapper)
+}
+
+func (s *dnsRecordsStore) WriteDnsRecord(ctx context.Context, record domain.DnsRecord) error {
+	return builder.
+		Insert("dns_records", builder.Values{
+			"host":        record.Host,
+			"zone_id":     record.ZoneID,
+			"app_id":      record.AppID,
+			"environment": record.Environment,
+			"records":     record.Records,
+			"status":      record.Status,
+			"error":       record.Error,
+			"drifted_at":  record.DriftedAt,
+			"checked_at":  record.CheckedAt,
+		}).
+		F(`ON CONFLICT(host) DO UPDATE SET
+			zone_id = excluded.zone_id
+			,app_id = excluded.app_id
+			,environment = excluded.environment
+			,records = excluded.records
+			,status = excluded.status
+			,error = excluded.error
+			,drifted_at = excluded.drifted_at
+			,checked_at = excluded.checked_at`).
+		Exec(s.db, ctx)
+}
+
+func (s *dnsRecordsStore) DeleteDnsRecord(ctx context.Context, host string) error {
+	return builder.
+		Command("DELETE FROM dns_records WHERE host = ?", host).
+		Exec(s.db, ctx)
+}
+
+func exposedDeploymentMapper(scanner storage.Scanner) (d exposedDeployment, err error) {
+	err = scanner.Scan(&d.appID, &d.environment, &d.services, &d.targetUrl)
+
+	return d, err
+}
+
+func dnsRecordMapper(scanner storage.Scanner) (r domain.DnsRecord, err error) {
+	err = scanner.Scan(
+		&r.Host,
+		&r.ZoneID,
+		&r.AppID,
+		&r.Environment,
+		&r.Records,
+		&r.Status,
+		&r.Error,
+		&r.DriftedAt,
+		&r.CheckedAt,
+	)
+
+	return r, err
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	EnvRevisionsStore interface {
+		domain.EnvRevisionsReader
+		domain.EnvRevisionsWriter
+	}
+
+	envRevisionsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewEnvRevisionsStore(db *sqlite.Database) EnvRevisionsStore {
+	return &envRevisionsStore{db}
+}
+
+func (s *envRevisionsStore) GetByID(ctx context.Context, id domain.EnvRevisionID) (domain.EnvRevision, error) {
+	return builder.
+		Query[domain.EnvRevision](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,vars
+			,changes
+			,created_by
+			,created_at
+		FROM env_revisions
+		WHERE id = ?`, id).
+		Decrypt("vars").
+		One(s.db, ctx, domain.EnvRevisionFrom)
+}
+
+func (s *envRevisionsStore) Write(ctx context.Context, revisions ...*domain.EnvRevision) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, revisions, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.EnvRevisionRecorded:
+			return builder.
+				Insert("env_revisions", builder.Values{
+					"id":          evt.ID,
+					"app_id":      evt.AppID,
+					"environment": evt.Environment,
+					"vars":        s.db.Encrypted(evt.Vars),
+					"changes":     evt.Changes,
+					"created_by":  evt.CreatedBy,
+					"created_at":  evt.CreatedAt,
+				}).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -2,16 +2,42 @@ package sqlite
 
 import (
 	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_addon_backups"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_addons"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_archives"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_env_revisions"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_incidents"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_monitors"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_resource_usage"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_traffic"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_artifacts_usage"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_stats"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployments_feed"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zone"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_dns_zones"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_gitops_runs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peer"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_peers"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_registries"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_saved_filters"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets_capacity"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_team"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_teams"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_trashed_apps"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
@@ -20,11 +46,12 @@ import (
 )
 
 type gateway struct {
-	db *sqlite.Database
+	db                *sqlite.Database
+	deploymentRunners int // Number of deployments processed concurrently, used to estimate queued ones start
 }
 
-func NewGateway(db *sqlite.Database) *gateway {
-	return &gateway{db}
+func NewGateway(db *sqlite.Database, deploymentRunners int) *gateway {
+	return &gateway{db, deploymentRunners}
 }
 
 func (s *gateway) GetAllApps(ctx context.Context, cmd get_apps.Query) ([]get_apps.App, error) {
@@ -45,16 +72,26 @@ func (s *gateway) GetAllApps(ctx context.Context, cmd get_apps.Query) ([]get_app
 				,staging_target.id
 				,staging_target.name
 				,staging_target.url
+				,teams.id
+				,teams.name
+				,apps.labels
 			FROM apps
 			INNER JOIN users ON users.id = apps.created_by
 			INNER JOIN targets AS production_target ON production_target.id = apps.production_target
 			INNER JOIN targets AS staging_target ON staging_target.id = apps.staging_target
-			LEFT JOIN users cusers ON cusers.id = apps.cleanup_requested_by`).
+			LEFT JOIN users cusers ON cusers.id = apps.cleanup_requested_by
+			LEFT JOIN teams ON teams.id = apps.team_id
+			WHERE TRUE`).
+		S(
+			builder.MaybeValue(cmd.TeamID, "AND apps.team_id = ?"),
+			hasLabels("apps.labels", cmd.Labels),
+			readableApps(ctx, "AND", ""),
+		).
 		All(s.db, ctx, appDataMapper, getDeploymentDataloader)
 }
 
 func (s *gateway) GetAppByID(ctx context.Context, cmd get_app_detail.Query) (get_app_detail.App, error) {
-	return builder.
+	a, err := builder.
 		Query[get_app_detail.App](`
 			SELECT
 				apps.id
@@ -65,23 +102,60 @@ func (s *gateway) GetAppByID(ctx context.Context, cmd get_app_detail.Query) (get
 				,production_target.name
 				,production_target.url
 				,apps.production_vars
+				,apps.production_exposure
+				,apps.production_protection
+				,apps.production_proxy_rules
+				,apps.production_compose_override
+				,apps.production_url_aliases
+				,apps.production_scale
 				,staging_target.id
 				,staging_target.name
 				,staging_target.url
 				,apps.staging_vars
+				,apps.staging_exposure
+				,apps.staging_protection
+				,apps.staging_proxy_rules
+				,apps.staging_compose_override
+				,apps.staging_url_aliases
+				,apps.staging_scale
 				,apps.cleanup_requested_at
 				,cusers.id
 				,cusers.email
 				,apps.created_at
 				,users.id
 				,users.email
+				,teams.id
+				,teams.name
+				,apps.dependencies
+				,apps.labels
+				,apps.platforms
+				,registries.id
+				,registries.name
+				,registries.url
+				,apps.static_site
+				,apps.badge_key IS NOT NULL
+				,apps.deploy_trigger_kind
+				,apps.freeze_windows
+				,apps.timeouts
 			FROM apps
 			INNER JOIN users ON users.id = apps.created_by
 			INNER JOIN targets production_target ON production_target.id = apps.production_target
 			INNER JOIN targets staging_target ON staging_target.id = apps.staging_target
 			LEFT JOIN users cusers ON cusers.id = apps.cleanup_requested_by
+			LEFT JOIN teams ON teams.id = apps.team_id
+			LEFT JOIN registries ON registries.id = apps.build_registry_id
 			WHERE apps.id = ?`, cmd.ID).
+		S(readableApps(ctx, "AND", "")).
+		Decrypt("version_control_token", "production_vars", "staging_vars").
 		One(s.db, ctx, appDetailDataMapper, getDeploymentDetailDataloader)
+
+	if err != nil {
+		return a, err
+	}
+
+	a.MaskSecrets(ctx)
+
+	return a, nil
 }
 
 func (s *gateway) GetAllDeploymentsByApp(ctx context.Context, cmd get_app_deployments.Query) (storage.Paginated[get_app_deployments.Deployment], error) {
@@ -98,24 +172,60 @@ func (s *gateway) GetAllDeploymentsByApp(ctx context.Context, cmd get_app_deploy
 			,deployments.source
 			,deployments.state_status
 			,deployments.state_errcode
+			,deployments.state_failure_reason
 			,deployments.state_started_at
 			,deployments.state_finished_at
 			,deployments.requested_at
 			,users.id
 			,users.email
+			,deployments.labels
+			,deployments.note
+			,deployments.metadata
 			,'' -- only to use the same mapper as the latest deployments`).
 		F(`
 			FROM deployments
 			INNER JOIN users ON users.id = deployments.requested_by
 			LEFT JOIN targets ON targets.id = deployments.config_target
 			WHERE deployments.app_id = ?`, cmd.AppID).
-		S(builder.MaybeValue(cmd.Environment, "AND deployments.config_environment = ?")).
+		S(
+			builder.MaybeValue(cmd.Environment, "AND deployments.config_environment = ?"),
+			hasLabels("deployments.labels", cmd.Labels),
+			searchDeployments(cmd.Search),
+			readableApps(ctx, "AND deployments.app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
 		F("ORDER BY deployments.deployment_number DESC").
 		Paginate(s.db, ctx, deploymentMapper(nil), cmd.Page.Get(1), 5)
 }
 
-func (s *gateway) GetDeploymentByID(ctx context.Context, cmd get_deployment.Query) (get_deployment.Deployment, error) {
+func (s *gateway) GetDeploymentsFeed(ctx context.Context, cmd get_deployments_feed.Query) ([]get_deployments_feed.Deployment, error) {
 	return builder.
+		Query[get_deployments_feed.Deployment](`
+		SELECT
+			deployments.app_id
+			,deployments.config_appname
+			,deployments.deployment_number
+			,deployments.config_environment
+			,deployments.state_status
+			,deployments.state_errcode
+			,deployments.note
+			,deployments.requested_at
+			,users.id
+			,users.email
+			,deployments.state_started_at
+			,deployments.state_finished_at
+		FROM deployments
+		INNER JOIN users ON users.id = deployments.requested_by
+		WHERE TRUE`).
+		S(
+			builder.MaybeValue(cmd.AppID, "AND deployments.app_id = ?"),
+			readableApps(ctx, "AND deployments.app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY deployments.requested_at DESC LIMIT ?", get_deployments_feed.Limit).
+		All(s.db, ctx, deploymentFeedMapper)
+}
+
+func (s *gateway) GetDeploymentByID(ctx context.Context, cmd get_deployment.Query) (get_deployment.Deployment, error) {
+	d, err := builder.
 		Query[get_deployment.Deployment](`
 		SELECT
 			deployments.app_id
@@ -130,18 +240,68 @@ func (s *gateway) GetDeploymentByID(ctx context.Context, cmd get_deployment.Quer
 			,deployments.source
 			,deployments.state_status
 			,deployments.state_errcode
+			,deployments.state_failure_reason
 			,deployments.state_services
 			,deployments.state_started_at
 			,deployments.state_finished_at
 			,deployments.requested_at
 			,users.id
 			,users.email
+			,deployments.labels
+			,deployments.note
+			,deployments.metadata
 			,'' -- only to use the same mapper as the latest deployments
 		FROM deployments
 		INNER JOIN users ON users.id = deployments.requested_by
 		LEFT JOIN targets ON targets.id = deployments.config_target
 		WHERE deployments.app_id = ? AND deployments.deployment_number = ?`, cmd.AppID, cmd.DeploymentNumber).
-		One(s.db, ctx, deploymentDetailMapper(nil))
+		S(readableApps(ctx, "AND deployments.app_id IN (SELECT apps.id FROM apps WHERE", ")")).
+		One(s.db, ctx, deploymentDetailMapper(nil), getDeploymentTimelineDataloader)
+
+	if err != nil || d.State.Status != uint8(domain.DeploymentStatusPending) {
+		return d, err
+	}
+
+	// Running deployments first, then pending ones up to this one in the order they were requested.
+	// Durations are the average of previous successful deployments of the same app environment,
+	// or of every app if it has never been deployed yet.
+	queued, err := builder.
+		Query[get_deployment.QueuedDeployment](`
+		SELECT
+			deployments.app_id || '/' || deployments.config_environment
+			,deployments.state_started_at
+			,COALESCE(
+				(SELECT CAST(AVG(h.duration) AS INTEGER) FROM deployments_history h
+					WHERE h.app_id = deployments.app_id AND h.environment = deployments.config_environment AND h.succeeded)
+				,(SELECT CAST(AVG(h.duration) AS INTEGER) FROM deployments_history h WHERE h.succeeded)
+			)
+		FROM deployments
+		WHERE deployments.state_status = ?
+			OR (deployments.state_status = ? AND (deployments.requested_at, deployments.app_id, deployments.deployment_number) <= (?, ?, ?))
+		ORDER BY deployments.state_started_at IS NULL, deployments.requested_at, deployments.app_id, deployments.deployment_number`,
+		domain.DeploymentStatusRunning, domain.DeploymentStatusPending, d.RequestedAt, d.AppID, d.DeploymentNumber).
+		All(s.db, ctx, func(scanner storage.Scanner) (q get_deployment.QueuedDeployment, err error) {
+			err = scanner.Scan(
+				&q.Group,
+				&q.StartedAt,
+				&q.Duration,
+			)
+
+			return q, err
+		})
+
+	if err != nil || len(queued) == 0 {
+		return d, err
+	}
+
+	d.Queue.Set(get_deployment.EstimateQueue(
+		time.Now().UTC(),
+		s.deploymentRunners,
+		queued[:len(queued)-1],
+		queued[len(queued)-1],
+	))
+
+	return d, nil
 }
 
 func (s *gateway) GetAllTargets(ctx context.Context, cmd get_targets.Query) ([]get_target.Target, error) {
@@ -156,6 +316,11 @@ func (s *gateway) GetAllTargets(ctx context.Context, cmd get_targets.Query) ([]g
 			,targets.state_status
 			,targets.state_errcode
 			,targets.state_last_ready_version
+			,targets.vars
+			,targets.platform
+			,targets.proxy_version
+			,targets.last_garbage_collection
+			,targets.labels
 			,targets.cleanup_requested_at
 			,cusers.id
 			,cusers.email
@@ -167,7 +332,11 @@ func (s *gateway) GetAllTargets(ctx context.Context, cmd get_targets.Query) ([]g
 		LEFT JOIN users cusers ON cusers.id = targets.cleanup_requested_by
 		WHERE TRUE
 		`).
-		S(builder.If(cmd.ActiveOnly, "AND targets.cleanup_requested_at IS NULL")).
+		S(
+			builder.If(cmd.ActiveOnly, "AND targets.cleanup_requested_at IS NULL"),
+			readableTargets(ctx),
+		).
+		Decrypt("provider", "vars").
 		All(s.db, ctx, targetMapper)
 }
 
@@ -183,6 +352,11 @@ func (s *gateway) GetTargetByID(ctx context.Context, cmd get_target.Query) (get_
 			,targets.state_status
 			,targets.state_errcode
 			,targets.state_last_ready_version
+			,targets.vars
+			,targets.platform
+			,targets.proxy_version
+			,targets.last_garbage_collection
+			,targets.labels
 			,targets.cleanup_requested_at
 			,cusers.id
 			,cusers.email
@@ -193,9 +367,32 @@ func (s *gateway) GetTargetByID(ctx context.Context, cmd get_target.Query) (get_
 		INNER JOIN users ON users.id = targets.created_by
 		LEFT JOIN users cusers ON cusers.id = targets.cleanup_requested_by
 		WHERE targets.id = ?`, cmd.ID).
+		S(readableTargets(ctx)).
+		Decrypt("provider", "vars").
 		One(s.db, ctx, targetMapper)
 }
 
+func (s *gateway) GetTargetsCapacity(ctx context.Context, cmd get_targets_capacity.Query) ([]get_targets_capacity.Target, error) {
+	return builder.
+		Query[get_targets_capacity.Target](`
+		SELECT
+			targets.id
+			,targets.name
+			,targets.url
+			,(SELECT COUNT(*) FROM apps WHERE apps.production_target = targets.id OR apps.staging_target = targets.id)
+			,targets.entrypoints
+			,target_capacity.running_containers
+			,target_capacity.images_size
+			,target_capacity.disk_free
+			,target_capacity.disk_total
+			,target_capacity.collected_at
+		FROM targets
+		LEFT JOIN target_capacity ON target_capacity.target_id = targets.id
+		WHERE targets.cleanup_requested_at IS NULL
+		ORDER BY target_capacity.disk_free IS NULL, target_capacity.disk_free DESC, 4, targets.name`).
+		All(s.db, ctx, targetCapacityMapper)
+}
+
 func (s *gateway) GetRegistries(ctx context.Context, cmd get_registries.Query) ([]get_registry.Registry, error) {
 	return builder.
 		Query[get_registry.Registry](`
@@ -210,6 +407,7 @@ func (s *gateway) GetRegistries(ctx context.Context, cmd get_registries.Query) (
 			,users.email
 		FROM registries
 		INNER JOIN users ON users.id = registries.created_by`).
+		Decrypt("credentials_password").
 		All(s.db, ctx, registryMapper)
 }
 
@@ -228,9 +426,583 @@ func (s *gateway) GetRegistryByID(ctx context.Context, cmd get_registry.Query) (
 		FROM registries
 		INNER JOIN users ON users.id = registries.created_by
 		WHERE registries.id = ?`, cmd.ID).
+		Decrypt("credentials_password").
 		One(s.db, ctx, registryMapper)
 }
 
+func (s *gateway) GetPeers(ctx context.Context, cmd get_peers.Query) ([]get_peer.Peer, error) {
+	return builder.
+		Query[get_peer.Peer](`
+		SELECT
+			peers.id
+			,peers.name
+			,peers.url
+			,peers.created_at
+			,users.id
+			,users.email
+		FROM peers
+		INNER JOIN users ON users.id = peers.created_by
+		ORDER BY peers.name`).
+		All(s.db, ctx, peerMapper)
+}
+
+func (s *gateway) GetPeerByID(ctx context.Context, cmd get_peer.Query) (get_peer.Peer, error) {
+	return builder.
+		Query[get_peer.Peer](`
+		SELECT
+			peers.id
+			,peers.name
+			,peers.url
+			,peers.created_at
+			,users.id
+			,users.email
+		FROM peers
+		INNER JOIN users ON users.id = peers.created_by
+		WHERE peers.id = ?`, cmd.ID).
+		One(s.db, ctx, peerMapper)
+}
+
+func (s *gateway) GetDnsZones(ctx context.Context, cmd get_dns_zones.Query) ([]get_dns_zone.Zone, error) {
+	return builder.
+		Query[get_dns_zone.Zone](`
+		SELECT
+			dns_zones.id
+			,dns_zones.name
+			,dns_zones.provider
+			,dns_zones.addresses
+			,dns_zones.created_at
+			,users.id
+			,users.email
+		FROM dns_zones
+		INNER JOIN users ON users.id = dns_zones.created_by
+		ORDER BY dns_zones.name`).
+		All(s.db, ctx, dnsZoneMapper, getDnsZoneRecordsDataloader)
+}
+
+func (s *gateway) GetDnsZoneByID(ctx context.Context, cmd get_dns_zone.Query) (get_dns_zone.Zone, error) {
+	return builder.
+		Query[get_dns_zone.Zone](`
+		SELECT
+			dns_zones.id
+			,dns_zones.name
+			,dns_zones.provider
+			,dns_zones.addresses
+			,dns_zones.created_at
+			,users.id
+			,users.email
+		FROM dns_zones
+		INNER JOIN users ON users.id = dns_zones.created_by
+		WHERE dns_zones.id = ?`, cmd.ID).
+		One(s.db, ctx, dnsZoneMapper, getDnsZoneRecordsDataloader)
+}
+
+func (s *gateway) GetTeams(ctx context.Context, cmd get_teams.Query) ([]get_teams.Team, error) {
+	return builder.
+		Query[get_teams.Team](`
+		SELECT
+			teams.id
+			,teams.name
+			,(SELECT COUNT(*) FROM apps WHERE apps.team_id = teams.id)
+			,teams.created_at
+			,users.id
+			,users.email
+		FROM teams
+		INNER JOIN users ON users.id = teams.created_by
+		WHERE TRUE`).
+		S(readableTeams(ctx)).
+		F("ORDER BY teams.name").
+		All(s.db, ctx, teamMapper)
+}
+
+func (s *gateway) GetSavedFilters(ctx context.Context, cmd get_saved_filters.Query) ([]get_saved_filters.SavedFilter, error) {
+	return builder.
+		Query[get_saved_filters.SavedFilter](`
+		SELECT
+			id
+			,name
+			,kind
+			,criteria
+			,created_at
+		FROM saved_filters
+		WHERE created_by = ?`, auth.CurrentUser(ctx).MustGet()).
+		S(builder.MaybeValue(cmd.Kind, "AND kind = ?")).
+		F("ORDER BY name").
+		All(s.db, ctx, savedFilterMapper)
+}
+
+func (s *gateway) GetTeamByID(ctx context.Context, cmd get_team.Query) (get_team.Team, error) {
+	return builder.
+		Query[get_team.Team](`
+		SELECT
+			teams.id
+			,teams.name
+			,teams.created_at
+			,users.id
+			,users.email
+		FROM teams
+		INNER JOIN users ON users.id = teams.created_by
+		WHERE teams.id = ?`, cmd.ID).
+		S(readableTeams(ctx)).
+		One(s.db, ctx, teamDetailMapper, getTeamMembersDataloader)
+}
+
+func (s *gateway) GetArtifactsUsage(ctx context.Context, cmd get_artifacts_usage.Query) (usage get_artifacts_usage.Usage, err error) {
+	usage.Apps, err = builder.
+		Query[get_artifacts_usage.App](`
+		SELECT
+			artifacts_usage.app_id
+			,apps.name
+			,artifacts_usage.size
+			,artifacts_usage.computed_at
+		FROM artifacts_usage
+		LEFT JOIN apps ON apps.id = artifacts_usage.app_id
+		ORDER BY artifacts_usage.size DESC`).
+		All(s.db, ctx, func(scanner storage.Scanner) (a get_artifacts_usage.App, err error) {
+			var computedAt time.Time
+
+			if err = scanner.Scan(
+				&a.ID,
+				&a.Name,
+				&a.Size,
+				&computedAt,
+			); err != nil {
+				return a, err
+			}
+
+			// Every row is written by the same collection
+			usage.Total += a.Size
+			usage.ComputedAt.Set(computedAt)
+
+			return a, nil
+		})
+
+	return usage, err
+}
+
+func (s *gateway) GetAppResourceUsage(ctx context.Context, cmd get_app_resource_usage.Query) ([]get_app_resource_usage.Sample, error) {
+	return builder.
+		Query[get_app_resource_usage.Sample](`
+		SELECT
+			environment
+			,service
+			,collected_at
+			,cpu
+			,memory
+			,memory_limit
+			,network_rx
+			,network_tx
+		FROM resource_usage
+		WHERE app_id = ?`, cmd.AppID).
+		S(
+			builder.MaybeValue(cmd.Environment, "AND environment = ?"),
+			builder.MaybeValue(cmd.Service, "AND service = ?"),
+			builder.MaybeValue(cmd.Since, "AND collected_at >= ?"),
+			readableApps(ctx, "AND app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY collected_at, environment, service").
+		All(s.db, ctx, func(scanner storage.Scanner) (sample get_app_resource_usage.Sample, err error) {
+			err = scanner.Scan(
+				&sample.Environment,
+				&sample.Service,
+				&sample.CollectedAt,
+				&sample.CPU,
+				&sample.Memory,
+				&sample.MemoryLimit,
+				&sample.NetworkRx,
+				&sample.NetworkTx,
+			)
+
+			return sample, err
+		})
+}
+
+func (s *gateway) GetAppTraffic(ctx context.Context, cmd get_app_traffic.Query) (get_app_traffic.Traffic, error) {
+	type row struct {
+		collectedAt time.Time
+		sample      domain.TrafficSample
+	}
+
+	rows, err := builder.
+		Query[row](`
+		SELECT
+			environment
+			,service
+			,collected_at
+			,requests
+			,statuses
+			,latencies
+		FROM traffic
+		WHERE app_id = ?`, cmd.AppID).
+		S(
+			builder.MaybeValue(cmd.Environment, "AND environment = ?"),
+			builder.MaybeValue(cmd.Service, "AND service = ?"),
+			builder.MaybeValue(cmd.Since, "AND collected_at >= ?"),
+			readableApps(ctx, "AND app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY collected_at, environment, service").
+		All(s.db, ctx, func(scanner storage.Scanner) (r row, err error) {
+			err = scanner.Scan(
+				&r.sample.Environment,
+				&r.sample.Service,
+				&r.collectedAt,
+				&r.sample.Requests,
+				&r.sample.Statuses,
+				&r.sample.Latencies,
+			)
+
+			return r, err
+		})
+
+	if err != nil {
+		return get_app_traffic.Traffic{}, err
+	}
+
+	// Latencies are merged here since percentiles could not be summed
+	var (
+		result = get_app_traffic.Traffic{
+			Services: []get_app_traffic.ServiceTraffic{},
+			Samples:  []get_app_traffic.Sample{},
+		}
+		latencies        domain.LatencyHistogram
+		serviceLatencies []domain.LatencyHistogram
+		serviceIndexes   = make(map[string]int)
+	)
+
+	for _, r := range rows {
+		if last := len(result.Samples) - 1; last < 0 || !result.Samples[last].CollectedAt.Equal(r.collectedAt) {
+			result.Samples = append(result.Samples, get_app_traffic.Sample{CollectedAt: r.collectedAt})
+		}
+
+		point := &result.Samples[len(result.Samples)-1]
+		point.Requests += r.sample.Requests
+		point.Statuses.Merge(r.sample.Statuses)
+
+		key := string(r.sample.Environment) + "/" + r.sample.Service
+		idx, exists := serviceIndexes[key]
+
+		if !exists {
+			idx = len(result.Services)
+			serviceIndexes[key] = idx
+			result.Services = append(result.Services, get_app_traffic.ServiceTraffic{
+				Environment: string(r.sample.Environment),
+				Service:     r.sample.Service,
+			})
+			serviceLatencies = append(serviceLatencies, nil)
+		}
+
+		result.Services[idx].Requests += r.sample.Requests
+		result.Services[idx].Statuses.Merge(r.sample.Statuses)
+		serviceLatencies[idx] = serviceLatencies[idx].Merge(r.sample.Latencies)
+
+		result.Requests += r.sample.Requests
+		result.Statuses.Merge(r.sample.Statuses)
+		latencies = latencies.Merge(r.sample.Latencies)
+	}
+
+	for i := range result.Services {
+		result.Services[i].P95Latency = serviceLatencies[i].Percentile(95).Milliseconds()
+	}
+
+	result.P95Latency = latencies.Percentile(95).Milliseconds()
+
+	return result, nil
+}
+
+func (s *gateway) GetDeploymentStats(ctx context.Context, cmd get_deployment_stats.Query) (get_deployment_stats.Stats, error) {
+	entries, err := builder.
+		Query[get_deployment_stats.Entry](`
+		SELECT
+			app_id
+			,environment
+			,succeeded
+			,failure_reason
+			,finished_at
+			,duration
+			,build_duration
+			,deploy_duration
+		FROM deployments_history
+		WHERE finished_at >= ? AND finished_at < ?`, cmd.Since, cmd.Until).
+		S(
+			builder.MaybeValue(cmd.AppID, "AND app_id = ?"),
+			builder.MaybeValue(cmd.TargetID, "AND target_id = ?"),
+			builder.MaybeValue(cmd.Environment, "AND environment = ?"),
+			readableApps(ctx, "AND app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY finished_at").
+		All(s.db, ctx, func(scanner storage.Scanner) (e get_deployment_stats.Entry, err error) {
+			err = scanner.Scan(
+				&e.AppID,
+				&e.Environment,
+				&e.Succeeded,
+				&e.FailureReason,
+				&e.FinishedAt,
+				&e.Duration,
+				&e.BuildDuration,
+				&e.DeployDuration,
+			)
+
+			return e, err
+		})
+
+	if err != nil {
+		return get_deployment_stats.Stats{}, err
+	}
+
+	return get_deployment_stats.Compute(cmd.Since, cmd.Until, entries), nil
+}
+
+func (s *gateway) GetAppIncidents(ctx context.Context, cmd get_app_incidents.Query) (storage.Paginated[get_app_incidents.Incident], error) {
+	return builder.
+		Select[get_app_incidents.Incident](`
+			incidents.id
+			,incidents.deployment_number
+			,deployments.config_environment
+			,incidents.service
+			,incidents.container
+			,incidents.kind
+			,incidents.exit_code
+			,incidents.occurred_at`).
+		F(`
+			FROM incidents
+			INNER JOIN deployments ON deployments.app_id = incidents.app_id AND deployments.deployment_number = incidents.deployment_number
+			WHERE incidents.app_id = ?`, cmd.AppID).
+		S(
+			builder.MaybeValue(cmd.Environment, "AND deployments.config_environment = ?"),
+			readableApps(ctx, "AND incidents.app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY incidents.occurred_at DESC").
+		Paginate(s.db, ctx, incidentMapper, cmd.Page.Get(1), 20)
+}
+
+func incidentMapper(scanner storage.Scanner) (i get_app_incidents.Incident, err error) {
+	var exitCode monad.Maybe[int64]
+
+	err = scanner.Scan(
+		&i.ID,
+		&i.DeploymentNumber,
+		&i.Environment,
+		&i.Service,
+		&i.Container,
+		&i.Kind,
+		&exitCode,
+		&i.OccurredAt,
+	)
+
+	if code, isSet := exitCode.TryGet(); isSet {
+		i.ExitCode.Set(int(code))
+	}
+
+	return i, err
+}
+
+func (s *gateway) GetAppEnvRevisions(ctx context.Context, cmd get_app_env_revisions.Query) (storage.Paginated[get_app_env_revisions.Revision], error) {
+	return builder.
+		Select[get_app_env_revisions.Revision](`
+			env_revisions.id
+			,env_revisions.environment
+			,env_revisions.vars
+			,env_revisions.changes
+			,env_revisions.created_at
+			,users.id
+			,users.email`).
+		F(`
+			FROM env_revisions
+			LEFT JOIN users ON users.id = env_revisions.created_by
+			WHERE env_revisions.app_id = ?`, cmd.AppID).
+		S(
+			builder.MaybeValue(cmd.Environment, "AND env_revisions.environment = ?"),
+			readableApps(ctx, "AND env_revisions.app_id IN (SELECT apps.id FROM apps WHERE", ")"),
+		).
+		F("ORDER BY env_revisions.created_at DESC").
+		Decrypt("vars").
+		Paginate(s.db, ctx, envRevisionMapper, cmd.Page.Get(1), 20)
+}
+
+func envRevisionMapper(scanner storage.Scanner) (r get_app_env_revisions.Revision, err error) {
+	var (
+		createdById    monad.Maybe[string]
+		createdByEmail monad.Maybe[string]
+	)
+
+	err = scanner.Scan(
+		&r.ID,
+		&r.Environment,
+		&r.Vars,
+		&r.Changes,
+		&r.CreatedAt,
+		&createdById,
+		&createdByEmail,
+	)
+
+	if id, isSet := createdById.TryGet(); isSet {
+		r.CreatedBy.Set(app.UserSummary{
+			ID:    id,
+			Email: createdByEmail.Get(""),
+		})
+	}
+
+	return r, err
+}
+
+func (s *gateway) GetAppMonitors(ctx context.Context, cmd get_app_monitors.Query) ([]get_app_monitors.Monitor, error) {
+	return builder.
+		Query[get_app_monitors.Monitor](`
+		SELECT
+			id
+			,environment
+			,interval
+			,status_code
+			,keyword
+			,status
+			,next_check_at
+		FROM monitors
+		WHERE app_id = ?`, cmd.AppID).
+		S(readableApps(ctx, "AND app_id IN (SELECT apps.id FROM apps WHERE", ")")).
+		F("ORDER BY environment").
+		All(s.db, ctx, monitorMapper, getMonitorChecksDataloader)
+}
+
+func (s *gateway) GetAppAddons(ctx context.Context, cmd get_app_addons.Query) ([]get_app_addons.Addon, error) {
+	return builder.
+		Query[get_app_addons.Addon](`
+		SELECT
+			addons.id
+			,addons.environment
+			,addons.kind
+			,addons.variable
+			,addons.username
+			,addons.password
+			,addons.target_id
+			,targets.name
+			,targets.url
+			,addons.state_status
+			,addons.state_version
+			,addons.state_errcode
+			,addons.cleanup_requested_at
+			,addons.created_at
+			,users.id
+			,users.email
+		FROM addons
+		INNER JOIN targets ON targets.id = addons.target_id
+		INNER JOIN users ON users.id = addons.created_by
+		WHERE addons.app_id = ?`, cmd.AppID).
+		S(readableApps(ctx, "AND addons.app_id IN (SELECT apps.id FROM apps WHERE", ")")).
+		F("ORDER BY addons.environment, addons.kind").
+		Decrypt("password").
+		All(s.db, ctx, addonMapper)
+}
+
+func (s *gateway) GetAddonBackups(ctx context.Context, cmd get_addon_backups.Query) ([]get_addon_backups.Backup, error) {
+	return builder.
+		Query[get_addon_backups.Backup](`
+		SELECT
+			addon_backups.id
+			,addon_backups.status
+			,addon_backups.size
+			,addon_backups.errcode
+			,addon_backups.restore_requested_at
+			,rusers.id
+			,rusers.email
+			,addon_backups.restore_finished_at
+			,addon_backups.restore_errcode
+			,addon_backups.created_at
+		FROM addon_backups
+		LEFT JOIN users rusers ON rusers.id = addon_backups.restore_requested_by
+		WHERE addon_backups.app_id = ? AND addon_backups.addon_id = ?`, cmd.AppID, cmd.AddonID).
+		S(readableApps(ctx, "AND addon_backups.app_id IN (SELECT apps.id FROM apps WHERE", ")")).
+		F("ORDER BY addon_backups.created_at DESC").
+		All(s.db, ctx, addonBackupMapper)
+}
+
+func (s *gateway) GetAppArchives(ctx context.Context, cmd get_app_archives.Query) ([]get_app_archives.Archive, error) {
+	return builder.
+		Query[get_app_archives.Archive](`
+		SELECT
+			app_archives.id
+			,app_archives.app_id
+			,app_archives.app_name
+			,app_archives.status
+			,app_archives.size
+			,app_archives.errcode
+			,app_archives.expires_at
+			,app_archives.requested_at
+			,users.id
+			,users.email
+		FROM app_archives
+		INNER JOIN users ON users.id = app_archives.requested_by
+		WHERE TRUE`).
+		S(requestedArchives(ctx)).
+		F("ORDER BY app_archives.requested_at DESC").
+		All(s.db, ctx, appArchiveMapper)
+}
+
+func (s *gateway) GetTrashedApps(ctx context.Context, cmd get_trashed_apps.Query) ([]get_trashed_apps.TrashedApp, error) {
+	return builder.
+		Query[get_trashed_apps.TrashedApp](`
+		SELECT
+			trashed_apps.id
+			,trashed_apps.name
+			,trashed_apps.production_target
+			,trashed_apps.staging_target
+			,trashed_apps.production_source_discriminator IS NOT NULL
+			,trashed_apps.staging_source_discriminator IS NOT NULL
+			,trashed_apps.deleted_at
+			,users.id
+			,users.email
+			,trashed_apps.expires_at
+		FROM trashed_apps
+		INNER JOIN users ON users.id = trashed_apps.deleted_by
+		WHERE TRUE`).
+		S(deletedApps(ctx)).
+		F("ORDER BY trashed_apps.deleted_at DESC").
+		All(s.db, ctx, trashedAppMapper)
+}
+
+func (s *gateway) GetGitOpsRuns(ctx context.Context, cmd get_gitops_runs.Query) (storage.Paginated[get_gitops_runs.Run], error) {
+	return builder.
+		Select[get_gitops_runs.Run](`
+			id
+			,commit_hash
+			,changes
+			,error
+			,created_at`).
+		F(`
+			FROM gitops_runs
+			ORDER BY created_at DESC`).
+		Paginate(s.db, ctx, gitOpsRunMapper, cmd.Page.Get(1), 20)
+}
+
+func deploymentFeedMapper(scanner storage.Scanner) (d get_deployments_feed.Deployment, err error) {
+	err = scanner.Scan(
+		&d.AppID,
+		&d.AppName,
+		&d.DeploymentNumber,
+		&d.Environment,
+		&d.Status,
+		&d.ErrCode,
+		&d.Note,
+		&d.RequestedAt,
+		&d.RequestedBy.ID,
+		&d.RequestedBy.Email,
+		&d.StartedAt,
+		&d.FinishedAt,
+	)
+
+	return d, err
+}
+
+func gitOpsRunMapper(scanner storage.Scanner) (r get_gitops_runs.Run, err error) {
+	err = scanner.Scan(
+		&r.ID,
+		&r.Commit,
+		&r.Changes,
+		&r.Error,
+		&r.CreatedAt,
+	)
+
+	return r, err
+}
+
 var getDeploymentDataloader = builder.NewDataloader(
 	func(a get_apps.App) string { return a.ID },
 	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_apps.App]) error {
@@ -248,11 +1020,15 @@ var getDeploymentDataloader = builder.NewDataloader(
 				,deployments.source
 				,deployments.state_status
 				,deployments.state_errcode
+				,deployments.state_failure_reason
 				,deployments.state_started_at
 				,deployments.state_finished_at
 				,deployments.requested_at
 				,users.id
 				,users.email
+				,deployments.labels
+				,deployments.note
+				,deployments.metadata
 				,MAX(requested_at) AS max_requested_at
 			FROM deployments
 			INNER JOIN users ON users.id = deployments.requested_by
@@ -282,28 +1058,271 @@ var getDeploymentDetailDataloader = builder.NewDataloader(
 				,deployments.source
 				,deployments.state_status
 				,deployments.state_errcode
+				,deployments.state_failure_reason
 				,deployments.state_services
 				,deployments.state_started_at
 				,deployments.state_finished_at
 				,deployments.requested_at
 				,users.id
 				,users.email
+				,deployments.labels
+				,deployments.note
+				,deployments.metadata
 				,MAX(requested_at) AS max_requested_at
 			FROM deployments
 			INNER JOIN users ON users.id = deployments.requested_by
 			LEFT JOIN targets ON targets.id = deployments.config_target`).
 			S(builder.Array("WHERE deployments.app_id IN", kr.Keys())).
 			F("GROUP BY deployments.app_id, deployments.config_environment").
-			All(e, ctx, deploymentDetailMapper(kr))
+			All(e, ctx, deploymentDetailMapper(kr), getDeploymentTimelineDataloader)
+
+		return err
+	})
+
+var getDeploymentTimelineDataloader = builder.NewDataloader(
+	func(d get_deployment.Deployment) string { return deploymentTimelineKey(d.AppID, d.DeploymentNumber) },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_deployment.Deployment]) error {
+		_, err := builder.
+			Query[get_deployment.Transition](`
+			SELECT
+				app_id
+				,deployment_number
+				,state
+				,occurred_at
+			FROM deployment_transitions`).
+			S(builder.Array("WHERE app_id || '/' || deployment_number IN", kr.Keys())).
+			F("ORDER BY app_id, deployment_number, occurred_at").
+			All(e, ctx, deploymentTransitionMapper(kr))
+
+		return err
+	})
+
+var getTeamMembersDataloader = builder.NewDataloader(
+	func(t get_team.Team) string { return t.ID },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_team.Team]) error {
+		_, err := builder.
+			Query[get_team.Member](`
+			SELECT
+				teams.id
+				,users.id
+				,users.email
+				,json_extract(users.grants, '$.team."' || teams.id || '"')
+			FROM teams
+			INNER JOIN users ON json_extract(users.grants, '$.team."' || teams.id || '"') IS NOT NULL`).
+			S(builder.Array("WHERE teams.id IN", kr.Keys())).
+			F("ORDER BY users.email").
+			All(e, ctx, teamMemberMapper(kr))
 
 		return err
 	})
 
+var getMonitorChecksDataloader = builder.NewDataloader(
+	func(m get_app_monitors.Monitor) string { return m.ID },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_app_monitors.Monitor]) error {
+		_, err := builder.
+			Query[get_app_monitors.Check](`
+			SELECT
+				monitor_id
+				,checked_at
+				,status
+				,status_code
+				,response_time
+				,reason
+			FROM monitor_checks`).
+			S(builder.Array("WHERE monitor_id IN", kr.Keys())).
+			F("ORDER BY checked_at DESC").
+			All(e, ctx, monitorCheckMapper(kr))
+
+		return err
+	})
+
+var getDnsZoneRecordsDataloader = builder.NewDataloader(
+	func(z get_dns_zone.Zone) string { return z.ID },
+	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_dns_zone.Zone]) error {
+		_, err := builder.
+			Query[get_dns_zone.Record](`
+			SELECT
+				dns_records.zone_id
+				,dns_records.host
+				,dns_records.app_id
+				,apps.name
+				,dns_records.environment
+				,dns_records.records
+				,dns_records.status
+				,dns_records.error
+				,dns_records.drifted_at
+				,dns_records.checked_at
+			FROM dns_records
+			INNER JOIN apps ON apps.id = dns_records.app_id`).
+			S(builder.Array("WHERE dns_records.zone_id IN", kr.Keys())).
+			F("ORDER BY dns_records.host").
+			All(e, ctx, dnsRecordReadMapper(kr))
+
+		return err
+	})
+
+// Restrict teams to the ones on which the current user has been granted a role.
+func readableTeams(ctx context.Context) builder.Statement {
+	return func(b builder.Builder) {
+		if !auth.RestrictedTo(ctx).HasValue() {
+			return
+		}
+
+		teams := auth.GrantedIDs(ctx, auth.ResourceTeam)
+
+		if len(teams) == 0 {
+			b.Apply("AND FALSE")
+			return
+		}
+
+		b.Apply("AND teams.id IN ("+placeholders(len(teams))+")", toArgs(teams)...)
+	}
+}
+
+// Restrict targets to the ones readable by the current user: the ones it owns and the
+// ones on which it has been granted a role. When authenticated with an API token scoped
+// to some apps, targets are also limited to the ones used by those apps.
+func readableTargets(ctx context.Context) builder.Statement {
+	return func(b builder.Builder) {
+		if uid, restricted := auth.RestrictedTo(ctx).TryGet(); restricted {
+			targets := auth.GrantedIDs(ctx, auth.ResourceTarget)
+
+			if len(targets) == 0 {
+				b.Apply("AND targets.created_by = ?", uid)
+			} else {
+				b.Apply("AND (targets.created_by = ? OR targets.id IN ("+placeholders(len(targets))+"))",
+					append([]any{uid}, toArgs(targets)...)...)
+			}
+		}
+
+		if ids, scoped := auth.ScopedAppIDs(ctx).TryGet(); scoped && len(ids) > 0 {
+			b.Apply("AND EXISTS (SELECT 1 FROM apps WHERE apps.id IN ("+placeholders(len(ids))+
+				") AND targets.id IN (apps.production_target, apps.staging_target))", toArgs(ids)...)
+		}
+	}
+}
+
+// Restrict app archives to the ones requested by the current user since their
+// application may not exist anymore.
+func requestedArchives(ctx context.Context) builder.Statement {
+	return func(b builder.Builder) {
+		if uid, restricted := auth.RestrictedTo(ctx).TryGet(); restricted {
+			b.Apply("AND app_archives.requested_by = ?", uid)
+		}
+	}
+}
+
+// Restrict trashed apps to the ones created or deleted by the current user since
+// grants on them may not exist anymore.
+func deletedApps(ctx context.Context) builder.Statement {
+	return func(b builder.Builder) {
+		if uid, restricted := auth.RestrictedTo(ctx).TryGet(); restricted {
+			b.Apply("AND (trashed_apps.created_by = ? OR trashed_apps.deleted_by = ?)", uid, uid)
+		}
+	}
+}
+
+// Restrict apps to the ones readable by the current user: the ones it owns and the
+// ones on which it has been granted a role, directly or through their targets or team.
+// When authenticated with an API token, apps are also limited to the token scopes.
+// The condition is wrapped by the given prefix and suffix.
+func readableApps(ctx context.Context, prefix, suffix string) builder.Statement {
+	return func(b builder.Builder) {
+		var (
+			conditions []string
+			args       []any
+		)
+
+		if uid, restricted := auth.RestrictedTo(ctx).TryGet(); restricted {
+			var (
+				readable = []string{"apps.created_by = ?"}
+				apps     = auth.GrantedIDs(ctx, auth.ResourceApp)
+				targets  = auth.GrantedIDs(ctx, auth.ResourceTarget)
+				teams    = auth.GrantedIDs(ctx, auth.ResourceTeam)
+			)
+
+			args = append(args, uid)
+
+			if len(apps) > 0 {
+				readable = append(readable, "apps.id IN ("+placeholders(len(apps))+")")
+				args = append(args, toArgs(apps)...)
+			}
+
+			if len(targets) > 0 {
+				readable = append(readable,
+					"apps.production_target IN ("+placeholders(len(targets))+")",
+					"apps.staging_target IN ("+placeholders(len(targets))+")")
+				args = append(append(args, toArgs(targets)...), toArgs(targets)...)
+			}
+
+			if len(teams) > 0 {
+				readable = append(readable, "apps.team_id IN ("+placeholders(len(teams))+")")
+				args = append(args, toArgs(teams)...)
+			}
+
+			conditions = append(conditions, "("+strings.Join(readable, " OR ")+")")
+		}
+
+		if ids, scoped := auth.ScopedAppIDs(ctx).TryGet(); scoped && len(ids) > 0 {
+			conditions = append(conditions, "apps.id IN ("+placeholders(len(ids))+")")
+			args = append(args, toArgs(ids)...)
+		}
+
+		if len(conditions) == 0 {
+			return
+		}
+
+		b.Apply(prefix+" "+strings.Join(conditions, " AND ")+suffix, args...)
+	}
+}
+
+// Restrict results to the ones having every given label in the given JSON array column.
+func hasLabels(column string, labels []string) builder.Statement {
+	return func(b builder.Builder) {
+		for _, label := range labels {
+			b.Apply("AND EXISTS (SELECT 1 FROM json_each("+column+") WHERE json_each.value = ?)", label)
+		}
+	}
+}
+
+// Matches deployments whose note, metadata keys or values contain the given search term.
+func searchDeployments(search monad.Maybe[string]) builder.Statement {
+	return func(b builder.Builder) {
+		term, isSet := search.TryGet()
+
+		if !isSet || strings.TrimSpace(term) == "" {
+			return
+		}
+
+		pattern := "%" + strings.TrimSpace(term) + "%"
+
+		b.Apply(`AND (deployments.note LIKE ? OR EXISTS (
+			SELECT 1 FROM json_each(deployments.metadata) WHERE json_each.key LIKE ? OR json_each.value LIKE ?))`,
+			pattern, pattern, pattern)
+	}
+}
+
+func placeholders(count int) string {
+	return strings.Repeat(",?", count)[1:]
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+
+	for i, value := range values {
+		args[i] = value
+	}
+
+	return args
+}
+
 // AppData scanner which include last deployments by environment.
 func appDataMapper(s storage.Scanner) (a get_apps.App, err error) {
 	var (
 		cleanupRequestedById    monad.Maybe[string]
 		cleanupRequestedByEmail monad.Maybe[string]
+		teamID                  monad.Maybe[string]
+		teamName                monad.Maybe[string]
 	)
 
 	err = s.Scan(
@@ -321,6 +1340,9 @@ func appDataMapper(s storage.Scanner) (a get_apps.App, err error) {
 		&a.StagingTarget.ID,
 		&a.StagingTarget.Name,
 		&a.StagingTarget.Url,
+		&teamID,
+		&teamName,
+		&a.Labels,
 	)
 
 	if id, isSet := cleanupRequestedById.TryGet(); isSet {
@@ -330,6 +1352,13 @@ func appDataMapper(s storage.Scanner) (a get_apps.App, err error) {
 		})
 	}
 
+	if id, isSet := teamID.TryGet(); isSet {
+		a.Team.Set(app.TeamSummary{
+			ID:   id,
+			Name: teamName.MustGet(),
+		})
+	}
+
 	return a, err
 }
 
@@ -340,6 +1369,11 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 		token                   monad.Maybe[storage.SecretString]
 		cleanupRequestedById    monad.Maybe[string]
 		cleanupRequestedByEmail monad.Maybe[string]
+		teamID                  monad.Maybe[string]
+		teamName                monad.Maybe[string]
+		registryID              monad.Maybe[string]
+		registryName            monad.Maybe[string]
+		registryUrl             monad.Maybe[string]
 	)
 
 	err = s.Scan(
@@ -351,16 +1385,41 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 		&a.Production.Target.Name,
 		&a.Production.Target.Url,
 		&a.Production.Vars,
+		&a.Production.Exposure,
+		&a.Production.Protection,
+		&a.Production.ProxyRules,
+		&a.Production.ComposeOverride,
+		&a.Production.UrlAliases,
+		&a.Production.Scale,
 		&a.Staging.Target.ID,
 		&a.Staging.Target.Name,
 		&a.Staging.Target.Url,
 		&a.Staging.Vars,
+		&a.Staging.Exposure,
+		&a.Staging.Protection,
+		&a.Staging.ProxyRules,
+		&a.Staging.ComposeOverride,
+		&a.Staging.UrlAliases,
+		&a.Staging.Scale,
 		&a.CleanupRequestedAt,
 		&cleanupRequestedById,
 		&cleanupRequestedByEmail,
 		&a.CreatedAt,
 		&a.CreatedBy.ID,
 		&a.CreatedBy.Email,
+		&teamID,
+		&teamName,
+		&a.Dependencies,
+		&a.Labels,
+		&a.Platforms,
+		&registryID,
+		&registryName,
+		&registryUrl,
+		&a.StaticSite,
+		&a.BadgeEnabled,
+		&a.DeployTrigger,
+		&a.FreezeWindows,
+		&a.Timeouts,
 	)
 
 	if u, isSet := url.TryGet(); isSet {
@@ -377,6 +1436,21 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 		})
 	}
 
+	if id, isSet := teamID.TryGet(); isSet {
+		a.Team.Set(app.TeamSummary{
+			ID:   id,
+			Name: teamName.MustGet(),
+		})
+	}
+
+	if id, isSet := registryID.TryGet(); isSet {
+		a.BuildRegistry.Set(get_app_detail.BuildRegistry{
+			ID:   id,
+			Name: registryName.MustGet(),
+			Url:  registryUrl.MustGet(),
+		})
+	}
+
 	return a, err
 }
 
@@ -400,11 +1474,15 @@ func deploymentMapper(kr storage.KeyedResult[get_apps.App]) storage.Mapper[get_a
 			&sourceData,
 			&d.State.Status,
 			&d.State.ErrCode,
+			&d.State.FailureReason,
 			&d.State.StartedAt,
 			&d.State.FinishedAt,
 			&d.RequestedAt,
 			&d.RequestedBy.ID,
 			&d.RequestedBy.Email,
+			&d.Labels,
+			&d.Note,
+			&d.Metadata,
 			&maxRequestedAt,
 		)
 
@@ -456,12 +1534,16 @@ func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.
 			&sourceData,
 			&d.State.Status,
 			&d.State.ErrCode,
+			&d.State.FailureReason,
 			&d.State.Services,
 			&d.State.StartedAt,
 			&d.State.FinishedAt,
 			&d.RequestedAt,
 			&d.RequestedBy.ID,
 			&d.RequestedBy.Email,
+			&d.Labels,
+			&d.Note,
+			&d.Metadata,
 			&maxRequestedAt,
 		)
 
@@ -478,6 +1560,9 @@ func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.
 
 		d.ResolveServicesUrls()
 
+		// Always return an array to ease the client work
+		d.Timeline = []get_deployment.Transition{}
+
 		if kr != nil {
 			kr.Update(d.AppID, func(a get_app_detail.App) get_app_detail.App {
 				switch domain.Environment(d.Environment) {
@@ -497,6 +1582,7 @@ func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.
 func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 	var (
 		providerData            string
+		vars                    domain.EnvVars
 		cleanupRequestedById    monad.Maybe[string]
 		cleanupRequestedByEmail monad.Maybe[string]
 	)
@@ -510,6 +1596,11 @@ func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 		&t.State.Status,
 		&t.State.ErrCode,
 		&t.State.LastReadyVersion,
+		&vars,
+		&t.Platform,
+		&t.ProxyVersion,
+		&t.GarbageCollection,
+		&t.Labels,
 		&t.CleanupRequestedAt,
 		&cleanupRequestedById,
 		&cleanupRequestedByEmail,
@@ -529,11 +1620,123 @@ func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 		})
 	}
 
+	t.Vars = vars.Masked()
 	t.Provider.Data, err = get_target.ProviderConfigTypes.From(t.Provider.Kind, providerData)
 
 	return t, err
 }
 
+func targetCapacityMapper(scanner storage.Scanner) (t get_targets_capacity.Target, err error) {
+	var (
+		entrypoints       domain.TargetEntrypoints
+		runningContainers monad.Maybe[int64]
+		imagesSize        monad.Maybe[int64]
+		diskFree          monad.Maybe[int64]
+		diskTotal         monad.Maybe[int64]
+		collectedAt       monad.Maybe[time.Time]
+	)
+
+	if err = scanner.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Url,
+		&t.Apps,
+		&entrypoints,
+		&runningContainers,
+		&imagesSize,
+		&diskFree,
+		&diskTotal,
+		&collectedAt,
+	); err != nil {
+		return t, err
+	}
+
+	t.ReservedPorts = make([]string, 0)
+
+	for _, envs := range entrypoints {
+		for _, names := range envs {
+			for name, port := range names {
+				if p, isAssigned := port.TryGet(); isAssigned {
+					t.ReservedPorts = append(t.ReservedPorts, p.String()+"/"+name.Protocol())
+				}
+			}
+		}
+	}
+
+	slices.Sort(t.ReservedPorts)
+
+	if at, isSet := collectedAt.TryGet(); isSet {
+		t.Resources.Set(get_targets_capacity.Resources{
+			RunningContainers: int(runningContainers.Get(0)),
+			ImagesSize:        imagesSize.Get(0),
+			DiskFree:          diskFree.Get(0),
+			DiskTotal:         diskTotal.Get(0),
+			CollectedAt:       at,
+		})
+	}
+
+	return t, nil
+}
+
+func peerMapper(scanner storage.Scanner) (p get_peer.Peer, err error) {
+	err = scanner.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Url,
+		&p.CreatedAt,
+		&p.CreatedBy.ID,
+		&p.CreatedBy.Email,
+	)
+
+	return p, err
+}
+
+func dnsZoneMapper(scanner storage.Scanner) (z get_dns_zone.Zone, err error) {
+	err = scanner.Scan(
+		&z.ID,
+		&z.Name,
+		&z.Provider,
+		&z.Addresses,
+		&z.CreatedAt,
+		&z.CreatedBy.ID,
+		&z.CreatedBy.Email,
+	)
+
+	z.Records = make([]get_dns_zone.Record, 0)
+
+	return z, err
+}
+
+func dnsRecordReadMapper(kr storage.KeyedResult[get_dns_zone.Zone]) storage.Mapper[get_dns_zone.Record] {
+	return func(scanner storage.Scanner) (r get_dns_zone.Record, err error) {
+		var zoneID string
+
+		err = scanner.Scan(
+			&zoneID,
+			&r.Host,
+			&r.AppID,
+			&r.AppName,
+			&r.Environment,
+			&r.Records,
+			&r.Status,
+			&r.Error,
+			&r.DriftedAt,
+			&r.CheckedAt,
+		)
+
+		if err != nil {
+			return r, err
+		}
+
+		kr.Update(zoneID, func(z get_dns_zone.Zone) get_dns_zone.Zone {
+			z.Records = append(z.Records, r)
+			return z
+		})
+
+		return r, err
+	}
+}
+
 func registryMapper(scanner storage.Scanner) (r get_registry.Registry, err error) {
 	var (
 		credentialsUsername monad.Maybe[string]
@@ -564,3 +1767,259 @@ func registryMapper(scanner storage.Scanner) (r get_registry.Registry, err error
 
 	return r, err
 }
+
+func savedFilterMapper(scanner storage.Scanner) (f get_saved_filters.SavedFilter, err error) {
+	err = scanner.Scan(
+		&f.ID,
+		&f.Name,
+		&f.Kind,
+		&f.Criteria,
+		&f.CreatedAt,
+	)
+
+	return f, err
+}
+
+func teamMapper(scanner storage.Scanner) (t get_teams.Team, err error) {
+	err = scanner.Scan(
+		&t.ID,
+		&t.Name,
+		&t.AppsCount,
+		&t.CreatedAt,
+		&t.CreatedBy.ID,
+		&t.CreatedBy.Email,
+	)
+
+	return t, err
+}
+
+func teamDetailMapper(scanner storage.Scanner) (t get_team.Team, err error) {
+	err = scanner.Scan(
+		&t.ID,
+		&t.Name,
+		&t.CreatedAt,
+		&t.CreatedBy.ID,
+		&t.CreatedBy.Email,
+	)
+
+	t.Members = make([]get_team.Member, 0)
+
+	return t, err
+}
+
+func deploymentTransitionMapper(kr storage.KeyedResult[get_deployment.Deployment]) storage.Mapper[get_deployment.Transition] {
+	return func(scanner storage.Scanner) (t get_deployment.Transition, err error) {
+		var (
+			appID  string
+			number int
+		)
+
+		err = scanner.Scan(
+			&appID,
+			&number,
+			&t.State,
+			&t.OccurredAt,
+		)
+
+		if err != nil {
+			return t, err
+		}
+
+		kr.Update(deploymentTimelineKey(appID, number), func(d get_deployment.Deployment) get_deployment.Deployment {
+			if last := len(d.Timeline) - 1; last >= 0 {
+				d.Timeline[last].Duration.Set(t.OccurredAt.Sub(d.Timeline[last].OccurredAt).Milliseconds())
+			}
+
+			d.Timeline = append(d.Timeline, t)
+			return d
+		})
+
+		return t, err
+	}
+}
+
+func deploymentTimelineKey(appID string, number int) string {
+	return appID + "/" + strconv.Itoa(number)
+}
+
+func teamMemberMapper(kr storage.KeyedResult[get_team.Team]) storage.Mapper[get_team.Member] {
+	return func(scanner storage.Scanner) (m get_team.Member, err error) {
+		var teamID string
+
+		err = scanner.Scan(
+			&teamID,
+			&m.ID,
+			&m.Email,
+			&m.Role,
+		)
+
+		if err != nil {
+			return m, err
+		}
+
+		kr.Update(teamID, func(t get_team.Team) get_team.Team {
+			t.Members = append(t.Members, m)
+			return t
+		})
+
+		return m, err
+	}
+}
+
+func addonMapper(scanner storage.Scanner) (a get_app_addons.Addon, err error) {
+	var username, password string
+
+	err = scanner.Scan(
+		&a.ID,
+		&a.Environment,
+		&a.Kind,
+		&a.Variable,
+		&username,
+		&password,
+		&a.Target.ID,
+		&a.Target.Name,
+		&a.Target.Url,
+		&a.State.Status,
+		&a.State.Version,
+		&a.State.ErrCode,
+		&a.CleanupRequestedAt,
+		&a.CreatedAt,
+		&a.CreatedBy.ID,
+		&a.CreatedBy.Email,
+	)
+
+	id := domain.AddonID(a.ID)
+
+	a.Host = domain.AddonHost(id)
+	a.ConnectionString = domain.AddonConnectionString(id, domain.AddonKind(a.Kind), domain.NewCredentials(username, password))
+
+	return a, err
+}
+
+func addonBackupMapper(scanner storage.Scanner) (b get_addon_backups.Backup, err error) {
+	var (
+		restoreRequestedAt      monad.Maybe[time.Time]
+		restoreRequestedById    monad.Maybe[string]
+		restoreRequestedByEmail monad.Maybe[string]
+		restore                 get_addon_backups.Restore
+	)
+
+	err = scanner.Scan(
+		&b.ID,
+		&b.Status,
+		&b.Size,
+		&b.ErrCode,
+		&restoreRequestedAt,
+		&restoreRequestedById,
+		&restoreRequestedByEmail,
+		&restore.FinishedAt,
+		&restore.ErrCode,
+		&b.CreatedAt,
+	)
+
+	if requestedAt, isSet := restoreRequestedAt.TryGet(); isSet {
+		restore.RequestedAt = requestedAt
+		restore.RequestedBy = app.UserSummary{
+			ID:    restoreRequestedById.Get(""),
+			Email: restoreRequestedByEmail.Get(""),
+		}
+		b.Restore.Set(restore)
+	}
+
+	return b, err
+}
+
+func monitorMapper(scanner storage.Scanner) (m get_app_monitors.Monitor, err error) {
+	err = scanner.Scan(
+		&m.ID,
+		&m.Environment,
+		&m.Interval,
+		&m.StatusCode,
+		&m.Keyword,
+		&m.Status,
+		&m.NextCheckAt,
+	)
+
+	m.Checks = make([]get_app_monitors.Check, 0)
+
+	return m, err
+}
+
+func monitorCheckMapper(kr storage.KeyedResult[get_app_monitors.Monitor]) storage.Mapper[get_app_monitors.Check] {
+	return func(scanner storage.Scanner) (c get_app_monitors.Check, err error) {
+		var (
+			monitorID  string
+			statusCode monad.Maybe[int64]
+		)
+
+		err = scanner.Scan(
+			&monitorID,
+			&c.CheckedAt,
+			&c.Status,
+			&statusCode,
+			&c.ResponseTime,
+			&c.Reason,
+		)
+
+		if err != nil {
+			return c, err
+		}
+
+		if code, isSet := statusCode.TryGet(); isSet {
+			c.StatusCode.Set(int(code))
+		}
+
+		kr.Update(monitorID, func(m get_app_monitors.Monitor) get_app_monitors.Monitor {
+			m.Checks = append(m.Checks, c)
+			return m
+		})
+
+		return c, err
+	}
+}
+
+func appArchiveMapper(scanner storage.Scanner) (a get_app_archives.Archive, err error) {
+	err = scanner.Scan(
+		&a.ID,
+		&a.AppID,
+		&a.AppName,
+		&a.Status,
+		&a.Size,
+		&a.ErrCode,
+		&a.ExpiresAt,
+		&a.RequestedAt,
+		&a.RequestedBy.ID,
+		&a.RequestedBy.Email,
+	)
+
+	return a, err
+}
+
+func trashedAppMapper(scanner storage.Scanner) (a get_trashed_apps.TrashedApp, err error) {
+	var productionRedeployable, stagingRedeployable bool
+
+	err = scanner.Scan(
+		&a.ID,
+		&a.Name,
+		&a.ProductionTarget,
+		&a.StagingTarget,
+		&productionRedeployable,
+		&stagingRedeployable,
+		&a.DeletedAt,
+		&a.DeletedBy.ID,
+		&a.DeletedBy.Email,
+		&a.ExpiresAt,
+	)
+
+	a.RedeployableEnvironments = make([]string, 0, 2)
+
+	if productionRedeployable {
+		a.RedeployableEnvironments = append(a.RedeployableEnvironments, string(domain.Production))
+	}
+
+	if stagingRedeployable {
+		a.RedeployableEnvironments = append(a.RedeployableEnvironments, string(domain.Staging))
+	}
+
+	return a, err
+}
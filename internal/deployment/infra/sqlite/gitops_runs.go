@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	GitOpsRunsStore interface {
+		domain.GitOpsRunsReader
+		domain.GitOpsRunsWriter
+	}
+
+	gitOpsRunsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewGitOpsRunsStore(db *sqlite.Database) GitOpsRunsStore {
+	return &gitOpsRunsStore{db}
+}
+
+func (s *gitOpsRunsStore) GetLatest(ctx context.Context) (domain.GitOpsRun, error) {
+	return builder.
+		Query[domain.GitOpsRun](`
+		SELECT
+			id
+			,commit_hash
+			,changes
+			,error
+			,created_at
+		FROM gitops_runs
+		ORDER BY created_at DESC
+		LIMIT 1`).
+		One(s.db, ctx, domain.GitOpsRunFrom)
+}
+
+func (s *gitOpsRunsStore) Write(ctx context.Context, runs ...*domain.GitOpsRun) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, runs, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.GitOpsRunRecorded:
+			return builder.
+				Insert("gitops_runs", builder.Values{
+					"id":          evt.ID,
+					"commit_hash": evt.Commit,
+					"changes":     evt.Changes,
+					"error":       evt.Err,
+					"created_at":  evt.CreatedAt,
+				}).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type incidentsStore struct {
+	db *sqlite.Database
+}
+
+func NewIncidentsStore(db *sqlite.Database) domain.IncidentsWriter {
+	return &incidentsStore{db}
+}
+
+func (s *incidentsStore) Write(ctx context.Context, incidents ...*domain.Incident) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, incidents, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.IncidentRecorded:
+			return builder.
+				Insert("incidents", builder.Values{
+					"id":                evt.ID,
+					"app_id":            evt.DeploymentID.AppID(),
+					"deployment_number": evt.DeploymentID.DeploymentNumber(),
+					"service":           evt.Service,
+					"container":         evt.Container,
+					"kind":              evt.Kind,
+					"exit_code":         evt.ExitCode,
+					"occurred_at":       evt.OccurredAt,
+				}).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
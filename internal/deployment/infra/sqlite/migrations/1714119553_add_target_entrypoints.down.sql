@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS on_deployment_failed_cleanup_jobs;
+
+ALTER TABLE targets DROP COLUMN entrypoints;
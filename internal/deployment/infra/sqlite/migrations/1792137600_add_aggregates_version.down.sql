@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN version;
+ALTER TABLE targets DROP COLUMN version;
+ALTER TABLE deployments DROP COLUMN version;
@@ -0,0 +1,4 @@
+-- Version columns used for optimistic concurrency checks when persisting aggregates.
+ALTER TABLE apps ADD version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE targets ADD version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE deployments ADD version INTEGER NOT NULL DEFAULT 1;
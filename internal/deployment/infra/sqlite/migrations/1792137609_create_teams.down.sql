@@ -0,0 +1,3 @@
+DROP INDEX idx_apps_team_id;
+ALTER TABLE apps DROP COLUMN team_id;
+DROP TABLE teams;
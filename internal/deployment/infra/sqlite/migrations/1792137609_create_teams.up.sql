@@ -0,0 +1,14 @@
+CREATE TABLE teams (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_teams PRIMARY KEY(id)
+    ,CONSTRAINT fk_teams_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- No foreign key here since sqlite could not drop such a column afterward, teams
+-- could not be deleted while they still have apps anyway.
+ALTER TABLE apps ADD team_id TEXT NULL;
+
+CREATE INDEX idx_apps_team_id ON apps(team_id);
@@ -0,0 +1 @@
+DROP TABLE artifacts_usage;
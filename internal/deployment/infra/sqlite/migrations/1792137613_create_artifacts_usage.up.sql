@@ -0,0 +1,8 @@
+-- No foreign key here since artifacts of deleted apps may still be on the disk
+-- until the next collection.
+CREATE TABLE artifacts_usage (
+    app_id TEXT NOT NULL
+    ,size INTEGER NOT NULL
+    ,computed_at DATETIME NOT NULL
+    ,CONSTRAINT pk_artifacts_usage PRIMARY KEY(app_id)
+);
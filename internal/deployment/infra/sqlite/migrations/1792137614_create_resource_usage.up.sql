@@ -0,0 +1,16 @@
+-- No foreign key here since containers of a deleted app may still be running when
+-- stats are collected, rows are removed once they are older than the retention.
+CREATE TABLE resource_usage (
+    app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,service TEXT NOT NULL
+    ,collected_at DATETIME NOT NULL
+    ,cpu REAL NOT NULL
+    ,memory INTEGER NOT NULL
+    ,memory_limit INTEGER NOT NULL
+    ,network_rx INTEGER NOT NULL
+    ,network_tx INTEGER NOT NULL
+    ,CONSTRAINT pk_resource_usage PRIMARY KEY(app_id, environment, service, collected_at)
+);
+
+CREATE INDEX idx_resource_usage_collected_at ON resource_usage(collected_at);
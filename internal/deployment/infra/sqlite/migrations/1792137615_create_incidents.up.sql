@@ -0,0 +1,14 @@
+CREATE TABLE incidents (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,deployment_number INTEGER NOT NULL
+    ,service TEXT NOT NULL
+    ,container TEXT NOT NULL
+    ,kind TEXT NOT NULL
+    ,exit_code INTEGER NULL
+    ,occurred_at DATETIME NOT NULL
+    ,CONSTRAINT pk_incidents PRIMARY KEY(id)
+    ,CONSTRAINT fk_incidents_deployment FOREIGN KEY(app_id, deployment_number) REFERENCES deployments(app_id, deployment_number) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incidents_app_id_occurred_at ON incidents(app_id, occurred_at);
@@ -0,0 +1,2 @@
+DROP TABLE monitor_checks;
+DROP TABLE monitors;
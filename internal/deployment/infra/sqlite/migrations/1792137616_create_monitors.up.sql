@@ -0,0 +1,30 @@
+CREATE TABLE monitors (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,interval INTEGER NOT NULL -- In seconds
+    ,status_code INTEGER NOT NULL
+    ,keyword TEXT NULL
+    ,status INTEGER NOT NULL
+    ,next_check_at DATETIME NOT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_monitors PRIMARY KEY(id)
+    ,CONSTRAINT unique_monitors_app_id_environment UNIQUE(app_id, environment)
+    ,CONSTRAINT fk_monitors_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_monitors_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_monitors_next_check_at ON monitors(next_check_at);
+
+-- Only the most recent checks of each monitor are kept.
+CREATE TABLE monitor_checks (
+    monitor_id TEXT NOT NULL
+    ,checked_at DATETIME NOT NULL
+    ,status INTEGER NOT NULL
+    ,status_code INTEGER NULL
+    ,response_time INTEGER NOT NULL -- In milliseconds
+    ,reason TEXT NULL
+    ,CONSTRAINT pk_monitor_checks PRIMARY KEY(monitor_id, checked_at)
+    ,CONSTRAINT fk_monitor_checks_monitor_id FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
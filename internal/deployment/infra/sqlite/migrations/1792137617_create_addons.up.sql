@@ -0,0 +1,22 @@
+CREATE TABLE addons (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,kind TEXT NOT NULL
+    ,variable TEXT NOT NULL -- Environment variable holding the connection string
+    ,username TEXT NOT NULL
+    ,password TEXT NOT NULL
+    ,target_id TEXT NOT NULL
+    ,state_status INTEGER NOT NULL
+    ,state_version DATETIME NOT NULL
+    ,state_errcode TEXT NULL
+    ,cleanup_requested_at DATETIME NULL
+    ,cleanup_requested_by TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_addons PRIMARY KEY(id)
+    ,CONSTRAINT unique_addons_app_id_environment_kind UNIQUE(app_id, environment, kind)
+    ,CONSTRAINT unique_addons_app_id_environment_variable UNIQUE(app_id, environment, variable)
+    ,CONSTRAINT fk_addons_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_addons_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE addon_backups;
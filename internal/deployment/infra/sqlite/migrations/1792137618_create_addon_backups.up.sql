@@ -0,0 +1,17 @@
+CREATE TABLE addon_backups (
+    id TEXT NOT NULL
+    ,addon_id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,status INTEGER NOT NULL
+    ,size INTEGER NOT NULL -- Size of the compressed dump in bytes
+    ,errcode TEXT NULL
+    ,restore_requested_at DATETIME NULL
+    ,restore_requested_by TEXT NULL
+    ,restore_finished_at DATETIME NULL
+    ,restore_errcode TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,CONSTRAINT pk_addon_backups PRIMARY KEY(id)
+    ,CONSTRAINT fk_addon_backups_addon_id FOREIGN KEY(addon_id) REFERENCES addons(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_addon_backups_addon_id_created_at ON addon_backups(addon_id, created_at);
@@ -0,0 +1 @@
+DROP TABLE env_revisions;
@@ -0,0 +1,33 @@
+CREATE TABLE env_revisions (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,vars TEXT NULL
+    ,changes TEXT NOT NULL -- Keys added, updated or removed by this revision
+    ,created_by TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,CONSTRAINT pk_env_revisions PRIMARY KEY(id)
+    ,CONSTRAINT fk_env_revisions_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_env_revisions_app_id_created_at ON env_revisions(app_id, created_at);
+
+-- Record current variables of existing apps so they could be restored later on.
+INSERT INTO env_revisions (id, app_id, environment, vars, changes, created_by, created_at)
+SELECT
+    app_id || '-' || environment
+    ,app_id
+    ,environment
+    ,vars
+    ,(
+        SELECT json_group_array(json_object('service', services.key, 'name', names.key, 'kind', 'added'))
+        FROM json_each(vars) services, json_each(services.value) names
+    )
+    ,NULL
+    ,datetime('now')
+FROM (
+    SELECT id AS app_id, 'production' AS environment, production_vars AS vars FROM apps
+    UNION ALL
+    SELECT id AS app_id, 'staging' AS environment, staging_vars AS vars FROM apps
+)
+WHERE vars IS NOT NULL AND vars NOT IN ('{}', 'null');
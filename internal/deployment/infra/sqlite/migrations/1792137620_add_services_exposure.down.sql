@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN config_exposure;
+ALTER TABLE apps DROP COLUMN staging_exposure;
+ALTER TABLE apps DROP COLUMN production_exposure;
@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD production_exposure TEXT NULL;
+ALTER TABLE apps ADD staging_exposure TEXT NULL;
+ALTER TABLE deployments ADD config_exposure TEXT NULL;
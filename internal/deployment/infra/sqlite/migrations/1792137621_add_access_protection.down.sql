@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN config_protection;
+ALTER TABLE apps DROP COLUMN staging_protection;
+ALTER TABLE apps DROP COLUMN production_protection;
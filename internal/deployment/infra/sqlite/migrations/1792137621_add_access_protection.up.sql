@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD production_protection TEXT NULL;
+ALTER TABLE apps ADD staging_protection TEXT NULL;
+ALTER TABLE deployments ADD config_protection TEXT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN config_proxy_rules;
+ALTER TABLE apps DROP COLUMN staging_proxy_rules;
+ALTER TABLE apps DROP COLUMN production_proxy_rules;
@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD production_proxy_rules TEXT NULL;
+ALTER TABLE apps ADD staging_proxy_rules TEXT NULL;
+ALTER TABLE deployments ADD config_proxy_rules TEXT NULL;
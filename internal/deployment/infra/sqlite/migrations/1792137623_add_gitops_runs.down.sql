@@ -0,0 +1 @@
+DROP TABLE gitops_runs;
@@ -0,0 +1,10 @@
+CREATE TABLE gitops_runs (
+    id TEXT NOT NULL
+    ,commit_hash TEXT NOT NULL
+    ,changes TEXT NOT NULL -- Targets and apps created, updated or deleted by this run
+    ,error TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,CONSTRAINT pk_gitops_runs PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_gitops_runs_created_at ON gitops_runs(created_at);
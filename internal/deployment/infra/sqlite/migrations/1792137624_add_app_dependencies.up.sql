@@ -0,0 +1 @@
+ALTER TABLE apps ADD dependencies TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1 @@
+ALTER TABLE targets ADD vars TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP TABLE saved_filters;
+
+ALTER TABLE deployments DROP COLUMN labels;
+ALTER TABLE apps DROP COLUMN labels;
@@ -0,0 +1,15 @@
+ALTER TABLE apps ADD labels TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE deployments ADD labels TEXT NOT NULL DEFAULT '[]';
+
+CREATE TABLE saved_filters (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,kind TEXT NOT NULL -- Which list the filter applies to, apps or deployments
+    ,criteria TEXT NOT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_saved_filters PRIMARY KEY(id)
+    ,CONSTRAINT fk_saved_filters_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_saved_filters_created_by ON saved_filters(created_by);
@@ -0,0 +1,43 @@
+CREATE TABLE deployments_history (
+    app_id TEXT NOT NULL
+    ,deployment_number INTEGER NOT NULL
+    ,environment TEXT NOT NULL
+    ,target_id TEXT NOT NULL -- No FK, metrics of a deleted target are kept with the apps deployed on it
+    ,succeeded BOOLEAN NOT NULL
+    ,requested_at DATETIME NOT NULL
+    ,started_at DATETIME NOT NULL
+    ,finished_at DATETIME NOT NULL
+    ,duration INTEGER NOT NULL -- All durations are in milliseconds
+    ,build_duration INTEGER NULL
+    ,deploy_duration INTEGER NULL
+    ,CONSTRAINT pk_deployments_history PRIMARY KEY(app_id, deployment_number)
+    ,CONSTRAINT fk_deployments_history_deployment FOREIGN KEY(app_id, deployment_number) REFERENCES deployments(app_id, deployment_number) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_deployments_history_app_id_finished_at ON deployments_history(app_id, finished_at);
+CREATE INDEX idx_deployments_history_target_id_finished_at ON deployments_history(target_id, finished_at);
+
+-- Deployments ended before the history existed do not have steps durations
+INSERT INTO deployments_history (
+    app_id
+    ,deployment_number
+    ,environment
+    ,target_id
+    ,succeeded
+    ,requested_at
+    ,started_at
+    ,finished_at
+    ,duration
+)
+SELECT
+    app_id
+    ,deployment_number
+    ,config_environment
+    ,config_target
+    ,state_status = 3
+    ,requested_at
+    ,COALESCE(state_started_at, state_finished_at)
+    ,state_finished_at
+    ,CAST((julianday(state_finished_at) - julianday(COALESCE(state_started_at, state_finished_at))) * 86400000 AS INTEGER)
+FROM deployments
+WHERE state_finished_at IS NOT NULL;
@@ -0,0 +1,20 @@
+CREATE TABLE deployment_transitions (
+    app_id TEXT NOT NULL
+    ,deployment_number INTEGER NOT NULL
+    ,state TEXT NOT NULL -- queued, a pipeline step, succeeded or failed
+    ,occurred_at DATETIME NOT NULL
+    ,CONSTRAINT fk_deployment_transitions_deployment FOREIGN KEY(app_id, deployment_number) REFERENCES deployments(app_id, deployment_number) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_deployment_transitions_deployment ON deployment_transitions(app_id, deployment_number, occurred_at);
+
+-- Only the known states of existing deployments could be rebuilt
+INSERT INTO deployment_transitions (app_id, deployment_number, state, occurred_at)
+SELECT app_id, deployment_number, 'queued', requested_at FROM deployments;
+
+INSERT INTO deployment_transitions (app_id, deployment_number, state, occurred_at)
+SELECT app_id, deployment_number, 'fetch', state_started_at FROM deployments WHERE state_started_at IS NOT NULL;
+
+INSERT INTO deployment_transitions (app_id, deployment_number, state, occurred_at)
+SELECT app_id, deployment_number, CASE state_status WHEN 3 THEN 'succeeded' ELSE 'failed' END, state_finished_at
+FROM deployments WHERE state_finished_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE deployments_history DROP COLUMN failure_reason;
+ALTER TABLE deployments DROP COLUMN state_failure_reason;
@@ -0,0 +1,21 @@
+ALTER TABLE deployments ADD state_failure_reason TEXT NULL;
+ALTER TABLE deployments_history ADD failure_reason TEXT NULL;
+
+-- Classify past failures from their error code when it is known to match a reason
+UPDATE deployments
+SET state_failure_reason = CASE
+    WHEN state_errcode LIKE 'git\_%' ESCAPE '\'
+        OR state_errcode LIKE '%archive%'
+        OR state_errcode IN ('app_retrieved_failed', 'write_compose_failed') THEN 'source_fetch_failed'
+    WHEN state_errcode IN ('compose_file_malformed', 'compose_file_open_failed') THEN 'compose_invalid'
+    ELSE 'unknown'
+END
+WHERE state_status = 2;
+
+UPDATE deployments_history
+SET failure_reason = (
+    SELECT d.state_failure_reason
+    FROM deployments d
+    WHERE d.app_id = deployments_history.app_id AND d.deployment_number = deployments_history.deployment_number
+)
+WHERE succeeded = 0;
@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN config_compose_override;
+ALTER TABLE apps DROP COLUMN staging_compose_override;
+ALTER TABLE apps DROP COLUMN production_compose_override;
@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD production_compose_override TEXT NULL;
+ALTER TABLE apps ADD staging_compose_override TEXT NULL;
+ALTER TABLE deployments ADD config_compose_override TEXT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN config_platforms;
+ALTER TABLE apps DROP COLUMN platforms;
+ALTER TABLE targets DROP COLUMN platform;
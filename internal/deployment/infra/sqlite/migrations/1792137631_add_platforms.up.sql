@@ -0,0 +1,3 @@
+ALTER TABLE targets ADD platform TEXT NULL;
+ALTER TABLE apps ADD platforms TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE deployments ADD config_platforms TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+ALTER TABLE deployments DROP COLUMN config_build_registry;
+ALTER TABLE apps DROP COLUMN build_registry_id;
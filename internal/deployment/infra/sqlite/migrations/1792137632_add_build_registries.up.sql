@@ -0,0 +1,2 @@
+ALTER TABLE apps ADD build_registry_id TEXT NULL;
+ALTER TABLE deployments ADD config_build_registry TEXT NULL;
@@ -0,0 +1 @@
+ALTER TABLE targets ADD proxy_version TEXT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE deployments DROP COLUMN config_static_site;
+ALTER TABLE apps DROP COLUMN static_site;
@@ -0,0 +1,2 @@
+ALTER TABLE apps ADD static_site TEXT NULL;
+ALTER TABLE deployments ADD config_static_site TEXT NULL;
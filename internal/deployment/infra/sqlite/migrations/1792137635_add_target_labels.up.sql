@@ -0,0 +1 @@
+ALTER TABLE targets ADD labels TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1 @@
+DROP TABLE app_archives;
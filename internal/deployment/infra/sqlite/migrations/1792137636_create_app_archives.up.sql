@@ -0,0 +1,14 @@
+CREATE TABLE app_archives (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL -- Not a foreign key since archives outlive their app
+    ,app_name TEXT NOT NULL
+    ,status INTEGER NOT NULL
+    ,size INTEGER NOT NULL -- Size of the compressed tarball in bytes
+    ,errcode TEXT NULL
+    ,expires_at DATETIME NULL
+    ,requested_at DATETIME NOT NULL
+    ,requested_by TEXT NOT NULL
+    ,CONSTRAINT pk_app_archives PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_app_archives_expires_at ON app_archives(expires_at);
@@ -0,0 +1 @@
+DROP TABLE trashed_apps;
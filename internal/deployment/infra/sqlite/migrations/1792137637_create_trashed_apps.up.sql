@@ -0,0 +1,34 @@
+CREATE TABLE trashed_apps (
+    id TEXT NOT NULL -- Identifier of the deleted app
+    ,name TEXT NOT NULL
+    ,version_control_url TEXT NULL
+    ,version_control_token TEXT NULL
+    ,production_target TEXT NOT NULL -- Not a foreign key since targets may be deleted in the meantime
+    ,production_version DATETIME NOT NULL
+    ,production_vars TEXT NULL
+    ,production_exposure TEXT NULL
+    ,production_protection TEXT NULL
+    ,production_proxy_rules TEXT NULL
+    ,production_compose_override TEXT NULL
+    ,staging_target TEXT NOT NULL
+    ,staging_version DATETIME NOT NULL
+    ,staging_vars TEXT NULL
+    ,staging_exposure TEXT NULL
+    ,staging_protection TEXT NULL
+    ,staging_proxy_rules TEXT NULL
+    ,staging_compose_override TEXT NULL
+    ,labels TEXT NOT NULL DEFAULT '[]'
+    ,production_source_discriminator TEXT NULL -- Source of the last successful production deployment
+    ,production_source TEXT NULL
+    ,production_deployment_labels TEXT NOT NULL DEFAULT '[]'
+    ,staging_source_discriminator TEXT NULL -- Source of the last successful staging deployment
+    ,staging_source TEXT NULL
+    ,staging_deployment_labels TEXT NOT NULL DEFAULT '[]'
+    ,created_by TEXT NOT NULL
+    ,deleted_at DATETIME NOT NULL
+    ,deleted_by TEXT NOT NULL
+    ,expires_at DATETIME NOT NULL
+    ,CONSTRAINT pk_trashed_apps PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_trashed_apps_expires_at ON trashed_apps(expires_at);
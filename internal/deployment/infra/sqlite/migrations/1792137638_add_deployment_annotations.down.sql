@@ -0,0 +1,2 @@
+ALTER TABLE deployments DROP COLUMN metadata;
+ALTER TABLE deployments DROP COLUMN note;
@@ -0,0 +1,2 @@
+ALTER TABLE deployments ADD note TEXT NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD metadata TEXT NOT NULL DEFAULT '{}';
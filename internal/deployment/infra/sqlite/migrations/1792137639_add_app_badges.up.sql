@@ -0,0 +1 @@
+ALTER TABLE apps ADD badge_key TEXT NULL;
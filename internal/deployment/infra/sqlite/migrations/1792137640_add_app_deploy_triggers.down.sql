@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN deploy_trigger_by;
+ALTER TABLE apps DROP COLUMN deploy_trigger_key;
+ALTER TABLE apps DROP COLUMN deploy_trigger_kind;
@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD deploy_trigger_kind TEXT NULL;
+ALTER TABLE apps ADD deploy_trigger_key TEXT NULL;
+ALTER TABLE apps ADD deploy_trigger_by TEXT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE deployments DROP COLUMN unfrozen_by;
+ALTER TABLE deployments DROP COLUMN unfrozen_at;
+ALTER TABLE apps DROP COLUMN freeze_windows;
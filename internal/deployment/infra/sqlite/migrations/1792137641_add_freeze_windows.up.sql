@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD freeze_windows TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE deployments ADD unfrozen_at DATETIME NULL;
+ALTER TABLE deployments ADD unfrozen_by TEXT NULL;
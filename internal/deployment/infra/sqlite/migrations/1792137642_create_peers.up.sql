@@ -0,0 +1,10 @@
+CREATE TABLE peers (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,url TEXT NOT NULL
+    ,token TEXT NOT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_peers PRIMARY KEY(id)
+    ,CONSTRAINT fk_peers_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
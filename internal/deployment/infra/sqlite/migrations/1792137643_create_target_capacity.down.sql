@@ -0,0 +1 @@
+DROP TABLE target_capacity;
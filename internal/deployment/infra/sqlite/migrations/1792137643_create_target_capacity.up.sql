@@ -0,0 +1,10 @@
+CREATE TABLE target_capacity (
+    target_id TEXT NOT NULL
+    ,running_containers INTEGER NOT NULL
+    ,images_size INTEGER NOT NULL
+    ,disk_free INTEGER NOT NULL
+    ,disk_total INTEGER NOT NULL
+    ,collected_at DATETIME NOT NULL
+    ,CONSTRAINT pk_target_capacity PRIMARY KEY(target_id)
+    ,CONSTRAINT fk_target_capacity_target_id FOREIGN KEY(target_id) REFERENCES targets(id) ON DELETE CASCADE
+);
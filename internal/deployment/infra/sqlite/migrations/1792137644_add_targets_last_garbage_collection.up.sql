@@ -0,0 +1 @@
+ALTER TABLE targets ADD last_garbage_collection TEXT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE dns_records;
+DROP TABLE dns_zones;
@@ -0,0 +1,26 @@
+CREATE TABLE dns_zones (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,provider TEXT NOT NULL
+    ,credentials TEXT NOT NULL
+    ,addresses TEXT NOT NULL DEFAULT '[]'
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_dns_zones PRIMARY KEY(id)
+    ,CONSTRAINT unique_dns_zones_name UNIQUE(name)
+    ,CONSTRAINT fk_dns_zones_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE dns_records (
+    host TEXT NOT NULL
+    ,zone_id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,records TEXT NOT NULL
+    ,status TEXT NOT NULL
+    ,error TEXT NULL
+    ,drifted_at DATETIME NULL
+    ,checked_at DATETIME NOT NULL
+    ,CONSTRAINT pk_dns_records PRIMARY KEY(host)
+    ,CONSTRAINT fk_dns_records_zone_id FOREIGN KEY(zone_id) REFERENCES dns_zones(id) ON DELETE CASCADE
+);
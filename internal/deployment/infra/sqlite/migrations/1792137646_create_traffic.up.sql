@@ -0,0 +1,14 @@
+-- Like the resource usage, no foreign key here since requests may still be served to
+-- a deleted app when they are collected, rows are removed once older than the retention.
+CREATE TABLE traffic (
+    app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,service TEXT NOT NULL
+    ,collected_at DATETIME NOT NULL
+    ,requests INTEGER NOT NULL
+    ,statuses TEXT NOT NULL
+    ,latencies TEXT NOT NULL
+    ,CONSTRAINT pk_traffic PRIMARY KEY(app_id, environment, service, collected_at)
+);
+
+CREATE INDEX idx_traffic_collected_at ON traffic(collected_at);
@@ -0,0 +1,5 @@
+ALTER TABLE trashed_apps DROP COLUMN staging_url_aliases;
+ALTER TABLE trashed_apps DROP COLUMN production_url_aliases;
+ALTER TABLE deployments DROP COLUMN config_url_aliases;
+ALTER TABLE apps DROP COLUMN staging_url_aliases;
+ALTER TABLE apps DROP COLUMN production_url_aliases;
@@ -0,0 +1,5 @@
+ALTER TABLE trashed_apps DROP COLUMN staging_scale;
+ALTER TABLE trashed_apps DROP COLUMN production_scale;
+ALTER TABLE deployments DROP COLUMN config_scale;
+ALTER TABLE apps DROP COLUMN staging_scale;
+ALTER TABLE apps DROP COLUMN production_scale;
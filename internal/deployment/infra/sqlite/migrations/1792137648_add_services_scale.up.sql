@@ -0,0 +1,5 @@
+ALTER TABLE apps ADD production_scale TEXT NULL;
+ALTER TABLE apps ADD staging_scale TEXT NULL;
+ALTER TABLE deployments ADD config_scale TEXT NULL;
+ALTER TABLE trashed_apps ADD production_scale TEXT NULL;
+ALTER TABLE trashed_apps ADD staging_scale TEXT NULL;
@@ -0,0 +1 @@
+ALTER TABLE apps ADD timeouts TEXT NOT NULL DEFAULT '{}';
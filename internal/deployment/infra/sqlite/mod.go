@@ -10,3 +10,22 @@ import (
 var migrations embed.FS
 
 var Migrations = sqlite.NewMigrationsModule("deployment", "migrations", migrations)
+
+// Columns holding secrets, such as credentials and environment variables, encrypted at rest.
+var EncryptedColumns = []sqlite.EncryptedColumn{
+	{Table: "apps", Column: "version_control_token"},
+	{Table: "apps", Column: "production_vars"},
+	{Table: "apps", Column: "staging_vars"},
+	{Table: "apps", Column: "deploy_trigger_key"},
+	{Table: "trashed_apps", Column: "version_control_token"},
+	{Table: "trashed_apps", Column: "production_vars"},
+	{Table: "trashed_apps", Column: "staging_vars"},
+	{Table: "deployments", Column: "config_vars"},
+	{Table: "env_revisions", Column: "vars"},
+	{Table: "targets", Column: "provider"},
+	{Table: "targets", Column: "vars"},
+	{Table: "registries", Column: "credentials_password"},
+	{Table: "addons", Column: "password"},
+	{Table: "peers", Column: "token"},
+	{Table: "dns_zones", Column: "credentials"},
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+const monitorHistorySize = 100 // How many checks are kept for each monitor
+
+type (
+	MonitorsStore interface {
+		domain.MonitorsReader
+		domain.MonitorsWriter
+	}
+
+	monitorsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewMonitorsStore(db *sqlite.Database) MonitorsStore {
+	return &monitorsStore{db}
+}
+
+func (s *monitorsStore) GetByID(ctx context.Context, id domain.MonitorID) (domain.Monitor, error) {
+	return builder.
+		Query[domain.Monitor](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,interval
+			,status_code
+			,keyword
+			,status
+			,next_check_at
+			,created_at
+			,created_by
+		FROM monitors
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.MonitorFrom)
+}
+
+func (s *monitorsStore) GetByApp(ctx context.Context, app domain.AppID, env domain.Environment) (domain.Monitor, error) {
+	return builder.
+		Query[domain.Monitor](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,interval
+			,status_code
+			,keyword
+			,status
+			,next_check_at
+			,created_at
+			,created_by
+		FROM monitors
+		WHERE app_id = ? AND environment = ?`, app, env).
+		One(s.db, ctx, domain.MonitorFrom)
+}
+
+func (s *monitorsStore) GetDue(ctx context.Context, at time.Time) ([]domain.Monitor, error) {
+	return builder.
+		Query[domain.Monitor](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,interval
+			,status_code
+			,keyword
+			,status
+			,next_check_at
+			,created_at
+			,created_by
+		FROM monitors
+		WHERE next_check_at <= ?
+		ORDER BY next_check_at`, at).
+		All(s.db, ctx, domain.MonitorFrom)
+}
+
+func (s *monitorsStore) Write(ctx context.Context, monitors ...*domain.Monitor) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, monitors, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.MonitorCreated:
+			return builder.
+				Insert("monitors", builder.Values{
+					"id":            evt.ID,
+					"app_id":        evt.AppID,
+					"environment":   evt.Environment,
+					"interval":      int64(evt.Check.Interval() / time.Second),
+					"status_code":   evt.Check.StatusCode(),
+					"keyword":       evt.Check.Keyword(),
+					"status":        domain.MonitorStatusUnknown,
+					"next_check_at": evt.NextCheckAt,
+					"created_at":    evt.Created.At(),
+					"created_by":    evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.MonitorCheckChanged:
+			return builder.
+				Update("monitors", builder.Values{
+					"interval":      int64(evt.Check.Interval() / time.Second),
+					"status_code":   evt.Check.StatusCode(),
+					"keyword":       evt.Check.Keyword(),
+					"next_check_at": evt.NextCheckAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.MonitorChecked:
+			if err := builder.
+				Update("monitors", builder.Values{
+					"status":        evt.Status,
+					"next_check_at": evt.NextCheckAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			if err := builder.
+				Insert("monitor_checks", builder.Values{
+					"monitor_id":    evt.ID,
+					"checked_at":    evt.CheckedAt,
+					"status":        evt.Status,
+					"status_code":   evt.StatusCode,
+					"response_time": evt.ResponseTime.Milliseconds(),
+					"reason":        evt.Reason,
+				}).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			return builder.
+				Command(`
+				DELETE FROM monitor_checks
+				WHERE monitor_id = ? AND checked_at NOT IN (
+					SELECT checked_at FROM monitor_checks
+					WHERE monitor_id = ?
+					ORDER BY checked_at DESC
+					LIMIT ?
+				)`, evt.ID, evt.ID, monitorHistorySize).
+				Exec(s.db, ctx)
+		case domain.MonitorDeleted:
+			return builder.
+				Command("DELETE FROM monitors WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
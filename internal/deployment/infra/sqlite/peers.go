@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	PeersStore interface {
+		domain.PeersReader
+		domain.PeersWriter
+	}
+
+	peersStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewPeersStore(db *sqlite.Database) PeersStore {
+	return &peersStore{db}
+}
+
+func (s *peersStore) GetByID(ctx context.Context, id domain.PeerID) (domain.Peer, error) {
+	return builder.
+		Query[domain.Peer](`
+		SELECT
+			id
+			,name
+			,url
+			,token
+			,created_at
+			,created_by
+		FROM peers
+		WHERE id = ?`, id).
+		Decrypt("token").
+		One(s.db, ctx, domain.PeerFrom)
+}
+
+func (s *peersStore) GetAll(ctx context.Context) ([]domain.Peer, error) {
+	return builder.
+		Query[domain.Peer](`
+		SELECT
+			id
+			,name
+			,url
+			,token
+			,created_at
+			,created_by
+		FROM peers
+		ORDER BY name`).
+		Decrypt("token").
+		All(s.db, ctx, domain.PeerFrom)
+}
+
+func (s *peersStore) Write(ctx context.Context, peers ...*domain.Peer) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, peers, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.PeerCreated:
+			return builder.
+				Insert("peers", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"url":        evt.Url,
+					"token":      s.db.Encrypted(evt.Token),
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.PeerRenamed:
+			return builder.
+				Update("peers", builder.Values{
+					"name": evt.Name,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.PeerUrlChanged:
+			return builder.
+				Update("peers", builder.Values{
+					"url": evt.Url,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.PeerTokenChanged:
+			return builder.
+				Update("peers", builder.Values{
+					"token": s.db.Encrypted(evt.Token),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.PeerDeleted:
+			return builder.
+				Command("DELETE FROM peers WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -47,6 +47,7 @@ func (s *registriesStore) GetByID(ctx context.Context, id domain.RegistryID) (do
 			,created_by
 		FROM registries
 		WHERE id = ?`, id).
+		Decrypt("credentials_password").
 		One(s.db, ctx, domain.RegistryFrom)
 }
 
@@ -62,6 +63,7 @@ func (s *registriesStore) GetAll(ctx context.Context) ([]domain.Registry, error)
 			,created_at
 			,created_by
 		FROM registries`).
+		Decrypt("credentials_password").
 		All(s.db, ctx, domain.RegistryFrom)
 }
 
@@ -96,7 +98,7 @@ func (s *registriesStore) Write(ctx context.Context, registries ...*domain.Regis
 			return builder.
 				Update("registries", builder.Values{
 					"credentials_username": evt.Credentials.Username(),
-					"credentials_password": evt.Credentials.Password(),
+					"credentials_password": s.db.Encrypted(evt.Credentials.Password()),
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type resourceUsageStore struct {
+	db *sqlite.Database
+}
+
+func NewResourceUsageStore(db *sqlite.Database) domain.ResourceUsageWriter {
+	return &resourceUsageStore{db}
+}
+
+func (s *resourceUsageStore) WriteResourceUsage(ctx context.Context, collectedAt time.Time, stats []domain.ServiceStats) (finalErr error) {
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	for _, stat := range stats {
+		if finalErr = builder.Insert("resource_usage", builder.Values{
+			"app_id":       stat.AppID,
+			"environment":  stat.Environment,
+			"service":      stat.Service,
+			"collected_at": collectedAt,
+			"cpu":          stat.CPU,
+			"memory":       stat.Memory,
+			"memory_limit": stat.MemoryLimit,
+			"network_rx":   stat.NetworkRx,
+			"network_tx":   stat.NetworkTx,
+		}).Exec(s.db, ctx); finalErr != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *resourceUsageStore) PruneResourceUsage(ctx context.Context, before time.Time) error {
+	return builder.
+		Command("DELETE FROM resource_usage WHERE collected_at < ?", before).
+		Exec(s.db, ctx)
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	SavedFiltersStore interface {
+		domain.SavedFiltersReader
+		domain.SavedFiltersWriter
+	}
+
+	savedFiltersStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewSavedFiltersStore(db *sqlite.Database) SavedFiltersStore {
+	return &savedFiltersStore{db}
+}
+
+func (s *savedFiltersStore) GetByID(ctx context.Context, id domain.SavedFilterID) (domain.SavedFilter, error) {
+	return builder.
+		Query[domain.SavedFilter](`
+		SELECT
+			id
+			,name
+			,kind
+			,criteria
+			,created_at
+			,created_by
+		FROM saved_filters
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.SavedFilterFrom)
+}
+
+func (s *savedFiltersStore) Write(ctx context.Context, filters ...*domain.SavedFilter) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, filters, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.SavedFilterCreated:
+			return builder.
+				Insert("saved_filters", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"kind":       evt.Kind,
+					"criteria":   evt.Criteria,
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.SavedFilterDeleted:
+			return builder.
+				Command("DELETE FROM saved_filters WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
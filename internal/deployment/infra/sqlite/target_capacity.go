@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type targetCapacityStore struct {
+	db *sqlite.Database
+}
+
+func NewTargetCapacityStore(db *sqlite.Database) domain.TargetCapacityWriter {
+	return &targetCapacityStore{db}
+}
+
+func (s *targetCapacityStore) WriteTargetCapacity(ctx context.Context, id domain.TargetID, collectedAt time.Time, capacity domain.TargetCapacity) error {
+	return builder.
+		Insert("target_capacity", builder.Values{
+			"target_id":          id,
+			"running_containers": capacity.RunningContainers,
+			"images_size":        capacity.ImagesSize,
+			"disk_free":          capacity.DiskFree,
+			"disk_total":         capacity.DiskTotal,
+			"collected_at":       collectedAt,
+		}).
+		F(`ON CONFLICT(target_id) DO UPDATE SET
+			running_containers = excluded.running_containers
+			,images_size = excluded.images_size
+			,disk_free = excluded.disk_free
+			,disk_total = excluded.disk_total
+			,collected_at = excluded.collected_at`).
+		Exec(s.db, ctx)
+}
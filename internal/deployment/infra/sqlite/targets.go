@@ -2,9 +2,12 @@ package sqlite
 
 import (
 	"context"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
 )
@@ -13,11 +16,18 @@ type (
 	TargetsStore interface {
 		domain.TargetsReader
 		domain.TargetsWriter
+		GetConfiguring(context.Context) ([]domain.Target, error)
 	}
 
 	targetsStore struct {
 		db *sqlite.Database
 	}
+
+	targetCandidateRow struct {
+		ID         domain.TargetID
+		Labels     domain.Labels
+		AssignedAt monad.Maybe[time.Time] // Creation of an app environment using the target, if any
+	}
 )
 
 func NewTargetsStore(db *sqlite.Database) TargetsStore {
@@ -58,13 +68,20 @@ func (s *targetsStore) GetLocalTarget(ctx context.Context) (domain.Target, error
 			,state_errcode
 			,state_last_ready_version
 			,entrypoints
+			,vars
+			,platform
+			,proxy_version
+			,last_garbage_collection
+			,labels
 			,cleanup_requested_at
 			,cleanup_requested_by
 			,created_at
 			,created_by
+			,version
 		FROM targets
 		WHERE provider_fingerprint = ''
 		LIMIT 1`).
+		Decrypt("provider", "vars").
 		One(s.db, ctx, domain.TargetFrom)
 }
 
@@ -82,17 +99,144 @@ func (s *targetsStore) GetByID(ctx context.Context, id domain.TargetID) (domain.
 			,state_errcode
 			,state_last_ready_version
 			,entrypoints
+			,vars
+			,platform
+			,proxy_version
+			,last_garbage_collection
+			,labels
 			,cleanup_requested_at
 			,cleanup_requested_by
 			,created_at
 			,created_by
+			,version
 		FROM targets
 		WHERE id = ?`, id).
+		Decrypt("provider", "vars").
 		One(s.db, ctx, domain.TargetFrom)
 }
 
+// Retrieve targets waiting for a configuration.
+func (s *targetsStore) GetConfiguring(ctx context.Context) ([]domain.Target, error) {
+	return builder.
+		Query[domain.Target](`
+		SELECT
+			id
+			,name
+			,url
+			,provider_kind
+			,provider
+			,state_status
+			,state_version
+			,state_errcode
+			,state_last_ready_version
+			,entrypoints
+			,vars
+			,platform
+			,proxy_version
+			,last_garbage_collection
+			,labels
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+			,version
+		FROM targets
+		WHERE state_status = ?`, domain.TargetStatusConfiguring).
+		Decrypt("provider", "vars").
+		All(s.db, ctx, domain.TargetFrom)
+}
+
+func (s *targetsStore) GetReady(ctx context.Context) ([]domain.Target, error) {
+	return builder.
+		Query[domain.Target](`
+		SELECT
+			id
+			,name
+			,url
+			,provider_kind
+			,provider
+			,state_status
+			,state_version
+			,state_errcode
+			,state_last_ready_version
+			,entrypoints
+			,vars
+			,platform
+			,proxy_version
+			,last_garbage_collection
+			,labels
+			,cleanup_requested_at
+			,cleanup_requested_by
+			,created_at
+			,created_by
+			,version
+		FROM targets
+		WHERE state_status = ? AND cleanup_requested_at IS NULL`, domain.TargetStatusReady).
+		Decrypt("provider", "vars").
+		All(s.db, ctx, domain.TargetFrom)
+}
+
+// Retrieve ready targets with the app environments using them, from the apps table
+// since an app environment may not have been deployed yet. Rows are aggregated here
+// because SQLite aggregates lose the column type and times would be scanned as strings.
+func (s *targetsStore) GetCandidates(ctx context.Context) ([]domain.TargetCandidate, error) {
+	rows, err := builder.
+		Query[targetCandidateRow](`
+		SELECT
+			targets.id
+			,targets.labels
+			,envs.assigned_at
+		FROM targets
+		LEFT JOIN (
+			SELECT production_target AS target, production_version AS assigned_at FROM apps
+			UNION ALL
+			SELECT staging_target AS target, staging_version AS assigned_at FROM apps
+		) envs ON envs.target = targets.id
+		WHERE targets.state_status = ? AND targets.cleanup_requested_at IS NULL
+		ORDER BY targets.id`, domain.TargetStatusReady).
+		All(s.db, ctx, func(scanner storage.Scanner) (r targetCandidateRow, err error) {
+			err = scanner.Scan(
+				&r.ID,
+				&r.Labels,
+				&r.AssignedAt,
+			)
+
+			return r, err
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.TargetCandidate
+
+	for _, row := range rows {
+		if len(candidates) == 0 || candidates[len(candidates)-1].ID != row.ID {
+			candidates = append(candidates, domain.TargetCandidate{
+				ID:     row.ID,
+				Labels: row.Labels,
+			})
+		}
+
+		assignedAt, isSet := row.AssignedAt.TryGet()
+
+		if !isSet {
+			continue
+		}
+
+		candidate := &candidates[len(candidates)-1]
+		candidate.Apps++
+
+		if last, hasLast := candidate.LastAssignedAt.TryGet(); !hasLast || assignedAt.After(last) {
+			candidate.LastAssignedAt.Set(assignedAt)
+		}
+	}
+
+	return candidates, nil
+}
+
 func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error {
-	return sqlite.WriteAndDispatch(s.db, c, targets, func(ctx context.Context, e event.Event) error {
+	return sqlite.WriteVersionedAndDispatch(s.db, c, "targets", targets, targetKey, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.TargetCreated:
 			return builder.
@@ -102,7 +246,7 @@ func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error
 					"url":                      evt.Url,
 					"provider_kind":            evt.Provider.Kind(),
 					"provider_fingerprint":     evt.Provider.Fingerprint(),
-					"provider":                 evt.Provider,
+					"provider":                 s.db.Encrypted(evt.Provider),
 					"state_status":             evt.State.Status(),
 					"state_version":            evt.State.Version(),
 					"state_errcode":            evt.State.ErrCode(),
@@ -141,7 +285,7 @@ func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error
 				Update("targets", builder.Values{
 					"provider_kind":        evt.Provider.Kind(),
 					"provider_fingerprint": evt.Provider.Fingerprint(),
-					"provider":             evt.Provider,
+					"provider":             s.db.Encrypted(evt.Provider),
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
@@ -152,6 +296,41 @@ func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
+		case domain.TargetVarsChanged:
+			return builder.
+				Update("targets", builder.Values{
+					"vars": s.db.Encrypted(evt.Vars),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TargetLabelsChanged:
+			return builder.
+				Update("targets", builder.Values{
+					"labels": evt.Labels,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TargetPlatformChanged:
+			return builder.
+				Update("targets", builder.Values{
+					"platform": evt.Platform,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TargetProxyVersionChanged:
+			return builder.
+				Update("targets", builder.Values{
+					"proxy_version": evt.Version,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TargetGarbageCollected:
+			return builder.
+				Update("targets", builder.Values{
+					"last_garbage_collection": evt.Report,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
 		case domain.TargetCleanupRequested:
 			return builder.
 				Update("targets", builder.Values{
@@ -169,3 +348,7 @@ func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error
 		}
 	})
 }
+
+func targetKey(t *domain.Target) (string, []any) {
+	return "id = ?", []any{t.ID()}
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	TeamsStore interface {
+		domain.TeamsReader
+		domain.TeamsWriter
+	}
+
+	teamsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewTeamsStore(db *sqlite.Database) TeamsStore {
+	return &teamsStore{db}
+}
+
+func (s *teamsStore) GetByID(ctx context.Context, id domain.TeamID) (domain.Team, error) {
+	return builder.
+		Query[domain.Team](`
+		SELECT
+			id
+			,name
+			,created_at
+			,created_by
+		FROM teams
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.TeamFrom)
+}
+
+func (s *teamsStore) Write(ctx context.Context, teams ...*domain.Team) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, teams, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.TeamCreated:
+			return builder.
+				Insert("teams", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.TeamRenamed:
+			return builder.
+				Update("teams", builder.Values{
+					"name": evt.Name,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TeamDeleted:
+			return builder.
+				Command("DELETE FROM teams WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type trafficStore struct {
+	db *sqlite.Database
+}
+
+func NewTrafficStore(db *sqlite.Database) domain.TrafficWriter {
+	return &trafficStore{db}
+}
+
+func (s *trafficStore) WriteTraffic(ctx context.Context, collectedAt time.Time, samples []domain.TrafficSample) (finalErr error) {
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	for _, sample := range samples {
+		if finalErr = builder.Insert("traffic", builder.Values{
+			"app_id":       sample.AppID,
+			"environment":  sample.Environment,
+			"service":      sample.Service,
+			"collected_at": collectedAt,
+			"requests":     sample.Requests,
+			"statuses":     sample.Statuses,
+			"latencies":    sample.Latencies,
+		}).Exec(s.db, ctx); finalErr != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *trafficStore) PruneTraffic(ctx context.Context, before time.Time) error {
+	return builder.
+		Command("DELETE FROM traffic WHERE collected_at < ?", before).
+		Exec(s.db, ctx)
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	TrashedAppsStore interface {
+		domain.TrashedAppsReader
+		domain.TrashedAppsWriter
+	}
+
+	trashedAppsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewTrashedAppsStore(db *sqlite.Database) TrashedAppsStore {
+	return &trashedAppsStore{db}
+}
+
+func (s *trashedAppsStore) GetByID(ctx context.Context, id domain.AppID) (domain.TrashedApp, error) {
+	return builder.
+		Query[domain.TrashedApp](trashedAppsSelect+`
+		WHERE id = ?`, id).
+		Decrypt("version_control_token", "production_vars", "staging_vars").
+		One(s.db, ctx, domain.TrashedAppFrom)
+}
+
+func (s *trashedAppsStore) GetExpired(ctx context.Context, at time.Time) ([]domain.TrashedApp, error) {
+	return builder.
+		Query[domain.TrashedApp](trashedAppsSelect+`
+		WHERE expires_at <= ?`, at).
+		Decrypt("version_control_token", "production_vars", "staging_vars").
+		All(s.db, ctx, domain.TrashedAppFrom)
+}
+
+func (s *trashedAppsStore) Write(ctx context.Context, apps ...*domain.TrashedApp) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, apps, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.TrashedAppCreated:
+			values := builder.Values{
+				"id":                           evt.ID,
+				"name":                         evt.Name,
+				"production_target":            evt.Production.Target(),
+				"production_version":           evt.Production.Version(),
+				"production_vars":              s.db.Encrypted(evt.Production.Vars()),
+				"production_exposure":          evt.Production.Exposure(),
+				"production_protection":        evt.Production.Protection(),
+				"production_proxy_rules":       evt.Production.ProxyRules(),
+				"production_compose_override":  evt.Production.ComposeOverride(),
+				"production_url_aliases":       evt.Production.UrlAliases(),
+				"production_scale":             evt.Production.ServicesScale(),
+				"staging_target":               evt.Staging.Target(),
+				"staging_version":              evt.Staging.Version(),
+				"staging_vars":                 s.db.Encrypted(evt.Staging.Vars()),
+				"staging_exposure":             evt.Staging.Exposure(),
+				"staging_protection":           evt.Staging.Protection(),
+				"staging_proxy_rules":          evt.Staging.ProxyRules(),
+				"staging_compose_override":     evt.Staging.ComposeOverride(),
+				"staging_url_aliases":          evt.Staging.UrlAliases(),
+				"staging_scale":                evt.Staging.ServicesScale(),
+				"labels":                       evt.Labels,
+				"production_deployment_labels": domain.Labels{},
+				"staging_deployment_labels":    domain.Labels{},
+				"created_by":                   evt.CreatedBy,
+				"deleted_at":                   evt.Deleted.At(),
+				"deleted_by":                   evt.Deleted.By(),
+				"expires_at":                   evt.ExpiresAt,
+			}
+
+			if vcs, isSet := evt.VersionControl.TryGet(); isSet {
+				values["version_control_url"] = vcs.Url()
+				values["version_control_token"] = s.db.Encrypted(vcs.Token())
+			}
+
+			// This is safe to interpolate the column name here since environments are
+			// validated by our own code.
+			for _, depl := range evt.Deployments {
+				values[string(depl.Environment())+"_source_discriminator"] = depl.Source().Kind()
+				values[string(depl.Environment())+"_source"] = depl.Source()
+				values[string(depl.Environment())+"_deployment_labels"] = depl.Labels()
+			}
+
+			return builder.
+				Insert("trashed_apps", values).
+				Exec(s.db, ctx)
+		case domain.TrashedAppRestored:
+			return builder.
+				Command("DELETE FROM trashed_apps WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TrashedAppPurged:
+			return builder.
+				Command("DELETE FROM trashed_apps WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
+
+const trashedAppsSelect = `
+		SELECT
+			id
+			,name
+			,version_control_url
+			,version_control_token
+			,production_target
+			,production_version
+			,production_vars
+			,production_exposure
+			,production_protection
+			,production_proxy_rules
+			,production_compose_override
+			,production_url_aliases
+			,production_scale
+			,staging_target
+			,staging_version
+			,staging_vars
+			,staging_exposure
+			,staging_protection
+			,staging_proxy_rules
+			,staging_compose_override
+			,staging_url_aliases
+			,staging_scale
+			,labels
+			,production_source_discriminator
+			,production_source
+			,production_deployment_labels
+			,staging_source_discriminator
+			,staging_source
+			,staging_deployment_labels
+			,created_by
+			,deleted_at
+			,deleted_by
+			,expires_at
+		FROM trashed_apps`
@@ -0,0 +1,65 @@
+package uptime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const (
+	timeout     = 10 * time.Second
+	userAgent   = "seelf-uptime"
+	maxBodySize = 1 << 20 // Only the beginning of the body is searched for the keyword
+)
+
+type probe struct {
+	client *http.Client
+}
+
+// Builds a probe requesting public urls of apps with a GET request. Redirections are
+// followed so an app redirecting to https is considered up.
+func NewProbe() domain.UptimeProbe {
+	return &probe{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *probe) Probe(ctx context.Context, url domain.Url) (result domain.ProbeResult) {
+	result.Url = url
+	result.CheckedAt = time.Now().UTC()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+
+	if err != nil {
+		result.Error.Set(err.Error())
+		return result
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := p.client.Do(req)
+	result.ResponseTime = time.Since(result.CheckedAt)
+
+	if err != nil {
+		result.Error.Set(err.Error())
+		return result
+	}
+
+	defer resp.Body.Close()
+
+	result.StatusCode.Set(resp.StatusCode)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+
+	if err != nil {
+		result.Error.Set(err.Error())
+		return result
+	}
+
+	result.Body = string(body)
+
+	return result
+}
@@ -0,0 +1,48 @@
+package uptime_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/uptime"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Probe(t *testing.T) {
+	ctx := context.Background()
+	probe := uptime.NewProbe()
+
+	t.Run("should return the status code and body of the response", func(t *testing.T) {
+		var userAgent string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.UserAgent()
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("maintenance"))
+		}))
+		t.Cleanup(server.Close)
+
+		result := probe.Probe(ctx, must.Panic(domain.UrlFrom(server.URL)))
+
+		testutil.Equals(t, "seelf-uptime", userAgent)
+		testutil.IsFalse(t, result.Error.HasValue())
+		testutil.Equals(t, http.StatusServiceUnavailable, result.StatusCode.MustGet())
+		testutil.Equals(t, "maintenance", result.Body)
+		testutil.IsFalse(t, result.CheckedAt.IsZero())
+	})
+
+	t.Run("should return an error if the url could not be reached", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := must.Panic(domain.UrlFrom(server.URL))
+		server.Close()
+
+		result := probe.Probe(ctx, url)
+
+		testutil.IsTrue(t, result.Error.HasValue())
+		testutil.IsFalse(t, result.StatusCode.HasValue())
+	})
+}
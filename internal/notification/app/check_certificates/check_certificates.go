@@ -0,0 +1,79 @@
+package check_certificates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/app/send_email"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Check certificates of every host exposed over HTTPS and warn admins by email if
+// some of them will expire soon. Periodically sent by the server itself.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "notification.command.check_certificates" }
+
+func Handler(
+	hostsReader domain.ExposedHostsReader,
+	recipientsReader domain.RecipientsReader,
+	inspector domain.CertificatesInspector,
+	scheduler bus.Scheduler,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		hosts, err := hostsReader.GetExposedHosts(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var (
+			now      = time.Now()
+			expiring strings.Builder
+		)
+
+		for _, host := range hosts {
+			expiresAt, err := inspector.ExpiresAt(ctx, host)
+
+			// An unreachable host should not prevent other ones from being checked
+			if err != nil {
+				continue
+			}
+
+			if domain.IsCertificateExpiringSoon(expiresAt, now) {
+				fmt.Fprintf(&expiring, "- %s expires on %s\n", host, expiresAt.UTC().Format(time.RFC1123))
+			}
+		}
+
+		if expiring.Len() == 0 {
+			return bus.Unit, nil
+		}
+
+		recipients, err := recipientsReader.GetCertificateExpiryRecipients(ctx)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		body := "The following certificates will expire soon and have not been renewed yet:\n\n" +
+			expiring.String() +
+			"\nMake sure the targets serving them are reachable so they could be renewed.\n"
+
+		for _, to := range recipients {
+			if err = scheduler.Queue(ctx, send_email.Command{
+				To:      to,
+				Subject: "[seelf] Certificates expiring soon",
+				Body:    body,
+			}); err != nil {
+				return bus.Unit, err
+			}
+		}
+
+		return bus.Unit, nil
+	}
+}
@@ -0,0 +1,90 @@
+package check_certificates_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/check_certificates"
+	"github.com/YuukanOO/seelf/internal/notification/app/send_email"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_CheckCertificates(t *testing.T) {
+	ctx := context.Background()
+	store := &dummyStore{
+		hosts:  []string{"app.example.com", "api.example.com", "unreachable.example.com"},
+		admins: []string{"admin@example.com", "ops@example.com"},
+	}
+
+	t.Run("should not send anything if no certificate expires soon", func(t *testing.T) {
+		scheduler := &dummyScheduler{}
+		uc := check_certificates.Handler(store, store, dummyInspector{
+			"app.example.com": time.Now().Add(60 * 24 * time.Hour),
+			"api.example.com": time.Now().Add(30 * 24 * time.Hour),
+		}, scheduler)
+
+		_, err := uc(ctx, check_certificates.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, scheduler.queued, 0)
+	})
+
+	t.Run("should warn admins about expiring certificates", func(t *testing.T) {
+		scheduler := &dummyScheduler{}
+		uc := check_certificates.Handler(store, store, dummyInspector{
+			"app.example.com": time.Now().Add(60 * 24 * time.Hour),
+			"api.example.com": time.Now().Add(2 * 24 * time.Hour),
+		}, scheduler)
+
+		_, err := uc(ctx, check_certificates.Command{})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, scheduler.queued, 2)
+
+		email := scheduler.queued[0].(send_email.Command)
+		testutil.Equals(t, "admin@example.com", email.To)
+		testutil.Contains(t, "api.example.com", email.Body)
+		testutil.IsFalse(t, strings.Contains(email.Body, "app.example.com"))
+		testutil.Equals(t, "ops@example.com", scheduler.queued[1].(send_email.Command).To)
+	})
+}
+
+type (
+	dummyStore struct {
+		hosts  []string
+		admins []string
+	}
+
+	dummyInspector map[string]time.Time
+
+	dummyScheduler struct {
+		bus.Scheduler
+		queued []bus.Schedulable
+	}
+)
+
+func (s *dummyStore) GetExposedHosts(context.Context) ([]string, error) { return s.hosts, nil }
+func (s *dummyStore) GetCertificateExpiryRecipients(context.Context) ([]string, error) {
+	return s.admins, nil
+}
+func (s *dummyStore) GetDeploymentFailureRecipients(context.Context, deployment.DeploymentID) ([]string, error) {
+	return nil, nil
+}
+
+func (i dummyInspector) ExpiresAt(_ context.Context, host string) (time.Time, error) {
+	if expiresAt, found := i[host]; found {
+		return expiresAt, nil
+	}
+
+	return time.Time{}, errors.New("connection refused")
+}
+
+func (s *dummyScheduler) Queue(_ context.Context, msg bus.Schedulable, _ ...bus.JobOptions) error {
+	s.queued = append(s.queued, msg)
+	return nil
+}
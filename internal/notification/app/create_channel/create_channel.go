@@ -0,0 +1,88 @@
+package create_channel
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Register a new channel where deployment results will be posted. Without an app,
+// the channel is notified of every deployment.
+type Command struct {
+	bus.Command[string]
+
+	Name        string              `json:"name"`
+	Kind        string              `json:"kind"`
+	Url         string              `json:"url"`
+	RoomID      string              `json:"room_id"`
+	AccessToken string              `json:"access_token"`
+	AppID       monad.Maybe[string] `json:"app_id"`
+}
+
+func (Command) Name_() string { return "notification.command.create_channel" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	writer domain.ChannelsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var (
+			kind domain.ChannelKind
+			url  domain.Url
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Field(cmd.Name, strings.Required),
+			"kind": validate.Value(cmd.Kind, &kind, domain.ChannelKindFrom),
+			"url":  validate.Value(cmd.Url, &url, domain.UrlFrom),
+			"room_id": validate.If(cmd.Kind == string(domain.ChannelKindMatrix), func() error {
+				return validate.Field(cmd.RoomID, strings.Required)
+			}),
+			"access_token": validate.If(cmd.Kind == string(domain.ChannelKindMatrix), func() error {
+				return validate.Field(cmd.AccessToken, strings.Required)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		var config domain.ChannelConfig
+
+		switch kind {
+		case domain.ChannelKindSlack:
+			config = domain.SlackConfig(url)
+		case domain.ChannelKindDiscord:
+			config = domain.DiscordConfig(url)
+		case domain.ChannelKindMatrix:
+			config = domain.MatrixConfig(url, cmd.RoomID, cmd.AccessToken)
+		}
+
+		var app monad.Maybe[deployment.AppID]
+
+		if id, isSet := cmd.AppID.TryGet(); isSet {
+			app.Set(deployment.AppID(id))
+		}
+
+		channel := domain.NewChannel(cmd.Name, config, app, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &channel); err != nil {
+			return "", err
+		}
+
+		return string(channel.ID()), nil
+	}
+}
@@ -0,0 +1,86 @@
+package create_channel_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_channel"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreateChannel(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := create_channel.Handler(memory.NewChannelsStore())
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_channel.Command{
+			Name: "ops",
+			Kind: "slack",
+			Url:  "https://hooks.slack.com/services/xxx",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := create_channel.Handler(memory.NewChannelsStore())
+
+		id, err := uc(ctx, create_channel.Command{
+			Kind: "irc",
+			Url:  "not-an-url",
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["name"])
+		testutil.ErrorIs(t, domain.ErrInvalidChannelKind, validationErr["kind"])
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require a room and an access token for matrix channels", func(t *testing.T) {
+		uc := create_channel.Handler(memory.NewChannelsStore())
+
+		_, err := uc(ctx, create_channel.Command{
+			Name: "ops",
+			Kind: "matrix",
+			Url:  "https://matrix.org",
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["room_id"])
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["access_token"])
+	})
+
+	t.Run("should create a new channel", func(t *testing.T) {
+		store := memory.NewChannelsStore()
+		uc := create_channel.Handler(store)
+
+		id, err := uc(ctx, create_channel.Command{
+			Name:        "ops",
+			Kind:        "matrix",
+			Url:         "https://matrix.org",
+			RoomID:      "!room:matrix.org",
+			AccessToken: "token",
+			AppID:       monad.Value("app-id"),
+		})
+
+		testutil.IsNil(t, err)
+		channel := must.Panic(store.GetByID(ctx, domain.ChannelID(id)))
+		testutil.Equals(t, "ops", channel.Name())
+		testutil.Equals(t, domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "token"), channel.Config())
+		testutil.Equals(t, "app-id", channel.App().MustGet())
+	})
+}
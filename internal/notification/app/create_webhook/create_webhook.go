@@ -0,0 +1,61 @@
+package create_webhook
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Register a new webhook notified of the given events, every event if none is given.
+type Command struct {
+	bus.Command[string]
+
+	Name   string   `json:"name"`
+	Url    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+func (Command) Name_() string { return "notification.command.create_webhook" }
+
+func (Command) AuditResource(result any) string {
+	id, _ := result.(string)
+	return id
+}
+
+func Handler(
+	writer domain.WebhooksWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var (
+			url    domain.Url
+			events domain.EventFilter
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name":   validate.Field(cmd.Name, strings.Required),
+			"url":    validate.Value(cmd.Url, &url, domain.UrlFrom),
+			"secret": validate.Field(cmd.Secret, strings.Required),
+			"events": validate.Value(cmd.Events, &events, domain.EventFilterFrom),
+		}); err != nil {
+			return "", err
+		}
+
+		webhook := domain.NewWebhook(cmd.Name, url, cmd.Secret, events, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &webhook); err != nil {
+			return "", err
+		}
+
+		return string(webhook.ID()), nil
+	}
+}
@@ -0,0 +1,69 @@
+package create_webhook_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreateWebhook(t *testing.T) {
+	ctx := auth.WithUserID(context.Background(), "some-uid")
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := create_webhook.Handler(memory.NewWebhooksStore())
+
+		id, err := uc(auth.WithUser(context.Background(), user), create_webhook.Command{
+			Name:   "my-hook",
+			Url:    "https://example.com",
+			Secret: "secret",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		uc := create_webhook.Handler(memory.NewWebhooksStore())
+
+		id, err := uc(ctx, create_webhook.Command{
+			Url:    "ftp://example.com",
+			Events: []string{"unknown"},
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["name"])
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["secret"])
+		testutil.ErrorIs(t, domain.ErrInvalidEventType, validationErr["events"])
+		testutil.Equals(t, "", id)
+	})
+
+	t.Run("should create a new webhook", func(t *testing.T) {
+		store := memory.NewWebhooksStore()
+		uc := create_webhook.Handler(store)
+
+		id, err := uc(ctx, create_webhook.Command{
+			Name:   "my-hook",
+			Url:    "https://example.com/hook",
+			Secret: "secret",
+			Events: []string{"deployment_failed", "target_unreachable"},
+		})
+
+		testutil.IsNil(t, err)
+		webhook := must.Panic(store.GetByID(ctx, domain.WebhookID(id)))
+		testutil.Equals(t, "my-hook", webhook.Name())
+		testutil.Equals(t, "https://example.com/hook", webhook.Url())
+		testutil.DeepEquals(t, domain.EventFilter{domain.EventDeploymentFailed, domain.EventTargetUnreachable}, webhook.Events())
+	})
+}
@@ -0,0 +1,41 @@
+package delete_channel
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Delete a channel, it will not be notified anymore.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string              { return "notification.command.delete_channel" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.ChannelsReader,
+	writer domain.ChannelsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !auth.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		channel, err := reader.GetByID(ctx, domain.ChannelID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		channel.Delete()
+
+		return bus.Unit, writer.Write(ctx, &channel)
+	}
+}
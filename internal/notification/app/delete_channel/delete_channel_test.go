@@ -0,0 +1,58 @@
+package delete_channel_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_channel"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteChannel(t *testing.T) {
+	sut := func(existing ...*domain.Channel) bus.RequestHandler[bus.UnitType, delete_channel.Command] {
+		store := memory.NewChannelsStore(existing...)
+		return delete_channel.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&channel)
+
+		_, err := uc(auth.WithUser(context.Background(), user), delete_channel.Command{
+			ID: string(channel.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing channel", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), delete_channel.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the channel", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		uc := sut(&channel)
+
+		_, err := uc(context.Background(), delete_channel.Command{
+			ID: string(channel.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.EventIs[domain.ChannelDeleted](t, &channel, 1)
+	})
+}
@@ -0,0 +1,41 @@
+package delete_webhook
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Delete a webhook along with its delivery history.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string              { return "notification.command.delete_webhook" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.WebhooksReader,
+	writer domain.WebhooksWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		if !auth.HasAdminRights(ctx) {
+			return bus.Unit, apperr.ErrForbidden
+		}
+
+		webhook, err := reader.GetByID(ctx, domain.WebhookID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		webhook.Delete()
+
+		return bus.Unit, writer.Write(ctx, &webhook)
+	}
+}
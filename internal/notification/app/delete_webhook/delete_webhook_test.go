@@ -0,0 +1,56 @@
+package delete_webhook_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_DeleteWebhook(t *testing.T) {
+	sut := func(existing ...*domain.Webhook) bus.RequestHandler[bus.UnitType, delete_webhook.Command] {
+		store := memory.NewWebhooksStore(existing...)
+		return delete_webhook.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&webhook)
+
+		_, err := uc(auth.WithUser(context.Background(), user), delete_webhook.Command{
+			ID: string(webhook.ID()),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing webhook", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), delete_webhook.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the webhook", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		uc := sut(&webhook)
+
+		_, err := uc(context.Background(), delete_webhook.Command{
+			ID: string(webhook.ID()),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.EventIs[domain.WebhookDeleted](t, &webhook, 1)
+	})
+}
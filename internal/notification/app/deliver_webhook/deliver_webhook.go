@@ -0,0 +1,69 @@
+package deliver_webhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Send the payload of a delivery to its webhook. On failure, the error is returned
+// so the scheduler retries it later, until the maximum number of attempts is reached.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string        { return "notification.command.deliver_webhook" }
+func (c Command) ResourceID() string { return c.ID }
+
+func Handler(
+	reader domain.DeliveriesReader,
+	writer domain.DeliveriesWriter,
+	webhooksReader domain.WebhooksReader,
+	sender domain.WebhookSender,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		delivery, err := reader.GetByID(ctx, domain.DeliveryID(cmd.ID))
+
+		if err != nil {
+			// Deliveries are removed along with their webhook
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		if delivery.Status() != domain.DeliveryStatusPending {
+			return bus.Unit, nil
+		}
+
+		webhook, err := webhooksReader.GetByID(ctx, delivery.Webhook())
+
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		statusCode, sendErr := sender.Send(ctx, webhook, delivery)
+
+		delivery.Attempted(statusCode, sendErr)
+
+		if err = writer.Write(ctx, &delivery); err != nil {
+			return bus.Unit, err
+		}
+
+		if delivery.Status() == domain.DeliveryStatusPending {
+			return bus.Unit, sendErr
+		}
+
+		return bus.Unit, nil
+	}
+}
@@ -0,0 +1,104 @@
+package deliver_webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/app/deliver_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type initialData struct {
+	webhooks   []*domain.Webhook
+	deliveries []*domain.Delivery
+}
+
+func Test_DeliverWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	sut := func(data initialData, sender *dummySender) (bus.RequestHandler[bus.UnitType, deliver_webhook.Command], memory.DeliveriesStore) {
+		webhooksStore := memory.NewWebhooksStore(data.webhooks...)
+		deliveriesStore := memory.NewDeliveriesStore(data.deliveries...)
+		return deliver_webhook.Handler(deliveriesStore, deliveriesStore, webhooksStore, sender), deliveriesStore
+	}
+
+	t.Run("should succeed silently if the delivery does not exist anymore", func(t *testing.T) {
+		sender := &dummySender{}
+		uc, _ := sut(initialData{}, sender)
+
+		_, err := uc(ctx, deliver_webhook.Command{ID: "non-existing-id"})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 0, sender.calls)
+	})
+
+	t.Run("should record a successful attempt", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+		sender := &dummySender{status: 200}
+		uc, store := sut(initialData{
+			webhooks:   []*domain.Webhook{&webhook},
+			deliveries: []*domain.Delivery{&delivery},
+		}, sender)
+
+		_, err := uc(ctx, deliver_webhook.Command{ID: string(delivery.ID())})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, sender.calls)
+		delivery = must.Panic(store.GetByID(ctx, delivery.ID()))
+		testutil.Equals(t, domain.DeliveryStatusSucceeded, delivery.Status())
+	})
+
+	t.Run("should return the error so the delivery is retried", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+		sendErr := errors.New("connection refused")
+		sender := &dummySender{err: sendErr}
+		uc, store := sut(initialData{
+			webhooks:   []*domain.Webhook{&webhook},
+			deliveries: []*domain.Delivery{&delivery},
+		}, sender)
+
+		_, err := uc(ctx, deliver_webhook.Command{ID: string(delivery.ID())})
+
+		testutil.ErrorIs(t, sendErr, err)
+		delivery = must.Panic(store.GetByID(ctx, delivery.ID()))
+		testutil.Equals(t, domain.DeliveryStatusPending, delivery.Status())
+		testutil.Equals(t, 1, delivery.Attempts())
+	})
+
+	t.Run("should give up once the maximum number of attempts is reached", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+		for i := 1; i < domain.MaxDeliveryAttempts; i++ {
+			delivery.Attempted(500, errors.New("some error"))
+		}
+		sender := &dummySender{status: 500, err: errors.New("some error")}
+		uc, store := sut(initialData{
+			webhooks:   []*domain.Webhook{&webhook},
+			deliveries: []*domain.Delivery{&delivery},
+		}, sender)
+
+		_, err := uc(ctx, deliver_webhook.Command{ID: string(delivery.ID())})
+
+		testutil.IsNil(t, err)
+		delivery = must.Panic(store.GetByID(ctx, delivery.ID()))
+		testutil.Equals(t, domain.DeliveryStatusFailed, delivery.Status())
+	})
+}
+
+type dummySender struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (s *dummySender) Send(context.Context, domain.Webhook, domain.Delivery) (int, error) {
+	s.calls++
+	return s.status, s.err
+}
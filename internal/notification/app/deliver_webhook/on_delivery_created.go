@@ -0,0 +1,17 @@
+package deliver_webhook
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Upon receiving a delivery created event, queue a job to send it.
+func OnDeliveryCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DeliveryCreated] {
+	return func(ctx context.Context, evt domain.DeliveryCreated) error {
+		return scheduler.Queue(ctx, Command{
+			ID: string(evt.ID),
+		})
+	}
+}
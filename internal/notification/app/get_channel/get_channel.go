@@ -0,0 +1,38 @@
+package get_channel
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve a channel, its access token is masked. Only available to admins.
+	Query struct {
+		bus.Query[Channel]
+
+		ID string `json:"id"`
+	}
+
+	Channel struct {
+		ID          string                            `json:"id"`
+		Name        string                            `json:"name"`
+		Kind        string                            `json:"kind"`
+		Url         string                            `json:"url"`
+		RoomID      monad.Maybe[string]               `json:"room_id"`
+		AccessToken monad.Maybe[storage.SecretString] `json:"access_token"`
+		App         monad.Maybe[AppSummary]           `json:"app"`
+		CreatedAt   time.Time                         `json:"created_at"`
+		CreatedBy   app.UserSummary                   `json:"created_by"`
+	}
+
+	AppSummary struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+func (Query) Name_() string { return "notification.query.get_channel" }
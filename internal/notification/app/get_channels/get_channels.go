@@ -0,0 +1,17 @@
+package get_channels
+
+import (
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channel"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Retrieve all channels, optionally only those notified of the given app deployments
+// (global ones included). Only available to admins.
+type Query struct {
+	bus.Query[[]get_channel.Channel]
+
+	AppID monad.Maybe[string]
+}
+
+func (Query) Name_() string { return "notification.query.get_channels" }
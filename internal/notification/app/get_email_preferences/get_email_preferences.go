@@ -0,0 +1,19 @@
+package get_email_preferences
+
+import "github.com/YuukanOO/seelf/pkg/bus"
+
+type (
+	// Retrieve which emails a user wants to receive.
+	Query struct {
+		bus.Query[EmailPreferences]
+
+		UserID string `json:"-"`
+	}
+
+	EmailPreferences struct {
+		DeploymentFailures bool `json:"deployment_failures"`
+		CertificateExpiry  bool `json:"certificate_expiry"`
+	}
+)
+
+func (Query) Name_() string { return "notification.query.get_email_preferences" }
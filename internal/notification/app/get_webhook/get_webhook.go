@@ -0,0 +1,29 @@
+package get_webhook
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/app"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve a webhook, its secret is never returned. Only available to admins.
+	Query struct {
+		bus.Query[Webhook]
+
+		ID string `json:"id"`
+	}
+
+	Webhook struct {
+		ID        string             `json:"id"`
+		Name      string             `json:"name"`
+		Url       string             `json:"url"`
+		Events    domain.EventFilter `json:"events"`
+		CreatedAt time.Time          `json:"created_at"`
+		CreatedBy app.UserSummary    `json:"created_by"`
+	}
+)
+
+func (Query) Name_() string { return "notification.query.get_webhook" }
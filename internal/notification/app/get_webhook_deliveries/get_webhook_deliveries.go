@@ -0,0 +1,34 @@
+package get_webhook_deliveries
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve the delivery history of a webhook, most recent first. Only available
+	// to admins.
+	Query struct {
+		bus.Query[storage.Paginated[Delivery]]
+
+		WebhookID string           `json:"-"`
+		Page      monad.Maybe[int] `form:"page"`
+	}
+
+	Delivery struct {
+		ID            string                 `json:"id"`
+		Event         string                 `json:"event"`
+		Payload       string                 `json:"payload"`
+		Status        uint8                  `json:"status"`
+		Attempts      int                    `json:"attempts"`
+		StatusCode    monad.Maybe[int]       `json:"status_code"`
+		ErrCode       monad.Maybe[string]    `json:"error_code"`
+		CreatedAt     time.Time              `json:"created_at"`
+		LastAttemptAt monad.Maybe[time.Time] `json:"last_attempt_at"`
+	}
+)
+
+func (Query) Name_() string { return "notification.query.get_webhook_deliveries" }
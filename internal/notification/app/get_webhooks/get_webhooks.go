@@ -0,0 +1,13 @@
+package get_webhooks
+
+import (
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retrieve all webhooks. Only available to admins.
+type Query struct {
+	bus.Query[[]get_webhook.Webhook]
+}
+
+func (Query) Name_() string { return "notification.query.get_webhooks" }
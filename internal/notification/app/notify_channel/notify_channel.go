@@ -0,0 +1,56 @@
+package notify_channel
+
+import (
+	"context"
+	"errors"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Post the result of a deployment on a channel. On failure, the error is returned
+// so the scheduler retries it later.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ChannelID        string              `json:"channel_id"`
+	AppID            string              `json:"app_id"`
+	AppName          string              `json:"app_name"`
+	DeploymentNumber int                 `json:"deployment_number"`
+	Environment      string              `json:"environment"`
+	Succeeded        bool                `json:"succeeded"`
+	ErrCode          monad.Maybe[string] `json:"error_code"`
+}
+
+func (Command) Name_() string        { return "notification.command.notify_channel" }
+func (c Command) ResourceID() string { return c.ChannelID }
+
+func Handler(
+	reader domain.ChannelsReader,
+	notifier domain.ChannelNotifier,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		channel, err := reader.GetByID(ctx, domain.ChannelID(cmd.ChannelID))
+
+		if err != nil {
+			// The channel may have been deleted in the meantime
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		return bus.Unit, notifier.Notify(ctx, channel, domain.DeploymentResult{
+			AppID:            deployment.AppID(cmd.AppID),
+			AppName:          cmd.AppName,
+			DeploymentNumber: cmd.DeploymentNumber,
+			Environment:      cmd.Environment,
+			Succeeded:        cmd.Succeeded,
+			ErrCode:          cmd.ErrCode,
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package notify_channel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_channel"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_NotifyChannel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should succeed silently if the channel does not exist anymore", func(t *testing.T) {
+		notifier := &dummyNotifier{}
+		uc := notify_channel.Handler(memory.NewChannelsStore(), notifier)
+
+		_, err := uc(ctx, notify_channel.Command{ChannelID: "non-existing-id"})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, notifier.results, 0)
+	})
+
+	t.Run("should post the deployment result on the channel", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifier := &dummyNotifier{}
+		uc := notify_channel.Handler(memory.NewChannelsStore(&channel), notifier)
+
+		_, err := uc(ctx, notify_channel.Command{
+			ChannelID:        string(channel.ID()),
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 2,
+			Environment:      "production",
+			ErrCode:          monad.Value("some error"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.DeploymentResult{
+			{
+				AppID:            "app-id",
+				AppName:          "my-app",
+				DeploymentNumber: 2,
+				Environment:      "production",
+				ErrCode:          monad.Value("some error"),
+			},
+		}, notifier.results)
+	})
+
+	t.Run("should return the error so the notification is retried", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifyErr := errors.New("connection refused")
+		uc := notify_channel.Handler(memory.NewChannelsStore(&channel), &dummyNotifier{err: notifyErr})
+
+		_, err := uc(ctx, notify_channel.Command{ChannelID: string(channel.ID())})
+
+		testutil.ErrorIs(t, notifyErr, err)
+	})
+}
+
+type dummyNotifier struct {
+	domain.ChannelNotifier
+	err     error
+	results []domain.DeploymentResult
+}
+
+func (n *dummyNotifier) Notify(_ context.Context, _ domain.Channel, result domain.DeploymentResult) error {
+	n.results = append(n.results, result)
+	return n.err
+}
@@ -0,0 +1,46 @@
+package notify_channel
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When a deployment has ended, queue a job to post its result on every channel
+// interested in the app.
+func OnDeploymentStateChangedHandler(
+	reader domain.ChannelsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[deployment.DeploymentStateChanged] {
+	return func(ctx context.Context, evt deployment.DeploymentStateChanged) error {
+		status := evt.State.Status()
+
+		if status != deployment.DeploymentStatusSucceeded && status != deployment.DeploymentStatusFailed {
+			return nil
+		}
+
+		channels, err := reader.GetForApp(ctx, evt.ID.AppID())
+
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			if err = scheduler.Queue(ctx, Command{
+				ChannelID:        string(channel.ID()),
+				AppID:            string(evt.ID.AppID()),
+				AppName:          string(evt.Config.AppName()),
+				DeploymentNumber: int(evt.ID.DeploymentNumber()),
+				Environment:      string(evt.Config.Environment()),
+				Succeeded:        status == deployment.DeploymentStatusSucceeded,
+				ErrCode:          evt.State.ErrCode(),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
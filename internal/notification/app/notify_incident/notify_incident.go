@@ -0,0 +1,60 @@
+package notify_incident
+
+import (
+	"context"
+	"errors"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Post an incident which happened to an app container on a channel. On failure, the
+// error is returned so the scheduler retries it later.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ChannelID        string           `json:"channel_id"`
+	IncidentID       string           `json:"incident_id"`
+	AppID            string           `json:"app_id"`
+	AppName          string           `json:"app_name"`
+	DeploymentNumber int              `json:"deployment_number"`
+	Environment      string           `json:"environment"`
+	Service          string           `json:"service"`
+	Kind             string           `json:"kind"`
+	ExitCode         monad.Maybe[int] `json:"exit_code"`
+}
+
+func (Command) Name_() string        { return "notification.command.notify_incident" }
+func (c Command) ResourceID() string { return c.ChannelID }
+
+func Handler(
+	reader domain.ChannelsReader,
+	notifier domain.ChannelNotifier,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		channel, err := reader.GetByID(ctx, domain.ChannelID(cmd.ChannelID))
+
+		if err != nil {
+			// The channel may have been deleted in the meantime
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		return bus.Unit, notifier.NotifyIncident(ctx, channel, domain.IncidentReport{
+			ID:               cmd.IncidentID,
+			AppID:            deployment.AppID(cmd.AppID),
+			AppName:          cmd.AppName,
+			DeploymentNumber: cmd.DeploymentNumber,
+			Environment:      cmd.Environment,
+			Service:          cmd.Service,
+			Kind:             deployment.IncidentKind(cmd.Kind),
+			ExitCode:         cmd.ExitCode,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package notify_incident_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_incident"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_NotifyIncident(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should succeed silently if the channel does not exist anymore", func(t *testing.T) {
+		notifier := &dummyNotifier{}
+		uc := notify_incident.Handler(memory.NewChannelsStore(), notifier)
+
+		_, err := uc(ctx, notify_incident.Command{ChannelID: "non-existing-id"})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, notifier.reports, 0)
+	})
+
+	t.Run("should post the incident on the channel", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifier := &dummyNotifier{}
+		uc := notify_incident.Handler(memory.NewChannelsStore(&channel), notifier)
+
+		_, err := uc(ctx, notify_incident.Command{
+			ChannelID:        string(channel.ID()),
+			IncidentID:       "incident-id",
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 2,
+			Environment:      "production",
+			Service:          "app",
+			Kind:             string(deployment.IncidentCrashed),
+			ExitCode:         monad.Value(1),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.IncidentReport{
+			{
+				ID:               "incident-id",
+				AppID:            "app-id",
+				AppName:          "my-app",
+				DeploymentNumber: 2,
+				Environment:      "production",
+				Service:          "app",
+				Kind:             deployment.IncidentCrashed,
+				ExitCode:         monad.Value(1),
+			},
+		}, notifier.reports)
+	})
+
+	t.Run("should return the error so the notification is retried", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifyErr := errors.New("connection refused")
+		uc := notify_incident.Handler(memory.NewChannelsStore(&channel), &dummyNotifier{err: notifyErr})
+
+		_, err := uc(ctx, notify_incident.Command{ChannelID: string(channel.ID())})
+
+		testutil.ErrorIs(t, notifyErr, err)
+	})
+}
+
+type dummyNotifier struct {
+	domain.ChannelNotifier
+	err     error
+	reports []domain.IncidentReport
+}
+
+func (n *dummyNotifier) NotifyIncident(_ context.Context, _ domain.Channel, report domain.IncidentReport) error {
+	n.reports = append(n.reports, report)
+	return n.err
+}
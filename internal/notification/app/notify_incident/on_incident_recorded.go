@@ -0,0 +1,42 @@
+package notify_incident
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an incident has been recorded, queue a job to post it on every channel
+// interested in the app.
+func OnIncidentRecordedHandler(
+	reader domain.ChannelsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[deployment.IncidentRecorded] {
+	return func(ctx context.Context, evt deployment.IncidentRecorded) error {
+		channels, err := reader.GetForApp(ctx, evt.DeploymentID.AppID())
+
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			if err = scheduler.Queue(ctx, Command{
+				ChannelID:        string(channel.ID()),
+				IncidentID:       string(evt.ID),
+				AppID:            string(evt.DeploymentID.AppID()),
+				AppName:          string(evt.Config.AppName()),
+				DeploymentNumber: int(evt.DeploymentID.DeploymentNumber()),
+				Environment:      string(evt.Config.Environment()),
+				Service:          evt.Service,
+				Kind:             string(evt.Kind),
+				ExitCode:         evt.ExitCode,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package notify_uptime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Post on a channel that the public url of an app environment went down or is back up.
+// On failure, the error is returned so the scheduler retries it later.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ChannelID   string              `json:"channel_id"`
+	MonitorID   string              `json:"monitor_id"`
+	AppID       string              `json:"app_id"`
+	AppName     string              `json:"app_name"`
+	Environment string              `json:"environment"`
+	Url         string              `json:"url"`
+	Up          bool                `json:"up"`
+	Reason      monad.Maybe[string] `json:"reason"`
+	ChangedAt   time.Time           `json:"changed_at"`
+}
+
+func (Command) Name_() string        { return "notification.command.notify_uptime" }
+func (c Command) ResourceID() string { return c.ChannelID }
+
+func Handler(
+	reader domain.ChannelsReader,
+	notifier domain.ChannelNotifier,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		channel, err := reader.GetByID(ctx, domain.ChannelID(cmd.ChannelID))
+
+		if err != nil {
+			// The channel may have been deleted in the meantime
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		return bus.Unit, notifier.NotifyUptime(ctx, channel, domain.UptimeReport{
+			MonitorID:   cmd.MonitorID,
+			AppID:       deployment.AppID(cmd.AppID),
+			AppName:     cmd.AppName,
+			Environment: cmd.Environment,
+			Url:         cmd.Url,
+			Up:          cmd.Up,
+			Reason:      cmd.Reason,
+			ChangedAt:   cmd.ChangedAt,
+		})
+	}
+}
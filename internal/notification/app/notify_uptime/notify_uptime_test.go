@@ -0,0 +1,81 @@
+package notify_uptime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_uptime"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_NotifyUptime(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should succeed silently if the channel does not exist anymore", func(t *testing.T) {
+		notifier := &dummyNotifier{}
+		uc := notify_uptime.Handler(memory.NewChannelsStore(), notifier)
+
+		_, err := uc(ctx, notify_uptime.Command{ChannelID: "non-existing-id"})
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, notifier.reports, 0)
+	})
+
+	t.Run("should post the uptime change on the channel", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifier := &dummyNotifier{}
+		uc := notify_uptime.Handler(memory.NewChannelsStore(&channel), notifier)
+		changedAt := time.Now().UTC()
+
+		_, err := uc(ctx, notify_uptime.Command{
+			ChannelID:   string(channel.ID()),
+			MonitorID:   "monitor-id",
+			AppID:       "app-id",
+			AppName:     "my-app",
+			Environment: "production",
+			Url:         "https://my-app.docker.localhost",
+			Reason:      monad.Value("unexpected status code 502"),
+			ChangedAt:   changedAt,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.UptimeReport{
+			{
+				MonitorID:   "monitor-id",
+				AppID:       "app-id",
+				AppName:     "my-app",
+				Environment: "production",
+				Url:         "https://my-app.docker.localhost",
+				Reason:      monad.Value("unexpected status code 502"),
+				ChangedAt:   changedAt,
+			},
+		}, notifier.reports)
+	})
+
+	t.Run("should return the error so the notification is retried", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		notifyErr := errors.New("connection refused")
+		uc := notify_uptime.Handler(memory.NewChannelsStore(&channel), &dummyNotifier{err: notifyErr})
+
+		_, err := uc(ctx, notify_uptime.Command{ChannelID: string(channel.ID())})
+
+		testutil.ErrorIs(t, notifyErr, err)
+	})
+}
+
+type dummyNotifier struct {
+	domain.ChannelNotifier
+	err     error
+	reports []domain.UptimeReport
+}
+
+func (n *dummyNotifier) NotifyUptime(_ context.Context, _ domain.Channel, report domain.UptimeReport) error {
+	n.reports = append(n.reports, report)
+	return n.err
+}
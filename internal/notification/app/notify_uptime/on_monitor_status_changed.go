@@ -0,0 +1,42 @@
+package notify_uptime
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an app environment went down or is back up, queue a job to post it on every
+// channel interested in the app.
+func OnMonitorStatusChangedHandler(
+	reader domain.ChannelsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[deployment.MonitorStatusChanged] {
+	return func(ctx context.Context, evt deployment.MonitorStatusChanged) error {
+		channels, err := reader.GetForApp(ctx, evt.Config.AppID())
+
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			if err = scheduler.Queue(ctx, Command{
+				ChannelID:   string(channel.ID()),
+				MonitorID:   string(evt.ID),
+				AppID:       string(evt.Config.AppID()),
+				AppName:     string(evt.Config.AppName()),
+				Environment: string(evt.Config.Environment()),
+				Url:         evt.Url.String(),
+				Up:          evt.Status == deployment.MonitorStatusUp,
+				Reason:      evt.Reason,
+				ChangedAt:   evt.ChangedAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
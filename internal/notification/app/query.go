@@ -0,0 +1,6 @@
+package app
+
+type UserSummary struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
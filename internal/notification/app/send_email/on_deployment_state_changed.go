@@ -0,0 +1,58 @@
+package send_email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a deployment has failed, queue an email for the user who requested it if
+// it wants to be notified.
+func OnDeploymentStateChangedHandler(
+	reader domain.RecipientsReader,
+	scheduler bus.Scheduler,
+	exposedUrl monad.Maybe[deployment.Url],
+) bus.SignalHandler[deployment.DeploymentStateChanged] {
+	return func(ctx context.Context, evt deployment.DeploymentStateChanged) error {
+		if evt.State.Status() != deployment.DeploymentStatusFailed {
+			return nil
+		}
+
+		recipients, err := reader.GetDeploymentFailureRecipients(ctx, evt.ID)
+
+		if err != nil {
+			return err
+		}
+
+		var body strings.Builder
+
+		fmt.Fprintf(&body, "Deployment #%d of %s (%s) has failed.\n",
+			evt.ID.DeploymentNumber(), evt.Config.AppName(), evt.Config.Environment())
+
+		if errcode, isSet := evt.State.ErrCode().TryGet(); isSet {
+			fmt.Fprintf(&body, "\n%s\n", errcode)
+		}
+
+		if url, isSet := exposedUrl.TryGet(); isSet {
+			fmt.Fprintf(&body, "\nSee the deployment logs: %s/apps/%s/deployments/%d\n",
+				strings.TrimSuffix(url.WithoutUser().String(), "/"), evt.ID.AppID(), evt.ID.DeploymentNumber())
+		}
+
+		for _, to := range recipients {
+			if err = scheduler.Queue(ctx, Command{
+				To:      to,
+				Subject: fmt.Sprintf("[seelf] Deployment #%d of %s failed", evt.ID.DeploymentNumber(), evt.Config.AppName()),
+				Body:    body.String(),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
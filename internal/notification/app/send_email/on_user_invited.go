@@ -0,0 +1,37 @@
+package send_email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a user has been invited, queue an email to let it know. The password is never
+// sent, the user should ask the admin who invited it.
+func OnUserInvitedHandler(
+	scheduler bus.Scheduler,
+	exposedUrl monad.Maybe[deployment.Url],
+) bus.SignalHandler[auth.UserInvited] {
+	return func(ctx context.Context, evt auth.UserInvited) error {
+		var body strings.Builder
+
+		body.WriteString("An account has been created for you on seelf.\n")
+
+		if url, isSet := exposedUrl.TryGet(); isSet {
+			fmt.Fprintf(&body, "\nSign in at %s using this email address.\n", strings.TrimSuffix(url.WithoutUser().String(), "/"))
+		}
+
+		body.WriteString("\nAsk the administrator who invited you for your password.\n")
+
+		return scheduler.Queue(ctx, Command{
+			To:      string(evt.Email),
+			Subject: "[seelf] You have been invited",
+			Body:    body.String(),
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package send_email
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Send an email through the configured SMTP server. On failure, the error is returned
+// so the scheduler retries it later.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+func (Command) Name_() string        { return "notification.command.send_email" }
+func (c Command) ResourceID() string { return c.To }
+
+func Handler(sender domain.EmailSender) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		return bus.Unit, sender.Send(ctx, domain.Email{
+			To:      cmd.To,
+			Subject: cmd.Subject,
+			Body:    cmd.Body,
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package send_email_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/app/send_email"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_SendEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should send the email", func(t *testing.T) {
+		sender := &dummySender{}
+		uc := send_email.Handler(sender)
+
+		_, err := uc(ctx, send_email.Command{
+			To:      "john@doe.com",
+			Subject: "Hello",
+			Body:    "World",
+		})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []domain.Email{
+			{To: "john@doe.com", Subject: "Hello", Body: "World"},
+		}, sender.emails)
+	})
+
+	t.Run("should return the error so the email is retried", func(t *testing.T) {
+		sendErr := errors.New("connection refused")
+		uc := send_email.Handler(&dummySender{err: sendErr})
+
+		_, err := uc(ctx, send_email.Command{To: "john@doe.com"})
+
+		testutil.ErrorIs(t, sendErr, err)
+	})
+}
+
+type dummySender struct {
+	err    error
+	emails []domain.Email
+}
+
+func (s *dummySender) Send(_ context.Context, email domain.Email) error {
+	s.emails = append(s.emails, email)
+	return s.err
+}
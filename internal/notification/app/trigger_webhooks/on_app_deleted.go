@@ -0,0 +1,22 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Apps are deleted once their resources have been cleaned up, notify interested webhooks.
+func OnAppDeletedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.AppDeleted] {
+	return func(ctx context.Context, evt deployment.AppDeleted) error {
+		return trigger(ctx, reader, writer, domain.EventCleanupCompleted, Cleanup{
+			Resource: resourceApp,
+			ID:       string(evt.ID),
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a deployment starts or ends, notify interested webhooks.
+func OnDeploymentStateChangedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.DeploymentStateChanged] {
+	return func(ctx context.Context, evt deployment.DeploymentStateChanged) error {
+		var eventType domain.EventType
+
+		switch evt.State.Status() {
+		case deployment.DeploymentStatusRunning:
+			eventType = domain.EventDeploymentStarted
+		case deployment.DeploymentStatusSucceeded:
+			eventType = domain.EventDeploymentSucceeded
+		case deployment.DeploymentStatusFailed:
+			eventType = domain.EventDeploymentFailed
+		default:
+			return nil
+		}
+
+		return trigger(ctx, reader, writer, eventType, Deployment{
+			AppID:            string(evt.ID.AppID()),
+			AppName:          string(evt.Config.AppName()),
+			DeploymentNumber: int(evt.ID.DeploymentNumber()),
+			Environment:      string(evt.Config.Environment()),
+			TargetID:         string(evt.Config.Target()),
+			ErrCode:          evt.State.ErrCode(),
+			FailureReason:    monad.Map(evt.State.FailureReason(), deployment.DeploymentFailureReason.String),
+		})
+	}
+}
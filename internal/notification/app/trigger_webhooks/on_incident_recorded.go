@@ -0,0 +1,31 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When an incident happened to an app container, notify interested webhooks.
+func OnIncidentRecordedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.IncidentRecorded] {
+	return func(ctx context.Context, evt deployment.IncidentRecorded) error {
+		return trigger(ctx, reader, writer, domain.EventContainerIncident, Incident{
+			ID:               string(evt.ID),
+			AppID:            string(evt.DeploymentID.AppID()),
+			AppName:          string(evt.Config.AppName()),
+			DeploymentNumber: int(evt.DeploymentID.DeploymentNumber()),
+			Environment:      string(evt.Config.Environment()),
+			TargetID:         string(evt.Config.Target()),
+			Service:          evt.Service,
+			Container:        evt.Container,
+			Kind:             string(evt.Kind),
+			ExitCode:         evt.ExitCode,
+			OccurredAt:       evt.OccurredAt,
+		})
+	}
+}
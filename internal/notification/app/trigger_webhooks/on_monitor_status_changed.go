@@ -0,0 +1,35 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When the public url of an app environment went down or is back up, notify interested
+// webhooks.
+func OnMonitorStatusChangedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.MonitorStatusChanged] {
+	return func(ctx context.Context, evt deployment.MonitorStatusChanged) error {
+		eventType := domain.EventAppDown
+
+		if evt.Status == deployment.MonitorStatusUp {
+			eventType = domain.EventAppUp
+		}
+
+		return trigger(ctx, reader, writer, eventType, Uptime{
+			MonitorID:   string(evt.ID),
+			AppID:       string(evt.Config.AppID()),
+			AppName:     string(evt.Config.AppName()),
+			Environment: string(evt.Config.Environment()),
+			TargetID:    string(evt.Config.Target()),
+			Url:         evt.Url.String(),
+			Reason:      evt.Reason,
+			ChangedAt:   evt.ChangedAt,
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Targets are deleted once their resources have been cleaned up, notify interested webhooks.
+func OnTargetDeletedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.TargetDeleted] {
+	return func(ctx context.Context, evt deployment.TargetDeleted) error {
+		return trigger(ctx, reader, writer, domain.EventCleanupCompleted, Cleanup{
+			Resource: resourceTarget,
+			ID:       string(evt.ID),
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package trigger_webhooks
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When a target could not be configured, it is considered unreachable so notify
+// interested webhooks.
+func OnTargetStateChangedHandler(
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+) bus.SignalHandler[deployment.TargetStateChanged] {
+	return func(ctx context.Context, evt deployment.TargetStateChanged) error {
+		if evt.State.Status() != deployment.TargetStatusFailed {
+			return nil
+		}
+
+		return trigger(ctx, reader, writer, domain.EventTargetUnreachable, Target{
+			ID:      string(evt.ID),
+			ErrCode: evt.State.ErrCode(),
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package trigger_webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	resourceApp    = "app"
+	resourceTarget = "target"
+)
+
+type (
+	// Body sent to webhooks.
+	Payload struct {
+		Event      domain.EventType `json:"event"`
+		OccurredAt time.Time        `json:"occurred_at"`
+		Data       any              `json:"data"`
+	}
+
+	// Data of deployment events.
+	Deployment struct {
+		AppID            string              `json:"app_id"`
+		AppName          string              `json:"app_name"`
+		DeploymentNumber int                 `json:"deployment_number"`
+		Environment      string              `json:"environment"`
+		TargetID         string              `json:"target_id"`
+		ErrCode          monad.Maybe[string] `json:"error_code"`
+		FailureReason    monad.Maybe[string] `json:"failure_reason"`
+	}
+
+	// Data of target events.
+	Target struct {
+		ID      string              `json:"id"`
+		ErrCode monad.Maybe[string] `json:"error_code"`
+	}
+
+	// Data of container incident events.
+	Incident struct {
+		ID               string           `json:"id"`
+		AppID            string           `json:"app_id"`
+		AppName          string           `json:"app_name"`
+		DeploymentNumber int              `json:"deployment_number"`
+		Environment      string           `json:"environment"`
+		TargetID         string           `json:"target_id"`
+		Service          string           `json:"service"`
+		Container        string           `json:"container"`
+		Kind             string           `json:"kind"`
+		ExitCode         monad.Maybe[int] `json:"exit_code"`
+		OccurredAt       time.Time        `json:"occurred_at"`
+	}
+
+	// Data of uptime events, raised when the public url of an app environment went down
+	// or is back up.
+	Uptime struct {
+		MonitorID   string              `json:"monitor_id"`
+		AppID       string              `json:"app_id"`
+		AppName     string              `json:"app_name"`
+		Environment string              `json:"environment"`
+		TargetID    string              `json:"target_id"`
+		Url         string              `json:"url"`
+		Reason      monad.Maybe[string] `json:"reason"`
+		ChangedAt   time.Time           `json:"changed_at"`
+	}
+
+	// Data of cleanup events, the resource is either an app or a target.
+	Cleanup struct {
+		Resource string `json:"resource"`
+		ID       string `json:"id"`
+	}
+)
+
+// Prepares a delivery of the given event for every webhook interested in it.
+func trigger(
+	ctx context.Context,
+	reader domain.WebhooksReader,
+	writer domain.DeliveriesWriter,
+	evt domain.EventType,
+	data any,
+) error {
+	webhooks, err := reader.GetAll(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:      evt,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*domain.Delivery
+
+	for _, webhook := range webhooks {
+		if !webhook.Listens(evt) {
+			continue
+		}
+
+		delivery := webhook.Deliver(evt, string(payload))
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return writer.Write(ctx, deliveries...)
+}
@@ -0,0 +1,81 @@
+package update_channel
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Update a channel. The kind and the app of a channel could not be changed, room and
+// access token are only used by matrix channels.
+type Command struct {
+	bus.Command[string]
+
+	ID          string              `json:"id"`
+	Name        monad.Maybe[string] `json:"name"`
+	Url         monad.Maybe[string] `json:"url"`
+	RoomID      monad.Maybe[string] `json:"room_id"`
+	AccessToken monad.Maybe[string] `json:"access_token"`
+}
+
+func (Command) Name_() string              { return "notification.command.update_channel" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.ChannelsReader,
+	writer domain.ChannelsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var url domain.Url
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Maybe(cmd.Name, strings.Required),
+			"url": validate.Maybe(cmd.Url, func(u string) error {
+				return validate.Value(u, &url, domain.UrlFrom)
+			}),
+			"room_id":      validate.Maybe(cmd.RoomID, strings.Required),
+			"access_token": validate.Maybe(cmd.AccessToken, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		channel, err := reader.GetByID(ctx, domain.ChannelID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if name, isSet := cmd.Name.TryGet(); isSet {
+			channel.Rename(name)
+		}
+
+		config := channel.Config()
+
+		if cmd.Url.HasValue() {
+			config.Url = url
+		}
+
+		if config.Kind == domain.ChannelKindMatrix {
+			config.RoomID = cmd.RoomID.Get(config.RoomID)
+			config.AccessToken = cmd.AccessToken.Get(config.AccessToken)
+		}
+
+		channel.HasConfig(config)
+
+		if err = writer.Write(ctx, &channel); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
@@ -0,0 +1,82 @@
+package update_channel_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_channel"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_UpdateChannel(t *testing.T) {
+	sut := func(existing ...*domain.Channel) bus.RequestHandler[string, update_channel.Command] {
+		store := memory.NewChannelsStore(existing...)
+		return update_channel.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&channel)
+
+		_, err := uc(auth.WithUser(context.Background(), user), update_channel.Command{
+			ID:   string(channel.ID()),
+			Name: monad.Value("deployments"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing channel", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), update_channel.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.None[deployment.AppID](), "uid")
+		uc := sut(&channel)
+
+		_, err := uc(context.Background(), update_channel.Command{
+			ID:          string(channel.ID()),
+			Url:         monad.Value("not-an-url"),
+			AccessToken: monad.Value(""),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+		testutil.ErrorIs(t, strings.ErrRequired, validationErr["access_token"])
+	})
+
+	t.Run("should update the channel", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "token"), monad.None[deployment.AppID](), "uid")
+		uc := sut(&channel)
+
+		id, err := uc(context.Background(), update_channel.Command{
+			ID:          string(channel.ID()),
+			Name:        monad.Value("deployments"),
+			AccessToken: monad.Value("new-token"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(channel.ID()), id)
+		testutil.Equals(t, "deployments", testutil.EventIs[domain.ChannelRenamed](t, &channel, 1).Name)
+		testutil.Equals(t, domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "new-token"),
+			testutil.EventIs[domain.ChannelConfigChanged](t, &channel, 2).Config)
+	})
+}
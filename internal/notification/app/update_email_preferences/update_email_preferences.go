@@ -0,0 +1,42 @@
+package update_email_preferences
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Update which emails a user wants to receive.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	UserID             string            `json:"-"`
+	DeploymentFailures monad.Maybe[bool] `json:"deployment_failures"`
+	CertificateExpiry  monad.Maybe[bool] `json:"certificate_expiry"`
+}
+
+func (Command) Name_() string              { return "notification.command.update_email_preferences" }
+func (c Command) AuditResource(any) string { return c.UserID }
+
+func Handler(
+	reader domain.EmailPreferencesReader,
+	writer domain.EmailPreferencesWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		preferences, err := reader.GetByUserID(ctx, auth.UserID(cmd.UserID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		preferences.Update(
+			cmd.DeploymentFailures.Get(preferences.DeploymentFailures()),
+			cmd.CertificateExpiry.Get(preferences.CertificateExpiry()),
+		)
+
+		return bus.Unit, writer.Write(ctx, &preferences)
+	}
+}
@@ -0,0 +1,54 @@
+package update_email_preferences_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/app/update_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_UpdateEmailPreferences(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should update only the given preferences", func(t *testing.T) {
+		store := memory.NewEmailPreferencesStore()
+		uc := update_email_preferences.Handler(store, store)
+
+		_, err := uc(ctx, update_email_preferences.Command{
+			UserID:            "uid",
+			CertificateExpiry: monad.Value(false),
+		})
+
+		testutil.IsNil(t, err)
+
+		preferences, err := store.GetByUserID(ctx, "uid")
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, preferences.DeploymentFailures())
+		testutil.IsFalse(t, preferences.CertificateExpiry())
+	})
+
+	t.Run("should keep previously saved preferences", func(t *testing.T) {
+		existing := domain.DefaultEmailPreferences("uid")
+		existing.Update(false, false)
+		store := memory.NewEmailPreferencesStore(&existing)
+		uc := update_email_preferences.Handler(store, store)
+
+		_, err := uc(ctx, update_email_preferences.Command{
+			UserID:             "uid",
+			DeploymentFailures: monad.Value(true),
+		})
+
+		testutil.IsNil(t, err)
+
+		preferences, err := store.GetByUserID(ctx, "uid")
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, preferences.DeploymentFailures())
+		testutil.IsFalse(t, preferences.CertificateExpiry())
+	})
+}
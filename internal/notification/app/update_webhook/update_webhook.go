@@ -0,0 +1,83 @@
+package update_webhook
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type Command struct {
+	bus.Command[string]
+
+	ID     string                `json:"id"`
+	Name   monad.Maybe[string]   `json:"name"`
+	Url    monad.Maybe[string]   `json:"url"`
+	Secret monad.Maybe[string]   `json:"secret"`
+	Events monad.Maybe[[]string] `json:"events"`
+}
+
+func (Command) Name_() string              { return "notification.command.update_webhook" }
+func (c Command) AuditResource(any) string { return c.ID }
+
+func Handler(
+	reader domain.WebhooksReader,
+	writer domain.WebhooksWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		if !auth.HasAdminRights(ctx) {
+			return "", apperr.ErrForbidden
+		}
+
+		var (
+			url    domain.Url
+			events domain.EventFilter
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Maybe(cmd.Name, strings.Required),
+			"url": validate.Maybe(cmd.Url, func(u string) error {
+				return validate.Value(u, &url, domain.UrlFrom)
+			}),
+			"secret": validate.Maybe(cmd.Secret, strings.Required),
+			"events": validate.Maybe(cmd.Events, func(e []string) error {
+				return validate.Value(e, &events, domain.EventFilterFrom)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		webhook, err := reader.GetByID(ctx, domain.WebhookID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if name, isSet := cmd.Name.TryGet(); isSet {
+			webhook.Rename(name)
+		}
+
+		if cmd.Url.HasValue() {
+			webhook.HasUrl(url)
+		}
+
+		if secret, isSet := cmd.Secret.TryGet(); isSet {
+			webhook.HasSecret(secret)
+		}
+
+		if cmd.Events.HasValue() {
+			webhook.ListensTo(events)
+		}
+
+		if err = writer.Write(ctx, &webhook); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
@@ -0,0 +1,83 @@
+package update_webhook_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/memory"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_UpdateWebhook(t *testing.T) {
+	sut := func(existing ...*domain.Webhook) bus.RequestHandler[string, update_webhook.Command] {
+		store := memory.NewWebhooksStore(existing...)
+		return update_webhook.Handler(store, store)
+	}
+
+	t.Run("should require admin rights", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		user := must.Panic(auth.NewUser(auth.NewEmailRequirement("john@doe.com", true), "password", "apikey"))
+		uc := sut(&webhook)
+
+		_, err := uc(auth.WithUser(context.Background(), user), update_webhook.Command{
+			ID:   string(webhook.ID()),
+			Name: monad.Value("new-hook"),
+		})
+
+		testutil.ErrorIs(t, apperr.ErrForbidden, err)
+	})
+
+	t.Run("should require an existing webhook", func(t *testing.T) {
+		uc := sut()
+
+		_, err := uc(context.Background(), update_webhook.Command{
+			ID: "non-existing-id",
+		})
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		uc := sut(&webhook)
+
+		_, err := uc(context.Background(), update_webhook.Command{
+			ID:     string(webhook.ID()),
+			Url:    monad.Value("not-an-url"),
+			Events: monad.Value([]string{"unknown"}),
+		})
+
+		validationErr, ok := apperr.As[validate.FieldErrors](err)
+		testutil.IsTrue(t, ok)
+		testutil.ErrorIs(t, domain.ErrInvalidUrl, validationErr["url"])
+		testutil.ErrorIs(t, domain.ErrInvalidEventType, validationErr["events"])
+	})
+
+	t.Run("should update the webhook", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+		uc := sut(&webhook)
+
+		id, err := uc(context.Background(), update_webhook.Command{
+			ID:     string(webhook.ID()),
+			Name:   monad.Value("new-hook"),
+			Url:    monad.Value("https://example.org"),
+			Secret: monad.Value("new-secret"),
+			Events: monad.Value([]string{"cleanup_completed"}),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, string(webhook.ID()), id)
+		testutil.Equals(t, "new-hook", testutil.EventIs[domain.WebhookRenamed](t, &webhook, 1).Name)
+		testutil.Equals(t, "https://example.org", testutil.EventIs[domain.WebhookUrlChanged](t, &webhook, 2).Url)
+		testutil.Equals(t, "new-secret", testutil.EventIs[domain.WebhookSecretChanged](t, &webhook, 3).Secret)
+		testutil.DeepEquals(t, domain.EventFilter{domain.EventCleanupCompleted}, testutil.EventIs[domain.WebhookEventsChanged](t, &webhook, 4).Events)
+	})
+}
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var ErrInvalidChannelKind = apperr.New("invalid_channel_kind")
+
+const (
+	ChannelKindSlack   ChannelKind = "slack"
+	ChannelKindDiscord ChannelKind = "discord"
+	ChannelKindMatrix  ChannelKind = "matrix"
+)
+
+type (
+	ChannelID string
+
+	// Chat service used by a channel.
+	ChannelKind string
+
+	// Configuration needed to post messages on a channel. For slack and discord, the
+	// url is the incoming webhook one. For matrix, it is the homeserver url and the
+	// room and access token must be set. Stored as json in the database.
+	ChannelConfig struct {
+		Kind        ChannelKind `json:"kind"`
+		Url         Url         `json:"url"`
+		RoomID      string      `json:"room_id,omitempty"`
+		AccessToken string      `json:"access_token,omitempty"`
+	}
+
+	// Chat room where deployment results and incidents are posted. A channel is either global, notified
+	// of every deployment, or bound to a specific application.
+	Channel struct {
+		event.Emitter
+
+		id      ChannelID
+		name    string
+		config  ChannelConfig
+		app     monad.Maybe[deployment.AppID]
+		created shared.Action[auth.UserID]
+	}
+
+	// Result of a deployment to post on channels.
+	DeploymentResult struct {
+		AppID            deployment.AppID
+		AppName          string
+		DeploymentNumber int
+		Environment      string
+		Succeeded        bool
+		ErrCode          monad.Maybe[string]
+	}
+
+	// Incident which happened to a container of a deployment to post on channels.
+	IncidentReport struct {
+		ID               string
+		AppID            deployment.AppID
+		AppName          string
+		DeploymentNumber int
+		Environment      string
+		Service          string
+		Kind             deployment.IncidentKind
+		ExitCode         monad.Maybe[int]
+	}
+
+	// Change of the status of an app environment public url to post on channels.
+	UptimeReport struct {
+		MonitorID   string
+		AppID       deployment.AppID
+		AppName     string
+		Environment string
+		Url         string
+		Up          bool
+		Reason      monad.Maybe[string]
+		ChangedAt   time.Time
+	}
+
+	// Posts formatted messages on channels.
+	ChannelNotifier interface {
+		Notify(context.Context, Channel, DeploymentResult) error
+		NotifyIncident(context.Context, Channel, IncidentReport) error
+		NotifyUptime(context.Context, Channel, UptimeReport) error
+	}
+
+	ChannelsReader interface {
+		GetByID(context.Context, ChannelID) (Channel, error)
+		// Retrieve channels which should be notified of the given app deployments, global
+		// ones included.
+		GetForApp(context.Context, deployment.AppID) ([]Channel, error)
+	}
+
+	ChannelsWriter interface {
+		Write(context.Context, ...*Channel) error
+	}
+
+	ChannelCreated struct {
+		bus.Notification
+
+		ID      ChannelID
+		Name    string
+		Config  ChannelConfig
+		App     monad.Maybe[deployment.AppID]
+		Created shared.Action[auth.UserID]
+	}
+
+	ChannelRenamed struct {
+		bus.Notification
+
+		ID   ChannelID
+		Name string
+	}
+
+	ChannelConfigChanged struct {
+		bus.Notification
+
+		ID     ChannelID
+		Config ChannelConfig
+	}
+
+	ChannelDeleted struct {
+		bus.Notification
+
+		ID ChannelID
+	}
+)
+
+func (ChannelCreated) Name_() string       { return "notification.event.channel_created" }
+func (ChannelRenamed) Name_() string       { return "notification.event.channel_renamed" }
+func (ChannelConfigChanged) Name_() string { return "notification.event.channel_config_changed" }
+func (ChannelDeleted) Name_() string       { return "notification.event.channel_deleted" }
+
+// Try to parse the given channel kind.
+func ChannelKindFrom(value string) (ChannelKind, error) {
+	switch kind := ChannelKind(value); kind {
+	case ChannelKindSlack, ChannelKindDiscord, ChannelKindMatrix:
+		return kind, nil
+	default:
+		return "", ErrInvalidChannelKind
+	}
+}
+
+// Builds the configuration of a slack channel given its incoming webhook url.
+func SlackConfig(url Url) ChannelConfig {
+	return ChannelConfig{Kind: ChannelKindSlack, Url: url}
+}
+
+// Builds the configuration of a discord channel given its webhook url.
+func DiscordConfig(url Url) ChannelConfig {
+	return ChannelConfig{Kind: ChannelKindDiscord, Url: url}
+}
+
+// Builds the configuration of a matrix room.
+func MatrixConfig(homeserver Url, roomID, accessToken string) ChannelConfig {
+	return ChannelConfig{
+		Kind:        ChannelKindMatrix,
+		Url:         homeserver,
+		RoomID:      roomID,
+		AccessToken: accessToken,
+	}
+}
+
+func (c ChannelConfig) Value() (driver.Value, error) { return storage.ValueJSON(c) }
+func (c *ChannelConfig) Scan(value any) error        { return storage.ScanJSON(value, c) }
+
+// Creates a new channel, bound to the given app if any.
+func NewChannel(name string, config ChannelConfig, app monad.Maybe[deployment.AppID], uid auth.UserID) (c Channel) {
+	c.apply(ChannelCreated{
+		ID:      id.New[ChannelID](),
+		Name:    name,
+		Config:  config,
+		App:     app,
+		Created: shared.NewAction(uid),
+	})
+
+	return c
+}
+
+// Recreates a channel from the persistent storage.
+func ChannelFrom(scanner storage.Scanner) (c Channel, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&c.id,
+		&c.name,
+		&c.config,
+		&c.app,
+		&createdAt,
+		&createdBy,
+	)
+
+	c.created = shared.ActionFrom(createdBy, createdAt)
+
+	return c, err
+}
+
+// Renames the channel.
+func (c *Channel) Rename(name string) {
+	if c.name == name {
+		return
+	}
+
+	c.apply(ChannelRenamed{
+		ID:   c.id,
+		Name: name,
+	})
+}
+
+// Updates the configuration used to post messages on the channel.
+func (c *Channel) HasConfig(config ChannelConfig) {
+	if c.config == config {
+		return
+	}
+
+	c.apply(ChannelConfigChanged{
+		ID:     c.id,
+		Config: config,
+	})
+}
+
+func (c *Channel) Delete() {
+	c.apply(ChannelDeleted{
+		ID: c.id,
+	})
+}
+
+func (c *Channel) ID() ChannelID                      { return c.id }
+func (c *Channel) Name() string                       { return c.name }
+func (c *Channel) Config() ChannelConfig              { return c.config }
+func (c *Channel) App() monad.Maybe[deployment.AppID] { return c.app }
+
+func (c *Channel) apply(e event.Event) {
+	switch evt := e.(type) {
+	case ChannelCreated:
+		c.id = evt.ID
+		c.name = evt.Name
+		c.config = evt.Config
+		c.app = evt.App
+		c.created = evt.Created
+	case ChannelRenamed:
+		c.name = evt.Name
+	case ChannelConfigChanged:
+		c.config = evt.Config
+	}
+
+	event.Store(c, e)
+}
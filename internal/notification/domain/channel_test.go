@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_ChannelKind(t *testing.T) {
+	t.Run("should accept known kinds", func(t *testing.T) {
+		for _, raw := range []string{"slack", "discord", "matrix"} {
+			kind, err := domain.ChannelKindFrom(raw)
+
+			testutil.IsNil(t, err)
+			testutil.Equals(t, domain.ChannelKind(raw), kind)
+		}
+	})
+
+	t.Run("should reject unknown kinds", func(t *testing.T) {
+		_, err := domain.ChannelKindFrom("irc")
+
+		testutil.ErrorIs(t, domain.ErrInvalidChannelKind, err)
+	})
+}
+
+func Test_Channel(t *testing.T) {
+	t.Run("could be created", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.SlackConfig("https://hooks.slack.com/services/xxx"), monad.Value[deployment.AppID]("app"), "uid")
+
+		created := testutil.EventIs[domain.ChannelCreated](t, &channel, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "ops", created.Name)
+		testutil.Equals(t, domain.ChannelConfig{
+			Kind: domain.ChannelKindSlack,
+			Url:  "https://hooks.slack.com/services/xxx",
+		}, created.Config)
+		testutil.Equals(t, "app", created.App.MustGet())
+		testutil.Equals(t, "uid", created.Created.By())
+	})
+
+	t.Run("should raise events only if something has changed", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "token"), monad.None[deployment.AppID](), "uid")
+
+		channel.Rename("ops")
+		channel.HasConfig(domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "token"))
+
+		testutil.HasNEvents(t, &channel, 1)
+
+		channel.Rename("deployments")
+		channel.HasConfig(domain.MatrixConfig("https://matrix.org", "!room:matrix.org", "another-token"))
+
+		testutil.HasNEvents(t, &channel, 3)
+		renamed := testutil.EventIs[domain.ChannelRenamed](t, &channel, 1)
+		testutil.Equals(t, "deployments", renamed.Name)
+		changed := testutil.EventIs[domain.ChannelConfigChanged](t, &channel, 2)
+		testutil.Equals(t, "another-token", changed.Config.AccessToken)
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		channel := domain.NewChannel("ops", domain.DiscordConfig("https://discord.com/api/webhooks/xxx"), monad.None[deployment.AppID](), "uid")
+
+		channel.Delete()
+
+		deleted := testutil.EventIs[domain.ChannelDeleted](t, &channel, 1)
+		testutil.Equals(t, channel.ID(), deleted.ID)
+	})
+}
@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+// Maximum number of attempts made to deliver a payload before giving up.
+const MaxDeliveryAttempts = 5
+
+const (
+	DeliveryStatusPending DeliveryStatus = iota
+	DeliveryStatusSucceeded
+	DeliveryStatusFailed
+)
+
+var ErrUnexpectedStatusCode = apperr.New("unexpected_status_code")
+
+type (
+	DeliveryID     string
+	DeliveryStatus uint8
+
+	// Payload sent, or to be sent, to a webhook. Every attempt is tracked so users
+	// could see why a receiver did not get notified.
+	Delivery struct {
+		event.Emitter
+
+		id            DeliveryID
+		webhook       WebhookID
+		event         EventType
+		payload       string
+		status        DeliveryStatus
+		attempts      int
+		statusCode    monad.Maybe[int]
+		errcode       monad.Maybe[string]
+		createdAt     time.Time
+		lastAttemptAt monad.Maybe[time.Time]
+	}
+
+	// Sends payloads to webhook endpoints and returns the status code of the response
+	// if any. Non 2xx responses should be returned as errors.
+	WebhookSender interface {
+		Send(context.Context, Webhook, Delivery) (int, error)
+	}
+
+	DeliveriesReader interface {
+		GetByID(context.Context, DeliveryID) (Delivery, error)
+	}
+
+	DeliveriesWriter interface {
+		Write(context.Context, ...*Delivery) error
+	}
+
+	DeliveryCreated struct {
+		bus.Notification
+
+		ID        DeliveryID
+		Webhook   WebhookID
+		Event     EventType
+		Payload   string
+		Status    DeliveryStatus
+		CreatedAt time.Time
+	}
+
+	DeliveryAttempted struct {
+		bus.Notification
+
+		ID          DeliveryID
+		Status      DeliveryStatus
+		Attempts    int
+		StatusCode  monad.Maybe[int]
+		ErrCode     monad.Maybe[string]
+		AttemptedAt time.Time
+	}
+)
+
+func (DeliveryCreated) Name_() string   { return "notification.event.delivery_created" }
+func (DeliveryAttempted) Name_() string { return "notification.event.delivery_attempted" }
+
+// Prepares the delivery of the given json payload to the webhook.
+func (w *Webhook) Deliver(evt EventType, payload string) (d Delivery) {
+	d.apply(DeliveryCreated{
+		ID:        id.New[DeliveryID](),
+		Webhook:   w.id,
+		Event:     evt,
+		Payload:   payload,
+		Status:    DeliveryStatusPending,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return d
+}
+
+// Recreates a delivery from the persistent storage.
+func DeliveryFrom(scanner storage.Scanner) (d Delivery, err error) {
+	var statusCode monad.Maybe[int64]
+
+	err = scanner.Scan(
+		&d.id,
+		&d.webhook,
+		&d.event,
+		&d.payload,
+		&d.status,
+		&d.attempts,
+		&statusCode,
+		&d.errcode,
+		&d.createdAt,
+		&d.lastAttemptAt,
+	)
+
+	if code, isSet := statusCode.TryGet(); isSet {
+		d.statusCode.Set(int(code))
+	}
+
+	return d, err
+}
+
+// Records the result of an attempt to deliver the payload. If it has failed, the
+// delivery stays pending until the maximum number of attempts has been reached.
+func (d *Delivery) Attempted(statusCode int, err error) {
+	if d.status != DeliveryStatusPending {
+		return
+	}
+
+	evt := DeliveryAttempted{
+		ID:          d.id,
+		Status:      DeliveryStatusSucceeded,
+		Attempts:    d.attempts + 1,
+		AttemptedAt: time.Now().UTC(),
+	}
+
+	if statusCode != 0 {
+		evt.StatusCode.Set(statusCode)
+	}
+
+	if err != nil {
+		evt.ErrCode.Set(err.Error())
+		evt.Status = DeliveryStatusPending
+
+		if evt.Attempts >= MaxDeliveryAttempts {
+			evt.Status = DeliveryStatusFailed
+		}
+	}
+
+	d.apply(evt)
+}
+
+func (d *Delivery) ID() DeliveryID         { return d.id }
+func (d *Delivery) Webhook() WebhookID     { return d.webhook }
+func (d *Delivery) Event() EventType       { return d.event }
+func (d *Delivery) Payload() string        { return d.payload }
+func (d *Delivery) Status() DeliveryStatus { return d.status }
+func (d *Delivery) Attempts() int          { return d.attempts }
+
+func (d *Delivery) apply(e event.Event) {
+	switch evt := e.(type) {
+	case DeliveryCreated:
+		d.id = evt.ID
+		d.webhook = evt.Webhook
+		d.event = evt.Event
+		d.payload = evt.Payload
+		d.status = evt.Status
+		d.createdAt = evt.CreatedAt
+	case DeliveryAttempted:
+		d.status = evt.Status
+		d.attempts = evt.Attempts
+		d.statusCode = evt.StatusCode
+		d.errcode = evt.ErrCode
+		d.lastAttemptAt.Set(evt.AttemptedAt)
+	}
+
+	event.Store(d, e)
+}
@@ -0,0 +1,65 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Delivery(t *testing.T) {
+	webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+
+	t.Run("could be created from a webhook", func(t *testing.T) {
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+
+		created := testutil.EventIs[domain.DeliveryCreated](t, &delivery, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, webhook.ID(), created.Webhook)
+		testutil.Equals(t, domain.EventDeploymentFailed, created.Event)
+		testutil.Equals(t, `{}`, created.Payload)
+		testutil.Equals(t, domain.DeliveryStatusPending, created.Status)
+		testutil.IsFalse(t, created.CreatedAt.IsZero())
+	})
+
+	t.Run("should succeed if no error is given", func(t *testing.T) {
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+
+		delivery.Attempted(200, nil)
+
+		attempted := testutil.EventIs[domain.DeliveryAttempted](t, &delivery, 1)
+		testutil.Equals(t, domain.DeliveryStatusSucceeded, attempted.Status)
+		testutil.Equals(t, 1, attempted.Attempts)
+		testutil.Equals(t, 200, attempted.StatusCode.MustGet())
+		testutil.IsFalse(t, attempted.ErrCode.HasValue())
+	})
+
+	t.Run("should stay pending until the maximum number of attempts is reached", func(t *testing.T) {
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+		err := errors.New("some error")
+
+		for i := 1; i < domain.MaxDeliveryAttempts; i++ {
+			delivery.Attempted(0, err)
+			testutil.Equals(t, domain.DeliveryStatusPending, delivery.Status())
+		}
+
+		delivery.Attempted(500, err)
+
+		testutil.Equals(t, domain.DeliveryStatusFailed, delivery.Status())
+		attempted := testutil.EventIs[domain.DeliveryAttempted](t, &delivery, domain.MaxDeliveryAttempts)
+		testutil.Equals(t, domain.MaxDeliveryAttempts, attempted.Attempts)
+		testutil.Equals(t, 500, attempted.StatusCode.MustGet())
+		testutil.Equals(t, "some error", attempted.ErrCode.MustGet())
+	})
+
+	t.Run("should not be attempted once done", func(t *testing.T) {
+		delivery := webhook.Deliver(domain.EventDeploymentFailed, `{}`)
+		delivery.Attempted(204, nil)
+
+		delivery.Attempted(500, errors.New("some error"))
+
+		testutil.HasNEvents(t, &delivery, 2)
+		testutil.Equals(t, domain.DeliveryStatusSucceeded, delivery.Status())
+	})
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+// Certificates expiring in less than this duration trigger a warning.
+const CertificateExpiryThreshold = 14 * 24 * time.Hour
+
+type (
+	// Plain text email sent to a single recipient.
+	Email struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	EmailSender interface {
+		Send(context.Context, Email) error
+	}
+
+	// Emails a user wants to receive. Users without stored preferences receive every email.
+	EmailPreferences struct {
+		event.Emitter
+
+		user               auth.UserID
+		deploymentFailures bool
+		certificateExpiry  bool
+	}
+
+	EmailPreferencesReader interface {
+		// Retrieve preferences of the given user, the default ones if none has been saved yet.
+		GetByUserID(context.Context, auth.UserID) (EmailPreferences, error)
+	}
+
+	EmailPreferencesWriter interface {
+		Write(context.Context, ...*EmailPreferences) error
+	}
+
+	// Retrieve email addresses of users which should be notified, taking their
+	// preferences into account and ignoring disabled ones.
+	RecipientsReader interface {
+		// Retrieve the user who has requested the given deployment.
+		GetDeploymentFailureRecipients(context.Context, deployment.DeploymentID) ([]string, error)
+		// Retrieve admins since they are the ones able to act on targets.
+		GetCertificateExpiryRecipients(context.Context) ([]string, error)
+	}
+
+	// Retrieve hosts exposed over HTTPS by seelf, which certificates should be checked.
+	ExposedHostsReader interface {
+		GetExposedHosts(context.Context) ([]string, error)
+	}
+
+	// Retrieve the expiration date of the certificate served by a host.
+	CertificatesInspector interface {
+		ExpiresAt(ctx context.Context, host string) (time.Time, error)
+	}
+
+	EmailPreferencesChanged struct {
+		bus.Notification
+
+		UserID             auth.UserID
+		DeploymentFailures bool
+		CertificateExpiry  bool
+	}
+)
+
+func (EmailPreferencesChanged) Name_() string { return "notification.event.email_preferences_changed" }
+
+// Builds the preferences of a user which has not saved any yet. Every email is enabled.
+func DefaultEmailPreferences(uid auth.UserID) EmailPreferences {
+	return EmailPreferences{
+		user:               uid,
+		deploymentFailures: true,
+		certificateExpiry:  true,
+	}
+}
+
+// Recreates email preferences from the persistent storage.
+func EmailPreferencesFrom(scanner storage.Scanner) (p EmailPreferences, err error) {
+	err = scanner.Scan(
+		&p.user,
+		&p.deploymentFailures,
+		&p.certificateExpiry,
+	)
+
+	return p, err
+}
+
+// Updates which emails the user wants to receive.
+func (p *EmailPreferences) Update(deploymentFailures, certificateExpiry bool) {
+	if p.deploymentFailures == deploymentFailures && p.certificateExpiry == certificateExpiry {
+		return
+	}
+
+	p.apply(EmailPreferencesChanged{
+		UserID:             p.user,
+		DeploymentFailures: deploymentFailures,
+		CertificateExpiry:  certificateExpiry,
+	})
+}
+
+// Returns true if the certificate expiring at the given date should be renewed soon.
+func IsCertificateExpiringSoon(expiresAt, now time.Time) bool {
+	return expiresAt.Sub(now) < CertificateExpiryThreshold
+}
+
+func (p *EmailPreferences) UserID() auth.UserID      { return p.user }
+func (p *EmailPreferences) DeploymentFailures() bool { return p.deploymentFailures }
+func (p *EmailPreferences) CertificateExpiry() bool  { return p.certificateExpiry }
+
+func (p *EmailPreferences) apply(e event.Event) {
+	switch evt := e.(type) {
+	case EmailPreferencesChanged:
+		p.deploymentFailures = evt.DeploymentFailures
+		p.certificateExpiry = evt.CertificateExpiry
+	}
+
+	event.Store(p, e)
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_EmailPreferences(t *testing.T) {
+	t.Run("should enable every email by default", func(t *testing.T) {
+		preferences := domain.DefaultEmailPreferences("uid")
+
+		testutil.Equals(t, "uid", preferences.UserID())
+		testutil.IsTrue(t, preferences.DeploymentFailures())
+		testutil.IsTrue(t, preferences.CertificateExpiry())
+		testutil.HasNEvents(t, &preferences, 0)
+	})
+
+	t.Run("should raise events only if something has changed", func(t *testing.T) {
+		preferences := domain.DefaultEmailPreferences("uid")
+
+		preferences.Update(true, true)
+
+		testutil.HasNEvents(t, &preferences, 0)
+
+		preferences.Update(false, true)
+
+		testutil.HasNEvents(t, &preferences, 1)
+		changed := testutil.EventIs[domain.EmailPreferencesChanged](t, &preferences, 0)
+		testutil.Equals(t, "uid", changed.UserID)
+		testutil.IsFalse(t, changed.DeploymentFailures)
+		testutil.IsTrue(t, changed.CertificateExpiry)
+	})
+}
+
+func Test_IsCertificateExpiringSoon(t *testing.T) {
+	now := time.Now()
+
+	testutil.IsTrue(t, domain.IsCertificateExpiringSoon(now.Add(-time.Hour), now))
+	testutil.IsTrue(t, domain.IsCertificateExpiringSoon(now.Add(13*24*time.Hour), now))
+	testutil.IsFalse(t, domain.IsCertificateExpiringSoon(now.Add(15*24*time.Hour), now))
+}
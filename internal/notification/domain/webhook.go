@@ -0,0 +1,308 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidUrl       = apperr.New("invalid_url")
+	ErrInvalidEventType = apperr.New("invalid_event_type")
+)
+
+const (
+	EventDeploymentStarted   EventType = "deployment_started"
+	EventDeploymentSucceeded EventType = "deployment_succeeded"
+	EventDeploymentFailed    EventType = "deployment_failed"
+	EventTargetUnreachable   EventType = "target_unreachable"
+	EventCleanupCompleted    EventType = "cleanup_completed"
+	EventContainerIncident   EventType = "container_incident"
+	EventAppDown             EventType = "app_down"
+	EventAppUp               EventType = "app_up"
+)
+
+type (
+	WebhookID string
+
+	// Endpoint url of a webhook, only http and https urls are accepted.
+	Url string
+
+	// Type of event a webhook could be notified about.
+	EventType string
+
+	// Events a webhook is interested in, an empty filter means every event. Stored as
+	// a json array in the database.
+	EventFilter []EventType
+
+	// Endpoint notified by seelf when something happens on deployments and targets.
+	// Payloads are signed with the webhook secret so receivers could check they come
+	// from this seelf instance.
+	Webhook struct {
+		event.Emitter
+
+		id      WebhookID
+		name    string
+		url     Url
+		secret  string
+		events  EventFilter
+		created shared.Action[auth.UserID]
+	}
+
+	WebhooksReader interface {
+		GetByID(context.Context, WebhookID) (Webhook, error)
+		GetAll(context.Context) ([]Webhook, error)
+	}
+
+	WebhooksWriter interface {
+		Write(context.Context, ...*Webhook) error
+	}
+
+	WebhookCreated struct {
+		bus.Notification
+
+		ID      WebhookID
+		Name    string
+		Url     Url
+		Secret  string
+		Events  EventFilter
+		Created shared.Action[auth.UserID]
+	}
+
+	WebhookRenamed struct {
+		bus.Notification
+
+		ID   WebhookID
+		Name string
+	}
+
+	WebhookUrlChanged struct {
+		bus.Notification
+
+		ID  WebhookID
+		Url Url
+	}
+
+	WebhookSecretChanged struct {
+		bus.Notification
+
+		ID     WebhookID
+		Secret string
+	}
+
+	WebhookEventsChanged struct {
+		bus.Notification
+
+		ID     WebhookID
+		Events EventFilter
+	}
+
+	WebhookDeleted struct {
+		bus.Notification
+
+		ID WebhookID
+	}
+)
+
+func (WebhookCreated) Name_() string       { return "notification.event.webhook_created" }
+func (WebhookRenamed) Name_() string       { return "notification.event.webhook_renamed" }
+func (WebhookUrlChanged) Name_() string    { return "notification.event.webhook_url_changed" }
+func (WebhookSecretChanged) Name_() string { return "notification.event.webhook_secret_changed" }
+func (WebhookEventsChanged) Name_() string { return "notification.event.webhook_events_changed" }
+func (WebhookDeleted) Name_() string       { return "notification.event.webhook_deleted" }
+
+// Try to parse the given raw url as a webhook endpoint.
+func UrlFrom(raw string) (Url, error) {
+	u, err := url.Parse(raw)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrInvalidUrl
+	}
+
+	return Url(u.String()), nil
+}
+
+// Try to parse the given event type.
+func EventTypeFrom(value string) (EventType, error) {
+	switch evt := EventType(value); evt {
+	case EventDeploymentStarted,
+		EventDeploymentSucceeded,
+		EventDeploymentFailed,
+		EventTargetUnreachable,
+		EventCleanupCompleted,
+		EventContainerIncident,
+		EventAppDown,
+		EventAppUp:
+		return evt, nil
+	default:
+		return "", ErrInvalidEventType
+	}
+}
+
+// Try to parse the given event types. Duplicates are removed.
+func EventFilterFrom(values []string) (EventFilter, error) {
+	filter := make(EventFilter, 0, len(values))
+
+	for _, value := range values {
+		evt, err := EventTypeFrom(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(filter, evt) {
+			filter = append(filter, evt)
+		}
+	}
+
+	return filter, nil
+}
+
+// Check if the given event type is accepted by this filter.
+func (f EventFilter) Accepts(evt EventType) bool {
+	return len(f) == 0 || slices.Contains(f, evt)
+}
+
+func (f EventFilter) Value() (driver.Value, error) { return storage.ValueJSON(f) }
+func (f *EventFilter) Scan(value any) error        { return storage.ScanJSON(value, f) }
+
+// Creates a new webhook notified of events matching the given filter.
+func NewWebhook(name string, url Url, secret string, events EventFilter, uid auth.UserID) (w Webhook) {
+	w.apply(WebhookCreated{
+		ID:      id.New[WebhookID](),
+		Name:    name,
+		Url:     url,
+		Secret:  secret,
+		Events:  events,
+		Created: shared.NewAction(uid),
+	})
+
+	return w
+}
+
+// Recreates a webhook from the persistent storage.
+func WebhookFrom(scanner storage.Scanner) (w Webhook, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&w.id,
+		&w.name,
+		&w.url,
+		&w.secret,
+		&w.events,
+		&createdAt,
+		&createdBy,
+	)
+
+	w.created = shared.ActionFrom(createdBy, createdAt)
+
+	return w, err
+}
+
+// Renames the webhook.
+func (w *Webhook) Rename(name string) {
+	if w.name == name {
+		return
+	}
+
+	w.apply(WebhookRenamed{
+		ID:   w.id,
+		Name: name,
+	})
+}
+
+// Updates the endpoint of the webhook.
+func (w *Webhook) HasUrl(url Url) {
+	if w.url == url {
+		return
+	}
+
+	w.apply(WebhookUrlChanged{
+		ID:  w.id,
+		Url: url,
+	})
+}
+
+// Updates the secret used to sign payloads.
+func (w *Webhook) HasSecret(secret string) {
+	if w.secret == secret {
+		return
+	}
+
+	w.apply(WebhookSecretChanged{
+		ID:     w.id,
+		Secret: secret,
+	})
+}
+
+// Updates events the webhook is interested in.
+func (w *Webhook) ListensTo(events EventFilter) {
+	if slices.Equal(w.events, events) {
+		return
+	}
+
+	w.apply(WebhookEventsChanged{
+		ID:     w.id,
+		Events: events,
+	})
+}
+
+func (w *Webhook) Delete() {
+	w.apply(WebhookDeleted{
+		ID: w.id,
+	})
+}
+
+// Check if the webhook should be notified of the given event type.
+func (w *Webhook) Listens(evt EventType) bool { return w.events.Accepts(evt) }
+
+// Computes the signature of the given payload, an hex encoded HMAC-SHA256 using the
+// webhook secret.
+func (w *Webhook) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *Webhook) ID() WebhookID       { return w.id }
+func (w *Webhook) Name() string        { return w.name }
+func (w *Webhook) Url() Url            { return w.url }
+func (w *Webhook) Events() EventFilter { return w.events }
+
+func (w *Webhook) apply(e event.Event) {
+	switch evt := e.(type) {
+	case WebhookCreated:
+		w.id = evt.ID
+		w.name = evt.Name
+		w.url = evt.Url
+		w.secret = evt.Secret
+		w.events = evt.Events
+		w.created = evt.Created
+	case WebhookRenamed:
+		w.name = evt.Name
+	case WebhookUrlChanged:
+		w.url = evt.Url
+	case WebhookSecretChanged:
+		w.secret = evt.Secret
+	case WebhookEventsChanged:
+		w.events = evt.Events
+	}
+
+	event.Store(w, e)
+}
@@ -0,0 +1,108 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Url(t *testing.T) {
+	t.Run("should accept http and https urls", func(t *testing.T) {
+		for _, raw := range []string{"http://example.com/hook", "https://example.com"} {
+			u, err := domain.UrlFrom(raw)
+
+			testutil.IsNil(t, err)
+			testutil.Equals(t, domain.Url(raw), u)
+		}
+	})
+
+	t.Run("should reject invalid urls", func(t *testing.T) {
+		for _, raw := range []string{"", "example.com", "ftp://example.com", "http://"} {
+			_, err := domain.UrlFrom(raw)
+
+			testutil.ErrorIs(t, domain.ErrInvalidUrl, err)
+		}
+	})
+}
+
+func Test_EventFilter(t *testing.T) {
+	t.Run("should reject unknown event types", func(t *testing.T) {
+		_, err := domain.EventFilterFrom([]string{"deployment_failed", "unknown"})
+
+		testutil.ErrorIs(t, domain.ErrInvalidEventType, err)
+	})
+
+	t.Run("should remove duplicates", func(t *testing.T) {
+		filter, err := domain.EventFilterFrom([]string{"deployment_failed", "deployment_failed", "cleanup_completed"})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, domain.EventFilter{domain.EventDeploymentFailed, domain.EventCleanupCompleted}, filter)
+	})
+
+	t.Run("should accept every event when empty", func(t *testing.T) {
+		var filter domain.EventFilter
+
+		testutil.IsTrue(t, filter.Accepts(domain.EventDeploymentStarted))
+		testutil.IsTrue(t, filter.Accepts(domain.EventTargetUnreachable))
+	})
+
+	t.Run("should only accept listed events", func(t *testing.T) {
+		filter := domain.EventFilter{domain.EventDeploymentFailed}
+
+		testutil.IsTrue(t, filter.Accepts(domain.EventDeploymentFailed))
+		testutil.IsFalse(t, filter.Accepts(domain.EventDeploymentSucceeded))
+	})
+}
+
+func Test_Webhook(t *testing.T) {
+	t.Run("could be created", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", domain.EventFilter{domain.EventDeploymentFailed}, "uid")
+
+		created := testutil.EventIs[domain.WebhookCreated](t, &webhook, 0)
+		testutil.NotEquals(t, "", created.ID)
+		testutil.Equals(t, "my-hook", created.Name)
+		testutil.Equals(t, "https://example.com", created.Url)
+		testutil.Equals(t, "secret", created.Secret)
+		testutil.DeepEquals(t, domain.EventFilter{domain.EventDeploymentFailed}, created.Events)
+		testutil.Equals(t, "uid", created.Created.By())
+	})
+
+	t.Run("should raise events only if something has changed", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+
+		webhook.Rename("my-hook")
+		webhook.HasUrl("https://example.com")
+		webhook.HasSecret("secret")
+		webhook.ListensTo(nil)
+
+		testutil.HasNEvents(t, &webhook, 1)
+
+		webhook.Rename("new-hook")
+		webhook.HasUrl("https://example.org")
+		webhook.HasSecret("new-secret")
+		webhook.ListensTo(domain.EventFilter{domain.EventCleanupCompleted})
+
+		testutil.HasNEvents(t, &webhook, 5)
+		testutil.Equals(t, "new-hook", testutil.EventIs[domain.WebhookRenamed](t, &webhook, 1).Name)
+		testutil.Equals(t, "https://example.org", testutil.EventIs[domain.WebhookUrlChanged](t, &webhook, 2).Url)
+		testutil.Equals(t, "new-secret", testutil.EventIs[domain.WebhookSecretChanged](t, &webhook, 3).Secret)
+		testutil.DeepEquals(t, domain.EventFilter{domain.EventCleanupCompleted}, testutil.EventIs[domain.WebhookEventsChanged](t, &webhook, 4).Events)
+		testutil.IsFalse(t, webhook.Listens(domain.EventDeploymentFailed))
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+
+		webhook.Delete()
+
+		deleted := testutil.EventIs[domain.WebhookDeleted](t, &webhook, 1)
+		testutil.Equals(t, webhook.ID(), deleted.ID)
+	})
+
+	t.Run("should sign payloads with its secret", func(t *testing.T) {
+		webhook := domain.NewWebhook("my-hook", "https://example.com", "secret", nil, "uid")
+
+		testutil.Equals(t, "91ca480ac36fdf863f508a5b437f1d06663e92ea01615f678e10ea24e27256bf", webhook.Sign([]byte(`{"event":"deployment_failed"}`)))
+	})
+}
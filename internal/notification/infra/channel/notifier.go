@@ -0,0 +1,263 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+const (
+	timeout        = 10 * time.Second
+	userAgent      = "seelf-notifier"
+	maxErrorLength = 1000
+)
+
+type (
+	Options interface {
+		AppExposedUrl() monad.Maybe[deployment.Url]
+	}
+
+	notifier struct {
+		client  *http.Client
+		options Options
+	}
+
+	// Content of a message, formatted differently depending on the channel kind.
+	message struct {
+		emoji    string
+		title    string
+		errcode  monad.Maybe[string]
+		link     monad.Maybe[string]
+		linkText string
+	}
+)
+
+// Builds a notifier posting deployment results, incidents and uptime changes on slack, discord
+// and matrix channels. When seelf is exposed, messages contain a link to the related page.
+func NewNotifier(options Options) domain.ChannelNotifier {
+	return &notifier{
+		client:  &http.Client{Timeout: timeout},
+		options: options,
+	}
+}
+
+func (n *notifier) Notify(ctx context.Context, channel domain.Channel, result domain.DeploymentResult) error {
+	return n.post(ctx, channel, n.message(result), fmt.Sprintf("seelf-%s-%d", result.AppID, result.DeploymentNumber))
+}
+
+func (n *notifier) NotifyIncident(ctx context.Context, channel domain.Channel, report domain.IncidentReport) error {
+	return n.post(ctx, channel, n.incidentMessage(report), "seelf-incident-"+report.ID)
+}
+
+func (n *notifier) NotifyUptime(ctx context.Context, channel domain.Channel, report domain.UptimeReport) error {
+	return n.post(ctx, channel, n.uptimeMessage(report), fmt.Sprintf("seelf-uptime-%s-%d", report.MonitorID, report.ChangedAt.UnixNano()))
+}
+
+// Posts the message on the channel, the transaction id is used by matrix to deduplicate
+// messages sent multiple times.
+func (n *notifier) post(ctx context.Context, channel domain.Channel, msg message, txnID string) error {
+	config := channel.Config()
+
+	switch config.Kind {
+	case domain.ChannelKindSlack:
+		return n.send(ctx, http.MethodPost, string(config.Url), "", slackPayload(msg))
+	case domain.ChannelKindDiscord:
+		return n.send(ctx, http.MethodPost, string(config.Url), "", discordPayload(msg))
+	case domain.ChannelKindMatrix:
+		endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+			strings.TrimSuffix(string(config.Url), "/"),
+			url.PathEscape(config.RoomID),
+			url.PathEscape(txnID),
+		)
+
+		return n.send(ctx, http.MethodPut, endpoint, config.AccessToken, matrixPayload(msg))
+	default:
+		return domain.ErrInvalidChannelKind
+	}
+}
+
+func (n *notifier) message(result domain.DeploymentResult) (msg message) {
+	status, emoji := "succeeded", "✅"
+
+	if !result.Succeeded {
+		status, emoji = "failed", "❌"
+
+		if errcode, isSet := result.ErrCode.TryGet(); isSet {
+			if len(errcode) > maxErrorLength {
+				errcode = strings.ToValidUTF8(errcode[:maxErrorLength], "") + "…"
+			}
+
+			msg.errcode.Set(errcode)
+		}
+	}
+
+	msg.emoji = emoji
+	msg.title = fmt.Sprintf("Deployment #%d of %s (%s) %s", result.DeploymentNumber, result.AppName, result.Environment, status)
+	msg.link = n.link(fmt.Sprintf("apps/%s/deployments/%d", result.AppID, result.DeploymentNumber))
+	msg.linkText = "View deployment"
+
+	return msg
+}
+
+func (n *notifier) incidentMessage(report domain.IncidentReport) (msg message) {
+	var what string
+
+	switch report.Kind {
+	case deployment.IncidentOutOfMemory:
+		what = "has been killed because it ran out of memory"
+	case deployment.IncidentRestarted:
+		what = "has been restarted after exiting unexpectedly"
+	default:
+		what = "has exited unexpectedly"
+	}
+
+	if code, isSet := report.ExitCode.TryGet(); isSet && report.Kind != deployment.IncidentOutOfMemory {
+		what += fmt.Sprintf(" with code %d", code)
+	}
+
+	msg.emoji = "⚠️"
+	msg.title = fmt.Sprintf("Service %s of %s (%s) %s", report.Service, report.AppName, report.Environment, what)
+	msg.link = n.link(fmt.Sprintf("apps/%s/deployments/%d", report.AppID, report.DeploymentNumber))
+	msg.linkText = "View deployment"
+
+	return msg
+}
+
+func (n *notifier) uptimeMessage(report domain.UptimeReport) (msg message) {
+	if report.Up {
+		msg.emoji = "🟢"
+		msg.title = fmt.Sprintf("%s (%s) is back up at %s", report.AppName, report.Environment, report.Url)
+	} else {
+		msg.emoji = "🔴"
+		msg.title = fmt.Sprintf("%s (%s) is down at %s", report.AppName, report.Environment, report.Url)
+
+		if reason, isSet := report.Reason.TryGet(); isSet {
+			msg.title += ": " + reason
+		}
+	}
+
+	msg.link = n.link("apps/" + string(report.AppID))
+	msg.linkText = "View app"
+
+	return msg
+}
+
+// Link to the given page of the dashboard if seelf is exposed.
+func (n *notifier) link(path string) (link monad.Maybe[string]) {
+	if exposedUrl, isSet := n.options.AppExposedUrl().TryGet(); isSet {
+		link.Set(strings.TrimSuffix(exposedUrl.WithoutUser().String(), "/") + "/" + path)
+	}
+
+	return link
+}
+
+func (n *notifier) send(ctx context.Context, method, endpoint, token string, payload any) error {
+	body, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain the body so the connection could be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", domain.ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+
+	return nil
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackPayload(msg message) map[string]any {
+	var b strings.Builder
+
+	b.WriteString(msg.emoji + " " + slackEscaper.Replace(msg.title))
+
+	if errcode, isSet := msg.errcode.TryGet(); isSet {
+		b.WriteString("\n```" + slackEscaper.Replace(errcode) + "```")
+	}
+
+	if link, isSet := msg.link.TryGet(); isSet {
+		b.WriteString("\n<" + link + "|" + msg.linkText + ">")
+	}
+
+	return map[string]any{"text": b.String()}
+}
+
+func discordPayload(msg message) map[string]any {
+	var b strings.Builder
+
+	b.WriteString(msg.emoji + " " + msg.title)
+
+	if errcode, isSet := msg.errcode.TryGet(); isSet {
+		b.WriteString("\n```\n" + strings.ReplaceAll(errcode, "```", "'''") + "\n```")
+	}
+
+	if link, isSet := msg.link.TryGet(); isSet {
+		b.WriteString("\n[" + msg.linkText + "](" + link + ")")
+	}
+
+	return map[string]any{
+		"content": b.String(),
+		// Never ping anyone, whatever the error contains
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}
+}
+
+func matrixPayload(msg message) map[string]any {
+	var plain, formatted strings.Builder
+
+	plain.WriteString(msg.emoji + " " + msg.title)
+	formatted.WriteString(msg.emoji + " " + html.EscapeString(msg.title))
+
+	if errcode, isSet := msg.errcode.TryGet(); isSet {
+		plain.WriteString("\n" + errcode)
+		formatted.WriteString("<pre><code>" + html.EscapeString(errcode) + "</code></pre>")
+	}
+
+	if link, isSet := msg.link.TryGet(); isSet {
+		plain.WriteString("\n" + link)
+		formatted.WriteString(`<br/><a href="` + html.EscapeString(link) + `">` + msg.linkText + `</a>`)
+	}
+
+	return map[string]any{
+		"msgtype":        "m.notice",
+		"body":           plain.String(),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted.String(),
+	}
+}
@@ -0,0 +1,181 @@
+package channel_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/channel"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Notifier(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		notifier = channel.NewNotifier(options{monad.Value(must.Panic(deployment.UrlFrom("https://seelf@seelf.example.com")))})
+		failed   = domain.DeploymentResult{
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 3,
+			Environment:      "production",
+			ErrCode:          monad.Value("some error"),
+		}
+	)
+
+	receiver := func(t *testing.T, status int) (*httptest.Server, *http.Request, map[string]any) {
+		var (
+			req  http.Request
+			body = make(map[string]any)
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = *r
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+
+		return server, &req, body
+	}
+
+	t.Run("should post slack messages", func(t *testing.T) {
+		server, req, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.SlackConfig(domain.Url(server.URL)), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.Notify(ctx, c, failed)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, http.MethodPost, req.Method)
+		testutil.Equals(t, "❌ Deployment #3 of my-app (production) failed\n```some error```\n<https://seelf.example.com/apps/app-id/deployments/3|View deployment>", body["text"].(string))
+	})
+
+	t.Run("should post discord messages", func(t *testing.T) {
+		server, req, body := receiver(t, http.StatusNoContent)
+		c := domain.NewChannel("ops", domain.DiscordConfig(domain.Url(server.URL)), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.Notify(ctx, c, domain.DeploymentResult{
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 4,
+			Environment:      "staging",
+			Succeeded:        true,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, http.MethodPost, req.Method)
+		testutil.Equals(t, "✅ Deployment #4 of my-app (staging) succeeded\n[View deployment](https://seelf.example.com/apps/app-id/deployments/4)", body["content"].(string))
+	})
+
+	t.Run("should send matrix messages", func(t *testing.T) {
+		server, req, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.MatrixConfig(domain.Url(server.URL), "!room:matrix.org", "token"), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.Notify(ctx, c, failed)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, http.MethodPut, req.Method)
+		testutil.Equals(t, "/_matrix/client/v3/rooms/%21room:matrix.org/send/m.room.message/seelf-app-id-3", req.URL.EscapedPath())
+		testutil.Equals(t, "Bearer token", req.Header.Get("Authorization"))
+		testutil.Equals(t, "m.notice", body["msgtype"].(string))
+		testutil.Equals(t, "❌ Deployment #3 of my-app (production) failed\nsome error\nhttps://seelf.example.com/apps/app-id/deployments/3", body["body"].(string))
+	})
+
+	t.Run("should post incidents", func(t *testing.T) {
+		server, _, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.SlackConfig(domain.Url(server.URL)), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.NotifyIncident(ctx, c, domain.IncidentReport{
+			ID:               "incident-id",
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 3,
+			Environment:      "production",
+			Service:          "app",
+			Kind:             deployment.IncidentRestarted,
+			ExitCode:         monad.Value(1),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "⚠️ Service app of my-app (production) has been restarted after exiting unexpectedly with code 1\n<https://seelf.example.com/apps/app-id/deployments/3|View deployment>", body["text"].(string))
+	})
+
+	t.Run("should send matrix incidents with their own transaction id", func(t *testing.T) {
+		server, req, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.MatrixConfig(domain.Url(server.URL), "!room:matrix.org", "token"), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.NotifyIncident(ctx, c, domain.IncidentReport{
+			ID:               "incident-id",
+			AppID:            "app-id",
+			AppName:          "my-app",
+			DeploymentNumber: 3,
+			Environment:      "staging",
+			Service:          "worker",
+			Kind:             deployment.IncidentOutOfMemory,
+			ExitCode:         monad.Value(137),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "/_matrix/client/v3/rooms/%21room:matrix.org/send/m.room.message/seelf-incident-incident-id", req.URL.EscapedPath())
+		testutil.Equals(t, "⚠️ Service worker of my-app (staging) has been killed because it ran out of memory\nhttps://seelf.example.com/apps/app-id/deployments/3", body["body"].(string))
+	})
+
+	t.Run("should post uptime changes", func(t *testing.T) {
+		server, _, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.SlackConfig(domain.Url(server.URL)), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.NotifyUptime(ctx, c, domain.UptimeReport{
+			MonitorID:   "monitor-id",
+			AppID:       "app-id",
+			AppName:     "my-app",
+			Environment: "production",
+			Url:         "https://my-app.example.com",
+			Reason:      monad.Value("unexpected status code 502"),
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "🔴 my-app (production) is down at https://my-app.example.com: unexpected status code 502\n<https://seelf.example.com/apps/app-id|View app>", body["text"].(string))
+	})
+
+	t.Run("should send matrix uptime changes with their own transaction id", func(t *testing.T) {
+		server, req, body := receiver(t, http.StatusOK)
+		c := domain.NewChannel("ops", domain.MatrixConfig(domain.Url(server.URL), "!room:matrix.org", "token"), monad.None[deployment.AppID](), "uid")
+		changedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		err := notifier.NotifyUptime(ctx, c, domain.UptimeReport{
+			MonitorID:   "monitor-id",
+			AppID:       "app-id",
+			AppName:     "my-app",
+			Environment: "staging",
+			Url:         "https://my-app-staging.example.com",
+			Up:          true,
+			ChangedAt:   changedAt,
+		})
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "/_matrix/client/v3/rooms/%21room:matrix.org/send/m.room.message/seelf-uptime-monitor-id-"+strconv.FormatInt(changedAt.UnixNano(), 10), req.URL.EscapedPath())
+		testutil.Equals(t, "🟢 my-app (staging) is back up at https://my-app-staging.example.com\nhttps://seelf.example.com/apps/app-id", body["body"].(string))
+	})
+
+	t.Run("should return an error on non successful responses", func(t *testing.T) {
+		server, _, _ := receiver(t, http.StatusNotFound)
+		c := domain.NewChannel("ops", domain.SlackConfig(domain.Url(server.URL)), monad.None[deployment.AppID](), "uid")
+
+		err := notifier.Notify(ctx, c, failed)
+
+		testutil.ErrorIs(t, domain.ErrUnexpectedStatusCode, err)
+	})
+}
+
+type options struct {
+	exposedUrl monad.Maybe[deployment.Url]
+}
+
+func (o options) AppExposedUrl() monad.Maybe[deployment.Url] { return o.exposedUrl }
@@ -0,0 +1,75 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+const (
+	httpsPort      = "443"
+	inspectTimeout = 10 * time.Second
+)
+
+var errNoCertificate = errors.New("no_certificate")
+
+type inspector struct {
+	logger log.Logger
+}
+
+// Builds an inspector connecting to hosts over TLS to read their certificate. The
+// certificate is not verified since an expired one should still be reported.
+func NewInspector(logger log.Logger) domain.CertificatesInspector {
+	return &inspector{logger}
+}
+
+func (i *inspector) ExpiresAt(ctx context.Context, host string) (time.Time, error) {
+	expiresAt, err := i.inspect(ctx, host)
+
+	if err != nil {
+		i.logger.Warnw("could not inspect certificate",
+			"host", host,
+			"error", err)
+	}
+
+	return expiresAt, err
+}
+
+func (*inspector) inspect(ctx context.Context, host string) (time.Time, error) {
+	hostname, addr := host, host
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	} else {
+		addr = net.JoinHostPort(host, httpsPort)
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: inspectTimeout},
+		Config: &tls.Config{
+			ServerName:         hostname,
+			InsecureSkipVerify: true, // Only used to read the expiration date
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	defer conn.Close()
+
+	certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+
+	if len(certificates) == 0 {
+		return time.Time{}, errNoCertificate
+	}
+
+	return certificates[0].NotAfter, nil
+}
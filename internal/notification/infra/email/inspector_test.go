@@ -0,0 +1,37 @@
+package email_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/infra/email"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Inspector(t *testing.T) {
+	inspector := email.NewInspector(must.Panic(log.NewLogger()))
+
+	t.Run("should retrieve the certificate expiration date", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		t.Cleanup(server.Close)
+
+		expiresAt, err := inspector.ExpiresAt(context.Background(), strings.TrimPrefix(server.URL, "https://"))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, server.Certificate().NotAfter, expiresAt)
+	})
+
+	t.Run("should fail if the host is unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		_, err := inspector.ExpiresAt(context.Background(), strings.TrimPrefix(server.URL, "http://"))
+
+		testutil.IsNotNil(t, err)
+	})
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/mail"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var ErrSMTPNotConfigured = errors.New("smtp_not_configured")
+
+type (
+	Options interface {
+		SMTP() monad.Maybe[mail.Options]
+	}
+
+	sender struct {
+		options Options
+	}
+)
+
+// Builds a sender delivering emails through the configured SMTP server. Options are
+// read for each email so the server settings could be reloaded while running.
+func NewSender(options Options) domain.EmailSender {
+	return &sender{options}
+}
+
+func (s *sender) Send(ctx context.Context, email domain.Email) error {
+	options, isSet := s.options.SMTP().TryGet()
+
+	if !isSet {
+		return ErrSMTPNotConfigured
+	}
+
+	return mail.NewSMTP(options).Send(ctx, mail.Message{
+		To:      email.To,
+		Subject: email.Subject,
+		Body:    email.Body,
+	})
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	ChannelsStore interface {
+		domain.ChannelsReader
+		domain.ChannelsWriter
+	}
+
+	channelsStore struct {
+		channels []*channelData
+	}
+
+	channelData struct {
+		id    domain.ChannelID
+		value *domain.Channel
+	}
+)
+
+func NewChannelsStore(existingChannels ...*domain.Channel) ChannelsStore {
+	s := &channelsStore{}
+
+	s.Write(context.Background(), existingChannels...)
+
+	return s
+}
+
+func (s *channelsStore) GetByID(ctx context.Context, id domain.ChannelID) (domain.Channel, error) {
+	for _, c := range s.channels {
+		if c.id == id {
+			return *c.value, nil
+		}
+	}
+
+	return domain.Channel{}, apperr.ErrNotFound
+}
+
+func (s *channelsStore) GetForApp(ctx context.Context, app deployment.AppID) ([]domain.Channel, error) {
+	var channels []domain.Channel
+
+	for _, c := range s.channels {
+		if id, isSet := c.value.App().TryGet(); !isSet || id == app {
+			channels = append(channels, *c.value)
+		}
+	}
+
+	return channels, nil
+}
+
+func (s *channelsStore) Write(ctx context.Context, channels ...*domain.Channel) error {
+	for _, channel := range channels {
+		for _, e := range event.Unwrap(channel) {
+			switch evt := e.(type) {
+			case domain.ChannelCreated:
+				var exist bool
+				for _, c := range s.channels {
+					if c.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.channels = append(s.channels, &channelData{
+					id:    evt.ID,
+					value: channel,
+				})
+			case domain.ChannelDeleted:
+				for i, c := range s.channels {
+					if c.id == channel.ID() {
+						*c.value = *channel
+						s.channels = append(s.channels[:i], s.channels[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, c := range s.channels {
+					if c.id == channel.ID() {
+						*c.value = *channel
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
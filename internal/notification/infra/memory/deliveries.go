@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	DeliveriesStore interface {
+		domain.DeliveriesReader
+		domain.DeliveriesWriter
+	}
+
+	deliveriesStore struct {
+		deliveries []*deliveryData
+	}
+
+	deliveryData struct {
+		id    domain.DeliveryID
+		value *domain.Delivery
+	}
+)
+
+func NewDeliveriesStore(existingDeliveries ...*domain.Delivery) DeliveriesStore {
+	s := &deliveriesStore{}
+
+	s.Write(context.Background(), existingDeliveries...)
+
+	return s
+}
+
+func (s *deliveriesStore) GetByID(ctx context.Context, id domain.DeliveryID) (domain.Delivery, error) {
+	for _, d := range s.deliveries {
+		if d.id == id {
+			return *d.value, nil
+		}
+	}
+
+	return domain.Delivery{}, apperr.ErrNotFound
+}
+
+func (s *deliveriesStore) Write(ctx context.Context, deliveries ...*domain.Delivery) error {
+	for _, delivery := range deliveries {
+		for _, e := range event.Unwrap(delivery) {
+			switch evt := e.(type) {
+			case domain.DeliveryCreated:
+				var exist bool
+				for _, d := range s.deliveries {
+					if d.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.deliveries = append(s.deliveries, &deliveryData{
+					id:    evt.ID,
+					value: delivery,
+				})
+			default:
+				for _, d := range s.deliveries {
+					if d.id == delivery.ID() {
+						*d.value = *delivery
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	EmailPreferencesStore interface {
+		domain.EmailPreferencesReader
+		domain.EmailPreferencesWriter
+	}
+
+	emailPreferencesStore struct {
+		preferences map[auth.UserID]*domain.EmailPreferences
+	}
+)
+
+func NewEmailPreferencesStore(existingPreferences ...*domain.EmailPreferences) EmailPreferencesStore {
+	s := &emailPreferencesStore{
+		preferences: make(map[auth.UserID]*domain.EmailPreferences),
+	}
+
+	s.Write(context.Background(), existingPreferences...)
+
+	return s
+}
+
+func (s *emailPreferencesStore) GetByUserID(ctx context.Context, uid auth.UserID) (domain.EmailPreferences, error) {
+	if p, found := s.preferences[uid]; found {
+		return *p, nil
+	}
+
+	return domain.DefaultEmailPreferences(uid), nil
+}
+
+func (s *emailPreferencesStore) Write(ctx context.Context, preferences ...*domain.EmailPreferences) error {
+	for _, p := range preferences {
+		for _, e := range event.Unwrap(p) {
+			switch e.(type) {
+			case domain.EmailPreferencesChanged:
+				s.preferences[p.UserID()] = p
+			}
+		}
+	}
+
+	return nil
+}
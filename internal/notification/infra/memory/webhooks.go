@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+)
+
+type (
+	WebhooksStore interface {
+		domain.WebhooksReader
+		domain.WebhooksWriter
+	}
+
+	webhooksStore struct {
+		webhooks []*webhookData
+	}
+
+	webhookData struct {
+		id    domain.WebhookID
+		value *domain.Webhook
+	}
+)
+
+func NewWebhooksStore(existingWebhooks ...*domain.Webhook) WebhooksStore {
+	s := &webhooksStore{}
+
+	s.Write(context.Background(), existingWebhooks...)
+
+	return s
+}
+
+func (s *webhooksStore) GetByID(ctx context.Context, id domain.WebhookID) (domain.Webhook, error) {
+	for _, w := range s.webhooks {
+		if w.id == id {
+			return *w.value, nil
+		}
+	}
+
+	return domain.Webhook{}, apperr.ErrNotFound
+}
+
+func (s *webhooksStore) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+
+	for _, w := range s.webhooks {
+		webhooks = append(webhooks, *w.value)
+	}
+
+	return webhooks, nil
+}
+
+func (s *webhooksStore) Write(ctx context.Context, webhooks ...*domain.Webhook) error {
+	for _, webhook := range webhooks {
+		for _, e := range event.Unwrap(webhook) {
+			switch evt := e.(type) {
+			case domain.WebhookCreated:
+				var exist bool
+				for _, w := range s.webhooks {
+					if w.id == evt.ID {
+						exist = true
+						break
+					}
+				}
+
+				if exist {
+					continue
+				}
+
+				s.webhooks = append(s.webhooks, &webhookData{
+					id:    evt.ID,
+					value: webhook,
+				})
+			case domain.WebhookDeleted:
+				for i, w := range s.webhooks {
+					if w.id == webhook.ID() {
+						*w.value = *webhook
+						s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+						break
+					}
+				}
+			default:
+				for _, w := range s.webhooks {
+					if w.id == webhook.ID() {
+						*w.value = *webhook
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
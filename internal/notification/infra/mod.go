@@ -0,0 +1,91 @@
+package infra
+
+import (
+	"github.com/YuukanOO/seelf/internal/notification/app/check_certificates"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/create_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/delete_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/deliver_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_incident"
+	"github.com/YuukanOO/seelf/internal/notification/app/notify_uptime"
+	"github.com/YuukanOO/seelf/internal/notification/app/send_email"
+	"github.com/YuukanOO/seelf/internal/notification/app/trigger_webhooks"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/app/update_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/infra/channel"
+	"github.com/YuukanOO/seelf/internal/notification/infra/email"
+	notificationsqlite "github.com/YuukanOO/seelf/internal/notification/infra/sqlite"
+	"github.com/YuukanOO/seelf/internal/notification/infra/webhook"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/mail"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+type Options interface {
+	channel.Options
+
+	SMTP() monad.Maybe[mail.Options] // Emails are only sent when an SMTP server is configured
+}
+
+// Setup the notification module which notifies external systems of what happens
+// in the deployment one.
+func Setup(
+	opts Options,
+	logger log.Logger,
+	db *sqlite.Database,
+	b bus.Bus,
+	scheduler bus.Scheduler,
+) error {
+	webhooksStore := notificationsqlite.NewWebhooksStore(db)
+	deliveriesStore := notificationsqlite.NewDeliveriesStore(db)
+	channelsStore := notificationsqlite.NewChannelsStore(db)
+	emailsStore := notificationsqlite.NewEmailsStore(db)
+	notificationQueryHandler := notificationsqlite.NewGateway(db.ReadOnly())
+
+	sender := webhook.NewSender()
+	notifier := channel.NewNotifier(opts)
+
+	bus.Register(b, create_webhook.Handler(webhooksStore))
+	bus.Register(b, update_webhook.Handler(webhooksStore, webhooksStore))
+	bus.Register(b, delete_webhook.Handler(webhooksStore, webhooksStore))
+	bus.Register(b, deliver_webhook.Handler(deliveriesStore, deliveriesStore, webhooksStore, sender))
+	bus.Register(b, notificationQueryHandler.GetWebhooks)
+	bus.Register(b, notificationQueryHandler.GetWebhookByID)
+	bus.Register(b, notificationQueryHandler.GetWebhookDeliveries)
+	bus.Register(b, create_channel.Handler(channelsStore))
+	bus.Register(b, update_channel.Handler(channelsStore, channelsStore))
+	bus.Register(b, delete_channel.Handler(channelsStore, channelsStore))
+	bus.Register(b, notify_channel.Handler(channelsStore, notifier))
+	bus.Register(b, notify_incident.Handler(channelsStore, notifier))
+	bus.Register(b, notify_uptime.Handler(channelsStore, notifier))
+	bus.Register(b, notificationQueryHandler.GetChannels)
+	bus.Register(b, notificationQueryHandler.GetChannelByID)
+	bus.Register(b, update_email_preferences.Handler(emailsStore, emailsStore))
+	bus.Register(b, notificationQueryHandler.GetEmailPreferences)
+
+	bus.On(b, deliver_webhook.OnDeliveryCreatedHandler(scheduler))
+	bus.On(b, trigger_webhooks.OnDeploymentStateChangedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, trigger_webhooks.OnTargetStateChangedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, trigger_webhooks.OnAppDeletedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, trigger_webhooks.OnTargetDeletedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, trigger_webhooks.OnIncidentRecordedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, trigger_webhooks.OnMonitorStatusChangedHandler(webhooksStore, deliveriesStore))
+	bus.On(b, notify_channel.OnDeploymentStateChangedHandler(channelsStore, scheduler))
+	bus.On(b, notify_incident.OnIncidentRecordedHandler(channelsStore, scheduler))
+	bus.On(b, notify_uptime.OnMonitorStatusChangedHandler(channelsStore, scheduler))
+
+	if opts.SMTP().HasValue() {
+		bus.Register(b, send_email.Handler(email.NewSender(opts)))
+		bus.Register(b, check_certificates.Handler(emailsStore, emailsStore, email.NewInspector(logger), scheduler))
+
+		bus.On(b, send_email.OnDeploymentStateChangedHandler(emailsStore, scheduler, opts.AppExposedUrl()))
+		bus.On(b, send_email.OnUserInvitedHandler(scheduler, opts.AppExposedUrl()))
+	}
+
+	return db.Migrate(notificationsqlite.Migrations)
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	ChannelsStore interface {
+		domain.ChannelsReader
+		domain.ChannelsWriter
+	}
+
+	channelsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewChannelsStore(db *sqlite.Database) ChannelsStore {
+	return &channelsStore{db}
+}
+
+func (s *channelsStore) GetByID(ctx context.Context, id domain.ChannelID) (domain.Channel, error) {
+	return builder.
+		Query[domain.Channel](`
+		SELECT
+			id
+			,name
+			,config
+			,app_id
+			,created_at
+			,created_by
+		FROM channels
+		WHERE id = ?`, id).
+		Decrypt("config").
+		One(s.db, ctx, domain.ChannelFrom)
+}
+
+func (s *channelsStore) GetForApp(ctx context.Context, app deployment.AppID) ([]domain.Channel, error) {
+	return builder.
+		Query[domain.Channel](`
+		SELECT
+			id
+			,name
+			,config
+			,app_id
+			,created_at
+			,created_by
+		FROM channels
+		WHERE app_id IS NULL OR app_id = ?`, app).
+		Decrypt("config").
+		All(s.db, ctx, domain.ChannelFrom)
+}
+
+func (s *channelsStore) Write(ctx context.Context, channels ...*domain.Channel) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, channels, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.ChannelCreated:
+			return builder.
+				Insert("channels", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"config":     s.db.Encrypted(evt.Config),
+					"app_id":     evt.App,
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.ChannelRenamed:
+			return builder.
+				Update("channels", builder.Values{
+					"name": evt.Name,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.ChannelConfigChanged:
+			return builder.
+				Update("channels", builder.Values{
+					"config": s.db.Encrypted(evt.Config),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.ChannelDeleted:
+			return builder.
+				Command("DELETE FROM channels WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	DeliveriesStore interface {
+		domain.DeliveriesReader
+		domain.DeliveriesWriter
+	}
+
+	deliveriesStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewDeliveriesStore(db *sqlite.Database) DeliveriesStore {
+	return &deliveriesStore{db}
+}
+
+func (s *deliveriesStore) GetByID(ctx context.Context, id domain.DeliveryID) (domain.Delivery, error) {
+	return builder.
+		Query[domain.Delivery](`
+		SELECT
+			id
+			,webhook_id
+			,event
+			,payload
+			,status
+			,attempts
+			,status_code
+			,errcode
+			,created_at
+			,last_attempt_at
+		FROM webhook_deliveries
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.DeliveryFrom)
+}
+
+func (s *deliveriesStore) Write(ctx context.Context, deliveries ...*domain.Delivery) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, deliveries, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.DeliveryCreated:
+			return builder.
+				Insert("webhook_deliveries", builder.Values{
+					"id":         evt.ID,
+					"webhook_id": evt.Webhook,
+					"event":      evt.Event,
+					"payload":    evt.Payload,
+					"status":     evt.Status,
+					"attempts":   0,
+					"created_at": evt.CreatedAt,
+				}).
+				Exec(s.db, ctx)
+		case domain.DeliveryAttempted:
+			return builder.
+				Update("webhook_deliveries", builder.Values{
+					"status":          evt.Status,
+					"attempts":        evt.Attempts,
+					"status_code":     evt.StatusCode,
+					"errcode":         evt.ErrCode,
+					"last_attempt_at": evt.AttemptedAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
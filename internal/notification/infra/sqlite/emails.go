@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	EmailsStore interface {
+		domain.EmailPreferencesReader
+		domain.EmailPreferencesWriter
+		domain.RecipientsReader
+		domain.ExposedHostsReader
+	}
+
+	emailsStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewEmailsStore(db *sqlite.Database) EmailsStore {
+	return &emailsStore{db}
+}
+
+func (s *emailsStore) GetByUserID(ctx context.Context, uid auth.UserID) (domain.EmailPreferences, error) {
+	preferences, err := builder.
+		Query[domain.EmailPreferences](`
+		SELECT
+			user_id
+			,deployment_failures
+			,certificate_expiry
+		FROM email_preferences
+		WHERE user_id = ?`, uid).
+		One(s.db, ctx, domain.EmailPreferencesFrom)
+
+	if errors.Is(err, apperr.ErrNotFound) {
+		return domain.DefaultEmailPreferences(uid), nil
+	}
+
+	return preferences, err
+}
+
+func (s *emailsStore) GetDeploymentFailureRecipients(ctx context.Context, id deployment.DeploymentID) ([]string, error) {
+	return builder.
+		Query[string](`
+		SELECT users.email
+		FROM deployments
+		INNER JOIN users ON users.id = deployments.requested_by
+		LEFT JOIN email_preferences ON email_preferences.user_id = users.id
+		WHERE deployments.app_id = ? AND deployments.deployment_number = ?
+			AND users.disabled_at IS NULL
+			AND COALESCE(email_preferences.deployment_failures, true)`, id.AppID(), id.DeploymentNumber()).
+		ExtractAll(s.db, ctx)
+}
+
+func (s *emailsStore) GetCertificateExpiryRecipients(ctx context.Context) ([]string, error) {
+	return builder.
+		Query[string](`
+		SELECT users.email
+		FROM users
+		LEFT JOIN email_preferences ON email_preferences.user_id = users.id
+		WHERE users.is_admin
+			AND users.disabled_at IS NULL
+			AND COALESCE(email_preferences.certificate_expiry, true)
+		ORDER BY users.email`).
+		ExtractAll(s.db, ctx)
+}
+
+// Exposed hosts are built from the http entrypoints of the latest successful deployment
+// of each app environment, deployed on a target using https.
+func (s *emailsStore) GetExposedHosts(ctx context.Context) ([]string, error) {
+	entries, err := builder.
+		Query[exposedEntrypoint](`
+		SELECT DISTINCT
+			targets.url
+			,json_extract(entrypoint.value, '$.subdomain')
+		FROM deployments
+		INNER JOIN targets ON targets.id = deployments.config_target
+		,json_each(deployments.state_services) service
+		,json_each(service.value, '$.entrypoints') entrypoint
+		WHERE deployments.state_status = ?
+			AND targets.url LIKE 'https://%'
+			AND json_extract(entrypoint.value, '$.router') = ?
+			AND json_extract(entrypoint.value, '$.subdomain') IS NOT NULL
+			AND deployments.deployment_number = (
+				SELECT MAX(latest.deployment_number)
+				FROM deployments latest
+				WHERE latest.app_id = deployments.app_id
+					AND latest.config_environment = deployments.config_environment
+					AND latest.state_status = deployments.state_status
+			)`, deployment.DeploymentStatusSucceeded, deployment.RouterHttp).
+		All(s.db, ctx, exposedEntrypointMapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, len(entries))
+
+	for i, entry := range entries {
+		hosts[i] = entry.url.SubDomain(entry.subdomain).Host()
+	}
+
+	return hosts, nil
+}
+
+func (s *emailsStore) Write(ctx context.Context, preferences ...*domain.EmailPreferences) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, preferences, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.EmailPreferencesChanged:
+			return builder.
+				Command(`
+					INSERT INTO email_preferences (user_id, deployment_failures, certificate_expiry)
+					VALUES (?, ?, ?)
+					ON CONFLICT(user_id) DO UPDATE SET
+						deployment_failures = excluded.deployment_failures
+						,certificate_expiry = excluded.certificate_expiry`,
+					evt.UserID, evt.DeploymentFailures, evt.CertificateExpiry).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
+
+type exposedEntrypoint struct {
+	url       deployment.Url
+	subdomain string
+}
+
+func exposedEntrypointMapper(scanner storage.Scanner) (e exposedEntrypoint, err error) {
+	err = scanner.Scan(&e.url, &e.subdomain)
+	return e, err
+}
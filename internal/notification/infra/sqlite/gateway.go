@@ -0,0 +1,235 @@
+package sqlite
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channel"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_channels"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_email_preferences"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhook_deliveries"
+	"github.com/YuukanOO/seelf/internal/notification/app/get_webhooks"
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type gateway struct {
+	db *sqlite.Database
+}
+
+func NewGateway(db *sqlite.Database) *gateway {
+	return &gateway{db}
+}
+
+func (s *gateway) GetWebhooks(ctx context.Context, q get_webhooks.Query) ([]get_webhook.Webhook, error) {
+	if !auth.HasAdminRights(ctx) {
+		return nil, apperr.ErrForbidden
+	}
+
+	return builder.
+		Query[get_webhook.Webhook](`
+		SELECT
+			webhooks.id
+			,webhooks.name
+			,webhooks.url
+			,webhooks.events
+			,webhooks.created_at
+			,users.id
+			,users.email
+		FROM webhooks
+		INNER JOIN users ON users.id = webhooks.created_by
+		ORDER BY webhooks.name`).
+		All(s.db, ctx, webhookMapper)
+}
+
+func (s *gateway) GetWebhookByID(ctx context.Context, q get_webhook.Query) (get_webhook.Webhook, error) {
+	if !auth.HasAdminRights(ctx) {
+		return get_webhook.Webhook{}, apperr.ErrForbidden
+	}
+
+	return builder.
+		Query[get_webhook.Webhook](`
+		SELECT
+			webhooks.id
+			,webhooks.name
+			,webhooks.url
+			,webhooks.events
+			,webhooks.created_at
+			,users.id
+			,users.email
+		FROM webhooks
+		INNER JOIN users ON users.id = webhooks.created_by
+		WHERE webhooks.id = ?`, q.ID).
+		One(s.db, ctx, webhookMapper)
+}
+
+func (s *gateway) GetWebhookDeliveries(ctx context.Context, q get_webhook_deliveries.Query) (storage.Paginated[get_webhook_deliveries.Delivery], error) {
+	if !auth.HasAdminRights(ctx) {
+		return storage.Paginated[get_webhook_deliveries.Delivery]{}, apperr.ErrForbidden
+	}
+
+	return builder.
+		Select[get_webhook_deliveries.Delivery](`
+			id
+			,event
+			,payload
+			,status
+			,attempts
+			,status_code
+			,errcode
+			,created_at
+			,last_attempt_at`).
+		F(`
+			FROM webhook_deliveries
+			WHERE webhook_id = ?`, q.WebhookID).
+		F("ORDER BY created_at DESC").
+		Paginate(s.db, ctx, deliveryMapper, q.Page.Get(1), 20)
+}
+
+func (s *gateway) GetChannels(ctx context.Context, q get_channels.Query) ([]get_channel.Channel, error) {
+	if !auth.HasAdminRights(ctx) {
+		return nil, apperr.ErrForbidden
+	}
+
+	return builder.
+		Query[get_channel.Channel](`
+		SELECT
+			channels.id
+			,channels.name
+			,channels.config
+			,apps.id
+			,apps.name
+			,channels.created_at
+			,users.id
+			,users.email
+		FROM channels
+		LEFT JOIN apps ON apps.id = channels.app_id
+		INNER JOIN users ON users.id = channels.created_by
+		WHERE TRUE`).
+		S(builder.MaybeValue(q.AppID, "AND (channels.app_id IS NULL OR channels.app_id = ?)")).
+		F("ORDER BY channels.name").
+		Decrypt("config").
+		All(s.db, ctx, channelMapper)
+}
+
+func (s *gateway) GetChannelByID(ctx context.Context, q get_channel.Query) (get_channel.Channel, error) {
+	if !auth.HasAdminRights(ctx) {
+		return get_channel.Channel{}, apperr.ErrForbidden
+	}
+
+	return builder.
+		Query[get_channel.Channel](`
+		SELECT
+			channels.id
+			,channels.name
+			,channels.config
+			,apps.id
+			,apps.name
+			,channels.created_at
+			,users.id
+			,users.email
+		FROM channels
+		LEFT JOIN apps ON apps.id = channels.app_id
+		INNER JOIN users ON users.id = channels.created_by
+		WHERE channels.id = ?`, q.ID).
+		Decrypt("config").
+		One(s.db, ctx, channelMapper)
+}
+
+func (s *gateway) GetEmailPreferences(ctx context.Context, q get_email_preferences.Query) (get_email_preferences.EmailPreferences, error) {
+	return builder.
+		Query[get_email_preferences.EmailPreferences](`
+		SELECT
+			COALESCE(email_preferences.deployment_failures, true)
+			,COALESCE(email_preferences.certificate_expiry, true)
+		FROM users
+		LEFT JOIN email_preferences ON email_preferences.user_id = users.id
+		WHERE users.id = ?`, q.UserID).
+		One(s.db, ctx, emailPreferencesMapper)
+}
+
+func webhookMapper(scanner storage.Scanner) (w get_webhook.Webhook, err error) {
+	err = scanner.Scan(
+		&w.ID,
+		&w.Name,
+		&w.Url,
+		&w.Events,
+		&w.CreatedAt,
+		&w.CreatedBy.ID,
+		&w.CreatedBy.Email,
+	)
+
+	return w, err
+}
+
+func deliveryMapper(scanner storage.Scanner) (d get_webhook_deliveries.Delivery, err error) {
+	var statusCode monad.Maybe[int64]
+
+	err = scanner.Scan(
+		&d.ID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&statusCode,
+		&d.ErrCode,
+		&d.CreatedAt,
+		&d.LastAttemptAt,
+	)
+
+	if code, isSet := statusCode.TryGet(); isSet {
+		d.StatusCode.Set(int(code))
+	}
+
+	return d, err
+}
+
+func channelMapper(scanner storage.Scanner) (c get_channel.Channel, err error) {
+	var (
+		config  domain.ChannelConfig
+		appID   monad.Maybe[string]
+		appName monad.Maybe[string]
+	)
+
+	err = scanner.Scan(
+		&c.ID,
+		&c.Name,
+		&config,
+		&appID,
+		&appName,
+		&c.CreatedAt,
+		&c.CreatedBy.ID,
+		&c.CreatedBy.Email,
+	)
+
+	c.Kind = string(config.Kind)
+	c.Url = string(config.Url)
+
+	if config.Kind == domain.ChannelKindMatrix {
+		c.RoomID.Set(config.RoomID)
+		c.AccessToken.Set(storage.SecretString(config.AccessToken))
+	}
+
+	if id, isSet := appID.TryGet(); isSet {
+		c.App.Set(get_channel.AppSummary{
+			ID:   id,
+			Name: appName.Get(""),
+		})
+	}
+
+	return c, err
+}
+
+func emailPreferencesMapper(scanner storage.Scanner) (p get_email_preferences.EmailPreferences, err error) {
+	err = scanner.Scan(
+		&p.DeploymentFailures,
+		&p.CertificateExpiry,
+	)
+
+	return p, err
+}
@@ -0,0 +1,28 @@
+CREATE TABLE webhooks (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,url TEXT NOT NULL
+    ,secret TEXT NOT NULL
+    ,events TEXT NOT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_webhooks PRIMARY KEY(id)
+    ,CONSTRAINT fk_webhooks_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_deliveries (
+    id TEXT NOT NULL
+    ,webhook_id TEXT NOT NULL
+    ,event TEXT NOT NULL
+    ,payload TEXT NOT NULL
+    ,status INTEGER NOT NULL
+    ,attempts INTEGER NOT NULL
+    ,status_code INTEGER NULL
+    ,errcode TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,last_attempt_at DATETIME NULL
+    ,CONSTRAINT pk_webhook_deliveries PRIMARY KEY(id)
+    ,CONSTRAINT fk_webhook_deliveries_webhook_id FOREIGN KEY(webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX idx_channels_app_id;
+DROP TABLE channels;
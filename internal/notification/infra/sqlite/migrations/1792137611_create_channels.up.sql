@@ -0,0 +1,13 @@
+CREATE TABLE channels (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,config TEXT NOT NULL
+    ,app_id TEXT NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_channels PRIMARY KEY(id)
+    ,CONSTRAINT fk_channels_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_channels_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_channels_app_id ON channels(app_id);
@@ -0,0 +1,7 @@
+CREATE TABLE email_preferences (
+    user_id TEXT NOT NULL
+    ,deployment_failures BOOLEAN NOT NULL
+    ,certificate_expiry BOOLEAN NOT NULL
+    ,CONSTRAINT pk_email_preferences PRIMARY KEY(user_id)
+    ,CONSTRAINT fk_email_preferences_user_id FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
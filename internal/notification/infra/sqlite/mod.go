@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"embed"
+
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+var Migrations = sqlite.NewMigrationsModule("notification", "migrations", migrations)
+
+// Columns holding secrets, such as webhooks signing secrets and channels credentials, encrypted at rest.
+var EncryptedColumns = []sqlite.EncryptedColumn{
+	{Table: "webhooks", Column: "secret"},
+	{Table: "channels", Column: "config"},
+}
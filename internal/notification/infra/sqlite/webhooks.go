@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+type (
+	WebhooksStore interface {
+		domain.WebhooksReader
+		domain.WebhooksWriter
+	}
+
+	webhooksStore struct {
+		db *sqlite.Database
+	}
+)
+
+func NewWebhooksStore(db *sqlite.Database) WebhooksStore {
+	return &webhooksStore{db}
+}
+
+func (s *webhooksStore) GetByID(ctx context.Context, id domain.WebhookID) (domain.Webhook, error) {
+	return builder.
+		Query[domain.Webhook](`
+		SELECT
+			id
+			,name
+			,url
+			,secret
+			,events
+			,created_at
+			,created_by
+		FROM webhooks
+		WHERE id = ?`, id).
+		Decrypt("secret").
+		One(s.db, ctx, domain.WebhookFrom)
+}
+
+func (s *webhooksStore) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	return builder.
+		Query[domain.Webhook](`
+		SELECT
+			id
+			,name
+			,url
+			,secret
+			,events
+			,created_at
+			,created_by
+		FROM webhooks`).
+		Decrypt("secret").
+		All(s.db, ctx, domain.WebhookFrom)
+}
+
+func (s *webhooksStore) Write(ctx context.Context, webhooks ...*domain.Webhook) error {
+	return sqlite.WriteAndDispatch(s.db, ctx, webhooks, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.WebhookCreated:
+			return builder.
+				Insert("webhooks", builder.Values{
+					"id":         evt.ID,
+					"name":       evt.Name,
+					"url":        evt.Url,
+					"secret":     s.db.Encrypted(evt.Secret),
+					"events":     evt.Events,
+					"created_at": evt.Created.At(),
+					"created_by": evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.WebhookRenamed:
+			return builder.
+				Update("webhooks", builder.Values{
+					"name": evt.Name,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.WebhookUrlChanged:
+			return builder.
+				Update("webhooks", builder.Values{
+					"url": evt.Url,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.WebhookSecretChanged:
+			return builder.
+				Update("webhooks", builder.Values{
+					"secret": s.db.Encrypted(evt.Secret),
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.WebhookEventsChanged:
+			return builder.
+				Update("webhooks", builder.Values{
+					"events": evt.Events,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.WebhookDeleted:
+			return builder.
+				Command("DELETE FROM webhooks WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
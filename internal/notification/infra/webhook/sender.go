@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+)
+
+const (
+	timeout         = 10 * time.Second
+	userAgent       = "seelf-webhook"
+	eventHeader     = "X-Seelf-Event"
+	deliveryHeader  = "X-Seelf-Delivery"
+	signatureHeader = "X-Seelf-Signature"
+	signaturePrefix = "sha256="
+)
+
+type sender struct {
+	client *http.Client
+}
+
+// Builds a sender posting payloads to webhook endpoints. Payloads are signed with
+// the webhook secret and the signature sent in the X-Seelf-Signature header.
+func NewSender() domain.WebhookSender {
+	return &sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *sender) Send(ctx context.Context, webhook domain.Webhook, delivery domain.Delivery) (int, error) {
+	payload := []byte(delivery.Payload())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(webhook.Url()), bytes.NewReader(payload))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(eventHeader, string(delivery.Event()))
+	req.Header.Set(deliveryHeader, string(delivery.ID()))
+	req.Header.Set(signatureHeader, signaturePrefix+webhook.Sign(payload))
+
+	resp, err := s.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain the body so the connection could be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w: %d", domain.ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
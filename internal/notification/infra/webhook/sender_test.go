@@ -0,0 +1,62 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/notification/domain"
+	"github.com/YuukanOO/seelf/internal/notification/infra/webhook"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Sender(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sender = webhook.NewSender()
+	)
+
+	t.Run("should post the signed payload", func(t *testing.T) {
+		var (
+			received http.Header
+			body     []byte
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		hook := domain.NewWebhook("my-hook", domain.Url(server.URL), "secret", nil, "uid")
+		delivery := hook.Deliver(domain.EventDeploymentFailed, `{"event":"deployment_failed"}`)
+
+		status, err := sender.Send(ctx, hook, delivery)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, http.StatusNoContent, status)
+		testutil.Equals(t, `{"event":"deployment_failed"}`, string(body))
+		testutil.Equals(t, "application/json", received.Get("Content-Type"))
+		testutil.Equals(t, "deployment_failed", received.Get("X-Seelf-Event"))
+		testutil.Equals(t, string(delivery.ID()), received.Get("X-Seelf-Delivery"))
+		testutil.Equals(t, "sha256=91ca480ac36fdf863f508a5b437f1d06663e92ea01615f678e10ea24e27256bf", received.Get("X-Seelf-Signature"))
+	})
+
+	t.Run("should return an error on non successful responses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		hook := domain.NewWebhook("my-hook", domain.Url(server.URL), "secret", nil, "uid")
+		delivery := hook.Deliver(domain.EventDeploymentFailed, `{}`)
+
+		status, err := sender.Send(ctx, hook, delivery)
+
+		testutil.ErrorIs(t, domain.ErrUnexpectedStatusCode, err)
+		testutil.Equals(t, http.StatusBadGateway, status)
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 )
 
-var ErrNotFound = New("not_found") // Common error used when a resource could not be found.
+var (
+	ErrNotFound  = New("not_found") // Common error used when a resource could not be found.
+	ErrForbidden = New("forbidden") // Common error used when the current user is not allowed to perform an action.
+)
 
 // Represents an application error with an optional detail.
 // Application errors represent an expected error from the domain perspective.
@@ -0,0 +1,34 @@
+package apperr
+
+import "errors"
+
+// Common error used when input validation has failed. The validate package wraps
+// field errors inside it.
+var ErrValidationFailed = New("validation_failed")
+
+// Kind of an error used to determine how it should be presented to the end user.
+type Kind uint8
+
+const (
+	KindNone           Kind = iota // No error at all
+	KindValidation                 // Input validation has failed
+	KindDomain                     // Expected error from the domain perspective (apperr.Error)
+	KindInfrastructure             // Unexpected error coming from the infrastructure
+)
+
+// Determine the kind of the given error.
+func KindOf(err error) Kind {
+	if err == nil {
+		return KindNone
+	}
+
+	if errors.Is(err, ErrValidationFailed) {
+		return KindValidation
+	}
+
+	if _, isAppErr := As[Error](err); isAppErr {
+		return KindDomain
+	}
+
+	return KindInfrastructure
+}
@@ -0,0 +1,26 @@
+package bus
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type contextKey string
+
+const correlationIDContextKey contextKey = "correlation-id"
+
+// Attach the given correlation ID to the context. It is persisted alongside scheduled
+// jobs so that their processing can be traced back to the originating request.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey, id)
+}
+
+// Retrieve the correlation ID attached to the given context if any.
+func CorrelationID(ctx context.Context) (m monad.Maybe[string]) {
+	if id, ok := ctx.Value(correlationIDContextKey).(string); ok && id != "" {
+		m.Set(id)
+	}
+
+	return m
+}
@@ -45,6 +45,11 @@ func Register[TResult any, TMsg TypedRequest[TResult]](bus Bus, handler RequestH
 	if _, isSchedulable := any(msg).(Schedulable); isSchedulable {
 		Marshallable.Register(msg, func(s string) (Request, error) { return storage.UnmarshalJSON[TMsg](s) })
 	}
+
+	// And keep track of upgradable ones to migrate payloads persisted by previous versions.
+	if upgradable, isUpgradable := any(msg).(Upgradable); isUpgradable {
+		upgradables[upgradable.Name_()] = upgradable
+	}
 }
 
 // Register a signal handler for the given signal. Multiple signals can be registered for the same signal
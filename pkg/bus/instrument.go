@@ -0,0 +1,57 @@
+package bus
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	messageDuration = telemetry.DurationHistogram("bus.message.duration", "Duration of messages handling")
+	jobDuration     = telemetry.DurationHistogram("scheduler.job.duration", "Duration of scheduled jobs processing")
+)
+
+// Middleware recording a span and the handling duration of every dispatched message.
+// Signals handlers are measured one by one.
+func Instrument(next NextFunc) NextFunc {
+	return func(ctx context.Context, msg Message) (any, error) {
+		start := time.Now()
+		ctx, span := telemetry.Tracer().Start(ctx, msg.Name_(),
+			trace.WithAttributes(attribute.Int("bus.message.kind", int(msg.Kind_()))))
+
+		result, err := next(ctx, msg)
+
+		telemetry.End(span, err)
+		messageDuration.Record(ctx, telemetry.Since(start), metric.WithAttributes(
+			attribute.String("bus.message.name", msg.Name_()),
+			attribute.Bool("error", err != nil),
+		))
+
+		return result, err
+	}
+}
+
+// Process a scheduled job inside its own span and record its duration.
+func instrumentJob(ctx context.Context, job ScheduledJob, fn func(context.Context) error) error {
+	start := time.Now()
+	name := job.Message().Name_()
+	ctx, span := telemetry.Tracer().Start(ctx, "job "+name,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("scheduler.job.id", job.ID()),
+			attribute.String("correlation_id", job.CorrelationID().Get(""))))
+
+	err := fn(ctx)
+
+	telemetry.End(span, err)
+	jobDuration.Record(ctx, telemetry.Since(start), metric.WithAttributes(
+		attribute.String("bus.message.name", name),
+		attribute.Bool("error", err != nil),
+	))
+
+	return err
+}
@@ -0,0 +1,51 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Instrument(t *testing.T) {
+	t.Run("should record a span for every handled message", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		defer otel.SetTracerProvider(previous)
+
+		innerErr := errors.New("some error")
+		b := memory.NewBus(bus.Instrument)
+		bus.Register(b, func(_ context.Context, cmd instrumentedCommand) (bus.UnitType, error) {
+			return bus.Unit, cmd.err
+		})
+
+		_, err := bus.Send(b, context.Background(), instrumentedCommand{})
+		testutil.IsNil(t, err)
+
+		_, err = bus.Send(b, context.Background(), instrumentedCommand{err: innerErr})
+		testutil.ErrorIs(t, innerErr, err)
+
+		spans := recorder.Ended()
+
+		testutil.HasLength(t, spans, 2)
+		testutil.Equals(t, "instrumentedCommand", spans[0].Name())
+		testutil.Equals(t, codes.Unset, spans[0].Status().Code)
+		testutil.Equals(t, codes.Error, spans[1].Status().Code)
+	})
+}
+
+type instrumentedCommand struct {
+	bus.Command[bus.UnitType]
+
+	err error
+}
+
+func (instrumentedCommand) Name_() string { return "instrumentedCommand" }
@@ -0,0 +1,77 @@
+package bus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Version of the payload of schedulable messages which do not implement the Upgradable
+// interface, also the one of jobs queued before payloads were versioned.
+const InitialPayloadVersion = 1
+
+var (
+	ErrUnsupportedPayloadVersion = errors.New("unsupported_payload_version")
+
+	upgradables = make(map[string]Upgradable) // Upgradable messages by name, populated by Register
+)
+
+type (
+	// Schedulable message whose payload schema has changed since it was first introduced.
+	// Jobs are persisted with the version of their payload so the ones queued by a previous
+	// seelf version could be upgraded when retrieved instead of failing to unmarshal.
+	Upgradable interface {
+		Schedulable
+		PayloadVersion_() int                                   // Current version of the payload schema, starting at InitialPayloadVersion
+		UpgradePayload_(from int, payload map[string]any) error // Upgrade in place a payload from the given version to the next one
+	}
+)
+
+// Retrieve the current version of the payload of the given message.
+func PayloadVersion(msg Request) int {
+	if upgradable, isUpgradable := msg.(Upgradable); isUpgradable {
+		return upgradable.PayloadVersion_()
+	}
+
+	return InitialPayloadVersion
+}
+
+// Rehydrate a scheduled message from its name and its payload persisted with the given
+// version, applying every needed upgrade to bring it to the current version first.
+func Unmarshal(name string, version int, data string) (Request, error) {
+	current := InitialPayloadVersion
+	upgradable, isUpgradable := upgradables[name]
+
+	if isUpgradable {
+		current = upgradable.PayloadVersion_()
+	}
+
+	// Persisted by a newer seelf version, there's no way to downgrade it
+	if version > current {
+		return nil, fmt.Errorf("%w: %s payload version %d is newer than %d", ErrUnsupportedPayloadVersion, name, version, current)
+	}
+
+	if version < current {
+		var payload map[string]any
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return nil, err
+		}
+
+		for v := version; v < current; v++ {
+			if err := upgradable.UpgradePayload_(v, payload); err != nil {
+				return nil, fmt.Errorf("upgrading %s payload from version %d: %w", name, v, err)
+			}
+		}
+
+		upgraded, err := json.Marshal(payload)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data = string(upgraded)
+	}
+
+	return Marshallable.From(name, data)
+}
@@ -0,0 +1,83 @@
+package bus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Payload(t *testing.T) {
+	b := memory.NewBus()
+	bus.Register(b, func(context.Context, greetCommand) (bus.UnitType, error) { return bus.Unit, nil })
+	bus.Register(b, func(context.Context, pingCommand) (bus.UnitType, error) { return bus.Unit, nil })
+
+	t.Run("should retrieve the current version of a message payload", func(t *testing.T) {
+		testutil.Equals(t, bus.InitialPayloadVersion, bus.PayloadVersion(pingCommand{}))
+		testutil.Equals(t, 3, bus.PayloadVersion(greetCommand{}))
+	})
+
+	t.Run("should unmarshal a payload at its current version as is", func(t *testing.T) {
+		msg, err := bus.Unmarshal(pingCommand{}.Name_(), bus.InitialPayloadVersion, `{"Host":"example.com"}`)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, pingCommand{Host: "example.com"}, msg.(pingCommand))
+	})
+
+	t.Run("should upgrade a payload persisted with a previous version", func(t *testing.T) {
+		msg, err := bus.Unmarshal(greetCommand{}.Name_(), bus.InitialPayloadVersion, `{"Name":"john"}`)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, greetCommand{Recipient: "john", Greeting: "hello"}, msg.(greetCommand))
+
+		msg, err = bus.Unmarshal(greetCommand{}.Name_(), 2, `{"Recipient":"jane"}`)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, greetCommand{Recipient: "jane", Greeting: "hello"}, msg.(greetCommand))
+	})
+
+	t.Run("should fail on a payload persisted with a newer version", func(t *testing.T) {
+		_, err := bus.Unmarshal(greetCommand{}.Name_(), 4, `{}`)
+		testutil.ErrorIs(t, bus.ErrUnsupportedPayloadVersion, err)
+
+		_, err = bus.Unmarshal(pingCommand{}.Name_(), 2, `{}`)
+		testutil.ErrorIs(t, bus.ErrUnsupportedPayloadVersion, err)
+	})
+}
+
+type (
+	pingCommand struct {
+		bus.Command[bus.UnitType]
+
+		Host string
+	}
+
+	// Version 1 had a Name field, renamed to Recipient in version 2 and a Greeting
+	// has been added in version 3.
+	greetCommand struct {
+		bus.Command[bus.UnitType]
+
+		Recipient string
+		Greeting  string
+	}
+)
+
+func (pingCommand) Name_() string      { return "PingCommand" }
+func (pingCommand) ResourceID() string { return "" }
+
+func (greetCommand) Name_() string        { return "GreetCommand" }
+func (greetCommand) ResourceID() string   { return "" }
+func (greetCommand) PayloadVersion_() int { return 3 }
+func (greetCommand) UpgradePayload_(from int, payload map[string]any) error {
+	switch from {
+	case 1:
+		payload["Recipient"] = payload["Name"]
+		delete(payload, "Name")
+	case 2:
+		payload["Greeting"] = "hello"
+	}
+
+	return nil
+}
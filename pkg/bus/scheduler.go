@@ -3,6 +3,7 @@ package bus
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/YuukanOO/seelf/pkg/log"
@@ -47,6 +48,24 @@ type (
 		Scheduler
 		Start()
 		Stop()
+		Heartbeat() time.Time          // Last time the scheduler was seen alive, zero if not started
+		SetPollInterval(time.Duration) // Change the poll interval, takes effect on the next poll
+		Pause()                        // Stop picking new jobs, running ones are left to finish
+		Resume()                       // Resume picking jobs after a pause
+		RunningJobs() int              // Number of jobs currently being processed
+		Status() WorkerHeartbeat       // Activity of the scheduler, persisted in the store on each beat
+	}
+
+	// Activity of the scheduler workers, persisted so administrators could tell a stuck
+	// worker from a busy queue.
+	WorkerHeartbeat struct {
+		StartedAt     time.Time              `json:"started_at"`
+		BeatAt        time.Time              `json:"beat_at"`        // Last time the scheduler was seen alive
+		LastPoll      monad.Maybe[time.Time] `json:"last_poll"`      // Last time pending jobs have been retrieved
+		JobsProcessed int64                  `json:"jobs_processed"` // Since the scheduler has been started
+		JobsFailed    int64                  `json:"jobs_failed"`    // Processed jobs which returned an error and will be retried
+		RunningJobs   int                    `json:"running_jobs"`
+		Paused        bool                   `json:"paused"`
 	}
 
 	// Represents a request that has been queued for dispatching.
@@ -54,6 +73,16 @@ type (
 		ID() string
 		Message() Request
 		Policy() JobPolicy
+		CorrelationID() monad.Maybe[string] // Correlation ID of the request which queued the job if any
+	}
+
+	// Signal sent once a job has been processed, successfully or not.
+	JobProcessed struct {
+		Notification
+
+		ID    string
+		Name  string              // Name of the job message
+		Error monad.Maybe[string] // Error returned by the job if any, it will be retried
 	}
 
 	GetJobsFilters struct {
@@ -61,7 +90,8 @@ type (
 	}
 
 	// Adapter used to store scheduled jobs. Could be anything from a database to a file or
-	// an in-memory store.
+	// an in-memory store. The correlation ID attached to the context given to Create (see
+	// WithCorrelationID) should be persisted and returned by the job.
 	ScheduledJobsStore interface {
 		Setup() error                                                                        // Setup the store
 		Create(context.Context, Schedulable, CreateOptions) error                            // Create a new scheduled job
@@ -70,11 +100,13 @@ type (
 		GetNextPendingJobs(context.Context) ([]ScheduledJob, error)                          // Get the next pending jobs to be dispatched
 		Retry(context.Context, ScheduledJob, error) error                                    // Retry the given job with the given reason
 		Done(context.Context, ScheduledJob) error                                            // Mark the given job as done
+		Beat(context.Context, WorkerHeartbeat) error                                         // Persist the latest scheduler heartbeat
+		GetHeartbeat(context.Context) (WorkerHeartbeat, error)                               // Retrieve the latest persisted heartbeat, apperr.ErrNotFound if none
 	}
 
 	defaultScheduler struct {
 		bus                    Dispatcher
-		pollInterval           time.Duration
+		pollInterval           atomic.Int64 // Stored as an int64 so it could be changed while running
 		logger                 log.Logger
 		store                  ScheduledJobsStore
 		started                bool
@@ -82,6 +114,13 @@ type (
 		exitGroup              sync.WaitGroup
 		groups                 []*workerGroup
 		messageNameToWorkerIdx map[string]int
+		heartbeat              atomic.Int64 // Unix nano timestamp of the last polling loop activity
+		lastPoll               atomic.Int64 // Unix nano timestamp of the last retrieval of pending jobs
+		startedAt              time.Time
+		paused                 atomic.Bool
+		running                atomic.Int32
+		processed              atomic.Int64
+		failed                 atomic.Int64
 	}
 
 	// Represents a worker group configuration used by a scheduler to spawn the appropriate
@@ -102,13 +141,14 @@ type (
 func NewScheduler(adapter ScheduledJobsStore, log log.Logger, bus Dispatcher, pollInterval time.Duration, groups ...WorkerGroup) RunnableScheduler {
 	s := &defaultScheduler{
 		bus:                    bus,
-		pollInterval:           pollInterval,
 		logger:                 log,
 		store:                  adapter,
 		groups:                 make([]*workerGroup, len(groups)),
 		messageNameToWorkerIdx: make(map[string]int),
 	}
 
+	s.SetPollInterval(pollInterval)
+
 	for i, g := range groups {
 		// Should always have at least one worker
 		if g.Size < 1 {
@@ -148,11 +188,57 @@ func (s *defaultScheduler) Start() {
 	}
 
 	s.started = true
+	s.startedAt = time.Now().UTC()
 
 	s.startGroupRunners()
 	s.startPolling()
 }
 
+func (s *defaultScheduler) Heartbeat() time.Time {
+	if beat := s.heartbeat.Load(); beat != 0 {
+		return time.Unix(0, beat)
+	}
+
+	return time.Time{}
+}
+
+func (s *defaultScheduler) SetPollInterval(interval time.Duration) {
+	s.pollInterval.Store(int64(interval))
+}
+
+func (s *defaultScheduler) Pause() {
+	if !s.paused.Swap(true) {
+		s.logger.Info("scheduler paused, running jobs will be left to finish")
+	}
+}
+
+func (s *defaultScheduler) Resume() {
+	if s.paused.Swap(false) {
+		s.logger.Info("scheduler resumed")
+	}
+}
+
+func (s *defaultScheduler) RunningJobs() int {
+	return int(s.running.Load())
+}
+
+func (s *defaultScheduler) Status() WorkerHeartbeat {
+	status := WorkerHeartbeat{
+		StartedAt:     s.startedAt,
+		BeatAt:        s.Heartbeat().UTC(),
+		JobsProcessed: s.processed.Load(),
+		JobsFailed:    s.failed.Load(),
+		RunningJobs:   s.RunningJobs(),
+		Paused:        s.paused.Load(),
+	}
+
+	if poll := s.lastPoll.Load(); poll != 0 {
+		status.LastPoll.Set(time.Unix(0, poll).UTC())
+	}
+
+	return status
+}
+
 func (s *defaultScheduler) Stop() {
 	if !s.started {
 		return
@@ -187,7 +273,8 @@ func (s *defaultScheduler) startPolling() {
 		)
 
 		for {
-			delay = s.pollInterval - time.Since(lastRun)
+			s.beat()
+			delay = time.Duration(s.pollInterval.Load()) - time.Since(lastRun)
 
 			select {
 			case <-done:
@@ -197,6 +284,10 @@ func (s *defaultScheduler) startPolling() {
 
 			lastRun = time.Now()
 
+			if s.paused.Load() {
+				continue
+			}
+
 			jobs, err := s.store.GetNextPendingJobs(context.Background())
 
 			if err != nil {
@@ -205,6 +296,8 @@ func (s *defaultScheduler) startPolling() {
 				continue
 			}
 
+			s.lastPoll.Store(lastRun.UnixNano())
+
 			for _, job := range jobs {
 				idx, handled := s.messageNameToWorkerIdx[job.Message().Name_()]
 
@@ -213,13 +306,50 @@ func (s *defaultScheduler) startPolling() {
 					continue
 				}
 
-				s.groups[idx].jobs <- job
+				if !s.dispatch(done, s.groups[idx], job) {
+					return
+				}
 			}
 		}
 	})
 }
 
+// Send the job to the given worker group, waiting for a worker to be available. While
+// waiting, the scheduler keeps beating so busy workers are not mistaken for a stuck
+// scheduler. Returns false if the scheduler has been stopped in the meantime, the job
+// will be picked up again on the next start.
+func (s *defaultScheduler) dispatch(done <-chan bool, group *workerGroup, job ScheduledJob) bool {
+	ticker := time.NewTicker(max(time.Duration(s.pollInterval.Load()), time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case group.jobs <- job:
+			return true
+		case <-done:
+			return false
+		case <-ticker.C:
+			s.beat()
+		}
+	}
+}
+
+func (s *defaultScheduler) beat() {
+	s.heartbeat.Store(time.Now().UnixNano())
+
+	if err := s.store.Beat(context.Background(), s.Status()); err != nil {
+		s.logger.Errorw("error while persisting the scheduler heartbeat",
+			"error", err)
+	}
+}
+
+func (JobProcessed) Name_() string { return "bus.event.job_processed" }
+
 func (s *defaultScheduler) handleJobReturn(ctx context.Context, job ScheduledJob, err error) {
+	defer s.notifyProcessed(ctx, job, err)
+
+	s.processed.Add(1)
+
 	if err == nil {
 		if err = s.store.Done(ctx, job); err != nil {
 			s.logger.Errorw("error while marking job as done",
@@ -230,13 +360,35 @@ func (s *defaultScheduler) handleJobReturn(ctx context.Context, job ScheduledJob
 		return
 	}
 
+	s.failed.Add(1)
+
 	s.logger.Warnw("error while processing job, it will be retried later",
 		"job", job.ID(),
 		"name", job.Message().Name_(),
+		"correlation_id", job.CorrelationID().Get(""),
 		"error", err)
 
 	if err = s.store.Retry(ctx, job, err); err != nil {
 		s.logger.Errorw("error while retrying job",
+			"job", job.ID(),
+			"name", job.Message().Name_(),
+			"correlation_id", job.CorrelationID().Get(""),
+			"error", err)
+	}
+}
+
+func (s *defaultScheduler) notifyProcessed(ctx context.Context, job ScheduledJob, err error) {
+	evt := JobProcessed{
+		ID:   job.ID(),
+		Name: job.Message().Name_(),
+	}
+
+	if err != nil {
+		evt.Error.Set(err.Error())
+	}
+
+	if err = s.bus.Notify(ctx, evt); err != nil {
+		s.logger.Errorw("error while notifying job processing",
 			"job", job.ID(),
 			"name", job.Message().Name_(),
 			"error", err)
@@ -253,10 +405,21 @@ func (s *defaultScheduler) startGroupRunners() {
 					case <-done:
 						return
 					case job := <-group.jobs:
+						s.running.Add(1)
 						ctx := context.Background()
-						_, err := s.bus.Send(ctx, job.Message())
+
+						// Propagate the correlation ID so the job can be traced back to its origin
+						if id, isSet := job.CorrelationID().TryGet(); isSet {
+							ctx = WithCorrelationID(ctx, id)
+						}
+
+						err := instrumentJob(ctx, job, func(ctx context.Context) error {
+							_, err := s.bus.Send(ctx, job.Message())
+							return err
+						})
 
 						s.handleJobReturn(ctx, job, err)
+						s.running.Add(-1)
 					}
 				}
 			})
@@ -7,11 +7,13 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/bus/memory"
 	"github.com/YuukanOO/seelf/pkg/flag"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/storage"
 	"github.com/YuukanOO/seelf/pkg/testutil"
@@ -71,6 +73,134 @@ func TestScheduler(t *testing.T) {
 		testutil.Equals(t, 3, adapter.retried[2].id)
 		testutil.ErrorIs(t, bus.ErrNoHandlerRegistered, adapter.retried[2].err)
 	})
+
+	t.Run("should propagate the correlation ID to the job handler", func(t *testing.T) {
+		var received monad.Maybe[string]
+
+		bus.Register(b, func(ctx context.Context, cmd correlatedCommand) (bus.UnitType, error) {
+			received = bus.CorrelationID(ctx)
+			return bus.Unit, nil
+		})
+
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{correlatedCommand{}.Name_()},
+		})
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		testutil.IsNil(t, scheduler.Queue(bus.WithCorrelationID(context.Background(), "some-id"), correlatedCommand{}))
+
+		adapter.wait()
+
+		testutil.HasLength(t, adapter.done, 1)
+		testutil.Equals(t, "some-id", received.MustGet())
+	})
+
+	t.Run("should notify when a job has been processed", func(t *testing.T) {
+		processed := make(chan bus.JobProcessed, 1)
+
+		bus.On(b, func(_ context.Context, evt bus.JobProcessed) error {
+			// Jobs processed by other tests are notified too, do not block them
+			select {
+			case processed <- evt:
+			default:
+			}
+			return nil
+		})
+
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		testutil.IsNil(t, scheduler.Queue(context.Background(), returnCommand{err: errors.New("some error")}))
+
+		evt := <-processed
+
+		testutil.Equals(t, "0", evt.ID)
+		testutil.Equals(t, returnCommand{}.Name_(), evt.Name)
+		testutil.Equals(t, "some error", evt.Error.Get(""))
+	})
+
+	t.Run("should not pick jobs while paused", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, time.Millisecond, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		scheduler.Pause()
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		testutil.IsNil(t, scheduler.Queue(context.Background(), returnCommand{}))
+
+		time.Sleep(20 * time.Millisecond)
+
+		testutil.HasLength(t, adapter.done, 0)
+		testutil.Equals(t, 0, scheduler.RunningJobs())
+
+		scheduler.Resume()
+		adapter.wait()
+
+		testutil.HasLength(t, adapter.done, 1)
+	})
+
+	t.Run("should beat once started", func(t *testing.T) {
+		scheduler := bus.NewScheduler(&adapter{}, logger, b, time.Millisecond)
+
+		testutil.IsTrue(t, scheduler.Heartbeat().IsZero())
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		deadline := time.Now().Add(time.Second)
+
+		for scheduler.Heartbeat().IsZero() && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		testutil.IsTrue(t, time.Since(scheduler.Heartbeat()) < time.Second)
+	})
+
+	t.Run("should persist its activity on each beat", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, time.Millisecond, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		testutil.IsNil(t, scheduler.Queue(context.Background(), returnCommand{}))
+		testutil.IsNil(t, scheduler.Queue(context.Background(), returnCommand{err: errors.New("some error")}))
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		adapter.wait()
+
+		var (
+			heartbeat bus.WorkerHeartbeat
+			deadline  = time.Now().Add(time.Second)
+		)
+
+		for heartbeat.JobsProcessed < 2 && time.Now().Before(deadline) {
+			heartbeat, _ = adapter.GetHeartbeat(context.Background())
+			time.Sleep(time.Millisecond)
+		}
+
+		testutil.Equals(t, int64(2), heartbeat.JobsProcessed)
+		testutil.Equals(t, int64(1), heartbeat.JobsFailed)
+		testutil.IsTrue(t, heartbeat.LastPoll.HasValue())
+		testutil.IsFalse(t, heartbeat.StartedAt.IsZero())
+		testutil.IsFalse(t, heartbeat.Paused)
+	})
 }
 
 var (
@@ -86,13 +216,16 @@ type (
 		policy        bus.JobPolicy
 		err           error
 		preserveOrder bool
+		correlationID monad.Maybe[string]
 	}
 
 	adapter struct {
-		wg      sync.WaitGroup
-		jobs    []*job
-		done    []*job
-		retried []*job
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		heartbeat monad.Maybe[bus.WorkerHeartbeat]
+		jobs      []*job
+		done      []*job
+		retried   []*job
 	}
 
 	returnCommand struct {
@@ -100,14 +233,22 @@ type (
 
 		err error
 	}
+
+	correlatedCommand struct {
+		bus.Command[bus.UnitType]
+	}
 )
 
 func (r returnCommand) Name_() string      { return "returnCommand" }
 func (r returnCommand) ResourceID() string { return "" }
 
-func (j *job) ID() string            { return strconv.Itoa(j.id) }
-func (j *job) Message() bus.Request  { return j.msg }
-func (j *job) Policy() bus.JobPolicy { return j.policy }
+func (correlatedCommand) Name_() string      { return "correlatedCommand" }
+func (correlatedCommand) ResourceID() string { return "" }
+
+func (j *job) ID() string                         { return strconv.Itoa(j.id) }
+func (j *job) Message() bus.Request               { return j.msg }
+func (j *job) Policy() bus.JobPolicy              { return j.policy }
+func (j *job) CorrelationID() monad.Maybe[string] { return j.correlationID }
 
 func (a *adapter) Setup() error { return nil }
 
@@ -115,9 +256,9 @@ func (a *adapter) GetAllJobs(context.Context, bus.GetJobsFilters) (storage.Pagin
 	return storage.Paginated[bus.ScheduledJob]{}, nil
 }
 
-func (a *adapter) Create(_ context.Context, msg bus.Schedulable, opts bus.CreateOptions) error {
+func (a *adapter) Create(ctx context.Context, msg bus.Schedulable, opts bus.CreateOptions) error {
 	a.wg.Add(1)
-	a.jobs = append(a.jobs, &job{id: len(a.jobs), msg: msg, policy: opts.Policy})
+	a.jobs = append(a.jobs, &job{id: len(a.jobs), msg: msg, policy: opts.Policy, correlationID: bus.CorrelationID(ctx)})
 	return nil
 }
 
@@ -150,6 +291,19 @@ func (a *adapter) Retry(_ context.Context, j bus.ScheduledJob, jobErr error) err
 
 }
 
+func (a *adapter) Beat(_ context.Context, heartbeat bus.WorkerHeartbeat) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.heartbeat.Set(heartbeat)
+	return nil
+}
+
+func (a *adapter) GetHeartbeat(context.Context) (bus.WorkerHeartbeat, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.heartbeat.Get(bus.WorkerHeartbeat{}), nil
+}
+
 func (a *adapter) Done(_ context.Context, j bus.ScheduledJob) error {
 	defer a.wg.Done()
 	a.done = append(a.done, j.(*job))
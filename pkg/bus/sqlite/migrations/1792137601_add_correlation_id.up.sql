@@ -0,0 +1 @@
+ALTER TABLE scheduled_jobs ADD correlation_id TEXT NULL;
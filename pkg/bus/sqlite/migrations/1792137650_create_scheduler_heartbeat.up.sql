@@ -0,0 +1,12 @@
+CREATE TABLE scheduler_heartbeat (
+    id INTEGER NOT NULL CHECK (id = 1), -- Only one row is ever stored
+    started_at DATETIME NOT NULL,
+    beat_at DATETIME NOT NULL,
+    last_poll DATETIME NULL,
+    jobs_processed INTEGER NOT NULL,
+    jobs_failed INTEGER NOT NULL,
+    running_jobs INTEGER NOT NULL,
+    paused BOOLEAN NOT NULL,
+
+    CONSTRAINT pk_scheduler_heartbeat PRIMARY KEY(id)
+);
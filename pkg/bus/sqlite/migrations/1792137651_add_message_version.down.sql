@@ -0,0 +1 @@
+ALTER TABLE scheduled_jobs DROP COLUMN message_version;
@@ -0,0 +1 @@
+ALTER TABLE scheduled_jobs ADD message_version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+ALTER TABLE scheduled_jobs ADD failed_at DATETIME NULL;
@@ -21,27 +21,32 @@ var (
 	//go:embed migrations/*.sql
 	migrations embed.FS
 
-	migrationsModule = sqlite.NewMigrationsModule("scheduler", "migrations", migrations)
+	Migrations = sqlite.NewMigrationsModule("scheduler", "migrations", migrations)
 )
 
 type (
 	job struct {
-		id     string
-		msg    bus.Request
-		policy bus.JobPolicy
+		id            string
+		msg           bus.Request
+		policy        bus.JobPolicy
+		correlationID monad.Maybe[string]
+		decodeErr     error // Set when the message could not be rehydrated from its payload
 	}
 
 	jobQuery struct {
-		JobID       string              `json:"id"`
-		ResourceID  string              `json:"resource_id"`
-		Group       string              `json:"group"`
-		MessageName string              `json:"message_name"`
-		MessageData string              `json:"message_data"`
-		QueuedAt    time.Time           `json:"queued_at"`
-		NotBefore   time.Time           `json:"not_before"`
-		ErrorCode   monad.Maybe[string] `json:"error_code"`
-		JobPolicy   bus.JobPolicy       `json:"policy"`
-		Retrieved   bool                `json:"retrieved"`
+		JobID       string                 `json:"id"`
+		ResourceID  string                 `json:"resource_id"`
+		Group       string                 `json:"group"`
+		MessageName string                 `json:"message_name"`
+		MessageData string                 `json:"message_data"`
+		Version     int                    `json:"message_version"`
+		QueuedAt    time.Time              `json:"queued_at"`
+		NotBefore   time.Time              `json:"not_before"`
+		ErrorCode   monad.Maybe[string]    `json:"error_code"`
+		FailedAt    monad.Maybe[time.Time] `json:"failed_at"`
+		JobPolicy   bus.JobPolicy          `json:"policy"`
+		Retrieved   bool                   `json:"retrieved"`
+		Correlation monad.Maybe[string]    `json:"correlation_id"`
 	}
 
 	store struct {
@@ -49,13 +54,15 @@ type (
 	}
 )
 
-func (j *job) ID() string            { return j.id }
-func (j *job) Message() bus.Request  { return j.msg }
-func (j *job) Policy() bus.JobPolicy { return j.policy }
+func (j *job) ID() string                         { return j.id }
+func (j *job) Message() bus.Request               { return j.msg }
+func (j *job) Policy() bus.JobPolicy              { return j.policy }
+func (j *job) CorrelationID() monad.Maybe[string] { return j.correlationID }
 
-func (j *jobQuery) ID() string            { return j.JobID }
-func (j *jobQuery) Message() bus.Request  { panic("not implemented") } // Should never happen because this is a query only job
-func (j *jobQuery) Policy() bus.JobPolicy { return j.JobPolicy }
+func (j *jobQuery) ID() string                         { return j.JobID }
+func (j *jobQuery) Message() bus.Request               { panic("not implemented") } // Should never happen because this is a query only job
+func (j *jobQuery) Policy() bus.JobPolicy              { return j.JobPolicy }
+func (j *jobQuery) CorrelationID() monad.Maybe[string] { return j.Correlation }
 
 // Builds a new adapter persisting jobs in the given sqlite database.
 // For it to work, commands must be (de)serializable using the bus.Marshallable mapper.
@@ -67,7 +74,7 @@ func NewScheduledJobsStore(db *sqlite.Database) bus.ScheduledJobsStore {
 // them as not retrieved so they will be picked up next time GetNextPendingJobs is called.
 // You MUST call this method at the application startup.
 func (s *store) Setup() error {
-	if err := s.db.Migrate(migrationsModule); err != nil {
+	if err := s.db.Migrate(Migrations); err != nil {
 		return err
 	}
 
@@ -93,8 +100,10 @@ func (s *store) Create(
 	}
 
 	var (
-		msgName    = msg.Name_()
-		resourceId = msg.ResourceID()
+		msgName       = msg.Name_()
+		msgVersion    = bus.PayloadVersion(msg)
+		resourceId    = msg.ResourceID()
+		correlationID = bus.CorrelationID(ctx)
 	)
 
 	// Could not use the ON CONFLICT here :'(
@@ -104,34 +113,38 @@ func (s *store) Create(
 		if err = s.db.QueryRowContext(ctx, `
 		SELECT id
 		FROM scheduled_jobs
-		WHERE resource_id = ? AND message_name = ? AND retrieved = false`, resourceId, msgName).
+		WHERE resource_id = ? AND message_name = ? AND retrieved = false AND failed_at IS NULL`, resourceId, msgName).
 			Scan(&existingJobId); err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return err
 		}
 
 		if existingJobId != "" {
-			_, err = s.db.ExecContext(ctx, `UPDATE scheduled_jobs SET message_data = ? WHERE id = ?`, msgValue, existingJobId)
+			_, err = s.db.ExecContext(ctx, `UPDATE scheduled_jobs SET message_data = ?, message_version = ?, correlation_id = ? WHERE id = ?`,
+				msgValue, msgVersion, correlationID, existingJobId)
 			return err
 		}
 	}
 
 	return builder.
 		Insert("scheduled_jobs", builder.Values{
-			"id":           jobId,
-			"resource_id":  resourceId,
-			"[group]":      options.Group.Get(jobId), // Default to the job id if no group set
-			"message_name": msgName,
-			"message_data": msgValue,
-			"queued_at":    now,
-			"not_before":   now,
-			"policy":       options.Policy,
-			"retrieved":    false,
+			"id":              jobId,
+			"resource_id":     resourceId,
+			"[group]":         options.Group.Get(jobId), // Default to the job id if no group set
+			"message_name":    msgName,
+			"message_data":    msgValue,
+			"message_version": msgVersion,
+			"queued_at":       now,
+			"not_before":      now,
+			"policy":          options.Policy,
+			"retrieved":       false,
+			"correlation_id":  correlationID,
 		}).
 		Exec(s.db, ctx)
 }
 
 func (s *store) Delete(ctx context.Context, id string) error {
-	r, err := s.db.ExecContext(ctx, "DELETE FROM scheduled_jobs WHERE id = ? AND (policy & ?) != 0",
+	// Failed jobs will never be processed so they could always be removed
+	r, err := s.db.ExecContext(ctx, "DELETE FROM scheduled_jobs WHERE id = ? AND ((policy & ?) != 0 OR failed_at IS NOT NULL)",
 		id, bus.JobPolicyCancellable)
 
 	if err != nil {
@@ -159,11 +172,14 @@ func (s *store) GetAllJobs(ctx context.Context, filters bus.GetJobsFilters) (sto
 			,[group]
 			,message_name
 			,message_data
+			,message_version
 			,queued_at
 			,not_before
 			,errcode
+			,failed_at
 			,policy
 			,retrieved
+			,correlation_id
 		`).
 		F("FROM scheduled_jobs ORDER BY queued_at").
 		Paginate(s.db, ctx, jobQueryMapper, filters.Page.Get(1), 10)
@@ -171,7 +187,7 @@ func (s *store) GetAllJobs(ctx context.Context, filters bus.GetJobsFilters) (sto
 
 func (s *store) GetNextPendingJobs(ctx context.Context) ([]bus.ScheduledJob, error) {
 	// This query will lock the database to make sure we can't retrieved the same job twice.
-	return builder.
+	jobs, err := builder.
 		Query[bus.ScheduledJob](`
 			UPDATE scheduled_jobs
 			SET retrieved = true
@@ -179,14 +195,36 @@ func (s *store) GetNextPendingJobs(ctx context.Context) ([]bus.ScheduledJob, err
 				SELECT id, MIN(not_before) FROM scheduled_jobs sj
 				WHERE 
 					sj.retrieved = false
+					AND sj.failed_at IS NULL
 					AND sj.not_before <= DATETIME('now')
 					AND sj.[group] NOT IN (SELECT DISTINCT [group] FROM scheduled_jobs WHERE retrieved = true)
-					AND (sj.policy & ? = 0 OR (SELECT COUNT(resource_id) FROM scheduled_jobs WHERE resource_id = sj.resource_id) <= 1)
+					AND (sj.policy & ? = 0 OR (SELECT COUNT(resource_id) FROM scheduled_jobs WHERE resource_id = sj.resource_id AND failed_at IS NULL) <= 1)
 					GROUP BY sj.[group]
 				)
 			)
-			RETURNING id, message_name, message_data, policy`, bus.JobPolicyWaitForOthersResourceID).
+			RETURNING id, message_name, message_data, message_version, policy, correlation_id`, bus.JobPolicyWaitForOthersResourceID).
 		All(s.db, ctx, jobMapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs which could not be rehydrated are put aside instead of blocking the others
+	pending := make([]bus.ScheduledJob, 0, len(jobs))
+
+	for _, j := range jobs {
+		if decodeErr := j.(*job).decodeErr; decodeErr != nil {
+			if err = s.putAside(ctx, j, decodeErr); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		pending = append(pending, j)
+	}
+
+	return pending, nil
 }
 
 func (s *store) Retry(ctx context.Context, j bus.ScheduledJob, jobErr error) error {
@@ -227,32 +265,94 @@ func (s *store) Retry(ctx context.Context, j bus.ScheduledJob, jobErr error) err
 	return err
 }
 
+// Jobs persisted by a newer seelf version are retried since they could be processed
+// once updated again. Other ones will never be rehydrated so they are marked as failed
+// and left in the store for users to see why before deleting them.
+func (s *store) putAside(ctx context.Context, j bus.ScheduledJob, decodeErr error) error {
+	if errors.Is(decodeErr, bus.ErrUnsupportedPayloadVersion) {
+		return s.Retry(ctx, j, decodeErr)
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			errcode = ?
+			,failed_at = ?
+			,retrieved = false
+		WHERE id = ?`, decodeErr.Error(), time.Now().UTC(), j.ID())
+
+	return err
+}
+
 func (s *store) Done(ctx context.Context, j bus.ScheduledJob) error {
 	_, err := s.db.ExecContext(ctx, "DELETE FROM scheduled_jobs WHERE id = ?", j.ID())
 	return err
 }
 
+func (s *store) Beat(ctx context.Context, heartbeat bus.WorkerHeartbeat) error {
+	return builder.
+		Command(`
+			INSERT INTO scheduler_heartbeat (id, started_at, beat_at, last_poll, jobs_processed, jobs_failed, running_jobs, paused)
+			VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				started_at = excluded.started_at
+				,beat_at = excluded.beat_at
+				,last_poll = excluded.last_poll
+				,jobs_processed = excluded.jobs_processed
+				,jobs_failed = excluded.jobs_failed
+				,running_jobs = excluded.running_jobs
+				,paused = excluded.paused`,
+			heartbeat.StartedAt,
+			heartbeat.BeatAt,
+			heartbeat.LastPoll,
+			heartbeat.JobsProcessed,
+			heartbeat.JobsFailed,
+			heartbeat.RunningJobs,
+			heartbeat.Paused,
+		).
+		Exec(s.db, ctx)
+}
+
+func (s *store) GetHeartbeat(ctx context.Context) (bus.WorkerHeartbeat, error) {
+	return builder.
+		Query[bus.WorkerHeartbeat](`
+			SELECT
+				started_at
+				,beat_at
+				,last_poll
+				,jobs_processed
+				,jobs_failed
+				,running_jobs
+				,paused
+			FROM scheduler_heartbeat
+			WHERE id = 1`).
+		One(s.db, ctx, heartbeatMapper)
+}
+
 func jobMapper(scanner storage.Scanner) (bus.ScheduledJob, error) {
 	var (
-		j       job
-		msgName string
-		msgData string
+		j          job
+		msgName    string
+		msgData    string
+		msgVersion int
 	)
 
 	err := scanner.Scan(
 		&j.id,
 		&msgName,
 		&msgData,
+		&msgVersion,
 		&j.policy,
+		&j.correlationID,
 	)
 
 	if err != nil {
 		return &j, err
 	}
 
-	j.msg, err = bus.Marshallable.From(msgName, msgData)
+	j.msg, j.decodeErr = bus.Unmarshal(msgName, msgVersion, msgData)
 
-	return &j, err
+	return &j, nil
 }
 
 func jobQueryMapper(scanner storage.Scanner) (bus.ScheduledJob, error) {
@@ -264,12 +364,31 @@ func jobQueryMapper(scanner storage.Scanner) (bus.ScheduledJob, error) {
 		&j.Group,
 		&j.MessageName,
 		&j.MessageData,
+		&j.Version,
 		&j.QueuedAt,
 		&j.NotBefore,
 		&j.ErrorCode,
+		&j.FailedAt,
 		&j.JobPolicy,
 		&j.Retrieved,
+		&j.Correlation,
 	)
 
 	return &j, err
 }
+
+func heartbeatMapper(scanner storage.Scanner) (bus.WorkerHeartbeat, error) {
+	var h bus.WorkerHeartbeat
+
+	err := scanner.Scan(
+		&h.StartedAt,
+		&h.BeatAt,
+		&h.LastPoll,
+		&h.JobsProcessed,
+		&h.JobsFailed,
+		&h.RunningJobs,
+		&h.Paused,
+	)
+
+	return h, err
+}
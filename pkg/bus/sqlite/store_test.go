@@ -0,0 +1,113 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	bussqlite "github.com/YuukanOO/seelf/pkg/bus/sqlite"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type pingCommand struct {
+	bus.Command[bus.UnitType]
+
+	Host string
+}
+
+func (pingCommand) Name_() string      { return "PingCommand" }
+func (pingCommand) ResourceID() string { return "" }
+
+func Test_Store(t *testing.T) {
+	ctx := context.Background()
+	bus.Register(memory.NewBus(), func(context.Context, pingCommand) (bus.UnitType, error) { return bus.Unit, nil })
+
+	open := func(t *testing.T) (*sqlite.Database, bus.ScheduledJobsStore) {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db"), logger, memory.NewBus())
+
+		testutil.IsNil(t, err)
+
+		t.Cleanup(func() {
+			db.Close()
+		})
+
+		store := bussqlite.NewScheduledJobsStore(db)
+
+		testutil.IsNil(t, store.Setup())
+
+		return db, store
+	}
+
+	queue := func(t *testing.T, db *sqlite.Database, id, name string, version int) {
+		queuedAt := time.Now().UTC().Add(-time.Minute)
+
+		testutil.IsNil(t, builder.Insert("scheduled_jobs", builder.Values{
+			"id":              id,
+			"resource_id":     "",
+			"[group]":         id,
+			"message_name":    name,
+			"message_data":    `{"Host":"example.com"}`,
+			"message_version": version,
+			"queued_at":       queuedAt,
+			"not_before":      queuedAt,
+			"policy":          0,
+			"retrieved":       false,
+		}).Exec(db, ctx))
+	}
+
+	state := func(t *testing.T, db *sqlite.Database, id string) (errcode sql.NullString, failedAt sql.NullTime) {
+		testutil.IsNil(t, db.QueryRowContext(ctx, "SELECT errcode, failed_at FROM scheduled_jobs WHERE id = ?", id).
+			Scan(&errcode, &failedAt))
+
+		return errcode, failedAt
+	}
+
+	t.Run("should mark jobs which could not be rehydrated as failed and never retrieve them again", func(t *testing.T) {
+		db, store := open(t)
+
+		queue(t, db, "unknown", "UnknownCommand", bus.InitialPayloadVersion)
+		queue(t, db, "ping", pingCommand{}.Name_(), bus.InitialPayloadVersion)
+
+		jobs, err := store.GetNextPendingJobs(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, jobs, 1)
+		testutil.Equals(t, "ping", jobs[0].ID())
+
+		errcode, failedAt := state(t, db, "unknown")
+
+		testutil.IsTrue(t, errcode.Valid)
+		testutil.IsTrue(t, failedAt.Valid)
+
+		jobs, err = store.GetNextPendingJobs(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, jobs, 0)
+
+		testutil.IsNil(t, store.Delete(ctx, "unknown"))
+	})
+
+	t.Run("should retry jobs persisted by a newer version", func(t *testing.T) {
+		db, store := open(t)
+
+		queue(t, db, "newer", pingCommand{}.Name_(), bus.InitialPayloadVersion+1)
+
+		jobs, err := store.GetNextPendingJobs(ctx)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, jobs, 0)
+
+		errcode, failedAt := state(t, db, "newer")
+
+		testutil.IsTrue(t, errcode.Valid)
+		testutil.IsFalse(t, failedAt.Valid)
+	})
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
+	"reflect"
+	"strings"
 
 	nenv "github.com/Netflix/go-env"
 	"github.com/YuukanOO/seelf/pkg/ostools"
@@ -20,6 +23,15 @@ type Processable interface {
 	PostLoad() error
 }
 
+// EnvPrefixed is an interface that can be implemented by the target of the Load function to
+// allow every configuration key to be overridden by an environment variable named after its
+// path in the yaml file, uppercased and prefixed, ie. PREFIX_HTTP_PORT for http.port.
+//
+// Those variables take precedence over the ones declared with the env struct tag.
+type EnvPrefixed interface {
+	EnvPrefix() string
+}
+
 // Load the configuration into the target from a yaml file and environment variables.
 //
 // The boolean returned is true if the config file has been found, false otherwise.
@@ -40,6 +52,12 @@ func Load(configFilePath string, target any, dotenvFiles ...string) (exists bool
 		return
 	}
 
+	if prefixed, ok := target.(EnvPrefixed); ok {
+		if err = loadFromPrefixedEnvironment(reflect.ValueOf(target).Elem(), prefixed.EnvPrefix()); err != nil {
+			return
+		}
+	}
+
 	postProcessable, ok := target.(Processable)
 
 	if !ok {
@@ -86,3 +104,71 @@ func loadFromEnvironment(filenames []string, target any) error {
 	_, err := nenv.UnmarshalFromEnviron(target)
 	return err
 }
+
+func loadFromPrefixedEnvironment(target reflect.Value, prefix string) error {
+	targetType := target.Type()
+
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		name, flags, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		// Inlined fields are part of the parent in the yaml file
+		if field.Anonymous && strings.Contains(flags, "inline") {
+			if err := loadFromPrefixedEnvironment(target.Field(i), prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name) // Same default as the yaml package
+		}
+
+		var (
+			key   = prefix + strings.ToUpper(name)
+			value = target.Field(i)
+		)
+
+		if field.Type.Kind() == reflect.Struct && !isEnvUnmarshaler(value) {
+			if err := loadFromPrefixedEnvironment(value, key+"_"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, found := os.LookupEnv(key)
+
+		if !found {
+			continue
+		}
+
+		if err := setFromEnvironment(value, raw); err != nil {
+			return fmt.Errorf("could not read %s environment variable: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func isEnvUnmarshaler(value reflect.Value) bool {
+	_, ok := value.Addr().Interface().(nenv.Unmarshaler)
+	return ok
+}
+
+func setFromEnvironment(value reflect.Value, raw string) error {
+	if unmarshaler, ok := value.Addr().Interface().(nenv.Unmarshaler); ok {
+		return unmarshaler.UnmarshalEnvironmentValue(raw)
+	}
+
+	// Strings are set as is since some values could not be parsed as yaml (such as templates)
+	if value.Kind() == reflect.String {
+		value.SetString(raw)
+		return nil
+	}
+
+	return yaml.Unmarshal([]byte(raw), value.Addr().Interface())
+}
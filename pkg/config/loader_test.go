@@ -35,6 +35,10 @@ type (
 	configurationWithProcessable struct {
 		configuration
 	}
+
+	configurationWithPrefix struct {
+		configuration `yaml:",inline"`
+	}
 )
 
 var errPostLoad = errors.New("post load error")
@@ -43,6 +47,8 @@ func (*configurationWithProcessable) PostLoad() error {
 	return errPostLoad
 }
 
+func (*configurationWithPrefix) EnvPrefix() string { return "APP_" }
+
 func Test_Load(t *testing.T) {
 	// Since for some tests, the monad has the initial value set to true but the
 	// env removes it (setting the monad hasValue to false but keeping the initial value)
@@ -177,6 +183,53 @@ HTTP_TWO=true`,
 		testutil.ErrorIs(t, errPostLoad, err)
 		testutil.IsFalse(t, exists)
 	})
+
+	t.Run("should override every key with prefixed env variables if the target implements the EnvPrefixed interface", func(t *testing.T) {
+		confFilename := "prefixed-conf.yml"
+
+		t.Cleanup(func() {
+			os.Remove(confFilename)
+		})
+
+		os.Clearenv()
+
+		testutil.IsNil(t, ostools.WriteFile(confFilename, []byte(`http:
+  host: 192.168.1.1
+  port: 7777`)))
+
+		t.Setenv("APP_VERBOSE", "true")
+		t.Setenv("APP_HTTP_PORT", "9999")
+		t.Setenv("APP_HTTP_SECURE", "true")
+		t.Setenv("APP_BALANCER_ACME_EMAIL", "admin@example.com")
+
+		var conf configurationWithPrefix
+
+		_, err := config.Load(confFilename, &conf)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, configuration{
+			Verbose: true,
+			Http: httpConfiguration{
+				Host:   "192.168.1.1",
+				Secure: monad.Value(true),
+				Port:   9999,
+			},
+			Balancer: balancerConfiguration{
+				AcmeEmail: "admin@example.com",
+			},
+		}, conf.configuration)
+	})
+
+	t.Run("should fail if a prefixed env variable could not be parsed", func(t *testing.T) {
+		os.Clearenv()
+		t.Setenv("APP_HTTP_PORT", "not a number")
+
+		var conf configurationWithPrefix
+
+		_, err := config.Load("missing-conf.yml", &conf)
+
+		testutil.IsNotNil(t, err)
+	})
 }
 
 func Test_Save(t *testing.T) {
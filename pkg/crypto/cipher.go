@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrInvalidCiphertext = errors.New("invalid_ciphertext")
+
+// Symmetric cipher used to store sensitive values at rest.
+type Cipher interface {
+	Encrypt(string) (string, error)
+	Decrypt(string) (string, error)
+}
+
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+// Builds a new AES-GCM cipher whose key is derived from the given secret.
+// Encrypted values are base64 encoded and prefixed by their random nonce.
+func NewCipher(secret []byte) (Cipher, error) {
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesCipher{aead}, nil
+}
+
+func (c *aesCipher) Encrypt(value string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+func (c *aesCipher) Decrypt(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
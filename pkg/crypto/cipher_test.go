@@ -0,0 +1,41 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Cipher(t *testing.T) {
+	t.Run("should encrypt and decrypt a value", func(t *testing.T) {
+		c, err := crypto.NewCipher([]byte("a secret"))
+		testutil.IsNil(t, err)
+
+		encrypted, err := c.Encrypt("some sensitive value")
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, encrypted != "some sensitive value")
+
+		decrypted, err := c.Decrypt(encrypted)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "some sensitive value", decrypted)
+	})
+
+	t.Run("should fail to decrypt a value encrypted with another secret", func(t *testing.T) {
+		c, _ := crypto.NewCipher([]byte("a secret"))
+		other, _ := crypto.NewCipher([]byte("another secret"))
+
+		encrypted, err := c.Encrypt("some sensitive value")
+		testutil.IsNil(t, err)
+
+		_, err = other.Decrypt(encrypted)
+		testutil.ErrorIs(t, crypto.ErrInvalidCiphertext, err)
+	})
+
+	t.Run("should fail to decrypt a malformed value", func(t *testing.T) {
+		c, _ := crypto.NewCipher([]byte("a secret"))
+
+		_, err := c.Decrypt("not base64!")
+		testutil.ErrorIs(t, crypto.ErrInvalidCiphertext, err)
+	})
+}
@@ -0,0 +1,109 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	encryptedPrefix    = "$seelf$" // Marks values encrypted by a keyring, followed by the id of the key used
+	encryptedSeparator = "$"
+	keyIDLength        = 8
+)
+
+var ErrUnknownKey = errors.New("unknown_encryption_key")
+
+type (
+	// Cipher which encrypts values with its current key while still being able to decrypt
+	// the ones encrypted with previous keys, making keys rotation possible. Encrypted values
+	// embed the id of the key used.
+	Keyring interface {
+		Cipher
+		// Returns true if the value has not been encrypted with the current key, including
+		// values which have not been encrypted at all.
+		NeedsRotation(string) bool
+	}
+
+	keyring struct {
+		currentID string
+		ciphers   map[string]Cipher
+	}
+)
+
+// Builds a keyring encrypting values with the current secret and able to decrypt values
+// encrypted with any of the previous ones.
+func NewKeyring(current []byte, previous ...[]byte) (Keyring, error) {
+	k := &keyring{
+		currentID: keyID(current),
+		ciphers:   make(map[string]Cipher, len(previous)+1),
+	}
+
+	for _, secret := range append([][]byte{current}, previous...) {
+		c, err := NewCipher(secret)
+
+		if err != nil {
+			return nil, err
+		}
+
+		k.ciphers[keyID(secret)] = c
+	}
+
+	return k, nil
+}
+
+// Returns true if the given value has been encrypted by a keyring.
+func IsEncrypted(value string) bool {
+	_, _, ok := parseEncrypted(value)
+	return ok
+}
+
+func (k *keyring) Encrypt(value string) (string, error) {
+	encrypted, err := k.ciphers[k.currentID].Encrypt(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + k.currentID + encryptedSeparator + encrypted, nil
+}
+
+func (k *keyring) Decrypt(value string) (string, error) {
+	id, encrypted, ok := parseEncrypted(value)
+
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	c, found := k.ciphers[id]
+
+	if !found {
+		return "", ErrUnknownKey
+	}
+
+	return c.Decrypt(encrypted)
+}
+
+func (k *keyring) NeedsRotation(value string) bool {
+	id, _, ok := parseEncrypted(value)
+	return !ok || id != k.currentID
+}
+
+// Identifies a secret without leaking it.
+func keyID(secret []byte) string {
+	sum := sha256.Sum256(append([]byte("seelf key id:"), secret...))
+	return hex.EncodeToString(sum[:])[:keyIDLength]
+}
+
+func parseEncrypted(value string) (id string, encrypted string, ok bool) {
+	rest, hasPrefix := strings.CutPrefix(value, encryptedPrefix)
+
+	if !hasPrefix {
+		return "", "", false
+	}
+
+	id, encrypted, ok = strings.Cut(rest, encryptedSeparator)
+
+	return id, encrypted, ok && len(id) == keyIDLength && encrypted != ""
+}
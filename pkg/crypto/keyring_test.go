@@ -0,0 +1,57 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Keyring(t *testing.T) {
+	t.Run("should encrypt and decrypt a value", func(t *testing.T) {
+		k, err := crypto.NewKeyring([]byte("a secret"))
+		testutil.IsNil(t, err)
+
+		encrypted, err := k.Encrypt("some sensitive value")
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, crypto.IsEncrypted(encrypted))
+		testutil.IsFalse(t, k.NeedsRotation(encrypted))
+
+		decrypted, err := k.Decrypt(encrypted)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "some sensitive value", decrypted)
+	})
+
+	t.Run("should decrypt values encrypted with a previous key", func(t *testing.T) {
+		old, _ := crypto.NewKeyring([]byte("old secret"))
+		k, _ := crypto.NewKeyring([]byte("new secret"), []byte("old secret"))
+
+		encrypted, err := old.Encrypt("some sensitive value")
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, k.NeedsRotation(encrypted))
+
+		decrypted, err := k.Decrypt(encrypted)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "some sensitive value", decrypted)
+	})
+
+	t.Run("should fail to decrypt a value encrypted with an unknown key", func(t *testing.T) {
+		other, _ := crypto.NewKeyring([]byte("another secret"))
+		k, _ := crypto.NewKeyring([]byte("a secret"))
+
+		encrypted, _ := other.Encrypt("some sensitive value")
+
+		_, err := k.Decrypt(encrypted)
+		testutil.ErrorIs(t, crypto.ErrUnknownKey, err)
+	})
+
+	t.Run("should consider values which are not encrypted as needing a rotation", func(t *testing.T) {
+		k, _ := crypto.NewKeyring([]byte("a secret"))
+
+		testutil.IsFalse(t, crypto.IsEncrypted("a plain value"))
+		testutil.IsTrue(t, k.NeedsRotation("a plain value"))
+
+		_, err := k.Decrypt("a plain value")
+		testutil.ErrorIs(t, crypto.ErrInvalidCiphertext, err)
+	})
+}
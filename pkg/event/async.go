@@ -0,0 +1,192 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+type (
+	// Pool of goroutines used to run asynchronous event handlers registered with `OnAsync`.
+	//
+	// Handlers run outside of the originating write: their errors and panics are logged
+	// and never propagated back, and the dispatch never waits for them.
+	Pool struct {
+		logger  log.Logger
+		size    int
+		tasks   chan asyncTask
+		mu      sync.RWMutex
+		wg      sync.WaitGroup
+		started bool
+		stopped bool
+	}
+
+	asyncTask struct {
+		name string
+		fn   func(context.Context) error
+	}
+
+	deferredKey struct{}
+
+	deferred struct {
+		mu  sync.Mutex
+		fns []func()
+	}
+)
+
+// Builds a new pool of size goroutines accepting at most capacity pending tasks.
+// When the pool is full, new tasks are dropped (and logged) to never block the caller.
+func NewPool(logger log.Logger, size int, capacity int) *Pool {
+	return &Pool{
+		logger: logger,
+		size:   size,
+		tasks:  make(chan asyncTask, capacity),
+	}
+}
+
+// Start the pool workers.
+func (p *Pool) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return
+	}
+
+	p.started = true
+	p.wg.Add(p.size)
+
+	for i := 0; i < p.size; i++ {
+		go p.work()
+	}
+}
+
+// Stop accepting new tasks and wait for pending ones to complete.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+
+	p.stopped = true
+	close(p.tasks)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *Pool) submit(name string, fn func(context.Context) error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		p.logger.Warnw("event pool stopped, async handler skipped",
+			"event", name)
+		return
+	}
+
+	select {
+	case p.tasks <- asyncTask{name, fn}:
+	default:
+		p.logger.Errorw("event pool is full, async handler skipped",
+			"event", name)
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task asyncTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorw("async event handler panicked",
+				"event", task.name,
+				"panic", fmt.Sprint(r))
+		}
+	}()
+
+	if err := task.fn(context.Background()); err != nil {
+		p.logger.Errorw("async event handler failed",
+			"event", task.name,
+			"error", err)
+	}
+}
+
+// Register a signal handler which will run in the given pool. The handler receives a fresh
+// context since the originating one (and its transaction if any) may be gone by then. Only
+// the correlation ID, if any, is carried over.
+//
+// If the signal is dispatched inside a context built with `WithDeferred`, the handler
+// will only be queued once the deferred functions are flushed, ie. when the originating
+// write has been committed.
+func OnAsync[TSignal bus.Signal](b bus.Bus, pool *Pool, handler bus.SignalHandler[TSignal]) {
+	bus.On(b, func(ctx context.Context, evt TSignal) error {
+		correlationID := bus.CorrelationID(ctx)
+
+		Defer(ctx, func() {
+			pool.submit(evt.Name_(), func(ctx context.Context) error {
+				if id, isSet := correlationID.TryGet(); isSet {
+					ctx = bus.WithCorrelationID(ctx, id)
+				}
+
+				return handler(ctx, evt)
+			})
+		})
+
+		return nil
+	})
+}
+
+// Returns a new context collecting functions registered with `Defer` and a function
+// to call them. The flush function should be called once the unit of work represented
+// by the context has succeeded. If it fails, just don't call it.
+//
+// If the given context is already collecting deferred functions, it is returned as it
+// and the returned flush function does nothing since the outer unit of work owns them.
+func WithDeferred(ctx context.Context) (context.Context, func()) {
+	if _, isDeferring := ctx.Value(deferredKey{}).(*deferred); isDeferring {
+		return ctx, func() {}
+	}
+
+	d := &deferred{}
+
+	return context.WithValue(ctx, deferredKey{}, d), d.flush
+}
+
+// Defer the given function until the unit of work represented by the given context
+// succeeds. If the context was not built with `WithDeferred`, the function is called
+// immediately.
+func Defer(ctx context.Context, fn func()) {
+	d, isDeferring := ctx.Value(deferredKey{}).(*deferred)
+
+	if !isDeferring {
+		fn()
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fns = append(d.fns, fn)
+}
+
+func (d *deferred) flush() {
+	d.mu.Lock()
+	fns := d.fns
+	d.fns = nil
+	d.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
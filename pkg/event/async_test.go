@@ -0,0 +1,111 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_OnAsync(t *testing.T) {
+	logger, _ := log.NewLogger()
+
+	t.Run("should run the handler in the pool without returning its error", func(t *testing.T) {
+		var called atomic.Int32
+		pool := event.NewPool(logger, 2, 10)
+		b := memory.NewBus()
+
+		event.OnAsync(b, pool, func(ctx context.Context, evt domainEventA) error {
+			called.Add(1)
+			return errors.New("some error")
+		})
+
+		pool.Start()
+
+		err := b.Notify(context.Background(), domainEventA{})
+
+		pool.Stop()
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, called.Load())
+	})
+
+	t.Run("should recover from a panicking handler", func(t *testing.T) {
+		var called atomic.Int32
+		pool := event.NewPool(logger, 1, 10)
+		b := memory.NewBus()
+
+		event.OnAsync(b, pool, func(ctx context.Context, evt domainEventA) error {
+			panic("oops")
+		})
+		bus.On(b, func(ctx context.Context, evt domainEventA) error {
+			called.Add(1)
+			return nil
+		})
+
+		pool.Start()
+
+		testutil.IsNil(t, b.Notify(context.Background(), domainEventA{}, domainEventA{}))
+
+		pool.Stop()
+
+		testutil.Equals(t, 2, called.Load())
+	})
+
+	t.Run("should not block when the pool is full", func(t *testing.T) {
+		pool := event.NewPool(logger, 1, 1)
+		b := memory.NewBus()
+
+		event.OnAsync(b, pool, func(ctx context.Context, evt domainEventA) error {
+			return nil
+		})
+
+		done := make(chan struct{})
+
+		go func() {
+			// Pool not started so only the first one will be queued
+			_ = b.Notify(context.Background(), domainEventA{}, domainEventA{}, domainEventA{})
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("notify should not block")
+		}
+	})
+
+	t.Run("should wait for deferred functions to be flushed", func(t *testing.T) {
+		var called atomic.Int32
+		pool := event.NewPool(logger, 1, 10)
+		b := memory.NewBus()
+
+		event.OnAsync(b, pool, func(ctx context.Context, evt domainEventA) error {
+			called.Add(1)
+			return nil
+		})
+
+		pool.Start()
+
+		ctx, flush := event.WithDeferred(context.Background())
+		nestedCtx, nestedFlush := event.WithDeferred(ctx)
+
+		testutil.IsNil(t, b.Notify(nestedCtx, domainEventA{}))
+		nestedFlush()
+
+		time.Sleep(10 * time.Millisecond)
+		testutil.Equals(t, 0, called.Load())
+
+		flush()
+		pool.Stop()
+
+		testutil.Equals(t, 1, called.Load())
+	})
+}
@@ -0,0 +1,68 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+type (
+	// Function handling a single event, such as the ones translating events to storage
+	// updates in stores.
+	Handler func(context.Context, Event) error
+
+	// Middleware used to add cross-cutting behavior around event handlers (logging, metrics,
+	// tracing, filtering, ...).
+	Middleware func(Handler) Handler
+)
+
+// Wraps the given handler with the given middlewares. The first middleware will be the
+// outermost one.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// Middleware logging every handled event with its duration and error if any.
+func Logger(logger log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, evt Event) error {
+			start := time.Now()
+			err := next(ctx, evt)
+
+			if err != nil {
+				logger.Errorw("event handling failed",
+					"event", evt.Name_(),
+					"correlation_id", bus.CorrelationID(ctx).Get(""),
+					"duration", time.Since(start),
+					"error", err)
+			} else {
+				logger.Debugw("event handled",
+					"event", evt.Name_(),
+					"correlation_id", bus.CorrelationID(ctx).Get(""),
+					"duration", time.Since(start))
+			}
+
+			return err
+		}
+	}
+}
+
+// Middleware which only calls the next handler for events matching the given predicate.
+// Other events are silently ignored.
+func Filter(predicate func(Event) bool) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, evt Event) error {
+			if !predicate(evt) {
+				return nil
+			}
+
+			return next(ctx, evt)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Middlewares(t *testing.T) {
+	t.Run("should chain middlewares with the first one being the outermost", func(t *testing.T) {
+		var calls []string
+
+		trace := func(name string) event.Middleware {
+			return func(next event.Handler) event.Handler {
+				return func(ctx context.Context, evt event.Event) error {
+					calls = append(calls, name)
+					return next(ctx, evt)
+				}
+			}
+		}
+
+		handler := event.Chain(func(ctx context.Context, evt event.Event) error {
+			calls = append(calls, "handler")
+			return nil
+		}, trace("first"), trace("second"))
+
+		err := handler(context.Background(), domainEventA{})
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"first", "second", "handler"}, calls)
+	})
+
+	t.Run("should filter events not matching the predicate", func(t *testing.T) {
+		var handled []string
+
+		handler := event.Chain(func(ctx context.Context, evt event.Event) error {
+			handled = append(handled, evt.Name_())
+			return nil
+		}, event.Filter(func(evt event.Event) bool {
+			_, isA := evt.(domainEventA)
+			return isA
+		}))
+
+		testutil.IsNil(t, handler(context.Background(), domainEventA{}))
+		testutil.IsNil(t, handler(context.Background(), domainEventB{}))
+
+		testutil.DeepEquals(t, []string{"domain_event_a"}, handled)
+	})
+}
@@ -0,0 +1,186 @@
+// Package health runs checks against the dependencies of the application to know if
+// it is ready to handle requests.
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+var ErrHeartbeatTooOld = errors.New("heartbeat_too_old")
+
+type (
+	// Returns an error if the checked dependency is not available.
+	Check func(context.Context) error
+
+	// Holds registered checks and run them all at once.
+	Checker struct {
+		mu      sync.RWMutex
+		timeout time.Duration
+		checks  []namedCheck
+	}
+
+	// Result of every checks run by a checker.
+	Report struct {
+		Healthy bool     `json:"healthy"`
+		Checks  []Result `json:"checks"`
+	}
+
+	// Result of a single check.
+	Result struct {
+		Name     string              `json:"name"`
+		Healthy  bool                `json:"healthy"`
+		Error    monad.Maybe[string] `json:"error"`
+		Duration time.Duration       `json:"duration"` // Time taken by the check in nanoseconds
+	}
+
+	namedCheck struct {
+		name  string
+		check Check
+	}
+)
+
+// Builds a new checker. Each check is given at most the provided timeout to complete.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register a check under the given name. Checks are reported in the registration order.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, namedCheck{name, check})
+}
+
+// Run every registered checks concurrently and returns the report. The report is
+// healthy only if every check has succeeded.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	var (
+		report = Report{
+			Healthy: true,
+			Checks:  make([]Result, len(checks)),
+		}
+		wg sync.WaitGroup
+	)
+
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check namedCheck) {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx, check)
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		report.Healthy = report.Healthy && result.Healthy
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check namedCheck) Result {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var (
+		start  = time.Now()
+		result = Result{Name: check.name}
+		done   = make(chan error, 1)
+	)
+
+	// Run in its own goroutine so a check ignoring its context could not hang the report
+	go func() { done <- check.check(ctx) }()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result.Duration = time.Since(start)
+	result.Healthy = err == nil
+
+	if err != nil {
+		result.Error.Set(err.Error())
+	}
+
+	return result
+}
+
+// Builds a check ensuring files could be created in the given directory.
+func Writable(dir string) Check {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+
+		if err != nil {
+			return err
+		}
+
+		name := f.Name()
+
+		if err = f.Close(); err != nil {
+			_ = os.Remove(name)
+			return err
+		}
+
+		return os.Remove(name)
+	}
+}
+
+// Builds a check ensuring the last heartbeat returned by the given function is not
+// older than the given duration.
+func Heartbeat(last func() time.Time, maxAge time.Duration) Check {
+	return func(context.Context) error {
+		if time.Since(last()) > maxAge {
+			return ErrHeartbeatTooOld
+		}
+
+		return nil
+	}
+}
+
+// Builds a check ensuring the given host name resolves to at least one address.
+func Resolvable(host string) Check {
+	return func(ctx context.Context) error {
+		_, err := net.DefaultResolver.LookupHost(ctx, host)
+		return err
+	}
+}
+
+// Builds a check ensuring the given url answers HTTP requests, whatever the response status.
+func Reachable(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+}
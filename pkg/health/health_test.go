@@ -0,0 +1,110 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/health"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Checker(t *testing.T) {
+	t.Run("should be healthy without any check", func(t *testing.T) {
+		report := health.NewChecker(time.Second).Run(context.Background())
+
+		testutil.IsTrue(t, report.Healthy)
+		testutil.HasLength(t, report.Checks, 0)
+	})
+
+	t.Run("should report every check in the registration order", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Register("first", func(context.Context) error { return nil })
+		checker.Register("second", func(context.Context) error { return errors.New("down") })
+
+		report := checker.Run(context.Background())
+
+		testutil.IsFalse(t, report.Healthy)
+		testutil.HasLength(t, report.Checks, 2)
+		testutil.Equals(t, "first", report.Checks[0].Name)
+		testutil.IsTrue(t, report.Checks[0].Healthy)
+		testutil.IsFalse(t, report.Checks[0].Error.HasValue())
+		testutil.Equals(t, "second", report.Checks[1].Name)
+		testutil.IsFalse(t, report.Checks[1].Healthy)
+		testutil.Equals(t, "down", report.Checks[1].Error.MustGet())
+	})
+
+	t.Run("should fail checks taking too long", func(t *testing.T) {
+		checker := health.NewChecker(10 * time.Millisecond)
+		checker.Register("slow", func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+
+		report := checker.Run(context.Background())
+
+		testutil.IsFalse(t, report.Healthy)
+		testutil.Equals(t, context.DeadlineExceeded.Error(), report.Checks[0].Error.MustGet())
+	})
+}
+
+func Test_Writable(t *testing.T) {
+	t.Run("should succeed if the directory is writable", func(t *testing.T) {
+		dir := t.TempDir()
+
+		testutil.IsNil(t, health.Writable(dir)(context.Background()))
+
+		entries, err := os.ReadDir(dir)
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, entries, 0)
+	})
+
+	t.Run("should fail if the directory does not exist", func(t *testing.T) {
+		testutil.IsNotNil(t, health.Writable(filepath.Join(t.TempDir(), "missing"))(context.Background()))
+	})
+}
+
+func Test_Heartbeat(t *testing.T) {
+	t.Run("should succeed if the heartbeat is recent", func(t *testing.T) {
+		check := health.Heartbeat(time.Now, time.Minute)
+
+		testutil.IsNil(t, check(context.Background()))
+	})
+
+	t.Run("should fail if the heartbeat is too old", func(t *testing.T) {
+		check := health.Heartbeat(func() time.Time { return time.Now().Add(-2 * time.Minute) }, time.Minute)
+
+		testutil.ErrorIs(t, health.ErrHeartbeatTooOld, check(context.Background()))
+	})
+}
+
+func Test_Resolvable(t *testing.T) {
+	t.Run("should succeed if the host resolves", func(t *testing.T) {
+		testutil.IsNil(t, health.Resolvable("localhost")(context.Background()))
+	})
+
+	t.Run("should fail if the host does not resolve", func(t *testing.T) {
+		testutil.IsNotNil(t, health.Resolvable("seelf.invalid")(context.Background()))
+	})
+}
+
+func Test_Reachable(t *testing.T) {
+	t.Run("should succeed whatever the response status", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		testutil.IsNil(t, health.Reachable(srv.Client(), srv.URL)(context.Background()))
+	})
+
+	t.Run("should fail if the url could not be reached", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		testutil.IsNotNil(t, health.Reachable(srv.Client(), srv.URL)(context.Background()))
+	})
+}
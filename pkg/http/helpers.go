@@ -3,15 +3,33 @@ package http
 import (
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"path"
 
 	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/gin-gonic/gin"
 )
 
-var ErrUnexpected = apperr.New("unexpected_error") // Error returned when an infrastructure error occurs
+var (
+	ErrUnexpected      = apperr.New("unexpected_error")  // Error returned when an infrastructure error occurs
+	ErrTooManyRequests = apperr.New("too_many_requests") // Error returned when a client has been rate limited
+	ErrRequestTooLarge = apperr.New("request_too_large") // Error returned when the request body exceeds the allowed size
+)
+
+// Body returned for validation errors. It keeps the `detail` map keyed by field names
+// and adds a flat list of structured errors with their parameters so clients can
+// localize them.
+type validationErrorBody struct {
+	apperr.Error
+	Errors []validate.FieldError `json:"errors"`
+}
 
 // Tiny interface to represents needed contrat in order to use helpers provided by this package.
 type Server interface {
@@ -25,6 +43,13 @@ func Bind[TIn any](s Server, handler func(*gin.Context, TIn) error) gin.HandlerF
 		var cmd TIn
 
 		if err := ctx.ShouldBind(&cmd); err != nil {
+			var maxBytesErr *http.MaxBytesError
+
+			if errors.As(err, &maxBytesErr) {
+				ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrRequestTooLarge)
+				return
+			}
+
 			ctx.AbortWithError(http.StatusUnprocessableEntity, err)
 			return
 		}
@@ -71,6 +96,26 @@ func File(ctx *gin.Context, filepath string) error {
 	return nil
 }
 
+// Returns the file at the given path to be downloaded by the client under the given
+// name. Range requests are supported.
+func Attachment(ctx *gin.Context, filepath, filename string) error {
+	ctx.FileAttachment(filepath, filename)
+	return nil
+}
+
+// Returns the content of the given reader to be downloaded by the client under the
+// given name.
+func AttachmentReader(ctx *gin.Context, filename, contentType string, r io.Reader) error {
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	return Reader(ctx, contentType, r)
+}
+
+// Returns the content of the given reader with the given content type.
+func Reader(ctx *gin.Context, contentType string, r io.Reader) error {
+	ctx.DataFromReader(http.StatusOK, -1, contentType, r, nil)
+	return nil
+}
+
 // Mark the request has succeeded with the given data.
 func Ok[TOut any](ctx *gin.Context, data TOut) error {
 	addCommonResponseHeaders(ctx)
@@ -78,29 +123,58 @@ func Ok[TOut any](ctx *gin.Context, data TOut) error {
 	return nil
 }
 
-// Handle the given non-nil error and sets the status code based on error type.
+// Handle the given non-nil error and sets the status code based on error kind.
 func HandleError(s Server, ctx *gin.Context, err error) {
 	var (
 		status int = http.StatusInternalServerError
 		data   any = ErrUnexpected
 	)
 
-	// Translates the error type to the appropriate HTTP status code
-	if _, isAppErr := apperr.As[apperr.Error](err); isAppErr {
-		status = http.StatusBadRequest // Default to HTTP 400
-		data = err
-
-		if errors.Is(err, apperr.ErrNotFound) {
-			status = http.StatusNotFound // But if it's a not found, that's an HTTP 404
-		}
-	} else {
-		s.Logger().Errorw(err.Error(), "error", err)
-	}
+	// Translates the error kind to the appropriate HTTP status code
+	monad.Fail[any](err).MatchErr(
+		func(err error) {
+			status = http.StatusUnprocessableEntity
+			data = validationError(err)
+		},
+		func(err error) {
+			status = domainErrorStatus(err)
+			data = err
+		},
+		func(err error) {
+			s.Logger().Errorw(err.Error(),
+				"correlation_id", bus.CorrelationID(ctx.Request.Context()).Get(""),
+				"error", err)
+		},
+	)
 
 	ctx.Error(err)
 	ctx.AbortWithStatusJSON(status, data)
 }
 
+func validationError(err error) any {
+	appErr, isAppErr := apperr.As[apperr.Error](err)
+	fieldErrs, isValidationErr := validate.Errors(err)
+
+	if !isAppErr || !isValidationErr {
+		return err
+	}
+
+	return validationErrorBody{appErr, fieldErrs}
+}
+
+func domainErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperr.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrConcurrentModification):
+		return http.StatusConflict // Resource has been modified by someone else
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func addCommonResponseHeaders(ctx *gin.Context) {
 	ctx.Header("Cache-Control", "public, max-age=0, must-revalidate")
 }
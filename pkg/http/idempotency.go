@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed" // Set on responses replayed from a previous request
+	maxIdempotencyKeyLength  = 255
+)
+
+var (
+	ErrInvalidIdempotencyKey = apperr.New("invalid_idempotency_key") // Error returned when the idempotency key is too long
+	ErrIdempotencyKeyInUse   = apperr.New("idempotency_key_in_use")  // Error returned when a request with the same key is still processing
+	ErrIdempotencyKeyReused  = apperr.New("idempotency_key_reused")  // Error returned when the key has been used for another request
+)
+
+type (
+	// Request made with an idempotency key, along with its response once processed.
+	IdempotentRequest struct {
+		Key         string
+		Fingerprint string // Identifies the request so a key could not be reused for another one
+		ExpiresAt   time.Time
+		Response    monad.Maybe[IdempotentResponse]
+	}
+
+	IdempotentResponse struct {
+		Status      int
+		ContentType string
+		Location    string
+		Body        []byte
+	}
+
+	// Persists idempotent requests until they expire.
+	IdempotencyStore interface {
+		// Reserve the key of the given request. If it has already been reserved and
+		// has not expired yet, returns the existing request instead.
+		Reserve(context.Context, IdempotentRequest) (monad.Maybe[IdempotentRequest], error)
+		// Store the response of a reserved request so it could be replayed.
+		Complete(context.Context, string, IdempotentResponse) error
+		// Release a reserved key so the request could be retried.
+		Release(context.Context, string) error
+	}
+
+	// Writer keeping a copy of the response body so it could be stored.
+	responseRecorder struct {
+		gin.ResponseWriter
+		body bytes.Buffer
+	}
+)
+
+// Make mutating requests sent with an Idempotency-Key header safe to retry: the first
+// response is stored for the given ttl and returned as is to subsequent requests with
+// the same key. The scope function returns who the key belongs to, such as the
+// authenticated user, so clients could not replay responses of each other.
+func Idempotent(s Server, store IdempotencyStore, ttl time.Duration, scope func(*gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+
+		if key == "" || ttl <= 0 || !isMutation(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrInvalidIdempotencyKey)
+			return
+		}
+
+		request := IdempotentRequest{
+			Key:         scope(ctx) + ":" + key,
+			Fingerprint: ctx.Request.Method + " " + ctx.Request.URL.Path,
+			ExpiresAt:   time.Now().UTC().Add(ttl),
+		}
+
+		existing, err := store.Reserve(ctx.Request.Context(), request)
+
+		if err != nil {
+			HandleError(s, ctx, err)
+			return
+		}
+
+		if previous, found := existing.TryGet(); found {
+			replay(ctx, request, previous)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		ctx.Next()
+
+		// Keep storing the response even if the client has gone away, that's the whole point
+		storeCtx := context.WithoutCancel(ctx.Request.Context())
+		status := recorder.Status()
+
+		// Server errors and conflicts are transient, let the client retry them
+		if status >= http.StatusInternalServerError || status == http.StatusConflict {
+			err = store.Release(storeCtx, request.Key)
+		} else {
+			err = store.Complete(storeCtx, request.Key, IdempotentResponse{
+				Status:      status,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Location:    recorder.Header().Get("Location"),
+				Body:        recorder.body.Bytes(),
+			})
+		}
+
+		if err != nil {
+			s.Logger().Errorw("could not save idempotent request",
+				"error", err)
+		}
+	}
+}
+
+func replay(ctx *gin.Context, request, previous IdempotentRequest) {
+	if previous.Fingerprint != request.Fingerprint {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrIdempotencyKeyReused)
+		return
+	}
+
+	response, processed := previous.Response.TryGet()
+
+	if !processed {
+		ctx.AbortWithStatusJSON(http.StatusConflict, ErrIdempotencyKeyInUse)
+		return
+	}
+
+	ctx.Header(IdempotentReplayedHeader, "true")
+
+	if response.Location != "" {
+		ctx.Header("Location", response.Location)
+	}
+
+	if len(response.Body) == 0 {
+		ctx.AbortWithStatus(response.Status)
+		return
+	}
+
+	ctx.Data(response.Status, response.ContentType, response.Body)
+	ctx.Abort()
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
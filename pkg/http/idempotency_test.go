@@ -0,0 +1,149 @@
+package http_test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_Idempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	arrange := func() (*gin.Engine, *int, *memoryIdempotencyStore) {
+		var (
+			calls  int
+			store  = &memoryIdempotencyStore{requests: make(map[string]http.IdempotentRequest)}
+			router = gin.New()
+		)
+
+		router.Use(http.Idempotent(server{}, store, time.Hour, func(*gin.Context) string { return "user" }))
+		router.POST("/apps", func(ctx *gin.Context) {
+			calls++
+			ctx.Header("Location", "/apps/1")
+			ctx.JSON(nethttp.StatusCreated, gin.H{"calls": calls})
+		})
+		router.POST("/other", func(ctx *gin.Context) {
+			ctx.Status(nethttp.StatusNoContent)
+		})
+		router.POST("/failing", func(ctx *gin.Context) {
+			calls++
+			ctx.Status(nethttp.StatusInternalServerError)
+		})
+
+		return router, &calls, store
+	}
+
+	send := func(router *gin.Engine, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, path, nil)
+
+		if key != "" {
+			req.Header.Set(http.IdempotencyKeyHeader, key)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should process requests without a key as usual", func(t *testing.T) {
+		router, calls, _ := arrange()
+
+		send(router, "/apps", "")
+		send(router, "/apps", "")
+
+		testutil.Equals(t, 2, *calls)
+	})
+
+	t.Run("should replay the response of a request sent with the same key", func(t *testing.T) {
+		router, calls, _ := arrange()
+
+		first := send(router, "/apps", "key")
+		second := send(router, "/apps", "key")
+
+		testutil.Equals(t, 1, *calls)
+		testutil.Equals(t, nethttp.StatusCreated, second.Code)
+		testutil.Equals(t, first.Body.String(), second.Body.String())
+		testutil.Equals(t, "/apps/1", second.Header().Get("Location"))
+		testutil.Equals(t, "true", second.Header().Get(http.IdempotentReplayedHeader))
+		testutil.Equals(t, "", first.Header().Get(http.IdempotentReplayedHeader))
+	})
+
+	t.Run("should reject a key reused for another request", func(t *testing.T) {
+		router, _, _ := arrange()
+
+		send(router, "/apps", "key")
+		w := send(router, "/other", "key")
+
+		testutil.Equals(t, nethttp.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("should reject a key still being processed", func(t *testing.T) {
+		router, calls, store := arrange()
+
+		store.requests["user:key"] = http.IdempotentRequest{
+			Key:         "user:key",
+			Fingerprint: "POST /apps",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+
+		w := send(router, "/apps", "key")
+
+		testutil.Equals(t, nethttp.StatusConflict, w.Code)
+		testutil.Equals(t, 0, *calls)
+	})
+
+	t.Run("should let the client retry a request which has failed", func(t *testing.T) {
+		router, calls, _ := arrange()
+
+		send(router, "/failing", "key")
+		send(router, "/failing", "key")
+
+		testutil.Equals(t, 2, *calls)
+	})
+}
+
+type memoryIdempotencyStore struct {
+	mu       sync.Mutex
+	requests map[string]http.IdempotentRequest
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, request http.IdempotentRequest) (monad.Maybe[http.IdempotentRequest], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, found := s.requests[request.Key]; found && existing.ExpiresAt.After(time.Now()) {
+		return monad.Value(existing), nil
+	}
+
+	s.requests[request.Key] = request
+
+	return monad.None[http.IdempotentRequest](), nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key string, response http.IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request := s.requests[key]
+	request.Response.Set(response)
+	s.requests[key] = request
+
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.requests, key)
+
+	return nil
+}
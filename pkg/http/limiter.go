@@ -0,0 +1,163 @@
+package http
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// Limiters not used for this duration are forgotten to keep the memory bounded.
+	limiterIdleTimeout = 10 * time.Minute
+	// Hard cap on the number of tracked keys, the least recently seen one is forgotten
+	// when reached so a flood of new keys could not grow the memory.
+	maxLimiters = 10000
+)
+
+type (
+	// Token bucket rate limiter keyed by an arbitrary string, such as a client IP
+	// or an API token. Keys are kept in a least recently seen list so idle and evicted
+	// ones could be found without scanning every key.
+	RateLimiter struct {
+		mu       sync.Mutex
+		limit    rate.Limit
+		burst    int
+		limiters map[string]*list.Element
+		recent   *list.List // Most recently seen keys first
+	}
+
+	keyedLimiter struct {
+		key      string
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+)
+
+// Limit the size of request bodies to the given number of bytes. Requests announcing
+// a bigger body are rejected right away, other ones fail when reading past the limit.
+func LimitBody(maxBytes int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > maxBytes {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrRequestTooLarge)
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)
+
+		ctx.Next()
+	}
+}
+
+// Rate limit requests using the given limiter. The key function returns which bucket
+// the request belongs to, such as the client IP.
+func RateLimit(limiter *RateLimiter, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if allowed, retryAfter := limiter.Allow(key(ctx)); !allowed {
+			TooManyRequests(ctx, retryAfter)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Abort the request of a rate limited client, telling it how long to wait before retrying.
+func TooManyRequests(ctx *gin.Context, retryAfter time.Duration) {
+	ctx.Header("Retry-After", RetryAfterSeconds(retryAfter))
+	ctx.AbortWithStatusJSON(http.StatusTooManyRequests, ErrTooManyRequests)
+}
+
+// Formats the given delay as a number of seconds, rounded up, as expected by the
+// Retry-After header.
+func RetryAfterSeconds(retryAfter time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+}
+
+// Builds a rate limiter allowing perSecond requests per key on average, with bursts
+// of at most burst requests.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		limit:    rate.Limit(perSecond),
+		burst:    burst,
+		limiters: make(map[string]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// Try to consume a token for the given key. If none is available, returns false
+// and how long the caller should wait before retrying.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	l.forgetIdle(now)
+
+	element, found := l.limiters[key]
+
+	if found {
+		l.recent.MoveToFront(element)
+	} else {
+		if len(l.limiters) >= maxLimiters {
+			l.forget(l.recent.Back())
+		}
+
+		element = l.recent.PushFront(&keyedLimiter{key: key, limiter: rate.NewLimiter(l.limit, l.burst)})
+		l.limiters[key] = element
+	}
+
+	entry := element.Value.(*keyedLimiter)
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+
+	if delay == 0 {
+		return true, 0
+	}
+
+	reservation.CancelAt(now)
+
+	return false, delay
+}
+
+// Check if the given key has no token left, without consuming one.
+func (l *RateLimiter) Exhausted(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, found := l.limiters[key]
+
+	return found && element.Value.(*keyedLimiter).limiter.TokensAt(time.Now()) < 1
+}
+
+// Number of keys currently tracked.
+func (l *RateLimiter) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.limiters)
+}
+
+// Forget keys not seen for a while, starting from the least recently seen one.
+func (l *RateLimiter) forgetIdle(now time.Time) {
+	for element := l.recent.Back(); element != nil; element = l.recent.Back() {
+		if now.Sub(element.Value.(*keyedLimiter).lastSeen) <= limiterIdleTimeout {
+			return
+		}
+
+		l.forget(element)
+	}
+}
+
+func (l *RateLimiter) forget(element *list.Element) {
+	l.recent.Remove(element)
+	delete(l.limiters, element.Value.(*keyedLimiter).key)
+}
@@ -0,0 +1,130 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	t.Run("should allow requests up to the burst", func(t *testing.T) {
+		limiter := http.NewRateLimiter(1, 2)
+
+		allowed, _ := limiter.Allow("client")
+		testutil.IsTrue(t, allowed)
+		allowed, _ = limiter.Allow("client")
+		testutil.IsTrue(t, allowed)
+
+		allowed, retryAfter := limiter.Allow("client")
+		testutil.IsFalse(t, allowed)
+		testutil.IsTrue(t, retryAfter > 0)
+	})
+
+	t.Run("should limit each key on its own", func(t *testing.T) {
+		limiter := http.NewRateLimiter(1, 1)
+
+		allowed, _ := limiter.Allow("client")
+		testutil.IsTrue(t, allowed)
+		allowed, _ = limiter.Allow("client")
+		testutil.IsFalse(t, allowed)
+
+		allowed, _ = limiter.Allow("another client")
+		testutil.IsTrue(t, allowed)
+	})
+
+	t.Run("should tell if a key has no token left", func(t *testing.T) {
+		limiter := http.NewRateLimiter(1, 1)
+
+		testutil.IsFalse(t, limiter.Exhausted("client"))
+
+		limiter.Allow("client")
+
+		testutil.IsTrue(t, limiter.Exhausted("client"))
+		testutil.Equals(t, 1, limiter.Size())
+	})
+
+	t.Run("should cap the number of tracked keys", func(t *testing.T) {
+		limiter := http.NewRateLimiter(1, 1)
+
+		limiter.Allow("first")
+
+		for i := 0; i < 10000; i++ {
+			limiter.Allow(strconv.Itoa(i))
+		}
+
+		testutil.Equals(t, 10000, limiter.Size())
+		testutil.IsFalse(t, limiter.Exhausted("first"))
+		testutil.IsTrue(t, limiter.Exhausted("9999"))
+	})
+
+	t.Run("should forget the least recently seen key when the cap is reached", func(t *testing.T) {
+		limiter := http.NewRateLimiter(1, 1)
+
+		limiter.Allow("first")
+		limiter.Allow("second")
+
+		for i := 0; i < 9998; i++ {
+			limiter.Allow(strconv.Itoa(i))
+		}
+
+		limiter.Allow("first")
+		limiter.Allow("new")
+
+		testutil.Equals(t, 10000, limiter.Size())
+		testutil.IsTrue(t, limiter.Exhausted("first"))
+		testutil.IsFalse(t, limiter.Exhausted("second"))
+		testutil.IsTrue(t, limiter.Exhausted("new"))
+	})
+}
+
+func Test_Limits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	router := gin.New()
+	router.Use(http.RateLimit(http.NewRateLimiter(1, 3), func(*gin.Context) string { return "client" }))
+	router.POST("/", http.LimitBody(32), http.Bind(server{}, func(ctx *gin.Context, p payload) error {
+		return http.Ok(ctx, p)
+	}))
+
+	send := func(body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		if chunked {
+			req.ContentLength = -1
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	big := `{"name":"` + strings.Repeat("a", 64) + `"}`
+
+	testutil.Equals(t, nethttp.StatusRequestEntityTooLarge, send(big, false).Code)
+	testutil.Equals(t, nethttp.StatusRequestEntityTooLarge, send(big, true).Code)
+
+	testutil.Equals(t, nethttp.StatusOK, send(`{"name":"john"}`, false).Code)
+
+	w := send(`{"name":"john"}`, false)
+
+	testutil.Equals(t, nethttp.StatusTooManyRequests, w.Code)
+	testutil.NotEquals(t, "", w.Header().Get("Retry-After"))
+}
+
+type server struct{}
+
+func (server) IsSecure() bool     { return false }
+func (server) Logger() log.Logger { return must.Panic(log.NewLogger()) }
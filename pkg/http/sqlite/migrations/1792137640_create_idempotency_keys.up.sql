@@ -0,0 +1,13 @@
+CREATE TABLE idempotency_keys (
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    response_status INTEGER NULL,
+    response_content_type TEXT NULL,
+    response_location TEXT NULL,
+    response_body BLOB NULL,
+
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY(key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+var (
+	//go:embed migrations/*.sql
+	migrations embed.FS
+
+	Migrations = sqlite.NewMigrationsModule("http", "migrations", migrations)
+)
+
+type (
+	IdempotencyStore interface {
+		http.IdempotencyStore
+		Setup() error
+		Purge(context.Context, time.Time) error // Remove requests expired at the given date
+	}
+
+	idempotencyStore struct {
+		db *sqlite.Database
+	}
+)
+
+// Builds a new store persisting idempotent requests in the given sqlite database.
+func NewIdempotencyStore(db *sqlite.Database) IdempotencyStore {
+	return &idempotencyStore{db}
+}
+
+// Migrate the database. You MUST call this method at the application startup.
+func (s *idempotencyStore) Setup() error {
+	return s.db.Migrate(Migrations)
+}
+
+func (s *idempotencyStore) Reserve(ctx context.Context, request http.IdempotentRequest) (monad.Maybe[http.IdempotentRequest], error) {
+	// Expired keys are reserved again as if they never existed
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			fingerprint = excluded.fingerprint
+			,expires_at = excluded.expires_at
+			,response_status = NULL
+			,response_content_type = NULL
+			,response_location = NULL
+			,response_body = NULL
+		WHERE idempotency_keys.expires_at <= ?`,
+		request.Key, request.Fingerprint, request.ExpiresAt, time.Now().UTC())
+
+	if err != nil {
+		return monad.None[http.IdempotentRequest](), err
+	}
+
+	if reserved, err := result.RowsAffected(); err != nil || reserved > 0 {
+		return monad.None[http.IdempotentRequest](), err
+	}
+
+	existing, err := builder.
+		Query[http.IdempotentRequest](`
+		SELECT
+			key
+			,fingerprint
+			,expires_at
+			,response_status
+			,response_content_type
+			,response_location
+			,response_body
+		FROM idempotency_keys
+		WHERE key = ?`, request.Key).
+		One(s.db, ctx, idempotentRequestMapper)
+
+	if err != nil {
+		return monad.None[http.IdempotentRequest](), err
+	}
+
+	return monad.Value(existing), nil
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, key string, response http.IdempotentResponse) error {
+	return builder.
+		Update("idempotency_keys", builder.Values{
+			"response_status":       response.Status,
+			"response_content_type": response.ContentType,
+			"response_location":     response.Location,
+			"response_body":         response.Body,
+		}).
+		F("WHERE key = ?", key).
+		Exec(s.db, ctx)
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, key string) error {
+	return builder.
+		Command("DELETE FROM idempotency_keys WHERE key = ?", key).
+		Exec(s.db, ctx)
+}
+
+func (s *idempotencyStore) Purge(ctx context.Context, date time.Time) error {
+	return builder.
+		Command("DELETE FROM idempotency_keys WHERE expires_at <= ?", date).
+		Exec(s.db, ctx)
+}
+
+func idempotentRequestMapper(scanner storage.Scanner) (r http.IdempotentRequest, err error) {
+	var (
+		status      *int
+		contentType *string
+		location    *string
+		body        []byte
+	)
+
+	err = scanner.Scan(
+		&r.Key,
+		&r.Fingerprint,
+		&r.ExpiresAt,
+		&status,
+		&contentType,
+		&location,
+		&body,
+	)
+
+	if err != nil || status == nil {
+		return r, err
+	}
+
+	r.Response.Set(http.IdempotentResponse{
+		Status:      *status,
+		ContentType: *contentType,
+		Location:    *location,
+		Body:        body,
+	})
+
+	return r, nil
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// Levels used by every logger sharing the same root. The base level applies to
+	// loggers without a module override.
+	levels struct {
+		mu        sync.RWMutex
+		base      zapcore.Level
+		overrides map[string]zapcore.Level
+	}
+
+	// Encoder and write syncer currently in use. Swapped as a whole when the logger
+	// is reconfigured so every derived logger picks up the new output.
+	output struct {
+		core  zapcore.Core
+		sinks []Sink
+	}
+
+	// Core used by every logger. It filters entries based on the module they come
+	// from and writes them to the current output.
+	moduleCore struct {
+		levels *levels
+		output *atomic.Pointer[output]
+		fields []zapcore.Field
+	}
+)
+
+func newLevels(base zapcore.Level) *levels {
+	return &levels{
+		base:      base,
+		overrides: make(map[string]zapcore.Level),
+	}
+}
+
+func (l *levels) setBase(lvl zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.base = lvl
+}
+
+func (l *levels) baseLevel() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.base
+}
+
+func (l *levels) set(module string, lvl zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[module] = lvl
+}
+
+func (l *levels) reset(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.overrides, module)
+}
+
+// Returns the lowest level enabled by any module so the fast path of zap does not
+// discard entries which may be enabled by an override.
+func (l *levels) min() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := l.base
+
+	for _, lvl := range l.overrides {
+		if lvl < result {
+			result = lvl
+		}
+	}
+
+	return result
+}
+
+// Retrieve the level for the given module. Nested modules (ie. deployment.docker)
+// inherit the level of their closest configured parent.
+func (l *levels) of(module string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name := module; name != ""; {
+		if lvl, found := l.overrides[name]; found {
+			return lvl
+		}
+
+		idx := strings.LastIndex(name, ".")
+
+		if idx < 0 {
+			break
+		}
+
+		name = name[:idx]
+	}
+
+	return l.base
+}
+
+func (c *moduleCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.levels.min()
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleCore{
+		levels: c.levels,
+		output: c.output,
+		fields: append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...),
+	}
+}
+
+func (c *moduleCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.of(entry.LoggerName) {
+		return checked
+	}
+
+	return checked.AddCore(entry, c)
+}
+
+func (c *moduleCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.fields) > 0 {
+		fields = append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...)
+	}
+
+	return c.output.Load().core.Write(entry, fields)
+}
+
+func (c *moduleCore) Sync() error {
+	return c.output.Load().core.Sync()
+}
@@ -3,15 +3,20 @@ package log
 
 import (
 	"errors"
+	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var (
-	ErrInvalidLevelValue  = errors.New("invalid_level_value")
-	ErrInvalidFormatValue = errors.New("invalid_format_value")
+	ErrInvalidLevelValue   = errors.New("invalid_level_value")
+	ErrInvalidFormatValue  = errors.New("invalid_format_value")
+	ErrInvalidModulesValue = errors.New("invalid_modules_value")
 )
 
 const (
@@ -40,28 +45,55 @@ type (
 
 		Warn(args ...any)
 		Warnw(msg string, keysAndValues ...any)
+
+		// Returns a logger for the given module. Its level can be overridden with
+		// SetModuleLevel. Nested modules are separated by a dot.
+		Named(module string) Logger
 	}
 
 	// Configurable logger to define additional settings.
 	ConfigurableLogger interface {
 		Logger
-		Configure(OutputFormat, Level) error // Configure the logger output format and level.
+		// Configure the logger output format, level and sinks. If no sink is given,
+		// logs are written to the standard error. Previous sinks not given again are closed.
+		Configure(OutputFormat, Level, ...Sink) error
+		SetModuleLevel(module string, lvl Level) // Override the level of a module, takes effect immediately.
+		ResetModuleLevel(module string)          // Remove the level override of a module.
 	}
 
 	wrappedLogger struct {
 		*zap.SugaredLogger
+		root *root
+	}
+
+	// State shared by a logger and every named logger derived from it.
+	root struct {
+		mu     sync.Mutex
+		levels *levels
+		output atomic.Pointer[output]
 	}
 )
 
-// Builds a new logger.
-func NewLogger() (ConfigurableLogger, error) {
-	l, err := configure(OutputConsole, InfoLevel)
+// Builds a new logger writing to the given sinks (standard error if none).
+func NewLogger(sinks ...Sink) (ConfigurableLogger, error) {
+	r := &root{levels: newLevels(zapcore.Level(InfoLevel))}
 
-	if err != nil {
+	if err := r.configure(OutputConsole, InfoLevel, sinks); err != nil {
 		return nil, err
 	}
 
-	return &wrappedLogger{l.Sugar()}, nil
+	core := &moduleCore{
+		levels: r.levels,
+		output: &r.output,
+	}
+
+	l := zap.New(core,
+		zap.AddCaller(),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		zap.AddStacktrace(zap.LevelEnablerFunc(r.stacktraceEnabled)),
+	)
+
+	return &wrappedLogger{l.Sugar(), r}, nil
 }
 
 // Try to parse the given raw level string into a valid log.Level.
@@ -92,30 +124,108 @@ func ParseFormat(format string) (OutputFormat, error) {
 	}
 }
 
-func (l *wrappedLogger) Configure(format OutputFormat, lvl Level) error {
-	newLogger, err := configure(format, lvl)
+// Parse per module levels expressed as a comma separated list of module=level,
+// such as "scheduler=debug,deployment.docker=warn".
+func ParseModuleLevels(value string) (map[string]Level, error) {
+	result := make(map[string]Level)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+
+		if part == "" {
+			continue
+		}
+
+		module, rawLevel, found := strings.Cut(part, "=")
+		module = strings.TrimSpace(module)
+
+		if !found || module == "" {
+			return nil, ErrInvalidModulesValue
+		}
 
-	if err != nil {
-		return err
+		lvl, err := ParseLevel(strings.TrimSpace(rawLevel))
+
+		if err != nil {
+			return nil, ErrInvalidModulesValue
+		}
+
+		result[module] = lvl
 	}
 
-	l.SugaredLogger = newLogger.Sugar()
+	return result, nil
+}
+
+func (l *wrappedLogger) Configure(format OutputFormat, lvl Level, sinks ...Sink) error {
+	return l.root.configure(format, lvl, sinks)
+}
+
+func (l *wrappedLogger) SetModuleLevel(module string, lvl Level) {
+	l.root.levels.set(module, zapcore.Level(lvl))
+}
+
+func (l *wrappedLogger) ResetModuleLevel(module string) {
+	l.root.levels.reset(module)
+}
 
-	return nil
+func (l *wrappedLogger) Named(module string) Logger {
+	return &wrappedLogger{l.SugaredLogger.Named(module), l.root}
 }
 
-func configure(format OutputFormat, lvl Level) (*zap.Logger, error) {
-	conf := zap.NewProductionConfig()
-	conf.Level.SetLevel(zapcore.Level(lvl))
-	conf.Development = lvl == DebugLevel
-	conf.Encoding = string(format)
+func (r *root) configure(format OutputFormat, lvl Level, sinks []Sink) error {
+	var encoder zapcore.Encoder
 
 	switch format {
 	case OutputConsole:
-		conf.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
 	case OutputJSON:
-		conf.EncoderConfig = zap.NewProductionEncoderConfig()
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	default:
+		return ErrInvalidFormatValue
+	}
+
+	if len(sinks) == 0 {
+		sinks = []Sink{Stderr()}
+	}
+
+	syncers := make([]zapcore.WriteSyncer, len(sinks))
+
+	for i, sink := range sinks {
+		syncers[i] = sink
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Filtering is done by the module core so the underlying one accepts everything
+	previous := r.output.Swap(&output{
+		core:  zapcore.NewCore(encoder, zapcore.Lock(zapcore.NewMultiWriteSyncer(syncers...)), zapcore.DebugLevel),
+		sinks: sinks,
+	})
+
+	r.levels.setBase(zapcore.Level(lvl))
+
+	if previous == nil {
+		return nil
+	}
+
+	var err error
+
+	for _, sink := range previous.sinks {
+		if !slices.Contains(sinks, sink) {
+			err = errors.Join(err, sink.Close())
+		}
+	}
+
+	return err
+}
+
+// Stack traces are included for errors and, in debug mode, warnings too.
+func (r *root) stacktraceEnabled(lvl zapcore.Level) bool {
+	threshold := zapcore.ErrorLevel
+
+	if r.levels.baseLevel() == zapcore.DebugLevel {
+		threshold = zapcore.WarnLevel
 	}
 
-	return conf.Build()
+	return lvl >= threshold
 }
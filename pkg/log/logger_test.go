@@ -0,0 +1,128 @@
+package log_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type memorySink struct {
+	mu     sync.Mutex
+	lines  []string
+	closed bool
+}
+
+func (s *memorySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = append(s.lines, string(p))
+	return len(p), nil
+}
+
+func (s *memorySink) Sync() error  { return nil }
+func (s *memorySink) Close() error { s.closed = true; return nil }
+
+func Test_ParseModuleLevels(t *testing.T) {
+	t.Run("should parse module levels", func(t *testing.T) {
+		levels, err := log.ParseModuleLevels(" scheduler=debug, deployment.docker=warn,")
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, map[string]log.Level{
+			"scheduler":         log.DebugLevel,
+			"deployment.docker": log.WarnLevel,
+		}, levels)
+	})
+
+	t.Run("should fail on invalid values", func(t *testing.T) {
+		_, err := log.ParseModuleLevels("scheduler")
+		testutil.ErrorIs(t, log.ErrInvalidModulesValue, err)
+
+		_, err = log.ParseModuleLevels("scheduler=verbose")
+		testutil.ErrorIs(t, log.ErrInvalidModulesValue, err)
+	})
+}
+
+func Test_Logger(t *testing.T) {
+	t.Run("should write to the given sinks", func(t *testing.T) {
+		sink := &memorySink{}
+		logger, err := log.NewLogger(sink)
+
+		testutil.IsNil(t, err)
+
+		logger.Info("hello")
+		logger.Debug("not shown")
+
+		testutil.HasLength(t, sink.lines, 1)
+		testutil.Contains(t, "hello", sink.lines[0])
+	})
+
+	t.Run("should apply module level overrides at runtime", func(t *testing.T) {
+		sink := &memorySink{}
+		logger, _ := log.NewLogger(sink)
+		scheduler := logger.Named("scheduler")
+		docker := logger.Named("deployment").Named("docker")
+
+		logger.SetModuleLevel("scheduler", log.DebugLevel)
+		logger.SetModuleLevel("deployment", log.ErrorLevel)
+
+		scheduler.Debug("scheduler debug")
+		docker.Warn("docker warn")
+		logger.Debug("root debug")
+
+		testutil.HasLength(t, sink.lines, 1)
+		testutil.Contains(t, "scheduler debug", sink.lines[0])
+
+		logger.ResetModuleLevel("deployment")
+		docker.Warn("docker warn")
+
+		testutil.HasLength(t, sink.lines, 2)
+	})
+
+	t.Run("should switch derived loggers to the new sinks and close the previous ones when reconfigured", func(t *testing.T) {
+		first, second := &memorySink{}, &memorySink{}
+		logger, _ := log.NewLogger(first)
+		named := logger.Named("http")
+
+		testutil.IsNil(t, logger.Configure(log.OutputJSON, log.InfoLevel, second))
+
+		named.Info("hello")
+
+		testutil.IsTrue(t, first.closed)
+		testutil.HasLength(t, first.lines, 0)
+		testutil.HasLength(t, second.lines, 1)
+		testutil.Contains(t, `"logger":"http"`, second.lines[0])
+	})
+}
+
+func Test_FileSink(t *testing.T) {
+	t.Run("should rotate the file when it reaches the max size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "logs", "seelf.log")
+		sink, err := log.NewFileSink(path, 1, 2)
+
+		testutil.IsNil(t, err)
+
+		chunk := []byte(strings.Repeat("a", 600*1024))
+
+		for i := 0; i < 4; i++ {
+			_, err = sink.Write(chunk)
+			testutil.IsNil(t, err)
+		}
+
+		testutil.IsNil(t, sink.Close())
+
+		for _, p := range []string{path, path + ".1", path + ".2"} {
+			info, err := os.Stat(p)
+			testutil.IsNil(t, err)
+			testutil.Equals(t, int64(len(chunk)), info.Size())
+		}
+
+		_, err = os.Stat(path + ".3")
+		testutil.IsTrue(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	fileSinkPerm = 0640
+	megabyte     = 1024 * 1024
+)
+
+type (
+	// Destination where log entries are written. Every configured sink receives
+	// all the entries enabled by the logger.
+	Sink interface {
+		Write([]byte) (int, error)
+		Sync() error
+		Close() error
+	}
+
+	stdSink struct {
+		file *os.File
+	}
+
+	fileSink struct {
+		mu         sync.Mutex
+		path       string
+		maxSize    int64
+		maxBackups int
+		file       *os.File
+		size       int64
+	}
+)
+
+// Sink writing to the standard output.
+func Stdout() Sink { return stdSink{os.Stdout} }
+
+// Sink writing to the standard error. This is the default one.
+func Stderr() Sink { return stdSink{os.Stderr} }
+
+func (s stdSink) Write(p []byte) (int, error) { return s.file.Write(p) }
+func (s stdSink) Sync() error                 { return s.file.Sync() }
+func (s stdSink) Close() error                { return nil } // Standard streams are never closed
+
+// Builds a new sink appending to the file at the given path. When the file reaches
+// maxSizeMB megabytes, it is rotated and at most maxBackups older files are kept
+// (path.1 being the most recent). A maxSizeMB of 0 disables the rotation.
+func NewFileSink(path string, maxSizeMB int, maxBackups int) (Sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	s := &fileSink{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * megabyte,
+		maxBackups: maxBackups,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(p)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+
+	return n, err
+}
+
+func (s *fileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Sync()
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileSinkPerm)
+
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	if s.maxBackups <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return s.open()
+	}
+
+	// Shift existing backups, the oldest one being overwritten
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *fileSink) backupPath(idx int) string {
+	return fmt.Sprintf("%s.%d", s.path, idx)
+}
@@ -0,0 +1,38 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+	"net/url"
+)
+
+// Builds a new sink sending entries to a syslog daemon. The address should be empty
+// to use the local daemon or an URL such as udp://host:514 or tcp://host:514.
+func NewSyslogSink(address string, tag string) (Sink, error) {
+	var network, raddr string
+
+	if address != "" {
+		u, err := url.Parse(address)
+
+		if err != nil {
+			return nil, err
+		}
+
+		network, raddr = u.Scheme, u.Host
+	}
+
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &syslogSink{w}, nil
+}
+
+type syslogSink struct {
+	*syslog.Writer
+}
+
+func (s *syslogSink) Sync() error { return nil }
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package log
+
+import "errors"
+
+var ErrSyslogNotSupported = errors.New("syslog_not_supported")
+
+// Syslog is not available on this platform.
+func NewSyslogSink(address string, tag string) (Sink, error) {
+	return nil, ErrSyslogNotSupported
+}
@@ -0,0 +1,167 @@
+// The package mail sends plain text emails through an SMTP server.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	implicitTLSPort = 465
+	dialTimeout     = 10 * time.Second
+)
+
+type (
+	// Options needed to reach an SMTP server.
+	Options struct {
+		Host     string
+		Port     int    // Port 465 uses implicit TLS, other ones upgrade the connection with STARTTLS when supported
+		Username string // Authenticate with PLAIN if set
+		Password string
+		From     string // Address used as the sender of every email, such as "seelf <seelf@example.com>"
+	}
+
+	// Plain text email.
+	Message struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	Sender interface {
+		Send(context.Context, Message) error
+	}
+
+	smtpSender struct {
+		options Options
+	}
+)
+
+// Builds a sender which opens a new connection to the SMTP server for each email.
+func NewSMTP(options Options) Sender {
+	return &smtpSender{options}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) (finalErr error) {
+	from, err := netmail.ParseAddress(s.options.From)
+
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.options.Host, strconv.Itoa(s.options.Port))
+	tlsConfig := &tls.Config{ServerName: s.options.Host}
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	var conn net.Conn
+
+	if s.options.Port == implicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.options.Host)
+
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	defer func() {
+		if finalErr != nil {
+			_ = client.Close()
+		}
+	}()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && s.options.Port != implicitTLSPort {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.options.Username, s.options.Password, s.options.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(s.build(from, msg)); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// Builds the raw message with its headers and a quoted-printable body.
+func (s *smtpSender) build(from *netmail.Address, msg Message) []byte {
+	var b bytes.Buffer
+
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", msg.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+
+	for _, header := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", header[0], header[1])
+	}
+
+	b.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n")))
+	_ = qp.Close()
+
+	return b.Bytes()
+}
+
+func messageID(address string) string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+
+	return "<" + hex.EncodeToString(buf) + address[strings.LastIndex(address, "@"):] + ">"
+}
@@ -0,0 +1,125 @@
+package mail_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/mail"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_SMTP(t *testing.T) {
+	t.Run("should send the message to the server", func(t *testing.T) {
+		host, port, received := fakeServer(t)
+		sender := mail.NewSMTP(mail.Options{
+			Host: host,
+			Port: port,
+			From: "seelf <seelf@example.com>",
+		})
+
+		err := sender.Send(context.Background(), mail.Message{
+			To:      "john@doe.com",
+			Subject: "Déploiement échoué",
+			Body:    "Hello\nWorld",
+		})
+
+		testutil.IsNil(t, err)
+
+		transcript := <-received
+
+		testutil.Contains(t, "MAIL FROM:<seelf@example.com>", transcript)
+		testutil.Contains(t, "RCPT TO:<john@doe.com>", transcript)
+		testutil.Contains(t, `From: "seelf" <seelf@example.com>`, transcript)
+		testutil.Contains(t, "To: john@doe.com", transcript)
+		testutil.Contains(t, "Subject: =?utf-8?q?D=C3=A9ploiement_=C3=A9chou=C3=A9?=", transcript)
+		testutil.Contains(t, "Message-ID: <", transcript)
+		testutil.Contains(t, "Hello\r\nWorld", transcript)
+	})
+
+	t.Run("should fail if the from address is invalid", func(t *testing.T) {
+		sender := mail.NewSMTP(mail.Options{
+			Host: "localhost",
+			Port: 25,
+			From: "not an address",
+		})
+
+		err := sender.Send(context.Background(), mail.Message{To: "john@doe.com"})
+
+		testutil.IsNotNil(t, err)
+	})
+}
+
+// Starts a minimal SMTP server accepting a single message and returning everything
+// the client has sent once the session is over.
+func fakeServer(t *testing.T) (string, int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		var (
+			transcript strings.Builder
+			reader     = bufio.NewReader(conn)
+			inData     bool
+		)
+
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+
+		for {
+			line, err := reader.ReadString('\n')
+
+			if err != nil {
+				break
+			}
+
+			transcript.WriteString(line)
+
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+				}
+				continue
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				inData = true
+				reply("354 Go ahead")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+
+		received <- transcript.String()
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	return addr.IP.String(), addr.Port, received
+}
@@ -4,10 +4,15 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
 
+var ErrIncompatibleType = errors.New("incompatible_type")
+
 // Represents an optional value of type T. It implements some infrastructure interfaces
 // such as JSON (un)marshalling and database convert.
 //
@@ -28,6 +33,24 @@ func None[T any]() (m Maybe[T]) {
 	return m
 }
 
+// Instantiates a monad from the given pointer, nil being represented as an empty monad.
+func FromPtr[T any](ptr *T) (m Maybe[T]) {
+	if ptr != nil {
+		m.Set(*ptr)
+	}
+
+	return m
+}
+
+// Transforms the inner value of the given monad if it has one.
+func Map[T, U any](m Maybe[T], mapper func(T) U) Maybe[U] {
+	if !m.hasValue {
+		return None[U]()
+	}
+
+	return Value(mapper(m.value))
+}
+
 // Assign the given value to the monad.
 func (m *Maybe[T]) Set(value T) {
 	m.hasValue = true
@@ -65,6 +88,26 @@ func (m Maybe[T]) Get(fallback T) T {
 	return m.value
 }
 
+// Retrieve the inner value or the result of the given function if it doesn't have one.
+// Useful when the fallback is expensive to compute.
+func (m Maybe[T]) OrElse(fallback func() T) T {
+	if !m.hasValue {
+		return fallback()
+	}
+
+	return m.value
+}
+
+// Returns a pointer to a copy of the inner value or nil if it doesn't have one.
+func (m Maybe[T]) Ptr() *T {
+	if !m.hasValue {
+		return nil
+	}
+
+	value := m.value
+	return &value
+}
+
 // Implements the db valuer interface to persist it easily. If you want the value of
 // the maybe, you may check Get instead.
 func (m Maybe[T]) Value() (driver.Value, error) {
@@ -79,6 +122,9 @@ func (m Maybe[T]) Value() (driver.Value, error) {
 // Implements the db scanner interface to retrieve it easily from the storage.
 func (m *Maybe[T]) Scan(value any) error {
 	if value == nil {
+		var zero T
+		m.hasValue = false
+		m.value = zero
 		return nil
 	}
 
@@ -97,7 +143,9 @@ func (m *Maybe[T]) Scan(value any) error {
 			return err
 		}
 
-		m.value = v.(T) // FIXME: it will panic if T is not the same type as v (for example, when scanning optional domain.UserID)
+		if m.value, err = convert[T](v); err != nil {
+			return err
+		}
 	}
 
 	// Either way, no error, it means the value has been retrieved correctly.
@@ -170,3 +218,29 @@ func (m *Maybe[T]) UnmarshalEnvironmentValue(data string) error {
 
 	return nil
 }
+
+// Converts the given driver value to the type T. If the value is not directly of type T
+// (for example a string scanned into an optional domain.UserID), it will try to convert it
+// if the underlying types are compatible.
+func convert[T any](value driver.Value) (T, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+
+	var target T
+
+	// Special case since the driver may return bytes for a text column
+	if b, isBytes := value.([]byte); isBytes {
+		value = string(b)
+	}
+
+	source := reflect.ValueOf(value)
+	targetType := reflect.TypeOf(target)
+
+	// Only convert between same kinds to prevent unexpected conversions such as int to string
+	if targetType == nil || source.Kind() != targetType.Kind() || !source.Type().ConvertibleTo(targetType) {
+		return target, fmt.Errorf("%w: could not convert %T to %T", ErrIncompatibleType, value, target)
+	}
+
+	return source.Convert(targetType).Interface().(T), nil
+}
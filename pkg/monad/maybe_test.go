@@ -124,6 +124,60 @@ func Test_Maybe(t *testing.T) {
 		testutil.Equals(t, "data", m.MustGet())
 	})
 
+	t.Run("should scan values into named types and reset the value on null", func(t *testing.T) {
+		type userID string
+
+		var m monad.Maybe[userID]
+
+		err := m.Scan([]byte("uid"))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "uid", m.MustGet())
+
+		err = m.Scan(nil)
+
+		testutil.IsNil(t, err)
+		testutil.IsFalse(t, m.HasValue())
+	})
+
+	t.Run("should returns an error when scanning an incompatible type", func(t *testing.T) {
+		var m monad.Maybe[string]
+
+		err := m.Scan(int64(42))
+
+		testutil.ErrorIs(t, monad.ErrIncompatibleType, err)
+		testutil.IsFalse(t, m.HasValue())
+	})
+
+	t.Run("should be built from a pointer", func(t *testing.T) {
+		value := "value"
+
+		testutil.IsFalse(t, monad.FromPtr[string](nil).HasValue())
+		testutil.Equals(t, "value", monad.FromPtr(&value).MustGet())
+		testutil.IsTrue(t, monad.None[string]().Ptr() == nil)
+		testutil.Equals(t, "value", *monad.Value("value").Ptr())
+	})
+
+	t.Run("should map the inner value if any", func(t *testing.T) {
+		length := func(s string) int { return len(s) }
+
+		testutil.IsFalse(t, monad.Map(monad.None[string](), length).HasValue())
+		testutil.Equals(t, 5, monad.Map(monad.Value("value"), length).MustGet())
+	})
+
+	t.Run("should call the fallback function only if it doesn't have a value", func(t *testing.T) {
+		called := false
+		fallback := func() string {
+			called = true
+			return "fallback"
+		}
+
+		testutil.Equals(t, "value", monad.Value("value").OrElse(fallback))
+		testutil.IsFalse(t, called)
+		testutil.Equals(t, "fallback", monad.None[string]().OrElse(fallback))
+		testutil.IsTrue(t, called)
+	})
+
 	t.Run("should correctly marshal to json", func(t *testing.T) {
 		var m monad.Maybe[string]
 
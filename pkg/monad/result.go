@@ -0,0 +1,74 @@
+package monad
+
+import "github.com/YuukanOO/seelf/pkg/apperr"
+
+// Represents the result of an operation which may have failed. Combined with `MapResult`
+// and `Bind`, it makes it easy to chain operations and stop at the first error.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Instantiates a successful result.
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Instantiates a failed result.
+func Fail[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Instantiates a result from a classic Go (value, error) return.
+func ResultFrom[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Fail[T](err)
+	}
+
+	return Ok(value)
+}
+
+// Transforms the inner value of a successful result.
+func MapResult[T, U any](r Result[T], mapper func(T) U) Result[U] {
+	if r.err != nil {
+		return Fail[U](r.err)
+	}
+
+	return Ok(mapper(r.value))
+}
+
+// Chains an operation which may fail on a successful result.
+func Bind[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Fail[U](r.err)
+	}
+
+	return fn(r.value)
+}
+
+// Has the operation succeeded?
+func (r Result[T]) IsOk() bool { return r.err == nil }
+
+// Retrieve the error if any.
+func (r Result[T]) Err() error { return r.err }
+
+// Returns the classic Go (value, error) pair, useful at the boundary of the app layer.
+func (r Result[T]) Get() (T, error) { return r.value, r.err }
+
+// Calls the appropriate function based on the kind of error hold by this result, if any.
+// It enables callers to distinguish validation, domain and infrastructure errors without
+// type checks everywhere.
+func (r Result[T]) MatchErr(
+	validation func(error),
+	domain func(error),
+	infrastructure func(error),
+) {
+	switch apperr.KindOf(r.err) {
+	case apperr.KindValidation:
+		validation(r.err)
+	case apperr.KindDomain:
+		domain(r.err)
+	case apperr.KindInfrastructure:
+		infrastructure(r.err)
+	}
+}
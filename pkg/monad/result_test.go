@@ -0,0 +1,62 @@
+package monad_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Result(t *testing.T) {
+	t.Run("should be built from a classic value, error pair", func(t *testing.T) {
+		ok := monad.ResultFrom(42, nil)
+		failed := monad.ResultFrom(0, errors.New("some error"))
+
+		testutil.IsTrue(t, ok.IsOk())
+		testutil.IsNil(t, ok.Err())
+		testutil.IsFalse(t, failed.IsOk())
+		testutil.IsNotNil(t, failed.Err())
+	})
+
+	t.Run("should map the value of a successful result", func(t *testing.T) {
+		value, err := monad.MapResult(monad.Ok(42), strconv.Itoa).Get()
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "42", value)
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		expectedErr := errors.New("some error")
+		called := false
+
+		_, err := monad.Bind(monad.Fail[int](expectedErr), func(v int) monad.Result[string] {
+			called = true
+			return monad.Ok(strconv.Itoa(v))
+		}).Get()
+
+		testutil.ErrorIs(t, expectedErr, err)
+		testutil.IsFalse(t, called)
+	})
+
+	t.Run("should match the error kind", func(t *testing.T) {
+		var kinds []string
+
+		match := func(err error) {
+			monad.Fail[int](err).MatchErr(
+				func(error) { kinds = append(kinds, "validation") },
+				func(error) { kinds = append(kinds, "domain") },
+				func(error) { kinds = append(kinds, "infrastructure") },
+			)
+		}
+
+		match(apperr.Wrap(apperr.ErrValidationFailed, errors.New("field error")))
+		match(apperr.New("some_domain_error"))
+		match(errors.New("some infra error"))
+		monad.Ok(42).MatchErr(nil, nil, nil) // Should not call anything
+
+		testutil.DeepEquals(t, []string{"validation", "domain", "infrastructure"}, kinds)
+	})
+}
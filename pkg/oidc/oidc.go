@@ -0,0 +1,204 @@
+// The package oidc implements the authorization code flow of OpenID Connect providers.
+//
+// The identity of the user is retrieved from the userinfo endpoint of the provider
+// using the access token received during the code exchange, which is done directly
+// between seelf and the provider, so there is no need to verify ID tokens locally.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+var (
+	ErrDiscoveryFailed = errors.New("oidc_discovery_failed")
+	ErrUserInfoFailed  = errors.New("oidc_userinfo_failed")
+	ErrMissingEmail    = errors.New("oidc_missing_email")
+
+	scopes = []string{"openid", "email", "profile"}
+)
+
+type (
+	// Options needed to reach an OpenID Connect provider.
+	Options struct {
+		Name         string // Name of the provider displayed to users
+		Issuer       string // Issuer url used to discover provider endpoints
+		ClientID     string
+		ClientSecret string
+	}
+
+	// Identity of a user as returned by the provider.
+	Identity struct {
+		Issuer        string
+		Subject       string
+		Email         string
+		EmailVerified bool
+	}
+
+	// Provider client. Endpoints are discovered on first use and kept afterwards.
+	Provider struct {
+		options Options
+		client  *http.Client
+		mu      sync.Mutex
+		config  *discovery
+	}
+
+	discovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+
+	userInfo struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"` // Some providers returns it as a string
+	}
+)
+
+// Builds a new provider client.
+func New(options Options) *Provider {
+	return &Provider{
+		options: options,
+		client:  http.DefaultClient,
+	}
+}
+
+func (p *Provider) Name() string { return p.options.Name }
+
+// Returns the provider url to which the user should be redirected to authenticate.
+// The state will be sent back to the redirect url and should be checked to prevent
+// CSRF attacks.
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURL, state string) (string, error) {
+	config, err := p.oauth2Config(ctx, redirectURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state), nil
+}
+
+// Exchange the code received on the redirect url for an access token and use it to
+// retrieve the user identity.
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code string) (Identity, error) {
+	config, err := p.oauth2Config(ctx, redirectURL)
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	token, err := config.Exchange(ctx, code)
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.UserInfoEndpoint, nil)
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	token.SetAuthHeader(req)
+
+	var info userInfo
+
+	if err = p.getJSON(req, &info); err != nil {
+		return Identity{}, errors.Join(ErrUserInfoFailed, err)
+	}
+
+	if info.Subject == "" {
+		return Identity{}, ErrUserInfoFailed
+	}
+
+	if info.Email == "" {
+		return Identity{}, ErrMissingEmail
+	}
+
+	return Identity{
+		Issuer:        p.config.Issuer,
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified == true || info.EmailVerified == "true",
+	}, nil
+}
+
+func (p *Provider) oauth2Config(ctx context.Context, redirectURL string) (oauth2.Config, error) {
+	if err := p.discover(ctx); err != nil {
+		return oauth2.Config{}, err
+	}
+
+	return oauth2.Config{
+		ClientID:     p.options.ClientID,
+		ClientSecret: p.options.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.config.AuthorizationEndpoint,
+			TokenURL: p.config.TokenEndpoint,
+		},
+	}, nil
+}
+
+// Retrieve provider endpoints. It is done lazily so seelf could start even if the
+// provider is not reachable yet.
+func (p *Provider) discover(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config != nil {
+		return nil
+	}
+
+	issuer := strings.TrimSuffix(p.options.Issuer, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+discoveryPath, nil)
+
+	if err != nil {
+		return errors.Join(ErrDiscoveryFailed, err)
+	}
+
+	var config discovery
+
+	if err = p.getJSON(req, &config); err != nil {
+		return errors.Join(ErrDiscoveryFailed, err)
+	}
+
+	if strings.TrimSuffix(config.Issuer, "/") != issuer {
+		return errors.Join(ErrDiscoveryFailed, fmt.Errorf("issuer mismatch, expected %s, got %s", issuer, config.Issuer))
+	}
+
+	p.config = &config
+
+	return nil
+}
+
+func (p *Provider) getJSON(req *http.Request, target any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
@@ -0,0 +1,134 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/oidc"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Provider(t *testing.T) {
+	newServer := func(issuer *string, userinfo map[string]any) *httptest.Server {
+		mux := http.NewServeMux()
+		srv := httptest.NewServer(mux)
+
+		mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 *issuer,
+				"authorization_endpoint": srv.URL + "/authorize",
+				"token_endpoint":         srv.URL + "/token",
+				"userinfo_endpoint":      srv.URL + "/userinfo",
+			})
+		})
+
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			if r.FormValue("code") != "acode" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "anaccesstoken",
+				"token_type":   "Bearer",
+			})
+		})
+
+		mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer anaccesstoken" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			json.NewEncoder(w).Encode(userinfo)
+		})
+
+		return srv
+	}
+
+	t.Run("should build the authorization url", func(t *testing.T) {
+		var issuer string
+		srv := newServer(&issuer, nil)
+		defer srv.Close()
+		issuer = srv.URL
+
+		provider := oidc.New(oidc.Options{Issuer: issuer, ClientID: "aclient"})
+
+		authURL, err := provider.AuthCodeURL(context.Background(), "http://seelf.localhost/callback", "astate")
+
+		testutil.IsNil(t, err)
+
+		u, _ := url.Parse(authURL)
+		testutil.Equals(t, "/authorize", u.Path)
+		testutil.Equals(t, "aclient", u.Query().Get("client_id"))
+		testutil.Equals(t, "astate", u.Query().Get("state"))
+		testutil.Equals(t, "http://seelf.localhost/callback", u.Query().Get("redirect_uri"))
+		testutil.Equals(t, "openid email profile", u.Query().Get("scope"))
+	})
+
+	t.Run("should fail if the issuer does not match", func(t *testing.T) {
+		issuer := "http://another.issuer"
+		srv := newServer(&issuer, nil)
+		defer srv.Close()
+
+		provider := oidc.New(oidc.Options{Issuer: srv.URL, ClientID: "aclient"})
+
+		_, err := provider.AuthCodeURL(context.Background(), "http://seelf.localhost/callback", "astate")
+
+		testutil.ErrorIs(t, oidc.ErrDiscoveryFailed, err)
+	})
+
+	t.Run("should fail if the code could not be exchanged", func(t *testing.T) {
+		var issuer string
+		srv := newServer(&issuer, nil)
+		defer srv.Close()
+		issuer = srv.URL
+
+		provider := oidc.New(oidc.Options{Issuer: issuer, ClientID: "aclient"})
+
+		_, err := provider.Exchange(context.Background(), "http://seelf.localhost/callback", "invalid")
+
+		testutil.IsNotNil(t, err)
+	})
+
+	t.Run("should fail if the provider does not return an email", func(t *testing.T) {
+		var issuer string
+		srv := newServer(&issuer, map[string]any{"sub": "asubject"})
+		defer srv.Close()
+		issuer = srv.URL
+
+		provider := oidc.New(oidc.Options{Issuer: issuer, ClientID: "aclient"})
+
+		_, err := provider.Exchange(context.Background(), "http://seelf.localhost/callback", "acode")
+
+		testutil.ErrorIs(t, oidc.ErrMissingEmail, err)
+	})
+
+	t.Run("should retrieve the user identity", func(t *testing.T) {
+		var issuer string
+		srv := newServer(&issuer, map[string]any{
+			"sub":            "asubject",
+			"email":          "john@doe.com",
+			"email_verified": "true",
+		})
+		defer srv.Close()
+		issuer = srv.URL
+
+		provider := oidc.New(oidc.Options{Issuer: issuer + "/", ClientID: "aclient"})
+
+		identity, err := provider.Exchange(context.Background(), "http://seelf.localhost/callback", "acode")
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, oidc.Identity{
+			Issuer:        issuer,
+			Subject:       "asubject",
+			Email:         "john@doe.com",
+			EmailVerified: true,
+		}, identity)
+	})
+}
@@ -0,0 +1,261 @@
+// Package openapi builds OpenAPI 3 documents from the Go types used by HTTP handlers
+// so clients and SDKs could be generated from them.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	Version = "3.0.3"
+
+	jsonContentType      = "application/json"
+	multipartContentType = "multipart/form-data"
+)
+
+type (
+	// Root OpenAPI document.
+	Document struct {
+		OpenAPI    string                `json:"openapi"`
+		Info       Info                  `json:"info"`
+		Servers    []Server              `json:"servers,omitempty"`
+		Security   []SecurityRequirement `json:"security,omitempty"`
+		Paths      map[string]PathItem   `json:"paths"`
+		Components Components            `json:"components"`
+
+		generator *generator
+	}
+
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	Server struct {
+		URL string `json:"url"`
+	}
+
+	// Operations available on a path keyed by their lowercased HTTP method.
+	PathItem map[string]*Operation
+
+	Operation struct {
+		OperationID string                 `json:"operationId"`
+		Summary     string                 `json:"summary,omitempty"`
+		Tags        []string               `json:"tags,omitempty"`
+		Security    *[]SecurityRequirement `json:"security,omitempty"` // Pointer so an empty slice could be serialized
+		Parameters  []Parameter            `json:"parameters,omitempty"`
+		RequestBody *RequestBody           `json:"requestBody,omitempty"`
+		Responses   map[string]Response    `json:"responses"`
+	}
+
+	Parameter struct {
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required,omitempty"`
+		Schema   *Schema `json:"schema"`
+	}
+
+	RequestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	Response struct {
+		Description string               `json:"description"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	Components struct {
+		Schemas         map[string]*Schema        `json:"schemas"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	SecurityScheme struct {
+		Type   string `json:"type"`
+		In     string `json:"in,omitempty"`
+		Name   string `json:"name,omitempty"`
+		Scheme string `json:"scheme,omitempty"`
+	}
+
+	// Security schemes names required by an operation, at least one should be satisfied.
+	SecurityRequirement map[string][]string
+
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Nullable             bool               `json:"nullable,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	}
+
+	// Describes an HTTP route to add to a document. Types are given as values and
+	// reflected upon to build the appropriate schemas.
+	Route struct {
+		Method      string
+		Path        string // Path relative to the server url, in the gin format (/apps/:id)
+		ID          string // Unique operation identifier, used by generators to name methods
+		Summary     string
+		Tag         string
+		Security    []SecurityRequirement // Overrides the document security, use an empty slice for public routes
+		Query       any                   // Struct whose fields with a `form` tag are query parameters
+		Body        any                   // Request payload
+		Multipart   bool                  // Wether the body could also be sent as a multipart form
+		Response    any                   // Response payload, nil if the route does not return any content
+		Status      int                   // Success status, default to 200 or 204 if no response is set
+		ContentType string                // Response content type, default to application/json
+	}
+)
+
+// Builds a new empty document.
+func New(title, version string, servers ...string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+
+	for _, url := range servers {
+		d.Servers = append(d.Servers, Server{URL: url})
+	}
+
+	d.generator = newGenerator(d.Components.Schemas)
+
+	return d
+}
+
+// Register a security scheme on the document.
+func (d *Document) SecurityScheme(name string, scheme SecurityScheme) *Document {
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = make(map[string]SecurityScheme)
+	}
+
+	d.Components.SecuritySchemes[name] = scheme
+
+	return d
+}
+
+// Override the schema generated for the type of the given value. Useful for types
+// which implement a custom JSON marshalling.
+func (d *Document) Override(value any, schema Schema) *Document {
+	d.generator.overrides[reflect.TypeOf(value)] = schema
+	return d
+}
+
+// Add the given routes to the document.
+func (d *Document) Add(routes ...Route) *Document {
+	for _, r := range routes {
+		path, params := convertPath(r.Path)
+		op := &Operation{
+			OperationID: r.ID,
+			Summary:     r.Summary,
+			Responses:   make(map[string]Response),
+		}
+
+		if r.Security != nil {
+			security := r.Security
+			op.Security = &security
+		}
+
+		if r.Tag != "" {
+			op.Tags = []string{r.Tag}
+		}
+
+		for _, name := range params {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+
+		if r.Query != nil {
+			op.Parameters = append(op.Parameters, d.generator.parameters(reflect.TypeOf(r.Query))...)
+		}
+
+		if r.Body != nil {
+			schema := d.generator.schema(reflect.TypeOf(r.Body))
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{jsonContentType: {Schema: schema}},
+			}
+
+			if r.Multipart {
+				op.RequestBody.Content[multipartContentType] = MediaType{Schema: schema}
+			}
+		}
+
+		status := r.Status
+
+		if status == 0 {
+			status = http.StatusOK
+
+			if r.Response == nil {
+				status = http.StatusNoContent
+			}
+		}
+
+		response := Response{Description: http.StatusText(status)}
+
+		if r.Response != nil {
+			contentType := r.ContentType
+
+			if contentType == "" {
+				contentType = jsonContentType
+			}
+
+			response.Content = map[string]MediaType{
+				contentType: {Schema: d.generator.schema(reflect.TypeOf(r.Response))},
+			}
+		}
+
+		op.Responses[fmt.Sprint(status)] = response
+
+		item, exists := d.Paths[path]
+
+		if !exists {
+			item = make(PathItem)
+			d.Paths[path] = item
+		}
+
+		item[strings.ToLower(r.Method)] = op
+	}
+
+	return d
+}
+
+// Converts a gin path such as /apps/:id to its OpenAPI representation /apps/{id}
+// and returns the path parameters names.
+func convertPath(path string) (string, []string) {
+	var params []string
+
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+
+		name := segment[1:]
+		params = append(params, name)
+		segments[i] = "{" + name + "}"
+	}
+
+	return strings.Join(segments, "/"), params
+}
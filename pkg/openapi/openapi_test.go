@@ -0,0 +1,146 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/openapi"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type (
+	createCommand struct {
+		bus.Command[string]
+
+		ID     string              `json:"-"`
+		Name   string              `json:"name"`
+		Tags   []string            `json:"tags,omitempty"`
+		Vars   map[string]string   `json:"vars"`
+		Parent monad.Maybe[string] `json:"parent"`
+		Token  monad.Patch[string] `json:"token"`
+	}
+
+	item struct {
+		ID        string                 `json:"id"`
+		Children  []item                 `json:"children"`
+		Secret    storage.SecretString   `json:"secret"`
+		CreatedAt time.Time              `json:"created_at"`
+		DeletedAt monad.Maybe[time.Time] `json:"deleted_at"`
+	}
+
+	filters struct {
+		Page   int    `form:"page"`
+		Search string `form:"search"`
+		Other  string
+	}
+)
+
+func Test_Document(t *testing.T) {
+	t.Run("should convert gin paths and add path parameters", func(t *testing.T) {
+		doc := openapi.New("test", "1").Add(openapi.Route{
+			Method: http.MethodDelete,
+			Path:   "/apps/:id/deployments/:number",
+			ID:     "deleteDeployment",
+		})
+
+		op := doc.Paths["/apps/{id}/deployments/{number}"]["delete"]
+
+		testutil.IsNotNil(t, op)
+		testutil.Equals(t, "deleteDeployment", op.OperationID)
+		testutil.HasLength(t, op.Parameters, 2)
+		testutil.Equals(t, "id", op.Parameters[0].Name)
+		testutil.Equals(t, "path", op.Parameters[0].In)
+		testutil.IsTrue(t, op.Parameters[0].Required)
+		testutil.Equals(t, "number", op.Parameters[1].Name)
+		testutil.Equals(t, "No Content", op.Responses["204"].Description)
+	})
+
+	t.Run("should build query parameters from form tags", func(t *testing.T) {
+		doc := openapi.New("test", "1").Add(openapi.Route{
+			Method: http.MethodGet,
+			Path:   "/items",
+			Query:  filters{},
+		})
+
+		op := doc.Paths["/items"]["get"]
+
+		testutil.HasLength(t, op.Parameters, 2)
+		testutil.Equals(t, "page", op.Parameters[0].Name)
+		testutil.Equals(t, "query", op.Parameters[0].In)
+		testutil.Equals(t, "integer", op.Parameters[0].Schema.Type)
+		testutil.Equals(t, "search", op.Parameters[1].Name)
+	})
+
+	t.Run("should register named structs as components", func(t *testing.T) {
+		doc := openapi.New("test", "1").Add(openapi.Route{
+			Method:   http.MethodPost,
+			Path:     "/items",
+			Body:     createCommand{},
+			Response: item{},
+			Status:   http.StatusCreated,
+		})
+
+		op := doc.Paths["/items"]["post"]
+
+		testutil.Equals(t, "#/components/schemas/openapi_test.createCommand", op.RequestBody.Content["application/json"].Schema.Ref)
+		testutil.Equals(t, "#/components/schemas/openapi_test.item", op.Responses["201"].Content["application/json"].Schema.Ref)
+
+		cmd := doc.Components.Schemas["openapi_test.createCommand"]
+
+		testutil.IsNotNil(t, cmd)
+		testutil.DeepEquals(t, []string{"name", "vars"}, cmd.Required)
+		testutil.Equals(t, 5, len(cmd.Properties))
+		testutil.Equals(t, "array", cmd.Properties["tags"].Type)
+		testutil.Equals(t, "string", cmd.Properties["vars"].AdditionalProperties.Type)
+		testutil.IsTrue(t, cmd.Properties["parent"].Nullable)
+		testutil.Equals(t, "string", cmd.Properties["token"].Type)
+		testutil.IsTrue(t, cmd.Properties["token"].Nullable)
+
+		it := doc.Components.Schemas["openapi_test.item"]
+
+		testutil.Equals(t, "#/components/schemas/openapi_test.item", it.Properties["children"].Items.Ref)
+		testutil.Equals(t, "date-time", it.Properties["created_at"].Format)
+		testutil.Equals(t, "date-time", it.Properties["deleted_at"].Format)
+		testutil.IsTrue(t, it.Properties["deleted_at"].Nullable)
+	})
+
+	t.Run("should inline generic structs", func(t *testing.T) {
+		doc := openapi.New("test", "1").Add(openapi.Route{
+			Method:   http.MethodGet,
+			Path:     "/items",
+			Response: storage.Paginated[item]{},
+		})
+
+		schema := doc.Paths["/items"]["get"].Responses["200"].Content["application/json"].Schema
+
+		testutil.Equals(t, "object", schema.Type)
+		testutil.Equals(t, "#/components/schemas/openapi_test.item", schema.Properties["data"].Items.Ref)
+	})
+
+	t.Run("should use overridden schemas", func(t *testing.T) {
+		doc := openapi.New("test", "1").
+			Override(storage.SecretString(""), openapi.Schema{Type: "string", Format: "password"}).
+			Add(openapi.Route{
+				Method:   http.MethodGet,
+				Path:     "/items/:id",
+				Response: item{},
+			})
+
+		testutil.Equals(t, "password", doc.Components.Schemas["openapi_test.item"].Properties["secret"].Format)
+	})
+
+	t.Run("should allow a route to override the document security", func(t *testing.T) {
+		doc := openapi.New("test", "1").Add(
+			openapi.Route{Method: http.MethodGet, Path: "/public", Security: []openapi.SecurityRequirement{}},
+			openapi.Route{Method: http.MethodGet, Path: "/private"},
+		)
+
+		testutil.IsNotNil(t, doc.Paths["/public"]["get"].Security)
+		testutil.HasLength(t, *doc.Paths["/public"]["get"].Security, 0)
+		testutil.IsTrue(t, doc.Paths["/private"]["get"].Security == nil)
+	})
+}
@@ -0,0 +1,226 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const monadPkgPath = "github.com/YuukanOO/seelf/pkg/monad"
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Reflects on Go types to build their schemas, registering named structs as
+// reusable components.
+type generator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	overrides  map[reflect.Type]Schema
+}
+
+func newGenerator(components map[string]*Schema) *generator {
+	return &generator{
+		components: components,
+		names:      make(map[reflect.Type]string),
+		overrides:  make(map[reflect.Type]Schema),
+	}
+}
+
+// Builds the schema of the given type.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if override, isSet := g.overrides[t]; isSet {
+		return &override
+	}
+
+	if inner, isOptional := unwrapOptional(t); isOptional {
+		s := g.schema(inner)
+
+		// References could not be marked as nullable so keep it as is
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+
+		return s
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		// Custom marshalling, nothing could be said about the resulting representation
+		if t.Implements(marshalerType) {
+			return &Schema{}
+		}
+
+		return g.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// Builds the schema of a struct. Named non generic structs are registered as
+// components and referenced.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" || strings.Contains(t.Name(), "[") {
+		return g.objectSchema(t)
+	}
+
+	name, exists := g.names[t]
+
+	if !exists {
+		name = g.componentName(t)
+		g.names[t] = name
+
+		// Registered before building properties to handle recursive types
+		g.components[name] = &Schema{}
+		*g.components[name] = *g.objectSchema(t)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	g.fields(t, s)
+
+	return s
+}
+
+// Append struct fields to the given object schema, following the encoding/json
+// rules regarding tags and embedded structs.
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && !hasTag {
+			embedded := field.Type
+
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, s)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if name == "" {
+			name, _, _ = strings.Cut(field.Tag.Get("form"), ",")
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = g.schema(field.Type)
+
+		if _, isOptional := unwrapOptional(field.Type); isOptional ||
+			field.Type.Kind() == reflect.Pointer ||
+			strings.Contains(options, "omitempty") {
+			continue
+		}
+
+		s.Required = append(s.Required, name)
+	}
+}
+
+// Builds query parameters from the struct fields with a `form` tag.
+func (g *generator) parameters(t reflect.Type) []Parameter {
+	var params []Parameter
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, g.parameters(field.Type)...)
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		params = append(params, Parameter{
+			Name:   name,
+			In:     "query",
+			Schema: g.schema(field.Type),
+		})
+	}
+
+	return params
+}
+
+// Component name of a type, prefixed by its package name to avoid conflicts.
+func (g *generator) componentName(t reflect.Type) string {
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	candidate := name
+
+	for i := 2; g.components[candidate] != nil; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+
+	return candidate
+}
+
+// Returns the inner type of monad.Maybe and monad.Patch types.
+func unwrapOptional(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || t.PkgPath() != monadPkgPath {
+		return t, false
+	}
+
+	switch {
+	case strings.HasPrefix(t.Name(), "Patch["):
+		inner, _ := unwrapOptional(t.Field(0).Type)
+		return inner, true
+	case strings.HasPrefix(t.Name(), "Maybe["):
+		value, _ := t.FieldByName("value")
+		return value.Type, true
+	default:
+		return t, false
+	}
+}
@@ -1,6 +1,10 @@
 package ostools
 
-import "os"
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
 
 // Tiny wrapper around the default os.MkdirAll but apply standard permissions.
 func MkdirAll(path string) error {
@@ -15,3 +19,35 @@ func EmptyDir(path string) error {
 
 	return MkdirAll(path)
 }
+
+// Computes the size of every regular file under the given path. A path which
+// does not exist has a size of 0.
+func Size(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
@@ -1,6 +1,8 @@
 package ostools
 
 import (
+	"compress/gzip"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -50,3 +52,100 @@ func RemovePattern(pattern string) error {
 
 	return nil
 }
+
+// Compress the src file into dst and removes src once done. The dst file is
+// written atomically so readers never see a partial archive.
+func Gzip(src, dst string) error {
+	return transform(src, dst, func(w io.Writer, r io.Reader) error {
+		gzw := gzip.NewWriter(w)
+
+		if _, err := io.Copy(gzw, r); err != nil {
+			return err
+		}
+
+		return gzw.Close()
+	})
+}
+
+// Decompress the src gzip file into dst and removes src once done.
+func Gunzip(src, dst string) error {
+	return transform(src, dst, func(w io.Writer, r io.Reader) error {
+		gzr, err := gzip.NewReader(r)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(w, gzr)
+
+		return err
+	})
+}
+
+// Open a gzip file to read its decompressed content.
+func OpenGzip(name string) (io.ReadCloser, error) {
+	file, err := os.Open(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gzr, err := gzip.NewReader(file)
+
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &gzipReadCloser{gzr, file}, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Write the src file content into dst using the given function, then removes src.
+func transform(src, dst string, fn func(io.Writer, io.Reader) error) error {
+	in, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, defaultPermissions)
+
+	if err != nil {
+		return err
+	}
+
+	err = fn(out, in)
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,99 @@
+// Package realtime broadcasts events to connected clients so they do not have to poll
+// the server to know something has changed.
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Number of events a subscriber could lag behind before being dropped.
+const subscriberBufferSize = 64
+
+type (
+	// Event sent to subscribers.
+	Event struct {
+		Type string `json:"type"`
+		Data any    `json:"data"`
+	}
+
+	// Determines if the subscriber identified by the given context is allowed to
+	// receive an event.
+	Filter func(context.Context) bool
+
+	// Publish events to subscribers.
+	Publisher interface {
+		Publish(Event, Filter)
+	}
+
+	// In-memory hub dispatching published events to its subscribers.
+	Hub struct {
+		mu          sync.Mutex
+		subscribers map[*subscriber]struct{}
+	}
+
+	subscriber struct {
+		ctx    context.Context
+		events chan Event
+	}
+)
+
+// Builds a new empty hub.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe to events published on the hub. The given context is used to filter
+// events and the subscription ends when it is done. The returned channel is closed
+// when the subscription ends or if the subscriber could not keep up.
+func (h *Hub) Subscribe(ctx context.Context) <-chan Event {
+	sub := &subscriber{
+		ctx:    ctx,
+		events: make(chan Event, subscriberBufferSize),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.remove(sub)
+	}()
+
+	return sub.events
+}
+
+// Publish the given event to every subscriber allowed by the filter, never blocking
+// the publisher. A nil filter sends the event to everyone.
+func (h *Hub) Publish(evt Event, filter Filter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if sub.ctx.Err() != nil || (filter != nil && !filter(sub.ctx)) {
+			continue
+		}
+
+		select {
+		case sub.events <- evt:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (h *Hub) remove(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.subscribers[sub]; !exists {
+		return
+	}
+
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
@@ -0,0 +1,78 @@
+package realtime_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/realtime"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type contextKey string
+
+const allowedKey contextKey = "allowed"
+
+func Test_Hub(t *testing.T) {
+	t.Run("should send published events to subscribers", func(t *testing.T) {
+		hub := realtime.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := hub.Subscribe(ctx)
+
+		hub.Publish(realtime.Event{Type: "something_happened", Data: 42}, nil)
+
+		evt := <-events
+		testutil.Equals(t, "something_happened", evt.Type)
+		testutil.Equals[any](t, 42, evt.Data)
+	})
+
+	t.Run("should only send events to subscribers allowed by the filter", func(t *testing.T) {
+		hub := realtime.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		allowed := hub.Subscribe(context.WithValue(ctx, allowedKey, true))
+		denied := hub.Subscribe(ctx)
+
+		hub.Publish(realtime.Event{Type: "secret"}, func(ctx context.Context) bool {
+			return ctx.Value(allowedKey) == true
+		})
+		hub.Publish(realtime.Event{Type: "public"}, nil)
+
+		testutil.Equals(t, "secret", (<-allowed).Type)
+		testutil.Equals(t, "public", (<-allowed).Type)
+		testutil.Equals(t, "public", (<-denied).Type)
+	})
+
+	t.Run("should close the subscription when the context is done", func(t *testing.T) {
+		hub := realtime.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		events := hub.Subscribe(ctx)
+		cancel()
+
+		_, open := <-events
+		testutil.IsFalse(t, open)
+	})
+
+	t.Run("should drop subscribers which could not keep up", func(t *testing.T) {
+		hub := realtime.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := hub.Subscribe(ctx)
+
+		for i := 0; i <= 64; i++ {
+			hub.Publish(realtime.Event{Type: "flood"}, nil)
+		}
+
+		count := 0
+
+		for range events {
+			count++
+		}
+
+		testutil.Equals(t, 64, count)
+	})
+}
@@ -0,0 +1,232 @@
+// Package s3 stores objects in an S3 compatible bucket (AWS, MinIO, Garage, ...).
+// Only the few operations needed by seelf are implemented.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	service         = "s3"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	defaultRegion   = "us-east-1"
+)
+
+var (
+	ErrNotFound         = errors.New("object_not_found")
+	ErrUnexpectedStatus = errors.New("unexpected_status")
+)
+
+type (
+	// Options needed to reach a bucket.
+	Options struct {
+		Endpoint  string // Such as https://s3.eu-west-3.amazonaws.com or http://localhost:9000
+		Region    string // Defaults to us-east-1, which is what most S3 compatible servers expect
+		Bucket    string
+		AccessKey string
+		SecretKey string
+		PathStyle bool // Use <endpoint>/<bucket>/<key> urls instead of <bucket>.<endpoint>/<key>, needed by most self-hosted servers
+	}
+
+	// Client used to manage objects in a single bucket.
+	Client struct {
+		options  Options
+		endpoint *url.URL
+		client   *http.Client
+		signer   *v4.Signer
+	}
+
+	listBucketResult struct {
+		Contents []struct {
+			Key string `xml:"Key"`
+		} `xml:"Contents"`
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+	}
+)
+
+// Builds a new client for the bucket described by the given options.
+func New(options Options) (*Client, error) {
+	endpoint, err := url.Parse(options.Endpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Region == "" {
+		options.Region = defaultRegion
+	}
+
+	return &Client{
+		options:  options,
+		endpoint: endpoint,
+		client:   &http.Client{},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			o.DisableURIPathEscaping = true // S3 keys are already escaped by url.URL
+		}),
+	}, nil
+}
+
+// Stores the content of the given reader under the given key. Size must be the exact
+// number of bytes which will be read.
+func (c *Client) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	resp, err := c.do(ctx, http.MethodPut, key, nil, body, size)
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Retrieve the object stored under the given key. The caller must close it.
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Removes the object stored under the given key, succeeds if it does not exist.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, 0)
+
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Lists keys of every object starting with the given prefix.
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	var (
+		keys  []string
+		query = url.Values{
+			"list-type": {"2"},
+			"prefix":    {prefix},
+		}
+	)
+
+	for {
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, 0)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+
+		if !result.IsTruncated {
+			return keys, nil
+		}
+
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// Removes every object starting with the given prefix.
+func (c *Client) DeletePrefix(ctx context.Context, prefix string) error {
+	keys, err := c.List(ctx, prefix)
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err = c.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sends a signed request for the given key and returns the response if successful.
+func (c *Client) do(
+	ctx context.Context,
+	method, key string,
+	query url.Values,
+	body io.Reader,
+	size int64,
+) (*http.Response, error) {
+	u := *c.endpoint
+	path := "/" + key
+
+	if c.options.PathStyle {
+		path = "/" + c.options.Bucket + path
+	} else {
+		u.Host = c.options.Bucket + "." + u.Host
+	}
+
+	u.Path = strings.TrimSuffix(c.endpoint.Path, "/") + path
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20") // Signature V4 expects spaces as %20
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = size
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	if err = c.signer.SignHTTP(ctx, aws.Credentials{
+		AccessKeyID:     c.options.AccessKey,
+		SecretAccessKey: c.options.SecretKey,
+	}, req, unsignedPayload, service, c.options.Region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, detail)
+}
@@ -0,0 +1,148 @@
+package s3_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/s3"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Client(t *testing.T) {
+	arrange := func(t *testing.T) *s3.Client {
+		srv := httptest.NewServer(newFakeBucket(t, "artifacts"))
+		t.Cleanup(srv.Close)
+
+		client, err := s3.New(s3.Options{
+			Endpoint:  srv.URL,
+			Bucket:    "artifacts",
+			AccessKey: "access",
+			SecretKey: "secret",
+			PathStyle: true,
+		})
+
+		testutil.IsNil(t, err)
+
+		return client
+	}
+
+	t.Run("should put and get an object", func(t *testing.T) {
+		client := arrange(t)
+		content := "some logs"
+
+		testutil.IsNil(t, client.Put(context.Background(), "logs/one.log", strings.NewReader(content), int64(len(content))))
+
+		r, err := client.Get(context.Background(), "logs/one.log")
+		testutil.IsNil(t, err)
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		testutil.IsNil(t, err)
+		testutil.Equals(t, content, string(data))
+	})
+
+	t.Run("should return ErrNotFound if the object does not exist", func(t *testing.T) {
+		client := arrange(t)
+
+		_, err := client.Get(context.Background(), "logs/missing.log")
+
+		testutil.ErrorIs(t, s3.ErrNotFound, err)
+	})
+
+	t.Run("should delete every object starting with a prefix", func(t *testing.T) {
+		client := arrange(t)
+
+		for _, key := range []string{"apps/one/a", "apps/one/b", "apps/two/a"} {
+			testutil.IsNil(t, client.Put(context.Background(), key, strings.NewReader("data"), 4))
+		}
+
+		testutil.IsNil(t, client.DeletePrefix(context.Background(), "apps/one/"))
+
+		keys, err := client.List(context.Background(), "apps/")
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{"apps/two/a"}, keys)
+	})
+}
+
+type fakeBucket struct {
+	t       *testing.T
+	name    string
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+// Minimal path-style S3 server keeping objects in memory. Listing returns one key per
+// page to exercise the pagination.
+func newFakeBucket(t *testing.T, name string) *fakeBucket {
+	return &fakeBucket{t: t, name: name, objects: make(map[string]string)}
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+b.name)
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	key = strings.TrimPrefix(key, "/")
+
+	switch {
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		b.objects[key] = string(data)
+	case r.Method == http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && key == "":
+		b.list(w, r)
+	case r.Method == http.MethodGet:
+		data, found := b.objects[key]
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(data))
+	}
+}
+
+func (b *fakeBucket) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		keys  []string
+		query = r.URL.Query()
+		after = query.Get("continuation-token")
+	)
+
+	for key := range b.objects {
+		if strings.HasPrefix(key, query.Get("prefix")) && key > after {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		return
+	}
+
+	fmt.Fprintf(w, `<ListBucketResult><Contents><Key>%s</Key></Contents><IsTruncated>%t</IsTruncated><NextContinuationToken>%s</NextContinuationToken></ListBucketResult>`,
+		keys[0], len(keys) > 1, keys[0])
+}
@@ -0,0 +1,338 @@
+// Package selfupdate replaces the running executable by the latest released one, making
+// sure it has been signed by the expected key and keeping the previous one around so it
+// could be restored if the new version does not start.
+//
+// Releases are described by a manifest giving their version, platform and the checksum
+// of the binary. Only the manifest is signed so a release could not be given for another
+// platform or replayed to downgrade an instance.
+package selfupdate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoPublicKey             = errors.New("no_public_key")
+	ErrAssetNotFound           = errors.New("release_asset_not_found")
+	ErrInvalidSignature        = errors.New("invalid_signature")
+	ErrInvalidManifest         = errors.New("invalid_manifest")
+	ErrChecksumMismatch        = errors.New("checksum_mismatch")
+	ErrNotNewer                = errors.New("release_not_newer")
+	ErrNoPreviousVersion       = errors.New("no_previous_version")
+	ErrUnexpectedStatus        = errors.New("unexpected_status")
+	defaultTimeout             = 5 * time.Minute
+	maxSignatureSize     int64 = 1024
+	maxManifestSize      int64 = 4096
+)
+
+const (
+	previousSuffix = ".previous" // Suffix of the executable kept to rollback an update
+	pendingSuffix  = ".pending"  // Suffix of the marker file created until the new version has started
+	newSuffix      = ".new"      // Suffix of the temporary file the new version is written to
+	manifestExt    = ".json"
+	signatureExt   = ".sig"
+)
+
+type (
+	// Released version of the application.
+	Release struct {
+		Version      string
+		BinaryURL    string
+		ManifestURL  string
+		SignatureURL string
+	}
+
+	// Signed description of a released binary.
+	Manifest struct {
+		Version  string `json:"version"`
+		Platform string `json:"platform"` // <os>_<arch> the binary has been built for
+		Sha256   string `json:"sha256"`   // Hex encoded checksum of the binary
+	}
+
+	// Updates the given executable with releases found at the given url.
+	Updater struct {
+		client      *http.Client
+		releasesURL string
+		assetName   string
+		platform    string
+		publicKey   ed25519.PublicKey
+		executable  string
+		current     string
+	}
+
+	// Release as returned by the GitHub API.
+	githubRelease struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+)
+
+// Builds a new updater for the given executable running the current version. The
+// releasesURL should point to a GitHub like API endpoint returning the latest release.
+// Release assets are expected to be named <name>_<os>_<arch> along with a JSON Manifest
+// named <name>_<os>_<arch>.json and a <name>_<os>_<arch>.sig file containing the raw
+// ed25519 signature of the manifest.
+func New(releasesURL, name string, publicKey ed25519.PublicKey, executable, current string) *Updater {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	return &Updater{
+		client:      &http.Client{Timeout: defaultTimeout},
+		releasesURL: releasesURL,
+		assetName:   name + "_" + platform,
+		platform:    platform,
+		publicKey:   publicKey,
+		executable:  executable,
+		current:     current,
+	}
+}
+
+// Retrieve the latest release available for the current platform.
+func (u *Updater) Latest(ctx context.Context) (Release, error) {
+	var (
+		release githubRelease
+		result  Release
+	)
+
+	body, err := u.get(ctx, u.releasesURL)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer body.Close()
+
+	if err = json.NewDecoder(body).Decode(&release); err != nil {
+		return result, err
+	}
+
+	result.Version = release.TagName
+
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case u.assetName:
+			result.BinaryURL = asset.URL
+		case u.assetName + manifestExt:
+			result.ManifestURL = asset.URL
+		case u.assetName + signatureExt:
+			result.SignatureURL = asset.URL
+		}
+	}
+
+	if result.BinaryURL == "" || result.ManifestURL == "" || result.SignatureURL == "" {
+		return result, ErrAssetNotFound
+	}
+
+	return result, nil
+}
+
+// Download the given release, verify its manifest and replace the executable with it.
+// The previous executable is kept until Confirm or Rollback is called.
+func (u *Updater) Install(ctx context.Context, release Release) error {
+	manifest, err := u.verifiedManifest(ctx, release)
+
+	if err != nil {
+		return err
+	}
+
+	binary, err := u.download(ctx, release.BinaryURL, -1)
+
+	if err != nil {
+		return err
+	}
+
+	if sum := sha256.Sum256(binary); hex.EncodeToString(sum[:]) != strings.ToLower(manifest.Sha256) {
+		return ErrChecksumMismatch
+	}
+
+	info, err := os.Stat(u.executable)
+
+	if err != nil {
+		return err
+	}
+
+	// Written next to the executable so the final rename stays on the same filesystem
+	if err = os.WriteFile(u.executable+newSuffix, binary, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if err = os.Rename(u.executable, u.executable+previousSuffix); err != nil {
+		_ = os.Remove(u.executable + newSuffix)
+		return err
+	}
+
+	if err = os.Rename(u.executable+newSuffix, u.executable); err != nil {
+		_ = os.Rename(u.executable+previousSuffix, u.executable)
+		return err
+	}
+
+	return os.WriteFile(u.executable+pendingSuffix, []byte(manifest.Version), 0644)
+}
+
+// Download the manifest of the given release and make sure it has been signed by the
+// expected key, targets the current platform and describes a version newer than the
+// current one.
+func (u *Updater) verifiedManifest(ctx context.Context, release Release) (Manifest, error) {
+	var manifest Manifest
+
+	if len(u.publicKey) != ed25519.PublicKeySize {
+		return manifest, ErrNoPublicKey
+	}
+
+	signature, err := u.download(ctx, release.SignatureURL, maxSignatureSize)
+
+	if err != nil {
+		return manifest, err
+	}
+
+	data, err := u.download(ctx, release.ManifestURL, maxManifestSize)
+
+	if err != nil {
+		return manifest, err
+	}
+
+	if !ed25519.Verify(u.publicKey, data, signature) {
+		return manifest, ErrInvalidSignature
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(&manifest); err != nil ||
+		manifest.Platform != u.platform ||
+		manifest.Version != release.Version ||
+		manifest.Sha256 == "" {
+		return manifest, ErrInvalidManifest
+	}
+
+	if !IsNewer(manifest.Version, u.current) {
+		return manifest, ErrNotNewer
+	}
+
+	return manifest, nil
+}
+
+// Returns true if a new version has been installed but has not successfully started yet.
+func (u *Updater) Pending() bool {
+	_, err := os.Stat(u.executable + pendingSuffix)
+	return err == nil
+}
+
+// Mark the installed version as working.
+func (u *Updater) Confirm() error {
+	if err := os.Remove(u.executable + pendingSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Restore the executable replaced by the last installation.
+func (u *Updater) Rollback() error {
+	if _, err := os.Stat(u.executable + previousSuffix); os.IsNotExist(err) {
+		return ErrNoPreviousVersion
+	}
+
+	if err := os.Rename(u.executable+previousSuffix, u.executable); err != nil {
+		return err
+	}
+
+	return u.Confirm()
+}
+
+func (u *Updater) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w %d from %s", ErrUnexpectedStatus, resp.StatusCode, url)
+	}
+
+	return resp.Body, nil
+}
+
+// Download the file at the given url, reading at most maxSize bytes if positive.
+func (u *Updater) download(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	body, err := u.get(ctx, url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	var reader io.Reader = body
+
+	if maxSize > 0 {
+		reader = io.LimitReader(body, maxSize)
+	}
+
+	return io.ReadAll(reader)
+}
+
+// Returns true if the candidate version is greater than the current one. Versions are
+// compared on their major, minor and patch numbers, a leading "v" and any suffix starting
+// with "-" or "+" are ignored.
+func IsNewer(candidate, current string) bool {
+	a, b := parseVersion(candidate), parseVersion(current)
+
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+
+	return false
+}
+
+// Resolves the path of the running executable, following symlinks.
+func Executable() (string, error) {
+	exe, err := os.Executable()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(exe)
+}
+
+func parseVersion(version string) (result [3]int) {
+	version = strings.TrimPrefix(version, "v")
+
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+
+	for i, part := range strings.SplitN(version, ".", len(result)) {
+		result[i], _ = strconv.Atoi(part)
+	}
+
+	return result
+}
@@ -0,0 +1,176 @@
+package selfupdate_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/selfupdate"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Updater(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	testutil.IsNil(t, err)
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	asset := "seelf_" + platform
+	newBinary := []byte("new version")
+
+	manifestOf := func(version, platform string, binary []byte) []byte {
+		sum := sha256.Sum256(binary)
+
+		return must.Panic(json.Marshal(selfupdate.Manifest{
+			Version:  version,
+			Platform: platform,
+			Sha256:   hex.EncodeToString(sum[:]),
+		}))
+	}
+
+	validManifest := manifestOf("v2.4.0", platform, newBinary)
+
+	arrange := func(t *testing.T, current string, manifest, signature []byte) (*selfupdate.Updater, string) {
+		mux := http.NewServeMux()
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+
+		mux.HandleFunc("/latest", func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"tag_name": "v2.4.0",
+				"assets": []map[string]string{
+					{"name": "seelf_unknown_arch", "browser_download_url": srv.URL + "/unknown"},
+					{"name": asset, "browser_download_url": srv.URL + "/binary"},
+					{"name": asset + ".json", "browser_download_url": srv.URL + "/manifest"},
+					{"name": asset + ".sig", "browser_download_url": srv.URL + "/signature"},
+				},
+			})
+		})
+		mux.HandleFunc("/binary", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(newBinary) })
+		mux.HandleFunc("/manifest", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(manifest) })
+		mux.HandleFunc("/signature", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(signature) })
+
+		exe := filepath.Join(t.TempDir(), "seelf")
+		testutil.IsNil(t, os.WriteFile(exe, []byte("old version"), 0755))
+
+		return selfupdate.New(srv.URL+"/latest", "seelf", publicKey, exe, current), exe
+	}
+
+	install := func(t *testing.T, updater *selfupdate.Updater) error {
+		release, err := updater.Latest(context.Background())
+		testutil.IsNil(t, err)
+
+		return updater.Install(context.Background(), release)
+	}
+
+	t.Run("should retrieve the latest release for the current platform", func(t *testing.T) {
+		updater, _ := arrange(t, "2.3.0", nil, nil)
+
+		release, err := updater.Latest(context.Background())
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, "v2.4.0", release.Version)
+		testutil.Contains(t, "/binary", release.BinaryURL)
+		testutil.Contains(t, "/manifest", release.ManifestURL)
+		testutil.Contains(t, "/signature", release.SignatureURL)
+	})
+
+	t.Run("should refuse a release with an invalid signature", func(t *testing.T) {
+		updater, exe := arrange(t, "2.3.0", validManifest, ed25519.Sign(privateKey, []byte("something else")))
+
+		testutil.ErrorIs(t, selfupdate.ErrInvalidSignature, install(t, updater))
+		testutil.IsFalse(t, updater.Pending())
+		testutil.DeepEquals(t, []byte("old version"), must.Panic(os.ReadFile(exe)))
+	})
+
+	t.Run("should refuse a release whose manifest targets another platform", func(t *testing.T) {
+		manifest := manifestOf("v2.4.0", "plan9_mips", newBinary)
+		updater, exe := arrange(t, "2.3.0", manifest, ed25519.Sign(privateKey, manifest))
+
+		testutil.ErrorIs(t, selfupdate.ErrInvalidManifest, install(t, updater))
+		testutil.DeepEquals(t, []byte("old version"), must.Panic(os.ReadFile(exe)))
+	})
+
+	t.Run("should refuse a release whose manifest does not match the release version", func(t *testing.T) {
+		manifest := manifestOf("v2.5.0", platform, newBinary)
+		updater, exe := arrange(t, "2.3.0", manifest, ed25519.Sign(privateKey, manifest))
+
+		testutil.ErrorIs(t, selfupdate.ErrInvalidManifest, install(t, updater))
+		testutil.DeepEquals(t, []byte("old version"), must.Panic(os.ReadFile(exe)))
+	})
+
+	t.Run("should refuse a binary not matching the manifest checksum", func(t *testing.T) {
+		manifest := manifestOf("v2.4.0", platform, []byte("another binary"))
+		updater, exe := arrange(t, "2.3.0", manifest, ed25519.Sign(privateKey, manifest))
+
+		testutil.ErrorIs(t, selfupdate.ErrChecksumMismatch, install(t, updater))
+		testutil.DeepEquals(t, []byte("old version"), must.Panic(os.ReadFile(exe)))
+	})
+
+	t.Run("should refuse a release not newer than the current version", func(t *testing.T) {
+		manifest := manifestOf("v2.4.0", platform, newBinary)
+		updater, _ := arrange(t, "2.4.0", manifest, ed25519.Sign(privateKey, manifest))
+
+		testutil.ErrorIs(t, selfupdate.ErrNotNewer, install(t, updater))
+	})
+
+	t.Run("should refuse to install without a public key", func(t *testing.T) {
+		updater := selfupdate.New("", "seelf", nil, filepath.Join(t.TempDir(), "seelf"), "2.3.0")
+
+		testutil.ErrorIs(t, selfupdate.ErrNoPublicKey, updater.Install(context.Background(), selfupdate.Release{}))
+	})
+
+	t.Run("should install a signed release and confirm it", func(t *testing.T) {
+		updater, exe := arrange(t, "2.3.0", validManifest, ed25519.Sign(privateKey, validManifest))
+
+		testutil.IsNil(t, install(t, updater))
+		testutil.IsTrue(t, updater.Pending())
+		testutil.DeepEquals(t, newBinary, must.Panic(os.ReadFile(exe)))
+
+		testutil.IsNil(t, updater.Confirm())
+		testutil.IsFalse(t, updater.Pending())
+	})
+
+	t.Run("should rollback to the previous executable", func(t *testing.T) {
+		updater, exe := arrange(t, "2.3.0", validManifest, ed25519.Sign(privateKey, validManifest))
+
+		testutil.ErrorIs(t, selfupdate.ErrNoPreviousVersion, updater.Rollback())
+
+		testutil.IsNil(t, install(t, updater))
+
+		testutil.IsNil(t, updater.Rollback())
+		testutil.IsFalse(t, updater.Pending())
+		testutil.DeepEquals(t, []byte("old version"), must.Panic(os.ReadFile(exe)))
+	})
+}
+
+func Test_IsNewer(t *testing.T) {
+	tests := []struct {
+		candidate string
+		current   string
+		expected  bool
+	}{
+		{"v2.4.0", "2.3.2", true},
+		{"v2.3.2", "2.3.2-abc1234", false},
+		{"v2.3.10", "2.3.9", true},
+		{"v3.0.0", "2.99.99", true},
+		{"v2.3.1", "2.3.2", false},
+		{"v2.4.0", "dev", true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s > %s", test.candidate, test.current), func(t *testing.T) {
+			testutil.Equals(t, test.expected, selfupdate.IsNewer(test.candidate, test.current))
+		})
+	}
+}
@@ -5,18 +5,24 @@ import (
 	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/ostools"
 	"github.com/kevinburke/ssh_config"
 )
 
+const (
+	defaultPort   = 22
+	bastionSuffix = "_bastion" // Suffix of the identifier of bastion entries
+)
+
 type (
 	// Represents a configurator used to manipulate an ssh config file and wrap
 	// common stuff to make working with ssh easier.
 	Configurator interface {
-		Upsert(conn Connection) error   // Ensure the given connection is present in the config and write private key if given.
-		Remove(identifier string) error // Remove an entry identified with the given value. It will also remove the private key referenced if found.
+		Upsert(conn Connection) error   // Ensure the given connection is present in the config and write private keys and pinned host keys if given.
+		Remove(identifier string) error // Remove an entry identified with the given value. It will also remove the private keys and pinned host keys referenced if found.
 	}
 
 	fileConfigurator struct {
@@ -31,6 +37,18 @@ type (
 		User       monad.Maybe[string]
 		Port       monad.Maybe[int]
 		PrivateKey monad.Maybe[ConnectionKey]
+		KeepAlive  monad.Maybe[KeepAlive] // If set, the connection will be kept alive and shared, see Pool
+		HostKey    monad.Maybe[HostKey]   // If set, only this host key will be trusted
+		Bastion    monad.Maybe[Bastion]   // Jump host used to reach the host if it lives in a private network
+	}
+
+	// Jump host, with its own credentials, through which a connection is made.
+	Bastion struct {
+		Host       Host
+		User       monad.Maybe[string]
+		Port       monad.Maybe[int]
+		PrivateKey monad.Maybe[ConnectionKey]
+		HostKey    monad.Maybe[HostKey]
 	}
 
 	ConnectionKey struct {
@@ -74,9 +92,14 @@ func (c *fileConfigurator) Upsert(conn Connection) error {
 		sshHost        *ssh_config.Host
 		hostname       = conn.Host.String()
 		hasIdentifier  = conn.Identifier != ""
-		oldPrivKeyPath string // old private key path if the node exists
+		name           = conn.Identifier // name used by the files related to this connection
+		oldPrivKeyPath string            // old private key path if the node exists
 	)
 
+	if !hasIdentifier {
+		name = hostname
+	}
+
 	// Try to retrieve an already existing host
 	for _, host := range sshConfig.Hosts {
 		if host.IsImplicit() ||
@@ -133,6 +156,40 @@ func (c *fileConfigurator) Upsert(conn Connection) error {
 		})
 	}
 
+	if keepAlive, isSet := conn.KeepAlive.TryGet(); isSet {
+		if keepAlive.Interval > 0 {
+			sshHost.Nodes = append(sshHost.Nodes,
+				&ssh_config.KV{
+					Key:   "ServerAliveInterval",
+					Value: seconds(keepAlive.Interval),
+				}, &ssh_config.KV{
+					Key:   "ServerAliveCountMax",
+					Value: strconv.Itoa(keepAlive.CountMax),
+				})
+		}
+
+		if keepAlive.Enabled() {
+			sshHost.Nodes = append(sshHost.Nodes,
+				&ssh_config.KV{
+					Key:   "ControlMaster",
+					Value: "auto",
+				}, &ssh_config.KV{
+					Key:   "ControlPath",
+					Value: filepath.Join(c.dir, "seelf-%C"), // Hash of the connection to keep the socket path short
+				}, &ssh_config.KV{
+					Key:   "ControlPersist",
+					Value: seconds(keepAlive.Persist),
+				})
+		}
+	}
+
+	if _, hasBastion := conn.Bastion.TryGet(); hasBastion {
+		sshHost.Nodes = append(sshHost.Nodes, &ssh_config.KV{
+			Key:   "ProxyJump",
+			Value: bastionAlias(name),
+		})
+	}
+
 	// Remove the old private key if it was set
 	if err = os.RemoveAll(oldPrivKeyPath); err != nil {
 		return err
@@ -155,6 +212,14 @@ func (c *fileConfigurator) Upsert(conn Connection) error {
 			})
 	}
 
+	if err = c.pinHostKey(sshHost, name, conn.Host, conn.Port, conn.HostKey); err != nil {
+		return err
+	}
+
+	if err = c.upsertBastion(sshConfig, name, conn.Bastion); err != nil {
+		return err
+	}
+
 	return ostools.WriteFile(c.path, []byte(sshConfig.String()), 0644)
 }
 
@@ -179,25 +244,186 @@ func (c *fileConfigurator) Remove(identifier string) error {
 		return err
 	}
 
-	// Remove the line matching the given identifier
-	for i, host := range sshConfig.Hosts {
-		if host.IsImplicit() || host.EOLComment != identifier {
+	// Remove the lines matching the given identifier and its bastion if any
+	for _, comment := range []string{identifier, identifier + bastionSuffix} {
+		if err = removeHost(sshConfig, comment); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{c.knownHostsPath(identifier), c.knownHostsPath(identifier + bastionSuffix)} {
+		if err = os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	return ostools.WriteFile(c.path, []byte(sshConfig.String()), 0644)
+}
+
+// Writes or removes the entry of the bastion used to reach the connection with the
+// given name. The bastion is given an alias so it could be referenced by a ProxyJump.
+func (c *fileConfigurator) upsertBastion(sshConfig *ssh_config.Config, name string, bastion monad.Maybe[Bastion]) error {
+	comment := name + bastionSuffix
+	b, hasBastion := bastion.TryGet()
+
+	if !hasBastion {
+		if err := removeHost(sshConfig, comment); err != nil {
+			return err
+		}
+
+		return os.RemoveAll(c.knownHostsPath(comment))
+	}
+
+	var sshHost *ssh_config.Host
+
+	for _, host := range sshConfig.Hosts {
+		if !host.IsImplicit() && host.EOLComment == comment {
+			sshHost = host
+			break
+		}
+	}
+
+	if sshHost == nil {
+		sshHost = &ssh_config.Host{}
+		sshConfig.Hosts = append(sshConfig.Hosts, sshHost)
+	} else if err := removeIdentityFile(sshHost); err != nil {
+		return err
+	}
+
+	pattern, err := ssh_config.NewPattern(bastionAlias(name))
+
+	if err != nil {
+		return err
+	}
+
+	sshHost.Patterns = []*ssh_config.Pattern{pattern}
+	sshHost.EOLComment = comment
+	sshHost.Nodes = []ssh_config.Node{
+		&ssh_config.KV{
+			Key:   "StrictHostKeyChecking",
+			Value: "accept-new",
+		},
+		&ssh_config.KV{
+			Key:   "HostName",
+			Value: b.Host.String(),
+		},
+	}
+
+	if user, isSet := b.User.TryGet(); isSet {
+		sshHost.Nodes = append(sshHost.Nodes, &ssh_config.KV{
+			Key:   "User",
+			Value: user,
+		})
+	}
+
+	if port, isSet := b.Port.TryGet(); isSet {
+		sshHost.Nodes = append(sshHost.Nodes, &ssh_config.KV{
+			Key:   "Port",
+			Value: strconv.Itoa(port),
+		})
+	}
+
+	if privKey, hasPrivKey := b.PrivateKey.TryGet(); hasPrivKey {
+		privateKeyPath := filepath.Join(c.dir, privKey.Name)
+
+		if err := ostools.WriteFile(privateKeyPath, []byte(privKey.Key), 0600); err != nil {
+			return err
+		}
+
+		sshHost.Nodes = append(sshHost.Nodes,
+			&ssh_config.KV{
+				Key:   "IdentityFile",
+				Value: privateKeyPath,
+			}, &ssh_config.KV{
+				Key:   "IdentitiesOnly",
+				Value: "yes",
+			})
+	}
+
+	return c.pinHostKey(sshHost, comment, b.Host, b.Port, b.HostKey)
+}
+
+// Writes the known_hosts file of the given host entry so only the given host key is
+// trusted. Without a host key, new hosts are still accepted on first connection.
+func (c *fileConfigurator) pinHostKey(
+	sshHost *ssh_config.Host,
+	name string,
+	host Host,
+	port monad.Maybe[int],
+	hostKey monad.Maybe[HostKey],
+) error {
+	path := c.knownHostsPath(name)
+
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+
+	key, isPinned := hostKey.TryGet()
+
+	if !isPinned {
+		return nil
+	}
+
+	line, err := key.knownHostsLine(host, port.Get(defaultPort))
+
+	if err != nil {
+		return err
+	}
+
+	if err = ostools.WriteFile(path, []byte(line+"\n"), 0600); err != nil {
+		return err
+	}
+
+	// The first node is always the StrictHostKeyChecking one
+	sshHost.Nodes[0].(*ssh_config.KV).Value = "yes"
+	sshHost.Nodes = append(sshHost.Nodes, &ssh_config.KV{
+		Key:   "UserKnownHostsFile",
+		Value: path,
+	})
+
+	return nil
+}
+
+func (c *fileConfigurator) knownHostsPath(name string) string {
+	return filepath.Join(c.dir, name+"_known_hosts")
+}
+
+// Removes hosts with the given comment and their private key from the file system if any.
+func removeHost(sshConfig *ssh_config.Config, comment string) error {
+	hosts := make([]*ssh_config.Host, 0, len(sshConfig.Hosts))
+
+	for _, host := range sshConfig.Hosts {
+		if host.IsImplicit() || host.EOLComment != comment {
+			hosts = append(hosts, host)
 			continue
 		}
 
-		// Remove the private key from the file system if any
-		for _, node := range host.Nodes {
-			if kv, isKV := node.(*ssh_config.KV); isKV && kv.Key == "IdentityFile" {
-				if err = os.RemoveAll(kv.Value); err != nil {
-					return err
-				}
-			}
+		if err := removeIdentityFile(host); err != nil {
+			return err
 		}
+	}
 
-		// Remove the host from sshConfig.Hosts
-		sshConfig.Hosts = append(sshConfig.Hosts[:i], sshConfig.Hosts[i+1:]...)
-		break
+	sshConfig.Hosts = hosts
+
+	return nil
+}
+
+func removeIdentityFile(host *ssh_config.Host) error {
+	for _, node := range host.Nodes {
+		if kv, isKV := node.(*ssh_config.KV); isKV && kv.Key == "IdentityFile" {
+			if err := os.RemoveAll(kv.Value); err != nil {
+				return err
+			}
+		}
 	}
 
-	return ostools.WriteFile(c.path, []byte(sshConfig.String()), 0644)
+	return nil
+}
+
+func bastionAlias(name string) string {
+	return "seelf-bastion-" + name
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(d.Seconds())))
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/id"
 	"github.com/YuukanOO/seelf/pkg/monad"
@@ -14,6 +15,8 @@ import (
 )
 
 func Test_FileConfigurator(t *testing.T) {
+	hostKey := ssh.HostKey("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINRBWdhdCjoIEj4Uq+12WVbRLVwL/SH9IeluylpbbS08")
+
 	sut := func(initialConfigContent string) (ssh.Configurator, string) {
 		path := filepath.Join(id.New[string](), "config")
 
@@ -120,6 +123,93 @@ IdentitiesOnly yes
 		testutil.FileEquals(t, expectedKeyPath, "privkeycontent")
 	})
 
+	t.Run("should keep the connection alive and share it if asked to", func(t *testing.T) {
+		configurator, path := sut("")
+
+		testutil.IsNil(t, configurator.Upsert(ssh.Connection{
+			Host: "example.com",
+			KeepAlive: monad.Value(ssh.KeepAlive{
+				Interval: 30 * time.Second,
+				CountMax: 3,
+				Persist:  10 * time.Minute,
+			}),
+		}))
+		testutil.FileEquals(t, path, fmt.Sprintf(`Host example.com
+StrictHostKeyChecking accept-new
+ServerAliveInterval 30
+ServerAliveCountMax 3
+ControlMaster auto
+ControlPath %s
+ControlPersist 600
+`, filepath.Join(filepath.Dir(path), "seelf-%C")))
+	})
+
+	t.Run("should pin the host key if set", func(t *testing.T) {
+		configurator, path := sut("")
+		knownHostsPath := filepath.Join(filepath.Dir(path), "my-identifier_known_hosts")
+
+		testutil.IsNil(t, configurator.Upsert(ssh.Connection{
+			Identifier: "my-identifier",
+			Host:       "example.com",
+			Port:       monad.Value(2222),
+			HostKey:    monad.Value(hostKey),
+		}))
+		testutil.FileEquals(t, path, fmt.Sprintf(`Host example.com #my-identifier
+StrictHostKeyChecking yes
+Port 2222
+UserKnownHostsFile %s
+`, knownHostsPath))
+		testutil.FileEquals(t, knownHostsPath, "[example.com]:2222 "+string(hostKey)+"\n")
+	})
+
+	t.Run("should reach the host through a bastion if set", func(t *testing.T) {
+		configurator, path := sut("")
+		dir := filepath.Dir(path)
+
+		testutil.IsNil(t, configurator.Upsert(ssh.Connection{
+			Identifier: "my-identifier",
+			Host:       "10.0.0.2",
+			HostKey:    monad.Value(hostKey),
+			Bastion: monad.Value(ssh.Bastion{
+				Host: "bastion.example.com",
+				User: monad.Value("jump"),
+				PrivateKey: monad.Value(ssh.ConnectionKey{
+					Name: "bastionkeyfilename",
+					Key:  "bastionkeycontent",
+				}),
+				HostKey: monad.Value(hostKey),
+			}),
+		}))
+		testutil.FileEquals(t, path, fmt.Sprintf(`Host 10.0.0.2 #my-identifier
+StrictHostKeyChecking yes
+ProxyJump seelf-bastion-my-identifier
+UserKnownHostsFile %s
+Host seelf-bastion-my-identifier #my-identifier_bastion
+StrictHostKeyChecking yes
+HostName bastion.example.com
+User jump
+IdentityFile %s
+IdentitiesOnly yes
+UserKnownHostsFile %s
+`,
+			filepath.Join(dir, "my-identifier_known_hosts"),
+			filepath.Join(dir, "bastionkeyfilename"),
+			filepath.Join(dir, "my-identifier_bastion_known_hosts"),
+		))
+		testutil.FileEquals(t, filepath.Join(dir, "bastionkeyfilename"), "bastionkeycontent")
+		testutil.FileEquals(t, filepath.Join(dir, "my-identifier_bastion_known_hosts"), "bastion.example.com "+string(hostKey)+"\n")
+
+		testutil.IsNil(t, configurator.Upsert(ssh.Connection{
+			Identifier: "my-identifier",
+			Host:       "10.0.0.2",
+		}))
+		testutil.FileEquals(t, path, `Host 10.0.0.2 #my-identifier
+StrictHostKeyChecking accept-new
+`)
+		testutil.FileEquals(t, filepath.Join(dir, "bastionkeyfilename"), "")
+		testutil.FileEquals(t, filepath.Join(dir, "my-identifier_known_hosts"), "")
+	})
+
 	t.Run("should remove the old private key if it was set", func(t *testing.T) {
 		configurator, path := sut("")
 		oldKeyPath := filepath.Join(filepath.Dir(path), "oldkeyfilename")
@@ -180,6 +270,30 @@ User root
 `)
 	})
 
+	t.Run("should remove the bastion and pinned host keys of the host being removed", func(t *testing.T) {
+		configurator, path := sut("")
+		dir := filepath.Dir(path)
+		configurator.Upsert(ssh.Connection{
+			Identifier: "my-identifier",
+			Host:       "10.0.0.2",
+			HostKey:    monad.Value(hostKey),
+			Bastion: monad.Value(ssh.Bastion{
+				Host: "bastion.example.com",
+				PrivateKey: monad.Value(ssh.ConnectionKey{
+					Name: "bastionkeyfilename",
+					Key:  "bastionkeycontent",
+				}),
+				HostKey: monad.Value(hostKey),
+			}),
+		})
+
+		testutil.IsNil(t, configurator.Remove("my-identifier"))
+		testutil.FileEquals(t, path, "")
+		testutil.FileEquals(t, filepath.Join(dir, "bastionkeyfilename"), "")
+		testutil.FileEquals(t, filepath.Join(dir, "my-identifier_known_hosts"), "")
+		testutil.FileEquals(t, filepath.Join(dir, "my-identifier_bastion_known_hosts"), "")
+	})
+
 	t.Run("should remove the private key attached to the host being removed", func(t *testing.T) {
 		configurator, path := sut("")
 		keyPath := filepath.Join(filepath.Dir(path), "privkeyfilename")
@@ -0,0 +1,39 @@
+package ssh
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var ErrInvalidHostKey = apperr.New("invalid_host_key")
+
+// Public key of an ssh server, used to pin the identity of a host.
+type HostKey string
+
+// Parses a raw public host key in the authorized_keys format, such as the content
+// of /etc/ssh/ssh_host_ed25519_key.pub. The comment, if any, is dropped.
+func ParseHostKey(value string) (HostKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(value))
+
+	if err != nil {
+		return "", ErrInvalidHostKey
+	}
+
+	return HostKey(strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))), nil
+}
+
+// Builds the known_hosts line trusting this key for the given host and port.
+func (k HostKey) knownHostsLine(host Host, port int) (string, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k))
+
+	if err != nil {
+		return "", ErrInvalidHostKey
+	}
+
+	return knownhosts.Line([]string{net.JoinHostPort(host.String(), strconv.Itoa(port))}, key), nil
+}
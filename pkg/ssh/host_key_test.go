@@ -0,0 +1,39 @@
+package ssh_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/ssh"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_HostKey(t *testing.T) {
+	t.Run("should correctly parse a host key", func(t *testing.T) {
+		tests := []struct {
+			value    string
+			expected ssh.HostKey
+			valid    bool
+		}{
+			{"", "", false},
+			{"invalid", "", false},
+			{
+				"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINRBWdhdCjoIEj4Uq+12WVbRLVwL/SH9IeluylpbbS08 root@host\n",
+				"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAINRBWdhdCjoIEj4Uq+12WVbRLVwL/SH9IeluylpbbS08",
+				true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.value, func(t *testing.T) {
+				got, err := ssh.ParseHostKey(tt.value)
+
+				if tt.valid {
+					testutil.IsNil(t, err)
+					testutil.Equals(t, tt.expected, got)
+				} else {
+					testutil.ErrorIs(t, ssh.ErrInvalidHostKey, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Keeps one multiplexed connection per host open and shares it with every ssh
+	// invocation targeting this host, so they do not have to authenticate each time.
+	// It relies on the ControlMaster feature of OpenSSH, configured by the Configurator.
+	Pool interface {
+		Acquire(ctx context.Context, host Host) error // Make sure a healthy connection to the host exists, (re)connecting if needed
+		Release(ctx context.Context, host Host) error // Close the connection to the host if any
+	}
+
+	// Options of pooled connections.
+	KeepAlive struct {
+		Interval time.Duration // Interval between keepalive messages, 0 to disable them
+		CountMax int           // Unanswered keepalive messages after which the connection is considered dead
+		Persist  time.Duration // How long an idle connection is kept open, 0 to disable pooling
+	}
+
+	// Runs the ssh binary with the given arguments.
+	Runner func(ctx context.Context, args ...string) error
+
+	commandPool struct {
+		mu      sync.Mutex
+		hosts   map[Host]*sync.Mutex
+		options KeepAlive
+		run     Runner
+	}
+)
+
+// Builds a new pool with the given options. Unless a runner is given (mostly for tests),
+// the ssh binary available in the PATH is used.
+func NewPool(options KeepAlive, runner ...Runner) Pool {
+	p := &commandPool{
+		hosts:   make(map[Host]*sync.Mutex),
+		options: options,
+		run:     runSSH,
+	}
+
+	if len(runner) > 0 {
+		p.run = runner[0]
+	}
+
+	return p
+}
+
+func (p *commandPool) Acquire(ctx context.Context, host Host) error {
+	if !p.options.Enabled() {
+		return nil
+	}
+
+	mu := p.lock(host)
+	defer mu.Unlock()
+
+	// Healthy master connection, nothing to do
+	if p.run(ctx, "-O", "check", host.String()) == nil {
+		return nil
+	}
+
+	// Drop a master which may be hung before opening a new one in the background
+	_ = p.run(ctx, "-O", "exit", host.String())
+
+	return p.run(ctx, "-o", "BatchMode=yes", "-o", "ControlMaster=yes", "-f", "-N", host.String())
+}
+
+func (p *commandPool) Release(ctx context.Context, host Host) error {
+	if !p.options.Enabled() {
+		return nil
+	}
+
+	mu := p.lock(host)
+	defer mu.Unlock()
+
+	// Not running, nothing to release
+	if p.run(ctx, "-O", "check", host.String()) != nil {
+		return nil
+	}
+
+	return p.run(ctx, "-O", "exit", host.String())
+}
+
+func (p *commandPool) lock(host Host) *sync.Mutex {
+	p.mu.Lock()
+
+	mu, exists := p.hosts[host]
+
+	if !exists {
+		mu = &sync.Mutex{}
+		p.hosts[host] = mu
+	}
+
+	p.mu.Unlock()
+
+	mu.Lock()
+
+	return mu
+}
+
+// Returns true if connections should be pooled.
+func (k KeepAlive) Enabled() bool { return k.Persist > 0 }
+
+func runSSH(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+
+	// When going to the background, ssh keeps its standard error open so it could not
+	// be captured without waiting for the connection to be closed.
+	if !slices.Contains(args, "-f") {
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package ssh_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/ssh"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Pool(t *testing.T) {
+	options := ssh.KeepAlive{
+		Interval: 30 * time.Second,
+		CountMax: 3,
+		Persist:  10 * time.Minute,
+	}
+
+	sut := func(options ssh.KeepAlive, healthy bool) (ssh.Pool, *[]string) {
+		var calls []string
+
+		return ssh.NewPool(options, func(_ context.Context, args ...string) error {
+			command := strings.Join(args, " ")
+			calls = append(calls, command)
+
+			if !healthy && strings.HasPrefix(command, "-O check") {
+				return errors.New("no master running")
+			}
+
+			return nil
+		}), &calls
+	}
+
+	t.Run("should do nothing if pooling is disabled", func(t *testing.T) {
+		pool, calls := sut(ssh.KeepAlive{}, false)
+
+		testutil.IsNil(t, pool.Acquire(context.Background(), "example.com"))
+		testutil.IsNil(t, pool.Release(context.Background(), "example.com"))
+		testutil.HasLength(t, *calls, 0)
+	})
+
+	t.Run("should reuse a healthy connection", func(t *testing.T) {
+		pool, calls := sut(options, true)
+
+		testutil.IsNil(t, pool.Acquire(context.Background(), "example.com"))
+		testutil.DeepEquals(t, []string{"-O check example.com"}, *calls)
+	})
+
+	t.Run("should reconnect if the connection is not healthy", func(t *testing.T) {
+		pool, calls := sut(options, false)
+
+		testutil.IsNil(t, pool.Acquire(context.Background(), "example.com"))
+		testutil.DeepEquals(t, []string{
+			"-O check example.com",
+			"-O exit example.com",
+			"-o BatchMode=yes -o ControlMaster=yes -f -N example.com",
+		}, *calls)
+	})
+
+	t.Run("should close a running connection when releasing it", func(t *testing.T) {
+		pool, calls := sut(options, true)
+
+		testutil.IsNil(t, pool.Release(context.Background(), "example.com"))
+		testutil.DeepEquals(t, []string{"-O check example.com", "-O exit example.com"}, *calls)
+	})
+}
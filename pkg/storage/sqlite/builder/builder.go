@@ -41,6 +41,10 @@ type (
 		// S for Statement, apply one or multiple statements to this builder.
 		S(...Statement) QueryBuilder[T]
 
+		// Decrypt values of the given result columns, which must have been written
+		// encrypted. Other columns are always returned as stored.
+		Decrypt(...string) QueryBuilder[T]
+
 		// Returns the SQL query generated
 		String() string
 
@@ -64,6 +68,7 @@ type queryBuilder[T any] struct {
 	supportPagination bool
 	parts             []string
 	arguments         []any
+	encrypted         []string
 }
 
 // Builds up a new query.
@@ -145,6 +150,11 @@ func (q *queryBuilder[T]) S(statements ...Statement) QueryBuilder[T] {
 	return q
 }
 
+func (q *queryBuilder[T]) Decrypt(columns ...string) QueryBuilder[T] {
+	q.encrypted = append(q.encrypted, columns...)
+	return q
+}
+
 func (q *queryBuilder[T]) Apply(sql string, args ...any) { q.F(sql, args...) }
 
 func (q *queryBuilder[T]) All(
@@ -152,7 +162,10 @@ func (q *queryBuilder[T]) All(
 	ctx context.Context,
 	mapper storage.Mapper[T],
 	loaders ...Dataloader[T],
-) ([]T, error) {
+) (_ []T, err error) {
+	ctx, end := startSpan(ctx, "query", q.String())
+	defer func() { end(err) }()
+
 	rows, err := ex.QueryContext(ctx, q.String(), q.arguments...)
 
 	if err != nil {
@@ -161,6 +174,10 @@ func (q *queryBuilder[T]) All(
 
 	defer rows.Close()
 
+	if mapper, err = q.decrypted(ex, rows, mapper); err != nil {
+		return nil, err
+	}
+
 	results := make([]T, 0)
 
 	// Instantiates needed stuff for data loaders
@@ -247,10 +264,11 @@ func (q *queryBuilder[T]) One(
 	ctx context.Context,
 	mapper storage.Mapper[T],
 	loaders ...Dataloader[T],
-) (T, error) {
-	row := ex.QueryRowContext(ctx, q.String(), q.arguments...)
+) (_ T, err error) {
+	ctx, end := startSpan(ctx, "query", q.String())
+	defer func() { end(err) }()
 
-	result, err := mapper(row)
+	result, err := q.first(ex, ctx, mapper)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return result, apperr.ErrNotFound
@@ -279,6 +297,40 @@ func (q *queryBuilder[T]) One(
 
 }
 
+// Maps the first row returned by the query, sql.ErrNoRows if there is none.
+func (q *queryBuilder[T]) first(ex Executor, ctx context.Context, mapper storage.Mapper[T]) (result T, err error) {
+	if len(q.encrypted) == 0 {
+		return mapper(ex.QueryRowContext(ctx, q.String(), q.arguments...))
+	}
+
+	// Result columns names are needed to know which values should be decrypted
+	rows, err := ex.QueryContext(ctx, q.String(), q.arguments...)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer rows.Close()
+
+	if mapper, err = q.decrypted(ex, rows, mapper); err != nil {
+		return result, err
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return result, err
+		}
+
+		return result, sql.ErrNoRows
+	}
+
+	if result, err = mapper(rows); err != nil {
+		return result, err
+	}
+
+	return result, rows.Close()
+}
+
 func (q *queryBuilder[T]) Extract(ex Executor, ctx context.Context) (T, error) {
 	return q.One(ex, ctx, valueMapper[T])
 }
@@ -287,8 +339,11 @@ func (q *queryBuilder[T]) ExtractAll(ex Executor, ctx context.Context) ([]T, err
 	return q.All(ex, ctx, valueMapper[T])
 }
 
-func (q *queryBuilder[T]) Exec(ex Executor, ctx context.Context) error {
-	_, err := ex.ExecContext(ctx, q.String(), q.arguments...)
+func (q *queryBuilder[T]) Exec(ex Executor, ctx context.Context) (err error) {
+	ctx, end := startSpan(ctx, "exec", q.String())
+	defer func() { end(err) }()
+
+	_, err = ex.ExecContext(ctx, q.String(), q.arguments...)
 	return err
 }
 
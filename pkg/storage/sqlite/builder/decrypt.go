@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var errNullString = errors.New("converting NULL to string is unsupported")
+
+type (
+	// Executor storing some values encrypted at rest. Values of columns declared as
+	// encrypted by a query are decrypted before being handed to mappers so they never
+	// have to care about it.
+	Decrypter interface {
+		// Decrypt the given value if it has been encrypted, returning false if it was not.
+		Decrypt(string) (string, bool, error)
+	}
+
+	decryptingScanner struct {
+		scanner   storage.Scanner
+		decrypter Decrypter
+		indexes   map[int]bool // Indexes of the columns to decrypt
+	}
+
+	// Destination of a scanned value which decrypts it before assigning it.
+	decryptedValue struct {
+		dest      any
+		decrypter Decrypter
+	}
+)
+
+// Wraps the mapper so values of the columns declared as encrypted by the query are
+// decrypted, if the executor supports it.
+func (q *queryBuilder[T]) decrypted(ex Executor, rows *sql.Rows, mapper storage.Mapper[T]) (storage.Mapper[T], error) {
+	decrypter, ok := ex.(Decrypter)
+
+	if !ok || len(q.encrypted) == 0 {
+		return mapper, nil
+	}
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[int]bool, len(q.encrypted))
+
+	for i, column := range columns {
+		if slices.Contains(q.encrypted, column) {
+			indexes[i] = true
+		}
+	}
+
+	return func(scanner storage.Scanner) (T, error) {
+		return mapper(&decryptingScanner{scanner, decrypter, indexes})
+	}, nil
+}
+
+func (s *decryptingScanner) Scan(dest ...any) error {
+	proxies := make([]any, len(dest))
+
+	for i, d := range dest {
+		proxies[i] = d
+
+		if !s.indexes[i] {
+			continue
+		}
+
+		// Only strings could hold encrypted values, other destinations are left untouched
+		switch d.(type) {
+		case sql.Scanner, *string:
+			proxies[i] = &decryptedValue{d, s.decrypter}
+		}
+	}
+
+	return s.scanner.Scan(proxies...)
+}
+
+func (v *decryptedValue) Scan(src any) error {
+	var raw string
+
+	switch value := src.(type) {
+	case string:
+		raw = value
+	case []byte:
+		raw = string(value)
+	}
+
+	if raw != "" {
+		plain, wasEncrypted, err := v.decrypter.Decrypt(raw)
+
+		if err != nil {
+			return err
+		}
+
+		if wasEncrypted {
+			src = plain
+		}
+	}
+
+	switch dest := v.dest.(type) {
+	case sql.Scanner:
+		return dest.Scan(src)
+	case *string:
+		var str sql.NullString
+
+		if err := str.Scan(src); err != nil {
+			return err
+		}
+
+		if !str.Valid {
+			return errNullString
+		}
+
+		*dest = str.String
+	}
+
+	return nil
+}
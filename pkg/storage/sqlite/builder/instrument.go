@@ -0,0 +1,40 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxStatementLength = 1024 // Keep spans reasonably sized, arguments are never included
+
+var queryDuration = telemetry.DurationHistogram("db.query.duration", "Duration of database queries")
+
+// Starts a span for the given query and returns the function to call with the
+// query result to end it and record its duration.
+func startSpan(ctx context.Context, operation string, query string) (context.Context, func(error)) {
+	start := time.Now()
+
+	if len(query) > maxStatementLength {
+		query = query[:maxStatementLength]
+	}
+
+	ctx, span := telemetry.Tracer().Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.statement", query),
+		))
+
+	return ctx, func(err error) {
+		telemetry.End(span, err)
+		queryDuration.Record(ctx, telemetry.Since(start), metric.WithAttributes(
+			attribute.String("db.operation", operation),
+			attribute.Bool("error", err != nil),
+		))
+	}
+}
@@ -3,96 +3,177 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	"io/fs"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/event"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage"
 	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 const (
 	dbDriverName                     = "sqlite3"
-	migrateSourceName                = "embed"
 	transactionContextKey contextKey = "sqlitetx"
 )
 
 var _ builder.Executor = (*Database)(nil) // Ensure Database implements the Executor interface
 
 type (
-	// Represents a single module for database migrations.
-	MigrationsModule struct {
-		name string // Name of the module, used as a prefix for the migrations history table.
-		dir  string // Relative directory in the fs containing *.sql migrations files.
-		fs   fs.FS
-	}
-
 	// Handle to a sqlite database with useful helper methods on it :)
 	Database struct {
-		conn   *sql.DB
-		bus    bus.Dispatcher
-		logger log.Logger
+		conn        *sql.DB
+		bus         bus.Dispatcher
+		logger      log.Logger
+		readOnly    *Database // Read-only handle, may be the database itself if no read pool is configured
+		middlewares []event.Middleware
+		keyring     crypto.Keyring // Encrypts sensitive values, may be nil
+		done        chan struct{}  // Closed when the database is closed to stop background tasks
+		wg          sync.WaitGroup
+	}
+
+	// Option used to configure a database when opening it.
+	Option func(*options)
+
+	options struct {
+		readConnections    int
+		checkpointInterval time.Duration
+		eventMiddlewares   []event.Middleware
+		keyring            crypto.Keyring
 	}
 
 	contextKey string
+
+	versionedSource interface {
+		event.Source
+		storage.Versionable
+	}
 )
 
 // Opens a connection to a sqlite database file.
-func Open(dsn string, logger log.Logger, bus bus.Dispatcher) (*Database, error) {
-	db, err := sql.Open(dbDriverName, dsn)
+func Open(dsn string, logger log.Logger, bus bus.Dispatcher, opts ...Option) (*Database, error) {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	conn, err := openConn(dsn)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err = db.PingContext(context.Background()); err != nil {
-		return nil, err
+	db := &Database{
+		conn:        conn,
+		bus:         bus,
+		logger:      logger,
+		middlewares: o.eventMiddlewares,
+		keyring:     o.keyring,
+		done:        make(chan struct{}),
 	}
 
-	return &Database{db, bus, logger}, nil
-}
+	db.readOnly = db
 
-// Close the underlying database.
-func (db *Database) Close() error {
-	return db.conn.Close()
-}
-
-// Migrates the opened database to the latest version.
-func (db *Database) Migrate(modules ...MigrationsModule) error {
-	for _, module := range modules {
-		source, err := iofs.New(module.fs, module.dir)
+	if o.readConnections > 0 {
+		// Must be opened after the main connection which is responsible for creating the database file
+		readConn, err := openConn(readOnlyDsn(dsn))
 
 		if err != nil {
-			return err
+			_ = conn.Close()
+			return nil, err
 		}
 
-		driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{
-			MigrationsTable: module.name + "_" + sqlite3.DefaultMigrationsTable,
-		})
+		readConn.SetMaxOpenConns(o.readConnections)
 
-		if err != nil {
-			return err
+		db.readOnly = &Database{
+			conn:        readConn,
+			bus:         bus,
+			logger:      logger,
+			middlewares: o.eventMiddlewares,
+			keyring:     o.keyring,
 		}
+		db.readOnly.readOnly = db.readOnly
+	}
+
+	if o.checkpointInterval > 0 {
+		db.wg.Add(1)
+		go db.checkpoint(o.checkpointInterval)
+	}
 
-		migrator, err := migrate.NewWithInstance(migrateSourceName, source, dbDriverName, driver)
+	return db, nil
+}
 
-		if err != nil {
-			return err
-		}
+// Use a dedicated pool of at most size read-only connections for the handle returned by
+// Database.ReadOnly. Since the WAL journal mode allow readers and writer to operate concurrently,
+// it prevents heavy queries from blocking writes.
+func WithReadPool(size int) Option {
+	return func(o *options) {
+		o.readConnections = size
+	}
+}
 
-		db.logger.Debugw("migrating database",
-			"module", module.name)
+// Periodically checkpoint the WAL file at the given interval. It uses a PASSIVE checkpoint
+// so it never blocks readers and writers (and plays nicely with tools such as litestream).
+func WithCheckpointInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.checkpointInterval = interval
+	}
+}
+
+// Wraps every event handled by WriteAndDispatch switchers with the given middlewares.
+// This is the place to add cross-cutting concerns such as logging, metrics or tracing.
+func WithEventMiddlewares(middlewares ...event.Middleware) Option {
+	return func(o *options) {
+		o.eventMiddlewares = append(o.eventMiddlewares, middlewares...)
+	}
+}
+
+// Retrieve a handle which should only be used for reads, such as query handlers.
+// The returned handle must not be closed, it will be when closing the parent database.
+func (db *Database) ReadOnly() *Database {
+	return db.readOnly
+}
 
-		if err := migrator.Up(); err != nil && err != migrate.ErrNoChange {
+// Close the underlying database.
+func (db *Database) Close() error {
+	if db.done != nil {
+		close(db.done)
+		db.wg.Wait()
+	}
+
+	if db.readOnly != db {
+		if err := db.readOnly.conn.Close(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return db.conn.Close()
+}
+
+// Make sure the database, and its read-only pool if any, could answer queries.
+func (db *Database) Ping(ctx context.Context) error {
+	var one int
+
+	if err := db.conn.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return err
+	}
+
+	if db.readOnly == db {
+		return nil
+	}
+
+	return db.readOnly.conn.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// Writes a consistent copy of the database to the given path, which must not exist yet.
+func (db *Database) Snapshot(ctx context.Context, path string) error {
+	_, err := db.conn.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
 }
 
 // Creates and enhance the given context with a transaction if no one exists yet.
@@ -176,14 +257,75 @@ func WriteAndDispatch[T event.Source](
 	ctx context.Context,
 	entities []T,
 	switcher func(context.Context, event.Event) error,
+) error {
+	return writeAndDispatch(db, ctx, entities, nil, switcher)
+}
+
+// Same as WriteAndDispatch but for versioned entities. Before processing events of an entity
+// which has already been persisted, its version is checked and incremented in the given table
+// using the key returned by the given function (a SQL condition and its arguments).
+//
+// If the persisted version does not match the one the entity has been loaded with, it means
+// someone else has modified it in the meantime and storage.ErrConcurrentModification is returned.
+func WriteVersionedAndDispatch[T versionedSource](
+	db *Database,
+	ctx context.Context,
+	table string,
+	entities []T,
+	key func(T) (string, []any),
+	switcher func(context.Context, event.Event) error,
+) error {
+	return writeAndDispatch(db, ctx, entities, func(ctx context.Context, ent T) error {
+		version := storage.VersionOf(ent)
+
+		// Not persisted yet, the version will be set by the insert statement
+		if version == 0 {
+			return nil
+		}
+
+		condition, args := key(ent)
+
+		r, err := db.ExecContext(ctx, "UPDATE "+table+" SET version = version + 1 WHERE "+condition+" AND version = ?",
+			append(args, version)...)
+
+		if err != nil {
+			return err
+		}
+
+		affected, err := r.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return storage.ErrConcurrentModification
+		}
+
+		return nil
+	}, switcher)
+}
+
+func writeAndDispatch[T event.Source](
+	db *Database,
+	ctx context.Context,
+	entities []T,
+	guard func(context.Context, T) error,
+	switcher event.Handler,
 ) (finalErr error) {
 	var (
 		tx      *sql.Tx
 		created bool
+		flush   func()
 	)
 
 	ctx, tx, created = db.WithTransaction(ctx)
 
+	// Asynchronous event handlers should only be triggered once the transaction is committed
+	if created {
+		ctx, flush = event.WithDeferred(ctx)
+	}
+
 	defer func() {
 		if !created {
 			return
@@ -193,17 +335,30 @@ func WriteAndDispatch[T event.Source](
 			if err := tx.Rollback(); err != nil {
 				finalErr = err
 			}
-		} else {
-			finalErr = tx.Commit()
+		} else if finalErr = tx.Commit(); finalErr == nil {
+			flush()
 		}
 	}()
 
+	handle := event.Chain(switcher, db.middlewares...)
+
 	for _, ent := range entities {
 		events := event.Unwrap(ent)
+
+		if len(events) == 0 {
+			continue
+		}
+
+		if guard != nil {
+			if finalErr = guard(ctx, ent); finalErr != nil {
+				return
+			}
+		}
+
 		notifs := make([]bus.Signal, len(events)) // It's a shame Go could not accept an array of events as a slice of signals since Event are effectively Signal
 
 		for i, evt := range events {
-			if finalErr = switcher(ctx, evt); finalErr != nil {
+			if finalErr = handle(ctx, evt); finalErr != nil {
 				return
 			}
 
@@ -217,11 +372,65 @@ func WriteAndDispatch[T event.Source](
 		// TODO: clear entities events (see #71)
 	}
 
+	// Everything went fine, keep in-memory versions in sync with the persisted ones but only
+	// once the transaction is committed, by this call or by the outer one which owns it.
+	if guard != nil {
+		for _, ent := range entities {
+			if versioned, ok := any(ent).(storage.Versionable); ok && len(event.Unwrap(ent)) > 0 {
+				event.Defer(ctx, func() { storage.NextVersion(versioned) })
+			}
+		}
+	}
+
 	return nil
 }
 
-// Builds a new migrations module with the given module name (used as a migrations history table name prefix)
-// and the directory where migrations are stored in the given filesystem.
-func NewMigrationsModule(name string, dir string, fs fs.FS) MigrationsModule {
-	return MigrationsModule{name, dir, fs}
+func (db *Database) checkpoint(interval time.Duration) {
+	defer db.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.done:
+			return
+		case <-ticker.C:
+			var busy, frames, checkpointed int
+
+			if err := db.conn.QueryRowContext(context.Background(), "PRAGMA wal_checkpoint(PASSIVE)").
+				Scan(&busy, &frames, &checkpointed); err != nil {
+				db.logger.Errorw("could not checkpoint the database",
+					"error", err)
+				continue
+			}
+
+			db.logger.Debugw("database checkpointed",
+				"frames", frames,
+				"checkpointed", checkpointed)
+		}
+	}
+}
+
+func openConn(dsn string) (*sql.DB, error) {
+	conn, err := sql.Open(dbDriverName, dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.PingContext(context.Background()); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func readOnlyDsn(dsn string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&mode=ro"
+	}
+
+	return dsn + "?mode=ro"
 }
@@ -0,0 +1,147 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type (
+	item struct {
+		event.Emitter
+		storage.Versioned
+	}
+
+	itemRenamed struct {
+		bus.Notification
+	}
+)
+
+func (itemRenamed) Name_() string { return "ItemRenamed" }
+
+func Test_Database(t *testing.T) {
+	open := func(t *testing.T, options ...sqlite.Option) *sqlite.Database {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db?_journal=WAL"), logger, memory.NewBus(), options...)
+
+		testutil.IsNil(t, err)
+
+		_, err = db.ExecContext(context.Background(), "CREATE TABLE items (name TEXT)")
+
+		testutil.IsNil(t, err)
+
+		return db
+	}
+
+	t.Run("should use the database itself as the read-only handle if no read pool is configured", func(t *testing.T) {
+		db := open(t)
+		defer db.Close()
+
+		testutil.IsTrue(t, db.ReadOnly() == db)
+	})
+
+	t.Run("should read committed data from the read pool and refuse writes", func(t *testing.T) {
+		db := open(t, sqlite.WithReadPool(2))
+		defer db.Close()
+
+		_, err := db.ExecContext(context.Background(), "INSERT INTO items (name) VALUES ('one')")
+		testutil.IsNil(t, err)
+
+		var count int
+		err = db.ReadOnly().QueryRowContext(context.Background(), "SELECT COUNT(*) FROM items").Scan(&count)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, count)
+
+		_, err = db.ReadOnly().ExecContext(context.Background(), "INSERT INTO items (name) VALUES ('two')")
+
+		testutil.IsNotNil(t, err)
+	})
+
+	t.Run("should ping the database and its read pool", func(t *testing.T) {
+		db := open(t, sqlite.WithReadPool(1))
+
+		testutil.IsNil(t, db.Ping(context.Background()))
+		testutil.IsNil(t, db.Close())
+		testutil.IsNotNil(t, db.Ping(context.Background()))
+	})
+
+	t.Run("should write a snapshot of the database", func(t *testing.T) {
+		db := open(t)
+		defer db.Close()
+
+		_, err := db.ExecContext(context.Background(), "INSERT INTO items (name) VALUES ('one')")
+		testutil.IsNil(t, err)
+
+		path := filepath.Join(t.TempDir(), "snapshot.db")
+		testutil.IsNil(t, db.Snapshot(context.Background(), path))
+
+		logger, _ := log.NewLogger()
+		snapshot, err := sqlite.Open("file:"+path, logger, memory.NewBus())
+		testutil.IsNil(t, err)
+		defer snapshot.Close()
+
+		var count int
+		testutil.IsNil(t, snapshot.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM items").Scan(&count))
+		testutil.Equals(t, 1, count)
+	})
+
+	t.Run("should stop the checkpoint task when closed", func(t *testing.T) {
+		db := open(t, sqlite.WithCheckpointInterval(time.Millisecond))
+
+		time.Sleep(5 * time.Millisecond)
+
+		testutil.IsNil(t, db.Close())
+	})
+
+	t.Run("should bump in-memory versions only once the owning transaction is committed", func(t *testing.T) {
+		db := open(t)
+		defer db.Close()
+
+		ctx := context.Background()
+		_, err := db.ExecContext(ctx, "CREATE TABLE versioned_items (id TEXT, version INTEGER)")
+		testutil.IsNil(t, err)
+		_, err = db.ExecContext(ctx, "INSERT INTO versioned_items (id, version) VALUES ('item', 1)")
+		testutil.IsNil(t, err)
+
+		key := func(*item) (string, []any) { return "id = ?", []any{"item"} }
+		noop := func(context.Context, event.Event) error { return nil }
+		errRollback := errors.New("rollback")
+
+		var ent item
+		testutil.IsNil(t, ent.Versioned.Scan(int64(1)))
+		event.Store(&ent, itemRenamed{})
+
+		var outer item
+		event.Store(&outer, itemRenamed{})
+
+		// The outer write owns the transaction and fails after the nested one succeeded
+		err = sqlite.WriteAndDispatch(db, ctx, []*item{&outer}, func(ctx context.Context, _ event.Event) error {
+			if err := sqlite.WriteVersionedAndDispatch(db, ctx, "versioned_items", []*item{&ent}, key, noop); err != nil {
+				return err
+			}
+
+			testutil.Equals(t, 1, storage.VersionOf(&ent))
+
+			return errRollback
+		})
+
+		testutil.ErrorIs(t, errRollback, err)
+		testutil.Equals(t, 1, storage.VersionOf(&ent))
+
+		err = sqlite.WriteVersionedAndDispatch(db, ctx, "versioned_items", []*item{&ent}, key, noop)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 2, storage.VersionOf(&ent))
+	})
+}
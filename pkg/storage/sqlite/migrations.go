@@ -0,0 +1,251 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+const migrateSourceName = "embed"
+
+var (
+	ErrSchemaNewerThanBinary = errors.New("database schema is newer than this binary, did you downgrade seelf?")
+	ErrIrreversibleMigration = errors.New("migration could not be reverted because it has no down migration")
+	ErrNoMigrationApplied    = errors.New("no migration has been applied yet")
+)
+
+type (
+	// Represents a single module for database migrations.
+	MigrationsModule struct {
+		name string // Name of the module, used as a prefix for the migrations history table.
+		dir  string // Relative directory in the fs containing *.sql migrations files.
+		fs   fs.FS
+	}
+
+	// Single migration as found in a migrations module.
+	Migration struct {
+		Version uint
+		Name    string
+	}
+
+	// Represents the current migrations state of a module in the database.
+	MigrationsStatus struct {
+		Module  string
+		Current uint // Current version applied to the database, 0 if none
+		Dirty   bool // Wether the last migration has failed and the database should be fixed manually
+		Latest  uint // Latest version known by this binary
+		Pending []Migration
+	}
+
+	migrationsRunner struct {
+		module   MigrationsModule
+		source   source.Driver
+		migrator *migrate.Migrate
+	}
+)
+
+// Builds a new migrations module with the given module name (used as a migrations history table name prefix)
+// and the directory where migrations are stored in the given filesystem.
+func NewMigrationsModule(name string, dir string, fs fs.FS) MigrationsModule {
+	return MigrationsModule{name, dir, fs}
+}
+
+func (m MigrationsModule) Name() string { return m.name }
+
+// Returns true if the database has been migrated to a version unknown to this binary.
+func (s MigrationsStatus) IsNewerThanBinary() bool { return s.Current > s.Latest }
+
+// Migrates the opened database to the latest version.
+func (db *Database) Migrate(modules ...MigrationsModule) error {
+	if err := db.EnsureSchemaCompatible(modules...); err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		runner, err := db.newMigrationsRunner(module)
+
+		if err != nil {
+			return err
+		}
+
+		db.logger.Debugw("migrating database",
+			"module", module.name)
+
+		if err := runner.migrator.Up(); err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Retrieve the migrations status of every given modules without applying anything.
+func (db *Database) MigrationsStatus(modules ...MigrationsModule) ([]MigrationsStatus, error) {
+	result := make([]MigrationsStatus, len(modules))
+
+	for i, module := range modules {
+		runner, err := db.newMigrationsRunner(module)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if result[i], err = runner.status(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Makes sure the database schema is not newer than the migrations known by this binary
+// for every given modules. It prevents an old binary from messing with a database
+// it does not understand.
+func (db *Database) EnsureSchemaCompatible(modules ...MigrationsModule) error {
+	statuses, err := db.MigrationsStatus(modules...)
+
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.IsNewerThanBinary() {
+			return fmt.Errorf("%w: %s module is at version %d but latest known is %d",
+				ErrSchemaNewerThanBinary, status.Module, status.Current, status.Latest)
+		}
+	}
+
+	return nil
+}
+
+// Reverts the given number of migrations for the given module. Every reverted migration
+// must have a down migration file or ErrIrreversibleMigration is returned and nothing is applied.
+func (db *Database) Rollback(module MigrationsModule, steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+
+	runner, err := db.newMigrationsRunner(module)
+
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := runner.migrator.Version()
+
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return ErrNoMigrationApplied
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return migrate.ErrDirty{Version: int(version)}
+	}
+
+	// Check every reverted migration has a down file before applying anything. Since
+	// golang-migrate considers a missing down file as an empty migration, it would
+	// silently update the version without reverting the schema.
+	for i := 0; i < steps; i++ {
+		r, _, err := runner.source.ReadDown(version)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s module version %d", ErrIrreversibleMigration, module.name, version)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_ = r.Close()
+
+		prev, err := runner.source.Prev(version)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			steps = i + 1 // Reached the first migration
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		version = prev
+	}
+
+	db.logger.Infow("rolling back migrations",
+		"module", module.name,
+		"steps", steps)
+
+	return runner.migrator.Steps(-steps)
+}
+
+func (db *Database) newMigrationsRunner(module MigrationsModule) (*migrationsRunner, error) {
+	source, err := iofs.New(module.fs, module.dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{
+		MigrationsTable: module.name + "_" + sqlite3.DefaultMigrationsTable,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrate.NewWithInstance(migrateSourceName, source, dbDriverName, driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrationsRunner{module, source, migrator}, nil
+}
+
+func (r *migrationsRunner) status() (MigrationsStatus, error) {
+	status := MigrationsStatus{Module: r.module.name}
+
+	current, dirty, err := r.migrator.Version()
+
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return status, err
+	}
+
+	status.Current = current
+	status.Dirty = dirty
+
+	version, err := r.source.First()
+
+	for err == nil {
+		status.Latest = version
+
+		if version > status.Current {
+			reader, name, err := r.source.ReadUp(version)
+
+			if err != nil {
+				return status, err
+			}
+
+			_ = reader.Close()
+
+			status.Pending = append(status.Pending, Migration{version, name})
+		}
+
+		version, err = r.source.Next(version)
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return status, err
+	}
+
+	return status, nil
+}
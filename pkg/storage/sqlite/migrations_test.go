@@ -0,0 +1,116 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Migrations(t *testing.T) {
+	module := sqlite.NewMigrationsModule("test", "migrations", fstest.MapFS{
+		"migrations/1_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id TEXT);")},
+		"migrations/2_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id TEXT);")},
+		"migrations/2_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"migrations/3_create_c.up.sql":   {Data: []byte("CREATE TABLE c (id TEXT);")},
+		"migrations/3_create_c.down.sql": {Data: []byte("DROP TABLE c;")},
+	})
+
+	older := sqlite.NewMigrationsModule("test", "migrations", fstest.MapFS{
+		"migrations/1_create_a.up.sql": {Data: []byte("CREATE TABLE a (id TEXT);")},
+	})
+
+	open := func(t *testing.T) *sqlite.Database {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+filepath.Join(t.TempDir(), "test.db"), logger, memory.NewBus())
+
+		testutil.IsNil(t, err)
+
+		t.Cleanup(func() {
+			db.Close()
+		})
+
+		return db
+	}
+
+	t.Run("should list pending migrations without applying them", func(t *testing.T) {
+		db := open(t)
+
+		statuses, err := db.MigrationsStatus(module)
+
+		testutil.IsNil(t, err)
+		testutil.HasLength(t, statuses, 1)
+		testutil.Equals(t, "test", statuses[0].Module)
+		testutil.Equals(t, 0, statuses[0].Current)
+		testutil.Equals(t, 3, statuses[0].Latest)
+		testutil.DeepEquals(t, []sqlite.Migration{
+			{1, "create_a"},
+			{2, "create_b"},
+			{3, "create_c"},
+		}, statuses[0].Pending)
+	})
+
+	t.Run("should apply pending migrations", func(t *testing.T) {
+		db := open(t)
+
+		err := db.Migrate(module)
+
+		testutil.IsNil(t, err)
+
+		statuses, err := db.MigrationsStatus(module)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 3, statuses[0].Current)
+		testutil.HasLength(t, statuses[0].Pending, 0)
+	})
+
+	t.Run("should refuse to migrate a database newer than known migrations", func(t *testing.T) {
+		db := open(t)
+
+		testutil.IsNil(t, db.Migrate(module))
+
+		testutil.ErrorIs(t, sqlite.ErrSchemaNewerThanBinary, db.Migrate(older))
+		testutil.ErrorIs(t, sqlite.ErrSchemaNewerThanBinary, db.EnsureSchemaCompatible(older))
+	})
+
+	t.Run("should rollback migrations", func(t *testing.T) {
+		db := open(t)
+
+		testutil.IsNil(t, db.Migrate(module))
+
+		err := db.Rollback(module, 2)
+
+		testutil.IsNil(t, err)
+
+		statuses, err := db.MigrationsStatus(module)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 1, statuses[0].Current)
+		testutil.HasLength(t, statuses[0].Pending, 2)
+	})
+
+	t.Run("should refuse to rollback a migration without a down file", func(t *testing.T) {
+		db := open(t)
+
+		testutil.IsNil(t, db.Migrate(module))
+
+		err := db.Rollback(module, 3)
+
+		testutil.ErrorIs(t, sqlite.ErrIrreversibleMigration, err)
+
+		statuses, err := db.MigrationsStatus(module)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 3, statuses[0].Current)
+	})
+
+	t.Run("should return an error when rolling back without any applied migration", func(t *testing.T) {
+		db := open(t)
+
+		testutil.ErrorIs(t, sqlite.ErrNoMigrationApplied, db.Rollback(module, 1))
+	})
+}
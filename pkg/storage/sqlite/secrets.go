@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+)
+
+var (
+	_ builder.Decrypter = (*Database)(nil) // Ensure encrypted values are decrypted by query builders
+
+	ErrNotEncryptable = errors.New("only values stored as strings could be encrypted")
+)
+
+type (
+	// Column storing values encrypted at rest.
+	EncryptedColumn struct {
+		Table  string
+		Column string
+	}
+
+	encryptedValue struct {
+		keyring crypto.Keyring
+		value   any
+	}
+
+	rowSecret struct {
+		rowID int64
+		value string
+	}
+)
+
+// Encrypt sensitive values written with Database.Encrypted using the given keyring.
+// Values read through query builders are decrypted transparently.
+func WithKeyring(keyring crypto.Keyring) Option {
+	return func(o *options) {
+		o.keyring = keyring
+	}
+}
+
+// Wraps the value so it is encrypted when written, if a keyring has been configured.
+// Only values stored as strings, such as json ones, could be encrypted.
+func (db *Database) Encrypted(value any) driver.Valuer {
+	return encryptedValue{db.keyring, value}
+}
+
+func (db *Database) Decrypt(value string) (string, bool, error) {
+	if !crypto.IsEncrypted(value) {
+		return value, false, nil
+	}
+
+	if db.keyring == nil {
+		return "", true, crypto.ErrUnknownKey
+	}
+
+	plain, err := db.keyring.Decrypt(value)
+
+	return plain, true, err
+}
+
+// Encrypt again values of the given columns which have not been encrypted with the
+// current key, including the ones written before being encrypted at all, and returns
+// how many of them have been updated. Values are decrypted with previous keys of the
+// keyring so they must still be configured.
+func (db *Database) RotateSecrets(ctx context.Context, columns ...EncryptedColumn) (count int, finalErr error) {
+	if db.keyring == nil {
+		return 0, crypto.ErrUnknownKey
+	}
+
+	ctx, tx, created := db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	for _, column := range columns {
+		secrets, err := db.secretsToRotate(ctx, column)
+
+		if err != nil {
+			return count, err
+		}
+
+		for _, secret := range secrets {
+			plain, _, err := db.Decrypt(secret.value)
+
+			if err != nil {
+				return count, err
+			}
+
+			if _, err = db.ExecContext(ctx,
+				"UPDATE "+column.Table+" SET "+column.Column+" = ? WHERE rowid = ?",
+				db.Encrypted(plain), secret.rowID); err != nil {
+				return count, err
+			}
+
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (db *Database) secretsToRotate(ctx context.Context, column EncryptedColumn) ([]rowSecret, error) {
+	rows, err := db.QueryContext(ctx, "SELECT rowid, "+column.Column+" FROM "+column.Table+" WHERE "+column.Column+" IS NOT NULL")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var secrets []rowSecret
+
+	for rows.Next() {
+		var secret rowSecret
+
+		if err = rows.Scan(&secret.rowID, &secret.value); err != nil {
+			return nil, err
+		}
+
+		if db.keyring.NeedsRotation(secret.value) {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, rows.Err()
+}
+
+func (v encryptedValue) Value() (driver.Value, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(v.value)
+
+	if err != nil || value == nil || v.keyring == nil {
+		return value, err
+	}
+
+	switch raw := value.(type) {
+	case string:
+		return v.keyring.Encrypt(raw)
+	case []byte:
+		return v.keyring.Encrypt(string(raw))
+	default:
+		return nil, ErrNotEncryptable
+	}
+}
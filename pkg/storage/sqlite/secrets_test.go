@@ -0,0 +1,155 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/crypto"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+func Test_Secrets(t *testing.T) {
+	ctx := context.Background()
+	column := sqlite.EncryptedColumn{Table: "items", Column: "secret"}
+
+	open := func(t *testing.T, path string, keyring crypto.Keyring) *sqlite.Database {
+		logger, _ := log.NewLogger()
+		db, err := sqlite.Open("file:"+path, logger, memory.NewBus(), sqlite.WithKeyring(keyring))
+
+		testutil.IsNil(t, err)
+
+		_, err = db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS items (name TEXT, secret TEXT NULL)")
+
+		testutil.IsNil(t, err)
+
+		return db
+	}
+
+	rawSecret := func(t *testing.T, db *sqlite.Database, name string) string {
+		var value string
+
+		testutil.IsNil(t, db.QueryRowContext(ctx, "SELECT secret FROM items WHERE name = ?", name).Scan(&value))
+
+		return value
+	}
+
+	readSecret := func(t *testing.T, db *sqlite.Database, name string) monad.Maybe[string] {
+		value, err := builder.
+			Query[monad.Maybe[string]]("SELECT secret FROM items WHERE name = ?", name).
+			Decrypt("secret").
+			Extract(db, ctx)
+
+		testutil.IsNil(t, err)
+
+		return value
+	}
+
+	t.Run("should encrypt values at rest and decrypt them when read", func(t *testing.T) {
+		db := open(t, filepath.Join(t.TempDir(), "test.db"), must.Panic(crypto.NewKeyring([]byte("a secret"))))
+		defer db.Close()
+
+		testutil.IsNil(t, builder.Insert("items", builder.Values{
+			"name":   "encrypted",
+			"secret": db.Encrypted("a sensitive value"),
+		}).Exec(db, ctx))
+		testutil.IsNil(t, builder.Insert("items", builder.Values{
+			"name":   "none",
+			"secret": db.Encrypted(monad.None[string]()),
+		}).Exec(db, ctx))
+
+		testutil.IsTrue(t, crypto.IsEncrypted(rawSecret(t, db, "encrypted")))
+		testutil.Equals(t, "a sensitive value", readSecret(t, db, "encrypted").MustGet())
+		testutil.IsFalse(t, readSecret(t, db, "none").HasValue())
+	})
+
+	t.Run("should rotate values encrypted with a previous key and plain ones", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path, must.Panic(crypto.NewKeyring([]byte("old secret"))))
+
+		testutil.IsNil(t, builder.Insert("items", builder.Values{
+			"name":   "encrypted",
+			"secret": db.Encrypted("a sensitive value"),
+		}).Exec(db, ctx))
+		testutil.IsNil(t, builder.Insert("items", builder.Values{
+			"name":   "plain",
+			"secret": "a plain value",
+		}).Exec(db, ctx))
+
+		old := rawSecret(t, db, "encrypted")
+		testutil.IsNil(t, db.Close())
+
+		keyring := must.Panic(crypto.NewKeyring([]byte("new secret"), []byte("old secret")))
+		db = open(t, path, keyring)
+		defer db.Close()
+
+		count, err := db.RotateSecrets(ctx, column)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 2, count)
+		testutil.NotEquals(t, old, rawSecret(t, db, "encrypted"))
+		testutil.IsFalse(t, keyring.NeedsRotation(rawSecret(t, db, "encrypted")))
+		testutil.IsFalse(t, keyring.NeedsRotation(rawSecret(t, db, "plain")))
+		testutil.Equals(t, "a sensitive value", readSecret(t, db, "encrypted").MustGet())
+		testutil.Equals(t, "a plain value", readSecret(t, db, "plain").MustGet())
+
+		count, err = db.RotateSecrets(ctx, column)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 0, count)
+	})
+
+	t.Run("should only decrypt columns declared as encrypted", func(t *testing.T) {
+		db := open(t, filepath.Join(t.TempDir(), "test.db"), must.Panic(crypto.NewKeyring([]byte("a secret"))))
+		defer db.Close()
+
+		lookalike := "$seelf$deadbeef$not-an-encrypted-value"
+
+		testutil.IsNil(t, builder.Insert("items", builder.Values{
+			"name":   lookalike,
+			"secret": db.Encrypted("a sensitive value"),
+		}).Exec(db, ctx))
+
+		name, err := builder.
+			Query[string]("SELECT name FROM items").
+			Extract(db, ctx)
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, lookalike, name)
+
+		names, err := builder.
+			Query[string]("SELECT name FROM items").
+			Decrypt("secret").
+			ExtractAll(db, ctx)
+
+		testutil.IsNil(t, err)
+		testutil.DeepEquals(t, []string{lookalike}, names)
+
+		encrypted, err := builder.
+			Query[string]("SELECT secret FROM items").
+			Extract(db, ctx)
+
+		testutil.IsNil(t, err)
+		testutil.IsTrue(t, crypto.IsEncrypted(encrypted))
+		testutil.Equals(t, "a sensitive value", readSecret(t, db, lookalike).MustGet())
+	})
+
+	t.Run("should return not found when no row matches a query with encrypted columns", func(t *testing.T) {
+		db := open(t, filepath.Join(t.TempDir(), "test.db"), must.Panic(crypto.NewKeyring([]byte("a secret"))))
+		defer db.Close()
+
+		_, err := builder.
+			Query[string]("SELECT secret FROM items").
+			Decrypt("secret").
+			Extract(db, ctx)
+
+		testutil.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+// Error returned by stores when trying to persist an entity which has been modified
+// by someone else since it was loaded.
+var ErrConcurrentModification = apperr.New("concurrent_modification")
+
+type (
+	// Version of an entity as persisted in the storage. A zero version means the entity
+	// has never been persisted yet.
+	Version uint
+
+	// Represents an entity which keeps track of the version it was loaded with, enabling
+	// optimistic concurrency checks when persisting it.
+	//
+	// Just like the event.Source, methods are unexported so domain entities are not polluted
+	// with those considerations. Use `VersionOf` and `NextVersion` to manipulate it.
+	Versionable interface {
+		currentVersion() Version
+		setVersion(Version)
+	}
+
+	// Implements the Versionable interface, embed it in your own entities and scan it
+	// with the `Versioned` field when rehydrating them.
+	Versioned struct {
+		version Version
+	}
+)
+
+// Retrieve the version the given entity was loaded with.
+func VersionOf(v Versionable) Version {
+	return v.currentVersion()
+}
+
+// Mark the given entity as persisted by incrementing its inner version. It should be called
+// by stores once the version has been successfully updated in the storage.
+func NextVersion(v Versionable) {
+	v.setVersion(v.currentVersion() + 1)
+}
+
+// Implements the sql.Scanner interface to rehydrate the version from the storage.
+func (v *Versioned) Scan(value any) error {
+	switch val := value.(type) {
+	case int64:
+		v.version = Version(val)
+	case nil:
+		v.version = 0
+	default:
+		return fmt.Errorf("%w: unexpected version type %T", ErrCouldNotUnmarshalGivenType, value)
+	}
+
+	return nil
+}
+
+func (v *Versioned) currentVersion() Version { return v.version }
+func (v *Versioned) setVersion(ver Version)  { v.version = ver }
@@ -0,0 +1,46 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/testutil"
+)
+
+type versionedEntity struct {
+	storage.Versioned
+}
+
+func Test_Versioned(t *testing.T) {
+	t.Run("should default to a zero version", func(t *testing.T) {
+		var ent versionedEntity
+
+		testutil.Equals(t, 0, storage.VersionOf(&ent))
+	})
+
+	t.Run("should be rehydrated from a storage value", func(t *testing.T) {
+		var ent versionedEntity
+
+		err := ent.Versioned.Scan(int64(4))
+
+		testutil.IsNil(t, err)
+		testutil.Equals(t, 4, storage.VersionOf(&ent))
+	})
+
+	t.Run("should fail to scan an unexpected type", func(t *testing.T) {
+		var ent versionedEntity
+
+		err := ent.Versioned.Scan("4")
+
+		testutil.ErrorIs(t, storage.ErrCouldNotUnmarshalGivenType, err)
+	})
+
+	t.Run("should increment the version", func(t *testing.T) {
+		var ent versionedEntity
+
+		storage.NextVersion(&ent)
+		storage.NextVersion(&ent)
+
+		testutil.Equals(t, 2, storage.VersionOf(&ent))
+	})
+}
@@ -0,0 +1,131 @@
+// The package telemetry configures OpenTelemetry tracing and metrics and exposes
+// the tracer and meter used by instrumented packages.
+//
+// Until Setup is called with an endpoint, the global no-op providers are used so
+// instrumentation costs almost nothing.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/YuukanOO/seelf"
+
+type (
+	// Options needed to export telemetry data.
+	Options interface {
+		TelemetryEndpoint() string // OTLP/HTTP endpoint (host:port), telemetry is disabled if empty
+		TelemetryInsecure() bool   // Wether or not to use plain HTTP to reach the endpoint
+	}
+
+	// Function to call on shutdown to flush pending telemetry data.
+	ShutdownFunc func(context.Context) error
+)
+
+// Configure global tracer and meter providers to export data using OTLP/HTTP.
+// If no endpoint is configured, it does nothing.
+func Setup(ctx context.Context, options Options, serviceVersion string) (ShutdownFunc, error) {
+	endpoint := options.TelemetryEndpoint()
+
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName("seelf"),
+		semconv.ServiceVersion(serviceVersion),
+	))
+
+	if err != nil {
+		return nil, err
+	}
+
+	traceOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	metricOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
+
+	if options.TelemetryInsecure() {
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+
+	if err != nil {
+		return nil, errors.Join(err, traceExporter.Shutdown(ctx))
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// Tracer used by instrumented packages.
+func Tracer() trace.Tracer { return otel.Tracer(instrumentationName) }
+
+// Meter used by instrumented packages.
+func Meter() metric.Meter { return otel.Meter(instrumentationName) }
+
+// Builds a histogram measuring durations in milliseconds. Instruments can be created
+// before Setup is called, they will be bound to the configured provider afterwards.
+func DurationHistogram(name, description string) metric.Float64Histogram {
+	histogram, err := Meter().Float64Histogram(name,
+		metric.WithDescription(description),
+		metric.WithUnit("ms"))
+
+	if err != nil {
+		return noop.Float64Histogram{}
+	}
+
+	return histogram
+}
+
+// Returns the elapsed time since start in milliseconds, as expected by duration histograms.
+func Since(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
+
+// End the span, recording the given error if any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
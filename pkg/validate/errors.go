@@ -0,0 +1,92 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+type (
+	// Parameters attached to a validation error so clients can build a localized
+	// message from it, such as the minimum length expected by a field.
+	Params map[string]any
+
+	// Structured representation of a single field validation error.
+	FieldError struct {
+		Field  string `json:"field"`
+		Code   string `json:"code"`
+		Params Params `json:"params,omitempty"`
+	}
+)
+
+func (p Params) Error() string {
+	keys := make([]string, 0, len(p))
+
+	for k := range p {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, p[k])
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Attach the given parameters to an application error. The result still matches
+// the original error with errors.Is.
+func WithParams(err error, params Params) error {
+	return apperr.Wrap(err, params)
+}
+
+// Retrieve structured field errors from a validation error, sorted by field name.
+// Returns false if the error is not a validation error.
+func Errors(err error) ([]FieldError, bool) {
+	if !errors.Is(err, ErrValidationFailed) {
+		return nil, false
+	}
+
+	fieldErrs, hasFields := apperr.As[FieldErrors](err)
+
+	if !hasFields {
+		return []FieldError{}, true
+	}
+
+	fieldErrs = fieldErrs.Flatten()
+	result := make([]FieldError, 0, len(fieldErrs))
+
+	for field, err := range fieldErrs {
+		result = append(result, newFieldError(field, err))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Field < result[j].Field
+	})
+
+	return result, true
+}
+
+func newFieldError(field string, err error) FieldError {
+	fieldErr := FieldError{Field: field, Code: err.Error()}
+
+	appErr, isAppErr := err.(apperr.Error)
+
+	if !isAppErr {
+		return fieldErr
+	}
+
+	fieldErr.Code = appErr.Code
+
+	if params, hasParams := appErr.Detail.(Params); hasParams {
+		fieldErr.Params = params
+	}
+
+	return fieldErr
+}
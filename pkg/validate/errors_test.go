@@ -0,0 +1,45 @@
+package validate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_WithParams(t *testing.T) {
+	t.Run("should keep the original error identity", func(t *testing.T) {
+		err := validate.WithParams(errRequired, validate.Params{"min": 5})
+
+		testutil.ErrorIs(t, errRequired, err)
+		testutil.Equals(t, "required:min=5", err.Error())
+	})
+}
+
+func Test_Errors(t *testing.T) {
+	t.Run("should return false if the error is not a validation one", func(t *testing.T) {
+		_, ok := validate.Errors(errors.New("some error"))
+
+		testutil.IsFalse(t, ok)
+	})
+
+	t.Run("should return structured field errors sorted by field name", func(t *testing.T) {
+		err := validate.Struct(validate.Of{
+			"name": validate.Field("", required),
+			"nested": validate.FieldErrors{
+				"value": validate.WithParams(errAlwaysFail, validate.Params{"max": 10}),
+			},
+			"other": errors.New("infra"),
+		})
+
+		fieldErrs, ok := validate.Errors(err)
+
+		testutil.IsTrue(t, ok)
+		testutil.DeepEquals(t, []validate.FieldError{
+			{Field: "name", Code: "required"},
+			{Field: "nested.value", Code: "always fail", Params: validate.Params{"max": 10}},
+			{Field: "other", Code: "infra"},
+		}, fieldErrs)
+	})
+}
@@ -5,12 +5,25 @@ import (
 	"github.com/YuukanOO/seelf/pkg/validate"
 )
 
-var ErrMin = apperr.New("min")
+var (
+	ErrMin = apperr.New("min")
+	ErrMax = apperr.New("max")
+)
 
 func Min(minValue int) validate.Validator[int] {
 	return func(value int) error {
 		if value < minValue {
-			return ErrMin
+			return validate.WithParams(ErrMin, validate.Params{"min": minValue})
+		}
+
+		return nil
+	}
+}
+
+func Max(maxValue int) validate.Validator[int] {
+	return func(value int) error {
+		if value > maxValue {
+			return validate.WithParams(ErrMax, validate.Params{"max": maxValue})
 		}
 
 		return nil
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/YuukanOO/seelf/pkg/testutil"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/YuukanOO/seelf/pkg/validate/numbers"
 )
 
@@ -13,8 +14,34 @@ func Test_Min(t *testing.T) {
 		testutil.ErrorIs(t, numbers.ErrMin, numbers.Min(3)(1))
 	})
 
+	t.Run("should expose the required min as a parameter", func(t *testing.T) {
+		err := validate.Struct(validate.Of{"value": numbers.Min(3)(2)})
+		fieldErrs, _ := validate.Errors(err)
+
+		testutil.DeepEquals(t, validate.Params{"min": 3}, fieldErrs[0].Params)
+	})
+
 	t.Run("should succeed on value greater then the required min", func(t *testing.T) {
 		testutil.IsNil(t, numbers.Min(3)(4))
 		testutil.IsNil(t, numbers.Min(3)(3))
 	})
 }
+
+func Test_Max(t *testing.T) {
+	t.Run("should fail on value greater than the allowed max", func(t *testing.T) {
+		testutil.ErrorIs(t, numbers.ErrMax, numbers.Max(3)(4))
+		testutil.ErrorIs(t, numbers.ErrMax, numbers.Max(3)(5))
+	})
+
+	t.Run("should expose the allowed max as a parameter", func(t *testing.T) {
+		err := validate.Struct(validate.Of{"value": numbers.Max(3)(4)})
+		fieldErrs, _ := validate.Errors(err)
+
+		testutil.DeepEquals(t, validate.Params{"max": 3}, fieldErrs[0].Params)
+	})
+
+	t.Run("should succeed on value lesser than the allowed max", func(t *testing.T) {
+		testutil.IsNil(t, numbers.Max(3)(2))
+		testutil.IsNil(t, numbers.Max(3)(3))
+	})
+}
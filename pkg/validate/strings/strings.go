@@ -37,7 +37,7 @@ func Match(expr *regexp.Regexp) validate.Validator[string] {
 func Min(length int) validate.Validator[string] {
 	return func(value string) error {
 		if utf8.RuneCountInString(value) < length {
-			return ErrMinLength
+			return validate.WithParams(ErrMinLength, validate.Params{"min": length})
 		}
 
 		return nil
@@ -47,7 +47,7 @@ func Min(length int) validate.Validator[string] {
 func Max(length int) validate.Validator[string] {
 	return func(value string) error {
 		if utf8.RuneCountInString(value) > length {
-			return ErrMaxLength
+			return validate.WithParams(ErrMaxLength, validate.Params{"max": length})
 		}
 
 		return nil
@@ -7,7 +7,7 @@ import (
 	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
-var ErrValidationFailed = apperr.New("validation_failed")
+var ErrValidationFailed = apperr.ErrValidationFailed
 
 type (
 	Validator[T any] func(T) error    // Represents a validator for a specific type